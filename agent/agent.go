@@ -161,6 +161,15 @@ const (
 	AgentConnUpperThreshold = "AGENT_CONN_UPPER_THRESHOLD"
 	AgentConnLookbackWindow = "AGENT_CONN_LOOKBACK_WINDOW"
 
+	AgentUserRequestRateBurst   = "AGENT_USER_REQUEST_RATE_BURST"
+	AgentUserRequestRateRefill  = "AGENT_USER_REQUEST_RATE_REFILL"
+	AgentModelRequestRateBurst  = "AGENT_MODEL_REQUEST_RATE_BURST"
+	AgentModelRequestRateRefill = "AGENT_MODEL_REQUEST_RATE_REFILL"
+
+	// AgentTraceEndpoint is the URL of an OTLP/HTTP collector that
+	// the agent sends trace spans to. If empty, tracing is disabled.
+	AgentTraceEndpoint = "AGENT_TRACE_ENDPOINT"
+
 	MgoStatsEnabled = "MGO_STATS_ENABLED"
 
 	// LoggingOverride will set the logging for this agent to the value
@@ -0,0 +1,62 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+)
+
+// UninstallFile is the name of the file, in the agent's data
+// directory, whose presence tells a machine agent that it should
+// uninstall itself when it terminates.
+const UninstallFile = "uninstall-agent"
+
+// keepWorkloadsContent is written to the uninstall file when the
+// workloads of the machine's units should be left installed.
+const keepWorkloadsContent = "keep-workloads"
+
+// SetCanUninstall creates the uninstall file in the data dir,
+// recording whether the workloads should be kept. It does nothing
+// if the supplied agent doesn't have a machine tag.
+func SetCanUninstall(a Agent, keepWorkloads bool) error {
+	tag := a.CurrentConfig().Tag()
+	if _, ok := tag.(names.MachineTag); !ok {
+		logger.Debugf("cannot uninstall non-machine agent %q", tag)
+		return nil
+	}
+	if a.CurrentConfig().DataDir() == "" {
+		return errors.New("cannot find data dir")
+	}
+	var content []byte
+	if keepWorkloads {
+		content = []byte(keepWorkloadsContent)
+	}
+	return errors.Trace(ioutil.WriteFile(uninstallFile(a), content, 0644))
+}
+
+// CanUninstall reports whether the uninstall file exists in the
+// agent's data dir, and if so whether the workloads should be kept.
+// If it encounters an error, it fails safe and returns false.
+func CanUninstall(a Agent) (ok bool, keepWorkloads bool) {
+	content, err := ioutil.ReadFile(uninstallFile(a))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			logger.Errorf("cannot read uninstall file: %v", err)
+		}
+		logger.Debugf("agent not marked ready for uninstall")
+		return false, false
+	}
+	logger.Infof("agent already marked ready for uninstall")
+	return true, strings.TrimSpace(string(content)) == keepWorkloadsContent
+}
+
+func uninstallFile(a Agent) string {
+	return filepath.Join(a.CurrentConfig().DataDir(), UninstallFile)
+}
@@ -0,0 +1,78 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent_test
+
+import (
+	"os"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/testing"
+)
+
+type uninstallSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&uninstallSuite{})
+
+func (s *uninstallSuite) newAgent(c *gc.C, tag names.Tag) agent.Agent {
+	params := attributeParams
+	params.Paths.DataDir = c.MkDir()
+	params.Tag = tag
+	conf, err := agent.NewAgentConfig(params)
+	c.Assert(err, jc.ErrorIsNil)
+	return fakeAgent{conf}
+}
+
+func (s *uninstallSuite) TestCanUninstallNotMarked(c *gc.C) {
+	a := s.newAgent(c, names.NewMachineTag("1"))
+	ok, keep := agent.CanUninstall(a)
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(keep, jc.IsFalse)
+}
+
+func (s *uninstallSuite) TestSetCanUninstall(c *gc.C) {
+	a := s.newAgent(c, names.NewMachineTag("1"))
+	err := agent.SetCanUninstall(a, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = os.Stat(filepath.Join(a.CurrentConfig().DataDir(), agent.UninstallFile))
+	c.Assert(err, jc.ErrorIsNil)
+	ok, keep := agent.CanUninstall(a)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(keep, jc.IsFalse)
+}
+
+func (s *uninstallSuite) TestSetCanUninstallKeepWorkloads(c *gc.C) {
+	a := s.newAgent(c, names.NewMachineTag("1"))
+	err := agent.SetCanUninstall(a, true)
+	c.Assert(err, jc.ErrorIsNil)
+	ok, keep := agent.CanUninstall(a)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(keep, jc.IsTrue)
+}
+
+func (s *uninstallSuite) TestSetCanUninstallNotMachine(c *gc.C) {
+	a := s.newAgent(c, names.NewUnitTag("mysql/0"))
+	err := agent.SetCanUninstall(a, false)
+	c.Assert(err, jc.ErrorIsNil)
+	ok, _ := agent.CanUninstall(a)
+	c.Assert(ok, jc.IsFalse)
+}
+
+type fakeAgent struct {
+	conf agent.Config
+}
+
+func (a fakeAgent) CurrentConfig() agent.Config {
+	return a.conf
+}
+
+func (a fakeAgent) ChangeConfig(agent.ConfigMutator) error {
+	return nil
+}
@@ -5,6 +5,7 @@ package action_test
 
 import (
 	"errors"
+	"time"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -262,3 +263,53 @@ func (s *actionSuite) TestTasksNotSupported(c *gc.C) {
 	_, err := client.Tasks(params.TaskQueryArgs{})
 	c.Assert(err, gc.ErrorMatches, "Tasks not supported by this version \\(4\\) of Juju")
 }
+
+func (s *actionSuite) TestCheckNetwork(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Assert(request, gc.Equals, "CheckNetwork")
+				c.Assert(a, jc.DeepEquals, params.CheckNetworkParams{
+					Applications: []string{"mysql"},
+					Timeout:      time.Second,
+				})
+				c.Assert(result, gc.FitsTypeOf, &params.ActionResults{})
+				*(result.(*params.ActionResults)) = params.ActionResults{
+					Results: []params.ActionResult{{
+						Action: &params.Action{Tag: "action-1"},
+					}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 6,
+	}
+	client := action.NewClient(apiCaller)
+	result, err := client.CheckNetwork([]string{"mysql"}, time.Second)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, []params.ActionResult{{
+		Action: &params.Action{Tag: "action-1"},
+	}})
+}
+
+func (s *actionSuite) TestCheckNetworkNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				return nil
+			},
+		),
+		BestVersion: 5,
+	}
+	client := action.NewClient(apiCaller)
+	_, err := client.CheckNetwork(nil, time.Second)
+	c.Assert(err, gc.ErrorMatches, "CheckNetwork not supported by this version \\(5\\) of Juju")
+}
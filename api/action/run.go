@@ -6,6 +6,8 @@ package action
 import (
 	"time"
 
+	"github.com/juju/errors"
+
 	"github.com/juju/juju/apiserver/params"
 )
 
@@ -25,3 +27,17 @@ func (c *Client) Run(run params.RunParams) ([]params.ActionResult, error) {
 	err := c.facade.FacadeCall("Run", run, &results)
 	return results.Results, err
 }
+
+// CheckNetwork asks the machines hosting units related to the given
+// applications, or to any application if none are given, to probe the
+// addresses and opened ports of those units. An action is queued for
+// each machine; its results hold the outcome of the probes.
+func (c *Client) CheckNetwork(applications []string, timeout time.Duration) ([]params.ActionResult, error) {
+	if v := c.BestAPIVersion(); v < 6 {
+		return nil, errors.Errorf("CheckNetwork not supported by this version (%d) of Juju", v)
+	}
+	var results params.ActionResults
+	args := params.CheckNetworkParams{Applications: applications, Timeout: timeout}
+	err := c.facade.FacadeCall("CheckNetwork", args, &results)
+	return results.Results, err
+}
@@ -59,7 +59,7 @@ const websocketFrameSize = 65536
 var logger = loggo.GetLogger("juju.api")
 
 type rpcConnection interface {
-	Call(req rpc.Request, params, response interface{}) error
+	CallContext(ctx context.Context, req rpc.Request, params, response interface{}) error
 	Dead() <-chan struct{}
 	Close() error
 }
@@ -285,6 +285,10 @@ func Open(info *Info, opts DialOpts) (Connection, error) {
 func loginWithContext(ctx context.Context, st *state, info *Info) error {
 	result := make(chan error, 1)
 	go func() {
+		if info.Token != "" {
+			result <- st.loginWithToken(info.Token)
+			return
+		}
 		result <- st.Login(info.Tag, info.Password, info.Nonce, info.Macaroons)
 	}()
 	select {
@@ -1176,14 +1180,24 @@ var apiCallRetryStrategy = retry.LimitTime(10*time.Second,
 // object id, and the specific RPC method. It marshalls the Arguments, and will
 // unmarshall the result into the response object that is supplied.
 func (s *state) APICall(facade string, version int, id, method string, args, response interface{}) error {
+	return s.APICallContext(context.Background(), facade, version, id, method, args, response)
+}
+
+// APICallContext is like APICall, but if the given context holds a trace
+// span, the call is made as part of that span's trace.
+func (s *state) APICallContext(ctx context.Context, facade string, version int, id, method string, args, response interface{}) error {
 	for a := retry.Start(apiCallRetryStrategy, s.clock); a.Next(); {
-		err := s.client.Call(rpc.Request{
+		err := s.client.CallContext(ctx, rpc.Request{
 			Type:    facade,
 			Version: version,
 			Id:      id,
 			Action:  method,
 		}, args, response)
-		if params.ErrCode(err) != params.CodeRetry {
+		switch params.ErrCode(err) {
+		case params.CodeRetry, params.CodeRateLimitExceeded:
+			// The server has asked us to back off; try again
+			// after the next retry delay.
+		default:
 			return errors.Trace(err)
 		}
 		if !a.More() {
@@ -28,11 +28,13 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
 	apitesting "github.com/juju/juju/api/testing"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/trace"
 	jjtesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
@@ -1092,6 +1094,24 @@ func (s *apiclientSuite) TestAPICallNoError(c *gc.C) {
 	c.Check(clock.waits, gc.HasLen, 0)
 }
 
+func (s *apiclientSuite) TestAPICallContextSendsTraceParent(c *gc.C) {
+	rpcConn := newRPCConnection()
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: rpcConn,
+		Clock:         &fakeClock{},
+	})
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := trace.ContextWithTraceParent(context.Background(), parent)
+	err := base.APICallContext(ctx, conn, "facade", 1, "id", "method", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rpcConn.traceParent, gc.Equals, parent)
+
+	err = conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rpcConn.traceParent, gc.Equals, "")
+}
+
 func (s *apiclientSuite) TestAPICallError(c *gc.C) {
 	clock := &fakeClock{}
 	conn := api.NewTestingState(api.TestingStateParams{
@@ -1123,6 +1143,24 @@ func (s *apiclientSuite) TestAPICallRetries(c *gc.C) {
 	c.Check(clock.waits, jc.DeepEquals, []time.Duration{100 * time.Millisecond})
 }
 
+func (s *apiclientSuite) TestAPICallRetriesRateLimited(c *gc.C) {
+	clock := &fakeClock{}
+	conn := api.NewTestingState(api.TestingStateParams{
+		RPCConnection: newRPCConnection(
+			errors.Trace(
+				&rpc.RequestError{
+					Message: "request rate limit exceeded",
+					Code:    params.CodeRateLimitExceeded,
+				}),
+		),
+		Clock: clock,
+	})
+
+	err := conn.APICall("facade", 1, "id", "method", nil, nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(clock.waits, jc.DeepEquals, []time.Duration{100 * time.Millisecond})
+}
+
 func (s *apiclientSuite) TestAPICallRetriesLimit(c *gc.C) {
 	clock := &fakeClock{}
 	retryError := errors.Trace(&rpc.RequestError{Message: "hmm...", Code: params.CodeRetry})
@@ -1294,8 +1332,9 @@ func newRPCConnection(errs ...error) *fakeRPCConnection {
 }
 
 type fakeRPCConnection struct {
-	stub     testing.Stub
-	response interface{}
+	stub        testing.Stub
+	response    interface{}
+	traceParent string
 }
 
 func (f *fakeRPCConnection) Dead() <-chan struct{} {
@@ -1306,8 +1345,9 @@ func (f *fakeRPCConnection) Close() error {
 	return nil
 }
 
-func (f *fakeRPCConnection) Call(req rpc.Request, params, response interface{}) error {
+func (f *fakeRPCConnection) CallContext(ctx context.Context, req rpc.Request, params, response interface{}) error {
 	f.stub.AddCall(req.Type+"."+req.Action, req.Version, params)
+	f.traceParent = trace.TraceParentFromContext(ctx)
 	if f.response != nil {
 		rv := reflect.ValueOf(response)
 		target := reflect.Indirect(rv)
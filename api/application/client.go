@@ -8,6 +8,7 @@
 package application
 
 import (
+	"context"
 	"time"
 
 	"github.com/juju/collections/set"
@@ -119,6 +120,13 @@ type DeployArgs struct {
 // it. Placement directives, if provided, specify the machine on which the charm
 // is deployed.
 func (c *Client) Deploy(args DeployArgs) error {
+	return c.DeployContext(context.Background(), args)
+}
+
+// DeployContext is like Deploy, but if the given context holds a trace
+// span, the deployment is recorded by the controller as part of that
+// span's trace.
+func (c *Client) DeployContext(ctx context.Context, args DeployArgs) error {
 	if len(args.AttachStorage) > 0 {
 		if args.NumUnits != 1 {
 			return errors.New("cannot attach existing storage when more than one unit is requested")
@@ -154,7 +162,7 @@ func (c *Client) Deploy(args DeployArgs) error {
 	}
 	var results params.ErrorResults
 	var err error
-	err = c.facade.FacadeCall("Deploy", deployArgs, &results)
+	err = base.FacadeCallContext(ctx, c.facade, "Deploy", deployArgs, &results)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -777,6 +785,20 @@ func (c *Client) Expose(application string) error {
 	return c.facade.FacadeCall("Expose", args, nil)
 }
 
+// ExposeEndpoints exposes the application, restricting the networks
+// from which the ports of each given endpoint may be reached. The
+// empty endpoint name refers to all of the application's endpoints.
+func (c *Client) ExposeEndpoints(application string, exposedEndpoints map[string]params.ExposedEndpoint) error {
+	if apiVersion := c.BestAPIVersion(); apiVersion < 13 {
+		return errors.NotSupportedf("exposing endpoints for Application facade v%v", apiVersion)
+	}
+	args := params.ApplicationExpose{
+		ApplicationName:  application,
+		ExposedEndpoints: exposedEndpoints,
+	}
+	return c.facade.FacadeCall("Expose", args, nil)
+}
+
 // Unexpose changes the juju-managed firewall to unexpose any ports that
 // were also explicitly marked by units as open.
 func (c *Client) Unexpose(application string) error {
@@ -784,6 +806,29 @@ func (c *Client) Unexpose(application string) error {
 	return c.facade.FacadeCall("Unexpose", args, nil)
 }
 
+// SetEgressPolicy restricts the outbound traffic of the application's
+// units to the destinations allowed by the given policy.
+func (c *Client) SetEgressPolicy(application string, policy params.EgressPolicy) error {
+	return c.setEgressPolicy(application, &policy)
+}
+
+// ClearEgressPolicy removes any outbound traffic restriction from the
+// application's units.
+func (c *Client) ClearEgressPolicy(application string) error {
+	return c.setEgressPolicy(application, nil)
+}
+
+func (c *Client) setEgressPolicy(application string, policy *params.EgressPolicy) error {
+	if apiVersion := c.BestAPIVersion(); apiVersion < 12 {
+		return errors.NotSupportedf("SetEgressPolicy for Application facade v%v", apiVersion)
+	}
+	args := params.ApplicationSetEgressPolicy{
+		ApplicationName: application,
+		Policy:          policy,
+	}
+	return c.facade.FacadeCall("SetEgressPolicy", args, nil)
+}
+
 // Get returns the configuration for the named application.
 func (c *Client) Get(branchName, application string) (*params.ApplicationGetResults, error) {
 	var results params.ApplicationGetResults
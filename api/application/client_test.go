@@ -1559,3 +1559,101 @@ func (s *applicationSuite) TestApplicationsInfoResultMismatch(c *gc.C) {
 	c.Check(called, jc.IsTrue)
 	c.Assert(err, gc.ErrorMatches, "expected 2 results, got 3")
 }
+
+func (s *applicationSuite) TestSetEgressPolicy(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "SetEgressPolicy")
+				c.Assert(a, jc.DeepEquals, params.ApplicationSetEgressPolicy{
+					ApplicationName: "foo",
+					Policy: &params.EgressPolicy{
+						ToCIDRs:        []string{"10.0.0.0/8"},
+						ToApplications: []string{"bar"},
+					},
+				})
+				return nil
+			},
+		),
+		BestVersion: 12,
+	})
+	err := client.SetEgressPolicy("foo", params.EgressPolicy{
+		ToCIDRs:        []string{"10.0.0.0/8"},
+		ToApplications: []string{"bar"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestClearEgressPolicy(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "SetEgressPolicy")
+				c.Assert(a, jc.DeepEquals, params.ApplicationSetEgressPolicy{
+					ApplicationName: "foo",
+				})
+				return nil
+			},
+		),
+		BestVersion: 12,
+	})
+	err := client.ClearEgressPolicy("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestSetEgressPolicyNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	err := client.SetEgressPolicy("foo", params.EgressPolicy{ToCIDRs: []string{"10.0.0.0/8"}})
+	c.Assert(err, gc.ErrorMatches, "SetEgressPolicy for Application facade v8 not supported")
+}
+
+func (s *applicationSuite) TestExposeEndpoints(c *gc.C) {
+	var called bool
+	client := application.NewClient(basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string, version int, id, request string, a, response interface{}) error {
+				called = true
+				c.Assert(request, gc.Equals, "Expose")
+				c.Assert(a, jc.DeepEquals, params.ApplicationExpose{
+					ApplicationName: "foo",
+					ExposedEndpoints: map[string]params.ExposedEndpoint{
+						"db": {
+							ExposeToSpaces: []string{"internal"},
+							ExposeToCIDRs:  []string{"10.0.0.0/8"},
+						},
+					},
+				})
+				return nil
+			},
+		),
+		BestVersion: 13,
+	})
+	err := client.ExposeEndpoints("foo", map[string]params.ExposedEndpoint{
+		"db": {
+			ExposeToSpaces: []string{"internal"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *applicationSuite) TestExposeEndpointsNotSupported(c *gc.C) {
+	client := newClient(func(objType string, version int, id, request string, a, response interface{}) error {
+		c.Fatalf("unexpected API call %q", request)
+		return nil
+	})
+	err := client.ExposeEndpoints("foo", map[string]params.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "exposing endpoints for Application facade v8 not supported")
+}
@@ -4,6 +4,7 @@
 package base
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/url"
@@ -49,6 +50,31 @@ type APICaller interface {
 	ControllerStreamConnector
 }
 
+// ContextAPICaller is implemented by APICallers that can make calls as
+// part of the trace held by a context, so that the server can record its
+// handling of the call in the caller's trace.
+type ContextAPICaller interface {
+	// APICallContext is like APICall, but if the given context holds
+	// a trace span, the call is made as part of that span's trace.
+	APICallContext(ctx context.Context, objType string, version int, id, request string, params, response interface{}) error
+}
+
+// APICallContext makes the call with caller as part of the trace held by
+// the given context. If caller cannot propagate traces, the call is made
+// without the context.
+func APICallContext(ctx context.Context, caller APICaller, objType string, version int, id, request string, params, response interface{}) error {
+	if ctxCaller, ok := caller.(ContextAPICaller); ok {
+		return ctxCaller.APICallContext(ctx, objType, version, id, request, params, response)
+	}
+	return caller.APICall(objType, version, id, request, params, response)
+}
+
+// FacadeCallContext is like fc.FacadeCall, but the call is made as part
+// of the trace held by the given context. See APICallContext.
+func FacadeCallContext(ctx context.Context, fc FacadeCaller, request string, params, response interface{}) error {
+	return APICallContext(ctx, fc.RawAPICaller(), fc.Name(), fc.BestAPIVersion(), "", request, params, response)
+}
+
 // StreamConnector is implemented by the client-facing State object.
 type StreamConnector interface {
 	// ConnectStream connects to the given HTTP websocket
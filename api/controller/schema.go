@@ -0,0 +1,26 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+const schemaPath = "/schema"
+
+// APISchema retrieves the JSON schema of the methods of every version of
+// every facade known to the controller.
+func (c *Client) APISchema() ([]params.FacadeSchema, error) {
+	httpClient, err := c.facade.RawAPICaller().HTTPClient()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot retrieve HTTP client")
+	}
+	var schemas []params.FacadeSchema
+	if err = httpClient.Get(schemaPath, &schemas); err != nil {
+		return nil, errors.Annotate(err, "cannot retrieve API schema")
+	}
+	return schemas, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/controller"
+	"github.com/juju/juju/apiserver/params"
+)
+
+func (s *Suite) TestAPISchema(c *gc.C) {
+	response := []params.FacadeSchema{{
+		Name:    "Client",
+		Version: 1,
+		Schema:  json.RawMessage(`{"type":"object"}`),
+	}, {
+		Name:    "Client",
+		Version: 2,
+		Schema:  json.RawMessage(`{"type":"object"}`),
+	}}
+	withHTTPClient(c, "/schema", "GET", func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+		sendJSONResponse(c, w, response)
+	}, func(client *controller.Client) {
+		schemas, err := client.APISchema()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(schemas, jc.DeepEquals, response)
+	})
+}
+
+func (s *Suite) TestAPISchemaError(c *gc.C) {
+	withHTTPClient(c, "/schema", "GET", func(w http.ResponseWriter, req *http.Request) {
+		defer req.Body.Close()
+		w.WriteHeader(http.StatusForbidden)
+	}, func(client *controller.Client) {
+		schemas, err := client.APISchema()
+		c.Assert(err, gc.ErrorMatches, "cannot retrieve API schema: .*")
+		c.Assert(schemas, gc.IsNil)
+	})
+}
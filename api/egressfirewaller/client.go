@@ -0,0 +1,56 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const egressFirewallerFacade = "EgressFirewaller"
+
+// Client provides access to the egress firewaller API facade.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side egress firewaller facade.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, egressFirewallerFacade),
+	}
+}
+
+// EgressCIDRs returns the destination CIDRs to which the agent of the
+// given machine must restrict its outbound traffic. A nil result means
+// the agent must not restrict it, either because the machine is
+// unrestricted or because the firewaller restricts it through the
+// provider.
+func (c *Client) EgressCIDRs(tag names.MachineTag) ([]string, error) {
+	var results params.EgressCIDRsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	if err := c.facade.FacadeCall("EgressCIDRs", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	if !result.Restricted {
+		return nil, nil
+	}
+	// A restricted machine always has a non-nil set of
+	// destinations, even if it is empty.
+	cidrs := make([]string, len(result.CIDRs))
+	copy(cidrs, result.CIDRs)
+	return cidrs, nil
+}
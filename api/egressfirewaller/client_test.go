@@ -0,0 +1,68 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/egressfirewaller"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) caller(c *gc.C, result params.EgressCIDRsResult) apitesting.APICallerFunc {
+	return apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, response interface{}) error {
+		c.Check(objType, gc.Equals, "EgressFirewaller")
+		c.Check(request, gc.Equals, "EgressCIDRs")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-3"}},
+		})
+		*(response.(*params.EgressCIDRsResults)) = params.EgressCIDRsResults{
+			Results: []params.EgressCIDRsResult{result},
+		}
+		return nil
+	})
+}
+
+func (s *clientSuite) TestEgressCIDRs(c *gc.C) {
+	client := egressfirewaller.NewClient(s.caller(c, params.EgressCIDRsResult{
+		Restricted: true,
+		CIDRs:      []string{"10.0.0.1/32"},
+	}))
+	cidrs, err := client.EgressCIDRs(names.NewMachineTag("3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.1/32"})
+}
+
+func (s *clientSuite) TestEgressCIDRsRestrictedToNothing(c *gc.C) {
+	client := egressfirewaller.NewClient(s.caller(c, params.EgressCIDRsResult{Restricted: true}))
+	cidrs, err := client.EgressCIDRs(names.NewMachineTag("3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.NotNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
+
+func (s *clientSuite) TestEgressCIDRsUnrestricted(c *gc.C) {
+	client := egressfirewaller.NewClient(s.caller(c, params.EgressCIDRsResult{}))
+	cidrs, err := client.EgressCIDRs(names.NewMachineTag("3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
+
+func (s *clientSuite) TestEgressCIDRsError(c *gc.C) {
+	client := egressfirewaller.NewClient(s.caller(c, params.EgressCIDRsResult{
+		Error: &params.Error{Message: "boom"},
+	}))
+	_, err := client.EgressCIDRs(names.NewMachineTag("3"))
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -11,14 +11,14 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       5,
+	"Action":                       6,
 	"ActionPruner":                 1,
 	"Agent":                        2,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
 	"AllWatcher":                   1,
 	"Annotations":                  2,
-	"Application":                  11,
+	"Application":                  13,
 	"ApplicationOffers":            2,
 	"ApplicationScaler":            1,
 	"Backups":                      2,
@@ -42,11 +42,12 @@ var facadeVersions = map[string]int{
 	"CrossModelRelations":          1,
 	"Deployer":                     1,
 	"DiskManager":                  2,
+	"EgressFirewaller":             1,
 	"EntityWatcher":                2,
 	"ExternalControllerUpdater":    1,
 	"FanConfigurer":                1,
 	"FilesystemAttachmentsWatcher": 2,
-	"Firewaller":                   5,
+	"Firewaller":                   7,
 	"FirewallRules":                1,
 	"HighAvailability":             2,
 	"HostKeyReporter":              1,
@@ -54,7 +55,7 @@ var facadeVersions = map[string]int{
 	"ImageMetadata":                3,
 	"ImageMetadataManager":         1,
 	"InstanceMutater":              2,
-	"InstancePoller":               3,
+	"InstancePoller":               4,
 	"KeyManager":                   1,
 	"KeyUpdater":                   1,
 	"LeadershipService":            2,
@@ -62,9 +63,10 @@ var facadeVersions = map[string]int{
 	"LogForwarding":                1,
 	"Logger":                       1,
 	"MachineActions":               1,
-	"MachineManager":               6,
+	"MachineManager":               8,
+	"MachineReplacer":              1,
 	"MachineUndertaker":            1,
-	"Machiner":                     1,
+	"Machiner":                     2,
 	"MeterStatus":                  1,
 	"MetricsAdder":                 2,
 	"MetricsDebug":                 2,
@@ -97,17 +99,17 @@ var facadeVersions = map[string]int{
 	"Spaces":                       5,
 	"SSHClient":                    2,
 	"StatusHistory":                2,
-	"Storage":                      6,
-	"StorageProvisioner":           4,
+	"Storage":                      9,
+	"StorageProvisioner":           8,
 	"StringsWatcher":               1,
 	"Subnets":                      3,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       13,
+	"Uniter":                       14,
 	"Upgrader":                     1,
 	"UpgradeSeries":                1,
 	"UpgradeSteps":                 1,
-	"UserManager":                  2,
+	"UserManager":                  3,
 	"VolumeAttachmentsWatcher":     2,
 	"VolumeAttachmentPlansWatcher": 1,
 }
@@ -64,3 +64,77 @@ func (s *Application) IsExposed() (bool, error) {
 	}
 	return result.Result, nil
 }
+
+// EgressCIDRs returns the destination CIDRs to which outbound traffic
+// from the application's units is restricted. A nil result means the
+// application has no egress policy, and its traffic is unrestricted.
+func (s *Application) EgressCIDRs() ([]string, error) {
+	if s.st.BestAPIVersion() < 6 {
+		// Egress policies are not supported by older controllers.
+		return nil, nil
+	}
+	var results params.EgressCIDRsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetEgressCIDRs", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, errors.NewNotFound(result.Error, "")
+		}
+		return nil, result.Error
+	}
+	if !result.Restricted {
+		return nil, nil
+	}
+	// A restricted application always has a non-nil set of
+	// destinations, even if it is empty.
+	cidrs := make([]string, len(result.CIDRs))
+	copy(cidrs, result.CIDRs)
+	return cidrs, nil
+}
+
+// ExposedCIDRs returns the networks from which the ports of each of the
+// application's exposed endpoints may be reached, keyed by endpoint
+// name; the empty name refers to all endpoints. A nil result means the
+// application is not exposed.
+func (s *Application) ExposedCIDRs() (map[string][]string, error) {
+	if s.st.BestAPIVersion() < 7 {
+		// Older controllers only expose applications to
+		// any network.
+		exposed, err := s.IsExposed()
+		if err != nil || !exposed {
+			return nil, err
+		}
+		return map[string][]string{"": {"0.0.0.0/0"}}, nil
+	}
+	var results params.ExposedCIDRsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("GetExposedCIDRs", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		if params.IsCodeNotFound(result.Error) {
+			return nil, errors.NewNotFound(result.Error, "")
+		}
+		return nil, result.Error
+	}
+	if !result.Exposed {
+		return nil, nil
+	}
+	return result.EndpointCIDRs, nil
+}
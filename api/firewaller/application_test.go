@@ -4,12 +4,14 @@
 package firewaller_test
 
 import (
+	"github.com/juju/collections/set"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/api/firewaller"
 	"github.com/juju/juju/core/watcher/watchertest"
+	"github.com/juju/juju/state"
 )
 
 type applicationSuite struct {
@@ -78,3 +80,31 @@ func (s *applicationSuite) TestIsExposed(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(isExposed, jc.IsFalse)
 }
+
+func (s *applicationSuite) TestEgressCIDRs(c *gc.C) {
+	cidrs, err := s.apiApplication.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+
+	err = s.application.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"192.168.0.0/16"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err = s.apiApplication.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(set.NewStrings(cidrs...).Contains("192.168.0.0/16"), jc.IsTrue)
+}
+
+func (s *applicationSuite) TestExposedCIDRs(c *gc.C) {
+	cidrs, err := s.apiApplication.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+
+	err = s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"192.168.0.0/16"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, err = s.apiApplication.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, map[string][]string{"": {"192.168.0.0/16"}})
+}
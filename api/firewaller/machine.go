@@ -112,6 +112,33 @@ func (m *Machine) ActiveSubnets() ([]names.SubnetTag, error) {
 // OpenedPorts returns a map of network.PortRange to unit tag for all opened
 // port ranges on the machine for the subnet matching given subnetTag.
 func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]names.UnitTag, error) {
+	openedRanges, err := m.OpenedPortRanges(subnetTag)
+	if err != nil {
+		return nil, err
+	}
+	endResult := make(map[network.PortRange]names.UnitTag)
+	for _, opened := range openedRanges {
+		endResult[opened.PortRange] = opened.UnitTag
+	}
+	return endResult, nil
+}
+
+// OpenedPortRange describes a port range opened on a machine by a unit
+// for one of its endpoints.
+type OpenedPortRange struct {
+	UnitTag   names.UnitTag
+	PortRange network.PortRange
+
+	// Endpoint is the name of the endpoint the range was opened for.
+	// The empty name refers to all of the unit's endpoints.
+	Endpoint string
+}
+
+// OpenedPortRanges returns all port ranges opened on the machine for the
+// subnet matching given subnetTag, along with the units and endpoints
+// they were opened for. A range opened for several endpoints is returned
+// once for each of them.
+func (m *Machine) OpenedPortRanges(subnetTag names.SubnetTag) ([]OpenedPortRange, error) {
 	var results params.MachinePortsResults
 	var subnetTagAsString string
 	if subnetTag.Id() != "" {
@@ -134,13 +161,17 @@ func (m *Machine) OpenedPorts(subnetTag names.SubnetTag) (map[network.PortRange]
 		return nil, result.Error
 	}
 	// Convert string tags to names.UnitTag before returning.
-	endResult := make(map[network.PortRange]names.UnitTag)
+	var endResult []OpenedPortRange
 	for _, ports := range result.Ports {
 		unitTag, err := names.ParseUnitTag(ports.UnitTag)
 		if err != nil {
 			return nil, err
 		}
-		endResult[ports.PortRange.NetworkPortRange()] = unitTag
+		endResult = append(endResult, OpenedPortRange{
+			UnitTag:   unitTag,
+			PortRange: ports.PortRange.NetworkPortRange(),
+			Endpoint:  ports.Endpoint,
+		})
 	}
 	return endResult, nil
 }
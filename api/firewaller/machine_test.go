@@ -135,6 +135,37 @@ func (s *machineSuite) TestOpenedPorts(c *gc.C) {
 	})
 }
 
+func (s *machineSuite) TestOpenedPortRanges(c *gc.C) {
+	unitTag := s.units[0].Tag().(names.UnitTag)
+
+	// No ports opened at first.
+	ranges, err := s.apiMachine.OpenedPortRanges(names.SubnetTag{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ranges, gc.HasLen, 0)
+
+	// Open a range for all endpoints and another for two of them.
+	err = s.units[0].OpenPort("tcp", 1234)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].OpenPortsForEndpoint("monitoring-port", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	ranges, err = s.apiMachine.OpenedPortRanges(names.SubnetTag{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ranges, jc.DeepEquals, []firewaller.OpenedPortRange{{
+		UnitTag:   unitTag,
+		PortRange: network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		Endpoint:  "monitoring-port",
+	}, {
+		UnitTag:   unitTag,
+		PortRange: network.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		Endpoint:  "url",
+	}, {
+		UnitTag:   unitTag,
+		PortRange: network.PortRange{FromPort: 1234, ToPort: 1234, Protocol: "tcp"},
+	}})
+}
+
 func (s *machineSuite) TestIsManual(c *gc.C) {
 	answer, err := s.machines[0].IsManual()
 	c.Assert(err, jc.ErrorIsNil)
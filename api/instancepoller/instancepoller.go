@@ -9,9 +9,11 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/common/cloudspec"
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/watcher"
+	"github.com/juju/juju/environs"
 )
 
 const instancePollerFacade = "InstancePoller"
@@ -60,3 +62,33 @@ func (api *API) WatchModelMachines() (watcher.StringsWatcher, error) {
 	}
 	return newStringsWatcher(api.facade.RawAPICaller(), result), nil
 }
+
+// RegionCloudSpec returns the cloud spec of the cloud region that the
+// machine with the given tag was placed in, or nil if the machine is in
+// the model's own region.
+func (api *API) RegionCloudSpec(tag names.MachineTag) (*environs.CloudSpec, error) {
+	var results params.CloudSpecResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	err := api.facade.FacadeCall("RegionCloudSpecs", args, &results)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.Result == nil {
+		return nil, nil
+	}
+	var specAPI cloudspec.CloudSpecAPI
+	spec, err := specAPI.MakeCloudSpec(result.Result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &spec, nil
+}
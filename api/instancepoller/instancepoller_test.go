@@ -106,6 +106,40 @@ func (s *InstancePollerSuite) TestWatchModelMachinesServerError(c *gc.C) {
 	c.Assert(w, gc.IsNil)
 }
 
+func (s *InstancePollerSuite) TestRegionCloudSpec(c *gc.C) {
+	expectedResults := params.CloudSpecResults{
+		Results: []params.CloudSpecResult{{Result: &params.CloudSpec{
+			Type:   "dummy",
+			Name:   "other-cloud",
+			Region: "other-region",
+			Credential: &params.CloudCredential{
+				AuthType:   "userpass",
+				Attributes: map[string]string{"username": "fred"},
+			},
+		}}},
+	}
+	apiCaller := successAPICaller(c, "RegionCloudSpecs", entitiesArgs, expectedResults)
+	api := instancepoller.NewAPI(apiCaller)
+	spec, err := api.RegionCloudSpec(names.NewMachineTag("42"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(apiCaller.CallCount, gc.Equals, 1)
+	c.Assert(spec, gc.NotNil)
+	c.Assert(spec.Name, gc.Equals, "other-cloud")
+	c.Assert(spec.Region, gc.Equals, "other-region")
+	c.Assert(spec.Credential.Attributes(), jc.DeepEquals, map[string]string{"username": "fred"})
+}
+
+func (s *InstancePollerSuite) TestRegionCloudSpecModelRegion(c *gc.C) {
+	expectedResults := params.CloudSpecResults{
+		Results: []params.CloudSpecResult{{}},
+	}
+	apiCaller := successAPICaller(c, "RegionCloudSpecs", entitiesArgs, expectedResults)
+	api := instancepoller.NewAPI(apiCaller)
+	spec, err := api.RegionCloudSpec(names.NewMachineTag("42"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spec, gc.IsNil)
+}
+
 func (s *InstancePollerSuite) TestWatchForModelConfigChangesClientError(c *gc.C) {
 	// We're not testing the success case as we're not patching the
 	// NewNotifyWatcher call the embedded ModelWatcher is calling.
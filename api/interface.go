@@ -80,6 +80,10 @@ type Info struct {
 	// Nonce holds the nonce used when provisioning the machine. Used
 	// only by the machine agent.
 	Nonce string `yaml:",omitempty"`
+
+	// Token holds an API token to log in with, instead of Tag and
+	// Password or Macaroons. Tokens are only valid for model logins.
+	Token string `yaml:",omitempty"`
 }
 
 // Ports returns the unique ports for the api addresses.
@@ -119,6 +123,17 @@ func (info *Info) Validate() error {
 		if len(info.Macaroons) > 0 {
 			return errors.NotValidf("specifying Macaroons and SkipLogin")
 		}
+		if info.Token != "" {
+			return errors.NotValidf("specifying Token and SkipLogin")
+		}
+	}
+	if info.Token != "" {
+		if info.Tag != nil || info.Password != "" || len(info.Macaroons) > 0 {
+			return errors.NotValidf("specifying Token and other credentials")
+		}
+		if info.ModelTag.Id() == "" {
+			return errors.NotValidf("specifying Token without ModelTag")
+		}
 	}
 	return nil
 }
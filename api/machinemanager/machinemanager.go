@@ -62,9 +62,9 @@ func (client *Client) ForceDestroyMachines(machines ...string) ([]params.Destroy
 }
 
 // DestroyMachinesWithParams removes the given set of machines, the semantics of which
-// is determined by the force and keep parameters.
+// is determined by the force, keep and keepWorkloads parameters.
 // TODO(wallyworld) - for Juju 3.0, this should be the preferred api to use.
-func (client *Client) DestroyMachinesWithParams(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+func (client *Client) DestroyMachinesWithParams(force, keep, keepWorkloads bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
 	args := params.DestroyMachinesParams{
 		Force:       force,
 		Keep:        keep,
@@ -73,6 +73,9 @@ func (client *Client) DestroyMachinesWithParams(force, keep bool, maxWait *time.
 	if client.BestAPIVersion() > 5 {
 		args.MaxWait = maxWait
 	}
+	if client.BestAPIVersion() > 7 {
+		args.KeepWorkloads = keepWorkloads
+	}
 	allResults := make([]params.DestroyMachineResult, len(machines))
 	index := make([]int, 0, len(machines))
 	for i, machineId := range machines {
@@ -259,3 +262,16 @@ func (client *Client) GetUpgradeSeriesMessages(machineName, watcherId string) ([
 
 	return result.Result, nil
 }
+
+// CloudQuotas returns the quotas of the cloud region in which the model
+// is deployed, and how much of each is in use.
+func (client *Client) CloudQuotas() (params.CloudQuotasResult, error) {
+	if client.BestAPIVersion() < 7 {
+		return params.CloudQuotasResult{}, errors.NotSupportedf("CloudQuotas")
+	}
+	var result params.CloudQuotasResult
+	if err := client.facade.FacadeCall("CloudQuotas", nil, &result); err != nil {
+		return params.CloudQuotasResult{}, errors.Trace(err)
+	}
+	return result, nil
+}
@@ -182,7 +182,7 @@ func (s *MachinemanagerSuite) TestDestroyMachinesInvalidIds(c *gc.C) {
 	c.Assert(results, jc.DeepEquals, expectedResults)
 }
 
-func (s *MachinemanagerSuite) clientToTestDestroyMachinesWithParams(c *gc.C, v int, maxWait *time.Duration) (*machinemanager.Client, []params.DestroyMachineResult) {
+func (s *MachinemanagerSuite) clientToTestDestroyMachinesWithParams(c *gc.C, v int, keepWorkloads bool, maxWait *time.Duration) (*machinemanager.Client, []params.DestroyMachineResult) {
 	expectedResults := []params.DestroyMachineResult{{
 		Error: &params.Error{Message: "boo"},
 	}, {
@@ -205,7 +205,8 @@ func (s *MachinemanagerSuite) clientToTestDestroyMachinesWithParams(c *gc.C, v i
 						"machine-0",
 						"machine-0-lxd-1",
 					},
-					MaxWait: maxWait,
+					MaxWait:       maxWait,
+					KeepWorkloads: keepWorkloads,
 				})
 				c.Assert(response, gc.FitsTypeOf, &params.DestroyMachineResults{})
 				out := response.(*params.DestroyMachineResults)
@@ -218,9 +219,9 @@ func (s *MachinemanagerSuite) clientToTestDestroyMachinesWithParams(c *gc.C, v i
 func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsV5NoWait(c *gc.C) {
 	// MaxWait will be ignored in all versions < 6, so expect the argument
 	// to apiserver to always be nl.
-	client, expected := s.clientToTestDestroyMachinesWithParams(c, 5, (*time.Duration)(nil))
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 5, false, (*time.Duration)(nil))
 	noWait := 0 * time.Second
-	results, err := client.DestroyMachinesWithParams(true, true, &noWait, "0", "0/lxd/1")
+	results, err := client.DestroyMachinesWithParams(true, true, false, &noWait, "0", "0/lxd/1")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
@@ -228,23 +229,86 @@ func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsV5NoWait(c *gc.C) {
 func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsV5NilWait(c *gc.C) {
 	// MaxWait will be ignored in all versions < 6, so expect the argument
 	// to apiserver to always be nl.
-	client, expected := s.clientToTestDestroyMachinesWithParams(c, 5, (*time.Duration)(nil))
-	results, err := client.DestroyMachinesWithParams(true, true, (*time.Duration)(nil), "0", "0/lxd/1")
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 5, false, (*time.Duration)(nil))
+	results, err := client.DestroyMachinesWithParams(true, true, false, (*time.Duration)(nil), "0", "0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsV7KeepWorkloads(c *gc.C) {
+	// KeepWorkloads will be ignored in all versions < 8.
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 7, false, (*time.Duration)(nil))
+	results, err := client.DestroyMachinesWithParams(true, true, true, (*time.Duration)(nil), "0", "0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsKeepWorkloads(c *gc.C) {
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 8, true, (*time.Duration)(nil))
+	results, err := client.DestroyMachinesWithParams(true, true, true, (*time.Duration)(nil), "0", "0/lxd/1")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
 
 func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsNoWait(c *gc.C) {
 	noWait := 0 * time.Second
-	client, expected := s.clientToTestDestroyMachinesWithParams(c, 6, &noWait)
-	results, err := client.DestroyMachinesWithParams(true, true, &noWait, "0", "0/lxd/1")
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 6, false, &noWait)
+	results, err := client.DestroyMachinesWithParams(true, true, false, &noWait, "0", "0/lxd/1")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
 
 func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsNilWait(c *gc.C) {
-	client, expected := s.clientToTestDestroyMachinesWithParams(c, 6, (*time.Duration)(nil))
-	results, err := client.DestroyMachinesWithParams(true, true, (*time.Duration)(nil), "0", "0/lxd/1")
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 6, false, (*time.Duration)(nil))
+	results, err := client.DestroyMachinesWithParams(true, true, false, (*time.Duration)(nil), "0", "0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsV7KeepWorkloads(c *gc.C) {
+	// KeepWorkloads will be ignored in all versions < 8.
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 7, false, (*time.Duration)(nil))
+	results, err := client.DestroyMachinesWithParams(true, true, true, (*time.Duration)(nil), "0", "0/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestDestroyMachinesWithParamsKeepWorkloads(c *gc.C) {
+	client, expected := s.clientToTestDestroyMachinesWithParams(c, 8, true, (*time.Duration)(nil))
+	results, err := client.DestroyMachinesWithParams(true, true, true, (*time.Duration)(nil), "0", "0/lxd/1")
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, jc.DeepEquals, expected)
 }
+
+func (s *MachinemanagerSuite) TestCloudQuotas(c *gc.C) {
+	expected := params.CloudQuotasResult{
+		Cloud:  "google",
+		Region: "us-east1",
+		Quotas: []params.CloudQuota{{Name: "vCPUs", Limit: 24, Usage: 8}},
+	}
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 7,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Assert(request, gc.Equals, "CloudQuotas")
+				c.Assert(a, gc.IsNil)
+				c.Assert(response, gc.FitsTypeOf, &params.CloudQuotasResult{})
+				*(response.(*params.CloudQuotasResult)) = expected
+				return nil
+			})})
+	result, err := client.CloudQuotas()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, expected)
+}
+
+func (s *MachinemanagerSuite) TestCloudQuotasNotSupported(c *gc.C) {
+	client := machinemanager.NewClient(
+		basetesting.BestVersionCaller{
+			BestVersion: 6,
+			APICallerFunc: basetesting.APICallerFunc(func(objType string, version int, id, request string, a, response interface{}) error {
+				c.Fatalf("unexpected API call %q", request)
+				return nil
+			})})
+	_, err := client.CloudQuotas()
+	c.Assert(err, gc.ErrorMatches, "CloudQuotas not supported")
+}
@@ -138,3 +138,28 @@ func (m *Machine) SetProviderNetworkConfig() error {
 	}
 	return result.OneError()
 }
+
+// KeepWorkloads reports whether the machine agent should leave the
+// workloads of its units installed when it uninstalls itself. Older
+// controllers do not support keeping workloads, so always report false.
+func (m *Machine) KeepWorkloads() (bool, error) {
+	if m.st.facade.BestAPIVersion() < 2 {
+		return false, nil
+	}
+	var results params.BoolResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("KeepWorkloads", args, &results)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return false, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.Result, nil
+}
@@ -133,6 +133,20 @@ func (s *machinerSuite) TestRefresh(c *gc.C) {
 	c.Assert(machine.Life(), gc.Equals, life.Dead)
 }
 
+func (s *machinerSuite) TestKeepWorkloads(c *gc.C) {
+	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+	keep, err := machine.KeepWorkloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keep, jc.IsFalse)
+
+	err = s.machine.SetKeepWorkloads(true)
+	c.Assert(err, jc.ErrorIsNil)
+	keep, err = machine.KeepWorkloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keep, jc.IsTrue)
+}
+
 func (s *machinerSuite) TestSetMachineAddresses(c *gc.C) {
 	machine, err := s.machiner.Machine(names.NewMachineTag("1"))
 	c.Assert(err, jc.ErrorIsNil)
@@ -0,0 +1,85 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+const machineReplacerFacade = "MachineReplacer"
+
+// Client provides access to the machine replacer API facade.
+type Client struct {
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client-side machine replacer facade.
+func NewClient(caller base.APICaller) *Client {
+	return &Client{
+		facade: base.NewFacadeCaller(caller, machineReplacerFacade),
+	}
+}
+
+// DownMachine describes a machine whose agent is down and whose
+// instance is not running, which hosts units of applications that have
+// opted into machine replacement, or a machine whose spot instance was
+// interrupted by the cloud.
+type DownMachine struct {
+	Tag names.MachineTag
+
+	// ReplaceAfter is how long the machine may be down before it
+	// is replaced; zero if it should be replaced straight away.
+	ReplaceAfter time.Duration
+}
+
+// DownMachines returns the machines that may need replacing.
+func (c *Client) DownMachines() ([]DownMachine, error) {
+	var result params.DownMachinesResult
+	if err := c.facade.FacadeCall("DownMachines", nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	machines := make([]DownMachine, len(result.Machines))
+	for i, m := range result.Machines {
+		tag, err := names.ParseMachineTag(m.Tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machines[i] = DownMachine{
+			Tag:          tag,
+			ReplaceAfter: m.ReplaceAfter,
+		}
+	}
+	return machines, nil
+}
+
+// ReplaceMachine provisions a replacement for the down machine, and
+// moves the units of applications that have opted into machine
+// replacement onto it. The replacement machine's tag is returned.
+func (c *Client) ReplaceMachine(tag names.MachineTag) (names.MachineTag, error) {
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: tag.String()}},
+	}
+	var results params.StringResults
+	if err := c.facade.FacadeCall("ReplaceMachines", args, &results); err != nil {
+		return names.MachineTag{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return names.MachineTag{}, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return names.MachineTag{}, result.Error
+	}
+	replacement, err := names.ParseMachineTag(result.Result)
+	if err != nil {
+		return names.MachineTag{}, errors.Trace(err)
+	}
+	return replacement, nil
+}
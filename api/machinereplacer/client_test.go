@@ -0,0 +1,82 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/machinereplacer"
+	"github.com/juju/juju/apiserver/params"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestDownMachines(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineReplacer")
+		c.Check(request, gc.Equals, "DownMachines")
+		c.Check(arg, gc.IsNil)
+		*(result.(*params.DownMachinesResult)) = params.DownMachinesResult{
+			Machines: []params.DownMachine{{Tag: "machine-3", ReplaceAfter: time.Minute}},
+		}
+		return nil
+	})
+	client := machinereplacer.NewClient(caller)
+	machines, err := client.DownMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, jc.DeepEquals, []machinereplacer.DownMachine{{
+		Tag:          names.NewMachineTag("3"),
+		ReplaceAfter: time.Minute,
+	}})
+}
+
+func (s *clientSuite) TestDownMachinesError(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		return errors.New("boom")
+	})
+	client := machinereplacer.NewClient(caller)
+	_, err := client.DownMachines()
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *clientSuite) TestReplaceMachine(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "MachineReplacer")
+		c.Check(request, gc.Equals, "ReplaceMachines")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "machine-3"}},
+		})
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Result: "machine-4"}},
+		}
+		return nil
+	})
+	client := machinereplacer.NewClient(caller)
+	replacement, err := client.ReplaceMachine(names.NewMachineTag("3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(replacement, gc.Equals, names.NewMachineTag("4"))
+}
+
+func (s *clientSuite) TestReplaceMachineErrorResult(c *gc.C) {
+	caller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		*(result.(*params.StringResults)) = params.StringResults{
+			Results: []params.StringResult{{Error: &params.Error{Message: "machine 3 is not down"}}},
+		}
+		return nil
+	})
+	client := machinereplacer.NewClient(caller)
+	_, err := client.ReplaceMachine(names.NewMachineTag("3"))
+	c.Assert(err, gc.ErrorMatches, "machine 3 is not down")
+}
@@ -12,6 +12,7 @@ import (
 
 	apiwatcher "github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/status"
@@ -101,6 +102,9 @@ type MachineProvisioner interface {
 	// for the machine.
 	KeepInstance() (bool, error)
 
+	// Constraints returns the machine's constraints.
+	Constraints() (constraints.Value, error)
+
 	// SetPassword sets the machine's password.
 	SetPassword(password string) error
 
@@ -433,6 +437,26 @@ func (m *Machine) KeepInstance() (bool, error) {
 	return result.Result, nil
 }
 
+// Constraints implements MachineProvisioner.Constraints.
+func (m *Machine) Constraints() (constraints.Value, error) {
+	var results params.ConstraintsResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: m.tag.String()}},
+	}
+	err := m.st.facade.FacadeCall("Constraints", args, &results)
+	if err != nil {
+		return constraints.Value{}, err
+	}
+	if len(results.Results) != 1 {
+		return constraints.Value{}, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return constraints.Value{}, result.Error
+	}
+	return result.Constraints, nil
+}
+
 // SetPassword implements MachineProvisioner.SetPassword.
 func (m *Machine) SetPassword(password string) error {
 	var result params.ErrorResults
@@ -9,6 +9,7 @@ import (
 
 	gomock "github.com/golang/mock/gomock"
 	params "github.com/juju/juju/apiserver/params"
+	constraints "github.com/juju/juju/core/constraints"
 	instance "github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/life"
 	status "github.com/juju/juju/core/status"
@@ -53,6 +54,19 @@ func (mr *MockMachineProvisionerMockRecorder) AvailabilityZone() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailabilityZone", reflect.TypeOf((*MockMachineProvisioner)(nil).AvailabilityZone))
 }
 
+// Constraints mocks base method
+func (m *MockMachineProvisioner) Constraints() (constraints.Value, error) {
+	ret := m.ctrl.Call(m, "Constraints")
+	ret0, _ := ret[0].(constraints.Value)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Constraints indicates an expected call of Constraints
+func (mr *MockMachineProvisionerMockRecorder) Constraints() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Constraints", reflect.TypeOf((*MockMachineProvisioner)(nil).Constraints))
+}
+
 // DistributionGroup mocks base method
 func (m *MockMachineProvisioner) DistributionGroup() ([]instance.Id, error) {
 	ret := m.ctrl.Call(m, "DistributionGroup")
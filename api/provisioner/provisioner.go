@@ -356,6 +356,7 @@ func (st *State) HostChangesForContainer(containerTag names.MachineTag) ([]netwo
 		res[i].BridgeName = bridgeInfo.BridgeName
 		res[i].DeviceName = bridgeInfo.HostDeviceName
 		res[i].MACAddress = bridgeInfo.MACAddress
+		res[i].MTU = bridgeInfo.MTU
 	}
 	return res, result.Results[0].ReconfigureDelay, nil
 }
@@ -424,6 +424,20 @@ func (s *provisionerSuite) TestKeepInstance(c *gc.C) {
 	c.Assert(keep, jc.IsTrue)
 }
 
+func (s *provisionerSuite) TestConstraints(c *gc.C) {
+	machine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:      "quantal",
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: constraints.MustParse("mem=8G spot=true"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	apiMachine := s.assertGetOneMachine(c, machine.MachineTag())
+	cons, err := apiMachine.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons.HasSpot(), jc.IsTrue)
+	c.Assert(*cons.Mem, gc.Equals, uint64(8*1024))
+}
+
 func (s *provisionerSuite) TestDistributionGroup(c *gc.C) {
 	apiMachine := s.assertGetOneMachine(c, s.machine.MachineTag())
 	instances, err := apiMachine.DistributionGroup()
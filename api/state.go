@@ -36,7 +36,6 @@ import (
 // This method is usually called automatically by Open. The machine nonce
 // should be empty unless logging in as a machine agent.
 func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaroon.Slice) error {
-	var result params.LoginResult
 	request := &params.LoginRequest{
 		AuthTag:     tagToString(tag),
 		Credentials: password,
@@ -58,6 +57,22 @@ func (st *state) Login(tag names.Tag, password, nonce string, macaroons []macaro
 			httpbakery.MacaroonsForURL(st.bakeryClient.Client.Jar, st.cookieURL)...,
 		)
 	}
+	return st.login(request, tag)
+}
+
+// loginWithToken authenticates to the API server using an API token
+// instead of an entity's credentials. The identity of the connection
+// is that of the user owning the token.
+func (st *state) loginWithToken(token string) error {
+	request := &params.LoginRequest{
+		Token:   token,
+		CLIArgs: utils.CommandString(os.Args...),
+	}
+	return st.login(request, nil)
+}
+
+func (st *state) login(request *params.LoginRequest, tag names.Tag) error {
+	var result params.LoginResult
 	err := st.APICall("Admin", 3, "", "Login", request, &result)
 	if err != nil {
 		if !params.IsRedirect(err) {
@@ -355,3 +355,156 @@ func (c *Client) Import(
 	}
 	return names.ParseStorageTag(results.Results[0].Result.StorageTag)
 }
+
+// CreateStorageSnapshot requests snapshots of the specified storage
+// instances. The Juju ID of each requested snapshot is returned; the
+// snapshot itself is taken asynchronously.
+func (c *Client) CreateStorageSnapshot(storageIds []string) ([]params.StringResult, error) {
+	if c.BestAPIVersion() < 7 {
+		return nil, errors.New("creating storage snapshots is not supported by this version of Juju")
+	}
+	entities, err := storageEntities(storageIds)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.StringResults
+	args := params.Entities{Entities: entities}
+	if err := c.facade.FacadeCall("CreateStorageSnapshot", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(storageIds) {
+		return nil, errors.Errorf(
+			"expected %d result(s), got %d",
+			len(storageIds), len(results.Results),
+		)
+	}
+	return results.Results, nil
+}
+
+// ListStorageSnapshots returns details of the snapshots of the specified
+// storage instances, or of all storage snapshots in the model if none
+// are specified.
+func (c *Client) ListStorageSnapshots(storageIds []string) ([]params.StorageSnapshotDetails, error) {
+	if c.BestAPIVersion() < 7 {
+		return nil, errors.New("listing storage snapshots is not supported by this version of Juju")
+	}
+	entities, err := storageEntities(storageIds)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var results params.StorageSnapshotDetailsResults
+	args := params.Entities{Entities: entities}
+	if err := c.facade.FacadeCall("ListStorageSnapshots", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
+
+// DestroyStorageSnapshot destroys the storage snapshots with the
+// specified IDs. The snapshots are deleted from the storage provider
+// asynchronously.
+func (c *Client) DestroyStorageSnapshot(snapshotIds []string) ([]params.ErrorResult, error) {
+	if c.BestAPIVersion() < 7 {
+		return nil, errors.New("destroying storage snapshots is not supported by this version of Juju")
+	}
+	var results params.ErrorResults
+	args := params.StorageSnapshotIds{Ids: snapshotIds}
+	if err := c.facade.FacadeCall("DestroyStorageSnapshot", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != len(snapshotIds) {
+		return nil, errors.Errorf(
+			"expected %d result(s), got %d",
+			len(snapshotIds), len(results.Results),
+		)
+	}
+	return results.Results, nil
+}
+
+// RestoreStorage creates new storage from the specified storage
+// snapshot, and attaches it to the specified unit.
+func (c *Client) RestoreStorage(snapshotId, unitId string) (names.StorageTag, error) {
+	if c.BestAPIVersion() < 7 {
+		return names.StorageTag{}, errors.New("restoring storage is not supported by this version of Juju")
+	}
+	if !names.IsValidUnit(unitId) {
+		return names.StorageTag{}, errors.NotValidf("unit ID %q", unitId)
+	}
+	var results params.StringResults
+	args := params.BulkRestoreStorageParams{
+		Args: []params.RestoreStorageParams{{
+			SnapshotId: snapshotId,
+			UnitTag:    names.NewUnitTag(unitId).String(),
+		}},
+	}
+	if err := c.facade.FacadeCall("RestoreStorage", args, &results); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return names.StorageTag{}, errors.Errorf(
+			"expected 1 result, got %d",
+			len(results.Results),
+		)
+	}
+	if err := results.Results[0].Error; err != nil {
+		return names.StorageTag{}, err
+	}
+	return names.ParseStorageTag(results.Results[0].Result)
+}
+
+// ResizeStorage requests that the volume or filesystem assigned to the
+// storage instance with the specified ID be grown to the specified size,
+// in MiB. The resize is performed asynchronously.
+func (c *Client) ResizeStorage(storageId string, size uint64) error {
+	if c.BestAPIVersion() < 8 {
+		return errors.New("resizing storage is not supported by this version of Juju")
+	}
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	var results params.ErrorResults
+	args := params.BulkResizeStorageParams{
+		Args: []params.ResizeStorageParams{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Size:       size,
+		}},
+	}
+	if err := c.facade.FacadeCall("ResizeStorage", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// MoveStorage requests that the volume assigned to the storage instance
+// with the specified ID be moved to the storage pool with the specified
+// name. The move is performed asynchronously.
+func (c *Client) MoveStorage(storageId, pool string) error {
+	if c.BestAPIVersion() < 9 {
+		return errors.New("moving storage is not supported by this version of Juju")
+	}
+	if !names.IsValidStorage(storageId) {
+		return errors.NotValidf("storage ID %q", storageId)
+	}
+	var results params.ErrorResults
+	args := params.BulkMoveStorageParams{
+		Args: []params.MoveStorageParams{{
+			StorageTag: names.NewStorageTag(storageId).String(),
+			Pool:       pool,
+		}},
+	}
+	if err := c.facade.FacadeCall("MoveStorage", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+func storageEntities(storageIds []string) ([]params.Entity, error) {
+	entities := make([]params.Entity, len(storageIds))
+	for i, id := range storageIds {
+		if !names.IsValidStorage(id) {
+			return nil, errors.NotValidf("storage ID %q", id)
+		}
+		entities[i].Tag = names.NewStorageTag(id).String()
+	}
+	return entities, nil
+}
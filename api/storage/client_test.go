@@ -1077,3 +1077,292 @@ func (s *storageMockSuite) TestUpdatePoolFacadeCallError(c *gc.C) {
 	err := storageClient.UpdatePool("", "", nil)
 	c.Assert(errors.Cause(err), gc.ErrorMatches, msg)
 }
+
+func (s *storageMockSuite) TestCreateStorageSnapshot(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				called = true
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "CreateStorageSnapshot")
+				c.Check(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{{Tag: "storage-data-0"}},
+				})
+				results := result.(*params.StringResults)
+				results.Results = []params.StringResult{{Result: "0"}}
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := storage.NewClient(apiCaller)
+	results, err := client.CreateStorageSnapshot([]string{"data/0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.StringResult{{Result: "0"}})
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *storageMockSuite) TestCreateStorageSnapshotNotSupported(c *gc.C) {
+	client := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 6})
+	_, err := client.CreateStorageSnapshot([]string{"data/0"})
+	c.Assert(err, gc.ErrorMatches, "creating storage snapshots is not supported by this version of Juju")
+}
+
+func (s *storageMockSuite) TestCreateStorageSnapshotInvalidStorageId(c *gc.C) {
+	client := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 7})
+	_, err := client.CreateStorageSnapshot([]string{"foo/bar"})
+	c.Assert(err, gc.ErrorMatches, `storage ID "foo/bar" not valid`)
+}
+
+func (s *storageMockSuite) TestListStorageSnapshots(c *gc.C) {
+	details := []params.StorageSnapshotDetails{{
+		Id:         "0",
+		StorageTag: "storage-data-0",
+		Kind:       params.StorageKindBlock,
+		Pool:       "loop",
+		Size:       1024,
+		Status:     "available",
+		SnapshotId: "snap-0",
+	}}
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "ListStorageSnapshots")
+				c.Check(a, jc.DeepEquals, params.Entities{
+					Entities: []params.Entity{},
+				})
+				results := result.(*params.StorageSnapshotDetailsResults)
+				results.Results = details
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := storage.NewClient(apiCaller)
+	results, err := client.ListStorageSnapshots(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, details)
+}
+
+func (s *storageMockSuite) TestDestroyStorageSnapshot(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "DestroyStorageSnapshot")
+				c.Check(a, jc.DeepEquals, params.StorageSnapshotIds{
+					Ids: []string{"0", "1"},
+				})
+				results := result.(*params.ErrorResults)
+				results.Results = []params.ErrorResult{
+					{},
+					{Error: &params.Error{Message: "snapshot is pending"}},
+				}
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := storage.NewClient(apiCaller)
+	results, err := client.DestroyStorageSnapshot([]string{"0", "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "snapshot is pending"}},
+	})
+}
+
+func (s *storageMockSuite) TestDestroyStorageSnapshotNotSupported(c *gc.C) {
+	client := storage.NewClient(basetesting.BestVersionCaller{BestVersion: 6})
+	_, err := client.DestroyStorageSnapshot([]string{"0"})
+	c.Assert(err, gc.ErrorMatches, "destroying storage snapshots is not supported by this version of Juju")
+}
+
+func (s *storageMockSuite) TestRestoreStorage(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "RestoreStorage")
+				c.Check(a, jc.DeepEquals, params.BulkRestoreStorageParams{
+					Args: []params.RestoreStorageParams{{
+						SnapshotId: "0",
+						UnitTag:    "unit-mysql-0",
+					}},
+				})
+				results := result.(*params.StringResults)
+				results.Results = []params.StringResult{{Result: "storage-data-1"}}
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := storage.NewClient(apiCaller)
+	tag, err := client.RestoreStorage("0", "mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tag, gc.Equals, names.NewStorageTag("data/1"))
+}
+
+func (s *storageMockSuite) TestRestoreStorageError(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(_ string, _ int, _, _ string, _, result interface{}) error {
+				results := result.(*params.StringResults)
+				results.Results = []params.StringResult{{
+					Error: &params.Error{Message: "boom"},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := storage.NewClient(apiCaller)
+	_, err := client.RestoreStorage("0", "mysql/0")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *storageMockSuite) TestResizeStorage(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "ResizeStorage")
+				c.Check(a, jc.DeepEquals, params.BulkResizeStorageParams{
+					Args: []params.ResizeStorageParams{{
+						StorageTag: "storage-data-0",
+						Size:       2048,
+					}},
+				})
+				results := result.(*params.ErrorResults)
+				results.Results = []params.ErrorResult{{}}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	}
+	client := storage.NewClient(apiCaller)
+	err := client.ResizeStorage("data/0", 2048)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageMockSuite) TestResizeStorageError(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(_ string, _ int, _, _ string, _, result interface{}) error {
+				results := result.(*params.ErrorResults)
+				results.Results = []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 8,
+	}
+	client := storage.NewClient(apiCaller)
+	err := client.ResizeStorage("data/0", 2048)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *storageMockSuite) TestResizeStorageNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(_ string, _ int, _, _ string, _, _ interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			},
+		),
+		BestVersion: 7,
+	}
+	client := storage.NewClient(apiCaller)
+	err := client.ResizeStorage("data/0", 2048)
+	c.Assert(err, gc.ErrorMatches, "resizing storage is not supported by this version of Juju")
+}
+
+func (s *storageMockSuite) TestMoveStorage(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(objType string,
+				version int,
+				id, request string,
+				a, result interface{},
+			) error {
+				c.Check(objType, gc.Equals, "Storage")
+				c.Check(id, gc.Equals, "")
+				c.Check(request, gc.Equals, "MoveStorage")
+				c.Check(a, jc.DeepEquals, params.BulkMoveStorageParams{
+					Args: []params.MoveStorageParams{{
+						StorageTag: "storage-data-0",
+						Pool:       "ebs",
+					}},
+				})
+				results := result.(*params.ErrorResults)
+				results.Results = []params.ErrorResult{{}}
+				return nil
+			},
+		),
+		BestVersion: 9,
+	}
+	client := storage.NewClient(apiCaller)
+	err := client.MoveStorage("data/0", "ebs")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageMockSuite) TestMoveStorageError(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(_ string, _ int, _, _ string, _, result interface{}) error {
+				results := result.(*params.ErrorResults)
+				results.Results = []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}}
+				return nil
+			},
+		),
+		BestVersion: 9,
+	}
+	client := storage.NewClient(apiCaller)
+	err := client.MoveStorage("data/0", "ebs")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *storageMockSuite) TestMoveStorageNotSupported(c *gc.C) {
+	apiCaller := basetesting.BestVersionCaller{
+		APICallerFunc: basetesting.APICallerFunc(
+			func(_ string, _ int, _, _ string, _, _ interface{}) error {
+				c.Fatalf("unexpected API call")
+				return nil
+			},
+		),
+		BestVersion: 8,
+	}
+	client := storage.NewClient(apiCaller)
+	err := client.MoveStorage("data/0", "ebs")
+	c.Assert(err, gc.ErrorMatches, "moving storage is not supported by this version of Juju")
+}
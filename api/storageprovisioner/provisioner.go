@@ -94,6 +94,44 @@ func (st *State) WatchFilesystems(scope names.Tag) (watcher.StringsWatcher, erro
 	return st.watchStorageEntities("WatchFilesystems", scope)
 }
 
+// WatchStorageSnapshots watches for lifecycle changes to storage
+// snapshots of volumes and filesystems scoped to the entity with the
+// specified tag.
+func (st *State) WatchStorageSnapshots(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 5 {
+		return nil, errors.NotImplementedf("storage snapshots")
+	}
+	return st.watchStorageEntities("WatchStorageSnapshots", scope)
+}
+
+// WatchVolumeResizes watches for requests to resize volumes scoped
+// to the entity with the specified tag.
+func (st *State) WatchVolumeResizes(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotImplementedf("volume resizes")
+	}
+	return st.watchStorageEntities("WatchVolumeResizes", scope)
+}
+
+// WatchFilesystemResizes watches for requests to resize filesystems
+// scoped to the entity with the specified tag.
+func (st *State) WatchFilesystemResizes(scope names.Tag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 6 {
+		return nil, errors.NotImplementedf("filesystem resizes")
+	}
+	return st.watchStorageEntities("WatchFilesystemResizes", scope)
+}
+
+// WatchVolumeCopies watches for requests to copy data between volumes
+// attached to the specified machine. The watcher reports the IDs of
+// the target volumes.
+func (st *State) WatchVolumeCopies(machine names.MachineTag) (watcher.StringsWatcher, error) {
+	if st.facade.BestAPIVersion() < 7 {
+		return nil, errors.NotImplementedf("volume copies")
+	}
+	return st.watchStorageEntities("WatchVolumeCopies", machine)
+}
+
 func (st *State) watchStorageEntities(method string, scope names.Tag) (watcher.StringsWatcher, error) {
 	var results params.StringsWatchResults
 	args := params.Entities{
@@ -371,6 +409,133 @@ func (st *State) FilesystemAttachmentParams(ids []params.MachineStorageId) ([]pa
 	return results.Results, nil
 }
 
+// StorageSnapshotParams returns the parameters for creating or deleting
+// the storage snapshots with the specified IDs.
+func (st *State) StorageSnapshotParams(ids []string) ([]params.StorageSnapshotParamsResult, error) {
+	args := params.StorageSnapshotIds{Ids: ids}
+	var results params.StorageSnapshotParamsResults
+	err := st.facade.FacadeCall("StorageSnapshotParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetStorageSnapshotResults records the outcomes of creating
+// storage snapshots.
+func (st *State) SetStorageSnapshotResults(snapshots []params.StorageSnapshotResult) ([]params.ErrorResult, error) {
+	args := params.StorageSnapshotResults{Results: snapshots}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetStorageSnapshotResults", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(snapshots) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(snapshots), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// StorageResizeParams returns the parameters for resizing the volumes
+// and filesystems with the specified tags.
+func (st *State) StorageResizeParams(tags []names.Tag) ([]params.StorageResizeParamsResult, error) {
+	args := params.Entities{Entities: make([]params.Entity, len(tags))}
+	for i, tag := range tags {
+		args.Entities[i].Tag = tag.String()
+	}
+	var results params.StorageResizeParamsResults
+	err := st.facade.FacadeCall("StorageResizeParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(tags) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(tags), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetStorageResizeResults records the outcomes of resizing volumes
+// and filesystems.
+func (st *State) SetStorageResizeResults(resizes []params.StorageResizeResult) ([]params.ErrorResult, error) {
+	args := params.StorageResizeResults{Results: resizes}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetStorageResizeResults", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(resizes) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(resizes), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// VolumeCopyParams returns the parameters for copying data between
+// the volumes attached to machines, identified by the attachments of
+// the target volumes.
+func (st *State) VolumeCopyParams(ids []params.MachineStorageId) ([]params.VolumeCopyParamsResult, error) {
+	args := params.MachineStorageIds{Ids: ids}
+	var results params.VolumeCopyParamsResults
+	err := st.facade.FacadeCall("VolumeCopyParams", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// SetVolumeCopyResults records the outcomes of copying data between
+// volumes.
+func (st *State) SetVolumeCopyResults(copies []params.VolumeCopyResult) ([]params.ErrorResult, error) {
+	args := params.VolumeCopyResults{Results: copies}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("SetVolumeCopyResults", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(copies) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(copies), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// VolumeEncryptionKeys returns the keys with which to encrypt the
+// volumes attached to machines, identified by the volume attachments.
+func (st *State) VolumeEncryptionKeys(ids []params.MachineStorageId) ([]params.StringResult, error) {
+	if st.facade.BestAPIVersion() < 8 {
+		return nil, errors.NotImplementedf("volume encryption keys")
+	}
+	args := params.MachineStorageIds{Ids: ids}
+	var results params.StringResults
+	err := st.facade.FacadeCall("VolumeEncryptionKeys", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
+// RemoveStorageSnapshots removes the dying storage snapshots with the
+// specified IDs, once they have been deleted from the storage provider.
+func (st *State) RemoveStorageSnapshots(ids []string) ([]params.ErrorResult, error) {
+	args := params.StorageSnapshotIds{Ids: ids}
+	var results params.ErrorResults
+	err := st.facade.FacadeCall("RemoveStorageSnapshots", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != len(ids) {
+		return nil, errors.Errorf("expected %d result(s), got %d", len(ids), len(results.Results))
+	}
+	return results.Results, nil
+}
+
 // SetVolumeInfo records the details of newly provisioned volumes.
 func (st *State) SetVolumeInfo(volumes []params.Volume) ([]params.ErrorResult, error) {
 	args := params.Volumes{Volumes: volumes}
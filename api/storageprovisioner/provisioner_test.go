@@ -71,6 +71,138 @@ func (s *provisionerSuite) TestWatchVolumes(c *gc.C) {
 	c.Check(callCount, gc.Equals, 1)
 }
 
+func (s *provisionerSuite) TestWatchStorageSnapshots(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "StorageProvisioner")
+			c.Check(version, gc.Equals, 5)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "WatchStorageSnapshots")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+			*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+				Results: []params.StringsWatchResult{{
+					Error: &params.Error{Message: "FAIL"},
+				}},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 5,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchStorageSnapshots(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *provisionerSuite) TestWatchStorageSnapshotsNotImplemented(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Fatalf("unexpected call to %s", request)
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchStorageSnapshots(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "storage snapshots not implemented")
+}
+
+func (s *provisionerSuite) TestWatchVolumeResizes(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "StorageProvisioner")
+			c.Check(version, gc.Equals, 6)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "WatchVolumeResizes")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+			*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+				Results: []params.StringsWatchResult{{
+					Error: &params.Error{Message: "FAIL"},
+				}},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 6,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeResizes(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *provisionerSuite) TestWatchFilesystemResizesNotImplemented(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 5,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchFilesystemResizes(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "filesystem resizes not implemented")
+}
+
+func (s *provisionerSuite) TestWatchVolumeCopies(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "StorageProvisioner")
+			c.Check(version, gc.Equals, 7)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "WatchVolumeCopies")
+			c.Check(arg, jc.DeepEquals, params.Entities{
+				Entities: []params.Entity{{Tag: "machine-123"}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringsWatchResults{})
+			*(result.(*params.StringsWatchResults)) = params.StringsWatchResults{
+				Results: []params.StringsWatchResult{{
+					Error: &params.Error{Message: "FAIL"},
+				}},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 7,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeCopies(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "FAIL")
+	c.Check(callCount, gc.Equals, 1)
+}
+
+func (s *provisionerSuite) TestWatchVolumeCopiesNotImplemented(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 6,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.WatchVolumeCopies(names.NewMachineTag("123"))
+	c.Check(err, gc.ErrorMatches, "volume copies not implemented")
+}
+
 func (s *provisionerSuite) TestWatchFilesystems(c *gc.C) {
 	var callCount int
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -663,6 +795,174 @@ func (s *provisionerSuite) TestFilesystemAttachmentParams(c *gc.C) {
 	c.Assert(filesystemParams, jc.DeepEquals, paramsResults)
 }
 
+func (s *provisionerSuite) TestStorageSnapshotParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "StorageSnapshotParams")
+		c.Check(arg, jc.DeepEquals, params.StorageSnapshotIds{Ids: []string{"0/1"}})
+		c.Assert(result, gc.FitsTypeOf, &params.StorageSnapshotParamsResults{})
+		*(result.(*params.StorageSnapshotParamsResults)) = params.StorageSnapshotParamsResults{
+			Results: []params.StorageSnapshotParamsResult{{
+				Result: &params.StorageSnapshotParams{
+					Id:        "0/1",
+					VolumeTag: "volume-0-0",
+					VolumeId:  "vol-0",
+					Provider:  "loop",
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.StorageSnapshotParams([]string{"0/1"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, []params.StorageSnapshotParamsResult{{
+		Result: &params.StorageSnapshotParams{
+			Id:        "0/1",
+			VolumeTag: "volume-0-0",
+			VolumeId:  "vol-0",
+			Provider:  "loop",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetStorageSnapshotResults(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetStorageSnapshotResults")
+		c.Check(arg, jc.DeepEquals, params.StorageSnapshotResults{
+			Results: []params.StorageSnapshotResult{
+				{Id: "0/1", SnapshotId: "snapshot-0-1"},
+				{Id: "2", Message: "not supported"},
+			},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	errorResults, err := st.SetStorageSnapshotResults([]params.StorageSnapshotResult{
+		{Id: "0/1", SnapshotId: "snapshot-0-1"},
+		{Id: "2", Message: "not supported"},
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 2)
+}
+
+func (s *provisionerSuite) TestRemoveStorageSnapshots(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "RemoveStorageSnapshots")
+		c.Check(arg, jc.DeepEquals, params.StorageSnapshotIds{Ids: []string{"0/1", "2"}})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	errorResults, err := st.RemoveStorageSnapshots([]string{"0/1", "2"})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 2)
+}
+
+func (s *provisionerSuite) TestStorageResizeParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "StorageResizeParams")
+		c.Check(arg, jc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "filesystem-0-1"}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.StorageResizeParamsResults{})
+		*(result.(*params.StorageResizeParamsResults)) = params.StorageResizeParamsResults{
+			Results: []params.StorageResizeParamsResult{{
+				Result: &params.StorageResizeParams{
+					Tag:        "filesystem-0-1",
+					ProviderId: "fs-0",
+					VolumeTag:  "volume-0-1",
+					Size:       2048,
+					Provider:   "rootfs",
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.StorageResizeParams([]names.Tag{names.NewFilesystemTag("0/1")})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, []params.StorageResizeParamsResult{{
+		Result: &params.StorageResizeParams{
+			Tag:        "filesystem-0-1",
+			ProviderId: "fs-0",
+			VolumeTag:  "volume-0-1",
+			Size:       2048,
+			Provider:   "rootfs",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetStorageResizeResults(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetStorageResizeResults")
+		c.Check(arg, jc.DeepEquals, params.StorageResizeResults{
+			Results: []params.StorageResizeResult{
+				{Tag: "volume-0-1", Size: 2048},
+				{Tag: "volume-2", Message: "not supported"},
+			},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	errorResults, err := st.SetStorageResizeResults([]params.StorageResizeResult{
+		{Tag: "volume-0-1", Size: 2048},
+		{Tag: "volume-2", Message: "not supported"},
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 2)
+}
+
 func (s *provisionerSuite) TestSetVolumeInfo(c *gc.C) {
 	var callCount int
 	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
@@ -1301,3 +1601,127 @@ func (s *provisionerSuite) TestLifeServerError(c *gc.C) {
 	c.Assert(results, gc.HasLen, 1)
 	c.Check(results[0].Error, gc.ErrorMatches, "MSG")
 }
+
+func (s *provisionerSuite) TestVolumeCopyParams(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "VolumeCopyParams")
+		c.Check(arg, jc.DeepEquals, params.MachineStorageIds{
+			Ids: []params.MachineStorageId{{
+				MachineTag: "machine-0", AttachmentTag: "volume-1",
+			}},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.VolumeCopyParamsResults{})
+		*(result.(*params.VolumeCopyParamsResults)) = params.VolumeCopyParamsResults{
+			Results: []params.VolumeCopyParamsResult{{
+				Result: &params.VolumeCopyParams{
+					MachineTag:      "machine-0",
+					SourceVolumeTag: "volume-0",
+					TargetVolumeTag: "volume-1",
+				},
+			}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.VolumeCopyParams([]params.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "volume-1",
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, []params.VolumeCopyParamsResult{{
+		Result: &params.VolumeCopyParams{
+			MachineTag:      "machine-0",
+			SourceVolumeTag: "volume-0",
+			TargetVolumeTag: "volume-1",
+		},
+	}})
+}
+
+func (s *provisionerSuite) TestSetVolumeCopyResults(c *gc.C) {
+	var callCount int
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Check(objType, gc.Equals, "StorageProvisioner")
+		c.Check(version, gc.Equals, 0)
+		c.Check(id, gc.Equals, "")
+		c.Check(request, gc.Equals, "SetVolumeCopyResults")
+		c.Check(arg, jc.DeepEquals, params.VolumeCopyResults{
+			Results: []params.VolumeCopyResult{
+				{MachineTag: "machine-0", VolumeTag: "volume-1"},
+				{MachineTag: "machine-0", VolumeTag: "volume-2", Message: "out of space"},
+			},
+		})
+		c.Assert(result, gc.FitsTypeOf, &params.ErrorResults{})
+		*(result.(*params.ErrorResults)) = params.ErrorResults{
+			Results: []params.ErrorResult{{}, {}},
+		}
+		callCount++
+		return nil
+	})
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	errorResults, err := st.SetVolumeCopyResults([]params.VolumeCopyResult{
+		{MachineTag: "machine-0", VolumeTag: "volume-1"},
+		{MachineTag: "machine-0", VolumeTag: "volume-2", Message: "out of space"},
+	})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(errorResults, gc.HasLen, 2)
+}
+
+func (s *provisionerSuite) TestVolumeEncryptionKeys(c *gc.C) {
+	var callCount int
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Check(objType, gc.Equals, "StorageProvisioner")
+			c.Check(version, gc.Equals, 8)
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "VolumeEncryptionKeys")
+			c.Check(arg, jc.DeepEquals, params.MachineStorageIds{
+				Ids: []params.MachineStorageId{{
+					MachineTag: "machine-0", AttachmentTag: "volume-1",
+				}},
+			})
+			c.Assert(result, gc.FitsTypeOf, &params.StringResults{})
+			*(result.(*params.StringResults)) = params.StringResults{
+				Results: []params.StringResult{{Result: "sekrit"}},
+			}
+			callCount++
+			return nil
+		}),
+		BestVersion: 8,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err := st.VolumeEncryptionKeys([]params.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "volume-1",
+	}})
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(callCount, gc.Equals, 1)
+	c.Assert(results, jc.DeepEquals, []params.StringResult{{Result: "sekrit"}})
+}
+
+func (s *provisionerSuite) TestVolumeEncryptionKeysNotImplemented(c *gc.C) {
+	apiCaller := testing.BestVersionCaller{
+		APICallerFunc: testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		}),
+		BestVersion: 7,
+	}
+
+	st, err := storageprovisioner.NewState(apiCaller)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.VolumeEncryptionKeys([]params.MachineStorageId{{
+		MachineTag: "machine-0", AttachmentTag: "volume-1",
+	}})
+	c.Check(err, gc.ErrorMatches, "volume encryption keys not implemented")
+}
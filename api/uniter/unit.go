@@ -494,6 +494,41 @@ func (u *Unit) ClosePorts(protocol string, fromPort, toPort int) error {
 	return result.OneError()
 }
 
+// OpenPortsForEndpoint sets the policy of the port range with protocol
+// to be opened for the given endpoint only.
+func (u *Unit) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.setEndpointPorts("OpenPorts", endpoint, protocol, fromPort, toPort)
+}
+
+// ClosePortsForEndpoint sets the policy of the port range with protocol
+// to be closed for the given endpoint only.
+func (u *Unit) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.setEndpointPorts("ClosePorts", endpoint, protocol, fromPort, toPort)
+}
+
+func (u *Unit) setEndpointPorts(method, endpoint, protocol string, fromPort, toPort int) error {
+	// Older controllers would ignore the endpoint and apply the
+	// change to all of the unit's endpoints.
+	if u.st.facade.BestAPIVersion() < 14 {
+		return errors.NotSupportedf("opening or closing ports for an endpoint on this version of Juju")
+	}
+	var result params.ErrorResults
+	args := params.EntitiesPortRanges{
+		Entities: []params.EntityPortRange{{
+			Tag:      u.tag.String(),
+			Protocol: protocol,
+			FromPort: fromPort,
+			ToPort:   toPort,
+			Endpoint: endpoint,
+		}},
+	}
+	err := u.st.facade.FacadeCall(method, args, &result)
+	if err != nil {
+		return err
+	}
+	return result.OneError()
+}
+
 var ErrNoCharmURLSet = errors.New("unit has no charm url set")
 
 // CharmURL returns the charm URL this unit is currently using.
@@ -481,6 +481,50 @@ func (s *unitSuite) TestOpenClosePortRanges(c *gc.C) {
 	c.Assert(ports, gc.HasLen, 0)
 }
 
+func (s *unitSuite) TestOpenClosePortsForEndpoint(c *gc.C) {
+	err := s.apiUnit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.OpenPortsForEndpoint("monitoring-port", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.apiUnit.OpenPortsForEndpoint("bogus", "tcp", 80, 80)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+
+	machinePorts, err := s.wordpressMachine.AllPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machinePorts, gc.HasLen, 1)
+	c.Assert(machinePorts[0].PortsForUnit(s.wordpressUnit.Name()), jc.DeepEquals, []state.PortRange{
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "url"},
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "monitoring-port"},
+	})
+
+	err = s.apiUnit.ClosePortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machinePorts, err = s.wordpressMachine.AllPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machinePorts[0].PortsForUnit(s.wordpressUnit.Name()), jc.DeepEquals, []state.PortRange{
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "monitoring-port"},
+	})
+}
+
+func (s *unitSuite) TestOpenPortsForEndpointOldFacadeVersion(c *gc.C) {
+	apiCaller := testing.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		c.Assert(request, gc.Equals, "Refresh")
+		*(result.(*params.UnitRefreshResults)) = params.UnitRefreshResults{
+			Results: []params.UnitRefreshResult{{Life: life.Alive}},
+		}
+		return nil
+	})
+	st := uniter.NewStateV4(apiCaller, names.NewUnitTag("wordpress/0"))
+	unit, err := st.Unit(names.NewUnitTag("wordpress/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = unit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	err = unit.ClosePortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *unitSuite) TestGetSetCharmURL(c *gc.C) {
 	// No charm URL set yet.
 	curl, ok := s.wordpressUnit.CharmURL()
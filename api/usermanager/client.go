@@ -6,6 +6,7 @@ package usermanager
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 )
 
 var logger = loggo.GetLogger("juju.api.usermanager")
@@ -207,3 +209,96 @@ func (c *Client) ResetPassword(username string) ([]byte, error) {
 	}
 	return result.SecretKey, nil
 }
+
+// AddAPITokenArgs holds the parameters for minting an API token.
+type AddAPITokenArgs struct {
+	// Owner is the name of the local user the token authenticates as.
+	Owner       string
+	Description string
+
+	// Models holds the UUIDs of the models the token may access.
+	Models []string
+
+	// Access is the maximum model access granted by the token.
+	Access  permission.Access
+	Expires time.Time
+}
+
+// AddAPIToken mints a new API token, returning its details and the
+// secret used to log in with it. The secret cannot be retrieved again.
+func (c *Client) AddAPIToken(args AddAPITokenArgs) (params.APIToken, string, error) {
+	if v := c.BestAPIVersion(); v < 3 {
+		return params.APIToken{}, "", errors.NotSupportedf("API tokens on this version of Juju")
+	}
+	if !names.IsValidUser(args.Owner) {
+		return params.APIToken{}, "", errors.Errorf("invalid user name %q", args.Owner)
+	}
+	modelTags := make([]string, len(args.Models))
+	for i, uuid := range args.Models {
+		if !names.IsValidModel(uuid) {
+			return params.APIToken{}, "", errors.NotValidf("model UUID %q", uuid)
+		}
+		modelTags[i] = names.NewModelTag(uuid).String()
+	}
+	in := params.AddAPITokens{
+		Tokens: []params.AddAPIToken{{
+			OwnerTag:    names.NewUserTag(args.Owner).String(),
+			Description: args.Description,
+			ModelTags:   modelTags,
+			Access:      string(args.Access),
+			Expires:     args.Expires,
+		}},
+	}
+	var out params.AddAPITokenResults
+	if err := c.facade.FacadeCall("AddAPITokens", in, &out); err != nil {
+		return params.APIToken{}, "", errors.Trace(err)
+	}
+	if count := len(out.Results); count != 1 {
+		return params.APIToken{}, "", errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := out.Results[0]
+	if result.Error != nil {
+		return params.APIToken{}, "", errors.Trace(result.Error)
+	}
+	return *result.Token, result.Secret, nil
+}
+
+// APITokens returns the API tokens owned by the specified user.
+func (c *Client) APITokens(owner string) ([]params.APIToken, error) {
+	if v := c.BestAPIVersion(); v < 3 {
+		return nil, errors.NotSupportedf("API tokens on this version of Juju")
+	}
+	if !names.IsValidUser(owner) {
+		return nil, errors.Errorf("invalid user name %q", owner)
+	}
+	in := params.Entities{
+		Entities: []params.Entity{{
+			Tag: names.NewUserTag(owner).String(),
+		}},
+	}
+	var out params.APITokensResults
+	if err := c.facade.FacadeCall("APITokens", in, &out); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if count := len(out.Results); count != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", count)
+	}
+	result := out.Results[0]
+	if result.Error != nil {
+		return nil, errors.Trace(result.Error)
+	}
+	return result.Tokens, nil
+}
+
+// RevokeAPIToken revokes the API token with the given ID.
+func (c *Client) RevokeAPIToken(id string) error {
+	if v := c.BestAPIVersion(); v < 3 {
+		return errors.NotSupportedf("API tokens on this version of Juju")
+	}
+	in := params.APITokenIds{Ids: []string{id}}
+	var out params.ErrorResults
+	if err := c.facade.FacadeCall("RevokeAPITokens", in, &out); err != nil {
+		return errors.Trace(err)
+	}
+	return out.OneError()
+}
@@ -103,9 +103,13 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	// Fetch the API server addresses from state.
 	// If the login comes from a client, return all available addresses.
 	// Otherwise return the addresses suitable for agent use.
+	// The authenticated entity is used rather than the requested auth
+	// tag, since logins with an API token do not specify a tag.
 	getHostPorts := a.root.state.APIHostPortsForAgents
-	if k, _ := names.TagKind(req.AuthTag); k == names.UserTagKind {
-		getHostPorts = a.root.state.APIHostPortsForClients
+	if a.root.entity != nil {
+		if _, ok := a.root.entity.Tag().(names.UserTag); ok {
+			getHostPorts = a.root.state.APIHostPortsForClients
+		}
 	}
 	hostPorts, err := getHostPorts()
 	if err != nil {
@@ -116,25 +120,7 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		pServers[i] = hps.HostPorts()
 	}
 
-	// apiRoot is the API root exposed to the client after login.
-	var apiRoot rpc.Root
-	apiRoot, err = newAPIRoot(
-		a.srv.clock,
-		a.root.state,
-		a.root.shared,
-		a.srv.facades,
-		a.root.resources,
-		a.root,
-	)
-	if err != nil {
-		return fail, errors.Trace(err)
-	}
-	apiRoot, err = restrictAPIRoot(
-		a.srv,
-		apiRoot,
-		a.root.model,
-		*authResult,
-	)
+	apiRoot, err := a.authenticatedAPIRoot(authResult)
 	if err != nil {
 		return fail, errors.Trace(err)
 	}
@@ -172,10 +158,57 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 	}, nil
 }
 
+// authenticatedAPIRoot returns the API root exposed to the client after
+// login, restricted according to the kind of login and subject to the
+// request rate limits.
+func (a *admin) authenticatedAPIRoot(authResult *authResult) (rpc.Root, error) {
+	root, err := newAPIRoot(
+		a.srv.clock,
+		a.root.state,
+		a.root.shared,
+		a.srv.facades,
+		a.root.resources,
+		a.root,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	apiRoot, err := restrictAPIRoot(
+		a.srv,
+		root,
+		a.root.model,
+		*authResult,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var entityTag names.Tag
+	if a.root.entity != nil {
+		entityTag = a.root.entity.Tag()
+	}
+	var modelUUID string
+	if !authResult.controllerOnlyLogin {
+		modelUUID = a.root.model.UUID()
+	}
+	if !authResult.controllerMachineLogin {
+		// Controller agents are never throttled; everyone
+		// else is subject to the request rate limits.
+		apiRoot = rateLimitRoot(apiRoot, a.srv.requestLimiter, entityTag, modelUUID)
+	}
+	if a.srv.tracer != nil {
+		apiRoot = traceRoot(apiRoot, a.srv.tracer, entityTag, modelUUID)
+	}
+	return apiRoot, nil
+}
+
 func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult, cfg auditlog.Config) (*auditlog.Recorder, error) {
 	if !authResult.userLogin || !cfg.Enabled {
 		return nil, nil
 	}
+	var apiTokenID string
+	if a.root.apiToken != nil {
+		apiTokenID = a.root.apiToken.ID
+	}
 	// Wrap the audit logger in a filter that prevents us from logging
 	// lots of readonly conversations (like "juju status" requests).
 	filter := observer.MakeInterestingRequestFilter(cfg.ExcludeMethods)
@@ -188,6 +221,7 @@ func (a *admin) getAuditRecorder(req params.LoginRequest, authResult *authResult
 			ModelName:    a.root.model.Name(),
 			ModelUUID:    a.root.model.UUID(),
 			ConnectionID: a.root.connectionID,
+			APITokenID:   apiTokenID,
 		},
 	)
 	if err != nil {
@@ -216,6 +250,16 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 	// Or better yet, provide a wrapper type that adds the rate-limiting.
 	//
 	// Maybe rate limit non-user auth attempts.
+	if req.Token != "" {
+		// API tokens are scoped to models, and so
+		// can never be used for controller logins.
+		if result.controllerOnlyLogin {
+			return nil, errors.Trace(common.ErrPerm)
+		}
+		if req.AuthTag != "" || req.Credentials != "" || len(req.Macaroons) > 0 {
+			return nil, errors.Annotatef(common.ErrBadRequest, "API token login with other credentials")
+		}
+	}
 	if req.AuthTag != "" {
 		tag, err := names.ParseTag(req.AuthTag)
 		if err == nil {
@@ -297,6 +341,7 @@ func (a *admin) authenticate(req params.LoginRequest) (*authResult, error) {
 			controllerConn = true
 		}
 		a.root.entity = authInfo.Entity
+		a.root.apiToken = authInfo.APIToken
 		// TODO(wallyworld) - we can't yet observe anonymous logins as entity must be non-nil
 		a.apiObserver.Login(
 			authInfo.Entity.Tag(),
@@ -399,6 +444,14 @@ func (a *admin) fillLoginDetails(result *authResult, lastConnection *time.Time)
 			return errors.Trace(err)
 		}
 		result.userInfo.LastConnection = lastConnection
+		if token := a.root.apiToken; token != nil {
+			// Report the access actually available through the
+			// token, which may be less than the user's own.
+			if permission.Access(result.userInfo.ModelAccess).GreaterModelAccessThan(token.Access) {
+				result.userInfo.ModelAccess = string(token.Access)
+			}
+			result.userInfo.ControllerAccess = string(permission.LoginAccess)
+		}
 	}
 	if result.controllerOnlyLogin {
 		if result.anonymousLogin {
@@ -394,6 +394,51 @@ func (s *loginSuite) TestLoginAddressesForClients(c *gc.C) {
 	c.Assert(hostPorts[1:], gc.DeepEquals, exp)
 }
 
+func (s *loginSuite) TestLoginAddressesForTokenClients(c *gc.C) {
+	info, srv := s.newServer(c)
+	defer assertStop(c, srv)
+
+	// Login with an API token, which carries no tag, to simulate a
+	// client connection.
+	user := s.Factory.MakeUser(c, &factory.UserParams{Access: permission.AdminAccess})
+	_, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     user.UserTag(),
+		Models:    []string{s.State.ModelUUID()},
+		Access:    permission.ReadAccess,
+		Expires:   time.Now().Add(time.Hour),
+		CreatedBy: s.AdminUserTag(c),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	info.ModelTag = s.Model.ModelTag()
+	info.Token = secret
+
+	serverAddresses := network.SpaceAddresses{
+		network.NewScopedSpaceAddress("server-1", network.ScopePublic),
+		network.NewScopedSpaceAddress("10.0.0.1", network.ScopeCloudLocal),
+	}
+	serverAddresses[1].SpaceID = s.mgmtSpace.Id()
+	err = s.State.SetAPIHostPorts([]network.SpaceHostPorts{
+		network.SpaceAddressesWithPort(serverAddresses, 123),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	exp := []network.MachineHostPorts{{
+		{
+			MachineAddress: network.NewScopedMachineAddress("server-1", network.ScopePublic),
+			NetPort:        123,
+		},
+		{
+			MachineAddress: network.NewScopedMachineAddress("10.0.0.1", network.ScopeCloudLocal),
+			NetPort:        123,
+		},
+	}}
+
+	_, hostPorts := s.loginHostPorts(c, info)
+	// The public address is only returned to clients, so the token
+	// login must not be treated as an agent login.
+	c.Assert(hostPorts[1:], gc.DeepEquals, exp)
+}
+
 func startNLogins(c *gc.C, n int, info *api.Info) (chan error, *sync.WaitGroup) {
 	errResults := make(chan error, 100)
 	var doneWG sync.WaitGroup
@@ -41,6 +41,7 @@ import (
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/pubsub/apiserver"
 	"github.com/juju/juju/resource"
 	"github.com/juju/juju/resource/resourceadapters"
@@ -67,6 +68,8 @@ type Server struct {
 	dataDir                string
 	logDir                 string
 	limiter                utils.Limiter
+	requestLimiter         *requestLimiter
+	tracer                 trace.Tracer
 	loginRetryPause        time.Duration
 	facades                *facade.Registry
 	authenticator          httpcontext.LocalMacaroonAuthenticator
@@ -175,6 +178,10 @@ type ServerConfig struct {
 	// MetricsCollector defines all the metrics to be collected for the
 	// apiserver
 	MetricsCollector *Collector
+
+	// Tracer, if non-nil, is used to record a trace span for each
+	// API request.
+	Tracer trace.Tracer
 }
 
 // Validate validates the API server configuration.
@@ -299,6 +306,10 @@ func newServer(cfg ServerConfig) (_ *Server, err error) {
 		},
 		metricsCollector: cfg.MetricsCollector,
 	}
+	srv.tracer = cfg.Tracer
+	srv.requestLimiter = newRequestLimiter(
+		cfg.RateLimitConfig, cfg.Clock, cfg.MetricsCollector.ThrottledRequestCount,
+	)
 	srv.shared.cancel = srv.tomb.Dying()
 
 	// The auth context for authenticating access to application offers.
@@ -593,6 +604,9 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 	}
 	backupHandler := &backupHandler{ctxt: httpCtxt}
 	registerHandler := &registerUserHandler{ctxt: httpCtxt}
+	gatewayHandler := &gatewayHandler{srv: srv}
+	openAPIHandler := &openAPIHandler{srv: srv}
+	schemaHandler := &schemaHandler{registry: srv.facades}
 	guiArchiveHandler := &guiArchiveHandler{ctxt: httpCtxt}
 	guiVersionHandler := &guiVersionHandler{ctxt: httpCtxt}
 
@@ -638,6 +652,14 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handler:         mainAPIHandler,
 		tracked:         true,
 		unauthenticated: true,
+	}, {
+		pattern: modelRoutePrefix + "/gateway/:facade/:version/:method",
+		methods: []string{"POST"},
+		handler: gatewayHandler,
+		tracked: true,
+		// The gateway authenticates each request in the
+		// same way as a login to the websocket API.
+		unauthenticated: true,
 	}, {
 		pattern: modelRoutePrefix + "/rest/1.0/:entity/:name/:attribute",
 		handler: modelRestServer,
@@ -700,6 +722,26 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		tracked:         true,
 		unauthenticated: true,
 		noModelUUID:     true,
+	}, {
+		pattern:         "/gateway/openapi.json",
+		methods:         []string{"GET"},
+		handler:         openAPIHandler,
+		unauthenticated: true,
+		noModelUUID:     true,
+	}, {
+		pattern: "/gateway/:facade/:version/:method",
+		methods: []string{"POST"},
+		handler: gatewayHandler,
+		tracked: true,
+		// The gateway authenticates each request in the
+		// same way as a login to the websocket API.
+		unauthenticated: true,
+		noModelUUID:     true,
+	}, {
+		pattern:    "/schema",
+		methods:    []string{"GET"},
+		handler:    schemaHandler,
+		authorizer: tagKindAuthorizer{names.UserTagKind},
 	}, {
 		pattern:         "/register",
 		handler:         registerHandler,
@@ -28,6 +28,9 @@ const MetricLabelModelUUID = "model_uuid"
 // MetricLabelState defines a constant for the LogWriteCount Label
 const MetricLabelState = "state"
 
+// MetricLabelScope defines a constant for the ThrottledRequestCount Label
+const MetricLabelScope = "scope"
+
 // MetricAPIConnectionsLabelNames defines a series of labels for the
 // APIConnections metric.
 var MetricAPIConnectionsLabelNames = []string{
@@ -48,6 +51,13 @@ var MetricLogLabelNames = []string{
 	MetricLabelState,
 }
 
+// MetricThrottledRequestLabelNames defines a series of labels for the
+// ThrottledRequestCount metric.
+var MetricThrottledRequestLabelNames = []string{
+	MetricLabelModelUUID,
+	MetricLabelScope,
+}
+
 // Collector is a prometheus.Collector that collects metrics based
 // on apiserver status.
 type Collector struct {
@@ -59,6 +69,8 @@ type Collector struct {
 	LogWriteCount      *prometheus.CounterVec
 	LogReadCount       *prometheus.CounterVec
 
+	ThrottledRequestCount *prometheus.CounterVec
+
 	DeprecatedAPIConnections     prometheus.Gauge
 	DeprecatedAPIRequestsTotal   *prometheus.CounterVec
 	DeprecatedAPIRequestDuration *prometheus.SummaryVec
@@ -110,6 +122,12 @@ func NewMetricsCollector() *Collector {
 			Name:      "log_read_count",
 			Help:      "Current number of log reads",
 		}, MetricLogLabelNames),
+		ThrottledRequestCount: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: apiserverMetricsNamespace,
+			Subsystem: apiserverSubsystemNamespace,
+			Name:      "throttled_request_count",
+			Help:      "Number of API requests rejected by per-user or per-model rate limits",
+		}, MetricThrottledRequestLabelNames),
 
 		// TODO (stickupkid): remove post 2.6 release
 		DeprecatedAPIConnections: prometheus.NewGauge(prometheus.GaugeOpts{
@@ -142,6 +160,7 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.PingFailureCount.Describe(ch)
 	c.LogWriteCount.Describe(ch)
 	c.LogReadCount.Describe(ch)
+	c.ThrottledRequestCount.Describe(ch)
 
 	// TODO (stickupkid): remove post 2.6 release
 	c.DeprecatedAPIConnections.Describe(ch)
@@ -158,6 +177,7 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.PingFailureCount.Collect(ch)
 	c.LogWriteCount.Collect(ch)
 	c.LogReadCount.Collect(ch)
+	c.ThrottledRequestCount.Collect(ch)
 
 	// TODO (stickupkid): remove post 2.6 release
 	c.DeprecatedAPIConnections.Collect(ch)
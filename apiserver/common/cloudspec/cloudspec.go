@@ -78,6 +78,13 @@ func (s cloudSpecAPI) GetCloudSpec(tag names.ModelTag) params.CloudSpecResult {
 		result.Error = common.ServerError(err)
 		return result
 	}
+	result.Result = MakeCloudSpec(spec)
+	return result
+}
+
+// MakeCloudSpec returns the params.CloudSpec for the given
+// environs.CloudSpec.
+func MakeCloudSpec(spec environs.CloudSpec) *params.CloudSpec {
 	var paramsCloudCredential *params.CloudCredential
 	if spec.Credential != nil && spec.Credential.AuthType() != "" {
 		paramsCloudCredential = &params.CloudCredential{
@@ -85,7 +92,7 @@ func (s cloudSpecAPI) GetCloudSpec(tag names.ModelTag) params.CloudSpecResult {
 			Attributes: spec.Credential.Attributes(),
 		}
 	}
-	result.Result = &params.CloudSpec{
+	return &params.CloudSpec{
 		Type:             spec.Type,
 		Name:             spec.Name,
 		Region:           spec.Region,
@@ -95,7 +102,6 @@ func (s cloudSpecAPI) GetCloudSpec(tag names.ModelTag) params.CloudSpecResult {
 		Credential:       paramsCloudCredential,
 		CACertificates:   spec.CACertificates,
 	}
-	return result
 }
 
 // WatchCloudSpecsChanges returns a watcher for cloud spec changes.
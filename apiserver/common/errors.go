@@ -127,6 +127,7 @@ var (
 	ErrBadRequest         = errors.New("invalid request")
 	ErrTryAgain           = errors.New("try again")
 	ErrActionNotAvailable = errors.New("action no longer available")
+	ErrRateLimitExceeded  = errors.New("request rate limit exceeded")
 )
 
 // OperationBlockedError returns an error which signifies that
@@ -160,6 +161,7 @@ var singletonErrorCodes = map[error]string{
 	ErrStoppedWatcher:            params.CodeStopped,
 	ErrTryAgain:                  params.CodeTryAgain,
 	ErrActionNotAvailable:        params.CodeActionNotAvailable,
+	ErrRateLimitExceeded:         params.CodeRateLimitExceeded,
 }
 
 func singletonCode(err error) (string, bool) {
@@ -213,6 +215,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		status = http.StatusUnauthorized
 	case params.CodeRetry:
 		status = http.StatusServiceUnavailable
+	case params.CodeRateLimitExceeded:
+		status = http.StatusTooManyRequests
 	case params.CodeRedirect:
 		status = http.StatusMovedPermanently
 	}
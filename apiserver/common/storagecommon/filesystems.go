@@ -55,7 +55,9 @@ func FilesystemParams(
 		string(providerType),
 		cfg.Attrs(),
 		filesystemTags,
+		"",  // snapshot ID set by the caller
 		nil, // attachment params set by the caller
+		FilesystemCompositionFromState(f),
 	}
 
 	volumeTag, err := f.Volume()
@@ -68,6 +70,24 @@ func FilesystemParams(
 	return result, nil
 }
 
+// FilesystemCompositionFromState returns the composition of the volumes
+// backing the given filesystem, or nil if it is not backed by composed
+// volumes.
+func FilesystemCompositionFromState(f state.Filesystem) *params.FilesystemComposition {
+	composition, ok := f.Composition()
+	if !ok {
+		return nil
+	}
+	volumeTags := make([]string, len(composition.Volumes))
+	for i, volumeTag := range composition.Volumes {
+		volumeTags[i] = volumeTag.String()
+	}
+	return &params.FilesystemComposition{
+		Type:       string(composition.Type),
+		VolumeTags: volumeTags,
+	}
+}
+
 // FilesystemToState converts a params.Filesystem to state.FilesystemInfo
 // and names.FilesystemTag.
 func FilesystemToState(v params.Filesystem) (names.FilesystemTag, state.FilesystemInfo, error) {
@@ -92,6 +112,7 @@ func FilesystemFromState(f state.Filesystem) (params.Filesystem, error) {
 		f.FilesystemTag().String(),
 		"",
 		FilesystemInfoFromState(info),
+		FilesystemCompositionFromState(f),
 	}
 	volumeTag, err := f.Volume()
 	if err == nil {
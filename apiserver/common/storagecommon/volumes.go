@@ -55,6 +55,7 @@ func VolumeParams(
 		string(providerType),
 		cfg.Attrs(),
 		volumeTags,
+		"",  // snapshot ID set by the caller
 		nil, // attachment params set by the caller
 	}, nil
 }
@@ -22,6 +22,13 @@ const (
 	defaultConnUpperThreshold     = 100000 // connections per second
 	defaultLogSinkRateLimitBurst  = 1000
 	defaultLogSinkRateLimitRefill = time.Millisecond
+
+	// Request rate limiting is disabled by default; a zero burst
+	// means that no token bucket is created for the scope.
+	defaultUserRequestRateBurst   = 0
+	defaultUserRequestRateRefill  = 10 * time.Millisecond
+	defaultModelRequestRateBurst  = 0
+	defaultModelRequestRateRefill = time.Millisecond
 )
 
 // RateLimitConfig holds parameters to control
@@ -36,6 +43,24 @@ type RateLimitConfig struct {
 	ConnLookbackWindow time.Duration
 	ConnLowerThreshold int
 	ConnUpperThreshold int
+
+	// UserRequestRateBurst is the number of API requests an
+	// authenticated user may make before being throttled. If zero,
+	// requests are not rate limited per user.
+	UserRequestRateBurst int64
+
+	// UserRequestRateRefill is the rate at which a user's token
+	// bucket is refilled once the initial burst has been used.
+	UserRequestRateRefill time.Duration
+
+	// ModelRequestRateBurst is the number of API requests that may be
+	// made against a single model, across all connections, before
+	// being throttled. If zero, requests are not rate limited per model.
+	ModelRequestRateBurst int64
+
+	// ModelRequestRateRefill is the rate at which a model's token
+	// bucket is refilled once the initial burst has been used.
+	ModelRequestRateRefill time.Duration
 }
 
 // DefaultRateLimitConfig returns a RateLimtConfig struct with
@@ -51,6 +76,11 @@ func DefaultRateLimitConfig() RateLimitConfig {
 		ConnLookbackWindow: defaultConnLookbackWindow,
 		ConnLowerThreshold: defaultConnLowerThreshold,
 		ConnUpperThreshold: defaultConnUpperThreshold,
+
+		UserRequestRateBurst:   defaultUserRequestRateBurst,
+		UserRequestRateRefill:  defaultUserRequestRateRefill,
+		ModelRequestRateBurst:  defaultModelRequestRateBurst,
+		ModelRequestRateRefill: defaultModelRequestRateRefill,
 	}
 }
 
@@ -79,6 +109,18 @@ func (c RateLimitConfig) Validate() error {
 	if c.ConnLookbackWindow < 0 || c.ConnLookbackWindow > 5*time.Second {
 		return errors.NotValidf("conn-lookback-window %d < 0 or > 5s", c.ConnMaxPause)
 	}
+	if c.UserRequestRateBurst < 0 {
+		return errors.NotValidf("user-request-rate-burst %d < 0", c.UserRequestRateBurst)
+	}
+	if c.UserRequestRateBurst > 0 && c.UserRequestRateRefill <= 0 {
+		return errors.NotValidf("user-request-rate-refill %s <= 0", c.UserRequestRateRefill)
+	}
+	if c.ModelRequestRateBurst < 0 {
+		return errors.NotValidf("model-request-rate-burst %d < 0", c.ModelRequestRateBurst)
+	}
+	if c.ModelRequestRateBurst > 0 && c.ModelRequestRateRefill <= 0 {
+		return errors.NotValidf("model-request-rate-refill %s <= 0", c.ModelRequestRateRefill)
+	}
 	return nil
 }
 
@@ -0,0 +1,48 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the methods the egress firewaller needs from
+// state.State.
+type Backend interface {
+	// Machine returns the machine with the given id.
+	Machine(id string) (Machine, error)
+
+	// ModelConfig returns the configuration of the model.
+	ModelConfig() (*config.Config, error)
+}
+
+// Machine defines the methods the egress firewaller needs from
+// state.Machine.
+type Machine interface {
+	IsContainer() bool
+	IsManual() (bool, error)
+	EgressCIDRs() ([]string, bool, error)
+}
+
+type backendShim struct {
+	*state.State
+	model *state.Model
+}
+
+// Machine implements Backend.
+func (b backendShim) Machine(id string) (Machine, error) {
+	m, err := b.State.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+// ModelConfig implements Backend.
+func (b backendShim) ModelConfig() (*config.Config, error) {
+	return b.model.ModelConfig()
+}
@@ -0,0 +1,106 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package egressfirewaller implements the API facade used by machine
+// agents to restrict the outbound traffic of their machines when the
+// firewaller cannot do so through the provider.
+package egressfirewaller
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+)
+
+// API implements the API facade used by the egress firewaller worker.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewAPI returns a new egress firewaller API facade.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthMachineAgent() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	model, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewAPI(backendShim{State: st, model: model}, ctx.Auth())
+}
+
+// EgressCIDRs returns, for each given machine, whether its agent must
+// restrict the outbound traffic of the machine, and if so the destination
+// CIDRs it may reach. Machines whose traffic is restricted by the
+// firewaller through the provider are reported as unrestricted.
+func (api *API) EgressCIDRs(args params.Entities) (params.EgressCIDRsResults, error) {
+	result := params.EgressCIDRsResults{
+		Results: make([]params.EgressCIDRsResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil || !api.authorizer.AuthOwner(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		result.Results[i].CIDRs, result.Results[i].Restricted, err = api.egressCIDRs(tag)
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+func (api *API) egressCIDRs(tag names.MachineTag) ([]string, bool, error) {
+	m, err := api.backend.Machine(tag.Id())
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	local, err := api.enforcedLocally(m)
+	if err != nil || !local {
+		return nil, false, errors.Trace(err)
+	}
+	return m.EgressCIDRs()
+}
+
+// enforcedLocally returns whether the outbound traffic of the machine
+// must be restricted by its agent. The firewaller only restricts the
+// instances of providers supporting it, in the instance firewall mode.
+func (api *API) enforcedLocally(m Machine) (bool, error) {
+	if m.IsContainer() {
+		return true, nil
+	}
+	manual, err := m.IsManual()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if manual {
+		return true, nil
+	}
+	cfg, err := api.backend.ModelConfig()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if cfg.FirewallMode() != config.FwInstance {
+		return true, nil
+	}
+	provider, err := environs.Provider(cfg.Type())
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	p, ok := provider.(environs.EgressFirewallingProvider)
+	return !ok || !p.SupportsInstanceEgress(), nil
+}
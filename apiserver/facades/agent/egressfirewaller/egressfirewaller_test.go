@@ -0,0 +1,103 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/agent/egressfirewaller"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs/config"
+	_ "github.com/juju/juju/provider/dummy"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type egressFirewallerSuite struct {
+	testing.IsolationSuite
+
+	backend *mockBackend
+}
+
+var _ = gc.Suite(&egressFirewallerSuite{})
+
+func (s *egressFirewallerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		machines: map[string]*mockMachine{
+			"0":       {cidrs: []string{"10.0.0.1/32"}},
+			"0/lxd/0": {container: true, cidrs: []string{"10.0.0.1/32"}},
+			"1":       {manual: true, cidrs: []string{"10.0.0.1/32"}},
+			"2":       {},
+		},
+	}
+	s.setModelConfig(c, config.FwInstance)
+}
+
+func (s *egressFirewallerSuite) setModelConfig(c *gc.C, mode string) {
+	s.backend.cfg = coretesting.CustomModelConfig(c, coretesting.Attrs{
+		"type":          "dummy",
+		"firewall-mode": mode,
+	})
+}
+
+func (s *egressFirewallerSuite) egressCIDRs(c *gc.C, id string) params.EgressCIDRsResult {
+	tag := names.NewMachineTag(id)
+	api, err := egressfirewaller.NewAPI(s.backend, apiservertesting.FakeAuthorizer{Tag: tag})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.EgressCIDRs(params.Entities{Entities: []params.Entity{{Tag: tag.String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 1)
+	return result.Results[0]
+}
+
+func (s *egressFirewallerSuite) TestRequiresMachineAgent(c *gc.C) {
+	_, err := egressfirewaller.NewAPI(s.backend, apiservertesting.FakeAuthorizer{Tag: names.NewUnitTag("mysql/0")})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *egressFirewallerSuite) TestOtherMachinePermissionDenied(c *gc.C) {
+	api, err := egressfirewaller.NewAPI(s.backend, apiservertesting.FakeAuthorizer{Tag: names.NewMachineTag("0")})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := api.EgressCIDRs(params.Entities{Entities: []params.Entity{{Tag: "machine-2"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, jc.DeepEquals, common.ServerError(common.ErrPerm))
+	s.backend.CheckNoCalls(c)
+}
+
+func (s *egressFirewallerSuite) TestEnforcedByProvider(c *gc.C) {
+	// The dummy provider restricts the egress of its instances.
+	c.Assert(s.egressCIDRs(c, "0"), jc.DeepEquals, params.EgressCIDRsResult{})
+}
+
+func (s *egressFirewallerSuite) TestGlobalFirewallMode(c *gc.C) {
+	s.setModelConfig(c, config.FwGlobal)
+	c.Assert(s.egressCIDRs(c, "0"), jc.DeepEquals, params.EgressCIDRsResult{
+		Restricted: true,
+		CIDRs:      []string{"10.0.0.1/32"},
+	})
+}
+
+func (s *egressFirewallerSuite) TestContainer(c *gc.C) {
+	c.Assert(s.egressCIDRs(c, "0/lxd/0"), jc.DeepEquals, params.EgressCIDRsResult{
+		Restricted: true,
+		CIDRs:      []string{"10.0.0.1/32"},
+	})
+}
+
+func (s *egressFirewallerSuite) TestManualMachine(c *gc.C) {
+	c.Assert(s.egressCIDRs(c, "1"), jc.DeepEquals, params.EgressCIDRsResult{
+		Restricted: true,
+		CIDRs:      []string{"10.0.0.1/32"},
+	})
+}
+
+func (s *egressFirewallerSuite) TestUnrestricted(c *gc.C) {
+	s.setModelConfig(c, config.FwGlobal)
+	c.Assert(s.egressCIDRs(c, "2"), jc.DeepEquals, params.EgressCIDRsResult{})
+}
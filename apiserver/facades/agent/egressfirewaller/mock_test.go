@@ -0,0 +1,49 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"github.com/juju/testing"
+
+	"github.com/juju/juju/apiserver/facades/agent/egressfirewaller"
+	"github.com/juju/juju/environs/config"
+)
+
+type mockBackend struct {
+	testing.Stub
+
+	cfg      *config.Config
+	machines map[string]*mockMachine
+}
+
+func (b *mockBackend) Machine(id string) (egressfirewaller.Machine, error) {
+	b.AddCall("Machine", id)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.machines[id], nil
+}
+
+func (b *mockBackend) ModelConfig() (*config.Config, error) {
+	b.AddCall("ModelConfig")
+	return b.cfg, b.NextErr()
+}
+
+type mockMachine struct {
+	container bool
+	manual    bool
+	cidrs     []string
+}
+
+func (m *mockMachine) IsContainer() bool {
+	return m.container
+}
+
+func (m *mockMachine) IsManual() (bool, error) {
+	return m.manual, nil
+}
+
+func (m *mockMachine) EgressCIDRs() ([]string, bool, error) {
+	return m.cidrs, m.cidrs != nil, nil
+}
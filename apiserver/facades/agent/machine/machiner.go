@@ -6,6 +6,7 @@
 package machine
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
 
@@ -34,6 +35,24 @@ type MachinerAPI struct {
 	getCanRead   common.GetAuthFunc
 }
 
+// MachinerAPIV1 implements the V1 API used by the machiner worker.
+// It does not have KeepWorkloads.
+type MachinerAPIV1 struct {
+	*MachinerAPI
+}
+
+// NewMachinerAPIV1 creates a new instance of the V1 Machiner API.
+func NewMachinerAPIV1(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*MachinerAPIV1, error) {
+	api, err := NewMachinerAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &MachinerAPIV1{api}, nil
+}
+
+// KeepWorkloads isn't on the V1 API.
+func (*MachinerAPIV1) KeepWorkloads(_, _ struct{}) {}
+
 // NewMachinerAPI creates a new instance of the Machiner API.
 func NewMachinerAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*MachinerAPI, error) {
 	if !authorizer.AuthMachineAgent() {
@@ -139,3 +158,43 @@ func (api *MachinerAPI) Jobs(args params.Entities) (params.JobsResults, error) {
 	}
 	return result, nil
 }
+
+// KeepWorkloads reports, for each given machine, whether its agent
+// should leave the workloads of its units installed when it
+// uninstalls itself.
+func (api *MachinerAPI) KeepWorkloads(args params.Entities) (params.BoolResults, error) {
+	result := params.BoolResults{
+		Results: make([]params.BoolResult, len(args.Entities)),
+	}
+	canRead, err := api.getCanRead()
+	if err != nil {
+		return result, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		if !canRead(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := api.getMachine(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		keep, err := machine.KeepWorkloads()
+		if errors.IsNotFound(err) {
+			// A machine without instance data has nothing
+			// installed to keep.
+			continue
+		} else if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = keep
+	}
+	return result, nil
+}
@@ -253,6 +253,36 @@ func (s *machinerSuite) TestJobs(c *gc.C) {
 	})
 }
 
+func (s *machinerSuite) TestKeepWorkloads(c *gc.C) {
+	err := s.machine1.SetProvisioned("i-1", "", "manual:10.0.0.1:nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine1.SetKeepWorkloads(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "machine-1"},
+		{Tag: "machine-0"},
+		{Tag: "machine-42"},
+	}}
+	result, err := s.machiner.KeepWorkloads(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{
+			{Result: true},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
+func (s *machinerSuite) TestKeepWorkloadsNotProvisioned(c *gc.C) {
+	result, err := s.machiner.KeepWorkloads(params.Entities{Entities: []params.Entity{{Tag: "machine-1"}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.BoolResults{
+		Results: []params.BoolResult{{Result: false}},
+	})
+}
+
 func (s *machinerSuite) TestWatch(c *gc.C) {
 	loggo.GetLogger("juju.state.pool.txnwatcher").SetLogLevel(loggo.TRACE)
 	loggo.GetLogger("juju.state.watcher").SetLogLevel(loggo.TRACE)
@@ -1214,6 +1214,7 @@ func (ctx *hostChangesContext) ProcessOneContainer(
 				HostDeviceName: bridgeInfo.DeviceName,
 				BridgeName:     bridgeInfo.BridgeName,
 				MACAddress:     bridgeInfo.MACAddress,
+				MTU:            bridgeInfo.MTU,
 			})
 	}
 	return nil
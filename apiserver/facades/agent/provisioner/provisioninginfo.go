@@ -10,13 +10,16 @@ import (
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/featureflag"
 	"github.com/juju/os/series"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/common/storagecommon"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
@@ -24,8 +27,10 @@ import (
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/simplestreams"
 	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/cloudimagemetadata"
+	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/storage"
 )
 
@@ -58,6 +63,18 @@ func (api *ProvisionerAPI) ProvisioningInfo(args params.Entities) (params.Provis
 }
 
 func (api *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.Environ) (*params.ProvisioningInfo, error) {
+	// Machines placed in another cloud region are provisioned using
+	// the environ for that region.
+	regionEnv, regionSpec, err := api.machineRegionEnviron(m)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var paramsRegionSpec *params.CloudSpec
+	if regionEnv != nil {
+		env = regionEnv
+		paramsRegionSpec = cloudspec.MakeCloudSpec(*regionSpec)
+	}
+
 	cons, err := m.Constraints()
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -117,9 +134,41 @@ func (api *ProvisionerAPI) getProvisioningInfo(m *state.Machine, env environs.En
 		ControllerConfig:  controllerCfg,
 		CloudInitUserData: env.Config().CloudInitUserData(),
 		CharmLXDProfiles:  pNames,
+		CloudSpec:         paramsRegionSpec,
 	}, nil
 }
 
+// machineRegionEnviron returns the environ and cloud spec for the cloud
+// region that the machine is placed in, if that is not the model's own
+// region, or the placement names another cloud or credential. Otherwise
+// it returns nil.
+func (api *ProvisionerAPI) machineRegionEnviron(m *state.Machine) (environs.Environ, *environs.CloudSpec, error) {
+	if !featureflag.Enabled(feature.MultiCloud) {
+		return nil, nil, nil
+	}
+	placement, ok := instance.ParseRegionPlacement(m.Placement())
+	if !ok || isModelRegion(api.m, placement) {
+		return nil, nil, nil
+	}
+	configGetter, err := stateenvirons.RegionConfigGetter(api.st, api.m, placement)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	env, spec, err := environs.GetEnvironAndCloud(configGetter, environs.New)
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "opening environ for region %q", placement.Region)
+	}
+	return env, spec, nil
+}
+
+// isModelRegion reports whether the region placement is for the model's
+// own cloud region and credential.
+func isModelRegion(m *state.Model, placement instance.RegionPlacement) bool {
+	return (placement.Cloud == "" || placement.Cloud == m.Cloud()) &&
+		placement.Region == m.CloudRegion() &&
+		placement.Credential == ""
+}
+
 // machineVolumeParams retrieves VolumeParams for the volumes that should be
 // provisioned with, and attached to, the machine. The client should ignore
 // parameters that it does not know how to handle.
@@ -154,6 +203,12 @@ func (api *ProvisionerAPI) machineVolumeParams(
 		if err != nil {
 			return nil, nil, errors.Annotatef(err, "getting volume %q", volumeTag.Id())
 		}
+		if stateVolumeParams, ok := volume.Params(); ok && stateVolumeParams.Snapshot != "" {
+			// Volumes restored from storage snapshots are
+			// created by the storage provisioner, once the
+			// machine has been provisioned.
+			continue
+		}
 		storageInstance, err := storagecommon.MaybeAssignedStorageInstance(
 			volume.StorageInstance, sb.StorageInstance,
 		)
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
@@ -464,6 +465,33 @@ func (s *withoutControllerSuite) TestProviderInfoCloudInitUserData(c *gc.C) {
 		"package_upgrade": false})
 }
 
+func (s *withoutControllerSuite) TestProvisioningInfoWithRegionPlacement(c *gc.C) {
+	s.SetFeatureFlags(feature.MultiCloud)
+	template := state.MachineTemplate{
+		Series:    "quantal",
+		Jobs:      []state.MachineJob{state.JobHostUnits},
+		Placement: "region=nether-region,valid",
+	}
+	m, err := s.State.AddOneMachine(template)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: m.Tag().String()},
+	}}
+	result, err := s.provisioner.ProvisioningInfo(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+	c.Assert(result.Results[0].Result.CloudSpec, gc.IsNil)
+	c.Assert(result.Results[1].Error, gc.IsNil)
+	spec := result.Results[1].Result.CloudSpec
+	c.Assert(spec, gc.NotNil)
+	c.Assert(spec.Type, gc.Equals, "dummy")
+	c.Assert(spec.Region, gc.Equals, "nether-region")
+	c.Assert(spec.Endpoint, gc.Equals, "nether-endpoint")
+	c.Assert(result.Results[1].Result.Placement, gc.Equals, "region=nether-region,valid")
+}
+
 var validCloudInitUserData = `
 packages:
   - 'python-keystoneclient'
@@ -56,6 +56,42 @@ func NewFacadeV4(st *state.State, resources facade.Resources, authorizer facade.
 	return NewStorageProvisionerAPIv4(v3), nil
 }
 
+// NewFacadeV5 provides the signature required for facade registration.
+func NewFacadeV5(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv5, error) {
+	v4, err := NewFacadeV4(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv5(v4), nil
+}
+
+// NewFacadeV6 provides the signature required for facade registration.
+func NewFacadeV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv6, error) {
+	v5, err := NewFacadeV5(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv6(v5), nil
+}
+
+// NewFacadeV7 provides the signature required for facade registration.
+func NewFacadeV7(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv7, error) {
+	v6, err := NewFacadeV6(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv7(v6), nil
+}
+
+// NewFacadeV8 provides the signature required for facade registration.
+func NewFacadeV8(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*StorageProvisionerAPIv8, error) {
+	v7, err := NewFacadeV7(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return NewStorageProvisionerAPIv8(v7), nil
+}
+
 type Backend interface {
 	state.EntityFinder
 	state.ModelAccessor
@@ -84,6 +120,13 @@ type StorageBackend interface {
 	WatchUnitVolumeAttachments(tag names.ApplicationTag) state.StringsWatcher
 	WatchVolumeAttachment(names.Tag, names.VolumeTag) state.NotifyWatcher
 	WatchMachineAttachmentsPlans(names.MachineTag) state.StringsWatcher
+	WatchModelStorageSnapshots() state.StringsWatcher
+	WatchMachineStorageSnapshots(names.MachineTag) state.StringsWatcher
+	WatchModelVolumeResizes() state.StringsWatcher
+	WatchMachineVolumeResizes(names.MachineTag) state.StringsWatcher
+	WatchModelFilesystemResizes() state.StringsWatcher
+	WatchMachineFilesystemResizes(names.MachineTag) state.StringsWatcher
+	WatchMachineVolumeCopies(names.MachineTag) state.StringsWatcher
 
 	StorageInstance(names.StorageTag) (state.StorageInstance, error)
 	AllStorageInstances() ([]state.StorageInstance, error)
@@ -118,6 +161,20 @@ type StorageBackend interface {
 	CreateVolumeAttachmentPlan(names.Tag, names.VolumeTag, state.VolumeAttachmentPlanInfo) error
 	RemoveVolumeAttachmentPlan(names.Tag, names.VolumeTag) error
 	SetVolumeAttachmentPlanBlockInfo(machineTag names.Tag, volumeTag names.VolumeTag, info state.BlockDeviceInfo) error
+
+	StorageSnapshot(string) (state.StorageSnapshot, error)
+	SetStorageSnapshotId(id, snapshotId string) error
+	SetStorageSnapshotFailed(id, message string) error
+	RemoveStorageSnapshot(id string) error
+
+	PendingStorageResize(names.Tag) (uint64, error)
+	SetStorageResized(names.Tag, uint64) error
+	SetStorageResizeFailed(tag names.Tag, message string) error
+
+	PendingVolumeCopy(names.MachineTag, names.VolumeTag) (state.VolumeCopy, error)
+	SetVolumeCopied(names.MachineTag, names.VolumeTag) error
+	SetVolumeCopyFailed(host names.MachineTag, target names.VolumeTag, message string) error
+	VolumeEncryptionKey(names.Tag, names.VolumeTag) (string, error)
 }
 
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
@@ -23,6 +23,26 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.storageprovisioner")
 
+// StorageProvisionerAPIv8 provides the StorageProvisioner API v8 facade.
+type StorageProvisionerAPIv8 struct {
+	*StorageProvisionerAPIv7
+}
+
+// StorageProvisionerAPIv7 provides the StorageProvisioner API v7 facade.
+type StorageProvisionerAPIv7 struct {
+	*StorageProvisionerAPIv6
+}
+
+// StorageProvisionerAPIv6 provides the StorageProvisioner API v6 facade.
+type StorageProvisionerAPIv6 struct {
+	*StorageProvisionerAPIv5
+}
+
+// StorageProvisionerAPIv5 provides the StorageProvisioner API v5 facade.
+type StorageProvisionerAPIv5 struct {
+	*StorageProvisionerAPIv4
+}
+
 // StorageProvisionerAPIv4 provides the StorageProvisioner API v4 facade.
 type StorageProvisionerAPIv4 struct {
 	*StorageProvisionerAPIv3
@@ -48,6 +68,26 @@ type StorageProvisionerAPIv3 struct {
 	getAttachmentAuthFunc    func() (func(names.Tag, names.Tag) bool, error)
 }
 
+// NewStorageProvisionerAPIv8 creates a new server-side StorageProvisioner v8 facade.
+func NewStorageProvisionerAPIv8(v7 *StorageProvisionerAPIv7) *StorageProvisionerAPIv8 {
+	return &StorageProvisionerAPIv8{v7}
+}
+
+// NewStorageProvisionerAPIv7 creates a new server-side StorageProvisioner v7 facade.
+func NewStorageProvisionerAPIv7(v6 *StorageProvisionerAPIv6) *StorageProvisionerAPIv7 {
+	return &StorageProvisionerAPIv7{v6}
+}
+
+// NewStorageProvisionerAPIv6 creates a new server-side StorageProvisioner v6 facade.
+func NewStorageProvisionerAPIv6(v5 *StorageProvisionerAPIv5) *StorageProvisionerAPIv6 {
+	return &StorageProvisionerAPIv6{v5}
+}
+
+// NewStorageProvisionerAPIv5 creates a new server-side StorageProvisioner v5 facade.
+func NewStorageProvisionerAPIv5(v4 *StorageProvisionerAPIv4) *StorageProvisionerAPIv5 {
+	return &StorageProvisionerAPIv5{v4}
+}
+
 // NewStorageProvisionerAPIv4 creates a new server-side StorageProvisioner v4 facade.
 func NewStorageProvisionerAPIv4(v3 *StorageProvisionerAPIv3) *StorageProvisionerAPIv4 {
 	return &StorageProvisionerAPIv4{v3}
@@ -768,6 +808,12 @@ func (s *StorageProvisionerAPIv3) VolumeParams(args params.Entities) (params.Vol
 		if err != nil {
 			return params.VolumeParams{}, err
 		}
+		if stateVolumeParams, ok := volume.Params(); ok && stateVolumeParams.Snapshot != "" {
+			volumeParams.SnapshotId, err = s.storageSnapshotId(stateVolumeParams.Snapshot)
+			if err != nil {
+				return params.VolumeParams{}, err
+			}
+		}
 		if len(volumeAttachments) == 1 {
 			// There is exactly one attachment to be made, so make
 			// it immediately. Otherwise we will defer attachments
@@ -916,6 +962,12 @@ func (s *StorageProvisionerAPIv3) FilesystemParams(args params.Entities) (params
 		if err != nil {
 			return params.FilesystemParams{}, err
 		}
+		if stateFilesystemParams, ok := filesystem.Params(); ok && stateFilesystemParams.Snapshot != "" {
+			filesystemParams.SnapshotId, err = s.storageSnapshotId(stateFilesystemParams.Snapshot)
+			if err != nil {
+				return params.FilesystemParams{}, err
+			}
+		}
 		return filesystemParams, nil
 	}
 	for i, arg := range args.Entities {
@@ -1606,3 +1658,498 @@ func (s *StorageProvisionerAPIv3) RemoveAttachment(args params.MachineStorageIds
 	}
 	return results, nil
 }
+
+// storageSnapshotId returns the provider ID of the storage snapshot
+// with the specified ID, from which a volume or filesystem is to be
+// created.
+func (s *StorageProvisionerAPIv3) storageSnapshotId(id string) (string, error) {
+	snapshot, err := s.sb.StorageSnapshot(id)
+	if err != nil {
+		return "", errors.Annotatef(err, "getting storage snapshot %q", id)
+	}
+	snapshotId, err := snapshot.SnapshotId()
+	if err != nil {
+		return "", errors.Annotatef(err, "getting storage snapshot %q", id)
+	}
+	return snapshotId, nil
+}
+
+// WatchStorageSnapshots watches for changes to storage snapshots of
+// volumes and filesystems scoped to the entity with the tag passed
+// to NewState.
+func (s *StorageProvisionerAPIv5) WatchStorageSnapshots(args params.Entities) (params.StringsWatchResults, error) {
+	for _, arg := range args.Entities {
+		// Snapshots are not supported for CAAS models.
+		if tag, err := names.ParseTag(arg.Tag); err == nil && tag.Kind() == names.ApplicationTagKind {
+			return params.StringsWatchResults{}, common.ServerError(
+				errors.NotSupportedf("watching storage snapshots for %v", tag),
+			)
+		}
+	}
+	return s.watchStorageEntities(args, s.sb.WatchModelStorageSnapshots, s.sb.WatchMachineStorageSnapshots, nil)
+}
+
+// StorageSnapshotParams returns the parameters for creating or deleting
+// the storage snapshots with the specified IDs.
+func (s *StorageProvisionerAPIv5) StorageSnapshotParams(args params.StorageSnapshotIds) (params.StorageSnapshotParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.StorageSnapshotParamsResults{}, err
+	}
+	modelConfig, err := s.st.ModelConfig()
+	if err != nil {
+		return params.StorageSnapshotParamsResults{}, err
+	}
+	controllerCfg, err := s.st.ControllerConfig()
+	if err != nil {
+		return params.StorageSnapshotParamsResults{}, err
+	}
+	results := params.StorageSnapshotParamsResults{
+		Results: make([]params.StorageSnapshotParamsResult, len(args.Ids)),
+	}
+	one := func(id string) (*params.StorageSnapshotParams, error) {
+		snapshot, err := s.storageSnapshot(id, canAccess)
+		if err != nil {
+			return nil, err
+		}
+		result := &params.StorageSnapshotParams{
+			Id:   id,
+			Life: life.Value(snapshot.Life().String()),
+		}
+		if snapshot.Life() != state.Alive {
+			// The snapshot is to be deleted, which requires
+			// only its provider ID. The snapshotted volume
+			// or filesystem may since have been removed.
+			result.SnapshotId, err = snapshot.SnapshotId()
+			if err != nil {
+				return nil, err
+			}
+			if volumeTag, err := snapshot.Volume(); err == nil {
+				result.VolumeTag = volumeTag.String()
+			} else if err != state.ErrNoBackingVolume {
+				return nil, err
+			} else {
+				filesystemTag, err := snapshot.Filesystem()
+				if err != nil {
+					return nil, err
+				}
+				result.FilesystemTag = filesystemTag.String()
+			}
+			if err := s.setStorageSnapshotProvider(result, snapshot.Pool()); err != nil {
+				return nil, err
+			}
+			return result, nil
+		}
+		if status, _ := snapshot.Status(); status != state.StorageSnapshotPending {
+			return nil, errors.Errorf("storage snapshot %q is %s", id, status)
+		}
+		if volumeTag, err := snapshot.Volume(); err == nil {
+			volume, err := s.sb.Volume(volumeTag)
+			if err != nil {
+				return nil, err
+			}
+			volumeInfo, err := volume.Info()
+			if err != nil {
+				return nil, err
+			}
+			result.VolumeTag = volumeTag.String()
+			result.VolumeId = volumeInfo.VolumeId
+		} else if err != state.ErrNoBackingVolume {
+			return nil, err
+		} else {
+			filesystemTag, err := snapshot.Filesystem()
+			if err != nil {
+				return nil, err
+			}
+			filesystem, err := s.sb.Filesystem(filesystemTag)
+			if err != nil {
+				return nil, err
+			}
+			filesystemInfo, err := filesystem.Info()
+			if err != nil {
+				return nil, err
+			}
+			result.FilesystemTag = filesystemTag.String()
+			result.FilesystemId = filesystemInfo.FilesystemId
+		}
+		storageInstance, err := s.sb.StorageInstance(snapshot.StorageInstance())
+		if err != nil {
+			return nil, err
+		}
+		result.Tags, err = storagecommon.StorageTags(
+			storageInstance, modelConfig.UUID(), controllerCfg.ControllerUUID(), modelConfig,
+		)
+		if err != nil {
+			return nil, errors.Annotate(err, "computing storage tags")
+		}
+		if err := s.setStorageSnapshotProvider(result, snapshot.Pool()); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	for i, id := range args.Ids {
+		var result params.StorageSnapshotParamsResult
+		snapshotParams, err := one(id)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = snapshotParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// setStorageSnapshotProvider sets the storage provider type and
+// attributes of the named pool in the snapshot parameters.
+func (s *StorageProvisionerAPIv5) setStorageSnapshotProvider(result *params.StorageSnapshotParams, pool string) error {
+	providerType, cfg, err := storagecommon.StoragePoolConfig(pool, s.poolManager, s.registry)
+	if err != nil {
+		return err
+	}
+	result.Provider = string(providerType)
+	result.Attributes = cfg.Attrs()
+	return nil
+}
+
+// SetStorageSnapshotResults records the outcome of creating storage
+// snapshots: either the provider's snapshot ID, or the reason the
+// snapshot could not be created.
+func (s *StorageProvisionerAPIv5) SetStorageSnapshotResults(args params.StorageSnapshotResults) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Results)),
+	}
+	one := func(arg params.StorageSnapshotResult) error {
+		if _, err := s.storageSnapshot(arg.Id, canAccess); err != nil {
+			return err
+		}
+		if arg.SnapshotId != "" {
+			return s.sb.SetStorageSnapshotId(arg.Id, arg.SnapshotId)
+		}
+		return s.sb.SetStorageSnapshotFailed(arg.Id, arg.Message)
+	}
+	for i, arg := range args.Results {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// RemoveStorageSnapshots removes the dying storage snapshots with the
+// specified IDs, once they have been deleted from the storage provider.
+func (s *StorageProvisionerAPIv5) RemoveStorageSnapshots(args params.StorageSnapshotIds) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Ids)),
+	}
+	one := func(id string) error {
+		if _, err := s.storageSnapshot(id, canAccess); err != nil {
+			return err
+		}
+		return s.sb.RemoveStorageSnapshot(id)
+	}
+	for i, id := range args.Ids {
+		err := one(id)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// storageSnapshot returns the storage snapshot with the specified ID,
+// if the authenticated agent may access the volume or filesystem that
+// it is a snapshot of.
+func (s *StorageProvisionerAPIv5) storageSnapshot(id string, canAccess common.AuthFunc) (state.StorageSnapshot, error) {
+	snapshot, err := s.sb.StorageSnapshot(id)
+	if errors.IsNotFound(err) {
+		return nil, common.ErrPerm
+	} else if err != nil {
+		return nil, err
+	}
+	var tag names.Tag
+	if volumeTag, err := snapshot.Volume(); err == nil {
+		tag = volumeTag
+	} else if err != state.ErrNoBackingVolume {
+		return nil, err
+	} else if tag, err = snapshot.Filesystem(); err != nil {
+		return nil, err
+	}
+	if !canAccess(tag) {
+		return nil, common.ErrPerm
+	}
+	return snapshot, nil
+}
+
+// WatchVolumeResizes watches for requests to resize volumes scoped
+// to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPIv6) WatchVolumeResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.sb.WatchModelVolumeResizes, s.sb.WatchMachineVolumeResizes, nil)
+}
+
+// WatchFilesystemResizes watches for requests to resize filesystems
+// scoped to the entity with the tag passed to NewState.
+func (s *StorageProvisionerAPIv6) WatchFilesystemResizes(args params.Entities) (params.StringsWatchResults, error) {
+	return s.watchStorageEntities(args, s.sb.WatchModelFilesystemResizes, s.sb.WatchMachineFilesystemResizes, nil)
+}
+
+// StorageResizeParams returns the parameters for resizing the volumes
+// and filesystems with the specified tags.
+func (s *StorageProvisionerAPIv6) StorageResizeParams(args params.Entities) (params.StorageResizeParamsResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.StorageResizeParamsResults{}, err
+	}
+	results := params.StorageResizeParamsResults{
+		Results: make([]params.StorageResizeParamsResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) (*params.StorageResizeParams, error) {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return nil, common.ErrPerm
+		}
+		size, err := s.sb.PendingStorageResize(tag)
+		if err != nil {
+			return nil, err
+		}
+		result := &params.StorageResizeParams{Tag: tag.String(), Size: size}
+		var pool string
+		switch tag := tag.(type) {
+		case names.VolumeTag:
+			volume, err := s.sb.Volume(tag)
+			if err != nil {
+				return nil, err
+			}
+			volumeInfo, err := volume.Info()
+			if err != nil {
+				return nil, err
+			}
+			result.ProviderId = volumeInfo.VolumeId
+			pool = volumeInfo.Pool
+		case names.FilesystemTag:
+			filesystem, err := s.sb.Filesystem(tag)
+			if err != nil {
+				return nil, err
+			}
+			filesystemInfo, err := filesystem.Info()
+			if err != nil {
+				return nil, err
+			}
+			result.ProviderId = filesystemInfo.FilesystemId
+			pool = filesystemInfo.Pool
+			if volumeTag, err := filesystem.Volume(); err == nil {
+				result.VolumeTag = volumeTag.String()
+			} else if err != state.ErrNoBackingVolume {
+				return nil, err
+			}
+		}
+		providerType, cfg, err := storagecommon.StoragePoolConfig(pool, s.poolManager, s.registry)
+		if err != nil {
+			return nil, err
+		}
+		result.Provider = string(providerType)
+		result.Attributes = cfg.Attrs()
+		return result, nil
+	}
+	for i, arg := range args.Entities {
+		var result params.StorageResizeParamsResult
+		resizeParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = resizeParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// SetStorageResizeResults records the outcome of resizing volumes and
+// filesystems: either the new size, or the reason the resize failed.
+func (s *StorageProvisionerAPIv6) SetStorageResizeResults(args params.StorageResizeResults) (params.ErrorResults, error) {
+	canAccess, err := s.getStorageEntityAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Results)),
+	}
+	one := func(arg params.StorageResizeResult) error {
+		tag, err := names.ParseTag(arg.Tag)
+		if err != nil || !canAccess(tag) {
+			return common.ErrPerm
+		}
+		if arg.Size != 0 {
+			return s.sb.SetStorageResized(tag, arg.Size)
+		}
+		return s.sb.SetStorageResizeFailed(tag, arg.Message)
+	}
+	for i, arg := range args.Results {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// WatchVolumeCopies watches for requests to copy the contents of
+// volumes attached to the specified machines. The watchers report
+// the IDs of the volumes to copy to.
+func (s *StorageProvisionerAPIv7) WatchVolumeCopies(args params.Entities) (params.StringsWatchResults, error) {
+	canAccess, err := s.getBlockDevicesAuthFunc()
+	if err != nil {
+		return params.StringsWatchResults{}, common.ServerError(common.ErrPerm)
+	}
+	results := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	one := func(arg params.Entity) (string, []string, error) {
+		machineTag, err := names.ParseMachineTag(arg.Tag)
+		if err != nil || !canAccess(machineTag) {
+			return "", nil, common.ErrPerm
+		}
+		w := s.sb.WatchMachineVolumeCopies(machineTag)
+		if changes, ok := <-w.Changes(); ok {
+			return s.resources.Register(w), changes, nil
+		}
+		return "", nil, watcher.EnsureErr(w)
+	}
+	for i, arg := range args.Entities {
+		var result params.StringsWatchResult
+		id, changes, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.StringsWatcherId = id
+			result.Changes = changes
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// VolumeCopyParams returns the parameters for copying data to the
+// volumes attached to machines with the specified IDs.
+func (s *StorageProvisionerAPIv7) VolumeCopyParams(args params.MachineStorageIds) (params.VolumeCopyParamsResults, error) {
+	canAccess, err := s.getAttachmentAuthFunc()
+	if err != nil {
+		return params.VolumeCopyParamsResults{}, common.ServerError(common.ErrPerm)
+	}
+	results := params.VolumeCopyParamsResults{
+		Results: make([]params.VolumeCopyParamsResult, len(args.Ids)),
+	}
+	one := func(arg params.MachineStorageId) (*params.VolumeCopyParams, error) {
+		machineTag, volumeTag, err := parseMachineVolumeId(arg, canAccess)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := s.sb.PendingVolumeCopy(machineTag, volumeTag)
+		if err != nil {
+			return nil, err
+		}
+		result := &params.VolumeCopyParams{
+			MachineTag:      machineTag.String(),
+			SourceVolumeTag: pending.Source.String(),
+			TargetVolumeTag: volumeTag.String(),
+		}
+		if pending.Filesystem.Id() != "" {
+			result.FilesystemTag = pending.Filesystem.String()
+		}
+		return result, nil
+	}
+	for i, arg := range args.Ids {
+		var result params.VolumeCopyParamsResult
+		copyParams, err := one(arg)
+		if err != nil {
+			result.Error = common.ServerError(err)
+		} else {
+			result.Result = copyParams
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
+
+// SetVolumeCopyResults records the outcome of copying data to volumes:
+// either success, or the reason the copy failed.
+func (s *StorageProvisionerAPIv7) SetVolumeCopyResults(args params.VolumeCopyResults) (params.ErrorResults, error) {
+	canAccess, err := s.getAttachmentAuthFunc()
+	if err != nil {
+		return params.ErrorResults{}, common.ServerError(common.ErrPerm)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Results)),
+	}
+	one := func(arg params.VolumeCopyResult) error {
+		machineTag, volumeTag, err := parseMachineVolumeId(params.MachineStorageId{
+			MachineTag:    arg.MachineTag,
+			AttachmentTag: arg.VolumeTag,
+		}, canAccess)
+		if err != nil {
+			return err
+		}
+		if arg.Message == "" {
+			return s.sb.SetVolumeCopied(machineTag, volumeTag)
+		}
+		return s.sb.SetVolumeCopyFailed(machineTag, volumeTag, arg.Message)
+	}
+	for i, arg := range args.Results {
+		err := one(arg)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// VolumeEncryptionKeys returns the keys with which the machine agent
+// is to encrypt the volumes attached to machines with the specified IDs.
+// Keys are released only to the agent of the machine to which the volume
+// is attached.
+func (s *StorageProvisionerAPIv8) VolumeEncryptionKeys(args params.MachineStorageIds) (params.StringResults, error) {
+	canAccess, err := s.getAttachmentAuthFunc()
+	if err != nil {
+		return params.StringResults{}, common.ServerError(common.ErrPerm)
+	}
+	results := params.StringResults{
+		Results: make([]params.StringResult, len(args.Ids)),
+	}
+	one := func(arg params.MachineStorageId) (string, error) {
+		machineTag, volumeTag, err := parseMachineVolumeId(arg, canAccess)
+		if err != nil {
+			return "", err
+		}
+		if !s.authorizer.AuthOwner(machineTag) {
+			return "", common.ErrPerm
+		}
+		return s.sb.VolumeEncryptionKey(machineTag, volumeTag)
+	}
+	for i, arg := range args.Ids {
+		key, err := one(arg)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+		} else {
+			results.Results[i].Result = key
+		}
+	}
+	return results, nil
+}
+
+// parseMachineVolumeId parses the machine and volume tags from the
+// specified machine storage ID, and checks that the authenticated
+// agent can access the volume attachment.
+func parseMachineVolumeId(
+	id params.MachineStorageId, canAccess func(names.Tag, names.Tag) bool,
+) (names.MachineTag, names.VolumeTag, error) {
+	machineTag, err := names.ParseMachineTag(id.MachineTag)
+	if err != nil {
+		return names.MachineTag{}, names.VolumeTag{}, common.ErrPerm
+	}
+	volumeTag, err := names.ParseVolumeTag(id.AttachmentTag)
+	if err != nil || !canAccess(machineTag, volumeTag) {
+		return names.MachineTag{}, names.VolumeTag{}, common.ErrPerm
+	}
+	return machineTag, volumeTag, nil
+}
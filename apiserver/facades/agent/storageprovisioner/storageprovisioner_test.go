@@ -55,7 +55,7 @@ type provisionerSuite struct {
 
 	resources      *common.Resources
 	authorizer     *apiservertesting.FakeAuthorizer
-	api            *storageprovisioner.StorageProvisionerAPIv4
+	api            *storageprovisioner.StorageProvisionerAPIv8
 	storageBackend storageprovisioner.StorageBackend
 }
 
@@ -86,7 +86,11 @@ func (s *iaasProvisionerSuite) SetUpTest(c *gc.C) {
 	s.storageBackend = storageBackend
 	v3, err := storageprovisioner.NewStorageProvisionerAPIv3(backend, storageBackend, s.resources, s.authorizer, registry, pm)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = storageprovisioner.NewStorageProvisionerAPIv4(v3)
+	s.api = storageprovisioner.NewStorageProvisionerAPIv8(storageprovisioner.NewStorageProvisionerAPIv7(
+		storageprovisioner.NewStorageProvisionerAPIv6(
+			storageprovisioner.NewStorageProvisionerAPIv5(storageprovisioner.NewStorageProvisionerAPIv4(v3)),
+		),
+	))
 }
 
 func (s *caasProvisionerSuite) SetUpTest(c *gc.C) {
@@ -118,7 +122,11 @@ func (s *caasProvisionerSuite) SetUpTest(c *gc.C) {
 	s.storageBackend = storageBackend
 	v3, err := storageprovisioner.NewStorageProvisionerAPIv3(backend, storageBackend, s.resources, s.authorizer, registry, pm)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = storageprovisioner.NewStorageProvisionerAPIv4(v3)
+	s.api = storageprovisioner.NewStorageProvisionerAPIv8(storageprovisioner.NewStorageProvisionerAPIv7(
+		storageprovisioner.NewStorageProvisionerAPIv6(
+			storageprovisioner.NewStorageProvisionerAPIv5(storageprovisioner.NewStorageProvisionerAPIv4(v3)),
+		),
+	))
 }
 
 func (s *provisionerSuite) TestNewStorageProvisionerAPINonMachine(c *gc.C) {
@@ -752,6 +760,469 @@ func (s *iaasProvisionerSuite) TestRemoveFilesystemParams(c *gc.C) {
 	})
 }
 
+func (s *iaasProvisionerSuite) setupStorageSnapshot(c *gc.C) (names.StorageTag, names.VolumeTag, string) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {
+				Count: 1,
+				Size:  1024,
+				Pool:  "modelscoped",
+			},
+			"allecto": {
+				Count: 1,
+				Size:  1024,
+				Pool:  "modelscoped",
+			},
+		},
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+	})
+	// Snapshot the "allecto" storage, so that it can
+	// be restored to the same unit.
+	allStorage, err := s.storageBackend.AllStorageInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	var storageTag names.StorageTag
+	for _, storageInstance := range allStorage {
+		if storageInstance.StorageName() == "allecto" {
+			storageTag = storageInstance.StorageTag()
+		}
+	}
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeInfo(storageVolume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "zing",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := sb.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	return storageTag, storageVolume.VolumeTag(), id
+}
+
+func (s *iaasProvisionerSuite) TestStorageSnapshotParams(c *gc.C) {
+	storageTag, volumeTag, id := s.setupStorageSnapshot(c)
+
+	results, err := s.api.StorageSnapshotParams(params.StorageSnapshotIds{
+		Ids: []string{id, "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageSnapshotParamsResults{
+		Results: []params.StorageSnapshotParamsResult{
+			{Result: &params.StorageSnapshotParams{
+				Id:        id,
+				Life:      life.Alive,
+				VolumeTag: volumeTag.String(),
+				VolumeId:  "zing",
+				Provider:  "modelscoped",
+				Tags: map[string]string{
+					tags.JujuController:      testing.ControllerTag.Id(),
+					tags.JujuModel:           testing.ModelTag.Id(),
+					tags.JujuStorageInstance: storageTag.Id(),
+					tags.JujuStorageOwner:    "storage-block/0",
+				},
+			}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestSetStorageSnapshotResults(c *gc.C) {
+	_, _, id := s.setupStorageSnapshot(c)
+
+	results, err := s.api.SetStorageSnapshotResults(params.StorageSnapshotResults{
+		Results: []params.StorageSnapshotResult{
+			{Id: id, SnapshotId: "snap-zing"},
+			{Id: "42", SnapshotId: "snap-42"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshotId, err := snapshot.SnapshotId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "snap-zing")
+
+	// The snapshot is no longer pending, so its
+	// parameters are no longer available.
+	paramsResults, err := s.api.StorageSnapshotParams(params.StorageSnapshotIds{
+		Ids: []string{id},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paramsResults.Results[0].Error, gc.ErrorMatches, `storage snapshot ".*" is available`)
+}
+
+func (s *iaasProvisionerSuite) TestSetStorageSnapshotResultsFailed(c *gc.C) {
+	_, _, id := s.setupStorageSnapshot(c)
+
+	results, err := s.api.SetStorageSnapshotResults(params.StorageSnapshotResults{
+		Results: []params.StorageSnapshotResult{
+			{Id: id, Message: "snapshots not supported"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotFailed)
+	c.Assert(message, gc.Equals, "snapshots not supported")
+}
+
+func (s *iaasProvisionerSuite) TestStorageSnapshotParamsDying(c *gc.C) {
+	_, volumeTag, id := s.setupStorageSnapshot(c)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.SetStorageSnapshotId(id, "snap-zing")
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.StorageSnapshotParams(params.StorageSnapshotIds{
+		Ids: []string{id},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageSnapshotParamsResults{
+		Results: []params.StorageSnapshotParamsResult{
+			{Result: &params.StorageSnapshotParams{
+				Id:         id,
+				Life:       life.Dying,
+				SnapshotId: "snap-zing",
+				VolumeTag:  volumeTag.String(),
+				Provider:   "modelscoped",
+			}},
+		},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestRemoveStorageSnapshots(c *gc.C) {
+	_, _, id := s.setupStorageSnapshot(c)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.SetStorageSnapshotId(id, "snap-zing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Alive snapshots cannot be removed.
+	results, err := s.api.RemoveStorageSnapshots(params.StorageSnapshotIds{
+		Ids: []string{id, "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: &params.Error{Message: `cannot remove storage snapshot "` + id + `": snapshot is not dying`}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+
+	err = sb.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	results, err = s.api.RemoveStorageSnapshots(params.StorageSnapshotIds{
+		Ids: []string{id},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	_, err = s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *iaasProvisionerSuite) TestVolumeParamsFromStorageSnapshot(c *gc.C) {
+	_, _, id := s.setupStorageSnapshot(c)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.SetStorageSnapshotId(id, "snap-zing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	storageTag, err := sb.RestoreStorage(id, names.NewUnitTag("storage-block/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := s.storageBackend.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.VolumeParams(params.Entities{
+		Entities: []params.Entity{{volume.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.SnapshotId, gc.Equals, "snap-zing")
+}
+
+func (s *iaasProvisionerSuite) setupStorageResize(c *gc.C) names.VolumeTag {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {
+				Count: 1,
+				Size:  1024,
+				Pool:  "modelscoped",
+			},
+		},
+	})
+	s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+	})
+	storageTag := names.NewStorageTag("data/0")
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeInfo(storageVolume.VolumeTag(), state.VolumeInfo{
+		VolumeId: "zing",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	return storageVolume.VolumeTag()
+}
+
+func (s *iaasProvisionerSuite) TestStorageResizeParams(c *gc.C) {
+	volumeTag := s.setupStorageResize(c)
+
+	results, err := s.api.StorageResizeParams(params.Entities{
+		Entities: []params.Entity{{volumeTag.String()}, {"volume-42"}, {"machine-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StorageResizeParamsResults{
+		Results: []params.StorageResizeParamsResult{
+			{Result: &params.StorageResizeParams{
+				Tag:        volumeTag.String(),
+				ProviderId: "zing",
+				Size:       2048,
+				Provider:   "modelscoped",
+			}},
+			{Error: &params.Error{Message: `pending resize of "42" not found`, Code: "not found"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestSetStorageResizeResults(c *gc.C) {
+	volumeTag := s.setupStorageResize(c)
+
+	results, err := s.api.SetStorageResizeResults(params.StorageResizeResults{
+		Results: []params.StorageResizeResult{
+			{Tag: volumeTag.String(), Size: 2048},
+			{Tag: "machine-0", Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+
+	volume, err := s.storageBackend.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeInfo, err := volume.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeInfo.Size, gc.Equals, uint64(2048))
+	_, err = s.storageBackend.PendingStorageResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *iaasProvisionerSuite) TestSetStorageResizeResultsFailed(c *gc.C) {
+	volumeTag := s.setupStorageResize(c)
+
+	results, err := s.api.SetStorageResizeResults(params.StorageResizeResults{
+		Results: []params.StorageResizeResult{
+			{Tag: volumeTag.String(), Message: "resizing not supported"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	volume, err := s.storageBackend.Volume(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeStatus, err := volume.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeStatus.Message, gc.Equals, "resizing to 2048MiB failed: resizing not supported")
+	_, err = s.storageBackend.PendingStorageResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+// setupVolumeCopy moves a unit's block storage from the "machinescoped"
+// pool to the "modelscoped" pool, and quiesces the storage as the unit
+// does after running its "storage-detaching" hook. The tags of the
+// source and target volumes are returned.
+func (s *iaasProvisionerSuite) setupVolumeCopy(c *gc.C) (names.VolumeTag, names.VolumeTag) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{
+			Name: "storage-block",
+		}),
+		Storage: map[string]state.StorageConstraints{
+			"data": {
+				Count: 1,
+				Size:  1024,
+				Pool:  "machinescoped",
+			},
+		},
+	})
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{
+		Application: application,
+	})
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machineId, gc.Equals, "0")
+	machineTag := names.NewMachineTag(machineId)
+
+	storageTag := names.NewStorageTag("data/0")
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	source := storageVolume.VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(source, state.VolumeInfo{
+		VolumeId: "zing",
+		Size:     1024,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(machineTag, source, state.VolumeAttachmentInfo{
+		DeviceName: "sdb",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.MoveStorageInstance(storageTag, "modelscoped")
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.RemoveStorageAttachment(storageTag, unit.UnitTag(), false)
+	c.Assert(err, jc.ErrorIsNil)
+	return source, names.NewVolumeTag("1")
+}
+
+func (s *iaasProvisionerSuite) TestVolumeCopyParams(c *gc.C) {
+	source, target := s.setupVolumeCopy(c)
+
+	results, err := s.api.VolumeCopyParams(params.MachineStorageIds{
+		Ids: []params.MachineStorageId{
+			{MachineTag: "machine-0", AttachmentTag: target.String()},
+			{MachineTag: "machine-0", AttachmentTag: "volume-42"},
+			{MachineTag: "machine-1", AttachmentTag: target.String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.VolumeCopyParamsResults{
+		Results: []params.VolumeCopyParamsResult{
+			{Result: &params.VolumeCopyParams{
+				MachineTag:      "machine-0",
+				SourceVolumeTag: source.String(),
+				TargetVolumeTag: target.String(),
+			}},
+			{Error: &params.Error{Message: `pending copy to volume attachment "0:42" not found`, Code: "not found"}},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+}
+
+func (s *iaasProvisionerSuite) TestSetVolumeCopyResults(c *gc.C) {
+	_, target := s.setupVolumeCopy(c)
+
+	results, err := s.api.SetVolumeCopyResults(params.VolumeCopyResults{
+		Results: []params.VolumeCopyResult{
+			{MachineTag: "machine-0", VolumeTag: target.String()},
+			{MachineTag: "machine-1", VolumeTag: target.String()},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+		},
+	})
+
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageVolume.VolumeTag(), gc.Equals, target)
+	_, err = s.storageBackend.PendingVolumeCopy(names.NewMachineTag("0"), target)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *iaasProvisionerSuite) TestSetVolumeCopyResultsFailed(c *gc.C) {
+	source, target := s.setupVolumeCopy(c)
+
+	results, err := s.api.SetVolumeCopyResults(params.VolumeCopyResults{
+		Results: []params.VolumeCopyResult{
+			{MachineTag: "machine-0", VolumeTag: target.String(), Message: "out of space"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+
+	storageVolume, err := s.storageBackend.StorageInstanceVolume(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageVolume.VolumeTag(), gc.Equals, source)
+	volumeStatus, err := storageVolume.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeStatus.Message, gc.Equals, `moving to pool "modelscoped" failed: out of space`)
+}
+
+func (s *iaasProvisionerSuite) TestVolumeEncryptionKeys(c *gc.C) {
+	env, err := stateenvirons.GetNewEnvironFunc(environs.New)(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	registry := stateenvirons.NewStorageProviderRegistry(env)
+	pm := poolmanager.New(state.NewStateSettings(s.State), registry)
+	_, err = pm.Create("encrypted", "modelscoped", map[string]interface{}{
+		"encrypted": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Volume 0 is in the encrypted pool, and volume 1 is not.
+	machine := s.Factory.MakeMachine(c, nil)
+	c.Assert(machine.Id(), gc.Equals, "0")
+	ch := s.Factory.MakeCharm(c, &factory.CharmParams{
+		Name: "storage-block",
+	})
+	for _, pool := range []string{"encrypted", "modelscoped"} {
+		application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+			Name:  "storage-" + pool,
+			Charm: ch,
+			Storage: map[string]state.StorageConstraints{
+				"data": {Count: 1, Size: 1024, Pool: pool},
+			},
+		})
+		s.Factory.MakeUnit(c, &factory.UnitParams{
+			Application: application,
+			Machine:     machine,
+		})
+	}
+
+	results, err := s.api.VolumeEncryptionKeys(params.MachineStorageIds{
+		Ids: []params.MachineStorageId{
+			{MachineTag: "machine-0", AttachmentTag: "volume-0"},
+			{MachineTag: "machine-0", AttachmentTag: "volume-1"},
+			{MachineTag: "machine-1", AttachmentTag: "volume-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result, gc.Not(gc.Equals), "")
+	c.Assert(results.Results[1:], jc.DeepEquals, []params.StringResult{
+		{Error: &params.Error{Message: `encryption key for volume "1" not found`, Code: "not found"}},
+		// Keys are only released to the machine agent
+		// to which the volume is attached.
+		{Error: &params.Error{Message: "permission denied", Code: "unauthorized access"}},
+	})
+}
+
 func (s *iaasProvisionerSuite) TestVolumeAttachmentParams(c *gc.C) {
 	// Only IAAS models support block storage right now.
 	s.setupVolumes(c)
@@ -1056,6 +1527,94 @@ func (s *iaasProvisionerSuite) TestWatchVolumes(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *iaasProvisionerSuite) TestWatchStorageSnapshots(c *gc.C) {
+	_, _, id := s.setupStorageSnapshot(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.Model.ModelTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchStorageSnapshots(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{}},
+			{StringsWatcherId: "2", Changes: []string{id}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resources were registered and stop them when done.
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	w0 := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w0)
+	w1 := s.resources.Get("2")
+	defer statetesting.AssertStop(c, w1)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, w1.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
+
+func (s *iaasProvisionerSuite) TestWatchVolumeResizes(c *gc.C) {
+	volumeTag := s.setupStorageResize(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.Model.ModelTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchVolumeResizes(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{}},
+			{StringsWatcherId: "2", Changes: []string{volumeTag.Id()}},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resources were registered and stop them when done.
+	c.Assert(s.resources.Count(), gc.Equals, 2)
+	w0 := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w0)
+	w1 := s.resources.Get("2")
+	defer statetesting.AssertStop(c, w1)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, w1.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
+
+func (s *iaasProvisionerSuite) TestWatchVolumeCopies(c *gc.C) {
+	_, target := s.setupVolumeCopy(c)
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{"machine-0"},
+		{s.Model.ModelTag().String()},
+		{"machine-42"}},
+	}
+	result, err := s.api.WatchVolumeCopies(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{StringsWatcherId: "1", Changes: []string{target.Id()}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop it when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	w := s.resources.Get("1")
+	defer statetesting.AssertStop(c, w)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, w.(state.StringsWatcher))
+	wc.AssertNoChange()
+}
+
 func (s *iaasProvisionerSuite) TestWatchVolumeAttachments(c *gc.C) {
 	// Only IAAS models support block storage right now.
 	s.setupVolumes(c)
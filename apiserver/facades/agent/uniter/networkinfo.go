@@ -11,6 +11,7 @@ import (
 	"github.com/juju/clock"
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	"github.com/juju/featureflag"
 	k8sprovider "github.com/juju/juju/caas/kubernetes/provider"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -21,7 +22,9 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/common/networkingcommon"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/instance"
 	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/feature"
 )
 
 // TODO (manadart 2019-10-09):
@@ -50,6 +53,7 @@ type NetworkInfo struct {
 	unit          *state.Unit
 	app           *state.Application
 	defaultEgress []string
+	preferIPv6    bool
 	bindings      map[string]string
 	spaces        []*state.Space
 }
@@ -81,7 +85,7 @@ func (n *NetworkInfo) init(tag names.UnitTag) error {
 	}
 	n.bindings = bindings.Map()
 
-	if n.defaultEgress, err = n.getModelEgressSubnets(); err != nil {
+	if n.defaultEgress, n.preferIPv6, err = n.getModelNetworkConfig(); err != nil {
 		return errors.Trace(err)
 	}
 
@@ -92,17 +96,28 @@ func (n *NetworkInfo) init(tag names.UnitTag) error {
 	return nil
 }
 
-// getModelEgressSubnets returns model configuration for egress subnets.
-func (n *NetworkInfo) getModelEgressSubnets() ([]string, error) {
+// getModelNetworkConfig returns model configuration for egress subnets
+// and whether IPv6 addresses are preferred over IPv4 addresses.
+func (n *NetworkInfo) getModelNetworkConfig() ([]string, bool, error) {
 	model, err := n.st.Model()
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, false, errors.Trace(err)
 	}
 	cfg, err := model.ModelConfig()
 	if err != nil {
-		return nil, errors.Trace(err)
+		return nil, false, errors.Trace(err)
 	}
-	return cfg.EgressSubnets(), nil
+	return cfg.EgressSubnets(), cfg.PreferIPv6(), nil
+}
+
+// sortAddresses sorts the input addresses so that the most appropriate
+// addresses come first, honouring the model's IPv6 preference.
+func (n *NetworkInfo) sortAddresses(addrs corenetwork.SpaceAddresses) {
+	if n.preferIPv6 {
+		corenetwork.SortAddressesPreferringIPv6(addrs)
+		return
+	}
+	corenetwork.SortAddresses(addrs)
 }
 
 // ProcessAPIRequest handles a request to the uniter API NetworkInfo method.
@@ -175,7 +190,7 @@ func (n *NetworkInfo) ProcessAPIRequest(args params.NetworkInfoParams) (params.N
 		if err != nil {
 			return params.NetworkInfoResults{}, err
 		}
-		corenetwork.SortAddresses(addrs)
+		n.sortAddresses(addrs)
 
 		// We record the interface addresses as the machine local ones - these
 		// are used later as the binding addresses.
@@ -217,7 +232,7 @@ func (n *NetworkInfo) ProcessAPIRequest(args params.NetworkInfoParams) (params.N
 
 		if len(info.IngressAddresses) == 0 {
 			ingress := spaceAddressesFromNetworkInfo(networkInfos[space].NetworkInfos)
-			corenetwork.SortAddresses(ingress)
+			n.sortAddresses(ingress)
 			info.IngressAddresses = make([]string, len(ingress))
 			for i, addr := range ingress {
 				info.IngressAddresses[i] = addr.Value
@@ -309,21 +324,33 @@ func (n *NetworkInfo) NetworksForRelation(
 
 	// If the endpoint for this relation is not bound to a space, or
 	// is bound to the default space, we need to look up the ingress
-	// address info which is aware of cross model relations.
+	// address info which is aware of cross model and cross region
+	// relations.
 	if boundSpace == corenetwork.AlphaSpaceId || err != nil {
 		_, crossModel, err := rel.RemoteApplication()
 		if err != nil {
 			return "", nil, nil, errors.Trace(err)
 		}
-		if crossModel && (n.unit.ShouldBeAssigned() || pollPublic) {
+		crossRegion, err := n.isCrossRegion(rel)
+		if err != nil {
+			return "", nil, nil, errors.Trace(err)
+		}
+		if (crossModel || crossRegion) && (n.unit.ShouldBeAssigned() || pollPublic) {
 			address, err := pollForAddress(n.unit.PublicAddress)
 			if err != nil {
 				logger.Warningf(
-					"no public address for unit %q in cross model relation %q, will use private address",
+					"no public address for unit %q in cross model or cross region relation %q, will use private address",
 					n.unit.Name(), rel,
 				)
 			} else if address.Value != "" {
 				ingress = append(ingress, address)
+				// For a dual-stack machine, the public addresses of
+				// the other address family are also valid ingress.
+				others, err := n.dualStackPublicAddresses(address)
+				if err != nil {
+					return "", nil, nil, errors.Trace(err)
+				}
+				ingress = append(ingress, others...)
 			}
 			if len(ingress) == 0 {
 				if err := fallbackIngressToPrivateAddr(); err != nil {
@@ -357,7 +384,7 @@ func (n *NetworkInfo) NetworksForRelation(
 		}
 	}
 
-	corenetwork.SortAddresses(ingress)
+	n.sortAddresses(ingress)
 
 	// If no egress subnets defined, We default to the ingress address.
 	if len(egress) == 0 && len(ingress) > 0 {
@@ -369,6 +396,105 @@ func (n *NetworkInfo) NetworksForRelation(
 	return boundSpace, ingress, egress, nil
 }
 
+// isCrossRegion reports whether any of the units related to this unit
+// are on machines in a different cloud region, in which case they can
+// only reach it by its public address.
+func (n *NetworkInfo) isCrossRegion(rel *state.Relation) (bool, error) {
+	if !featureflag.Enabled(feature.MultiCloud) || !n.unit.ShouldBeAssigned() {
+		return false, nil
+	}
+	model, err := n.st.Model()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	region, err := n.unitRegion(n.unit, model)
+	if errors.IsNotAssigned(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+	for _, ep := range rel.Endpoints() {
+		if ep.ApplicationName == n.unit.ApplicationName() {
+			continue
+		}
+		app, err := n.st.Application(ep.ApplicationName)
+		if errors.IsNotFound(err) {
+			// Remote applications are handled as cross model.
+			continue
+		} else if err != nil {
+			return false, errors.Trace(err)
+		}
+		units, err := app.AllUnits()
+		if err != nil {
+			return false, errors.Trace(err)
+		}
+		for _, unit := range units {
+			unitRegion, err := n.unitRegion(unit, model)
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return false, errors.Trace(err)
+			}
+			if unitRegion != region {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// unitRegion returns the cloud and region, as "<cloud>/<region>", in which
+// the unit's machine, or the machine hosting its container, was placed.
+func (n *NetworkInfo) unitRegion(unit *state.Unit, model *state.Model) (string, error) {
+	machineID, err := unit.AssignedMachineId()
+	if err != nil {
+		return "", err
+	}
+	machine, err := n.st.Machine(state.TopParentId(machineID))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	cloudName, region := model.Cloud(), model.CloudRegion()
+	if placement, ok := instance.ParseRegionPlacement(machine.Placement()); ok {
+		region = placement.Region
+		if placement.Cloud != "" {
+			cloudName = placement.Cloud
+		}
+	}
+	return cloudName + "/" + region, nil
+}
+
+// dualStackPublicAddresses returns the public addresses of the unit's
+// machine that are not of the same address type as the input address.
+// CAAS units do not have machines, so nothing is returned for them.
+func (n *NetworkInfo) dualStackPublicAddresses(
+	address corenetwork.SpaceAddress,
+) (corenetwork.SpaceAddresses, error) {
+	if !n.unit.ShouldBeAssigned() {
+		return nil, nil
+	}
+	machineID, err := n.unit.AssignedMachineId()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine, err := n.st.Machine(machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var addrs corenetwork.SpaceAddresses
+	for _, addr := range machine.Addresses() {
+		if addr.Scope != corenetwork.ScopePublic || addr.Value == address.Value {
+			continue
+		}
+		if addr.Type == address.Type || addr.Type == corenetwork.HostName {
+			continue
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
 // machineNetworkInfos returns network info for the unit's machine based on
 // devices with addresses in the input spaces.
 // TODO (manadart 2019-10-10): `GetNetworkInfoForSpaces` is only used here and
@@ -409,6 +535,8 @@ func (n *NetworkInfo) spaceForBinding(endpoint string) (string, error) {
 // information that we need.
 // The best we can do here is identify fan addresses so that they are sorted
 // after other addresses.
+// Link-local addresses, such as those assigned to every IPv6 enabled device,
+// are not reachable from other machines and so are omitted.
 func spaceAddressesFromNetworkInfo(netInfos []network.NetworkInfo) corenetwork.SpaceAddresses {
 	var addrs corenetwork.SpaceAddresses
 	for _, nwInfo := range netInfos {
@@ -418,6 +546,9 @@ func spaceAddressesFromNetworkInfo(netInfos []network.NetworkInfo) corenetwork.S
 		}
 
 		for _, addr := range nwInfo.Addresses {
+			if corenetwork.NewMachineAddress(addr.Address).Scope == corenetwork.ScopeLinkLocal {
+				continue
+			}
 			addrs = append(addrs, corenetwork.NewScopedSpaceAddress(addr.Address, scope))
 		}
 	}
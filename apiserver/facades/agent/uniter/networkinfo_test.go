@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/apiserver/facades/agent/uniter"
 	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -151,6 +152,43 @@ func (s *networkInfoSuite) TestNetworksForRelationWithSpaces(c *gc.C) {
 	c.Assert(egress, gc.DeepEquals, []string{"3.2.3.4/32"})
 }
 
+func (s *networkInfoSuite) TestNetworksForRelationWithDualStackSpacePreferringIPv6(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnet1, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "1.2.0.0/16"})
+	c.Assert(err, jc.ErrorIsNil)
+	subnet2, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "2001:db8::/64"})
+	c.Assert(err, jc.ErrorIsNil)
+	subnet3, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "fe80::/64"})
+	c.Assert(err, jc.ErrorIsNil)
+	space, err := s.State.AddSpace(
+		"dual-stack", "pid-1", []string{subnet1.ID(), subnet2.ID(), subnet3.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	prr := s.newProReqRelationWithBindings(c, charm.ScopeGlobal, map[string]string{"": "dual-stack"}, nil)
+	err = prr.pu0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := prr.pu0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.addDevicesWithAddresses(c, machine, "1.2.3.4/16", "2001:db8::4/64", "fe80::4/64")
+
+	boundSpace, ingress, egress, err := s.newNetworkInfo(c, prr.pu0.UnitTag()).NetworksForRelation("", prr.rel, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Both address families are returned with IPv6 first,
+	// and the link-local address is omitted.
+	c.Assert(boundSpace, gc.Equals, space.Id())
+	c.Assert(ingress, gc.DeepEquals, network.SpaceAddresses{
+		network.NewScopedSpaceAddress("2001:db8::4", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("1.2.3.4", network.ScopeCloudLocal),
+	})
+	c.Assert(egress, gc.DeepEquals, []string{"2001:db8::4/128"})
+}
+
 func (s *networkInfoSuite) TestNetworksForRelationRemoteRelation(c *gc.C) {
 	prr := s.newRemoteProReqRelation(c)
 	err := prr.ru0.AssignToNewMachine()
@@ -175,6 +213,67 @@ func (s *networkInfoSuite) TestNetworksForRelationRemoteRelation(c *gc.C) {
 	c.Assert(egress, gc.DeepEquals, []string{"4.3.2.1/32"})
 }
 
+func (s *networkInfoSuite) TestNetworksForRelationRemoteRelationDualStack(c *gc.C) {
+	prr := s.newRemoteProReqRelation(c)
+	err := prr.ru0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := prr.ru0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetProviderAddresses(
+		network.NewScopedSpaceAddress("1.2.3.4", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("4.3.2.1", network.ScopePublic),
+		network.NewScopedSpaceAddress("2001:db8::1", network.ScopePublic),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	boundSpace, ingress, egress, err := s.newNetworkInfo(c, prr.ru0.UnitTag()).NetworksForRelation("", prr.rel, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(boundSpace, gc.Equals, network.AlphaSpaceId)
+	c.Assert(ingress, gc.DeepEquals, network.SpaceAddresses{
+		network.NewScopedSpaceAddress("4.3.2.1", network.ScopePublic),
+		network.NewScopedSpaceAddress("2001:db8::1", network.ScopePublic),
+	})
+	c.Assert(egress, gc.DeepEquals, []string{"4.3.2.1/32"})
+}
+
+func (s *networkInfoSuite) TestNetworksForRelationCrossRegion(c *gc.C) {
+	s.SetFeatureFlags(feature.MultiCloud)
+	prr := s.newProReqRelation(c, charm.ScopeGlobal)
+	err := prr.pu0.AssignToNewMachine()
+	c.Assert(err, jc.ErrorIsNil)
+	id, err := prr.pu0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(id)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewScopedSpaceAddress("1.2.3.4", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("4.3.2.1", network.ScopePublic),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The related unit is in another region of the model's cloud.
+	regionMachine, err := s.State.AddOneMachine(state.MachineTemplate{
+		Series:    "quantal",
+		Jobs:      []state.MachineJob{state.JobHostUnits},
+		Placement: "region=nether-region",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.ru0.AssignToMachine(regionMachine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	boundSpace, ingress, egress, err := s.newNetworkInfo(c, prr.pu0.UnitTag()).NetworksForRelation("", prr.rel, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(boundSpace, gc.Equals, network.AlphaSpaceId)
+	c.Assert(ingress, gc.DeepEquals,
+		network.SpaceAddresses{network.NewScopedSpaceAddress("4.3.2.1", network.ScopePublic)})
+	c.Assert(egress, gc.DeepEquals, []string{"4.3.2.1/32"})
+}
+
 func (s *networkInfoSuite) TestNetworksForRelationRemoteRelationNoPublicAddr(c *gc.C) {
 	prr := s.newRemoteProReqRelation(c)
 	err := prr.ru0.AssignToNewMachine()
@@ -206,6 +206,8 @@ func (s *StorageAPI) fromStateStorageAttachment(stateStorageAttachment state.Sto
 		params.StorageKind(stateStorageInstance.Kind()),
 		info.Location,
 		life.Value(stateStorageAttachment.Life().String()),
+		info.Size,
+		stateStorageAttachment.MoveStatus() != state.StorageMoveNone,
 	}, nil
 }
 
@@ -435,7 +437,8 @@ func accessUnitTag(tag string, canAccess func(names.Tag) bool) (names.UnitTag, e
 
 // watchStorageAttachment returns a state.NotifyWatcher that reacts to changes
 // to the VolumeAttachmentInfo or FilesystemAttachmentInfo corresponding to the
-// tags specified.
+// tags specified, and to the volume or filesystem itself (e.g. when it is
+// resized).
 func watchStorageAttachment(
 	st storageInterface,
 	stVolume storageVolumeInterface,
@@ -463,6 +466,7 @@ func watchStorageAttachment(
 		// device could change (most likely, become present).
 		watchers = []state.NotifyWatcher{
 			stVolume.WatchVolumeAttachment(hostTag, volume.VolumeTag()),
+			stVolume.WatchVolume(volume.VolumeTag()),
 		}
 
 		// TODO(caas) - we currently only support block devices on machines.
@@ -486,6 +490,7 @@ func watchStorageAttachment(
 		}
 		watchers = []state.NotifyWatcher{
 			stFile.WatchFilesystemAttachment(hostTag, filesystem.FilesystemTag()),
+			stFile.WatchFilesystem(filesystem.FilesystemTag()),
 		}
 	default:
 		return nil, errors.Errorf("invalid storage kind %v", storageInstance.Kind())
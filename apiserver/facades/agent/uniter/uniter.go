@@ -36,8 +36,8 @@ import (
 
 var logger = loggo.GetLogger("juju.apiserver.uniter")
 
-// UniterAPI implements the latest version (v13) of the Uniter API,
-// which adds UpdateNetworkInfo.
+// UniterAPI implements the latest version (v14) of the Uniter API,
+// which allows OpenPorts and ClosePorts to be given an endpoint.
 type UniterAPI struct {
 	*common.LifeGetter
 	*StatusAPI
@@ -80,6 +80,12 @@ type UniterAPI struct {
 // and WatchLXDProfileUpgradeNotifications
 type UniterAPIV12 struct {
 	*LXDProfileAPI
+	UniterAPIV13
+}
+
+// UniterAPIV13 implements version (v13) of the Uniter API,
+// which adds UpdateNetworkInfo.
+type UniterAPIV13 struct {
 	UniterAPI
 }
 
@@ -222,9 +228,20 @@ func NewUniterAPI(context facade.Context) (*UniterAPI, error) {
 	}, nil
 }
 
+// NewUniterAPIV13 creates an instance of the V13 uniter API.
+func NewUniterAPIV13(context facade.Context) (*UniterAPIV13, error) {
+	uniterAPI, err := NewUniterAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV13{
+		UniterAPI: *uniterAPI,
+	}, nil
+}
+
 // NewUniterAPIV12 creates an instance of the V12 uniter API.
 func NewUniterAPIV12(context facade.Context) (*UniterAPIV12, error) {
-	uniterAPI, err := NewUniterAPI(context)
+	uniterAPI, err := NewUniterAPIV13(context)
 	if err != nil {
 		return nil, err
 	}
@@ -234,7 +251,7 @@ func NewUniterAPIV12(context facade.Context) (*UniterAPIV12, error) {
 	accessUnit := unitAccessor(authorizer, st)
 	return &UniterAPIV12{
 		LXDProfileAPI: NewExternalLXDProfileAPI(st, resources, authorizer, accessUnit, logger),
-		UniterAPI:     *uniterAPI,
+		UniterAPIV13:  *uniterAPI,
 	}, nil
 }
 
@@ -936,7 +953,8 @@ func (u *UniterAPI) SetWorkloadVersion(args params.EntityWorkloadVersions) (para
 }
 
 // OpenPorts sets the policy of the port range with protocol to be
-// opened, for all given units.
+// opened, for all given units. Ranges given with an endpoint are
+// opened for that endpoint only.
 func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -956,7 +974,11 @@ func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResul
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.OpenPorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				if entity.Endpoint != "" {
+					err = unit.OpenPortsForEndpoint(entity.Endpoint, entity.Protocol, entity.FromPort, entity.ToPort)
+				} else {
+					err = unit.OpenPorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				}
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -965,7 +987,8 @@ func (u *UniterAPI) OpenPorts(args params.EntitiesPortRanges) (params.ErrorResul
 }
 
 // ClosePorts sets the policy of the port range with protocol to be
-// closed, for all given units.
+// closed, for all given units. Ranges given with an endpoint are
+// closed for that endpoint only.
 func (u *UniterAPI) ClosePorts(args params.EntitiesPortRanges) (params.ErrorResults, error) {
 	result := params.ErrorResults{
 		Results: make([]params.ErrorResult, len(args.Entities)),
@@ -985,7 +1008,11 @@ func (u *UniterAPI) ClosePorts(args params.EntitiesPortRanges) (params.ErrorResu
 			var unit *state.Unit
 			unit, err = u.getUnit(tag)
 			if err == nil {
-				err = unit.ClosePorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				if entity.Endpoint != "" {
+					err = unit.ClosePortsForEndpoint(entity.Endpoint, entity.Protocol, entity.FromPort, entity.ToPort)
+				} else {
+					err = unit.ClosePorts(entity.Protocol, entity.FromPort, entity.ToPort)
+				}
 			}
 		}
 		result.Results[i].Error = common.ServerError(err)
@@ -1045,6 +1045,48 @@ func (s *uniterSuite) TestClosePorts(c *gc.C) {
 	c.Assert(openedPorts, gc.HasLen, 0)
 }
 
+func (s *uniterSuite) TestOpenClosePortsForEndpoint(c *gc.C) {
+	args := params.EntitiesPortRanges{Entities: []params.EntityPortRange{
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 80, ToPort: 80, Endpoint: "url"},
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 80, ToPort: 80, Endpoint: "monitoring-port"},
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 80, ToPort: 80, Endpoint: "bogus"},
+	}}
+	result, err := s.uniter.OpenPorts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{nil},
+			{nil},
+			{&params.Error{
+				Message: `cannot open ports 80-80/tcp ("wordpress/0", endpoint "bogus") for unit "wordpress/0" on subnet "": endpoint "bogus" not found`,
+				Code:    params.CodeNotFound,
+			}},
+		},
+	})
+
+	// Verify the range is opened for both endpoints.
+	ports, err := s.machine0.AllPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.HasLen, 1)
+	c.Assert(ports[0].PortsForUnit("wordpress/0"), jc.DeepEquals, []state.PortRange{
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "url"},
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "monitoring-port"},
+	})
+
+	result, err = s.uniter.ClosePorts(params.EntitiesPortRanges{Entities: []params.EntityPortRange{
+		{Tag: "unit-wordpress-0", Protocol: "tcp", FromPort: 80, ToPort: 80, Endpoint: "url"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+
+	// Verify the range is still opened for the other endpoint.
+	ports, err = s.machine0.AllPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports[0].PortsForUnit("wordpress/0"), jc.DeepEquals, []state.PortRange{
+		{UnitName: "wordpress/0", FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "monitoring-port"},
+	})
+}
+
 func (s *uniterSuite) TestWatchConfigSettingsHash(c *gc.C) {
 	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
 	c.Assert(err, jc.ErrorIsNil)
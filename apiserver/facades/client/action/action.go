@@ -44,6 +44,11 @@ type APIv4 struct {
 
 // APIv5 provides the Action API facade for version 5.
 type APIv5 struct {
+	*APIv6
+}
+
+// APIv6 provides the Action API facade for version 6.
+type APIv6 struct {
 	*ActionAPI
 }
 
@@ -74,15 +79,24 @@ func NewActionAPIV4(ctx facade.Context) (*APIv4, error) {
 	return &APIv4{api}, nil
 }
 
-// NewActionAPIV5 returns an initialized ActionAPI for version 4.
+// NewActionAPIV5 returns an initialized ActionAPI for version 5.
 func NewActionAPIV5(ctx facade.Context) (*APIv5, error) {
-	api, err := newActionAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	api, err := NewActionAPIV6(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv5{api}, nil
 }
 
+// NewActionAPIV6 returns an initialized ActionAPI for version 6.
+func NewActionAPIV6(ctx facade.Context) (*APIv6, error) {
+	api, err := newActionAPI(ctx.State(), ctx.Resources(), ctx.Auth())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv6{api}, nil
+}
+
 func newActionAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*ActionAPI, error) {
 	if !authorizer.AuthClient() {
 		return nil, common.ErrPerm
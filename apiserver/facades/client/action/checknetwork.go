@@ -0,0 +1,242 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"net"
+	"sort"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/actions"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state"
+)
+
+// ingressAddressKey is the relation setting holding the address on
+// which a unit expects to be reached by the units it is related to.
+const ingressAddressKey = "ingress-address"
+
+// CheckNetwork queues an action on each machine hosting a unit of the
+// given applications, or of all applications if none are given, which
+// probes the ingress address and opened ports of every unit related to
+// the units on that machine.
+func (a *ActionAPI) CheckNetwork(args params.CheckNetworkParams) (results params.ActionResults, err error) {
+	if err := a.checkCanAdmin(); err != nil {
+		return results, err
+	}
+
+	if err := a.check.ChangeAllowed(); err != nil {
+		return results, errors.Trace(err)
+	}
+
+	if a.model.Type() != state.ModelTypeIAAS {
+		return results, errors.Errorf("cannot check the network of a %s model", a.model.Type())
+	}
+
+	targets, err := networkProbeTargets(a.state, args.Applications)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+
+	machineIds := make([]string, 0, len(targets))
+	for machineId := range targets {
+		machineIds = append(machineIds, machineId)
+	}
+	sort.Strings(machineIds)
+
+	actionParams := params.Actions{Actions: []params.Action{}}
+	for _, machineId := range machineIds {
+		actionParams.Actions = append(actionParams.Actions, params.Action{
+			Receiver: names.NewMachineTag(machineId).String(),
+			Name:     actions.JujuCheckNetworkActionName,
+			Parameters: map[string]interface{}{
+				"targets": targets[machineId],
+				"timeout": args.Timeout.Nanoseconds(),
+			},
+		})
+	}
+	return queueActions(a, actionParams)
+}
+
+// CheckNetwork is not available via the V5 API.
+func (a *APIv5) CheckNetwork(_, _ struct{}) {}
+
+// probeUnit holds the details of a related unit needed to probe it.
+type probeUnit struct {
+	unit      *state.Unit
+	machineId string
+}
+
+// networkProbeTargets returns the juju-check-network targets to probe
+// from each machine, keyed by machine id. A target is added for each
+// pair of units, hosted on different machines, taking part in a
+// relation of one of the given applications.
+func networkProbeTargets(st *state.State, applications []string) (map[string][]interface{}, error) {
+	wanted := set.NewStrings(applications...)
+	for _, name := range applications {
+		if _, err := st.Application(name); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	relations, err := st.AllRelations()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	probeUnits := make(map[string][]probeUnit)
+	unitsOf := func(appName string) ([]probeUnit, error) {
+		if units, ok := probeUnits[appName]; ok {
+			return units, nil
+		}
+		app, err := st.Application(appName)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		units, err := app.AllUnits()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var result []probeUnit
+		for _, unit := range units {
+			machineId, err := unit.AssignedMachineId()
+			if errors.IsNotAssigned(err) {
+				continue
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			result = append(result, probeUnit{unit: unit, machineId: machineId})
+		}
+		probeUnits[appName] = result
+		return result, nil
+	}
+
+	devices := make(map[string][]*state.LinkLayerDevice)
+	targets := make(map[string][]interface{})
+	for _, rel := range relations {
+		endpoints := rel.Endpoints()
+		related := wanted.IsEmpty()
+		for _, ep := range endpoints {
+			related = related || wanted.Contains(ep.ApplicationName)
+		}
+		if !related {
+			continue
+		}
+
+		for _, fromEp := range endpoints {
+			relatedEps, err := rel.RelatedEndpoints(fromEp.ApplicationName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			fromUnits, err := unitsOf(fromEp.ApplicationName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, toEp := range relatedEps {
+				toUnits, err := unitsOf(toEp.ApplicationName)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				for _, to := range toUnits {
+					address, err := ingressAddress(rel, to.unit)
+					if err != nil {
+						return nil, errors.Trace(err)
+					}
+					if address == "" {
+						continue
+					}
+					ports, err := to.unit.OpenedPorts()
+					if err != nil {
+						return nil, errors.Trace(err)
+					}
+					portValues := make([]interface{}, len(ports))
+					for i, port := range ports {
+						portValues[i] = port.String()
+					}
+
+					for _, from := range fromUnits {
+						if from.machineId == to.machineId {
+							continue
+						}
+						if _, ok := devices[from.machineId]; !ok {
+							machine, err := st.Machine(from.machineId)
+							if err != nil {
+								return nil, errors.Trace(err)
+							}
+							if devices[from.machineId], err = machine.AllLinkLayerDevices(); err != nil {
+								return nil, errors.Trace(err)
+							}
+						}
+						target := map[string]interface{}{
+							"from":     from.unit.Name(),
+							"to":       to.unit.Name(),
+							"relation": rel.String(),
+							"address":  address,
+							"ports":    portValues,
+						}
+						if mtu := routeMTU(devices[from.machineId], address); mtu > 0 {
+							target["mtu"] = mtu
+						}
+						targets[from.machineId] = append(targets[from.machineId], target)
+					}
+				}
+			}
+		}
+	}
+	return targets, nil
+}
+
+// ingressAddress returns the address published by the unit for the
+// relation, falling back to the unit's private address when the unit
+// has not yet entered the relation's scope.
+func ingressAddress(rel *state.Relation, unit *state.Unit) (string, error) {
+	ru, err := rel.Unit(unit)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	settings, err := ru.Settings()
+	if err != nil && !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+	if err == nil {
+		if address, ok := settings.Get(ingressAddressKey); ok {
+			if address, ok := address.(string); ok && address != "" {
+				return address, nil
+			}
+		}
+	}
+	address, err := unit.PrivateAddress()
+	if network.IsNoAddressError(err) {
+		return "", nil
+	} else if err != nil {
+		return "", errors.Trace(err)
+	}
+	return address.Value, nil
+}
+
+// routeMTU returns the MTU of the device with an address on the subnet
+// containing the given address, or zero if there is no such device.
+func routeMTU(devices []*state.LinkLayerDevice, address string) uint {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return 0
+	}
+	for _, dev := range devices {
+		addrs, err := dev.Addresses()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			_, ipNet, err := net.ParseCIDR(addr.SubnetCIDR())
+			if err == nil && ipNet.Contains(ip) {
+				return dev.MTU()
+			}
+		}
+	}
+	return 0
+}
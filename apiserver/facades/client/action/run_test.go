@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/apiserver/facades/client/action"
 	"github.com/juju/juju/apiserver/params"
 	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/core/network"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
@@ -335,3 +336,109 @@ func (s *runSuite) TestRunOnAllMachinesRequiresAdmin(c *gc.C) {
 	_, err = client.RunOnAllMachines(params.RunParams{})
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *runSuite) TestBlockCheckNetwork(c *gc.C) {
+	// block all changes
+	s.BlockAllChanges(c, "TestBlockCheckNetwork")
+	_, err := s.client.CheckNetwork(params.CheckNetworkParams{})
+	s.AssertBlocked(c, err, "TestBlockCheckNetwork")
+}
+
+func (s *runSuite) TestCheckNetwork(c *gc.C) {
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	wordpress0 := s.addUnit(c, wordpress)
+	mysql := s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	mysql0 := s.addUnit(c, mysql)
+	s.AddTestingApplication(c, "unrelated", s.AddTestingCharm(c, "dummy"))
+
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// mysql/0 publishes its ingress address, while wordpress/0 has
+	// not yet entered scope and is probed on its private address.
+	ru, err := rel.Unit(mysql0)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"ingress-address": "10.0.0.2"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = mysql0.OpenPort("tcp", 3306)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineId, err := wordpress0.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(network.NewScopedSpaceAddress("10.0.0.1", network.ScopeCloudLocal))
+	c.Assert(err, jc.ErrorIsNil)
+
+	expectedArgs := params.Actions{
+		Actions: []params.Action{{
+			Receiver: "machine-0",
+			Name:     "juju-check-network",
+			Parameters: map[string]interface{}{
+				"targets": []interface{}{map[string]interface{}{
+					"from":     "wordpress/0",
+					"to":       "mysql/0",
+					"relation": rel.String(),
+					"address":  "10.0.0.2",
+					"ports":    []interface{}{"3306/tcp"},
+				}},
+				"timeout": testing.ShortWait.Nanoseconds(),
+			},
+		}, {
+			Receiver: "machine-1",
+			Name:     "juju-check-network",
+			Parameters: map[string]interface{}{
+				"targets": []interface{}{map[string]interface{}{
+					"from":     "mysql/0",
+					"to":       "wordpress/0",
+					"relation": rel.String(),
+					"address":  "10.0.0.1",
+					"ports":    []interface{}{},
+				}},
+				"timeout": testing.ShortWait.Nanoseconds(),
+			},
+		}},
+	}
+	called := false
+	s.PatchValue(action.QueueActions, func(client *action.ActionAPI, args params.Actions) (params.ActionResults, error) {
+		called = true
+		c.Assert(args, jc.DeepEquals, expectedArgs)
+		return params.ActionResults{}, nil
+	})
+
+	_, err = s.client.CheckNetwork(params.CheckNetworkParams{
+		Applications: []string{"mysql"},
+		Timeout:      testing.ShortWait,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+
+	// Applications without relations have nothing to probe.
+	called = false
+	expectedArgs = params.Actions{Actions: []params.Action{}}
+	_, err = s.client.CheckNetwork(params.CheckNetworkParams{
+		Applications: []string{"unrelated"},
+		Timeout:      testing.ShortWait,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+
+	_, err = s.client.CheckNetwork(params.CheckNetworkParams{
+		Applications: []string{"missing"},
+	})
+	c.Assert(err, gc.ErrorMatches, `application "missing" not found`)
+}
+
+func (s *runSuite) TestCheckNetworkRequiresAdmin(c *gc.C) {
+	alpha := names.NewUserTag("alpha@bravo")
+	auth := apiservertesting.FakeAuthorizer{
+		Tag:         alpha,
+		HasWriteTag: alpha,
+	}
+	client, err := action.NewActionAPI(s.State, nil, auth)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.CheckNetwork(params.CheckNetworkParams{})
+	c.Assert(errors.Cause(err), gc.Equals, common.ErrPerm)
+}
@@ -7,6 +7,7 @@
 package application
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"net"
@@ -88,6 +89,18 @@ type APIv10 struct {
 // The Get call also returns the current endpoint bindings while the SetCharm
 // call access a map of operator-defined bindings.
 type APIv11 struct {
+	*APIv12
+}
+
+// APIv12 provides the Application API facade for version 12.
+// It adds SetEgressPolicy.
+type APIv12 struct {
+	*APIv13
+}
+
+// APIv13 provides the Application API facade for version 13.
+// The Expose call accepts per-endpoint expose settings.
+type APIv13 struct {
 	*APIBase
 }
 
@@ -186,13 +199,29 @@ func NewFacadeV10(ctx facade.Context) (*APIv10, error) {
 }
 
 func NewFacadeV11(ctx facade.Context) (*APIv11, error) {
-	api, err := newFacadeBase(ctx)
+	api, err := NewFacadeV12(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &APIv11{api}, nil
 }
 
+func NewFacadeV12(ctx facade.Context) (*APIv12, error) {
+	api, err := NewFacadeV13(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv12{api}, nil
+}
+
+func NewFacadeV13(ctx facade.Context) (*APIv13, error) {
+	api, err := newFacadeBase(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &APIv13{api}, nil
+}
+
 type caasBrokerInterface interface {
 	ValidateStorageClass(config map[string]interface{}) error
 	Version() (*version.Number, error)
@@ -321,7 +350,7 @@ func (api *APIBase) SetMetricCredentials(args params.ApplicationMetricCredential
 // Deploy fetches the charms from the charm store and deploys them
 // using the specified placement directives.
 // V5 deploy did not support policy, so pass through an empty string.
-func (api *APIv5) Deploy(args params.ApplicationsDeployV5) (params.ErrorResults, error) {
+func (api *APIv5) Deploy(ctx context.Context, args params.ApplicationsDeployV5) (params.ErrorResults, error) {
 	noDefinedPolicy := ""
 	var newArgs params.ApplicationsDeploy
 	for _, value := range args.Applications {
@@ -342,13 +371,13 @@ func (api *APIv5) Deploy(args params.ApplicationsDeployV5) (params.ErrorResults,
 			Resources:        value.Resources,
 		})
 	}
-	return api.APIBase.Deploy(newArgs)
+	return api.APIBase.Deploy(ctx, newArgs)
 }
 
 // Deploy fetches the charms from the charm store and deploys them
 // using the specified placement directives.
 // V6 deploy did not support devices, so pass through an empty map.
-func (api *APIv6) Deploy(args params.ApplicationsDeployV6) (params.ErrorResults, error) {
+func (api *APIv6) Deploy(ctx context.Context, args params.ApplicationsDeployV6) (params.ErrorResults, error) {
 	var newArgs params.ApplicationsDeploy
 	for _, value := range args.Applications {
 		newArgs.Applications = append(newArgs.Applications, params.ApplicationDeploy{
@@ -369,12 +398,13 @@ func (api *APIv6) Deploy(args params.ApplicationsDeployV6) (params.ErrorResults,
 			Resources:        value.Resources,
 		})
 	}
-	return api.APIBase.Deploy(newArgs)
+	return api.APIBase.Deploy(ctx, newArgs)
 }
 
 // Deploy fetches the charms from the charm store and deploys them
-// using the specified placement directives.
-func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults, error) {
+// using the specified placement directives. The transactions run are
+// recorded in the trace held by the context, if any.
+func (api *APIBase) Deploy(ctx context.Context, args params.ApplicationsDeploy) (params.ErrorResults, error) {
 	if err := api.checkCanWrite(); err != nil {
 		return params.ErrorResults{}, errors.Trace(err)
 	}
@@ -385,9 +415,10 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 		return result, errors.Trace(err)
 	}
 
+	backend := traceBackend(ctx, api.backend)
 	for i, arg := range args.Applications {
 		err := deployApplication(
-			api.backend,
+			backend,
 			api.model,
 			api.stateCharm,
 			arg,
@@ -406,7 +437,7 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 			// TODO(babbageclunk): rework the deploy API so the
 			// resources are created transactionally to avoid needing
 			// to do this.
-			resources, err := api.backend.Resources()
+			resources, err := backend.Resources()
 			if err != nil {
 				logger.Errorf("couldn't get backend.Resources")
 				continue
@@ -422,7 +453,8 @@ func (api *APIBase) Deploy(args params.ApplicationsDeploy) (params.ErrorResults,
 
 func applicationConfigSchema(modelType state.ModelType) (environschema.Fields, schema.Defaults, error) {
 	if modelType != state.ModelTypeCAAS {
-		return trustFields, trustDefaults, nil
+		// Only machines can be replaced.
+		return AddTrustSchemaAndDefaults(machineReplacementFields, nil)
 	}
 	// TODO(caas) - get the schema from the provider
 	defaults := caas.ConfigDefaults(k8s.ConfigDefaults())
@@ -634,6 +666,10 @@ func deployApplication(
 		appSettings[k] = v
 	}
 
+	if err := validateMachineReplacement(appSettings); err != nil {
+		return errors.Trace(err)
+	}
+
 	var applicationConfig *application.Config
 	configSchema, defaults, err := applicationConfigSchema(modelType)
 	if err != nil {
@@ -1247,6 +1283,9 @@ func (api *APIBase) Expose(args params.ApplicationExpose) error {
 		return errors.Trace(err)
 	}
 	if api.modelType == state.ModelTypeCAAS {
+		if len(args.ExposedEndpoints) > 0 {
+			return errors.NotSupportedf("exposing endpoints to specific spaces or CIDRs on a container model")
+		}
 		appConfig, err := app.ApplicationConfig()
 		if err != nil {
 			return errors.Trace(err)
@@ -1257,7 +1296,17 @@ func (api *APIBase) Expose(args params.ApplicationExpose) error {
 					"juju config %s %s=<value>", caas.JujuExternalHostNameKey, args.ApplicationName, caas.JujuExternalHostNameKey)
 		}
 	}
-	return app.SetExposed()
+	if len(args.ExposedEndpoints) == 0 {
+		return app.SetExposed()
+	}
+	exposedEndpoints := make(map[string]state.ExposedEndpoint, len(args.ExposedEndpoints))
+	for endpoint, exposed := range args.ExposedEndpoints {
+		exposedEndpoints[endpoint] = state.ExposedEndpoint{
+			ExposeToSpaces: exposed.ExposeToSpaces,
+			ExposeToCIDRs:  exposed.ExposeToCIDRs,
+		}
+	}
+	return app.MergeExposeSettings(exposedEndpoints)
 }
 
 // Unexpose changes the juju-managed firewall to unexpose any ports that
@@ -1276,6 +1325,36 @@ func (api *APIBase) Unexpose(args params.ApplicationUnexpose) error {
 	return app.ClearExposed()
 }
 
+// SetEgressPolicy isn't on the v11 API.
+func (u *APIv11) SetEgressPolicy(_, _ struct{}) {}
+
+// SetEgressPolicy restricts the outbound traffic of an application's
+// units to the destinations allowed by the given policy, or removes
+// any restriction if no policy is given.
+func (api *APIBase) SetEgressPolicy(args params.ApplicationSetEgressPolicy) error {
+	if err := api.checkCanWrite(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := api.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	if api.modelType == state.ModelTypeCAAS {
+		return errors.NotSupportedf("egress policies on a container model")
+	}
+	app, err := api.backend.Application(args.ApplicationName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if args.Policy == nil {
+		return app.ClearEgressPolicy()
+	}
+	return app.SetEgressPolicy(state.EgressPolicy{
+		ToCIDRs:        args.Policy.ToCIDRs,
+		ToSpaces:       args.Policy.ToSpaces,
+		ToApplications: args.Policy.ToApplications,
+	})
+}
+
 // AddUnits adds a given number of units to an application.
 func (api *APIv5) AddUnits(args params.AddApplicationUnitsV5) (params.AddApplicationUnitsResults, error) {
 	noDefinedPolicy := ""
@@ -2220,6 +2299,9 @@ func (api *APIBase) setApplicationConfig(arg params.ApplicationConfigSet) error
 	}
 
 	if len(appConfigAttrs) > 0 {
+		if err := validateMachineReplacement(appConfigAttrs); err != nil {
+			return errors.Trace(err)
+		}
 		if err := app.UpdateApplicationConfig(appConfigAttrs, nil, configSchema, defaults); err != nil {
 			return errors.Annotate(err, "updating application config values")
 		}
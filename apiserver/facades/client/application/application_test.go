@@ -4,12 +4,14 @@
 package application_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"regexp"
 	"sync"
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
@@ -33,6 +35,7 @@ import (
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/trace"
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
@@ -51,7 +54,7 @@ type applicationSuite struct {
 	apiservertesting.CharmStoreSuite
 	commontesting.BlockHelper
 
-	applicationAPI *application.APIv11
+	applicationAPI *application.APIv13
 	application    *state.Application
 	authorizer     *apiservertesting.FakeAuthorizer
 }
@@ -88,7 +91,7 @@ func (s *applicationSuite) TearDownTest(c *gc.C) {
 	s.JujuConnSuite.TearDownTest(c)
 }
 
-func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv11 {
+func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv13 {
 	resources := common.NewResources()
 	c.Assert(resources.RegisterNamed("dataDir", common.StringResource(c.MkDir())), jc.ErrorIsNil)
 	storageAccess, err := application.GetStorageState(s.State)
@@ -112,7 +115,7 @@ func (s *applicationSuite) makeAPI(c *gc.C) *application.APIv11 {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	return &application.APIv11{api}
+	return &application.APIv13{api}
 }
 
 func (s *applicationSuite) TestCharmConfig(c *gc.C) {
@@ -136,7 +139,9 @@ func (s *applicationSuite) TestCharmConfigV8(c *gc.C) {
 	api := &application.APIv8{
 		APIv9: &application.APIv9{
 			APIv10: &application.APIv10{
-				APIv11: s.applicationAPI,
+				APIv11: &application.APIv11{
+					APIv12: &application.APIv12{APIv13: s.applicationAPI},
+				},
 			},
 		},
 	}
@@ -380,7 +385,7 @@ func (s *applicationSuite) TestApplicationDeployWithStorage(c *gc.C) {
 		Constraints:     cons,
 		Storage:         storageConstraints,
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -435,7 +440,7 @@ func (s *applicationSuite) TestApplicationDeployWithInvalidStoragePool(c *gc.C)
 		Constraints:     cons,
 		Storage:         storageConstraints,
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -456,7 +461,7 @@ func (s *applicationSuite) TestApplicationDeployDefaultFilesystemStorage(c *gc.C
 		NumUnits:        1,
 		Constraints:     cons,
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -491,7 +496,7 @@ func (s *applicationSuite) TestApplicationDeploy(c *gc.C) {
 			{"deadbeef-0bad-400d-8000-4b1d0d06f00d", "valid"},
 		},
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -509,6 +514,36 @@ func (s *applicationSuite) TestApplicationDeploy(c *gc.C) {
 	c.Assert(files, gc.HasLen, 0)
 }
 
+func (s *applicationSuite) TestApplicationDeployRecordsTransactionsInTrace(c *gc.C) {
+	curl, _ := s.UploadCharm(c, "precise/dummy-42", "dummy")
+	err := application.AddCharmWithAuthorization(application.NewStateShim(s.State), params.AddCharmWithAuthorization{
+		URL: curl.String(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	exporter := &trace.MemoryExporter{}
+	tracer := trace.NewTracer(exporter, testclock.NewClock(time.Time{}))
+	ctx, parent := tracer.Start(context.Background(), "Application(13).Deploy", trace.SpanKindServer)
+	results, err := s.applicationAPI.Deploy(ctx, params.ApplicationsDeploy{
+		Applications: []params.ApplicationDeploy{{
+			ApplicationName: "application",
+			CharmURL:        curl.String(),
+			NumUnits:        1,
+		}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.OneError(), jc.ErrorIsNil)
+	parent.End()
+
+	spans := exporter.Spans()
+	c.Assert(len(spans) > 1, jc.IsTrue)
+	for _, span := range spans[:len(spans)-1] {
+		c.Check(span.Name, gc.Equals, "mongo.txn")
+		c.Check(span.SpanContext.TraceID, gc.Equals, parent.SpanContext().TraceID)
+		c.Check(span.ParentSpanID, gc.Equals, parent.SpanContext().SpanID)
+	}
+}
+
 func (s *applicationSuite) TestApplicationDeployWithInvalidPlacement(c *gc.C) {
 	curl, _ := s.UploadCharm(c, "precise/dummy-42", "dummy")
 	err := application.AddCharmWithAuthorization(application.NewStateShim(s.State), params.AddCharmWithAuthorization{
@@ -525,7 +560,7 @@ func (s *applicationSuite) TestApplicationDeployWithInvalidPlacement(c *gc.C) {
 			{"deadbeef-0bad-400d-8000-4b1d0d06f00d", "invalid"},
 		},
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -576,7 +611,7 @@ func (s *applicationSuite) testApplicationDeployWithPlacementLockedError(
 		Constraints:     cons,
 		Placement:       []*instance.Placement{&placement},
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -595,7 +630,7 @@ func (s *applicationSuite) TestApplicationDeploymentRemovesPendingResourcesOnFai
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			ApplicationName: "haha/borken",
 			NumUnits:        1,
@@ -622,7 +657,7 @@ func (s *applicationSuite) TestApplicationDeploymentLeavesResourcesOnSuccess(c *
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			ApplicationName: "unborken",
 			NumUnits:        1,
@@ -658,7 +693,7 @@ func (s *applicationSuite) TestApplicationDeploymentWithTrust(c *gc.C) {
 			{"deadbeef-0bad-400d-8000-4b1d0d06f00d", "valid"},
 		},
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -693,7 +728,7 @@ func (s *applicationSuite) TestApplicationDeploymentNoTrust(c *gc.C) {
 			{"deadbeef-0bad-400d-8000-4b1d0d06f00d", "valid"},
 		},
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -724,7 +759,7 @@ func (s *applicationSuite) testClientApplicationsDeployWithBindings(c *gc.C, end
 		EndpointBindings: endpointBindings,
 	}
 
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{args}},
 	)
 	c.Assert(err, jc.ErrorIsNil)
@@ -981,7 +1016,7 @@ func (s *applicationSuite) TestApplicationSetCharm(c *gc.C) {
 		_, err := s.State.AddMachine("quantal", state.JobHostUnits)
 		c.Assert(err, jc.ErrorIsNil)
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1028,7 +1063,7 @@ func (s *applicationSuite) setupApplicationSetCharm(c *gc.C) {
 		c.Assert(err, jc.ErrorIsNil)
 	}
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1103,7 +1138,7 @@ func (s *applicationSuite) TestApplicationSetCharmForceUnits(c *gc.C) {
 		_, err := s.State.AddMachine("quantal", state.JobHostUnits)
 		c.Assert(err, jc.ErrorIsNil)
 	}
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1182,7 +1217,7 @@ func (s *applicationSuite) TestApplicationSetCharmLegacy(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1212,7 +1247,7 @@ func (s *applicationSuite) TestApplicationSetCharmUnsupportedSeries(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1240,7 +1275,7 @@ func (s *applicationSuite) assertApplicationSetCharmSeries(c *gc.C, upgradeCharm
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1287,7 +1322,7 @@ func (s *applicationSuite) TestApplicationSetCharmWrongOS(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1339,7 +1374,7 @@ func (s *applicationSuite) TestSpecializeStoreOnDeployApplicationSetCharmAndAddC
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1377,7 +1412,7 @@ func (s *applicationSuite) setupApplicationDeploy(c *gc.C, args string) (*charm.
 }
 
 func (s *applicationSuite) assertApplicationDeployPrincipal(c *gc.C, curl *charm.URL, ch charm.Charm, mem4g constraints.Value) {
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1391,7 +1426,7 @@ func (s *applicationSuite) assertApplicationDeployPrincipal(c *gc.C, curl *charm
 }
 
 func (s *applicationSuite) assertApplicationDeployPrincipalBlocked(c *gc.C, msg string, curl *charm.URL, mem4g constraints.Value) {
-	_, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	_, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -1425,7 +1460,7 @@ func (s *applicationSuite) TestApplicationDeploySubordinate(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application-name",
@@ -1462,7 +1497,7 @@ func (s *applicationSuite) TestApplicationDeployConfig(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application-name",
@@ -1490,7 +1525,7 @@ func (s *applicationSuite) TestApplicationDeployConfigError(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application-name",
@@ -1513,7 +1548,7 @@ func (s *applicationSuite) TestApplicationDeployToMachine(c *gc.C) {
 
 	machine, err := s.State.AddMachine("precise", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application-name",
@@ -1555,7 +1590,7 @@ func (s *applicationSuite) TestApplicationDeployToMachineWithLXDProfile(c *gc.C)
 
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application-name",
@@ -1606,7 +1641,7 @@ func (s *applicationSuite) TestApplicationDeployToMachineWithInvalidLXDProfileAn
 
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application-name",
@@ -1640,7 +1675,7 @@ func (s *applicationSuite) TestApplicationDeployToMachineWithInvalidLXDProfileAn
 }
 
 func (s *applicationSuite) TestApplicationDeployToMachineNotFound(c *gc.C) {
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        "cs:precise/application-name-1",
 			ApplicationName: "application-name",
@@ -1661,7 +1696,7 @@ func (s *applicationSuite) deployApplicationForUpdateTests(c *gc.C) {
 		URL: curl.String(),
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	results, err := s.applicationAPI.Deploy(params.ApplicationsDeploy{
+	results, err := s.applicationAPI.Deploy(context.Background(), params.ApplicationsDeploy{
 		Applications: []params.ApplicationDeploy{{
 			CharmURL:        curl.String(),
 			ApplicationName: "application",
@@ -2467,7 +2502,7 @@ func (s *applicationSuite) TestApplicationExpose(c *gc.C) {
 	c.Assert(apps[1].IsExposed(), jc.IsTrue)
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err = s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err = s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -2519,7 +2554,7 @@ var applicationExposeTests = []struct {
 func (s *applicationSuite) assertApplicationExpose(c *gc.C) {
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err := s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		if t.err != "" {
 			c.Assert(err, gc.ErrorMatches, t.err)
 		} else {
@@ -2534,7 +2569,7 @@ func (s *applicationSuite) assertApplicationExpose(c *gc.C) {
 func (s *applicationSuite) assertApplicationExposeBlocked(c *gc.C, msg string) {
 	for i, t := range applicationExposeTests {
 		c.Logf("test %d. %s", i, t.about)
-		err := s.applicationAPI.Expose(params.ApplicationExpose{t.application})
+		err := s.applicationAPI.Expose(params.ApplicationExpose{ApplicationName: t.application})
 		s.AssertBlocked(c, err, msg)
 	}
 }
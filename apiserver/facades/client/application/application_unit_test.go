@@ -33,6 +33,7 @@ import (
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
@@ -55,7 +56,7 @@ type ApplicationSuite struct {
 	env          environs.Environ
 	blockChecker mockBlockChecker
 	authorizer   apiservertesting.FakeAuthorizer
-	api          *application.APIv11
+	api          *application.APIv13
 	deployParams map[string]application.DeployApplicationParams
 }
 
@@ -85,7 +86,7 @@ func (s *ApplicationSuite) setAPIUser(c *gc.C, user names.UserTag) {
 		s.caasBroker,
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.api = &application.APIv11{api}
+	s.api = &application.APIv13{api}
 }
 
 func (s *ApplicationSuite) SetUpTest(c *gc.C) {
@@ -1568,6 +1569,38 @@ func (s *ApplicationSuite) TestSetApplicationConfigBranch(c *gc.C) {
 	s.backend.generation.CheckCall(c, 0, "AssignApplication", "postgresql")
 }
 
+func (s *ApplicationSuite) TestSetApplicationConfigMachineReplacement(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeIAAS)
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"replace-machines-after": "15m",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "UpdateApplicationConfig")
+	c.Assert(app.Calls()[0].Args[0], jc.DeepEquals, coreapplication.ConfigAttributes{
+		"replace-machines-after": "15m",
+	})
+}
+
+func (s *ApplicationSuite) TestSetApplicationConfigMachineReplacementInvalid(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeIAAS)
+	result, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{
+		Args: []params.ApplicationConfigSet{{
+			ApplicationName: "postgresql",
+			Config: map[string]string{
+				"replace-machines-after": "soon",
+			},
+		}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.OneError(), gc.ErrorMatches, `replace-machines-after value "soon" not valid`)
+	s.backend.applications["postgresql"].CheckNoCalls(c)
+}
+
 func (s *ApplicationSuite) TestBlockSetApplicationConfig(c *gc.C) {
 	s.blockChecker.SetErrors(errors.New("blocked"))
 	_, err := s.api.SetApplicationsConfig(params.ApplicationConfigSetArgs{})
@@ -1707,6 +1740,92 @@ func (s *ApplicationSuite) TestCAASExposeWithHostname(c *gc.C) {
 	app.CheckCallNames(c, "ApplicationConfig", "SetExposed")
 }
 
+func (s *ApplicationSuite) TestSetEgressPolicy(c *gc.C) {
+	err := s.api.SetEgressPolicy(params.ApplicationSetEgressPolicy{
+		ApplicationName: "postgresql",
+		Policy: &params.EgressPolicy{
+			ToCIDRs:  []string{"10.0.0.0/8"},
+			ToSpaces: []string{"internal"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "SetEgressPolicy")
+	app.CheckCall(c, 0, "SetEgressPolicy", state.EgressPolicy{
+		ToCIDRs:  []string{"10.0.0.0/8"},
+		ToSpaces: []string{"internal"},
+	})
+}
+
+func (s *ApplicationSuite) TestClearEgressPolicy(c *gc.C) {
+	err := s.api.SetEgressPolicy(params.ApplicationSetEgressPolicy{
+		ApplicationName: "postgresql",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "ClearEgressPolicy")
+}
+
+func (s *ApplicationSuite) TestSetEgressPolicyGlobalFirewallMode(c *gc.C) {
+	// Machine agents restrict the outbound traffic of their machines
+	// when the firewaller cannot do it through the provider.
+	s.model.cfg["firewall-mode"] = config.FwGlobal
+	err := s.api.SetEgressPolicy(params.ApplicationSetEgressPolicy{
+		ApplicationName: "postgresql",
+		Policy:          &params.EgressPolicy{ToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.backend.applications["postgresql"].CheckCallNames(c, "SetEgressPolicy")
+}
+
+func (s *ApplicationSuite) TestCAASSetEgressPolicy(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	err := s.api.SetEgressPolicy(params.ApplicationSetEgressPolicy{
+		ApplicationName: "postgresql",
+		Policy:          &params.EgressPolicy{ToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, gc.ErrorMatches, "egress policies on a container model not supported")
+}
+
+func (s *ApplicationSuite) TestExposeEndpoints(c *gc.C) {
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"db": {
+				ExposeToSpaces: []string{"internal"},
+				ExposeToCIDRs:  []string{"10.0.0.0/8"},
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "MergeExposeSettings")
+	app.CheckCall(c, 0, "MergeExposeSettings", map[string]state.ExposedEndpoint{
+		"db": {
+			ExposeToSpaces: []string{"internal"},
+			ExposeToCIDRs:  []string{"10.0.0.0/8"},
+		},
+	})
+}
+
+func (s *ApplicationSuite) TestExposeWithoutEndpoints(c *gc.C) {
+	err := s.api.Expose(params.ApplicationExpose{ApplicationName: "postgresql"})
+	c.Assert(err, jc.ErrorIsNil)
+	app := s.backend.applications["postgresql"]
+	app.CheckCallNames(c, "SetExposed")
+}
+
+func (s *ApplicationSuite) TestCAASExposeEndpoints(c *gc.C) {
+	application.SetModelType(s.api, state.ModelTypeCAAS)
+	err := s.api.Expose(params.ApplicationExpose{
+		ApplicationName: "postgresql",
+		ExposedEndpoints: map[string]params.ExposedEndpoint{
+			"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		},
+	})
+	c.Assert(err, gc.ErrorMatches, "exposing endpoints to specific spaces or CIDRs on a container model not supported")
+}
+
 func (s *ApplicationSuite) TestApplicationsInfoOne(c *gc.C) {
 	entities := []params.Entity{{Tag: "application-postgresql"}}
 	result, err := s.api.ApplicationsInfo(params.Entities{entities})
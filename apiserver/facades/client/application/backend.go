@@ -4,6 +4,7 @@
 package application
 
 import (
+	"context"
 	"time"
 
 	"github.com/juju/schema"
@@ -72,6 +73,7 @@ type Application interface {
 	CharmURL() (*charm.URL, bool)
 	Channel() csparams.Channel
 	ClearExposed() error
+	ClearEgressPolicy() error
 	CharmConfig(string) (charm.Settings, error)
 	Constraints() (constraints.Value, error)
 	Destroy() error
@@ -79,12 +81,14 @@ type Application interface {
 	EndpointBindings() (Bindings, error)
 	Endpoints() ([]state.Endpoint, error)
 	IsExposed() bool
+	MergeExposeSettings(map[string]state.ExposedEndpoint) error
 	IsPrincipal() bool
 	IsRemote() bool
 	Series() string
 	SetCharm(state.SetCharmConfig) error
 	SetConstraints(constraints.Value) error
 	SetExposed() error
+	SetEgressPolicy(state.EgressPolicy) error
 	SetMetricCredentials([]byte) error
 	SetMinUnits(int) error
 	UpdateApplicationSeries(string, bool) error
@@ -186,6 +190,19 @@ type stateShim struct {
 	*state.State
 }
 
+// traceBackend returns a Backend whose transactions are recorded in the
+// trace held by the context. Backends not backed by state are returned
+// unchanged.
+func traceBackend(ctx context.Context, backend Backend) Backend {
+	switch shim := backend.(type) {
+	case *stateShim:
+		return &stateShim{shim.WithTraceContext(ctx)}
+	case stateShim:
+		return stateShim{shim.WithTraceContext(ctx)}
+	}
+	return backend
+}
+
 type ExternalController state.ExternalController
 
 func (s stateShim) SaveController(controllerInfo crossmodel.ControllerInfo, modelUUID string) (ExternalController, error) {
@@ -29,7 +29,7 @@ import (
 type getSuite struct {
 	jujutesting.JujuConnSuite
 
-	applicationAPI *application.APIv11
+	applicationAPI *application.APIv13
 	authorizer     apiservertesting.FakeAuthorizer
 }
 
@@ -60,12 +60,12 @@ func (s *getSuite) SetUpTest(c *gc.C) {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.applicationAPI = &application.APIv11{api}
+	s.applicationAPI = &application.APIv13{api}
 }
 
 func (s *getSuite) TestClientApplicationGetSmokeTestV4(c *gc.C) {
 	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
-	v4 := &application.APIv4{&application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{s.applicationAPI}}}}}}}
+	v4 := &application.APIv4{&application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{s.applicationAPI}}}}}}}}
 	results, err := v4.Get(params.ApplicationGet{ApplicationName: "wordpress"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.ApplicationGetResults{
@@ -85,7 +85,7 @@ func (s *getSuite) TestClientApplicationGetSmokeTestV4(c *gc.C) {
 
 func (s *getSuite) TestClientApplicationGetSmokeTestV5(c *gc.C) {
 	s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
-	v5 := &application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{s.applicationAPI}}}}}}
+	v5 := &application.APIv5{&application.APIv6{&application.APIv7{&application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{s.applicationAPI}}}}}}}
 	results, err := v5.Get(params.ApplicationGet{ApplicationName: "wordpress"})
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results, gc.DeepEquals, params.ApplicationGetResults{
@@ -128,6 +128,11 @@ func (s *getSuite) TestClientApplicationGetIAASModelSmokeTest(c *gc.C) {
 				"source":      "default",
 				"type":        environschema.Tbool,
 				"value":       false,
+			},
+			"replace-machines-after": map[string]interface{}{
+				"description": "How long a machine hosting units of this application may be down before it is replaced (e.g. 15m); unset disables replacement",
+				"source":      "unset",
+				"type":        environschema.Tstring,
 			}},
 		Series: "quantal",
 		EndpointBindings: map[string]string{
@@ -213,7 +218,7 @@ func (s *getSuite) TestClientApplicationGetCAASModelSmokeTest(c *gc.C) {
 		nil, // CAAS Broker not used in this suite.
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	apiV8 := &application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{api}}}}
+	apiV8 := &application.APIv8{&application.APIv9{&application.APIv10{&application.APIv11{&application.APIv12{&application.APIv13{api}}}}}}
 
 	results, err := apiV8.Get(params.ApplicationGet{ApplicationName: "dashboard4miner"})
 	c.Assert(err, jc.ErrorIsNil)
@@ -297,6 +302,11 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"replace-machines-after": map[string]interface{}{
+				"description": "How long a machine hosting units of this application may be down before it is replaced (e.g. 15m); unset disables replacement",
+				"source":      "unset",
+				"type":        "string",
+			},
 		},
 		Series: "quantal",
 		EndpointBindings: map[string]string{
@@ -358,6 +368,11 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"replace-machines-after": map[string]interface{}{
+				"description": "How long a machine hosting units of this application may be down before it is replaced (e.g. 15m); unset disables replacement",
+				"source":      "unset",
+				"type":        "string",
+			},
 		},
 		Series: "quantal",
 		EndpointBindings: map[string]string{
@@ -378,6 +393,11 @@ var getTests = []struct {
 				"source":      "default",
 				"type":        "bool",
 			},
+			"replace-machines-after": map[string]interface{}{
+				"description": "How long a machine hosting units of this application may be down before it is replaced (e.g. 15m); unset disables replacement",
+				"source":      "unset",
+				"type":        "string",
+			},
 		},
 		EndpointBindings: map[string]string{
 			"":                  network.AlphaSpaceName,
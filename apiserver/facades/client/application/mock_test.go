@@ -237,11 +237,26 @@ func (a *mockApplication) SetExposed() error {
 	return a.NextErr()
 }
 
+func (a *mockApplication) SetEgressPolicy(policy state.EgressPolicy) error {
+	a.MethodCall(a, "SetEgressPolicy", policy)
+	return a.NextErr()
+}
+
+func (a *mockApplication) ClearEgressPolicy() error {
+	a.MethodCall(a, "ClearEgressPolicy")
+	return a.NextErr()
+}
+
 func (a *mockApplication) IsExposed() bool {
 	a.MethodCall(a, "IsExposed")
 	return a.exposed
 }
 
+func (a *mockApplication) MergeExposeSettings(exposedEndpoints map[string]state.ExposedEndpoint) error {
+	a.MethodCall(a, "MergeExposeSettings", exposedEndpoints)
+	return a.NextErr()
+}
+
 func (a *mockApplication) IsRemote() bool {
 	a.MethodCall(a, "IsRemote")
 	return a.remote
@@ -0,0 +1,57 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/environschema.v1"
+
+	coreapplication "github.com/juju/juju/core/application"
+)
+
+// MachineReplacementConfigOptionName is the option name used to opt an
+// application into the automatic replacement of down machines.
+const MachineReplacementConfigOptionName = "replace-machines-after"
+
+var machineReplacementFields = environschema.Fields{
+	MachineReplacementConfigOptionName: {
+		Description: "How long a machine hosting units of this application may be down before it is replaced (e.g. 15m); unset disables replacement",
+		Type:        environschema.Tstring,
+		Group:       environschema.JujuGroup,
+	},
+}
+
+// MachineReplacementDelay returns how long a machine hosting units of the
+// application with the given config may be down before it is replaced. A
+// zero duration means that the application has not opted in.
+func MachineReplacementDelay(config coreapplication.ConfigAttributes) (time.Duration, error) {
+	value := config.GetString(MachineReplacementConfigOptionName, "")
+	if value == "" {
+		return 0, nil
+	}
+	delay, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.NotValidf("%s value %q", MachineReplacementConfigOptionName, value)
+	}
+	if delay < 0 {
+		return 0, errors.NotValidf("negative %s value %q", MachineReplacementConfigOptionName, value)
+	}
+	return delay, nil
+}
+
+// validateMachineReplacement checks that any machine replacement delay in
+// the given application config attributes is a valid duration.
+func validateMachineReplacement(attrs map[string]interface{}) error {
+	// Values of the wrong type are rejected by the schema.
+	value, ok := attrs[MachineReplacementConfigOptionName].(string)
+	if !ok {
+		return nil
+	}
+	_, err := MachineReplacementDelay(coreapplication.ConfigAttributes{
+		MachineReplacementConfigOptionName: value,
+	})
+	return errors.Trace(err)
+}
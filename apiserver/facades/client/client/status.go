@@ -1125,6 +1125,15 @@ func (context *statusContext) processApplication(application *state.Application)
 		CharmVersion: applicationCharm.Version(),
 		CharmProfile: charmProfileName,
 	}
+	if exposedEndpoints := application.ExposedEndpoints(); len(exposedEndpoints) > 0 {
+		processedStatus.ExposedEndpoints = make(map[string]params.ExposedEndpoint, len(exposedEndpoints))
+		for endpoint, exposed := range exposedEndpoints {
+			processedStatus.ExposedEndpoints[endpoint] = params.ExposedEndpoint{
+				ExposeToSpaces: exposed.ExposeToSpaces,
+				ExposeToCIDRs:  exposed.ExposeToCIDRs,
+			}
+		}
+	}
 
 	if latestCharm, ok := context.allAppsUnitsCharmBindings.latestCharms[*applicationCharm.URL().WithRevision(-1)]; ok && latestCharm != nil {
 		if latestCharm.Revision() > applicationCharm.URL().Revision {
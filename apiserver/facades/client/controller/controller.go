@@ -6,6 +6,7 @@
 package controller
 
 import (
+	"context"
 	"encoding/json"
 	"sort"
 	"strings"
@@ -27,6 +28,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	corecontroller "github.com/juju/juju/controller"
 	coremigration "github.com/juju/juju/core/migration"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/migration"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/pubsub/controller"
@@ -642,11 +644,11 @@ func (c *ControllerAPI) ModifyControllerAccess(args params.ModifyControllerAcces
 // ConfigSet changes the value of specified controller configuration
 // settings. Only some settings can be changed after bootstrap.
 // Settings that aren't specified in the params are left unchanged.
-func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
+func (c *ControllerAPI) ConfigSet(ctx context.Context, args params.ControllerConfigSet) error {
 	if err := c.checkHasAdmin(); err != nil {
 		return errors.Trace(err)
 	}
-	if err := c.state.UpdateControllerConfig(args.Config, nil); err != nil {
+	if err := c.state.WithTraceContext(ctx).UpdateControllerConfig(args.Config, nil); err != nil {
 		return errors.Trace(err)
 	}
 	// TODO(thumper): add a version to controller config to allow for
@@ -658,7 +660,10 @@ func (c *ControllerAPI) ConfigSet(args params.ControllerConfigSet) error {
 	}
 	if _, err := c.hub.Publish(
 		controller.ConfigChanged,
-		controller.ConfigChangedMessage{cfg}); err != nil {
+		controller.ConfigChangedMessage{
+			Config:      cfg,
+			TraceParent: trace.TraceParentFromContext(ctx),
+		}); err != nil {
 		return errors.Trace(err)
 	}
 	return nil
@@ -4,6 +4,7 @@
 package controller_test
 
 import (
+	"context"
 	"encoding/json"
 	"regexp"
 	"time"
@@ -25,6 +26,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/cloud"
 	corecontroller "github.com/juju/juju/controller"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/permission"
@@ -926,7 +928,7 @@ func (s *controllerSuite) TestConfigSet(c *gc.C) {
 	// Sanity check.
 	c.Assert(config.AuditingEnabled(), gc.Equals, false)
 
-	err = s.controller.ConfigSet(params.ControllerConfigSet{Config: map[string]interface{}{
+	err = s.controller.ConfigSet(context.Background(), params.ControllerConfigSet{Config: map[string]interface{}{
 		"auditing-enabled": true,
 	}})
 	c.Assert(err, jc.ErrorIsNil)
@@ -951,7 +953,7 @@ func (s *controllerSuite) TestConfigSetRequiresSuperUser(c *gc.C) {
 		})
 	c.Assert(err, jc.ErrorIsNil)
 
-	err = endpoint.ConfigSet(params.ControllerConfigSet{Config: map[string]interface{}{
+	err = endpoint.ConfigSet(context.Background(), params.ControllerConfigSet{Config: map[string]interface{}{
 		"something": 23,
 	}})
 
@@ -967,7 +969,7 @@ func (s *controllerSuite) TestConfigSetPublishesEvent(c *gc.C) {
 		close(done)
 	})
 
-	err := s.controller.ConfigSet(params.ControllerConfigSet{Config: map[string]interface{}{
+	err := s.controller.ConfigSet(context.Background(), params.ControllerConfigSet{Config: map[string]interface{}{
 		"features": []string{"foo", "bar"},
 	}})
 	c.Assert(err, jc.ErrorIsNil)
@@ -981,6 +983,31 @@ func (s *controllerSuite) TestConfigSetPublishesEvent(c *gc.C) {
 	c.Assert(config.Features().SortedValues(), jc.DeepEquals, []string{"bar", "foo"})
 }
 
+func (s *controllerSuite) TestConfigSetPublishesTraceParent(c *gc.C) {
+	done := make(chan struct{})
+	var traceParent string
+	s.hub.Subscribe(pscontroller.ConfigChanged, func(topic string, data pscontroller.ConfigChangedMessage, err error) {
+		c.Check(err, jc.ErrorIsNil)
+		traceParent = data.TraceParent
+		close(done)
+	})
+
+	const parent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := trace.ContextWithTraceParent(context.Background(), parent)
+	err := s.controller.ConfigSet(ctx, params.ControllerConfigSet{Config: map[string]interface{}{
+		"features": []string{"foo"},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	select {
+	case <-done:
+	case <-time.After(testing.LongWait):
+		c.Fatal("no event sent}")
+	}
+
+	c.Assert(traceParent, gc.Equals, parent)
+}
+
 func (s *controllerSuite) TestMongoVersion(c *gc.C) {
 	result, err := s.controller.MongoVersion()
 	c.Assert(err, jc.ErrorIsNil)
@@ -21,13 +21,12 @@ func (mm *MachineManagerAPI) InstanceTypes(cons params.ModelInstanceTypesConstra
 
 type environGetFunc func(st environs.EnvironConfigGetter, newEnviron environs.NewEnvironFunc) (environs.Environ, error)
 
-func instanceTypes(mm *MachineManagerAPI,
-	getEnviron environGetFunc,
-	cons params.ModelInstanceTypesConstraints,
-) (params.InstanceTypesResults, error) {
+// modelEnviron returns the environ of the cloud and region in which the
+// current model is deployed.
+func (mm *MachineManagerAPI) modelEnviron(getEnviron environGetFunc) (environs.Environ, error) {
 	model, err := mm.st.Model()
 	if err != nil {
-		return params.InstanceTypesResults{}, errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
 
 	cloudSpec := func() (environs.CloudSpec, error) {
@@ -40,8 +39,15 @@ func instanceTypes(mm *MachineManagerAPI,
 		CloudSpecFunc:   cloudSpec,
 		ModelConfigFunc: model.Config,
 	}
-
 	env, err := getEnviron(backend, environs.New)
+	return env, errors.Trace(err)
+}
+
+func instanceTypes(mm *MachineManagerAPI,
+	getEnviron environGetFunc,
+	cons params.ModelInstanceTypesConstraints,
+) (params.InstanceTypesResults, error) {
+	env, err := mm.modelEnviron(getEnviron)
 	if err != nil {
 		return params.InstanceTypesResults{}, errors.Trace(err)
 	}
@@ -71,6 +71,18 @@ type MachineManagerAPIV5 struct {
 // Version 6 of Machine Manager API.
 // Changes input parameters to DestroyMachineWithParams and ForceDestroyMachine.
 type MachineManagerAPIV6 struct {
+	*MachineManagerAPIV7
+}
+
+// Version 7 of Machine Manager API.
+// Adds CloudQuotas.
+type MachineManagerAPIV7 struct {
+	*MachineManagerAPIV8
+}
+
+// Version 8 of Machine Manager API.
+// Adds KeepWorkloads to DestroyMachineWithParams.
+type MachineManagerAPIV8 struct {
 	*MachineManagerAPI
 }
 
@@ -94,11 +106,29 @@ func NewFacadeV5(ctx facade.Context) (*MachineManagerAPIV5, error) {
 
 // NewFacadeV6 creates a new server-side MachineManager API facade.
 func NewFacadeV6(ctx facade.Context) (*MachineManagerAPIV6, error) {
+	machineManagerAPIv7, err := NewFacadeV7(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV6{machineManagerAPIv7}, nil
+}
+
+// NewFacadeV7 creates a new server-side MachineManager API facade.
+func NewFacadeV7(ctx facade.Context) (*MachineManagerAPIV7, error) {
+	machineManagerAPIv8, err := NewFacadeV8(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &MachineManagerAPIV7{machineManagerAPIv8}, nil
+}
+
+// NewFacadeV8 creates a new server-side MachineManager API facade.
+func NewFacadeV8(ctx facade.Context) (*MachineManagerAPIV8, error) {
 	machineManagerAPI, err := NewFacade(ctx)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	return &MachineManagerAPIV6{machineManagerAPI}, nil
+	return &MachineManagerAPIV8{machineManagerAPI}, nil
 }
 
 // NewMachineManagerAPI creates a new server-side MachineManager API facade.
@@ -270,14 +300,14 @@ func (mm *MachineManagerAPI) addOneMachine(p params.AddMachineParams) (*state.Ma
 
 // DestroyMachine removes a set of machines from the model.
 func (mm *MachineManagerAPI) DestroyMachine(args params.Entities) (params.DestroyMachineResults, error) {
-	return mm.destroyMachine(args, false, false, time.Duration(0))
+	return mm.destroyMachine(args, false, false, false, time.Duration(0))
 }
 
 // ForceDestroyMachine forcibly removes a set of machines from the model.
 // TODO (anastasiamac 2019-4-24) From Juju 3.0 this call will be removed in favour of DestroyMachinesWithParams.
 // Also from ModelManger v6 this call is less useful as it does not support MaxWait customisation.
 func (mm *MachineManagerAPI) ForceDestroyMachine(args params.Entities) (params.DestroyMachineResults, error) {
-	return mm.destroyMachine(args, true, false, time.Duration(0))
+	return mm.destroyMachine(args, true, false, false, time.Duration(0))
 }
 
 // DestroyMachineWithParams removes a set of machines from the model.
@@ -287,7 +317,17 @@ func (mm *MachineManagerAPIV5) DestroyMachineWithParams(args params.DestroyMachi
 	for i, tag := range args.MachineTags {
 		entities.Entities[i].Tag = tag
 	}
-	return mm.destroyMachine(entities, args.Force, args.Keep, time.Duration(0))
+	return mm.destroyMachine(entities, args.Force, args.Keep, false, time.Duration(0))
+}
+
+// DestroyMachineWithParams removes a set of machines from the model.
+// v7 and prior versions did not support KeepWorkloads.
+func (mm *MachineManagerAPIV7) DestroyMachineWithParams(args params.DestroyMachinesParams) (params.DestroyMachineResults, error) {
+	entities := params.Entities{Entities: make([]params.Entity, len(args.MachineTags))}
+	for i, tag := range args.MachineTags {
+		entities.Entities[i].Tag = tag
+	}
+	return mm.destroyMachine(entities, args.Force, args.Keep, false, common.MaxWait(args.MaxWait))
 }
 
 // DestroyMachineWithParams removes a set of machines from the model.
@@ -296,10 +336,10 @@ func (mm *MachineManagerAPI) DestroyMachineWithParams(args params.DestroyMachine
 	for i, tag := range args.MachineTags {
 		entities.Entities[i].Tag = tag
 	}
-	return mm.destroyMachine(entities, args.Force, args.Keep, common.MaxWait(args.MaxWait))
+	return mm.destroyMachine(entities, args.Force, args.Keep, args.KeepWorkloads, common.MaxWait(args.MaxWait))
 }
 
-func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bool, maxWait time.Duration) (params.DestroyMachineResults, error) {
+func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep, keepWorkloads bool, maxWait time.Duration) (params.DestroyMachineResults, error) {
 	if err := mm.checkCanWrite(); err != nil {
 		return params.DestroyMachineResults{}, err
 	}
@@ -330,6 +370,15 @@ func (mm *MachineManagerAPI) destroyMachine(args params.Entities, force, keep bo
 				logger.Warningf("could not keep instance for machine %v: %v", machineTag.Id(), err)
 			}
 		}
+		if keepWorkloads {
+			logger.Infof("destroy machine %v but keep workloads", machineTag.Id())
+			if err := machine.SetKeepWorkloads(keepWorkloads); err != nil {
+				if !force {
+					return fail(err)
+				}
+				logger.Warningf("could not keep workloads for machine %v: %v", machineTag.Id(), err)
+			}
+		}
 		var info params.DestroyMachineInfo
 		units, err := machine.Units()
 		if err != nil {
@@ -375,6 +375,32 @@ func (s *MachineManagerSuite) TestDestroyMachineWithParamsV4(c *gc.C) {
 	})
 }
 
+func (s *MachineManagerSuite) TestDestroyMachineWithParamsKeepWorkloads(c *gc.C) {
+	s.st.machines["0"] = &mockMachine{}
+	_, err := s.api.DestroyMachineWithParams(params.DestroyMachinesParams{
+		KeepWorkloads: true,
+		MachineTags:   []string{"machine-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.st.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.(*mockMachine).keepWorkloads, jc.IsTrue)
+	c.Assert(m.(*mockMachine).keep, jc.IsFalse)
+}
+
+func (s *MachineManagerSuite) TestDestroyMachineWithParamsKeepWorkloadsV7(c *gc.C) {
+	apiV7 := s.machineManagerAPIV7()
+	s.st.machines["0"] = &mockMachine{}
+	_, err := apiV7.DestroyMachineWithParams(params.DestroyMachinesParams{
+		KeepWorkloads: true,
+		MachineTags:   []string{"machine-0"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	m, err := s.st.Machine("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(m.(*mockMachine).keepWorkloads, jc.IsFalse)
+}
+
 func (s *MachineManagerSuite) assertDestroyMachineWithParams(c *gc.C, in params.DestroyMachinesParams, out params.DestroyMachineResults) {
 	s.st.machines["0"] = &mockMachine{}
 	results, err := s.api.DestroyMachineWithParams(in)
@@ -450,7 +476,8 @@ func (s *MachineManagerSuite) setupUpgradeSeries(c *gc.C) {
 }
 
 func (s *MachineManagerSuite) apiV5() machinemanager.MachineManagerAPIV5 {
-	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{s.api}}
+	apiV7 := s.machineManagerAPIV7()
+	return machinemanager.MachineManagerAPIV5{MachineManagerAPIV6: &machinemanager.MachineManagerAPIV6{&apiV7}}
 }
 
 func (s *MachineManagerSuite) TestUpgradeSeriesValidateOK(c *gc.C) {
@@ -922,6 +949,7 @@ type mockMachine struct {
 	machinemanager.Machine
 
 	keep           bool
+	keepWorkloads  bool
 	series         string
 	units          []string
 	unitAgentState status.Status
@@ -952,6 +980,12 @@ func (m *mockMachine) SetKeepInstance(keep bool) error {
 	return nil
 }
 
+func (m *mockMachine) SetKeepWorkloads(keep bool) error {
+	m.MethodCall(m, "SetKeepWorkloads", keep)
+	m.keepWorkloads = keep
+	return nil
+}
+
 func (m *mockMachine) Series() string {
 	m.MethodCall(m, "Series")
 	return m.series
@@ -1065,6 +1099,10 @@ func (v *mockVolume) Detachable() bool {
 	return v.detachable
 }
 
+func (s *MachineManagerSuite) machineManagerAPIV7() machinemanager.MachineManagerAPIV7 {
+	return machinemanager.MachineManagerAPIV7{&machinemanager.MachineManagerAPIV8{s.api}}
+}
+
 func (s *MachineManagerSuite) machineManagerAPIV4() machinemanager.MachineManagerAPIV4 {
 	apiV5 := s.apiV5()
 	return machinemanager.MachineManagerAPIV4{&apiV5}
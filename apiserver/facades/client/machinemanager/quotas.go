@@ -0,0 +1,56 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+)
+
+// CloudQuotas returns the quotas of the cloud region in which the
+// current model is deployed, and how much of each is in use.
+func (mm *MachineManagerAPI) CloudQuotas() (params.CloudQuotasResult, error) {
+	return cloudQuotas(mm, environs.GetEnviron)
+}
+
+// CloudQuotas was added in version 7 of the API. The API reflection code
+// skips 2-argument methods, so this masks it out of version 6.
+func (*MachineManagerAPIV6) CloudQuotas(_, _ struct{}) {}
+
+func cloudQuotas(mm *MachineManagerAPI, getEnviron environGetFunc) (params.CloudQuotasResult, error) {
+	if err := mm.checkCanRead(); err != nil {
+		return params.CloudQuotasResult{}, err
+	}
+	model, err := mm.st.Model()
+	if err != nil {
+		return params.CloudQuotasResult{}, errors.Trace(err)
+	}
+	env, err := mm.modelEnviron(getEnviron)
+	if err != nil {
+		return params.CloudQuotasResult{}, errors.Trace(err)
+	}
+	checker, ok := env.(environs.QuotaChecker)
+	if !ok {
+		return params.CloudQuotasResult{}, errors.NotSupportedf("reporting quotas of cloud %q", model.Cloud())
+	}
+	quotas, err := checker.Quotas(mm.callContext)
+	if err != nil {
+		return params.CloudQuotasResult{}, errors.Trace(err)
+	}
+	result := params.CloudQuotasResult{
+		Cloud:  model.Cloud(),
+		Region: model.CloudRegion(),
+		Quotas: make([]params.CloudQuota, len(quotas)),
+	}
+	for i, quota := range quotas {
+		result.Quotas[i] = params.CloudQuota{
+			Name:  quota.Name,
+			Limit: quota.Limit,
+			Usage: quota.Usage,
+		}
+	}
+	return result, nil
+}
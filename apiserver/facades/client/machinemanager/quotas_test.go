@@ -0,0 +1,76 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinemanager_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facades/client/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+type cloudQuotasSuite struct {
+	api *machinemanager.MachineManagerAPI
+}
+
+var _ = gc.Suite(&cloudQuotasSuite{})
+
+func (s *cloudQuotasSuite) SetUpTest(c *gc.C) {
+	backend := &mockBackend{}
+	admin := names.NewUserTag("admin")
+	authorizer := testing.FakeAuthorizer{Tag: admin, AdminTag: admin}
+	api, err := machinemanager.NewMachineManagerAPI(backend, backend, &mockPool{}, authorizer, backend.ModelTag(), context.NewCloudCallContext(), common.NewResources())
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *cloudQuotasSuite) TestCloudQuotas(c *gc.C) {
+	env := &mockQuotaEnviron{
+		quotas: []environs.Quota{
+			{Name: "vCPUs", Limit: 24, Usage: 8},
+			{Name: "instances", Limit: 100, Usage: 4},
+		},
+	}
+	getEnviron := func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+		return env, nil
+	}
+	result, err := machinemanager.CloudQuotas(s.api, getEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.CloudQuotasResult{
+		Cloud:  "a-cloud",
+		Region: "a-region",
+		Quotas: []params.CloudQuota{
+			{Name: "vCPUs", Limit: 24, Usage: 8},
+			{Name: "instances", Limit: 100, Usage: 4},
+		},
+	})
+}
+
+func (s *cloudQuotasSuite) TestCloudQuotasNotSupported(c *gc.C) {
+	getEnviron := func(environs.EnvironConfigGetter, environs.NewEnvironFunc) (environs.Environ, error) {
+		return &mockEnviron{}, nil
+	}
+	_, err := machinemanager.CloudQuotas(s.api, getEnviron)
+	c.Assert(err, gc.ErrorMatches, `reporting quotas of cloud "a-cloud" not supported`)
+}
+
+type mockQuotaEnviron struct {
+	mockEnviron
+
+	quotas []environs.Quota
+}
+
+func (m *mockQuotaEnviron) Quotas(ctx context.ProviderCallContext) ([]environs.Quota, error) {
+	return m.quotas, nil
+}
+
+func (m *mockQuotaEnviron) CheckInstanceQuotas(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	return nil
+}
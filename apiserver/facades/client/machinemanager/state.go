@@ -48,6 +48,7 @@ type Machine interface {
 	Series() string
 	Units() ([]Unit, error)
 	SetKeepInstance(keepInstance bool) error
+	SetKeepWorkloads(keepWorkloads bool) error
 	CreateUpgradeSeriesLock([]string, string) error
 	RemoveUpgradeSeriesLock() error
 	CompleteUpgradeSeries() error
@@ -75,7 +75,13 @@ func (s *baseStorageSuite) SetUpTest(c *gc.C) {
 	s.apiv3 = &storage.StorageAPIv3{
 		StorageAPIv4: storage.StorageAPIv4{
 			StorageAPIv5: storage.StorageAPIv5{
-				StorageAPI: *newAPI,
+				StorageAPIv6: storage.StorageAPIv6{
+					StorageAPIv7: storage.StorageAPIv7{
+						StorageAPIv8: storage.StorageAPIv8{
+							StorageAPI: *newAPI,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -71,6 +71,13 @@ type mockStorageAccessor struct {
 	attachStorage                       func(names.StorageTag, names.UnitTag) error
 	detachStorage                       func(names.StorageTag, names.UnitTag, bool) error
 	addExistingFilesystem               func(state.FilesystemInfo, *state.VolumeInfo, string) (names.StorageTag, error)
+	createStorageSnapshot               func(names.StorageTag) (string, error)
+	allStorageSnapshots                 func() ([]state.StorageSnapshot, error)
+	storageInstanceSnapshots            func(names.StorageTag) ([]state.StorageSnapshot, error)
+	destroyStorageSnapshot              func(string) error
+	restoreStorage                      func(string, names.UnitTag) (names.StorageTag, error)
+	resizeStorageInstance               func(names.StorageTag, uint64) error
+	moveStorageInstance                 func(names.StorageTag, string) error
 }
 
 func (st *mockStorageAccessor) VolumeAccess() storage.StorageVolume {
@@ -189,6 +196,34 @@ func (st *mockStorageAccessor) AddExistingFilesystem(f state.FilesystemInfo, v *
 	return st.addExistingFilesystem(f, v, s)
 }
 
+func (st *mockStorageAccessor) CreateStorageSnapshot(tag names.StorageTag) (string, error) {
+	return st.createStorageSnapshot(tag)
+}
+
+func (st *mockStorageAccessor) AllStorageSnapshots() ([]state.StorageSnapshot, error) {
+	return st.allStorageSnapshots()
+}
+
+func (st *mockStorageAccessor) StorageInstanceSnapshots(tag names.StorageTag) ([]state.StorageSnapshot, error) {
+	return st.storageInstanceSnapshots(tag)
+}
+
+func (st *mockStorageAccessor) DestroyStorageSnapshot(id string) error {
+	return st.destroyStorageSnapshot(id)
+}
+
+func (st *mockStorageAccessor) RestoreStorage(snapshotId string, unit names.UnitTag) (names.StorageTag, error) {
+	return st.restoreStorage(snapshotId, unit)
+}
+
+func (st *mockStorageAccessor) ResizeStorageInstance(tag names.StorageTag, size uint64) error {
+	return st.resizeStorageInstance(tag, size)
+}
+
+func (st *mockStorageAccessor) MoveStorageInstance(tag names.StorageTag, pool string) error {
+	return st.moveStorageInstance(tag, pool)
+}
+
 type mockVolume struct {
 	state.Volume
 	tag     names.VolumeTag
@@ -331,6 +366,58 @@ func (m *mockStorageInstance) Life() state.Life {
 	return m.life
 }
 
+type mockStorageSnapshot struct {
+	state.StorageSnapshot
+	id         string
+	storage    names.StorageTag
+	kind       state.StorageKind
+	pool       string
+	size       uint64
+	status     state.StorageSnapshotStatus
+	snapshotId string
+	created    time.Time
+	life       state.Life
+}
+
+func (m *mockStorageSnapshot) Id() string {
+	return m.id
+}
+
+func (m *mockStorageSnapshot) StorageInstance() names.StorageTag {
+	return m.storage
+}
+
+func (m *mockStorageSnapshot) Kind() state.StorageKind {
+	return m.kind
+}
+
+func (m *mockStorageSnapshot) Pool() string {
+	return m.pool
+}
+
+func (m *mockStorageSnapshot) Size() uint64 {
+	return m.size
+}
+
+func (m *mockStorageSnapshot) Status() (state.StorageSnapshotStatus, string) {
+	return m.status, ""
+}
+
+func (m *mockStorageSnapshot) SnapshotId() (string, error) {
+	if m.status != state.StorageSnapshotAvailable {
+		return "", errors.NotProvisionedf("storage snapshot %q", m.id)
+	}
+	return m.snapshotId, nil
+}
+
+func (m *mockStorageSnapshot) Created() time.Time {
+	return m.created
+}
+
+func (m *mockStorageSnapshot) Life() state.Life {
+	return m.life
+}
+
 type mockStorageAttachment struct {
 	state.StorageAttachment
 	storage *mockStorageInstance
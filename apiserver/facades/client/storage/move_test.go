@@ -0,0 +1,58 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type storageMoveSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageMoveSuite{})
+
+func (s *storageMoveSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.storageAccessor.moveStorageInstance = func(tag names.StorageTag, pool string) error {
+		s.stub.AddCall("moveStorageInstance", tag, pool)
+		if tag == s.storageTag {
+			return nil
+		}
+		return errors.NotFoundf("%s", names.ReadableString(tag))
+	}
+}
+
+func (s *storageMoveSuite) TestMoveStorage(c *gc.C) {
+	results, err := s.api.MoveStorage(params.BulkMoveStorageParams{
+		Args: []params.MoveStorageParams{
+			{StorageTag: s.storageTag.String(), Pool: "ebs"},
+			{StorageTag: "storage-foo-42", Pool: "ebs"},
+			{StorageTag: "volume-0", Pool: "ebs"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: "storage foo/42 not found"}},
+			{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+		},
+	})
+	s.stub.CheckCallNames(c, getBlockForTypeCall, "moveStorageInstance", "moveStorageInstance")
+	s.stub.CheckCall(c, 1, "moveStorageInstance", s.storageTag, "ebs")
+}
+
+func (s *storageMoveSuite) TestMoveStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestMoveStorageBlocked")
+	_, err := s.api.MoveStorage(params.BulkMoveStorageParams{
+		Args: []params.MoveStorageParams{{StorageTag: s.storageTag.String(), Pool: "ebs"}},
+	})
+	s.assertBlocked(c, err, "TestMoveStorageBlocked")
+}
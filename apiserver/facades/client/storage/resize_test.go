@@ -0,0 +1,58 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type storageResizeSuite struct {
+	baseStorageSuite
+}
+
+var _ = gc.Suite(&storageResizeSuite{})
+
+func (s *storageResizeSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.storageAccessor.resizeStorageInstance = func(tag names.StorageTag, size uint64) error {
+		s.stub.AddCall("resizeStorageInstance", tag, size)
+		if tag == s.storageTag {
+			return nil
+		}
+		return errors.NotFoundf("%s", names.ReadableString(tag))
+	}
+}
+
+func (s *storageResizeSuite) TestResizeStorage(c *gc.C) {
+	results, err := s.api.ResizeStorage(params.BulkResizeStorageParams{
+		Args: []params.ResizeStorageParams{
+			{StorageTag: s.storageTag.String(), Size: 2048},
+			{StorageTag: "storage-foo-42", Size: 2048},
+			{StorageTag: "volume-0", Size: 2048},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: "storage foo/42 not found"}},
+			{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+		},
+	})
+	s.stub.CheckCallNames(c, getBlockForTypeCall, "resizeStorageInstance", "resizeStorageInstance")
+	s.stub.CheckCall(c, 1, "resizeStorageInstance", s.storageTag, uint64(2048))
+}
+
+func (s *storageResizeSuite) TestResizeStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestResizeStorageBlocked")
+	_, err := s.api.ResizeStorage(params.BulkResizeStorageParams{
+		Args: []params.ResizeStorageParams{{StorageTag: s.storageTag.String(), Size: 2048}},
+	})
+	s.assertBlocked(c, err, "TestResizeStorageBlocked")
+}
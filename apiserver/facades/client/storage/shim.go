@@ -61,6 +61,33 @@ type storageInterface interface {
 
 	// ReleaseStorageInstance releases the storage instance with the specified tag.
 	ReleaseStorageInstance(names.StorageTag, bool, bool, time.Duration) error
+
+	// CreateStorageSnapshot requests a snapshot of the storage
+	// instance with the specified tag.
+	CreateStorageSnapshot(names.StorageTag) (string, error)
+
+	// AllStorageSnapshots returns all storage snapshots in the model.
+	AllStorageSnapshots() ([]state.StorageSnapshot, error)
+
+	// StorageInstanceSnapshots returns the snapshots of the storage
+	// instance with the specified tag.
+	StorageInstanceSnapshots(names.StorageTag) ([]state.StorageSnapshot, error)
+
+	// DestroyStorageSnapshot destroys the storage snapshot
+	// with the specified ID.
+	DestroyStorageSnapshot(string) error
+
+	// RestoreStorage creates a storage instance from the storage
+	// snapshot with the specified ID, attached to the specified unit.
+	RestoreStorage(string, names.UnitTag) (names.StorageTag, error)
+
+	// ResizeStorageInstance requests that the storage instance with
+	// the specified tag be grown to the specified size, in MiB.
+	ResizeStorageInstance(names.StorageTag, uint64) error
+
+	// MoveStorageInstance requests that the storage instance with
+	// the specified tag be moved to the named storage pool.
+	MoveStorageInstance(names.StorageTag, string) error
 }
 
 type storageVolume interface {
@@ -0,0 +1,196 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/state"
+)
+
+type storageSnapshotSuite struct {
+	baseStorageSuite
+	snapshot *mockStorageSnapshot
+}
+
+var _ = gc.Suite(&storageSnapshotSuite{})
+
+func (s *storageSnapshotSuite) SetUpTest(c *gc.C) {
+	s.baseStorageSuite.SetUpTest(c)
+	s.snapshot = &mockStorageSnapshot{
+		id:         "66/0",
+		storage:    s.storageTag,
+		kind:       state.StorageKindFilesystem,
+		pool:       "loop",
+		size:       1024,
+		status:     state.StorageSnapshotAvailable,
+		snapshotId: "snapshot-66-0",
+		created:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	s.storageAccessor.createStorageSnapshot = func(tag names.StorageTag) (string, error) {
+		s.stub.AddCall("createStorageSnapshot", tag)
+		if tag == s.storageTag {
+			return s.snapshot.id, nil
+		}
+		return "", errors.NotFoundf("%s", names.ReadableString(tag))
+	}
+	s.storageAccessor.allStorageSnapshots = func() ([]state.StorageSnapshot, error) {
+		s.stub.AddCall("allStorageSnapshots")
+		return []state.StorageSnapshot{s.snapshot}, nil
+	}
+	s.storageAccessor.storageInstanceSnapshots = func(tag names.StorageTag) ([]state.StorageSnapshot, error) {
+		s.stub.AddCall("storageInstanceSnapshots", tag)
+		if tag == s.storageTag {
+			return []state.StorageSnapshot{s.snapshot}, nil
+		}
+		return nil, nil
+	}
+	s.storageAccessor.restoreStorage = func(id string, unit names.UnitTag) (names.StorageTag, error) {
+		s.stub.AddCall("restoreStorage", id, unit)
+		if id == s.snapshot.id {
+			return names.NewStorageTag("data/1"), nil
+		}
+		return names.StorageTag{}, errors.NotFoundf("storage snapshot %q", id)
+	}
+	s.storageAccessor.destroyStorageSnapshot = func(id string) error {
+		s.stub.AddCall("destroyStorageSnapshot", id)
+		if id == s.snapshot.id {
+			return nil
+		}
+		return errors.NotFoundf("storage snapshot %q", id)
+	}
+}
+
+func (s *storageSnapshotSuite) TestCreateStorageSnapshot(c *gc.C) {
+	results, err := s.api.CreateStorageSnapshot(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.storageTag.String()},
+			{Tag: "storage-foo-42"},
+			{Tag: "volume-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "66/0"},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: "storage foo/42 not found"}},
+			{Error: &params.Error{Message: `"volume-0" is not a valid storage tag`}},
+		},
+	})
+	s.stub.CheckCallNames(c, getBlockForTypeCall, "createStorageSnapshot", "createStorageSnapshot")
+}
+
+func (s *storageSnapshotSuite) TestCreateStorageSnapshotBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestCreateStorageSnapshotBlocked")
+	_, err := s.api.CreateStorageSnapshot(params.Entities{
+		Entities: []params.Entity{{Tag: s.storageTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestCreateStorageSnapshotBlocked")
+}
+
+func (s *storageSnapshotSuite) TestListStorageSnapshots(c *gc.C) {
+	expected := params.StorageSnapshotDetailsResults{
+		Results: []params.StorageSnapshotDetails{{
+			Id:         "66/0",
+			StorageTag: s.storageTag.String(),
+			Kind:       params.StorageKindFilesystem,
+			Pool:       "loop",
+			Size:       1024,
+			Status:     "available",
+			Life:       life.Alive,
+			SnapshotId: "snapshot-66-0",
+			Created:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	results, err := s.api.ListStorageSnapshots(params.Entities{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+
+	results, err = s.api.ListStorageSnapshots(params.Entities{
+		Entities: []params.Entity{{Tag: s.storageTag.String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expected)
+	s.stub.CheckCallNames(c, "allStorageSnapshots", "storageInstanceSnapshots")
+}
+
+func (s *storageSnapshotSuite) TestListStorageSnapshotsPending(c *gc.C) {
+	s.snapshot.status = state.StorageSnapshotPending
+	results, err := s.api.ListStorageSnapshots(params.Entities{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Status, gc.Equals, "pending")
+	c.Assert(results.Results[0].SnapshotId, gc.Equals, "")
+}
+
+func (s *storageSnapshotSuite) TestListStorageSnapshotsDying(c *gc.C) {
+	s.snapshot.life = state.Dying
+	results, err := s.api.ListStorageSnapshots(params.Entities{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Life, gc.Equals, life.Dying)
+}
+
+func (s *storageSnapshotSuite) TestDestroyStorageSnapshot(c *gc.C) {
+	results, err := s.api.DestroyStorageSnapshot(params.StorageSnapshotIds{
+		Ids: []string{"66/0", "42"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: `storage snapshot "42" not found`}},
+		},
+	})
+	s.stub.CheckCallNames(c,
+		getBlockForTypeCall, // Remove
+		getBlockForTypeCall, // Change
+		"destroyStorageSnapshot",
+		"destroyStorageSnapshot",
+	)
+	s.stub.CheckCall(c, 2, "destroyStorageSnapshot", "66/0")
+}
+
+func (s *storageSnapshotSuite) TestDestroyStorageSnapshotBlocked(c *gc.C) {
+	s.blockRemoveObject(c, "TestDestroyStorageSnapshotBlocked")
+	_, err := s.api.DestroyStorageSnapshot(params.StorageSnapshotIds{
+		Ids: []string{"66/0"},
+	})
+	s.assertBlocked(c, err, "TestDestroyStorageSnapshotBlocked")
+}
+
+func (s *storageSnapshotSuite) TestRestoreStorage(c *gc.C) {
+	results, err := s.api.RestoreStorage(params.BulkRestoreStorageParams{
+		Args: []params.RestoreStorageParams{
+			{SnapshotId: "66/0", UnitTag: s.unitTag.String()},
+			{SnapshotId: "42", UnitTag: s.unitTag.String()},
+			{SnapshotId: "66/0", UnitTag: "machine-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{
+			{Result: "storage-data-1"},
+			{Error: &params.Error{Code: params.CodeNotFound, Message: `storage snapshot "42" not found`}},
+			{Error: &params.Error{Message: `"machine-0" is not a valid unit tag`}},
+		},
+	})
+	s.stub.CheckCall(c, 1, "restoreStorage", "66/0", s.unitTag)
+}
+
+func (s *storageSnapshotSuite) TestRestoreStorageBlocked(c *gc.C) {
+	s.blockAllChanges(c, "TestRestoreStorageBlocked")
+	_, err := s.api.RestoreStorage(params.BulkRestoreStorageParams{
+		Args: []params.RestoreStorageParams{{SnapshotId: "66/0", UnitTag: s.unitTag.String()}},
+	})
+	s.assertBlocked(c, err, "TestRestoreStorageBlocked")
+}
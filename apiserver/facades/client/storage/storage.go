@@ -30,7 +30,7 @@ import (
 	"github.com/juju/juju/storage/poolmanager"
 )
 
-// StorageAPI implements the latest version (v6) of the Storage API.
+// StorageAPI implements the latest version (v9) of the Storage API.
 type StorageAPI struct {
 	backend       backend
 	storageAccess storageAccess
@@ -41,9 +41,24 @@ type StorageAPI struct {
 	modelType     state.ModelType
 }
 
+// StorageAPIv8 implements the storage v8 API.
+type StorageAPIv8 struct {
+	StorageAPI
+}
+
+// StorageAPIv7 implements the storage v7 API.
+type StorageAPIv7 struct {
+	StorageAPIv8
+}
+
+// StorageAPIv6 implements the storage v6 API.
+type StorageAPIv6 struct {
+	StorageAPIv7
+}
+
 // APIv5 implements the storage v5 API.
 type StorageAPIv5 struct {
-	StorageAPI
+	StorageAPIv6
 }
 
 // APIv4 implements the storage v4 API adding AddToUnit, Import and Remove (replacing Destroy)
@@ -104,14 +119,47 @@ func newStorageAPI(
 	}
 }
 
+// NewStorageAPIV8 returns a new storage v8 API facade.
+func NewStorageAPIV8(context facade.Context) (*StorageAPIv8, error) {
+	storageAPI, err := NewStorageAPI(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv8{
+		StorageAPI: *storageAPI,
+	}, nil
+}
+
+// NewStorageAPIV7 returns a new storage v7 API facade.
+func NewStorageAPIV7(context facade.Context) (*StorageAPIv7, error) {
+	storageAPI, err := NewStorageAPIV8(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv7{
+		StorageAPIv8: *storageAPI,
+	}, nil
+}
+
+// NewStorageAPIV6 returns a new storage v6 API facade.
+func NewStorageAPIV6(context facade.Context) (*StorageAPIv6, error) {
+	storageAPI, err := NewStorageAPIV7(context)
+	if err != nil {
+		return nil, err
+	}
+	return &StorageAPIv6{
+		StorageAPIv7: *storageAPI,
+	}, nil
+}
+
 // NewStorageAPIV5 returns a new storage v5 API facade.
 func NewStorageAPIV5(context facade.Context) (*StorageAPIv5, error) {
-	storageAPI, err := NewStorageAPI(context)
+	storageAPI, err := NewStorageAPIV6(context)
 	if err != nil {
 		return nil, err
 	}
 	return &StorageAPIv5{
-		StorageAPI: *storageAPI,
+		StorageAPIv6: *storageAPI,
 	}, nil
 }
 
@@ -1209,10 +1257,214 @@ func (a *StorageAPI) UpdatePool(p params.StoragePoolArgs) (params.ErrorResults,
 	return results, nil
 }
 
+// CreateStorageSnapshot requests point-in-time snapshots of the volumes
+// or filesystems assigned to the specified storage instances, returning
+// the ID of each snapshot. Snapshots are taken asynchronously by the
+// storage provisioner.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) CreateStorageSnapshot(args params.Entities) (params.StringResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.StringResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		tag, err := names.ParseStorageTag(arg.Tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		id, err := a.storageAccess.CreateStorageSnapshot(tag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = id
+	}
+	return params.StringResults{Results: results}, nil
+}
+
+// ListStorageSnapshots returns details of the snapshots of the specified
+// storage instances, or of all storage snapshots in the model if none
+// are specified.
+func (a *StorageAPI) ListStorageSnapshots(args params.Entities) (params.StorageSnapshotDetailsResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.StorageSnapshotDetailsResults{}, errors.Trace(err)
+	}
+
+	var snapshots []state.StorageSnapshot
+	if len(args.Entities) == 0 {
+		all, err := a.storageAccess.AllStorageSnapshots()
+		if err != nil {
+			return params.StorageSnapshotDetailsResults{}, errors.Trace(err)
+		}
+		snapshots = all
+	}
+	for _, arg := range args.Entities {
+		tag, err := names.ParseStorageTag(arg.Tag)
+		if err != nil {
+			return params.StorageSnapshotDetailsResults{}, errors.Trace(err)
+		}
+		instanceSnapshots, err := a.storageAccess.StorageInstanceSnapshots(tag)
+		if err != nil {
+			return params.StorageSnapshotDetailsResults{}, errors.Trace(err)
+		}
+		snapshots = append(snapshots, instanceSnapshots...)
+	}
+
+	results := make([]params.StorageSnapshotDetails, len(snapshots))
+	for i, snapshot := range snapshots {
+		results[i] = createStorageSnapshotDetails(snapshot)
+	}
+	return params.StorageSnapshotDetailsResults{Results: results}, nil
+}
+
+func createStorageSnapshotDetails(snapshot state.StorageSnapshot) params.StorageSnapshotDetails {
+	status, message := snapshot.Status()
+	snapshotId, _ := snapshot.SnapshotId()
+	return params.StorageSnapshotDetails{
+		Id:         snapshot.Id(),
+		StorageTag: snapshot.StorageInstance().String(),
+		Kind:       params.StorageKind(snapshot.Kind()),
+		Pool:       snapshot.Pool(),
+		Size:       snapshot.Size(),
+		Status:     string(status),
+		Life:       life.Value(snapshot.Life().String()),
+		Message:    message,
+		SnapshotId: snapshotId,
+		Created:    snapshot.Created(),
+	}
+}
+
+// DestroyStorageSnapshot destroys the storage snapshots with the
+// specified IDs. Snapshots are deleted from the storage provider
+// asynchronously by the storage provisioner.
+// A "REMOVE" block can block this operation.
+func (a *StorageAPI) DestroyStorageSnapshot(args params.StorageSnapshotIds) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.RemoveAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		err := a.storageAccess.DestroyStorageSnapshot(id)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// RestoreStorage creates new storage instances from storage snapshots,
+// and attaches them to the specified units. The tag of each new storage
+// instance is returned.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) RestoreStorage(args params.BulkRestoreStorageParams) (params.StringResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.StringResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.StringResult, len(args.Args))
+	for i, arg := range args.Args {
+		unitTag, err := names.ParseUnitTag(arg.UnitTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		storageTag, err := a.storageAccess.RestoreStorage(arg.SnapshotId, unitTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		results[i].Result = storageTag.String()
+	}
+	return params.StringResults{Results: results}, nil
+}
+
+// ResizeStorage requests that the volumes or filesystems assigned to the
+// specified storage instances be grown to the specified sizes. Resizes
+// are performed asynchronously by the storage provisioner.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) ResizeStorage(args params.BulkResizeStorageParams) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		tag, err := names.ParseStorageTag(arg.StorageTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = a.storageAccess.ResizeStorageInstance(tag, arg.Size)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
+// MoveStorage requests that the specified storage instances be moved to
+// the specified storage pools. The storage's data is copied to a new
+// volume in the target pool, while the unit using the storage is
+// quiesced by running its "storage-detaching" hook.
+// A "CHANGE" block can block this operation.
+func (a *StorageAPI) MoveStorage(args params.BulkMoveStorageParams) (params.ErrorResults, error) {
+	if err := a.checkCanWrite(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	blockChecker := common.NewBlockChecker(a.backend)
+	if err := blockChecker.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+
+	results := make([]params.ErrorResult, len(args.Args))
+	for i, arg := range args.Args {
+		tag, err := names.ParseStorageTag(arg.StorageTag)
+		if err != nil {
+			results[i].Error = common.ServerError(err)
+			continue
+		}
+		err = a.storageAccess.MoveStorageInstance(tag, arg.Pool)
+		results[i].Error = common.ServerError(err)
+	}
+	return params.ErrorResults{Results: results}, nil
+}
+
 // Mask out old methods from the new API versions. The API reflection
 // code in rpc/rpcreflect/type.go:newMethod skips 2-argument methods,
 // so this removes the method as far as the RPC machinery is concerned.
 
+// Added in v7 api version
+func (*StorageAPIv6) CreateStorageSnapshot(_, _ struct{})  {}
+func (*StorageAPIv6) ListStorageSnapshots(_, _ struct{})   {}
+func (*StorageAPIv6) RestoreStorage(_, _ struct{})         {}
+func (*StorageAPIv6) DestroyStorageSnapshot(_, _ struct{}) {}
+
+// Added in v9 api version
+func (*StorageAPIv8) MoveStorage(_, _ struct{}) {}
+
+// Added in v8 api version
+func (*StorageAPIv7) ResizeStorage(_, _ struct{}) {}
+
 // Added in v6 api version
 func (*StorageAPIv5) DetachStorage(_, _ struct{}) {}
 
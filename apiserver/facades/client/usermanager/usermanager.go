@@ -430,3 +430,173 @@ func (api *UserManagerAPI) ResetPassword(args params.Entities) (params.AddUserRe
 	}
 	return result, nil
 }
+
+// AddAPITokens mints API tokens that allow automation to log in to the
+// specified models as the token's owner, with at most the given access,
+// until the token expires or is revoked. Controller superusers may mint
+// tokens for any local user; other users only for themselves.
+func (api *UserManagerAPI) AddAPITokens(args params.AddAPITokens) (params.AddAPITokenResults, error) {
+	var result params.AddAPITokenResults
+
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	if len(args.Tokens) == 0 {
+		return result, nil
+	}
+
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.AddAPITokenResult, len(args.Tokens))
+	for i, arg := range args.Tokens {
+		token, secret, err := api.addAPIToken(arg, isSuperUser)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		info := apiTokenInfo(token)
+		result.Results[i] = params.AddAPITokenResult{
+			Token:  &info,
+			Secret: secret,
+		}
+	}
+	return result, nil
+}
+
+func (api *UserManagerAPI) addAPIToken(arg params.AddAPIToken, isSuperUser bool) (*state.APIToken, string, error) {
+	owner, err := names.ParseUserTag(arg.OwnerTag)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if !isSuperUser && owner != api.apiUser {
+		return nil, "", errors.Trace(common.ErrPerm)
+	}
+	access := permission.Access(arg.Access)
+	modelUUIDs := make([]string, len(arg.ModelTags))
+	for i, tagStr := range arg.ModelTags {
+		modelTag, err := names.ParseModelTag(tagStr)
+		if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		exists, err := api.state.ModelExists(modelTag.Id())
+		if err != nil {
+			return nil, "", errors.Trace(err)
+		}
+		if !exists {
+			return nil, "", errors.NotFoundf("model %q", modelTag.Id())
+		}
+		if !isSuperUser {
+			// Users can't hand out more access than they have.
+			ok, err := api.authorizer.HasPermission(access, modelTag)
+			if err != nil && !errors.IsNotFound(err) {
+				return nil, "", errors.Trace(err)
+			}
+			if !ok {
+				return nil, "", errors.Trace(common.ErrPerm)
+			}
+		}
+		modelUUIDs[i] = modelTag.Id()
+	}
+	token, secret, err := api.state.AddAPIToken(state.AddAPITokenArgs{
+		Owner:       owner,
+		Description: arg.Description,
+		Models:      modelUUIDs,
+		Access:      access,
+		Expires:     arg.Expires,
+		CreatedBy:   api.apiUser,
+	})
+	if err != nil {
+		return nil, "", errors.Annotate(err, "failed to create API token")
+	}
+	return token, secret, nil
+}
+
+// APITokens returns the API tokens owned by each of the given users,
+// including expired and revoked tokens. Token secrets are never returned.
+func (api *UserManagerAPI) APITokens(args params.Entities) (params.APITokensResults, error) {
+	var result params.APITokensResults
+
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.APITokensResult, len(args.Entities))
+	for i, arg := range args.Entities {
+		owner, err := names.ParseUserTag(arg.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !isSuperUser && owner != api.apiUser {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		tokens, err := api.state.APITokens(owner)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Tokens = make([]params.APIToken, len(tokens))
+		for j, token := range tokens {
+			result.Results[i].Tokens[j] = apiTokenInfo(token)
+		}
+	}
+	return result, nil
+}
+
+// RevokeAPITokens revokes the API tokens with the given IDs, so that they
+// can no longer be used to log in. Controller superusers may revoke any
+// token; other users only their own.
+func (api *UserManagerAPI) RevokeAPITokens(args params.APITokenIds) (params.ErrorResults, error) {
+	var result params.ErrorResults
+
+	if err := api.check.ChangeAllowed(); err != nil {
+		return result, errors.Trace(err)
+	}
+
+	isSuperUser, err := api.hasControllerAdminAccess()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+
+	result.Results = make([]params.ErrorResult, len(args.Ids))
+	for i, id := range args.Ids {
+		token, err := api.state.APIToken(id)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if !isSuperUser && token.Owner() != api.apiUser {
+			// Don't reveal the existence of other users' tokens.
+			result.Results[i].Error = common.ServerError(errors.NotFoundf("API token %q", id))
+			continue
+		}
+		if err := token.Revoke(); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+func apiTokenInfo(token *state.APIToken) params.APIToken {
+	modelTags := make([]string, len(token.Models()))
+	for i, uuid := range token.Models() {
+		modelTags[i] = names.NewModelTag(uuid).String()
+	}
+	return params.APIToken{
+		Id:          token.Id(),
+		OwnerTag:    token.Owner().String(),
+		Description: token.Description(),
+		ModelTags:   modelTags,
+		Access:      string(token.Access()),
+		CreatedBy:   token.CreatedBy(),
+		DateCreated: token.DateCreated(),
+		Expires:     token.Expires(),
+		Revoked:     token.IsRevoked(),
+	}
+}
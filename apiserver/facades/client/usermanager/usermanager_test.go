@@ -992,3 +992,111 @@ func (s *userManagerSuite) TestResetPasswordEmpty(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(results.Results, gc.HasLen, 0)
 }
+
+func (s *userManagerSuite) addAPITokenArgs(owner names.UserTag, access permission.Access) params.AddAPITokens {
+	return params.AddAPITokens{Tokens: []params.AddAPIToken{{
+		OwnerTag:    owner.String(),
+		Description: "nightly deploy",
+		ModelTags:   []string{s.Model.ModelTag().String()},
+		Access:      string(access),
+		Expires:     time.Now().Add(time.Hour),
+	}}}
+}
+
+func (s *userManagerSuite) TestAddAPITokens(c *gc.C) {
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	results, err := s.usermanager.AddAPITokens(s.addAPITokenArgs(bot.UserTag(), permission.WriteAccess))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	c.Assert(result.Secret, gc.Not(gc.Equals), "")
+	c.Assert(result.Token.OwnerTag, gc.Equals, bot.Tag().String())
+	c.Assert(result.Token.ModelTags, jc.DeepEquals, []string{s.Model.ModelTag().String()})
+	c.Assert(result.Token.Access, gc.Equals, "write")
+	c.Assert(result.Token.CreatedBy, gc.Equals, s.adminName)
+
+	token, err := s.State.AuthenticateAPIToken(result.Secret, s.Model.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.Id(), gc.Equals, result.Token.Id)
+}
+
+func (s *userManagerSuite) TestAddAPITokensUnknownModel(c *gc.C) {
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	args := s.addAPITokenArgs(bot.UserTag(), permission.ReadAccess)
+	args.Tokens[0].ModelTags = []string{names.NewModelTag("00000000-0bad-400d-8000-4b1d0d06f00d").String()}
+	results, err := s.usermanager.AddAPITokens(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.ErrorMatches, `model "00000000-0bad-400d-8000-4b1d0d06f00d" not found`)
+}
+
+func (s *userManagerSuite) TestAddAPITokensNotControllerAdmin(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag(), HasWriteTag: alex.UserTag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Users may not mint tokens for others.
+	results, err := usermanager.AddAPITokens(s.addAPITokenArgs(bot.UserTag(), permission.ReadAccess))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, common.ServerError(common.ErrPerm))
+
+	// Nor grant more access than they have.
+	results, err = usermanager.AddAPITokens(s.addAPITokenArgs(alex.UserTag(), permission.AdminAccess))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, common.ServerError(common.ErrPerm))
+
+	results, err = usermanager.AddAPITokens(s.addAPITokenArgs(alex.UserTag(), permission.WriteAccess))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+}
+
+func (s *userManagerSuite) TestAddAPITokensBlocked(c *gc.C) {
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	s.BlockAllChanges(c, "TestAddAPITokensBlocked")
+	_, err := s.usermanager.AddAPITokens(s.addAPITokenArgs(bot.UserTag(), permission.ReadAccess))
+	s.AssertBlocked(c, err, "TestAddAPITokensBlocked")
+}
+
+func (s *userManagerSuite) TestAPITokensAndRevoke(c *gc.C) {
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	added, err := s.usermanager.AddAPITokens(s.addAPITokenArgs(bot.UserTag(), permission.ReadAccess))
+	c.Assert(err, jc.ErrorIsNil)
+	id := added.Results[0].Token.Id
+
+	revoked, err := s.usermanager.RevokeAPITokens(params.APITokenIds{Ids: []string{id, "missing"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revoked.Results, gc.HasLen, 2)
+	c.Assert(revoked.Results[0].Error, gc.IsNil)
+	c.Assert(revoked.Results[1].Error, gc.ErrorMatches, `API token "missing" not found`)
+
+	results, err := s.usermanager.APITokens(params.Entities{Entities: []params.Entity{{Tag: bot.Tag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Tokens, gc.HasLen, 1)
+	c.Assert(results.Results[0].Tokens[0].Id, gc.Equals, id)
+	c.Assert(results.Results[0].Tokens[0].Revoked, jc.IsTrue)
+}
+
+func (s *userManagerSuite) TestAPITokensNotControllerAdmin(c *gc.C) {
+	alex := s.Factory.MakeUser(c, &factory.UserParams{Name: "alex", NoModelUser: true})
+	bot := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	added, err := s.usermanager.AddAPITokens(s.addAPITokenArgs(bot.UserTag(), permission.ReadAccess))
+	c.Assert(err, jc.ErrorIsNil)
+	id := added.Results[0].Token.Id
+
+	usermanager, err := usermanager.NewUserManagerAPI(
+		s.State, s.resources, apiservertesting.FakeAuthorizer{Tag: alex.Tag()})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := usermanager.APITokens(params.Entities{Entities: []params.Entity{{Tag: bot.Tag().String()}}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results[0].Error, jc.DeepEquals, common.ServerError(common.ErrPerm))
+
+	revoked, err := usermanager.RevokeAPITokens(params.APITokenIds{Ids: []string{id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(revoked.Results[0].Error, gc.ErrorMatches, `API token ".*" not found`)
+}
@@ -4,6 +4,8 @@
 package firewaller
 
 import (
+	"sort"
+
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"gopkg.in/juju/names.v3"
@@ -13,7 +15,6 @@ import (
 	"github.com/juju/juju/apiserver/common/firewall"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
-	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/watcher"
@@ -51,6 +52,16 @@ type FirewallerAPIV5 struct {
 	*FirewallerAPIV4
 }
 
+// FirewallerAPIV6 provides access to the Firewaller v6 API facade.
+type FirewallerAPIV6 struct {
+	*FirewallerAPIV5
+}
+
+// FirewallerAPIV7 provides access to the Firewaller v7 API facade.
+type FirewallerAPIV7 struct {
+	*FirewallerAPIV6
+}
+
 // NewStateFirewallerAPIV3 creates a new server-side FirewallerAPIV3 facade.
 func NewStateFirewallerAPIV3(context facade.Context) (*FirewallerAPIV3, error) {
 	st := context.State()
@@ -92,6 +103,28 @@ func NewStateFirewallerAPIV5(context facade.Context) (*FirewallerAPIV5, error) {
 	}, nil
 }
 
+// NewStateFirewallerAPIV6 creates a new server-side FirewallerAPIV6 facade.
+func NewStateFirewallerAPIV6(context facade.Context) (*FirewallerAPIV6, error) {
+	facadev5, err := NewStateFirewallerAPIV5(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV6{
+		FirewallerAPIV5: facadev5,
+	}, nil
+}
+
+// NewStateFirewallerAPIV7 creates a new server-side FirewallerAPIV7 facade.
+func NewStateFirewallerAPIV7(context facade.Context) (*FirewallerAPIV7, error) {
+	facadev6, err := NewStateFirewallerAPIV6(context)
+	if err != nil {
+		return nil, err
+	}
+	return &FirewallerAPIV7{
+		FirewallerAPIV6: facadev6,
+	}, nil
+}
+
 // NewFirewallerAPI creates a new server-side FirewallerAPIV3 facade.
 func NewFirewallerAPI(
 	st State,
@@ -245,19 +278,33 @@ func (f *FirewallerAPIV3) GetMachinePorts(args params.MachinePortsParams) (param
 			continue
 		}
 		if ports != nil {
-			portRangeMap := ports.AllPortRanges()
-			var portRanges []network.PortRange
-			for portRange := range portRangeMap {
-				portRanges = append(portRanges, portRange)
-			}
-			network.SortPortRanges(portRanges)
+			// A range opened for several endpoints is returned
+			// once for each of them, ordered by endpoint.
+			portRanges := ports.PortRanges()
+			sort.Slice(portRanges, func(a, b int) bool {
+				rangeA, rangeB := portRanges[a], portRanges[b]
+				switch {
+				case rangeA.Protocol != rangeB.Protocol:
+					return rangeA.Protocol < rangeB.Protocol
+				case rangeA.FromPort != rangeB.FromPort:
+					return rangeA.FromPort < rangeB.FromPort
+				case rangeA.ToPort != rangeB.ToPort:
+					return rangeA.ToPort < rangeB.ToPort
+				}
+				return rangeA.Endpoint < rangeB.Endpoint
+			})
 
 			for _, portRange := range portRanges {
-				unitTag := names.NewUnitTag(portRangeMap[portRange]).String()
+				unitTag := names.NewUnitTag(portRange.UnitName).String()
 				result.Results[i].Ports = append(result.Results[i].Ports,
 					params.MachinePortRange{
-						UnitTag:   unitTag,
-						PortRange: params.FromNetworkPortRange(portRange),
+						UnitTag: unitTag,
+						PortRange: params.PortRange{
+							FromPort: portRange.FromPort,
+							ToPort:   portRange.ToPort,
+							Protocol: portRange.Protocol,
+						},
+						Endpoint: portRange.Endpoint,
 					})
 			}
 		}
@@ -527,3 +574,56 @@ func (f *FirewallerAPIV5) AreManuallyProvisioned(args params.Entities) (params.B
 	}
 	return result, nil
 }
+
+// GetEgressCIDRs returns, for each given application, whether outbound
+// traffic from its units is restricted, and if so the destination CIDRs
+// allowed by its egress policy.
+func (f *FirewallerAPIV6) GetEgressCIDRs(args params.Entities) (params.EgressCIDRsResults, error) {
+	result := params.EgressCIDRsResults{
+		Results: make([]params.EgressCIDRsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.EgressCIDRsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err == nil {
+			result.Results[i].CIDRs, result.Results[i].Restricted, err = application.EgressCIDRs()
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// GetExposedCIDRs returns, for each given application, whether it is
+// exposed, and if so the networks from which each of its exposed
+// endpoints may be reached.
+func (f *FirewallerAPIV7) GetExposedCIDRs(args params.Entities) (params.ExposedCIDRsResults, error) {
+	result := params.ExposedCIDRsResults{
+		Results: make([]params.ExposedCIDRsResult, len(args.Entities)),
+	}
+	canAccess, err := f.accessApplication()
+	if err != nil {
+		return params.ExposedCIDRsResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseApplicationTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		application, err := f.getApplication(canAccess, tag)
+		if err == nil {
+			result.Results[i].EndpointCIDRs, err = application.ExposedCIDRs()
+			result.Results[i].Exposed = result.Results[i].EndpointCIDRs != nil
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -203,6 +203,40 @@ func (s *firewallerSuite) TestGetMachinePorts(c *gc.C) {
 
 }
 
+func (s *firewallerSuite) TestGetMachinePortsWithEndpoints(c *gc.C) {
+	err := s.units[0].OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].OpenPortsForEndpoint("monitoring-port", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].OpenPorts("tcp", 443, 443)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.MachinePortsParams{
+		Params: []params.MachinePorts{
+			{MachineTag: s.machines[0].Tag().String(), SubnetTag: ""},
+		},
+	}
+	unit0Tag := s.units[0].Tag().String()
+	result, err := s.firewaller.GetMachinePorts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.MachinePortsResults{
+		Results: []params.MachinePortsResult{{
+			Ports: []params.MachinePortRange{{
+				UnitTag:   unit0Tag,
+				PortRange: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+				Endpoint:  "monitoring-port",
+			}, {
+				UnitTag:   unit0Tag,
+				PortRange: params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+				Endpoint:  "url",
+			}, {
+				UnitTag:   unit0Tag,
+				PortRange: params.PortRange{FromPort: 443, ToPort: 443, Protocol: "tcp"},
+			}},
+		}},
+	})
+}
+
 func (s *firewallerSuite) TestGetMachineActiveSubnets(c *gc.C) {
 	s.openPorts(c)
 
@@ -276,3 +310,87 @@ func (s *firewallerSuite) TestAreManuallyProvisioned(c *gc.C) {
 		},
 	})
 }
+
+func (s *firewallerSuite) TestGetEgressCIDRs(c *gc.C) {
+	err := s.State.SetAPIHostPorts([]network.SpaceHostPorts{{{
+		SpaceAddress: network.NewScopedSpaceAddress("10.0.0.1", network.ScopeCloudLocal),
+		NetPort:      17070,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.application.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"192.168.0.0/16"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	apiv6 := &firewaller.FirewallerAPIV6{
+		&firewaller.FirewallerAPIV5{
+			&firewaller.FirewallerAPIV4{
+				FirewallerAPIV3:     s.firewaller,
+				ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id())),
+			}}}
+	result, err := apiv6.GetEgressCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.EgressCIDRsResults{
+		Results: []params.EgressCIDRsResult{
+			{Restricted: true, CIDRs: []string{"10.0.0.1/32", "192.168.0.0/16"}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`application "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.application.ClearEgressPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = apiv6.GetEgressCIDRs(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.EgressCIDRsResults{
+		Results: []params.EgressCIDRsResult{{}},
+	})
+}
+
+func (s *firewallerSuite) TestGetExposedCIDRs(c *gc.C) {
+	err := s.application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := addFakeEntities(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	apiv7 := &firewaller.FirewallerAPIV7{
+		&firewaller.FirewallerAPIV6{
+			&firewaller.FirewallerAPIV5{
+				&firewaller.FirewallerAPIV4{
+					FirewallerAPIV3:     s.firewaller,
+					ControllerConfigAPI: common.NewControllerConfig(newMockState(coretesting.ModelTag.Id())),
+				}}}}
+	result, err := apiv7.GetExposedCIDRs(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ExposedCIDRsResults{
+		Results: []params.ExposedCIDRsResult{
+			{Exposed: true, EndpointCIDRs: map[string][]string{"": {"10.0.0.0/8"}}},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError(`application "bar"`)},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	err = s.application.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = apiv7.GetExposedCIDRs(params.Entities{Entities: []params.Entity{
+		{Tag: s.application.Tag().String()},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.ExposedCIDRsResults{
+		Results: []params.ExposedCIDRsResult{{}},
+	})
+}
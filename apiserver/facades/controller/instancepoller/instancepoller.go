@@ -11,9 +11,11 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/common/cloudspec"
 	"github.com/juju/juju/apiserver/facade"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state"
 )
 
@@ -32,6 +34,28 @@ type InstancePollerAPI struct {
 	clock         clock.Clock
 }
 
+// InstancePollerAPIV3 implements the V3 InstancePoller API, which lacks
+// RegionCloudSpecs.
+type InstancePollerAPIV3 struct {
+	*InstancePollerAPI
+}
+
+// NewFacadeV3 creates a new instance of the V3 InstancePoller API.
+func NewFacadeV3(
+	st *state.State,
+	resources facade.Resources,
+	authorizer facade.Authorizer,
+) (*InstancePollerAPIV3, error) {
+	api, err := NewFacade(st, resources, authorizer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &InstancePollerAPIV3{api}, nil
+}
+
+// RegionCloudSpecs isn't on the V3 API.
+func (*InstancePollerAPIV3) RegionCloudSpecs(_, _ struct{}) {}
+
 // NewFacade wraps NewInstancePollerAPI for facade registration.
 func NewFacade(
 	st *state.State,
@@ -261,3 +285,29 @@ func (a *InstancePollerAPI) AreManuallyProvisioned(args params.Entities) (params
 	}
 	return result, nil
 }
+
+// RegionCloudSpecs returns the cloud spec of the cloud region that each
+// given machine was placed in, if that is not the model's own region.
+// The result for machines in the model's region is empty. Only machine
+// tags are accepted.
+func (a *InstancePollerAPI) RegionCloudSpecs(args params.Entities) (params.CloudSpecResults, error) {
+	result := params.CloudSpecResults{
+		Results: make([]params.CloudSpecResult, len(args.Entities)),
+	}
+	canAccess, err := a.accessMachine()
+	if err != nil {
+		return result, err
+	}
+	for i, arg := range args.Entities {
+		machine, err := a.getOneMachine(arg.Tag, canAccess)
+		if err == nil {
+			var spec *environs.CloudSpec
+			spec, err = a.st.RegionCloudSpec(machine.Placement())
+			if err == nil && spec != nil {
+				result.Results[i].Result = cloudspec.MakeCloudSpec(*spec)
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
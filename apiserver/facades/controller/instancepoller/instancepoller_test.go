@@ -19,6 +19,7 @@ import (
 	"github.com/juju/juju/core/life"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	coretesting "github.com/juju/juju/testing"
@@ -698,6 +699,39 @@ func (s *InstancePollerSuite) TestAreManuallyProvisionedSuccess(c *gc.C) {
 	s.st.CheckFindEntityCall(c, 4, "42")
 }
 
+func (s *InstancePollerSuite) TestRegionCloudSpecs(c *gc.C) {
+	s.st.SetMachineInfo(c, machineInfo{id: "1"})
+	s.st.SetMachineInfo(c, machineInfo{id: "2", placement: "region=other-cloud/other-region"})
+	s.st.SetRegionCloudSpec("region=other-cloud/other-region", environs.CloudSpec{
+		Type:   "dummy",
+		Name:   "other-cloud",
+		Region: "other-region",
+	})
+
+	result, err := s.api.RegionCloudSpecs(s.mixedEntities)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.CloudSpecResults{
+		Results: []params.CloudSpecResult{
+			{},
+			{Result: &params.CloudSpec{Type: "dummy", Name: "other-cloud", Region: "other-region"}},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ServerError(`"application-unknown" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"invalid-tag" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"unit-missing-1" is not a valid machine tag`)},
+			{Error: apiservertesting.ServerError(`"" is not a valid tag`)},
+			{Error: apiservertesting.ServerError(`"42" is not a valid tag`)},
+		}},
+	)
+
+	s.st.CheckFindEntityCall(c, 0, "1")
+	s.st.CheckCall(c, 1, "Placement")
+	s.st.CheckCall(c, 2, "RegionCloudSpec", "")
+	s.st.CheckFindEntityCall(c, 3, "2")
+	s.st.CheckCall(c, 4, "Placement")
+	s.st.CheckCall(c, 5, "RegionCloudSpec", "region=other-cloud/other-region")
+	s.st.CheckFindEntityCall(c, 6, "42")
+}
+
 func (s *InstancePollerSuite) TestAreManuallyProvisionedFailure(c *gc.C) {
 	s.st.SetErrors(
 		errors.New("pow!"),                   // m1 := FindEntity("1")
@@ -16,6 +16,7 @@ import (
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/state"
 )
@@ -30,14 +31,16 @@ type mockState struct {
 	configWatchers   []*mockConfigWatcher
 	machinesWatchers []*mockMachinesWatcher
 
-	config   *config.Config
-	machines map[string]*mockMachine
+	config      *config.Config
+	machines    map[string]*mockMachine
+	regionSpecs map[string]environs.CloudSpec
 }
 
 func NewMockState() *mockState {
 	return &mockState{
-		Stub:     &testing.Stub{},
-		machines: make(map[string]*mockMachine),
+		Stub:        &testing.Stub{},
+		machines:    make(map[string]*mockMachine),
+		regionSpecs: make(map[string]environs.CloudSpec),
 	}
 }
 
@@ -200,6 +203,31 @@ func (m *mockState) Machine(id string) (instancepoller.StateMachine, error) {
 	return machine, nil
 }
 
+// SetRegionCloudSpec sets the cloud spec returned for machines with the
+// given placement.
+func (m *mockState) SetRegionCloudSpec(placement string, spec environs.CloudSpec) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.regionSpecs[placement] = spec
+}
+
+// RegionCloudSpec implements StateInterface.
+func (m *mockState) RegionCloudSpec(placement string) (*environs.CloudSpec, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "RegionCloudSpec", placement)
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	spec, found := m.regionSpecs[placement]
+	if !found {
+		return nil, nil
+	}
+	return &spec, nil
+}
+
 // AllSpaceInfos implements network.AllSpaceInfos.
 // This method never throws an error.
 func (m *mockState) AllSpaceInfos() (network.SpaceInfos, error) {
@@ -219,6 +247,7 @@ type machineInfo struct {
 	providerAddresses []network.SpaceAddress
 	life              state.Life
 	isManual          bool
+	placement         string
 }
 
 type mockMachine struct {
@@ -315,6 +344,16 @@ func (m *mockMachine) IsManual() (bool, error) {
 	return m.isManual, m.NextErr()
 }
 
+// Placement implements StateMachine.
+func (m *mockMachine) Placement() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.MethodCall(m, "Placement")
+	m.NextErr() // consume the unused error
+	return m.placement
+}
+
 // Status implements StateMachine.
 func (m *mockMachine) Status() (status.StatusInfo, error) {
 	m.mu.Lock()
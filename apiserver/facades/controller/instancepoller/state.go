@@ -4,10 +4,16 @@
 package instancepoller
 
 import (
+	"github.com/juju/errors"
+	"github.com/juju/featureflag"
+
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/network"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/stateenvirons"
 )
 
 // StateMachine represents a machine from state package.
@@ -26,6 +32,7 @@ type StateMachine interface {
 	Life() state.Life
 	Status() (status.StatusInfo, error)
 	IsManual() (bool, error)
+	Placement() string
 }
 
 type StateInterface interface {
@@ -35,6 +42,11 @@ type StateInterface interface {
 	network.SpaceLookup
 
 	Machine(id string) (StateMachine, error)
+
+	// RegionCloudSpec returns the cloud spec of the cloud region named
+	// by a machine's placement directive, or nil if the machine is in
+	// the model's own region.
+	RegionCloudSpec(placement string) (*environs.CloudSpec, error)
 }
 
 // TODO - CAAS(ericclaudejones): This should contain state alone, model will be
@@ -48,6 +60,28 @@ func (s stateShim) Machine(id string) (StateMachine, error) {
 	return s.State.Machine(id)
 }
 
+func (s stateShim) RegionCloudSpec(placement string) (*environs.CloudSpec, error) {
+	if !featureflag.Enabled(feature.MultiCloud) {
+		return nil, nil
+	}
+	p, ok := instance.ParseRegionPlacement(placement)
+	if !ok {
+		return nil, nil
+	}
+	if (p.Cloud == "" || p.Cloud == s.Model.Cloud()) && p.Region == s.Model.CloudRegion() && p.Credential == "" {
+		return nil, nil
+	}
+	g, err := stateenvirons.RegionConfigGetter(s.State, s.Model, p)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spec, err := g.CloudSpec()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &spec, nil
+}
+
 var getState = func(st *state.State, m *state.Model) StateInterface {
 	return stateShim{st, m}
 }
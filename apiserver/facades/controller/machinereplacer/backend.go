@@ -0,0 +1,160 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	coreapplication "github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+// Backend defines the methods the machine replacer needs from
+// state.State.
+type Backend interface {
+	// AllMachines returns all of the machines in the model.
+	AllMachines() ([]Machine, error)
+
+	// Machine returns the machine with the given id.
+	Machine(id string) (Machine, error)
+
+	// Application returns the application with the given name.
+	Application(name string) (Application, error)
+
+	// AddOneMachine creates a new machine from the template.
+	AddOneMachine(template state.MachineTemplate) (Machine, error)
+
+	// AssignUnit assigns the named unit to the machine with the
+	// given id.
+	AssignUnit(unitName, machineId string) error
+
+	// ApplyOperation applies the model operation.
+	ApplyOperation(state.ModelOperation) error
+}
+
+// Machine defines the methods the machine replacer needs from
+// state.Machine.
+type Machine interface {
+	common.MachineStatusGetter
+
+	IsContainer() bool
+	IsManager() bool
+	IsManual() (bool, error)
+	InstanceId() (instance.Id, error)
+	InstanceStatus() (status.StatusInfo, error)
+	Series() string
+	Constraints() (constraints.Value, error)
+	Placement() string
+	Units() ([]Unit, error)
+	SetStatus(status.StatusInfo) error
+	ForceDestroy(maxWait time.Duration) error
+}
+
+// Unit defines the methods the machine replacer needs from state.Unit.
+type Unit interface {
+	Name() string
+	ApplicationName() string
+	IsPrincipal() bool
+	DestroyOperation() *state.DestroyUnitOperation
+}
+
+// Application defines the methods the machine replacer needs from
+// state.Application.
+type Application interface {
+	ApplicationConfig() (coreapplication.ConfigAttributes, error)
+	AddUnit(args state.AddUnitParams) (Unit, error)
+}
+
+type backendShim struct {
+	*state.State
+}
+
+// AllMachines implements Backend.
+func (b backendShim) AllMachines() ([]Machine, error) {
+	machines, err := b.State.AllMachines()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Machine, len(machines))
+	for i, m := range machines {
+		result[i] = machineShim{m}
+	}
+	return result, nil
+}
+
+// Machine implements Backend.
+func (b backendShim) Machine(id string) (Machine, error) {
+	m, err := b.State.Machine(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machineShim{m}, nil
+}
+
+// Application implements Backend.
+func (b backendShim) Application(name string) (Application, error) {
+	app, err := b.State.Application(name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return applicationShim{app}, nil
+}
+
+// AddOneMachine implements Backend.
+func (b backendShim) AddOneMachine(template state.MachineTemplate) (Machine, error) {
+	m, err := b.State.AddOneMachine(template)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machineShim{m}, nil
+}
+
+// AssignUnit implements Backend.
+func (b backendShim) AssignUnit(unitName, machineId string) error {
+	unit, err := b.State.Unit(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m, err := b.State.Machine(machineId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return unit.AssignToMachine(m)
+}
+
+type machineShim struct {
+	*state.Machine
+}
+
+// Units implements Machine.
+func (m machineShim) Units() ([]Unit, error) {
+	units, err := m.Machine.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]Unit, len(units))
+	for i, u := range units {
+		result[i] = u
+	}
+	return result, nil
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+// AddUnit implements Application.
+func (a applicationShim) AddUnit(args state.AddUnitParams) (Unit, error) {
+	unit, err := a.Application.AddUnit(args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return unit, nil
+}
@@ -0,0 +1,383 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/facades/client/application"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+var logger = loggo.GetLogger("juju.apiserver.machinereplacer")
+
+// replacedByKey is the machine status data key recording the machine
+// that replaced it.
+const replacedByKey = "replaced-by"
+
+// interruptedKey is the instance status data key with which the
+// provisioner marks spot instances that the cloud has reclaimed.
+const interruptedKey = "interrupted"
+
+// API implements the API facade used by the machine replacer worker.
+type API struct {
+	backend  Backend
+	presence common.ModelPresenceContext
+}
+
+// NewAPI returns a new machine replacer API facade.
+func NewAPI(backend Backend, presence common.ModelPresence, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:  backend,
+		presence: common.ModelPresenceContext{Presence: presence},
+	}, nil
+}
+
+// NewFacade provides the signature required for facade registration.
+func NewFacade(ctx facade.Context) (*API, error) {
+	st := ctx.State()
+	var presence common.ModelPresence
+	if p := ctx.Presence(); p != nil {
+		presence = p.ModelPresence(st.ModelUUID())
+	}
+	return NewAPI(backendShim{st}, presence, ctx.Auth())
+}
+
+// DownMachines returns the machines whose agents are down and whose
+// instances are not running, and which host units of applications that
+// have opted into machine replacement. Machines whose spot instances
+// have been interrupted by the cloud are returned too, with no delay,
+// if they host any principal units.
+func (api *API) DownMachines() (params.DownMachinesResult, error) {
+	var result params.DownMachinesResult
+	machines, err := api.backend.AllMachines()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	delays := make(map[string]time.Duration)
+	for _, m := range machines {
+		interrupted, err := api.isInterrupted(m)
+		if err != nil {
+			return result, errors.Annotatef(err, "checking machine %s", m.Id())
+		} else if interrupted {
+			units, err := principalUnits(m)
+			if err != nil {
+				return result, errors.Annotatef(err, "checking units of machine %s", m.Id())
+			} else if len(units) > 0 {
+				result.Machines = append(result.Machines, params.DownMachine{
+					Tag: names.NewMachineTag(m.Id()).String(),
+				})
+			}
+			continue
+		}
+		down, err := api.isDown(m)
+		if err != nil {
+			return result, errors.Annotatef(err, "checking machine %s", m.Id())
+		} else if !down {
+			continue
+		}
+		_, delay, _, err := api.replaceableUnits(m, delays)
+		if err != nil {
+			return result, errors.Annotatef(err, "checking units of machine %s", m.Id())
+		} else if delay == 0 {
+			continue
+		}
+		result.Machines = append(result.Machines, params.DownMachine{
+			Tag:          names.NewMachineTag(m.Id()).String(),
+			ReplaceAfter: delay,
+		})
+	}
+	return result, nil
+}
+
+// ReplaceMachines provisions a replacement for each of the given down
+// machines, with the same series, constraints and placement, and moves
+// the units of applications that have opted into machine replacement
+// onto it. All of the principal units of a machine whose spot instance
+// was interrupted are moved, as the instance is gone. The tag of each
+// replacement machine is returned.
+func (api *API) ReplaceMachines(args params.Entities) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		replacementId, err := api.replaceMachine(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Result = names.NewMachineTag(replacementId).String()
+	}
+	return result, nil
+}
+
+func (api *API) replaceMachine(id string) (string, error) {
+	m, err := api.backend.Machine(id)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	units, allUnits, err := api.unitsToReplace(m)
+	if err != nil {
+		return "", errors.Trace(err)
+	} else if len(units) == 0 {
+		return "", errors.Errorf("machine %s has no units to replace", id)
+	}
+
+	cons, err := m.Constraints()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	replacement, err := api.backend.AddOneMachine(state.MachineTemplate{
+		Series:      m.Series(),
+		Constraints: cons,
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Placement:   m.Placement(),
+	})
+	if err != nil {
+		return "", errors.Annotate(err, "adding replacement machine")
+	}
+	logger.Infof("replacing down machine %s with machine %s", id, replacement.Id())
+
+	// If the units cannot be moved, the replacement and any units added
+	// to it are removed, so that nothing is left behind and the down
+	// machine can be replaced again at the next attempt.
+	var newUnits []Unit
+	rollback := func(cause error) (string, error) {
+		api.removeReplacement(replacement, newUnits)
+		return "", cause
+	}
+	if err := replacement.SetStatus(status.StatusInfo{
+		Status:  status.Pending,
+		Message: fmt.Sprintf("replacing machine %s", id),
+	}); err != nil {
+		return rollback(errors.Trace(err))
+	}
+	for _, unit := range units {
+		app, err := api.backend.Application(unit.ApplicationName())
+		if err != nil {
+			return rollback(errors.Trace(err))
+		}
+		newUnit, err := app.AddUnit(state.AddUnitParams{})
+		if err != nil {
+			return rollback(errors.Trace(err))
+		}
+		newUnits = append(newUnits, newUnit)
+		if err := api.backend.AssignUnit(newUnit.Name(), replacement.Id()); err != nil {
+			return rollback(errors.Trace(err))
+		}
+		logger.Infof("replacing unit %s with %s", unit.Name(), newUnit.Name())
+	}
+
+	// Record the replacement in the down machine's status history. This
+	// also stops the down machine being replaced again.
+	if err := m.SetStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: fmt.Sprintf("replaced by machine %s", replacement.Id()),
+		Data:    map[string]interface{}{replacedByKey: replacement.Id()},
+	}); err != nil {
+		return rollback(errors.Trace(err))
+	}
+
+	// The down machine's agent cannot clean up after its units, so they
+	// are removed by force. If nothing else is left on the machine, it
+	// is removed too.
+	if allUnits {
+		return replacement.Id(), errors.Trace(m.ForceDestroy(common.MaxWait(nil)))
+	}
+	for _, unit := range units {
+		op := unit.DestroyOperation()
+		op.Force = true
+		op.MaxWait = common.MaxWait(nil)
+		if err := api.backend.ApplyOperation(op); err != nil {
+			return "", errors.Annotatef(err, "removing unit %s", unit.Name())
+		}
+	}
+	return replacement.Id(), nil
+}
+
+// unitsToReplace returns the units to move off the machine, and whether
+// they are all of its principal units. If the machine's spot instance
+// was interrupted, all of its principal units are moved; otherwise the
+// machine must be down, and only the units of applications that have
+// opted into machine replacement are moved.
+func (api *API) unitsToReplace(m Machine) ([]Unit, bool, error) {
+	interrupted, err := api.isInterrupted(m)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	} else if interrupted {
+		units, err := principalUnits(m)
+		return units, true, errors.Trace(err)
+	}
+	down, err := api.isDown(m)
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	} else if !down {
+		return nil, false, errors.Errorf("machine %s is not down", m.Id())
+	}
+	units, _, allUnits, err := api.replaceableUnits(m, make(map[string]time.Duration))
+	return units, allUnits, errors.Trace(err)
+}
+
+// removeReplacement removes, by force, a replacement machine and the
+// units added to it. Failures are logged rather than returned, so that
+// the error that caused the removal is reported.
+func (api *API) removeReplacement(replacement Machine, units []Unit) {
+	for _, unit := range units {
+		op := unit.DestroyOperation()
+		op.Force = true
+		op.MaxWait = common.MaxWait(nil)
+		if err := api.backend.ApplyOperation(op); err != nil {
+			logger.Errorf("cannot remove unit %s: %v", unit.Name(), err)
+		}
+	}
+	if err := replacement.ForceDestroy(common.MaxWait(nil)); err != nil {
+		logger.Errorf("cannot remove replacement machine %s: %v", replacement.Id(), err)
+	}
+}
+
+// isInterrupted reports whether the machine is an alive, top level
+// workload machine whose spot instance the provisioner has found to be
+// interrupted by the cloud, and which has not already been replaced.
+func (api *API) isInterrupted(m Machine) (bool, error) {
+	if m.Life() != state.Alive || m.IsContainer() || m.IsManager() {
+		return false, nil
+	}
+	instanceStatus, err := m.InstanceStatus()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if interrupted, _ := instanceStatus.Data[interruptedKey].(bool); !interrupted {
+		return false, nil
+	}
+	machineStatus, err := m.Status()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	_, replaced := machineStatus.Data[replacedByKey]
+	return !replaced, nil
+}
+
+// isDown reports whether the machine is a provisioned, alive, top level
+// workload machine whose agent is down and whose instance is not
+// running, and which has not already been replaced.
+func (api *API) isDown(m Machine) (bool, error) {
+	if m.Life() != state.Alive || m.IsContainer() || m.IsManager() {
+		return false, nil
+	}
+	if manual, err := m.IsManual(); err != nil {
+		return false, errors.Trace(err)
+	} else if manual {
+		// There is no instance to replace a manual machine with.
+		return false, nil
+	}
+	if _, err := m.InstanceId(); errors.IsNotProvisioned(err) {
+		return false, nil
+	} else if err != nil {
+		return false, errors.Trace(err)
+	}
+
+	machineStatus, err := api.presence.MachineStatus(m)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	if machineStatus.Status != status.Down {
+		return false, nil
+	}
+	if _, ok := machineStatus.Data[replacedByKey]; ok {
+		return false, nil
+	}
+	// A running instance with a down agent needs a human to look at it.
+	instanceStatus, err := m.InstanceStatus()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return instanceStatus.Status != status.Running, nil
+}
+
+// replaceableUnits returns the principal units on the machine whose
+// applications have opted into machine replacement, the shortest
+// replacement delay of those applications, and whether they are all of
+// the machine's principal units. Delays are cached by application name.
+func (api *API) replaceableUnits(m Machine, delays map[string]time.Duration) ([]Unit, time.Duration, bool, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, 0, false, errors.Trace(err)
+	}
+	var (
+		replaceable []Unit
+		minDelay    time.Duration
+		principals  int
+	)
+	for _, unit := range units {
+		if !unit.IsPrincipal() {
+			continue
+		}
+		principals++
+		delay, err := api.applicationDelay(unit.ApplicationName(), delays)
+		if err != nil {
+			return nil, 0, false, errors.Trace(err)
+		} else if delay == 0 {
+			continue
+		}
+		replaceable = append(replaceable, unit)
+		if minDelay == 0 || delay < minDelay {
+			minDelay = delay
+		}
+	}
+	return replaceable, minDelay, len(replaceable) == principals, nil
+}
+
+// principalUnits returns the principal units on the machine.
+func principalUnits(m Machine) ([]Unit, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var principals []Unit
+	for _, unit := range units {
+		if unit.IsPrincipal() {
+			principals = append(principals, unit)
+		}
+	}
+	return principals, nil
+}
+
+func (api *API) applicationDelay(name string, delays map[string]time.Duration) (time.Duration, error) {
+	if delay, ok := delays[name]; ok {
+		return delay, nil
+	}
+	app, err := api.backend.Application(name)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	config, err := app.ApplicationConfig()
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	delay, err := application.MachineReplacementDelay(config)
+	if err != nil {
+		// Invalid values are rejected when set, so this shouldn't
+		// happen; don't replace anything for the application.
+		logger.Warningf("application %q: %v", name, err)
+	}
+	delays[name] = delay
+	return delay, nil
+}
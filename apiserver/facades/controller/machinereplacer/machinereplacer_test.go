@@ -0,0 +1,270 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/facades/controller/machinereplacer"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	coreapplication "github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+type machineReplacerSuite struct {
+	testing.IsolationSuite
+
+	backend *mockBackend
+	api     *machinereplacer.API
+}
+
+var _ = gc.Suite(&machineReplacerSuite{})
+
+func (s *machineReplacerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &mockBackend{
+		applications: map[string]*mockApplication{
+			"mysql": {
+				config:  coreapplication.ConfigAttributes{"replace-machines-after": "10m"},
+				newUnit: "mysql/1",
+			},
+			"wordpress": {
+				config:  coreapplication.ConfigAttributes{"replace-machines-after": "5m"},
+				newUnit: "wordpress/1",
+			},
+			"haproxy": {
+				config:  coreapplication.ConfigAttributes{},
+				newUnit: "haproxy/1",
+			},
+		},
+		added: &mockMachine{id: "7"},
+	}
+	api, err := machinereplacer.NewAPI(s.backend, nil, apiservertesting.FakeAuthorizer{Controller: true})
+	c.Assert(err, jc.ErrorIsNil)
+	s.api = api
+}
+
+func (s *machineReplacerSuite) TestRequiresController(c *gc.C) {
+	_, err := machinereplacer.NewAPI(s.backend, nil, apiservertesting.FakeAuthorizer{Controller: false})
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *machineReplacerSuite) TestDownMachines(c *gc.C) {
+	manager := newDownMachine("0", &mockUnit{name: "mysql/0", application: "mysql"})
+	manager.manager = true
+	both := newDownMachine("1",
+		&mockUnit{name: "mysql/1", application: "mysql"},
+		&mockUnit{name: "wordpress/0", application: "wordpress"},
+	)
+	running := newDownMachine("2", &mockUnit{name: "mysql/2", application: "mysql"})
+	running.instanceStatus = status.Running
+	alive := newDownMachine("3", &mockUnit{name: "mysql/3", application: "mysql"})
+	alive.alive = true
+	optedOut := newDownMachine("4", &mockUnit{name: "haproxy/0", application: "haproxy"})
+	replaced := newDownMachine("5", &mockUnit{name: "mysql/4", application: "mysql"})
+	replaced.status = status.StatusInfo{
+		Status: status.Error,
+		Data:   map[string]interface{}{"replaced-by": "6"},
+	}
+	unprovisioned := newDownMachine("6", &mockUnit{name: "mysql/5", application: "mysql"})
+	unprovisioned.provisioned = false
+	mysql := newDownMachine("8", &mockUnit{name: "mysql/6", application: "mysql"})
+	s.backend.machines = []*mockMachine{manager, both, running, alive, optedOut, replaced, unprovisioned, mysql}
+
+	result, err := s.api.DownMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DownMachinesResult{
+		Machines: []params.DownMachine{
+			{Tag: "machine-1", ReplaceAfter: 5 * time.Minute},
+			{Tag: "machine-8", ReplaceAfter: 10 * time.Minute},
+		},
+	})
+}
+
+func (s *machineReplacerSuite) TestDownMachinesInterrupted(c *gc.C) {
+	// Interrupted machines are replaced straight away, even if the
+	// agent is still up and the applications haven't opted in.
+	interrupted := newDownMachine("1", &mockUnit{name: "haproxy/0", application: "haproxy"})
+	interrupted.alive = true
+	interrupted.interrupted = true
+	interrupted.instanceStatus = status.ProvisioningError
+	empty := newDownMachine("2")
+	empty.interrupted = true
+	replaced := newDownMachine("3", &mockUnit{name: "haproxy/1", application: "haproxy"})
+	replaced.interrupted = true
+	replaced.status = status.StatusInfo{
+		Status: status.Error,
+		Data:   map[string]interface{}{"replaced-by": "4"},
+	}
+	s.backend.machines = []*mockMachine{interrupted, empty, replaced}
+
+	result, err := s.api.DownMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.DownMachinesResult{
+		Machines: []params.DownMachine{{Tag: "machine-1"}},
+	})
+}
+
+func (s *machineReplacerSuite) TestReplaceInterruptedMachine(c *gc.C) {
+	interrupted := newDownMachine("1",
+		&mockUnit{name: "mysql/0", application: "mysql"},
+		&mockUnit{name: "haproxy/0", application: "haproxy"},
+		&mockUnit{name: "logging/0", application: "logging", subordinate: true},
+	)
+	interrupted.alive = true
+	interrupted.interrupted = true
+	interrupted.instanceStatus = status.ProvisioningError
+	s.backend.machines = []*mockMachine{interrupted}
+
+	result, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{{Result: "machine-7"}},
+	})
+
+	// All of the principal units are moved onto the new machine,
+	// whether or not their applications opted into replacement.
+	s.backend.CheckCallNames(c,
+		"Machine", "AddOneMachine",
+		"Application", "AssignUnit", "Application", "AssignUnit",
+	)
+	s.backend.CheckCall(c, 3, "AssignUnit", "mysql/1", "7")
+	s.backend.CheckCall(c, 5, "AssignUnit", "haproxy/1", "7")
+	interrupted.CheckCalls(c, []testing.StubCall{
+		{"SetStatus", []interface{}{status.StatusInfo{
+			Status:  status.Error,
+			Message: "replaced by machine 7",
+			Data:    map[string]interface{}{"replaced-by": "7"},
+		}}},
+		{"ForceDestroy", []interface{}{time.Minute}},
+	})
+}
+
+func (s *machineReplacerSuite) TestReplaceMachines(c *gc.C) {
+	down := newDownMachine("1",
+		&mockUnit{name: "mysql/0", application: "mysql"},
+		&mockUnit{name: "logging/0", application: "logging", subordinate: true},
+	)
+	s.backend.machines = []*mockMachine{down}
+
+	result, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.StringResults{
+		Results: []params.StringResult{{Result: "machine-7"}},
+	})
+
+	s.backend.CheckCall(c, 2, "AddOneMachine", state.MachineTemplate{
+		Series:      "bionic",
+		Constraints: constraints.MustParse("mem=4G"),
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Placement:   "zone=a",
+	})
+	s.backend.CheckCall(c, 4, "AssignUnit", "mysql/1", "7")
+	s.backend.applications["mysql"].CheckCall(c, 0, "AddUnit", state.AddUnitParams{})
+	down.CheckCalls(c, []testing.StubCall{
+		{"SetStatus", []interface{}{status.StatusInfo{
+			Status:  status.Error,
+			Message: "replaced by machine 7",
+			Data:    map[string]interface{}{"replaced-by": "7"},
+		}}},
+		{"ForceDestroy", []interface{}{time.Minute}},
+	})
+	s.backend.added.CheckCalls(c, []testing.StubCall{
+		{"SetStatus", []interface{}{status.StatusInfo{
+			Status:  status.Pending,
+			Message: "replacing machine 1",
+		}}},
+	})
+}
+
+func (s *machineReplacerSuite) TestReplaceMachinesKeepsOtherUnits(c *gc.C) {
+	down := newDownMachine("1",
+		&mockUnit{name: "mysql/0", application: "mysql"},
+		&mockUnit{name: "haproxy/0", application: "haproxy"},
+	)
+	s.backend.machines = []*mockMachine{down}
+
+	result, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.IsNil)
+
+	s.backend.CheckCallNames(c,
+		"Machine", "Application", "Application", "AddOneMachine",
+		"Application", "AssignUnit", "ApplyOperation",
+	)
+	op := s.backend.Calls()[6].Args[0].(*state.DestroyUnitOperation)
+	c.Assert(op.Force, jc.IsTrue)
+	down.CheckCallNames(c, "SetStatus")
+}
+
+func (s *machineReplacerSuite) TestReplaceMachinesAddUnitFailsRemovesReplacement(c *gc.C) {
+	down := newDownMachine("1", &mockUnit{name: "mysql/0", application: "mysql"})
+	s.backend.machines = []*mockMachine{down}
+	s.backend.applications["mysql"].SetErrors(errors.New("boom"))
+
+	result, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "boom")
+
+	s.backend.CheckCallNames(c, "Machine", "Application", "AddOneMachine", "Application")
+	s.backend.added.CheckCallNames(c, "SetStatus", "ForceDestroy")
+	// The down machine is left to be replaced again.
+	down.CheckNoCalls(c)
+}
+
+func (s *machineReplacerSuite) TestReplaceMachinesAssignUnitFailsRemovesReplacement(c *gc.C) {
+	down := newDownMachine("1", &mockUnit{name: "mysql/0", application: "mysql"})
+	s.backend.machines = []*mockMachine{down}
+	s.backend.SetErrors(nil, nil, nil, nil, errors.New("boom"))
+
+	result, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "boom")
+
+	s.backend.CheckCallNames(c,
+		"Machine", "Application", "AddOneMachine",
+		"Application", "AssignUnit", "ApplyOperation",
+	)
+	op := s.backend.Calls()[5].Args[0].(*state.DestroyUnitOperation)
+	c.Assert(op.Force, jc.IsTrue)
+	s.backend.added.CheckCallNames(c, "SetStatus", "ForceDestroy")
+	down.CheckNoCalls(c)
+}
+
+func (s *machineReplacerSuite) TestReplaceMachinesNotDown(c *gc.C) {
+	running := newDownMachine("1", &mockUnit{name: "mysql/0", application: "mysql"})
+	running.instanceStatus = status.Running
+	optedOut := newDownMachine("2", &mockUnit{name: "haproxy/0", application: "haproxy"})
+	s.backend.machines = []*mockMachine{running, optedOut}
+
+	result, err := s.api.ReplaceMachines(params.Entities{
+		Entities: []params.Entity{{Tag: "machine-1"}, {Tag: "machine-2"}, {Tag: "unit-mysql-0"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Results, gc.HasLen, 3)
+	c.Assert(result.Results[0].Error, gc.ErrorMatches, "machine 1 is not down")
+	c.Assert(result.Results[1].Error, gc.ErrorMatches, "machine 2 has no units to replace")
+	c.Assert(result.Results[2].Error, gc.ErrorMatches, `"unit-mysql-0" is not a valid machine tag`)
+	for _, call := range s.backend.Calls() {
+		c.Assert(call.FuncName, gc.Not(gc.Equals), "AddOneMachine")
+	}
+}
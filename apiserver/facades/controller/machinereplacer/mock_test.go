@@ -0,0 +1,218 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+
+	"github.com/juju/juju/apiserver/facades/controller/machinereplacer"
+	coreapplication "github.com/juju/juju/core/application"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/state"
+)
+
+type mockBackend struct {
+	testing.Stub
+
+	machines     []*mockMachine
+	applications map[string]*mockApplication
+	added        *mockMachine
+}
+
+func (b *mockBackend) AllMachines() ([]machinereplacer.Machine, error) {
+	b.AddCall("AllMachines")
+	result := make([]machinereplacer.Machine, len(b.machines))
+	for i, m := range b.machines {
+		result[i] = m
+	}
+	return result, b.NextErr()
+}
+
+func (b *mockBackend) Machine(id string) (machinereplacer.Machine, error) {
+	b.AddCall("Machine", id)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	for _, m := range b.machines {
+		if m.id == id {
+			return m, nil
+		}
+	}
+	return nil, errors.NotFoundf("machine %s", id)
+}
+
+func (b *mockBackend) Application(name string) (machinereplacer.Application, error) {
+	b.AddCall("Application", name)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	app, ok := b.applications[name]
+	if !ok {
+		return nil, errors.NotFoundf("application %q", name)
+	}
+	return app, nil
+}
+
+func (b *mockBackend) AddOneMachine(template state.MachineTemplate) (machinereplacer.Machine, error) {
+	b.AddCall("AddOneMachine", template)
+	if err := b.NextErr(); err != nil {
+		return nil, err
+	}
+	return b.added, nil
+}
+
+func (b *mockBackend) AssignUnit(unitName, machineId string) error {
+	b.AddCall("AssignUnit", unitName, machineId)
+	return b.NextErr()
+}
+
+func (b *mockBackend) ApplyOperation(op state.ModelOperation) error {
+	b.AddCall("ApplyOperation", op)
+	return b.NextErr()
+}
+
+type mockMachine struct {
+	testing.Stub
+
+	id             string
+	life           state.Life
+	manager        bool
+	manual         bool
+	provisioned    bool
+	alive          bool
+	status         status.StatusInfo
+	instanceStatus status.Status
+	interrupted    bool
+	units          []*mockUnit
+}
+
+func newDownMachine(id string, units ...*mockUnit) *mockMachine {
+	return &mockMachine{
+		id:             id,
+		life:           state.Alive,
+		provisioned:    true,
+		status:         status.StatusInfo{Status: status.Started},
+		instanceStatus: status.Unknown,
+		units:          units,
+	}
+}
+
+func (m *mockMachine) Id() string {
+	return m.id
+}
+
+func (m *mockMachine) Life() state.Life {
+	return m.life
+}
+
+func (m *mockMachine) Status() (status.StatusInfo, error) {
+	return m.status, nil
+}
+
+func (m *mockMachine) AgentPresence() (bool, error) {
+	return m.alive, nil
+}
+
+func (m *mockMachine) IsContainer() bool {
+	return false
+}
+
+func (m *mockMachine) IsManager() bool {
+	return m.manager
+}
+
+func (m *mockMachine) IsManual() (bool, error) {
+	return m.manual, nil
+}
+
+func (m *mockMachine) InstanceId() (instance.Id, error) {
+	if !m.provisioned {
+		return "", errors.NotProvisionedf("machine %s", m.id)
+	}
+	return instance.Id("inst-" + m.id), nil
+}
+
+func (m *mockMachine) InstanceStatus() (status.StatusInfo, error) {
+	info := status.StatusInfo{Status: m.instanceStatus}
+	if m.interrupted {
+		info.Data = map[string]interface{}{"interrupted": true}
+	}
+	return info, nil
+}
+
+func (m *mockMachine) Series() string {
+	return "bionic"
+}
+
+func (m *mockMachine) Constraints() (constraints.Value, error) {
+	return constraints.MustParse("mem=4G"), nil
+}
+
+func (m *mockMachine) Placement() string {
+	return "zone=a"
+}
+
+func (m *mockMachine) Units() ([]machinereplacer.Unit, error) {
+	result := make([]machinereplacer.Unit, len(m.units))
+	for i, u := range m.units {
+		result[i] = u
+	}
+	return result, nil
+}
+
+func (m *mockMachine) SetStatus(info status.StatusInfo) error {
+	m.AddCall("SetStatus", info)
+	return m.NextErr()
+}
+
+func (m *mockMachine) ForceDestroy(maxWait time.Duration) error {
+	m.AddCall("ForceDestroy", maxWait)
+	return m.NextErr()
+}
+
+type mockUnit struct {
+	name        string
+	application string
+	subordinate bool
+}
+
+func (u *mockUnit) Name() string {
+	return u.name
+}
+
+func (u *mockUnit) ApplicationName() string {
+	return u.application
+}
+
+func (u *mockUnit) IsPrincipal() bool {
+	return !u.subordinate
+}
+
+func (u *mockUnit) DestroyOperation() *state.DestroyUnitOperation {
+	return &state.DestroyUnitOperation{}
+}
+
+type mockApplication struct {
+	testing.Stub
+
+	config  coreapplication.ConfigAttributes
+	newUnit string
+}
+
+func (a *mockApplication) ApplicationConfig() (coreapplication.ConfigAttributes, error) {
+	return a.config, nil
+}
+
+func (a *mockApplication) AddUnit(args state.AddUnitParams) (machinereplacer.Unit, error) {
+	a.AddCall("AddUnit", args)
+	if err := a.NextErr(); err != nil {
+		return nil, err
+	}
+	return &mockUnit{name: a.newUnit}, nil
+}
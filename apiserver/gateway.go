@@ -0,0 +1,244 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/juju/errors"
+	"github.com/juju/rpcreflect"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/httpcontext"
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/stateauthenticator"
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/rpc"
+)
+
+// gatewayRequestVersion is the RPC wire format version recorded for
+// requests made through the gateway.
+const gatewayRequestVersion = 1
+
+// gatewayHandler serves facade method calls as plain HTTP POST requests
+// with JSON bodies, for clients that can't easily use the websocket RPC
+// protocol. Each request is authenticated and authorized exactly as a
+// login followed by a single call on a websocket connection would be.
+//
+// The gateway is only available when the "http-gateway" controller
+// feature is enabled.
+type gatewayHandler struct {
+	srv *Server
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *gatewayHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !h.srv.shared.featureEnabled(feature.HTTPGateway) {
+		http.NotFound(w, req)
+		return
+	}
+	result, err := h.serveCall(req)
+	if err != nil {
+		if err := sendError(w, err); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	if err := sendStatusAndJSON(w, http.StatusOK, result); err != nil {
+		logger.Errorf("%v", err)
+	}
+}
+
+// serveCall authenticates the request, and then calls the facade method
+// identified by the request path, returning its result.
+func (h *gatewayHandler) serveCall(req *http.Request) (interface{}, error) {
+	query := req.URL.Query()
+	facadeName := query.Get(":facade")
+	methodName := query.Get(":method")
+	version, err := strconv.Atoi(query.Get(":version"))
+	if err != nil {
+		return nil, errors.NotValidf("facade version %q", query.Get(":version"))
+	}
+	if !gatewayFacade(facadeName) {
+		return nil, errors.NotSupportedf("calling %s facade through the HTTP gateway", facadeName)
+	}
+
+	loginRequest, err := gatewayLoginRequest(req)
+	if err != nil {
+		return nil, errors.NewUnauthorized(err, "")
+	}
+
+	srv := h.srv
+	connectionID := atomic.AddUint64(&srv.lastConnectionID, 1)
+	apiObserver := srv.newObserver()
+	apiObserver.Join(req, connectionID)
+	defer apiObserver.Leave()
+
+	// An empty model UUID signifies a controller-only login,
+	// as for websocket connections to the /api endpoint.
+	modelUUID := httpcontext.RequestModelUUID(req)
+	resolvedModelUUID := modelUUID
+	if modelUUID == "" {
+		resolvedModelUUID = srv.shared.statePool.SystemState().ModelUUID()
+	}
+	st, err := srv.shared.statePool.Get(resolvedModelUUID)
+	if errors.IsNotFound(err) {
+		return nil, errors.Wrap(err, common.UnknownModelError(resolvedModelUUID))
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer st.Release()
+
+	root, err := newAPIHandler(srv, st.State, nil, modelUUID, connectionID, req.Host)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Any resources created by the call, such as watchers,
+	// are only valid for the lifetime of the request.
+	defer root.Kill()
+	if root.model == nil {
+		return nil, errors.Trace(common.UnknownModelError(resolvedModelUUID))
+	}
+
+	a := &admin{
+		srv:         srv,
+		root:        root,
+		apiObserver: apiObserver,
+	}
+	authResult, err := a.authenticate(loginRequest)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !authResult.userLogin {
+		// The gateway is for users and automation acting
+		// on their behalf; agents use the websocket API.
+		return nil, errors.Trace(common.ErrPerm)
+	}
+	apiRoot, err := a.authenticatedAPIRoot(authResult)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	auditConfig := srv.GetAuditConfig()
+	auditRecorder, err := a.getAuditRecorder(loginRequest, authResult, auditConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	recorder := observer.NewRecorderFactory(
+		apiObserver, auditRecorder, auditConfig.CaptureAPIArgs,
+	)()
+
+	hdr := &rpc.Header{
+		RequestId: 1,
+		Request: rpc.Request{
+			Type:    facadeName,
+			Version: version,
+			Id:      query.Get("id"),
+			Action:  methodName,
+		},
+		Version: gatewayRequestVersion,
+	}
+	// Continue the client's trace, if it sent one, as
+	// websocket clients do with each request.
+	ctx := trace.ContextWithTraceParent(req.Context(), req.Header.Get("traceparent"))
+	result, err := callGatewayMethod(ctx, req, apiRoot, hdr, recorder)
+	if err != nil {
+		serverErr := common.ServerError(err)
+		replyHdr := &rpc.Header{
+			RequestId: hdr.RequestId,
+			Error:     serverErr.Message,
+			ErrorCode: serverErr.Code,
+			ErrorInfo: serverErr.Info,
+			Version:   gatewayRequestVersion,
+		}
+		if err := recorder.HandleReply(hdr.Request, replyHdr, struct{}{}); err != nil {
+			logger.Errorf("error recording reply %+v: %v", replyHdr, err)
+		}
+		return nil, err
+	}
+	replyHdr := &rpc.Header{
+		RequestId: hdr.RequestId,
+		Version:   gatewayRequestVersion,
+	}
+	if err := recorder.HandleReply(hdr.Request, replyHdr, result); err != nil {
+		logger.Errorf("error recording reply %+v: %v", replyHdr, err)
+	}
+	return result, nil
+}
+
+// callGatewayMethod decodes the request body as the parameters of the
+// method identified by hdr, and calls it with the given context.
+func callGatewayMethod(ctx context.Context, req *http.Request, root rpc.Root, hdr *rpc.Header, recorder rpc.Recorder) (interface{}, error) {
+	caller, err := root.FindMethod(hdr.Request.Type, hdr.Request.Version, hdr.Request.Action)
+	if err != nil {
+		if err := recorder.HandleRequest(hdr, nil); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, ok := errors.Cause(err).(*rpcreflect.CallNotImplementedError); ok {
+			return nil, errors.NewNotFound(err, "")
+		}
+		return nil, err
+	}
+	var arg reflect.Value
+	var body interface{} = struct{}{}
+	if paramsType := caller.ParamsType(); paramsType != nil {
+		v := reflect.New(paramsType)
+		if err := json.NewDecoder(req.Body).Decode(v.Interface()); err != nil && err != io.EOF {
+			if err := recorder.HandleRequest(hdr, nil); err != nil {
+				return nil, errors.Trace(err)
+			}
+			return nil, errors.NewBadRequest(err, "cannot decode request parameters")
+		}
+		arg = v.Elem()
+		body = arg.Interface()
+	}
+	if err := recorder.HandleRequest(hdr, body); err != nil {
+		return nil, errors.Trace(err)
+	}
+	rv, err := caller.Call(ctx, hdr.Request.Id, arg)
+	if err != nil {
+		return nil, err
+	}
+	if !rv.IsValid() {
+		return struct{}{}, nil
+	}
+	return rv.Interface(), nil
+}
+
+// gatewayFacade reports whether the named facade may be called through
+// the gateway. Facades that only make sense on a long-lived connection,
+// such as watchers and the pinger, are excluded.
+func gatewayFacade(facadeName string) bool {
+	return facadeName != "Pinger" && !strings.HasSuffix(facadeName, "Watcher")
+}
+
+// gatewayLoginRequest extracts the login details from a gateway request.
+// In addition to the basic auth and macaroon credentials accepted by the
+// other HTTP endpoints, API tokens may be presented as bearer tokens.
+func gatewayLoginRequest(req *http.Request) (params.LoginRequest, error) {
+	parts := strings.Fields(req.Header.Get("Authorization"))
+	if len(parts) == 2 && parts[0] == "Bearer" {
+		return params.LoginRequest{Token: parts[1]}, nil
+	}
+	loginRequest, err := stateauthenticator.LoginRequest(req)
+	if err != nil {
+		return params.LoginRequest{}, errors.Trace(err)
+	}
+	if loginRequest.AuthTag != "" {
+		if kind, _ := names.TagKind(loginRequest.AuthTag); kind != names.UserTagKind {
+			return params.LoginRequest{}, errors.NotValidf("%s login through the HTTP gateway", kind)
+		}
+	}
+	return loginRequest, nil
+}
@@ -0,0 +1,252 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	corecontroller "github.com/juju/juju/controller"
+	"github.com/juju/juju/feature"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/version"
+)
+
+type gatewaySuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&gatewaySuite{})
+
+func (s *gatewaySuite) SetUpTest(c *gc.C) {
+	s.ControllerConfig = map[string]interface{}{
+		corecontroller.Features: []interface{}{feature.HTTPGateway},
+	}
+	s.apiserverBaseSuite.SetUpTest(c)
+}
+
+func (s *gatewaySuite) modelGatewayURI(path string) string {
+	return s.URL(fmt.Sprintf("/model/%s/gateway/%s", s.State.ModelUUID(), path), nil).String()
+}
+
+func (s *gatewaySuite) controllerGatewayURI(path string) string {
+	return s.URL("/gateway/"+path, nil).String()
+}
+
+func (s *gatewaySuite) assertError(c *gc.C, resp *http.Response, expStatus int, expCode, expError string) {
+	body := apitesting.AssertResponse(c, resp, expStatus, params.ContentTypeJSON)
+	var result params.ErrorResult
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil, gc.Commentf("body: %s", body))
+	c.Assert(result.Error, gc.NotNil)
+	c.Check(result.Error.Code, gc.Equals, expCode)
+	c.Check(result.Error.Message, gc.Matches, expError)
+}
+
+func (s *gatewaySuite) TestModelFacadeCall(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "POST",
+		URL:      s.modelGatewayURI("Client/2/FullStatus"),
+		JSONBody: params.StatusParams{},
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var status params.FullStatus
+	err := json.Unmarshal(body, &status)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(status.Model.Name, gc.Equals, s.Model.Name())
+}
+
+func (s *gatewaySuite) TestControllerFacadeCall(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.controllerGatewayURI("UserManager/3/UserInfo"),
+		JSONBody: params.UserInfoRequest{
+			Entities: []params.Entity{{Tag: s.Owner.String()}},
+		},
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+	var results params.UserInfoResults
+	err := json.Unmarshal(body, &results)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Result.Username, gc.Equals, s.Owner.Name())
+}
+
+func (s *gatewaySuite) TestModelFacadeNotAvailableOnController(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.controllerGatewayURI("Client/2/FullStatus"),
+	})
+	s.assertError(c, resp, http.StatusInternalServerError, params.CodeNotSupported,
+		`facade "Client" not supported for controller API connection`)
+}
+
+func (s *gatewaySuite) TestUnknownMethod(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelGatewayURI("Client/2/NoSuchMethod"),
+	})
+	s.assertError(c, resp, http.StatusNotFound, params.CodeNotFound, `no such request - method Client\(2\).NoSuchMethod is not implemented`)
+}
+
+func (s *gatewaySuite) TestBadParams(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelGatewayURI("Client/2/FullStatus"),
+		JSONBody: map[string]interface{}{
+			"patterns": "not a list",
+		},
+	})
+	s.assertError(c, resp, http.StatusBadRequest, params.CodeBadRequest, `cannot decode request parameters: .*`)
+}
+
+func (s *gatewaySuite) TestWatchersNotSupported(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelGatewayURI("AllWatcher/1/Next"),
+	})
+	s.assertError(c, resp, http.StatusInternalServerError, params.CodeNotSupported,
+		`calling AllWatcher facade through the HTTP gateway not supported`)
+}
+
+func (s *gatewaySuite) TestRequiresAuth(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelGatewayURI("Client/2/FullStatus"),
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, params.CodeUnauthorized, `.*no credentials provided`)
+}
+
+func (s *gatewaySuite) TestBadPassword(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "POST",
+		URL:      s.modelGatewayURI("Client/2/FullStatus"),
+		Tag:      s.Owner.String(),
+		Password: "wrong",
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, params.CodeUnauthorized, `.*invalid entity name or password.*`)
+}
+
+func (s *gatewaySuite) TestAgentsRejected(c *gc.C) {
+	machine, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "fake_nonce",
+	})
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "POST",
+		URL:      s.modelGatewayURI("Client/2/FullStatus"),
+		Tag:      machine.Tag().String(),
+		Password: password,
+		Nonce:    "fake_nonce",
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, params.CodeUnauthorized, `.*machine login through the HTTP gateway not valid`)
+}
+
+func (s *gatewaySuite) TestAPIToken(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", Access: permission.AdminAccess})
+	_, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     user.UserTag(),
+		Models:    []string{s.State.ModelUUID()},
+		Access:    permission.ReadAccess,
+		Expires:   time.Now().Add(time.Hour),
+		CreatedBy: s.Owner,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	headers := map[string]string{"Authorization": "Bearer " + secret}
+
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:       "POST",
+		URL:          s.modelGatewayURI("Client/2/FullStatus"),
+		JSONBody:     params.StatusParams{},
+		ExtraHeaders: headers,
+	})
+	apitesting.AssertResponse(c, resp, http.StatusOK, params.ContentTypeJSON)
+
+	// The token only grants read access, even though its owner is
+	// a model admin.
+	resp = apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    s.modelGatewayURI("Client/2/SetModelAgentVersion"),
+		JSONBody: params.SetModelAgentVersion{
+			Version: version.MustParse("2.9.99"),
+		},
+		ExtraHeaders: headers,
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, params.CodeUnauthorized, `permission denied`)
+
+	// Tokens may not be used for controller logins.
+	resp = apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:       "POST",
+		URL:          s.controllerGatewayURI("UserManager/3/UserInfo"),
+		JSONBody:     params.UserInfoRequest{},
+		ExtraHeaders: headers,
+	})
+	s.assertError(c, resp, http.StatusUnauthorized, params.CodeUnauthorized, `permission denied`)
+}
+
+func (s *gatewaySuite) TestOpenAPI(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.controllerGatewayURI("openapi.json"),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, "application/json")
+	var doc struct {
+		OpenAPI    string                 `json:"openapi"`
+		Paths      map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	err := json.Unmarshal(body, &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(doc.OpenAPI, gc.Equals, "3.0.3")
+	for _, path := range []string{
+		"/model/{modeluuid}/gateway/Client/2/FullStatus",
+		"/gateway/UserManager/3/AddAPITokens",
+	} {
+		_, ok := doc.Paths[path]
+		c.Check(ok, jc.IsTrue, gc.Commentf("path %q", path))
+	}
+	for _, path := range []string{
+		"/gateway/Client/2/FullStatus",
+		"/model/{modeluuid}/gateway/AllWatcher/1/Next",
+	} {
+		_, ok := doc.Paths[path]
+		c.Check(ok, jc.IsFalse, gc.Commentf("path %q", path))
+	}
+	for _, name := range []string{"FullStatus", "GatewayErrorResult"} {
+		_, ok := doc.Components.Schemas[name]
+		c.Check(ok, jc.IsTrue, gc.Commentf("schema %q", name))
+	}
+}
+
+type gatewayDisabledSuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&gatewayDisabledSuite{})
+
+func (s *gatewayDisabledSuite) TestDisabledByDefault(c *gc.C) {
+	url := s.URL(fmt.Sprintf("/model/%s/gateway/Client/2/FullStatus", s.State.ModelUUID()), nil)
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "POST",
+		URL:    url.String(),
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+
+	resp = s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.URL("/gateway/openapi.json", nil).String(),
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusNotFound)
+}
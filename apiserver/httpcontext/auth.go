@@ -17,6 +17,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/permission"
 )
 
 var logger = loggo.GetLogger("juju.apiserver.httpcontext")
@@ -93,6 +94,34 @@ type AuthInfo struct {
 	// Controller reports whether or not the authenticated
 	// entity is a controller agent.
 	Controller bool
+
+	// APIToken holds details of the API token used to authenticate,
+	// if any. When set, the entity's access is restricted to the
+	// scope of the token.
+	APIToken *APITokenInfo
+}
+
+// APITokenInfo describes an API token used to authenticate a login.
+type APITokenInfo struct {
+	// ID is the unique identifier of the token.
+	ID string
+
+	// ModelUUID is the UUID of the model the token was used to
+	// log in to.
+	ModelUUID string
+
+	// Access is the maximum model access granted by the token.
+	Access permission.Access
+}
+
+// Permits reports whether the token allows the given operation on
+// the target. Tokens only ever grant access to the model they were
+// used to log in to, and never to the controller or clouds.
+func (t *APITokenInfo) Permits(operation permission.Access, target names.Tag) bool {
+	if target.Kind() != names.ModelTagKind || target.Id() != t.ModelUUID {
+		return false
+	}
+	return t.Access.EqualOrGreaterModelAccessThan(operation)
 }
 
 // BasicAuthHandler is an http.Handler that authenticates requests that
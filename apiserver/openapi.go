@@ -0,0 +1,314 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	jsonschema "github.com/juju/jsonschema-gen"
+	"github.com/juju/rpcreflect"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/feature"
+	jujuversion "github.com/juju/juju/version"
+)
+
+const (
+	openAPIVersion = "3.0.3"
+
+	gatewayControllerPath = "/gateway"
+	gatewayModelPath      = "/model/{modeluuid}/gateway"
+
+	jsonSchemaRefPrefix = "#/definitions/"
+	openAPIRefPrefix    = "#/components/schemas/"
+
+	// gatewayErrorSchema is the name of the schema describing
+	// the body of error responses from the gateway.
+	gatewayErrorSchema = "GatewayErrorResult"
+)
+
+// openAPIHandler serves an OpenAPI document describing the methods that
+// may be called through the HTTP gateway, and their parameter and result
+// types. The document is generated from the facade registry on first use.
+type openAPIHandler struct {
+	srv *Server
+
+	once sync.Once
+	doc  []byte
+	err  error
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *openAPIHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if !h.srv.shared.featureEnabled(feature.HTTPGateway) {
+		http.NotFound(w, req)
+		return
+	}
+	h.once.Do(func() {
+		var doc map[string]interface{}
+		doc, h.err = gatewayOpenAPI(h.srv.facades)
+		if h.err == nil {
+			h.doc, h.err = json.Marshal(doc)
+		}
+	})
+	if h.err != nil {
+		if err := sendError(w, errors.Annotate(h.err, "cannot generate OpenAPI document")); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprint(len(h.doc)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(h.doc); err != nil {
+		logger.Errorf("cannot write OpenAPI document: %v", err)
+	}
+}
+
+// gatewayOpenAPI returns an OpenAPI document describing every version of
+// every facade in the registry that may be called through the gateway.
+func gatewayOpenAPI(registry *facade.Registry) (map[string]interface{}, error) {
+	paths := make(map[string]interface{})
+	schemas := map[string]interface{}{
+		gatewayErrorSchema: gatewayErrorResultSchema(),
+	}
+	for _, desc := range registry.List() {
+		if !gatewayFacade(desc.Name) {
+			continue
+		}
+		for _, version := range desc.Versions {
+			facadeType, err := registry.GetType(desc.Name, version)
+			if err != nil {
+				return nil, errors.Annotatef(err, "getting type for facade %s version %d", desc.Name, version)
+			}
+			methods, err := facadeMethodSchemas(desc.Name, version, facadeType, schemas)
+			if err != nil {
+				return nil, errors.Annotatef(err, "generating schema for facade %s version %d", desc.Name, version)
+			}
+			var prefixes []string
+			if IsControllerFacade(desc.Name) {
+				prefixes = append(prefixes, gatewayControllerPath)
+			}
+			if IsModelFacade(desc.Name) {
+				prefixes = append(prefixes, gatewayModelPath)
+			}
+			for methodName, method := range methods {
+				for _, prefix := range prefixes {
+					path := fmt.Sprintf("%s/%s/%d/%s", prefix, desc.Name, version, methodName)
+					paths[path] = map[string]interface{}{
+						"post": gatewayOperation(desc.Name, version, methodName, prefix, method),
+					}
+				}
+			}
+		}
+	}
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":   "Juju API",
+			"version": jujuversion.Current.String(),
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": schemas,
+			"securitySchemes": map[string]interface{}{
+				"basic":  map[string]interface{}{"type": "http", "scheme": "basic"},
+				"bearer": map[string]interface{}{"type": "http", "scheme": "bearer"},
+			},
+		},
+		"security": []interface{}{
+			map[string]interface{}{"basic": []interface{}{}},
+			map[string]interface{}{"bearer": []interface{}{}},
+		},
+	}, nil
+}
+
+// methodSchema holds the schemas of a facade method's parameters and
+// result, either of which may be nil.
+type methodSchema struct {
+	params interface{}
+	result interface{}
+}
+
+// facadeMethodSchemas reflects over the facade type to find the schemas
+// of its methods. The definitions used by the methods are added to
+// schemas; where a definition of the same name but a different shape
+// already exists there, the facade's definition is renamed so that it
+// is qualified by the facade name and version.
+func facadeMethodSchemas(name string, version int, facadeType reflect.Type, schemas map[string]interface{}) (map[string]methodSchema, error) {
+	schema := jsonschema.ReflectFromObjType(rpcreflect.ObjTypeOf(facadeType))
+
+	// Go through JSON to get a generic representation
+	// that we can compare and rewrite.
+	data, err := json.Marshal(schema)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var generic struct {
+		Properties map[string]struct {
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"properties"`
+		Definitions map[string]interface{} `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	// Find the definitions that clash with those from other facades,
+	// including those that only clash because they refer to others
+	// that do, and rename them.
+	renames := make(map[string]string)
+	for {
+		renamed := false
+		for defName, def := range generic.Definitions {
+			if _, ok := renames[defName]; ok {
+				continue
+			}
+			existing, ok := schemas[defName]
+			if ok && !reflect.DeepEqual(existing, rewriteSchemaRefs(def, renames)) {
+				renames[defName] = fmt.Sprintf("%sV%d.%s", name, version, defName)
+				renamed = true
+			}
+		}
+		if !renamed {
+			break
+		}
+	}
+	for defName, def := range generic.Definitions {
+		if newName, ok := renames[defName]; ok {
+			defName = newName
+		}
+		if _, ok := schemas[defName]; !ok {
+			schemas[defName] = rewriteSchemaRefs(def, renames)
+		}
+	}
+
+	result := make(map[string]methodSchema)
+	for methodName, method := range generic.Properties {
+		var m methodSchema
+		if params, ok := method.Properties["Params"]; ok {
+			m.params = rewriteSchemaRefs(params, renames)
+		}
+		if res, ok := method.Properties["Result"]; ok {
+			m.result = rewriteSchemaRefs(res, renames)
+		}
+		result[methodName] = m
+	}
+	return result, nil
+}
+
+// rewriteSchemaRefs returns a copy of the generic JSON schema value v with
+// all references to definitions rewritten to refer to OpenAPI component
+// schemas, applying the given renames.
+func rewriteSchemaRefs(v interface{}, renames map[string]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" && strings.HasPrefix(ref, jsonSchemaRefPrefix) {
+				defName := strings.TrimPrefix(ref, jsonSchemaRefPrefix)
+				if newName, ok := renames[defName]; ok {
+					defName = newName
+				}
+				out[key] = openAPIRefPrefix + defName
+				continue
+			}
+			out[key] = rewriteSchemaRefs(value, renames)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, value := range v {
+			out[i] = rewriteSchemaRefs(value, renames)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// gatewayOperation returns the OpenAPI operation object describing a
+// call to the given facade method through the gateway.
+func gatewayOperation(facadeName string, version int, methodName, prefix string, method methodSchema) map[string]interface{} {
+	operationID := fmt.Sprintf("%sV%d.%s", facadeName, version, methodName)
+	parameters := []interface{}{
+		map[string]interface{}{
+			"name":        "id",
+			"in":          "query",
+			"description": "The ID of the facade object to call the method on, if any.",
+			"schema":      map[string]interface{}{"type": "string"},
+		},
+	}
+	if prefix == gatewayModelPath {
+		operationID = "Model." + operationID
+		parameters = append(parameters, map[string]interface{}{
+			"name":     "modeluuid",
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+	responseBody := map[string]interface{}{"type": "object"}
+	if method.result != nil {
+		responseBody = method.result.(map[string]interface{})
+	}
+	op := map[string]interface{}{
+		"operationId": operationID,
+		"tags":        []interface{}{facadeName},
+		"parameters":  parameters,
+		"responses": map[string]interface{}{
+			"200": map[string]interface{}{
+				"description": "The result of the call.",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{"schema": responseBody},
+				},
+			},
+			"default": map[string]interface{}{
+				"description": "The call failed.",
+				"content": map[string]interface{}{
+					"application/json": map[string]interface{}{
+						"schema": map[string]interface{}{"$ref": openAPIRefPrefix + gatewayErrorSchema},
+					},
+				},
+			},
+		},
+	}
+	if method.params != nil {
+		op["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": method.params},
+			},
+		}
+	}
+	return op
+}
+
+// gatewayErrorResultSchema returns the schema of params.ErrorResult, as
+// sent in the body of error responses.
+func gatewayErrorResultSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"error": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"message": map[string]interface{}{"type": "string"},
+					"code":    map[string]interface{}{"type": "string"},
+					"info": map[string]interface{}{
+						"type":                 "object",
+						"additionalProperties": true,
+					},
+				},
+				"required": []interface{}{"message", "code"},
+			},
+		},
+	}
+}
@@ -174,6 +174,7 @@ const (
 	CodeIncompatibleSeries        = "incompatible series"
 	CodeCloudRegionRequired       = "cloud region required"
 	CodeIncompatibleClouds        = "incompatible clouds"
+	CodeRateLimitExceeded         = "rate limit exceeded"
 )
 
 // ErrCode returns the error code associated with
@@ -347,3 +348,10 @@ func IsCodeForbidden(err error) bool {
 func IsCodeCloudRegionRequired(err error) bool {
 	return ErrCode(err) == CodeCloudRegionRequired
 }
+
+// IsCodeRateLimitExceeded reports whether the request was rejected
+// because the caller exceeded its request rate. Such requests may be
+// retried after backing off.
+func IsCodeRateLimitExceeded(err error) bool {
+	return ErrCode(err) == CodeRateLimitExceeded
+}
@@ -154,6 +154,20 @@ type ApplicationSetCharm struct {
 // ApplicationExpose holds the parameters for making the application Expose call.
 type ApplicationExpose struct {
 	ApplicationName string `json:"application"`
+
+	// ExposedEndpoints restricts the networks from which the
+	// application's endpoints may be reached, keyed by endpoint
+	// name; the empty name refers to all endpoints. If empty, the
+	// application is exposed to any network. This field is only
+	// understood by Application facade version 13 and greater.
+	ExposedEndpoints map[string]ExposedEndpoint `json:"exposed-endpoints,omitempty"`
+}
+
+// ExposedEndpoint describes the networks from which the ports opened
+// for an exposed application endpoint may be reached.
+type ExposedEndpoint struct {
+	ExposeToSpaces []string `json:"expose-to-spaces,omitempty"`
+	ExposeToCIDRs  []string `json:"expose-to-cidrs,omitempty"`
 }
 
 // ApplicationSet holds the parameters for an application Set
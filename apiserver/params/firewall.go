@@ -60,3 +60,63 @@ func (v KnownServiceValue) Validate() error {
 	}
 	return errors.NotValidf("known service %q", v)
 }
+
+// EgressPolicy describes the destinations to which the units of an
+// application may send outbound traffic.
+type EgressPolicy struct {
+	// ToCIDRs holds the destination networks, in CIDR notation.
+	ToCIDRs []string `json:"to-cidrs,omitempty"`
+
+	// ToSpaces holds the names of the spaces whose subnets
+	// may be reached.
+	ToSpaces []string `json:"to-spaces,omitempty"`
+
+	// ToApplications holds the names of the applications whose
+	// units may be reached.
+	ToApplications []string `json:"to-applications,omitempty"`
+}
+
+// ApplicationSetEgressPolicy holds the parameters for the application
+// SetEgressPolicy call. A nil policy removes any restriction.
+type ApplicationSetEgressPolicy struct {
+	ApplicationName string        `json:"application"`
+	Policy          *EgressPolicy `json:"policy,omitempty"`
+}
+
+// EgressCIDRsResult holds the resolved outbound destinations for
+// an application, or an error.
+type EgressCIDRsResult struct {
+	// Restricted is true if the application has an egress policy.
+	Restricted bool `json:"restricted"`
+
+	// CIDRs holds the destinations permitted by the policy.
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+// EgressCIDRsResults holds the results of the firewaller
+// GetEgressCIDRs call.
+type EgressCIDRsResults struct {
+	Results []EgressCIDRsResult `json:"results"`
+}
+
+// ExposedCIDRsResult holds the networks from which the ports of an
+// application's exposed endpoints may be reached, or an error.
+type ExposedCIDRsResult struct {
+	// Exposed is true if the application is exposed.
+	Exposed bool `json:"exposed"`
+
+	// EndpointCIDRs holds the networks, in CIDR notation, from
+	// which each exposed endpoint may be reached. The empty
+	// endpoint name refers to all endpoints.
+	EndpointCIDRs map[string][]string `json:"endpoint-cidrs,omitempty"`
+
+	Error *Error `json:"error,omitempty"`
+}
+
+// ExposedCIDRsResults holds the results of the firewaller
+// GetExposedCIDRs call.
+type ExposedCIDRsResults struct {
+	Results []ExposedCIDRsResult `json:"results"`
+}
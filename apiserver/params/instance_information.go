@@ -72,3 +72,19 @@ type InstanceType struct {
 	Deprecated   bool     `json:"deprecated,omitempty"`
 	Cost         int      `json:"cost,omitempty"`
 }
+
+// CloudQuotasResult contains the result of prompting a cloud for the
+// quotas of the region in which the current model is deployed.
+type CloudQuotasResult struct {
+	Cloud  string       `json:"cloud"`
+	Region string       `json:"region,omitempty"`
+	Quotas []CloudQuota `json:"quotas"`
+}
+
+// CloudQuota represents a limit on the use of a resource in a cloud
+// region, and how much of the resource is in use.
+type CloudQuota struct {
+	Name  string  `json:"name"`
+	Limit float64 `json:"limit"`
+	Usage float64 `json:"usage"`
+}
@@ -4,6 +4,7 @@
 package params
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/juju/version"
@@ -696,6 +697,17 @@ type RunParams struct {
 	WorkloadContext bool `json:"workload-context,omitempty"`
 }
 
+// CheckNetworkParams holds the arguments for checking the network
+// connectivity between related units.
+type CheckNetworkParams struct {
+	// Applications restricts the check to the relations of these
+	// applications. All relations are checked if it is empty.
+	Applications []string `json:"applications,omitempty"`
+
+	// Timeout is the time allowed for each probe.
+	Timeout time.Duration `json:"timeout"`
+}
+
 // RunResult contains the result from an individual run call on a machine.
 // UnitId is populated if the command was run inside the unit context.
 type RunResult struct {
@@ -735,6 +747,11 @@ type ProvisioningInfo struct {
 	ControllerConfig  map[string]interface{}   `json:"controller-config,omitempty"`
 	CloudInitUserData map[string]interface{}   `json:"cloudinit-userdata,omitempty"`
 	CharmLXDProfiles  []string                 `json:"charm-lxd-profiles,omitempty"`
+
+	// CloudSpec is set for machines placed in a region of the model's
+	// cloud other than the model's own, and is the spec of the cloud
+	// region in which the machine's instance is to be started.
+	CloudSpec *CloudSpec `json:"cloud-spec,omitempty"`
 }
 
 // ProvisioningInfoResult holds machine provisioning info or an error.
@@ -833,6 +850,16 @@ type GUIVersionRequest struct {
 	Version version.Number `json:"version"`
 }
 
+// FacadeSchema holds the JSON schema of the methods of a single facade
+// version, as returned by /schema GET requests. The field names match
+// those used in the facade schema checked in to the source tree, so that
+// the two can be compared.
+type FacadeSchema struct {
+	Name    string          `json:"Name"`
+	Version int             `json:"Version"`
+	Schema  json.RawMessage `json:"Schema"`
+}
+
 // LogMessage is a structured logging entry.
 type LogMessage struct {
 	Entity    string    `json:"tag"`
@@ -914,3 +941,21 @@ type ContainerTypeResult struct {
 	Type  instance.ContainerType `json:"container-type"`
 	Error *Error                 `json:"error"`
 }
+
+// DownMachine holds the details of a machine whose agent is down and
+// whose instance is not running, which hosts units of applications that
+// have opted into machine replacement, or of a machine whose spot
+// instance was interrupted by the cloud.
+type DownMachine struct {
+	Tag string `json:"tag"`
+
+	// ReplaceAfter is how long the machine may be down before it is
+	// replaced; the shortest delay of the applications with units on it,
+	// or zero if its spot instance was interrupted.
+	ReplaceAfter time.Duration `json:"replace-after"`
+}
+
+// DownMachinesResult holds the machines that may need replacing.
+type DownMachinesResult struct {
+	Machines []DownMachine `json:"machines"`
+}
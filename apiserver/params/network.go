@@ -172,6 +172,7 @@ type DeviceBridgeInfo struct {
 	HostDeviceName string `json:"host-device-name"`
 	BridgeName     string `json:"bridge-name"`
 	MACAddress     string `json:"mac-address"`
+	MTU            uint   `json:"mtu,omitempty"`
 }
 
 // ProviderInterfaceInfoResults holds the results of a
@@ -268,12 +269,14 @@ type EntitiesPorts struct {
 	Entities []EntityPort `json:"entities"`
 }
 
-// EntityPortRange holds an entity's tag, a protocol and a port range.
+// EntityPortRange holds an entity's tag, a protocol and a port range,
+// and the endpoint the range is opened for, if any.
 type EntityPortRange struct {
 	Tag      string `json:"tag"`
 	Protocol string `json:"protocol"`
 	FromPort int    `json:"from-port"`
 	ToPort   int    `json:"to-port"`
+	Endpoint string `json:"endpoint,omitempty"`
 }
 
 // EntitiesPortRanges holds the parameters for making an OpenPorts or
@@ -526,11 +529,13 @@ type MachineAddressesResults struct {
 }
 
 // MachinePortRange holds a single port range open on a machine for
-// the given unit and relation tags.
+// the given unit and relation tags, and the endpoint the range was
+// opened for. An empty endpoint refers to all of the unit's endpoints.
 type MachinePortRange struct {
 	UnitTag     string    `json:"unit-tag"`
 	RelationTag string    `json:"relation-tag"`
 	PortRange   PortRange `json:"port-range"`
+	Endpoint    string    `json:"endpoint,omitempty"`
 }
 
 // MachinePorts holds a machine and subnet tags. It's used when referring to
@@ -257,6 +257,11 @@ type DestroyMachinesParams struct {
 	// will wait before forcing the next step to kick-off. This parameter
 	// only makes sense in combination with 'force' set to 'true'.
 	MaxWait *time.Duration `json:"max-wait,omitempty"`
+
+	// KeepWorkloads specifies that the agents of manually provisioned
+	// machines should leave the workloads of their units installed
+	// when they uninstall themselves.
+	KeepWorkloads bool `json:"keep-workloads,omitempty"`
 }
 
 // UpdateSeriesArg holds the parameters for updating the series for the
@@ -506,6 +511,7 @@ type LoginRequest struct {
 	Macaroons   []macaroon.Slice `json:"macaroons"`
 	CLIArgs     string           `json:"cli-args,omitempty"`
 	UserData    string           `json:"user-data"`
+	Token       string           `json:"token,omitempty"`
 }
 
 // LoginRequestCompat holds credentials for identifying an entity to the Login v1
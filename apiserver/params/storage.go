@@ -99,6 +99,11 @@ type StorageAttachment struct {
 	Kind     StorageKind `json:"kind"`
 	Location string      `json:"location"`
 	Life     life.Value  `json:"life"`
+	Size     uint64      `json:"size,omitempty"`
+
+	// Moving is true if the storage is being moved to another
+	// storage pool, and the unit should stop using it.
+	Moving bool `json:"moving,omitempty"`
 }
 
 // StorageAttachmentId identifies a storage attachment by the tags of the
@@ -258,6 +263,7 @@ type VolumeParams struct {
 	Provider   string                  `json:"provider"`
 	Attributes map[string]interface{}  `json:"attributes,omitempty"`
 	Tags       map[string]string       `json:"tags,omitempty"`
+	SnapshotId string                  `json:"snapshot-id,omitempty"`
 	Attachment *VolumeAttachmentParams `json:"attachment,omitempty"`
 }
 
@@ -371,9 +377,22 @@ type VolumeAttachmentParamsResults struct {
 
 // Filesystem identifies and describes a storage filesystem in the model.
 type Filesystem struct {
-	FilesystemTag string         `json:"filesystem-tag"`
-	VolumeTag     string         `json:"volume-tag,omitempty"`
-	Info          FilesystemInfo `json:"info"`
+	FilesystemTag string                 `json:"filesystem-tag"`
+	VolumeTag     string                 `json:"volume-tag,omitempty"`
+	Info          FilesystemInfo         `json:"info"`
+	Composition   *FilesystemComposition `json:"composition,omitempty"`
+}
+
+// FilesystemComposition describes how the volumes backing a filesystem
+// are combined by the machine agent to present a single block device.
+type FilesystemComposition struct {
+	// Type is the way in which the volumes are combined:
+	// "raid0", "raid1" or "lvm".
+	Type string `json:"type"`
+
+	// VolumeTags holds the tags of the combined volumes. The
+	// first is the filesystem's backing volume.
+	VolumeTags []string `json:"volume-tags"`
 }
 
 // Filesystem describes a storage filesystem in the model.
@@ -419,7 +438,9 @@ type FilesystemParams struct {
 	Provider      string                      `json:"provider"`
 	Attributes    map[string]interface{}      `json:"attributes,omitempty"`
 	Tags          map[string]string           `json:"tags,omitempty"`
+	SnapshotId    string                      `json:"snapshot-id,omitempty"`
 	Attachment    *FilesystemAttachmentParams `json:"attachment,omitempty"`
+	Composition   *FilesystemComposition      `json:"composition,omitempty"`
 }
 
 // RemoveFilesystemParams holds the parameters for destroying or releasing
@@ -951,3 +972,259 @@ type AddStorageDetails struct {
 	// of the added storage instances.
 	StorageTags []string `json:"storage-tags"`
 }
+
+// StorageSnapshotIds holds the IDs of storage snapshots.
+type StorageSnapshotIds struct {
+	Ids []string `json:"ids"`
+}
+
+// StorageSnapshotParams holds the parameters for taking or deleting a
+// snapshot of a volume or filesystem. Exactly one of VolumeTag and
+// FilesystemTag will be set.
+type StorageSnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string `json:"id"`
+
+	// Life is the life of the snapshot. Alive snapshots are to be
+	// taken, and dying snapshots deleted.
+	Life life.Value `json:"life"`
+
+	// SnapshotId is the storage provider's unique ID for the snapshot,
+	// set only for dying snapshots.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	VolumeTag     string                 `json:"volume-tag,omitempty"`
+	VolumeId      string                 `json:"volume-id,omitempty"`
+	FilesystemTag string                 `json:"filesystem-tag,omitempty"`
+	FilesystemId  string                 `json:"filesystem-id,omitempty"`
+	Provider      string                 `json:"provider"`
+	Attributes    map[string]interface{} `json:"attributes,omitempty"`
+	Tags          map[string]string      `json:"tags,omitempty"`
+}
+
+// StorageSnapshotParamsResult holds provisioning parameters for
+// a storage snapshot.
+type StorageSnapshotParamsResult struct {
+	Result *StorageSnapshotParams `json:"result,omitempty"`
+	Error  *Error                 `json:"error,omitempty"`
+}
+
+// StorageSnapshotParamsResults holds provisioning parameters for
+// multiple storage snapshots.
+type StorageSnapshotParamsResults struct {
+	Results []StorageSnapshotParamsResult `json:"results,omitempty"`
+}
+
+// StorageSnapshotResult records the outcome of taking a storage snapshot.
+// If the snapshot could not be taken, SnapshotId will be empty and Message
+// will describe the failure.
+type StorageSnapshotResult struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string `json:"id"`
+
+	// SnapshotId is the storage provider's unique ID for the snapshot.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	// Message describes the reason the snapshot could not be taken.
+	Message string `json:"message,omitempty"`
+}
+
+// StorageSnapshotResults records the outcomes of taking storage snapshots.
+type StorageSnapshotResults struct {
+	Results []StorageSnapshotResult `json:"results"`
+}
+
+// StorageSnapshotDetails holds information about a storage snapshot.
+type StorageSnapshotDetails struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string `json:"id"`
+
+	// StorageTag is the tag of the storage instance that was snapshotted.
+	StorageTag string `json:"storage-tag"`
+
+	// Kind is the kind of the storage instance that was snapshotted.
+	Kind StorageKind `json:"kind"`
+
+	// Pool is the name of the storage pool that the snapshotted storage
+	// was provisioned from.
+	Pool string `json:"pool"`
+
+	// Size is the size of the snapshotted storage, in MiB.
+	Size uint64 `json:"size"`
+
+	// Status is the status of the snapshot: pending, available or failed.
+	Status string `json:"status"`
+
+	// Life is the life of the snapshot. Dying snapshots are being
+	// deleted from the storage provider.
+	Life life.Value `json:"life,omitempty"`
+
+	// Message describes the reason the snapshot failed, if it did.
+	Message string `json:"message,omitempty"`
+
+	// SnapshotId is the storage provider's unique ID for the snapshot.
+	SnapshotId string `json:"snapshot-id,omitempty"`
+
+	// Created is the time at which the snapshot was requested.
+	Created time.Time `json:"created"`
+}
+
+// StorageSnapshotDetailsResults holds information about multiple storage
+// snapshots.
+type StorageSnapshotDetailsResults struct {
+	Results []StorageSnapshotDetails `json:"results"`
+}
+
+// RestoreStorageParams holds the parameters for restoring a storage
+// snapshot to a unit.
+type RestoreStorageParams struct {
+	// SnapshotId is the ID assigned by Juju to the snapshot to restore.
+	SnapshotId string `json:"snapshot-id"`
+
+	// UnitTag is the tag of the unit to which the restored storage
+	// will be attached.
+	UnitTag string `json:"unit-tag"`
+}
+
+// BulkRestoreStorageParams holds the parameters for restoring
+// multiple storage snapshots.
+type BulkRestoreStorageParams struct {
+	Args []RestoreStorageParams `json:"args"`
+}
+
+// StorageResizeParams holds the parameters for resizing a volume or
+// filesystem.
+type StorageResizeParams struct {
+	// Tag is the tag of the volume or filesystem to resize.
+	Tag string `json:"tag"`
+
+	// ProviderId is the storage provider's unique ID for the
+	// volume or filesystem.
+	ProviderId string `json:"provider-id"`
+
+	// VolumeTag is the tag of the volume backing the filesystem
+	// to resize, if any.
+	VolumeTag string `json:"volume-tag,omitempty"`
+
+	// Size is the size, in MiB, to grow the volume or filesystem to.
+	Size uint64 `json:"size"`
+
+	Provider   string                 `json:"provider"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// StorageResizeParamsResult holds the parameters for resizing a
+// volume or filesystem.
+type StorageResizeParamsResult struct {
+	Result *StorageResizeParams `json:"result,omitempty"`
+	Error  *Error               `json:"error,omitempty"`
+}
+
+// StorageResizeParamsResults holds the parameters for resizing
+// multiple volumes and filesystems.
+type StorageResizeParamsResults struct {
+	Results []StorageResizeParamsResult `json:"results,omitempty"`
+}
+
+// StorageResizeResult records the outcome of resizing a volume or
+// filesystem. If the resize failed, Size will be zero and Message
+// will describe the failure.
+type StorageResizeResult struct {
+	// Tag is the tag of the resized volume or filesystem.
+	Tag string `json:"tag"`
+
+	// Size is the size, in MiB, of the resized volume or filesystem.
+	Size uint64 `json:"size,omitempty"`
+
+	// Message describes the reason the resize failed.
+	Message string `json:"message,omitempty"`
+}
+
+// StorageResizeResults records the outcomes of resizing volumes and
+// filesystems.
+type StorageResizeResults struct {
+	Results []StorageResizeResult `json:"results"`
+}
+
+// ResizeStorageParams holds the parameters for growing the volume or
+// filesystem assigned to a storage instance.
+type ResizeStorageParams struct {
+	// StorageTag is the tag of the storage instance to resize.
+	StorageTag string `json:"storage-tag"`
+
+	// Size is the size, in MiB, to grow the storage to.
+	Size uint64 `json:"size"`
+}
+
+// BulkResizeStorageParams holds the parameters for resizing multiple
+// storage instances.
+type BulkResizeStorageParams struct {
+	Args []ResizeStorageParams `json:"args"`
+}
+
+// VolumeCopyParams holds the parameters for copying the contents of
+// one volume to another, both attached to the same machine.
+type VolumeCopyParams struct {
+	// MachineTag is the tag of the machine to which both
+	// volumes are attached.
+	MachineTag string `json:"machine-tag"`
+
+	// SourceVolumeTag is the tag of the volume to copy from.
+	SourceVolumeTag string `json:"source-volume-tag"`
+
+	// TargetVolumeTag is the tag of the volume to copy to.
+	TargetVolumeTag string `json:"target-volume-tag"`
+
+	// FilesystemTag is the tag of the filesystem backed by the
+	// source volume, if any. The filesystem must be unmounted
+	// while its data is copied, and remounted from the target
+	// volume once the copy succeeds.
+	FilesystemTag string `json:"filesystem-tag,omitempty"`
+}
+
+// VolumeCopyParamsResult holds the parameters for copying the
+// contents of a volume.
+type VolumeCopyParamsResult struct {
+	Result *VolumeCopyParams `json:"result,omitempty"`
+	Error  *Error            `json:"error,omitempty"`
+}
+
+// VolumeCopyParamsResults holds the parameters for copying the
+// contents of multiple volumes.
+type VolumeCopyParamsResults struct {
+	Results []VolumeCopyParamsResult `json:"results,omitempty"`
+}
+
+// VolumeCopyResult records the outcome of copying data to a volume.
+// If the copy failed, Message will describe the failure.
+type VolumeCopyResult struct {
+	// MachineTag is the tag of the machine that performed the copy.
+	MachineTag string `json:"machine-tag"`
+
+	// VolumeTag is the tag of the volume that was copied to.
+	VolumeTag string `json:"volume-tag"`
+
+	// Message describes the reason the copy failed.
+	Message string `json:"message,omitempty"`
+}
+
+// VolumeCopyResults records the outcomes of copying data to volumes.
+type VolumeCopyResults struct {
+	Results []VolumeCopyResult `json:"results"`
+}
+
+// MoveStorageParams holds the parameters for moving a storage
+// instance to another storage pool.
+type MoveStorageParams struct {
+	// StorageTag is the tag of the storage instance to move.
+	StorageTag string `json:"storage-tag"`
+
+	// Pool is the name of the storage pool to move the storage to.
+	Pool string `json:"pool"`
+}
+
+// BulkMoveStorageParams holds the parameters for moving multiple
+// storage instances.
+type BulkMoveStorageParams struct {
+	Args []MoveStorageParams `json:"args"`
+}
@@ -67,3 +67,67 @@ type AddUserResult struct {
 	SecretKey []byte `json:"secret-key,omitempty"`
 	Error     *Error `json:"error,omitempty"`
 }
+
+// AddAPITokens holds the parameters for minting API tokens.
+type AddAPITokens struct {
+	Tokens []AddAPIToken `json:"tokens"`
+}
+
+// AddAPIToken holds the parameters for minting one API token.
+type AddAPIToken struct {
+	// OwnerTag is the tag of the local user the token will
+	// authenticate as.
+	OwnerTag    string `json:"owner-tag"`
+	Description string `json:"description,omitempty"`
+
+	// ModelTags holds the tags of the models the token may be
+	// used to access.
+	ModelTags []string `json:"model-tags"`
+
+	// Access is the maximum model access the token grants.
+	Access  string    `json:"access"`
+	Expires time.Time `json:"expires"`
+}
+
+// AddAPITokenResults holds the results of the bulk AddAPITokens API call.
+type AddAPITokenResults struct {
+	Results []AddAPITokenResult `json:"results"`
+}
+
+// AddAPITokenResult holds the details of a newly minted API token along
+// with the secret used to log in with it, or an error. The secret is
+// only ever returned here.
+type AddAPITokenResult struct {
+	Token  *APIToken `json:"token,omitempty"`
+	Secret string    `json:"secret,omitempty"`
+	Error  *Error    `json:"error,omitempty"`
+}
+
+// APIToken holds information on an API token.
+type APIToken struct {
+	Id          string    `json:"id"`
+	OwnerTag    string    `json:"owner-tag"`
+	Description string    `json:"description,omitempty"`
+	ModelTags   []string  `json:"model-tags"`
+	Access      string    `json:"access"`
+	CreatedBy   string    `json:"created-by"`
+	DateCreated time.Time `json:"date-created"`
+	Expires     time.Time `json:"expires"`
+	Revoked     bool      `json:"revoked"`
+}
+
+// APITokensResult holds the API tokens owned by a user, or an error.
+type APITokensResult struct {
+	Tokens []APIToken `json:"tokens,omitempty"`
+	Error  *Error     `json:"error,omitempty"`
+}
+
+// APITokensResults holds the results of the bulk APITokens API call.
+type APITokensResults struct {
+	Results []APITokensResult `json:"results"`
+}
+
+// APITokenIds holds the IDs of API tokens.
+type APITokenIds struct {
+	Ids []string `json:"ids"`
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/ratelimit"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+)
+
+const (
+	throttleScopeUser  = "user"
+	throttleScopeModel = "model"
+)
+
+// unthrottledFacades holds the names of facades whose methods are never
+// subject to request rate limiting. Pings must always get through, or
+// throttled connections would be dropped as dead.
+var unthrottledFacades = map[string]bool{
+	"Pinger": true,
+}
+
+// bucketSweepInterval is the minimum time between sweeps for idle
+// token buckets.
+const bucketSweepInterval = time.Minute
+
+// requestLimiter holds the token buckets used to rate limit API requests
+// made by authenticated users and against individual models. Buckets are
+// created lazily, and are shared by all connections for the same user or
+// model. Buckets that have been idle for long enough to be full again are
+// indistinguishable from new ones, so they are dropped.
+type requestLimiter struct {
+	config    RateLimitConfig
+	clock     clock.Clock
+	throttled *prometheus.CounterVec
+
+	mu        sync.Mutex
+	users     map[string]*limiterBucket
+	models    map[string]*limiterBucket
+	lastSweep time.Time
+}
+
+// limiterBucket is a token bucket along with the time it was last used.
+type limiterBucket struct {
+	*ratelimit.Bucket
+	lastUsed time.Time
+}
+
+// newRequestLimiter returns a requestLimiter applying the request rate
+// limits in the given config. Rejected requests are counted in throttled,
+// if it is non-nil.
+func newRequestLimiter(config RateLimitConfig, clock clock.Clock, throttled *prometheus.CounterVec) *requestLimiter {
+	return &requestLimiter{
+		config:    config,
+		clock:     clock,
+		throttled: throttled,
+		users:     make(map[string]*limiterBucket),
+		models:    make(map[string]*limiterBucket),
+		lastSweep: clock.Now(),
+	}
+}
+
+// enabled reports whether any request rate limiting is configured.
+func (l *requestLimiter) enabled() bool {
+	return l.config.UserRequestRateBurst > 0 || l.config.ModelRequestRateBurst > 0
+}
+
+// take consumes a token from the buckets for the given user and model,
+// returning common.ErrRateLimitExceeded if either bucket is empty.
+// A nil user or empty model UUID means that the corresponding limit
+// does not apply. Tokens are only taken once both buckets are known
+// to have one available, so that a throttled request consumes neither
+// the user's nor the model's allowance.
+func (l *requestLimiter) take(user names.Tag, modelUUID string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	l.sweep(now)
+
+	var userBucket, modelBucket *limiterBucket
+	if user != nil && l.config.UserRequestRateBurst > 0 {
+		userBucket = l.bucket(l.users, user.String(), now, l.config.UserRequestRateRefill, l.config.UserRequestRateBurst)
+		if userBucket.Available() < 1 {
+			l.recordThrottled(modelUUID, throttleScopeUser)
+			return common.ErrRateLimitExceeded
+		}
+	}
+	if modelUUID != "" && l.config.ModelRequestRateBurst > 0 {
+		modelBucket = l.bucket(l.models, modelUUID, now, l.config.ModelRequestRateRefill, l.config.ModelRequestRateBurst)
+		if modelBucket.Available() < 1 {
+			l.recordThrottled(modelUUID, throttleScopeModel)
+			return common.ErrRateLimitExceeded
+		}
+	}
+	// Both buckets are only used with l.mu held, so the tokens
+	// checked for above are still available.
+	if userBucket != nil {
+		userBucket.TakeAvailable(1)
+	}
+	if modelBucket != nil {
+		modelBucket.TakeAvailable(1)
+	}
+	return nil
+}
+
+// bucket returns the bucket with the given key, creating it if needed,
+// and records that it was used at the given time. It must be called
+// with l.mu held.
+func (l *requestLimiter) bucket(
+	buckets map[string]*limiterBucket, key string, now time.Time, refill time.Duration, burst int64,
+) *limiterBucket {
+	bucket, ok := buckets[key]
+	if !ok {
+		bucket = &limiterBucket{
+			Bucket: ratelimit.NewBucketWithClock(refill, burst, ratelimitClock{l.clock}),
+		}
+		buckets[key] = bucket
+	}
+	bucket.lastUsed = now
+	return bucket
+}
+
+// sweep drops the buckets that have not been used for long enough to
+// have refilled completely. It must be called with l.mu held.
+func (l *requestLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < bucketSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	sweepBuckets(l.users, now, l.config.UserRequestRateRefill*time.Duration(l.config.UserRequestRateBurst))
+	sweepBuckets(l.models, now, l.config.ModelRequestRateRefill*time.Duration(l.config.ModelRequestRateBurst))
+}
+
+func sweepBuckets(buckets map[string]*limiterBucket, now time.Time, refillTime time.Duration) {
+	for key, bucket := range buckets {
+		if now.Sub(bucket.lastUsed) >= refillTime {
+			delete(buckets, key)
+		}
+	}
+}
+
+func (l *requestLimiter) recordThrottled(modelUUID, scope string) {
+	logger.Debugf("throttling API request (%s limit) for model %q", scope, modelUUID)
+	if l.throttled == nil {
+		return
+	}
+	l.throttled.With(prometheus.Labels{
+		MetricLabelModelUUID: modelUUID,
+		MetricLabelScope:     scope,
+	}).Inc()
+}
+
+// rateLimitRoot wraps the provided root so that every API request made
+// through it is subject to the per-user and per-model request limits.
+// Only user logins are limited per user; agents are limited only by
+// the model they are connected to.
+func rateLimitRoot(root rpc.Root, limiter *requestLimiter, authTag names.Tag, modelUUID string) rpc.Root {
+	if !limiter.enabled() {
+		return root
+	}
+	var user names.Tag
+	if tag, ok := authTag.(names.UserTag); ok {
+		user = tag
+	}
+	return restrictRoot(root, func(facadeName, _ string) error {
+		if unthrottledFacades[facadeName] {
+			return nil
+		}
+		return limiter.take(user, modelUUID)
+	})
+}
+
+// ratelimitClock adapts clock.Clock to ratelimit.Clock.
+type ratelimitClock struct {
+	clock.Clock
+}
+
+// Sleep is defined by the ratelimit.Clock interface.
+func (c ratelimitClock) Sleep(d time.Duration) {
+	<-c.Clock.After(d)
+}
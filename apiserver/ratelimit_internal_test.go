@@ -0,0 +1,169 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/rpcreflect"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+)
+
+type requestLimiterSuite struct {
+	testing.IsolationSuite
+
+	clock     *testclock.Clock
+	collector *Collector
+}
+
+var _ = gc.Suite(&requestLimiterSuite{})
+
+const limiterModelUUID = "deadbeef-0bad-400d-8000-4b1d0d06f00d"
+
+func (s *requestLimiterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Now())
+	s.collector = NewMetricsCollector()
+}
+
+func (s *requestLimiterSuite) newLimiter(userBurst, modelBurst int64) *requestLimiter {
+	config := DefaultRateLimitConfig()
+	config.UserRequestRateBurst = userBurst
+	config.UserRequestRateRefill = time.Second
+	config.ModelRequestRateBurst = modelBurst
+	config.ModelRequestRateRefill = time.Second
+	return newRequestLimiter(config, s.clock, s.collector.ThrottledRequestCount)
+}
+
+func (s *requestLimiterSuite) throttledCount(c *gc.C, scope string) float64 {
+	var metric dto.Metric
+	err := s.collector.ThrottledRequestCount.With(prometheus.Labels{
+		MetricLabelModelUUID: limiterModelUUID,
+		MetricLabelScope:     scope,
+	}).Write(&metric)
+	c.Assert(err, jc.ErrorIsNil)
+	return metric.GetCounter().GetValue()
+}
+
+func (s *requestLimiterSuite) TestDisabledByDefault(c *gc.C) {
+	limiter := newRequestLimiter(DefaultRateLimitConfig(), s.clock, nil)
+	c.Assert(limiter.enabled(), jc.IsFalse)
+
+	root := &fakeRoot{}
+	c.Assert(rateLimitRoot(root, limiter, names.NewUserTag("bob"), limiterModelUUID), gc.Equals, root)
+}
+
+func (s *requestLimiterSuite) TestUserLimit(c *gc.C) {
+	limiter := s.newLimiter(2, 0)
+	bob := names.NewUserTag("bob")
+	c.Assert(limiter.take(bob, limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.take(bob, limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.take(bob, limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(s.throttledCount(c, throttleScopeUser), gc.Equals, float64(1))
+
+	// Other users have their own bucket.
+	c.Assert(limiter.take(names.NewUserTag("mary"), limiterModelUUID), jc.ErrorIsNil)
+
+	// Tokens are refilled over time.
+	s.clock.Advance(time.Second)
+	c.Assert(limiter.take(bob, limiterModelUUID), jc.ErrorIsNil)
+}
+
+func (s *requestLimiterSuite) TestModelLimit(c *gc.C) {
+	limiter := s.newLimiter(0, 2)
+	c.Assert(limiter.take(names.NewUserTag("bob"), limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.take(names.NewMachineTag("0"), limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.take(names.NewUserTag("mary"), limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(s.throttledCount(c, throttleScopeModel), gc.Equals, float64(1))
+
+	// Controller-only logins have no model to limit.
+	c.Assert(limiter.take(names.NewUserTag("mary"), ""), jc.ErrorIsNil)
+}
+
+func (s *requestLimiterSuite) TestThrottledUserDoesNotConsumeModelTokens(c *gc.C) {
+	limiter := s.newLimiter(1, 2)
+	bob := names.NewUserTag("bob")
+	c.Assert(limiter.take(bob, limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.take(bob, limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(limiter.take(bob, limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(limiter.take(names.NewUserTag("mary"), limiterModelUUID), jc.ErrorIsNil)
+}
+
+func (s *requestLimiterSuite) TestThrottledModelDoesNotConsumeUserTokens(c *gc.C) {
+	config := DefaultRateLimitConfig()
+	config.UserRequestRateBurst = 2
+	config.UserRequestRateRefill = time.Hour
+	config.ModelRequestRateBurst = 1
+	config.ModelRequestRateRefill = time.Second
+	limiter := newRequestLimiter(config, s.clock, s.collector.ThrottledRequestCount)
+
+	bob := names.NewUserTag("bob")
+	c.Assert(limiter.take(bob, limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.take(bob, limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(limiter.take(bob, limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(s.throttledCount(c, throttleScopeModel), gc.Equals, float64(2))
+
+	// Bob's second token was not consumed by the rejected requests.
+	s.clock.Advance(time.Second)
+	c.Assert(limiter.take(bob, limiterModelUUID), jc.ErrorIsNil)
+	s.clock.Advance(time.Second)
+	c.Assert(limiter.take(bob, limiterModelUUID), gc.Equals, common.ErrRateLimitExceeded)
+	c.Assert(s.throttledCount(c, throttleScopeUser), gc.Equals, float64(1))
+}
+
+func (s *requestLimiterSuite) TestIdleBucketsSwept(c *gc.C) {
+	limiter := s.newLimiter(2, 2)
+	c.Assert(limiter.take(names.NewUserTag("bob"), limiterModelUUID), jc.ErrorIsNil)
+	c.Assert(limiter.users, gc.HasLen, 1)
+	c.Assert(limiter.models, gc.HasLen, 1)
+
+	// Buckets are kept until they have refilled, and at least
+	// bucketSweepInterval has passed since the last sweep.
+	s.clock.Advance(bucketSweepInterval)
+	c.Assert(limiter.take(names.NewUserTag("mary"), ""), jc.ErrorIsNil)
+	c.Assert(limiter.users, gc.HasLen, 1)
+	c.Assert(limiter.users, gc.Not(jc.HasKey), names.NewUserTag("bob").String())
+	c.Assert(limiter.models, gc.HasLen, 0)
+}
+
+func (s *requestLimiterSuite) TestRateLimitRoot(c *gc.C) {
+	limiter := s.newLimiter(1, 0)
+	root := rateLimitRoot(&fakeRoot{}, limiter, names.NewUserTag("bob"), limiterModelUUID)
+
+	_, err := root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = root.FindMethod("Client", 1, "FullStatus")
+	c.Assert(err, gc.Equals, common.ErrRateLimitExceeded)
+
+	// Pings are never throttled.
+	_, err = root.FindMethod("Pinger", 1, "Ping")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *requestLimiterSuite) TestRateLimitRootAgentsOnlyModelLimited(c *gc.C) {
+	limiter := s.newLimiter(1, 0)
+	root := rateLimitRoot(&fakeRoot{}, limiter, names.NewMachineTag("0"), limiterModelUUID)
+
+	for i := 0; i < 3; i++ {
+		_, err := root.FindMethod("Uniter", 1, "Life")
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+type fakeRoot struct {
+	rpc.Root
+}
+
+func (*fakeRoot) FindMethod(string, int, string) (rpcreflect.MethodCaller, error) {
+	return nil, nil
+}
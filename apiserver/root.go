@@ -18,6 +18,7 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/httpcontext"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/leadership"
@@ -53,6 +54,11 @@ type apiHandler struct {
 	shared    *sharedServerContext
 	entity    state.Entity
 
+	// apiToken holds details of the API token the entity logged in
+	// with, if any. The entity's permissions are restricted to those
+	// granted by the token.
+	apiToken *httpcontext.APITokenInfo
+
 	// An empty modelUUID means that the user has logged in through the
 	// root of the API server rather than the /model/:model-uuid/api
 	// path, logins processed with v2 or later will only offer the
@@ -666,6 +672,9 @@ func (r *apiHandler) ConnectedModel() string {
 
 // HasPermission returns true if the logged in user can perform <operation> on <target>.
 func (r *apiHandler) HasPermission(operation permission.Access, target names.Tag) (bool, error) {
+	if r.apiToken != nil && !r.apiToken.Permits(operation, target) {
+		return false, nil
+	}
 	return common.HasPermission(r.state.UserPermission, r.entity.Tag(), operation, target)
 }
 
@@ -0,0 +1,65 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/generate/schemagen/gen"
+)
+
+// schemaHandler serves the JSON schema of the methods of every version
+// of every facade known to the controller, derived by reflection over
+// the facade types. The format is that of the facade schema generated
+// by "make rebuild-schema", so that clients may be generated from it
+// and the schemas of different releases compared.
+type schemaHandler struct {
+	registry *facade.Registry
+
+	once   sync.Once
+	schema []byte
+	err    error
+}
+
+// ServeHTTP is part of the http.Handler interface.
+func (h *schemaHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// The registry doesn't change once the server is running,
+	// so the schema only needs to be generated once.
+	h.once.Do(func() {
+		var result []gen.FacadeSchema
+		result, h.err = gen.Generate(registryAPIServer{h.registry}, gen.WithAllVersions())
+		if h.err == nil {
+			h.schema, h.err = json.Marshal(result)
+		}
+	})
+	if h.err != nil {
+		if err := sendError(w, errors.Annotate(h.err, "cannot generate facade schema")); err != nil {
+			logger.Errorf("%v", err)
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", fmt.Sprint(len(h.schema)))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(h.schema); err != nil {
+		logger.Errorf("cannot write facade schema: %v", err)
+	}
+}
+
+// registryAPIServer adapts a facade registry for use by the
+// schema generator.
+type registryAPIServer struct {
+	registry *facade.Registry
+}
+
+// AllFacades is part of the gen.APIServer interface.
+func (s registryAPIServer) AllFacades() gen.Registry {
+	return s.registry
+}
@@ -0,0 +1,95 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver"
+	"github.com/juju/juju/apiserver/params"
+	apitesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+type schemaSuite struct {
+	apiserverBaseSuite
+}
+
+var _ = gc.Suite(&schemaSuite{})
+
+func (s *schemaSuite) schemaURI() string {
+	return s.URL("/schema", nil).String()
+}
+
+func (s *schemaSuite) TestSchema(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.schemaURI(),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, "application/json")
+	var schemas []params.FacadeSchema
+	err := json.Unmarshal(body, &schemas)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Every version of every facade is included.
+	versions := make(map[string][]int)
+	for _, schema := range schemas {
+		versions[schema.Name] = append(versions[schema.Name], schema.Version)
+		c.Check(schema.Schema, gc.Not(gc.HasLen), 0)
+	}
+	for _, desc := range apiserver.AllFacades().List() {
+		c.Check(versions[desc.Name], jc.DeepEquals, desc.Versions, gc.Commentf("facade %q", desc.Name))
+	}
+}
+
+func (s *schemaSuite) TestSchemaMethods(c *gc.C) {
+	resp := s.sendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.schemaURI(),
+	})
+	body := apitesting.AssertResponse(c, resp, http.StatusOK, "application/json")
+	var schemas []struct {
+		Name    string
+		Version int
+		Schema  struct {
+			Properties map[string]interface{} `json:"properties"`
+		}
+	}
+	err := json.Unmarshal(body, &schemas)
+	c.Assert(err, jc.ErrorIsNil)
+	for _, schema := range schemas {
+		if schema.Name == "Client" && schema.Version == 2 {
+			_, ok := schema.Schema.Properties["FullStatus"]
+			c.Assert(ok, jc.IsTrue)
+			return
+		}
+	}
+	c.Fatalf("no schema found for Client facade version 2")
+}
+
+func (s *schemaSuite) TestRequiresAuth(c *gc.C) {
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method: "GET",
+		URL:    s.schemaURI(),
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusUnauthorized)
+}
+
+func (s *schemaSuite) TestRequiresUser(c *gc.C) {
+	machine, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "fake_nonce",
+	})
+	resp := apitesting.SendHTTPRequest(c, apitesting.HTTPRequestParams{
+		Method:   "GET",
+		URL:      s.schemaURI(),
+		Tag:      machine.Tag().String(),
+		Password: password,
+		Nonce:    "fake_nonce",
+	})
+	c.Assert(resp.StatusCode, gc.Equals, http.StatusForbidden)
+}
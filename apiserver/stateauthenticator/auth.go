@@ -103,6 +103,10 @@ func (a *Authenticator) AuthenticateLoginRequest(
 	modelUUID string,
 	req params.LoginRequest,
 ) (httpcontext.AuthInfo, error) {
+	if req.Token != "" {
+		return a.authenticateAPIToken(modelUUID, req.Token)
+	}
+
 	var authTag names.Tag
 	if req.AuthTag != "" {
 		tag, err := names.ParseTag(req.AuthTag)
@@ -171,7 +175,54 @@ func (a *Authenticator) checkCreds(
 	if entity, ok := entity.(withIsManager); ok {
 		authInfo.Controller = entity.IsManager()
 	}
+	if err := updateLastLogin(entity, &authInfo); err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	return authInfo, nil
+}
+
+// authenticateAPIToken authenticates a login made with an API token. The
+// token is looked up in the controller, and the login is made as the
+// user owning the token, restricted to the token's scope.
+func (a *Authenticator) authenticateAPIToken(modelUUID, secret string) (httpcontext.AuthInfo, error) {
+	token, err := a.statePool.SystemState().AuthenticateAPIToken(secret, modelUUID)
+	if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+
+	st, err := a.statePool.Get(modelUUID)
+	if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	defer st.Release()
+
+	entity, err := modelUserEntityFinder{st.State}.FindEntity(token.Owner())
+	if errors.IsNotFound(err) {
+		return httpcontext.AuthInfo{}, errors.NewUnauthorized(common.ErrBadCreds, "")
+	} else if err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	if user, ok := entity.(*modelUserEntity); ok && user.user != nil && user.user.IsDisabled() {
+		return httpcontext.AuthInfo{}, errors.NewUnauthorized(common.ErrBadCreds, "")
+	}
+
+	authInfo := httpcontext.AuthInfo{
+		Entity: entity,
+		APIToken: &httpcontext.APITokenInfo{
+			ID:        token.Id(),
+			ModelUUID: modelUUID,
+			Access:    token.Access(),
+		},
+	}
+	if err := updateLastLogin(entity, &authInfo); err != nil {
+		return httpcontext.AuthInfo{}, errors.Trace(err)
+	}
+	return authInfo, nil
+}
 
+// updateLastLogin records the previous login time of the entity, if it
+// tracks one, in authInfo and then updates it to now.
+func updateLastLogin(entity state.Entity, authInfo *httpcontext.AuthInfo) error {
 	type withLastLogin interface {
 		LastLogin() (time.Time, error)
 		UpdateLastLogin() error
@@ -181,14 +232,14 @@ func (a *Authenticator) checkCreds(
 		if err == nil {
 			authInfo.LastConnection = lastLogin
 		} else if !state.IsNeverLoggedInError(err) {
-			return httpcontext.AuthInfo{}, errors.Trace(err)
+			return errors.Trace(err)
 		}
 		// TODO log or return error returned by
 		// UpdateLastLogin? Old code didn't do
 		// anything with it.
 		entity.UpdateLastLogin()
 	}
-	return authInfo, nil
+	return nil
 }
 
 // LoginRequest extracts basic auth login details from an http.Request.
@@ -0,0 +1,91 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/juju/rpcreflect"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/rpc"
+)
+
+// untracedFacades holds the names of facades whose methods are not
+// recorded as trace spans. Pings are frequent and uninteresting.
+var untracedFacades = map[string]bool{
+	"Pinger": true,
+}
+
+// traceRoot wraps the provided root so that every API request made
+// through it is recorded as a span by the given tracer. If the caller
+// propagated its own trace context with the request, the span continues
+// that trace. Facade methods that take a context.Context are passed one
+// holding the span, so that the work they do can be attributed to it.
+func traceRoot(root rpc.Root, tracer trace.Tracer, authTag names.Tag, modelUUID string) rpc.Root {
+	attrs := []trace.Attribute{
+		trace.StringAttr("rpc.system", "juju"),
+	}
+	if authTag != nil {
+		attrs = append(attrs, trace.StringAttr("juju.entity", authTag.String()))
+	}
+	if modelUUID != "" {
+		attrs = append(attrs, trace.StringAttr("juju.model-uuid", modelUUID))
+	}
+	return &tracedRoot{
+		Root:   root,
+		tracer: tracer,
+		attrs:  attrs,
+	}
+}
+
+type tracedRoot struct {
+	rpc.Root
+	tracer trace.Tracer
+	attrs  []trace.Attribute
+}
+
+// FindMethod implements rpc.Root.
+func (r *tracedRoot) FindMethod(facadeName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.Root.FindMethod(facadeName, version, methodName)
+	if err != nil || untracedFacades[facadeName] {
+		return caller, err
+	}
+	attrs := make([]trace.Attribute, len(r.attrs), len(r.attrs)+3)
+	copy(attrs, r.attrs)
+	attrs = append(attrs,
+		trace.StringAttr("rpc.service", facadeName),
+		trace.StringAttr("rpc.method", methodName),
+		trace.StringAttr("juju.facade-version", strconv.Itoa(version)),
+	)
+	return &tracedMethodCaller{
+		MethodCaller: caller,
+		tracer:       r.tracer,
+		name:         fmt.Sprintf("%s(%d).%s", facadeName, version, methodName),
+		attrs:        attrs,
+	}, nil
+}
+
+type tracedMethodCaller struct {
+	rpcreflect.MethodCaller
+	tracer trace.Tracer
+	name   string
+	attrs  []trace.Attribute
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *tracedMethodCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	ctx, span := c.tracer.Start(ctx, c.name, trace.SpanKindServer, c.attrs...)
+	defer span.End()
+	if objId != "" {
+		span.SetAttributes(trace.StringAttr("juju.object-id", objId))
+	}
+	rv, err := c.MethodCaller.Call(ctx, objId, arg)
+	span.RecordError(err)
+	return rv, err
+}
@@ -0,0 +1,97 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/rpcreflect"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/core/trace"
+	"github.com/juju/juju/rpc"
+)
+
+type tracingSuite struct {
+	testing.IsolationSuite
+
+	exporter *trace.MemoryExporter
+	tracer   trace.Tracer
+}
+
+var _ = gc.Suite(&tracingSuite{})
+
+func (s *tracingSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.exporter = &trace.MemoryExporter{}
+	s.tracer = trace.NewTracer(s.exporter, testclock.NewClock(time.Time{}))
+}
+
+func (s *tracingSuite) TestTraceRootRecordsSpan(c *gc.C) {
+	caller := &recordingCaller{err: errors.New("boom")}
+	root := traceRoot(&tracingFakeRoot{caller: caller}, s.tracer, names.NewUserTag("bob"), limiterModelUUID)
+
+	method, err := root.FindMethod("Application", 10, "Deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := trace.ContextWithTraceParent(context.Background(), traceParent)
+	_, err = method.Call(ctx, "", reflect.Value{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+
+	spans := s.exporter.Spans()
+	c.Assert(spans, gc.HasLen, 1)
+	span := spans[0]
+	c.Check(span.Name, gc.Equals, "Application(10).Deploy")
+	c.Check(span.Kind, gc.Equals, trace.SpanKindServer)
+	c.Check(span.SpanContext.TraceID.String(), gc.Equals, "4bf92f3577b34da6a3ce929d0e0e4736")
+	c.Check(span.ParentSpanID.String(), gc.Equals, "00f067aa0ba902b7")
+	c.Check(span.Error, gc.Equals, "boom")
+	c.Check(span.Attributes, jc.DeepEquals, []trace.Attribute{
+		{Key: "rpc.system", Value: "juju"},
+		{Key: "juju.entity", Value: "user-bob"},
+		{Key: "juju.model-uuid", Value: limiterModelUUID},
+		{Key: "rpc.service", Value: "Application"},
+		{Key: "rpc.method", Value: "Deploy"},
+		{Key: "juju.facade-version", Value: "10"},
+	})
+
+	// The facade method is called with the span's context.
+	c.Check(trace.TraceParentFromContext(caller.ctx), gc.Equals, span.SpanContext.TraceParent())
+}
+
+func (s *tracingSuite) TestTraceRootSkipsPings(c *gc.C) {
+	root := traceRoot(&tracingFakeRoot{caller: &recordingCaller{}}, s.tracer, names.NewUserTag("bob"), "")
+	method, err := root.FindMethod("Pinger", 1, "Ping")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = method.Call(context.Background(), "", reflect.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.exporter.Spans(), gc.HasLen, 0)
+}
+
+type tracingFakeRoot struct {
+	rpc.Root
+	caller rpcreflect.MethodCaller
+}
+
+func (r *tracingFakeRoot) FindMethod(string, int, string) (rpcreflect.MethodCaller, error) {
+	return r.caller, nil
+}
+
+type recordingCaller struct {
+	rpcreflect.MethodCaller
+	ctx context.Context
+	err error
+}
+
+func (c *recordingCaller) Call(ctx context.Context, objId string, arg reflect.Value) (reflect.Value, error) {
+	c.ctx = ctx
+	return reflect.Value{}, c.err
+}
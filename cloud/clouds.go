@@ -72,6 +72,10 @@ const (
 	// https://tools.ietf.org/html/draft-cavage-http-signatures-06
 	HTTPSigAuthType AuthType = "httpsig"
 
+	// SSHKeyAuthType is an authentication type using an SSH private key,
+	// e.g. for libvirt hosts reached over qemu+ssh.
+	SSHKeyAuthType AuthType = "ssh-key"
+
 	// InteractiveAuthType is a credential auth-type provided as an option to
 	// "juju add-credential", which takes the user through the process of
 	// adding credentials.  e.g. for lxd: generating a certificate credential.
@@ -5,6 +5,7 @@ package application
 
 import (
 	"archive/zip"
+	"context"
 	"io/ioutil"
 	"os"
 	"sort"
@@ -47,6 +48,7 @@ import (
 	"github.com/juju/juju/core/devices"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/resource/resourceadapters"
 	"github.com/juju/juju/storage"
@@ -255,7 +257,11 @@ func (a *deployAPIAdapter) Deploy(args application.DeployArgs) error {
 		args.Placement[i] = p
 	}
 
-	return errors.Trace(a.applicationClient.Deploy(args))
+	// Each deployment is made as part of a new trace, so that the
+	// controller's handling of it can be found in the trace collector.
+	ctx := trace.ContextWithNewTrace(context.Background())
+	logger.Debugf("deploying %q in trace %s", args.ApplicationName, trace.TraceParentFromContext(ctx))
+	return errors.Trace(a.applicationClient.DeployContext(ctx, args))
 }
 
 func (a *deployAPIAdapter) Resolve(cfg *config.Config, url *charm.URL, preferredChannel params.Channel) (
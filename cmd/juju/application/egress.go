@@ -0,0 +1,193 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+var usageRestrictEgressSummary = `
+Restricts the outbound network traffic of an application.`[1:]
+
+var usageRestrictEgressDetails = `
+Adjusts the firewall rules of the machines hosting the application's
+units so that new outbound connections may only be made to the given
+destinations. Destinations may be given as CIDRs, as spaces (all of
+whose subnets may be reached) or as applications (whose units'
+machines may be reached). The controller can always be reached.
+
+In models whose firewall-mode is "instance", the restriction is applied
+with security groups on EC2 and OpenStack, and with iptables on OCI,
+vSphere and Rackspace. Otherwise, including on containers and manual
+machines, the machine agent applies it with iptables. Only IPv4
+destinations may be given; IPv6 traffic is not restricted. Machines
+hosting units of other, unrestricted, applications are left
+unrestricted.
+
+Running the command again replaces the existing restriction.
+
+Examples:
+    juju restrict-egress wordpress --to-cidrs 10.0.0.0/8,192.168.1.0/24
+    juju restrict-egress wordpress --to-spaces internal --to-applications mysql
+
+See also:
+    unrestrict-egress
+    expose`[1:]
+
+var usageUnrestrictEgressSummary = `
+Removes the outbound network traffic restriction of an application.`[1:]
+
+var usageUnrestrictEgressDetails = `
+Removes any restriction placed on the outbound network traffic of the
+application's units by restrict-egress.
+
+Examples:
+    juju unrestrict-egress wordpress
+
+See also:
+    restrict-egress`[1:]
+
+type egressAPI interface {
+	Close() error
+	SetEgressPolicy(application string, policy params.EgressPolicy) error
+	ClearEgressPolicy(application string) error
+}
+
+// egressCommandBase holds the functionality shared by the
+// restrict-egress and unrestrict-egress commands.
+type egressCommandBase struct {
+	modelcmd.ModelCommandBase
+	modelcmd.IAASOnlyCommand
+
+	newAPIFunc      func() (egressAPI, error)
+	applicationName string
+}
+
+func (c *egressCommandBase) initApplication(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no application name specified")
+	}
+	c.applicationName = args[0]
+	if !names.IsValidApplication(c.applicationName) {
+		return errors.Errorf("invalid application name %q", c.applicationName)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+func (c *egressCommandBase) getAPI() (egressAPI, error) {
+	if c.newAPIFunc != nil {
+		return c.newAPIFunc()
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return application.NewClient(root), nil
+}
+
+// NewRestrictEgressCommand returns a command to restrict the outbound
+// traffic of applications.
+func NewRestrictEgressCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&restrictEgressCommand{})
+}
+
+// restrictEgressCommand is responsible for restricting the outbound
+// traffic of an application.
+type restrictEgressCommand struct {
+	egressCommandBase
+
+	toCIDRs        []string
+	toSpaces       []string
+	toApplications []string
+}
+
+// Info implements cmd.Command.
+func (c *restrictEgressCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "restrict-egress",
+		Args:    "<application name>",
+		Purpose: usageRestrictEgressSummary,
+		Doc:     usageRestrictEgressDetails,
+	})
+}
+
+// SetFlags implements cmd.Command.
+func (c *restrictEgressCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.egressCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.toCIDRs), "to-cidrs", "Comma separated list of destination CIDRs")
+	f.Var(cmd.NewStringsValue(nil, &c.toSpaces), "to-spaces", "Comma separated list of destination spaces")
+	f.Var(cmd.NewStringsValue(nil, &c.toApplications), "to-applications", "Comma separated list of destination applications")
+}
+
+// Init implements cmd.Command.
+func (c *restrictEgressCommand) Init(args []string) error {
+	if err := c.initApplication(args); err != nil {
+		return errors.Trace(err)
+	}
+	if len(c.toCIDRs) == 0 && len(c.toSpaces) == 0 && len(c.toApplications) == 0 {
+		return errors.New("at least one of --to-cidrs, --to-spaces or --to-applications must be specified")
+	}
+	return nil
+}
+
+// Run implements cmd.Command.
+func (c *restrictEgressCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.SetEgressPolicy(c.applicationName, params.EgressPolicy{
+		ToCIDRs:        c.toCIDRs,
+		ToSpaces:       c.toSpaces,
+		ToApplications: c.toApplications,
+	})
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
+
+// NewUnrestrictEgressCommand returns a command to remove the outbound
+// traffic restriction of applications.
+func NewUnrestrictEgressCommand() modelcmd.ModelCommand {
+	return modelcmd.Wrap(&unrestrictEgressCommand{})
+}
+
+// unrestrictEgressCommand is responsible for removing the outbound
+// traffic restriction of an application.
+type unrestrictEgressCommand struct {
+	egressCommandBase
+}
+
+// Info implements cmd.Command.
+func (c *unrestrictEgressCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "unrestrict-egress",
+		Args:    "<application name>",
+		Purpose: usageUnrestrictEgressSummary,
+		Doc:     usageUnrestrictEgressDetails,
+	})
+}
+
+// Init implements cmd.Command.
+func (c *unrestrictEgressCommand) Init(args []string) error {
+	return c.initApplication(args)
+}
+
+// Run implements cmd.Command.
+func (c *unrestrictEgressCommand) Run(_ *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return block.ProcessBlockedError(client.ClearEgressPolicy(c.applicationName), block.BlockChange)
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package application
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+)
+
+type EgressSuite struct {
+	testing.IsolationSuite
+
+	mockAPI *mockEgressAPI
+}
+
+var _ = gc.Suite(&EgressSuite{})
+
+type mockEgressAPI struct {
+	*testing.Stub
+}
+
+func (s mockEgressAPI) Close() error {
+	s.MethodCall(s, "Close")
+	return s.NextErr()
+}
+
+func (s mockEgressAPI) SetEgressPolicy(application string, policy params.EgressPolicy) error {
+	s.MethodCall(s, "SetEgressPolicy", application, policy)
+	return s.NextErr()
+}
+
+func (s mockEgressAPI) ClearEgressPolicy(application string) error {
+	s.MethodCall(s, "ClearEgressPolicy", application)
+	return s.NextErr()
+}
+
+func (s *EgressSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.mockAPI = &mockEgressAPI{Stub: &testing.Stub{}}
+}
+
+func (s *EgressSuite) runRestrictEgress(c *gc.C, args ...string) error {
+	store := jujuclienttesting.MinimalStore()
+	_, err := cmdtesting.RunCommand(c, NewRestrictEgressCommandForTest(s.mockAPI, store), args...)
+	return err
+}
+
+func (s *EgressSuite) runUnrestrictEgress(c *gc.C, args ...string) error {
+	store := jujuclienttesting.MinimalStore()
+	_, err := cmdtesting.RunCommand(c, NewUnrestrictEgressCommandForTest(s.mockAPI, store), args...)
+	return err
+}
+
+func (s *EgressSuite) TestRestrictEgress(c *gc.C) {
+	err := s.runRestrictEgress(c, "wordpress",
+		"--to-cidrs", "10.0.0.0/8,192.168.1.0/24",
+		"--to-spaces", "internal",
+		"--to-applications", "mysql",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{{
+		"SetEgressPolicy", []interface{}{"wordpress", params.EgressPolicy{
+			ToCIDRs:        []string{"10.0.0.0/8", "192.168.1.0/24"},
+			ToSpaces:       []string{"internal"},
+			ToApplications: []string{"mysql"},
+		}},
+	}, {
+		"Close", nil,
+	}})
+}
+
+func (s *EgressSuite) TestRestrictEgressInitErrors(c *gc.C) {
+	err := s.runRestrictEgress(c)
+	c.Assert(err, gc.ErrorMatches, "no application name specified")
+
+	err = s.runRestrictEgress(c, "wordpress")
+	c.Assert(err, gc.ErrorMatches, "at least one of --to-cidrs, --to-spaces or --to-applications must be specified")
+
+	err = s.runRestrictEgress(c, "wordpress", "mysql", "--to-cidrs", "10.0.0.0/8")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["mysql"\]`)
+	s.mockAPI.CheckNoCalls(c)
+}
+
+func (s *EgressSuite) TestRestrictEgressBlocked(c *gc.C) {
+	s.mockAPI.SetErrors(&params.Error{Code: params.CodeOperationBlocked, Message: "nope"})
+	err := s.runRestrictEgress(c, "wordpress", "--to-cidrs", "10.0.0.0/8")
+	c.Assert(err.Error(), jc.Contains, `All operations that change model have been disabled for the current model.`)
+}
+
+func (s *EgressSuite) TestUnrestrictEgress(c *gc.C) {
+	err := s.runUnrestrictEgress(c, "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	s.mockAPI.CheckCalls(c, []testing.StubCall{{
+		"ClearEgressPolicy", []interface{}{"wordpress"},
+	}, {
+		"Close", nil,
+	}})
+}
@@ -333,3 +333,25 @@ func (s *JujuConnBaseSuite) SetUpTest(c *gc.C) {
 		return defaultSupportedJujuSeries
 	})
 }
+
+// NewRestrictEgressCommandForTest returns a restrict-egress command
+// with the api provided as specified.
+func NewRestrictEgressCommandForTest(api egressAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &restrictEgressCommand{}
+	cmd.newAPIFunc = func() (egressAPI, error) {
+		return api, nil
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewUnrestrictEgressCommandForTest returns an unrestrict-egress
+// command with the api provided as specified.
+func NewUnrestrictEgressCommandForTest(api egressAPI, store jujuclient.ClientStore) modelcmd.ModelCommand {
+	cmd := &unrestrictEgressCommand{}
+	cmd.newAPIFunc = func() (egressAPI, error) {
+		return api, nil
+	}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
@@ -6,8 +6,10 @@ package application
 import (
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
 
 	"github.com/juju/juju/api/application"
+	"github.com/juju/juju/apiserver/params"
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/modelcmd"
@@ -20,8 +22,19 @@ var usageExposeDetails = `
 Adjusts the firewall rules and any relevant security mechanisms of the
 cloud to allow public access to the application.
 
+Access can instead be limited to the subnets of the spaces given with
+--to-spaces and to the CIDRs given with --to-cidrs. By default these
+settings apply to all of the application's endpoints; --endpoints limits
+them to the given endpoints. Settings given for an endpoint apply to the
+ports opened for it, and to the ports opened for all endpoints. Settings
+for different endpoints are merged, and running the command again for an
+endpoint replaces its settings. Running expose without any of these
+options, or running unexpose, removes all settings.
+
 Examples:
     juju expose wordpress
+    juju expose wordpress --to-spaces internal --to-cidrs 10.0.0.0/8
+    juju expose mysql --endpoints db-admin --to-cidrs 192.168.1.0/24
 
 See also: 
     unexpose`[1:]
@@ -35,6 +48,10 @@ func NewExposeCommand() modelcmd.ModelCommand {
 type exposeCommand struct {
 	modelcmd.ModelCommandBase
 	ApplicationName string
+
+	endpoints []string
+	toSpaces  []string
+	toCIDRs   []string
 }
 
 func (c *exposeCommand) Info() *cmd.Info {
@@ -46,6 +63,13 @@ func (c *exposeCommand) Info() *cmd.Info {
 	})
 }
 
+func (c *exposeCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.endpoints), "endpoints", "Comma separated list of endpoints to expose")
+	f.Var(cmd.NewStringsValue(nil, &c.toSpaces), "to-spaces", "Comma separated list of spaces allowed to access the exposed endpoints")
+	f.Var(cmd.NewStringsValue(nil, &c.toCIDRs), "to-cidrs", "Comma separated list of CIDRs allowed to access the exposed endpoints")
+}
+
 func (c *exposeCommand) Init(args []string) error {
 	if len(args) == 0 {
 		return errors.New("no application name specified")
@@ -57,6 +81,7 @@ func (c *exposeCommand) Init(args []string) error {
 type applicationExposeAPI interface {
 	Close() error
 	Expose(applicationName string) error
+	ExposeEndpoints(applicationName string, exposedEndpoints map[string]params.ExposedEndpoint) error
 	Unexpose(applicationName string) error
 }
 
@@ -76,5 +101,26 @@ func (c *exposeCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	if len(c.endpoints) == 0 && len(c.toSpaces) == 0 && len(c.toCIDRs) == 0 {
+		return block.ProcessBlockedError(client.Expose(c.ApplicationName), block.BlockChange)
+	}
+	return block.ProcessBlockedError(client.ExposeEndpoints(c.ApplicationName, c.exposedEndpoints()), block.BlockChange)
+}
+
+// exposedEndpoints returns the expose settings given by the
+// command's flags, keyed by endpoint name.
+func (c *exposeCommand) exposedEndpoints() map[string]params.ExposedEndpoint {
+	endpoints := c.endpoints
+	if len(endpoints) == 0 {
+		// The settings apply to all endpoints.
+		endpoints = []string{""}
+	}
+	exposedEndpoints := make(map[string]params.ExposedEndpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		exposedEndpoints[endpoint] = params.ExposedEndpoint{
+			ExposeToSpaces: c.toSpaces,
+			ExposeToCIDRs:  c.toCIDRs,
+		}
+	}
+	return exposedEndpoints
 }
@@ -11,6 +11,7 @@ import (
 
 	jujutesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/state"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/testing/factory"
 )
@@ -55,6 +56,31 @@ func (s *ExposeSuite) TestExpose(c *gc.C) {
 	})
 }
 
+func (s *ExposeSuite) TestExposeToCIDRs(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
+
+	err := runExpose(c, "some-application-name", "--to-cidrs", "10.0.0.0/8,192.168.0.0/16")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertExposed(c, "some-application-name")
+
+	err = runExpose(c, "some-application-name", "--endpoints", "server", "--to-cidrs", "172.16.0.0/12")
+	c.Assert(err, jc.ErrorIsNil)
+
+	app, err := s.State.Application("some-application-name")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(app.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"":       {ExposeToCIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+		"server": {ExposeToCIDRs: []string{"172.16.0.0/12"}},
+	})
+}
+
+func (s *ExposeSuite) TestExposeToUnknownEndpoint(c *gc.C) {
+	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
+
+	err := runExpose(c, "some-application-name", "--endpoints", "bogus")
+	c.Assert(err, gc.ErrorMatches, `cannot set expose settings for application "some-application-name": endpoint "bogus" not found`)
+}
+
 func (s *ExposeSuite) TestBlockExpose(c *gc.C) {
 	s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "some-application-name"})
 
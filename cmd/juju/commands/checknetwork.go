@@ -0,0 +1,380 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	actionapi "github.com/juju/juju/api/action"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/jujuclient"
+)
+
+// probeOK is the result reported by machine agents for a successful probe.
+const probeOK = "ok"
+
+func newDefaultCheckNetworkCommand(store jujuclient.ClientStore) cmd.Command {
+	return newCheckNetworkCommand(store, time.After)
+}
+
+func newCheckNetworkCommand(store jujuclient.ClientStore, timeAfter func(time.Duration) <-chan time.Time) cmd.Command {
+	cmd := modelcmd.Wrap(&checkNetworkCommand{
+		timeAfter: timeAfter,
+	})
+	cmd.SetClientStore(store)
+	return cmd
+}
+
+// checkNetworkCommand probes the network connectivity between related units.
+type checkNetworkCommand struct {
+	modelcmd.ModelCommandBase
+	out          cmd.Output
+	applications []string
+	timeout      time.Duration
+	wait         time.Duration
+	timeAfter    func(time.Duration) <-chan time.Time
+}
+
+const checkNetworkDoc = `
+Check the network connectivity between related units. Only admin users of
+a model are able to use this command.
+
+For every relation of the given applications, or of all applications if
+none are given, the machine agent hosting each unit probes the units it is
+related to on other machines. The ingress address published by the related
+unit is sent an ICMP echo request the size of the MTU of the interface
+connected to the address's subnet, which must not be fragmented, and a TCP
+connection is made to each TCP port opened by the unit.
+
+The results are shown as a matrix of probes, making it possible to spot
+units which cannot reach each other because of misconfigured spaces,
+bridges, MTUs or firewalls. The command fails if any probe is unsuccessful.
+
+Since check-network creates actions, the probes made by a machine can
+be queried by calling "juju show-action-status --name juju-check-network".
+
+Examples:
+
+    juju check-network
+    juju check-network wordpress mysql --timeout 10s
+
+See also:
+    exec
+    spaces
+`
+
+// Info implements Command.Info.
+func (c *checkNetworkCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "check-network",
+		Args:    "[<application name> ...]",
+		Purpose: "Check the network connectivity between related units.",
+		Doc:     checkNetworkDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *checkNetworkCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCheckNetworkTabular,
+	})
+	f.DurationVar(&c.timeout, "timeout", 5*time.Second, "How long to wait for each probe to succeed")
+	f.DurationVar(&c.wait, "wait", 5*time.Minute, "How long to wait for the results of the probes")
+}
+
+// Init implements Command.Init.
+func (c *checkNetworkCommand) Init(args []string) error {
+	for _, application := range args {
+		if !names.IsValidApplication(application) {
+			return errors.NotValidf("application name %q", application)
+		}
+	}
+	c.applications = args
+	if c.timeout <= 0 {
+		return errors.NotValidf("timeout %v", c.timeout)
+	}
+	return nil
+}
+
+// CheckNetworkClient exposes the capabilities required by the CLI.
+type CheckNetworkClient interface {
+	action.APIClient
+	CheckNetwork(applications []string, timeout time.Duration) ([]params.ActionResult, error)
+}
+
+// In order to be able to easily mock out the API side for testing,
+// the API client is retrieved using a function.
+var getCheckNetworkAPIClient = func(c *checkNetworkCommand) (CheckNetworkClient, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return actionapi.NewClient(root), nil
+}
+
+// networkProbe holds the outcome of the probes of one unit made from the
+// machine hosting a unit related to it.
+type networkProbe struct {
+	Machine  string            `yaml:"machine" json:"machine"`
+	From     string            `yaml:"from,omitempty" json:"from,omitempty"`
+	To       string            `yaml:"to" json:"to"`
+	Relation string            `yaml:"relation,omitempty" json:"relation,omitempty"`
+	Address  string            `yaml:"address" json:"address"`
+	MTU      int               `yaml:"mtu" json:"mtu"`
+	ICMP     string            `yaml:"icmp" json:"icmp"`
+	Ports    map[string]string `yaml:"ports,omitempty" json:"ports,omitempty"`
+}
+
+// failed returns true if any of the probes was unsuccessful.
+func (p networkProbe) failed() bool {
+	if p.ICMP != probeOK {
+		return true
+	}
+	for _, result := range p.Ports {
+		if result != probeOK {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNetworkResult is the formatted output of check-network.
+type checkNetworkResult struct {
+	Probes []networkProbe `yaml:"probes" json:"probes"`
+
+	// Errors holds, keyed by machine id, the reasons the probes of
+	// a machine could not be made.
+	Errors map[string]string `yaml:"errors,omitempty" json:"errors,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *checkNetworkCommand) Run(ctx *cmd.Context) error {
+	client, err := getCheckNetworkAPIClient(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if client.BestAPIVersion() < 6 {
+		return errors.Errorf("check-network is not supported by this controller" +
+			"\nconsider upgrading your controller")
+	}
+
+	queued, err := client.CheckNetwork(c.applications, c.timeout)
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+
+	result := checkNetworkResult{Probes: []networkProbe{}}
+	addError := func(machineId, message string) {
+		if result.Errors == nil {
+			result.Errors = make(map[string]string)
+		}
+		result.Errors[machineId] = message
+	}
+
+	if len(queued) == 0 {
+		ctx.Infof("No related units on different machines to check.")
+		return nil
+	}
+
+	actionsToQuery := []actionQuery{}
+	for _, queuedResult := range queued {
+		if queuedResult.Error != nil {
+			fmt.Fprintf(ctx.GetStderr(), "couldn't queue one action: %v\n", queuedResult.Error)
+			continue
+		}
+		actionTag, err := names.ParseActionTag(queuedResult.Action.Tag)
+		if err != nil {
+			fmt.Fprintf(ctx.GetStderr(), "got invalid action tag %v for receiver %v\n", queuedResult.Action.Tag, queuedResult.Action.Receiver)
+			continue
+		}
+		machineTag, err := names.ParseMachineTag(queuedResult.Action.Receiver)
+		if err != nil {
+			fmt.Fprintf(ctx.GetStderr(), "got invalid machine tag %v for action %v\n", queuedResult.Action.Receiver, queuedResult.Action.Tag)
+			continue
+		}
+		actionsToQuery = append(actionsToQuery, actionQuery{
+			actionTag: actionTag,
+			receiver: actionReceiver{
+				receiverType: "MachineId",
+				tag:          machineTag,
+			}})
+	}
+	if len(actionsToQuery) == 0 {
+		return errors.New("no actions were successfully enqueued, aborting")
+	}
+
+	timeout := c.timeAfter(c.wait)
+	for len(actionsToQuery) > 0 {
+		actionResults, err := client.Actions(entities(actionsToQuery))
+		if err != nil {
+			return errors.Trace(err)
+		}
+
+		newActionsToQuery := []actionQuery{}
+		for i, actionResult := range actionResults.Results {
+			machineId := actionsToQuery[i].receiver.tag.Id()
+			switch {
+			case actionResult.Error != nil:
+				addError(machineId, actionResult.Error.Error())
+			case actionResult.Status == params.ActionRunning, actionResult.Status == params.ActionPending:
+				newActionsToQuery = append(newActionsToQuery, actionsToQuery[i])
+			case actionResult.Status != params.ActionCompleted:
+				message := actionResult.Message
+				if message == "" {
+					message = fmt.Sprintf("action %s", actionResult.Status)
+				}
+				addError(machineId, message)
+			default:
+				probes, err := parseNetworkProbes(machineId, actionResult.Output)
+				if err != nil {
+					addError(machineId, err.Error())
+					continue
+				}
+				result.Probes = append(result.Probes, probes...)
+			}
+		}
+		actionsToQuery = newActionsToQuery
+
+		if len(actionsToQuery) > 0 {
+			var timedOut bool
+			select {
+			case <-timeout:
+				timedOut = true
+			case <-c.timeAfter(1 * time.Second):
+			}
+			if timedOut {
+				break
+			}
+		}
+	}
+	for _, actionToQuery := range actionsToQuery {
+		addError(actionToQuery.receiver.tag.Id(), "timed out waiting for result")
+	}
+
+	sort.Slice(result.Probes, func(i, j int) bool {
+		pi, pj := result.Probes[i], result.Probes[j]
+		if pi.From != pj.From {
+			return pi.From < pj.From
+		}
+		if pi.To != pj.To {
+			return pi.To < pj.To
+		}
+		return pi.Relation < pj.Relation
+	})
+	if err := c.out.Write(ctx, result); err != nil {
+		return err
+	}
+
+	failed := len(result.Errors)
+	for _, probe := range result.Probes {
+		if probe.failed() {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("network check failed: %d unsuccessful probe(s)", failed)
+	}
+	return nil
+}
+
+// parseNetworkProbes converts the output of a juju-check-network action
+// run on the given machine into network probes.
+func parseNetworkProbes(machineId string, actionOutput map[string]interface{}) ([]networkProbe, error) {
+	values, ok := actionOutput["probes"].([]interface{})
+	if !ok {
+		return nil, errors.New("no probe results")
+	}
+	probes := make([]networkProbe, 0, len(values))
+	for _, value := range values {
+		value, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("unexpected probe result %v", value)
+		}
+		probe := networkProbe{Machine: machineId}
+		probe.From, _ = value["from"].(string)
+		probe.To, _ = value["to"].(string)
+		probe.Relation, _ = value["relation"].(string)
+		probe.Address, _ = value["address"].(string)
+		probe.ICMP, _ = value["icmp"].(string)
+		switch mtu := value["mtu"].(type) {
+		case float64:
+			probe.MTU = int(mtu)
+		case int:
+			probe.MTU = mtu
+		}
+		if ports, ok := value["ports"].(map[string]interface{}); ok {
+			probe.Ports = make(map[string]string, len(ports))
+			for port, result := range ports {
+				probe.Ports[port], _ = result.(string)
+			}
+		}
+		probes = append(probes, probe)
+	}
+	return probes, nil
+}
+
+func formatCheckNetworkTabular(writer io.Writer, value interface{}) error {
+	result, ok := value.(checkNetworkResult)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", result, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+
+	if len(result.Probes) > 0 {
+		w.Println("Machine", "From", "To", "Relation", "Address", "ICMP", "MTU", "Ports")
+		for _, probe := range result.Probes {
+			ports := make([]string, 0, len(probe.Ports))
+			for port := range probe.Ports {
+				ports = append(ports, port)
+			}
+			sort.Strings(ports)
+			for i, port := range ports {
+				ports[i] = port + ":" + probe.Ports[port]
+			}
+			w.Print(probe.Machine, probe.From, probe.To, probe.Relation, probe.Address)
+			if probe.ICMP == probeOK {
+				w.PrintColor(output.GoodHighlight, probe.ICMP)
+			} else {
+				w.PrintColor(output.ErrorHighlight, probe.ICMP)
+			}
+			w.Println(probe.MTU, strings.Join(ports, " "))
+		}
+	}
+
+	if len(result.Errors) > 0 {
+		if len(result.Probes) > 0 {
+			w.Println()
+		}
+		machineIds := make([]string, 0, len(result.Errors))
+		for machineId := range result.Errors {
+			machineIds = append(machineIds, machineId)
+		}
+		sort.Strings(machineIds)
+		w.Println("Machine", "Error")
+		for _, machineId := range machineIds {
+			w.Println(machineId, result.Errors[machineId])
+		}
+	}
+	return tw.Flush()
+}
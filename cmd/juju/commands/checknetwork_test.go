@@ -0,0 +1,212 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package commands
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/testing"
+)
+
+type CheckNetworkSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+
+	mock *mockCheckNetworkAPI
+}
+
+var _ = gc.Suite(&CheckNetworkSuite{})
+
+func (s *CheckNetworkSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.mock = &mockCheckNetworkAPI{
+		bestAPIVersion: 6,
+		queued: []params.ActionResult{{
+			Action: &params.Action{Tag: "action-1", Receiver: "machine-0"},
+		}, {
+			Action: &params.Action{Tag: "action-2", Receiver: "machine-1"},
+		}, {
+			Action: &params.Action{Tag: "action-3", Receiver: "machine-2"},
+		}},
+		results: map[string]params.ActionResult{
+			"action-1": {
+				Status: params.ActionCompleted,
+				Output: map[string]interface{}{
+					"probes": []interface{}{map[string]interface{}{
+						"from":     "wordpress/0",
+						"to":       "mysql/0",
+						"relation": "wordpress:db mysql:server",
+						"address":  "10.0.0.2",
+						"mtu":      float64(1500),
+						"icmp":     "ok",
+						"ports": map[string]interface{}{
+							"3306/tcp": "ok",
+							"443/tcp":  "connection refused",
+						},
+					}},
+				},
+			},
+			"action-2": {
+				Status: params.ActionCompleted,
+				Output: map[string]interface{}{
+					"probes": []interface{}{map[string]interface{}{
+						"from":     "mysql/0",
+						"to":       "wordpress/0",
+						"relation": "wordpress:db mysql:server",
+						"address":  "10.0.0.1",
+						"mtu":      float64(9000),
+						"icmp":     "message too long",
+						"ports": map[string]interface{}{
+							"80/tcp": "ok",
+						},
+					}},
+				},
+			},
+			"action-3": {
+				Status:  params.ActionFailed,
+				Message: "action failed",
+			},
+		},
+	}
+	s.PatchValue(&getCheckNetworkAPIClient, func(_ *checkNetworkCommand) (CheckNetworkClient, error) {
+		return s.mock, nil
+	})
+}
+
+func (s *CheckNetworkSuite) runCheckNetwork(c *gc.C, args ...string) (string, error) {
+	cmd := newCheckNetworkCommand(minimalStore(model.IAAS), (&mockClock{}).After)
+	ctx, err := cmdtesting.RunCommand(c, cmd, args...)
+	if ctx == nil {
+		return "", err
+	}
+	return cmdtesting.Stdout(ctx), err
+}
+
+func (s *CheckNetworkSuite) TestInit(c *gc.C) {
+	_, err := s.runCheckNetwork(c, "not/valid")
+	c.Assert(err, gc.ErrorMatches, `application name "not/valid" not valid`)
+
+	_, err = s.runCheckNetwork(c, "--timeout", "0s")
+	c.Assert(err, gc.ErrorMatches, `timeout 0s not valid`)
+}
+
+func (s *CheckNetworkSuite) TestCheckNetworkTabular(c *gc.C) {
+	out, err := s.runCheckNetwork(c, "wordpress", "--timeout", "10s")
+	c.Assert(err, gc.ErrorMatches, `network check failed: 3 unsuccessful probe\(s\)`)
+	c.Assert(s.mock.applications, jc.DeepEquals, []string{"wordpress"})
+	c.Assert(s.mock.timeout, gc.Equals, 10*time.Second)
+	c.Assert(out, gc.Equals, `
+Machine  From         To           Relation                   Address   ICMP              MTU   Ports
+1        mysql/0      wordpress/0  wordpress:db mysql:server  10.0.0.1  message too long  9000  80/tcp:ok
+0        wordpress/0  mysql/0      wordpress:db mysql:server  10.0.0.2  ok                1500  3306/tcp:ok 443/tcp:connection refused
+
+Machine  Error
+2        action failed
+`[1:])
+}
+
+func (s *CheckNetworkSuite) TestCheckNetworkYAML(c *gc.C) {
+	delete(s.mock.results, "action-3")
+	s.mock.queued = s.mock.queued[:2]
+	s.mock.results["action-1"].Output["probes"].([]interface{})[0].(map[string]interface{})["ports"] = map[string]interface{}{
+		"3306/tcp": "ok",
+	}
+	s.mock.results["action-2"].Output["probes"].([]interface{})[0].(map[string]interface{})["icmp"] = "ok"
+
+	out, err := s.runCheckNetwork(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mock.applications, gc.HasLen, 0)
+	c.Assert(s.mock.timeout, gc.Equals, 5*time.Second)
+	c.Assert(out, gc.Equals, `
+probes:
+- machine: "1"
+  from: mysql/0
+  to: wordpress/0
+  relation: wordpress:db mysql:server
+  address: 10.0.0.1
+  mtu: 9000
+  icmp: ok
+  ports:
+    80/tcp: ok
+- machine: "0"
+  from: wordpress/0
+  to: mysql/0
+  relation: wordpress:db mysql:server
+  address: 10.0.0.2
+  mtu: 1500
+  icmp: ok
+  ports:
+    3306/tcp: ok
+`[1:])
+}
+
+func (s *CheckNetworkSuite) TestCheckNetworkNothingToCheck(c *gc.C) {
+	s.mock.queued = nil
+	out, err := s.runCheckNetwork(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, "")
+}
+
+func (s *CheckNetworkSuite) TestCheckNetworkBlocked(c *gc.C) {
+	s.mock.block = true
+	_, err := s.runCheckNetwork(c)
+	testing.AssertOperationWasBlocked(c, err, ".*To enable changes.*")
+}
+
+func (s *CheckNetworkSuite) TestCheckNetworkNotSupported(c *gc.C) {
+	s.mock.bestAPIVersion = 5
+	_, err := s.runCheckNetwork(c)
+	c.Assert(err, gc.ErrorMatches, "check-network is not supported by this controller\nconsider upgrading your controller")
+}
+
+type mockCheckNetworkAPI struct {
+	action.APIClient
+
+	bestAPIVersion int
+	block          bool
+	queued         []params.ActionResult
+	results        map[string]params.ActionResult
+
+	// received values
+	applications []string
+	timeout      time.Duration
+}
+
+var _ CheckNetworkClient = (*mockCheckNetworkAPI)(nil)
+
+func (*mockCheckNetworkAPI) Close() error {
+	return nil
+}
+
+func (m *mockCheckNetworkAPI) BestAPIVersion() int {
+	return m.bestAPIVersion
+}
+
+func (m *mockCheckNetworkAPI) CheckNetwork(applications []string, timeout time.Duration) ([]params.ActionResult, error) {
+	m.applications = applications
+	m.timeout = timeout
+	if m.block {
+		return nil, common.OperationBlockedError("the operation has been blocked")
+	}
+	return m.queued, nil
+}
+
+func (m *mockCheckNetworkAPI) Actions(args params.Entities) (params.ActionResults, error) {
+	results := params.ActionResults{Results: make([]params.ActionResult, len(args.Entities))}
+	for i, entity := range args.Entities {
+		result, ok := m.results[entity.Tag]
+		if !ok {
+			result.Error = &params.Error{Message: "action not found"}
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
@@ -296,6 +296,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Error resolution and debugging commands.
 	r.Register(newDefaultRunCommand(nil))
+	r.Register(newDefaultCheckNetworkCommand(nil))
 	r.Register(newSCPCommand(nil))
 	r.Register(newSSHCommand(nil, nil))
 	r.Register(application.NewResolvedCommand())
@@ -344,6 +345,9 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(user.NewLogoutCommand())
 	r.Register(user.NewRemoveCommand())
 	r.Register(user.NewWhoAmICommand())
+	r.Register(user.NewAddAPITokenCommand())
+	r.Register(user.NewListAPITokensCommand())
+	r.Register(user.NewRevokeAPITokenCommand())
 
 	// Manage cached images
 	r.Register(cachedimages.NewRemoveCommand())
@@ -354,6 +358,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(machine.NewRemoveCommand())
 	r.Register(machine.NewListMachinesCommand())
 	r.Register(machine.NewShowMachineCommand())
+	r.Register(machine.NewShowCloudQuotasCommand())
 	r.Register(machine.NewUpgradeSeriesCommand())
 
 	// Manage model
@@ -406,6 +411,8 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(application.NewDeployCommand())
 	r.Register(application.NewExposeCommand())
 	r.Register(application.NewUnexposeCommand())
+	r.Register(application.NewRestrictEgressCommand())
+	r.Register(application.NewUnrestrictEgressCommand())
 	r.Register(application.NewApplicationGetConstraintsCommand())
 	r.Register(application.NewApplicationSetConstraintsCommand())
 	r.Register(application.NewBundleDiffCommand())
@@ -428,6 +435,12 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(storage.NewDetachStorageCommandWithAPI())
 	r.Register(storage.NewAttachStorageCommandWithAPI())
 	r.Register(storage.NewImportFilesystemCommand(storage.NewStorageImporter, nil))
+	r.Register(storage.NewCreateStorageSnapshotCommand())
+	r.Register(storage.NewListStorageSnapshotsCommand())
+	r.Register(storage.NewRemoveStorageSnapshotCommand())
+	r.Register(storage.NewRestoreStorageCommand())
+	r.Register(storage.NewResizeStorageCommand())
+	r.Register(storage.NewMoveStorageCommand())
 
 	// Manage spaces
 	r.Register(space.NewAddCommand())
@@ -457,6 +470,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(controller.NewUnregisterCommand(jujuclient.NewFileClientStore()))
 	r.Register(controller.NewEnableDestroyControllerCommand())
 	r.Register(controller.NewShowControllerCommand())
+	r.Register(controller.NewAPISchemaCommand())
 	r.Register(controller.NewConfigCommand())
 
 	// Debug Metrics
@@ -425,6 +425,7 @@ func assertNoArgs(c *gc.C, argChan <-chan []string) {
 
 var commandNames = []string{
 	"actions",
+	"add-api-token",
 	"add-cloud",
 	"add-credential",
 	"add-k8s",
@@ -439,6 +440,8 @@ var commandNames = []string{
 	"add-user",
 	"agree",
 	"agreements",
+	"api-schema",
+	"api-tokens",
 	"attach",
 	"attach-resource",
 	"attach-storage",
@@ -453,6 +456,7 @@ var commandNames = []string{
 	"change-user-password",
 	"charm",
 	"charm-resources",
+	"check-network",
 	"clouds",
 	"collect-metrics",
 	"config",
@@ -461,6 +465,7 @@ var commandNames = []string{
 	"controllers",
 	"create-backup",
 	"create-storage-pool",
+	"create-storage-snapshot",
 	"create-wallet",
 	"credentials",
 	"debug-hook",
@@ -501,6 +506,7 @@ var commandNames = []string{
 	"kill-controller",
 	"list-actions",
 	"list-agreements",
+	"list-api-tokens",
 	"list-backups",
 	"list-cached-images",
 	"list-charm-resources",
@@ -521,6 +527,7 @@ var commandNames = []string{
 	"list-ssh-keys",
 	"list-storage",
 	"list-storage-pools",
+	"list-storage-snapshots",
 	"list-subnets",
 	"list-tasks",
 	"list-users",
@@ -534,6 +541,7 @@ var commandNames = []string{
 	"model-default",
 	"model-defaults",
 	"models",
+	"move-storage",
 	"offer",
 	"offers",
 	"payloads",
@@ -556,15 +564,20 @@ var commandNames = []string{
 	"remove-ssh-key",
 	"remove-storage",
 	"remove-storage-pool",
+	"remove-storage-snapshot",
 	"remove-unit",
 	"remove-user",
+	"resize-storage",
 	"resolved",
 	"resolve",
 	"resources",
 	"restore-backup",
+	"restore-storage",
+	"restrict-egress",
 	"resume-relation",
 	"retry-provisioning",
 	"revoke",
+	"revoke-api-token",
 	"revoke-cloud",
 	"run",
 	"scale-application",
@@ -583,6 +596,7 @@ var commandNames = []string{
 	"show-application",
 	"show-backup",
 	"show-cloud",
+	"show-cloud-quotas",
 	"show-controller",
 	"show-credential",
 	"show-credentials",
@@ -603,6 +617,7 @@ var commandNames = []string{
 	"status",
 	"storage",
 	"storage-pools",
+	"storage-snapshots",
 	"subnets",
 	"suspend-relation",
 	"switch",
@@ -612,6 +627,7 @@ var commandNames = []string{
 	"trust",
 	"unexpose",
 	"unregister",
+	"unrestrict-egress",
 	"update-cloud",
 	"update-public-clouds",
 	"update-credential",
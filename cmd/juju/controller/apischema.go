@@ -0,0 +1,154 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// NewAPISchemaCommand returns a command that displays the JSON schema of
+// the API facades served by a controller.
+func NewAPISchemaCommand() cmd.Command {
+	return modelcmd.WrapController(&apiSchemaCommand{})
+}
+
+type apiSchemaCommand struct {
+	modelcmd.ControllerCommandBase
+	out cmd.Output
+	api apiSchemaAPI
+
+	facades []string
+	latest  bool
+}
+
+type apiSchemaAPI interface {
+	Close() error
+	APISchema() ([]params.FacadeSchema, error)
+}
+
+const apiSchemaDoc = `
+Displays a JSON schema describing the methods, and their parameters and
+results, of every version of every API facade served by the controller.
+The schema is derived from the facade implementations by reflection, and
+may be used to generate API clients in other languages, or compared with
+the schema of another release to find incompatible changes.
+
+The output may be restricted to particular facades with --facade, and to
+the latest version of each facade with --latest.
+
+Examples:
+
+    juju api-schema -o schema.json
+    juju api-schema --facade Client --facade Application --latest
+
+See also:
+    show-controller
+`
+
+// Info implements Command.Info.
+func (c *apiSchemaCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "api-schema",
+		Purpose: "Displays the schema of the API served by a controller.",
+		Doc:     apiSchemaDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *apiSchemaCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.Var(cmd.NewAppendStringsValue(&c.facades), "facade", "Only display the schema of the named facade (may be repeated)")
+	f.BoolVar(&c.latest, "latest", false, "Only display the schema of the latest version of each facade")
+	c.out.AddFlags(f, "json", map[string]cmd.Formatter{
+		"json": formatSchemaJSON,
+	})
+}
+
+func (c *apiSchemaCommand) getAPI() (apiSchemaAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewControllerAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *apiSchemaCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	schemas, err := client.APISchema()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(c.facades) > 0 {
+		schemas = filterSchemaFacades(schemas, c.facades)
+		if len(schemas) == 0 {
+			return errors.NotFoundf("facades %v", c.facades)
+		}
+	}
+	if c.latest {
+		schemas = latestSchemaVersions(schemas)
+	}
+	return c.out.Write(ctx, schemas)
+}
+
+// filterSchemaFacades returns the schemas of the named facades.
+func filterSchemaFacades(schemas []params.FacadeSchema, names []string) []params.FacadeSchema {
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var result []params.FacadeSchema
+	for _, schema := range schemas {
+		if wanted[schema.Name] {
+			result = append(result, schema)
+		}
+	}
+	return result
+}
+
+// latestSchemaVersions returns the schema of the latest version of each
+// facade, preserving the order in which the facades appear.
+func latestSchemaVersions(schemas []params.FacadeSchema) []params.FacadeSchema {
+	latest := make(map[string]int)
+	var order []string
+	for i, schema := range schemas {
+		j, ok := latest[schema.Name]
+		if !ok {
+			order = append(order, schema.Name)
+		}
+		if !ok || schema.Version > schemas[j].Version {
+			latest[schema.Name] = i
+		}
+	}
+	result := make([]params.FacadeSchema, len(order))
+	for i, name := range order {
+		result[i] = schemas[latest[name]]
+	}
+	return result
+}
+
+// formatSchemaJSON writes the schemas as indented JSON, in the same form
+// as the facade schema in the source tree.
+func formatSchemaJSON(writer io.Writer, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "    ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	_, err = writer.Write(data)
+	return err
+}
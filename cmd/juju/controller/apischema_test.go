@@ -0,0 +1,131 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"encoding/json"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/jujuclient"
+)
+
+type apiSchemaSuite struct {
+	baseControllerSuite
+	api   *fakeAPISchemaAPI
+	store *jujuclient.MemStore
+}
+
+var _ = gc.Suite(&apiSchemaSuite{})
+
+func (s *apiSchemaSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+
+	s.api = &fakeAPISchemaAPI{
+		schemas: []params.FacadeSchema{{
+			Name:    "Application",
+			Version: 1,
+			Schema:  json.RawMessage(`{"type":"object"}`),
+		}, {
+			Name:    "Application",
+			Version: 2,
+			Schema:  json.RawMessage(`{"type":"object","properties":{"Deploy":{}}}`),
+		}, {
+			Name:    "Client",
+			Version: 1,
+			Schema:  json.RawMessage(`{"type":"object"}`),
+		}},
+	}
+	s.store = jujuclient.NewMemStore()
+	s.store.CurrentControllerName = "fake"
+	s.store.Controllers["fake"] = jujuclient.ControllerDetails{}
+}
+
+func (s *apiSchemaSuite) newCommand() cmd.Command {
+	return controller.NewAPISchemaCommandForTest(s.api, s.store)
+}
+
+func (s *apiSchemaSuite) run(c *gc.C, args ...string) []params.FacadeSchema {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), args...)
+	c.Assert(err, jc.ErrorIsNil)
+	var schemas []params.FacadeSchema
+	err = json.Unmarshal([]byte(cmdtesting.Stdout(ctx)), &schemas)
+	c.Assert(err, jc.ErrorIsNil)
+	return schemas
+}
+
+func (s *apiSchemaSuite) TestAll(c *gc.C) {
+	schemas := s.run(c)
+	c.Assert(schemas, gc.HasLen, 3)
+	for i, schema := range schemas {
+		c.Check(schema.Name, gc.Equals, s.api.schemas[i].Name)
+		c.Check(schema.Version, gc.Equals, s.api.schemas[i].Version)
+	}
+}
+
+func (s *apiSchemaSuite) TestIndented(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, s.newCommand(), "--facade", "Client")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+[
+    {
+        "Name": "Client",
+        "Version": 1,
+        "Schema": {
+            "type": "object"
+        }
+    }
+]
+`[1:])
+}
+
+func (s *apiSchemaSuite) TestLatest(c *gc.C) {
+	schemas := s.run(c, "--latest")
+	c.Assert(schemas, gc.HasLen, 2)
+	c.Check(schemas[0].Name, gc.Equals, "Application")
+	c.Check(schemas[0].Version, gc.Equals, 2)
+	c.Check(schemas[1].Name, gc.Equals, "Client")
+	c.Check(schemas[1].Version, gc.Equals, 1)
+}
+
+func (s *apiSchemaSuite) TestFacade(c *gc.C) {
+	schemas := s.run(c, "--facade", "Application")
+	c.Assert(schemas, gc.HasLen, 2)
+	for _, schema := range schemas {
+		c.Check(schema.Name, gc.Equals, "Application")
+	}
+}
+
+func (s *apiSchemaSuite) TestUnknownFacade(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "--facade", "Nope")
+	c.Assert(err, gc.ErrorMatches, `facades \[Nope\] not found`)
+}
+
+func (s *apiSchemaSuite) TestError(c *gc.C) {
+	s.api.err = errors.New("boom")
+	_, err := cmdtesting.RunCommand(c, s.newCommand())
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *apiSchemaSuite) TestUnrecognizedArg(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, s.newCommand(), "whoops")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["whoops"\]`)
+}
+
+type fakeAPISchemaAPI struct {
+	schemas []params.FacadeSchema
+	err     error
+}
+
+func (*fakeAPISchemaAPI) Close() error { return nil }
+
+func (f *fakeAPISchemaAPI) APISchema() ([]params.FacadeSchema, error) {
+	return f.schemas, f.err
+}
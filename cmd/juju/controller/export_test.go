@@ -93,6 +93,16 @@ func NewEnableDestroyControllerCommandForTest(api removeBlocksAPI, store jujucli
 	return modelcmd.WrapController(c)
 }
 
+// NewAPISchemaCommandForTest returns an apiSchemaCommand with the
+// API mocked out.
+func NewAPISchemaCommandForTest(api apiSchemaAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &apiSchemaCommand{
+		api: api,
+	}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 // NewDestroyCommandForTest returns a DestroyCommand with the controller and
 // client endpoints mocked out.
 func NewDestroyCommandForTest(
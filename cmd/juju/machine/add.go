@@ -58,6 +58,30 @@ and bringing it under Juju's management; currently this requires that the
 machine be running Ubuntu, that it be accessible via SSH, and be running on
 the same network as the API server.
 
+Many existing machines can be manually provisioned at once by listing them
+in an inventory file, and passing it with "--inventory". The machines are
+provisioned in parallel (at most "--parallel" at a time), and the machines
+created and hosts that failed are reported when all are done. Each host
+must allow SSH login with a key, and sudo for the user, without a
+password prompt; password authentication is not attempted. The hardware
+of each host is checked against its constraints before it is added. An
+inventory file looks like this:
+
+    defaults:
+      user: admin
+      identity-file: ~/.ssh/rack1
+      constraints: mem=16G
+    hosts:
+      - host: 10.10.0.3
+      - host: 10.10.0.4
+        port: 2222
+      - host: root@10.10.0.5
+        identity-file: keys/rack2
+        constraints: mem=64G cores=16
+
+Host settings override the defaults, and hosts without constraints in the
+inventory use those given with "--constraints".
+
 It is possible to override or augment constraints by passing provider-specific
 "placement directives" as an argument; these give the provider additional
 information about how to allocate the machine. For example, one can direct the
@@ -72,6 +96,7 @@ Examples:
    juju add-machine --constraints mem=8G (starts a machine with at least 8GB RAM)
    juju add-machine ssh:user@10.10.0.3   (manually provisions machine with ssh)
    juju add-machine winrm:user@10.10.0.3 (manually provisions machine with winrm)
+   juju add-machine --inventory hosts.yaml (manually provisions the machines in hosts.yaml)
    juju add-machine zone=us-east-1a      (start a machine in zone us-east-1a on AWS)
    juju add-machine maas2.name           (acquire machine maas2.name on MAAS)
 
@@ -115,6 +140,11 @@ type addCommand struct {
 	NumMachines int
 	// Disks describes disks that are to be attached to the machine.
 	Disks []storage.Constraints
+	// Inventory is the path of a file listing hosts to manually provision.
+	Inventory string
+	// Parallel is the maximum number of hosts in the inventory to
+	// provision at once.
+	Parallel int
 }
 
 func (c *addCommand) Info() *cmd.Info {
@@ -132,6 +162,8 @@ func (c *addCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.IntVar(&c.NumMachines, "n", 1, "The number of machines to add")
 	f.StringVar(&c.ConstraintsStr, "constraints", "", "Additional machine constraints")
 	f.Var(disksFlag{&c.Disks}, "disks", "Constraints for disks to attach to the machine")
+	f.StringVar(&c.Inventory, "inventory", "", "Manually provision the hosts listed in this file")
+	f.IntVar(&c.Parallel, "parallel", 10, "The maximum number of inventory hosts to provision at once")
 }
 
 func (c *addCommand) Init(args []string) error {
@@ -153,6 +185,14 @@ func (c *addCommand) Init(args []string) error {
 	if c.NumMachines > 1 && c.Placement != nil && c.Placement.Directive != "" {
 		return errors.New("cannot use -n when specifying a placement directive")
 	}
+	if c.Inventory != "" {
+		if c.Placement != nil || c.NumMachines > 1 || len(c.Disks) > 0 {
+			return errors.New("cannot use --inventory with a placement directive, -n or --disks")
+		}
+		if c.Parallel < 1 {
+			return errors.New("--parallel must be at least 1")
+		}
+	}
 	return nil
 }
 
@@ -261,6 +301,10 @@ func (c *addCommand) Run(ctx *cmd.Context) error {
 		return errors.Trace(err)
 	}
 
+	if c.Inventory != "" {
+		return c.enlistInventory(client, config, ctx)
+	}
+
 	if c.Placement != nil {
 		err := c.tryManualProvision(client, config, ctx)
 		if err != errNonManualScope {
@@ -4,8 +4,11 @@
 package machine_test
 
 import (
+	"io/ioutil"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
@@ -16,6 +19,7 @@ import (
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/provider/dummy"
@@ -90,6 +94,18 @@ func (s *AddMachineSuite) TestInit(c *gc.C) {
 			args:      []string{"something:special"},
 			count:     1,
 			placement: "something:special",
+		}, {
+			args:  []string{"--inventory", "hosts.yaml"},
+			count: 1,
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "ssh:10.1.2.3"},
+			errorString: "cannot use --inventory with a placement directive, -n or --disks",
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "-n", "2"},
+			errorString: "cannot use --inventory with a placement directive, -n or --disks",
+		}, {
+			args:        []string{"--inventory", "hosts.yaml", "--parallel", "0"},
+			errorString: "--parallel must be at least 1",
 		},
 	} {
 		c.Logf("test %d", i)
@@ -156,6 +172,111 @@ func (s *AddMachineSuite) TestSSHPlacementError(c *gc.C) {
 	c.Assert(cmdtesting.Stderr(context), gc.Equals, "")
 }
 
+func (s *AddMachineSuite) writeInventory(c *gc.C, content string) string {
+	path := filepath.Join(c.MkDir(), "hosts.yaml")
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	return path
+}
+
+func (s *AddMachineSuite) TestInventory(c *gc.C) {
+	var mu sync.Mutex
+	provisioned := make(map[string]manual.ProvisionMachineArgs)
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		provisioned[args.Host] = args
+		return strings.TrimPrefix(args.Host, "10.1.2."), nil
+	})
+	path := s.writeInventory(c, `
+defaults:
+  user: admin
+  constraints: mem=4G
+hosts:
+  - host: 10.1.2.3
+  - host: root@10.1.2.4
+    port: 2222
+    constraints: cores=8
+`)
+
+	context, err := s.run(c, "--inventory", path, "--constraints", "arch=amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, `
+enlisting 2 hosts
+created machine 3 on 10.1.2.3
+created machine 4 on 10.1.2.4
+`[1:])
+	c.Assert(provisioned, gc.HasLen, 2)
+	c.Check(provisioned["10.1.2.3"].User, gc.Equals, "admin")
+	c.Check(provisioned["10.1.2.3"].Constraints, jc.DeepEquals, constraints.MustParse("mem=4G"))
+	c.Check(provisioned["10.1.2.4"].User, gc.Equals, "root")
+	c.Check(provisioned["10.1.2.4"].Constraints, jc.DeepEquals, constraints.MustParse("cores=8"))
+	c.Check(provisioned["10.1.2.4"].SSHOptions, gc.NotNil)
+	c.Check(provisioned["10.1.2.4"].NonInteractive, jc.IsTrue)
+}
+
+func (s *AddMachineSuite) TestInventoryDefaultConstraints(c *gc.C) {
+	var args manual.ProvisionMachineArgs
+	s.PatchValue(machine.SSHProvisioner, func(a manual.ProvisionMachineArgs) (string, error) {
+		args = a
+		return "0", nil
+	})
+	path := s.writeInventory(c, "hosts:\n  - host: 10.1.2.3\n")
+
+	_, err := s.run(c, "--inventory", path, "--constraints", "mem=8G")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(args.Constraints, jc.DeepEquals, constraints.MustParse("mem=8G"))
+}
+
+func (s *AddMachineSuite) TestInventoryFailures(c *gc.C) {
+	s.PatchValue(machine.SSHProvisioner, func(args manual.ProvisionMachineArgs) (string, error) {
+		if args.Host == "10.1.2.4" {
+			return "", errors.New("machine does not satisfy constraints: mem (1024M < 4096M)")
+		}
+		return "0", nil
+	})
+	path := s.writeInventory(c, `
+hosts:
+  - host: 10.1.2.3
+  - host: 10.1.2.4
+`)
+
+	context, err := s.run(c, "--inventory", path)
+	c.Assert(err, gc.ErrorMatches, "failed to enlist 1 of 2 hosts")
+	c.Assert(cmdtesting.Stderr(context), gc.Equals, `
+enlisting 2 hosts
+created machine 0 on 10.1.2.3
+failed to enlist:
+  10.1.2.4: machine does not satisfy constraints: mem (1024M < 4096M)
+`[1:])
+}
+
+func (s *AddMachineSuite) TestInventoryInvalid(c *gc.C) {
+	for i, test := range []struct {
+		content string
+		err     string
+	}{{
+		content: "hosts: []",
+		err:     `no hosts in inventory ".*"`,
+	}, {
+		content: "hosts:\n  - user: admin\n",
+		err:     `host 1 in inventory ".*" has no address`,
+	}, {
+		content: "hosts:\n  - host: 10.1.2.3\n  - host: 10.1.2.3\n",
+		err:     `host "10.1.2.3" appears more than once in inventory ".*"`,
+	}, {
+		content: "hosts:\n  - host: 10.1.2.3\n    constraints: mem=lots\n",
+		err:     `invalid constraints for host "10.1.2.3": .*`,
+	}, {
+		content: "hosts:\n  - host: 10.1.2.3\n    password: secret\n",
+		err:     `cannot parse inventory ".*": .*`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.run(c, "--inventory", s.writeInventory(c, test.content))
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
 func (s *AddMachineSuite) TestParamsPassedOn(c *gc.C) {
 	_, err := s.run(c, "--constraints", "mem=8G", "--series=special", "zone=nz")
 	c.Assert(err, jc.ErrorIsNil)
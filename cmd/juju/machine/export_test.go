@@ -76,3 +76,11 @@ func NewUpgradeSeriesCommandForTest(upgradeAPI UpgradeMachineSeriesAPI) cmd.Comm
 func NewDisksFlag(disks *[]storage.Constraints) *disksFlag {
 	return &disksFlag{disks}
 }
+
+// NewShowCloudQuotasCommandForTest returns a showCloudQuotasCommand with
+// the specified api.
+func NewShowCloudQuotasCommandForTest(api CloudQuotasAPI) cmd.Command {
+	command := &showCloudQuotasCommand{api: api}
+	command.SetClientStore(jujuclienttesting.MinimalStore())
+	return modelcmd.Wrap(command)
+}
@@ -0,0 +1,211 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/ssh"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/manual"
+)
+
+// inventory is the format of the file given to add-machine --inventory.
+type inventory struct {
+	// Defaults holds the settings used for hosts which do not
+	// specify their own.
+	Defaults inventoryHost `yaml:"defaults"`
+
+	// Hosts holds the hosts to enlist.
+	Hosts []inventoryHost `yaml:"hosts"`
+}
+
+// inventoryHost describes how to reach a host to enlist.
+type inventoryHost struct {
+	Host         string `yaml:"host"`
+	User         string `yaml:"user,omitempty"`
+	Port         int    `yaml:"port,omitempty"`
+	IdentityFile string `yaml:"identity-file,omitempty"`
+	Constraints  string `yaml:"constraints,omitempty"`
+
+	constraints constraints.Value
+}
+
+// readInventory reads the hosts to enlist from the inventory file
+// at path, applying the inventory's defaults to them. Relative
+// identity file paths are taken to be relative to the inventory.
+func readInventory(path string) ([]inventoryHost, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var inv inventory
+	if err := yaml.UnmarshalStrict(data, &inv); err != nil {
+		return nil, errors.Annotatef(err, "cannot parse inventory %q", path)
+	}
+	if len(inv.Hosts) == 0 {
+		return nil, errors.Errorf("no hosts in inventory %q", path)
+	}
+	seen := make(map[string]bool)
+	hosts := make([]inventoryHost, len(inv.Hosts))
+	for i, host := range inv.Hosts {
+		if host.Host == "" {
+			return nil, errors.Errorf("host %d in inventory %q has no address", i+1, path)
+		}
+		if user, address := splitUserHost(host.Host); user != "" {
+			host.User, host.Host = user, address
+		}
+		if seen[host.Host] {
+			return nil, errors.Errorf("host %q appears more than once in inventory %q", host.Host, path)
+		}
+		seen[host.Host] = true
+
+		if host.User == "" {
+			host.User = inv.Defaults.User
+		}
+		if host.Port == 0 {
+			host.Port = inv.Defaults.Port
+		}
+		if host.IdentityFile == "" {
+			host.IdentityFile = inv.Defaults.IdentityFile
+		}
+		if host.Constraints == "" {
+			host.Constraints = inv.Defaults.Constraints
+		}
+		if host.IdentityFile != "" {
+			if host.IdentityFile, err = utils.NormalizePath(host.IdentityFile); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if !filepath.IsAbs(host.IdentityFile) {
+				host.IdentityFile = filepath.Join(filepath.Dir(path), host.IdentityFile)
+			}
+		}
+		if host.constraints, err = constraints.Parse(host.Constraints); err != nil {
+			return nil, errors.Annotatef(err, "invalid constraints for host %q", host.Host)
+		}
+		hosts[i] = host
+	}
+	return hosts, nil
+}
+
+// sshOptions returns the options for SSH connections to the host.
+func (h inventoryHost) sshOptions() *ssh.Options {
+	var options ssh.Options
+	if h.Port != 0 {
+		options.SetPort(h.Port)
+	}
+	if h.IdentityFile != "" {
+		options.SetIdentities(h.IdentityFile)
+	}
+	return &options
+}
+
+// enlistResult records the outcome of enlisting a host.
+type enlistResult struct {
+	machineId string
+	err       error
+}
+
+// enlistHosts provisions the hosts, running at most parallel
+// provisioning operations at once. The results are returned in the
+// same order as the hosts.
+func enlistHosts(hosts []inventoryHost, parallel int, provision func(inventoryHost) (string, error)) []enlistResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+	results := make([]enlistResult, len(hosts))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host inventoryHost) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			machineId, err := provision(host)
+			results[i] = enlistResult{machineId: machineId, err: err}
+		}(i, host)
+	}
+	wg.Wait()
+	return results
+}
+
+// enlistInventory manually provisions each of the hosts in the
+// inventory file in parallel, then reports which were enlisted and
+// which failed.
+func (c *addCommand) enlistInventory(client AddMachineAPI, config *config.Config, ctx *cmd.Context) error {
+	hosts, err := readInventory(ctx.AbsPath(c.Inventory))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	authKeys, err := common.ReadAuthorizedKeys(ctx, "")
+	if err != nil {
+		return errors.Annotatef(err, "cannot read authorized-keys")
+	}
+
+	ctx.Infof("enlisting %d hosts", len(hosts))
+	results := enlistHosts(hosts, c.Parallel, func(host inventoryHost) (string, error) {
+		cons := host.constraints
+		if host.Constraints == "" {
+			cons = c.Constraints
+		}
+		// Hosts are enlisted concurrently, so their output
+		// cannot be interleaved on the terminal, and there is
+		// no way to answer a password prompt; SSH and sudo
+		// must not prompt at all.
+		var output bytes.Buffer
+		machineId, err := sshProvisioner(manual.ProvisionMachineArgs{
+			Host:           host.Host,
+			User:           host.User,
+			Client:         client,
+			Stdin:          &bytes.Buffer{},
+			Stdout:         &output,
+			Stderr:         &output,
+			AuthorizedKeys: authKeys,
+			SSHOptions:     host.sshOptions(),
+			NonInteractive: true,
+			Constraints:    cons,
+			UpdateBehavior: &params.UpdateBehavior{
+				EnableOSRefreshUpdate: config.EnableOSRefreshUpdate(),
+				EnableOSUpgrade:       config.EnableOSUpgrade(),
+			},
+		})
+		if err != nil {
+			logger.Debugf("enlisting %s failed, output:\n%s", host.Host, output.String())
+		}
+		return machineId, err
+	})
+
+	var failed int
+	for i, result := range results {
+		if result.err == nil {
+			ctx.Infof("created machine %v on %s", result.machineId, hosts[i].Host)
+		}
+	}
+	for i, result := range results {
+		if result.err != nil {
+			if failed == 0 {
+				fmt.Fprintln(ctx.Stderr, "failed to enlist:")
+			}
+			failed++
+			fmt.Fprintf(ctx.Stderr, "  %s: %v\n", hosts[i].Host, result.err)
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to enlist %d of %d hosts", failed, len(hosts))
+	}
+	return nil
+}
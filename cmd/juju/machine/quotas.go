@@ -0,0 +1,160 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/api/machinemanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+const showCloudQuotasCommandDoc = `
+Show the quotas of the cloud region in which a model is deployed, and
+how much of each is in use. Quotas are read live from the cloud.
+
+Machines are not started if doing so would exceed a quota; instead they
+are given a status such as "quota exceeded: vCPUs".
+
+Not all clouds can report their quotas.
+
+Examples:
+    juju show-cloud-quotas
+    juju show-cloud-quotas -m mymodel --format yaml
+
+See also:
+    add-machine
+    show-machine
+`
+
+// NewShowCloudQuotasCommand returns a command that shows the quotas of
+// the cloud region in which a model is deployed.
+func NewShowCloudQuotasCommand() cmd.Command {
+	return modelcmd.Wrap(&showCloudQuotasCommand{})
+}
+
+// CloudQuotasAPI defines the API methods for the show-cloud-quotas command.
+type CloudQuotasAPI interface {
+	CloudQuotas() (params.CloudQuotasResult, error)
+	Close() error
+}
+
+// showCloudQuotasCommand shows the quotas of a model's cloud region.
+type showCloudQuotasCommand struct {
+	baseMachinesCommand
+	api CloudQuotasAPI
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *showCloudQuotasCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "show-cloud-quotas",
+		Purpose: "Show the quotas of a model's cloud region.",
+		Doc:     showCloudQuotasCommandDoc,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *showCloudQuotasCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.baseMachinesCommand.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatCloudQuotasTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *showCloudQuotasCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *showCloudQuotasCommand) getAPI() (CloudQuotasAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return machinemanager.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *showCloudQuotasCommand) Run(ctx *cmd.Context) error {
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	result, err := client.CloudQuotas()
+	if errors.IsNotSupported(err) {
+		return errors.New("cannot show cloud quotas: not supported by this controller or cloud")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	quotas := cloudQuotas{
+		Cloud:  result.Cloud,
+		Region: result.Region,
+		Quotas: make([]cloudQuota, len(result.Quotas)),
+	}
+	for i, q := range result.Quotas {
+		available := q.Limit - q.Usage
+		if available < 0 {
+			available = 0
+		}
+		quotas.Quotas[i] = cloudQuota{
+			Name:      q.Name,
+			Limit:     q.Limit,
+			Usage:     q.Usage,
+			Available: available,
+		}
+	}
+	return c.out.Write(ctx, quotas)
+}
+
+type cloudQuotas struct {
+	Cloud  string       `yaml:"cloud" json:"cloud"`
+	Region string       `yaml:"region,omitempty" json:"region,omitempty"`
+	Quotas []cloudQuota `yaml:"quotas" json:"quotas"`
+}
+
+type cloudQuota struct {
+	Name      string  `yaml:"name" json:"name"`
+	Limit     float64 `yaml:"limit" json:"limit"`
+	Usage     float64 `yaml:"usage" json:"usage"`
+	Available float64 `yaml:"available" json:"available"`
+}
+
+func formatCloudQuotasTabular(writer io.Writer, value interface{}) error {
+	quotas, ok := value.(cloudQuotas)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", quotas, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Cloud", "Region")
+	w.Println(quotas.Cloud, quotas.Region)
+	w.Println()
+	w.Println("Quota", "Limit", "Usage", "Available")
+	for _, q := range quotas.Quotas {
+		w.Println(q.Name, formatQuantity(q.Limit), formatQuantity(q.Usage), formatQuantity(q.Available))
+	}
+	return tw.Flush()
+}
+
+func formatQuantity(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
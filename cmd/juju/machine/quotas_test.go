@@ -0,0 +1,101 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machine_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/testing"
+)
+
+type ShowCloudQuotasCommandSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+
+	api *fakeCloudQuotasAPI
+}
+
+var _ = gc.Suite(&ShowCloudQuotasCommandSuite{})
+
+func (s *ShowCloudQuotasCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = &fakeCloudQuotasAPI{
+		result: params.CloudQuotasResult{
+			Cloud:  "google",
+			Region: "us-east1",
+			Quotas: []params.CloudQuota{
+				{Name: "vCPUs", Limit: 24, Usage: 20},
+				{Name: "instances", Limit: 100, Usage: 4},
+				{Name: "disk GB", Limit: 4096, Usage: 4100.5},
+			},
+		},
+	}
+}
+
+func (s *ShowCloudQuotasCommandSuite) TestShowCloudQuotas(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowCloudQuotasCommandForTest(s.api))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Cloud   Region
+google  us-east1
+
+Quota      Limit  Usage   Available
+vCPUs      24     20      4
+instances  100    4       96
+disk GB    4096   4100.5  0
+`[1:])
+	c.Assert(s.api.closed, jc.IsTrue)
+}
+
+func (s *ShowCloudQuotasCommandSuite) TestShowCloudQuotasYAML(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, machine.NewShowCloudQuotasCommandForTest(s.api), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+cloud: google
+region: us-east1
+quotas:
+- name: vCPUs
+  limit: 24
+  usage: 20
+  available: 4
+- name: instances
+  limit: 100
+  usage: 4
+  available: 96
+- name: disk GB
+  limit: 4096
+  usage: 4100.5
+  available: 0
+`[1:])
+}
+
+func (s *ShowCloudQuotasCommandSuite) TestShowCloudQuotasNotSupported(c *gc.C) {
+	s.api.err = errors.NotSupportedf("CloudQuotas")
+	_, err := cmdtesting.RunCommand(c, machine.NewShowCloudQuotasCommandForTest(s.api))
+	c.Assert(err, gc.ErrorMatches, "cannot show cloud quotas: not supported by this controller or cloud")
+}
+
+func (s *ShowCloudQuotasCommandSuite) TestInitTooManyArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, machine.NewShowCloudQuotasCommandForTest(s.api), "foo")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["foo"\]`)
+}
+
+type fakeCloudQuotasAPI struct {
+	result params.CloudQuotasResult
+	err    error
+	closed bool
+}
+
+func (f *fakeCloudQuotasAPI) CloudQuotas() (params.CloudQuotasResult, error) {
+	return f.result, f.err
+}
+
+func (f *fakeCloudQuotasAPI) Close() error {
+	f.closed = true
+	return nil
+}
@@ -27,13 +27,14 @@ func NewRemoveCommand() cmd.Command {
 // removeCommand causes an existing machine to be destroyed.
 type removeCommand struct {
 	baseMachinesCommand
-	apiRoot      api.Connection
-	machineAPI   RemoveMachineAPI
-	MachineIds   []string
-	Force        bool
-	KeepInstance bool
-	NoWait       bool
-	fs           *gnuflag.FlagSet
+	apiRoot       api.Connection
+	machineAPI    RemoveMachineAPI
+	MachineIds    []string
+	Force         bool
+	KeepInstance  bool
+	KeepWorkloads bool
+	NoWait        bool
+	fs            *gnuflag.FlagSet
 }
 
 const destroyMachineDoc = `
@@ -43,6 +44,15 @@ output of ` + "`juju status`." + `
 It is possible to remove machine from Juju model without affecting
 the corresponding cloud instnace by using --keep-instance option.
 
+When a manually provisioned machine is removed, its machine agent
+uninstalls Juju from it: the agent services, /var/lib/juju, the
+Juju logs and the installed charms are removed, and anything which
+could not be removed is reported in /var/log/juju-uninstall.log on
+the machine. Use --keep-workloads to leave the charms of the
+machine's units, and their logs, in place. Packages installed by
+charms, and any files they wrote outside the charm directories, are
+not tracked by Juju and are left on the machine.
+
 Machines responsible for the model cannot be removed.
 
 Machines running units or containers can be removed using the '--force'
@@ -60,6 +70,7 @@ Examples:
     juju remove-machine 6 --force
     juju remove-machine 6 --force --no-wait
     juju remove-machine 7 --keep-instance
+    juju remove-machine 8 --keep-workloads
 
 See also:
     add-machine
@@ -80,6 +91,7 @@ func (c *removeCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.Force, "force", false, "Completely remove a machine and all its dependencies")
 	f.BoolVar(&c.KeepInstance, "keep-instance", false, "Do not stop the running cloud instance")
+	f.BoolVar(&c.KeepWorkloads, "keep-workloads", false, "Leave the workloads of manually provisioned machines installed")
 	f.BoolVar(&c.NoWait, "no-wait", false, "Rush through machine removal without waiting for each individual step to complete")
 	c.fs = f
 }
@@ -100,7 +112,7 @@ func (c *removeCommand) Init(args []string) error {
 type RemoveMachineAPI interface {
 	// TODO (anastasiamac 2019-4-24) From Juju 3.0 this call will be removed in favour of DestroyMachinesWithParams.
 	DestroyMachines(machines ...string) ([]params.DestroyMachineResult, error)
-	DestroyMachinesWithParams(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error)
+	DestroyMachinesWithParams(force, keep, keepWorkloads bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error)
 	Close() error
 }
 
@@ -114,7 +126,7 @@ func (a removeMachineAdapter) DestroyMachines(machines ...string) ([]params.Dest
 	return a.destroyMachines(a.Client.DestroyMachines, machines)
 }
 
-func (a removeMachineAdapter) DestroyMachinesWithParams(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+func (a removeMachineAdapter) DestroyMachinesWithParams(force, keep, keepWorkloads bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
 	return a.destroyMachines(a.Client.ForceDestroyMachines, machines)
 }
 
@@ -144,6 +156,9 @@ func (c *removeCommand) getRemoveMachineAPI() (RemoveMachineAPI, error) {
 	if root.BestFacadeVersion("MachineManager") < 4 && c.KeepInstance {
 		return nil, errors.New("this version of Juju doesn't support --keep-instance")
 	}
+	if root.BestFacadeVersion("MachineManager") < 8 && c.KeepWorkloads {
+		return nil, errors.New("this version of Juju doesn't support --keep-workloads")
+	}
 	if root.BestFacadeVersion("MachineManager") >= 3 && c.machineAPI == nil {
 		return machinemanager.NewClient(root), nil
 	}
@@ -183,8 +198,8 @@ func (c *removeCommand) Run(ctx *cmd.Context) error {
 
 	var results []params.DestroyMachineResult
 
-	if c.KeepInstance || c.Force {
-		results, err = client.DestroyMachinesWithParams(c.Force, c.KeepInstance, maxWait, c.MachineIds...)
+	if c.KeepInstance || c.KeepWorkloads || c.Force {
+		results, err = client.DestroyMachinesWithParams(c.Force, c.KeepInstance, c.KeepWorkloads, maxWait, c.MachineIds...)
 	} else {
 		results, err = client.DestroyMachines(c.MachineIds...)
 	}
@@ -151,6 +151,16 @@ func (s *RemoveMachineSuite) TestRemoveKeep(c *gc.C) {
 	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
 }
 
+func (s *RemoveMachineSuite) TestRemoveKeepWorkloads(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 8
+	_, err := s.run(c, "--keep-workloads", "1", "2")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.fake.forced, jc.IsFalse)
+	c.Assert(s.fake.keep, jc.IsFalse)
+	c.Assert(s.fake.keepWorkloads, jc.IsTrue)
+	c.Assert(s.fake.machines, jc.DeepEquals, []string{"1", "2"})
+}
+
 func (s *RemoveMachineSuite) TestBlockedError(c *gc.C) {
 	s.fake.removeError = common.OperationBlockedError("TestBlockedError")
 	_, err := s.run(c, "1")
@@ -171,12 +181,19 @@ func (s *RemoveMachineSuite) TestOldFacadeRemoveKeep(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support --keep-instance")
 }
 
+func (s *RemoveMachineSuite) TestOldFacadeRemoveKeepWorkloads(c *gc.C) {
+	s.apiConnection.bestFacadeVersion = 7
+	_, err := s.run(c, "--keep-workloads", "1")
+	c.Assert(err, gc.ErrorMatches, "this version of Juju doesn't support --keep-workloads")
+}
+
 type fakeRemoveMachineAPI struct {
-	forced      bool
-	keep        bool
-	machines    []string
-	removeError error
-	results     []params.DestroyMachineResult
+	forced        bool
+	keep          bool
+	keepWorkloads bool
+	machines      []string
+	removeError   error
+	results       []params.DestroyMachineResult
 }
 
 func (f *fakeRemoveMachineAPI) Close() error {
@@ -188,9 +205,10 @@ func (f *fakeRemoveMachineAPI) DestroyMachines(machines ...string) ([]params.Des
 	return f.destroyMachines(machines)
 }
 
-func (f *fakeRemoveMachineAPI) DestroyMachinesWithParams(force, keep bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
+func (f *fakeRemoveMachineAPI) DestroyMachinesWithParams(force, keep, keepWorkloads bool, maxWait *time.Duration, machines ...string) ([]params.DestroyMachineResult, error) {
 	f.forced = force
 	f.keep = keep
+	f.keepWorkloads = keepWorkloads
 	return f.destroyMachines(machines)
 }
 
@@ -114,27 +114,35 @@ type lxdProfileContents struct {
 }
 
 type applicationStatus struct {
-	Err              error                 `json:"-" yaml:",omitempty"`
-	Charm            string                `json:"charm" yaml:"charm"`
-	Series           string                `json:"series"`
-	OS               string                `json:"os"`
-	CharmOrigin      string                `json:"charm-origin" yaml:"charm-origin"`
-	CharmName        string                `json:"charm-name" yaml:"charm-name"`
-	CharmRev         int                   `json:"charm-rev" yaml:"charm-rev"`
-	CharmVersion     string                `json:"charm-version,omitempty" yaml:"charm-version,omitempty"`
-	CharmProfile     string                `json:"charm-profile,omitempty" yaml:"charm-profile,omitempty"`
-	CanUpgradeTo     string                `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
-	Scale            int                   `json:"scale,omitempty" yaml:"scale,omitempty"`
-	ProviderId       string                `json:"provider-id,omitempty" yaml:"provider-id,omitempty"`
-	Address          string                `json:"address,omitempty" yaml:"address,omitempty"`
-	Exposed          bool                  `json:"exposed" yaml:"exposed"`
-	Life             string                `json:"life,omitempty" yaml:"life,omitempty"`
-	StatusInfo       statusInfoContents    `json:"application-status,omitempty" yaml:"application-status"`
-	Relations        map[string][]string   `json:"relations,omitempty" yaml:"relations,omitempty"`
-	SubordinateTo    []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
-	Units            map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
-	Version          string                `json:"version,omitempty" yaml:"version,omitempty"`
-	EndpointBindings map[string]string     `json:"endpoint-bindings,omitempty" yaml:"endpoint-bindings,omitempty"`
+	Err              error                      `json:"-" yaml:",omitempty"`
+	Charm            string                     `json:"charm" yaml:"charm"`
+	Series           string                     `json:"series"`
+	OS               string                     `json:"os"`
+	CharmOrigin      string                     `json:"charm-origin" yaml:"charm-origin"`
+	CharmName        string                     `json:"charm-name" yaml:"charm-name"`
+	CharmRev         int                        `json:"charm-rev" yaml:"charm-rev"`
+	CharmVersion     string                     `json:"charm-version,omitempty" yaml:"charm-version,omitempty"`
+	CharmProfile     string                     `json:"charm-profile,omitempty" yaml:"charm-profile,omitempty"`
+	CanUpgradeTo     string                     `json:"can-upgrade-to,omitempty" yaml:"can-upgrade-to,omitempty"`
+	Scale            int                        `json:"scale,omitempty" yaml:"scale,omitempty"`
+	ProviderId       string                     `json:"provider-id,omitempty" yaml:"provider-id,omitempty"`
+	Address          string                     `json:"address,omitempty" yaml:"address,omitempty"`
+	Exposed          bool                       `json:"exposed" yaml:"exposed"`
+	ExposedEndpoints map[string]exposedEndpoint `json:"exposed-endpoints,omitempty" yaml:"exposed-endpoints,omitempty"`
+	Life             string                     `json:"life,omitempty" yaml:"life,omitempty"`
+	StatusInfo       statusInfoContents         `json:"application-status,omitempty" yaml:"application-status"`
+	Relations        map[string][]string        `json:"relations,omitempty" yaml:"relations,omitempty"`
+	SubordinateTo    []string                   `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
+	Units            map[string]unitStatus      `json:"units,omitempty" yaml:"units,omitempty"`
+	Version          string                     `json:"version,omitempty" yaml:"version,omitempty"`
+	EndpointBindings map[string]string          `json:"endpoint-bindings,omitempty" yaml:"endpoint-bindings,omitempty"`
+}
+
+// exposedEndpoint holds the networks from which an exposed
+// application endpoint may be reached.
+type exposedEndpoint struct {
+	ExposeToSpaces []string `json:"expose-to-spaces,omitempty" yaml:"expose-to-spaces,omitempty"`
+	ExposeToCIDRs  []string `json:"expose-to-cidrs,omitempty" yaml:"expose-to-cidrs,omitempty"`
 }
 
 type applicationStatusNoMarshal applicationStatus
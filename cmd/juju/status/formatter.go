@@ -280,6 +280,16 @@ func (sf *statusFormatter) formatApplication(name string, application params.App
 		EndpointBindings: application.EndpointBindings,
 	}
 
+	if len(application.ExposedEndpoints) > 0 {
+		out.ExposedEndpoints = make(map[string]exposedEndpoint, len(application.ExposedEndpoints))
+		for endpoint, exposed := range application.ExposedEndpoints {
+			out.ExposedEndpoints[endpoint] = exposedEndpoint{
+				ExposeToSpaces: exposed.ExposeToSpaces,
+				ExposeToCIDRs:  exposed.ExposeToCIDRs,
+			}
+		}
+	}
+
 	for k, m := range application.Units {
 		out.Units[k] = sf.formatUnit(unitFormatInfo{
 			unit:            m,
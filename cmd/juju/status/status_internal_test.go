@@ -3683,6 +3683,46 @@ var statusTests = []testCase{
 			},
 		},
 	),
+	test( // 28
+		"application exposed to specific networks",
+		addMachine{machineId: "0", job: state.JobManageModel},
+		setAddresses{"0", network.NewSpaceAddresses("10.0.0.1")},
+		startAliveMachine{"0", ""},
+		setMachineStatus{"0", status.Started, ""},
+		addCharm{"dummy"},
+		addApplication{name: "exposed-application", charm: "dummy"},
+		setApplicationExposeSettings{"exposed-application", map[string]state.ExposedEndpoint{
+			"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		}},
+		expect{
+			what: "exposed application with expose settings",
+			output: M{
+				"model": model,
+				"machines": M{
+					"0": machine0,
+				},
+				"applications": M{
+					"exposed-application": dummyCharm(M{
+						"application-status": M{
+							"current": "waiting",
+							"message": "waiting for machine",
+							"since":   "01 Apr 15 01:23+10:00",
+						},
+						"exposed": true,
+						"exposed-endpoints": M{
+							"": M{
+								"expose-to-cidrs": L{"10.0.0.0/8"},
+							},
+						},
+					}),
+				},
+				"storage": M{},
+				"controller": M{
+					"timestamp": "15:04:05+07:00",
+				},
+			},
+		},
+	),
 }
 
 func mysqlCharm(extras M) M {
@@ -4176,6 +4216,18 @@ func (sse setApplicationExposed) step(c *gc.C, ctx *context) {
 	}
 }
 
+type setApplicationExposeSettings struct {
+	name             string
+	exposedEndpoints map[string]state.ExposedEndpoint
+}
+
+func (sse setApplicationExposeSettings) step(c *gc.C, ctx *context) {
+	s, err := ctx.st.Application(sse.name)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.MergeExposeSettings(sse.exposedEndpoints)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setApplicationCharm struct {
 	name  string
 	charm string
@@ -0,0 +1,111 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+)
+
+// StorageMoveAPI defines the API methods that the move-storage
+// command uses.
+type StorageMoveAPI interface {
+	Close() error
+	MoveStorage(storageId, pool string) error
+}
+
+const moveStorageCommandDoc = `
+Moves a storage instance to a different storage pool. The storage ID is
+as output by "juju storage". Only storage attached to a single unit on
+a machine can be moved. Filesystem storage can be moved if it is backed
+by a single volume, which is replaced by the new volume. Storage that is
+encrypted by the machine agent cannot be moved.
+
+A new volume is provisioned in the target pool and attached to the
+unit's machine. The charm's "<name>-storage-detaching" hook is run so
+that the unit stops using the storage, after which the data is copied
+to the new volume; filesystems are unmounted during the copy. The
+storage is then reattached, and the charm's "<name>-storage-attached"
+hook is run, with the new volume in place. The original volume is
+destroyed once the copy has completed. If the copy fails, the storage
+is reattached with the original volume.
+
+Examples:
+    juju move-storage pgdata/0 --pool ebs-ssd
+`
+
+// NewMoveStorageCommand returns a command used to move storage
+// between pools.
+func NewMoveStorageCommand() cmd.Command {
+	command := &moveStorageCommand{}
+	command.newAPIFunc = func() (StorageMoveAPI, error) {
+		return command.NewStorageAPI()
+	}
+	return modelcmd.Wrap(command)
+}
+
+// moveStorageCommand moves a storage instance to another pool.
+type moveStorageCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageMoveAPI, error)
+	storageId  string
+	pool       string
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *moveStorageCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	f.StringVar(&c.pool, "pool", "", "The storage pool to move the storage to")
+}
+
+// Init implements Command.Init.
+func (c *moveStorageCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("move-storage requires a storage ID")
+	}
+	if !names.IsValidStorage(args[0]) {
+		return errors.NotValidf("storage ID %q", args[0])
+	}
+	if c.pool == "" {
+		return errors.New("--pool must be specified")
+	}
+	c.storageId = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Info implements Command.Info.
+func (c *moveStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "move-storage",
+		Purpose: "Moves storage to a different storage pool.",
+		Doc:     moveStorageCommandDoc,
+		Args:    "<storage> --pool <pool>",
+	})
+}
+
+// Run implements Command.Run.
+func (c *moveStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.MoveStorage(c.storageId, c.pool); err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "move storage")
+		}
+		return err
+	}
+	ctx.Infof("moving %s to pool %q", c.storageId, c.pool)
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/storage"
+)
+
+type MoveStorageSuite struct {
+	SubStorageSuite
+	api *mockStorageMoveAPI
+}
+
+var _ = gc.Suite(&MoveStorageSuite{})
+
+func (s *MoveStorageSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.api = &mockStorageMoveAPI{}
+}
+
+func (s *MoveStorageSuite) TestMoveStorage(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewMoveStorageCommandForTest(s.api, s.store), "data/0", "--pool", "ebs")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"MoveStorage", []interface{}{"data/0", "ebs"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "moving data/0 to pool \"ebs\"\n")
+}
+
+func (s *MoveStorageSuite) TestMoveStorageError(c *gc.C) {
+	s.api.SetErrors(errors.New(`cannot move storage data/0: storage is already in pool "ebs"`))
+	_, err := cmdtesting.RunCommand(c, storage.NewMoveStorageCommandForTest(s.api, s.store), "data/0", "--pool", "ebs")
+	c.Assert(err, gc.ErrorMatches, `cannot move storage data/0: storage is already in pool "ebs"`)
+	s.api.CheckCallNames(c, "MoveStorage", "Close")
+}
+
+func (s *MoveStorageSuite) TestMoveStorageInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"--pool", "ebs"},
+		err:  "move-storage requires a storage ID",
+	}, {
+		args: []string{"data", "--pool", "ebs"},
+		err:  `storage ID "data" not valid`,
+	}, {
+		args: []string{"data/0"},
+		err:  "--pool must be specified",
+	}, {
+		args: []string{"data/0", "extra", "--pool", "ebs"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := cmdtesting.RunCommand(c, storage.NewMoveStorageCommandForTest(s.api, s.store), test.args...)
+		c.Assert(err, gc.ErrorMatches, test.err)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+type mockStorageMoveAPI struct {
+	testing.Stub
+}
+
+func (m *mockStorageMoveAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockStorageMoveAPI) MoveStorage(storageId, pool string) error {
+	m.MethodCall(m, "MoveStorage", storageId, pool)
+	return m.NextErr()
+}
@@ -0,0 +1,84 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/storage"
+)
+
+type ResizeStorageSuite struct {
+	SubStorageSuite
+	api *mockStorageResizeAPI
+}
+
+var _ = gc.Suite(&ResizeStorageSuite{})
+
+func (s *ResizeStorageSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.api = &mockStorageResizeAPI{}
+}
+
+func (s *ResizeStorageSuite) TestResizeStorage(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewResizeStorageCommandForTest(s.api, s.store), "data/0", "2G")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"ResizeStorage", []interface{}{"data/0", uint64(2048)}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "resizing data/0 to 2048MiB\n")
+}
+
+func (s *ResizeStorageSuite) TestResizeStorageError(c *gc.C) {
+	s.api.SetErrors(errors.New("new size 1024MiB must be larger than current size 2048MiB"))
+	_, err := cmdtesting.RunCommand(c, storage.NewResizeStorageCommandForTest(s.api, s.store), "data/0", "1024")
+	c.Assert(err, gc.ErrorMatches, "new size 1024MiB must be larger than current size 2048MiB")
+	s.api.CheckCallNames(c, "ResizeStorage", "Close")
+}
+
+func (s *ResizeStorageSuite) TestResizeStorageInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"data/0"},
+		err:  "resize-storage requires a storage ID and a size",
+	}, {
+		args: []string{"data", "2G"},
+		err:  `storage ID "data" not valid`,
+	}, {
+		args: []string{"data/0", "big"},
+		err:  `cannot parse size: .*`,
+	}, {
+		args: []string{"data/0", "0"},
+		err:  "size 0 not valid",
+	}, {
+		args: []string{"data/0", "2G", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := cmdtesting.RunCommand(c, storage.NewResizeStorageCommandForTest(s.api, s.store), test.args...)
+		c.Assert(err, gc.ErrorMatches, test.err)
+	}
+	s.api.CheckNoCalls(c)
+}
+
+type mockStorageResizeAPI struct {
+	testing.Stub
+}
+
+func (m *mockStorageResizeAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockStorageResizeAPI) ResizeStorage(storageId string, size uint64) error {
+	m.MethodCall(m, "ResizeStorage", storageId, size)
+	return m.NextErr()
+}
@@ -0,0 +1,412 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// StorageSnapshotAPI defines the API methods that the storage snapshot
+// commands use.
+type StorageSnapshotAPI interface {
+	Close() error
+	CreateStorageSnapshot(storageIds []string) ([]params.StringResult, error)
+	ListStorageSnapshots(storageIds []string) ([]params.StorageSnapshotDetails, error)
+	DestroyStorageSnapshot(snapshotIds []string) ([]params.ErrorResult, error)
+	RestoreStorage(snapshotId, unitId string) (names.StorageTag, error)
+}
+
+const createStorageSnapshotCommandDoc = `
+Requests a point-in-time snapshot of each of the specified storage
+instances. Snapshots are taken asynchronously by the storage provider;
+use "juju storage-snapshots" to see when they become available.
+
+Snapshots are only supported by the loop, LXD and EBS storage
+providers; snapshots of storage from other providers fail.
+
+Examples:
+    juju create-storage-snapshot pgdata/0
+
+See also:
+    storage-snapshots
+    remove-storage-snapshot
+    restore-storage
+`
+
+// NewCreateStorageSnapshotCommand returns a command used to request
+// snapshots of storage instances.
+func NewCreateStorageSnapshotCommand() cmd.Command {
+	command := &createStorageSnapshotCommand{}
+	command.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return command.NewStorageAPI()
+	}
+	return modelcmd.Wrap(command)
+}
+
+// createStorageSnapshotCommand requests snapshots of storage instances.
+type createStorageSnapshotCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageSnapshotAPI, error)
+	storageIds []string
+}
+
+// Init implements Command.Init.
+func (c *createStorageSnapshotCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("create-storage-snapshot requires at least one storage ID")
+	}
+	c.storageIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *createStorageSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "create-storage-snapshot",
+		Purpose: "Creates snapshots of storage.",
+		Doc:     createStorageSnapshotCommandDoc,
+		Args:    "<storage> [<storage> ...]",
+	})
+}
+
+// Run implements Command.Run.
+func (c *createStorageSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.CreateStorageSnapshot(c.storageIds)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "create storage snapshots")
+		}
+		return err
+	}
+	anyFailed := false
+	for i, result := range results {
+		if result.Error != nil {
+			ctx.Infof("failed to snapshot %s: %s", c.storageIds[i], result.Error)
+			anyFailed = true
+			continue
+		}
+		ctx.Infof("snapshot %s of %s requested", result.Result, c.storageIds[i])
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+const listStorageSnapshotsCommandDoc = `
+Lists the snapshots of the specified storage instances, or of all
+storage in the model if none are specified.
+
+Examples:
+    juju storage-snapshots
+    juju storage-snapshots pgdata/0 --format yaml
+
+See also:
+    create-storage-snapshot
+    remove-storage-snapshot
+    restore-storage
+`
+
+// NewListStorageSnapshotsCommand returns a command used to list
+// storage snapshots.
+func NewListStorageSnapshotsCommand() cmd.Command {
+	command := &listStorageSnapshotsCommand{}
+	command.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return command.NewStorageAPI()
+	}
+	return modelcmd.Wrap(command)
+}
+
+// StorageSnapshotInfo defines the serialization behaviour of storage
+// snapshot information.
+type StorageSnapshotInfo struct {
+	Storage    string    `yaml:"storage" json:"storage"`
+	Kind       string    `yaml:"kind" json:"kind"`
+	Pool       string    `yaml:"pool" json:"pool"`
+	Size       uint64    `yaml:"size" json:"size"`
+	Status     string    `yaml:"status" json:"status"`
+	Life       string    `yaml:"life,omitempty" json:"life,omitempty"`
+	Message    string    `yaml:"message,omitempty" json:"message,omitempty"`
+	SnapshotId string    `yaml:"snapshot-id,omitempty" json:"snapshot-id,omitempty"`
+	Created    time.Time `yaml:"created" json:"created"`
+}
+
+// listStorageSnapshotsCommand lists storage snapshots.
+type listStorageSnapshotsCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageSnapshotAPI, error)
+	storageIds []string
+	out        cmd.Output
+}
+
+// Init implements Command.Init.
+func (c *listStorageSnapshotsCommand) Init(args []string) error {
+	c.storageIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *listStorageSnapshotsCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "storage-snapshots",
+		Purpose: "Lists storage snapshots.",
+		Doc:     listStorageSnapshotsCommandDoc,
+		Args:    "[<storage> ...]",
+		Aliases: []string{"list-storage-snapshots"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listStorageSnapshotsCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.StorageCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatStorageSnapshotsTabular,
+	})
+}
+
+// Run implements Command.Run.
+func (c *listStorageSnapshotsCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	result, err := api.ListStorageSnapshots(c.storageIds)
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		ctx.Infof("No storage snapshots to display.")
+		return nil
+	}
+	output := make(map[string]StorageSnapshotInfo)
+	for _, details := range result {
+		storageId := details.StorageTag
+		if tag, err := names.ParseStorageTag(details.StorageTag); err == nil {
+			storageId = tag.Id()
+		}
+		output[details.Id] = StorageSnapshotInfo{
+			Storage:    storageId,
+			Kind:       details.Kind.String(),
+			Pool:       details.Pool,
+			Size:       details.Size,
+			Status:     details.Status,
+			Life:       string(details.Life),
+			Message:    details.Message,
+			SnapshotId: details.SnapshotId,
+			Created:    details.Created,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
+
+// formatStorageSnapshotsTabular writes a tabular summary of storage
+// snapshots.
+func formatStorageSnapshotsTabular(writer io.Writer, value interface{}) error {
+	snapshots, ok := value.(map[string]StorageSnapshotInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", snapshots, value)
+	}
+	tw := output.TabWriter(writer)
+	print := func(values ...string) {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	print("Snapshot", "Storage", "Kind", "Pool", "Size", "Status", "Provider ID", "Message")
+	ids := make([]string, 0, len(snapshots))
+	for id := range snapshots {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		info := snapshots[id]
+		print(
+			id, info.Storage, info.Kind, info.Pool,
+			humanizeStorageSize(info.Size), info.Status,
+			info.SnapshotId, info.Message,
+		)
+	}
+	return tw.Flush()
+}
+
+const removeStorageSnapshotCommandDoc = `
+Removes the specified storage snapshots. Snapshots are deleted from the
+storage provider asynchronously, and remain listed by "juju
+storage-snapshots" until they have been deleted. Pending snapshots,
+and snapshots from which storage is still being restored, cannot be
+removed.
+
+Examples:
+    juju remove-storage-snapshot 3 0/4
+
+See also:
+    create-storage-snapshot
+    storage-snapshots
+`
+
+// NewRemoveStorageSnapshotCommand returns a command used to remove
+// storage snapshots.
+func NewRemoveStorageSnapshotCommand() cmd.Command {
+	command := &removeStorageSnapshotCommand{}
+	command.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return command.NewStorageAPI()
+	}
+	return modelcmd.Wrap(command)
+}
+
+// removeStorageSnapshotCommand removes storage snapshots.
+type removeStorageSnapshotCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc  func() (StorageSnapshotAPI, error)
+	snapshotIds []string
+}
+
+// Init implements Command.Init.
+func (c *removeStorageSnapshotCommand) Init(args []string) error {
+	if len(args) < 1 {
+		return errors.New("remove-storage-snapshot requires at least one snapshot ID")
+	}
+	c.snapshotIds = args
+	return nil
+}
+
+// Info implements Command.Info.
+func (c *removeStorageSnapshotCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "remove-storage-snapshot",
+		Purpose: "Removes storage snapshots.",
+		Doc:     removeStorageSnapshotCommandDoc,
+		Args:    "<snapshot> [<snapshot> ...]",
+	})
+}
+
+// Run implements Command.Run.
+func (c *removeStorageSnapshotCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	results, err := api.DestroyStorageSnapshot(c.snapshotIds)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "remove storage snapshots")
+		}
+		return err
+	}
+	anyFailed := false
+	for i, result := range results {
+		if result.Error != nil {
+			ctx.Infof("failed to remove snapshot %s: %s", c.snapshotIds[i], result.Error)
+			anyFailed = true
+			continue
+		}
+		ctx.Infof("removing snapshot %s", c.snapshotIds[i])
+	}
+	if anyFailed {
+		return cmd.ErrSilent
+	}
+	return nil
+}
+
+const restoreStorageCommandDoc = `
+Creates new storage from a storage snapshot, and attaches it to the
+specified unit. The snapshot ID is as output by "juju storage-snapshots".
+The storage from which the snapshot was taken is not affected.
+
+Examples:
+    juju restore-storage 3 postgresql/1
+
+See also:
+    create-storage-snapshot
+    storage-snapshots
+`
+
+// NewRestoreStorageCommand returns a command used to restore storage
+// from a snapshot.
+func NewRestoreStorageCommand() cmd.Command {
+	command := &restoreStorageCommand{}
+	command.newAPIFunc = func() (StorageSnapshotAPI, error) {
+		return command.NewStorageAPI()
+	}
+	return modelcmd.Wrap(command)
+}
+
+// restoreStorageCommand restores storage from a snapshot.
+type restoreStorageCommand struct {
+	StorageCommandBase
+	modelcmd.IAASOnlyCommand
+	newAPIFunc func() (StorageSnapshotAPI, error)
+	snapshotId string
+	unitId     string
+}
+
+// Init implements Command.Init.
+func (c *restoreStorageCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return errors.New("restore-storage requires a snapshot ID and a unit ID")
+	}
+	if !names.IsValidUnit(args[1]) {
+		return errors.NotValidf("unit name %q", args[1])
+	}
+	c.snapshotId = args[0]
+	c.unitId = args[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// Info implements Command.Info.
+func (c *restoreStorageCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "restore-storage",
+		Purpose: "Restores storage from a snapshot to a unit.",
+		Doc:     restoreStorageCommandDoc,
+		Args:    "<snapshot> <unit>",
+	})
+}
+
+// Run implements Command.Run.
+func (c *restoreStorageCommand) Run(ctx *cmd.Context) error {
+	api, err := c.newAPIFunc()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	storageTag, err := api.RestoreStorage(c.snapshotId, c.unitId)
+	if err != nil {
+		if params.IsCodeUnauthorized(err) {
+			common.PermissionsMessage(ctx.Stderr, "restore storage")
+		}
+		return err
+	}
+	ctx.Infof("restored snapshot %s as %s", c.snapshotId, storageTag.Id())
+	return nil
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/core/life"
+)
+
+type StorageSnapshotSuite struct {
+	SubStorageSuite
+	api *mockStorageSnapshotAPI
+}
+
+var _ = gc.Suite(&StorageSnapshotSuite{})
+
+func (s *StorageSnapshotSuite) SetUpTest(c *gc.C) {
+	s.SubStorageSuite.SetUpTest(c)
+	s.api = &mockStorageSnapshotAPI{}
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshot(c *gc.C) {
+	s.api.createResults = []params.StringResult{{Result: "0"}, {Result: "1"}}
+	ctx, err := cmdtesting.RunCommand(c, storage.NewCreateStorageSnapshotCommandForTest(s.api, s.store), "data/0", "logs/1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"CreateStorageSnapshot", []interface{}{[]string{"data/0", "logs/1"}}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+snapshot 0 of data/0 requested
+snapshot 1 of logs/1 requested
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotError(c *gc.C) {
+	s.api.createResults = []params.StringResult{
+		{Error: &params.Error{Message: "snapshots of \"rootfs\" filesystems not supported"}},
+	}
+	ctx, err := cmdtesting.RunCommand(c, storage.NewCreateStorageSnapshotCommandForTest(s.api, s.store), "data/0")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+failed to snapshot data/0: snapshots of "rootfs" filesystems not supported
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotNoArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewCreateStorageSnapshotCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "create-storage-snapshot requires at least one storage ID")
+}
+
+func (s *StorageSnapshotSuite) TestListStorageSnapshotsTabular(c *gc.C) {
+	s.api.listResults = []params.StorageSnapshotDetails{{
+		Id:         "1",
+		StorageTag: "storage-data-0",
+		Kind:       params.StorageKindBlock,
+		Pool:       "loop",
+		Size:       1024,
+		Status:     "pending",
+	}, {
+		Id:         "0",
+		StorageTag: "storage-data-0",
+		Kind:       params.StorageKindBlock,
+		Pool:       "loop",
+		Size:       1024,
+		Status:     "available",
+		SnapshotId: "loop0-snap-0",
+		Created:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListStorageSnapshotsCommandForTest(s.api, s.store), "data/0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCall(c, 0, "ListStorageSnapshots", []string{"data/0"})
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+Snapshot  Storage  Kind   Pool  Size    Status     Provider ID   Message
+0         data/0   block  loop  1.0GiB  available  loop0-snap-0  
+1         data/0   block  loop  1.0GiB  pending                  
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestListStorageSnapshotsNone(c *gc.C) {
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListStorageSnapshotsCommandForTest(s.api, s.store))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "No storage snapshots to display.\n")
+	s.api.CheckCall(c, 0, "ListStorageSnapshots", []string(nil))
+}
+
+func (s *StorageSnapshotSuite) TestListStorageSnapshotsYAML(c *gc.C) {
+	s.api.listResults = []params.StorageSnapshotDetails{{
+		Id:         "0",
+		StorageTag: "storage-data-0",
+		Kind:       params.StorageKindBlock,
+		Pool:       "loop",
+		Size:       1024,
+		Status:     "available",
+		Life:       life.Dying,
+		SnapshotId: "loop0-snap-0",
+		Created:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}
+	ctx, err := cmdtesting.RunCommand(c, storage.NewListStorageSnapshotsCommandForTest(s.api, s.store), "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+"0":
+  storage: data/0
+  kind: block
+  pool: loop
+  size: 1024
+  status: available
+  life: dying
+  snapshot-id: loop0-snap-0
+  created: 2020-01-01T00:00:00Z
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestRemoveStorageSnapshot(c *gc.C) {
+	s.api.destroyResults = []params.ErrorResult{
+		{},
+		{Error: &params.Error{Message: "snapshot is pending"}},
+	}
+	ctx, err := cmdtesting.RunCommand(c, storage.NewRemoveStorageSnapshotCommandForTest(s.api, s.store), "0", "1")
+	c.Assert(err, gc.Equals, cmd.ErrSilent)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"DestroyStorageSnapshot", []interface{}{[]string{"0", "1"}}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+removing snapshot 0
+failed to remove snapshot 1: snapshot is pending
+`[1:])
+}
+
+func (s *StorageSnapshotSuite) TestRemoveStorageSnapshotNoArgs(c *gc.C) {
+	_, err := cmdtesting.RunCommand(c, storage.NewRemoveStorageSnapshotCommandForTest(s.api, s.store))
+	c.Assert(err, gc.ErrorMatches, "remove-storage-snapshot requires at least one snapshot ID")
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorage(c *gc.C) {
+	s.api.restoreResult = names.NewStorageTag("data/1")
+	ctx, err := cmdtesting.RunCommand(c, storage.NewRestoreStorageCommandForTest(s.api, s.store), "0", "postgresql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []testing.StubCall{
+		{"RestoreStorage", []interface{}{"0", "postgresql/1"}},
+		{"Close", nil},
+	})
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "restored snapshot 0 as data/1\n")
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorageInitErrors(c *gc.C) {
+	for _, t := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"0"},
+		err:  "restore-storage requires a snapshot ID and a unit ID",
+	}, {
+		args: []string{"0", "postgresql"},
+		err:  `unit name "postgresql" not valid`,
+	}, {
+		args: []string{"0", "postgresql/1", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		_, err := cmdtesting.RunCommand(c, storage.NewRestoreStorageCommandForTest(s.api, s.store), t.args...)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorageError(c *gc.C) {
+	s.api.SetErrors(&params.Error{Code: params.CodeUnauthorized, Message: "nope"})
+	ctx, err := cmdtesting.RunCommand(c, storage.NewRestoreStorageCommandForTest(s.api, s.store), "0", "postgresql/1")
+	c.Assert(err, gc.ErrorMatches, "nope")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, `
+You do not have permission to restore storage.
+You may ask an administrator to grant you access with "juju grant".
+
+`[1:])
+}
+
+type mockStorageSnapshotAPI struct {
+	testing.Stub
+	createResults  []params.StringResult
+	listResults    []params.StorageSnapshotDetails
+	destroyResults []params.ErrorResult
+	restoreResult  names.StorageTag
+}
+
+func (m *mockStorageSnapshotAPI) Close() error {
+	m.MethodCall(m, "Close")
+	return m.NextErr()
+}
+
+func (m *mockStorageSnapshotAPI) CreateStorageSnapshot(storageIds []string) ([]params.StringResult, error) {
+	m.MethodCall(m, "CreateStorageSnapshot", storageIds)
+	return m.createResults, m.NextErr()
+}
+
+func (m *mockStorageSnapshotAPI) ListStorageSnapshots(storageIds []string) ([]params.StorageSnapshotDetails, error) {
+	m.MethodCall(m, "ListStorageSnapshots", storageIds)
+	return m.listResults, m.NextErr()
+}
+
+func (m *mockStorageSnapshotAPI) DestroyStorageSnapshot(snapshotIds []string) ([]params.ErrorResult, error) {
+	m.MethodCall(m, "DestroyStorageSnapshot", snapshotIds)
+	return m.destroyResults, m.NextErr()
+}
+
+func (m *mockStorageSnapshotAPI) RestoreStorage(snapshotId, unitId string) (names.StorageTag, error) {
+	m.MethodCall(m, "RestoreStorage", snapshotId, unitId)
+	return m.restoreResult, m.NextErr()
+}
@@ -0,0 +1,416 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/permission"
+)
+
+const defaultAPITokenExpiry = 24 * time.Hour
+
+var usageAddAPITokenSummary = `
+Creates an API token for logging in to models without a password.`[1:]
+
+var usageAddAPITokenDetails = `
+An API token lets automation, such as a CI pipeline, log in to one or more
+models as the given user without needing the user's password or an
+interactive login. The token is scoped to the specified models, grants at
+most the specified model access (read, write or admin), and expires after
+the given duration. The user never gets more access through the token than
+they have been granted on the model.
+
+Only a controller superuser may create tokens for other users.
+
+The token secret is printed once, and cannot be retrieved again; store it
+somewhere safe. Tokens may be revoked with the revoke-api-token command.
+
+Examples:
+    juju add-api-token ci-bot --model staging --access write
+    juju add-api-token ci-bot --model staging,production --expires 720h \
+        --description "nightly deploy"
+
+See also:
+    api-tokens
+    revoke-api-token
+    add-user`[1:]
+
+var usageListAPITokensSummary = `
+Lists the API tokens owned by a user.`[1:]
+
+var usageListAPITokensDetails = `
+Lists the API tokens owned by the given user, or by the current user if no
+user is specified. Token secrets are never shown.
+
+Examples:
+    juju api-tokens
+    juju api-tokens ci-bot --format yaml
+
+See also:
+    add-api-token
+    revoke-api-token`[1:]
+
+var usageRevokeAPITokenSummary = `
+Revokes an API token.`[1:]
+
+var usageRevokeAPITokenDetails = `
+Revokes the API token with the given ID, so that it can no longer be used
+to log in. Existing connections made using the token are not affected.
+
+Examples:
+    juju revoke-api-token 4f2a9c81d03b6e17
+
+See also:
+    add-api-token
+    api-tokens`[1:]
+
+// APITokenAPI defines the usermanager API methods that the API token
+// commands use.
+type APITokenAPI interface {
+	AddAPIToken(args usermanager.AddAPITokenArgs) (params.APIToken, string, error)
+	APITokens(owner string) ([]params.APIToken, error)
+	RevokeAPIToken(id string) error
+	Close() error
+}
+
+// apiTokenCommandBase is the base type for the API token commands.
+type apiTokenCommandBase struct {
+	modelcmd.ControllerCommandBase
+	api APITokenAPI
+}
+
+func (c *apiTokenCommandBase) getAPI() (APITokenAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	return c.NewUserManagerAPIClient()
+}
+
+// NewAddAPITokenCommand returns a command for creating API tokens.
+func NewAddAPITokenCommand() cmd.Command {
+	return modelcmd.WrapController(&addAPITokenCommand{})
+}
+
+// addAPITokenCommand creates an API token for a user.
+type addAPITokenCommand struct {
+	apiTokenCommandBase
+
+	User        string
+	ModelNames  []string
+	Access      string
+	Expires     time.Duration
+	Description string
+}
+
+// Info implements Command.Info.
+func (c *addAPITokenCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "add-api-token",
+		Args:    "<user name>",
+		Purpose: usageAddAPITokenSummary,
+		Doc:     usageAddAPITokenDetails,
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *addAPITokenCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.Var(cmd.NewStringsValue(nil, &c.ModelNames), "model", "Comma-separated names of the models the token may access")
+	f.StringVar(&c.Access, "access", string(permission.ReadAccess), "Maximum model access granted by the token (read, write or admin)")
+	f.DurationVar(&c.Expires, "expires", defaultAPITokenExpiry, "How long the token remains valid")
+	f.StringVar(&c.Description, "description", "", "A note describing what the token is used for")
+}
+
+// Init implements Command.Init.
+func (c *addAPITokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no username supplied")
+	}
+	c.User = args[0]
+	if !names.IsValidUser(c.User) {
+		return errors.NotValidf("user name %q", c.User)
+	}
+	if len(c.ModelNames) == 0 {
+		return errors.New("at least one model must be specified with --model")
+	}
+	for _, modelName := range c.ModelNames {
+		if jujuclient.IsQualifiedModelName(modelName) {
+			var err error
+			modelName, _, err = jujuclient.SplitModelName(modelName)
+			if err != nil {
+				return errors.Annotatef(err, "validating model name %q", modelName)
+			}
+		}
+		if !names.IsValidModelName(modelName) {
+			return errors.NotValidf("model name %q", modelName)
+		}
+	}
+	if err := permission.ValidateModelAccess(permission.Access(c.Access)); err != nil {
+		return errors.Trace(err)
+	}
+	if c.Expires <= 0 {
+		return errors.NotValidf("expiry %v", c.Expires)
+	}
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *addAPITokenCommand) Run(ctx *cmd.Context) error {
+	modelNames, err := c.qualifiedModelNames()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	models, err := c.ModelUUIDs(modelNames)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	token, secret, err := api.AddAPIToken(usermanager.AddAPITokenArgs{
+		Owner:       c.User,
+		Description: c.Description,
+		Models:      models,
+		Access:      permission.Access(c.Access),
+		Expires:     time.Now().Add(c.Expires),
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("API token %q created for user %q, expiring %s.", token.Id, c.User, token.Expires.Local().Format(time.RFC3339))
+	ctx.Infof("The token secret is shown below and cannot be retrieved again.")
+	fmt.Fprintln(ctx.Stdout, secret)
+	return nil
+}
+
+// qualifiedModelNames returns the model names given on the command line,
+// qualifying any unqualified names with the current user.
+func (c *addAPITokenCommand) qualifiedModelNames() ([]string, error) {
+	var currentUser names.UserTag
+	result := make([]string, len(c.ModelNames))
+	for i, modelName := range c.ModelNames {
+		if !jujuclient.IsQualifiedModelName(modelName) {
+			if currentUser.Id() == "" {
+				accountDetails, err := c.CurrentAccountDetails()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				currentUser = names.NewUserTag(accountDetails.User)
+			}
+			modelName = jujuclient.JoinOwnerModelName(currentUser, modelName)
+		}
+		result[i] = modelName
+	}
+	return result, nil
+}
+
+// NewListAPITokensCommand returns a command for listing API tokens.
+func NewListAPITokensCommand() cmd.Command {
+	return modelcmd.WrapController(&listAPITokensCommand{})
+}
+
+// listAPITokensCommand lists the API tokens owned by a user.
+type listAPITokensCommand struct {
+	apiTokenCommandBase
+	out cmd.Output
+
+	User string
+}
+
+// APITokenInfo holds the details of an API token for output.
+type APITokenInfo struct {
+	Id          string    `yaml:"id" json:"id"`
+	Description string    `yaml:"description,omitempty" json:"description,omitempty"`
+	Models      []string  `yaml:"models" json:"models"`
+	Access      string    `yaml:"access" json:"access"`
+	CreatedBy   string    `yaml:"created-by" json:"created-by"`
+	DateCreated time.Time `yaml:"date-created" json:"date-created"`
+	Expires     time.Time `yaml:"expires" json:"expires"`
+	Status      string    `yaml:"status" json:"status"`
+}
+
+// Info implements Command.Info.
+func (c *listAPITokensCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "api-tokens",
+		Args:    "[<user name>]",
+		Purpose: usageListAPITokensSummary,
+		Doc:     usageListAPITokensDetails,
+		Aliases: []string{"list-api-tokens"},
+	})
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *listAPITokensCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAPITokensTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *listAPITokensCommand) Init(args []string) (err error) {
+	c.User, err = cmd.ZeroOrOneArgs(args)
+	if err != nil {
+		return err
+	}
+	if c.User != "" && !names.IsValidUser(c.User) {
+		return errors.NotValidf("user name %q", c.User)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *listAPITokensCommand) Run(ctx *cmd.Context) error {
+	owner := c.User
+	if owner == "" {
+		accountDetails, err := c.CurrentAccountDetails()
+		if err != nil {
+			return errors.Trace(err)
+		}
+		owner = accountDetails.User
+	}
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	tokens, err := api.APITokens(owner)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	now := time.Now()
+	result := make([]APITokenInfo, len(tokens))
+	for i, token := range tokens {
+		result[i] = APITokenInfo{
+			Id:          token.Id,
+			Description: token.Description,
+			Models:      modelUUIDsFromTags(token.ModelTags),
+			Access:      token.Access,
+			CreatedBy:   token.CreatedBy,
+			DateCreated: token.DateCreated,
+			Expires:     token.Expires,
+			Status:      apiTokenStatus(token, now),
+		}
+	}
+	return c.out.Write(ctx, result)
+}
+
+func modelUUIDsFromTags(modelTags []string) []string {
+	result := make([]string, 0, len(modelTags))
+	for _, modelTag := range modelTags {
+		tag, err := names.ParseModelTag(modelTag)
+		if err != nil {
+			logger.Warningf("ignoring invalid model tag %q", modelTag)
+			continue
+		}
+		result = append(result, tag.Id())
+	}
+	return result
+}
+
+func apiTokenStatus(token params.APIToken, now time.Time) string {
+	switch {
+	case token.Revoked:
+		return "revoked"
+	case !now.Before(token.Expires):
+		return "expired"
+	default:
+		return "active"
+	}
+}
+
+func formatAPITokensTabular(writer io.Writer, value interface{}) error {
+	tokens, ok := value.([]APITokenInfo)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", tokens, value)
+	}
+	if len(tokens) == 0 {
+		fmt.Fprintln(writer, "No API tokens to display.")
+		return nil
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("ID", "Access", "Status", "Expires", "Models", "Description")
+	for _, token := range tokens {
+		w.Println(
+			token.Id,
+			token.Access,
+			token.Status,
+			token.Expires.Local().Format(time.RFC3339),
+			strings.Join(token.Models, ","),
+			token.Description,
+		)
+	}
+	tw.Flush()
+	return nil
+}
+
+// NewRevokeAPITokenCommand returns a command for revoking API tokens.
+func NewRevokeAPITokenCommand() cmd.Command {
+	return modelcmd.WrapController(&revokeAPITokenCommand{})
+}
+
+// revokeAPITokenCommand revokes an API token.
+type revokeAPITokenCommand struct {
+	apiTokenCommandBase
+
+	TokenId string
+}
+
+// Info implements Command.Info.
+func (c *revokeAPITokenCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "revoke-api-token",
+		Args:    "<token id>",
+		Purpose: usageRevokeAPITokenSummary,
+		Doc:     usageRevokeAPITokenDetails,
+	})
+}
+
+// Init implements Command.Init.
+func (c *revokeAPITokenCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no token ID supplied")
+	}
+	c.TokenId = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *revokeAPITokenCommand) Run(ctx *cmd.Context) error {
+	api, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer api.Close()
+
+	if err := api.RevokeAPIToken(c.TokenId); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("API token %q revoked", c.TokenId)
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package user_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/usermanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/user"
+	"github.com/juju/juju/core/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/permission"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type APITokenCommandSuite struct {
+	BaseSuite
+	mockAPI *mockAPITokenAPI
+}
+
+var _ = gc.Suite(&APITokenCommandSuite{})
+
+func (s *APITokenCommandSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mockAPI = &mockAPITokenAPI{}
+	s.store.Models["testing"] = &jujuclient.ControllerModels{
+		Models: map[string]jujuclient.ModelDetails{
+			"current-user/staging": {ModelUUID: coretesting.ModelTag.Id(), ModelType: model.IAAS},
+		},
+	}
+}
+
+func (s *APITokenCommandSuite) TestAddInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no username supplied",
+	}, {
+		args: []string{"ci-bot"},
+		err:  "at least one model must be specified with --model",
+	}, {
+		args: []string{"ci-bot", "--model", "staging", "--access", "superuser"},
+		err:  `"superuser" model access not valid`,
+	}, {
+		args: []string{"ci-bot", "--model", "staging", "--expires", "0s"},
+		err:  "expiry 0s not valid",
+	}, {
+		args: []string{"ci-bot", "--model", "staging", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		err := cmdtesting.InitCommand(user.NewAddAPITokenCommandForTest(s.mockAPI, s.store), test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *APITokenCommandSuite) TestAdd(c *gc.C) {
+	s.mockAPI.secret = "4f2a9c81d03b6e17:sekrit"
+	command := user.NewAddAPITokenCommandForTest(s.mockAPI, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command,
+		"ci-bot", "--model", "staging", "--access", "write", "--description", "nightly deploy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "4f2a9c81d03b6e17:sekrit\n")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Matches, `(?s)API token "4f2a9c81d03b6e17" created for user "ci-bot".*`)
+
+	args := s.mockAPI.addArgs
+	c.Assert(args.Owner, gc.Equals, "ci-bot")
+	c.Assert(args.Description, gc.Equals, "nightly deploy")
+	c.Assert(args.Models, jc.DeepEquals, []string{coretesting.ModelTag.Id()})
+	c.Assert(args.Access, gc.Equals, permission.WriteAccess)
+	c.Assert(args.Expires.After(time.Now()), jc.IsTrue)
+}
+
+func (s *APITokenCommandSuite) TestAddError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	command := user.NewAddAPITokenCommandForTest(s.mockAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command, "ci-bot", "--model", "current-user/staging")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *APITokenCommandSuite) TestList(c *gc.C) {
+	expires := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	s.mockAPI.tokens = []params.APIToken{{
+		Id:          "4f2a9c81d03b6e17",
+		OwnerTag:    "user-ci-bot",
+		Description: "nightly deploy",
+		ModelTags:   []string{coretesting.ModelTag.String()},
+		Access:      "write",
+		CreatedBy:   "admin",
+		DateCreated: expires.Add(-time.Hour),
+		Expires:     expires,
+	}, {
+		Id:        "0a1b2c3d4e5f6a7b",
+		OwnerTag:  "user-ci-bot",
+		ModelTags: []string{coretesting.ModelTag.String()},
+		Access:    "read",
+		CreatedBy: "admin",
+		Expires:   expires,
+		Revoked:   true,
+	}}
+	command := user.NewListAPITokensCommandForTest(s.mockAPI, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "ci-bot", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.owner, gc.Equals, "ci-bot")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, `
+- id: 4f2a9c81d03b6e17
+  description: nightly deploy
+  models:
+  - deadbeef-0bad-400d-8000-4b1d0d06f00d
+  access: write
+  created-by: admin
+  date-created: 2030-01-02T02:04:05Z
+  expires: 2030-01-02T03:04:05Z
+  status: active
+- id: 0a1b2c3d4e5f6a7b
+  models:
+  - deadbeef-0bad-400d-8000-4b1d0d06f00d
+  access: read
+  created-by: admin
+  date-created: 0001-01-01T00:00:00Z
+  expires: 2030-01-02T03:04:05Z
+  status: revoked
+`[1:])
+}
+
+func (s *APITokenCommandSuite) TestListDefaultsToCurrentUser(c *gc.C) {
+	command := user.NewListAPITokensCommandForTest(s.mockAPI, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.owner, gc.Equals, "current-user")
+	c.Assert(cmdtesting.Stdout(ctx), gc.Equals, "No API tokens to display.\n")
+}
+
+func (s *APITokenCommandSuite) TestRevoke(c *gc.C) {
+	command := user.NewRevokeAPITokenCommandForTest(s.mockAPI, s.store)
+	ctx, err := cmdtesting.RunCommand(c, command, "4f2a9c81d03b6e17")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.revoked, gc.Equals, "4f2a9c81d03b6e17")
+	c.Assert(cmdtesting.Stderr(ctx), gc.Equals, "API token \"4f2a9c81d03b6e17\" revoked\n")
+}
+
+func (s *APITokenCommandSuite) TestRevokeNoId(c *gc.C) {
+	command := user.NewRevokeAPITokenCommandForTest(s.mockAPI, s.store)
+	_, err := cmdtesting.RunCommand(c, command)
+	c.Assert(err, gc.ErrorMatches, "no token ID supplied")
+}
+
+type mockAPITokenAPI struct {
+	addArgs usermanager.AddAPITokenArgs
+	secret  string
+	tokens  []params.APIToken
+	owner   string
+	revoked string
+	err     error
+}
+
+func (*mockAPITokenAPI) Close() error { return nil }
+
+func (m *mockAPITokenAPI) AddAPIToken(args usermanager.AddAPITokenArgs) (params.APIToken, string, error) {
+	m.addArgs = args
+	if m.err != nil {
+		return params.APIToken{}, "", m.err
+	}
+	return params.APIToken{
+		Id:       "4f2a9c81d03b6e17",
+		OwnerTag: "user-" + args.Owner,
+		Access:   string(args.Access),
+		Expires:  args.Expires,
+	}, m.secret, nil
+}
+
+func (m *mockAPITokenAPI) APITokens(owner string) ([]params.APIToken, error) {
+	m.owner = owner
+	return m.tokens, m.err
+}
+
+func (m *mockAPITokenAPI) RevokeAPIToken(id string) error {
+	m.revoked = id
+	return m.err
+}
@@ -124,3 +124,21 @@ func NewWhoAmICommandForTest(store jujuclient.ClientStore) cmd.Command {
 	c := &whoAmICommand{store: store}
 	return c
 }
+
+func NewAddAPITokenCommandForTest(api APITokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &addAPITokenCommand{apiTokenCommandBase: apiTokenCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewListAPITokensCommandForTest(api APITokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &listAPITokensCommand{apiTokenCommandBase: apiTokenCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
+func NewRevokeAPITokenCommandForTest(api APITokenAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &revokeAPITokenCommand{apiTokenCommandBase: apiTokenCommandBase{api: api}}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/loggo"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/provisioner"
+	jujucmd "github.com/juju/juju/cmd"
+	"github.com/juju/juju/cmd/jujud/util"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
+	"github.com/juju/juju/worker/apicaller"
+)
+
+const bridgeNetplanDoc = `
+Print the netplan configuration that the machine agent would apply to
+bridge host devices for the specified container.
+
+The devices to bridge are those that Juju's bridge policy requires for
+the container, as reported by the controller. Bonds, VLANs and the MTUs
+of the bridged devices are handled as they are when the machine agent
+provisions the container.
+
+This is a dry run: the machine's network configuration is not changed.
+
+Examples:
+    jujud bridge-netplan machine-3 3/lxd/1
+`
+
+// HostChangesFunc returns the devices that Juju's bridge policy requires
+// to be bridged on the given agent's machine to host the specified
+// container.
+type HostChangesFunc func(agent.Agent, names.MachineTag) ([]network.DeviceToBridge, error)
+
+// HostChangesAsAgent connects to the API as the given agent, and asks
+// the controller which devices must be bridged to host the container.
+// It's extracted so tests can pass something else in.
+func HostChangesAsAgent(a agent.Agent, container names.MachineTag) ([]network.DeviceToBridge, error) {
+	conn, err := apicaller.ScaryConnect(a, api.Open, loggo.GetLogger("juju.agent"))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer conn.Close()
+	devices, _, err := provisioner.NewState(conn).HostChangesForContainer(container)
+	return devices, errors.Trace(err)
+}
+
+type bridgeNetplanCommand struct {
+	cmd.CommandBase
+	config      AgentConf
+	hostChanges HostChangesFunc
+	container   names.MachineTag
+	directory   string
+}
+
+// NewBridgeNetplanCommand returns a command that prints the netplan
+// configuration that would result from bridging host devices for a
+// container.
+func NewBridgeNetplanCommand(config AgentConf, hostChanges HostChangesFunc) cmd.Command {
+	return &bridgeNetplanCommand{
+		config:      config,
+		hostChanges: hostChanges,
+	}
+}
+
+// Info is part of cmd.Command.
+func (c *bridgeNetplanCommand) Info() *cmd.Info {
+	return jujucmd.Info(&cmd.Info{
+		Name:    "bridge-netplan",
+		Args:    "<machine-agent-name> <container-id>",
+		Purpose: "print the netplan configuration for bridging a container's host devices",
+		Doc:     bridgeNetplanDoc,
+	})
+}
+
+// SetFlags is part of cmd.Command.
+func (c *bridgeNetplanCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.directory, "netplan-dir", "/etc/netplan", "directory containing the netplan configuration")
+}
+
+// Init is part of cmd.Command.
+func (c *bridgeNetplanCommand) Init(args []string) error {
+	if len(args) < 2 {
+		return &util.FatalError{"machine-agent-name and container-id arguments are required"}
+	}
+	agentName, containerId, args := args[0], args[1], args[2:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return err
+	}
+	tag, err := names.ParseTag(agentName)
+	if err != nil {
+		return errors.Annotatef(err, "machine-agent-name")
+	}
+	if tag.Kind() != names.MachineTagKind {
+		return &util.FatalError{"machine-agent-name must be a machine tag"}
+	}
+	if !names.IsContainerMachine(containerId) {
+		return errors.NotValidf("container ID %q", containerId)
+	}
+	if err := c.config.ReadConfig(agentName); err != nil {
+		return errors.Trace(err)
+	}
+	c.container = names.NewMachineTag(containerId)
+	return nil
+}
+
+// Run is part of cmd.Command.
+func (c *bridgeNetplanCommand) Run(ctx *cmd.Context) error {
+	devices, err := c.hostChanges(c.config, c.container)
+	if err != nil {
+		return errors.Annotatef(err, "getting devices to bridge for %s", names.ReadableString(c.container))
+	}
+	if len(devices) == 0 {
+		ctx.Infof("%s requires no additional bridges", names.ReadableString(c.container))
+		return nil
+	}
+	result, err := netplan.BridgeAndActivate(netplan.ActivationParams{
+		Devices:   network.NetplanDevicesToBridge(devices),
+		Directory: c.directory,
+		DryRun:    true,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprint(ctx.Stdout, result.Stdout)
+	return nil
+}
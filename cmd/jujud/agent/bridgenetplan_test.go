@@ -0,0 +1,120 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package agent_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/agent"
+	agentcmd "github.com/juju/juju/cmd/jujud/agent"
+	"github.com/juju/juju/network"
+)
+
+type bridgeNetplanSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&bridgeNetplanSuite{})
+
+func (s *bridgeNetplanSuite) TestInitChecksArgs(c *gc.C) {
+	command := agentcmd.NewBridgeNetplanCommand(newAgentConf(), nil)
+	err := cmdtesting.InitCommand(command, []string{"machine-3"})
+	c.Assert(err, gc.ErrorMatches, "machine-agent-name and container-id arguments are required")
+	err = cmdtesting.InitCommand(command, []string{"unit-mysql-0", "3/lxd/1"})
+	c.Assert(err, gc.ErrorMatches, "machine-agent-name must be a machine tag")
+	err = cmdtesting.InitCommand(command, []string{"machine-3", "4"})
+	c.Assert(err, gc.ErrorMatches, `container ID "4" not valid`)
+	err = cmdtesting.InitCommand(command, []string{"machine-3", "3/lxd/1", "eno1"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["eno1"\]`)
+}
+
+func (s *bridgeNetplanSuite) TestRunComplainsAboutAPIErrors(c *gc.C) {
+	command := agentcmd.NewBridgeNetplanCommand(newAgentConf(),
+		func(agent.Agent, names.MachineTag) ([]network.DeviceToBridge, error) {
+			return nil, errors.New("connection refused")
+		})
+	_, err := cmdtesting.RunCommand(c, command, "machine-3", "3/lxd/1")
+	c.Assert(err, gc.ErrorMatches, "getting devices to bridge for machine 3/lxd/1: connection refused")
+}
+
+func (s *bridgeNetplanSuite) TestRunNoBridges(c *gc.C) {
+	command := agentcmd.NewBridgeNetplanCommand(newAgentConf(),
+		func(agent.Agent, names.MachineTag) ([]network.DeviceToBridge, error) {
+			return nil, nil
+		})
+	ctx, err := cmdtesting.RunCommand(c, command, "machine-3", "3/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, "")
+	c.Check(cmdtesting.Stderr(ctx), gc.Equals, "machine 3/lxd/1 requires no additional bridges\n")
+}
+
+func (s *bridgeNetplanSuite) TestRun(c *gc.C) {
+	dir := c.MkDir()
+	config := `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      addresses:
+      - 10.0.0.2/24
+      mtu: 9000
+  vlans:
+    eno1.100:
+      id: 100
+      link: eno1
+`[1:]
+	err := ioutil.WriteFile(filepath.Join(dir, "50-cloud-init.yaml"), []byte(config), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	agentConf := newAgentConf()
+	var container names.MachineTag
+	command := agentcmd.NewBridgeNetplanCommand(agentConf,
+		func(a agent.Agent, tag names.MachineTag) ([]network.DeviceToBridge, error) {
+			container = tag
+			return []network.DeviceToBridge{{
+				DeviceName: "eno1",
+				BridgeName: "br-eno1",
+				MTU:        9000,
+			}, {
+				DeviceName: "eno1.100",
+				BridgeName: "br-eno1.100",
+			}}, nil
+		})
+	ctx, err := cmdtesting.RunCommand(c, command, "--netplan-dir", dir, "machine-3", "3/lxd/1")
+	c.Assert(err, jc.ErrorIsNil)
+	agentConf.stub.CheckCall(c, 0, "ReadConfig", "machine-3")
+	c.Check(container, gc.Equals, names.NewMachineTag("3/lxd/1"))
+	c.Check(cmdtesting.Stdout(ctx), gc.Equals, `
+network:
+  version: 2
+  ethernets:
+    eno1:
+      mtu: 9000
+  bridges:
+    br-eno1:
+      interfaces: [eno1]
+      addresses:
+      - 10.0.0.2/24
+      mtu: 9000
+    br-eno1.100:
+      interfaces: [eno1.100]
+  vlans:
+    eno1.100:
+      id: 100
+      link: eno1
+`[1:])
+
+	// The configuration is left untouched.
+	data, err := ioutil.ReadFile(filepath.Join(dir, "50-cloud-init.yaml"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(data), gc.Equals, config)
+}
@@ -41,6 +41,7 @@ var (
 		"instance-mutater",
 		"instance-poller",
 		"logging-config-updater",  // tertiary dependency: will be inactive because migration workers will be inactive
+		"machine-replacer",        // tertiary dependency: will be inactive because migration workers will be inactive
 		"machine-undertaker",      // tertiary dependency: will be inactive because migration workers will be inactive
 		"metric-worker",           // tertiary dependency: will be inactive because migration workers will be inactive
 		"migration-fortress",      // secondary dependency: will be inactive because depends on model-upgrader
@@ -65,6 +66,7 @@ var (
 		"instance-poller",
 		"log-forwarder",
 		"logging-config-updater",
+		"machine-replacer",
 		"machine-undertaker",
 		"metric-worker",
 		"migration-fortress",
@@ -142,6 +144,7 @@ var (
 	notMigratingMachineWorkers = []string{
 		"api-address-updater",
 		"disk-manager",
+		"egress-firewaller",
 		"fan-configurer",
 		// "host-key-reporter", not stable, exits when done
 		"log-sender",
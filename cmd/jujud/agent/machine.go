@@ -50,6 +50,7 @@ import (
 	jujucmd "github.com/juju/juju/cmd"
 	"github.com/juju/juju/cmd/jujud/agent/machine"
 	"github.com/juju/juju/cmd/jujud/agent/model"
+	"github.com/juju/juju/cmd/jujud/agent/uninstall"
 	"github.com/juju/juju/cmd/jujud/reboot"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/container"
@@ -62,6 +63,7 @@ import (
 	"github.com/juju/juju/core/paths"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/environs"
 	jujunames "github.com/juju/juju/juju/names"
@@ -69,6 +71,7 @@ import (
 	"github.com/juju/juju/mongo/mongometrics"
 	"github.com/juju/juju/pubsub/centralhub"
 	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/systemd"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/storage/looputil"
@@ -396,6 +399,10 @@ type MachineAgent struct {
 	// peergrouper have manifolds.
 	centralHub *pubsub.StructuredHub
 
+	// tracer records trace spans for the API requests served by
+	// the agent and the work they cause.
+	tracer trace.Tracer
+
 	isCaasAgent bool
 }
 
@@ -486,6 +493,18 @@ func (a *MachineAgent) Run(ctx *cmd.Context) (err error) {
 	// have dependencies on a central hub worker.
 	a.centralHub = centralhub.New(a.Tag())
 
+	tracer, stopTracer, err := newTracer(a.CurrentConfig())
+	if err != nil {
+		// Tracing is a diagnostic aid; not being able to
+		// set it up must not stop the agent from running.
+		logger.Errorf("cannot start tracing: %v", err)
+		tracer, stopTracer = trace.NoopTracer{}, func() {}
+	}
+	a.tracer = tracer
+	// Stopping the tracer sends any spans it is
+	// holding, including those of the shutdown.
+	defer stopTracer()
+
 	// Before doing anything else, we need to make sure the certificate generated for
 	// use by mongo to validate controller connections is correct. This needs to be done
 	// before any possible restart of the mongo service.
@@ -527,6 +546,10 @@ func (a *MachineAgent) Run(ctx *cmd.Context) (err error) {
 	close(a.workersStarted)
 	err = a.runner.Wait()
 	switch errors.Cause(err) {
+	case jworker.ErrTerminateAgent:
+		if uninstallErr := a.uninstallAgent(); uninstallErr != nil {
+			logger.Errorf("cannot uninstall agent: %v", uninstallErr)
+		}
 	case jworker.ErrRebootMachine:
 		logger.Infof("Caught reboot error")
 		err = a.executeRebootOrShutdown(params.ShouldReboot)
@@ -593,6 +616,7 @@ func (a *MachineAgent) makeEngineCreator(agentName string, previousAgentVersion
 			CentralHub:              a.centralHub,
 			PubSubReporter:          pubsubReporter,
 			PresenceRecorder:        presenceRecorder,
+			Tracer:                  a.tracer,
 			UpdateLoggerConfig:      updateAgentConfLogging,
 			UpdateControllerAPIPort: updateControllerAPIPort,
 			NewAgentStatusSetter: func(apiConn api.Connection) (upgradesteps.StatusSetter, error) {
@@ -641,6 +665,34 @@ func (a *MachineAgent) makeEngineCreator(agentName string, previousAgentVersion
 	}
 }
 
+// newTracer returns the tracer that the agent records trace spans with,
+// sending them to the collector named by the AGENT_TRACE_ENDPOINT agent
+// config value, and a func that must be called to stop it. If no
+// collector is configured, spans are discarded.
+func newTracer(agentConfig agent.Config) (trace.Tracer, func(), error) {
+	endpoint := agentConfig.Value(agent.AgentTraceEndpoint)
+	if endpoint == "" {
+		return trace.NoopTracer{}, func() {}, nil
+	}
+	exporter, err := trace.NewOTLPExporter(trace.OTLPConfig{
+		Endpoint:    endpoint,
+		ServiceName: "jujud",
+		Attributes: []trace.Attribute{
+			trace.StringAttr("juju.agent", agentConfig.Tag().String()),
+		},
+		Clock: clock.WallClock,
+	})
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "parsing %s", agent.AgentTraceEndpoint)
+	}
+	stop := func() {
+		if err := worker.Stop(exporter); err != nil {
+			logger.Errorf("stopping trace exporter: %v", err)
+		}
+	}
+	return trace.NewTracer(exporter, clock.WallClock), stop, nil
+}
+
 func (a *MachineAgent) executeRebootOrShutdown(action params.RebootAction) error {
 	// block until all units/containers are ready, and reboot/shutdown
 	finalize, err := reboot.NewRebootWaiter(a.CurrentConfig())
@@ -1098,6 +1150,7 @@ func (a *MachineAgent) startModelWorkers(cfg modelworkermanager.NewModelConfig)
 		CharmRevisionUpdateInterval: 24 * time.Hour,
 		StatusHistoryPrunerInterval: 5 * time.Minute,
 		ActionPrunerInterval:        24 * time.Hour,
+		MachineReplacerInterval:     time.Minute,
 		NewEnvironFunc:              newEnvirons,
 		NewContainerBrokerFunc:      newCAASBroker,
 		NewMigrationMaster:          migrationmaster.NewWorker,
@@ -1340,14 +1393,56 @@ func (a *MachineAgent) createSymlink(target, link string) error {
 	return symlink.New(target, fullLink)
 }
 
-func (a *MachineAgent) removeJujudSymlinks() (errs []error) {
+// uninstallReportFilename is the name of the file, alongside the
+// agents' log directory, in which uninstallAgent reports what it did.
+const uninstallReportFilename = "juju-uninstall.log"
+
+// uninstallAgent removes the agent, and everything installed with it,
+// from a manually provisioned machine which has been removed from the
+// model. It does nothing unless the machine was marked for uninstall.
+func (a *MachineAgent) uninstallAgent() error {
+	canUninstall, keepWorkloads := agent.CanUninstall(a)
+	if !canUninstall {
+		logger.Infof("ignoring uninstall request")
+		return nil
+	}
+	logger.Infof("uninstalling agent")
+
+	agentConfig := a.CurrentConfig()
+	// Loop devices backed by files in the data dir would keep
+	// those files in use, so detach them first.
+	if err := a.loopDeviceManager.DetachLoopDevices("/", agentConfig.DataDir()); err != nil {
+		logger.Errorf("cannot detach loop devices: %v", err)
+	}
+
+	agentServiceName := agentConfig.Value(agent.AgentServiceName)
+	if agentServiceName == "" {
+		agentServiceName = "jujud-" + a.Tag().String()
+	}
+	paths := []string{
+		utils.EnsureBaseDir(a.rootDir, introspection.ProfileFilename(introspection.ProfileDir)),
+		utils.EnsureBaseDir(a.rootDir, systemd.CleanShutdownServicePath),
+	}
 	for _, link := range jujudSymlinks {
-		err := os.Remove(utils.EnsureBaseDir(a.rootDir, link))
-		if err != nil && !os.IsNotExist(err) {
-			errs = append(errs, errors.Annotatef(err, "failed to remove %s symlink", link))
-		}
+		paths = append(paths, utils.EnsureBaseDir(a.rootDir, link))
+	}
+	reportFile := filepath.Join(filepath.Dir(agentConfig.LogDir()), uninstallReportFilename)
+	report, err := uninstall.Uninstall(uninstall.Config{
+		DataDir:          agentConfig.DataDir(),
+		LogDir:           agentConfig.LogDir(),
+		Paths:            paths,
+		AgentServiceName: agentServiceName,
+		Services:         uninstall.SystemServices{},
+		KeepWorkloads:    keepWorkloads,
+		ReportFile:       reportFile,
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !report.Clean() {
+		return errors.Errorf("%d items left behind, see %s", len(report.Remaining), reportFile)
 	}
-	return
+	return nil
 }
 
 // newDeployContext gives the tests the opportunity to create a deployer.Context
@@ -31,6 +31,7 @@ import (
 	"github.com/juju/juju/core/machinelock"
 	"github.com/juju/juju/core/presence"
 	"github.com/juju/juju/core/raftlease"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 	proxyconfig "github.com/juju/juju/utils/proxy"
@@ -53,6 +54,7 @@ import (
 	"github.com/juju/juju/worker/credentialvalidator"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskmanager"
+	"github.com/juju/juju/worker/egressfirewaller"
 	"github.com/juju/juju/worker/externalcontrollerupdater"
 	"github.com/juju/juju/worker/fanconfigurer"
 	"github.com/juju/juju/worker/featureflag"
@@ -108,6 +110,10 @@ const (
 	// delay when a concurrent global clock update is detected.
 	globalClockUpdaterBackoffDelay = 10 * time.Second
 
+	// egressFirewallerCheckInterval is the interval between checks
+	// of the egress policy enforced by the machine agent.
+	egressFirewallerCheckInterval = 1 * time.Minute
+
 	// leaseRequestTopic is the pubsub topic that lease FSM updates
 	// will be published on.
 	leaseRequestTopic = "lease.request"
@@ -209,6 +215,10 @@ type ManifoldsConfig struct {
 	// PresenceRecorder
 	PresenceRecorder presence.Recorder
 
+	// Tracer is used by the apiserver and the workers that handle
+	// its pubsub messages to record trace spans.
+	Tracer trace.Tracer
+
 	// UpdateLoggerConfig is a function that will save the specified
 	// config value as the logging config in the agent.conf file.
 	UpdateLoggerConfig func(string) error
@@ -317,10 +327,11 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 		// The termination worker returns ErrTerminateAgent if a
 		// termination signal is received by the process it's running
 		// in. It has no inputs and its only output is the error it
-		// returns. It depends on the uninstall file having been
-		// written *by the manual provider* at install time; it would
-		// be Very Wrong Indeed to use SetCanUninstall in conjunction
-		// with this code.
+		// returns. Whether the agent then uninstalls itself depends
+		// on the uninstall file, which is only ever written for
+		// manually provisioned machines: by the manual provider when
+		// the controller is destroyed, and by the machiner, through
+		// agent.SetCanUninstall, once the machine is Dead.
 		terminationName: terminationworker.Manifold(),
 
 		clockName: clockManifold(config.Clock),
@@ -574,6 +585,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			AgentName:      agentName,
 			APICallerName:  apiCallerName,
 			CentralHubName: centralHubName,
+			Tracer:         config.Tracer,
 			Logger:         loggo.GetLogger("juju.worker.agentconfigupdater"),
 		})),
 
@@ -699,6 +711,7 @@ func commonManifolds(config ManifoldsConfig) dependency.Manifolds {
 			RegisterIntrospectionHTTPHandlers: config.RegisterIntrospectionHTTPHandlers,
 			Hub:                               config.CentralHub,
 			Presence:                          config.PresenceRecorder,
+			Tracer:                            config.Tracer,
 			NewWorker:                         apiserver.NewWorker,
 			NewMetricsCollector:               apiserver.NewMetricsCollector,
 		})),
@@ -860,6 +873,19 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			Clock:         config.Clock,
 		})),
 
+		// The egress firewaller restricts the outbound traffic of the
+		// machine with iptables where the firewaller cannot do so
+		// through the provider.
+		egressFirewallerName: ifNotMigrating(egressfirewaller.Manifold(egressfirewaller.ManifoldConfig{
+			AgentName:     agentName,
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			CheckInterval: egressFirewallerCheckInterval,
+			Logger:        loggo.GetLogger("juju.worker.egressfirewaller"),
+			NewFacade:     egressfirewaller.NewFacade,
+			NewWorker:     egressfirewaller.NewWorker,
+		})),
+
 		certificateUpdaterName: ifFullyUpgraded(certupdater.Manifold(certupdater.ManifoldConfig{
 			AgentName:                agentName,
 			StateName:                stateName,
@@ -1129,6 +1155,7 @@ const (
 	machineActionName             = "machine-action-runner"
 	hostKeyReporterName           = "host-key-reporter"
 	fanConfigurerName             = "fan-configurer"
+	egressFirewallerName          = "egress-firewaller"
 	externalControllerUpdaterName = "external-controller-updater"
 	globalClockUpdaterName        = "global-clock-updater"
 	leaseClockUpdaterName         = "lease-clock-updater"
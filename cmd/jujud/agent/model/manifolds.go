@@ -47,6 +47,7 @@ import (
 	"github.com/juju/juju/worker/logforwarder"
 	"github.com/juju/juju/worker/logforwarder/sinks"
 	"github.com/juju/juju/worker/logger"
+	"github.com/juju/juju/worker/machinereplacer"
 	"github.com/juju/juju/worker/machineundertaker"
 	"github.com/juju/juju/worker/metricworker"
 	"github.com/juju/juju/worker/migrationflag"
@@ -105,6 +106,10 @@ type ManifoldsConfig struct {
 	// worker is run.
 	ActionPrunerInterval time.Duration
 
+	// MachineReplacerInterval controls how often the machine replacer
+	// checks for down machines.
+	MachineReplacerInterval time.Duration
+
 	// NewEnvironFunc is a function opens a provider "environment"
 	// (typically environs.New).
 	NewEnvironFunc environs.NewEnvironFunc
@@ -420,6 +425,14 @@ func IAASManifolds(config ManifoldsConfig) dependency.Manifolds {
 			NewCredentialValidatorFacade: common.NewCredentialInvalidatorFacade,
 			Logger:                       config.LoggingContext.GetLogger("juju.worker.machineundertaker"),
 		}))),
+		machineReplacerName: ifNotMigrating(machinereplacer.Manifold(machinereplacer.ManifoldConfig{
+			APICallerName: apiCallerName,
+			Clock:         config.Clock,
+			CheckInterval: config.MachineReplacerInterval,
+			Logger:        config.LoggingContext.GetLogger("juju.worker.machinereplacer"),
+			NewFacade:     machinereplacer.NewFacade,
+			NewWorker:     machinereplacer.NewWorker,
+		})),
 		modelUpgraderName: ifNotDead(ifCredentialValid(modelupgrader.Manifold(modelupgrader.ManifoldConfig{
 			APICallerName:                apiCallerName,
 			EnvironName:                  environTrackerName,
@@ -637,6 +650,7 @@ const (
 	statusHistoryPrunerName  = "status-history-pruner"
 	actionPrunerName         = "action-pruner"
 	machineUndertakerName    = "machine-undertaker"
+	machineReplacerName      = "machine-replacer"
 	remoteRelationsName      = "remote-relations"
 	logForwarderName         = "log-forwarder"
 	loggingConfigUpdaterName = "logging-config-updater"
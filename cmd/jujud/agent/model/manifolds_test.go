@@ -49,6 +49,7 @@ func (s *ManifoldsSuite) TestIAASNames(c *gc.C) {
 		"is-responsible-flag",
 		"log-forwarder",
 		"logging-config-updater",
+		"machine-replacer",
 		"machine-undertaker",
 		"metric-worker",
 		"migration-fortress",
@@ -493,6 +494,17 @@ var expectedIAASModelManifoldsWithDependencies = map[string][]string{
 		"not-dead-flag",
 	},
 
+	"machine-replacer": {
+		"agent",
+		"api-caller",
+		"is-responsible-flag",
+		"migration-fortress",
+		"migration-inactive-flag",
+		"model-upgrade-gate",
+		"model-upgraded-flag",
+		"not-dead-flag",
+	},
+
 	"machine-undertaker": {
 		"agent",
 		"api-caller",
@@ -0,0 +1,36 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uninstall
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/service"
+	"github.com/juju/juju/service/common"
+)
+
+// SystemServices is a ServiceManager for the local init system.
+type SystemServices struct{}
+
+// ListServices is part of the ServiceManager interface.
+func (SystemServices) ListServices() ([]string, error) {
+	return service.ListServices()
+}
+
+// RemoveService is part of the ServiceManager interface.
+func (SystemServices) RemoveService(name string, stop bool) error {
+	svc, err := service.DiscoverService(name, common.Conf{})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if stop {
+		if err := svc.Stop(); err != nil {
+			return errors.Annotate(err, "cannot stop")
+		}
+	}
+	if err := svc.Remove(); err != nil {
+		return errors.Annotate(err, "cannot remove")
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package uninstall removes a machine agent, and everything Juju
+// installed alongside it, from a manually provisioned machine.
+package uninstall
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/mongo"
+)
+
+var logger = loggo.GetLogger("juju.cmd.jujud.agent.uninstall")
+
+// cleanShutdownService is the name of the service which releases
+// DHCP leases when a machine provisioned by Juju shuts down.
+const cleanShutdownService = "juju-clean-shutdown"
+
+// untrackedNote is added to every report, as Juju cannot know what
+// charm hooks installed on the machine.
+const untrackedNote = "packages installed by charm hooks are not tracked by Juju and have not been removed"
+
+// ServiceManager lists and removes the services of the machine's
+// init system.
+type ServiceManager interface {
+	// ListServices returns the names of all installed services.
+	ListServices() ([]string, error)
+
+	// RemoveService removes the named service, first stopping it
+	// if stop is true.
+	RemoveService(name string, stop bool) error
+}
+
+// Config holds the details of the agent installation to remove.
+type Config struct {
+	// DataDir is the agent's data directory.
+	DataDir string
+
+	// LogDir is the directory holding the agents' logs.
+	LogDir string
+
+	// Paths holds other files installed with the agent, such as
+	// the links to jujud.
+	Paths []string
+
+	// AgentServiceName is the name of the service running the
+	// agent doing the uninstall. It is removed but not stopped.
+	AgentServiceName string
+
+	// Services manages the machine's services.
+	Services ServiceManager
+
+	// KeepWorkloads is true if the units' charms, and their logs,
+	// should be left on the machine.
+	KeepWorkloads bool
+
+	// ReportFile is where the uninstall report is written. It must
+	// be outside DataDir and LogDir.
+	ReportFile string
+}
+
+// Validate returns an error if the config cannot be used to
+// uninstall an agent.
+func (config Config) Validate() error {
+	if config.DataDir == "" {
+		return errors.NotValidf("empty DataDir")
+	}
+	if config.LogDir == "" {
+		return errors.NotValidf("empty LogDir")
+	}
+	if config.Services == nil {
+		return errors.NotValidf("nil Services")
+	}
+	if config.ReportFile == "" {
+		return errors.NotValidf("empty ReportFile")
+	}
+	for _, dir := range []string{config.DataDir, config.LogDir} {
+		if isWithin(config.ReportFile, dir) {
+			return errors.NotValidf("ReportFile %q within %q", config.ReportFile, dir)
+		}
+	}
+	return nil
+}
+
+// Report records what an uninstall removed, what it deliberately
+// kept, and what it could not remove.
+type Report struct {
+	Removed   []string
+	Kept      []string
+	Remaining []string
+}
+
+// Clean reports whether everything which should have been removed was.
+func (r *Report) Clean() bool {
+	return len(r.Remaining) == 0
+}
+
+// String returns the report in the form written to the report file.
+func (r *Report) String() string {
+	var buf bytes.Buffer
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&buf, "%s:\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&buf, "  %s\n", item)
+		}
+	}
+	section("removed", r.Removed)
+	section("kept", r.Kept)
+	section("left behind", r.Remaining)
+	fmt.Fprintf(&buf, "note: %s\n", untrackedNote)
+	return buf.String()
+}
+
+func (r *Report) removed(item string) {
+	r.Removed = append(r.Removed, item)
+}
+
+func (r *Report) kept(item string) {
+	r.Kept = append(r.Kept, item)
+}
+
+func (r *Report) remaining(item string, err error) {
+	r.Remaining = append(r.Remaining, fmt.Sprintf("%s: %v", item, err))
+}
+
+// Uninstall removes the agent's services, files and directories,
+// then checks that they have gone. Anything which is still there is
+// listed in the returned report, which is also written to the
+// config's ReportFile. An error is only returned if the report
+// could not be written.
+func Uninstall(config Config) (*Report, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	report := &Report{}
+	removeServices(config, report)
+	for _, path := range config.Paths {
+		removePath(path, report)
+	}
+	removeDir(config.DataDir, workloadPaths(config.DataDir, config.KeepWorkloads, "agents/unit-*/charm"), report)
+	removeDir(config.LogDir, workloadPaths(config.LogDir, config.KeepWorkloads, "unit-*.log"), report)
+	verifyRemoved(config, report)
+
+	if report.Clean() {
+		logger.Infof("uninstall complete")
+	} else {
+		logger.Warningf("uninstall left behind:\n  %s", strings.Join(report.Remaining, "\n  "))
+	}
+	if err := ioutil.WriteFile(config.ReportFile, []byte(report.String()), 0644); err != nil {
+		return report, errors.Annotate(err, "writing uninstall report")
+	}
+	return report, nil
+}
+
+// isAgentService reports whether the named service was installed by
+// Juju to run its agents or their supporting processes.
+func isAgentService(name string) bool {
+	return strings.HasPrefix(name, "jujud-") ||
+		name == mongo.ServiceName ||
+		name == cleanShutdownService
+}
+
+func agentServices(config Config) ([]string, error) {
+	all, err := config.Services.ListServices()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var services []string
+	for _, name := range all {
+		if isAgentService(name) {
+			services = append(services, name)
+		}
+	}
+	sort.Strings(services)
+	return services, nil
+}
+
+func removeServices(config Config, report *Report) {
+	services, err := agentServices(config)
+	if err != nil {
+		report.remaining("services", errors.Annotate(err, "cannot list"))
+		return
+	}
+	for _, name := range services {
+		// Stopping our own service would kill the uninstall.
+		stop := name != config.AgentServiceName
+		if err := config.Services.RemoveService(name, stop); err != nil {
+			report.remaining("service "+name, err)
+			continue
+		}
+		report.removed("service " + name)
+	}
+}
+
+// workloadPaths returns the paths, matching the pattern within dir,
+// which are to be kept on the machine.
+func workloadPaths(dir string, keepWorkloads bool, pattern string) []string {
+	if !keepWorkloads {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, pattern))
+	if err != nil {
+		// The patterns are constant and valid.
+		panic(err)
+	}
+	return paths
+}
+
+// removeDir removes the directory and its contents, except for the
+// paths to keep.
+func removeDir(dir string, keep []string, report *Report) {
+	if len(keep) == 0 {
+		removePath(dir, report)
+		return
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		report.remaining(dir, err)
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		switch keepWithin(path, keep) {
+		case keepAll:
+			report.kept(path)
+		case keepSome:
+			removeDir(path, keep, report)
+		default:
+			removePath(path, report)
+		}
+	}
+}
+
+const (
+	keepNone = iota
+	keepSome
+	keepAll
+)
+
+// keepWithin reports whether all, some or none of path is to be kept.
+func keepWithin(path string, keep []string) int {
+	result := keepNone
+	for _, k := range keep {
+		if k == path {
+			return keepAll
+		}
+		if isWithin(k, path) {
+			result = keepSome
+		}
+	}
+	return result
+}
+
+func removePath(path string, report *Report) {
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		return
+	}
+	if err := os.RemoveAll(path); err != nil {
+		report.remaining(path, err)
+		return
+	}
+	report.removed(path)
+}
+
+// verifyRemoved checks that everything reported as removed is gone,
+// moving anything which is still there into the remaining items.
+func verifyRemoved(config Config, report *Report) {
+	var services []string
+	if len(report.Removed) > 0 {
+		var err error
+		if services, err = agentServices(config); err != nil {
+			report.remaining("services", errors.Annotate(err, "cannot verify removal"))
+		}
+	}
+	installed := make(map[string]bool)
+	for _, name := range services {
+		installed["service "+name] = true
+	}
+	removed := report.Removed[:0]
+	for _, item := range report.Removed {
+		if installed[item] {
+			report.remaining(item, errors.New("still installed"))
+			continue
+		}
+		if !strings.HasPrefix(item, "service ") {
+			if _, err := os.Lstat(item); err == nil {
+				report.remaining(item, errors.New("still present"))
+				continue
+			}
+		}
+		removed = append(removed, item)
+	}
+	report.Removed = removed
+}
+
+// isWithin reports whether path is inside dir.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,218 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uninstall_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/jujud/agent/uninstall"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type uninstallSuite struct {
+	testing.IsolationSuite
+
+	root     string
+	services *fakeServices
+	config   uninstall.Config
+}
+
+var _ = gc.Suite(&uninstallSuite{})
+
+func (s *uninstallSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.root = c.MkDir()
+	s.services = &fakeServices{
+		Stub: &testing.Stub{},
+		installed: map[string]bool{
+			"jujud-machine-1":       true,
+			"jujud-unit-mysql-0":    true,
+			"juju-clean-shutdown":   true,
+			"ssh":                   true,
+			"unattended-upgrades":   true,
+			"jujud-machine-1-other": true,
+		},
+	}
+	s.config = uninstall.Config{
+		DataDir:          s.path("var/lib/juju"),
+		LogDir:           s.path("var/log/juju"),
+		Paths:            []string{s.path("usr/bin/juju-run"), s.path("etc/profile.d/juju-introspection.sh")},
+		AgentServiceName: "jujud-machine-1",
+		Services:         s.services,
+		ReportFile:       s.path("var/log/juju-uninstall.log"),
+	}
+	for _, file := range []string{
+		"var/lib/juju/agents/machine-1/agent.conf",
+		"var/lib/juju/agents/unit-mysql-0/agent.conf",
+		"var/lib/juju/agents/unit-mysql-0/charm/metadata.yaml",
+		"var/lib/juju/tools/2.8.0-bionic-amd64/jujud",
+		"var/log/juju/machine-1.log",
+		"var/log/juju/unit-mysql-0.log",
+		"usr/bin/juju-run",
+		"etc/profile.d/juju-introspection.sh",
+	} {
+		s.writeFile(c, file)
+	}
+}
+
+func (s *uninstallSuite) path(rel string) string {
+	return filepath.Join(s.root, filepath.FromSlash(rel))
+}
+
+func (s *uninstallSuite) writeFile(c *gc.C, rel string) {
+	path := s.path(rel)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(path, []byte(rel), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *uninstallSuite) TestValidate(c *gc.C) {
+	config := s.config
+	config.ReportFile = s.path("var/log/juju/uninstall.log")
+	_, err := uninstall.Uninstall(config)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err, gc.ErrorMatches, `ReportFile ".*" within ".*" not valid`)
+
+	config = s.config
+	config.Services = nil
+	_, err = uninstall.Uninstall(config)
+	c.Assert(err, gc.ErrorMatches, "nil Services not valid")
+}
+
+func (s *uninstallSuite) TestUninstall(c *gc.C) {
+	report, err := uninstall.Uninstall(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Clean(), jc.IsTrue)
+	c.Assert(report.Kept, gc.HasLen, 0)
+	c.Assert(report.Removed, jc.DeepEquals, []string{
+		"service juju-clean-shutdown",
+		"service jujud-machine-1",
+		"service jujud-machine-1-other",
+		"service jujud-unit-mysql-0",
+		s.path("usr/bin/juju-run"),
+		s.path("etc/profile.d/juju-introspection.sh"),
+		s.path("var/lib/juju"),
+		s.path("var/log/juju"),
+	})
+
+	s.services.CheckCalls(c, []testing.StubCall{
+		{FuncName: "ListServices"},
+		{FuncName: "RemoveService", Args: []interface{}{"juju-clean-shutdown", true}},
+		{FuncName: "RemoveService", Args: []interface{}{"jujud-machine-1", false}},
+		{FuncName: "RemoveService", Args: []interface{}{"jujud-machine-1-other", true}},
+		{FuncName: "RemoveService", Args: []interface{}{"jujud-unit-mysql-0", true}},
+		{FuncName: "ListServices"},
+	})
+	for _, rel := range []string{"var/lib/juju", "var/log/juju", "usr/bin/juju-run"} {
+		_, err := os.Stat(s.path(rel))
+		c.Check(os.IsNotExist(err), jc.IsTrue, gc.Commentf("%s", rel))
+	}
+
+	data, err := ioutil.ReadFile(s.config.ReportFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, report.String())
+	c.Assert(string(data), gc.Matches, `(?s)removed:\n  service juju-clean-shutdown\n.*note: packages installed by charm hooks .*\n`)
+}
+
+func (s *uninstallSuite) TestUninstallKeepWorkloads(c *gc.C) {
+	s.config.KeepWorkloads = true
+	report, err := uninstall.Uninstall(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Clean(), jc.IsTrue)
+	c.Assert(report.Kept, jc.DeepEquals, []string{
+		s.path("var/lib/juju/agents/unit-mysql-0/charm"),
+		s.path("var/log/juju/unit-mysql-0.log"),
+	})
+	c.Assert(report.Removed, jc.DeepEquals, []string{
+		"service juju-clean-shutdown",
+		"service jujud-machine-1",
+		"service jujud-machine-1-other",
+		"service jujud-unit-mysql-0",
+		s.path("usr/bin/juju-run"),
+		s.path("etc/profile.d/juju-introspection.sh"),
+		s.path("var/lib/juju/agents/machine-1"),
+		s.path("var/lib/juju/agents/unit-mysql-0/agent.conf"),
+		s.path("var/lib/juju/tools"),
+		s.path("var/log/juju/machine-1.log"),
+	})
+	c.Assert(s.path("var/lib/juju/agents/unit-mysql-0/charm/metadata.yaml"), jc.IsNonEmptyFile)
+	c.Assert(s.path("var/log/juju/unit-mysql-0.log"), jc.IsNonEmptyFile)
+}
+
+func (s *uninstallSuite) TestUninstallReportsLeftovers(c *gc.C) {
+	s.services.SetErrors(
+		nil,                             // ListServices
+		nil,                             // RemoveService juju-clean-shutdown
+		nil,                             // RemoveService jujud-machine-1
+		nil,                             // RemoveService jujud-machine-1-other
+		errors.New("cannot stop: oops"), // RemoveService jujud-unit-mysql-0
+	)
+	s.services.keep = "jujud-machine-1-other"
+
+	report, err := uninstall.Uninstall(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Clean(), jc.IsFalse)
+	c.Assert(report.Remaining, jc.DeepEquals, []string{
+		"service jujud-unit-mysql-0: cannot stop: oops",
+		"service jujud-machine-1-other: still installed",
+	})
+	c.Assert(report.String(), gc.Matches, `(?s).*left behind:\n  service jujud-unit-mysql-0: cannot stop: oops\n  service jujud-machine-1-other: still installed\n.*`)
+}
+
+func (s *uninstallSuite) TestUninstallListServicesError(c *gc.C) {
+	s.services.SetErrors(errors.New("no dbus"))
+
+	report, err := uninstall.Uninstall(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.Remaining, jc.DeepEquals, []string{
+		"services: cannot list: no dbus",
+	})
+	// The files are still removed.
+	_, err = os.Stat(s.config.DataDir)
+	c.Assert(os.IsNotExist(err), jc.IsTrue)
+}
+
+type fakeServices struct {
+	*testing.Stub
+	installed map[string]bool
+
+	// keep names a service which RemoveService claims to
+	// remove, but leaves installed.
+	keep string
+}
+
+func (f *fakeServices) ListServices() ([]string, error) {
+	f.AddCall("ListServices")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range f.installed {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (f *fakeServices) RemoveService(name string, stop bool) error {
+	f.AddCall("RemoveService", name, stop)
+	if err := f.NextErr(); err != nil {
+		return err
+	}
+	if name != f.keep {
+		delete(f.installed, name)
+	}
+	return nil
+}
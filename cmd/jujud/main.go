@@ -251,6 +251,7 @@ func jujuDMain(args []string, ctx *cmd.Context) (code int, err error) {
 	jujud.Register(caasOperatorAgent)
 
 	jujud.Register(agentcmd.NewCheckConnectionCommand(agentConf, agentcmd.ConnectAsAgent))
+	jujud.Register(agentcmd.NewBridgeNetplanCommand(agentConf, agentcmd.HostChangesAsAgent))
 
 	code = cmd.Main(jujud, ctx, args[1:])
 	return code, nil
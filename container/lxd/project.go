@@ -0,0 +1,97 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/errors"
+	"github.com/lxc/lxd/shared"
+	"github.com/lxc/lxd/shared/api"
+)
+
+// ProjectSupported reports whether the server supports LXD projects.
+func (s *Server) ProjectSupported() bool {
+	return s.projectAPISupport
+}
+
+// projectDescription identifies the LXD projects created by Juju.
+const projectDescription = "Created by Juju"
+
+// EnsureProject creates the named LXD project if it does not already exist.
+// Projects created by Juju isolate containers only; images and profiles are
+// shared with the default project.
+func (s *Server) EnsureProject(name string) error {
+	if !s.projectAPISupport {
+		return errors.NotSupportedf("LXD projects")
+	}
+	names, err := s.GetProjectNames()
+	if err != nil {
+		return errors.Annotate(err, "listing projects")
+	}
+	if shared.StringInSlice(name, names) {
+		return nil
+	}
+	req := api.ProjectsPost{
+		Name: name,
+		ProjectPut: api.ProjectPut{
+			Description: projectDescription,
+			Config: map[string]string{
+				"features.images":   "false",
+				"features.profiles": "false",
+			},
+		},
+	}
+	logger.Debugf("creating LXD project %q", name)
+	return errors.Annotatef(s.CreateProject(req), "creating project %q", name)
+}
+
+// DeleteProject deletes the named LXD project if it was created by Juju
+// and nothing is left in it. Projects created outside of Juju, and those
+// still in use, for instance by another model, are left in place.
+func (s *Server) DeleteProject(name string) error {
+	if !s.projectAPISupport {
+		return errors.NotSupportedf("LXD projects")
+	}
+	project, _, err := s.GetProject(name)
+	if err != nil {
+		if IsLXDNotFound(err) {
+			return nil
+		}
+		return errors.Annotatef(err, "getting project %q", name)
+	}
+	if project.Description != projectDescription {
+		logger.Debugf("not deleting LXD project %q: it was not created by Juju", name)
+		return nil
+	}
+	if len(project.UsedBy) > 0 {
+		logger.Infof("not deleting LXD project %q: it is still used by %v", name, project.UsedBy)
+		return nil
+	}
+	logger.Debugf("deleting LXD project %q", name)
+	return errors.Annotatef(s.ContainerServer.DeleteProject(name), "deleting project %q", name)
+}
+
+// JujuProjectNames returns the names of the LXD projects created by Juju.
+func (s *Server) JujuProjectNames() ([]string, error) {
+	if !s.projectAPISupport {
+		return nil, errors.NotSupportedf("LXD projects")
+	}
+	projects, err := s.GetProjects()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing projects")
+	}
+	var names []string
+	for _, project := range projects {
+		if project.Description == projectDescription {
+			names = append(names, project.Name)
+		}
+	}
+	return names, nil
+}
+
+// UseProjectServer returns a new Server based on the input project name.
+// All operations made with it are made within the project.
+func (s Server) UseProjectServer(name string) (*Server, error) {
+	logger.Debugf("creating LXD server for project %q", name)
+	return NewServer(s.UseProject(name))
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	lxdapi "github.com/lxc/lxd/shared/api"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+)
+
+type projectSuite struct {
+	lxdtesting.BaseSuite
+}
+
+var _ = gc.Suite(&projectSuite{})
+
+func (s *projectSuite) TestEnsureProjectExists(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	cSvr.EXPECT().GetProjectNames().Return([]string{"default", "juju-model"}, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.EnsureProject("juju-model")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestEnsureProjectCreated(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	req := lxdapi.ProjectsPost{
+		Name: "juju-model",
+		ProjectPut: lxdapi.ProjectPut{
+			Description: "Created by Juju",
+			Config: map[string]string{
+				"features.images":   "false",
+				"features.profiles": "false",
+			},
+		},
+	}
+	gomock.InOrder(
+		cSvr.EXPECT().GetProjectNames().Return([]string{"default"}, nil),
+		cSvr.EXPECT().CreateProject(req).Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.EnsureProject("juju-model")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestEnsureProjectNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.EnsureProject("juju-model")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *projectSuite) TestDeleteProject(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	project := &lxdapi.Project{
+		Name:       "juju-model",
+		ProjectPut: lxdapi.ProjectPut{Description: "Created by Juju"},
+	}
+	gomock.InOrder(
+		cSvr.EXPECT().GetProject("juju-model").Return(project, lxdtesting.ETag, nil),
+		cSvr.EXPECT().DeleteProject("juju-model").Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.DeleteProject("juju-model")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestDeleteProjectInUse(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	project := &lxdapi.Project{
+		Name:       "juju-model",
+		ProjectPut: lxdapi.ProjectPut{Description: "Created by Juju"},
+		UsedBy:     []string{"/1.0/containers/juju-123456-0?project=juju-model"},
+	}
+	cSvr.EXPECT().GetProject("juju-model").Return(project, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.DeleteProject("juju-model")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestDeleteProjectNotCreatedByJuju(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	project := &lxdapi.Project{Name: "juju-model"}
+	cSvr.EXPECT().GetProject("juju-model").Return(project, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.DeleteProject("juju-model")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *projectSuite) TestJujuProjectNames(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	projects := []lxdapi.Project{{
+		Name:       "default",
+		ProjectPut: lxdapi.ProjectPut{Description: "Default LXD project"},
+	}, {
+		Name:       "juju-model",
+		ProjectPut: lxdapi.ProjectPut{Description: "Created by Juju"},
+	}, {
+		Name: "juju-not-ours",
+	}}
+	cSvr.EXPECT().GetProjects().Return(projects, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	names, err := jujuSvr.JujuProjectNames()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(names, jc.DeepEquals, []string{"juju-model"})
+}
+
+func (s *projectSuite) TestJujuProjectNamesNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.JujuProjectNames()
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *projectSuite) TestUseProjectServer(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+	pSvr := s.NewMockServerWithExtensions(ctrl, "projects")
+
+	cSvr.EXPECT().UseProject("juju-model").Return(pSvr)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = jujuSvr.UseProjectServer("juju-model")
+	c.Assert(err, jc.ErrorIsNil)
+}
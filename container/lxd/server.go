@@ -44,6 +44,7 @@ type Server struct {
 	networkAPISupport bool
 	clusterAPISupport bool
 	storageAPISupport bool
+	projectAPISupport bool
 
 	localBridgeName string
 
@@ -120,6 +121,7 @@ func NewServer(svr lxd.ContainerServer) (*Server, error) {
 		networkAPISupport: shared.StringInSlice("network", apiExt),
 		clusterAPISupport: shared.StringInSlice("clustering", apiExt),
 		storageAPISupport: shared.StringInSlice("storage", apiExt),
+		projectAPISupport: shared.StringInSlice("projects", apiExt),
 		serverVersion:     info.Environment.ServerVersion,
 		clock:             clock.WallClock,
 	}, nil
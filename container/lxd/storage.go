@@ -4,15 +4,42 @@
 package lxd
 
 import (
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
+	lxd "github.com/lxc/lxd/client"
 	"github.com/lxc/lxd/shared/api"
 )
 
+const customVolumeType = "custom"
+
+// clusterMemberPoolConfig holds the storage pool config keys which LXD
+// requires to be set for each cluster member, rather than for the cluster.
+var clusterMemberPoolConfig = set.NewStrings(
+	"source",
+	"size",
+	"zfs.pool_name",
+	"lvm.thinpool_name",
+	"lvm.vg_name",
+)
+
+// IsClusterMemberPoolConfig reports whether the storage pool config key
+// is set for each member of an LXD cluster. Such keys are not returned
+// when a pool is retrieved without targeting a member.
+func IsClusterMemberPoolConfig(key string) bool {
+	return clusterMemberPoolConfig.Contains(key)
+}
+
 func (s *Server) StorageSupported() bool {
 	return s.storageAPISupport
 }
 
+// CreatePool creates a storage pool with the input name, driver and config.
+// For a clustered server the pool is first created on each member, with the
+// config specific to members, then created for the cluster as a whole.
 func (s *Server) CreatePool(name, driver string, cfg map[string]string) error {
+	if s.clustered {
+		return errors.Trace(s.createClusterPool(name, driver, cfg))
+	}
 	req := api.StoragePoolsPost{
 		Name:           name,
 		Driver:         driver,
@@ -24,12 +51,120 @@ func (s *Server) CreatePool(name, driver string, cfg map[string]string) error {
 func (s *Server) CreateVolume(pool, name string, cfg map[string]string) error {
 	req := api.StorageVolumesPost{
 		Name:             name,
-		Type:             "custom",
+		Type:             customVolumeType,
 		StorageVolumePut: api.StorageVolumePut{Config: cfg},
 	}
 	return errors.Annotatef(s.CreateStoragePoolVolume(pool, req), "creating storage pool volume %q", name)
 }
 
+// CreateVolumeFromSnapshot creates a custom volume in the input pool by
+// copying the snapshot of a volume in the source pool.
+// The snapshot is identified by "<volume-name>/<snapshot-name>".
+func (s *Server) CreateVolumeFromSnapshot(pool, name, sourcePool, snapshot string, cfg map[string]string) error {
+	req := api.StorageVolumesPost{
+		Name:             name,
+		Type:             customVolumeType,
+		StorageVolumePut: api.StorageVolumePut{Config: cfg},
+		Source: api.StorageVolumeSource{
+			Type: "copy",
+			Name: snapshot,
+			Pool: sourcePool,
+		},
+	}
+	return errors.Annotatef(
+		s.CreateStoragePoolVolume(pool, req),
+		"creating storage pool volume %q from snapshot %q", name, snapshot,
+	)
+}
+
+// CreateVolumeSnapshot creates a snapshot with the input name, of the named
+// custom volume in the input pool.
+func (s *Server) CreateVolumeSnapshot(pool, volume, snapshot string) error {
+	req := api.StorageVolumeSnapshotsPost{Name: snapshot}
+	op, err := s.CreateStoragePoolVolumeSnapshot(pool, customVolumeType, volume, req)
+	if err != nil {
+		return errors.Annotatef(err, "creating snapshot of storage pool volume %q", volume)
+	}
+	return errors.Annotatef(op.Wait(), "creating snapshot of storage pool volume %q", volume)
+}
+
+// DeleteVolumeSnapshot deletes the named snapshot of the named custom
+// volume in the input pool. It is not an error if the snapshot does
+// not exist.
+func (s *Server) DeleteVolumeSnapshot(pool, volume, snapshot string) error {
+	op, err := s.DeleteStoragePoolVolumeSnapshot(pool, customVolumeType, volume, snapshot)
+	if err == nil {
+		err = op.Wait()
+	}
+	if err != nil && !IsLXDNotFound(err) {
+		return errors.Annotatef(err, "deleting snapshot %q of storage pool volume %q", snapshot, volume)
+	}
+	return nil
+}
+
+func (s *Server) createClusterPool(name, driver string, cfg map[string]string) error {
+	members, err := s.GetClusterMembers()
+	if err != nil {
+		return errors.Annotate(err, "listing cluster members")
+	}
+
+	memberCfg := make(map[string]string)
+	clusterCfg := make(map[string]string)
+	for k, v := range cfg {
+		if IsClusterMemberPoolConfig(k) {
+			memberCfg[k] = v
+		} else {
+			clusterCfg[k] = v
+		}
+	}
+
+	for _, member := range members {
+		req := api.StoragePoolsPost{
+			Name:           name,
+			Driver:         driver,
+			StoragePoolPut: api.StoragePoolPut{Config: memberCfg},
+		}
+		if err := s.UseTarget(member.ServerName).CreateStoragePool(req); err != nil {
+			return errors.Annotatef(err, "creating storage pool %q on cluster member %q", name, member.ServerName)
+		}
+	}
+
+	req := api.StoragePoolsPost{
+		Name:           name,
+		Driver:         driver,
+		StoragePoolPut: api.StoragePoolPut{Config: clusterCfg},
+	}
+	return errors.Annotatef(s.CreateStoragePool(req), "creating storage pool %q", name)
+}
+
+// MoveVolume moves the named custom volume in the input pool from one
+// cluster member to another, by copying it to the target member and then
+// deleting the original. The volume must not be in use.
+func (s *Server) MoveVolume(pool, name, from, to string) error {
+	source := s.UseTarget(from)
+	volume, _, err := source.GetStoragePoolVolume(pool, customVolumeType, name)
+	if err != nil {
+		return errors.Annotatef(err, "getting storage pool volume %q", name)
+	}
+	if len(volume.UsedBy) > 0 {
+		return errors.Errorf("storage pool volume %q is in use by %d containers", name, len(volume.UsedBy))
+	}
+
+	logger.Debugf("moving storage pool volume %q from %q to %q", name, from, to)
+	args := &lxd.StoragePoolVolumeCopyArgs{Name: name}
+	op, err := s.UseTarget(to).CopyStoragePoolVolume(pool, source, pool, *volume, args)
+	if err != nil {
+		return errors.Annotatef(err, "copying storage pool volume %q to %q", name, to)
+	}
+	if err := op.Wait(); err != nil {
+		return errors.Annotatef(err, "copying storage pool volume %q to %q", name, to)
+	}
+	return errors.Annotatef(
+		source.DeleteStoragePoolVolume(pool, customVolumeType, name),
+		"deleting storage pool volume %q from %q", name, from,
+	)
+}
+
 // EnsureDefaultStorage ensures that the input profile is configured with a
 // disk device, creating a new storage pool and a device if required.
 func (s *Server) EnsureDefaultStorage(profile *api.Profile, eTag string) error {
@@ -5,7 +5,9 @@ package lxd_test
 
 import (
 	"github.com/golang/mock/gomock"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	lxdclient "github.com/lxc/lxd/client"
 	lxdapi "github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
 
@@ -102,6 +104,210 @@ func (s *storageSuite) TestCreateVolume(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *storageSuite) TestCreateVolumeFromSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+
+	cfg := map[string]string{"size": "1024MB"}
+
+	req := lxdapi.StorageVolumesPost{
+		Name: "volume",
+		Type: "custom",
+		StorageVolumePut: lxdapi.StorageVolumePut{
+			Config: cfg,
+		},
+		Source: lxdapi.StorageVolumeSource{
+			Type: "copy",
+			Name: "old-volume/snap0",
+			Pool: "old-pool",
+		},
+	}
+	cSvr.EXPECT().CreateStoragePoolVolume("default-pool", req).Return(nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.CreateVolumeFromSnapshot("default-pool", "volume", "old-pool", "old-volume/snap0", cfg)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestCreateVolumeSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+	snapOp := lxdtesting.NewMockOperation(ctrl)
+
+	req := lxdapi.StorageVolumeSnapshotsPost{Name: "snap0"}
+	gomock.InOrder(
+		cSvr.EXPECT().CreateStoragePoolVolumeSnapshot("juju", "custom", "vol", req).Return(snapOp, nil),
+		snapOp.EXPECT().Wait().Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.CreateVolumeSnapshot("juju", "vol", "snap0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestCreateVolumeSnapshotError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+	snapOp := lxdtesting.NewMockOperation(ctrl)
+
+	req := lxdapi.StorageVolumeSnapshotsPost{Name: "snap0"}
+	gomock.InOrder(
+		cSvr.EXPECT().CreateStoragePoolVolumeSnapshot("juju", "custom", "vol", req).Return(snapOp, nil),
+		snapOp.EXPECT().Wait().Return(errors.New("boom")),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.CreateVolumeSnapshot("juju", "vol", "snap0")
+	c.Assert(err, gc.ErrorMatches, `creating snapshot of storage pool volume "vol": boom`)
+}
+
+func (s *storageSuite) TestDeleteVolumeSnapshot(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+	snapOp := lxdtesting.NewMockOperation(ctrl)
+
+	gomock.InOrder(
+		cSvr.EXPECT().DeleteStoragePoolVolumeSnapshot("juju", "custom", "vol", "snap0").Return(snapOp, nil),
+		snapOp.EXPECT().Wait().Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.DeleteVolumeSnapshot("juju", "vol", "snap0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestDeleteVolumeSnapshotNotFound(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+
+	cSvr.EXPECT().DeleteStoragePoolVolumeSnapshot("juju", "custom", "vol", "snap0").Return(nil, errors.New("not found"))
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.DeleteVolumeSnapshot("juju", "vol", "snap0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestDeleteVolumeSnapshotError(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerWithExtensions(ctrl, "storage")
+	snapOp := lxdtesting.NewMockOperation(ctrl)
+
+	gomock.InOrder(
+		cSvr.EXPECT().DeleteStoragePoolVolumeSnapshot("juju", "custom", "vol", "snap0").Return(snapOp, nil),
+		snapOp.EXPECT().Wait().Return(errors.New("boom")),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.DeleteVolumeSnapshot("juju", "vol", "snap0")
+	c.Assert(err, gc.ErrorMatches, `deleting snapshot "snap0" of storage pool volume "vol": boom`)
+}
+
+func (s *storageSuite) TestCreatePoolClustered(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerClustered(ctrl, "cluster-1")
+	c2Svr := lxdtesting.NewMockContainerServer(ctrl)
+
+	members := []lxdapi.ClusterMember{{ServerName: "cluster-1"}, {ServerName: "cluster-2"}}
+	memberReq := lxdapi.StoragePoolsPost{
+		Name:   "new-pool",
+		Driver: "zfs",
+		StoragePoolPut: lxdapi.StoragePoolPut{
+			Config: map[string]string{"zfs.pool_name": "juju-lxd"},
+		},
+	}
+	clusterReq := lxdapi.StoragePoolsPost{
+		Name:   "new-pool",
+		Driver: "zfs",
+		StoragePoolPut: lxdapi.StoragePoolPut{
+			Config: map[string]string{"volume.size": "1GB"},
+		},
+	}
+	gomock.InOrder(
+		cSvr.EXPECT().GetClusterMembers().Return(members, nil),
+		cSvr.EXPECT().UseTarget("cluster-1").Return(cSvr),
+		cSvr.EXPECT().CreateStoragePool(memberReq).Return(nil),
+		cSvr.EXPECT().UseTarget("cluster-2").Return(c2Svr),
+		c2Svr.EXPECT().CreateStoragePool(memberReq).Return(nil),
+		cSvr.EXPECT().CreateStoragePool(clusterReq).Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.CreatePool("new-pool", "zfs", map[string]string{
+		"zfs.pool_name": "juju-lxd",
+		"volume.size":   "1GB",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestMoveVolume(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerClustered(ctrl, "cluster-1")
+	srcSvr := lxdtesting.NewMockContainerServer(ctrl)
+	dstSvr := lxdtesting.NewMockContainerServer(ctrl)
+	copyOp := lxdtesting.NewMockRemoteOperation(ctrl)
+
+	volume := &lxdapi.StorageVolume{Name: "vol", Type: "custom", Location: "cluster-1"}
+	args := &lxdclient.StoragePoolVolumeCopyArgs{Name: "vol"}
+	gomock.InOrder(
+		cSvr.EXPECT().UseTarget("cluster-1").Return(srcSvr),
+		srcSvr.EXPECT().GetStoragePoolVolume("juju", "custom", "vol").Return(volume, lxdtesting.ETag, nil),
+		cSvr.EXPECT().UseTarget("cluster-2").Return(dstSvr),
+		dstSvr.EXPECT().CopyStoragePoolVolume("juju", srcSvr, "juju", *volume, args).Return(copyOp, nil),
+		copyOp.EXPECT().Wait().Return(nil),
+		srcSvr.EXPECT().DeleteStoragePoolVolume("juju", "custom", "vol").Return(nil),
+	)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.MoveVolume("juju", "vol", "cluster-1", "cluster-2")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestMoveVolumeInUse(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	cSvr := s.NewMockServerClustered(ctrl, "cluster-1")
+	srcSvr := lxdtesting.NewMockContainerServer(ctrl)
+
+	volume := &lxdapi.StorageVolume{
+		Name:     "vol",
+		Type:     "custom",
+		Location: "cluster-1",
+		UsedBy:   []string{"/1.0/containers/juju-0"},
+	}
+	cSvr.EXPECT().UseTarget("cluster-1").Return(srcSvr)
+	srcSvr.EXPECT().GetStoragePoolVolume("juju", "custom", "vol").Return(volume, lxdtesting.ETag, nil)
+
+	jujuSvr, err := lxd.NewServer(cSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = jujuSvr.MoveVolume("juju", "vol", "cluster-1", "cluster-2")
+	c.Assert(err, gc.ErrorMatches, `storage pool volume "vol" is in use by 1 containers`)
+}
+
 func (s *storageSuite) TestEnsureDefaultStorageDevicePresent(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
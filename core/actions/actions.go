@@ -11,6 +11,10 @@ import (
 // JujuRunActionName defines the action name used by juju-run.
 const JujuRunActionName = "juju-run"
 
+// JujuCheckNetworkActionName defines the action name used by
+// check-network to probe the network from a machine.
+const JujuCheckNetworkActionName = "juju-check-network"
+
 // PredefinedActionsSpec defines a spec for each predefined action.
 var PredefinedActionsSpec = map[string]charm.ActionSpec{
 	JujuRunActionName: {
@@ -36,4 +40,56 @@ var PredefinedActionsSpec = map[string]charm.ActionSpec{
 			},
 		},
 	},
+	JujuCheckNetworkActionName: {
+		Description: "predefined juju-check-network action",
+		Params: map[string]interface{}{
+			"type":        "object",
+			"title":       JujuCheckNetworkActionName,
+			"description": "predefined juju-check-network action params",
+			"required":    []interface{}{"targets", "timeout"},
+			"properties": map[string]interface{}{
+				"targets": map[string]interface{}{
+					"type":        "array",
+					"description": "the addresses to probe",
+					"items": map[string]interface{}{
+						"type":     "object",
+						"required": []interface{}{"to", "address"},
+						"properties": map[string]interface{}{
+							"from": map[string]interface{}{
+								"type":        "string",
+								"description": "the unit on the machine the probe is made for",
+							},
+							"to": map[string]interface{}{
+								"type":        "string",
+								"description": "the unit being probed",
+							},
+							"relation": map[string]interface{}{
+								"type":        "string",
+								"description": "the relation the units take part in",
+							},
+							"address": map[string]interface{}{
+								"type":        "string",
+								"description": "the ingress address of the unit being probed",
+							},
+							"ports": map[string]interface{}{
+								"type":        "array",
+								"description": "the ports opened by the unit being probed, as port/protocol",
+								"items": map[string]interface{}{
+									"type": "string",
+								},
+							},
+							"mtu": map[string]interface{}{
+								"type":        "number",
+								"description": "the size of the ICMP packets sent to the address",
+							},
+						},
+					},
+				},
+				"timeout": map[string]interface{}{
+					"type":        "number",
+					"description": "timeout for each probe",
+				},
+			},
+		},
+	},
 }
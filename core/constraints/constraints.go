@@ -34,6 +34,8 @@ const (
 	Spaces         = "spaces"
 	VirtType       = "virt-type"
 	Zones          = "zones"
+	Spot           = "spot"
+	SpotMaxPrice   = "spot-max-price"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -97,6 +99,21 @@ type Value struct {
 	// Zones, if not nil, holds a list of availability zones limiting where
 	// the machine can be located.
 	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
+
+	// Spot, if true, indicates that the machine should be started as an
+	// interruptible instance, such as an EC2 spot instance or a GCE
+	// preemptible instance. These are cheaper, but may be reclaimed by
+	// the cloud at short notice. When that happens, the machine is
+	// replaced by a new one with the same constraints, and its units
+	// are moved onto it. Only valid for clouds which support
+	// interruptible instances.
+	Spot *bool `json:"spot,omitempty" yaml:"spot,omitempty"`
+
+	// SpotMaxPrice, if not nil or empty, indicates the maximum hourly
+	// price, in US dollars, to pay for a spot instance. If unset, the
+	// cloud's on-demand price is the limit. Only valid for clouds whose
+	// interruptible instances are priced by bidding.
+	SpotMaxPrice *string `json:"spot-max-price,omitempty" yaml:"spot-max-price,omitempty"`
 }
 
 var rawAliases = map[string]string{
@@ -208,6 +225,18 @@ func (v *Value) HasZones() bool {
 	return v.Zones != nil && len(*v.Zones) > 0
 }
 
+// HasSpot returns true if the constraints.Value specifies that the
+// machine should be an interruptible instance.
+func (v *Value) HasSpot() bool {
+	return v.Spot != nil && *v.Spot
+}
+
+// HasSpotMaxPrice returns true if the constraints.Value specifies a
+// maximum price for a spot instance.
+func (v *Value) HasSpotMaxPrice() bool {
+	return v.SpotMaxPrice != nil && *v.SpotMaxPrice != ""
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -259,6 +288,12 @@ func (v Value) String() string {
 		s := strings.Join(*v.Zones, ",")
 		strs = append(strs, "zones="+s)
 	}
+	if v.Spot != nil {
+		strs = append(strs, "spot="+strconv.FormatBool(*v.Spot))
+	}
+	if v.SpotMaxPrice != nil {
+		strs = append(strs, "spot-max-price="+(*v.SpotMaxPrice))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -305,6 +340,12 @@ func (v Value) GoString() string {
 	} else if v.Zones != nil {
 		values = append(values, "Zones: (*[]string)(nil)")
 	}
+	if v.Spot != nil {
+		values = append(values, fmt.Sprintf("Spot: %v", *v.Spot))
+	}
+	if v.SpotMaxPrice != nil {
+		values = append(values, fmt.Sprintf("SpotMaxPrice: %q", *v.SpotMaxPrice))
+	}
 	return fmt.Sprintf("{%s}", strings.Join(values, ", "))
 }
 
@@ -465,6 +506,10 @@ func (v *Value) setRaw(name, str string) error {
 		err = v.setVirtType(str)
 	case Zones:
 		err = v.setZones(str)
+	case Spot:
+		err = v.setSpot(str)
+	case SpotMaxPrice:
+		err = v.setSpotMaxPrice(str)
 	default:
 		return errors.Errorf("unknown constraint %q", name)
 	}
@@ -532,6 +577,10 @@ func (v *Value) UnmarshalYAML(unmarshal func(interface{}) error) error {
 			v.VirtType = &vstr
 		case Zones:
 			v.Zones, err = parseYamlStrings("zones", val)
+		case Spot:
+			v.Spot, err = parseBool(vstr)
+		case SpotMaxPrice:
+			v.SpotMaxPrice, err = parsePrice(vstr)
 		default:
 			return errors.Errorf("unknown constraint value: %v", k)
 		}
@@ -672,6 +721,44 @@ func (v *Value) setZones(str string) error {
 	return nil
 }
 
+func (v *Value) setSpot(str string) (err error) {
+	if v.Spot != nil {
+		return errors.Errorf("already set")
+	}
+	v.Spot, err = parseBool(str)
+	return
+}
+
+func (v *Value) setSpotMaxPrice(str string) (err error) {
+	if v.SpotMaxPrice != nil {
+		return errors.Errorf("already set")
+	}
+	v.SpotMaxPrice, err = parsePrice(str)
+	return
+}
+
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, errors.Errorf("must be true or false")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
+func parsePrice(str string) (*string, error) {
+	if str != "" {
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil || val <= 0 || math.IsInf(val, 0) {
+			return nil, errors.Errorf("must be a positive decimal number")
+		}
+	}
+	return &str, nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -342,6 +342,40 @@ var parseConstraintsTests = []struct {
 		args:    []string{"zones="},
 	},
 
+	// Spot
+	{
+		summary: "set spot",
+		args:    []string{"spot=true"},
+	}, {
+		summary: "unset spot",
+		args:    []string{"spot=false"},
+	}, {
+		summary: "spot empty",
+		args:    []string{"spot="},
+	}, {
+		summary: "spot not a bool",
+		args:    []string{"spot=maybe"},
+		err:     `bad "spot" constraint: must be true or false`,
+	}, {
+		summary: "double set spot",
+		args:    []string{"spot=true", "spot=false"},
+		err:     `bad "spot" constraint: already set`,
+	}, {
+		summary: "set spot max price",
+		args:    []string{"spot=true spot-max-price=0.05"},
+	}, {
+		summary: "spot max price empty",
+		args:    []string{"spot-max-price="},
+	}, {
+		summary: "spot max price not a number",
+		args:    []string{"spot-max-price=cheap"},
+		err:     `bad "spot-max-price" constraint: must be a positive decimal number`,
+	}, {
+		summary: "spot max price zero",
+		args:    []string{"spot-max-price=0"},
+		err:     `bad "spot-max-price" constraint: must be a positive decimal number`,
+	},
+
 	// Everything at once.
 	{
 		summary: "kitchen sink together",
@@ -467,6 +501,21 @@ func (s *ConstraintsSuite) TestInvalidSpaces(c *gc.C) {
 	}
 }
 
+func (s *ConstraintsSuite) TestHasSpot(c *gc.C) {
+	con := constraints.MustParse("spot=true spot-max-price=0.1")
+	c.Check(con.HasSpot(), jc.IsTrue)
+	c.Check(con.HasSpotMaxPrice(), jc.IsTrue)
+	c.Check(*con.SpotMaxPrice, gc.Equals, "0.1")
+
+	con = constraints.MustParse("spot=false spot-max-price=")
+	c.Check(con.HasSpot(), jc.IsFalse)
+	c.Check(con.HasSpotMaxPrice(), jc.IsFalse)
+
+	con = constraints.MustParse("mem=4G")
+	c.Check(con.HasSpot(), jc.IsFalse)
+	c.Check(con.HasSpotMaxPrice(), jc.IsFalse)
+}
+
 func (s *ConstraintsSuite) TestHasZones(c *gc.C) {
 	con := constraints.MustParse("zones=az1,az2,az3")
 	c.Assert(con.Zones, gc.Not(gc.IsNil))
@@ -535,6 +584,10 @@ func strp(s string) *string {
 	return &s
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
 func ctypep(ctype string) *instance.ContainerType {
 	res := instance.ContainerType(ctype)
 	return &res
@@ -577,6 +630,11 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Zones1", constraints.Value{Zones: nil}},
 	{"Zones2", constraints.Value{Zones: &[]string{}}},
 	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Spot1", constraints.Value{Spot: nil}},
+	{"Spot2", constraints.Value{Spot: boolp(false)}},
+	{"Spot3", constraints.Value{Spot: boolp(true)}},
+	{"SpotMaxPrice1", constraints.Value{SpotMaxPrice: strp("")}},
+	{"SpotMaxPrice2", constraints.Value{SpotMaxPrice: strp("0.25")}},
 	{"All", constraints.Value{
 		Arch:           strp("i386"),
 		Container:      ctypep("lxd"),
@@ -589,6 +647,8 @@ var constraintsRoundtripTests = []roundTrip{
 		Spaces:         &[]string{"space1", "^space2"},
 		InstanceType:   strp("foo"),
 		Zones:          &[]string{"az1", "az2"},
+		Spot:           boolp(true),
+		SpotMaxPrice:   strp("0.25"),
 	}},
 }
 
@@ -18,6 +18,19 @@ const (
 
 var ErrPlacementScopeMissing = fmt.Errorf("placement scope missing")
 
+// regionDirectivePrefix prefixes placement directives that place a
+// machine in a region of the model's cloud other than the model's own
+// (e.g. --to region=us-west-2). A provider-specific directive may follow
+// the region, separated by a comma (e.g. region=us-west-2,zone=us-west-2a).
+// The region may be qualified by the name of another cloud, and followed
+// by the name of the credential to use there (e.g.
+// region=aws-gov/us-gov-west-1,credential=gov).
+const regionDirectivePrefix = "region="
+
+// credentialDirectivePrefix prefixes the credential name in a region
+// placement directive.
+const credentialDirectivePrefix = "credential="
+
 // Placement defines a placement directive, which has a scope
 // and a value that is scope-specific.
 type Placement struct {
@@ -83,3 +96,61 @@ func MustParsePlacement(directive string) *Placement {
 	}
 	return placement
 }
+
+// RegionPlacement describes a region placement directive, which places a
+// machine in a cloud region other than the model's own.
+type RegionPlacement struct {
+	// Cloud is the name of the cloud containing the region. If empty,
+	// the region belongs to the model's cloud.
+	Cloud string
+
+	// Region is the name of the cloud region.
+	Region string
+
+	// Credential is the name of the model owner's credential to use in
+	// the region. If empty, the model's credential is used.
+	Credential string
+
+	// Directive is the provider-specific placement directive following
+	// the region, if any.
+	Directive string
+}
+
+// ParseRegionPlacement parses a region placement directive of the form
+//
+//	region=[<cloud>/]<region>[,credential=<name>][,<directive>]
+//
+// It returns false if the directive does not name a region.
+func ParseRegionPlacement(directive string) (RegionPlacement, bool) {
+	if !strings.HasPrefix(directive, regionDirectivePrefix) {
+		return RegionPlacement{}, false
+	}
+	var p RegionPlacement
+	rest := strings.TrimPrefix(directive, regionDirectivePrefix)
+	p.Region, rest = splitDirective(rest)
+	if slash := strings.IndexRune(p.Region, '/'); slash != -1 {
+		p.Cloud, p.Region = p.Region[:slash], p.Region[slash+1:]
+		if p.Cloud == "" {
+			return RegionPlacement{}, false
+		}
+	}
+	if p.Region == "" {
+		return RegionPlacement{}, false
+	}
+	if strings.HasPrefix(rest, credentialDirectivePrefix) {
+		p.Credential, rest = splitDirective(strings.TrimPrefix(rest, credentialDirectivePrefix))
+		if p.Credential == "" {
+			return RegionPlacement{}, false
+		}
+	}
+	p.Directive = rest
+	return p, true
+}
+
+// splitDirective splits s at the first comma.
+func splitDirective(s string) (first, rest string) {
+	if comma := strings.IndexRune(s, ','); comma != -1 {
+		return s[:comma], s[comma+1:]
+	}
+	return s, ""
+}
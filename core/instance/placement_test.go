@@ -75,3 +75,53 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		}
 	}
 }
+
+func (s *PlacementSuite) TestParseRegionPlacement(c *gc.C) {
+	parseRegionPlacementTests := []struct {
+		arg      string
+		expect   instance.RegionPlacement
+		expectOK bool
+	}{{
+		arg: "",
+	}, {
+		arg: "zone=us-east-1a",
+	}, {
+		arg: "region=",
+	}, {
+		arg: "region=,zone=us-east-1a",
+	}, {
+		arg: "region=aws/",
+	}, {
+		arg: "region=/us-west-2",
+	}, {
+		arg: "region=us-west-2,credential=",
+	}, {
+		arg:      "region=us-west-2",
+		expect:   instance.RegionPlacement{Region: "us-west-2"},
+		expectOK: true,
+	}, {
+		arg:      "region=us-west-2,zone=us-west-2a",
+		expect:   instance.RegionPlacement{Region: "us-west-2", Directive: "zone=us-west-2a"},
+		expectOK: true,
+	}, {
+		arg:      "region=aws-gov/us-gov-west-1",
+		expect:   instance.RegionPlacement{Cloud: "aws-gov", Region: "us-gov-west-1"},
+		expectOK: true,
+	}, {
+		arg: "region=aws-gov/us-gov-west-1,credential=gov,zone=us-gov-west-1a",
+		expect: instance.RegionPlacement{
+			Cloud:      "aws-gov",
+			Region:     "us-gov-west-1",
+			Credential: "gov",
+			Directive:  "zone=us-gov-west-1a",
+		},
+		expectOK: true,
+	}}
+
+	for i, t := range parseRegionPlacementTests {
+		c.Logf("test %d: %s", i, t.arg)
+		p, ok := instance.ParseRegionPlacement(t.arg)
+		c.Check(p, jc.DeepEquals, t.expect)
+		c.Check(ok, gc.Equals, t.expectOK)
+	}
+}
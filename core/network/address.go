@@ -196,6 +196,20 @@ func (a MachineAddress) sortOrder() int {
 	return order
 }
 
+// sortOrderPreferringIPv6 calculates the sort weight of the address in
+// the same way as sortOrder, but with IPv6 addresses ordered before IPv4
+// addresses of the same scope.
+func (a MachineAddress) sortOrderPreferringIPv6() int {
+	order := a.sortOrder()
+	switch a.Type {
+	case IPv4Address:
+		order++
+	case IPv6Address:
+		order--
+	}
+	return order
+}
+
 // NewMachineAddress creates a new MachineAddress, deriving its type from the
 // value and using ScopeUnknown as scope. It is a shortcut to calling
 // NewScopedMachineAddress(value, ScopeUnknown).
@@ -623,6 +637,38 @@ func ScopeMatchCloudLocal(addr Address) ScopeMatch {
 	return invalidScope
 }
 
+// PreferIPv6 wraps the input scope matching function so that IPv6
+// addresses are preferred over IPv4 addresses with the same scope.
+// The scope hierarchy of the wrapped function is otherwise unchanged.
+func PreferIPv6(matchFunc ScopeMatchFunc) ScopeMatchFunc {
+	return func(addr Address) ScopeMatch {
+		match := matchFunc(addr)
+		// Within each scope the IPv4 ranks come before the others,
+		// so swap the ranks of IPv4 and IPv6 addresses around.
+		switch addr.AddressType() {
+		case IPv4Address:
+			switch match {
+			case exactScopeIPv4:
+				return exactScope
+			case firstFallbackScopeIPv4:
+				return firstFallbackScope
+			case secondFallbackScopeIPv4:
+				return secondFallbackScope
+			}
+		case IPv6Address:
+			switch match {
+			case exactScope:
+				return exactScopeIPv4
+			case firstFallbackScope:
+				return firstFallbackScopeIPv4
+			case secondFallbackScope:
+				return secondFallbackScopeIPv4
+			}
+		}
+		return match
+	}
+}
+
 type addressByIndexFunc func(index int) Address
 
 // indexesForScope returns the indexes of the addresses with the best
@@ -700,6 +746,28 @@ func SortAddresses(addrs []SpaceAddress) {
 	sort.Sort(addressesPreferringIPv4Slice(addrs))
 }
 
+type addressesPreferringIPv6Slice []SpaceAddress
+
+func (a addressesPreferringIPv6Slice) Len() int      { return len(a) }
+func (a addressesPreferringIPv6Slice) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a addressesPreferringIPv6Slice) Less(i, j int) bool {
+	addr1 := a[i]
+	addr2 := a[j]
+	order1 := addr1.sortOrderPreferringIPv6()
+	order2 := addr2.sortOrderPreferringIPv6()
+	if order1 == order2 {
+		return addr1.Value < addr2.Value
+	}
+	return order1 < order2
+}
+
+// SortAddressesPreferringIPv6 sorts the given Address slice in the same way
+// as SortAddresses, except that IPv6 addresses are ordered before IPv4
+// addresses of the same scope.
+func SortAddressesPreferringIPv6(addrs []SpaceAddress) {
+	sort.Sort(addressesPreferringIPv6Slice(addrs))
+}
+
 // MergedAddresses provides a single list of addresses without duplicates
 // suitable for returning as an address list for a machine.
 // TODO (cherylj) Add explicit unit tests - tracked with bug #1544158
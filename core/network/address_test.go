@@ -734,6 +734,82 @@ func (*AddressSuite) TestSortAddresses(c *gc.C) {
 	))
 }
 
+func (*AddressSuite) TestSortAddressesPreferringIPv6(c *gc.C) {
+	addrs := network.NewSpaceAddresses(
+		"127.0.0.1",
+		"::1",
+		"fc00::1",
+		"169.254.1.2",
+		"localhost",
+		"2001:db8::1",
+		"fe80::2",
+		"172.16.0.1",
+		"example.com",
+		"8.8.8.8",
+	)
+	network.SortAddressesPreferringIPv6(addrs)
+	c.Assert(addrs, jc.DeepEquals, network.NewSpaceAddresses(
+		// Public IPv6 addresses on top.
+		"2001:db8::1",
+		// After that public IPv4 addresses.
+		"8.8.8.8",
+		// Then hostnames.
+		"example.com",
+		"localhost",
+		// Then IPv6 cloud-local addresses.
+		"fc00::1",
+		// Then IPv4 cloud-local addresses.
+		"172.16.0.1",
+		// Then machine-local IPv6 addresses.
+		"::1",
+		// Then machine-local IPv4 addresses.
+		"127.0.0.1",
+		// Then link-local IPv6 addresses.
+		"fe80::2",
+		// Finally, link-local IPv4 addresses.
+		"169.254.1.2",
+	))
+}
+
+var selectPublicPreferringIPv6Tests = []selectTest{{
+	"a public IPv4 address is selected when there is no IPv6 address",
+	[]network.SpaceAddress{
+		network.NewScopedSpaceAddress("8.8.8.8", network.ScopePublic),
+	},
+	0,
+}, {
+	"a public IPv6 address is preferred over a public IPv4 address",
+	[]network.SpaceAddress{
+		network.NewScopedSpaceAddress("8.8.8.8", network.ScopePublic),
+		network.NewScopedSpaceAddress("2001:db8::1", network.ScopePublic),
+	},
+	1,
+}, {
+	"a public IPv4 address is preferred over a cloud local IPv6 address",
+	[]network.SpaceAddress{
+		network.NewScopedSpaceAddress("fc00::1", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("8.8.8.8", network.ScopePublic),
+	},
+	1,
+}, {
+	"a cloud local IPv6 address is preferred over a cloud local IPv4 address",
+	[]network.SpaceAddress{
+		network.NewScopedSpaceAddress("172.16.1.1", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("fc00::1", network.ScopeCloudLocal),
+	},
+	1,
+}}
+
+func (s *AddressSuite) TestSelectPublicAddressPreferringIPv6(c *gc.C) {
+	for i, t := range selectPublicPreferringIPv6Tests {
+		c.Logf("test %d: %s", i, t.about)
+		expectAddr, expectOK := t.expected()
+		actualAddr, actualOK := t.addresses.OneMatchingScope(network.PreferIPv6(network.ScopeMatchPublic))
+		c.Check(actualOK, gc.Equals, expectOK)
+		c.Check(actualAddr, gc.Equals, expectAddr)
+	}
+}
+
 func (*AddressSuite) TestExactScopeMatch(c *gc.C) {
 	var addr network.Address
 
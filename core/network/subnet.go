@@ -121,3 +121,16 @@ func IsValidCidr(cidr string) bool {
 	}
 	return false
 }
+
+// CIDRAddressType returns the type of the addresses in the input CIDR,
+// or an empty address type if the CIDR is not valid.
+func CIDRAddressType(cidr string) AddressType {
+	ip, _, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return ""
+	}
+	if ip.To4() != nil {
+		return IPv4Address
+	}
+	return IPv6Address
+}
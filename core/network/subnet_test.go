@@ -0,0 +1,23 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network_test
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/testing"
+)
+
+type SubnetSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&SubnetSuite{})
+
+func (s *SubnetSuite) TestCIDRAddressType(c *gc.C) {
+	c.Check(network.CIDRAddressType("10.0.0.0/24"), gc.Equals, network.IPv4Address)
+	c.Check(network.CIDRAddressType("2001:db8::/64"), gc.Equals, network.IPv6Address)
+	c.Check(network.CIDRAddressType("10.0.0.0"), gc.Equals, network.AddressType(""))
+}
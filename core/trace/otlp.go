@@ -0,0 +1,317 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"gopkg.in/tomb.v2"
+)
+
+var logger = loggo.GetLogger("juju.core.trace")
+
+const (
+	// otlpTracesPath is the path, relative to the collector endpoint,
+	// that OTLP/HTTP collectors accept trace data on.
+	otlpTracesPath = "/v1/traces"
+
+	defaultBatchSize     = 512
+	defaultFlushInterval = 5 * time.Second
+	defaultQueueSize     = 4096
+)
+
+// OTLPConfig holds the configuration for an OTLPExporter.
+type OTLPConfig struct {
+	// Endpoint is the base URL of the OTLP/HTTP collector,
+	// for example "http://localhost:4318".
+	Endpoint string
+
+	// ServiceName is reported as the "service.name"
+	// resource attribute of the exported spans.
+	ServiceName string
+
+	// Attributes are reported as additional resource
+	// attributes, for example to identify the machine.
+	Attributes []Attribute
+
+	// Clock is used to time when batches are sent.
+	Clock clock.Clock
+
+	// HTTPClient is used to send spans to the collector.
+	// If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// BatchSize is the maximum number of spans sent in one
+	// request. If zero, a default is used.
+	BatchSize int
+
+	// FlushInterval is the maximum time a finished span waits
+	// before being sent. If zero, a default is used.
+	FlushInterval time.Duration
+}
+
+// Validate checks that the configuration is usable.
+func (config OTLPConfig) Validate() error {
+	if config.Endpoint == "" {
+		return errors.NotValidf("empty Endpoint")
+	}
+	u, err := url.Parse(config.Endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.NotValidf("endpoint %q", config.Endpoint)
+	}
+	if config.ServiceName == "" {
+		return errors.NotValidf("empty ServiceName")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.BatchSize < 0 {
+		return errors.NotValidf("negative BatchSize")
+	}
+	if config.FlushInterval < 0 {
+		return errors.NotValidf("negative FlushInterval")
+	}
+	return nil
+}
+
+// OTLPExporter is an Exporter that sends spans in batches to a collector
+// using the JSON encoding of the OpenTelemetry protocol over HTTP. It is
+// a worker; spans are queued by ExportSpan and sent in the background.
+// If the queue is full, for example because the collector is down,
+// spans are dropped rather than holding up the traced operations.
+type OTLPExporter struct {
+	tomb   tomb.Tomb
+	config OTLPConfig
+	url    string
+	queue  chan SpanData
+}
+
+// NewOTLPExporter returns a new OTLPExporter with the given configuration.
+func NewOTLPExporter(config OTLPConfig) (*OTLPExporter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = defaultBatchSize
+	}
+	if config.FlushInterval == 0 {
+		config.FlushInterval = defaultFlushInterval
+	}
+	e := &OTLPExporter{
+		config: config,
+		url:    strings.TrimSuffix(config.Endpoint, "/") + otlpTracesPath,
+		queue:  make(chan SpanData, defaultQueueSize),
+	}
+	e.tomb.Go(e.loop)
+	return e, nil
+}
+
+// ExportSpan is part of the Exporter interface.
+func (e *OTLPExporter) ExportSpan(span SpanData) {
+	select {
+	case e.queue <- span:
+	default:
+		logger.Tracef("trace queue full, dropping span %q", span.Name)
+	}
+}
+
+// Kill is part of the worker.Worker interface.
+func (e *OTLPExporter) Kill() {
+	e.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (e *OTLPExporter) Wait() error {
+	return e.tomb.Wait()
+}
+
+func (e *OTLPExporter) loop() error {
+	var batch []SpanData
+	var flush <-chan time.Time
+	send := func() {
+		if err := e.send(batch); err != nil {
+			// Failing to deliver spans must not stop the
+			// agent, so we just log and carry on.
+			logger.Warningf("cannot send %d spans to %s: %v", len(batch), e.url, err)
+		}
+		batch = nil
+		flush = nil
+	}
+	for {
+		select {
+		case <-e.tomb.Dying():
+			// Send what we have, so that the spans of the
+			// operations that led to shutdown aren't lost.
+			if len(batch) > 0 {
+				send()
+			}
+			return tomb.ErrDying
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= e.config.BatchSize {
+				send()
+			} else if flush == nil {
+				flush = e.config.Clock.After(e.config.FlushInterval)
+			}
+		case <-flush:
+			send()
+		}
+	}
+}
+
+func (e *OTLPExporter) send(spans []SpanData) error {
+	data, err := json.Marshal(otlpRequest(e.config.ServiceName, e.config.Attributes, spans))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", e.url, bytes.NewReader(data))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.config.HTTPClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("collector returned %s: %s", resp.Status, body)
+	}
+	return nil
+}
+
+// The following types mirror the JSON encoding of an OTLP
+// ExportTraceServiceRequest. IDs are hex encoded, and times
+// are nanoseconds since the epoch, encoded as strings.
+
+type otlpExportRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
+
+// OTLP span kind and status code values.
+const (
+	otlpKindInternal = 1
+	otlpKindServer   = 2
+	otlpKindConsumer = 5
+
+	otlpStatusUnset = 0
+	otlpStatusError = 2
+)
+
+// otlpScopeName is reported as the instrumentation scope of all spans.
+const otlpScopeName = "github.com/juju/juju"
+
+func otlpRequest(serviceName string, attrs []Attribute, spans []SpanData) otlpExportRequest {
+	resourceAttrs := append([]Attribute{StringAttr("service.name", serviceName)}, attrs...)
+	otlpSpans := make([]otlpSpan, len(spans))
+	for i, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.SpanContext.TraceID.String(),
+			SpanID:            span.SpanContext.SpanID.String(),
+			Name:              span.Name,
+			Kind:              otlpKind(span.Kind),
+			StartTimeUnixNano: fmt.Sprint(span.Start.UnixNano()),
+			EndTimeUnixNano:   fmt.Sprint(span.End.UnixNano()),
+			Attributes:        otlpAttributes(span.Attributes),
+			Status:            otlpStatus{Code: otlpStatusUnset},
+		}
+		if span.ParentSpanID.IsValid() {
+			s.ParentSpanID = span.ParentSpanID.String()
+		}
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+		otlpSpans[i] = s
+	}
+	return otlpExportRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{Attributes: otlpAttributes(resourceAttrs)},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: otlpScopeName},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func otlpKind(kind SpanKind) int {
+	switch kind {
+	case SpanKindServer:
+		return otlpKindServer
+	case SpanKindConsumer:
+		return otlpKindConsumer
+	default:
+		return otlpKindInternal
+	}
+}
+
+func otlpAttributes(attrs []Attribute) []otlpKeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+	result := make([]otlpKeyValue, len(attrs))
+	for i, attr := range attrs {
+		result[i] = otlpKeyValue{Key: attr.Key, Value: otlpValue{StringValue: attr.Value}}
+	}
+	return result
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/core/trace"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type otlpSuite struct {
+	testing.IsolationSuite
+
+	server   *httptest.Server
+	requests chan map[string]interface{}
+	clock    *testclock.Clock
+}
+
+var _ = gc.Suite(&otlpSuite{})
+
+func (s *otlpSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.requests = make(chan map[string]interface{}, 10)
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		c.Check(req.Method, gc.Equals, "POST")
+		c.Check(req.URL.Path, gc.Equals, "/v1/traces")
+		c.Check(req.Header.Get("Content-Type"), gc.Equals, "application/json")
+		body, err := ioutil.ReadAll(req.Body)
+		c.Check(err, jc.ErrorIsNil)
+		var doc map[string]interface{}
+		c.Check(json.Unmarshal(body, &doc), jc.ErrorIsNil)
+		s.requests <- doc
+	}))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.clock = testclock.NewClock(time.Unix(1577836800, 0))
+}
+
+func (s *otlpSuite) config() trace.OTLPConfig {
+	return trace.OTLPConfig{
+		Endpoint:      s.server.URL,
+		ServiceName:   "jujud",
+		Attributes:    []trace.Attribute{trace.StringAttr("juju.agent", "machine-0")},
+		Clock:         s.clock,
+		BatchSize:     2,
+		FlushInterval: time.Second,
+	}
+}
+
+func (s *otlpSuite) nextRequest(c *gc.C) map[string]interface{} {
+	select {
+	case doc := <-s.requests:
+		return doc
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for spans")
+	}
+	return nil
+}
+
+func spansOf(c *gc.C, doc map[string]interface{}) []interface{} {
+	resourceSpans := doc["resourceSpans"].([]interface{})
+	c.Assert(resourceSpans, gc.HasLen, 1)
+	scopeSpans := resourceSpans[0].(map[string]interface{})["scopeSpans"].([]interface{})
+	c.Assert(scopeSpans, gc.HasLen, 1)
+	return scopeSpans[0].(map[string]interface{})["spans"].([]interface{})
+}
+
+func (s *otlpSuite) TestValidate(c *gc.C) {
+	config := s.config()
+	config.Endpoint = "localhost:4318"
+	_, err := trace.NewOTLPExporter(config)
+	c.Assert(err, gc.ErrorMatches, `endpoint "localhost:4318" not valid`)
+
+	config = s.config()
+	config.ServiceName = ""
+	_, err = trace.NewOTLPExporter(config)
+	c.Assert(err, gc.ErrorMatches, "empty ServiceName not valid")
+}
+
+func (s *otlpSuite) TestBatch(c *gc.C) {
+	exporter, err := trace.NewOTLPExporter(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, exporter)
+
+	tracer := trace.NewTracer(exporter, s.clock)
+	ctx, parent := tracer.Start(context.Background(), "Client(2).FullStatus", trace.SpanKindServer,
+		trace.StringAttr("juju.facade", "Client"),
+	)
+	_, child := tracer.Start(ctx, "mongo.txn", trace.SpanKindInternal)
+	child.RecordError(errors.New("aborted"))
+	child.End()
+	parent.End()
+
+	doc := s.nextRequest(c)
+	resource := doc["resourceSpans"].([]interface{})[0].(map[string]interface{})["resource"]
+	c.Check(resource, jc.DeepEquals, map[string]interface{}{
+		"attributes": []interface{}{
+			map[string]interface{}{"key": "service.name", "value": map[string]interface{}{"stringValue": "jujud"}},
+			map[string]interface{}{"key": "juju.agent", "value": map[string]interface{}{"stringValue": "machine-0"}},
+		},
+	})
+	spans := spansOf(c, doc)
+	c.Assert(spans, gc.HasLen, 2)
+	childSpan := spans[0].(map[string]interface{})
+	parentSpan := spans[1].(map[string]interface{})
+
+	c.Check(parentSpan["name"], gc.Equals, "Client(2).FullStatus")
+	c.Check(parentSpan["kind"], gc.Equals, float64(2))
+	c.Check(parentSpan["traceId"], gc.Equals, parent.SpanContext().TraceID.String())
+	c.Check(parentSpan["spanId"], gc.Equals, parent.SpanContext().SpanID.String())
+	c.Check(parentSpan["startTimeUnixNano"], gc.Equals, "1577836800000000000")
+	c.Check(parentSpan["status"], jc.DeepEquals, map[string]interface{}{"code": float64(0)})
+	_, ok := parentSpan["parentSpanId"]
+	c.Check(ok, jc.IsFalse)
+
+	c.Check(childSpan["parentSpanId"], gc.Equals, parent.SpanContext().SpanID.String())
+	c.Check(childSpan["status"], jc.DeepEquals, map[string]interface{}{"code": float64(2), "message": "aborted"})
+}
+
+func (s *otlpSuite) TestFlushInterval(c *gc.C) {
+	exporter, err := trace.NewOTLPExporter(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, exporter)
+
+	tracer := trace.NewTracer(exporter, s.clock)
+	_, span := tracer.Start(context.Background(), "op", trace.SpanKindInternal)
+	span.End()
+
+	select {
+	case <-s.requests:
+		c.Fatalf("spans sent before flush interval")
+	case <-time.After(coretesting.ShortWait):
+	}
+	err = s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spansOf(c, s.nextRequest(c)), gc.HasLen, 1)
+}
+
+func (s *otlpSuite) TestFlushOnKill(c *gc.C) {
+	exporter, err := trace.NewOTLPExporter(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+
+	tracer := trace.NewTracer(exporter, s.clock)
+	_, span := tracer.Start(context.Background(), "op", trace.SpanKindInternal)
+	span.End()
+	// Wait for the span to be queued before killing.
+	err = s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	workertest.CleanKill(c, exporter)
+	c.Assert(spansOf(c, s.nextRequest(c)), gc.HasLen, 1)
+}
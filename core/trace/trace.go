@@ -0,0 +1,372 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package trace provides request tracing, so that an API call can be
+// followed through the API server, the database transactions it runs
+// and the worker activity it triggers.
+//
+// The model follows that of OpenTelemetry: a trace is a tree of spans,
+// each recording a named operation with a start and end time. Span
+// contexts are propagated between processes in the W3C Trace Context
+// "traceparent" format, and finished spans are handed to an Exporter,
+// such as one sending them to an OTLP collector.
+package trace
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+)
+
+// TraceID identifies a trace.
+type TraceID [16]byte
+
+// String returns the ID in lower case hex.
+func (id TraceID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is non-zero.
+func (id TraceID) IsValid() bool {
+	return id != TraceID{}
+}
+
+// SpanID identifies a span within a trace.
+type SpanID [8]byte
+
+// String returns the ID in lower case hex.
+func (id SpanID) String() string {
+	return hex.EncodeToString(id[:])
+}
+
+// IsValid reports whether the ID is non-zero.
+func (id SpanID) IsValid() bool {
+	return id != SpanID{}
+}
+
+// SpanContext holds the identity of a span, as propagated to the
+// operations it causes.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+// IsValid reports whether both the trace and span IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// traceParentVersion is the only version of the traceparent
+// format defined by the W3C Trace Context specification.
+const traceParentVersion = "00"
+
+// TraceParent returns the span context in the W3C Trace Context
+// "traceparent" format, or "" if it is not valid.
+func (sc SpanContext) TraceParent() string {
+	if !sc.IsValid() {
+		return ""
+	}
+	return fmt.Sprintf("%s-%s-%s-01", traceParentVersion, sc.TraceID, sc.SpanID)
+}
+
+// ParseTraceParent parses a span context in the W3C Trace Context
+// "traceparent" format.
+func ParseTraceParent(s string) (SpanContext, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 4 || parts[0] != traceParentVersion {
+		return SpanContext{}, errors.NotValidf("traceparent %q", s)
+	}
+	var sc SpanContext
+	if err := decodeID(sc.TraceID[:], parts[1]); err != nil {
+		return SpanContext{}, errors.NotValidf("trace ID in traceparent %q", s)
+	}
+	if err := decodeID(sc.SpanID[:], parts[2]); err != nil {
+		return SpanContext{}, errors.NotValidf("span ID in traceparent %q", s)
+	}
+	if len(parts[3]) != 2 {
+		return SpanContext{}, errors.NotValidf("flags in traceparent %q", s)
+	}
+	if !sc.IsValid() {
+		return SpanContext{}, errors.NotValidf("traceparent %q", s)
+	}
+	return sc, nil
+}
+
+func decodeID(dst []byte, s string) error {
+	if len(s) != hex.EncodedLen(len(dst)) || strings.ToLower(s) != s {
+		return errors.New("bad length or case")
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// SpanKind describes the relationship of a span to its callers.
+type SpanKind int
+
+const (
+	// SpanKindInternal is used for operations within a process.
+	SpanKindInternal SpanKind = iota
+
+	// SpanKindServer is used for the handling of a request
+	// made by another process.
+	SpanKindServer
+
+	// SpanKindConsumer is used for the handling of a message
+	// published by another process or component.
+	SpanKindConsumer
+)
+
+// Attribute is a key/value pair describing a span.
+type Attribute struct {
+	Key   string
+	Value string
+}
+
+// StringAttr returns an Attribute with the given key and value.
+func StringAttr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+// SpanData holds the details of a finished span.
+type SpanData struct {
+	Name         string
+	Kind         SpanKind
+	SpanContext  SpanContext
+	ParentSpanID SpanID
+	Start        time.Time
+	End          time.Time
+	Attributes   []Attribute
+	// Error holds the message of the error the operation
+	// failed with, if any.
+	Error string
+}
+
+// Exporter is implemented by types that deliver finished spans to a
+// trace collector. ExportSpan must not block.
+type Exporter interface {
+	ExportSpan(SpanData)
+}
+
+// Span represents an operation in progress.
+type Span interface {
+	// SpanContext returns the identity of the span.
+	SpanContext() SpanContext
+
+	// SetAttributes adds the given attributes to the span.
+	SetAttributes(attrs ...Attribute)
+
+	// RecordError records that the operation failed with the given
+	// error. It does nothing if err is nil.
+	RecordError(err error)
+
+	// End marks the operation as finished. Calls after the first
+	// have no effect.
+	End()
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span with the given name. If the context holds
+	// a span context, the new span is its child; otherwise a new trace
+	// is started. The returned context holds the new span's context.
+	Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span)
+}
+
+// NewTracer returns a Tracer that hands finished spans to the given
+// exporter, timing them with the given clock.
+func NewTracer(exporter Exporter, clock clock.Clock) Tracer {
+	return &tracer{
+		exporter: exporter,
+		clock:    clock,
+	}
+}
+
+type tracer struct {
+	exporter Exporter
+	clock    clock.Clock
+}
+
+// Start is part of the Tracer interface.
+func (t *tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	s := &span{
+		tracer: t,
+		data: SpanData{
+			Name:       name,
+			Kind:       kind,
+			Start:      t.clock.Now(),
+			Attributes: attrs,
+		},
+	}
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		s.data.SpanContext.TraceID = parent.TraceID
+		s.data.ParentSpanID = parent.SpanID
+	} else {
+		s.data.SpanContext.TraceID = newTraceID()
+	}
+	s.data.SpanContext.SpanID = newSpanID()
+	ctx = context.WithValue(ctx, tracerKey{}, Tracer(t))
+	return ContextWithSpanContext(ctx, s.data.SpanContext), s
+}
+
+type span struct {
+	tracer *tracer
+
+	mu    sync.Mutex
+	data  SpanData
+	ended bool
+}
+
+// SpanContext is part of the Span interface.
+func (s *span) SpanContext() SpanContext {
+	return s.data.SpanContext
+}
+
+// SetAttributes is part of the Span interface.
+func (s *span) SetAttributes(attrs ...Attribute) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Attributes = append(s.data.Attributes, attrs...)
+}
+
+// RecordError is part of the Span interface.
+func (s *span) RecordError(err error) {
+	if err == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data.Error = err.Error()
+}
+
+// End is part of the Span interface.
+func (s *span) End() {
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = s.tracer.clock.Now()
+	data := s.data
+	s.mu.Unlock()
+	s.tracer.exporter.ExportSpan(data)
+}
+
+// NoopTracer is a Tracer whose spans are discarded. It is used when
+// tracing is not configured.
+type NoopTracer struct{}
+
+// Start is part of the Tracer interface. The context is returned
+// unchanged, so that any incoming span context is still propagated.
+func (NoopTracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	sc, _ := SpanContextFromContext(ctx)
+	return ctx, noopSpan{sc}
+}
+
+type noopSpan struct {
+	sc SpanContext
+}
+
+func (s noopSpan) SpanContext() SpanContext { return s.sc }
+func (noopSpan) SetAttributes(...Attribute) {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+func newTraceID() (id TraceID) {
+	randomID(id[:])
+	return id
+}
+
+func newSpanID() (id SpanID) {
+	randomID(id[:])
+	return id
+}
+
+func randomID(b []byte) {
+	// An all-zero ID is invalid, so in the unlikely event of
+	// reading one we try again.
+	for {
+		if _, err := rand.Read(b); err != nil {
+			panic(fmt.Sprintf("cannot read random bytes: %v", err))
+		}
+		for _, v := range b {
+			if v != 0 {
+				return
+			}
+		}
+	}
+}
+
+type tracerKey struct{}
+
+// TracerFromContext returns the tracer that started the span held by the
+// context, or a NoopTracer if there is none. It allows code that is handed
+// a context, but not a tracer, to record spans as part of the same trace.
+func TracerFromContext(ctx context.Context) Tracer {
+	if t, ok := ctx.Value(tracerKey{}).(Tracer); ok {
+		return t
+	}
+	return NoopTracer{}
+}
+
+// StartSpan starts a span using the tracer held by the context. See
+// TracerFromContext.
+func StartSpan(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, Span) {
+	return TracerFromContext(ctx).Start(ctx, name, kind, attrs...)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a context holding the given span
+// context, so that spans started with it become children of that span.
+// It is used to continue a trace propagated from another process.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context held by the context,
+// if any.
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// TraceParentFromContext returns the span context held by the context
+// in the "traceparent" format, or "" if there is none. It is used to
+// propagate the trace in messages to other processes.
+func TraceParentFromContext(ctx context.Context) string {
+	sc, _ := SpanContextFromContext(ctx)
+	return sc.TraceParent()
+}
+
+// ContextWithNewTrace returns a context holding the span context of a
+// new trace. It is used by processes, such as clients, that propagate
+// a trace to others without recording spans of their own.
+func ContextWithNewTrace(ctx context.Context) context.Context {
+	return ContextWithSpanContext(ctx, SpanContext{
+		TraceID: newTraceID(),
+		SpanID:  newSpanID(),
+	})
+}
+
+// ContextWithTraceParent returns a context holding the span context in
+// the given "traceparent" value. If the value is empty or not valid, the
+// context is returned unchanged, so that a bad value from a client only
+// results in a new trace being started.
+func ContextWithTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+	sc, err := ParseTraceParent(traceParent)
+	if err != nil {
+		return ctx
+	}
+	return ContextWithSpanContext(ctx, sc)
+}
@@ -0,0 +1,154 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package trace_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/trace"
+)
+
+type traceSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&traceSuite{})
+
+const validTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func (s *traceSuite) TestParseTraceParent(c *gc.C) {
+	sc, err := trace.ParseTraceParent(validTraceParent)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sc.TraceID.String(), gc.Equals, "4bf92f3577b34da6a3ce929d0e0e4736")
+	c.Assert(sc.SpanID.String(), gc.Equals, "00f067aa0ba902b7")
+	c.Assert(sc.TraceParent(), gc.Equals, validTraceParent)
+}
+
+func (s *traceSuite) TestParseTraceParentInvalid(c *gc.C) {
+	for i, value := range []string{
+		"",
+		"garbage",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902bz-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1",
+	} {
+		c.Logf("test %d: %q", i, value)
+		_, err := trace.ParseTraceParent(value)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *traceSuite) TestInvalidSpanContext(c *gc.C) {
+	c.Assert(trace.SpanContext{}.TraceParent(), gc.Equals, "")
+}
+
+func (s *traceSuite) TestSpans(c *gc.C) {
+	clock := testclock.NewClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	exporter := &trace.MemoryExporter{}
+	tracer := trace.NewTracer(exporter, clock)
+
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.SpanKindServer,
+		trace.StringAttr("facade", "Client"),
+	)
+	clock.Advance(time.Second)
+	_, child := tracer.Start(ctx, "child", trace.SpanKindInternal)
+	child.RecordError(errors.New("boom"))
+	clock.Advance(time.Second)
+	child.End()
+	parent.SetAttributes(trace.StringAttr("result", "ok"))
+	parent.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	c.Assert(spans, gc.HasLen, 2)
+	childData, parentData := spans[0], spans[1]
+
+	c.Check(parentData.Name, gc.Equals, "parent")
+	c.Check(parentData.Kind, gc.Equals, trace.SpanKindServer)
+	c.Check(parentData.ParentSpanID.IsValid(), jc.IsFalse)
+	c.Check(parentData.End.Sub(parentData.Start), gc.Equals, 2*time.Second)
+	c.Check(parentData.Attributes, jc.DeepEquals, []trace.Attribute{
+		{Key: "facade", Value: "Client"},
+		{Key: "result", Value: "ok"},
+	})
+	c.Check(parentData.Error, gc.Equals, "")
+
+	c.Check(childData.Name, gc.Equals, "child")
+	c.Check(childData.SpanContext.TraceID, gc.Equals, parentData.SpanContext.TraceID)
+	c.Check(childData.ParentSpanID, gc.Equals, parentData.SpanContext.SpanID)
+	c.Check(childData.SpanContext.SpanID, gc.Not(gc.Equals), parentData.SpanContext.SpanID)
+	c.Check(childData.Error, gc.Equals, "boom")
+}
+
+func (s *traceSuite) TestRemoteParent(c *gc.C) {
+	exporter := &trace.MemoryExporter{}
+	tracer := trace.NewTracer(exporter, testclock.NewClock(time.Time{}))
+
+	ctx := trace.ContextWithTraceParent(context.Background(), validTraceParent)
+	ctx, span := tracer.Start(ctx, "op", trace.SpanKindServer)
+	span.End()
+
+	spans := exporter.Spans()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(spans[0].SpanContext.TraceID.String(), gc.Equals, "4bf92f3577b34da6a3ce929d0e0e4736")
+	c.Check(spans[0].ParentSpanID.String(), gc.Equals, "00f067aa0ba902b7")
+	c.Check(trace.TraceParentFromContext(ctx), gc.Equals, spans[0].SpanContext.TraceParent())
+}
+
+func (s *traceSuite) TestBadTraceParentIgnored(c *gc.C) {
+	ctx := trace.ContextWithTraceParent(context.Background(), "garbage")
+	_, ok := trace.SpanContextFromContext(ctx)
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *traceSuite) TestContextWithNewTrace(c *gc.C) {
+	ctx1 := trace.ContextWithNewTrace(context.Background())
+	sc1, ok := trace.SpanContextFromContext(ctx1)
+	c.Assert(ok, jc.IsTrue)
+	ctx2 := trace.ContextWithNewTrace(context.Background())
+	sc2, ok := trace.SpanContextFromContext(ctx2)
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(sc1.TraceID, gc.Not(gc.Equals), sc2.TraceID)
+}
+
+func (s *traceSuite) TestNoopTracerPropagates(c *gc.C) {
+	ctx := trace.ContextWithTraceParent(context.Background(), validTraceParent)
+	ctx, span := trace.NoopTracer{}.Start(ctx, "op", trace.SpanKindServer)
+	span.End()
+	c.Assert(trace.TraceParentFromContext(ctx), gc.Equals, validTraceParent)
+	c.Assert(span.SpanContext().TraceParent(), gc.Equals, validTraceParent)
+}
+
+func (s *traceSuite) TestStartSpanUsesContextTracer(c *gc.C) {
+	exporter := &trace.MemoryExporter{}
+	tracer := trace.NewTracer(exporter, testclock.NewClock(time.Time{}))
+
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.SpanKindServer)
+	_, child := trace.StartSpan(ctx, "child", trace.SpanKindInternal)
+	child.End()
+	parent.End()
+
+	spans := exporter.Spans()
+	c.Assert(spans, gc.HasLen, 2)
+	c.Check(spans[0].Name, gc.Equals, "child")
+	c.Check(spans[0].ParentSpanID, gc.Equals, parent.SpanContext().SpanID)
+}
+
+func (s *traceSuite) TestStartSpanWithoutTracer(c *gc.C) {
+	ctx := trace.ContextWithTraceParent(context.Background(), validTraceParent)
+	_, span := trace.StartSpan(ctx, "op", trace.SpanKindInternal)
+	span.End()
+	c.Assert(span.SpanContext().TraceParent(), gc.Equals, validTraceParent)
+}
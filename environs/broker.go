@@ -186,3 +186,14 @@ type LXDProfiler interface {
 	// LXDProfileNames returns all the profiles associated to a container name
 	LXDProfileNames(containerName string) ([]string, error)
 }
+
+// InstanceInterruptionChecker is an optional interface implemented by
+// brokers that can start interruptible instances, such as EC2 spot
+// instances or GCE preemptible instances, which the cloud may reclaim
+// at short notice.
+type InstanceInterruptionChecker interface {
+	// InterruptedInstances returns the IDs of those of the given
+	// instances that the cloud has interrupted, or has given notice
+	// that it will interrupt. Unknown instance IDs are ignored.
+	InterruptedInstances(ctx context.ProviderCallContext, ids ...instance.Id) ([]instance.Id, error)
+}
@@ -245,6 +245,11 @@ const (
 	// endpoint bindings.
 	DefaultSpace = "default-space"
 
+	// PreferIPv6Key, when true, causes IPv6 addresses to be preferred
+	// over IPv4 addresses of the same scope when selecting a machine's
+	// preferred addresses and ordering network-get ingress addresses.
+	PreferIPv6Key = "prefer-ipv6"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -425,6 +430,7 @@ var defaultConfigValues = map[string]interface{}{
 	"ssl-hostname-verification":  true,
 	"proxy-ssh":                  false,
 	DefaultSpace:                 "",
+	PreferIPv6Key:                false,
 	// Why is net-bond-reconfigure-delay set to 17 seconds?
 	//
 	// The value represents the amount of time in seconds to sleep
@@ -854,6 +860,13 @@ func (c *Config) DefaultSpace() string {
 	return c.asString(DefaultSpace)
 }
 
+// PreferIPv6 reports whether IPv6 addresses should be preferred over
+// IPv4 addresses of the same scope.
+func (c *Config) PreferIPv6() bool {
+	value, _ := c.defined[PreferIPv6Key].(bool)
+	return value
+}
+
 // DefaultSeries returns the configured default Ubuntu series for the environment,
 // and whether the default series was explicitly configured on the environment.
 func (c *Config) DefaultSeries() (string, bool) {
@@ -1545,6 +1558,7 @@ var alwaysOptional = schema.Defaults{
 	ContainerInheritPropertiesKey: schema.Omit,
 	BackupDirKey:                  schema.Omit,
 	DefaultSpace:                  schema.Omit,
+	PreferIPv6Key:                 schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -2058,4 +2072,9 @@ data of the store. (default false)`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	PreferIPv6Key: {
+		Description: "Whether IPv6 addresses are preferred over IPv4 addresses when selecting machine and ingress addresses",
+		Type:        environschema.Tbool,
+		Group:       environschema.EnvironGroup,
+	},
 }
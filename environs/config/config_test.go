@@ -577,6 +577,19 @@ var configTests = []configTest{
 		attrs: minimalConfigAttrs.Merge(testing.Attrs{
 			"default-space": "bar",
 		}),
+	}, {
+		about:       "Prefer-ipv6 takes a boolean value",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"prefer-ipv6": true,
+		}),
+	}, {
+		about:       "Invalid prefer-ipv6",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"prefer-ipv6": "invalid",
+		}),
+		err: `prefer-ipv6: expected bool, got string\("invalid"\)`,
 	},
 }
 
@@ -653,6 +666,9 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		c.Assert(cfg.DefaultSpace(), gc.Equals, m)
 	}
 
+	preferIPv6, _ := test.attrs["prefer-ipv6"].(bool)
+	c.Assert(cfg.PreferIPv6(), gc.Equals, preferIPv6)
+
 	keys, _ := test.attrs["authorized-keys"].(string)
 	c.Assert(cfg.AuthorizedKeys(), gc.Equals, keys)
 
@@ -41,3 +41,19 @@ type InstanceFirewaller interface {
 	// address rules for that port range.
 	IngressRules(ctx context.ProviderCallContext, machineId string) ([]network.IngressRule, error)
 }
+
+// InstanceEgressFirewaller is implemented by instances whose provider
+// can restrict the outbound traffic originating from them.
+type InstanceEgressFirewaller interface {
+	// SetEgressCIDRs restricts new outbound connections from the
+	// instance, which should have been started with the given machine
+	// id, to the given destination CIDRs. A nil slice removes any
+	// restriction. Implementations may return an error satisfying
+	// errors.IsNotSupported for CIDRs they cannot enforce.
+	SetEgressCIDRs(ctx context.ProviderCallContext, machineId string, cidrs []string) error
+
+	// EgressCIDRs returns the sorted destination CIDRs to which
+	// outbound traffic from the instance is restricted, or nil if
+	// the instance is unrestricted.
+	EgressCIDRs(ctx context.ProviderCallContext, machineId string) ([]string, error)
+}
@@ -489,6 +489,16 @@ type Firewaller interface {
 	IngressRules(ctx context.ProviderCallContext) ([]network.IngressRule, error)
 }
 
+// EgressFirewallingProvider is implemented by providers whose instances
+// implement instances.InstanceEgressFirewaller, so that the outbound
+// traffic of their machines can be restricted when the model uses the
+// FwInstance firewall mode.
+type EgressFirewallingProvider interface {
+	// SupportsInstanceEgress returns whether the provider's instances
+	// can have their outbound traffic restricted.
+	SupportsInstanceEgress() bool
+}
+
 // InstanceTagger is an interface that can be used for tagging instances.
 type InstanceTagger interface {
 	// TagInstance tags the given instance with the specified tags.
@@ -0,0 +1,37 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+)
+
+// CheckConstraints returns an error if the hardware characteristics
+// detected on a manually provisioned machine do not satisfy the given
+// constraints. Characteristics that were not detected are not checked.
+func CheckConstraints(hc instance.HardwareCharacteristics, cons constraints.Value) error {
+	var unmet []string
+	if cons.HasArch() && hc.Arch != nil && *hc.Arch != *cons.Arch {
+		unmet = append(unmet, fmt.Sprintf("arch (%s, not %s)", *hc.Arch, *cons.Arch))
+	}
+	checkMin := func(name string, want, have *uint64, unit string) {
+		if want != nil && have != nil && *have < *want {
+			unmet = append(unmet, fmt.Sprintf("%s (%d%s < %d%s)", name, *have, unit, *want, unit))
+		}
+	}
+	checkMin("cores", cons.CpuCores, hc.CpuCores, "")
+	checkMin("cpu-power", cons.CpuPower, hc.CpuPower, "")
+	checkMin("mem", cons.Mem, hc.Mem, "M")
+	checkMin("root-disk", cons.RootDisk, hc.RootDisk, "M")
+	if len(unmet) > 0 {
+		return errors.Errorf("machine does not satisfy constraints: %s", strings.Join(unmet, ", "))
+	}
+	return nil
+}
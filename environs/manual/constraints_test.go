@@ -0,0 +1,49 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package manual_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/manual"
+	"github.com/juju/juju/testing"
+)
+
+type constraintsSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&constraintsSuite{})
+
+func (s *constraintsSuite) TestCheckConstraints(c *gc.C) {
+	hc := instance.MustParseHardware("arch=amd64 cores=4 mem=8192M")
+	for i, test := range []struct {
+		cons string
+		err  string
+	}{{
+		cons: "",
+	}, {
+		cons: "arch=amd64 cores=4 mem=8G",
+	}, {
+		// root-disk was not detected, so it is not checked.
+		cons: "root-disk=100G",
+	}, {
+		cons: "arch=arm64",
+		err:  `machine does not satisfy constraints: arch \(amd64, not arm64\)`,
+	}, {
+		cons: "cores=8 mem=16G",
+		err:  `machine does not satisfy constraints: cores \(4 < 8\), mem \(8192M < 16384M\)`,
+	}} {
+		c.Logf("test %d: %q", i, test.cons)
+		err := manual.CheckConstraints(hc, constraints.MustParse(test.cons))
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
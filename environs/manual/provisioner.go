@@ -9,9 +9,11 @@ import (
 	"io"
 
 	"github.com/juju/loggo"
+	"github.com/juju/utils/ssh"
 	"github.com/juju/utils/winrm"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/constraints"
 )
 
 var (
@@ -51,6 +53,21 @@ type ProvisionMachineArgs struct {
 	// ubuntu user's ~/.ssh/authorized_keys.
 	AuthorizedKeys string
 
+	// SSHOptions contains options for SSH connections to the machine,
+	// such as the port and identity files to use. If nil, the defaults
+	// are used.
+	SSHOptions *ssh.Options
+
+	// NonInteractive, if true, means that there is nobody to answer
+	// password prompts, so SSH password authentication and PTY
+	// allocation are disabled, and sudo fails rather than prompting.
+	NonInteractive bool
+
+	// Constraints, if set, are checked against the hardware
+	// characteristics detected on the machine, which is not
+	// provisioned if they are not satisfied.
+	Constraints constraints.Value
+
 	// WinRM contains keys and client interface api with the remote windows machine
 	WinRM WinRMArgs
 
@@ -7,3 +7,5 @@ package sshprovisioner
 const (
 	DetectionScript = detectionScript
 )
+
+var InitUbuntuUserWithOptions = initUbuntuUser
@@ -5,6 +5,9 @@
 package sshprovisioner_test
 
 import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	jc "github.com/juju/testing/checkers"
@@ -29,7 +32,7 @@ func (s *initialisationSuite) TestDetectSeries(c *gc.C) {
 		"processor: 0",
 	}, "\n")
 	defer installFakeSSH(c, sshprovisioner.DetectionScript, response, 0)()
-	_, series, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics("whatever")
+	_, series, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics("whatever", nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(series, gc.Equals, "edgy")
 }
@@ -44,11 +47,11 @@ func (s *initialisationSuite) TestDetectionError(c *gc.C) {
 	// if the script fails for whatever reason, then checkProvisioned
 	// will return an error. stderr will be included in the error message.
 	defer installFakeSSH(c, sshprovisioner.DetectionScript, []string{scriptResponse, "oh noes"}, 33)()
-	hc, _, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics("hostname")
+	hc, _, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics("hostname", nil)
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 33 \\(oh noes\\)")
 	// if the script doesn't fail, stderr is simply ignored.
 	defer installFakeSSH(c, sshprovisioner.DetectionScript, []string{scriptResponse, "non-empty-stderr"}, 0)()
-	hc, _, err = sshprovisioner.DetectSeriesAndHardwareCharacteristics("hostname")
+	hc, _, err = sshprovisioner.DetectSeriesAndHardwareCharacteristics("hostname", nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(hc.String(), gc.Equals, "arch=armhf cores=1 mem=4M")
 }
@@ -120,7 +123,7 @@ func (s *initialisationSuite) TestDetectHardwareCharacteristics(c *gc.C) {
 		c.Logf("test %d: %s", i, test.summary)
 		scriptResponse := strings.Join(test.scriptResponse, "\n")
 		defer installFakeSSH(c, sshprovisioner.DetectionScript, scriptResponse, 0)()
-		hc, _, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics("hostname")
+		hc, _, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics("hostname", nil)
 		c.Assert(err, jc.ErrorIsNil)
 		c.Assert(hc.String(), gc.Equals, test.expectedHc)
 	}
@@ -129,25 +132,25 @@ func (s *initialisationSuite) TestDetectHardwareCharacteristics(c *gc.C) {
 func (s *initialisationSuite) TestCheckProvisioned(c *gc.C) {
 	listCmd := service.ListServicesScript()
 	defer installFakeSSH(c, listCmd, "", 0)()
-	provisioned, err := sshprovisioner.CheckProvisioned("example.com")
+	provisioned, err := sshprovisioner.CheckProvisioned("example.com", nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsFalse)
 
 	defer installFakeSSH(c, listCmd, "juju...", 0)()
-	provisioned, err = sshprovisioner.CheckProvisioned("example.com")
+	provisioned, err = sshprovisioner.CheckProvisioned("example.com", nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsTrue)
 
 	// stderr should not affect result.
 	defer installFakeSSH(c, listCmd, []string{"", "non-empty-stderr"}, 0)()
-	provisioned, err = sshprovisioner.CheckProvisioned("example.com")
+	provisioned, err = sshprovisioner.CheckProvisioned("example.com", nil)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(provisioned, jc.IsFalse)
 
 	// if the script fails for whatever reason, then checkProvisioned
 	// will return an error. stderr will be included in the error message.
 	defer installFakeSSH(c, listCmd, []string{"non-empty-stdout", "non-empty-stderr"}, 255)()
-	_, err = sshprovisioner.CheckProvisioned("example.com")
+	_, err = sshprovisioner.CheckProvisioned("example.com", nil)
 	c.Assert(err, gc.ErrorMatches, "subprocess encountered error code 255 \\(non-empty-stderr\\)")
 }
 
@@ -163,6 +166,31 @@ func (s *initialisationSuite) TestInitUbuntuUserExisting(c *gc.C) {
 	sshprovisioner.InitUbuntuUser("testhost", "testuser", "", nil, nil)
 }
 
+func (s *initialisationSuite) TestInitUbuntuUserNonInteractive(c *gc.C) {
+	// Record the arguments of each ssh call. The ubuntu@ login
+	// fails, so that the specified login is used.
+	fakebin := c.MkDir()
+	argsFile := filepath.Join(fakebin, "args")
+	script := fmt.Sprintf(`#!/bin/bash --norc
+echo "$@" >> %[1]s
+cat > /dev/null
+[ "$(wc -l < %[1]s)" -gt 1 ]
+`, argsFile)
+	err := ioutil.WriteFile(filepath.Join(fakebin, "ssh"), []byte(script), 0777)
+	c.Assert(err, jc.ErrorIsNil)
+	s.PatchEnvPathPrepend(fakebin)
+
+	err = sshprovisioner.InitUbuntuUserWithOptions("testhost", "testuser", "", nil, false, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadFile(argsFile)
+	c.Assert(err, jc.ErrorIsNil)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	c.Assert(lines, gc.HasLen, 2)
+	c.Check(lines[1], jc.Contains, "PasswordAuthentication no")
+	c.Check(lines[1], jc.Contains, "testuser@testhost sudo -n ")
+	c.Check(lines[1], gc.Not(jc.Contains), " -t ")
+}
+
 func (s *initialisationSuite) TestInitUbuntuUserError(c *gc.C) {
 	defer installFakeSSH(c, "", []string{"", "failed to create ubuntu user"}, 123)()
 	defer installFakeSSH(c, "", "", 1)() // simulate failure of ubuntu@ login
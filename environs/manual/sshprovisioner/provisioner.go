@@ -32,12 +32,12 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	// the ubuntu user's authorized_keys file with the public keys in the current
 	// user's ~/.ssh directory. The authenticationworker will later update the
 	// ubuntu user's authorized_keys.
-	if err = InitUbuntuUser(args.Host, args.User,
-		args.AuthorizedKeys, args.Stdin, args.Stdout); err != nil {
+	if err = initUbuntuUser(args.Host, args.User,
+		args.AuthorizedKeys, args.SSHOptions, !args.NonInteractive, args.Stdin, args.Stdout); err != nil {
 		return "", err
 	}
 
-	machineParams, err := gatherMachineParams(args.Host)
+	machineParams, err := gatherMachineParams(args.Host, args.SSHOptions, args.Constraints)
 	if err != nil {
 		return "", err
 	}
@@ -60,7 +60,7 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	}
 
 	// Finally, provision the machine agent.
-	err = runProvisionScript(provisioningScript, args.Host, args.Stderr)
+	err = runProvisionScript(provisioningScript, args.Host, args.SSHOptions, args.Stderr)
 	if err != nil {
 		return machineId, err
 	}
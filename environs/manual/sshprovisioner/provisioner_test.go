@@ -11,6 +11,7 @@ import (
 	"github.com/juju/os/series"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/shell"
+	"github.com/juju/utils/ssh"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cloudconfig"
 	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs/manual"
 	"github.com/juju/juju/environs/manual/sshprovisioner"
@@ -127,6 +129,50 @@ func (s *provisionerSuite) TestProvisionMachine(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "error checking if provisioned: subprocess encountered error code 255")
 }
 
+func (s *provisionerSuite) TestProvisionMachineConstraintsNotSatisfied(c *gc.C) {
+	args := s.getArgs(c)
+	args.User = "ubuntu"
+	args.Constraints = constraints.MustParse("mem=8G")
+
+	defer fakeSSH{
+		Series:             series.DefaultSupportedLTS(),
+		Arch:               "amd64",
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+	}.install(c).Restore()
+
+	machineId, err := sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, gc.ErrorMatches, `machine does not satisfy constraints: mem \(4M < 8192M\)`)
+	c.Assert(machineId, gc.Equals, "")
+
+	machines, err := s.State.AllMachines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 0)
+}
+
+func (s *provisionerSuite) TestProvisionMachineUsesDetectSeriesAndHardwareCharacteristics(c *gc.C) {
+	args := s.getArgs(c)
+	args.User = "ubuntu"
+	args.Constraints = constraints.MustParse("mem=8G")
+
+	var detectedHost string
+	s.PatchValue(&sshprovisioner.DetectSeriesAndHardwareCharacteristics,
+		func(host string, _ *ssh.Options) (instance.HardwareCharacteristics, string, error) {
+			detectedHost = host
+			return instance.MustParseHardware("arch=amd64 mem=2G"), series.DefaultSupportedLTS(), nil
+		},
+	)
+	defer fakeSSH{
+		InitUbuntuUser:     true,
+		SkipProvisionAgent: true,
+		SkipDetection:      true,
+	}.install(c).Restore()
+
+	_, err := sshprovisioner.ProvisionMachine(args)
+	c.Assert(err, gc.ErrorMatches, `machine does not satisfy constraints: mem \(2048M < 8192M\)`)
+	c.Assert(detectedHost, gc.Equals, args.Host)
+}
+
 func (s *provisionerSuite) TestFinishInstancConfig(c *gc.C) {
 	var series = series.DefaultSupportedLTS()
 	const arch = "amd64"
@@ -22,6 +22,7 @@ import (
 	"github.com/juju/juju/cloudconfig/cloudinit"
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/cloudconfig/sshinit"
+	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/model"
 	"github.com/juju/juju/environs/manual"
@@ -40,6 +41,14 @@ import (
 // authorizedKeys may be empty, in which case the file
 // will be created and left empty.
 func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io.Writer) error {
+	return initUbuntuUser(host, login, authorizedKeys, nil, true, read, write)
+}
+
+// initUbuntuUser is InitUbuntuUser with the given SSH options. If
+// interactive is false, the specified login must be able to log in
+// with a key and use sudo without a password, as nothing is prompted
+// for.
+func initUbuntuUser(host, login, authorizedKeys string, sshOptions *ssh.Options, interactive bool, read io.Reader, write io.Writer) error {
 	logger.Infof("initialising %q, user %q", host, login)
 
 	// To avoid unnecessary prompting for the specified login,
@@ -49,7 +58,7 @@ func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io
 	//
 	// Note that we explicitly do not allocate a PTY, so we
 	// get a failure if sudo prompts.
-	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, nil)
+	cmd := ssh.Command("ubuntu@"+host, []string{"sudo", "-n", "true"}, sshOptions)
 	if cmd.Run() == nil {
 		logger.Infof("ubuntu user is already initialised")
 		return nil
@@ -62,9 +71,17 @@ func InitUbuntuUser(host, login, authorizedKeys string, read io.Reader, write io
 	}
 	script := fmt.Sprintf(initUbuntuScript, utils.ShQuote(authorizedKeys))
 	var options ssh.Options
-	options.AllowPasswordAuthentication()
-	options.EnablePTY()
-	cmd = ssh.Command(host, []string{"sudo", "/bin/bash -c " + utils.ShQuote(script)}, &options)
+	if sshOptions != nil {
+		options = *sshOptions
+	}
+	sudo := []string{"sudo"}
+	if interactive {
+		options.AllowPasswordAuthentication()
+		options.EnablePTY()
+	} else {
+		sudo = append(sudo, "-n")
+	}
+	cmd = ssh.Command(host, append(sudo, "/bin/bash -c "+utils.ShQuote(script)), &options)
 	var stderr bytes.Buffer
 	cmd.Stdin = read
 	cmd.Stdout = write
@@ -96,11 +113,12 @@ fi`
 // DetectSeriesAndHardwareCharacteristics detects the OS
 // series and hardware characteristics of the remote machine
 // by connecting to the machine and executing a bash script.
+// sshOptions may be nil, in which case the default options are used.
 var DetectSeriesAndHardwareCharacteristics = detectSeriesAndHardwareCharacteristics
 
-func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCharacteristics, series string, err error) {
+func detectSeriesAndHardwareCharacteristics(host string, sshOptions *ssh.Options) (hc instance.HardwareCharacteristics, series string, err error) {
 	logger.Infof("Detecting series and characteristics on %s", host)
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, sshOptions)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -160,15 +178,16 @@ func detectSeriesAndHardwareCharacteristics(host string) (hc instance.HardwareCh
 }
 
 // CheckProvisioned checks if any juju init service already
-// exist on the host machine.
+// exist on the host machine. sshOptions may be nil, in which
+// case the default options are used.
 var CheckProvisioned = checkProvisioned
 
-func checkProvisioned(host string) (bool, error) {
+func checkProvisioned(host string, sshOptions *ssh.Options) (bool, error) {
 	logger.Infof("Checking if %s is already provisioned", host)
 
 	script := service.ListServicesScript()
 
-	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, nil)
+	cmd := ssh.Command("ubuntu@"+host, []string{"/bin/bash"}, sshOptions)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -210,7 +229,7 @@ cat /proc/cpuinfo`
 // The hostname supplied should not include a username.
 // If we can, we will reverse lookup the hostname by its IP address, and use
 // the DNS resolved name, rather than the name that was supplied
-func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
+func gatherMachineParams(hostname string, sshOptions *ssh.Options, cons constraints.Value) (*params.AddMachineParams, error) {
 
 	// Generate a unique nonce for the machine.
 	uuid, err := utils.NewUUID()
@@ -223,7 +242,7 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		return nil, errors.Annotatef(err, "failed to compute public address for %q", hostname)
 	}
 
-	provisioned, err := checkProvisioned(hostname)
+	provisioned, err := CheckProvisioned(hostname, sshOptions)
 	if err != nil {
 		return nil, errors.Annotatef(err, "error checking if provisioned")
 	}
@@ -231,10 +250,13 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 		return nil, manual.ErrProvisioned
 	}
 
-	hc, series, err := DetectSeriesAndHardwareCharacteristics(hostname)
+	hc, series, err := DetectSeriesAndHardwareCharacteristics(hostname, sshOptions)
 	if err != nil {
 		return nil, errors.Annotatef(err, "error detecting linux hardware characteristics")
 	}
+	if err := manual.CheckConstraints(hc, cons); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	// There will never be a corresponding "instance" that any provider
 	// knows about. This is fine, and works well with the provisioner
@@ -254,9 +276,10 @@ func gatherMachineParams(hostname string) (*params.AddMachineParams, error) {
 	return machineParams, nil
 }
 
-func runProvisionScript(script, host string, progressWriter io.Writer) error {
+func runProvisionScript(script, host string, sshOptions *ssh.Options, progressWriter io.Writer) error {
 	params := sshinit.ConfigureParams{
 		Host:           "ubuntu@" + host,
+		SSHOptions:     sshOptions,
 		ProgressWriter: progressWriter,
 	}
 	return sshinit.RunConfigureScript(script, params)
@@ -37,6 +37,9 @@ func ProvisionMachine(args manual.ProvisionMachineArgs) (machineId string, err e
 	if err != nil {
 		return "", err
 	}
+	if err := manual.CheckConstraints(machineParams.HardwareCharacteristics, args.Constraints); err != nil {
+		return "", errors.Trace(err)
+	}
 
 	machineId, err = manual.RecordMachineInState(args.Client, *machineParams)
 	if err != nil {
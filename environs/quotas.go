@@ -0,0 +1,91 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/context"
+)
+
+// Quota describes a limit that a cloud places on the use of a resource
+// in a region, and how much of the resource is already in use.
+type Quota struct {
+	// Name is the name of the quota, e.g. "vCPUs" or "instances".
+	Name string
+
+	// Limit is the most of the resource that may be used.
+	Limit float64
+
+	// Usage is how much of the resource is currently in use.
+	Usage float64
+}
+
+// Available returns how much more of the resource may be used.
+func (q Quota) Available() float64 {
+	if q.Usage >= q.Limit {
+		return 0
+	}
+	return q.Limit - q.Usage
+}
+
+// QuotaChecker is an interface that may be implemented by an Environ
+// that can report the live quotas of its cloud region.
+type QuotaChecker interface {
+	// Quotas returns the quotas of the environ's cloud region.
+	Quotas(ctx context.ProviderCallContext) ([]Quota, error)
+
+	// CheckInstanceQuotas returns an error satisfying IsQuotaExceeded
+	// if starting an instance with the given parameters would exceed
+	// one of the region's quotas.
+	CheckInstanceQuotas(ctx context.ProviderCallContext, args StartInstanceParams) error
+}
+
+// CheckQuotas checks that the required amounts of resources, keyed by
+// quota name, are available under the given quotas. Resources without a
+// quota are not limited. The error returned for the first quota that
+// would be exceeded satisfies IsQuotaExceeded.
+func CheckQuotas(quotas []Quota, required map[string]float64) error {
+	for _, quota := range quotas {
+		amount, ok := required[quota.Name]
+		if !ok || amount <= quota.Available() {
+			continue
+		}
+		return NewQuotaExceededError(quota.Name)
+	}
+	return nil
+}
+
+type quotaExceededError struct {
+	quota string
+}
+
+// Error is part of the error interface. The message is kept short, as
+// it is shown as the status of the machine that could not be started.
+func (e *quotaExceededError) Error() string {
+	return fmt.Sprintf("quota exceeded: %s", e.quota)
+}
+
+// AvailabilityZoneIndependent is part of the AvailabilityZoneError
+// interface. Quotas apply to a whole region, so there is no point in
+// trying another zone.
+func (e *quotaExceededError) AvailabilityZoneIndependent() bool {
+	return true
+}
+
+// NewQuotaExceededError returns an error satisfying IsQuotaExceeded for
+// the named quota. Providers use this to report quota errors returned
+// by their cloud API.
+func NewQuotaExceededError(quota string) error {
+	return errors.WithStack(&quotaExceededError{quota: quota})
+}
+
+// IsQuotaExceeded reports whether the error, or its cause, was returned
+// because a cloud quota would be exceeded.
+func IsQuotaExceeded(err error) bool {
+	_, ok := errors.Cause(err).(*quotaExceededError)
+	return ok
+}
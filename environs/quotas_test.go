@@ -0,0 +1,49 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+)
+
+type quotasSuite struct{}
+
+var _ = gc.Suite(&quotasSuite{})
+
+func (*quotasSuite) TestAvailable(c *gc.C) {
+	c.Assert(environs.Quota{Name: "vCPUs", Limit: 24, Usage: 20}.Available(), gc.Equals, 4.0)
+	c.Assert(environs.Quota{Name: "vCPUs", Limit: 24, Usage: 30}.Available(), gc.Equals, 0.0)
+}
+
+func (*quotasSuite) TestCheckQuotas(c *gc.C) {
+	quotas := []environs.Quota{
+		{Name: "instances", Limit: 10, Usage: 9},
+		{Name: "vCPUs", Limit: 24, Usage: 20},
+	}
+	err := environs.CheckQuotas(quotas, map[string]float64{
+		"instances": 1,
+		"vCPUs":     4,
+		"GPUs":      1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = environs.CheckQuotas(quotas, map[string]float64{
+		"instances": 1,
+		"vCPUs":     8,
+	})
+	c.Assert(err, gc.ErrorMatches, "quota exceeded: vCPUs")
+	c.Assert(environs.IsQuotaExceeded(err), jc.IsTrue)
+	c.Assert(environs.IsAvailabilityZoneIndependent(err), jc.IsTrue)
+}
+
+func (*quotasSuite) TestIsQuotaExceeded(c *gc.C) {
+	err := errors.Annotate(environs.NewQuotaExceededError("instances"), "starting instance")
+	c.Assert(err, gc.ErrorMatches, "starting instance: quota exceeded: instances")
+	c.Assert(environs.IsQuotaExceeded(err), jc.IsTrue)
+	c.Assert(environs.IsQuotaExceeded(errors.New("quota exceeded: instances")), jc.IsFalse)
+}
@@ -60,7 +60,10 @@ const MongoDbSnap = "mongodb-snap"
 const MongoDbSSTXN = "mongodb-sstxn"
 
 // MultiCloud tells Juju to allow a different IAAS cloud to the one the controller
-// was bootstrapped on to be added to the controller.
+// was bootstrapped on to be added to the controller, and machines to be placed
+// in other regions of the model's cloud or of other clouds, optionally with
+// another of the model owner's credentials (e.g. --to region=us-west-2 or
+// --to region=aws-gov/us-gov-west-1,credential=gov).
 const MultiCloud = "multi-cloud"
 
 // JujuV3 indicates that new CLI commands and behaviour for v3 should be enabled.
@@ -71,3 +74,9 @@ const JujuV3 = "juju-v3"
 // This feature is disabled during import and export of information, turning
 // this on will allow that to happen.
 const CMRMigrations = "cmr-migrations"
+
+// HTTPGateway enables the HTTP/JSON gateway, which allows facade methods
+// to be called with plain HTTP POST requests rather than over the
+// websocket RPC connection.
+// This value is only checked using the controller config "features" attribute.
+const HTTPGateway = "http-gateway"
@@ -113,6 +113,11 @@ func (s *cmdJujuSuite) TestApplicationUnset(c *gc.C) {
 func (s *cmdJujuSuite) TestApplicationGetIAASModel(c *gc.C) {
 	expected := `application: dummy-application
 application-config:
+  replace-machines-after:
+    description: How long a machine hosting units of this application may be down
+      before it is replaced (e.g. 15m); unset disables replacement
+    source: unset
+    type: string
   trust:
     default: false
     description: Does this application have access to trusted credentials
@@ -279,6 +284,11 @@ settings:
 func (s *cmdJujuSuite) TestApplicationGetWeirdYAML(c *gc.C) {
 	expected := `application: yaml-config
 application-config:
+  replace-machines-after:
+    description: How long a machine hosting units of this application may be down
+      before it is replaced (e.g. 15m); unset disables replacement
+    source: unset
+    type: string
   trust:
     default: false
     description: Does this application have access to trusted credentials
@@ -22,24 +22,50 @@ type Registry interface {
 	GetType(name string, version int) (reflect.Type, error)
 }
 
+// Option configures how the schema is generated.
+type Option func(*options)
+
+type options struct {
+	allVersions bool
+}
+
+// WithAllVersions causes a schema to be generated for every version of
+// each facade, rather than only the latest one.
+func WithAllVersions() Option {
+	return func(o *options) {
+		o.allVersions = true
+	}
+}
+
 // Generate a FacadeSchema from the APIServer
-func Generate(client APIServer) ([]FacadeSchema, error) {
+func Generate(client APIServer, opts ...Option) ([]FacadeSchema, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	registry := client.AllFacades()
 	facades := registry.List()
-	result := make([]FacadeSchema, len(facades))
-	for i, facade := range facades {
-		// select the latest version from the facade list
-		version := facade.Versions[len(facade.Versions)-1]
-
-		result[i].Name = facade.Name
-		result[i].Version = version
-
-		kind, err := registry.GetType(facade.Name, version)
-		if err != nil {
-			return nil, errors.Annotatef(err, "getting type for facade %s at version %d", facade.Name, version)
+	result := make([]FacadeSchema, 0, len(facades))
+	for _, facade := range facades {
+		// select the latest version from the facade list, unless
+		// all of them have been asked for.
+		versions := facade.Versions[len(facade.Versions)-1:]
+		if o.allVersions {
+			versions = facade.Versions
+		}
+		for _, version := range versions {
+			kind, err := registry.GetType(facade.Name, version)
+			if err != nil {
+				return nil, errors.Annotatef(err, "getting type for facade %s at version %d", facade.Name, version)
+			}
+			objType := rpcreflect.ObjTypeOf(kind)
+			result = append(result, FacadeSchema{
+				Name:    facade.Name,
+				Version: version,
+				Schema:  jsonschema.ReflectFromObjType(objType),
+			})
 		}
-		objType := rpcreflect.ObjTypeOf(kind)
-		result[i].Schema = jsonschema.ReflectFromObjType(objType)
 	}
 	return result, nil
 }
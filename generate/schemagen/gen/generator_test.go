@@ -44,6 +44,27 @@ func (s *GenSuite) TestResult(c *gc.C) {
 	})
 }
 
+func (s *GenSuite) TestResultAllVersions(c *gc.C) {
+	defer s.setup(c).Finish()
+
+	s.scenario(c,
+		s.expectList,
+		s.expectGetTypeAllVersions,
+	)
+	result, err := Generate(s.apiServer, WithAllVersions())
+	c.Check(err, jc.ErrorIsNil)
+
+	objtype := rpcreflect.ObjTypeOf(reflect.TypeOf(ResourcesFacade{}))
+	c.Assert(result, gc.HasLen, 4)
+	for i, schema := range result {
+		c.Check(schema, gc.DeepEquals, FacadeSchema{
+			Name:    "Resources",
+			Version: i + 1,
+			Schema:  jsonschema.ReflectFromObjType(objtype),
+		})
+	}
+}
+
 func (s *GenSuite) setup(c *gc.C) *gomock.Controller {
 	ctrl := gomock.NewController(c)
 
@@ -82,3 +103,10 @@ func (s *GenSuite) expectGetType() {
 	rExp := s.registry.EXPECT()
 	rExp.GetType("Resources", 4).Return(reflect.TypeOf(ResourcesFacade{}), nil)
 }
+
+func (s *GenSuite) expectGetTypeAllVersions() {
+	rExp := s.registry.EXPECT()
+	for version := 1; version <= 4; version++ {
+		rExp.GetType("Resources", version).Return(reflect.TypeOf(ResourcesFacade{}), nil)
+	}
+}
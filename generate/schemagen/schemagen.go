@@ -4,6 +4,7 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -12,15 +13,20 @@ import (
 	"github.com/juju/juju/generate/schemagen/gen"
 )
 
+var allVersions = flag.Bool("all-versions", false, "generate the schema of every facade version, not just the latest")
+
 func main() {
-	// the first argument here will be the name of the binary, so we ignore
-	// argument 0 when looking for the filepath.
-	if len(os.Args) != 2 {
+	flag.Parse()
+	if flag.NArg() != 1 {
 		fmt.Fprintln(os.Stderr, "Expected one argument: filepath of json schema to save.")
 		os.Exit(1)
 	}
 
-	result, err := gen.Generate(apiServerShim{})
+	var opts []gen.Option
+	if *allVersions {
+		opts = append(opts, gen.WithAllVersions())
+	}
+	result, err := gen.Generate(apiServerShim{}, opts...)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -32,7 +38,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	err = ioutil.WriteFile(os.Args[1], jsonSchema, 0644)
+	err = ioutil.WriteFile(flag.Arg(0), jsonSchema, 0644)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -90,14 +90,10 @@ type netplanBridger struct {
 var _ Bridger = (*netplanBridger)(nil)
 
 func (b *netplanBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int) error {
-	npDevices := make([]netplan.DeviceToBridge, len(devices))
-	for i, device := range devices {
-		npDevices[i] = netplan.DeviceToBridge(device)
-	}
 	params := netplan.ActivationParams{
 		Clock:     clock.WallClock,
 		Directory: b.Directory,
-		Devices:   npDevices,
+		Devices:   NetplanDevicesToBridge(devices),
 		Timeout:   b.Timeout,
 	}
 
@@ -121,6 +117,16 @@ func (b *netplanBridger) Bridge(devices []DeviceToBridge, reconfigureDelay int)
 	return nil
 }
 
+// NetplanDevicesToBridge converts devices chosen by the bridge policy
+// into the form used to bridge them through netplan.
+func NetplanDevicesToBridge(devices []DeviceToBridge) []netplan.DeviceToBridge {
+	npDevices := make([]netplan.DeviceToBridge, len(devices))
+	for i, device := range devices {
+		npDevices[i] = netplan.DeviceToBridge(device)
+	}
+	return npDevices
+}
+
 func newNetplanBridger(clock clock.Clock, timeout time.Duration, directory string) Bridger {
 	return &netplanBridger{
 		Clock:     clock,
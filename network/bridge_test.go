@@ -9,9 +9,11 @@ import (
 
 	"github.com/juju/clock"
 	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/network/netplan"
 )
 
 // A note regarding the use of clock.WallClock in these unit tests.
@@ -97,3 +99,18 @@ func (*BridgeSuite) TestENIBridgerWithDryRun(c *gc.C) {
 	err := bridger.Bridge(devices, 0)
 	c.Assert(err, gc.IsNil)
 }
+
+func (*BridgeSuite) TestNetplanDevicesToBridge(c *gc.C) {
+	devices := []network.DeviceToBridge{{
+		DeviceName: "eno1",
+		BridgeName: "br-eno1",
+		MACAddress: "00:11:22:33:44:55",
+		MTU:        9000,
+	}}
+	c.Assert(network.NetplanDevicesToBridge(devices), jc.DeepEquals, []netplan.DeviceToBridge{{
+		DeviceName: "eno1",
+		BridgeName: "br-eno1",
+		MACAddress: "00:11:22:33:44:55",
+		MTU:        9000,
+	}})
+}
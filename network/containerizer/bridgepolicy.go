@@ -182,6 +182,7 @@ func (p *BridgePolicy) FindMissingBridgesForContainer(
 			DeviceName: hostName,
 			BridgeName: BridgeNameForDevice(hostName),
 			MACAddress: hostDeviceByName[hostName].MACAddress(),
+			MTU:        hostDeviceByName[hostName].MTU(),
 		})
 	}
 	return hostToBridge, reconfigureDelay, nil
@@ -642,6 +642,45 @@ func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerDefaultUnbrid
 	c.Check(reconfigureDelay, gc.Equals, 0)
 }
 
+func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerPropagatesMTU(c *gc.C) {
+	s.setupTwoSpaces(c)
+	err := s.machine.SetLinkLayerDevices(
+		state.LinkLayerDeviceArgs{
+			Name:       "eth0",
+			Type:       corenetwork.EthernetDevice,
+			MACAddress: "00:16:3e:00:00:01",
+			MTU:        9000,
+			IsUp:       true,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetDevicesAddresses(
+		state.LinkLayerDeviceAddress{
+			DeviceName:   "eth0",
+			CIDRAddress:  "10.0.0.20/24",
+			ConfigMethod: state.StaticAddress,
+		},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addContainerMachine(c)
+	err = s.containerMachine.SetConstraints(constraints.Value{
+		Spaces: &[]string{"somespace"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	bridgePolicy, err := containerizer.NewBridgePolicy(cfg(c, 13, "provider"), s.State)
+	c.Assert(err, jc.ErrorIsNil)
+
+	missing, _, err := bridgePolicy.FindMissingBridgesForContainer(s.machine, s.containerMachine)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(missing, gc.DeepEquals, []network.DeviceToBridge{{
+		DeviceName: "eth0",
+		BridgeName: "br-eth0",
+		MACAddress: "00:16:3e:00:00:01",
+		MTU:        9000,
+	}})
+}
+
 func (s *bridgePolicyStateSuite) TestFindMissingBridgesForContainerNoHostDevices(c *gc.C) {
 	s.setupTwoSpaces(c)
 	s.createSpaceAndSubnet(c, "third", "10.20.0.0/24")
@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -26,6 +27,10 @@ const (
 	// iptablesInternalCommand is the comment attached to iptables
 	// rules that are not directly related to ingress rules.
 	iptablesInternalComment = "juju internal"
+
+	// iptablesEgressComment is the comment attached to iptables
+	// rules directly related to egress rules.
+	iptablesEgressComment = "juju egress"
 )
 
 // DropCommand represents an iptables DROP target command.
@@ -145,6 +150,121 @@ func (c IngressRuleCommand) render(commandFlag string) string {
 	return strings.Join(args, " ")
 }
 
+// EgressRuleCommand represents an iptables ACCEPT target command
+// for new outbound connections to a destination network.
+type EgressRuleCommand struct {
+	DestinationCIDR string
+}
+
+// Render renders the command to a string which can be executed via
+// bash in order to install the iptables rule.
+func (c EgressRuleCommand) Render() string {
+	args := []string{
+		"sudo iptables",
+		"-A OUTPUT",
+		"-d", c.DestinationCIDR,
+		"-j ACCEPT",
+		"-m comment --comment", fmt.Sprintf("'%s'", iptablesEgressComment),
+	}
+	return strings.Join(args, " ")
+}
+
+// EgressDropCommand represents the iptables commands which accept
+// loopback traffic and then drop any new outbound connection not
+// accepted by a preceding EgressRuleCommand.
+type EgressDropCommand struct{}
+
+// Render renders the command to a string which can be executed via
+// bash in order to install the iptables rules.
+func (c EgressDropCommand) Render() string {
+	comment := fmt.Sprintf("'%s'", iptablesEgressComment)
+	loopback := strings.Join([]string{
+		"sudo iptables",
+		"-A OUTPUT",
+		"-o lo",
+		"-j ACCEPT",
+		"-m comment --comment", comment,
+	}, " ")
+	drop := strings.Join([]string{
+		"sudo iptables",
+		"-A OUTPUT",
+		"-m state --state NEW",
+		"-j DROP",
+		"-m comment --comment", comment,
+	}, " ")
+	return loopback + "\n" + drop
+}
+
+// FlushEgressCommand represents the iptables commands which remove
+// all rules previously installed by EgressRuleCommand and
+// EgressDropCommand.
+type FlushEgressCommand struct{}
+
+// Render renders the command to a string which can be executed via
+// bash in order to remove the iptables rules.
+func (c FlushEgressCommand) Render() string {
+	return fmt.Sprintf(
+		`sudo iptables -S OUTPUT | grep -- '--comment "%s"' | sed -e 's/^-A /-D /' | xargs -r -L1 sudo iptables`,
+		iptablesEgressComment,
+	)
+}
+
+// ParseEgressRules parses the output of "iptables -S OUTPUT",
+// extracting the destination CIDRs of previously added egress rules,
+// as rendered by EgressRuleCommand. If no EgressDropCommand rules are
+// present, outbound traffic is unrestricted and nil is returned.
+//
+// The iptables rules we care about have the following format, and we
+// will skip all other rules:
+//
+//    -P OUTPUT ACCEPT
+//    -A OUTPUT -d 10.0.0.0/8 -m comment --comment "juju egress" -j ACCEPT
+//    -A OUTPUT -o lo -m comment --comment "juju egress" -j ACCEPT
+//    -A OUTPUT -m state --state NEW -m comment --comment "juju egress" -j DROP
+//
+func ParseEgressRules(r io.Reader) ([]string, error) {
+	var (
+		cidrs      []string
+		restricted bool
+	)
+	marker := fmt.Sprintf("--comment \"%s\"", iptablesEgressComment)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "-A OUTPUT") || !strings.Contains(line, marker) {
+			continue
+		}
+		fields := strings.Fields(line)
+		var destination, target string
+		for i := 0; i < len(fields)-1; i++ {
+			switch fields[i] {
+			case "-d":
+				destination = fields[i+1]
+			case "-j":
+				target = fields[i+1]
+			}
+		}
+		switch {
+		case target == "DROP":
+			restricted = true
+		case target == "ACCEPT" && destination != "":
+			cidrs = append(cidrs, destination)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Annotate(err, "reading iptables output")
+	}
+	if !restricted {
+		return nil, nil
+	}
+	if cidrs == nil {
+		// Restricted to no destinations at all.
+		cidrs = []string{}
+	}
+	sort.Strings(cidrs)
+	return cidrs, nil
+}
+
 // ParseIngressRules parses the output of "iptables -L INPUT -n",
 // extracting previously added ingress rules, as rendered by
 // IngressRuleCommand.
@@ -192,6 +192,59 @@ ACCEPT     icmp --  0.0.0.0/0            0.0.0.0/0    icmptype 8 /* juju ingress
 	)
 }
 
+func (*IptablesSuite) TestEgressRuleCommand(c *gc.C) {
+	assertRender(c,
+		iptables.EgressRuleCommand{DestinationCIDR: "10.0.0.0/8"},
+		"sudo iptables -A OUTPUT -d 10.0.0.0/8 -j ACCEPT -m comment --comment 'juju egress'",
+	)
+}
+
+func (*IptablesSuite) TestEgressDropCommand(c *gc.C) {
+	assertRender(c,
+		iptables.EgressDropCommand{},
+		"sudo iptables -A OUTPUT -o lo -j ACCEPT -m comment --comment 'juju egress'\n"+
+			"sudo iptables -A OUTPUT -m state --state NEW -j DROP -m comment --comment 'juju egress'",
+	)
+}
+
+func (*IptablesSuite) TestFlushEgressCommand(c *gc.C) {
+	assertRender(c,
+		iptables.FlushEgressCommand{},
+		`sudo iptables -S OUTPUT | grep -- '--comment "juju egress"' | sed -e 's/^-A /-D /' | xargs -r -L1 sudo iptables`,
+	)
+}
+
+func (*IptablesSuite) TestParseEgressRulesUnrestricted(c *gc.C) {
+	cidrs, err := iptables.ParseEgressRules(strings.NewReader(`
+-P OUTPUT ACCEPT
+-A OUTPUT -d 10.0.0.0/8 -m comment --comment "juju egress" -j ACCEPT
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
+
+func (*IptablesSuite) TestParseEgressRules(c *gc.C) {
+	cidrs, err := iptables.ParseEgressRules(strings.NewReader(`
+-P OUTPUT ACCEPT
+-A OUTPUT -d 192.168.1.0/24 -m comment --comment "juju egress" -j ACCEPT
+-A OUTPUT -d 172.16.0.1/32 -j ACCEPT
+-A OUTPUT -d 10.0.0.0/8 -m comment --comment "juju egress" -j ACCEPT
+-A OUTPUT -o lo -m comment --comment "juju egress" -j ACCEPT
+-A OUTPUT -m state --state NEW -m comment --comment "juju egress" -j DROP
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/8", "192.168.1.0/24"})
+}
+
+func (*IptablesSuite) TestParseEgressRulesNoDestinations(c *gc.C) {
+	cidrs, err := iptables.ParseEgressRules(strings.NewReader(`
+-A OUTPUT -m state --state NEW -m comment --comment "juju egress" -j DROP
+`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.NotNil)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
+
 func assertParseIngressRules(c *gc.C, in string, expect []network.IngressRule) {
 	rules, err := iptables.ParseIngressRules(strings.NewReader(in))
 	c.Assert(err, jc.ErrorIsNil)
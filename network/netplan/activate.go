@@ -21,6 +21,7 @@ var logger = loggo.GetLogger("juju.network.netplan")
 type ActivationParams struct {
 	Clock     clock.Clock
 	Devices   []DeviceToBridge
+	DryRun    bool
 	RunPrefix string
 	Directory string
 	Timeout   time.Duration
@@ -37,7 +38,8 @@ type ActivationResult struct {
 // BridgeAndActivate will parse a set of netplan yaml files in a directory,
 // create a new netplan config with the provided interfaces bridged
 // bridged, then reconfigure the network using the ifupdown package
-// for the new bridges.
+// for the new bridges. If params.DryRun is true, the new netplan
+// config is returned in the result's Stdout, and nothing is changed.
 func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 	if len(params.Devices) == 0 {
 		return nil, errors.Errorf("no devices specified")
@@ -49,31 +51,15 @@ func BridgeAndActivate(params ActivationParams) (*ActivationResult, error) {
 		return nil, err
 	}
 
-	for _, device := range params.Devices {
-		var deviceId string
-		deviceId, deviceType, err := netplan.FindDeviceByNameOrMAC(device.DeviceName, device.MACAddress)
+	if err := netplan.BridgeDevices(params.Devices); err != nil {
+		return nil, err
+	}
+	if params.DryRun {
+		out, err := Marshal(&netplan)
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		switch deviceType {
-		case TypeEthernet:
-			err = netplan.BridgeEthernetById(deviceId, device.BridgeName)
-			if err != nil {
-				return nil, err
-			}
-		case TypeBond:
-			err = netplan.BridgeBondById(deviceId, device.BridgeName)
-			if err != nil {
-				return nil, err
-			}
-		case TypeVLAN:
-			err = netplan.BridgeVLANById(deviceId, device.BridgeName)
-			if err != nil {
-				return nil, err
-			}
-		default:
-			return nil, errors.Errorf("unable to create bridge for %q, unknown device type %q", deviceId, deviceType)
-		}
+		return &ActivationResult{Stdout: string(out)}, nil
 	}
 	_, err = netplan.Write("")
 	if err != nil {
@@ -108,6 +108,50 @@ func (s *ActivateSuite) TestActivateDeviceAndVLAN(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *ActivateSuite) TestActivateDryRun(c *gc.C) {
+	tempDir := c.MkDir()
+	params := netplan.ActivationParams{
+		Devices: []netplan.DeviceToBridge{
+			{
+				DeviceName: "eno1",
+				MACAddress: "00:11:22:33:44:55",
+				BridgeName: "br-eno1",
+			},
+		},
+		Directory: tempDir,
+		DryRun:    true,
+		RunPrefix: "exit 1 &&",
+	}
+	files := []string{"00.yaml", "01.yaml"}
+	contents := make([][]byte, len(files))
+	for i, file := range files {
+		var err error
+		contents[i], err = ioutil.ReadFile(path.Join("testdata/TestReadWriteBackup", file))
+		c.Assert(err, jc.ErrorIsNil)
+		err = ioutil.WriteFile(path.Join(tempDir, file), contents[i], 0644)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	result, err := netplan.BridgeAndActivate(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.NotNil)
+	c.Check(result.Code, gc.Equals, 0)
+	c.Check(result.Stdout, jc.Contains, `
+  bridges:
+    br-eno1:
+      interfaces: [eno1]
+`[1:])
+
+	// Nothing is written or moved aside.
+	fileInfos, err := ioutil.ReadDir(tempDir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fileInfos, gc.HasLen, len(files))
+	for i, file := range files {
+		content, err := ioutil.ReadFile(path.Join(tempDir, file))
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(content), gc.Equals, string(contents[i]))
+	}
+}
+
 func (s *ActivateSuite) TestActivateFailure(c *gc.C) {
 	coretesting.SkipIfWindowsBug(c, "lp:1771077")
 	tempDir := c.MkDir()
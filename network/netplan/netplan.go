@@ -199,6 +199,40 @@ type BondParameters struct {
 	Primary             string `yaml:"primary,omitempty"`
 }
 
+// defaultMTU is the MTU of devices for which netplan does not specify one.
+const defaultMTU = 1500
+
+// BridgeDevices creates a bridge for each of the specified devices, using
+// the devices' config. Devices are found by name or by MAC address; an
+// ethernet device found by MAC address that is a member of a bond causes
+// the bond to be bridged instead. The MTU of each device, if known, is
+// propagated to the bridge.
+func (np *Netplan) BridgeDevices(devices []DeviceToBridge) error {
+	for _, device := range devices {
+		deviceId, deviceType, err := np.FindDeviceByNameOrMAC(device.DeviceName, device.MACAddress)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		switch deviceType {
+		case TypeEthernet:
+			err = np.BridgeEthernetById(deviceId, device.BridgeName)
+		case TypeBond:
+			err = np.BridgeBondById(deviceId, device.BridgeName)
+		case TypeVLAN:
+			err = np.BridgeVLANById(deviceId, device.BridgeName)
+		default:
+			return errors.Errorf("unable to create bridge for %q, unknown device type %q", deviceId, deviceType)
+		}
+		if err != nil {
+			return err
+		}
+		if device.MTU != 0 {
+			np.propagateMTU(deviceId, deviceType, device.BridgeName, int(device.MTU))
+		}
+	}
+	return nil
+}
+
 // BridgeEthernetById takes a deviceId and creates a bridge with this device
 // using this devices config
 func (np *Netplan) BridgeEthernetById(deviceId string, bridgeName string) (err error) {
@@ -206,6 +240,9 @@ func (np *Netplan) BridgeEthernetById(deviceId string, bridgeName string) (err e
 	if !ok {
 		return errors.NotFoundf("ethernet device with id %q for bridge %q", deviceId, bridgeName)
 	}
+	if bondId, ok := np.bondForInterface(deviceId); ok {
+		return errors.Errorf("cannot create bridge %q, device %q is a member of bond %q", bridgeName, deviceId, bondId)
+	}
 	shouldCreate, err := np.shouldCreateBridge(deviceId, bridgeName)
 	if !shouldCreate {
 		// err may be nil, but we shouldn't continue creating
@@ -286,6 +323,71 @@ func (np *Netplan) createBridgeFromInterface(bridgeName, deviceId string, intf *
 	*intf = Interface{MTU: intf.MTU}
 }
 
+// propagateMTU sets the MTU of the bridge with the specified name, and of
+// the device that it bridges, to the specified MTU if the configuration
+// does not already specify one. The MTU of a bridged VLAN's link device
+// is raised to at least the VLAN's MTU, as a VLAN's MTU cannot exceed
+// that of its link.
+func (np *Netplan) propagateMTU(deviceId string, deviceType DeviceType, bridgeName string, mtu int) {
+	if bridge, ok := np.Network.Bridges[bridgeName]; ok && bridge.MTU == 0 {
+		bridge.MTU = mtu
+		np.Network.Bridges[bridgeName] = bridge
+	}
+	switch deviceType {
+	case TypeEthernet:
+		ethernet := np.Network.Ethernets[deviceId]
+		if ethernet.MTU == 0 {
+			ethernet.MTU = mtu
+			np.Network.Ethernets[deviceId] = ethernet
+		}
+	case TypeBond:
+		bond := np.Network.Bonds[deviceId]
+		if bond.MTU == 0 {
+			bond.MTU = mtu
+			np.Network.Bonds[deviceId] = bond
+		}
+	case TypeVLAN:
+		vlan := np.Network.VLANs[deviceId]
+		if vlan.MTU == 0 {
+			vlan.MTU = mtu
+			np.Network.VLANs[deviceId] = vlan
+		}
+		np.raiseMTU(vlan.Link, vlan.MTU)
+	}
+}
+
+// raiseMTU sets the MTU of the ethernet or bond device with the specified
+// id to the specified MTU, if its MTU is lower.
+func (np *Netplan) raiseMTU(deviceId string, mtu int) {
+	effectiveMTU := func(mtu int) int {
+		if mtu == 0 {
+			return defaultMTU
+		}
+		return mtu
+	}
+	if ethernet, ok := np.Network.Ethernets[deviceId]; ok && effectiveMTU(ethernet.MTU) < mtu {
+		ethernet.MTU = mtu
+		np.Network.Ethernets[deviceId] = ethernet
+	}
+	if bond, ok := np.Network.Bonds[deviceId]; ok && effectiveMTU(bond.MTU) < mtu {
+		bond.MTU = mtu
+		np.Network.Bonds[deviceId] = bond
+	}
+}
+
+// bondForInterface returns the id of the bond of which the device with
+// the specified id is a member, if any.
+func (np *Netplan) bondForInterface(deviceId string) (string, bool) {
+	for bondId, bond := range np.Network.Bonds {
+		for _, i := range bond.Interfaces {
+			if i == deviceId {
+				return bondId, true
+			}
+		}
+	}
+	return "", false
+}
+
 func (np *Netplan) merge(other *Netplan) {
 	// Only copy attributes that would be unmarshalled from yaml.
 	// This blithely replaces keys in the maps (eg. Ethernets or
@@ -586,6 +688,12 @@ func (np *Netplan) FindDeviceByNameOrMAC(name, mac string) (string, DeviceType,
 		}
 		ethernet, err := np.FindEthernetByMAC(mac)
 		if err == nil {
+			// Bonds take the MAC address of one of their members,
+			// which is what we will have been given when the bond
+			// itself does not specify a MAC address.
+			if bond, ok := np.bondForInterface(ethernet); ok {
+				return bond, TypeBond, nil
+			}
 			return ethernet, TypeEthernet, nil
 		}
 	}
@@ -883,6 +883,135 @@ network:
 	c.Check(string(out), gc.Equals, expected)
 }
 
+func (s *NetplanSuite) TestBridgeBondMember(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  renderer: NetworkManager
+  ethernets:
+    id0:
+      match:
+        macaddress: de:ad:22:33:44:55
+    id1:
+      match:
+        macaddress: de:ad:22:33:44:66
+  bonds:
+    bond0:
+      interfaces: [id0, id1]
+      addresses:
+      - 1.2.3.4/24
+`)
+	err := np.BridgeEthernetById("id0", "br-id0")
+	c.Check(err, gc.ErrorMatches, `cannot create bridge "br-id0", device "id0" is a member of bond "bond0"`)
+}
+
+func (s *NetplanSuite) TestBridgeDevicesBondByMemberMAC(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  renderer: NetworkManager
+  ethernets:
+    id0:
+      match:
+        macaddress: de:ad:22:33:44:55
+    id1:
+      match:
+        macaddress: de:ad:22:33:44:66
+  bonds:
+    bond0:
+      interfaces: [id0, id1]
+      addresses:
+      - 1.2.3.4/24
+      mtu: 9000
+`)
+	expected := `
+network:
+  version: 2
+  renderer: NetworkManager
+  ethernets:
+    id0:
+      match:
+        macaddress: de:ad:22:33:44:55
+    id1:
+      match:
+        macaddress: de:ad:22:33:44:66
+  bridges:
+    br-bond0:
+      interfaces: [bond0]
+      addresses:
+      - 1.2.3.4/24
+      mtu: 9000
+  bonds:
+    bond0:
+      interfaces: [id0, id1]
+      mtu: 9000
+`[1:]
+	err := np.BridgeDevices([]netplan.DeviceToBridge{{
+		DeviceName: "bond0-on-the-host",
+		MACAddress: "de:ad:22:33:44:55",
+		BridgeName: "br-bond0",
+		MTU:        1500,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := netplan.Marshal(np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, expected)
+}
+
+func (s *NetplanSuite) TestBridgeDevicesPropagatesMTU(c *gc.C) {
+	np := MustNetplanFromYaml(c, `
+network:
+  version: 2
+  renderer: NetworkManager
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      addresses:
+      - 2.3.4.5/24
+  vlans:
+    id0.1234:
+      id: 1234
+      link: id0
+      addresses:
+      - 1.2.3.4/24
+`)
+	expected := `
+network:
+  version: 2
+  renderer: NetworkManager
+  ethernets:
+    id0:
+      match:
+        macaddress: "00:11:22:33:44:55"
+      addresses:
+      - 2.3.4.5/24
+      mtu: 9000
+  bridges:
+    br-id0.1234:
+      interfaces: [id0.1234]
+      addresses:
+      - 1.2.3.4/24
+      mtu: 9000
+  vlans:
+    id0.1234:
+      id: 1234
+      link: id0
+      mtu: 9000
+`[1:]
+	err := np.BridgeDevices([]netplan.DeviceToBridge{{
+		DeviceName: "id0.1234",
+		BridgeName: "br-id0.1234",
+		MTU:        9000,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	out, err := netplan.Marshal(np)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(out), gc.Equals, expected)
+}
+
 func (s *NetplanSuite) TestBridgerBondMissing(c *gc.C) {
 	np := MustNetplanFromYaml(c, `
 network:
@@ -1261,6 +1390,8 @@ network:
 	checkFindDevice(c, np, "bond0.209", "", "bond0.209", netplan.TypeVLAN, "")
 	checkFindDevice(c, np, "eno3.123", "de:ad:be:ef:01:03", "eno3.123", netplan.TypeVLAN, "")
 	checkFindDevice(c, np, "", "de:ad:be:ef:01:03", "eno3.123", netplan.TypeVLAN, "")
+	// Bond members found by MAC address resolve to the bond.
+	checkFindDevice(c, np, "", "de:ad:be:ef:01:02", "bond0", netplan.TypeBond, "")
 }
 
 func (s *NetplanSuite) TestReadDirectory(c *gc.C) {
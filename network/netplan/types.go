@@ -15,4 +15,9 @@ type DeviceToBridge struct {
 
 	// MACAddress is the MAC address of the device to be bridged
 	MACAddress string
+
+	// MTU is the MTU of the device to be bridged, if known. It is
+	// applied to the bridge, and to the device, when the netplan
+	// configuration does not specify one.
+	MTU uint
 }
@@ -364,6 +364,9 @@ type DeviceToBridge struct {
 
 	// MACAddress is the MAC address of the device to be bridged
 	MACAddress string
+
+	// MTU is the MTU of the device to be bridged, if known.
+	MTU uint
 }
 
 // LXCNetDefaultConfig is the location of the default network config
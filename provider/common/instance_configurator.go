@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/utils/ssh"
 
+	corenetwork "github.com/juju/juju/core/network"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/network/iptables"
 )
@@ -35,6 +36,14 @@ type InstanceConfigurator interface {
 
 	// List all ingress rules.
 	FindIngressRules() ([]network.IngressRule, error)
+
+	// Restrict new outbound connections to the given IPv4
+	// destinations, or remove any restriction if nil.
+	SetEgressCIDRs(cidrs []string) error
+
+	// List the destinations outbound connections are restricted to,
+	// or nil if unrestricted.
+	FindEgressCIDRs() ([]string, error)
 }
 
 type sshInstanceConfigurator struct {
@@ -152,3 +161,38 @@ func (c *sshInstanceConfigurator) FindIngressRules() ([]network.IngressRule, err
 	logger.Tracef("find open ports output: %s", output)
 	return iptables.ParseIngressRules(strings.NewReader(output))
 }
+
+// SetEgressCIDRs implements InstanceConfigurator interface.
+func (c *sshInstanceConfigurator) SetEgressCIDRs(cidrs []string) error {
+	cmds := []string{
+		iptables.FlushEgressCommand{}.Render(),
+	}
+	if cidrs != nil {
+		for _, cidr := range cidrs {
+			// The egress rules are managed with iptables,
+			// which only handles IPv4 traffic.
+			if corenetwork.CIDRAddressType(cidr) != corenetwork.IPv4Address {
+				return errors.NotSupportedf("restricting egress to non-IPv4 destination %q", cidr)
+			}
+			cmds = append(cmds, iptables.EgressRuleCommand{DestinationCIDR: cidr}.Render())
+		}
+		cmds = append(cmds, iptables.EgressDropCommand{}.Render())
+	}
+
+	output, err := c.runCommand(strings.Join(cmds, "\n"))
+	if err != nil {
+		return errors.Annotatef(err, "configuring egress rules: %s", output)
+	}
+	logger.Tracef("set egress rules output: %s", output)
+	return nil
+}
+
+// FindEgressCIDRs implements InstanceConfigurator interface.
+func (c *sshInstanceConfigurator) FindEgressCIDRs() ([]string, error) {
+	output, err := c.runCommand("sudo iptables -S OUTPUT")
+	if err != nil {
+		return nil, errors.Errorf("failed to list egress rules: %s", output)
+	}
+	logger.Tracef("find egress rules output: %s", output)
+	return iptables.ParseEgressRules(strings.NewReader(output))
+}
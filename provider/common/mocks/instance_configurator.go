@@ -69,6 +69,19 @@ func (mr *MockInstanceConfiguratorMockRecorder) DropAllPorts(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DropAllPorts", reflect.TypeOf((*MockInstanceConfigurator)(nil).DropAllPorts), arg0, arg1)
 }
 
+// FindEgressCIDRs mocks base method
+func (m *MockInstanceConfigurator) FindEgressCIDRs() ([]string, error) {
+	ret := m.ctrl.Call(m, "FindEgressCIDRs")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindEgressCIDRs indicates an expected call of FindEgressCIDRs
+func (mr *MockInstanceConfiguratorMockRecorder) FindEgressCIDRs() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindEgressCIDRs", reflect.TypeOf((*MockInstanceConfigurator)(nil).FindEgressCIDRs))
+}
+
 // FindIngressRules mocks base method
 func (m *MockInstanceConfigurator) FindIngressRules() ([]network.IngressRule, error) {
 	ret := m.ctrl.Call(m, "FindIngressRules")
@@ -81,3 +94,15 @@ func (m *MockInstanceConfigurator) FindIngressRules() ([]network.IngressRule, er
 func (mr *MockInstanceConfiguratorMockRecorder) FindIngressRules() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindIngressRules", reflect.TypeOf((*MockInstanceConfigurator)(nil).FindIngressRules))
 }
+
+// SetEgressCIDRs mocks base method
+func (m *MockInstanceConfigurator) SetEgressCIDRs(arg0 []string) error {
+	ret := m.ctrl.Call(m, "SetEgressCIDRs", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetEgressCIDRs indicates an expected call of SetEgressCIDRs
+func (mr *MockInstanceConfiguratorMockRecorder) SetEgressCIDRs(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEgressCIDRs", reflect.TypeOf((*MockInstanceConfigurator)(nil).SetEgressCIDRs), arg0)
+}
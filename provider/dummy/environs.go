@@ -26,6 +26,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -240,6 +241,7 @@ type environProvider struct {
 	newStatePolicy         state.NewPolicyFunc
 	supportsSpaces         bool
 	supportsSpaceDiscovery bool
+	supportsIPv6           bool
 	apiPort                int
 	controllerState        *environState
 	state                  map[string]*environState
@@ -328,6 +330,7 @@ func Reset(c *gc.C) {
 	dummy.newStatePolicy = stateenvirons.GetNewPolicyFunc()
 	dummy.supportsSpaces = true
 	dummy.supportsSpaceDiscovery = false
+	dummy.supportsIPv6 = false
 	dummy.mu.Unlock()
 
 	// NOTE(axw) we must destroy the old states without holding
@@ -528,6 +531,23 @@ func SetSupportsSpaceDiscovery(supports bool) bool {
 	return current
 }
 
+// SetSupportsIPv6 allows to enable and disable dual-stack networking
+// for tests. When enabled, the dummy subnets and network interfaces
+// include IPv6 counterparts of the IPv4 ones.
+func SetSupportsIPv6(supports bool) bool {
+	dummy.mu.Lock()
+	defer dummy.mu.Unlock()
+	current := dummy.supportsIPv6
+	dummy.supportsIPv6 = supports
+	return current
+}
+
+func supportsIPv6() bool {
+	dummy.mu.Lock()
+	defer dummy.mu.Unlock()
+	return dummy.supportsIPv6
+}
+
 // Listen directs subsequent operations on any dummy environment
 // to channel c (if not nil).
 func Listen(c chan<- Operation) {
@@ -676,6 +696,11 @@ func (*environProvider) Version() int {
 	return 0
 }
 
+// SupportsInstanceEgress is part of the EgressFirewallingProvider interface.
+func (*environProvider) SupportsInstanceEgress() bool {
+	return true
+}
+
 func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -1450,6 +1475,22 @@ func (env *environ) NetworkInterfaces(ctx context.ProviderCallContext, ids []ins
 				),
 			}
 		}
+		if supportsIPv6() {
+			// The enabled NICs also have an address in the
+			// IPv6 half of their dual-stack network.
+			for i, netName := range []string{"private", "public"} {
+				info := infos[idIndex][i]
+				info.ProviderSubnetId = corenetwork.Id("dummy-" + netName + "-ipv6")
+				info.CIDR = fmt.Sprintf("2001:db8:%d::/64", (i+1)*10)
+				info.Address = corenetwork.NewProviderAddress(
+					fmt.Sprintf("2001:db8:%d::%d", (i+1)*10+idIndex, estate.maxAddr+2),
+				)
+				info.GatewayAddress = corenetwork.NewProviderAddress(
+					fmt.Sprintf("2001:db8:%d::1", (i+1)*10+idIndex),
+				)
+				infos[idIndex] = append(infos[idIndex], info)
+			}
+		}
 
 		estate.ops <- OpNetworkInterfaces{
 			Env:        env.name,
@@ -1547,15 +1588,30 @@ func (env *environ) Subnets(
 		CIDR:       "0.20.0.0/24",
 		ProviderId: "dummy-public",
 	}}
+	if supportsIPv6() {
+		// Each network is dual-stack, with an IPv6 subnet
+		// alongside the IPv4 one.
+		allSubnets[0].ProviderNetworkId = "dummy-private-net"
+		allSubnets[1].ProviderNetworkId = "dummy-public-net"
+		allSubnets = append(allSubnets, corenetwork.SubnetInfo{
+			CIDR:              "2001:db8:10::/64",
+			ProviderId:        "dummy-private-ipv6",
+			ProviderNetworkId: "dummy-private-net",
+			AvailabilityZones: []string{"zone1", "zone2"},
+		}, corenetwork.SubnetInfo{
+			CIDR:              "2001:db8:20::/64",
+			ProviderId:        "dummy-public-ipv6",
+			ProviderNetworkId: "dummy-public-net",
+		})
+	}
 
 	// Filter result by ids, if given.
 	var result []corenetwork.SubnetInfo
 	for _, subId := range subnetIds {
-		switch subId {
-		case "dummy-private":
-			result = append(result, allSubnets[0])
-		case "dummy-public":
-			result = append(result, allSubnets[1])
+		for _, subnet := range allSubnets {
+			if subnet.ProviderId == subId {
+				result = append(result, subnet)
+			}
 		}
 	}
 	if len(subnetIds) == 0 {
@@ -1711,6 +1767,7 @@ func (*environ) Provider() environs.EnvironProvider {
 type dummyInstance struct {
 	state        *environState
 	rules        network.IngressRuleSlice
+	egressCIDRs  []string
 	id           instance.Id
 	status       string
 	machineId    string
@@ -1888,6 +1945,49 @@ func (inst *dummyInstance) IngressRules(ctx context.ProviderCallContext, machine
 	return
 }
 
+func (inst *dummyInstance) SetEgressCIDRs(ctx context.ProviderCallContext, machineId string, cidrs []string) error {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return fmt.Errorf("invalid firewall mode %q for restricting egress on instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("SetEgressCIDRs with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("SetEgressCIDRs"); err != nil {
+		return err
+	}
+	if cidrs == nil {
+		inst.egressCIDRs = nil
+		return nil
+	}
+	inst.egressCIDRs = append([]string{}, cidrs...)
+	sort.Strings(inst.egressCIDRs)
+	return nil
+}
+
+func (inst *dummyInstance) EgressCIDRs(ctx context.ProviderCallContext, machineId string) ([]string, error) {
+	defer delay()
+	if inst.firewallMode != config.FwInstance {
+		return nil, fmt.Errorf("invalid firewall mode %q for retrieving egress CIDRs from instance",
+			inst.firewallMode)
+	}
+	if inst.machineId != machineId {
+		panic(fmt.Errorf("EgressCIDRs with mismatched machine id, expected %q got %q", inst.machineId, machineId))
+	}
+	inst.state.mu.Lock()
+	defer inst.state.mu.Unlock()
+	if err := inst.checkBroken("EgressCIDRs"); err != nil {
+		return nil, err
+	}
+	if inst.egressCIDRs == nil {
+		return nil, nil
+	}
+	return append([]string{}, inst.egressCIDRs...), nil
+}
+
 // providerDelay controls the delay before dummy responds.
 // non empty values in JUJU_DUMMY_DELAY will be parsed as
 // time.Durations into this value.
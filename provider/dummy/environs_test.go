@@ -346,6 +346,62 @@ func (s *suite) TestSubnets(c *gc.C) {
 	c.Assert(netInfo, gc.HasLen, 0)
 }
 
+func (s *suite) TestNetworkInterfacesDualStack(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy(s.callCtx)
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+	defer dummy.SetSupportsIPv6(dummy.SetSupportsIPv6(true))
+
+	infoList, err := e.NetworkInterfaces(s.callCtx, []instance.Id{instance.Id("i-42")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infoList, gc.HasLen, 1)
+	info := infoList[0]
+	c.Assert(info, gc.HasLen, 5)
+
+	// The IPv6 addresses are on the same devices as the enabled
+	// IPv4 ones, in the IPv6 half of each dual-stack network.
+	c.Check(info[3].InterfaceName, gc.Equals, "eth0")
+	c.Check(info[3].ProviderSubnetId, gc.Equals, corenetwork.Id("dummy-private-ipv6"))
+	c.Check(info[3].CIDR, gc.Equals, "2001:db8:10::/64")
+	c.Check(info[3].Address, gc.Equals, corenetwork.NewProviderAddress("2001:db8:10::2"))
+	c.Check(info[4].InterfaceName, gc.Equals, "eth1")
+	c.Check(info[4].ProviderSubnetId, gc.Equals, corenetwork.Id("dummy-public-ipv6"))
+	c.Check(info[4].CIDR, gc.Equals, "2001:db8:20::/64")
+	c.Check(info[4].Address, gc.Equals, corenetwork.NewProviderAddress("2001:db8:20::2"))
+}
+
+func (s *suite) TestSubnetsDualStack(c *gc.C) {
+	e := s.bootstrapTestEnviron(c)
+	defer func() {
+		err := e.Destroy(s.callCtx)
+		c.Assert(err, jc.ErrorIsNil)
+	}()
+	defer dummy.SetSupportsIPv6(dummy.SetSupportsIPv6(true))
+
+	expectInfo := []corenetwork.SubnetInfo{{
+		CIDR:              "0.10.0.0/24",
+		ProviderId:        "dummy-private",
+		ProviderNetworkId: "dummy-private-net",
+		AvailabilityZones: []string{"zone1", "zone2"},
+	}, {
+		CIDR:              "2001:db8:10::/64",
+		ProviderId:        "dummy-private-ipv6",
+		ProviderNetworkId: "dummy-private-net",
+		AvailabilityZones: []string{"zone1", "zone2"},
+	}}
+
+	ids := []corenetwork.Id{"dummy-private", "dummy-private-ipv6"}
+	netInfo, err := e.Subnets(s.callCtx, "i-foo", ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(netInfo, jc.DeepEquals, expectInfo)
+
+	netInfo, err = e.Subnets(s.callCtx, "i-foo", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(netInfo, gc.HasLen, 4)
+}
+
 func assertInterfaces(c *gc.C, e environs.Environ, opc chan dummy.Operation, expectInstId instance.Id, expectInfo []network.InterfaceInfo) {
 	select {
 	case op := <-opc:
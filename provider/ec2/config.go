@@ -14,7 +14,7 @@ import (
 
 var configSchema = environschema.Fields{
 	"vpc-id": {
-		Description: "Use a specific AWS VPC ID (optional). When not specified, Juju requires a default VPC or EC2-Classic features to be available for the account/region.",
+		Description: "Use a specific AWS VPC ID (optional). When not specified, Juju requires a default VPC or EC2-Classic features to be available for the account/region. When set to \"create\", Juju creates a VPC for the model, with a public subnet in each availability zone, and deletes it when the model is destroyed.",
 		Example:     "vpc-a1b2c3d4",
 		Type:        environschema.Tstring,
 		Group:       environschema.AccountGroup,
@@ -26,6 +26,10 @@ var configSchema = environschema.Fields{
 		Group:       environschema.AccountGroup,
 		Immutable:   true,
 	},
+	"network-cidr": {
+		Description: "The address range of the VPC Juju creates for the model when vpc-id is \"create\". It must be an IPv4 range between /16 and /24; each availability zone gets a subnet of a sixteenth of it. It is used when the VPC is created.",
+		Type:        environschema.Tstring,
+	},
 }
 
 var configFields = func() schema.Fields {
@@ -39,6 +43,7 @@ var configFields = func() schema.Fields {
 var configDefaults = schema.Defaults{
 	"vpc-id":       "",
 	"vpc-id-force": false,
+	"network-cidr": defaultVPCCIDRBlock,
 }
 
 type environConfig struct {
@@ -54,6 +59,10 @@ func (c *environConfig) forceVPCID() bool {
 	return c.attrs["vpc-id-force"].(bool)
 }
 
+func (c *environConfig) networkCIDR() string {
+	return c.attrs["network-cidr"].(string)
+}
+
 func (p environProvider) newConfig(cfg *config.Config) (*environConfig, error) {
 	valid, err := p.Validate(cfg, nil)
 	if err != nil {
@@ -100,6 +109,10 @@ func validateConfig(cfg, old *config.Config) (*environConfig, error) {
 		return nil, fmt.Errorf("cannot use vpc-id-force without specifying vpc-id as well")
 	}
 
+	if err := validateVPCCIDRBlock(ecfg.networkCIDR()); err != nil {
+		return nil, fmt.Errorf("invalid network-cidr %q: %v", ecfg.networkCIDR(), err)
+	}
+
 	if old != nil {
 		attrs := old.UnknownAttrs()
 
@@ -110,6 +110,23 @@ func (t configTest) check(c *gc.C) {
 var configTests = []configTest{
 	{
 		config: attrs{},
+	}, {
+		config: attrs{},
+		expect: attrs{
+			"network-cidr": "10.0.0.0/16",
+		},
+	}, {
+		config: attrs{
+			"network-cidr": "192.168.0.0/20",
+		},
+		expect: attrs{
+			"network-cidr": "192.168.0.0/20",
+		},
+	}, {
+		config: attrs{
+			"network-cidr": "10.0.0.0/8",
+		},
+		err: `.*invalid network-cidr "10.0.0.0/8": prefix length must be between /16 and /24`,
 	}, {
 		config:     attrs{},
 		vpcID:      "",
@@ -127,6 +144,12 @@ var configTests = []configTest{
 		},
 		vpcID:      "none",
 		forceVPCID: false,
+	}, {
+		config: attrs{
+			"vpc-id": vpcIDCreate,
+		},
+		vpcID:      "create",
+		forceVPCID: false,
 	}, {
 		config: attrs{
 			"vpc-id": 42,
@@ -72,6 +72,7 @@ const (
 	deviceInUse        = "InvalidDevice.InUse"
 	attachmentNotFound = "InvalidAttachment.NotFound"
 	volumeNotFound     = "InvalidVolume.NotFound"
+	snapshotNotFound   = "InvalidSnapshot.NotFound"
 	incorrectState     = "IncorrectState"
 )
 
@@ -255,6 +256,13 @@ func (*ebsProvider) Releasable() bool {
 	return true
 }
 
+// EncryptsVolumes is defined on the storage.NativeEncryptionProvider
+// interface. EBS volumes are encrypted by AWS when the "encrypted"
+// pool attribute is set.
+func (*ebsProvider) EncryptsVolumes() bool {
+	return true
+}
+
 // DefaultPools is defined on the Provider interface.
 func (e *ebsProvider) DefaultPools() []*storage.Config {
 	ssdPool, _ := storage.NewConfig("ebs-ssd", EBS_ProviderType, map[string]interface{}{
@@ -286,6 +294,7 @@ type ebsVolumeSource struct {
 }
 
 var _ storage.VolumeSource = (*ebsVolumeSource)(nil)
+var _ storage.VolumeSnapshotter = (*ebsVolumeSource)(nil)
 
 // parseVolumeOptions uses storage volume parameters to make a struct used to create volumes.
 func parseVolumeOptions(size uint64, attrs map[string]interface{}) (_ ec2.CreateVolume, _ error) {
@@ -387,6 +396,7 @@ func (v *ebsVolumeSource) createVolume(ctx context.ProviderCallContext, p storag
 	}
 	vol, _ := parseVolumeOptions(p.Size, p.Attributes)
 	vol.AvailZone = inst.AvailZone
+	vol.SnapshotId = p.SnapshotId
 	resp, err := v.env.ec2.CreateVolume(vol)
 	if err != nil {
 		return nil, nil, errors.Trace(maybeConvertCredentialError(err, ctx))
@@ -980,6 +990,79 @@ func (v *ebsVolumeSource) ImportVolume(ctx context.ProviderCallContext, volumeId
 	}, nil
 }
 
+// SnapshotClient defines the EC2 methods needed to take and delete
+// EBS snapshots.
+type SnapshotClient interface {
+	resourceTagger
+	CreateSnapshot(volumeId, description string) (*ec2.CreateSnapshotResp, error)
+	DeleteSnapshots(ids []string) (*ec2.SimpleResp, error)
+}
+
+// CreateVolumeSnapshots is specified on the storage.VolumeSnapshotter interface.
+//
+// EBS snapshots are taken while the volume may still be attached and in
+// use, so they are only crash-consistent.
+func (v *ebsVolumeSource) CreateVolumeSnapshots(
+	ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams,
+) ([]storage.CreateSnapshotsResult, error) {
+	results := make([]storage.CreateSnapshotsResult, len(params))
+	for i, p := range params {
+		snapshotId, err := createVolumeSnapshot(v.env.ec2, ctx, p, v.envName)
+		if err != nil {
+			if common.IsCredentialNotValid(err) {
+				return nil, errors.Trace(err)
+			}
+			results[i].Error = errors.Annotatef(err, "creating snapshot of %s", names.ReadableString(p.Volume))
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+	}
+	return results, nil
+}
+
+func createVolumeSnapshot(client SnapshotClient, ctx context.ProviderCallContext, p storage.VolumeSnapshotParams, envName string) (string, error) {
+	resp, err := client.CreateSnapshot(p.VolumeId, "juju snapshot "+p.Id)
+	if err != nil {
+		return "", maybeConvertCredentialError(err, ctx)
+	}
+	resourceTags := make(map[string]string)
+	for k, v := range p.ResourceTags {
+		resourceTags[k] = v
+	}
+	resourceTags[tagName] = resourceName(p.Volume, envName) + "-snapshot-" + p.Id
+	if err := tagResources(client, ctx, resourceTags, resp.Id); err != nil {
+		if _, delErr := client.DeleteSnapshots([]string{resp.Id}); delErr != nil {
+			logger.Errorf("error cleaning up snapshot %v: %v", resp.Id, maybeConvertCredentialError(delErr, ctx))
+		}
+		return "", errors.Annotate(err, "tagging snapshot")
+	}
+	return resp.Id, nil
+}
+
+// DeleteVolumeSnapshots is specified on the storage.VolumeSnapshotter interface.
+func (v *ebsVolumeSource) DeleteVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if err := deleteVolumeSnapshot(v.env.ec2, ctx, snapshotId); err != nil {
+			if common.IsCredentialNotValid(err) {
+				return nil, errors.Trace(err)
+			}
+			results[i] = errors.Annotatef(err, "deleting snapshot %q", snapshotId)
+		}
+	}
+	return results, nil
+}
+
+func deleteVolumeSnapshot(client SnapshotClient, ctx context.ProviderCallContext, snapshotId string) error {
+	if _, err := client.DeleteSnapshots([]string{snapshotId}); err != nil {
+		if ec2ErrCode(err) == snapshotNotFound {
+			return nil
+		}
+		return maybeConvertCredentialError(err, ctx)
+	}
+	return nil
+}
+
 var errTooManyVolumes = errors.New("too many EBS volumes to attach")
 
 // blockDeviceNamer returns a function that cycles through block device names.
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/clock"
 	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	awsec2 "gopkg.in/amz.v3/ec2"
 	"gopkg.in/amz.v3/ec2/ec2test"
@@ -1218,6 +1219,93 @@ func makeDescribeVolumesResponseModifier(modify func(*awsec2.VolumesResp) error)
 	}
 }
 
+type ebsSnapshotSuite struct {
+	testing.BaseSuite
+	client *stubSnapshotClient
+}
+
+var _ = gc.Suite(&ebsSnapshotSuite{})
+
+func (s *ebsSnapshotSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.client = &stubSnapshotClient{Stub: &gitjujutesting.Stub{}}
+}
+
+func (s *ebsSnapshotSuite) TestCreateVolumeSnapshot(c *gc.C) {
+	snapshotId, err := ec2.CreateVolumeSnapshot(s.client, context.NewCloudCallContext(), storage.VolumeSnapshotParams{
+		Id:           "0",
+		Volume:       names.NewVolumeTag("0"),
+		VolumeId:     "vol-0",
+		ResourceTags: map[string]string{"juju-model-uuid": "deadbeef"},
+	}, "env")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "snap-0")
+	s.client.CheckCallNames(c, "CreateSnapshot", "CreateTags")
+	s.client.CheckCall(c, 0, "CreateSnapshot", "vol-0", "juju snapshot 0")
+	tags := s.client.Calls()[1].Args[1].([]awsec2.Tag)
+	c.Assert(tags, jc.SameContents, []awsec2.Tag{
+		{"juju-model-uuid", "deadbeef"},
+		{"Name", "juju-env-volume-0-snapshot-0"},
+	})
+}
+
+func (s *ebsSnapshotSuite) TestCreateVolumeSnapshotTagFailure(c *gc.C) {
+	s.client.SetErrors(nil, errors.New("boom"))
+	_, err := ec2.CreateVolumeSnapshot(s.client, context.NewCloudCallContext(), storage.VolumeSnapshotParams{
+		Id:       "0",
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "vol-0",
+	}, "env")
+	c.Assert(err, gc.ErrorMatches, "tagging snapshot: boom")
+	s.client.CheckCallNames(c, "CreateSnapshot", "CreateTags", "DeleteSnapshots")
+	s.client.CheckCall(c, 2, "DeleteSnapshots", []string{"snap-0"})
+}
+
+func (s *ebsSnapshotSuite) TestDeleteVolumeSnapshot(c *gc.C) {
+	err := ec2.DeleteVolumeSnapshot(s.client, context.NewCloudCallContext(), "snap-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCall(c, 0, "DeleteSnapshots", []string{"snap-0"})
+}
+
+func (s *ebsSnapshotSuite) TestDeleteVolumeSnapshotNotFound(c *gc.C) {
+	s.client.SetErrors(&awsec2.Error{Code: "InvalidSnapshot.NotFound"})
+	err := ec2.DeleteVolumeSnapshot(s.client, context.NewCloudCallContext(), "snap-0")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ebsSnapshotSuite) TestDeleteVolumeSnapshotError(c *gc.C) {
+	s.client.SetErrors(&awsec2.Error{Code: "SnapshotInUse"})
+	err := ec2.DeleteVolumeSnapshot(s.client, context.NewCloudCallContext(), "snap-0")
+	c.Assert(errors.Cause(err), gc.DeepEquals, &awsec2.Error{Code: "SnapshotInUse"})
+}
+
+type stubSnapshotClient struct {
+	*gitjujutesting.Stub
+}
+
+var _ ec2.SnapshotClient = (*stubSnapshotClient)(nil)
+
+func (c *stubSnapshotClient) CreateSnapshot(volumeId, description string) (*awsec2.CreateSnapshotResp, error) {
+	c.MethodCall(c, "CreateSnapshot", volumeId, description)
+	if err := c.NextErr(); err != nil {
+		return nil, err
+	}
+	resp := &awsec2.CreateSnapshotResp{}
+	resp.Id = "snap-" + volumeId[len("vol-"):]
+	resp.VolumeId = volumeId
+	return resp, nil
+}
+
+func (c *stubSnapshotClient) CreateTags(resourceIds []string, tags []awsec2.Tag) (*awsec2.SimpleResp, error) {
+	c.MethodCall(c, "CreateTags", resourceIds, tags)
+	return nil, c.NextErr()
+}
+
+func (c *stubSnapshotClient) DeleteSnapshots(ids []string) (*awsec2.SimpleResp, error) {
+	c.MethodCall(c, "DeleteSnapshots", ids)
+	return nil, c.NextErr()
+}
+
 func replaceResponseBody(resp *http.Response, value interface{}) error {
 	var buf bytes.Buffer
 	if err := xml.NewEncoder(&buf).Encode(value); err != nil {
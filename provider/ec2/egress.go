@@ -0,0 +1,201 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+const (
+	// allProtocols is the protocol of security group rules
+	// applying to all traffic, such as the egress rule AWS
+	// creates for every new VPC security group.
+	allProtocols = "-1"
+
+	authorizeEgressAction = "AuthorizeSecurityGroupEgress"
+	revokeEgressAction    = "RevokeSecurityGroupEgress"
+)
+
+var _ instances.InstanceEgressFirewaller = (*ec2Instance)(nil)
+
+var (
+	describeGroupEgress = _describeGroupEgress
+	changeGroupEgress   = _changeGroupEgress
+)
+
+// egressPerm describes an IPv4 egress rule of a security group.
+type egressPerm struct {
+	Protocol string   `xml:"ipProtocol"`
+	FromPort int      `xml:"fromPort"`
+	ToPort   int      `xml:"toPort"`
+	CIDRs    []string `xml:"ipRanges>item>cidrIp"`
+}
+
+// groupEgress holds the VPC and the egress rules of a security group,
+// which the amz.v3 client does not decode. Groups without a VPC are
+// EC2-Classic groups, which have no egress rules.
+type groupEgress struct {
+	VPCID string       `xml:"vpcId"`
+	Perms []egressPerm `xml:"ipPermissionsEgress>item"`
+}
+
+// _describeGroupEgress returns the egress rules of the security group
+// with the given ID.
+func _describeGroupEgress(e *ec2.EC2, groupId string) (*groupEgress, error) {
+	var resp struct {
+		RequestId string        `xml:"requestId"`
+		Groups    []groupEgress `xml:"securityGroupInfo>item"`
+	}
+	params := map[string]string{"GroupId.1": groupId}
+	if err := ec2Query(e, "DescribeSecurityGroups", params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Groups) != 1 {
+		return nil, errors.Errorf("expected 1 security group, got %d", len(resp.Groups))
+	}
+	return &resp.Groups[0], nil
+}
+
+// _changeGroupEgress authorizes or revokes, depending on the action,
+// the egress rules of the security group with the given ID.
+func _changeGroupEgress(e *ec2.EC2, action, groupId string, perms []egressPerm) error {
+	params := map[string]string{"GroupId": groupId}
+	for i, perm := range perms {
+		prefix := "IpPermissions." + strconv.Itoa(i+1)
+		params[prefix+".IpProtocol"] = perm.Protocol
+		if perm.Protocol != allProtocols {
+			params[prefix+".FromPort"] = strconv.Itoa(perm.FromPort)
+			params[prefix+".ToPort"] = strconv.Itoa(perm.ToPort)
+		}
+		for j, cidr := range perm.CIDRs {
+			params[prefix+".IpRanges."+strconv.Itoa(j+1)+".CidrIp"] = cidr
+		}
+	}
+	return ec2Query(e, action, params, &simpleQueryResp{})
+}
+
+// SetEgressCIDRs implements instances.InstanceEgressFirewaller.
+//
+// Instances are members of both the model group and their machine group,
+// and EC2 allows any traffic allowed by one of them. The rule allowing all
+// outbound IPv4 traffic is therefore revoked from the model group before
+// restricting an instance; unrestricted instances keep the one in their
+// machine group. IPv6 traffic is not restricted.
+func (inst *ec2Instance) SetEgressCIDRs(ctx context.ProviderCallContext, machineId string, cidrs []string) error {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return errors.Errorf("invalid firewall mode %q for restricting egress on instance",
+			inst.e.Config().FirewallMode())
+	}
+	want := set.NewStrings(defaultRouteCIDRBlock)
+	if cidrs != nil {
+		want = set.NewStrings()
+		for _, cidr := range cidrs {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return errors.NotValidf("egress CIDR %q", cidr)
+			}
+			if ip.To4() == nil {
+				return errors.NotSupportedf("restricting egress to non-IPv4 destination %q", cidr)
+			}
+			want.Add(cidr)
+		}
+		if err := inst.e.setEgressInGroup(ctx, inst.e.jujuGroupName(), set.NewStrings()); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	name := inst.e.machineGroupName(machineId)
+	if err := inst.e.setEgressInGroup(ctx, name, want); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("set egress rules in security group %s: %v", name, want.SortedValues())
+	return nil
+}
+
+// EgressCIDRs implements instances.InstanceEgressFirewaller.
+func (inst *ec2Instance) EgressCIDRs(ctx context.ProviderCallContext, machineId string) ([]string, error) {
+	if inst.e.Config().FirewallMode() != config.FwInstance {
+		return nil, errors.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			inst.e.Config().FirewallMode())
+	}
+	g, err := inst.e.groupByName(ctx, inst.e.machineGroupName(machineId))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	egress, err := describeGroupEgress(inst.e.ec2, g.Id)
+	if err != nil {
+		return nil, errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot get egress rules")
+	}
+	cidrs := set.NewStrings()
+	for _, perm := range egress.Perms {
+		if perm.Protocol != allProtocols {
+			continue
+		}
+		for _, cidr := range perm.CIDRs {
+			if cidr == defaultRouteCIDRBlock {
+				return nil, nil
+			}
+			cidrs.Add(cidr)
+		}
+	}
+	if egress.VPCID == "" {
+		// EC2-Classic groups don't restrict outbound traffic.
+		return nil, nil
+	}
+	return cidrs.SortedValues(), nil
+}
+
+// setEgressInGroup makes the IPv4 egress rules of the named group allow
+// outbound traffic to the given CIDRs only.
+func (e *environ) setEgressInGroup(ctx context.ProviderCallContext, name string, cidrs set.Strings) error {
+	g, err := e.groupByName(ctx, name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	egress, err := describeGroupEgress(e.ec2, g.Id)
+	if err != nil {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot get egress rules")
+	}
+	if egress.VPCID == "" {
+		return errors.NotSupportedf("restricting egress of EC2-Classic instances")
+	}
+	have := set.NewStrings()
+	var revoke []egressPerm
+	for _, perm := range egress.Perms {
+		if perm.Protocol != allProtocols {
+			// Juju doesn't create rules limited to a protocol.
+			if len(perm.CIDRs) > 0 {
+				revoke = append(revoke, perm)
+			}
+			continue
+		}
+		for _, cidr := range perm.CIDRs {
+			if cidrs.Contains(cidr) {
+				have.Add(cidr)
+				continue
+			}
+			revoke = append(revoke, egressPerm{Protocol: allProtocols, CIDRs: []string{cidr}})
+		}
+	}
+	if len(revoke) > 0 {
+		if err := changeGroupEgress(e.ec2, revokeEgressAction, g.Id, revoke); err != nil {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot revoke egress rules")
+		}
+	}
+	if add := cidrs.Difference(have); !add.IsEmpty() {
+		perms := []egressPerm{{Protocol: allProtocols, CIDRs: add.SortedValues()}}
+		if err := changeGroupEgress(e.ec2, authorizeEgressAction, g.Id, perms); err != nil {
+			return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot authorize egress rules")
+		}
+	}
+	return nil
+}
@@ -79,6 +79,11 @@ type environ struct {
 	defaultVPC        *ec2.VPC
 
 	ensureGroupMutex sync.Mutex
+
+	// createdVPCID caches the ID of the VPC created for the model
+	// when vpc-id is "create".
+	createdVPCMutex sync.Mutex
+	createdVPCID    string
 }
 
 var _ environs.Environ = (*environ)(nil)
@@ -134,6 +139,9 @@ func (env *environ) Create(ctx context.ProviderCallContext, args environs.Create
 		return err
 	}
 	vpcID := env.ecfg().vpcID()
+	if vpcID == vpcIDCreate {
+		return errors.Trace(env.ensureModelVPC(ctx, args.ControllerUUID))
+	}
 	if err := validateModelVPC(env.ec2, ctx, env.name, vpcID); err != nil {
 		return errors.Trace(maybeConvertCredentialError(err, ctx))
 	}
@@ -144,6 +152,12 @@ func (env *environ) Create(ctx context.ProviderCallContext, args environs.Create
 
 // Bootstrap is part of the Environ interface.
 func (e *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.ProviderCallContext, args environs.BootstrapParams) (*environs.BootstrapResult, error) {
+	if e.ecfg().vpcID() == vpcIDCreate {
+		ctx.Infof("Creating VPC for model %q", e.name)
+		if err := e.ensureModelVPC(callCtx, args.ControllerConfig.ControllerUUID()); err != nil {
+			return nil, maybeConvertCredentialError(err, callCtx)
+		}
+	}
 	r, err := common.Bootstrap(ctx, e, callCtx, args)
 	return r, maybeConvertCredentialError(err, callCtx)
 }
@@ -561,7 +575,11 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	runArgs := commonRunArgs
 	runArgs.AvailZone = availabilityZone
 
-	haveVPCID := isVPCIDSet(e.ecfg().vpcID())
+	vpcID, err := e.vpcID(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	haveVPCID := isVPCIDSet(vpcID)
 	var subnetIDsForZone []string
 	var subnetErr error
 	if haveVPCID {
@@ -573,7 +591,7 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 				allowedSubnetIDs = append(allowedSubnetIDs, string(subnetID))
 			}
 		}
-		subnetIDsForZone, subnetErr = getVPCSubnetIDsForAvailabilityZone(e.ec2, ctx, e.ecfg().vpcID(), availabilityZone, allowedSubnetIDs)
+		subnetIDsForZone, subnetErr = getVPCSubnetIDsForAvailabilityZone(e.ec2, ctx, vpcID, availabilityZone, allowedSubnetIDs)
 	} else if args.Constraints.HasSpaces() {
 		subnetIDsForZone, subnetErr = findSubnetIDsForAvailabilityZone(availabilityZone, args.SubnetsToZones)
 		if subnetErr == nil && placementSubnetID != "" {
@@ -606,7 +624,15 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	}
 
 	callback(status.Allocating, fmt.Sprintf("Trying to start instance in availability zone %q", availabilityZone), nil)
-	instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+	if args.Constraints.HasSpot() {
+		var maxPrice string
+		if args.Constraints.HasSpotMaxPrice() {
+			maxPrice = *args.Constraints.SpotMaxPrice
+		}
+		instResp, err = runSpotInstances(e.ec2, ctx, runArgs, maxPrice, callback)
+	} else {
+		instResp, err = runInstances(e.ec2, ctx, runArgs, callback)
+	}
 	if err != nil {
 		if !isZoneOrSubnetConstrainedError(err) {
 			err = annotateWrapError(err, "cannot run instances")
@@ -623,9 +649,8 @@ func (e *environ) StartInstance(ctx context.ProviderCallContext, args environs.S
 	}
 	instAZ := inst.Instance.AvailZone
 	if haveVPCID {
-		instVPC := e.ecfg().vpcID()
 		instSubnet := inst.Instance.SubnetId
-		logger.Infof("started instance %q in AZ %q, subnet %q, VPC %q", inst.Id(), instAZ, instSubnet, instVPC)
+		logger.Infof("started instance %q in AZ %q, subnet %q, VPC %q", inst.Id(), instAZ, instSubnet, vpcID)
 	} else {
 		logger.Infof("started instance %q in AZ %q", inst.Id(), instAZ)
 	}
@@ -779,10 +804,15 @@ func volumeAttachmentsZone(ec2 *ec2.EC2, ctx context.ProviderCallContext, attach
 	return resp.Volumes[0].AvailZone, nil
 }
 
+// resourceTagger is the subset of the EC2 API used to tag resources.
+type resourceTagger interface {
+	CreateTags(resourceIds []string, tags []ec2.Tag) (*ec2.SimpleResp, error)
+}
+
 // tagResources calls ec2.CreateTags, tagging each of the specified resources
 // with the given tags. tagResources will retry for a short period of time
 // if it receives a *.NotFound error response from EC2.
-func tagResources(e *ec2.EC2, ctx context.ProviderCallContext, tags map[string]string, resourceIds ...string) error {
+func tagResources(e resourceTagger, ctx context.ProviderCallContext, tags map[string]string, resourceIds ...string) error {
 	if len(tags) == 0 {
 		return nil
 	}
@@ -868,7 +898,7 @@ func (e *environ) StopInstances(ctx context.ProviderCallContext, ids ...instance
 // groupInfoByName returns information on the security group
 // with the given name including rules and other details.
 func (e *environ) groupInfoByName(ctx context.ProviderCallContext, groupName string) (ec2.SecurityGroupInfo, error) {
-	resp, err := e.securityGroupsByNameOrID(groupName)
+	resp, err := e.securityGroupsByNameOrID(ctx, groupName)
 	if err != nil {
 		return ec2.SecurityGroupInfo{}, maybeConvertCredentialError(err, ctx)
 	}
@@ -1281,7 +1311,10 @@ func (e *environ) Subnets(
 
 func (e *environ) subnetsForVPC(ctx context.ProviderCallContext) (resp *ec2.SubnetsResp, vpcId string, err error) {
 	filter := ec2.NewFilter()
-	vpcId = e.ecfg().vpcID()
+	vpcId, err = e.vpcID(ctx)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
 	if !isVPCIDSet(vpcId) {
 		if hasDefaultVPC, err := e.hasDefaultVPC(ctx); err == nil && hasDefaultVPC {
 			vpcId = e.defaultVPC.Id
@@ -1436,6 +1469,11 @@ func (e *environ) Destroy(ctx context.ProviderCallContext) error {
 	if err := e.cleanEnvironmentSecurityGroups(ctx); err != nil {
 		return errors.Annotate(maybeConvertCredentialError(err, ctx), "cannot delete environment security groups")
 	}
+	if e.ecfg().vpcID() == vpcIDCreate {
+		if err := e.deleteCreatedVPCs(ctx, map[string]string{tags.JujuModel: e.uuid()}); err != nil {
+			return errors.Annotate(err, "cannot delete model VPC")
+		}
+	}
 	return nil
 }
 
@@ -1447,7 +1485,15 @@ func (e *environ) DestroyController(ctx context.ProviderCallContext, controllerU
 	if err := e.destroyControllerManagedEnvirons(ctx, controllerUUID); err != nil {
 		return errors.Annotate(err, "destroying managed environs")
 	}
-	return e.Destroy(ctx)
+	if err := e.Destroy(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	// Delete the VPCs created for any hosted models, now that nothing
+	// is left in them.
+	if err := e.deleteCreatedVPCs(ctx, map[string]string{tags.JujuController: controllerUUID}); err != nil {
+		return errors.Annotate(err, "cannot delete model VPCs")
+	}
+	return nil
 }
 
 // destroyControllerManagedEnvirons destroys all environments managed by this
@@ -1919,8 +1965,12 @@ var zeroGroup ec2.SecurityGroup
 // securityGroupsByNameOrID calls ec2.SecurityGroups() either with the given
 // groupName or with filter by vpc-id and group-name, depending on whether
 // vpc-id is empty or not.
-func (e *environ) securityGroupsByNameOrID(groupName string) (*ec2.SecurityGroupsResp, error) {
-	if chosenVPCID := e.ecfg().vpcID(); isVPCIDSet(chosenVPCID) {
+func (e *environ) securityGroupsByNameOrID(ctx context.ProviderCallContext, groupName string) (*ec2.SecurityGroupsResp, error) {
+	chosenVPCID, err := e.vpcID(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if isVPCIDSet(chosenVPCID) {
 		// AWS VPC API requires both of these filters (and no
 		// group names/ids set) for non-default EC2-VPC groups:
 		filter := ec2.NewFilter()
@@ -1948,7 +1998,10 @@ func (e *environ) ensureGroup(ctx context.ProviderCallContext, controllerUUID, n
 	defer e.ensureGroupMutex.Unlock()
 
 	// Specify explicit VPC ID if needed (not for default VPC or EC2-classic).
-	chosenVPCID := e.ecfg().vpcID()
+	chosenVPCID, err := e.vpcID(ctx)
+	if err != nil {
+		return zeroGroup, errors.Trace(err)
+	}
 	inVPCLogSuffix := fmt.Sprintf(" (in VPC %q)", chosenVPCID)
 	if !isVPCIDSet(chosenVPCID) {
 		chosenVPCID = ""
@@ -1976,7 +2029,7 @@ func (e *environ) ensureGroup(ctx context.ProviderCallContext, controllerUUID, n
 		}
 		logger.Debugf("created security group %q with ID %q%s", name, g.Id, inVPCLogSuffix)
 	} else {
-		resp, err := e.securityGroupsByNameOrID(name)
+		resp, err := e.securityGroupsByNameOrID(ctx, name)
 		if err != nil {
 			return zeroGroup, errors.Annotatef(maybeConvertCredentialError(err, ctx), "fetching security group %q%s", name, inVPCLogSuffix)
 		}
@@ -2105,6 +2158,8 @@ func isZoneConstrainedError(err error) bool {
 		case "VolumeTypeNotAvailableInZone":
 			return true
 		}
+	case *spotCapacityError:
+		return true
 	}
 	return false
 }
@@ -2210,7 +2265,10 @@ func (*environ) SSHAddresses(ctx context.ProviderCallContext, addresses corenetw
 
 // SuperSubnets implements NetworkingEnviron.SuperSubnets
 func (e *environ) SuperSubnets(ctx context.ProviderCallContext) ([]string, error) {
-	vpcId := e.ecfg().vpcID()
+	vpcId, err := e.vpcID(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	if !isVPCIDSet(vpcId) {
 		if hasDefaultVPC, err := e.hasDefaultVPC(ctx); err == nil && hasDefaultVPC {
 			vpcId = e.defaultVPC.Id
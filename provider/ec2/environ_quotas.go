@@ -0,0 +1,117 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"strconv"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+var _ environs.QuotaChecker = (*environ)(nil)
+
+const (
+	// maxInstancesAttribute is the EC2 account attribute holding the
+	// most on-demand instances that may run in the region.
+	maxInstancesAttribute = "max-instances"
+
+	// quotaInstances is the name of the instances quota reported to
+	// users.
+	quotaInstances = "instances"
+)
+
+// quotaAPIClient defines the subset of the EC2 API needed to report the
+// quotas of a region.
+type quotaAPIClient interface {
+	AccountAttributes(attributeNames ...string) (*ec2.AccountAttributesResp, error)
+	Instances(instIds []string, filter *ec2.Filter) (*ec2.InstancesResp, error)
+}
+
+// Quotas is part of the environs.QuotaChecker interface. EC2 reports
+// the limit on the number of on-demand instances in the region; its
+// usage is the number of pending and running on-demand instances.
+func (e *environ) Quotas(ctx context.ProviderCallContext) ([]environs.Quota, error) {
+	return regionQuotas(e.ec2, ctx)
+}
+
+func regionQuotas(client quotaAPIClient, ctx context.ProviderCallContext) ([]environs.Quota, error) {
+	resp, err := client.AccountAttributes(maxInstancesAttribute)
+	if err != nil {
+		return nil, errors.Annotate(maybeConvertCredentialError(err, ctx), "getting account attributes")
+	}
+	var quotas []environs.Quota
+	for _, attr := range resp.Attributes {
+		if attr.Name != maxInstancesAttribute || len(attr.Values) == 0 {
+			continue
+		}
+		limit, err := strconv.ParseFloat(attr.Values[0], 64)
+		if err != nil {
+			return nil, errors.Annotatef(err, "parsing %s account attribute", maxInstancesAttribute)
+		}
+		usage, err := onDemandInstanceCount(client, ctx)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		quotas = append(quotas, environs.Quota{
+			Name:  quotaInstances,
+			Limit: limit,
+			Usage: float64(usage),
+		})
+	}
+	return quotas, nil
+}
+
+// onDemandInstanceCount returns the number of pending and running
+// on-demand instances in the region, whether or not juju started them.
+// On-demand instances have no lifecycle filter, so they are counted as
+// all instances less the spot instances.
+func onDemandInstanceCount(client quotaAPIClient, ctx context.ProviderCallContext) (int, error) {
+	all, err := aliveInstanceCount(client, ctx, "")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	spot, err := aliveInstanceCount(client, ctx, "spot")
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return all - spot, nil
+}
+
+// aliveInstanceCount returns the number of pending and running instances
+// in the region with the given lifecycle, or all of them if lifecycle is
+// empty.
+func aliveInstanceCount(client quotaAPIClient, ctx context.ProviderCallContext, lifecycle string) (int, error) {
+	filter := ec2.NewFilter()
+	filter.Add("instance-state-name", aliveInstanceStates...)
+	if lifecycle != "" {
+		filter.Add("instance-lifecycle", lifecycle)
+	}
+	resp, err := client.Instances(nil, filter)
+	if err != nil {
+		return 0, errors.Annotate(maybeConvertCredentialError(err, ctx), "listing instances")
+	}
+	count := 0
+	for _, r := range resp.Reservations {
+		count += len(r.Instances)
+	}
+	return count, nil
+}
+
+// CheckInstanceQuotas is part of the environs.QuotaChecker interface.
+// It checks the region has room for one more on-demand instance. Spot
+// instances are not limited by the region's instance quota.
+func (e *environ) CheckInstanceQuotas(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	if args.Constraints.HasSpot() {
+		return nil
+	}
+	quotas, err := e.Quotas(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return environs.CheckQuotas(quotas, map[string]float64{quotaInstances: 1})
+}
@@ -0,0 +1,105 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+type quotasSuite struct {
+	testing.IsolationSuite
+
+	stubAPI *stubQuotaAPIClient
+
+	cloudCallCtx context.ProviderCallContext
+}
+
+var _ = gc.Suite(&quotasSuite{})
+
+func (s *quotasSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stubAPI = &stubQuotaAPIClient{Stub: &testing.Stub{}}
+	s.cloudCallCtx = context.NewCloudCallContext()
+}
+
+func (s *quotasSuite) TestRegionQuotas(c *gc.C) {
+	s.stubAPI.attributes = map[string][]string{maxInstancesAttribute: {"20"}}
+	s.stubAPI.instanceCounts = []int{7, 2}
+
+	quotas, err := regionQuotas(s.stubAPI, s.cloudCallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, jc.DeepEquals, []environs.Quota{
+		{Name: "instances", Limit: 20, Usage: 5},
+	})
+	s.stubAPI.CheckCallNames(c, "AccountAttributes", "Instances", "Instances")
+	s.stubAPI.CheckCall(c, 0, "AccountAttributes", maxInstancesAttribute)
+}
+
+func (s *quotasSuite) TestRegionQuotasNoLimit(c *gc.C) {
+	s.stubAPI.attributes = map[string][]string{}
+
+	quotas, err := regionQuotas(s.stubAPI, s.cloudCallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, gc.HasLen, 0)
+	s.stubAPI.CheckCallNames(c, "AccountAttributes")
+}
+
+func (s *quotasSuite) TestRegionQuotasExceeded(c *gc.C) {
+	s.stubAPI.attributes = map[string][]string{maxInstancesAttribute: {"5"}}
+	s.stubAPI.instanceCounts = []int{5, 0}
+
+	quotas, err := regionQuotas(s.stubAPI, s.cloudCallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	err = environs.CheckQuotas(quotas, map[string]float64{quotaInstances: 1})
+	c.Assert(err, gc.ErrorMatches, "quota exceeded: instances")
+	c.Assert(environs.IsQuotaExceeded(err), jc.IsTrue)
+}
+
+func (s *quotasSuite) TestRegionQuotasError(c *gc.C) {
+	s.stubAPI.SetErrors(errors.New("boom"))
+
+	_, err := regionQuotas(s.stubAPI, s.cloudCallCtx)
+	c.Assert(err, gc.ErrorMatches, "getting account attributes: boom")
+}
+
+// stubQuotaAPIClient implements quotaAPIClient.
+type stubQuotaAPIClient struct {
+	*testing.Stub
+
+	attributes     map[string][]string
+	instanceCounts []int
+}
+
+func (s *stubQuotaAPIClient) AccountAttributes(attributeNames ...string) (*ec2.AccountAttributesResp, error) {
+	s.AddCall("AccountAttributes", makeArgsFromStrings(attributeNames...)...)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	resp := &ec2.AccountAttributesResp{}
+	for _, name := range attributeNames {
+		if values, ok := s.attributes[name]; ok {
+			resp.Attributes = append(resp.Attributes, ec2.AccountAttribute{Name: name, Values: values})
+		}
+	}
+	return resp, nil
+}
+
+func (s *stubQuotaAPIClient) Instances(instIds []string, filter *ec2.Filter) (*ec2.InstancesResp, error) {
+	s.AddCall("Instances", instIds, filter)
+	if err := s.NextErr(); err != nil {
+		return nil, err
+	}
+	count := s.instanceCounts[0]
+	s.instanceCounts = s.instanceCounts[1:]
+	return &ec2.InstancesResp{
+		Reservations: []ec2.Reservation{{Instances: make([]ec2.Instance, count)}},
+	}, nil
+}
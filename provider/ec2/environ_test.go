@@ -4,8 +4,14 @@
 package ec2
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/aws"
 	amzec2 "gopkg.in/amz.v3/ec2"
 	gc "gopkg.in/check.v1"
 
@@ -200,3 +206,84 @@ func (*Suite) TestSupportsContainerAddresses(c *gc.C) {
 	c.Assert(supported, jc.IsFalse)
 	c.Check(environs.SupportsContainerAddresses(callCtx, env), jc.IsFalse)
 }
+
+func (*Suite) TestIsSpotInterruption(c *gc.C) {
+	for _, code := range []string{
+		"marked-for-termination",
+		"marked-for-stop",
+		"instance-terminated-by-price",
+		"instance-terminated-no-capacity",
+		"instance-stopped-by-price",
+	} {
+		c.Check(isSpotInterruption(code), jc.IsTrue, gc.Commentf("%s", code))
+	}
+	for _, code := range []string{
+		"fulfilled",
+		"pending-fulfillment",
+		"instance-terminated-by-user",
+		"instance-stopped-by-user",
+		"",
+	} {
+		c.Check(isSpotInterruption(code), jc.IsFalse, gc.Commentf("%s", code))
+	}
+}
+
+func (*Suite) TestRequestSpotInstances(c *gc.C) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `<RequestSpotInstancesResponse>
+<requestId>req-0</requestId>
+<spotInstanceRequestSet><item>
+<spotInstanceRequestId>sir-0</spotInstanceRequestId>
+<state>open</state>
+<status><code>pending-evaluation</code><message>Your Spot request has been submitted.</message></status>
+</item></spotInstanceRequestSet>
+</RequestSpotInstancesResponse>`)
+	}))
+	defer server.Close()
+	e := amzec2.New(aws.Auth{}, aws.Region{EC2Endpoint: server.URL}, aws.SignV4Factory("test", "ec2"))
+
+	resp, err := requestSpotInstances(e, &amzec2.RunInstances{
+		ImageId:        "ami-0",
+		InstanceType:   "m5.large",
+		SecurityGroups: []amzec2.SecurityGroup{{Id: "sg-0"}},
+		AvailZone:      "test-available",
+		SubnetId:       "subnet-0",
+		UserData:       []byte("#cloud-config"),
+	}, "0.05")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.SpotRequestResults, jc.DeepEquals, []spotRequestResult{{
+		SpotRequestId: "sir-0",
+		State:         "open",
+		Status: spotRequestStatus{
+			Code:    "pending-evaluation",
+			Message: "Your Spot request has been submitted.",
+		},
+	}})
+
+	c.Check(query.Get("Action"), gc.Equals, "RequestSpotInstances")
+	c.Check(query.Get("Type"), gc.Equals, "one-time")
+	c.Check(query.Get("SpotPrice"), gc.Equals, "0.05")
+	c.Check(query.Get("LaunchSpecification.ImageId"), gc.Equals, "ami-0")
+	c.Check(query.Get("LaunchSpecification.SecurityGroupId.1"), gc.Equals, "sg-0")
+	c.Check(query.Get("LaunchSpecification.Placement.AvailabilityZone"), gc.Equals, "test-available")
+	c.Check(query.Get("LaunchSpecification.SubnetId"), gc.Equals, "subnet-0")
+	c.Check(query.Get("LaunchSpecification.UserData"), gc.Equals, "I2Nsb3VkLWNvbmZpZw==")
+}
+
+func (*Suite) TestQueryError(c *gc.C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error>
+<Code>InvalidSpotInstanceRequestID.NotFound</Code>
+<Message>The spot instance request ID 'sir-0' does not exist</Message>
+</Error></Errors><RequestID>req-0</RequestID></Response>`)
+	}))
+	defer server.Close()
+	e := amzec2.New(aws.Auth{}, aws.Region{EC2Endpoint: server.URL}, aws.SignV4Factory("test", "ec2"))
+
+	_, err := describeSpotRequests(e, []string{"sir-0"}, nil)
+	c.Assert(err, gc.ErrorMatches, `The spot instance request ID 'sir-0' does not exist \(InvalidSpotInstanceRequestID.NotFound\)`)
+	c.Assert(ec2ErrCode(err), gc.Equals, "InvalidSpotInstanceRequestID.NotFound")
+}
@@ -508,7 +508,7 @@ func findSubnetIDsForAvailabilityZone(zoneName string, subnetsToZones map[networ
 }
 
 func isVPCIDSetButInvalid(vpcID string) bool {
-	return isVPCIDSet(vpcID) && !strings.HasPrefix(vpcID, "vpc-")
+	return isVPCIDSet(vpcID) && vpcID != vpcIDCreate && !strings.HasPrefix(vpcID, "vpc-")
 }
 
 func isVPCIDSet(vpcID string) bool {
@@ -519,7 +519,8 @@ func validateBootstrapVPC(apiClient vpcAPIClient, cloudCtx context.ProviderCallC
 	if vpcID == vpcIDNone {
 		ctx.Infof("Using EC2-classic features or default VPC in region %q", region)
 	}
-	if !isVPCIDSet(vpcID) {
+	if !isVPCIDSet(vpcID) || vpcID == vpcIDCreate {
+		// A created VPC meets all the requirements.
 		return nil
 	}
 
@@ -545,7 +546,7 @@ func validateBootstrapVPC(apiClient vpcAPIClient, cloudCtx context.ProviderCallC
 }
 
 func validateModelVPC(apiClient vpcAPIClient, ctx context.ProviderCallContext, modelName, vpcID string) error {
-	if !isVPCIDSet(vpcID) {
+	if !isVPCIDSet(vpcID) || vpcID == vpcIDCreate {
 		return nil
 	}
 
@@ -0,0 +1,421 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	"gopkg.in/amz.v3/ec2"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/provider/common"
+)
+
+const (
+	// vpcIDCreate is the value of vpc-id that makes Juju create a VPC
+	// for the model, and delete it again when the model is destroyed.
+	vpcIDCreate = "create"
+
+	// defaultVPCCIDRBlock is the default network-cidr, the CIDR block
+	// of the VPCs Juju creates. Each availability zone gets a subnet
+	// of a sixteenth of it, so there can be at most
+	// maxCreatedVPCSubnets of them.
+	defaultVPCCIDRBlock  = "10.0.0.0/16"
+	maxCreatedVPCSubnets = 16
+
+	// minVPCPrefixLength and maxVPCPrefixLength bound the size of the
+	// VPCs Juju creates. EC2 does not allow larger VPCs, nor subnets
+	// smaller than a /28.
+	minVPCPrefixLength = 16
+	maxVPCPrefixLength = 24
+)
+
+// vpcManagementClient extends vpcAPIClient with the calls needed to
+// create and delete the VPCs of models with vpc-id set to "create".
+type vpcManagementClient interface {
+	vpcAPIClient
+
+	CreateVPC(cidrBlock, instanceTenancy string) (*ec2.CreateVPCResp, error)
+	DeleteVPC(id string) (*ec2.SimpleResp, error)
+	CreateSubnet(vpcID, cidrBlock, availZone string) (*ec2.CreateSubnetResp, error)
+	DeleteSubnet(id string) (*ec2.SimpleResp, error)
+	ModifySubnetAttribute(id, attrName, attrValue string) (*ec2.SimpleResp, error)
+	CreateTags(resourceIds []string, tags []ec2.Tag) (*ec2.SimpleResp, error)
+
+	// The remaining calls are not provided by the amz.v3 client.
+	EnableVPCDNSHostnames(vpcID string) error
+	CreateInternetGateway() (string, error)
+	AttachInternetGateway(igwID, vpcID string) error
+	DetachInternetGateway(igwID, vpcID string) error
+	DeleteInternetGateway(igwID string) error
+	CreateRoute(routeTableID, cidrBlock, igwID string) error
+}
+
+// vpcQueryClient implements vpcManagementClient, making the calls the
+// amz.v3 client lacks through the EC2 query API.
+type vpcQueryClient struct {
+	*ec2.EC2
+}
+
+type simpleQueryResp struct {
+	RequestId string `xml:"requestId"`
+	Return    bool   `xml:"return"`
+}
+
+// EnableVPCDNSHostnames is part of the vpcManagementClient interface.
+func (c vpcQueryClient) EnableVPCDNSHostnames(vpcID string) error {
+	return ec2Query(c.EC2, "ModifyVpcAttribute", map[string]string{
+		"VpcId":                    vpcID,
+		"EnableDnsHostnames.Value": "true",
+	}, &simpleQueryResp{})
+}
+
+// CreateInternetGateway is part of the vpcManagementClient interface.
+func (c vpcQueryClient) CreateInternetGateway() (string, error) {
+	var resp struct {
+		InternetGatewayId string `xml:"internetGateway>internetGatewayId"`
+	}
+	if err := ec2Query(c.EC2, "CreateInternetGateway", nil, &resp); err != nil {
+		return "", err
+	}
+	return resp.InternetGatewayId, nil
+}
+
+// AttachInternetGateway is part of the vpcManagementClient interface.
+func (c vpcQueryClient) AttachInternetGateway(igwID, vpcID string) error {
+	return ec2Query(c.EC2, "AttachInternetGateway", map[string]string{
+		"InternetGatewayId": igwID,
+		"VpcId":             vpcID,
+	}, &simpleQueryResp{})
+}
+
+// DetachInternetGateway is part of the vpcManagementClient interface.
+func (c vpcQueryClient) DetachInternetGateway(igwID, vpcID string) error {
+	return ec2Query(c.EC2, "DetachInternetGateway", map[string]string{
+		"InternetGatewayId": igwID,
+		"VpcId":             vpcID,
+	}, &simpleQueryResp{})
+}
+
+// DeleteInternetGateway is part of the vpcManagementClient interface.
+func (c vpcQueryClient) DeleteInternetGateway(igwID string) error {
+	return ec2Query(c.EC2, "DeleteInternetGateway", map[string]string{
+		"InternetGatewayId": igwID,
+	}, &simpleQueryResp{})
+}
+
+// CreateRoute is part of the vpcManagementClient interface.
+func (c vpcQueryClient) CreateRoute(routeTableID, cidrBlock, igwID string) error {
+	return ec2Query(c.EC2, "CreateRoute", map[string]string{
+		"RouteTableId":         routeTableID,
+		"DestinationCidrBlock": cidrBlock,
+		"GatewayId":            igwID,
+	}, &simpleQueryResp{})
+}
+
+// validateVPCCIDRBlock checks that cidr can be used as the CIDR block
+// of a VPC created by Juju.
+func validateVPCCIDRBlock(cidr string) error {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return err
+	}
+	if ip.To4() == nil {
+		return errors.New("not an IPv4 range")
+	}
+	if !ip.Equal(ipNet.IP) {
+		return errors.Errorf("not a network address, expected %s", ipNet)
+	}
+	ones, _ := ipNet.Mask.Size()
+	if ones < minVPCPrefixLength || ones > maxVPCPrefixLength {
+		return errors.Errorf("prefix length must be between /%d and /%d", minVPCPrefixLength, maxVPCPrefixLength)
+	}
+	return nil
+}
+
+// vpcSubnetCIDRBlocks returns the CIDR blocks of the first n of the
+// sixteen subnets the VPC CIDR block is divided into.
+func vpcSubnetCIDRBlocks(cidr string, n int) ([]string, error) {
+	if err := validateVPCCIDRBlock(cidr); err != nil {
+		return nil, errors.Annotatef(err, "VPC CIDR block %q", cidr)
+	}
+	_, ipNet, _ := net.ParseCIDR(cidr)
+	ones, _ := ipNet.Mask.Size()
+	subnetOnes := ones + 4
+	base := binary.BigEndian.Uint32(ipNet.IP.To4())
+	size := uint32(1) << uint(32-subnetOnes)
+	blocks := make([]string, n)
+	for i := range blocks {
+		ip := make(net.IP, net.IPv4len)
+		binary.BigEndian.PutUint32(ip, base+uint32(i)*size)
+		blocks[i] = fmt.Sprintf("%s/%d", ip, subnetOnes)
+	}
+	return blocks, nil
+}
+
+// createVPC creates a VPC with the given CIDR block that meets all of
+// Juju's requirements: it has an internet gateway, a default route to
+// it in the main route table, and a public subnet in each of the given
+// availability zones. All the resources are tagged with the given tags,
+// and given names starting with the given name. The VPC is tagged
+// first, so that it can be found and deleted even if creating the rest
+// of it fails.
+func createVPC(client vpcManagementClient, ctx context.ProviderCallContext, name, cidr string, zones []string, resourceTags map[string]string) (string, error) {
+	if len(zones) == 0 {
+		return "", errors.New("no availability zones to create subnets in")
+	}
+	if len(zones) > maxCreatedVPCSubnets {
+		zones = zones[:maxCreatedVPCSubnets]
+	}
+	subnetCIDRs, err := vpcSubnetCIDRBlocks(cidr, len(zones))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	resp, err := client.CreateVPC(cidr, "")
+	if err != nil {
+		return "", errors.Annotate(maybeConvertCredentialError(err, ctx), "creating VPC")
+	}
+	vpc := resp.VPC
+	if err := tagResources(client, ctx, withNameTag(resourceTags, name), vpc.Id); err != nil {
+		return "", errors.Annotatef(err, "tagging VPC %q", vpc.Id)
+	}
+	if err := client.EnableVPCDNSHostnames(vpc.Id); err != nil {
+		return "", errors.Annotatef(maybeConvertCredentialError(err, ctx), "enabling DNS hostnames in VPC %q", vpc.Id)
+	}
+
+	igwID, err := client.CreateInternetGateway()
+	if err != nil {
+		return "", errors.Annotate(maybeConvertCredentialError(err, ctx), "creating internet gateway")
+	}
+	if err := tagResources(client, ctx, withNameTag(resourceTags, name), igwID); err != nil {
+		return "", errors.Annotatef(err, "tagging internet gateway %q", igwID)
+	}
+	if err := client.AttachInternetGateway(igwID, vpc.Id); err != nil {
+		return "", errors.Annotatef(maybeConvertCredentialError(err, ctx), "attaching internet gateway %q", igwID)
+	}
+	routeTables, err := getVPCRouteTables(client, ctx, &vpc)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	mainTable, err := findVPCMainRouteTable(routeTables)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := client.CreateRoute(mainTable.Id, defaultRouteCIDRBlock, igwID); err != nil {
+		return "", errors.Annotatef(maybeConvertCredentialError(err, ctx), "adding default route to route table %q", mainTable.Id)
+	}
+
+	for i, zone := range zones {
+		resp, err := client.CreateSubnet(vpc.Id, subnetCIDRs[i], zone)
+		if err != nil {
+			return "", errors.Annotatef(maybeConvertCredentialError(err, ctx), "creating subnet in zone %q", zone)
+		}
+		subnetID := resp.Subnet.Id
+		if err := tagResources(client, ctx, withNameTag(resourceTags, name+"-"+zone), subnetID); err != nil {
+			return "", errors.Annotatef(err, "tagging subnet %q", subnetID)
+		}
+		if _, err := client.ModifySubnetAttribute(subnetID, "MapPublicIpOnLaunch", "true"); err != nil {
+			return "", errors.Annotatef(maybeConvertCredentialError(err, ctx), "making subnet %q public", subnetID)
+		}
+	}
+	return vpc.Id, nil
+}
+
+func withNameTag(resourceTags map[string]string, name string) map[string]string {
+	result := make(map[string]string, len(resourceTags)+1)
+	for k, v := range resourceTags {
+		result[k] = v
+	}
+	result[tagName] = name
+	return result
+}
+
+// createdVPCs returns the VPCs created by Juju that have all the given
+// tags. Only VPCs created by Juju have Juju's tags.
+func createdVPCs(client vpcAPIClient, ctx context.ProviderCallContext, matchTags map[string]string) ([]ec2.VPC, error) {
+	filter := ec2.NewFilter()
+	for k, v := range matchTags {
+		filter.Add("tag:"+k, v)
+	}
+	resp, err := client.VPCs(nil, filter)
+	if err != nil {
+		return nil, errors.Annotate(maybeConvertCredentialError(err, ctx), "listing VPCs")
+	}
+	return resp.VPCs, nil
+}
+
+// deleteVPC deletes a VPC created by createVPC, along with its subnets
+// and internet gateway. Subnets cannot be deleted until the network
+// interfaces of terminated instances have gone, so deleting them is
+// retried for a while.
+func deleteVPC(client vpcManagementClient, ctx context.ProviderCallContext, clock clock.Clock, vpcID string) error {
+	filter := ec2.NewFilter()
+	filter.Add("attachment.vpc-id", vpcID)
+	igwResp, err := client.InternetGateways(nil, filter)
+	if err != nil {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "listing internet gateways")
+	}
+	for _, igw := range igwResp.InternetGateways {
+		if err := client.DetachInternetGateway(igw.Id, vpcID); err != nil && !isResourceNotFoundError(err) {
+			return errors.Annotatef(maybeConvertCredentialError(err, ctx), "detaching internet gateway %q", igw.Id)
+		}
+		if err := client.DeleteInternetGateway(igw.Id); err != nil && !isResourceNotFoundError(err) {
+			return errors.Annotatef(maybeConvertCredentialError(err, ctx), "deleting internet gateway %q", igw.Id)
+		}
+	}
+
+	filter = ec2.NewFilter()
+	filter.Add("vpc-id", vpcID)
+	subnetsResp, err := client.Subnets(nil, filter)
+	if err != nil {
+		return errors.Annotate(maybeConvertCredentialError(err, ctx), "listing subnets")
+	}
+	for _, subnet := range subnetsResp.Subnets {
+		if err := deleteInsistently(ctx, clock, "subnet "+subnet.Id, func() error {
+			_, err := client.DeleteSubnet(subnet.Id)
+			return err
+		}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	return deleteInsistently(ctx, clock, "VPC "+vpcID, func() error {
+		_, err := client.DeleteVPC(vpcID)
+		return err
+	})
+}
+
+// isResourceNotFoundError reports whether err is an EC2 error for a
+// resource that does not exist, e.g. "InvalidSubnetID.NotFound".
+func isResourceNotFoundError(err error) bool {
+	return strings.HasSuffix(ec2ErrCode(err), ".NotFound")
+}
+
+// deleteInsistently calls the delete function until it succeeds, or
+// fails for a reason other than a dependency on another resource that
+// is still being deleted.
+func deleteInsistently(ctx context.ProviderCallContext, clock clock.Clock, what string, delete func() error) error {
+	err := retry.Call(retry.CallArgs{
+		Attempts:    30,
+		Delay:       time.Second,
+		MaxDelay:    time.Minute,
+		BackoffFunc: retry.DoubleDelay,
+		Clock:       clock,
+		Func: func() error {
+			err := delete()
+			if err == nil || isResourceNotFoundError(err) {
+				logger.Debugf("deleted %s", what)
+				return nil
+			}
+			return errors.Trace(maybeConvertCredentialError(err, ctx))
+		},
+		IsFatalError: func(err error) bool {
+			return common.IsCredentialNotValid(err) || ec2ErrCode(err) != "DependencyViolation"
+		},
+		NotifyFunc: func(err error, attempt int) {
+			logger.Debugf("deleting %s, attempt %d: %v", what, attempt, err)
+		},
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot delete %s: consider deleting it manually", what)
+	}
+	return nil
+}
+
+// vpcID returns the vpc-id model config setting or, if that is "create",
+// the ID of the VPC Juju created for the model.
+func (e *environ) vpcID(ctx context.ProviderCallContext) (string, error) {
+	vpcID := e.ecfg().vpcID()
+	if vpcID != vpcIDCreate {
+		return vpcID, nil
+	}
+	e.createdVPCMutex.Lock()
+	defer e.createdVPCMutex.Unlock()
+	return e.createdVPCIDLocked(ctx)
+}
+
+// createdVPCIDLocked returns the ID of the VPC Juju created for the
+// model, or an error satisfying errors.IsNotFound if there is none.
+// createdVPCMutex must be held.
+func (e *environ) createdVPCIDLocked(ctx context.ProviderCallContext) (string, error) {
+	if e.createdVPCID != "" {
+		return e.createdVPCID, nil
+	}
+	vpcs, err := createdVPCs(e.ec2, ctx, map[string]string{tags.JujuModel: e.uuid()})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(vpcs) == 0 {
+		return "", errors.NotFoundf("VPC created for model %q", e.name)
+	}
+	e.createdVPCID = vpcs[0].Id
+	return e.createdVPCID, nil
+}
+
+// ensureModelVPC creates a VPC for the model, unless it already has
+// one. It is called when a model with vpc-id set to "create" is
+// bootstrapped or added. The lock is held while looking for an
+// existing VPC and creating a new one, so that concurrent calls create
+// only one VPC.
+func (e *environ) ensureModelVPC(ctx context.ProviderCallContext, controllerUUID string) error {
+	e.createdVPCMutex.Lock()
+	defer e.createdVPCMutex.Unlock()
+	if _, err := e.createdVPCIDLocked(ctx); !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	zones, err := e.AvailabilityZones(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var zoneNames []string
+	for _, zone := range zones {
+		if zone.Available() {
+			zoneNames = append(zoneNames, zone.Name())
+		}
+	}
+	ecfg := e.ecfg()
+	resourceTags := tags.ResourceTags(
+		names.NewModelTag(ecfg.UUID()),
+		names.NewControllerTag(controllerUUID),
+		ecfg,
+	)
+
+	vpcID, err := createVPC(vpcQueryClient{e.ec2}, ctx, "juju-"+e.name, ecfg.networkCIDR(), zoneNames, resourceTags)
+	if err != nil {
+		return errors.Annotate(err, "creating VPC")
+	}
+	logger.Infof("created VPC %q for model %q", vpcID, e.name)
+	e.createdVPCID = vpcID
+	return nil
+}
+
+// deleteCreatedVPCs deletes the VPCs Juju created that have all the
+// given tags.
+func (e *environ) deleteCreatedVPCs(ctx context.ProviderCallContext, matchTags map[string]string) error {
+	client := vpcQueryClient{e.ec2}
+	vpcs, err := createdVPCs(client, ctx, matchTags)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, vpc := range vpcs {
+		if err := deleteVPC(client, ctx, clock.WallClock, vpc.Id); err != nil {
+			return errors.Annotatef(err, "deleting VPC %q", vpc.Id)
+		}
+		logger.Infof("deleted VPC %q", vpc.Id)
+	}
+	e.createdVPCMutex.Lock()
+	e.createdVPCID = ""
+	e.createdVPCMutex.Unlock()
+	return nil
+}
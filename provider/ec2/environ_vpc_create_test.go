@@ -0,0 +1,242 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/context"
+)
+
+type vpcCreateSuite struct {
+	testing.IsolationSuite
+
+	stubAPI *stubVPCManagementClient
+
+	cloudCallCtx context.ProviderCallContext
+}
+
+var _ = gc.Suite(&vpcCreateSuite{})
+
+func (s *vpcCreateSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stubAPI = &stubVPCManagementClient{
+		stubVPCAPIClient: &stubVPCAPIClient{Stub: &testing.Stub{}},
+	}
+	s.cloudCallCtx = context.NewCloudCallContext()
+}
+
+func (s *vpcCreateSuite) TestCreateVPC(c *gc.C) {
+	s.stubAPI.SetRouteTablesResponse(makeEC2RouteTable("rtb-main", true, nil, nil))
+
+	vpcID, err := createVPC(s.stubAPI, s.cloudCallCtx, "juju-test", "10.0.0.0/16", []string{"zone-a", "zone-b"}, map[string]string{
+		"juju-model-uuid": "model-uuid",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vpcID, gc.Equals, "vpc-new")
+
+	s.stubAPI.CheckCallNames(c,
+		"CreateVPC", "CreateTags", "EnableVPCDNSHostnames",
+		"CreateInternetGateway", "CreateTags", "AttachInternetGateway",
+		"RouteTables", "CreateRoute",
+		"CreateSubnet", "CreateTags", "ModifySubnetAttribute",
+		"CreateSubnet", "CreateTags", "ModifySubnetAttribute",
+	)
+	s.stubAPI.CheckCall(c, 0, "CreateVPC", "10.0.0.0/16", "")
+	s.stubAPI.CheckCall(c, 1, "CreateTags", []string{"vpc-new"}, map[string]string{
+		"juju-model-uuid": "model-uuid",
+		"Name":            "juju-test",
+	})
+	s.stubAPI.CheckCall(c, 5, "AttachInternetGateway", "igw-new", "vpc-new")
+	s.stubAPI.CheckCall(c, 7, "CreateRoute", "rtb-main", "0.0.0.0/0", "igw-new")
+	s.stubAPI.CheckCall(c, 8, "CreateSubnet", "vpc-new", "10.0.0.0/20", "zone-a")
+	s.stubAPI.CheckCall(c, 9, "CreateTags", []string{"subnet-zone-a"}, map[string]string{
+		"juju-model-uuid": "model-uuid",
+		"Name":            "juju-test-zone-a",
+	})
+	s.stubAPI.CheckCall(c, 10, "ModifySubnetAttribute", "subnet-zone-a", "MapPublicIpOnLaunch", "true")
+	s.stubAPI.CheckCall(c, 11, "CreateSubnet", "vpc-new", "10.0.16.0/20", "zone-b")
+}
+
+func (s *vpcCreateSuite) TestCreateVPCCIDR(c *gc.C) {
+	s.stubAPI.SetRouteTablesResponse(makeEC2RouteTable("rtb-main", true, nil, nil))
+
+	_, err := createVPC(s.stubAPI, s.cloudCallCtx, "juju-test", "192.168.0.0/20", []string{"zone-a", "zone-b"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stubAPI.CheckCall(c, 0, "CreateVPC", "192.168.0.0/20", "")
+	s.stubAPI.CheckCall(c, 8, "CreateSubnet", "vpc-new", "192.168.0.0/24", "zone-a")
+	s.stubAPI.CheckCall(c, 11, "CreateSubnet", "vpc-new", "192.168.1.0/24", "zone-b")
+}
+
+func (s *vpcCreateSuite) TestVPCSubnetCIDRBlocks(c *gc.C) {
+	blocks, err := vpcSubnetCIDRBlocks("10.1.0.0/16", 3)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blocks, jc.DeepEquals, []string{"10.1.0.0/20", "10.1.16.0/20", "10.1.32.0/20"})
+
+	blocks, err = vpcSubnetCIDRBlocks("172.16.4.0/24", 16)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blocks[1], gc.Equals, "172.16.4.16/28")
+	c.Assert(blocks[15], gc.Equals, "172.16.4.240/28")
+}
+
+func (s *vpcCreateSuite) TestValidateVPCCIDRBlock(c *gc.C) {
+	for cidr, expectErr := range map[string]string{
+		"10.0.0.0/16":    "",
+		"192.168.8.0/24": "",
+		"10.0.0.0":       "invalid CIDR address: 10.0.0.0",
+		"10.0.0.1/16":    `not a network address, expected 10.0.0.0/16`,
+		"10.0.0.0/8":     `prefix length must be between /16 and /24`,
+		"10.0.0.0/25":    `prefix length must be between /16 and /24`,
+		"fd00::/48":      "not an IPv4 range",
+	} {
+		err := validateVPCCIDRBlock(cidr)
+		if expectErr == "" {
+			c.Check(err, jc.ErrorIsNil, gc.Commentf("%s", cidr))
+		} else {
+			c.Check(err, gc.ErrorMatches, expectErr, gc.Commentf("%s", cidr))
+		}
+	}
+}
+
+func (s *vpcCreateSuite) TestCreateVPCNoZones(c *gc.C) {
+	_, err := createVPC(s.stubAPI, s.cloudCallCtx, "juju-test", "10.0.0.0/16", nil, nil)
+	c.Assert(err, gc.ErrorMatches, "no availability zones to create subnets in")
+	s.stubAPI.CheckNoCalls(c)
+}
+
+func (s *vpcCreateSuite) TestCreateVPCError(c *gc.C) {
+	s.stubAPI.SetErrors(nil, nil, nil, errors.New("boom"))
+
+	_, err := createVPC(s.stubAPI, s.cloudCallCtx, "juju-test", "10.0.0.0/16", []string{"zone-a"}, nil)
+	c.Assert(err, gc.ErrorMatches, "creating internet gateway: boom")
+	s.stubAPI.CheckCallNames(c, "CreateVPC", "CreateTags", "EnableVPCDNSHostnames", "CreateInternetGateway")
+}
+
+func (s *vpcCreateSuite) TestCreatedVPCs(c *gc.C) {
+	s.stubAPI.vpcsResponse = &ec2.VPCsResp{VPCs: []ec2.VPC{{
+		Id:   "vpc-1",
+		Tags: []ec2.Tag{{Key: "juju-model-uuid", Value: "model-1"}, {Key: "juju-controller-uuid", Value: "controller"}},
+	}}}
+
+	vpcs, err := createdVPCs(s.stubAPI, s.cloudCallCtx, map[string]string{"juju-model-uuid": "model-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vpcs, gc.HasLen, 1)
+	c.Assert(vpcs[0].Id, gc.Equals, "vpc-1")
+
+	// The VPCs are filtered on their tags by EC2.
+	filter := ec2.NewFilter()
+	filter.Add("tag:juju-model-uuid", "model-1")
+	s.stubAPI.CheckCalls(c, []testing.StubCall{{"VPCs", []interface{}{[]string(nil), filter}}})
+}
+
+func (s *vpcCreateSuite) TestDeleteVPC(c *gc.C) {
+	s.stubAPI.SetGatewaysResponse(1, "available")
+	s.stubAPI.SetSubnetsResponse(2, "zone-a", true)
+
+	err := deleteVPC(s.stubAPI, s.cloudCallCtx, testclock.NewClock(time.Time{}), "vpc-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stubAPI.CheckCallNames(c,
+		"InternetGateways", "DetachInternetGateway", "DeleteInternetGateway",
+		"Subnets", "DeleteSubnet", "DeleteSubnet", "DeleteVPC",
+	)
+	s.stubAPI.CheckCall(c, 1, "DetachInternetGateway", "igw-0", "vpc-0")
+	s.stubAPI.CheckCall(c, 4, "DeleteSubnet", "subnet-0")
+	s.stubAPI.CheckCall(c, 6, "DeleteVPC", "vpc-0")
+}
+
+func (s *vpcCreateSuite) TestDeleteVPCAlreadyDeleted(c *gc.C) {
+	s.stubAPI.SetGatewaysResponse(0, "")
+	s.stubAPI.SetSubnetsResponse(0, "", false)
+	s.stubAPI.SetErrors(nil, nil, makeVPCNotFoundError("vpc-0"))
+
+	err := deleteVPC(s.stubAPI, s.cloudCallCtx, testclock.NewClock(time.Time{}), "vpc-0")
+	c.Assert(err, jc.ErrorIsNil)
+	s.stubAPI.CheckCallNames(c, "InternetGateways", "Subnets", "DeleteVPC")
+}
+
+func (s *vpcCreateSuite) TestDeleteVPCError(c *gc.C) {
+	s.stubAPI.SetGatewaysResponse(0, "")
+	s.stubAPI.SetSubnetsResponse(1, "zone-a", true)
+	s.stubAPI.SetErrors(nil, nil, makeEC2Error(400, "InvalidParameterValue", "not allowed", "fake-request-id"))
+
+	err := deleteVPC(s.stubAPI, s.cloudCallCtx, testclock.NewClock(time.Time{}), "vpc-0")
+	c.Assert(err, gc.ErrorMatches, `cannot delete subnet subnet-0: consider deleting it manually: not allowed \(InvalidParameterValue\)`)
+	s.stubAPI.CheckCallNames(c, "InternetGateways", "Subnets", "DeleteSubnet")
+}
+
+type stubVPCManagementClient struct {
+	*stubVPCAPIClient
+}
+
+func (s *stubVPCManagementClient) CreateVPC(cidrBlock, instanceTenancy string) (*ec2.CreateVPCResp, error) {
+	s.Stub.AddCall("CreateVPC", cidrBlock, instanceTenancy)
+	return &ec2.CreateVPCResp{VPC: ec2.VPC{Id: "vpc-new"}}, s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) DeleteVPC(id string) (*ec2.SimpleResp, error) {
+	s.Stub.AddCall("DeleteVPC", id)
+	return &ec2.SimpleResp{}, s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) CreateSubnet(vpcID, cidrBlock, availZone string) (*ec2.CreateSubnetResp, error) {
+	s.Stub.AddCall("CreateSubnet", vpcID, cidrBlock, availZone)
+	return &ec2.CreateSubnetResp{Subnet: ec2.Subnet{Id: "subnet-" + availZone}}, s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) DeleteSubnet(id string) (*ec2.SimpleResp, error) {
+	s.Stub.AddCall("DeleteSubnet", id)
+	return &ec2.SimpleResp{}, s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) ModifySubnetAttribute(id, attrName, attrValue string) (*ec2.SimpleResp, error) {
+	s.Stub.AddCall("ModifySubnetAttribute", id, attrName, attrValue)
+	return &ec2.SimpleResp{}, s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) CreateTags(resourceIds []string, tags []ec2.Tag) (*ec2.SimpleResp, error) {
+	tagMap := make(map[string]string)
+	for _, tag := range tags {
+		tagMap[tag.Key] = tag.Value
+	}
+	s.Stub.AddCall("CreateTags", resourceIds, tagMap)
+	return &ec2.SimpleResp{}, s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) EnableVPCDNSHostnames(vpcID string) error {
+	s.Stub.AddCall("EnableVPCDNSHostnames", vpcID)
+	return s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) CreateInternetGateway() (string, error) {
+	s.Stub.AddCall("CreateInternetGateway")
+	return "igw-new", s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) AttachInternetGateway(igwID, vpcID string) error {
+	s.Stub.AddCall("AttachInternetGateway", igwID, vpcID)
+	return s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) DetachInternetGateway(igwID, vpcID string) error {
+	s.Stub.AddCall("DetachInternetGateway", igwID, vpcID)
+	return s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) DeleteInternetGateway(igwID string) error {
+	s.Stub.AddCall("DeleteInternetGateway", igwID)
+	return s.Stub.NextErr()
+}
+
+func (s *stubVPCManagementClient) CreateRoute(routeTableID, cidrBlock, igwID string) error {
+	s.Stub.AddCall("CreateRoute", routeTableID, cidrBlock, igwID)
+	return s.Stub.NextErr()
+}
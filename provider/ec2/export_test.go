@@ -52,6 +52,7 @@ func AllModelGroups(e environs.Environ, ctx context.ProviderCallContext) ([]stri
 var (
 	EC2AvailabilityZones           = &ec2AvailabilityZones
 	RunInstances                   = &runInstances
+	RunSpotInstances               = &runSpotInstances
 	BlockDeviceNamer               = blockDeviceNamer
 	GetBlockDeviceMappings         = getBlockDeviceMappings
 	IsVPCNotUsableError            = isVPCNotUsableError
@@ -61,10 +62,21 @@ var (
 	DeleteSecurityGroupInsistently = &deleteSecurityGroupInsistently
 	TerminateInstancesById         = &terminateInstancesById
 	MaybeConvertCredentialError    = maybeConvertCredentialError
+	CreateVolumeSnapshot           = createVolumeSnapshot
+	DeleteVolumeSnapshot           = deleteVolumeSnapshot
+	DescribeGroupEgress            = &describeGroupEgress
+	ChangeGroupEgress              = &changeGroupEgress
 )
 
+type EgressPerm = egressPerm
+type GroupEgress = groupEgress
+
 const VPCIDNone = vpcIDNone
 
 func VerifyCredentials(env environs.Environ, ctx context.ProviderCallContext) error {
 	return verifyCredentials(env.(*environ), ctx)
 }
+
+func NewSpotCapacityError(zone, message string) error {
+	return &spotCapacityError{zone: zone, message: message}
+}
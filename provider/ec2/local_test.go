@@ -748,6 +748,144 @@ func (t *localServerSuite) TestStartInstanceHardwareCharacteristics(c *gc.C) {
 	c.Check(*hc.CpuCores, gc.Equals, uint64(2))
 }
 
+func (t *localServerSuite) TestStartInstanceSpot(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	var maxPrices []string
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunSpotInstances, func(e *amzec2.EC2, ctx context.ProviderCallContext, ri *amzec2.RunInstances, maxPrice string, c environs.StatusCallbackFunc) (*amzec2.RunInstancesResp, error) {
+		maxPrices = append(maxPrices, maxPrice)
+		return realRunInstances(e, ctx, ri, c)
+	})
+
+	params := environs.StartInstanceParams{
+		ControllerUUID: t.ControllerUUID,
+		StatusCallback: fakeCallback,
+		Constraints:    constraints.MustParse("spot=true spot-max-price=0.05"),
+	}
+	_, err := testing.StartInstanceWithParams(env, t.callCtx, "1", params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(maxPrices, jc.DeepEquals, []string{"0.05"})
+
+	// Instances without the spot constraint are started on demand.
+	params.Constraints = constraints.MustParse("spot=false spot-max-price=0.05")
+	_, err = testing.StartInstanceWithParams(env, t.callCtx, "2", params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(maxPrices, gc.HasLen, 1)
+}
+
+func (t *localServerSuite) TestStartInstanceSpotNoCapacity(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+
+	t.PatchValue(ec2.RunSpotInstances, func(e *amzec2.EC2, ctx context.ProviderCallContext, ri *amzec2.RunInstances, maxPrice string, c environs.StatusCallbackFunc) (*amzec2.RunInstancesResp, error) {
+		return nil, ec2.NewSpotCapacityError(ri.AvailZone, "There is no Spot capacity available that matches your request.")
+	})
+
+	params := environs.StartInstanceParams{
+		ControllerUUID:   t.ControllerUUID,
+		StatusCallback:   fakeCallback,
+		AvailabilityZone: "test-available",
+		Constraints:      constraints.MustParse("spot=true"),
+	}
+	_, err := testing.StartInstanceWithParams(env, t.callCtx, "1", params)
+	// Lack of spot capacity is specific to the zone, so the
+	// provisioner will try the next one.
+	c.Assert(err, gc.Not(jc.Satisfies), environs.IsAvailabilityZoneIndependent)
+	c.Assert(err, gc.ErrorMatches, `no spot capacity in Availability Zone "test-available": .*`)
+}
+
+// fakeGroupEgress keeps the all-protocol egress rules of security
+// groups in place of the EC2 query API, which the test server doesn't
+// implement. New groups allow all outbound traffic, as in AWS.
+type fakeGroupEgress struct {
+	vpcID  string
+	groups map[string]set.Strings
+}
+
+func (t *localServerSuite) patchGroupEgress(vpcID string) *fakeGroupEgress {
+	fake := &fakeGroupEgress{vpcID: vpcID, groups: make(map[string]set.Strings)}
+	t.PatchValue(ec2.DescribeGroupEgress, func(e *amzec2.EC2, groupId string) (*ec2.GroupEgress, error) {
+		return &ec2.GroupEgress{
+			VPCID: fake.vpcID,
+			Perms: []ec2.EgressPerm{{Protocol: "-1", CIDRs: fake.cidrs(groupId).SortedValues()}},
+		}, nil
+	})
+	t.PatchValue(ec2.ChangeGroupEgress, func(e *amzec2.EC2, action, groupId string, perms []ec2.EgressPerm) error {
+		cidrs := fake.cidrs(groupId)
+		for _, perm := range perms {
+			for _, cidr := range perm.CIDRs {
+				if action == "AuthorizeSecurityGroupEgress" {
+					cidrs.Add(cidr)
+				} else {
+					cidrs.Remove(cidr)
+				}
+			}
+		}
+		return nil
+	})
+	return fake
+}
+
+func (f *fakeGroupEgress) cidrs(groupId string) set.Strings {
+	cidrs, ok := f.groups[groupId]
+	if !ok {
+		cidrs = set.NewStrings("0.0.0.0/0")
+		f.groups[groupId] = cidrs
+	}
+	return cidrs
+}
+
+func (t *localServerSuite) TestInstanceEgressCIDRs(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	fake := t.patchGroupEgress("vpc-1")
+	inst, _ := testing.AssertStartInstance(c, env, t.callCtx, t.ControllerUUID, "1")
+	fwInst, ok := inst.(instances.InstanceEgressFirewaller)
+	c.Assert(ok, jc.IsTrue)
+
+	cidrs, err := fwInst.EgressCIDRs(t.callCtx, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+
+	err = fwInst.SetEgressCIDRs(t.callCtx, "1", []string{"192.168.1.1/32", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs, err = fwInst.EgressCIDRs(t.callCtx, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/24", "192.168.1.1/32"})
+
+	// The model group no longer allows all outbound traffic,
+	// as it would override the machine group's restriction.
+	groups, err := ec2.InstanceSecurityGroups(env, t.callCtx, []instance.Id{inst.Id()})
+	c.Assert(err, jc.ErrorIsNil)
+	var modelGroupId string
+	for _, g := range groups {
+		if g.Name == "juju-"+env.Config().UUID() {
+			modelGroupId = g.Id
+		}
+	}
+	c.Assert(modelGroupId, gc.Not(gc.Equals), "")
+	c.Assert(fake.cidrs(modelGroupId).IsEmpty(), jc.IsTrue)
+
+	// Lifting the restriction restores the default rule.
+	err = fwInst.SetEgressCIDRs(t.callCtx, "1", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs, err = fwInst.EgressCIDRs(t.callCtx, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
+
+func (t *localServerSuite) TestInstanceEgressCIDRsEC2Classic(c *gc.C) {
+	env := t.prepareAndBootstrap(c)
+	t.patchGroupEgress("")
+	inst, _ := testing.AssertStartInstance(c, env, t.callCtx, t.ControllerUUID, "1")
+	fwInst := inst.(instances.InstanceEgressFirewaller)
+
+	err := fwInst.SetEgressCIDRs(t.callCtx, "1", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	cidrs, err := fwInst.EgressCIDRs(t.callCtx, "1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
+
 func (t *localServerSuite) TestStartInstanceAvailZone(c *gc.C) {
 	inst, err := t.testStartInstanceAvailZone(c, "test-available")
 	c.Assert(err, jc.ErrorIsNil)
@@ -34,6 +34,12 @@ func (environProvider) Version() int {
 	return 0
 }
 
+// SupportsInstanceEgress is part of the EgressFirewallingProvider interface.
+// Egress rules are added to the VPC security groups of the instances.
+func (environProvider) SupportsInstanceEgress() bool {
+	return true
+}
+
 // Open is specified in the EnvironProvider interface.
 func (p environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
 	logger.Infof("opening model %q", args.Config.Name())
@@ -0,0 +1,83 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+)
+
+// queryAPIVersion is the EC2 API version used for the requests made
+// by ec2Query.
+const queryAPIVersion = "2016-11-15"
+
+// ec2Query makes a signed request to the EC2 query API, decoding the
+// response into resp. It is used for the few actions Juju needs that
+// the amz.v3 client does not provide. Errors returned by EC2 are
+// *ec2.Error, as with the client's own requests.
+func ec2Query(e *ec2.EC2, action string, params map[string]string, resp interface{}) error {
+	req, err := http.NewRequest("GET", e.Region.EC2Endpoint, nil)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	query := req.URL.Query()
+	query.Add("Action", action)
+	query.Add("Version", queryAPIVersion)
+	for name, value := range params {
+		query.Add(name, value)
+	}
+	now := time.Now().In(time.UTC)
+	query.Add("Timestamp", now.Format(time.RFC3339))
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("x-amz-date", now.Format(aws.ISO8601BasicFormat))
+	if err := e.Sign(req, e.Auth); err != nil {
+		return errors.Trace(err)
+	}
+
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		var xmlErrors struct {
+			RequestId string      `xml:"RequestID"`
+			Errors    []ec2.Error `xml:"Errors>Error"`
+		}
+		xml.NewDecoder(r.Body).Decode(&xmlErrors)
+		var ec2err ec2.Error
+		if len(xmlErrors.Errors) > 0 {
+			ec2err = xmlErrors.Errors[0]
+		}
+		ec2err.RequestId = xmlErrors.RequestId
+		ec2err.StatusCode = r.StatusCode
+		if ec2err.Message == "" {
+			ec2err.Message = r.Status
+		}
+		return &ec2err
+	}
+	return errors.Trace(xml.NewDecoder(r.Body).Decode(resp))
+}
+
+// addQueryList adds the values to the query parameters as a list with
+// the given prefix, e.g. "InstanceId.1", "InstanceId.2".
+func addQueryList(params map[string]string, prefix string, values []string) {
+	for i, value := range values {
+		params[prefix+"."+strconv.Itoa(i+1)] = value
+	}
+}
+
+// addQueryFilter adds a filter on the given name to the query
+// parameters, at the given (1-based) index.
+func addQueryFilter(params map[string]string, index int, name string, values ...string) {
+	prefix := "Filter." + strconv.Itoa(index)
+	params[prefix+".Name"] = name
+	addQueryList(params, prefix+".Value", values)
+}
@@ -0,0 +1,296 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+)
+
+const (
+	// spotRequestOneTime is the type of spot request made for
+	// instances; the request is closed once fulfilled, so an
+	// interrupted instance is not replaced behind Juju's back.
+	spotRequestOneTime = "one-time"
+
+	spotRequestStateOpen   = "open"
+	spotRequestStateActive = "active"
+)
+
+var _ environs.InstanceInterruptionChecker = (*environ)(nil)
+
+var (
+	runSpotInstances     = _runSpotInstances
+	requestSpotInstances = _requestSpotInstances
+	describeSpotRequests = _describeSpotRequests
+	cancelSpotRequests   = _cancelSpotRequests
+
+	// spotRequestAttempt is used to wait for a spot request to be
+	// fulfilled.
+	spotRequestAttempt = utils.AttemptStrategy{
+		Total: 5 * time.Minute,
+		Delay: 5 * time.Second,
+	}
+)
+
+// spotRequestsResp is the response to the RequestSpotInstances and
+// DescribeSpotInstanceRequests EC2 actions.
+type spotRequestsResp struct {
+	RequestId          string              `xml:"requestId"`
+	SpotRequestResults []spotRequestResult `xml:"spotInstanceRequestSet>item"`
+}
+
+// spotRequestResult describes a spot instance request.
+type spotRequestResult struct {
+	SpotRequestId string            `xml:"spotInstanceRequestId"`
+	State         string            `xml:"state"`
+	Status        spotRequestStatus `xml:"status"`
+	InstanceId    string            `xml:"instanceId"`
+}
+
+// spotRequestStatus is the status of a spot instance request.
+type spotRequestStatus struct {
+	Code    string `xml:"code"`
+	Message string `xml:"message"`
+}
+
+// _requestSpotInstances makes a one-time request for a spot instance
+// with the launch specification of the given RunInstances.
+func _requestSpotInstances(e *ec2.EC2, ri *ec2.RunInstances, maxPrice string) (*spotRequestsResp, error) {
+	params := map[string]string{
+		"InstanceCount":                    "1",
+		"Type":                             spotRequestOneTime,
+		"LaunchSpecification.ImageId":      ri.ImageId,
+		"LaunchSpecification.InstanceType": ri.InstanceType,
+	}
+	if maxPrice != "" {
+		params["SpotPrice"] = maxPrice
+	}
+	const spec = "LaunchSpecification."
+	i, j := 1, 1
+	for _, g := range ri.SecurityGroups {
+		if g.Id != "" {
+			params[spec+"SecurityGroupId."+strconv.Itoa(i)] = g.Id
+			i++
+		} else {
+			params[spec+"SecurityGroup."+strconv.Itoa(j)] = g.Name
+			j++
+		}
+	}
+	if ri.UserData != nil {
+		params[spec+"UserData"] = base64.StdEncoding.EncodeToString(ri.UserData)
+	}
+	if ri.AvailZone != "" {
+		params[spec+"Placement.AvailabilityZone"] = ri.AvailZone
+	}
+	if ri.SubnetId != "" {
+		params[spec+"SubnetId"] = ri.SubnetId
+	}
+	if ri.IAMInstanceProfile != "" {
+		params[spec+"IamInstanceProfile.Name"] = ri.IAMInstanceProfile
+	}
+	for n, b := range ri.BlockDeviceMappings {
+		prefix := spec + "BlockDeviceMapping." + strconv.Itoa(n+1)
+		if b.DeviceName != "" {
+			params[prefix+".DeviceName"] = b.DeviceName
+		}
+		if b.VirtualName != "" {
+			params[prefix+".VirtualName"] = b.VirtualName
+		}
+		if b.VolumeType != "" {
+			params[prefix+".Ebs.VolumeType"] = b.VolumeType
+		}
+		if b.VolumeSize > 0 {
+			params[prefix+".Ebs.VolumeSize"] = strconv.FormatInt(b.VolumeSize, 10)
+		}
+		if b.IOPS > 0 {
+			params[prefix+".Ebs.Iops"] = strconv.FormatInt(b.IOPS, 10)
+		}
+		if b.DeleteOnTermination {
+			params[prefix+".Ebs.DeleteOnTermination"] = "true"
+		}
+	}
+	resp := &spotRequestsResp{}
+	if err := ec2Query(e, "RequestSpotInstances", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// _describeSpotRequests describes the spot requests with the given
+// IDs, or those for the given instances. At least one of requestIds
+// and instanceIds must be non-empty.
+func _describeSpotRequests(e *ec2.EC2, requestIds, instanceIds []string) (*spotRequestsResp, error) {
+	params := make(map[string]string)
+	addQueryList(params, "SpotInstanceRequestId", requestIds)
+	if len(instanceIds) > 0 {
+		addQueryFilter(params, 1, "instance-id", instanceIds...)
+	}
+	resp := &spotRequestsResp{}
+	if err := ec2Query(e, "DescribeSpotInstanceRequests", params, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// _cancelSpotRequests cancels the spot requests with the given IDs.
+// Instances already started for the requests are left running.
+func _cancelSpotRequests(e *ec2.EC2, requestIds []string) error {
+	params := make(map[string]string)
+	addQueryList(params, "SpotInstanceRequestId", requestIds)
+	var resp struct {
+		RequestId string `xml:"requestId"`
+	}
+	return ec2Query(e, "CancelSpotInstanceRequests", params, &resp)
+}
+
+// spotCapacityError is returned when a spot request cannot be fulfilled
+// for lack of spot capacity in the requested availability zone.
+type spotCapacityError struct {
+	zone    string
+	message string
+}
+
+// Error is part of the error interface.
+func (e *spotCapacityError) Error() string {
+	return fmt.Sprintf("no spot capacity in Availability Zone %q: %s", e.zone, e.message)
+}
+
+// _runSpotInstances requests a one-time spot instance with the same
+// launch specification as the given RunInstances, and waits for the
+// request to be fulfilled. If maxPrice is empty, the on-demand price of
+// the instance type is the limit.
+func _runSpotInstances(e *ec2.EC2, ctx context.ProviderCallContext, ri *ec2.RunInstances, maxPrice string, c environs.StatusCallbackFunc) (*ec2.RunInstancesResp, error) {
+	c(status.Allocating, "Requesting spot instance", nil)
+	resp, err := requestSpotInstances(e, ri, maxPrice)
+	if err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
+	}
+	if len(resp.SpotRequestResults) != 1 {
+		return nil, errors.Errorf("expected 1 spot request, got %d", len(resp.SpotRequestResults))
+	}
+	requestId := resp.SpotRequestResults[0].SpotRequestId
+
+	instId, err := waitSpotRequest(e, ctx, requestId, ri.AvailZone, c)
+	if err != nil {
+		// An open request may still be fulfilled later, leaving an
+		// instance unknown to Juju.
+		if cancelErr := cancelSpotRequests(e, []string{requestId}); cancelErr != nil {
+			logger.Warningf("cannot cancel spot request %q: %v", requestId, cancelErr)
+		}
+		return nil, errors.Trace(err)
+	}
+
+	var instResp *ec2.InstancesResp
+	for a := shortAttempt.Start(); a.Next(); {
+		instResp, err = e.Instances([]string{instId}, nil)
+		if err == nil || ec2ErrCode(err) != "InvalidInstanceID.NotFound" {
+			break
+		}
+	}
+	if err != nil {
+		return nil, maybeConvertCredentialError(err, ctx)
+	}
+	if len(instResp.Reservations) != 1 || len(instResp.Reservations[0].Instances) != 1 {
+		return nil, errors.Errorf("cannot find instance %q of spot request %q", instId, requestId)
+	}
+	return &ec2.RunInstancesResp{
+		Instances: instResp.Reservations[0].Instances,
+	}, nil
+}
+
+// waitSpotRequest waits for the spot request to be fulfilled, returning
+// the ID of the instance started for it.
+func waitSpotRequest(e *ec2.EC2, ctx context.ProviderCallContext, requestId, zone string, c environs.StatusCallbackFunc) (string, error) {
+	var lastStatus spotRequestStatus
+	for a := spotRequestAttempt.Start(); a.Next(); {
+		resp, err := describeSpotRequests(e, []string{requestId}, nil)
+		if ec2ErrCode(err) == "InvalidSpotInstanceRequestID.NotFound" {
+			// The request is not visible yet.
+			continue
+		} else if err != nil {
+			return "", maybeConvertCredentialError(err, ctx)
+		}
+		if len(resp.SpotRequestResults) != 1 {
+			return "", errors.Errorf("expected 1 spot request, got %d", len(resp.SpotRequestResults))
+		}
+		result := resp.SpotRequestResults[0]
+		lastStatus = result.Status
+		switch result.State {
+		case spotRequestStateActive:
+			if result.InstanceId != "" {
+				return result.InstanceId, nil
+			}
+		case spotRequestStateOpen:
+			switch result.Status.Code {
+			case "capacity-not-available", "capacity-oversubscribed":
+				return "", &spotCapacityError{zone: zone, message: result.Status.Message}
+			case "price-too-low":
+				return "", errors.Errorf("spot request %q: %s", requestId, result.Status.Message)
+			}
+		default:
+			// closed, cancelled or failed.
+			return "", errors.Errorf(
+				"spot request %q %s: %s", requestId, result.State, result.Status.Message,
+			)
+		}
+		c(status.Allocating, fmt.Sprintf("Waiting for spot request: %s", result.Status.Code), nil)
+	}
+	return "", errors.Errorf(
+		"timed out waiting for spot request %q (last status %q)", requestId, lastStatus.Code,
+	)
+}
+
+// isSpotInterruption reports whether the spot request status code
+// indicates that the cloud has interrupted the request's instance, or
+// has given notice that it will. Interruptions by the user are not
+// included.
+func isSpotInterruption(code string) bool {
+	switch {
+	case strings.HasSuffix(code, "-by-user"):
+		return false
+	case strings.HasPrefix(code, "marked-for-"),
+		strings.HasPrefix(code, "instance-terminated-"),
+		strings.HasPrefix(code, "instance-stopped-"):
+		return true
+	}
+	return false
+}
+
+// InterruptedInstances is part of the environs.InstanceInterruptionChecker
+// interface. Spot instances are interrupted when the spot price exceeds
+// their maximum price, or when EC2 needs the capacity back.
+func (e *environ) InterruptedInstances(ctx context.ProviderCallContext, ids ...instance.Id) ([]instance.Id, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	idStrings := make([]string, len(ids))
+	for i, id := range ids {
+		idStrings[i] = string(id)
+	}
+	resp, err := describeSpotRequests(e.ec2, nil, idStrings)
+	if err != nil {
+		return nil, errors.Annotate(maybeConvertCredentialError(err, ctx), "listing spot requests")
+	}
+	var interrupted []instance.Id
+	for _, result := range resp.SpotRequestResults {
+		if result.InstanceId != "" && isSpotInterruption(result.Status.Code) {
+			logger.Infof("spot instance %q interrupted: %s", result.InstanceId, result.Status.Message)
+			interrupted = append(interrupted, instance.Id(result.InstanceId))
+		}
+	}
+	return interrupted, nil
+}
@@ -69,6 +69,8 @@ type gceConnection interface {
 	InstanceDisks(zone, instanceId string) ([]*google.AttachedDisk, error)
 	// ListMachineTypes returns a list of machines available in the project and zone provided.
 	ListMachineTypes(zone string) ([]google.MachineType, error)
+	// RegionQuotas returns the quotas of the given region.
+	RegionQuotas(region string) ([]google.Quota, error)
 }
 
 type environ struct {
@@ -204,6 +204,7 @@ func (env *environ) newRawInstance(ctx context.ProviderCallContext, args environ
 		Metadata:          metadata,
 		Tags:              tags,
 		AvailabilityZone:  args.AvailabilityZone,
+		Preemptible:       args.Constraints.HasSpot(),
 		// Network is omitted (left empty).
 	})
 	if err != nil {
@@ -259,10 +260,7 @@ func getMetadata(args environs.StartInstanceParams, os jujuos.OSType) (map[strin
 // disk with characteristics determined by the provides args and
 // constraints.
 func getDisks(spec *instances.InstanceSpec, cons constraints.Value, ser, eUUID string, imageURLBase string) ([]google.DiskSpec, error) {
-	size := common.MinRootDiskSizeGiB(ser)
-	if cons.RootDisk != nil && *cons.RootDisk > size {
-		size = common.MiBToGiB(*cons.RootDisk)
-	}
+	size := rootDiskSizeGB(cons, ser)
 	if imageURLBase == "" {
 		return nil, errors.NotValidf("imageURLBase must be set")
 	}
@@ -282,6 +280,16 @@ func getDisks(spec *instances.InstanceSpec, cons constraints.Value, ser, eUUID s
 	return []google.DiskSpec{dSpec}, nil
 }
 
+// rootDiskSizeGB returns the size of the root disk to create for an
+// instance with the given constraints and series.
+func rootDiskSizeGB(cons constraints.Value, ser string) uint64 {
+	size := common.MinRootDiskSizeGiB(ser)
+	if cons.RootDisk != nil && *cons.RootDisk > size {
+		size = common.MiBToGiB(*cons.RootDisk)
+	}
+	return size
+}
+
 // getHardwareCharacteristics compiles hardware-related details about
 // the given instance and relative to the provided spec and returns it.
 func (env *environ) getHardwareCharacteristics(spec *instances.InstanceSpec, inst *environInstance) *instance.HardwareCharacteristics {
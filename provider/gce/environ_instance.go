@@ -186,3 +186,33 @@ func checkInstanceType(cons constraints.Value) bool {
 	}
 	return false
 }
+
+var _ environs.InstanceInterruptionChecker = (*environ)(nil)
+
+// InterruptedInstances is part of the environs.InstanceInterruptionChecker
+// interface. GCE stops preemptible instances when it needs the capacity
+// back, and always after 24 hours.
+func (env *environ) InterruptedInstances(ctx context.ProviderCallContext, ids ...instance.Id) ([]instance.Id, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	gceInstances, err := env.gceInstances(ctx,
+		google.StatusStopping, google.StatusStopped, google.StatusTerminated,
+	)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var interrupted []instance.Id
+	for _, inst := range gceInstances {
+		if !inst.Preempted() {
+			continue
+		}
+		for _, id := range ids {
+			if id == instance.Id(inst.ID) {
+				interrupted = append(interrupted, id)
+				break
+			}
+		}
+	}
+	return interrupted, nil
+}
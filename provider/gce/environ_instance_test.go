@@ -242,3 +242,23 @@ func (s *environInstSuite) TestAdoptResourcesInvalidCredentialError(c *gc.C) {
 	c.Check(err, gc.NotNil)
 	c.Assert(s.InvalidatedCredentials, jc.IsTrue)
 }
+
+func (s *environInstSuite) TestInterruptedInstances(c *gc.C) {
+	preempted := google.NewInstance(google.InstanceSummary{
+		ID:          "spam",
+		Status:      google.StatusTerminated,
+		Preemptible: true,
+	}, nil)
+	stopped := google.NewInstance(google.InstanceSummary{
+		ID:     "ham",
+		Status: google.StatusTerminated,
+	}, nil)
+	s.FakeConn.Insts = []google.Instance{*preempted, *stopped}
+
+	ids, err := s.Env.InterruptedInstances(s.CallCtx, "spam", "ham", "eggs")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ids, jc.DeepEquals, []instance.Id{"spam"})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].Statuses, jc.DeepEquals, []string{google.StatusStopping, google.StatusStopped, google.StatusTerminated})
+}
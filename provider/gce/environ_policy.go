@@ -34,6 +34,8 @@ func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args envir
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.VirtType,
+	// Preemptible instances have a fixed price.
+	constraints.SpotMaxPrice,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -0,0 +1,73 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+var _ environs.QuotaChecker = (*environ)(nil)
+
+// quotaNames maps the GCE quota metrics juju knows about to the names
+// reported to users. Other metrics are reported as they are.
+var quotaNames = map[string]string{
+	google.QuotaCPUs:            "vCPUs",
+	google.QuotaPreemptibleCPUs: "preemptible vCPUs",
+	google.QuotaInstances:       "instances",
+	google.QuotaDisksTotalGB:    "disk GB",
+	google.QuotaAddresses:       "IP addresses",
+}
+
+func quotaName(metric string) string {
+	if name, ok := quotaNames[metric]; ok {
+		return name
+	}
+	return metric
+}
+
+// Quotas is part of the environs.QuotaChecker interface.
+func (env *environ) Quotas(ctx context.ProviderCallContext) ([]environs.Quota, error) {
+	quotas, err := env.gce.RegionQuotas(env.cloud.Region)
+	if err != nil {
+		return nil, google.HandleCredentialError(errors.Trace(err), ctx)
+	}
+	result := make([]environs.Quota, len(quotas))
+	for i, quota := range quotas {
+		result[i] = environs.Quota{
+			Name:  quotaName(quota.Metric),
+			Limit: quota.Limit,
+			Usage: quota.Usage,
+		}
+	}
+	return result, nil
+}
+
+// CheckInstanceQuotas is part of the environs.QuotaChecker interface.
+// It checks the region has room for the vCPUs and root disk of the
+// machine type StartInstance would choose, and for one more instance.
+func (env *environ) CheckInstanceQuotas(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	spec, err := buildInstanceSpec(env, args)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	quotas, err := env.Quotas(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	cpus := google.QuotaCPUs
+	if args.Constraints.HasSpot() {
+		cpus = google.QuotaPreemptibleCPUs
+	}
+	required := map[string]float64{
+		quotaName(google.QuotaInstances):    1,
+		quotaName(cpus):                     float64(spec.InstanceType.CpuCores),
+		quotaName(google.QuotaDisksTotalGB): float64(rootDiskSizeGB(args.Constraints, args.Tools.OneSeries())),
+	}
+	return environs.CheckQuotas(quotas, required)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+)
+
+type environQuotasSuite struct {
+	gce.BaseSuite
+}
+
+var _ = gc.Suite(&environQuotasSuite{})
+
+func (s *environQuotasSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.FakeEnviron.Spec = &instances.InstanceSpec{InstanceType: s.InstanceType}
+	s.FakeConn.Quotas = []google.Quota{
+		{Metric: "CPUS", Limit: 24, Usage: 23},
+		{Metric: "PREEMPTIBLE_CPUS", Limit: 24, Usage: 24},
+		{Metric: "INSTANCES", Limit: 100, Usage: 4},
+		{Metric: "DISKS_TOTAL_GB", Limit: 4096, Usage: 80},
+		{Metric: "SSD_TOTAL_GB", Limit: 500, Usage: 0},
+	}
+}
+
+func (s *environQuotasSuite) TestQuotas(c *gc.C) {
+	quotas, err := s.Env.Quotas(s.CallCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(quotas, jc.DeepEquals, []environs.Quota{
+		{Name: "vCPUs", Limit: 24, Usage: 23},
+		{Name: "preemptible vCPUs", Limit: 24, Usage: 24},
+		{Name: "instances", Limit: 100, Usage: 4},
+		{Name: "disk GB", Limit: 4096, Usage: 80},
+		{Name: "SSD_TOTAL_GB", Limit: 500, Usage: 0},
+	})
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RegionQuotas")
+	c.Check(s.FakeConn.Calls[0].Region, gc.Equals, "us-east1")
+}
+
+func (s *environQuotasSuite) TestCheckInstanceQuotas(c *gc.C) {
+	err := s.Env.CheckInstanceQuotas(s.CallCtx, s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *environQuotasSuite) TestCheckInstanceQuotasExceeded(c *gc.C) {
+	s.FakeConn.Quotas[0].Usage = 24
+
+	err := s.Env.CheckInstanceQuotas(s.CallCtx, s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, "quota exceeded: vCPUs")
+	c.Assert(environs.IsQuotaExceeded(err), jc.IsTrue)
+}
+
+func (s *environQuotasSuite) TestCheckInstanceQuotasSpot(c *gc.C) {
+	s.StartInstArgs.Constraints = constraints.MustParse("spot=true")
+
+	err := s.Env.CheckInstanceQuotas(s.CallCtx, s.StartInstArgs)
+	c.Assert(err, gc.ErrorMatches, "quota exceeded: preemptible vCPUs")
+}
@@ -104,6 +104,10 @@ type rawConnectionWrapper interface {
 
 	// ListNetworks returns a list of Networks available in the given project.
 	ListNetworks(projectID string) ([]*compute.Network, error)
+
+	// GetRegion returns the given region of the project, including the
+	// region's quotas.
+	GetRegion(projectID, region string) (*compute.Region, error)
 }
 
 // TODO(ericsnow) Add specific error types for common failures
@@ -0,0 +1,23 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import "github.com/juju/errors"
+
+// RegionQuotas returns the quotas of the given region.
+func (gce *Connection) RegionQuotas(region string) ([]Quota, error) {
+	rawRegion, err := gce.raw.GetRegion(gce.projectID, region)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	res := make([]Quota, len(rawRegion.Quotas))
+	for i, quota := range rawRegion.Quotas {
+		res[i] = Quota{
+			Metric: quota.Metric,
+			Limit:  quota.Limit,
+			Usage:  quota.Usage,
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"google.golang.org/api/compute/v1"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/gce/google"
+)
+
+func (s *connSuite) TestConnectionRegionQuotas(c *gc.C) {
+	s.FakeConn.Region = &compute.Region{
+		Name: "a",
+		Quotas: []*compute.Quota{
+			{Metric: "CPUS", Limit: 24, Usage: 8},
+			{Metric: "INSTANCES", Limit: 100, Usage: 4},
+		},
+	}
+
+	quotas, err := s.Conn.RegionQuotas("a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(quotas, jc.DeepEquals, []google.Quota{
+		{Metric: "CPUS", Limit: 24, Usage: 8},
+		{Metric: "INSTANCES", Limit: 100, Usage: 4},
+	})
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetRegion")
+	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].Region, gc.Equals, "a")
+}
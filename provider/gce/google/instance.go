@@ -52,6 +52,11 @@ type InstanceSpec struct {
 	// AvailabilityZone holds the name of the availability zone in which
 	// to create the instance.
 	AvailabilityZone string
+
+	// Preemptible indicates whether the instance should be preemptible,
+	// meaning GCE may stop it at any time, and will stop it after 24
+	// hours.
+	Preemptible bool
 }
 
 func (is InstanceSpec) raw() *compute.Instance {
@@ -61,10 +66,25 @@ func (is InstanceSpec) raw() *compute.Instance {
 		NetworkInterfaces: is.networkInterfaces(),
 		Metadata:          packMetadata(is.Metadata),
 		Tags:              &compute.Tags{Items: is.Tags},
+		Scheduling:        is.scheduling(),
 		// MachineType is set in the addInstance call.
 	}
 }
 
+func (is InstanceSpec) scheduling() *compute.Scheduling {
+	if !is.Preemptible {
+		return nil
+	}
+	// Preemptible instances cannot be restarted automatically, nor
+	// migrated for host maintenance.
+	automaticRestart := false
+	return &compute.Scheduling{
+		Preemptible:       true,
+		AutomaticRestart:  &automaticRestart,
+		OnHostMaintenance: "TERMINATE",
+	}
+}
+
 // Summary builds an InstanceSummary based on the spec and returns it.
 func (is InstanceSpec) Summary() InstanceSummary {
 	raw := is.raw()
@@ -111,6 +131,8 @@ type InstanceSummary struct {
 	// NetworkInterfaces are the network connections associated with
 	// the instance.
 	NetworkInterfaces []*compute.NetworkInterface
+	// Preemptible indicates whether the instance is preemptible.
+	Preemptible bool
 }
 
 func newInstanceSummary(raw *compute.Instance) InstanceSummary {
@@ -121,6 +143,7 @@ func newInstanceSummary(raw *compute.Instance) InstanceSummary {
 		Metadata:          unpackMetadata(raw.Metadata),
 		Addresses:         extractAddresses(raw.NetworkInterfaces...),
 		NetworkInterfaces: raw.NetworkInterfaces,
+		Preemptible:       raw.Scheduling != nil && raw.Scheduling.Preemptible,
 	}
 }
 
@@ -175,6 +198,20 @@ func (gi Instance) Status() string {
 	return gi.InstanceSummary.Status
 }
 
+// Preempted reports whether GCE has preempted the instance, or is in
+// the process of doing so. Preempted instances are stopped rather than
+// deleted.
+func (gi Instance) Preempted() bool {
+	if !gi.InstanceSummary.Preemptible {
+		return false
+	}
+	switch gi.InstanceSummary.Status {
+	case StatusStopping, StatusStopped, StatusTerminated:
+		return true
+	}
+	return false
+}
+
 // Addresses identifies information about the network addresses
 // associated with the instance and returns it.
 func (gi Instance) Addresses() network.ProviderAddresses {
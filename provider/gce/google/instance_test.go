@@ -62,6 +62,29 @@ func (s *instanceSuite) TestInstanceStatusDown(c *gc.C) {
 	c.Check(status, gc.Equals, google.StatusDown)
 }
 
+func (s *instanceSuite) TestNewInstancePreemptible(c *gc.C) {
+	raw := s.RawInstanceFull
+	raw.Status = google.StatusTerminated
+	raw.Scheduling = &compute.Scheduling{Preemptible: true}
+	inst := google.NewInstanceRaw(&raw, nil)
+
+	c.Check(inst.Preemptible, jc.IsTrue)
+	c.Check(inst.Preempted(), jc.IsTrue)
+}
+
+func (s *instanceSuite) TestInstancePreempted(c *gc.C) {
+	c.Check(s.Instance.Preempted(), jc.IsFalse)
+
+	s.Instance.InstanceSummary.Status = google.StatusStopping
+	c.Check(s.Instance.Preempted(), jc.IsFalse)
+
+	s.Instance.InstanceSummary.Preemptible = true
+	c.Check(s.Instance.Preempted(), jc.IsTrue)
+
+	s.Instance.InstanceSummary.Status = google.StatusRunning
+	c.Check(s.Instance.Preempted(), jc.IsFalse)
+}
+
 func (s *instanceSuite) TestInstanceAddresses(c *gc.C) {
 	addresses := s.Instance.Addresses()
 
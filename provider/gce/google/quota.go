@@ -0,0 +1,23 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+// These are the GCE region quota metrics juju checks before starting
+// an instance.
+const (
+	QuotaCPUs            = "CPUS"
+	QuotaPreemptibleCPUs = "PREEMPTIBLE_CPUS"
+	QuotaInstances       = "INSTANCES"
+	QuotaDisksTotalGB    = "DISKS_TOTAL_GB"
+	QuotaAddresses       = "IN_USE_ADDRESSES"
+)
+
+// Quota represents a gce region quota.
+// this is basically a copy of compute.Quota put here to
+// satisfy an extra layer of abstraction.
+type Quota struct {
+	Metric string
+	Limit  float64
+	Usage  float64
+}
@@ -452,3 +452,9 @@ func (rc *rawConn) ListNetworks(projectID string) ([]*compute.Network, error) {
 	}
 	return results, nil
 }
+
+func (rc *rawConn) GetRegion(projectID, region string) (*compute.Region, error) {
+	call := rc.Regions.Get(projectID, region)
+	result, err := call.Do()
+	return result, errors.Trace(convertRawAPIError(err))
+}
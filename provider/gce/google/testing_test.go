@@ -185,6 +185,7 @@ type fakeConn struct {
 	AttachedDisks []*compute.AttachedDisk
 	Networks      []*compute.Network
 	Subnetworks   []*compute.Subnetwork
+	Region        *compute.Region
 }
 
 func (rc *fakeConn) GetProject(projectID string) (*compute.Project, error) {
@@ -540,3 +541,18 @@ func (rc *fakeConn) ListSubnetworks(projectID, region string) ([]*compute.Subnet
 	}
 	return rc.Subnetworks, nil
 }
+
+func (rc *fakeConn) GetRegion(projectID, region string) (*compute.Region, error) {
+	call := fakeCall{
+		FuncName:  "GetRegion",
+		ProjectID: projectID,
+		Region:    region,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Region, err
+}
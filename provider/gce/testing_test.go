@@ -519,6 +519,8 @@ type fakeConn struct {
 	AttachedDisk  *google.AttachedDisk
 	AttachedDisks []*google.AttachedDisk
 
+	Quotas []google.Quota
+
 	Err        error
 	FailOnCall int
 }
@@ -731,4 +733,12 @@ func (fc *fakeConn) ListMachineTypes(zone string) ([]google.MachineType, error)
 	}, nil
 }
 
+func (fc *fakeConn) RegionQuotas(region string) ([]google.Quota, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RegionQuotas",
+		Region:   region,
+	})
+	return fc.Quotas, fc.err()
+}
+
 var InvalidCredentialError = &url.Error{"Get", "testbad.com", errors.New("400 Bad Request")}
@@ -0,0 +1,117 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+)
+
+const (
+	cfgHosts         = "hosts"
+	cfgStoragePool   = "storage-pool"
+	cfgNetworkBridge = "network-bridge"
+
+	defaultStoragePool   = "default"
+	defaultNetworkBridge = "virbr0"
+)
+
+var configFields = schema.Fields{
+	cfgHosts:         schema.String(),
+	cfgStoragePool:   schema.String(),
+	cfgNetworkBridge: schema.String(),
+}
+
+var configDefaultFields = schema.Defaults{
+	cfgHosts:         "",
+	cfgStoragePool:   defaultStoragePool,
+	cfgNetworkBridge: defaultNetworkBridge,
+}
+
+var configImmutableFields = []string{
+	cfgStoragePool,
+}
+
+func validateConfig(cfg *config.Config, old *environConfig) (*environConfig, error) {
+	// Check sanity of juju-level fields.
+	var oldCfg *config.Config
+	if old != nil {
+		oldCfg = old.Config
+	}
+	if err := config.Validate(cfg, oldCfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	newAttrs, err := cfg.ValidateUnknownAttrs(configFields, configDefaultFields)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, field := range []string{cfgStoragePool, cfgNetworkBridge} {
+		if newAttrs[field] == "" {
+			return nil, errors.Errorf("%s: must not be empty", field)
+		}
+	}
+
+	// If an old config was supplied, check any immutable fields have not changed.
+	if old != nil {
+		for _, field := range configImmutableFields {
+			if old.attrs[field] != newAttrs[field] {
+				return nil, errors.Errorf(
+					"%s: cannot change from %v to %v",
+					field, old.attrs[field], newAttrs[field],
+				)
+			}
+		}
+	}
+
+	ecfg := &environConfig{
+		attrs: newAttrs,
+	}
+	for _, uri := range ecfg.hosts() {
+		if err := validateURI(uri); err != nil {
+			return nil, errors.Annotatef(err, "%s", cfgHosts)
+		}
+	}
+
+	// Merge the validated provider-specific fields into the original config,
+	// to ensure the object we return is internally consistent.
+	ecfg.Config, err = cfg.Apply(newAttrs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ecfg, nil
+}
+
+type environConfig struct {
+	*config.Config
+	attrs map[string]interface{}
+}
+
+// hosts returns the libvirt URIs of any hypervisor hosts in addition to
+// the cloud endpoint.
+func (c *environConfig) hosts() []string {
+	var uris []string
+	for _, uri := range strings.Split(c.attrs[cfgHosts].(string), ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+// storagePool returns the name of the libvirt storage pool that holds
+// instance disks and Juju volumes on every host.
+func (c *environConfig) storagePool() string {
+	return c.attrs[cfgStoragePool].(string)
+}
+
+// networkBridge returns the name of the host bridge that instance NICs
+// are attached to.
+func (c *environConfig) networkBridge() string {
+	return c.attrs[cfgNetworkBridge].(string)
+}
@@ -0,0 +1,69 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type configSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+func (s *configSuite) TestDefaults(c *gc.C) {
+	ecfg, err := validateConfig(newConfig(c, nil), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.hosts(), gc.HasLen, 0)
+	c.Check(ecfg.storagePool(), gc.Equals, "default")
+	c.Check(ecfg.networkBridge(), gc.Equals, "virbr0")
+}
+
+func (s *configSuite) TestHosts(c *gc.C) {
+	ecfg, err := validateConfig(newConfig(c, testing.Attrs{
+		"hosts": "qemu+ssh://root@kvm2/system, qemu+ssh://root@kvm3/system,",
+	}), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.hosts(), jc.DeepEquals, []string{
+		"qemu+ssh://root@kvm2/system",
+		"qemu+ssh://root@kvm3/system",
+	})
+}
+
+func (s *configSuite) TestInvalidHost(c *gc.C) {
+	_, err := validateConfig(newConfig(c, testing.Attrs{
+		"hosts": "qemu+ssh://root@kvm2/system,lxc:///",
+	}), nil)
+	c.Assert(err, gc.ErrorMatches, `hosts: libvirt URI "lxc:///" \(only qemu drivers are supported\) not valid`)
+}
+
+func (s *configSuite) TestEmptyStoragePool(c *gc.C) {
+	_, err := validateConfig(newConfig(c, testing.Attrs{
+		"storage-pool": "",
+	}), nil)
+	c.Assert(err, gc.ErrorMatches, "storage-pool: must not be empty")
+}
+
+func (s *configSuite) TestStoragePoolImmutable(c *gc.C) {
+	old, err := validateConfig(newConfig(c, nil), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validateConfig(newConfig(c, testing.Attrs{
+		"storage-pool": "fast",
+	}), old)
+	c.Assert(err, gc.ErrorMatches, "storage-pool: cannot change from default to fast")
+}
+
+func (s *configSuite) TestNetworkBridgeMutable(c *gc.C) {
+	old, err := validateConfig(newConfig(c, nil), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg, err := validateConfig(newConfig(c, testing.Attrs{
+		"network-bridge": "br0",
+	}), old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.networkBridge(), gc.Equals, "br0")
+}
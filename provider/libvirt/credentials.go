@@ -0,0 +1,43 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+)
+
+type environProviderCredentials struct{}
+
+const (
+	credAttrPrivateKey = "private-key"
+)
+
+// CredentialSchemas is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) CredentialSchemas() map[cloud.AuthType]cloud.CredentialSchema {
+	return map[cloud.AuthType]cloud.CredentialSchema{
+		cloud.SSHKeyAuthType: {{
+			credAttrPrivateKey, cloud.CredentialAttr{
+				Description: "SSH private key used to connect to the libvirt hosts",
+				Hidden:      true,
+				FileAttr:    "private-key-path",
+			},
+		}},
+		// The empty auth type relies on the connecting user's own SSH
+		// configuration, or on a local qemu:///system socket.
+		cloud.EmptyAuthType: {},
+	}
+}
+
+// DetectCredentials is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) DetectCredentials() (*cloud.CloudCredential, error) {
+	return nil, errors.NotFoundf("credentials")
+}
+
+// FinalizeCredential is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) FinalizeCredential(_ environs.FinalizeCredentialContext, args environs.FinalizeCredentialParams) (*cloud.Credential, error) {
+	return &args.Credential, nil
+}
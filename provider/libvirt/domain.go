@@ -0,0 +1,178 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"crypto/rand"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+)
+
+const (
+	metadataFile = "meta-data"
+	userdataFile = "user-data"
+
+	// nvramCode is the path of the UEFI firmware on arm64 hosts.
+	nvramCode = "/usr/share/AAVMF/AAVMF_CODE.fd"
+)
+
+// domainParams implements the parameters needed by kvmlibvirt.NewDomain
+// to build the XML definition of an instance.
+type domainParams struct {
+	name     string
+	arch     string
+	cpus     uint64
+	memMiB   uint64
+	disks    []kvmlibvirt.DiskInfo
+	networks []kvmlibvirt.InterfaceInfo
+}
+
+// Arch implements kvmlibvirt.domainParams.
+func (p domainParams) Arch() string {
+	return p.arch
+}
+
+// CPUs implements kvmlibvirt.domainParams.
+func (p domainParams) CPUs() uint64 {
+	return p.cpus
+}
+
+// DiskInfo implements kvmlibvirt.domainParams.
+func (p domainParams) DiskInfo() []kvmlibvirt.DiskInfo {
+	return p.disks
+}
+
+// Host implements kvmlibvirt.domainParams.
+func (p domainParams) Host() string {
+	return p.name
+}
+
+// Loader implements kvmlibvirt.domainParams.
+func (p domainParams) Loader() string {
+	return nvramCode
+}
+
+// NetworkInfo implements kvmlibvirt.domainParams.
+func (p domainParams) NetworkInfo() []kvmlibvirt.InterfaceInfo {
+	return p.networks
+}
+
+// RAM implements kvmlibvirt.domainParams.
+func (p domainParams) RAM() uint64 {
+	return p.memMiB
+}
+
+// ValidateDomainParams implements kvmlibvirt.domainParams.
+func (p domainParams) ValidateDomainParams() error {
+	if p.name == "" {
+		return errors.New("missing required domain name")
+	}
+	if !set.NewStrings(supportedArches...).Contains(p.arch) {
+		return errors.NotValidf("architecture %q", p.arch)
+	}
+	if len(p.disks) < 2 {
+		// We need at least the root disk and the cloud-init seed.
+		return errors.Errorf("got %d disks, need at least 2", len(p.disks))
+	}
+	return nil
+}
+
+// diskInfo implements kvmlibvirt.DiskInfo.
+type diskInfo struct {
+	source, driver string
+}
+
+// Source implements kvmlibvirt.DiskInfo.
+func (d diskInfo) Source() string {
+	return d.source
+}
+
+// Driver implements kvmlibvirt.DiskInfo.
+func (d diskInfo) Driver() string {
+	return d.driver
+}
+
+// interfaceInfo implements kvmlibvirt.InterfaceInfo.
+type interfaceInfo struct {
+	mac, bridge, name string
+}
+
+// MACAddress implements kvmlibvirt.InterfaceInfo.
+func (i interfaceInfo) MACAddress() string {
+	return i.mac
+}
+
+// ParentInterfaceName implements kvmlibvirt.InterfaceInfo.
+func (i interfaceInfo) ParentInterfaceName() string {
+	return i.bridge
+}
+
+// InterfaceName implements kvmlibvirt.InterfaceInfo.
+func (i interfaceInfo) InterfaceName() string {
+	return i.name
+}
+
+// newMACAddress returns a random MAC address in the 52:54:00 prefix
+// used by QEMU for locally administered addresses.
+func newMACAddress() (string, error) {
+	buf := make([]byte, 3)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("52:54:00:%02x:%02x:%02x", buf[0], buf[1], buf[2]), nil
+}
+
+// writeDomainXML writes the domain definition for p to dir, returning
+// the path of the file.
+func writeDomainXML(dir string, p domainParams) (string, error) {
+	dom, err := kvmlibvirt.NewDomain(p)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	data, err := xml.MarshalIndent(&dom, "", "    ")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	path := filepath.Join(dir, p.name+".xml")
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return "", errors.Trace(err)
+	}
+	return path, nil
+}
+
+// writeSeedImage writes a cloud-init NoCloud seed image containing the
+// user data to dir, returning the path of the image. See
+// http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html
+func writeSeedImage(run runFunc, dir, name string, userData []byte) (string, error) {
+	// instance-id is required; cloud-init uses it to detect first boot.
+	metadata := fmt.Sprintf("{\"instance-id\": %q, \"local-hostname\": %q}", name, name)
+	if err := ioutil.WriteFile(filepath.Join(dir, metadataFile), []byte(metadata), 0644); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, userdataFile), userData, 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+	// NoCloud requires the files to be named exactly user-data and
+	// meta-data, so genisoimage is run from dir with relative paths.
+	path := filepath.Join(dir, name+"-seed.iso")
+	out, err := run(dir,
+		"genisoimage",
+		"-output", path,
+		"-volid", "cidata",
+		"-joliet", "-rock",
+		userdataFile,
+		metadataFile,
+	)
+	if err != nil {
+		return "", errors.Annotatef(err, "creating seed image: %s", out)
+	}
+	return path, nil
+}
@@ -0,0 +1,245 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/provider/common"
+)
+
+type environ struct {
+	name      string
+	uuid      string
+	cloud     environs.CloudSpec
+	namespace instance.Namespace
+	key       string
+	run       runFunc
+
+	lock sync.Mutex
+	ecfg *environConfig
+}
+
+var _ common.ZonedEnviron = (*environ)(nil)
+
+func newEnviron(spec environs.CloudSpec, cfg *config.Config, run runFunc) (*environ, error) {
+	ecfg, err := validateConfig(cfg, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	namespace, err := instance.NewNamespace(cfg.UUID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	key, err := privateKey(spec.Credential)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &environ{
+		name:      ecfg.Name(),
+		uuid:      ecfg.UUID(),
+		cloud:     spec,
+		namespace: namespace,
+		key:       key,
+		run:       run,
+		ecfg:      ecfg,
+	}, nil
+}
+
+// privateKey returns the SSH private key from an ssh-key credential,
+// or an empty string for any other credential.
+func privateKey(cred *cloud.Credential) (string, error) {
+	if cred == nil || cred.AuthType() != cloud.SSHKeyAuthType {
+		return "", nil
+	}
+	key := cred.Attributes()[credAttrPrivateKey]
+	if key == "" {
+		return "", errors.NotValidf("empty %q", credAttrPrivateKey)
+	}
+	return key, nil
+}
+
+// Name returns the Environ's name.
+func (env *environ) Name() string {
+	return env.name
+}
+
+// Provider returns the EnvironProvider that created this Environ.
+func (*environ) Provider() environs.EnvironProvider {
+	return providerInstance
+}
+
+// SetConfig updates the Environ's configuration.
+func (env *environ) SetConfig(cfg *config.Config) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	ecfg, err := validateConfig(cfg, env.ecfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env.ecfg = ecfg
+	return nil
+}
+
+// Config returns the configuration data with which the Environ was created.
+func (env *environ) Config() *config.Config {
+	return env.config().Config
+}
+
+func (env *environ) config() *environConfig {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+	return env.ecfg
+}
+
+// hosts returns a host for the cloud endpoint followed by one for each
+// additional URI in the model config. Each host is an availability zone.
+func (env *environ) hosts() []*host {
+	uris := append([]string{env.cloud.Endpoint}, env.config().hosts()...)
+	hosts := make([]*host, len(uris))
+	for i, uri := range uris {
+		hosts[i] = newHost(uri, env.key, env.run)
+	}
+	return hosts
+}
+
+// host returns the host for the named availability zone.
+func (env *environ) host(zone string) (*host, error) {
+	for _, h := range env.hosts() {
+		if h.zone == zone {
+			return h, nil
+		}
+	}
+	return nil, errors.NotFoundf("libvirt host for zone %q", zone)
+}
+
+// PrepareForBootstrap is part of the Environ interface.
+func (env *environ) PrepareForBootstrap(ctx environs.BootstrapContext, controllerName string) error {
+	for _, h := range env.hosts() {
+		if _, err := h.version(); err != nil {
+			return errors.Annotatef(err, "connecting to libvirt host %q", h.zone)
+		}
+	}
+	return nil
+}
+
+// Create is part of the Environ interface.
+func (env *environ) Create(context.ProviderCallContext, environs.CreateParams) error {
+	return nil
+}
+
+// Bootstrap is part of the Environ interface.
+func (env *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.ProviderCallContext, params environs.BootstrapParams) (*environs.BootstrapResult, error) {
+	return common.Bootstrap(ctx, env, callCtx, params)
+}
+
+// ControllerInstances is part of the Environ interface.
+func (env *environ) ControllerInstances(ctx context.ProviderCallContext, controllerUUID string) ([]instance.Id, error) {
+	insts, err := env.prefixedInstances(env.namespace.Prefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var ids []instance.Id
+	for _, inst := range insts {
+		domainTags, err := inst.host.domainTags(inst.name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if domainTags[tags.JujuIsController] == "true" && domainTags[tags.JujuController] == controllerUUID {
+			ids = append(ids, inst.Id())
+		}
+	}
+	if len(ids) == 0 {
+		return nil, environs.ErrNoInstances
+	}
+	return ids, nil
+}
+
+// AdoptResources is part of the Environ interface.
+func (env *environ) AdoptResources(ctx context.ProviderCallContext, controllerUUID string, fromVersion version.Number) error {
+	insts, err := env.prefixedInstances(env.namespace.Prefix())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, inst := range insts {
+		domainTags, err := inst.host.domainTags(inst.name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		domainTags[tags.JujuController] = controllerUUID
+		if err := inst.host.setDomainTags(inst.name, domainTags); err != nil {
+			return errors.Annotatef(err, "adopting instance %q", inst.name)
+		}
+	}
+	return nil
+}
+
+// Destroy is part of the Environ interface.
+func (env *environ) Destroy(ctx context.ProviderCallContext) error {
+	return common.Destroy(env, ctx)
+}
+
+// DestroyController implements the Environ interface.
+func (env *environ) DestroyController(ctx context.ProviderCallContext, controllerUUID string) error {
+	if err := env.Destroy(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	// Destroy all instances of hosted models with juju-controller-uuid
+	// matching the specified UUID.
+	insts, err := env.prefixedInstances(tags.JujuTagPrefix)
+	if err != nil {
+		return errors.Annotate(err, "listing instances")
+	}
+	var ids []instance.Id
+	for _, inst := range insts {
+		domainTags, err := inst.host.domainTags(inst.name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if domainTags[tags.JujuController] == controllerUUID {
+			ids = append(ids, inst.Id())
+		}
+	}
+	return errors.Trace(env.StopInstances(ctx, ids...))
+}
+
+// PrecheckInstance is part of the Environ interface.
+func (env *environ) PrecheckInstance(ctx context.ProviderCallContext, args environs.PrecheckInstanceParams) error {
+	_, err := env.parsePlacement(ctx, args.Placement)
+	return errors.Trace(err)
+}
+
+var unsupportedConstraints = []string{
+	constraints.Container,
+	constraints.InstanceType,
+	constraints.Tags,
+	constraints.VirtType,
+	constraints.Spaces,
+}
+
+// ConstraintsValidator returns a Validator instance which
+// is used to validate and merge constraints.
+func (env *environ) ConstraintsValidator(ctx context.ProviderCallContext) (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterVocabulary(constraints.Arch, supportedArches)
+	return validator, nil
+}
+
+// InstanceTypes implements environs.InstanceTypesFetcher.
+func (env *environ) InstanceTypes(ctx context.ProviderCallContext, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	return instances.InstanceTypesWithCostMetadata{}, errors.NotSupportedf("InstanceTypes")
+}
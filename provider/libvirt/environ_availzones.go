@@ -0,0 +1,92 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/common"
+)
+
+// hostZone is an availability zone backed by a single libvirt host.
+type hostZone struct {
+	name      string
+	available bool
+}
+
+// Name implements common.AvailabilityZone.
+func (z *hostZone) Name() string {
+	return z.name
+}
+
+// Available implements common.AvailabilityZone.
+func (z *hostZone) Available() bool {
+	return z.available
+}
+
+// AvailabilityZones is part of the common.ZonedEnviron interface. There
+// is one zone per libvirt host; a host that cannot be reached is
+// reported as unavailable.
+func (env *environ) AvailabilityZones(ctx context.ProviderCallContext) ([]common.AvailabilityZone, error) {
+	hosts := env.hosts()
+	zones := make([]common.AvailabilityZone, len(hosts))
+	for i, h := range hosts {
+		_, err := h.version()
+		if err != nil {
+			logger.Warningf("libvirt host %q is unavailable: %v", h.zone, err)
+		}
+		zones[i] = &hostZone{name: h.zone, available: err == nil}
+	}
+	return zones, nil
+}
+
+// InstanceAvailabilityZoneNames is part of the common.ZonedEnviron interface.
+func (env *environ) InstanceAvailabilityZoneNames(ctx context.ProviderCallContext, ids []instance.Id) ([]string, error) {
+	insts, err := env.Instances(ctx, ids)
+	if err != nil && err != environs.ErrPartialInstances {
+		return nil, err
+	}
+	zones := make([]string, len(insts))
+	for i, inst := range insts {
+		if inst != nil {
+			zones[i] = inst.(*environInstance).host.zone
+		}
+	}
+	return zones, err
+}
+
+// DeriveAvailabilityZones is part of the common.ZonedEnviron interface.
+func (env *environ) DeriveAvailabilityZones(ctx context.ProviderCallContext, args environs.StartInstanceParams) ([]string, error) {
+	zone, err := env.parsePlacement(ctx, args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone == "" {
+		return nil, nil
+	}
+	return []string{zone}, nil
+}
+
+// parsePlacement returns the availability zone named by a placement
+// directive of the form "zone=<host>", or an empty string if there is
+// no placement.
+func (env *environ) parsePlacement(ctx context.ProviderCallContext, placement string) (string, error) {
+	if placement == "" {
+		return "", nil
+	}
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 || placement[:pos] != "zone" {
+		return "", errors.Errorf("unknown placement directive: %v", placement)
+	}
+	zone := placement[pos+1:]
+	if err := common.ValidateAvailabilityZone(env, ctx, zone); err != nil {
+		return "", errors.Trace(err)
+	}
+	return zone, nil
+}
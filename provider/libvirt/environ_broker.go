@@ -0,0 +1,360 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	kvmlibvirt "github.com/juju/juju/container/kvm/libvirt"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/tools"
+)
+
+const (
+	defaultCPUs          uint64 = 1
+	defaultMemoryMiB     uint64 = 2048
+	defaultRootDiskMiB   uint64 = 8 * 1024
+	minRootDiskMiB       uint64 = 4 * 1024
+	rootVolumeSuffix            = "-root"
+	seedVolumeSuffix            = "-seed"
+	defaultInterfaceName        = "eth0"
+)
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker. The instance is a
+// libvirt domain booted from a copy-on-write overlay of a cloud image,
+// with a NoCloud seed image carrying the cloud-init user data.
+func (env *environ) StartInstance(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if args.InstanceConfig == nil {
+		return nil, errors.New("instance configuration is nil")
+	}
+	series := args.InstanceConfig.Series
+	logger.Debugf("StartInstance: %q, %s", args.InstanceConfig.MachineId, series)
+
+	h, err := env.startInstanceHost(ctx, args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	instArch := arch.AMD64
+	if args.Constraints.HasArch() {
+		instArch = *args.Constraints.Arch
+	}
+	matchingTools, err := args.Tools.Match(tools.Filter{Arch: instArch})
+	if err != nil {
+		return nil, errors.Errorf("chosen architecture %v not present in %v", instArch, args.Tools.Arches())
+	}
+	if err := args.InstanceConfig.SetTools(matchingTools); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := instancecfg.FinishInstanceConfig(args.InstanceConfig, env.Config()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudCfg, err := cloudinit.New(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, cloudCfg, libvirtRenderer{})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	logger.Debugf("libvirt user data; %d bytes", len(userData))
+
+	name, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hwc := hardwareCharacteristics(args, instArch, h.zone)
+
+	statusCallback := func(msg string) {
+		if args.StatusCallback != nil {
+			args.StatusCallback(status.Provisioning, msg, nil)
+		}
+	}
+	statusCallback("uploading cloud image")
+	pool := env.config().storagePool()
+	base, err := ensureBaseImage(h, pool, series, instArch, env.Config().ImageStream())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	statusCallback("creating domain")
+	if err := env.createDomain(h, name, base, userData, args.InstanceConfig.Tags, hwc); err != nil {
+		env.removeDomain(h, name)
+		if args.StatusCallback != nil {
+			args.StatusCallback(status.ProvisioningError, err.Error(), nil)
+		}
+		return nil, errors.Annotatef(err, "creating domain %q on %s", name, h.zone)
+	}
+	logger.Infof("started instance %q on %s", name, h.zone)
+
+	return &environs.StartInstanceResult{
+		Instance: &environInstance{name: name, state: "running", host: h},
+		Hardware: hwc,
+	}, nil
+}
+
+// startInstanceHost returns the host in which to start the instance,
+// from the placement directive or the availability zone chosen by the
+// provisioner, defaulting to the host of the cloud endpoint.
+func (env *environ) startInstanceHost(ctx context.ProviderCallContext, args environs.StartInstanceParams) (*host, error) {
+	zone, err := env.parsePlacement(ctx, args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone == "" {
+		zone = args.AvailabilityZone
+	}
+	if zone == "" {
+		return env.hosts()[0], nil
+	}
+	h, err := env.host(zone)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return h, nil
+}
+
+// hardwareCharacteristics returns the hardware of an instance started
+// with the given constraints, applying the provider defaults.
+func hardwareCharacteristics(args environs.StartInstanceParams, instArch, zone string) *instance.HardwareCharacteristics {
+	cons := args.Constraints
+	cpus := defaultCPUs
+	if cons.HasCpuCores() {
+		cpus = *cons.CpuCores
+	}
+	mem := defaultMemoryMiB
+	if cons.HasMem() {
+		mem = *cons.Mem
+	}
+	rootDisk := defaultRootDiskMiB
+	if cons.RootDisk != nil {
+		rootDisk = *cons.RootDisk
+		if rootDisk < minRootDiskMiB {
+			rootDisk = minRootDiskMiB
+		}
+	}
+	return &instance.HardwareCharacteristics{
+		Arch:             &instArch,
+		CpuCores:         &cpus,
+		Mem:              &mem,
+		RootDisk:         &rootDisk,
+		AvailabilityZone: &zone,
+	}
+}
+
+// createDomain creates the disks of the named domain in the host's
+// storage pool, and defines and starts the domain.
+func (env *environ) createDomain(
+	h *host, name, base string, userData []byte,
+	instanceTags map[string]string, hwc *instance.HardwareCharacteristics,
+) error {
+	pool := env.config().storagePool()
+	dir, err := ioutil.TempDir("", "juju-libvirt-")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rootName := name + rootVolumeSuffix
+	if err := h.createVolume(pool, rootName, *hwc.RootDisk, "qcow2", base); err != nil {
+		return errors.Trace(err)
+	}
+	rootPath, err := h.volumePath(pool, rootName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	seed, err := writeSeedImage(env.run, dir, name, userData)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := os.Stat(seed)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	seedName := name + seedVolumeSuffix
+	seedMiB := uint64(info.Size()+(1<<20)-1) >> 20
+	if err := h.createVolume(pool, seedName, seedMiB, "raw", ""); err != nil {
+		return errors.Trace(err)
+	}
+	if err := h.uploadVolume(pool, seedName, seed); err != nil {
+		return errors.Trace(err)
+	}
+	seedPath, err := h.volumePath(pool, seedName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	mac, err := newMACAddress()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	domainPath, err := writeDomainXML(dir, domainParams{
+		name:   name,
+		arch:   *hwc.Arch,
+		cpus:   *hwc.CpuCores,
+		memMiB: *hwc.Mem,
+		disks: []kvmlibvirt.DiskInfo{
+			diskInfo{source: rootPath, driver: "qcow2"},
+			diskInfo{source: seedPath, driver: "raw"},
+		},
+		networks: []kvmlibvirt.InterfaceInfo{
+			interfaceInfo{
+				mac:    mac,
+				bridge: env.config().networkBridge(),
+				name:   defaultInterfaceName,
+			},
+		},
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := h.defineDomain(domainPath); err != nil {
+		return errors.Trace(err)
+	}
+
+	// Only Juju-defined tags are recorded on the domain, so that they
+	// can be used to find the model's and controller's instances.
+	domainTags := make(map[string]string)
+	for k, v := range instanceTags {
+		if strings.HasPrefix(k, tags.JujuTagPrefix) {
+			domainTags[k] = v
+		}
+	}
+	if err := h.setDomainTags(name, domainTags); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(h.startDomain(name))
+}
+
+// removeDomain removes the named domain and its root and seed volumes.
+// Errors are logged rather than returned, as any part of the domain may
+// not exist.
+func (env *environ) removeDomain(h *host, name string) {
+	if err := h.removeDomain(name); err != nil {
+		logger.Debugf("removing domain %q: %v", name, err)
+	}
+	pool := env.config().storagePool()
+	for _, vol := range []string{name + rootVolumeSuffix, name + seedVolumeSuffix} {
+		if err := h.deleteVolume(pool, vol); err != nil {
+			logger.Debugf("removing volume %q: %v", vol, err)
+		}
+	}
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances(ctx context.ProviderCallContext) ([]instances.Instance, error) {
+	insts, err := env.prefixedInstances(env.namespace.Prefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := make([]instances.Instance, len(insts))
+	for i, inst := range insts {
+		result[i] = inst
+	}
+	return result, nil
+}
+
+// AllRunningInstances implements environs.InstanceBroker.
+func (env *environ) AllRunningInstances(ctx context.ProviderCallContext) ([]instances.Instance, error) {
+	insts, err := env.prefixedInstances(env.namespace.Prefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []instances.Instance
+	for _, inst := range insts {
+		if inst.state == "running" {
+			result = append(result, inst)
+		}
+	}
+	return result, nil
+}
+
+// Instances implements environs.InstanceBroker.
+func (env *environ) Instances(ctx context.ProviderCallContext, ids []instance.Id) ([]instances.Instance, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	insts, err := env.prefixedInstances(env.namespace.Prefix())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byName := make(map[string]*environInstance)
+	for _, inst := range insts {
+		byName[inst.name] = inst
+	}
+
+	var found int
+	result := make([]instances.Instance, len(ids))
+	for i, id := range ids {
+		if inst, ok := byName[string(id)]; ok {
+			result[i] = inst
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, environs.ErrNoInstances
+	} else if found != len(ids) {
+		return result, environs.ErrPartialInstances
+	}
+	return result, nil
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(ctx context.ProviderCallContext, ids ...instance.Id) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	insts, err := env.prefixedInstances(tags.JujuTagPrefix)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	stop := make(map[string]bool)
+	for _, id := range ids {
+		stop[string(id)] = true
+	}
+	for _, inst := range insts {
+		if stop[inst.name] {
+			logger.Debugf("stopping instance %q on %s", inst.name, inst.host.zone)
+			env.removeDomain(inst.host, inst.name)
+		}
+	}
+	return nil
+}
+
+// prefixedInstances returns the domains on every host whose names have
+// the given prefix.
+func (env *environ) prefixedInstances(prefix string) ([]*environInstance, error) {
+	var result []*environInstance
+	for _, h := range env.hosts() {
+		domains, err := h.domains()
+		if err != nil {
+			return nil, errors.Annotatef(err, "listing domains on %s", h.zone)
+		}
+		for _, d := range domains {
+			if strings.HasPrefix(d.name, prefix) {
+				result = append(result, &environInstance{name: d.name, state: d.state, host: h})
+			}
+		}
+	}
+	return result, nil
+}
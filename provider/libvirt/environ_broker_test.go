@@ -0,0 +1,239 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/imagedownloads"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type environBrokerSuite struct {
+	testing.BaseSuite
+
+	runner  *fakeRunner
+	env     *environ
+	callCtx context.ProviderCallContext
+}
+
+var _ = gc.Suite(&environBrokerSuite{})
+
+func (s *environBrokerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.runner = newFakeRunner()
+	env, err := newEnviron(fakeCloudSpec(), newConfig(c, testing.Attrs{
+		"hosts": "qemu+ssh://root@kvm2/system",
+	}), s.runner.run)
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+	s.callCtx = context.NewCloudCallContext()
+
+	s.PatchValue(&findImage, func(series, imageArch, stream string) (*imagedownloads.Metadata, error) {
+		return &imagedownloads.Metadata{
+			Arch:    imageArch,
+			Release: series,
+			Version: "20200101",
+			Size:    300 << 20,
+		}, nil
+	})
+	dir := c.MkDir()
+	s.PatchValue(&downloadImage, func(*imagedownloads.Metadata) (string, error) {
+		path := filepath.Join(dir, "image")
+		return path, ioutil.WriteFile(path, []byte("qcow2"), 0644)
+	})
+}
+
+func (s *environBrokerSuite) startInstanceArgs(c *gc.C) environs.StartInstanceParams {
+	var cons constraints.Value
+	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(
+		testing.FakeControllerConfig(), cons, cons, "bionic", "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Tags = map[string]string{
+		tags.JujuController:   testing.ControllerTag.Id(),
+		tags.JujuIsController: "true",
+		"owner":               "someone",
+	}
+	tools := coretools.List{{
+		Version: version.Binary{
+			Number: version.MustParse("2.8.0"),
+			Arch:   arch.AMD64,
+			Series: "bionic",
+		},
+		URL: "https://example.org",
+	}}
+	return environs.StartInstanceParams{
+		ControllerUUID: testing.ControllerTag.Id(),
+		InstanceConfig: instanceConfig,
+		Tools:          tools,
+		Constraints:    cons,
+	}
+}
+
+// commands returns the virsh sub-commands (or other commands) run.
+func (s *environBrokerSuite) commands() []string {
+	var names []string
+	for _, call := range s.runner.calls {
+		names = append(names, strings.Fields(call)[0])
+	}
+	return names
+}
+
+func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
+	s.runner.outputs["vol-info"] = "error: Storage volume not found"
+	s.runner.errors["vol-info"] = errors.New("exit status 1")
+	s.runner.outputs["vol-path"] = "/var/lib/libvirt/images/vol\n"
+
+	result, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+
+	name, err := s.env.namespace.Hostname("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id(name))
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "kvm1")
+	c.Assert(*result.Hardware.Arch, gc.Equals, "amd64")
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(8192))
+
+	c.Assert(s.commands(), jc.DeepEquals, []string{
+		"vol-info", "vol-create-as", "vol-upload",
+		"vol-create-as", "vol-path",
+		"genisoimage", "vol-create-as", "vol-upload", "vol-path",
+		"define", "desc", "autostart", "start",
+	})
+	c.Assert(s.runner.calls[1], gc.Equals, "vol-create-as default juju-base-bionic-amd64 300M --format qcow2")
+	c.Assert(s.runner.calls[3], gc.Equals,
+		"vol-create-as default "+name+"-root 8192M --format qcow2 --backing-vol juju-base-bionic-amd64 --backing-vol-format qcow2")
+	// Only Juju tags are recorded on the domain.
+	c.Assert(s.runner.calls[10], gc.Equals, "desc "+name+" --config --new-desc "+
+		"juju-controller-uuid="+testing.ControllerTag.Id()+"\njuju-is-controller=true")
+}
+
+func (s *environBrokerSuite) TestStartInstanceExistingImage(c *gc.C) {
+	s.runner.outputs["vol-info"] = "Capacity:       2361393152 bytes\n"
+	_, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.commands()[:2], jc.DeepEquals, []string{"vol-info", "vol-create-as"})
+	c.Assert(s.runner.calls[1], jc.Contains, "-root")
+}
+
+func (s *environBrokerSuite) TestStartInstancePlacement(c *gc.C) {
+	s.runner.outputs["vol-info"] = "Capacity:       2361393152 bytes\n"
+	args := s.startInstanceArgs(c)
+	args.Placement = "zone=kvm2"
+	result, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "kvm2")
+}
+
+func (s *environBrokerSuite) TestStartInstanceUnknownZone(c *gc.C) {
+	args := s.startInstanceArgs(c)
+	args.Placement = "zone=kvm9"
+	_, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `availability zone "kvm9" not valid`)
+}
+
+func (s *environBrokerSuite) TestStartInstanceCleansUpOnFailure(c *gc.C) {
+	s.runner.outputs["vol-info"] = "Capacity:       2361393152 bytes\n"
+	s.runner.errors["define"] = errors.New("exit status 1")
+	_, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c))
+	c.Assert(err, gc.ErrorMatches, `creating domain ".*" on kvm1: virsh define on kvm1: : exit status 1`)
+	commands := s.commands()
+	c.Assert(commands[len(commands)-4:], jc.DeepEquals, []string{
+		"destroy", "undefine", "vol-delete", "vol-delete",
+	})
+}
+
+func (s *environBrokerSuite) TestAllInstances(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["list"] = ` 1    ` + prefix + `0   running
+ -    ` + prefix + `1   shut off
+ 2    juju-0ther0-0   running
+ 3    ubuntu   running
+`
+	insts, err := s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	// Two hosts, each reporting the same domains.
+	c.Assert(insts, gc.HasLen, 4)
+	c.Assert(insts[0].Id(), gc.Equals, instance.Id(prefix+"0"))
+	c.Assert(insts[1].Id(), gc.Equals, instance.Id(prefix+"1"))
+	c.Assert(insts[1].Status(s.callCtx).Message, gc.Equals, "shut off")
+
+	running, err := s.env.AllRunningInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 2)
+}
+
+func (s *environBrokerSuite) TestInstancesPartial(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["list"] = ` 1    ` + prefix + `0   running
+`
+	insts, err := s.env.Instances(s.callCtx, []instance.Id{instance.Id(prefix + "0"), "missing"})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(insts[0], gc.NotNil)
+	c.Assert(insts[1], gc.IsNil)
+
+	_, err = s.env.Instances(s.callCtx, []instance.Id{"missing"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["list"] = ` 1    ` + prefix + `0   running
+`
+	err := s.env.StopInstances(s.callCtx, instance.Id(prefix+"0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.runner.calls, jc.DeepEquals, []string{
+		"-q list --all",
+		"-q list --all",
+		"destroy " + prefix + "0",
+		"undefine --nvram " + prefix + "0",
+		"vol-delete --pool default " + prefix + "0-root",
+		"vol-delete --pool default " + prefix + "0-seed",
+		"destroy " + prefix + "0",
+		"undefine --nvram " + prefix + "0",
+		"vol-delete --pool default " + prefix + "0-root",
+		"vol-delete --pool default " + prefix + "0-seed",
+	})
+}
+
+func (s *environBrokerSuite) TestControllerInstances(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["list"] = ` 1    ` + prefix + `0   running
+`
+	s.runner.outputs["desc"] = "juju-controller-uuid=" + testing.ControllerTag.Id() + "\njuju-is-controller=true\n"
+	ids, err := s.env.ControllerInstances(s.callCtx, testing.ControllerTag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, gc.HasLen, 2)
+	c.Assert(ids[0], gc.Equals, instance.Id(prefix+"0"))
+
+	_, err = s.env.ControllerInstances(s.callCtx, "other")
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environBrokerSuite) TestAvailabilityZones(c *gc.C) {
+	s.runner.errors["version"] = errors.New("exit status 1")
+	zones, err := s.env.AvailabilityZones(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 2)
+	c.Assert(zones[0].Name(), gc.Equals, "kvm1")
+	c.Assert(zones[1].Name(), gc.Equals, "kvm2")
+	c.Assert(zones[0].Available(), jc.IsFalse)
+}
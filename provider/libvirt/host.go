@@ -0,0 +1,369 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+// This file contains a wrapper around virsh, used to manage domains and
+// storage volumes on a single (usually remote) libvirt host. virsh is
+// found in the libvirt-clients package, which must be installed wherever
+// the provider runs: on the client for bootstrap, and on controllers.
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const virsh = "virsh"
+
+// runFunc provides the signature for running an external
+// command and returning the combined output.
+// The first parameter, if non-empty will use the input
+// path as the working directory for the command.
+type runFunc func(string, string, ...string) (string, error)
+
+// run the command and return the combined output.
+func run(dir, command string, args ...string) (string, error) {
+	logger.Tracef("(%s) %s %v", dir, command, args)
+
+	cmd := exec.Command(command, args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+var (
+	// domainListPattern breaks up the output of 'virsh -q list --all'
+	// into the domain id (or "-" when not running), name and state.
+	domainListPattern = regexp.MustCompile(`(?m)^[ \t]*\S+[ \t]+(?P<name>\S+)[ \t]+(?P<state>\S.*?)[ \t]*$`)
+
+	// capacityPattern extracts the capacity from 'virsh vol-info --bytes'.
+	capacityPattern = regexp.MustCompile(`(?m)^Capacity:\s+(\d+) bytes`)
+)
+
+// domain describes a libvirt domain as reported by 'virsh list'.
+type domain struct {
+	name  string
+	state string
+}
+
+// host runs virsh commands against a single libvirt host.
+type host struct {
+	// uri is the libvirt connection URI.
+	uri string
+	// key is the SSH private key used to authenticate SSH based
+	// connections, if any.
+	key string
+	// zone is the availability zone name of the host.
+	zone string
+	run  runFunc
+}
+
+// newHost returns a host for the given libvirt URI. If key is not
+// empty, it is used to authenticate SSH based connections.
+func newHost(uri, key string, run runFunc) *host {
+	h := &host{uri: uri, zone: zoneName(uri), run: run}
+	if u, err := url.Parse(uri); err == nil && strings.Contains(u.Scheme, "ssh") {
+		h.key = key
+	}
+	return h
+}
+
+// zoneName returns the availability zone name of the host with the
+// given libvirt URI, which is the host name of the URI.
+func zoneName(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Hostname() == "" {
+		return "localhost"
+	}
+	return u.Hostname()
+}
+
+// connectURI returns the URI virsh should connect to. If the host has
+// an SSH key, it is written to a new temporary file, readable only by
+// the current user, which is passed to ssh through the URI; the
+// returned function removes the file again.
+func (h *host) connectURI() (string, func(), error) {
+	if h.key == "" {
+		return h.uri, func() {}, nil
+	}
+	u, err := url.Parse(h.uri)
+	if err != nil {
+		return "", nil, errors.Trace(err)
+	}
+	// TempFile creates the file with mode 0600.
+	f, err := ioutil.TempFile("", "juju-libvirt-")
+	if err != nil {
+		return "", nil, errors.Annotate(err, "writing SSH key")
+	}
+	remove := func() {
+		if err := os.Remove(f.Name()); err != nil && !os.IsNotExist(err) {
+			logger.Warningf("removing SSH key file: %v", err)
+		}
+	}
+	_, err = f.WriteString(h.key)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		remove()
+		return "", nil, errors.Annotate(err, "writing SSH key")
+	}
+	q := u.Query()
+	q.Set("keyfile", f.Name())
+	u.RawQuery = q.Encode()
+	return u.String(), remove, nil
+}
+
+func (h *host) virsh(args ...string) (string, error) {
+	uri, cleanup, err := h.connectURI()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer cleanup()
+	out, err := h.run("", virsh, append([]string{"-c", uri}, args...)...)
+	if err != nil {
+		command := args[0]
+		if command == "-q" {
+			command = args[1]
+		}
+		return "", errors.Annotatef(err, "virsh %s on %s: %s", command, h.zone, strings.TrimSpace(out))
+	}
+	return out, nil
+}
+
+// version returns the output of 'virsh version', and is used to check
+// that the host is reachable.
+func (h *host) version() (string, error) {
+	return h.virsh("version")
+}
+
+// domains returns every domain defined on the host, sorted by name.
+func (h *host) domains() ([]domain, error) {
+	out, err := h.virsh("-q", "list", "--all")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []domain
+	for _, m := range domainListPattern.FindAllStringSubmatch(out, -1) {
+		result = append(result, domain{name: m[1], state: m[2]})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+	return result, nil
+}
+
+// domainTags returns the key=value tags stored in the domain's
+// description.
+func (h *host) domainTags(name string) (map[string]string, error) {
+	out, err := h.virsh("desc", name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tags := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 {
+			tags[parts[0]] = parts[1]
+		}
+	}
+	return tags, nil
+}
+
+// setDomainTags replaces the domain's description with the given
+// key=value tags.
+func (h *host) setDomainTags(name string, tags map[string]string) error {
+	lines := make([]string, 0, len(tags))
+	for k, v := range tags {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(lines)
+	_, err := h.virsh("desc", name, "--config", "--new-desc", strings.Join(lines, "\n"))
+	return errors.Trace(err)
+}
+
+// defineDomain defines a persistent domain from the XML file at path.
+func (h *host) defineDomain(path string) error {
+	_, err := h.virsh("define", path)
+	return errors.Trace(err)
+}
+
+// startDomain starts the named domain, and marks it to be started
+// automatically when the host boots.
+func (h *host) startDomain(name string) error {
+	if _, err := h.virsh("autostart", name); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := h.virsh("start", name)
+	return errors.Trace(err)
+}
+
+// removeDomain stops and undefines the named domain. It is not an error
+// if the domain is already stopped.
+func (h *host) removeDomain(name string) error {
+	if _, err := h.virsh("destroy", name); err != nil {
+		logger.Debugf("destroying domain %q: %v", name, err)
+	}
+	_, err := h.virsh("undefine", "--nvram", name)
+	return errors.Trace(err)
+}
+
+// interfaceAddresses returns the IP addresses of the domain's network
+// interfaces, as known to the host's DHCP leases or ARP table.
+func (h *host) interfaceAddresses(name string) ([]string, error) {
+	var addrs []string
+	for _, source := range []string{"lease", "arp"} {
+		out, err := h.virsh("domifaddr", name, "--source", source)
+		if err != nil {
+			logger.Debugf("getting %s addresses of %q: %v", source, name, err)
+			continue
+		}
+		for _, fields := range tableRows(out) {
+			if len(fields) < 4 {
+				continue
+			}
+			addrs = append(addrs, strings.SplitN(fields[3], "/", 2)[0])
+		}
+		if len(addrs) > 0 {
+			break
+		}
+	}
+	return addrs, nil
+}
+
+// volumes returns the names of every volume in the pool.
+func (h *host) volumes(pool string) ([]string, error) {
+	out, err := h.virsh("vol-list", "--pool", pool)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for _, fields := range tableRows(out) {
+		names = append(names, fields[0])
+	}
+	return names, nil
+}
+
+// volumeCapacity returns the capacity of the named volume in MiB. If
+// the volume does not exist, an error satisfying errors.IsNotFound is
+// returned.
+func (h *host) volumeCapacity(pool, name string) (uint64, error) {
+	out, err := h.virsh("vol-info", "--pool", pool, name, "--bytes")
+	if err != nil {
+		if strings.Contains(err.Error(), "Storage volume not found") {
+			return 0, errors.NotFoundf("volume %q in pool %q on %s", name, pool, h.zone)
+		}
+		return 0, errors.Trace(err)
+	}
+	m := capacityPattern.FindStringSubmatch(out)
+	if m == nil {
+		return 0, errors.Errorf("cannot parse capacity of volume %q", name)
+	}
+	bytes, err := strconv.ParseUint(m[1], 10, 64)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return bytes / (1024 * 1024), nil
+}
+
+// createVolume creates a volume in the pool with the given capacity in
+// MiB and format. If backing is not empty, the new qcow2 volume is a
+// copy-on-write overlay of the named volume in the same pool.
+func (h *host) createVolume(pool, name string, sizeMiB uint64, format, backing string) error {
+	args := []string{
+		"vol-create-as", pool, name, fmt.Sprintf("%dM", sizeMiB),
+		"--format", format,
+	}
+	if backing != "" {
+		args = append(args, "--backing-vol", backing, "--backing-vol-format", "qcow2")
+	}
+	_, err := h.virsh(args...)
+	return errors.Trace(err)
+}
+
+// uploadVolume replaces the content of the named volume with the local
+// file at path.
+func (h *host) uploadVolume(pool, name, path string) error {
+	_, err := h.virsh("vol-upload", "--pool", pool, name, path)
+	return errors.Trace(err)
+}
+
+// deleteVolume deletes the named volume from the pool.
+func (h *host) deleteVolume(pool, name string) error {
+	_, err := h.virsh("vol-delete", "--pool", pool, name)
+	return errors.Trace(err)
+}
+
+// volumePath returns the path of the named volume on the host.
+func (h *host) volumePath(pool, name string) (string, error) {
+	out, err := h.virsh("vol-path", "--pool", pool, name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// blockDevices returns the domain's disks as a map of target device
+// name to source path.
+func (h *host) blockDevices(name string) (map[string]string, error) {
+	out, err := h.virsh("domblklist", name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	devices := make(map[string]string)
+	for _, fields := range tableRows(out) {
+		if len(fields) == 2 {
+			devices[fields[0]] = fields[1]
+		}
+	}
+	return devices, nil
+}
+
+// attachDisk attaches the disk at source to the domain as the given
+// target device, exposing serial to the guest.
+func (h *host) attachDisk(name, source, target, serial string) error {
+	_, err := h.virsh(
+		"attach-disk", name, source, target,
+		"--targetbus", "virtio",
+		"--subdriver", "qcow2",
+		"--serial", serial,
+		"--persistent",
+	)
+	return errors.Trace(err)
+}
+
+// detachDisk detaches the target device from the domain.
+func (h *host) detachDisk(name, target string) error {
+	_, err := h.virsh("detach-disk", name, target, "--persistent")
+	return errors.Trace(err)
+}
+
+// tableRows returns the whitespace separated fields of each row of a
+// virsh table, skipping the header and separator lines.
+func tableRows(out string) [][]string {
+	var rows [][]string
+	lines := strings.Split(out, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "---") {
+			continue
+		}
+		// The header is the line immediately before the separator.
+		if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "---") {
+			continue
+		}
+		rows = append(rows, fields)
+	}
+	return rows
+}
@@ -0,0 +1,207 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+// fakeRunner records the commands it is asked to run, and returns
+// canned output for virsh sub-commands.
+type fakeRunner struct {
+	calls   []string
+	outputs map[string]string
+	errors  map[string]error
+}
+
+func newFakeRunner() *fakeRunner {
+	return &fakeRunner{
+		outputs: make(map[string]string),
+		errors:  make(map[string]error),
+	}
+}
+
+func (r *fakeRunner) run(dir, command string, args ...string) (string, error) {
+	key := command
+	switch command {
+	case virsh:
+		// Skip "-c <uri>", and the quiet flag.
+		key = args[2]
+		if key == "-q" {
+			key = args[3]
+		}
+		r.calls = append(r.calls, strings.Join(args[2:], " "))
+	case "genisoimage":
+		r.calls = append(r.calls, command)
+		for i, arg := range args {
+			if arg == "-output" {
+				if err := ioutil.WriteFile(args[i+1], []byte("iso"), 0644); err != nil {
+					return "", err
+				}
+			}
+		}
+	}
+	return r.outputs[key], r.errors[key]
+}
+
+type hostSuite struct {
+	testing.IsolationSuite
+
+	runner *fakeRunner
+	host   *host
+}
+
+var _ = gc.Suite(&hostSuite{})
+
+func (s *hostSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.runner = newFakeRunner()
+	s.host = newHost("qemu+ssh://root@kvm1/system", "", s.runner.run)
+}
+
+func (s *hostSuite) TestZoneName(c *gc.C) {
+	c.Check(zoneName("qemu+ssh://root@kvm1.lab/system"), gc.Equals, "kvm1.lab")
+	c.Check(zoneName("qemu:///system"), gc.Equals, "localhost")
+}
+
+func (s *hostSuite) TestKeyFile(c *gc.C) {
+	var keyFile string
+	run := func(dir, command string, args ...string) (string, error) {
+		u, err := url.Parse(args[1])
+		c.Assert(err, jc.ErrorIsNil)
+		keyFile = u.Query().Get("keyfile")
+		info, err := os.Stat(keyFile)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+		data, err := ioutil.ReadFile(keyFile)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(string(data), gc.Equals, "private-key")
+		return "", nil
+	}
+	h := newHost("qemu+ssh://root@kvm1/system", "private-key", run)
+	c.Check(h.zone, gc.Equals, "kvm1")
+	_, err := h.version()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keyFile, gc.Not(gc.Equals), "")
+	c.Assert(keyFile, jc.DoesNotExist)
+
+	// Local connections do not use SSH.
+	h = newHost("qemu:///system", "private-key", s.runner.run)
+	c.Check(h.key, gc.Equals, "")
+}
+
+func (s *hostSuite) TestDomains(c *gc.C) {
+	s.runner.outputs["list"] = `
+ 3    juju-06f00d-1   running
+ -    juju-06f00d-0   shut off
+`
+	domains, err := s.host.domains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, jc.DeepEquals, []domain{
+		{name: "juju-06f00d-0", state: "shut off"},
+		{name: "juju-06f00d-1", state: "running"},
+	})
+	c.Assert(s.runner.calls, jc.DeepEquals, []string{"-q list --all"})
+}
+
+func (s *hostSuite) TestDomainsError(c *gc.C) {
+	s.runner.outputs["list"] = "error: failed to connect to the hypervisor"
+	s.runner.errors["list"] = errors.New("exit status 1")
+	_, err := s.host.domains()
+	c.Assert(err, gc.ErrorMatches, "virsh list on kvm1: error: failed to connect to the hypervisor: exit status 1")
+}
+
+func (s *hostSuite) TestDomainTags(c *gc.C) {
+	s.runner.outputs["desc"] = "juju-controller-uuid=deadbeef\njuju-is-controller=true\n"
+	domainTags, err := s.host.domainTags("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domainTags, jc.DeepEquals, map[string]string{
+		"juju-controller-uuid": "deadbeef",
+		"juju-is-controller":   "true",
+	})
+}
+
+func (s *hostSuite) TestSetDomainTags(c *gc.C) {
+	err := s.host.setDomainTags("juju-06f00d-0", map[string]string{
+		"juju-model-uuid":      "cafe",
+		"juju-controller-uuid": "deadbeef",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.runner.calls, jc.DeepEquals, []string{
+		"desc juju-06f00d-0 --config --new-desc juju-controller-uuid=deadbeef\njuju-model-uuid=cafe",
+	})
+}
+
+func (s *hostSuite) TestInterfaceAddresses(c *gc.C) {
+	s.runner.outputs["domifaddr"] = `
+ Name       MAC address          Protocol     Address
+-------------------------------------------------------------------------------
+ vnet0      52:54:00:a1:b2:c3    ipv4         192.168.122.45/24
+`
+	addrs, err := s.host.interfaceAddresses("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"192.168.122.45"})
+}
+
+func (s *hostSuite) TestVolumes(c *gc.C) {
+	s.runner.outputs["vol-list"] = `
+ Name                        Path
+------------------------------------------------------------------------------
+ juju-base-bionic-amd64      /var/lib/libvirt/images/juju-base-bionic-amd64
+ juju-06f00d-0-root          /var/lib/libvirt/images/juju-06f00d-0-root
+`
+	names, err := s.host.volumes("default")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"juju-base-bionic-amd64", "juju-06f00d-0-root"})
+}
+
+func (s *hostSuite) TestVolumeCapacity(c *gc.C) {
+	s.runner.outputs["vol-info"] = `
+Name:           juju-06f00d-volume-0
+Type:           file
+Capacity:       10737418240 bytes
+Allocation:     196608 bytes
+`
+	size, err := s.host.volumeCapacity("default", "juju-06f00d-volume-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(10240))
+}
+
+func (s *hostSuite) TestVolumeCapacityNotFound(c *gc.C) {
+	s.runner.outputs["vol-info"] = "error: failed to get vol 'foo'\nerror: Storage volume not found: no storage vol with matching path 'foo'"
+	s.runner.errors["vol-info"] = errors.New("exit status 1")
+	_, err := s.host.volumeCapacity("default", "foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *hostSuite) TestCreateVolumeWithBacking(c *gc.C) {
+	err := s.host.createVolume("default", "juju-06f00d-0-root", 8192, "qcow2", "juju-base-bionic-amd64")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.runner.calls, jc.DeepEquals, []string{
+		"vol-create-as default juju-06f00d-0-root 8192M --format qcow2 --backing-vol juju-base-bionic-amd64 --backing-vol-format qcow2",
+	})
+}
+
+func (s *hostSuite) TestBlockDevices(c *gc.C) {
+	s.runner.outputs["domblklist"] = `
+ Target   Source
+------------------------------------------------
+ vda      /var/lib/libvirt/images/juju-06f00d-0-root
+ vdb      /var/lib/libvirt/images/juju-06f00d-0-seed
+`
+	devices, err := s.host.blockDevices("juju-06f00d-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, jc.DeepEquals, map[string]string{
+		"vda": "/var/lib/libvirt/images/juju-06f00d-0-root",
+		"vdb": "/var/lib/libvirt/images/juju-06f00d-0-seed",
+	})
+}
@@ -0,0 +1,119 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/environs/imagedownloads"
+)
+
+const (
+	// biosFType is the simplestreams file type of the cloud images
+	// booted by BIOS on amd64 and ppc64el.
+	biosFType = "disk1.img"
+
+	// uefiFType is the simplestreams file type of the cloud images
+	// booted by UEFI on arm64.
+	uefiFType = "uefi1.img"
+)
+
+// supportedArches are the architectures for which cloud images are
+// published in the image-downloads simplestreams data.
+var supportedArches = []string{arch.AMD64, arch.ARM64, arch.PPC64EL}
+
+// findImage returns the metadata of the most recent cloud image for the
+// series and architecture. It is a variable so it can be patched in tests.
+var findImage = func(series, imageArch, stream string) (*imagedownloads.Metadata, error) {
+	fType := biosFType
+	if imageArch == arch.ARM64 {
+		fType = uefiFType
+	}
+	return imagedownloads.One(imageArch, series, stream, fType, nil)
+}
+
+// downloadImage fetches the cloud image described by md to a temporary
+// file, verifying its checksum, and returns the file's path. The caller
+// is responsible for removing the file. It is a variable so it can be
+// patched in tests.
+var downloadImage = func(md *imagedownloads.Metadata) (string, error) {
+	dlURL, err := md.DownloadURL()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	resp, err := http.Get(dlURL.String())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.NotFoundf("got %d fetching image %q", resp.StatusCode, dlURL)
+	}
+
+	f, err := ioutil.TempFile("", "juju-libvirt-image-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer f.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(f, hash), resp.Body); err != nil {
+		os.Remove(f.Name())
+		return "", errors.Trace(err)
+	}
+	if sum := fmt.Sprintf("%x", hash.Sum(nil)); sum != md.SHA256 {
+		os.Remove(f.Name())
+		return "", errors.Errorf("hash sum mismatch for %s: %s != %s", dlURL, sum, md.SHA256)
+	}
+	return f.Name(), nil
+}
+
+// baseVolumeName returns the name of the pool volume holding the cloud
+// image for the series and architecture. Instance root disks are
+// copy-on-write overlays of this volume.
+func baseVolumeName(series, arch string) string {
+	return fmt.Sprintf("juju-base-%s-%s", series, arch)
+}
+
+// ensureBaseImage uploads the cloud image for the series and architecture
+// to the host's storage pool, unless it is already there, and returns the
+// name of the volume.
+func ensureBaseImage(h *host, pool, series, arch, stream string) (string, error) {
+	name := baseVolumeName(series, arch)
+	if _, err := h.volumeCapacity(pool, name); err == nil {
+		return name, nil
+	} else if !errors.IsNotFound(err) {
+		return "", errors.Trace(err)
+	}
+
+	md, err := findImage(series, arch, stream)
+	if err != nil {
+		return "", errors.Annotatef(err, "finding %s/%s cloud image", series, arch)
+	}
+	logger.Infof("uploading %s/%s cloud image %s to %s", series, arch, md.Version, h.zone)
+	path, err := downloadImage(md)
+	if err != nil {
+		return "", errors.Annotate(err, "downloading cloud image")
+	}
+	defer os.Remove(path)
+
+	sizeMiB := uint64(md.Size+(1<<20)-1) >> 20
+	if err := h.createVolume(pool, name, sizeMiB, "qcow2", ""); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := h.uploadVolume(pool, name, path); err != nil {
+		if err := h.deleteVolume(pool, name); err != nil {
+			logger.Errorf("removing partially uploaded image %q: %v", name, err)
+		}
+		return "", errors.Trace(err)
+	}
+	return name, nil
+}
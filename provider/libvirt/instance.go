@@ -0,0 +1,74 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/network"
+)
+
+// environInstance is a libvirt domain on one of the environ's hosts.
+type environInstance struct {
+	name  string
+	state string
+	host  *host
+}
+
+var _ instances.Instance = (*environInstance)(nil)
+
+// Id implements instances.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.name)
+}
+
+// Status implements instances.Instance, mapping the domain state
+// reported by virsh to a Juju status.
+func (inst *environInstance) Status(ctx context.ProviderCallContext) instance.Status {
+	var jujuStatus status.Status
+	switch inst.state {
+	case "running", "idle":
+		jujuStatus = status.Running
+	case "shut off", "in shutdown", "paused", "pmsuspended":
+		jujuStatus = status.Empty
+	case "crashed":
+		jujuStatus = status.ProvisioningError
+	default:
+		jujuStatus = status.Pending
+	}
+	return instance.Status{
+		Status:  jujuStatus,
+		Message: inst.state,
+	}
+}
+
+// Addresses implements instances.Instance.
+func (inst *environInstance) Addresses(ctx context.ProviderCallContext) (corenetwork.ProviderAddresses, error) {
+	ips, err := inst.host.interfaceAddresses(inst.name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return corenetwork.NewProviderAddresses(ips...), nil
+}
+
+// OpenPorts implements instances.InstanceFirewaller. Instances are
+// attached directly to a host bridge, so there is no firewall to manage.
+func (inst *environInstance) OpenPorts(ctx context.ProviderCallContext, machineID string, rules []network.IngressRule) error {
+	return errors.NotImplementedf("OpenPorts")
+}
+
+// ClosePorts implements instances.InstanceFirewaller.
+func (inst *environInstance) ClosePorts(ctx context.ProviderCallContext, machineID string, rules []network.IngressRule) error {
+	return errors.NotImplementedf("ClosePorts")
+}
+
+// IngressRules implements instances.InstanceFirewaller.
+func (inst *environInstance) IngressRules(ctx context.ProviderCallContext, machineID string) ([]network.IngressRule, error) {
+	return nil, errors.NotImplementedf("IngressRules")
+}
@@ -0,0 +1,190 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package libvirt implements a Juju provider that drives one or more
+// libvirt/QEMU hypervisor hosts directly, using virsh over the libvirt
+// remote protocol (typically qemu+ssh). Each host is an availability zone.
+package libvirt
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/jsonschema"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/environs/context"
+)
+
+var logger = loggo.GetLogger("juju.provider.libvirt")
+
+const (
+	providerType = "libvirt"
+)
+
+var cloudSchema = &jsonschema.Schema{
+	Type:     []jsonschema.Type{jsonschema.ObjectType},
+	Required: []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Order:    []string{cloud.EndpointKey, cloud.AuthTypesKey, cloud.RegionsKey},
+	Properties: map[string]*jsonschema.Schema{
+		cloud.EndpointKey: {
+			Singular: "the libvirt URI of the hypervisor host (e.g. qemu+ssh://root@kvm1/system)",
+			Type:     []jsonschema.Type{jsonschema.StringType},
+		},
+		cloud.AuthTypesKey: {
+			Singular:    "auth type",
+			Plural:      "auth types",
+			Type:        []jsonschema.Type{jsonschema.ArrayType},
+			UniqueItems: jsonschema.Bool(true),
+			Default:     string(cloud.SSHKeyAuthType),
+			Items: &jsonschema.ItemSpec{
+				Schemas: []*jsonschema.Schema{{
+					Type: []jsonschema.Type{jsonschema.StringType},
+					Enum: []interface{}{
+						string(cloud.SSHKeyAuthType),
+						string(cloud.EmptyAuthType),
+					},
+				}},
+			},
+		},
+		cloud.RegionsKey: {
+			Type:     []jsonschema.Type{jsonschema.ObjectType},
+			Singular: "region",
+			Plural:   "regions",
+			Default:  "default",
+			AdditionalProperties: &jsonschema.Schema{
+				Type:          []jsonschema.Type{jsonschema.ObjectType},
+				Required:      []string{cloud.EndpointKey},
+				MaxProperties: jsonschema.Int(1),
+				Properties: map[string]*jsonschema.Schema{
+					cloud.EndpointKey: {
+						Singular:      "the libvirt URI of the region's hypervisor host",
+						Type:          []jsonschema.Type{jsonschema.StringType},
+						Default:       "",
+						PromptDefault: "use cloud endpoint",
+					},
+				},
+			},
+		},
+	},
+}
+
+type environProvider struct {
+	environProviderCredentials
+}
+
+var providerInstance = environProvider{}
+
+// check the provider implements environs.EnvironProvider interface
+var _ environs.CloudEnvironProvider = (*environProvider)(nil)
+
+func init() {
+	// This will only happen in binaries that actually import this provider
+	// somewhere. To enable a provider, import it in the "providers/all"
+	// package; please do *not* import individual providers anywhere else,
+	// except in direct tests for that provider.
+	environs.RegisterProvider(providerType, providerInstance)
+}
+
+// Version is part of the EnvironProvider interface.
+func (environProvider) Version() int {
+	return 0
+}
+
+// Open opens the environment and returns it.
+// The configuration must have come from a previously
+// prepared environment.
+func (environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
+	logger.Infof("opening model %q", args.Config.Name())
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	env, err := newEnviron(args.Cloud, args.Config, run)
+	return env, errors.Trace(err)
+}
+
+// CloudSchema returns the schema used to validate input for add-cloud.
+func (environProvider) CloudSchema() *jsonschema.Schema {
+	return cloudSchema
+}
+
+// Ping tests the connection to the cloud, to verify the endpoint is valid.
+func (environProvider) Ping(ctx context.ProviderCallContext, endpoint string) error {
+	if err := validateURI(endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	host := newHost(endpoint, "", run)
+	if _, err := host.version(); err != nil {
+		return errors.Errorf("no libvirt daemon reachable at %s", endpoint)
+	}
+	return nil
+}
+
+// PrepareConfig is defined by EnvironProvider.
+func (environProvider) PrepareConfig(args environs.PrepareConfigParams) (*config.Config, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	return args.Config, nil
+}
+
+// Validate ensures that config is a valid configuration for this
+// provider, applying changes to it if necessary, and returns the
+// validated configuration.
+// If old is not nil, it holds the previous environment configuration
+// for consideration when validating changes.
+func (environProvider) Validate(cfg, old *config.Config) (*config.Config, error) {
+	newEcfg, err := validateConfig(cfg, nil)
+	if err != nil {
+		return nil, errors.Errorf("invalid config: %v", err)
+	}
+	if old != nil {
+		oldEcfg, err := validateConfig(old, nil)
+		if err != nil {
+			return nil, errors.Errorf("invalid base config: %v", err)
+		}
+		if newEcfg, err = validateConfig(cfg, oldEcfg); err != nil {
+			return nil, errors.Errorf("invalid config change: %v", err)
+		}
+	}
+	return newEcfg.Config, nil
+}
+
+func validateCloudSpec(spec environs.CloudSpec) error {
+	if err := spec.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateURI(spec.Endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	if spec.Credential == nil {
+		return errors.NotValidf("missing credential")
+	}
+	switch authType := spec.Credential.AuthType(); authType {
+	case cloud.SSHKeyAuthType, cloud.EmptyAuthType:
+	default:
+		return errors.NotSupportedf("%q auth-type", authType)
+	}
+	return nil
+}
+
+// validateURI checks that uri is a libvirt connection URI for the QEMU
+// driver, e.g. qemu:///system or qemu+ssh://user@host/system.
+func validateURI(uri string) error {
+	if uri == "" {
+		return errors.NotValidf("missing libvirt URI")
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return errors.NotValidf("libvirt URI %q", uri)
+	}
+	switch u.Scheme {
+	case "qemu", "qemu+ssh", "qemu+tcp", "qemu+tls", "qemu+libssh2":
+	default:
+		return errors.NotValidf("libvirt URI %q (only qemu drivers are supported)", uri)
+	}
+	return nil
+}
@@ -0,0 +1,351 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	storageProviderType = storage.ProviderType("libvirt")
+
+	// maxSerialLength is the length to which QEMU truncates the serial
+	// number of a virtio block device.
+	maxSerialLength = 20
+)
+
+// StorageProviderTypes implements storage.ProviderRegistry.
+func (env *environ) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return []storage.ProviderType{storageProviderType}, nil
+}
+
+// StorageProvider implements storage.ProviderRegistry.
+func (env *environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == storageProviderType {
+		return &storageProvider{env}, nil
+	}
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
+
+// storageProvider creates volumes in the libvirt storage pool of the
+// host of the instance they are attached to.
+type storageProvider struct {
+	env *environ
+}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// ValidateConfig is part of the storage.Provider interface.
+func (*storageProvider) ValidateConfig(cfg *storage.Config) error {
+	return nil
+}
+
+// Supports is part of the storage.Provider interface.
+func (*storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is part of the storage.Provider interface.
+func (*storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is part of the storage.Provider interface.
+func (*storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the storage.Provider interface.
+func (*storageProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is part of the storage.Provider interface.
+func (*storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// FilesystemSource is part of the storage.Provider interface.
+func (*storageProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// VolumeSource is part of the storage.Provider interface.
+func (p *storageProvider) VolumeSource(*storage.Config) (storage.VolumeSource, error) {
+	return &volumeSource{env: p.env}, nil
+}
+
+type volumeSource struct {
+	env *environ
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// volumeId returns the provider ID of the named volume on the host with
+// the given zone.
+func volumeId(zone, name string) string {
+	return zone + ":" + name
+}
+
+// parseVolumeId returns the zone and name of the volume with the given
+// provider ID.
+func parseVolumeId(volumeId string) (zone, name string, _ error) {
+	pos := strings.LastIndex(volumeId, ":")
+	if pos <= 0 || pos == len(volumeId)-1 {
+		return "", "", errors.NotValidf("volume ID %q", volumeId)
+	}
+	return volumeId[:pos], volumeId[pos+1:], nil
+}
+
+// volumeName returns the name of the pool volume for a storage volume.
+func (v *volumeSource) volumeName(tag string) string {
+	return v.env.namespace.Value(tag)
+}
+
+// volumeSerial returns the serial number exposed to the guest for the
+// named volume, from which the device link is derived.
+func (v *volumeSource) volumeSerial(name string) string {
+	serial := strings.TrimPrefix(name, v.env.namespace.Prefix())
+	if len(serial) > maxSerialLength {
+		serial = serial[len(serial)-maxSerialLength:]
+	}
+	return serial
+}
+
+func deviceLink(serial string) string {
+	return fmt.Sprintf("/dev/disk/by-id/virtio-%s", serial)
+}
+
+// CreateVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(ctx context.ProviderCallContext, params []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(params))
+	for i, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Volume, results[i].VolumeAttachment, results[i].Error = v.createVolume(ctx, p)
+	}
+	return results, nil
+}
+
+func (v *volumeSource) createVolume(ctx context.ProviderCallContext, p storage.VolumeParams) (*storage.Volume, *storage.VolumeAttachment, error) {
+	// The volume must be created in the pool of the instance's host.
+	insts, err := v.env.Instances(ctx, []instance.Id{p.Attachment.InstanceId})
+	if err != nil {
+		return nil, nil, errors.Annotatef(err, "finding instance %q", p.Attachment.InstanceId)
+	}
+	h := insts[0].(*environInstance).host
+	name := v.volumeName(p.Tag.String())
+	if err := h.createVolume(v.env.config().storagePool(), name, p.Size, "qcow2", ""); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	volume := &storage.Volume{
+		Tag: p.Tag,
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId:   volumeId(h.zone, name),
+			Size:       p.Size,
+			Persistent: true,
+		},
+	}
+	info, err := v.attachVolume(ctx, volume.VolumeId, string(p.Attachment.InstanceId))
+	if err != nil {
+		// The volume exists, so report it; the storage provisioner
+		// will retry the attachment.
+		logger.Warningf("attaching %q to %q: %v", name, p.Attachment.InstanceId, err)
+		return volume, nil, nil
+	}
+	attachment := &storage.VolumeAttachment{
+		Volume:               p.Tag,
+		Machine:              p.Attachment.Machine,
+		VolumeAttachmentInfo: *info,
+	}
+	return volume, attachment, nil
+}
+
+// ValidateVolumeParams is part of the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if params.Attachment == nil || params.Attachment.InstanceId == "" {
+		// The host, and so the pool, is decided by the instance.
+		return errors.NotSupportedf("creating unattached libvirt volumes")
+	}
+	return nil
+}
+
+// ListVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
+	prefix := v.volumeName("volume-")
+	var ids []string
+	for _, h := range v.env.hosts() {
+		names, err := h.volumes(v.env.config().storagePool())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, name := range names {
+			if strings.HasPrefix(name, prefix) {
+				ids = append(ids, volumeId(h.zone, name))
+			}
+		}
+	}
+	return ids, nil
+}
+
+// DescribeVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	for i, id := range volumeIds {
+		h, name, err := v.volumeHost(id)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		size, err := h.volumeCapacity(v.env.config().storagePool(), name)
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].VolumeInfo = &storage.VolumeInfo{
+			VolumeId:   id,
+			Size:       size,
+			Persistent: true,
+		}
+	}
+	return results, nil
+}
+
+// DestroyVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i, id := range volumeIds {
+		h, name, err := v.volumeHost(id)
+		if err != nil {
+			results[i] = err
+			continue
+		}
+		results[i] = h.deleteVolume(v.env.config().storagePool(), name)
+	}
+	return results, nil
+}
+
+// ReleaseVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) ReleaseVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i := range volumeIds {
+		results[i] = errors.NotSupportedf("releasing libvirt volumes")
+	}
+	return results, nil
+}
+
+// AttachVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(ctx context.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(params))
+	for i, p := range params {
+		info, err := v.attachVolume(ctx, p.VolumeId, string(p.InstanceId))
+		if err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].VolumeAttachment = &storage.VolumeAttachment{
+			Volume:               p.Volume,
+			Machine:              p.Machine,
+			VolumeAttachmentInfo: *info,
+		}
+	}
+	return results, nil
+}
+
+// attachVolume attaches the volume to the domain, unless it is already
+// attached. Volumes can only be attached to domains on the same host.
+func (v *volumeSource) attachVolume(ctx context.ProviderCallContext, volumeId, domainName string) (*storage.VolumeAttachmentInfo, error) {
+	h, name, err := v.volumeHost(volumeId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	path, err := h.volumePath(v.env.config().storagePool(), name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	devices, err := h.blockDevices(domainName)
+	if err != nil {
+		return nil, errors.Annotatef(err, "volume %q and instance %q must be on the same host", volumeId, domainName)
+	}
+	serial := v.volumeSerial(name)
+	info := &storage.VolumeAttachmentInfo{DeviceLink: deviceLink(serial)}
+	for _, source := range devices {
+		if source == path {
+			return info, nil
+		}
+	}
+	target, err := nextTarget(devices)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := h.attachDisk(domainName, path, target, serial); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return info, nil
+}
+
+// nextTarget returns the first unused virtio target device name.
+func nextTarget(devices map[string]string) (string, error) {
+	for c := 'b'; c <= 'z'; c++ {
+		target := fmt.Sprintf("vd%c", c)
+		if _, ok := devices[target]; !ok {
+			return target, nil
+		}
+	}
+	return "", errors.New("no free virtio target devices")
+}
+
+// DetachVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) DetachVolumes(ctx context.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(params))
+	for i, p := range params {
+		results[i] = v.detachVolume(p.VolumeId, string(p.InstanceId))
+	}
+	return results, nil
+}
+
+func (v *volumeSource) detachVolume(volumeId, domainName string) error {
+	h, name, err := v.volumeHost(volumeId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	path, err := h.volumePath(v.env.config().storagePool(), name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	devices, err := h.blockDevices(domainName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for target, source := range devices {
+		if source == path {
+			return errors.Trace(h.detachDisk(domainName, target))
+		}
+	}
+	// Not attached; nothing to do.
+	return nil
+}
+
+// volumeHost returns the host and pool volume name of the volume with
+// the given provider ID.
+func (v *volumeSource) volumeHost(volumeId string) (*host, string, error) {
+	zone, name, err := parseVolumeId(volumeId)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	h, err := v.env.host(zone)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	return h, name, nil
+}
@@ -0,0 +1,175 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/testing"
+)
+
+type storageSuite struct {
+	testing.BaseSuite
+
+	runner  *fakeRunner
+	env     *environ
+	source  storage.VolumeSource
+	callCtx context.ProviderCallContext
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.runner = newFakeRunner()
+	env, err := newEnviron(fakeCloudSpec(), newConfig(c, nil), s.runner.run)
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+	s.callCtx = context.NewCloudCallContext()
+
+	provider, err := env.StorageProvider(storageProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = provider.VolumeSource(nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestParseVolumeId(c *gc.C) {
+	zone, name, err := parseVolumeId("kvm1:juju-06f00d-volume-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(zone, gc.Equals, "kvm1")
+	c.Check(name, gc.Equals, "juju-06f00d-volume-0")
+
+	for _, id := range []string{"", "kvm1", ":name", "kvm1:"} {
+		_, _, err := parseVolumeId(id)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *storageSuite) TestNextTarget(c *gc.C) {
+	target, err := nextTarget(map[string]string{"vda": "root", "vdb": "seed"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(target, gc.Equals, "vdc")
+}
+
+func (s *storageSuite) TestVolumeSerial(c *gc.C) {
+	vs := s.source.(*volumeSource)
+	c.Check(vs.volumeSerial(s.env.namespace.Prefix()+"volume-0"), gc.Equals, "volume-0")
+	c.Check(vs.volumeSerial(s.env.namespace.Prefix()+"volume-1234567890-12345"), gc.Equals, "ume-1234567890-12345")
+}
+
+func (s *storageSuite) TestListVolumes(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["vol-list"] = `
+ Name                        Path
+------------------------------------------------------------------------------
+ juju-base-bionic-amd64      /var/lib/libvirt/images/juju-base-bionic-amd64
+ ` + prefix + `0-root   /var/lib/libvirt/images/` + prefix + `0-root
+ ` + prefix + `volume-0   /var/lib/libvirt/images/` + prefix + `volume-0
+`
+	ids, err := s.source.ListVolumes(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{"kvm1:" + prefix + "volume-0"})
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["list"] = " 1    " + prefix + "0   running\n"
+	s.runner.outputs["vol-path"] = "/var/lib/libvirt/images/" + prefix + "volume-0\n"
+	s.runner.outputs["domblklist"] = `
+ Target   Source
+------------------------------------------------
+ vda      /var/lib/libvirt/images/` + prefix + `0-root
+ vdb      /var/lib/libvirt/images/` + prefix + `0-seed
+`
+	results, err := s.source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: storageProviderType,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: instance.Id(prefix + "0"),
+			},
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "kvm1:"+prefix+"volume-0")
+	c.Assert(results[0].VolumeAttachment.DeviceLink, gc.Equals, "/dev/disk/by-id/virtio-volume-0")
+
+	c.Assert(s.runner.calls, jc.DeepEquals, []string{
+		"-q list --all",
+		"vol-create-as default " + prefix + "volume-0 1024M --format qcow2",
+		"vol-path --pool default " + prefix + "volume-0",
+		"domblklist " + prefix + "0",
+		"attach-disk " + prefix + "0 /var/lib/libvirt/images/" + prefix + "volume-0 vdc " +
+			"--targetbus virtio --subdriver qcow2 --serial volume-0 --persistent",
+	})
+}
+
+func (s *storageSuite) TestCreateVolumesUnattached(c *gc.C) {
+	results, err := s.source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: storageProviderType,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *storageSuite) TestAttachVolumesAlreadyAttached(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["vol-path"] = "/var/lib/libvirt/images/" + prefix + "volume-0\n"
+	s.runner.outputs["domblklist"] = `
+ Target   Source
+------------------------------------------------
+ vda      /var/lib/libvirt/images/` + prefix + `0-root
+ vdc      /var/lib/libvirt/images/` + prefix + `volume-0
+`
+	results, err := s.source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id(prefix + "0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "kvm1:" + prefix + "volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	for _, call := range s.runner.calls {
+		c.Check(call, gc.Not(jc.HasPrefix), "attach-disk")
+	}
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.runner.outputs["vol-path"] = "/var/lib/libvirt/images/" + prefix + "volume-0\n"
+	s.runner.outputs["domblklist"] = `
+ Target   Source
+------------------------------------------------
+ vdc      /var/lib/libvirt/images/` + prefix + `volume-0
+`
+	results, err := s.source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			InstanceId: instance.Id(prefix + "0"),
+		},
+		VolumeId: "kvm1:" + prefix + "volume-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0], jc.ErrorIsNil)
+	c.Assert(s.runner.calls[len(s.runner.calls)-1], gc.Equals, "detach-disk "+prefix+"0 vdc --persistent")
+}
+
+func (s *storageSuite) TestDestroyVolumesUnknownHost(c *gc.C) {
+	results, err := s.source.DestroyVolumes(s.callCtx, []string{"kvm9:juju-06f00d-volume-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0], gc.NotNil)
+}
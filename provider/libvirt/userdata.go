@@ -0,0 +1,26 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package libvirt
+
+import (
+	"github.com/juju/errors"
+	jujuos "github.com/juju/os"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/providerinit/renderers"
+)
+
+type libvirtRenderer struct{}
+
+// Render implements renderers.ProviderRenderer. The NoCloud datasource
+// reads user-data verbatim, so the YAML is not encoded.
+func (libvirtRenderer) Render(cfg cloudinit.CloudConfig, os jujuos.OSType) ([]byte, error) {
+	switch os {
+	case jujuos.Ubuntu, jujuos.CentOS, jujuos.OpenSUSE:
+		bytes, err := renderers.RenderYAML(cfg)
+		return bytes, errors.Trace(err)
+	default:
+		return nil, errors.Errorf("cannot encode userdata for OS %q", os)
+	}
+}
@@ -11,8 +11,20 @@ import (
 	"github.com/juju/juju/environs/config"
 )
 
+const (
+	// ProjectKey is the attribute name for the LXD project in which the
+	// model's containers are created.
+	ProjectKey = "project"
+)
+
 var (
-	configSchema                 = environschema.Fields{}
+	configSchema = environschema.Fields{
+		ProjectKey: {
+			Description: "The LXD project in which the model's containers are created, isolating them from those of other models. The project is created with the model if it does not exist, and deleted with the model if Juju created it and nothing else uses it. If empty, the default project is used.",
+			Type:        environschema.Tstring,
+			Immutable:   true,
+		},
+	}
 	configFields, configDefaults = func() (schema.Fields, schema.Defaults) {
 		fields, defaults, err := configSchema.ValidationSchema()
 		if err != nil {
@@ -62,6 +74,21 @@ func (c *environConfig) validate() error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	// There are currently no known extra fields for LXD
+	return nil
+}
+
+// project returns the LXD project for the model's containers, or an empty
+// string if the default project is used.
+func (c *environConfig) project() string {
+	project, _ := c.attrs[ProjectKey].(string)
+	return project
+}
+
+// validateConfigChange returns an error if the new config changes
+// attributes that cannot be changed once the model is created.
+func validateConfigChange(cfg, old *environConfig) error {
+	if cfg.project() != old.project() {
+		return errors.Errorf("cannot change %s from %q to %q", ProjectKey, old.project(), cfg.project())
+	}
 	return nil
 }
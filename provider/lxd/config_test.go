@@ -130,6 +130,10 @@ var newConfigTests = []configTestSpec{{
 	info:   "unknown field is not touched",
 	insert: testing.Attrs{"unknown-field": 12345},
 	expect: testing.Attrs{"unknown-field": 12345},
+}, {
+	info:   "project is set",
+	insert: testing.Attrs{"project": "juju-model"},
+	expect: testing.Attrs{"project": "juju-model"},
 }}
 
 func (s *configSuite) TestNewModelConfig(c *gc.C) {
@@ -212,6 +216,10 @@ var changeConfigTests = []configTestSpec{{
 	info:   "can insert unknown field",
 	insert: testing.Attrs{"unknown": "ignoti"},
 	expect: testing.Attrs{"unknown": "ignoti"},
+}, {
+	info:   "cannot change project",
+	insert: testing.Attrs{"project": "juju-other"},
+	err:    `cannot change project from "" to "juju-other"`,
 }}
 
 // TODO(wwitzel3) refactor this to the provider_test file.
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/lxc/lxd/shared/api"
 	"gopkg.in/juju/charm.v6"
@@ -46,6 +47,11 @@ type environ struct {
 	ecfgUnlocked   *environConfig
 	serverUnlocked Server
 
+	// defaultServerUnlocked is the server outside of the model's
+	// project, used to create and delete the project. It is the same
+	// as serverUnlocked if the model does not use a project.
+	defaultServerUnlocked Server
+
 	// profileMutex is used when writing profiles via the server.
 	profileMutex sync.Mutex
 }
@@ -83,10 +89,13 @@ func newEnviron(
 	return env, nil
 }
 
+// initProfile creates the model's profile. Profiles are shared with
+// the default project, so it is created there; the model's project may
+// not exist yet.
 func (env *environ) initProfile() error {
 	pName := env.profileName()
 
-	hasProfile, err := env.serverUnlocked.HasProfile(pName)
+	hasProfile, err := env.defaultServerUnlocked.HasProfile(pName)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -98,7 +107,7 @@ func (env *environ) initProfile() error {
 		"boot.autostart":   "true",
 		"security.nesting": "true",
 	}
-	return env.serverUnlocked.CreateProfileWithConfig(pName, cfg)
+	return env.defaultServerUnlocked.CreateProfileWithConfig(pName, cfg)
 }
 
 func (env *environ) profileName() string {
@@ -123,6 +132,9 @@ func (env *environ) SetConfig(cfg *config.Config) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	if err := validateConfigChange(ecfg, env.ecfgUnlocked); err != nil {
+		return errors.Annotate(err, "invalid config change")
+	}
 	env.ecfgUnlocked = ecfg
 	return nil
 }
@@ -137,10 +149,30 @@ func (env *environ) SetCloudSpec(spec environs.CloudSpec) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
+	env.defaultServerUnlocked = server
+	// The project itself is created by Create and Bootstrap, which
+	// fail if the server does not support projects.
+	if project := env.ecfgUnlocked.project(); project != "" {
+		if !server.ProjectSupported() {
+			logger.Warningf("LXD server does not support projects, ignoring project %q", project)
+		} else if server, err = useProjectServer(server, project); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	env.serverUnlocked = server
 	return env.initProfile()
 }
 
+// useProjectServer returns a Server whose operations are made within
+// the named project. It is a variable so that tests can replace it.
+var useProjectServer = func(server Server, project string) (Server, error) {
+	projectServer, err := server.UseProjectServer(project)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return projectServer, nil
+}
+
 func (env *environ) server() Server {
 	env.lock.Lock()
 	defer env.lock.Unlock()
@@ -148,6 +180,46 @@ func (env *environ) server() Server {
 	return env.serverUnlocked
 }
 
+func (env *environ) defaultServer() Server {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	return env.defaultServerUnlocked
+}
+
+func (env *environ) project() string {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	return env.ecfgUnlocked.project()
+}
+
+// ensureProject creates the model's LXD project, if it uses one.
+func (env *environ) ensureProject(ctx context.ProviderCallContext) error {
+	project := env.project()
+	if project == "" {
+		return nil
+	}
+	if err := env.defaultServer().EnsureProject(project); err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// deleteProject deletes the model's LXD project, if it uses one.
+func (env *environ) deleteProject(ctx context.ProviderCallContext) error {
+	project := env.project()
+	if project == "" {
+		return nil
+	}
+	if err := env.defaultServer().DeleteProject(project); err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return errors.Annotate(err, "destroying LXD project for model")
+	}
+	return nil
+}
+
 // Config returns the configuration data with which the env was created.
 func (env *environ) Config() *config.Config {
 	env.lock.Lock()
@@ -163,19 +235,31 @@ func (env *environ) PrepareForBootstrap(ctx environs.BootstrapContext, controlle
 }
 
 // Create implements environs.Environ.
-func (env *environ) Create(context.ProviderCallContext, environs.CreateParams) error {
-	return nil
+func (env *environ) Create(ctx context.ProviderCallContext, _ environs.CreateParams) error {
+	return errors.Trace(env.ensureProject(ctx))
 }
 
 // Bootstrap implements environs.Environ.
 func (env *environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.ProviderCallContext, params environs.BootstrapParams) (*environs.BootstrapResult, error) {
 	ctx.Infof("%s", bootstrapMessage)
+	if err := env.ensureProject(callCtx); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return env.base.BootstrapEnv(ctx, callCtx, params)
 }
 
 // Destroy shuts down all known machines and destroys the rest of the
 // known environment.
 func (env *environ) Destroy(ctx context.ProviderCallContext) error {
+	if err := env.destroyModel(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(env.deleteProject(ctx))
+}
+
+// destroyModel destroys the model's machines and filesystems, leaving
+// its LXD project in place.
+func (env *environ) destroyModel(ctx context.ProviderCallContext) error {
 	if err := env.base.DestroyEnv(ctx); err != nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return errors.Trace(err)
@@ -191,10 +275,11 @@ func (env *environ) Destroy(ctx context.ProviderCallContext) error {
 
 // DestroyController implements the Environ interface.
 func (env *environ) DestroyController(ctx context.ProviderCallContext, controllerUUID string) error {
-	if err := env.Destroy(ctx); err != nil {
+	if err := env.destroyModel(ctx); err != nil {
 		return errors.Trace(err)
 	}
-	if err := env.destroyHostedModelResources(controllerUUID); err != nil {
+	hostedProjects, err := env.destroyHostedModelResources(controllerUUID)
+	if err != nil {
 		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
 		return errors.Trace(err)
 	}
@@ -204,31 +289,84 @@ func (env *environ) DestroyController(ctx context.ProviderCallContext, controlle
 			return errors.Annotate(err, "destroying LXD filesystems for controller")
 		}
 	}
-	return nil
+	// DeleteProject leaves projects that are still in use, such as
+	// those shared with models of another controller, in place.
+	for _, project := range hostedProjects {
+		if err := env.defaultServer().DeleteProject(project); err != nil {
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			return errors.Annotatef(err, "destroying LXD project %q", project)
+		}
+	}
+	return errors.Trace(env.deleteProject(ctx))
+}
+
+// destroyHostedModelResources removes the containers of the controller's
+// hosted models. Hosted models keep their containers either in the default
+// project or in their own project, so the default project and every project
+// created by Juju are searched. The projects, other than this model's, that
+// held any of the containers are returned so that they can be deleted.
+func (env *environ) destroyHostedModelResources(controllerUUID string) ([]string, error) {
+	server := env.defaultServer()
+	projects := []string{""}
+	if server.ProjectSupported() {
+		names, err := server.JujuProjectNames()
+		if err != nil {
+			return nil, errors.Annotate(err, "listing LXD projects")
+		}
+		if project := env.project(); project != "" && !set.NewStrings(names...).Contains(project) {
+			projects = append(projects, project)
+		}
+		projects = append(projects, names...)
+	}
+
+	var hostedProjects []string
+	for _, project := range projects {
+		projectServer := server
+		if project != "" {
+			var err error
+			if projectServer, err = useProjectServer(server, project); err != nil {
+				return nil, errors.Annotatef(err, "using LXD project %q", project)
+			}
+		}
+		removed, err := env.destroyHostedModelContainers(projectServer, controllerUUID)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if removed && project != "" && project != env.project() {
+			hostedProjects = append(hostedProjects, project)
+		}
+	}
+	return hostedProjects, nil
 }
 
-func (env *environ) destroyHostedModelResources(controllerUUID string) error {
+// destroyHostedModelContainers removes the containers, visible to the
+// server, of the controller's hosted models and reports whether there
+// were any.
+func (env *environ) destroyHostedModelContainers(server Server, controllerUUID string) (bool, error) {
 	// Destroy all instances with juju-controller-uuid
 	// matching the specified UUID.
 	const prefix = "juju-"
-	instances, err := env.prefixedInstances(prefix)
+	containers, err := server.AliveContainers(prefix)
 	if err != nil {
-		return errors.Annotate(err, "listing instances")
+		return false, errors.Annotate(err, "listing instances")
 	}
 
 	var names []string
-	for _, inst := range instances {
-		if inst.container.Metadata(tags.JujuModel) == env.uuid {
+	for _, container := range containers {
+		if container.Metadata(tags.JujuModel) == env.uuid {
 			continue
 		}
-		if inst.container.Metadata(tags.JujuController) != controllerUUID {
+		if container.Metadata(tags.JujuController) != controllerUUID {
 			continue
 		}
-		names = append(names, string(inst.Id()))
+		names = append(names, container.Name)
 	}
 	logger.Debugf("removing instances: %v", names)
 
-	return errors.Trace(env.server().RemoveContainers(names))
+	if err := server.RemoveContainers(names); err != nil {
+		return false, errors.Trace(err)
+	}
+	return len(names) > 0, nil
 }
 
 // lxdAvailabilityZone wraps a LXD cluster member as an availability zone.
@@ -253,10 +253,17 @@ func (env *environ) getTargetServer(
 		return nil, errors.Trace(err)
 	}
 
+	server := env.server()
 	if p.nodeName == "" {
-		return env.server(), nil
+		// Without a placement directive, use the zone chosen by the
+		// provisioner, so that instances are spread across the members
+		// of a cluster.
+		if args.AvailabilityZone == "" || !server.IsClustered() {
+			return server, nil
+		}
+		return server.UseTargetServer(args.AvailabilityZone)
 	}
-	return env.server().UseTargetServer(p.nodeName)
+	return server.UseTargetServer(p.nodeName)
 }
 
 type lxdPlacement struct {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/cmd/modelcmd"
+	containerlxd "github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/core/lxdprofile"
 	"github.com/juju/juju/environs"
@@ -124,6 +125,43 @@ func (s *environSuite) TestBootstrapAPI(c *gc.C) {
 	}})
 }
 
+func (s *environSuite) TestBootstrapWithProject(c *gc.C) {
+	s.UpdateConfig(c, map[string]interface{}{"project": "juju-model"})
+	s.Stub.ResetCalls()
+
+	ctx := envtesting.BootstrapContext(c)
+	params := environs.BootstrapParams{
+		ControllerConfig:         coretesting.FakeControllerConfig(),
+		SupportedBootstrapSeries: coretesting.FakeSupportedJujuSeries,
+	}
+	_, err := s.Env.Bootstrap(ctx, s.callCtx, params)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{
+		{"EnsureProject", []interface{}{"juju-model"}},
+		{"Bootstrap", []interface{}{ctx, s.callCtx, params}},
+	})
+}
+
+func (s *environSuite) TestCreateWithProject(c *gc.C) {
+	s.UpdateConfig(c, map[string]interface{}{"project": "juju-model"})
+	s.Stub.ResetCalls()
+
+	err := s.Env.Create(s.callCtx, environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{
+		{"EnsureProject", []interface{}{"juju-model"}},
+	})
+}
+
+func (s *environSuite) TestCreateWithoutProject(c *gc.C) {
+	err := s.Env.Create(s.callCtx, environs.CreateParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckNoCalls(c)
+}
+
 func (s *environSuite) TestDestroy(c *gc.C) {
 	s.Client.Volumes = map[string][]api.StorageVolume{
 		"juju": {{
@@ -156,6 +194,23 @@ func (s *environSuite) TestDestroy(c *gc.C) {
 	})
 }
 
+func (s *environSuite) TestDestroyWithProject(c *gc.C) {
+	s.UpdateConfig(c, map[string]interface{}{"project": "juju-model"})
+	s.Stub.ResetCalls()
+
+	err := s.Env.Destroy(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Destroy", []interface{}{s.callCtx}},
+		{"StorageSupported", nil},
+		{"GetStoragePools", nil},
+		{"GetStoragePoolVolumes", []interface{}{"juju"}},
+		{"GetStoragePoolVolumes", []interface{}{"juju-zfs"}},
+		{"DeleteProject", []interface{}{"juju-model"}},
+	})
+}
+
 func (s *environSuite) TestDestroyInvalidCredentials(c *gc.C) {
 	c.Assert(s.invalidCredential, jc.IsFalse)
 	s.Client.Stub.SetErrors(errTestUnAuth)
@@ -242,6 +297,7 @@ func (s *environSuite) TestDestroyController(c *gc.C) {
 		{"GetStoragePools", nil},
 		{"GetStoragePoolVolumes", []interface{}{"juju"}},
 		{"GetStoragePoolVolumes", []interface{}{"juju-zfs"}},
+		{"ProjectSupported", nil},
 		{"AliveContainers", []interface{}{"juju-"}},
 		{"RemoveContainers", []interface{}{[]string{machine1.Name}}},
 		{"StorageSupported", nil},
@@ -252,6 +308,58 @@ func (s *environSuite) TestDestroyController(c *gc.C) {
 	})
 }
 
+func (s *environSuite) TestDestroyControllerHostedProjects(c *gc.C) {
+	s.UpdateConfig(c, map[string]interface{}{
+		"controller-uuid": s.Config.UUID(),
+	})
+	s.Client.StorageIsSupported = false
+	s.Client.ProjectIsSupported = true
+	s.Client.JujuProjects = []string{"juju-hosted", "juju-other"}
+	s.Stub.ResetCalls()
+
+	// machine1 is in a hosted model's project.
+	machine1 := s.NewContainer(c, "juju-hosted-machine-1")
+	machine1.Config["user.juju-model-uuid"] = "not-" + s.Config.UUID()
+	machine1.Config["user.juju-controller-uuid"] = s.Config.UUID()
+
+	// machine2 is in the project of a model of another controller.
+	machine2 := s.NewContainer(c, "juju-other-machine-2")
+	machine2.Config["user.juju-model-uuid"] = "not-" + s.Config.UUID()
+	machine2.Config["user.juju-controller-uuid"] = "not-" + s.Config.UUID()
+
+	projectContainers := map[string][]containerlxd.Container{
+		"juju-hosted": {*machine1},
+		"juju-other":  {*machine2},
+	}
+	s.PatchValue(lxd.UseProjectServer, func(_ lxd.Server, project string) (lxd.Server, error) {
+		s.Stub.AddCall("UseProjectServer", project)
+		return &lxd.StubClient{
+			Stub:       s.Stub,
+			Containers: projectContainers[project],
+		}, nil
+	})
+
+	err := s.Env.DestroyController(s.callCtx, s.Config.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Destroy", []interface{}{s.callCtx}},
+		{"StorageSupported", nil},
+		{"ProjectSupported", nil},
+		{"JujuProjectNames", nil},
+		{"AliveContainers", []interface{}{"juju-"}},
+		{"RemoveContainers", []interface{}{[]string{}}},
+		{"UseProjectServer", []interface{}{"juju-hosted"}},
+		{"AliveContainers", []interface{}{"juju-"}},
+		{"RemoveContainers", []interface{}{[]string{machine1.Name}}},
+		{"UseProjectServer", []interface{}{"juju-other"}},
+		{"AliveContainers", []interface{}{"juju-"}},
+		{"RemoveContainers", []interface{}{[]string{}}},
+		{"StorageSupported", nil},
+		{"DeleteProject", []interface{}{"juju-hosted"}},
+	})
+}
+
 func (s *environSuite) TestDestroyControllerInvalidCredentialsHostedModels(c *gc.C) {
 	c.Assert(s.invalidCredential, jc.IsFalse)
 	s.UpdateConfig(c, map[string]interface{}{
@@ -289,6 +397,7 @@ func (s *environSuite) TestDestroyControllerInvalidCredentialsHostedModels(c *gc
 		{"GetStoragePools", nil},
 		{"GetStoragePoolVolumes", []interface{}{"juju"}},
 		{"GetStoragePoolVolumes", []interface{}{"juju-zfs"}},
+		{"ProjectSupported", nil},
 		{"AliveContainers", []interface{}{"juju-"}},
 		{"RemoveContainers", []interface{}{[]string{}}},
 	})
@@ -298,6 +407,7 @@ func (s *environSuite) TestDestroyControllerInvalidCredentialsHostedModels(c *gc
 		"GetStoragePools",
 		"GetStoragePoolVolumes",
 		"GetStoragePoolVolumes",
+		"ProjectSupported",
 		"AliveContainers",
 		"RemoveContainers")
 }
@@ -339,6 +449,7 @@ func (s *environSuite) TestDestroyControllerInvalidCredentialsDestroyFilesystem(
 		{"GetStoragePools", nil},
 		{"GetStoragePoolVolumes", []interface{}{"juju"}},
 		{"GetStoragePoolVolumes", []interface{}{"juju-zfs"}},
+		{"ProjectSupported", nil},
 		{"AliveContainers", []interface{}{"juju-"}},
 		{"RemoveContainers", []interface{}{[]string{}}},
 		{"StorageSupported", nil},
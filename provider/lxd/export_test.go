@@ -16,6 +16,7 @@ var (
 	NewInstance           = newInstance
 	GetCertificates       = getCertificates
 	IsSupportedAPIVersion = isSupportedAPIVersion
+	UseProjectServer      = &useProjectServer
 )
 
 func NewProviderWithMocks(
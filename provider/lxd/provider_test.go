@@ -12,10 +12,13 @@ import (
 	gitjujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
+	"github.com/lxc/lxd/shared/api"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/yaml.v2"
 
 	"github.com/juju/juju/cloud"
+	containerlxd "github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/environs/testing"
@@ -457,6 +460,81 @@ func (s *providerSuite) TestValidateWithInvalidConfig(c *gc.C) {
 	c.Assert(err, gc.NotNil)
 }
 
+func (s *providerSuite) TestValidateProjectChange(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	deps := s.createProvider(ctrl)
+
+	config, err := s.Config.Apply(map[string]interface{}{
+		lxd.ProjectKey: "juju-other",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = deps.provider.Validate(config, s.Config)
+	c.Assert(err, gc.ErrorMatches, `invalid config change: cannot change project from "" to "juju-other"`)
+}
+
+func (s *providerSuite) TestOpenWithProject(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	deps := s.createProvider(ctrl)
+
+	svr := lxd.NewMockServer(ctrl)
+	projectSvr := lxdtesting.NewMockContainerServer(ctrl)
+	projectSvr.EXPECT().GetServer().Return(&api.Server{}, lxdtesting.ETag, nil)
+	jujuProjectSvr, err := containerlxd.NewServer(projectSvr)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The project is not created until the model is created or
+	// bootstrapped, and the profile is shared with the default project.
+	gomock.InOrder(
+		deps.factory.EXPECT().RemoteServer(gomock.Any()).Return(svr, nil),
+		svr.EXPECT().ProjectSupported().Return(true),
+		svr.EXPECT().UseProjectServer("juju-testmodel").Return(jujuProjectSvr, nil),
+		svr.EXPECT().HasProfile("juju-testmodel").Return(true, nil),
+	)
+
+	config, err := s.Config.Apply(map[string]interface{}{
+		lxd.ProjectKey: "juju-testmodel",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = environs.Open(deps.provider, environs.OpenParams{
+		Cloud:  lxdCloudSpec(),
+		Config: config,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) TestOpenWithProjectNotSupported(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	deps := s.createProvider(ctrl)
+
+	// The default project is used; creating or bootstrapping the
+	// model reports that projects are not supported.
+	svr := lxd.NewMockServer(ctrl)
+	gomock.InOrder(
+		deps.factory.EXPECT().RemoteServer(gomock.Any()).Return(svr, nil),
+		svr.EXPECT().ProjectSupported().Return(false),
+		svr.EXPECT().HasProfile("juju-testmodel").Return(true, nil),
+	)
+
+	config, err := s.Config.Apply(map[string]interface{}{
+		lxd.ProjectKey: "juju-testmodel",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = environs.Open(deps.provider, environs.OpenParams{
+		Cloud:  lxdCloudSpec(),
+		Config: config,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *providerSuite) TestCloudSchema(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -67,6 +67,9 @@ type Server interface {
 	GetStoragePoolVolume(pool string, volType string, name string) (*lxdapi.StorageVolume, string, error)
 	GetStoragePoolVolumes(pool string) (volumes []lxdapi.StorageVolume, err error)
 	CreateVolume(pool, name string, config map[string]string) error
+	CreateVolumeFromSnapshot(pool, name, sourcePool, snapshot string, config map[string]string) error
+	CreateVolumeSnapshot(pool, volume, snapshot string) error
+	DeleteVolumeSnapshot(pool, volume, snapshot string) error
 	UpdateStoragePoolVolume(pool string, volType string, name string, volume lxdapi.StorageVolumePut, ETag string) error
 	DeleteStoragePoolVolume(pool string, volType string, name string) (err error)
 	ServerCertificate() string
@@ -75,6 +78,12 @@ type Server interface {
 	GetNICsFromProfile(profName string) (map[string]map[string]string, error)
 	IsClustered() bool
 	UseTargetServer(name string) (*lxd.Server, error)
+	MoveVolume(pool, name, from, to string) error
+	ProjectSupported() bool
+	JujuProjectNames() ([]string, error)
+	EnsureProject(name string) error
+	DeleteProject(name string) error
+	UseProjectServer(name string) (*lxd.Server, error)
 	GetClusterMembers() (members []lxdapi.ClusterMember, err error)
 	Name() string
 }
@@ -148,6 +148,30 @@ func (mr *MockServerMockRecorder) CreateVolume(arg0, arg1, arg2 interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolume", reflect.TypeOf((*MockServer)(nil).CreateVolume), arg0, arg1, arg2)
 }
 
+// CreateVolumeFromSnapshot mocks base method
+func (m *MockServer) CreateVolumeFromSnapshot(arg0, arg1, arg2, arg3 string, arg4 map[string]string) error {
+	ret := m.ctrl.Call(m, "CreateVolumeFromSnapshot", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVolumeFromSnapshot indicates an expected call of CreateVolumeFromSnapshot
+func (mr *MockServerMockRecorder) CreateVolumeFromSnapshot(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolumeFromSnapshot", reflect.TypeOf((*MockServer)(nil).CreateVolumeFromSnapshot), arg0, arg1, arg2, arg3, arg4)
+}
+
+// CreateVolumeSnapshot mocks base method
+func (m *MockServer) CreateVolumeSnapshot(arg0, arg1, arg2 string) error {
+	ret := m.ctrl.Call(m, "CreateVolumeSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVolumeSnapshot indicates an expected call of CreateVolumeSnapshot
+func (mr *MockServerMockRecorder) CreateVolumeSnapshot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVolumeSnapshot", reflect.TypeOf((*MockServer)(nil).CreateVolumeSnapshot), arg0, arg1, arg2)
+}
+
 // DeleteCertificate mocks base method
 func (m *MockServer) DeleteCertificate(arg0 string) error {
 	ret := m.ctrl.Call(m, "DeleteCertificate", arg0)
@@ -172,6 +196,18 @@ func (mr *MockServerMockRecorder) DeleteProfile(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockServer)(nil).DeleteProfile), arg0)
 }
 
+// DeleteProject mocks base method
+func (m *MockServer) DeleteProject(arg0 string) error {
+	ret := m.ctrl.Call(m, "DeleteProject", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProject indicates an expected call of DeleteProject
+func (mr *MockServerMockRecorder) DeleteProject(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProject", reflect.TypeOf((*MockServer)(nil).DeleteProject), arg0)
+}
+
 // DeleteStoragePoolVolume mocks base method
 func (m *MockServer) DeleteStoragePoolVolume(arg0, arg1, arg2 string) error {
 	ret := m.ctrl.Call(m, "DeleteStoragePoolVolume", arg0, arg1, arg2)
@@ -184,6 +220,18 @@ func (mr *MockServerMockRecorder) DeleteStoragePoolVolume(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStoragePoolVolume", reflect.TypeOf((*MockServer)(nil).DeleteStoragePoolVolume), arg0, arg1, arg2)
 }
 
+// DeleteVolumeSnapshot mocks base method
+func (m *MockServer) DeleteVolumeSnapshot(arg0, arg1, arg2 string) error {
+	ret := m.ctrl.Call(m, "DeleteVolumeSnapshot", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVolumeSnapshot indicates an expected call of DeleteVolumeSnapshot
+func (mr *MockServerMockRecorder) DeleteVolumeSnapshot(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVolumeSnapshot", reflect.TypeOf((*MockServer)(nil).DeleteVolumeSnapshot), arg0, arg1, arg2)
+}
+
 // EnableHTTPSListener mocks base method
 func (m *MockServer) EnableHTTPSListener() error {
 	ret := m.ctrl.Call(m, "EnableHTTPSListener")
@@ -208,6 +256,18 @@ func (mr *MockServerMockRecorder) EnsureDefaultStorage(arg0, arg1 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureDefaultStorage", reflect.TypeOf((*MockServer)(nil).EnsureDefaultStorage), arg0, arg1)
 }
 
+// EnsureProject mocks base method
+func (m *MockServer) EnsureProject(arg0 string) error {
+	ret := m.ctrl.Call(m, "EnsureProject", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnsureProject indicates an expected call of EnsureProject
+func (mr *MockServerMockRecorder) EnsureProject(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnsureProject", reflect.TypeOf((*MockServer)(nil).EnsureProject), arg0)
+}
+
 // FilterContainers mocks base method
 func (m *MockServer) FilterContainers(arg0 string, arg1 ...string) ([]lxd.Container, error) {
 	varargs := []interface{}{arg0}
@@ -424,6 +484,19 @@ func (mr *MockServerMockRecorder) IsClustered() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsClustered", reflect.TypeOf((*MockServer)(nil).IsClustered))
 }
 
+// JujuProjectNames mocks base method
+func (m *MockServer) JujuProjectNames() ([]string, error) {
+	ret := m.ctrl.Call(m, "JujuProjectNames")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JujuProjectNames indicates an expected call of JujuProjectNames
+func (mr *MockServerMockRecorder) JujuProjectNames() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JujuProjectNames", reflect.TypeOf((*MockServer)(nil).JujuProjectNames))
+}
+
 // LocalBridgeName mocks base method
 func (m *MockServer) LocalBridgeName() string {
 	ret := m.ctrl.Call(m, "LocalBridgeName")
@@ -436,6 +509,18 @@ func (mr *MockServerMockRecorder) LocalBridgeName() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LocalBridgeName", reflect.TypeOf((*MockServer)(nil).LocalBridgeName))
 }
 
+// MoveVolume mocks base method
+func (m *MockServer) MoveVolume(arg0, arg1, arg2, arg3 string) error {
+	ret := m.ctrl.Call(m, "MoveVolume", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveVolume indicates an expected call of MoveVolume
+func (mr *MockServerMockRecorder) MoveVolume(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveVolume", reflect.TypeOf((*MockServer)(nil).MoveVolume), arg0, arg1, arg2, arg3)
+}
+
 // Name mocks base method
 func (m *MockServer) Name() string {
 	ret := m.ctrl.Call(m, "Name")
@@ -448,6 +533,18 @@ func (mr *MockServerMockRecorder) Name() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockServer)(nil).Name))
 }
 
+// ProjectSupported mocks base method
+func (m *MockServer) ProjectSupported() bool {
+	ret := m.ctrl.Call(m, "ProjectSupported")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// ProjectSupported indicates an expected call of ProjectSupported
+func (mr *MockServerMockRecorder) ProjectSupported() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProjectSupported", reflect.TypeOf((*MockServer)(nil).ProjectSupported))
+}
+
 // RemoveContainer mocks base method
 func (m *MockServer) RemoveContainer(arg0 string) error {
 	ret := m.ctrl.Call(m, "RemoveContainer", arg0)
@@ -568,6 +665,19 @@ func (mr *MockServerMockRecorder) UpdateStoragePoolVolume(arg0, arg1, arg2, arg3
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStoragePoolVolume", reflect.TypeOf((*MockServer)(nil).UpdateStoragePoolVolume), arg0, arg1, arg2, arg3, arg4)
 }
 
+// UseProjectServer mocks base method
+func (m *MockServer) UseProjectServer(arg0 string) (*lxd.Server, error) {
+	ret := m.ctrl.Call(m, "UseProjectServer", arg0)
+	ret0, _ := ret[0].(*lxd.Server)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseProjectServer indicates an expected call of UseProjectServer
+func (mr *MockServerMockRecorder) UseProjectServer(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseProjectServer", reflect.TypeOf((*MockServer)(nil).UseProjectServer), arg0)
+}
+
 // UseTargetServer mocks base method
 func (m *MockServer) UseTargetServer(arg0 string) (*lxd.Server, error) {
 	ret := m.ctrl.Call(m, "UseTargetServer", arg0)
@@ -217,6 +217,11 @@ func ensureLXDStoragePool(env *environ, cfg *lxdStorageConfig) error {
 		)
 	}
 	for k, v := range cfg.attrs {
+		// Config specific to cluster members is not reported
+		// for the pool as a whole, so cannot be verified.
+		if lxd.IsClusterMemberPoolConfig(k) && server.IsClustered() {
+			continue
+		}
 		if haveV, ok := pool.Config[k]; !ok || haveV != v {
 			return errors.Errorf(
 				`LXD storage pool %q exists, with conflicting config attribute %q=%q. Specify an alternative pool name via the "lxd-pool" attribute.`,
@@ -239,7 +244,7 @@ func (s *lxdFilesystemSource) CreateFilesystems(ctx context.ProviderCallContext,
 			results[i].Error = err
 			continue
 		}
-		filesystem, err := s.createFilesystem(arg)
+		filesystem, err := s.createFilesystem(ctx, arg)
 		if err != nil {
 			results[i].Error = err
 			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
@@ -251,6 +256,7 @@ func (s *lxdFilesystemSource) CreateFilesystems(ctx context.ProviderCallContext,
 }
 
 func (s *lxdFilesystemSource) createFilesystem(
+	ctx context.ProviderCallContext,
 	arg storage.FilesystemParams,
 ) (*storage.Filesystem, error) {
 
@@ -281,7 +287,21 @@ func (s *lxdFilesystemSource) createFilesystem(
 		config["size"] = fmt.Sprintf("%dMiB", arg.Size)
 	}
 
-	if err := s.env.server().CreateVolume(cfg.lxdPool, volumeName, config); err != nil {
+	server, err := s.volumeServer(ctx, arg.Attachment)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if arg.SnapshotId != "" {
+		// The snapshot ID is the filesystem ID of the snapshotted
+		// volume, with the snapshot name appended.
+		sourcePool, snapshot, err := parseFilesystemId(arg.SnapshotId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if err := server.CreateVolumeFromSnapshot(cfg.lxdPool, volumeName, sourcePool, snapshot, config); err != nil {
+			return nil, errors.Annotate(err, "creating volume")
+		}
+	} else if err := server.CreateVolume(cfg.lxdPool, volumeName, config); err != nil {
 		return nil, errors.Annotate(err, "creating volume")
 	}
 
@@ -295,6 +315,38 @@ func (s *lxdFilesystemSource) createFilesystem(
 	return &filesystem, nil
 }
 
+// volumeServer returns the server on which to create a volume. If the
+// volume is to be attached to an instance in a cluster, this targets the
+// cluster member hosting the instance, so that volumes in pools local to
+// each member are created alongside the instance.
+func (s *lxdFilesystemSource) volumeServer(
+	ctx context.ProviderCallContext, attachment *storage.FilesystemAttachmentParams,
+) (Server, error) {
+	server := s.env.server()
+	if attachment == nil || attachment.InstanceId == "" {
+		return server, nil
+	}
+	instances, err := s.env.Instances(ctx, []instance.Id{attachment.InstanceId})
+	if err == environs.ErrNoInstances {
+		// The instance is not yet provisioned.
+		return server, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	location := instances[0].(*environInstance).container.Location
+	if !isClusterMember(location) {
+		return server, nil
+	}
+	return server.UseTargetServer(location)
+}
+
+// isClusterMember reports whether the location of a container or volume
+// names a cluster member. Locations on servers that are not clustered are
+// empty, or "none".
+func isClusterMember(location string) bool {
+	return location != "" && location != "none"
+}
+
 func makeFilesystemId(cfg *lxdStorageConfig, volumeName string) string {
 	// We need to include the LXD pool name in the filesystem ID,
 	// so that we can map it back to a volume.
@@ -471,6 +523,10 @@ func (s *lxdFilesystemSource) attachFilesystem(
 		return nil, errors.Trace(err)
 	}
 
+	if err := s.ensureVolumeLocation(poolName, volumeName, inst); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	deviceName := arg.Filesystem.String()
 	if err = inst.container.AddDisk(deviceName, arg.Path, volumeName, poolName, arg.ReadOnly); err != nil {
 		return nil, errors.Trace(err)
@@ -491,6 +547,43 @@ func (s *lxdFilesystemSource) attachFilesystem(
 	return &filesystemAttachment, nil
 }
 
+// ensureVolumeLocation moves the volume to the cluster member hosting the
+// instance, if the volume is in a pool local to a different member.
+// Volumes in pools shared by the cluster, such as Ceph pools, have no
+// member location and are never moved.
+func (s *lxdFilesystemSource) ensureVolumeLocation(poolName, volumeName string, inst *environInstance) error {
+	target := inst.container.Location
+	if !isClusterMember(target) {
+		return nil
+	}
+
+	location, err := s.volumeLocation(poolName, volumeName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !isClusterMember(location) || location == target {
+		return nil
+	}
+	logger.Infof("moving volume %q from cluster member %q to %q", volumeName, location, target)
+	return errors.Trace(s.env.server().MoveVolume(poolName, volumeName, location, target))
+}
+
+// volumeLocation returns the location of the named custom volume in the
+// input pool. The location is a cluster member only for volumes in pools
+// local to each member.
+func (s *lxdFilesystemSource) volumeLocation(poolName, volumeName string) (string, error) {
+	volumes, err := s.env.server().GetStoragePoolVolumes(poolName)
+	if err != nil {
+		return "", errors.Annotatef(err, "listing volumes in LXD storage pool %q", poolName)
+	}
+	for _, volume := range volumes {
+		if volume.Name == volumeName && volume.Type == storagePoolVolumeType {
+			return volume.Location, nil
+		}
+	}
+	return "", errors.NotFoundf("volume %q in LXD storage pool %q", volumeName, poolName)
+}
+
 // DetachFilesystems is specified on the storage.FilesystemSource interface.
 func (s *lxdFilesystemSource) DetachFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemAttachmentParams) ([]error, error) {
 	var instanceIds []instance.Id
@@ -543,6 +636,153 @@ func (s *lxdFilesystemSource) detachFilesystem(
 	return errors.Trace(s.env.server().WriteContainer(inst.container))
 }
 
+// CreateFilesystemSnapshots is part of the storage.FilesystemSnapshotter
+// interface.
+func (s *lxdFilesystemSource) CreateFilesystemSnapshots(
+	ctx context.ProviderCallContext,
+	args []storage.FilesystemSnapshotParams,
+) ([]storage.CreateSnapshotsResult, error) {
+	results := make([]storage.CreateSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshotId, err := s.createFilesystemSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(
+				err, "creating snapshot of %s", names.ReadableString(arg.Filesystem),
+			)
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+	}
+	return results, nil
+}
+
+func (s *lxdFilesystemSource) createFilesystemSnapshot(arg storage.FilesystemSnapshotParams) (string, error) {
+	lxdPool, volumeName, err := parseFilesystemId(arg.FilesystemId)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	// Volumes in pools local to a cluster member must
+	// be snapshotted by the member hosting the volume.
+	server := s.env.server()
+	location, err := s.volumeLocation(lxdPool, volumeName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if isClusterMember(location) {
+		if server, err = server.UseTargetServer(location); err != nil {
+			return "", errors.Trace(err)
+		}
+	}
+
+	snapshotName := "snapshot-" + strings.Replace(arg.Id, "/", "-", -1)
+	if err := server.CreateVolumeSnapshot(lxdPool, volumeName, snapshotName); err != nil {
+		return "", errors.Trace(err)
+	}
+	return arg.FilesystemId + "/" + snapshotName, nil
+}
+
+// DeleteFilesystemSnapshots is part of the storage.FilesystemSnapshotter
+// interface.
+func (s *lxdFilesystemSource) DeleteFilesystemSnapshots(
+	ctx context.ProviderCallContext,
+	snapshotIds []string,
+) ([]error, error) {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if err := s.deleteFilesystemSnapshot(snapshotId); err != nil {
+			results[i] = errors.Annotatef(err, "deleting snapshot %q", snapshotId)
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		}
+	}
+	return results, nil
+}
+
+func (s *lxdFilesystemSource) deleteFilesystemSnapshot(snapshotId string) error {
+	i := strings.LastIndex(snapshotId, "/")
+	if i < 0 {
+		return errors.Errorf(
+			"invalid snapshot ID %q; expected ID in format <lxd-pool>:<volume-name>/<snapshot-name>", snapshotId,
+		)
+	}
+	lxdPool, volumeName, err := parseFilesystemId(snapshotId[:i])
+	if err != nil {
+		return errors.Trace(err)
+	}
+	snapshotName := snapshotId[i+1:]
+
+	// Snapshots are deleted along with their volumes, and snapshots
+	// of volumes in pools local to a cluster member must be deleted
+	// by the member hosting the volume.
+	server := s.env.server()
+	location, err := s.volumeLocation(lxdPool, volumeName)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if isClusterMember(location) {
+		if server, err = server.UseTargetServer(location); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(server.DeleteVolumeSnapshot(lxdPool, volumeName, snapshotName))
+}
+
+// ResizeFilesystems is part of the storage.FilesystemResizer interface.
+func (s *lxdFilesystemSource) ResizeFilesystems(
+	ctx context.ProviderCallContext,
+	args []storage.FilesystemResizeParams,
+) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(args))
+	for i, arg := range args {
+		if err := s.resizeFilesystem(arg); err != nil {
+			results[i].Error = errors.Annotatef(
+				err, "resizing %s", names.ReadableString(arg.Filesystem),
+			)
+			common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+			continue
+		}
+		results[i].Size = arg.Size
+	}
+	return results, nil
+}
+
+func (s *lxdFilesystemSource) resizeFilesystem(arg storage.FilesystemResizeParams) error {
+	lxdPool, volumeName, err := parseFilesystemId(arg.FilesystemId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	// Volumes in pools local to a cluster member must
+	// be updated by the member hosting the volume.
+	server := s.env.server()
+	location, err := s.volumeLocation(lxdPool, volumeName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if isClusterMember(location) {
+		if server, err = server.UseTargetServer(location); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	volume, eTag, err := server.GetStoragePoolVolume(lxdPool, storagePoolVolumeType, volumeName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Volumes created in pools whose driver rejects the size
+	// attribute (e.g. "dir") have no size to grow.
+	if volume.Config["size"] == "" {
+		return errors.NotSupportedf("resizing volumes in LXD storage pool %q", lxdPool)
+	}
+	volume.Config["size"] = fmt.Sprintf("%dMiB", arg.Size)
+	return errors.Trace(server.UpdateStoragePoolVolume(
+		lxdPool, storagePoolVolumeType, volumeName, volume.Writable(), eTag,
+	))
+}
+
 // ImportFilesystem is part of the storage.FilesystemImporter interface.
 func (s *lxdFilesystemSource) ImportFilesystem(
 	callCtx context.ProviderCallContext,
@@ -4,6 +4,7 @@
 package lxd_test
 
 import (
+	"github.com/golang/mock/gomock"
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -12,6 +13,7 @@ import (
 	"gopkg.in/juju/names.v3"
 
 	containerlxd "github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
 	"github.com/juju/juju/environs/context"
 	"github.com/juju/juju/provider/lxd"
 	"github.com/juju/juju/storage"
@@ -143,6 +145,36 @@ func (s *storageSuite) TestCreateFilesystems(c *gc.C) {
 	})
 }
 
+func (s *storageSuite) TestCreateFilesystemsFromSnapshot(c *gc.C) {
+	source := s.filesystemSource(c, "source")
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:        names.NewFilesystemTag("1"),
+		Provider:   "lxd",
+		Size:       1024,
+		SnapshotId: "radiance:juju-f75cba-filesystem-0/snapshot-2",
+		Attributes: map[string]interface{}{
+			"lxd-pool": "radiance",
+			"driver":   "btrfs",
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Filesystem, jc.DeepEquals, &storage.Filesystem{
+		Tag: names.NewFilesystemTag("1"),
+		FilesystemInfo: storage.FilesystemInfo{
+			FilesystemId: "radiance:juju-f75cba-filesystem-1",
+			Size:         1024,
+		},
+	})
+
+	s.Stub.CheckCallNames(c, "CreatePool", "CreateVolumeFromSnapshot")
+	s.Stub.CheckCall(c, 1, "CreateVolumeFromSnapshot",
+		"radiance", "juju-f75cba-filesystem-1", "radiance", "juju-f75cba-filesystem-0/snapshot-2",
+		map[string]string{"size": "1024MiB"},
+	)
+}
+
 func (s *storageSuite) TestCreateFilesystemsPoolExists(c *gc.C) {
 	s.Stub.SetErrors(errors.New("pool already exists"))
 	source := s.filesystemSource(c, "source")
@@ -377,6 +409,77 @@ func (s *storageSuite) TestAttachFilesystems(c *gc.C) {
 	}})
 }
 
+func (s *storageSuite) TestAttachFilesystemsMovesVolumeToInstanceMember(c *gc.C) {
+	container := s.NewContainer(c, "inst-0")
+	container.Location = "node02"
+	s.Client.Containers = []containerlxd.Container{*container}
+	s.Client.Volumes = map[string][]api.StorageVolume{
+		"pool": {{
+			Name:     "filesystem-0",
+			Type:     "custom",
+			Location: "node01",
+		}},
+	}
+
+	source := s.filesystemSource(c, "pool")
+	results, err := source.AttachFilesystems(s.callCtx, []storage.FilesystemAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   "lxd",
+			Machine:    names.NewMachineTag("123"),
+			InstanceId: "inst-0",
+		},
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "pool:filesystem-0",
+		Path:         "/mnt/path",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{{
+		"AliveContainers",
+		[]interface{}{"juju-f75cba-"},
+	}, {
+		"GetStoragePoolVolumes",
+		[]interface{}{"pool"},
+	}, {
+		"MoveVolume",
+		[]interface{}{"pool", "filesystem-0", "node01", "node02"},
+	}, {
+		"WriteContainer",
+		[]interface{}{&s.Client.Containers[0]},
+	}})
+}
+
+func (s *storageSuite) TestAttachFilesystemsVolumeOnInstanceMember(c *gc.C) {
+	container := s.NewContainer(c, "inst-0")
+	container.Location = "node02"
+	s.Client.Containers = []containerlxd.Container{*container}
+	s.Client.Volumes = map[string][]api.StorageVolume{
+		"pool": {{
+			Name:     "filesystem-0",
+			Type:     "custom",
+			Location: "node02",
+		}},
+	}
+
+	source := s.filesystemSource(c, "pool")
+	results, err := source.AttachFilesystems(s.callCtx, []storage.FilesystemAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   "lxd",
+			Machine:    names.NewMachineTag("123"),
+			InstanceId: "inst-0",
+		},
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "pool:filesystem-0",
+		Path:         "/mnt/path",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	s.Stub.CheckCallNames(c, "AliveContainers", "GetStoragePoolVolumes", "WriteContainer")
+}
+
 func (s *storageSuite) TestAttachFilesystemsInvalidCredentialsInstanceError(c *gc.C) {
 	c.Assert(s.invalidCredential, jc.IsFalse)
 	s.Client.Stub.SetErrors(errTestUnAuth)
@@ -555,6 +658,145 @@ func (s *storageSuite) TestDetachFilesystemsInvalidCredentialsDetachFilesystem(c
 	c.Assert(results[0], gc.ErrorMatches, ".*not authorized")
 }
 
+func (s *storageSuite) TestCreateFilesystemSnapshots(c *gc.C) {
+	source := s.filesystemSource(c, "pool")
+	c.Assert(source, gc.Implements, new(storage.FilesystemSnapshotter))
+	snapshotter := source.(storage.FilesystemSnapshotter)
+
+	s.Client.Volumes = map[string][]api.StorageVolume{
+		"pool": {{
+			Name:     "filesystem-0",
+			Type:     "custom",
+			Location: "none",
+		}},
+	}
+
+	results, err := snapshotter.CreateFilesystemSnapshots(s.callCtx, []storage.FilesystemSnapshotParams{{
+		Id:           "2",
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "pool:filesystem-0",
+	}, {
+		Id:           "3",
+		Filesystem:   names.NewFilesystemTag("1"),
+		FilesystemId: "pool:filesystem-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.CreateSnapshotsResult{
+		SnapshotId: "pool:filesystem-0/snapshot-2",
+	})
+	c.Assert(results[1].Error, gc.ErrorMatches,
+		`creating snapshot of filesystem 1: volume "filesystem-1" in LXD storage pool "pool" not found`)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"GetStoragePoolVolumes", []interface{}{"pool"}},
+		{"CreateVolumeSnapshot", []interface{}{"pool", "filesystem-0", "snapshot-2"}},
+		{"GetStoragePoolVolumes", []interface{}{"pool"}},
+	})
+}
+
+func (s *storageSuite) TestCreateFilesystemSnapshotsInvalidCredentials(c *gc.C) {
+	c.Assert(s.invalidCredential, jc.IsFalse)
+	source := s.filesystemSource(c, "pool")
+	snapshotter := source.(storage.FilesystemSnapshotter)
+
+	s.Client.Stub.SetErrors(errTestUnAuth)
+	results, err := snapshotter.CreateFilesystemSnapshots(s.callCtx, []storage.FilesystemSnapshotParams{{
+		Id:           "2",
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "pool:filesystem-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, ".*not authorized")
+	c.Assert(s.invalidCredential, jc.IsTrue)
+}
+
+func (s *storageSuite) TestDeleteFilesystemSnapshots(c *gc.C) {
+	source := s.filesystemSource(c, "pool")
+	snapshotter := source.(storage.FilesystemSnapshotter)
+
+	s.Client.Volumes = map[string][]api.StorageVolume{
+		"pool": {{
+			Name:     "filesystem-0",
+			Type:     "custom",
+			Location: "none",
+		}},
+	}
+
+	results, err := snapshotter.DeleteFilesystemSnapshots(s.callCtx, []string{
+		"pool:filesystem-0/snapshot-2",
+		"pool:filesystem-1/snapshot-3",
+		"pool:filesystem-0",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.ErrorIsNil)
+	// The snapshots of a deleted volume are deleted with it.
+	c.Assert(results[1], jc.ErrorIsNil)
+	c.Assert(results[2], gc.ErrorMatches, `deleting snapshot "pool:filesystem-0": invalid snapshot ID .*`)
+
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"GetStoragePoolVolumes", []interface{}{"pool"}},
+		{"DeleteVolumeSnapshot", []interface{}{"pool", "filesystem-0", "snapshot-2"}},
+		{"GetStoragePoolVolumes", []interface{}{"pool"}},
+	})
+}
+
+func (s *storageSuite) TestResizeFilesystems(c *gc.C) {
+	source := s.filesystemSource(c, "pool")
+	c.Assert(source, gc.Implements, new(storage.FilesystemResizer))
+	resizer := source.(storage.FilesystemResizer)
+
+	s.Client.Volumes = map[string][]api.StorageVolume{
+		"pool": {{
+			Name:     "filesystem-0",
+			Type:     "custom",
+			Location: "none",
+			StorageVolumePut: api.StorageVolumePut{
+				Config: map[string]string{
+					"size": "1024MiB",
+				},
+			},
+		}, {
+			Name:     "filesystem-1",
+			Type:     "custom",
+			Location: "none",
+			StorageVolumePut: api.StorageVolumePut{
+				Config: map[string]string{},
+			},
+		}},
+	}
+
+	results, err := resizer.ResizeFilesystems(s.callCtx, []storage.FilesystemResizeParams{{
+		Filesystem:   names.NewFilesystemTag("0"),
+		FilesystemId: "pool:filesystem-0",
+		Size:         2048,
+	}, {
+		Filesystem:   names.NewFilesystemTag("1"),
+		FilesystemId: "pool:filesystem-1",
+		Size:         2048,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 2)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeResult{Size: 2048})
+	c.Assert(results[1].Error, gc.ErrorMatches,
+		`resizing filesystem 1: resizing volumes in LXD storage pool "pool" not supported`)
+
+	update := api.StorageVolumePut{
+		Config: map[string]string{
+			"size": "2048MiB",
+		},
+	}
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"GetStoragePoolVolumes", []interface{}{"pool"}},
+		{"GetStoragePoolVolume", []interface{}{"pool", "custom", "filesystem-0"}},
+		{"UpdateStoragePoolVolume", []interface{}{"pool", "custom", "filesystem-0", update, "eTag"}},
+		{"GetStoragePoolVolumes", []interface{}{"pool"}},
+		{"GetStoragePoolVolume", []interface{}{"pool", "custom", "filesystem-1"}},
+	})
+}
+
 func (s *storageSuite) TestImportFilesystem(c *gc.C) {
 	source := s.filesystemSource(c, "pool")
 	c.Assert(source, gc.Implements, new(storage.FilesystemImporter))
@@ -637,3 +879,71 @@ func (s *storageSuite) TestImportFilesystemInvalidCredentialsUpdatePool(c *gc.C)
 	c.Assert(s.invalidCredential, jc.IsTrue)
 	c.Assert(info, jc.DeepEquals, storage.FilesystemInfo{})
 }
+
+type storageClusterSuite struct {
+	lxd.EnvironSuite
+}
+
+var _ = gc.Suite(&storageClusterSuite{})
+
+func (s *storageClusterSuite) TestCreateFilesystemsOnInstanceMember(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+	svr := lxd.NewMockServer(ctrl)
+
+	target := lxdtesting.NewMockContainerServer(ctrl)
+	tExp := target.EXPECT()
+	tExp.GetServer().Return(&api.Server{}, lxdtesting.ETag, nil)
+	tExp.CreateStoragePoolVolume("radiance", api.StorageVolumesPost{
+		Name: "juju-f75cba-filesystem-0",
+		Type: "custom",
+		StorageVolumePut: api.StorageVolumePut{
+			Config: map[string]string{"size": "1024MiB"},
+		},
+	}).Return(nil)
+
+	jujuTarget, err := containerlxd.NewServer(target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	containers := []containerlxd.Container{{
+		Container: api.Container{Name: "juju-f75cba-0", Location: "node02"},
+	}}
+
+	sExp := svr.EXPECT()
+	gomock.InOrder(
+		sExp.StorageSupported().Return(true),
+		sExp.CreatePool("radiance", "btrfs", nil).Return(nil),
+		sExp.AliveContainers("juju-f75cba-").Return(containers, nil),
+		sExp.UseTargetServer("node02").Return(jujuTarget, nil),
+	)
+
+	env := s.NewEnviron(c, svr, nil)
+	provider, err := env.StorageProvider("lxd")
+	c.Assert(err, jc.ErrorIsNil)
+	storageConfig, err := storage.NewConfig("radiance", "lxd", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := provider.FilesystemSource(storageConfig)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := source.CreateFilesystems(context.NewCloudCallContext(), []storage.FilesystemParams{{
+		Tag:      names.NewFilesystemTag("0"),
+		Provider: "lxd",
+		Size:     1024,
+		Attributes: map[string]interface{}{
+			"lxd-pool": "radiance",
+			"driver":   "btrfs",
+		},
+		Attachment: &storage.FilesystemAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Provider:   "lxd",
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: "juju-f75cba-0",
+			},
+			Filesystem: names.NewFilesystemTag("0"),
+			Path:       "/mnt/path",
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+}
@@ -338,6 +338,7 @@ func (s *BaseSuite) SetUpTest(c *gc.C) {
 
 	// Patch out all expensive external deps.
 	s.Env.serverUnlocked = s.Client
+	s.Env.defaultServerUnlocked = s.Client
 	s.Env.base = s.Common
 }
 
@@ -366,7 +367,9 @@ func NewBaseConfig(c *gc.C) *config.Config {
 	return cfg
 }
 
-type ConfigValues struct{}
+type ConfigValues struct {
+	Project string
+}
 
 type Config struct {
 	*environConfig
@@ -384,6 +387,8 @@ func (ecfg *Config) Values(c *gc.C) (ConfigValues, map[string]interface{}) {
 	extras := make(map[string]interface{})
 	for k, v := range ecfg.attrs {
 		switch k {
+		case ProjectKey:
+			values.Project = v.(string)
 		default:
 			extras[k] = v
 		}
@@ -433,6 +438,8 @@ type StubClient struct {
 	Server             *api.Server
 	Profile            *api.Profile
 	StorageIsSupported bool
+	ProjectIsSupported bool
+	JujuProjects       []string
 	Volumes            map[string][]api.StorageVolume
 	ServerCert         string
 	ServerHostArch     string
@@ -604,6 +611,21 @@ func (conn *StubClient) CreateVolume(pool, volume string, config map[string]stri
 	return conn.NextErr()
 }
 
+func (conn *StubClient) CreateVolumeFromSnapshot(pool, volume, sourcePool, snapshot string, config map[string]string) error {
+	conn.AddCall("CreateVolumeFromSnapshot", pool, volume, sourcePool, snapshot, config)
+	return conn.NextErr()
+}
+
+func (conn *StubClient) CreateVolumeSnapshot(pool, volume, snapshot string) error {
+	conn.AddCall("CreateVolumeSnapshot", pool, volume, snapshot)
+	return conn.NextErr()
+}
+
+func (conn *StubClient) DeleteVolumeSnapshot(pool, volume, snapshot string) error {
+	conn.AddCall("DeleteVolumeSnapshot", pool, volume, snapshot)
+	return conn.NextErr()
+}
+
 func (conn *StubClient) DeleteStoragePoolVolume(pool, volType, volume string) error {
 	conn.AddCall("DeleteStoragePoolVolume", pool, volType, volume)
 	return conn.NextErr()
@@ -726,6 +748,42 @@ func (conn *StubClient) GetClusterMembers() (members []api.ClusterMember, err er
 	return nil, conn.NextErr()
 }
 
+func (conn *StubClient) MoveVolume(pool, name, from, to string) error {
+	conn.AddCall("MoveVolume", pool, name, from, to)
+	return conn.NextErr()
+}
+
+func (conn *StubClient) ProjectSupported() bool {
+	conn.AddCall("ProjectSupported")
+	return conn.ProjectIsSupported
+}
+
+func (conn *StubClient) JujuProjectNames() ([]string, error) {
+	conn.AddCall("JujuProjectNames")
+	if err := conn.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return conn.JujuProjects, nil
+}
+
+func (conn *StubClient) EnsureProject(name string) error {
+	conn.AddCall("EnsureProject", name)
+	return conn.NextErr()
+}
+
+func (conn *StubClient) DeleteProject(name string) error {
+	conn.AddCall("DeleteProject", name)
+	return conn.NextErr()
+}
+
+// UseProjectServer, like UseTargetServer, only exists to satisfy the
+// Server interface and should not be called in tests.
+func (conn *StubClient) UseProjectServer(name string) (*lxd.Server, error) {
+	conn.AddCall("UseProjectServer", name)
+	return nil, conn.NextErr()
+}
+
 type MockClock struct {
 	clock.Clock
 	now time.Time
@@ -764,9 +822,10 @@ func (s *EnvironSuite) NewEnviron(c *gc.C, svr Server, cfgEdit map[string]interf
 	c.Assert(err, jc.ErrorIsNil)
 
 	return &environ{
-		serverUnlocked: svr,
-		ecfgUnlocked:   eCfg,
-		namespace:      namespace,
+		serverUnlocked:        svr,
+		defaultServerUnlocked: svr,
+		ecfgUnlocked:          eCfg,
+		namespace:             namespace,
 	}
 }
 
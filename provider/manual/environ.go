@@ -24,6 +24,7 @@ import (
 	"github.com/juju/juju/cloudconfig/instancecfg"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/paths"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
@@ -111,7 +112,7 @@ func (e *manualEnviron) Create(context.ProviderCallContext, environs.CreateParam
 
 // Bootstrap is part of the Environ interface.
 func (e *manualEnviron) Bootstrap(ctx environs.BootstrapContext, callCtx context.ProviderCallContext, args environs.BootstrapParams) (*environs.BootstrapResult, error) {
-	provisioned, err := sshprovisioner.CheckProvisioned(e.host)
+	provisioned, err := sshprovisioner.CheckProvisioned(e.host, nil)
 	if err != nil {
 		return nil, errors.Annotate(err, "failed to check provisioned status")
 	}
@@ -268,7 +269,8 @@ function wait_for_jujud {
 # There might be no jujud at all (for example, after a failed deployment) so
 # don't require pkill to succeed before looking for a jujud process.
 # SIGABRT not SIGTERM, as abort lets the worker know it should uninstall itself,
-# rather than terminate normally.
+# rather than terminate normally. The uninstall file confirms that it should.
+[[ -d %[3]s ]] && touch %[3]s/%[4]s
 pkill -SIGABRT jujud
 wait_for_jujud
 
@@ -297,6 +299,8 @@ exit 0
 		script,
 		diagnostics,
 		mongo.ServiceName,
+		paths.NixDataDir,
+		agent.UninstallFile,
 	)
 	logger.Tracef("destroy controller script: %s", script)
 	stdout, stderr, err := runSSHCommand(
@@ -357,7 +361,7 @@ func (e *manualEnviron) seriesAndHardwareCharacteristics() (_ *instance.Hardware
 	if e.hw != nil {
 		return e.hw, e.series, nil
 	}
-	hw, series, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics(e.host)
+	hw, series, err := sshprovisioner.DetectSeriesAndHardwareCharacteristics(e.host, nil)
 	if err != nil {
 		return nil, "", errors.Trace(err)
 	}
@@ -10,6 +10,7 @@ import (
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/arch"
+	"github.com/juju/utils/ssh"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/core/constraints"
@@ -106,7 +107,8 @@ function wait_for_jujud {
 # There might be no jujud at all (for example, after a failed deployment) so
 # don't require pkill to succeed before looking for a jujud process.
 # SIGABRT not SIGTERM, as abort lets the worker know it should uninstall itself,
-# rather than terminate normally.
+# rather than terminate normally. The uninstall file confirms that it should.
+[[ -d /var/lib/juju ]] && touch /var/lib/juju/uninstall-agent
 pkill -SIGABRT jujud
 wait_for_jujud
 
@@ -154,7 +156,7 @@ func (s *environSuite) TestSupportsNetworking(c *gc.C) {
 
 func (s *environSuite) TestConstraintsValidator(c *gc.C) {
 	s.PatchValue(&sshprovisioner.DetectSeriesAndHardwareCharacteristics,
-		func(string) (instance.HardwareCharacteristics, string, error) {
+		func(string, *ssh.Options) (instance.HardwareCharacteristics, string, error) {
 			amd64 := "amd64"
 			return instance.HardwareCharacteristics{
 				Arch: &amd64,
@@ -49,6 +49,7 @@ type vnicWithIndex struct {
 }
 
 var _ instances.Instance = (*ociInstance)(nil)
+var _ instances.InstanceEgressFirewaller = (*ociInstance)(nil)
 var maxPollIterations = 30
 var pollTime = 10 * time.Second
 
@@ -351,6 +352,32 @@ func (o *ociInstance) IngressRules(
 	return rules, errors.Trace(err)
 }
 
+// SetEgressCIDRs (InstanceEgressFirewaller) restricts the outbound
+// traffic of the machine with the input ID to the input CIDRs.
+func (o *ociInstance) SetEgressCIDRs(
+	ctx envcontext.ProviderCallContext, _ string, cidrs []string,
+) error {
+	client, err := o.getInstanceConfigurator(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(client.SetEgressCIDRs(cidrs))
+}
+
+// EgressCIDRs (InstanceEgressFirewaller) returns the CIDRs to which the
+// outbound traffic of the input machine ID is restricted.
+func (o *ociInstance) EgressCIDRs(
+	ctx envcontext.ProviderCallContext, _ string,
+) ([]string, error) {
+	client, err := o.getInstanceConfigurator(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	cidrs, err := client.FindEgressCIDRs()
+	return cidrs, errors.Trace(err)
+}
+
 func (o *ociInstance) getInstanceConfigurator(
 	ctx envcontext.ProviderCallContext,
 ) (common.InstanceConfigurator, error) {
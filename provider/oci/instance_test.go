@@ -202,3 +202,25 @@ func (i *instanceSuite) TestInstanceConfiguratorUsesPublicAddress(c *gc.C) {
 	c.Assert(err, gc.IsNil)
 	c.Assert(inst.OpenPorts(nil, "", rules), gc.IsNil)
 }
+
+func (i *instanceSuite) TestSetEgressCIDRs(c *gc.C) {
+	ctrl := i.patchEnv(c)
+	defer ctrl.Finish()
+
+	vnicID := "fakeVnicId"
+	i.setupListVnicsExpectations(i.testInstanceID, vnicID)
+
+	cidrs := []string{"10.0.0.0/8"}
+
+	ic := mocks.NewMockInstanceConfigurator(ctrl)
+	ic.EXPECT().SetEgressCIDRs(cidrs).Return(nil)
+
+	factory := func(addr string) common.InstanceConfigurator {
+		c.Assert(addr, gc.Equals, "2.2.2.2")
+		return ic
+	}
+
+	inst, err := oci.NewInstanceWithConfigurator(*i.ociInstance, i.env, factory)
+	c.Assert(err, gc.IsNil)
+	c.Assert(inst.SetEgressCIDRs(nil, "", cidrs), gc.IsNil)
+}
@@ -40,6 +40,7 @@ type environConfig struct {
 
 var _ config.ConfigSchemaSource = (*EnvironProvider)(nil)
 var _ environs.ProviderSchema = (*EnvironProvider)(nil)
+var _ environs.EgressFirewallingProvider = (*EnvironProvider)(nil)
 
 // var cloudSchema = &jsonschema.Schema{
 // 	Type:     []jsonschema.Type{jsonschema.ObjectType},
@@ -186,6 +187,11 @@ func (e EnvironProvider) Version() int {
 	return 1
 }
 
+// SupportsInstanceEgress implements environs.EgressFirewallingProvider.
+func (e EnvironProvider) SupportsInstanceEgress() bool {
+	return true
+}
+
 // CloudSchema implements environs.EnvironProvider.
 func (e EnvironProvider) CloudSchema() *jsonschema.Schema {
 	return nil
@@ -5,6 +5,7 @@ package openstack
 
 import (
 	"fmt"
+	"net"
 
 	"github.com/juju/schema"
 	"github.com/juju/utils"
@@ -16,6 +17,7 @@ import (
 const (
 	ExternalNetworkKey    = "external-network"
 	NetworkKey            = "network"
+	NetworkCIDRKey        = "network-cidr"
 	PolicyTargetGroupKey  = "policy-target-group"
 	UseDefaultSecgroupKey = "use-default-secgroup"
 	UseOpenstackGBPKey    = "use-openstack-gbp"
@@ -32,7 +34,11 @@ var configSchema = environschema.Fields{
 		Type:        environschema.Tbool,
 	},
 	NetworkKey: {
-		Description: "The network label or UUID to bring machines up on when multiple networks exist.",
+		Description: `The network label or UUID to bring machines up on when multiple networks exist, or "create" to have Juju create a network for the model, routed through the external network, and delete it when the model is destroyed.`,
+		Type:        environschema.Tstring,
+	},
+	NetworkCIDRKey: {
+		Description: `The address range of the subnet in the network Juju creates for the model when "network" is "create". It is used when the network is created.`,
 		Type:        environschema.Tstring,
 	},
 	ExternalNetworkKey: {
@@ -53,6 +59,7 @@ var configDefaults = schema.Defaults{
 	UseFloatingIPKey:      false,
 	UseDefaultSecgroupKey: false,
 	NetworkKey:            "",
+	NetworkCIDRKey:        defaultNetworkCIDR,
 	ExternalNetworkKey:    "",
 	UseOpenstackGBPKey:    false,
 	PolicyTargetGroupKey:  "",
@@ -83,6 +90,10 @@ func (c *environConfig) network() string {
 	return c.attrs[NetworkKey].(string)
 }
 
+func (c *environConfig) networkCIDR() string {
+	return c.attrs[NetworkCIDRKey].(string)
+}
+
 func (c *environConfig) externalNetwork() string {
 	return c.attrs[ExternalNetworkKey].(string)
 }
@@ -155,6 +166,10 @@ func (p EnvironProvider) Validate(cfg, old *config.Config) (valid *config.Config
 		}
 	}
 
+	if _, _, err := net.ParseCIDR(ecfg.networkCIDR()); err != nil {
+		return nil, fmt.Errorf("invalid %s %q: %v", NetworkCIDRKey, ecfg.networkCIDR(), err)
+	}
+
 	// Check for deprecated fields and log a warning. We also print to stderr to ensure the user sees the message
 	// even if they are not running with --debug.
 	if defaultImageId := cfgAttrs["default-image-id"]; defaultImageId != nil && defaultImageId.(string) != "" {
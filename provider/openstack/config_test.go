@@ -210,6 +210,32 @@ var configTests = []configTest{
 			NetworkKey: "a-network-label",
 		}),
 		network: "a-network-label",
+	}, {
+		summary: "create network",
+		config: requiredConfig.Merge(testing.Attrs{
+			NetworkKey: networkCreate,
+		}),
+		network: "create",
+	}, {
+		summary: "default network CIDR",
+		config:  requiredConfig,
+		expect: testing.Attrs{
+			NetworkCIDRKey: "10.0.0.0/16",
+		},
+	}, {
+		summary: "network CIDR",
+		config: requiredConfig.Merge(testing.Attrs{
+			NetworkCIDRKey: "192.168.0.0/20",
+		}),
+		expect: testing.Attrs{
+			NetworkCIDRKey: "192.168.0.0/20",
+		},
+	}, {
+		summary: "invalid network CIDR",
+		config: requiredConfig.Merge(testing.Attrs{
+			NetworkCIDRKey: "192.168.0.0",
+		}),
+		err: `invalid network-cidr "192.168.0.0": .*`,
 	}, {}, {
 		summary:         "default external network",
 		config:          requiredConfig,
@@ -5,12 +5,14 @@ package openstack
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/juju/clock"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/retry"
 	"gopkg.in/goose.v2/neutron"
@@ -85,6 +87,14 @@ type Firewaller interface {
 
 	// InstanceIngressRules returns the ingress rules applied to the specified  instance.
 	InstanceIngressRules(ctx context.ProviderCallContext, inst instances.Instance, machineId string) ([]network.IngressRule, error)
+
+	// SetInstanceEgressCIDRs restricts the outbound traffic of the specified
+	// instance to the given destination CIDRs. A nil slice removes the restriction.
+	SetInstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string, cidrs []string) error
+
+	// InstanceEgressCIDRs returns the destination CIDRs to which the outbound
+	// traffic of the specified instance is restricted, or nil if it is unrestricted.
+	InstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string) ([]string, error)
 }
 
 type firewallerFactory struct {
@@ -209,6 +219,20 @@ func (f *switchingFirewaller) InstanceIngressRules(ctx context.ProviderCallConte
 	return f.fw.InstanceIngressRules(ctx, inst, machineId)
 }
 
+func (f *switchingFirewaller) SetInstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string, cidrs []string) error {
+	if err := f.initFirewaller(ctx); err != nil {
+		return errors.Trace(err)
+	}
+	return f.fw.SetInstanceEgressCIDRs(ctx, inst, machineId, cidrs)
+}
+
+func (f *switchingFirewaller) InstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string) ([]string, error) {
+	if err := f.initFirewaller(ctx); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f.fw.InstanceEgressCIDRs(ctx, inst, machineId)
+}
+
 type firewallerBase struct {
 	environ          *Environ
 	ensureGroupMutex sync.Mutex
@@ -445,6 +469,12 @@ func (c *firewallerBase) machineGroupRegexp(machineId string) string {
 	return fmt.Sprintf("%s-%s$", c.jujuGroupRegexp(), machineId)
 }
 
+func (c *firewallerBase) modelGroupRegexp() string {
+	// we are only looking to match the model group, not the
+	// machine or global groups
+	return fmt.Sprintf("%s$", c.jujuGroupRegexp())
+}
+
 type neutronFirewaller struct {
 	firewallerBase
 }
@@ -867,6 +897,144 @@ func (c *neutronFirewaller) InstanceIngressRules(ctx context.ProviderCallContext
 	return rules, err
 }
 
+// allIPv4 is the destination of the egress rule created by Neutron for
+// every new security group, which allows all outbound IPv4 traffic.
+const allIPv4 = "0.0.0.0/0"
+
+// SetInstanceEgressCIDRs implements Firewaller interface.
+//
+// Instances are members of both the model group and their machine group,
+// and Neutron allows any traffic allowed by one of them. The rule allowing
+// all outbound IPv4 traffic is therefore removed from the model group
+// before restricting an instance; unrestricted instances keep the one in
+// their machine group. IPv6 traffic is not restricted.
+func (c *neutronFirewaller) SetInstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string, cidrs []string) error {
+	if c.environ.Config().FirewallMode() != config.FwInstance {
+		return errors.Errorf("invalid firewall mode %q for restricting egress on instance",
+			c.environ.Config().FirewallMode())
+	}
+	if c.environ.ecfg().useDefaultSecurityGroup() {
+		// The default group allows all outbound traffic, and
+		// isn't managed by Juju.
+		return errors.NotSupportedf("restricting egress with use-default-security-group")
+	}
+	// For bug 1680787
+	// No security groups exist if the network used to boot the instance has
+	// PortSecurityEnabled set to false.
+	if securityGroups := inst.(*openstackInstance).getServerDetail().Groups; securityGroups == nil {
+		return errors.NotSupportedf("restricting egress without port security")
+	}
+	want := set.NewStrings(allIPv4)
+	if cidrs != nil {
+		want = set.NewStrings()
+		for _, cidr := range cidrs {
+			ip, _, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return errors.NotValidf("egress CIDR %q", cidr)
+			}
+			if ip.To4() == nil {
+				return errors.NotSupportedf("restricting egress to non-IPv4 destination %q", cidr)
+			}
+			want.Add(cidr)
+		}
+		if err := c.setEgressRulesInGroup(ctx, c.modelGroupRegexp(), set.NewStrings()); err != nil {
+			handleCredentialError(err, ctx)
+			return errors.Trace(err)
+		}
+	}
+	if err := c.setEgressRulesInGroup(ctx, c.machineGroupRegexp(machineId), want); err != nil {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	logger.Infof("set egress rules in security group %s-%s: %v", c.environ.Config().UUID(), machineId, want.SortedValues())
+	return nil
+}
+
+// InstanceEgressCIDRs implements Firewaller interface.
+func (c *neutronFirewaller) InstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string) ([]string, error) {
+	if c.environ.Config().FirewallMode() != config.FwInstance {
+		return nil, errors.Errorf("invalid firewall mode %q for retrieving egress rules from instance",
+			c.environ.Config().FirewallMode())
+	}
+	if c.environ.ecfg().useDefaultSecurityGroup() {
+		return nil, nil
+	}
+	// For bug 1680787
+	if securityGroups := inst.(*openstackInstance).getServerDetail().Groups; securityGroups == nil {
+		return nil, nil
+	}
+	group, err := c.matchingGroup(ctx, c.machineGroupRegexp(machineId))
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return nil, errors.Trace(err)
+	}
+	cidrs := set.NewStrings()
+	for _, rule := range group.Rules {
+		cidr, ok := ipv4EgressRuleCIDR(rule)
+		if !ok || cidr == "" {
+			continue
+		}
+		if cidr == allIPv4 {
+			return nil, nil
+		}
+		cidrs.Add(cidr)
+	}
+	return cidrs.SortedValues(), nil
+}
+
+// setEgressRulesInGroup makes the IPv4 egress rules of the matching group
+// allow outbound traffic to the given CIDRs only.
+func (c *neutronFirewaller) setEgressRulesInGroup(ctx context.ProviderCallContext, nameRegExp string, cidrs set.Strings) error {
+	group, err := c.matchingGroup(ctx, nameRegExp)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	neutronClient := c.environ.neutron()
+	have := set.NewStrings()
+	for _, rule := range group.Rules {
+		cidr, ok := ipv4EgressRuleCIDR(rule)
+		if !ok {
+			continue
+		}
+		if cidrs.Contains(cidr) && !have.Contains(cidr) {
+			have.Add(cidr)
+			continue
+		}
+		if err := neutronClient.DeleteSecurityGroupRuleV2(rule.Id); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, cidr := range cidrs.Difference(have).SortedValues() {
+		rule := neutron.RuleInfoV2{
+			Direction:      "egress",
+			EthernetType:   "IPv4",
+			RemoteIPPrefix: cidr,
+			ParentGroupId:  group.Id,
+		}
+		if _, err := neutronClient.CreateSecurityGroupRuleV2(rule); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// ipv4EgressRuleCIDR returns the destination CIDR of the rule, and true,
+// if the rule is an IPv4 egress rule. Rules without a destination allow
+// all traffic, and are reported as 0.0.0.0/0. An empty CIDR is returned
+// for rules limited to a protocol, which Juju doesn't create.
+func ipv4EgressRuleCIDR(rule neutron.SecurityGroupRuleV2) (string, bool) {
+	if rule.Direction != "egress" || rule.EthernetType == "IPv6" {
+		return "", false
+	}
+	if rule.IPProtocol != nil && *rule.IPProtocol != "" {
+		return "", true
+	}
+	if rule.RemoteIPPrefix == "" {
+		return allIPv4, true
+	}
+	return rule.RemoteIPPrefix, true
+}
+
 // Matching a security group by name only works if each name is unqiue.  Neutron
 // security groups are not required to have unique names.  Juju constructs unique
 // names, but there are frequently multiple matches to 'default'
@@ -252,6 +252,18 @@ func (c *legacyNovaFirewaller) InstanceIngressRules(ctx context.ProviderCallCont
 	return c.instanceIngressRules(ctx, c.ingressRulesInGroup, machineId)
 }
 
+// SetInstanceEgressCIDRs implements Firewaller interface. Egress
+// rules need Neutron security groups.
+func (c *legacyNovaFirewaller) SetInstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string, cidrs []string) error {
+	return errors.NotSupportedf("restricting egress without Neutron")
+}
+
+// InstanceEgressCIDRs implements Firewaller interface. Nova security
+// groups don't restrict outbound traffic.
+func (c *legacyNovaFirewaller) InstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string) ([]string, error) {
+	return nil, nil
+}
+
 func (c *legacyNovaFirewaller) matchingGroup(ctx context.ProviderCallContext, nameRegExp string) (nova.SecurityGroup, error) {
 	re, err := regexp.Compile(nameRegExp)
 	if err != nil {
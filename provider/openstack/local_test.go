@@ -1794,6 +1794,66 @@ func (s *localServerSuite) TestMatchingGroup(c *gc.C) {
 	c.Assert(group2.Id, gc.Equals, groupMatched.Id)
 }
 
+// ipv4EgressRules returns the IPv4 egress rules of the matching group.
+func ipv4EgressRules(c *gc.C, env environs.Environ, callCtx context.ProviderCallContext, nameRegExp string) []neutron.RuleInfoV2 {
+	group, err := openstack.MatchingGroup(env, callCtx, nameRegExp)
+	c.Assert(err, jc.ErrorIsNil)
+	var rules []neutron.RuleInfoV2
+	for _, rule := range ruleToRuleInfo(group.Rules) {
+		if rule.Direction == "egress" && rule.EthernetType != "IPv6" {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+func (s *localServerSuite) TestInstanceEgressCIDRs(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"firewall-mode": config.FwInstance})
+	inst, _ := testing.AssertStartInstance(c, env, s.callCtx, s.ControllerUUID, "100")
+	fwInst, ok := inst.(instances.InstanceEgressFirewaller)
+	c.Assert(ok, jc.IsTrue)
+
+	cidrs, err := fwInst.EgressCIDRs(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+
+	err = fwInst.SetEgressCIDRs(s.callCtx, "100", []string{"192.168.1.1/32", "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs, err = fwInst.EgressCIDRs(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.0/24", "192.168.1.1/32"})
+
+	// The model group no longer allows all outbound IPv4 traffic,
+	// as it would override the machine group's restriction.
+	modelGroupRegexp := fmt.Sprintf("juju-.*-%s$", env.Config().UUID())
+	c.Assert(ipv4EgressRules(c, env, s.callCtx, modelGroupRegexp), gc.HasLen, 0)
+
+	// Lifting the restriction restores the default rule.
+	err = fwInst.SetEgressCIDRs(s.callCtx, "100", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs, err = fwInst.EgressCIDRs(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+	machineRules := ipv4EgressRules(c, env, s.callCtx, openstack.MachineGroupRegexp(env, "100"))
+	c.Assert(machineRules, jc.DeepEquals, []neutron.RuleInfoV2{{
+		Direction:      "egress",
+		EthernetType:   "IPv4",
+		RemoteIPPrefix: "0.0.0.0/0",
+	}})
+}
+
+func (s *localServerSuite) TestInstanceEgressCIDRsIPv6NotSupported(c *gc.C) {
+	env := s.openEnviron(c, coretesting.Attrs{"firewall-mode": config.FwInstance})
+	inst, _ := testing.AssertStartInstance(c, env, s.callCtx, s.ControllerUUID, "100")
+	fwInst := inst.(instances.InstanceEgressFirewaller)
+
+	err := fwInst.SetEgressCIDRs(s.callCtx, "100", []string{"2001:db8::/32"})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	cidrs, err := fwInst.EgressCIDRs(s.callCtx, "100")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
+
 // localHTTPSServerSuite contains tests that run against an Openstack service
 // double connected on an HTTPS port with a self-signed certificate. This
 // service is set up and torn down for every test.  This should only test
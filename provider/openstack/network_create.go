@@ -0,0 +1,476 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/retry"
+	"gopkg.in/goose.v2/client"
+	gooseerrors "gopkg.in/goose.v2/errors"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/neutron"
+
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+)
+
+const (
+	// networkCreate is the value of the "network" model config
+	// setting which makes Juju create a network for the model,
+	// and delete it again when the model is destroyed.
+	networkCreate = "create"
+
+	// defaultNetworkCIDR is the default address range of the
+	// subnet in a network created by Juju. Created networks are
+	// isolated from each other, so the range may be the same for
+	// every model.
+	defaultNetworkCIDR = "10.0.0.0/16"
+
+	apiRoutersV2 = "routers"
+)
+
+// networkManagementClient is the subset of the Neutron API needed to
+// create and delete model networks. It is not provided by the goose
+// neutron client, so it is implemented by neutronManagementClient.
+type networkManagementClient interface {
+	ListNetworksByTags(tags ...string) ([]createdNetwork, error)
+	CreateNetwork(name string) (string, error)
+	DeleteNetwork(networkID string) error
+	CreateSubnet(networkID, name, cidr string) (string, error)
+	CreateRouter(name, externalNetworkID string) (createdRouter, error)
+	DeleteRouter(routerID string) error
+	ListRoutersByTags(tags ...string) ([]string, error)
+	AddRouterInterface(routerID, subnetID string) error
+	RemoveRouterInterface(routerID, subnetID string) error
+	SetTags(resource, id string, tags []string) error
+}
+
+// createdNetwork is a network created by Juju, with the tags that
+// identify its controller and model.
+type createdNetwork struct {
+	neutron.NetworkV2
+	Tags []string `json:"tags"`
+}
+
+// createdRouter is a router created by Juju to connect a created
+// network to the external network.
+type createdRouter struct {
+	Id string `json:"id"`
+
+	// SNAT reports whether the router translates the addresses of
+	// traffic leaving the network through its gateway, giving machines
+	// without floating IP addresses outbound access.
+	SNAT bool
+}
+
+// neutronManagementClient implements networkManagementClient using
+// requests made directly to the Neutron API.
+type neutronManagementClient struct {
+	client client.Client
+}
+
+func (c neutronManagementClient) send(method, url string, req, resp interface{}, expectedStatus int) error {
+	return c.client.SendRequest(method, "network", "v2.0", url, &goosehttp.RequestData{
+		ReqValue:       req,
+		RespValue:      resp,
+		ExpectedStatus: []int{expectedStatus},
+	})
+}
+
+// CreateNetwork creates a network with the given name, returning its ID.
+func (c neutronManagementClient) CreateNetwork(name string) (string, error) {
+	var req struct {
+		Network struct {
+			Name         string `json:"name"`
+			AdminStateUp bool   `json:"admin_state_up"`
+		} `json:"network"`
+	}
+	req.Network.Name = name
+	req.Network.AdminStateUp = true
+	var resp struct {
+		Network neutron.NetworkV2 `json:"network"`
+	}
+	if err := c.send(client.POST, neutron.ApiNetworksV2, &req, &resp, http.StatusCreated); err != nil {
+		return "", errors.Annotatef(err, "creating network %q", name)
+	}
+	return resp.Network.Id, nil
+}
+
+// DeleteNetwork deletes the network, along with its subnets.
+func (c neutronManagementClient) DeleteNetwork(networkID string) error {
+	url := fmt.Sprintf("%s/%s", neutron.ApiNetworksV2, networkID)
+	return c.send(client.DELETE, url, nil, nil, http.StatusNoContent)
+}
+
+// CreateSubnet creates an IPv4 subnet with the given CIDR in the
+// network, returning its ID.
+func (c neutronManagementClient) CreateSubnet(networkID, name, cidr string) (string, error) {
+	var req struct {
+		Subnet struct {
+			NetworkId  string `json:"network_id"`
+			Name       string `json:"name"`
+			Cidr       string `json:"cidr"`
+			IPVersion  int    `json:"ip_version"`
+			EnableDHCP bool   `json:"enable_dhcp"`
+		} `json:"subnet"`
+	}
+	req.Subnet.NetworkId = networkID
+	req.Subnet.Name = name
+	req.Subnet.Cidr = cidr
+	req.Subnet.IPVersion = 4
+	req.Subnet.EnableDHCP = true
+	var resp struct {
+		Subnet neutron.SubnetV2 `json:"subnet"`
+	}
+	if err := c.send(client.POST, neutron.ApiSubnetsV2, &req, &resp, http.StatusCreated); err != nil {
+		return "", errors.Annotatef(err, "creating subnet %q", name)
+	}
+	return resp.Subnet.Id, nil
+}
+
+// ListNetworksByTags returns the networks with all of the given tags.
+func (c neutronManagementClient) ListNetworksByTags(tags ...string) ([]createdNetwork, error) {
+	var resp struct {
+		Networks []createdNetwork `json:"networks"`
+	}
+	apiCall := fmt.Sprintf("%s?%s", neutron.ApiNetworksV2, tagsQuery(tags))
+	if err := c.send(client.GET, apiCall, nil, &resp, http.StatusOK); err != nil {
+		return nil, errors.Annotate(err, "listing networks")
+	}
+	return resp.Networks, nil
+}
+
+// CreateRouter creates a router with the given name and a gateway on
+// the external network. Neutron enables SNAT on the gateway unless the
+// cloud's policy says otherwise, so the router's SNAT setting is
+// returned along with its ID.
+func (c neutronManagementClient) CreateRouter(name, externalNetworkID string) (createdRouter, error) {
+	var req struct {
+		Router struct {
+			Name                string `json:"name"`
+			ExternalGatewayInfo struct {
+				NetworkId string `json:"network_id"`
+			} `json:"external_gateway_info"`
+		} `json:"router"`
+	}
+	req.Router.Name = name
+	req.Router.ExternalGatewayInfo.NetworkId = externalNetworkID
+	var resp struct {
+		Router struct {
+			Id                  string `json:"id"`
+			ExternalGatewayInfo struct {
+				EnableSNAT *bool `json:"enable_snat"`
+			} `json:"external_gateway_info"`
+		} `json:"router"`
+	}
+	if err := c.send(client.POST, apiRoutersV2, &req, &resp, http.StatusCreated); err != nil {
+		return createdRouter{}, errors.Annotatef(err, "creating router %q", name)
+	}
+	// Clouds without the ext-gw-mode extension always SNAT.
+	snat := resp.Router.ExternalGatewayInfo.EnableSNAT
+	return createdRouter{
+		Id:   resp.Router.Id,
+		SNAT: snat == nil || *snat,
+	}, nil
+}
+
+// DeleteRouter deletes the router.
+func (c neutronManagementClient) DeleteRouter(routerID string) error {
+	url := fmt.Sprintf("%s/%s", apiRoutersV2, routerID)
+	return c.send(client.DELETE, url, nil, nil, http.StatusNoContent)
+}
+
+// ListRoutersByTags returns the IDs of the routers with all of the
+// given tags.
+func (c neutronManagementClient) ListRoutersByTags(tags ...string) ([]string, error) {
+	var resp struct {
+		Routers []struct {
+			Id string `json:"id"`
+		} `json:"routers"`
+	}
+	apiCall := fmt.Sprintf("%s?%s", apiRoutersV2, tagsQuery(tags))
+	if err := c.send(client.GET, apiCall, nil, &resp, http.StatusOK); err != nil {
+		return nil, errors.Annotate(err, "listing routers")
+	}
+	ids := make([]string, len(resp.Routers))
+	for i, router := range resp.Routers {
+		ids[i] = router.Id
+	}
+	return ids, nil
+}
+
+// SetTags replaces the tags of the Neutron resource, e.g. "networks",
+// with the given ID.
+func (c neutronManagementClient) SetTags(resource, id string, tags []string) error {
+	req := struct {
+		Tags []string `json:"tags"`
+	}{tags}
+	var resp struct{}
+	url := fmt.Sprintf("%s/%s/tags", resource, id)
+	return c.send(client.PUT, url, &req, &resp, http.StatusOK)
+}
+
+func tagsQuery(tags []string) string {
+	return url.Values{"tags": {strings.Join(tags, ",")}}.Encode()
+}
+
+// AddRouterInterface connects the subnet to the router.
+func (c neutronManagementClient) AddRouterInterface(routerID, subnetID string) error {
+	return c.routerInterface("add_router_interface", routerID, subnetID)
+}
+
+// RemoveRouterInterface disconnects the subnet from the router.
+func (c neutronManagementClient) RemoveRouterInterface(routerID, subnetID string) error {
+	return c.routerInterface("remove_router_interface", routerID, subnetID)
+}
+
+func (c neutronManagementClient) routerInterface(action, routerID, subnetID string) error {
+	req := struct {
+		SubnetId string `json:"subnet_id"`
+	}{subnetID}
+	var resp struct{}
+	url := fmt.Sprintf("%s/%s/%s", apiRoutersV2, routerID, action)
+	return c.send(client.PUT, url, &req, &resp, http.StatusOK)
+}
+
+// createdNetworkName returns the name of the network that Juju
+// creates for the model. It follows the naming of the model's
+// security groups. Created networks are found by their tags, not
+// their names.
+func createdNetworkName(controllerUUID, modelUUID string) string {
+	return fmt.Sprintf("juju-%s-%s", controllerUUID, modelUUID)
+}
+
+// modelNetworkTag returns the tag of the network, and router, created
+// for the model.
+func modelNetworkTag(modelUUID string) string {
+	return tags.JujuModel + "=" + modelUUID
+}
+
+// controllerNetworksTag returns the tag of the networks, and routers,
+// created for the controller's models.
+func controllerNetworksTag(controllerUUID string) string {
+	return tags.JujuController + "=" + controllerUUID
+}
+
+// createNetwork creates a network with the given name and CIDR,
+// containing a single subnet connected through a router to the
+// external network. The network and router are tagged as owned by the
+// controller and model. Neutron networks span availability zones, so
+// unlike EC2 there is no subnet per zone. The network's ID is returned.
+func createNetwork(
+	client networkManagementClient,
+	name, cidr, externalNetworkID string,
+	controllerUUID, modelUUID string,
+) (_ string, err error) {
+	networkID, err := client.CreateNetwork(name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		// An untagged network would never be deleted.
+		if err != nil {
+			if delErr := client.DeleteNetwork(networkID); delErr != nil {
+				logger.Errorf("cannot delete network %q: %v", name, delErr)
+			}
+		}
+	}()
+	ownerTags := []string{controllerNetworksTag(controllerUUID), modelNetworkTag(modelUUID)}
+	if err := client.SetTags(neutron.ApiNetworksV2, networkID, ownerTags); err != nil {
+		return "", errors.Annotate(err, "tagging network")
+	}
+	subnetID, err := client.CreateSubnet(networkID, name, cidr)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	router, err := client.CreateRouter(name, externalNetworkID)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := client.SetTags(apiRoutersV2, router.Id, ownerTags); err != nil {
+		if delErr := client.DeleteRouter(router.Id); delErr != nil {
+			logger.Errorf("cannot delete router %q: %v", name, delErr)
+		}
+		return "", errors.Annotate(err, "tagging router")
+	}
+	if !router.SNAT {
+		logger.Warningf(
+			"SNAT is disabled on the router of network %q: machines without floating IP addresses have no outbound access",
+			name,
+		)
+	}
+	if err := client.AddRouterInterface(router.Id, subnetID); err != nil {
+		if delErr := client.DeleteRouter(router.Id); delErr != nil {
+			logger.Errorf("cannot delete router %q: %v", name, delErr)
+		}
+		return "", errors.Annotate(err, "connecting subnet to router")
+	}
+	return networkID, nil
+}
+
+// deleteNetwork deletes a network created by createNetwork, along
+// with its router. Neutron refuses to delete a network which still
+// has ports, which it may do for a while after the model's instances
+// have been terminated, so deletion is retried.
+func deleteNetwork(client networkManagementClient, clock clock.Clock, network createdNetwork) error {
+	routerIDs, err := client.ListRoutersByTags(network.Tags...)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, routerID := range routerIDs {
+		for _, subnetID := range network.SubnetIds {
+			if err := client.RemoveRouterInterface(routerID, subnetID); err != nil && !gooseerrors.IsNotFound(err) {
+				return errors.Annotatef(err, "disconnecting subnet %s from router", subnetID)
+			}
+		}
+		if err := client.DeleteRouter(routerID); err != nil && !gooseerrors.IsNotFound(err) {
+			return errors.Annotatef(err, "deleting router %s", routerID)
+		}
+	}
+	logger.Debugf("deleting network %q", network.Name)
+	err = retry.Call(retry.CallArgs{
+		Func: func() error {
+			err := client.DeleteNetwork(network.Id)
+			if err != nil && !gooseerrors.IsNotFound(err) {
+				return errors.Trace(err)
+			}
+			return nil
+		},
+		IsFatalError: func(err error) bool {
+			return !isConflictError(err)
+		},
+		NotifyFunc: func(err error, attempt int) {
+			logger.Debugf("deleting network %q, attempt %d: %v", network.Name, attempt, err)
+		},
+		Attempts: 30,
+		Delay:    time.Second,
+		Clock:    clock,
+	})
+	if err != nil {
+		return errors.Annotatef(err, "cannot delete network %q: consider deleting it manually", network.Name)
+	}
+	return nil
+}
+
+// isConflictError reports whether the error was returned by Neutron
+// because the resource is still in use.
+func isConflictError(err error) bool {
+	httpErr, ok := errors.Cause(err).(*goosehttp.HttpError)
+	return ok && httpErr.StatusCode == http.StatusConflict
+}
+
+// usesCreatedNetwork reports whether Juju creates the model's network.
+func (e *Environ) usesCreatedNetwork() bool {
+	return e.ecfg().network() == networkCreate
+}
+
+// internalNetwork returns the name or ID of the network on which
+// machines are started: the configured network, or the network
+// created for the model if the network setting is "create". The ID of
+// the created network is cached, as it does not change.
+func (e *Environ) internalNetwork() (string, error) {
+	network := e.ecfg().network()
+	if network != networkCreate {
+		return network, nil
+	}
+	e.createdNetworkMutex.Lock()
+	defer e.createdNetworkMutex.Unlock()
+	if e.createdNetworkID != "" {
+		return e.createdNetworkID, nil
+	}
+	networks, err := e.networkManagementClient().ListNetworksByTags(modelNetworkTag(e.uuid))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if len(networks) == 0 {
+		return "", errors.NotFoundf("network created for model %q", e.name)
+	}
+	e.createdNetworkID = networks[0].Id
+	return e.createdNetworkID, nil
+}
+
+func (e *Environ) networkManagementClient() networkManagementClient {
+	return neutronManagementClient{client: e.client()}
+}
+
+// ensureModelNetwork creates the model's network, if it does not
+// already exist.
+func (e *Environ) ensureModelNetwork(ctx context.ProviderCallContext, controllerUUID string) error {
+	if !e.supportsNeutron() {
+		return errors.NotSupportedf("creating a network without Neutron")
+	}
+	if _, err := e.internalNetwork(); err == nil {
+		return nil
+	} else if !errors.IsNotFound(err) {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	externalNetworkID, err := e.routerExternalNetwork()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	name := createdNetworkName(controllerUUID, e.uuid)
+	networkID, err := createNetwork(
+		e.networkManagementClient(), name, e.ecfg().networkCIDR(), externalNetworkID, controllerUUID, e.uuid,
+	)
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return errors.Annotate(err, "creating model network")
+	}
+	e.createdNetworkMutex.Lock()
+	e.createdNetworkID = networkID
+	e.createdNetworkMutex.Unlock()
+	return nil
+}
+
+// routerExternalNetwork returns the ID of the external network to
+// route a created network through: the configured external network,
+// or the only external network there is.
+func (e *Environ) routerExternalNetwork() (string, error) {
+	neutronClient := e.neutron()
+	if externalNetwork := e.ecfg().externalNetwork(); externalNetwork != "" {
+		return resolveNeutronNetwork(neutronClient, externalNetwork, true)
+	}
+	networks, err := neutronClient.ListNetworksV2(externalNetworkFilter())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	switch len(networks) {
+	case 0:
+		return "", errors.NotFoundf("external network to route the model network through")
+	case 1:
+		return networks[0].Id, nil
+	}
+	return "", errors.Errorf("multiple external networks found, set %q to choose one", ExternalNetworkKey)
+}
+
+// deleteCreatedNetworks deletes the networks created by Juju which
+// have the given tag.
+func (e *Environ) deleteCreatedNetworks(ctx context.ProviderCallContext, tag string) error {
+	client := e.networkManagementClient()
+	networks, err := client.ListNetworksByTags(tag)
+	if err != nil {
+		handleCredentialError(err, ctx)
+		return errors.Trace(err)
+	}
+	e.createdNetworkMutex.Lock()
+	defer e.createdNetworkMutex.Unlock()
+	for _, network := range networks {
+		if err := deleteNetwork(client, e.clock, network); err != nil {
+			handleCredentialError(err, ctx)
+			return errors.Trace(err)
+		}
+		if network.Id == e.createdNetworkID {
+			e.createdNetworkID = ""
+		}
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	gooseerrors "gopkg.in/goose.v2/errors"
+	goosehttp "gopkg.in/goose.v2/http"
+	"gopkg.in/goose.v2/neutron"
+)
+
+type networkCreateSuite struct {
+	testing.IsolationSuite
+
+	client *stubNetworkManagementClient
+}
+
+var _ = gc.Suite(&networkCreateSuite{})
+
+func (s *networkCreateSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.client = &stubNetworkManagementClient{Stub: &testing.Stub{}}
+}
+
+func (s *networkCreateSuite) TestCreateNetwork(c *gc.C) {
+	networkID, err := createNetwork(s.client, "juju-ctrl-model", "192.168.0.0/20", "ext-net", "ctrl", "model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(networkID, gc.Equals, "net-new")
+	ownerTags := []string{"juju-controller-uuid=ctrl", "juju-model-uuid=model"}
+	s.client.CheckCalls(c, []testing.StubCall{
+		{FuncName: "CreateNetwork", Args: []interface{}{"juju-ctrl-model"}},
+		{FuncName: "SetTags", Args: []interface{}{"networks", "net-new", ownerTags}},
+		{FuncName: "CreateSubnet", Args: []interface{}{"net-new", "juju-ctrl-model", "192.168.0.0/20"}},
+		{FuncName: "CreateRouter", Args: []interface{}{"juju-ctrl-model", "ext-net"}},
+		{FuncName: "SetTags", Args: []interface{}{"routers", "router-new", ownerTags}},
+		{FuncName: "AddRouterInterface", Args: []interface{}{"router-new", "subnet-new"}},
+	})
+}
+
+func (s *networkCreateSuite) TestCreateNetworkError(c *gc.C) {
+	s.client.SetErrors(nil, nil, nil, nil, nil, errors.New("boom"))
+
+	_, err := createNetwork(s.client, "juju-ctrl-model", "10.0.0.0/16", "ext-net", "ctrl", "model")
+	c.Assert(err, gc.ErrorMatches, "connecting subnet to router: boom")
+	s.client.CheckCallNames(c,
+		"CreateNetwork", "SetTags", "CreateSubnet", "CreateRouter", "SetTags", "AddRouterInterface",
+		"DeleteRouter", "DeleteNetwork",
+	)
+}
+
+func (s *networkCreateSuite) TestCreateNetworkTagError(c *gc.C) {
+	s.client.SetErrors(nil, errors.New("boom"))
+
+	_, err := createNetwork(s.client, "juju-ctrl-model", "10.0.0.0/16", "ext-net", "ctrl", "model")
+	c.Assert(err, gc.ErrorMatches, "tagging network: boom")
+	s.client.CheckCallNames(c, "CreateNetwork", "SetTags", "DeleteNetwork")
+	s.client.CheckCall(c, 2, "DeleteNetwork", "net-new")
+}
+
+func (s *networkCreateSuite) TestCreateNetworkWithoutSNAT(c *gc.C) {
+	s.client.noSNAT = true
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("network-create-test", &tw), jc.ErrorIsNil)
+	defer loggo.RemoveWriter("network-create-test")
+
+	_, err := createNetwork(s.client, "juju-ctrl-model", "10.0.0.0/16", "ext-net", "ctrl", "model")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tw.Log(), jc.LogMatches, jc.SimpleMessages{{
+		loggo.WARNING, `SNAT is disabled on the router of network "juju-ctrl-model".*`,
+	}})
+}
+
+func (s *networkCreateSuite) TestModelNetworkTags(c *gc.C) {
+	c.Assert(modelNetworkTag("model"), gc.Equals, "juju-model-uuid=model")
+	c.Assert(controllerNetworksTag("ctrl"), gc.Equals, "juju-controller-uuid=ctrl")
+}
+
+func (s *networkCreateSuite) TestDeleteNetwork(c *gc.C) {
+	s.client.routerIDs = []string{"router-0"}
+	network := createdNetwork{
+		NetworkV2: neutron.NetworkV2{Id: "net-0", Name: "juju-ctrl-model", SubnetIds: []string{"subnet-0"}},
+		Tags:      []string{"juju-controller-uuid=ctrl", "juju-model-uuid=model"},
+	}
+
+	err := deleteNetwork(s.client, testclock.NewClock(time.Time{}), network)
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCalls(c, []testing.StubCall{
+		{FuncName: "ListRoutersByTags", Args: []interface{}{[]string{"juju-controller-uuid=ctrl", "juju-model-uuid=model"}}},
+		{FuncName: "RemoveRouterInterface", Args: []interface{}{"router-0", "subnet-0"}},
+		{FuncName: "DeleteRouter", Args: []interface{}{"router-0"}},
+		{FuncName: "DeleteNetwork", Args: []interface{}{"net-0"}},
+	})
+}
+
+func (s *networkCreateSuite) TestDeleteNetworkAlreadyDeleted(c *gc.C) {
+	s.client.SetErrors(nil, gooseerrors.NewNotFoundf(nil, "", "no network"))
+	network := createdNetwork{NetworkV2: neutron.NetworkV2{Id: "net-0", Name: "juju-ctrl-model"}}
+
+	err := deleteNetwork(s.client, testclock.NewClock(time.Time{}), network)
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "ListRoutersByTags", "DeleteNetwork")
+}
+
+func (s *networkCreateSuite) TestDeleteNetworkError(c *gc.C) {
+	s.client.SetErrors(nil, errors.New("boom"))
+	network := createdNetwork{NetworkV2: neutron.NetworkV2{Id: "net-0", Name: "juju-ctrl-model"}}
+
+	err := deleteNetwork(s.client, testclock.NewClock(time.Time{}), network)
+	c.Assert(err, gc.ErrorMatches, `cannot delete network "juju-ctrl-model": consider deleting it manually: boom`)
+	s.client.CheckCallNames(c, "ListRoutersByTags", "DeleteNetwork")
+}
+
+func (s *networkCreateSuite) TestIsConflictError(c *gc.C) {
+	c.Assert(isConflictError(&goosehttp.HttpError{StatusCode: http.StatusConflict}), jc.IsTrue)
+	c.Assert(isConflictError(&goosehttp.HttpError{StatusCode: http.StatusBadRequest}), jc.IsFalse)
+	c.Assert(isConflictError(errors.New("boom")), jc.IsFalse)
+}
+
+type stubNetworkManagementClient struct {
+	*testing.Stub
+
+	networks  []createdNetwork
+	routerIDs []string
+	noSNAT    bool
+}
+
+func (s *stubNetworkManagementClient) ListNetworksByTags(tags ...string) ([]createdNetwork, error) {
+	s.AddCall("ListNetworksByTags", tags)
+	return s.networks, s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) CreateNetwork(name string) (string, error) {
+	s.AddCall("CreateNetwork", name)
+	return "net-new", s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) DeleteNetwork(networkID string) error {
+	s.AddCall("DeleteNetwork", networkID)
+	return s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) CreateSubnet(networkID, name, cidr string) (string, error) {
+	s.AddCall("CreateSubnet", networkID, name, cidr)
+	return "subnet-new", s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) CreateRouter(name, externalNetworkID string) (createdRouter, error) {
+	s.AddCall("CreateRouter", name, externalNetworkID)
+	return createdRouter{Id: "router-new", SNAT: !s.noSNAT}, s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) DeleteRouter(routerID string) error {
+	s.AddCall("DeleteRouter", routerID)
+	return s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) ListRoutersByTags(tags ...string) ([]string, error) {
+	s.AddCall("ListRoutersByTags", tags)
+	return s.routerIDs, s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) AddRouterInterface(routerID, subnetID string) error {
+	s.AddCall("AddRouterInterface", routerID, subnetID)
+	return s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) RemoveRouterInterface(routerID, subnetID string) error {
+	s.AddCall("RemoveRouterInterface", routerID, subnetID)
+	return s.NextErr()
+}
+
+func (s *stubNetworkManagementClient) SetTags(resource, id string, tags []string) error {
+	s.AddCall("SetTags", resource, id, tags)
+	return s.NextErr()
+}
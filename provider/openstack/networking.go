@@ -179,7 +179,10 @@ func (n *NeutronNetworking) AllocatePublicIP(instId instance.Id) (*string, error
 		// Create slice of network.Ids for external networks in the same AZ as
 		// the instance's network, to find an existing floating ip in, or allocate
 		// a new floating ip from.
-		net := n.env.ecfg().network()
+		net, err := n.env.internalNetwork()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 		netId, err := resolveNeutronNetwork(neutronClient, net, false)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -335,8 +338,11 @@ func makeSubnetInfo(neutron *neutron.Client, subnet neutron.SubnetV2) (corenetwo
 func (n *NeutronNetworking) Subnets(instId instance.Id, subnetIds []corenetwork.Id) ([]corenetwork.SubnetInfo, error) {
 	netIds := set.NewStrings()
 	neutron := n.env.neutron()
-	internalNet := n.env.ecfg().network()
-	netId, err := resolveNeutronNetwork(neutron, internalNet, false)
+	var netId string
+	internalNet, err := n.env.internalNetwork()
+	if err == nil {
+		netId, err = resolveNeutronNetwork(neutron, internalNet, false)
+	}
 	if err != nil {
 		// Note: (jam 2018-05-23) We don't treat this as fatal because we used to never pay attention to it anyway
 		if internalNet == "" {
@@ -160,6 +160,12 @@ func (EnvironProvider) Version() int {
 	return 0
 }
 
+// SupportsInstanceEgress is part of the EgressFirewallingProvider interface.
+// Egress rules are added to the Neutron security groups of the instances.
+func (EnvironProvider) SupportsInstanceEgress() bool {
+	return true
+}
+
 func (p EnvironProvider) Open(args environs.OpenParams) (environs.Environ, error) {
 	logger.Infof("opening model %q", args.Config.Name())
 	if err := validateCloudSpec(args.Cloud); err != nil {
@@ -293,6 +299,11 @@ type Environ struct {
 	neutronUnlocked *neutron.Client
 	volumeURL       *url.URL
 
+	// createdNetworkMutex protects createdNetworkID, which caches the
+	// ID of the network created for the model.
+	createdNetworkMutex sync.Mutex
+	createdNetworkID    string
+
 	// keystoneImageDataSource caches the result of getKeystoneImageSource.
 	keystoneImageDataSourceMutex sync.Mutex
 	keystoneImageDataSource      simplestreams.DataSource
@@ -345,6 +356,7 @@ func (inst *openstackInstance) String() string {
 }
 
 var _ instances.Instance = (*openstackInstance)(nil)
+var _ instances.InstanceEgressFirewaller = (*openstackInstance)(nil)
 
 func (inst *openstackInstance) Refresh(ctx context.ProviderCallContext) error {
 	inst.mu.Lock()
@@ -505,6 +517,16 @@ func (inst *openstackInstance) IngressRules(ctx context.ProviderCallContext, mac
 	return inst.e.firewaller.InstanceIngressRules(ctx, inst, machineId)
 }
 
+// SetEgressCIDRs implements instances.InstanceEgressFirewaller.
+func (inst *openstackInstance) SetEgressCIDRs(ctx context.ProviderCallContext, machineId string, cidrs []string) error {
+	return inst.e.firewaller.SetInstanceEgressCIDRs(ctx, inst, machineId, cidrs)
+}
+
+// EgressCIDRs implements instances.InstanceEgressFirewaller.
+func (inst *openstackInstance) EgressCIDRs(ctx context.ProviderCallContext, machineId string) ([]string, error) {
+	return inst.e.firewaller.InstanceEgressCIDRs(ctx, inst, machineId)
+}
+
 func (e *Environ) ecfg() *environConfig {
 	e.ecfgMutex.Lock()
 	ecfg := e.ecfgUnlocked
@@ -727,6 +749,9 @@ func (e *Environ) Create(ctx context.ProviderCallContext, args environs.CreatePa
 	}
 	// TODO(axw) 2016-08-04 #1609643
 	// Create global security group(s) here.
+	if e.usesCreatedNetwork() {
+		return e.ensureModelNetwork(ctx, args.ControllerUUID)
+	}
 	return nil
 }
 
@@ -738,6 +763,12 @@ func (e *Environ) Bootstrap(ctx environs.BootstrapContext, callCtx context.Provi
 		handleCredentialError(err, callCtx)
 		return nil, err
 	}
+	if e.usesCreatedNetwork() {
+		ctx.Infof("Creating network for model %q", e.name)
+		if err := e.ensureModelNetwork(callCtx, args.ControllerConfig.ControllerUUID()); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
 	result, err := common.Bootstrap(ctx, e, callCtx, args)
 	if err != nil {
 		handleCredentialError(err, callCtx)
@@ -1374,7 +1405,10 @@ func (e *Environ) networksForInstance() ([]nova.ServerNetworks, error) {
 		return nil, errors.Annotate(err, "getting initial networks")
 	}
 
-	usingNetwork := e.ecfg().network()
+	usingNetwork, err := e.internalNetwork()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	networkId, err := e.networking.ResolveNetwork(usingNetwork, false)
 	if err != nil {
 		if usingNetwork == "" {
@@ -1823,6 +1857,11 @@ func (e *Environ) Destroy(ctx context.ProviderCallContext) error {
 		handleCredentialError(err, ctx)
 		return errors.Trace(err)
 	}
+	if e.usesCreatedNetwork() {
+		if err := e.deleteCreatedNetworks(ctx, modelNetworkTag(e.uuid)); err != nil {
+			return errors.Annotate(err, "deleting model network")
+		}
+	}
 	return nil
 }
 
@@ -1843,6 +1882,11 @@ func (e *Environ) DestroyController(ctx context.ProviderCallContext, controllerU
 		handleCredentialError(err, ctx)
 		return errors.Trace(err)
 	}
+	if e.supportsNeutron() {
+		if err := e.deleteCreatedNetworks(ctx, controllerNetworksTag(controllerUUID)); err != nil {
+			return errors.Annotate(err, "deleting model networks")
+		}
+	}
 	return nil
 }
 
@@ -43,6 +43,7 @@ func (c *defaultConfigurator) GetConfigDefaults() schema.Defaults {
 		"use-floating-ip":      false,
 		"use-default-secgroup": false,
 		"network":              "",
+		"network-cidr":         "10.0.0.0/16",
 		"external-network":     "",
 		"use-openstack-gbp":    false,
 		"policy-target-group":  "",
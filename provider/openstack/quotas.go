@@ -0,0 +1,123 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"gopkg.in/goose.v2/client"
+	goosehttp "gopkg.in/goose.v2/http"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+var _ environs.QuotaChecker = (*Environ)(nil)
+
+// Names of the quotas reported to users.
+const (
+	quotaCPUs      = "vCPUs"
+	quotaInstances = "instances"
+	quotaRAM       = "RAM MB"
+)
+
+// absoluteLimits holds the compute limits of a project, and how much of
+// each is used, as reported by Nova. A limit of -1 means unlimited.
+type absoluteLimits struct {
+	MaxTotalCores      float64 `json:"maxTotalCores"`
+	MaxTotalInstances  float64 `json:"maxTotalInstances"`
+	MaxTotalRAMSize    float64 `json:"maxTotalRAMSize"`
+	TotalCoresUsed     float64 `json:"totalCoresUsed"`
+	TotalInstancesUsed float64 `json:"totalInstancesUsed"`
+	TotalRAMUsed       float64 `json:"totalRAMUsed"`
+}
+
+// computeLimitsClient is the subset of the Nova API needed to report the
+// quotas of a project. It is not provided by the goose nova client, so
+// it is implemented by novaLimitsClient.
+type computeLimitsClient interface {
+	AbsoluteLimits() (absoluteLimits, error)
+}
+
+// novaLimitsClient implements computeLimitsClient using requests made
+// directly to the Nova API.
+type novaLimitsClient struct {
+	client client.Client
+}
+
+// AbsoluteLimits returns the absolute compute limits of the project.
+func (c novaLimitsClient) AbsoluteLimits() (absoluteLimits, error) {
+	var resp struct {
+		Limits struct {
+			Absolute absoluteLimits `json:"absolute"`
+		} `json:"limits"`
+	}
+	err := c.client.SendRequest(client.GET, "compute", "v2", "limits", &goosehttp.RequestData{
+		RespValue:      &resp,
+		ExpectedStatus: []int{http.StatusOK},
+	})
+	if err != nil {
+		return absoluteLimits{}, errors.Annotate(err, "getting compute limits")
+	}
+	return resp.Limits.Absolute, nil
+}
+
+// Quotas is part of the environs.QuotaChecker interface. OpenStack
+// reports the compute quotas of the model's project.
+func (e *Environ) Quotas(ctx context.ProviderCallContext) ([]environs.Quota, error) {
+	quotas, err := projectQuotas(novaLimitsClient{client: e.client()})
+	handleCredentialError(err, ctx)
+	return quotas, errors.Trace(err)
+}
+
+func projectQuotas(client computeLimitsClient) ([]environs.Quota, error) {
+	limits, err := client.AbsoluteLimits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var quotas []environs.Quota
+	add := func(name string, limit, usage float64) {
+		if limit < 0 {
+			// The resource is not limited.
+			return
+		}
+		quotas = append(quotas, environs.Quota{Name: name, Limit: limit, Usage: usage})
+	}
+	add(quotaCPUs, limits.MaxTotalCores, limits.TotalCoresUsed)
+	add(quotaInstances, limits.MaxTotalInstances, limits.TotalInstancesUsed)
+	add(quotaRAM, limits.MaxTotalRAMSize, limits.TotalRAMUsed)
+	return quotas, nil
+}
+
+// CheckInstanceQuotas is part of the environs.QuotaChecker interface.
+// It checks the project has room for the vCPUs and memory of the flavor
+// StartInstance would choose, and for one more instance.
+func (e *Environ) CheckInstanceQuotas(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	spec, err := findInstanceSpec(e, instances.InstanceConstraint{
+		Region:      e.cloud().Region,
+		Series:      args.Tools.OneSeries(),
+		Arches:      args.Tools.Arches(),
+		Constraints: args.Constraints,
+	}, args.ImageMetadata)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	quotas, err := e.Quotas(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return environs.CheckQuotas(quotas, instanceQuotaRequirements(spec.InstanceType))
+}
+
+// instanceQuotaRequirements returns the quota needed to start an
+// instance of the given type.
+func instanceQuotaRequirements(instType instances.InstanceType) map[string]float64 {
+	return map[string]float64{
+		quotaInstances: 1,
+		quotaCPUs:      float64(instType.CpuCores),
+		quotaRAM:       float64(instType.Mem),
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/instances"
+)
+
+type quotasSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&quotasSuite{})
+
+func (s *quotasSuite) TestProjectQuotas(c *gc.C) {
+	client := &stubComputeLimitsClient{
+		Stub: &testing.Stub{},
+		limits: absoluteLimits{
+			MaxTotalCores:      20,
+			MaxTotalInstances:  10,
+			MaxTotalRAMSize:    -1,
+			TotalCoresUsed:     4,
+			TotalInstancesUsed: 2,
+			TotalRAMUsed:       8192,
+		},
+	}
+	quotas, err := projectQuotas(client)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(quotas, jc.DeepEquals, []environs.Quota{
+		{Name: "vCPUs", Limit: 20, Usage: 4},
+		{Name: "instances", Limit: 10, Usage: 2},
+	})
+	client.CheckCallNames(c, "AbsoluteLimits")
+}
+
+func (s *quotasSuite) TestProjectQuotasError(c *gc.C) {
+	client := &stubComputeLimitsClient{Stub: &testing.Stub{}}
+	client.SetErrors(errors.New("boom"))
+
+	_, err := projectQuotas(client)
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+func (s *quotasSuite) TestInstanceQuotaRequirements(c *gc.C) {
+	quotas := []environs.Quota{
+		{Name: "vCPUs", Limit: 20, Usage: 18},
+		{Name: "instances", Limit: 10, Usage: 2},
+		{Name: "RAM MB", Limit: 51200, Usage: 8192},
+	}
+	small := instances.InstanceType{CpuCores: 2, Mem: 4096}
+	c.Assert(environs.CheckQuotas(quotas, instanceQuotaRequirements(small)), jc.ErrorIsNil)
+
+	large := instances.InstanceType{CpuCores: 4, Mem: 8192}
+	err := environs.CheckQuotas(quotas, instanceQuotaRequirements(large))
+	c.Assert(err, gc.ErrorMatches, "quota exceeded: vCPUs")
+	c.Assert(environs.IsQuotaExceeded(err), jc.IsTrue)
+}
+
+type stubComputeLimitsClient struct {
+	*testing.Stub
+
+	limits absoluteLimits
+}
+
+func (c *stubComputeLimitsClient) AbsoluteLimits() (absoluteLimits, error) {
+	c.MethodCall(c, "AbsoluteLimits")
+	return c.limits, c.NextErr()
+}
@@ -0,0 +1,72 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"net/http"
+
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+// DialFunc is a function type for dialing Proxmox VE API clients.
+type DialFunc func(context.Context, proxmoxclient.Config) (Client, error)
+
+// Client is an interface for interacting with the Proxmox VE API.
+type Client interface {
+	Version(context.Context) (string, error)
+	Nodes(context.Context) ([]proxmoxclient.Node, error)
+	VirtualMachines(context.Context) ([]proxmoxclient.VirtualMachine, error)
+	VirtualMachineConfig(_ context.Context, node string, vmid int) (map[string]string, error)
+	UpdateVirtualMachineConfig(_ context.Context, node string, vmid int, config map[string]string) error
+	NextID(context.Context) (int, error)
+	CloneVirtualMachine(context.Context, proxmoxclient.CloneVirtualMachineParams) error
+	ResizeDisk(_ context.Context, node string, vmid int, disk string, sizeMiB uint64) error
+	StartVirtualMachine(_ context.Context, node string, vmid int) error
+	StopVirtualMachine(_ context.Context, node string, vmid int) error
+	DeleteVirtualMachine(_ context.Context, node string, vmid int) error
+	InterfaceAddresses(_ context.Context, node string, vmid int) ([]string, error)
+	Volumes(_ context.Context, node, storage string) ([]proxmoxclient.Volume, error)
+	CreateVolume(_ context.Context, node, storage string, vmid int, name string, sizeMiB uint64) (string, error)
+	DeleteVolume(_ context.Context, node, storage, volid string) error
+	UploadISO(_ context.Context, node, storage, filename string, content io.Reader) (string, error)
+}
+
+func dialClient(ctx context.Context, spec environs.CloudSpec, dial DialFunc) (Client, error) {
+	credAttrs := spec.Credential.Attributes()
+	config := proxmoxclient.Config{
+		Endpoint: spec.Endpoint,
+		Logger:   logger,
+	}
+	switch spec.Credential.AuthType() {
+	case cloud.AccessKeyAuthType:
+		config.TokenID = credAttrs[credAttrTokenID]
+		config.TokenSecret = credAttrs[credAttrTokenSecret]
+	default:
+		config.Username = credAttrs[credAttrUsername]
+		config.Password = credAttrs[credAttrPassword]
+	}
+	if len(spec.CACertificates) > 0 {
+		// Proxmox VE nodes use a self-signed cluster CA by default.
+		pool := x509.NewCertPool()
+		for _, cert := range spec.CACertificates {
+			pool.AppendCertsFromPEM([]byte(cert))
+		}
+		tlsConfig := utils.SecureTLSConfig()
+		tlsConfig.RootCAs = pool
+		config.HTTPClient = &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: tlsConfig,
+			},
+		}
+	}
+	return dial(ctx, config)
+}
@@ -0,0 +1,115 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+
+	"github.com/juju/juju/environs/config"
+)
+
+const (
+	cfgTemplatePrefix = "template-prefix"
+	cfgStorage        = "storage"
+	cfgISOStorage     = "iso-storage"
+	cfgNetworkBridge  = "network-bridge"
+
+	defaultTemplatePrefix = "ubuntu-"
+	defaultStorage        = "local-lvm"
+	defaultISOStorage     = "local"
+	defaultNetworkBridge  = "vmbr0"
+)
+
+var configFields = schema.Fields{
+	cfgTemplatePrefix: schema.String(),
+	cfgStorage:        schema.String(),
+	cfgISOStorage:     schema.String(),
+	cfgNetworkBridge:  schema.String(),
+}
+
+var configDefaultFields = schema.Defaults{
+	cfgTemplatePrefix: defaultTemplatePrefix,
+	cfgStorage:        defaultStorage,
+	cfgISOStorage:     defaultISOStorage,
+	cfgNetworkBridge:  defaultNetworkBridge,
+}
+
+var configImmutableFields = []string{
+	cfgStorage,
+}
+
+func validateConfig(cfg *config.Config, old *environConfig) (*environConfig, error) {
+	// Check sanity of juju-level fields.
+	var oldCfg *config.Config
+	if old != nil {
+		oldCfg = old.Config
+	}
+	if err := config.Validate(cfg, oldCfg); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	newAttrs, err := cfg.ValidateUnknownAttrs(configFields, configDefaultFields)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for field := range configFields {
+		if newAttrs[field] == "" {
+			return nil, errors.Errorf("%s: must not be empty", field)
+		}
+	}
+
+	// If an old config was supplied, check any immutable fields have not changed.
+	if old != nil {
+		for _, field := range configImmutableFields {
+			if old.attrs[field] != newAttrs[field] {
+				return nil, errors.Errorf(
+					"%s: cannot change from %v to %v",
+					field, old.attrs[field], newAttrs[field],
+				)
+			}
+		}
+	}
+
+	ecfg := &environConfig{
+		attrs: newAttrs,
+	}
+	// Merge the validated provider-specific fields into the original config,
+	// to ensure the object we return is internally consistent.
+	ecfg.Config, err = cfg.Apply(newAttrs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ecfg, nil
+}
+
+type environConfig struct {
+	*config.Config
+	attrs map[string]interface{}
+}
+
+// templatePrefix returns the prefix of the names of the templates from
+// which instances are cloned. The template for a series is named with
+// the prefix followed by the series, e.g. "ubuntu-bionic".
+func (c *environConfig) templatePrefix() string {
+	return c.attrs[cfgTemplatePrefix].(string)
+}
+
+// storage returns the name of the storage holding instance disks and
+// Juju volumes.
+func (c *environConfig) storage() string {
+	return c.attrs[cfgStorage].(string)
+}
+
+// isoStorage returns the name of the storage to which cloud-init seed
+// images are uploaded. It must allow ISO image content.
+func (c *environConfig) isoStorage() string {
+	return c.attrs[cfgISOStorage].(string)
+}
+
+// networkBridge returns the name of the node bridge that instance NICs
+// are attached to.
+func (c *environConfig) networkBridge() string {
+	return c.attrs[cfgNetworkBridge].(string)
+}
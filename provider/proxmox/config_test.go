@@ -0,0 +1,52 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+)
+
+type configSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+func (s *configSuite) TestDefaults(c *gc.C) {
+	ecfg, err := validateConfig(newConfig(c, nil), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.templatePrefix(), gc.Equals, "ubuntu-")
+	c.Check(ecfg.storage(), gc.Equals, "local-lvm")
+	c.Check(ecfg.isoStorage(), gc.Equals, "local")
+	c.Check(ecfg.networkBridge(), gc.Equals, "vmbr0")
+}
+
+func (s *configSuite) TestEmptyISOStorage(c *gc.C) {
+	_, err := validateConfig(newConfig(c, testing.Attrs{
+		"iso-storage": "",
+	}), nil)
+	c.Assert(err, gc.ErrorMatches, "iso-storage: must not be empty")
+}
+
+func (s *configSuite) TestStorageImmutable(c *gc.C) {
+	old, err := validateConfig(newConfig(c, nil), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validateConfig(newConfig(c, testing.Attrs{
+		"storage": "ceph",
+	}), old)
+	c.Assert(err, gc.ErrorMatches, "storage: cannot change from local-lvm to ceph")
+}
+
+func (s *configSuite) TestNetworkBridgeMutable(c *gc.C) {
+	old, err := validateConfig(newConfig(c, nil), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ecfg, err := validateConfig(newConfig(c, testing.Attrs{
+		"network-bridge": "vmbr1",
+	}), old)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ecfg.networkBridge(), gc.Equals, "vmbr1")
+}
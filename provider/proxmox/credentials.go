@@ -0,0 +1,60 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+)
+
+const (
+	credAttrTokenID     = "token-id"
+	credAttrTokenSecret = "token-secret"
+	credAttrUsername    = "username"
+	credAttrPassword    = "password"
+)
+
+type environProviderCredentials struct{}
+
+// CredentialSchemas is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) CredentialSchemas() map[cloud.AuthType]cloud.CredentialSchema {
+	return map[cloud.AuthType]cloud.CredentialSchema{
+		cloud.AccessKeyAuthType: {
+			{
+				credAttrTokenID, cloud.CredentialAttr{
+					Description: "The API token ID, including the user (e.g. juju@pve!automation)",
+				},
+			}, {
+				credAttrTokenSecret, cloud.CredentialAttr{
+					Description: "The API token secret",
+					Hidden:      true,
+				},
+			},
+		},
+		cloud.UserPassAuthType: {
+			{
+				credAttrUsername, cloud.CredentialAttr{
+					Description: "The user name, including the realm (e.g. juju@pve)",
+				},
+			}, {
+				credAttrPassword, cloud.CredentialAttr{
+					Description: "The password for the user",
+					Hidden:      true,
+				},
+			},
+		},
+	}
+}
+
+// DetectCredentials is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) DetectCredentials() (*cloud.CloudCredential, error) {
+	return nil, errors.NotFoundf("credentials")
+}
+
+// FinalizeCredential is part of the environs.ProviderCredentials interface.
+func (environProviderCredentials) FinalizeCredential(_ environs.FinalizeCredentialContext, args environs.FinalizeCredentialParams) (*cloud.Credential, error) {
+	return &args.Credential, nil
+}
@@ -0,0 +1,300 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+// descriptionKey is the virtual machine configuration option in which
+// Juju's tags are recorded, as key=value lines. Proxmox VE's own tags
+// cannot hold arbitrary values.
+const descriptionKey = "description"
+
+type environ struct {
+	name      string
+	uuid      string
+	cloud     environs.CloudSpec
+	provider  *environProvider
+	namespace instance.Namespace
+
+	lock sync.Mutex
+	ecfg *environConfig
+}
+
+var _ common.ZonedEnviron = (*environ)(nil)
+
+func newEnviron(provider *environProvider, spec environs.CloudSpec, cfg *config.Config) (*environ, error) {
+	ecfg, err := validateConfig(cfg, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	namespace, err := instance.NewNamespace(cfg.UUID())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &environ{
+		name:      ecfg.Name(),
+		uuid:      ecfg.UUID(),
+		cloud:     spec,
+		provider:  provider,
+		namespace: namespace,
+		ecfg:      ecfg,
+	}, nil
+}
+
+// withClient dials a client and calls f with it. Authentication
+// failures invalidate the credential.
+func (env *environ) withClient(callCtx callcontext.ProviderCallContext, f func(context.Context, Client) error) error {
+	ctx := context.Background()
+	client, err := dialClient(ctx, env.cloud, env.provider.dial)
+	if err != nil {
+		HandleCredentialError(err, callCtx)
+		return errors.Annotate(err, "dialing client")
+	}
+	err = f(ctx, client)
+	HandleCredentialError(err, callCtx)
+	return err
+}
+
+// Name returns the Environ's name.
+func (env *environ) Name() string {
+	return env.name
+}
+
+// Provider returns the EnvironProvider that created this Environ.
+func (env *environ) Provider() environs.EnvironProvider {
+	return env.provider
+}
+
+// SetConfig updates the Environ's configuration.
+func (env *environ) SetConfig(cfg *config.Config) error {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+
+	ecfg, err := validateConfig(cfg, env.ecfg)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env.ecfg = ecfg
+	return nil
+}
+
+// Config returns the configuration data with which the Environ was created.
+func (env *environ) Config() *config.Config {
+	return env.config().Config
+}
+
+func (env *environ) config() *environConfig {
+	env.lock.Lock()
+	defer env.lock.Unlock()
+	return env.ecfg
+}
+
+// PrepareForBootstrap is part of the Environ interface.
+func (env *environ) PrepareForBootstrap(ctx environs.BootstrapContext, controllerName string) error {
+	return nil
+}
+
+// Create is part of the Environ interface.
+func (env *environ) Create(callcontext.ProviderCallContext, environs.CreateParams) error {
+	return nil
+}
+
+// Bootstrap is part of the Environ interface.
+func (env *environ) Bootstrap(ctx environs.BootstrapContext, callCtx callcontext.ProviderCallContext, params environs.BootstrapParams) (*environs.BootstrapResult, error) {
+	return common.Bootstrap(ctx, env, callCtx, params)
+}
+
+// ControllerInstances is part of the Environ interface.
+func (env *environ) ControllerInstances(callCtx callcontext.ProviderCallContext, controllerUUID string) ([]instance.Id, error) {
+	var ids []instance.Id
+	err := env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		vms, err := env.prefixedVirtualMachines(ctx, client, env.namespace.Prefix())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, vm := range vms {
+			vmTags, err := virtualMachineTags(ctx, client, vm)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if vmTags[tags.JujuIsController] == "true" && vmTags[tags.JujuController] == controllerUUID {
+				ids = append(ids, instance.Id(vm.Name))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(ids) == 0 {
+		return nil, environs.ErrNoInstances
+	}
+	return ids, nil
+}
+
+// AdoptResources is part of the Environ interface.
+func (env *environ) AdoptResources(callCtx callcontext.ProviderCallContext, controllerUUID string, fromVersion version.Number) error {
+	return env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		vms, err := env.prefixedVirtualMachines(ctx, client, env.namespace.Prefix())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, vm := range vms {
+			vmTags, err := virtualMachineTags(ctx, client, vm)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			vmTags[tags.JujuController] = controllerUUID
+			if err := client.UpdateVirtualMachineConfig(ctx, vm.Node, vm.VMID, map[string]string{
+				descriptionKey: formatTags(vmTags),
+			}); err != nil {
+				return errors.Annotatef(err, "adopting instance %q", vm.Name)
+			}
+		}
+		return nil
+	})
+}
+
+// Destroy is part of the Environ interface.
+func (env *environ) Destroy(ctx callcontext.ProviderCallContext) error {
+	return common.Destroy(env, ctx)
+}
+
+// DestroyController implements the Environ interface.
+func (env *environ) DestroyController(callCtx callcontext.ProviderCallContext, controllerUUID string) error {
+	if err := env.Destroy(callCtx); err != nil {
+		return errors.Trace(err)
+	}
+	// Destroy all instances of hosted models with juju-controller-uuid
+	// matching the specified UUID.
+	var ids []instance.Id
+	err := env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		vms, err := env.prefixedVirtualMachines(ctx, client, tags.JujuTagPrefix)
+		if err != nil {
+			return errors.Annotate(err, "listing instances")
+		}
+		for _, vm := range vms {
+			vmTags, err := virtualMachineTags(ctx, client, vm)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if vmTags[tags.JujuController] == controllerUUID {
+				ids = append(ids, instance.Id(vm.Name))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(env.StopInstances(callCtx, ids...))
+}
+
+// PrecheckInstance is part of the Environ interface.
+func (env *environ) PrecheckInstance(ctx callcontext.ProviderCallContext, args environs.PrecheckInstanceParams) error {
+	if _, err := env.parsePlacement(ctx, args.Placement); err != nil {
+		return errors.Trace(err)
+	}
+	if args.Constraints.HasInstanceType() {
+		if _, err := findInstanceType(args.Constraints); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+var unsupportedConstraints = []string{
+	constraints.Container,
+	constraints.CpuPower,
+	constraints.Tags,
+	constraints.Spaces,
+}
+
+// ConstraintsValidator returns a Validator instance which
+// is used to validate and merge constraints.
+func (env *environ) ConstraintsValidator(ctx callcontext.ProviderCallContext) (constraints.Validator, error) {
+	validator := constraints.NewValidator()
+	validator.RegisterConflicts(
+		[]string{constraints.InstanceType},
+		[]string{constraints.Mem, constraints.Cores},
+	)
+	validator.RegisterUnsupported(unsupportedConstraints)
+	validator.RegisterVocabulary(constraints.Arch, []string{arch.AMD64})
+	validator.RegisterVocabulary(constraints.VirtType, []string{virtType})
+	instTypeNames := make([]string, len(allInstanceTypes))
+	for i, itype := range allInstanceTypes {
+		instTypeNames[i] = itype.Name
+	}
+	sort.Strings(instTypeNames)
+	validator.RegisterVocabulary(constraints.InstanceType, instTypeNames)
+	return validator, nil
+}
+
+// prefixedVirtualMachines returns the virtual machines, excluding
+// templates, whose names have the given prefix.
+func (env *environ) prefixedVirtualMachines(ctx context.Context, client Client, prefix string) ([]proxmoxclient.VirtualMachine, error) {
+	vms, err := client.VirtualMachines(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []proxmoxclient.VirtualMachine
+	for _, vm := range vms {
+		if !vm.IsTemplate() && strings.HasPrefix(vm.Name, prefix) {
+			result = append(result, vm)
+		}
+	}
+	return result, nil
+}
+
+// virtualMachineTags returns the Juju tags recorded in the virtual
+// machine's description.
+func virtualMachineTags(ctx context.Context, client Client, vm proxmoxclient.VirtualMachine) (map[string]string, error) {
+	config, err := client.VirtualMachineConfig(ctx, vm.Node, vm.VMID)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting configuration of %q", vm.Name)
+	}
+	return parseTags(config[descriptionKey]), nil
+}
+
+// parseTags parses key=value lines from a virtual machine description.
+func parseTags(description string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(description, "\n") {
+		parts := strings.SplitN(strings.TrimSpace(line), "=", 2)
+		if len(parts) == 2 {
+			result[parts[0]] = parts[1]
+		}
+	}
+	return result
+}
+
+// formatTags formats tags as sorted key=value lines for a virtual
+// machine description.
+func formatTags(tags map[string]string) string {
+	lines := make([]string, 0, len(tags))
+	for k, v := range tags {
+		lines = append(lines, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
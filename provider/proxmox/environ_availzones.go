@@ -0,0 +1,98 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/common"
+)
+
+// nodeZone is an availability zone backed by a cluster node.
+type nodeZone struct {
+	name      string
+	available bool
+}
+
+// Name implements common.AvailabilityZone.
+func (z *nodeZone) Name() string {
+	return z.name
+}
+
+// Available implements common.AvailabilityZone.
+func (z *nodeZone) Available() bool {
+	return z.available
+}
+
+// AvailabilityZones is part of the common.ZonedEnviron interface. There
+// is one zone per cluster node; nodes that are not online are reported
+// as unavailable.
+func (env *environ) AvailabilityZones(callCtx callcontext.ProviderCallContext) ([]common.AvailabilityZone, error) {
+	var zones []common.AvailabilityZone
+	err := env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		nodes, err := client.Nodes(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, node := range nodes {
+			zones = append(zones, &nodeZone{name: node.Name, available: node.Online()})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return zones, nil
+}
+
+// InstanceAvailabilityZoneNames is part of the common.ZonedEnviron interface.
+func (env *environ) InstanceAvailabilityZoneNames(ctx callcontext.ProviderCallContext, ids []instance.Id) ([]string, error) {
+	insts, err := env.Instances(ctx, ids)
+	if err != nil && err != environs.ErrPartialInstances {
+		return nil, err
+	}
+	zones := make([]string, len(insts))
+	for i, inst := range insts {
+		if inst != nil {
+			zones[i] = inst.(*environInstance).vm.Node
+		}
+	}
+	return zones, err
+}
+
+// DeriveAvailabilityZones is part of the common.ZonedEnviron interface.
+func (env *environ) DeriveAvailabilityZones(ctx callcontext.ProviderCallContext, args environs.StartInstanceParams) ([]string, error) {
+	zone, err := env.parsePlacement(ctx, args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone == "" {
+		return nil, nil
+	}
+	return []string{zone}, nil
+}
+
+// parsePlacement returns the availability zone named by a placement
+// directive of the form "zone=<node>", or an empty string if there is
+// no placement.
+func (env *environ) parsePlacement(ctx callcontext.ProviderCallContext, placement string) (string, error) {
+	if placement == "" {
+		return "", nil
+	}
+	pos := strings.IndexRune(placement, '=')
+	if pos == -1 || placement[:pos] != "zone" {
+		return "", errors.Errorf("unknown placement directive: %v", placement)
+	}
+	zone := placement[pos+1:]
+	if err := common.ValidateAvailabilityZone(env, ctx, zone); err != nil {
+		return "", errors.Trace(err)
+	}
+	return zone, nil
+}
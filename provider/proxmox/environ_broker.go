@@ -0,0 +1,360 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/cloudconfig/cloudinit"
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/cloudconfig/providerinit"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/environs"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+	"github.com/juju/juju/tools"
+)
+
+const (
+	// rootDisk is the configuration key of the templates' boot disk.
+	rootDisk = "scsi0"
+
+	// seedDrive is the configuration key of the CD-ROM drive holding
+	// the cloud-init seed image.
+	seedDrive = "ide2"
+)
+
+// MaintainInstance is specified in the InstanceBroker interface.
+func (*environ) MaintainInstance(ctx callcontext.ProviderCallContext, args environs.StartInstanceParams) error {
+	return nil
+}
+
+// StartInstance implements environs.InstanceBroker. The instance is
+// cloned from the template for the series, and configured by cloud-init
+// from a NoCloud seed image.
+func (env *environ) StartInstance(callCtx callcontext.ProviderCallContext, args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if args.InstanceConfig == nil {
+		return nil, errors.New("instance configuration is nil")
+	}
+	series := args.InstanceConfig.Series
+	logger.Debugf("StartInstance: %q, %s", args.InstanceConfig.MachineId, series)
+
+	zone, err := env.parsePlacement(callCtx, args.Placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if zone == "" {
+		zone = args.AvailabilityZone
+	}
+	itype, err := findInstanceType(args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	matchingTools, err := args.Tools.Match(tools.Filter{Arch: arch.AMD64})
+	if err != nil {
+		return nil, errors.Errorf("chosen architecture %v not present in %v", arch.AMD64, args.Tools.Arches())
+	}
+	if err := args.InstanceConfig.SetTools(matchingTools); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := instancecfg.FinishInstanceConfig(args.InstanceConfig, env.Config()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	cloudCfg, err := cloudinit.New(series)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	userData, err := providerinit.ComposeUserData(args.InstanceConfig, cloudCfg, proxmoxRenderer{})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot make user data")
+	}
+	logger.Debugf("proxmox user data; %d bytes", len(userData))
+
+	name, err := env.namespace.Hostname(args.InstanceConfig.MachineId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	statusCallback := func(msg string) {
+		if args.StatusCallback != nil {
+			args.StatusCallback(status.Provisioning, msg, nil)
+		}
+	}
+	var result *environs.StartInstanceResult
+	err = env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		if zone == "" {
+			node, err := defaultNode(ctx, client)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			zone = node
+		}
+		template, err := env.findTemplate(ctx, client, series, zone)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		vmid, err := client.NextID(ctx)
+		if err != nil {
+			return errors.Annotate(err, "allocating VM ID")
+		}
+		vm := proxmoxclient.VirtualMachine{
+			VMID:   vmid,
+			Name:   name,
+			Node:   zone,
+			Type:   "qemu",
+			Status: "running",
+		}
+
+		statusCallback(fmt.Sprintf("cloning template %q", template.Name))
+		if err := client.CloneVirtualMachine(ctx, proxmoxclient.CloneVirtualMachineParams{
+			Node:       template.Node,
+			VMID:       template.VMID,
+			NewID:      vmid,
+			Name:       name,
+			TargetNode: zone,
+			// Linked clones must be on the template's node.
+			Full:    template.Node != zone,
+			Storage: env.config().storage(),
+		}); err != nil {
+			return errors.Annotatef(err, "cloning template %q", template.Name)
+		}
+
+		statusCallback("configuring instance")
+		hwc, err := env.configureInstance(ctx, client, vm, template, itype, args, userData)
+		if err == nil {
+			err = client.StartVirtualMachine(ctx, zone, vmid)
+		}
+		if err != nil {
+			env.deleteInstance(ctx, client, vm)
+			if args.StatusCallback != nil {
+				args.StatusCallback(status.ProvisioningError, err.Error(), nil)
+			}
+			return errors.Annotatef(err, "creating instance %q on %s", name, zone)
+		}
+		logger.Infof("started instance %q on %s", name, zone)
+		result = &environs.StartInstanceResult{
+			Instance: &environInstance{vm: vm, env: env},
+			Hardware: hwc,
+		}
+		return nil
+	})
+	return result, errors.Trace(err)
+}
+
+// defaultNode returns the first online node, for instances without a
+// placement or availability zone.
+func defaultNode(ctx context.Context, client Client) (string, error) {
+	nodes, err := client.Nodes(ctx)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for _, node := range nodes {
+		if node.Online() {
+			return node.Name, nil
+		}
+	}
+	return "", errors.New("no online Proxmox VE nodes")
+}
+
+// findTemplate returns the template for the series, preferring one on
+// the given node.
+func (env *environ) findTemplate(ctx context.Context, client Client, series, node string) (proxmoxclient.VirtualMachine, error) {
+	vms, err := client.VirtualMachines(ctx)
+	if err != nil {
+		return proxmoxclient.VirtualMachine{}, errors.Trace(err)
+	}
+	name := env.config().templatePrefix() + series
+	var found []proxmoxclient.VirtualMachine
+	for _, vm := range vms {
+		if vm.IsTemplate() && vm.Name == name {
+			if vm.Node == node {
+				return vm, nil
+			}
+			found = append(found, vm)
+		}
+	}
+	if len(found) == 0 {
+		return proxmoxclient.VirtualMachine{}, errors.NotFoundf("template %q", name)
+	}
+	return found[0], nil
+}
+
+// configureInstance sizes a newly cloned virtual machine, attaches its
+// seed image and network interface, and records its tags.
+func (env *environ) configureInstance(
+	ctx context.Context, client Client,
+	vm, template proxmoxclient.VirtualMachine,
+	itype instances.InstanceType,
+	args environs.StartInstanceParams,
+	userData []byte,
+) (*instance.HardwareCharacteristics, error) {
+	ecfg := env.config()
+	dir, err := ioutil.TempDir("", "juju-proxmox-")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer os.RemoveAll(dir)
+	seedPath, err := writeSeedImage(env.provider.run, dir, vm.Name, userData)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	f, err := os.Open(seedPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer f.Close()
+	seedVolume, err := client.UploadISO(ctx, vm.Node, ecfg.isoStorage(), seedImageName(vm.Name), f)
+	if err != nil {
+		return nil, errors.Annotate(err, "uploading seed image")
+	}
+
+	// Only Juju-defined tags are recorded on the virtual machine, so
+	// that they can be used to find the model's and controller's
+	// instances.
+	vmTags := make(map[string]string)
+	for k, v := range args.InstanceConfig.Tags {
+		if strings.HasPrefix(k, tags.JujuTagPrefix) {
+			vmTags[k] = v
+		}
+	}
+	if err := client.UpdateVirtualMachineConfig(ctx, vm.Node, vm.VMID, map[string]string{
+		"cores":        strconv.FormatUint(itype.CpuCores, 10),
+		"memory":       strconv.FormatUint(itype.Mem, 10),
+		"net0":         "virtio,bridge=" + ecfg.networkBridge(),
+		seedDrive:      seedVolume + ",media=cdrom",
+		"agent":        "1",
+		"onboot":       "1",
+		descriptionKey: formatTags(vmTags),
+	}); err != nil {
+		return nil, errors.Annotate(err, "configuring instance")
+	}
+
+	rootDiskMiB := template.MaxDisk >> 20
+	if cons := args.Constraints; cons.RootDisk != nil && *cons.RootDisk > rootDiskMiB {
+		if err := client.ResizeDisk(ctx, vm.Node, vm.VMID, rootDisk, *cons.RootDisk); err != nil {
+			return nil, errors.Annotate(err, "resizing root disk")
+		}
+		rootDiskMiB = *cons.RootDisk
+	}
+
+	instArch := arch.AMD64
+	return &instance.HardwareCharacteristics{
+		Arch:             &instArch,
+		CpuCores:         &itype.CpuCores,
+		Mem:              &itype.Mem,
+		RootDisk:         &rootDiskMiB,
+		AvailabilityZone: &vm.Node,
+		VirtType:         &virtType,
+	}, nil
+}
+
+// deleteInstance stops and deletes the virtual machine, with its disks
+// and seed image. Errors are logged rather than returned, as the
+// instance may be partially created.
+func (env *environ) deleteInstance(ctx context.Context, client Client, vm proxmoxclient.VirtualMachine) {
+	if err := client.StopVirtualMachine(ctx, vm.Node, vm.VMID); err != nil {
+		logger.Debugf("stopping %q: %v", vm.Name, err)
+	}
+	if err := client.DeleteVirtualMachine(ctx, vm.Node, vm.VMID); err != nil && !proxmoxclient.IsNotFound(err) {
+		logger.Errorf("deleting %q: %v", vm.Name, err)
+	}
+	isoStorage := env.config().isoStorage()
+	seedVolume := fmt.Sprintf("%s:iso/%s", isoStorage, seedImageName(vm.Name))
+	if err := client.DeleteVolume(ctx, vm.Node, isoStorage, seedVolume); err != nil && !proxmoxclient.IsNotFound(err) {
+		logger.Debugf("deleting seed image of %q: %v", vm.Name, err)
+	}
+}
+
+// AllInstances implements environs.InstanceBroker.
+func (env *environ) AllInstances(callCtx callcontext.ProviderCallContext) ([]instances.Instance, error) {
+	return env.allInstances(callCtx, false)
+}
+
+// AllRunningInstances implements environs.InstanceBroker.
+func (env *environ) AllRunningInstances(callCtx callcontext.ProviderCallContext) ([]instances.Instance, error) {
+	return env.allInstances(callCtx, true)
+}
+
+func (env *environ) allInstances(callCtx callcontext.ProviderCallContext, runningOnly bool) ([]instances.Instance, error) {
+	var result []instances.Instance
+	err := env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		vms, err := env.prefixedVirtualMachines(ctx, client, env.namespace.Prefix())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, vm := range vms {
+			if !runningOnly || vm.Status == "running" {
+				result = append(result, &environInstance{vm: vm, env: env})
+			}
+		}
+		return nil
+	})
+	return result, errors.Trace(err)
+}
+
+// Instances implements environs.InstanceBroker.
+func (env *environ) Instances(callCtx callcontext.ProviderCallContext, ids []instance.Id) ([]instances.Instance, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	insts, err := env.AllInstances(callCtx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byName := make(map[instance.Id]instances.Instance)
+	for _, inst := range insts {
+		byName[inst.Id()] = inst
+	}
+
+	var found int
+	result := make([]instances.Instance, len(ids))
+	for i, id := range ids {
+		if inst, ok := byName[id]; ok {
+			result[i] = inst
+			found++
+		}
+	}
+	if found == 0 {
+		return nil, environs.ErrNoInstances
+	} else if found != len(ids) {
+		return result, environs.ErrPartialInstances
+	}
+	return result, nil
+}
+
+// StopInstances implements environs.InstanceBroker.
+func (env *environ) StopInstances(callCtx callcontext.ProviderCallContext, ids ...instance.Id) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	stop := make(map[string]bool)
+	for _, id := range ids {
+		stop[string(id)] = true
+	}
+	return env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		vms, err := env.prefixedVirtualMachines(ctx, client, tags.JujuTagPrefix)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, vm := range vms {
+			if stop[vm.Name] {
+				logger.Debugf("stopping instance %q on %s", vm.Name, vm.Node)
+				env.deleteInstance(ctx, client, vm)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,292 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/arch"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cloudconfig/instancecfg"
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/tags"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+)
+
+type environBrokerSuite struct {
+	coretesting.BaseSuite
+
+	dialStub testing.Stub
+	client   *fakeClient
+	env      *environ
+	callCtx  callcontext.ProviderCallContext
+}
+
+var _ = gc.Suite(&environBrokerSuite{})
+
+func (s *environBrokerSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dialStub.ResetCalls()
+	s.client = newFakeClient()
+	s.client.vms = []proxmoxclient.VirtualMachine{
+		{VMID: 9000, Name: "ubuntu-bionic", Node: "pve1", Type: "qemu", Template: 1, MaxDisk: 8 << 30},
+		{VMID: 9001, Name: "ubuntu-bionic", Node: "pve2", Type: "qemu", Template: 1, MaxDisk: 8 << 30},
+		{VMID: 9002, Name: "ubuntu-focal", Node: "pve1", Type: "qemu", Template: 1, MaxDisk: 8 << 30},
+	}
+	provider := &environProvider{
+		dial: newFakeDialFunc(&s.dialStub, s.client),
+		run:  fakeRun,
+	}
+	env, err := newEnviron(provider, fakeCloudSpec(), newConfig(c, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+	s.callCtx = callcontext.NewCloudCallContext()
+}
+
+func (s *environBrokerSuite) startInstanceArgs(c *gc.C, cons constraints.Value) environs.StartInstanceParams {
+	instanceConfig, err := instancecfg.NewBootstrapInstanceConfig(
+		coretesting.FakeControllerConfig(), cons, cons, "bionic", "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	instanceConfig.Tags = map[string]string{
+		tags.JujuController:   coretesting.ControllerTag.Id(),
+		tags.JujuIsController: "true",
+		"owner":               "someone",
+	}
+	tools := coretools.List{{
+		Version: version.Binary{
+			Number: version.MustParse("2.8.0"),
+			Arch:   arch.AMD64,
+			Series: "bionic",
+		},
+		URL: "https://example.org",
+	}}
+	return environs.StartInstanceParams{
+		ControllerUUID: coretesting.ControllerTag.Id(),
+		InstanceConfig: instanceConfig,
+		Tools:          tools,
+		Constraints:    cons,
+	}
+}
+
+func (s *environBrokerSuite) addInstance(vmid int, name, node, status string) {
+	s.client.vms = append(s.client.vms, proxmoxclient.VirtualMachine{
+		VMID: vmid, Name: name, Node: node, Type: "qemu", Status: status,
+	})
+}
+
+func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
+	result, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c, constraints.MustParse("mem=3G root-disk=20G")))
+	c.Assert(err, jc.ErrorIsNil)
+
+	name, err := s.env.namespace.Hostname("0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Instance.Id(), gc.Equals, instance.Id(name))
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "pve1")
+	c.Assert(*result.Hardware.CpuCores, gc.Equals, uint64(2))
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(4096))
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(20480))
+
+	s.client.CheckCallNames(c,
+		"Nodes", "VirtualMachines", "NextID", "CloneVirtualMachine",
+		"UploadISO", "UpdateVirtualMachineConfig", "ResizeDisk", "StartVirtualMachine",
+	)
+	calls := s.client.Calls()
+	c.Assert(calls[3].Args[0], jc.DeepEquals, proxmoxclient.CloneVirtualMachineParams{
+		Node:       "pve1",
+		VMID:       9000,
+		NewID:      100,
+		Name:       name,
+		TargetNode: "pve1",
+		Storage:    "local-lvm",
+	})
+	c.Assert(calls[4].Args[:3], jc.DeepEquals, []interface{}{"pve1", "local", name + "-seed.iso"})
+	c.Assert(calls[5].Args[2], jc.DeepEquals, map[string]string{
+		"cores":  "2",
+		"memory": "4096",
+		"net0":   "virtio,bridge=vmbr0",
+		"ide2":   "local:iso/" + name + "-seed.iso,media=cdrom",
+		"agent":  "1",
+		"onboot": "1",
+		// Only Juju tags are recorded.
+		"description": "juju-controller-uuid=" + coretesting.ControllerTag.Id() + "\njuju-is-controller=true",
+	})
+	calls[6].CheckArgs(c, "pve1", 100, "scsi0", uint64(20480))
+}
+
+func (s *environBrokerSuite) TestStartInstanceOtherNode(c *gc.C) {
+	args := s.startInstanceArgs(c, constraints.Value{})
+	args.Placement = "zone=pve2"
+	result, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.AvailabilityZone, gc.Equals, "pve2")
+	c.Assert(*result.Hardware.RootDisk, gc.Equals, uint64(8192))
+
+	// The template on the instance's node is preferred.
+	s.client.CheckCallNames(c,
+		"Nodes", "VirtualMachines", "NextID", "CloneVirtualMachine",
+		"UploadISO", "UpdateVirtualMachineConfig", "StartVirtualMachine",
+	)
+	clone := s.client.Calls()[3].Args[0].(proxmoxclient.CloneVirtualMachineParams)
+	c.Assert(clone.VMID, gc.Equals, 9001)
+	c.Assert(clone.Full, jc.IsFalse)
+}
+
+func (s *environBrokerSuite) TestStartInstanceFullClone(c *gc.C) {
+	s.client.vms = s.client.vms[:1]
+	args := s.startInstanceArgs(c, constraints.Value{})
+	args.AvailabilityZone = "pve2"
+	_, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, jc.ErrorIsNil)
+	clone := s.client.Calls()[2].Args[0].(proxmoxclient.CloneVirtualMachineParams)
+	c.Assert(clone.Node, gc.Equals, "pve1")
+	c.Assert(clone.TargetNode, gc.Equals, "pve2")
+	c.Assert(clone.Full, jc.IsTrue)
+}
+
+func (s *environBrokerSuite) TestStartInstanceUnavailableZone(c *gc.C) {
+	args := s.startInstanceArgs(c, constraints.Value{})
+	args.Placement = "zone=pve3"
+	_, err := s.env.StartInstance(s.callCtx, args)
+	c.Assert(err, gc.ErrorMatches, `availability zone "pve3" is unavailable`)
+}
+
+func (s *environBrokerSuite) TestStartInstanceInstanceType(c *gc.C) {
+	result, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c, constraints.MustParse("instance-type=highmem-large")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*result.Hardware.CpuCores, gc.Equals, uint64(4))
+	c.Assert(*result.Hardware.Mem, gc.Equals, uint64(32768))
+}
+
+func (s *environBrokerSuite) TestStartInstanceNoTemplate(c *gc.C) {
+	s.client.vms = nil
+	_, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c, constraints.Value{}))
+	c.Assert(err, gc.ErrorMatches, `template "ubuntu-bionic" not found`)
+}
+
+func (s *environBrokerSuite) TestStartInstanceCleansUpOnFailure(c *gc.C) {
+	s.client.SetErrors(nil, nil, nil, nil, nil, errors.New("bad config"))
+	_, err := s.env.StartInstance(s.callCtx, s.startInstanceArgs(c, constraints.Value{}))
+	c.Assert(err, gc.ErrorMatches, `creating instance ".*" on pve1: configuring instance: bad config`)
+	s.client.CheckCallNames(c,
+		"Nodes", "VirtualMachines", "NextID", "CloneVirtualMachine",
+		"UploadISO", "UpdateVirtualMachineConfig",
+		"StopVirtualMachine", "DeleteVirtualMachine", "DeleteVolume",
+	)
+}
+
+func (s *environBrokerSuite) TestAllInstances(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.addInstance(100, prefix+"0", "pve1", "running")
+	s.addInstance(101, prefix+"1", "pve2", "stopped")
+	s.addInstance(102, "juju-0ther0-0", "pve1", "running")
+	s.addInstance(103, "ubuntu", "pve1", "running")
+
+	insts, err := s.env.AllInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(insts, gc.HasLen, 2)
+	c.Assert(insts[0].Id(), gc.Equals, instance.Id(prefix+"0"))
+	c.Assert(insts[1].Status(s.callCtx).Message, gc.Equals, "stopped")
+
+	running, err := s.env.AllRunningInstances(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 1)
+}
+
+func (s *environBrokerSuite) TestInstancesPartial(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.addInstance(100, prefix+"0", "pve1", "running")
+	insts, err := s.env.Instances(s.callCtx, []instance.Id{instance.Id(prefix + "0"), "missing"})
+	c.Assert(err, gc.Equals, environs.ErrPartialInstances)
+	c.Assert(insts[0], gc.NotNil)
+	c.Assert(insts[1], gc.IsNil)
+
+	_, err = s.env.Instances(s.callCtx, []instance.Id{"missing"})
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environBrokerSuite) TestInstanceAvailabilityZoneNames(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.addInstance(100, prefix+"0", "pve2", "running")
+	zones, err := s.env.InstanceAvailabilityZoneNames(s.callCtx, []instance.Id{instance.Id(prefix + "0")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, jc.DeepEquals, []string{"pve2"})
+}
+
+func (s *environBrokerSuite) TestStopInstances(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.addInstance(100, prefix+"0", "pve1", "running")
+	s.addInstance(101, prefix+"1", "pve2", "running")
+	err := s.env.StopInstances(s.callCtx, instance.Id(prefix+"1"))
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCalls(c, []testing.StubCall{
+		{"VirtualMachines", nil},
+		{"StopVirtualMachine", []interface{}{"pve2", 101}},
+		{"DeleteVirtualMachine", []interface{}{"pve2", 101}},
+		{"DeleteVolume", []interface{}{"pve2", "local", "local:iso/" + prefix + "1-seed.iso"}},
+	})
+}
+
+func (s *environBrokerSuite) TestControllerInstances(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.addInstance(100, prefix+"0", "pve1", "running")
+	s.addInstance(101, prefix+"1", "pve1", "running")
+	s.client.configs[100] = map[string]string{
+		"description": "juju-controller-uuid=" + coretesting.ControllerTag.Id() + "\njuju-is-controller=true",
+	}
+	ids, err := s.env.ControllerInstances(s.callCtx, coretesting.ControllerTag.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []instance.Id{instance.Id(prefix + "0")})
+
+	_, err = s.env.ControllerInstances(s.callCtx, "other")
+	c.Assert(err, gc.Equals, environs.ErrNoInstances)
+}
+
+func (s *environBrokerSuite) TestAdoptResources(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	s.addInstance(100, prefix+"0", "pve1", "running")
+	s.client.configs[100] = map[string]string{
+		"description": "juju-controller-uuid=old\njuju-model-uuid=" + s.env.uuid,
+	}
+	err := s.env.AdoptResources(s.callCtx, "new", version.MustParse("2.8.0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.client.configs[100]["description"], gc.Equals, "juju-controller-uuid=new\njuju-model-uuid="+s.env.uuid)
+}
+
+func (s *environBrokerSuite) TestAvailabilityZones(c *gc.C) {
+	zones, err := s.env.AvailabilityZones(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(zones, gc.HasLen, 3)
+	c.Assert(zones[0].Name(), gc.Equals, "pve1")
+	c.Assert(zones[0].Available(), jc.IsTrue)
+	c.Assert(zones[2].Available(), jc.IsFalse)
+}
+
+func (s *environBrokerSuite) TestInstanceTypes(c *gc.C) {
+	result, err := s.env.InstanceTypes(s.callCtx, constraints.MustParse("cores=8"))
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, itype := range result.InstanceTypes {
+		names = append(names, itype.Name)
+	}
+	c.Assert(names, jc.DeepEquals, []string{"highcpu-large", "xlarge", "highcpu-xlarge", "highmem-xlarge", "2xlarge", "4xlarge"})
+}
+
+func (s *environBrokerSuite) TestConstraintsValidator(c *gc.C) {
+	validator, err := s.env.ConstraintsValidator(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = validator.Validate(constraints.MustParse("instance-type=huge"))
+	c.Assert(err, gc.ErrorMatches, `invalid constraint value: instance-type=huge\nvalid values are:.*`)
+	unsupported, err := validator.Validate(constraints.MustParse("instance-type=large tags=foo"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(unsupported, jc.DeepEquals, []string{"tags"})
+}
@@ -0,0 +1,16 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+// HandleCredentialError marks the current credential as invalid if
+// the passed Proxmox VE error indicates it should be.
+func HandleCredentialError(err error, ctx context.ProviderCallContext) {
+	common.HandleCredentialError(proxmoxclient.IsUnauthorized, err, ctx)
+}
@@ -0,0 +1,20 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+func init() {
+	dial := func(ctx context.Context, config proxmoxclient.Config) (Client, error) {
+		return proxmoxclient.Dial(ctx, config)
+	}
+	environs.RegisterProvider(providerType, NewEnvironProvider(EnvironProviderConfig{
+		Dial: dial,
+	}))
+}
@@ -0,0 +1,83 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	corenetwork "github.com/juju/juju/core/network"
+	"github.com/juju/juju/core/status"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+// environInstance is a Proxmox VE virtual machine.
+type environInstance struct {
+	vm  proxmoxclient.VirtualMachine
+	env *environ
+}
+
+var _ instances.Instance = (*environInstance)(nil)
+
+// Id implements instances.Instance.
+func (inst *environInstance) Id() instance.Id {
+	return instance.Id(inst.vm.Name)
+}
+
+// Status implements instances.Instance, mapping the virtual machine
+// status reported by Proxmox VE to a Juju status.
+func (inst *environInstance) Status(ctx callcontext.ProviderCallContext) instance.Status {
+	var jujuStatus status.Status
+	switch inst.vm.Status {
+	case "running":
+		jujuStatus = status.Running
+	case "stopped", "paused":
+		jujuStatus = status.Empty
+	default:
+		jujuStatus = status.Pending
+	}
+	return instance.Status{
+		Status:  jujuStatus,
+		Message: inst.vm.Status,
+	}
+}
+
+// Addresses implements instances.Instance. Addresses are reported by
+// the QEMU guest agent, which must be installed in the template.
+func (inst *environInstance) Addresses(callCtx callcontext.ProviderCallContext) (corenetwork.ProviderAddresses, error) {
+	var addrs []string
+	err := inst.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		var err error
+		addrs, err = client.InterfaceAddresses(ctx, inst.vm.Node, inst.vm.VMID)
+		return errors.Trace(err)
+	})
+	if err != nil {
+		// The guest agent is not running until the instance has booted.
+		logger.Debugf("getting addresses of %q: %v", inst.vm.Name, err)
+		return nil, nil
+	}
+	return corenetwork.NewProviderAddresses(addrs...), nil
+}
+
+// OpenPorts implements instances.InstanceFirewaller. Instances are
+// attached directly to a node bridge; the Proxmox VE firewall is not
+// managed by Juju.
+func (inst *environInstance) OpenPorts(ctx callcontext.ProviderCallContext, machineID string, rules []network.IngressRule) error {
+	return errors.NotImplementedf("OpenPorts")
+}
+
+// ClosePorts implements instances.InstanceFirewaller.
+func (inst *environInstance) ClosePorts(ctx callcontext.ProviderCallContext, machineID string, rules []network.IngressRule) error {
+	return errors.NotImplementedf("ClosePorts")
+}
+
+// IngressRules implements instances.InstanceFirewaller.
+func (inst *environInstance) IngressRules(ctx callcontext.ProviderCallContext, machineID string) ([]network.IngressRule, error) {
+	return nil, errors.NotImplementedf("IngressRules")
+}
@@ -0,0 +1,66 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/utils/arch"
+
+	"github.com/juju/juju/core/constraints"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/environs/instances"
+)
+
+var _ environs.InstanceTypesFetcher = (*environ)(nil)
+
+var virtType = "kvm"
+
+// allInstanceTypes is the catalogue of instance types from which
+// instances are sized. Proxmox VE has no notion of instance types; these
+// give constraints a predictable mapping onto VM sizes. The cost is
+// nominal, and orders the types by size.
+var allInstanceTypes = []instances.InstanceType{
+	newInstanceType("small", 1, 2048, 1),
+	newInstanceType("medium", 2, 4096, 2),
+	newInstanceType("large", 4, 8192, 4),
+	newInstanceType("xlarge", 8, 16384, 8),
+	newInstanceType("2xlarge", 16, 32768, 16),
+	newInstanceType("4xlarge", 32, 65536, 32),
+	newInstanceType("highmem-large", 4, 32768, 6),
+	newInstanceType("highmem-xlarge", 8, 65536, 12),
+	newInstanceType("highcpu-large", 8, 8192, 6),
+	newInstanceType("highcpu-xlarge", 16, 16384, 12),
+}
+
+func newInstanceType(name string, cores, memMiB, cost uint64) instances.InstanceType {
+	return instances.InstanceType{
+		Id:       name,
+		Name:     name,
+		Arches:   []string{arch.AMD64},
+		CpuCores: cores,
+		Mem:      memMiB,
+		Cost:     cost,
+		VirtType: &virtType,
+	}
+}
+
+// InstanceTypes implements InstanceTypesFetcher.
+func (env *environ) InstanceTypes(ctx context.ProviderCallContext, c constraints.Value) (instances.InstanceTypesWithCostMetadata, error) {
+	iTypes, err := instances.MatchingInstanceTypes(allInstanceTypes, "", c)
+	if err != nil {
+		return instances.InstanceTypesWithCostMetadata{}, errors.Trace(err)
+	}
+	return instances.InstanceTypesWithCostMetadata{InstanceTypes: iTypes}, nil
+}
+
+// findInstanceType returns the cheapest instance type that satisfies
+// the constraints.
+func findInstanceType(cons constraints.Value) (instances.InstanceType, error) {
+	iTypes, err := instances.MatchingInstanceTypes(allInstanceTypes, "", cons)
+	if err != nil {
+		return instances.InstanceType{}, errors.Trace(err)
+	}
+	return iTypes[0], nil
+}
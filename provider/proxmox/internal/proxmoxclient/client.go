@@ -0,0 +1,314 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package proxmoxclient is a small client for the Proxmox VE REST API,
+// exposing the subset of functionality that the Juju provider requires.
+package proxmoxclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+)
+
+const (
+	// apiPath is the path of the JSON API, relative to the endpoint.
+	apiPath = "/api2/json"
+
+	// taskPollInterval is how often the status of a long running
+	// task is checked.
+	taskPollInterval = 2 * time.Second
+)
+
+// Config holds the parameters for connecting to a Proxmox VE cluster.
+// Either TokenID and TokenSecret, or Username and Password, must be
+// specified.
+type Config struct {
+	// Endpoint is the URL of any node in the cluster,
+	// e.g. https://pve1.example.com:8006.
+	Endpoint string
+
+	// TokenID and TokenSecret identify an API token,
+	// e.g. "juju@pve!automation".
+	TokenID     string
+	TokenSecret string
+
+	// Username and Password are used to obtain an authentication
+	// ticket, e.g. "juju@pve".
+	Username string
+	Password string
+
+	// HTTPClient is the client used to make requests. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+
+	// Clock is used to wait for long running tasks. If nil,
+	// clock.WallClock is used.
+	Clock clock.Clock
+
+	Logger loggo.Logger
+}
+
+// Validate checks that the config is usable.
+func (config Config) Validate() error {
+	if config.Endpoint == "" {
+		return errors.NotValidf("empty Endpoint")
+	}
+	if config.TokenID == "" && config.Username == "" {
+		return errors.NotValidf("missing TokenID or Username")
+	}
+	return nil
+}
+
+// Client is a Proxmox VE API client.
+type Client struct {
+	baseURL    string
+	config     Config
+	httpClient *http.Client
+	clock      clock.Clock
+	logger     loggo.Logger
+
+	// ticket and csrfToken are set when authenticating with a
+	// username and password.
+	ticket    string
+	csrfToken string
+}
+
+// Dial returns a new Client for the cluster described by config. If
+// config specifies a username and password, an authentication ticket
+// is obtained before Dial returns.
+func Dial(ctx context.Context, config Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	c := &Client{
+		baseURL:    strings.TrimSuffix(config.Endpoint, "/") + apiPath,
+		config:     config,
+		httpClient: config.HTTPClient,
+		clock:      config.Clock,
+		logger:     config.Logger,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.clock == nil {
+		c.clock = clock.WallClock
+	}
+	if config.TokenID == "" {
+		if err := c.login(ctx); err != nil {
+			return nil, errors.Annotate(err, "logging in")
+		}
+	}
+	return c, nil
+}
+
+// login obtains an authentication ticket for the configured user.
+func (c *Client) login(ctx context.Context) error {
+	var result struct {
+		Ticket    string `json:"ticket"`
+		CSRFToken string `json:"CSRFPreventionToken"`
+	}
+	err := c.do(ctx, http.MethodPost, "/access/ticket", url.Values{
+		"username": {c.config.Username},
+		"password": {c.config.Password},
+	}, &result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.ticket = result.Ticket
+	c.csrfToken = result.CSRFToken
+	return nil
+}
+
+// Error is an error response from the API.
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+// Error is part of the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("proxmox API error %d: %s", e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err was caused by a request for a
+// resource that does not exist.
+func IsNotFound(err error) bool {
+	e, ok := errors.Cause(err).(*Error)
+	if !ok {
+		return false
+	}
+	// Proxmox reports most missing resources as internal errors.
+	return e.StatusCode == http.StatusNotFound ||
+		strings.Contains(e.Message, "does not exist") ||
+		strings.Contains(e.Message, "no such")
+}
+
+// IsUnauthorized reports whether err was caused by invalid or
+// insufficient credentials.
+func IsUnauthorized(err error) bool {
+	e, ok := errors.Cause(err).(*Error)
+	return ok && (e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden)
+}
+
+// do makes an API request. Parameters are passed in the query string
+// for GET and DELETE requests, and as a form otherwise. If result is not
+// nil, the "data" member of the response is decoded into it.
+func (c *Client) do(ctx context.Context, method, path string, params url.Values, result interface{}) error {
+	var body io.Reader
+	u := c.baseURL + path
+	if method == http.MethodGet || method == http.MethodDelete {
+		if len(params) > 0 {
+			u += "?" + params.Encode()
+		}
+	} else {
+		body = strings.NewReader(params.Encode())
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return errors.Trace(c.send(ctx, req, result))
+}
+
+// send authenticates and sends the request, decoding the response.
+func (c *Client) send(ctx context.Context, req *http.Request, result interface{}) error {
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	switch {
+	case c.config.TokenID != "":
+		req.Header.Set("Authorization", fmt.Sprintf("PVEAPIToken=%s=%s", c.config.TokenID, c.config.TokenSecret))
+	case c.ticket != "":
+		req.AddCookie(&http.Cookie{Name: "PVEAuthCookie", Value: c.ticket})
+		if req.Method != http.MethodGet {
+			req.Header.Set("CSRFPreventionToken", c.csrfToken)
+		}
+	}
+	c.logger.Tracef("%s %s", req.Method, req.URL.Path)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// The reason phrase carries the error message; the body may
+		// additionally describe invalid parameters.
+		message := strings.TrimSpace(strings.TrimPrefix(resp.Status, fmt.Sprint(resp.StatusCode)))
+		var errResp struct {
+			Errors map[string]string `json:"errors"`
+		}
+		if json.Unmarshal(data, &errResp) == nil {
+			for param, msg := range errResp.Errors {
+				message += fmt.Sprintf("; %s: %s", param, strings.TrimSpace(msg))
+			}
+		}
+		return &Error{StatusCode: resp.StatusCode, Message: message}
+	}
+	if result == nil {
+		return nil
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return errors.Annotatef(err, "decoding response from %s", req.URL.Path)
+	}
+	if len(envelope.Data) == 0 {
+		return nil
+	}
+	return errors.Annotatef(json.Unmarshal(envelope.Data, result), "decoding response from %s", req.URL.Path)
+}
+
+// Version returns the version of Proxmox VE running on the endpoint.
+func (c *Client) Version(ctx context.Context) (string, error) {
+	var result struct {
+		Version string `json:"version"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/version", nil, &result); err != nil {
+		return "", errors.Trace(err)
+	}
+	return result.Version, nil
+}
+
+// Node describes a node in the cluster.
+type Node struct {
+	Name   string `json:"node"`
+	Status string `json:"status"`
+	MaxCPU int    `json:"maxcpu"`
+	MaxMem uint64 `json:"maxmem"`
+}
+
+// Online reports whether the node is available.
+func (n Node) Online() bool {
+	return n.Status == "online"
+}
+
+// Nodes returns the nodes in the cluster.
+func (c *Client) Nodes(ctx context.Context) ([]Node, error) {
+	var nodes []Node
+	if err := c.do(ctx, http.MethodGet, "/nodes", nil, &nodes); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return nodes, nil
+}
+
+// task is the status of a long running task.
+type task struct {
+	Status     string `json:"status"`
+	ExitStatus string `json:"exitstatus"`
+}
+
+// waitTask waits for the task with the given ID, as returned by an
+// asynchronous API call, to finish.
+func (c *Client) waitTask(ctx context.Context, node, upid string) error {
+	if upid == "" {
+		// The request completed synchronously.
+		return nil
+	}
+	path := fmt.Sprintf("/nodes/%s/tasks/%s/status", url.PathEscape(node), url.PathEscape(upid))
+	for {
+		var t task
+		if err := c.do(ctx, http.MethodGet, path, nil, &t); err != nil {
+			return errors.Annotatef(err, "getting status of task %s", upid)
+		}
+		if t.Status == "stopped" {
+			if t.ExitStatus != "OK" {
+				return errors.Errorf("task %s failed: %s", upid, t.ExitStatus)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Trace(ctx.Err())
+		case <-c.clock.After(taskPollInterval):
+		}
+	}
+}
+
+// doTask makes an API request that starts a task, and waits for the
+// task to finish.
+func (c *Client) doTask(ctx context.Context, method, node, path string, params url.Values) error {
+	var upid string
+	if err := c.do(ctx, method, path, params, &upid); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(c.waitTask(ctx, node, upid))
+}
@@ -0,0 +1,280 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmoxclient_test
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+// fakeResponse is a canned response from the fake API server.
+type fakeResponse struct {
+	status int
+	body   string
+}
+
+// fakeServer is a stand-in for the Proxmox VE API, serving canned
+// responses keyed by "<method> <path>".
+type fakeServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	requests  []*http.Request
+	forms     []string
+	responses map[string][]fakeResponse
+}
+
+func newFakeServer() *fakeServer {
+	s := &fakeServer{responses: make(map[string][]fakeResponse)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// respond queues a response for the given request. The last response
+// queued for a request is repeated.
+func (s *fakeServer) respond(key string, status int, body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[key] = append(s.responses[key], fakeResponse{status: status, body: body})
+}
+
+func (s *fakeServer) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, _ := ioutil.ReadAll(req.Body)
+	s.requests = append(s.requests, req)
+	s.forms = append(s.forms, string(body))
+
+	key := req.Method + " " + strings.TrimPrefix(req.URL.Path, "/api2/json")
+	queue := s.responses[key]
+	if len(queue) == 0 {
+		http.Error(w, "not found", http.StatusNotImplemented)
+		return
+	}
+	resp := queue[0]
+	if len(queue) > 1 {
+		s.responses[key] = queue[1:]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(resp.status)
+	fmt.Fprint(w, resp.body)
+}
+
+func (s *fakeServer) paths() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var paths []string
+	for _, req := range s.requests {
+		paths = append(paths, req.Method+" "+strings.TrimPrefix(req.URL.Path, "/api2/json"))
+	}
+	return paths
+}
+
+type clientSuite struct {
+	testing.IsolationSuite
+
+	server *fakeServer
+	clock  *testclock.Clock
+	client *proxmoxclient.Client
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.server = newFakeServer()
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.clock = testclock.NewClock(time.Time{})
+
+	client, err := proxmoxclient.Dial(context.Background(), proxmoxclient.Config{
+		Endpoint:    s.server.URL,
+		TokenID:     "juju@pve!automation",
+		TokenSecret: "sekrit",
+		Clock:       s.clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.client = client
+}
+
+func (s *clientSuite) TestDialValidates(c *gc.C) {
+	_, err := proxmoxclient.Dial(context.Background(), proxmoxclient.Config{
+		Endpoint: s.server.URL,
+	})
+	c.Assert(err, gc.ErrorMatches, "missing TokenID or Username not valid")
+}
+
+func (s *clientSuite) TestTokenAuthentication(c *gc.C) {
+	s.server.respond("GET /version", http.StatusOK, `{"data":{"version":"6.2-4"}}`)
+	version, err := s.client.Version(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "6.2-4")
+	c.Assert(s.server.requests[0].Header.Get("Authorization"), gc.Equals, "PVEAPIToken=juju@pve!automation=sekrit")
+}
+
+func (s *clientSuite) TestTicketAuthentication(c *gc.C) {
+	s.server.respond("POST /access/ticket", http.StatusOK, `{"data":{"ticket":"PVE:juju@pve:ABC","CSRFPreventionToken":"csrf"}}`)
+	s.server.respond("POST /nodes/pve1/qemu/100/status/start", http.StatusOK, `{"data":null}`)
+	client, err := proxmoxclient.Dial(context.Background(), proxmoxclient.Config{
+		Endpoint: s.server.URL,
+		Username: "juju@pve",
+		Password: "password",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.server.forms[0], gc.Equals, "password=password&username=juju%40pve")
+
+	err = client.StartVirtualMachine(context.Background(), "pve1", 100)
+	c.Assert(err, jc.ErrorIsNil)
+	req := s.server.requests[1]
+	cookie, err := req.Cookie("PVEAuthCookie")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cookie.Value, gc.Equals, "PVE:juju@pve:ABC")
+	c.Assert(req.Header.Get("CSRFPreventionToken"), gc.Equals, "csrf")
+}
+
+func (s *clientSuite) TestErrors(c *gc.C) {
+	s.server.respond("POST /nodes/pve1/qemu/101/config", http.StatusBadRequest, `{"data":null,"errors":{"cores":"value must be at least 1\n"}}`)
+	err := s.client.UpdateVirtualMachineConfig(context.Background(), "pve1", 101, map[string]string{"cores": "0"})
+	c.Assert(err, gc.ErrorMatches, "proxmox API error 400: Bad Request; cores: value must be at least 1")
+
+	s.server.respond("GET /nodes", http.StatusUnauthorized, `{"data":null}`)
+	_, err = s.client.Nodes(context.Background())
+	c.Assert(err, jc.Satisfies, proxmoxclient.IsUnauthorized)
+}
+
+func (s *clientSuite) TestIsNotFound(c *gc.C) {
+	c.Assert(proxmoxclient.IsNotFound(&proxmoxclient.Error{StatusCode: 500, Message: "Configuration file 'nodes/pve1/qemu-server/101.conf' does not exist"}), jc.IsTrue)
+	c.Assert(proxmoxclient.IsNotFound(errors.Trace(&proxmoxclient.Error{StatusCode: 404})), jc.IsTrue)
+	c.Assert(proxmoxclient.IsNotFound(&proxmoxclient.Error{StatusCode: 500, Message: "timeout"}), jc.IsFalse)
+	c.Assert(proxmoxclient.IsNotFound(errors.New("does not exist")), jc.IsFalse)
+}
+
+func (s *clientSuite) TestNodes(c *gc.C) {
+	s.server.respond("GET /nodes", http.StatusOK, `{"data":[
+		{"node":"pve1","status":"online","maxcpu":16,"maxmem":68719476736},
+		{"node":"pve2","status":"offline"}
+	]}`)
+	nodes, err := s.client.Nodes(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nodes, jc.DeepEquals, []proxmoxclient.Node{
+		{Name: "pve1", Status: "online", MaxCPU: 16, MaxMem: 68719476736},
+		{Name: "pve2", Status: "offline"},
+	})
+	c.Assert(nodes[0].Online(), jc.IsTrue)
+	c.Assert(nodes[1].Online(), jc.IsFalse)
+}
+
+func (s *clientSuite) TestVirtualMachines(c *gc.C) {
+	s.server.respond("GET /cluster/resources", http.StatusOK, `{"data":[
+		{"id":"qemu/101","vmid":101,"name":"juju-06f00d-0","node":"pve2","type":"qemu","status":"running"},
+		{"id":"lxc/102","vmid":102,"name":"ct","node":"pve1","type":"lxc","status":"running"},
+		{"id":"qemu/9000","vmid":9000,"name":"ubuntu-bionic","node":"pve1","type":"qemu","status":"stopped","template":1}
+	]}`)
+	vms, err := s.client.VirtualMachines(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vms, gc.HasLen, 2)
+	c.Assert(vms[0].Name, gc.Equals, "juju-06f00d-0")
+	c.Assert(vms[0].IsTemplate(), jc.IsFalse)
+	c.Assert(vms[1].IsTemplate(), jc.IsTrue)
+	c.Assert(s.server.requests[0].URL.RawQuery, gc.Equals, "type=vm")
+}
+
+func (s *clientSuite) TestVirtualMachineConfig(c *gc.C) {
+	s.server.respond("GET /nodes/pve1/qemu/101/config", http.StatusOK, `{"data":{
+		"cores":2,"memory":4096,"scsi0":"local-lvm:vm-101-disk-0,size=8G","description":"juju-is-controller=true\n"
+	}}`)
+	config, err := s.client.VirtualMachineConfig(context.Background(), "pve1", 101)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, map[string]string{
+		"cores":       "2",
+		"memory":      "4096",
+		"scsi0":       "local-lvm:vm-101-disk-0,size=8G",
+		"description": "juju-is-controller=true\n",
+	})
+}
+
+func (s *clientSuite) TestCloneWaitsForTask(c *gc.C) {
+	const upid = "UPID:pve1:00001234:0000ABCD:5E8F0000:qmclone:9000:juju@pve!automation:"
+	s.server.respond("POST /nodes/pve1/qemu/9000/clone", http.StatusOK, `{"data":"`+upid+`"}`)
+	s.server.respond("GET /nodes/pve1/tasks/"+upid+"/status", http.StatusOK, `{"data":{"status":"running"}}`)
+	s.server.respond("GET /nodes/pve1/tasks/"+upid+"/status", http.StatusOK, `{"data":{"status":"stopped","exitstatus":"OK"}}`)
+
+	done := make(chan error)
+	go func() {
+		done <- s.client.CloneVirtualMachine(context.Background(), proxmoxclient.CloneVirtualMachineParams{
+			Node:       "pve1",
+			VMID:       9000,
+			NewID:      101,
+			Name:       "juju-06f00d-0",
+			TargetNode: "pve2",
+			Full:       true,
+			Storage:    "local-lvm",
+		})
+	}()
+	c.Assert(s.clock.WaitAdvance(2*time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	select {
+	case err := <-done:
+		c.Assert(err, jc.ErrorIsNil)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for clone")
+	}
+	c.Assert(s.server.forms[0], gc.Equals, "full=1&name=juju-06f00d-0&newid=101&storage=local-lvm&target=pve2")
+	c.Assert(s.server.paths(), gc.HasLen, 3)
+}
+
+func (s *clientSuite) TestTaskFailure(c *gc.C) {
+	s.server.respond("DELETE /nodes/pve1/qemu/101", http.StatusOK, `{"data":"UPID:1"}`)
+	s.server.respond("GET /nodes/pve1/tasks/UPID:1/status", http.StatusOK, `{"data":{"status":"stopped","exitstatus":"VM is locked (clone)"}}`)
+	err := s.client.DeleteVirtualMachine(context.Background(), "pve1", 101)
+	c.Assert(err, gc.ErrorMatches, `task UPID:1 failed: VM is locked \(clone\)`)
+	c.Assert(s.server.requests[0].URL.RawQuery, gc.Equals, "purge=1")
+}
+
+func (s *clientSuite) TestNextID(c *gc.C) {
+	s.server.respond("GET /cluster/nextid", http.StatusOK, `{"data":"105"}`)
+	id, err := s.client.NextID(context.Background())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, 105)
+}
+
+func (s *clientSuite) TestInterfaceAddresses(c *gc.C) {
+	s.server.respond("GET /nodes/pve1/qemu/101/agent/network-get-interfaces", http.StatusOK, `{"data":{"result":[
+		{"name":"lo","ip-addresses":[{"ip-address":"127.0.0.1"}]},
+		{"name":"eth0","ip-addresses":[{"ip-address":"10.0.0.5"},{"ip-address":"fe80::1"},{"ip-address":"2001:db8::5"}]}
+	]}}`)
+	addrs, err := s.client.InterfaceAddresses(context.Background(), "pve1", 101)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addrs, jc.DeepEquals, []string{"10.0.0.5", "2001:db8::5"})
+}
+
+func (s *clientSuite) TestCreateVolume(c *gc.C) {
+	s.server.respond("POST /nodes/pve1/storage/local-lvm/content", http.StatusOK, `{"data":"local-lvm:vm-101-juju-06f00d-volume-0"}`)
+	volid, err := s.client.CreateVolume(context.Background(), "pve1", "local-lvm", 101, "vm-101-juju-06f00d-volume-0", 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volid, gc.Equals, "local-lvm:vm-101-juju-06f00d-volume-0")
+	c.Assert(s.server.forms[0], gc.Equals, "filename=vm-101-juju-06f00d-volume-0&size=1024M&vmid=101")
+}
+
+func (s *clientSuite) TestUploadISO(c *gc.C) {
+	s.server.respond("POST /nodes/pve1/storage/local/upload", http.StatusOK, `{"data":null}`)
+	volid, err := s.client.UploadISO(context.Background(), "pve1", "local", "juju-06f00d-0-seed.iso", strings.NewReader("iso"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volid, gc.Equals, "local:iso/juju-06f00d-0-seed.iso")
+	c.Assert(s.server.requests[0].Header.Get("Content-Type"), jc.HasPrefix, "multipart/form-data")
+	c.Assert(s.server.forms[0], jc.Contains, `filename="juju-06f00d-0-seed.iso"`)
+}
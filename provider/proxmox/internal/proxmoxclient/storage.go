@@ -0,0 +1,103 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmoxclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/juju/errors"
+)
+
+// Volume describes a volume in a storage.
+type Volume struct {
+	// VolID is the storage-qualified volume ID,
+	// e.g. "local-lvm:vm-100-disk-1".
+	VolID   string `json:"volid"`
+	Size    uint64 `json:"size"`
+	VMID    int    `json:"vmid"`
+	Content string `json:"content"`
+}
+
+func storagePath(node, storage string, elem ...string) string {
+	path := fmt.Sprintf("/nodes/%s/storage/%s", url.PathEscape(node), url.PathEscape(storage))
+	for _, e := range elem {
+		path += "/" + url.PathEscape(e)
+	}
+	return path
+}
+
+// Volumes returns the disk image volumes in the storage on the node.
+func (c *Client) Volumes(ctx context.Context, node, storage string) ([]Volume, error) {
+	var volumes []Volume
+	err := c.do(ctx, http.MethodGet, storagePath(node, storage, "content"), url.Values{
+		"content": {"images"},
+	}, &volumes)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return volumes, nil
+}
+
+// CreateVolume creates a disk image volume of the given size in MiB in
+// the storage on the node, owned by the virtual machine with ID vmid.
+// The name must be of the form "vm-<vmid>-<suffix>". The new volume's
+// ID is returned.
+func (c *Client) CreateVolume(ctx context.Context, node, storage string, vmid int, name string, sizeMiB uint64) (string, error) {
+	var volid string
+	err := c.do(ctx, http.MethodPost, storagePath(node, storage, "content"), url.Values{
+		"vmid":     {strconv.Itoa(vmid)},
+		"filename": {name},
+		"size":     {fmt.Sprintf("%dM", sizeMiB)},
+	}, &volid)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return volid, nil
+}
+
+// DeleteVolume deletes the volume from the storage on the node.
+func (c *Client) DeleteVolume(ctx context.Context, node, storage, volid string) error {
+	return errors.Trace(c.doTask(ctx, http.MethodDelete, node, storagePath(node, storage, "content", volid), nil))
+}
+
+// UploadISO uploads an ISO image with the given file name to the
+// storage on the node, returning its volume ID.
+func (c *Client) UploadISO(ctx context.Context, node, storage, filename string, content io.Reader) (string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("content", "iso"); err != nil {
+		return "", errors.Trace(err)
+	}
+	part, err := w.CreateFormFile("filename", filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := w.Close(); err != nil {
+		return "", errors.Trace(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+storagePath(node, storage, "upload"), &buf)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	var upid string
+	if err := c.send(ctx, req, &upid); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := c.waitTask(ctx, node, upid); err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("%s:iso/%s", storage, filename), nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmoxclient
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// VirtualMachine describes a QEMU virtual machine or template, as
+// reported by the cluster resources API.
+type VirtualMachine struct {
+	VMID     int    `json:"vmid"`
+	Name     string `json:"name"`
+	Node     string `json:"node"`
+	Type     string `json:"type"`
+	Status   string `json:"status"`
+	Template int    `json:"template"`
+	MaxCPU   int    `json:"maxcpu"`
+	MaxMem   uint64 `json:"maxmem"`
+	MaxDisk  uint64 `json:"maxdisk"`
+}
+
+// IsTemplate reports whether the virtual machine is a template.
+func (vm VirtualMachine) IsTemplate() bool {
+	return vm.Template == 1
+}
+
+func vmPath(node string, vmid int, elem ...string) string {
+	path := fmt.Sprintf("/nodes/%s/qemu/%d", url.PathEscape(node), vmid)
+	for _, e := range elem {
+		path += "/" + e
+	}
+	return path
+}
+
+// VirtualMachines returns every QEMU virtual machine and template in
+// the cluster, sorted by ID.
+func (c *Client) VirtualMachines(ctx context.Context) ([]VirtualMachine, error) {
+	var resources []VirtualMachine
+	err := c.do(ctx, http.MethodGet, "/cluster/resources", url.Values{"type": {"vm"}}, &resources)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var vms []VirtualMachine
+	for _, r := range resources {
+		// Containers are also reported as "vm" resources.
+		if r.Type == "qemu" {
+			vms = append(vms, r)
+		}
+	}
+	sort.Slice(vms, func(i, j int) bool {
+		return vms[i].VMID < vms[j].VMID
+	})
+	return vms, nil
+}
+
+// VirtualMachineConfig returns the configuration of the virtual
+// machine, with every value as a string.
+func (c *Client) VirtualMachineConfig(ctx context.Context, node string, vmid int) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := c.do(ctx, http.MethodGet, vmPath(node, vmid, "config"), nil, &raw); err != nil {
+		return nil, errors.Trace(err)
+	}
+	config := make(map[string]string, len(raw))
+	for k, v := range raw {
+		config[k] = fmt.Sprint(v)
+	}
+	return config, nil
+}
+
+// UpdateVirtualMachineConfig sets the given configuration options of the
+// virtual machine. Options may be removed by naming them in the "delete"
+// option.
+func (c *Client) UpdateVirtualMachineConfig(ctx context.Context, node string, vmid int, config map[string]string) error {
+	params := make(url.Values)
+	for k, v := range config {
+		params.Set(k, v)
+	}
+	return errors.Trace(c.doTask(ctx, http.MethodPost, node, vmPath(node, vmid, "config"), params))
+}
+
+// NextID returns a free virtual machine ID.
+func (c *Client) NextID(ctx context.Context) (int, error) {
+	// The ID is returned as a string.
+	var id string
+	if err := c.do(ctx, http.MethodGet, "/cluster/nextid", nil, &id); err != nil {
+		return 0, errors.Trace(err)
+	}
+	return strconv.Atoi(id)
+}
+
+// CloneVirtualMachineParams holds the parameters for cloning a template.
+type CloneVirtualMachineParams struct {
+	// Node and VMID identify the template to clone.
+	Node string
+	VMID int
+
+	// NewID and Name are the ID and name of the new virtual machine.
+	NewID int
+	Name  string
+
+	// TargetNode, if not empty, is the node on which to create the
+	// clone. Cloning to another node requires a full clone, unless
+	// the template is on shared storage.
+	TargetNode string
+
+	// Full requests a full copy of the template's disks, rather than
+	// a linked clone.
+	Full bool
+
+	// Storage, if not empty, is the storage in which a full clone's
+	// disks are created.
+	Storage string
+
+	Description string
+}
+
+// CloneVirtualMachine clones a template, waiting for the clone to
+// complete.
+func (c *Client) CloneVirtualMachine(ctx context.Context, args CloneVirtualMachineParams) error {
+	params := url.Values{
+		"newid": {strconv.Itoa(args.NewID)},
+		"name":  {args.Name},
+	}
+	if args.TargetNode != "" && args.TargetNode != args.Node {
+		params.Set("target", args.TargetNode)
+	}
+	if args.Full {
+		params.Set("full", "1")
+		if args.Storage != "" {
+			params.Set("storage", args.Storage)
+		}
+	}
+	if args.Description != "" {
+		params.Set("description", args.Description)
+	}
+	return errors.Trace(c.doTask(ctx, http.MethodPost, args.Node, vmPath(args.Node, args.VMID, "clone"), params))
+}
+
+// ResizeDisk grows the named disk of the virtual machine to the given
+// size in MiB. Disks cannot be shrunk.
+func (c *Client) ResizeDisk(ctx context.Context, node string, vmid int, disk string, sizeMiB uint64) error {
+	params := url.Values{
+		"disk": {disk},
+		"size": {fmt.Sprintf("%dM", sizeMiB)},
+	}
+	// Resizing is synchronous in older releases, and returns a task
+	// in newer ones; doTask handles both.
+	return errors.Trace(c.doTask(ctx, http.MethodPut, node, vmPath(node, vmid, "resize"), params))
+}
+
+// StartVirtualMachine starts the virtual machine.
+func (c *Client) StartVirtualMachine(ctx context.Context, node string, vmid int) error {
+	return errors.Trace(c.doTask(ctx, http.MethodPost, node, vmPath(node, vmid, "status", "start"), nil))
+}
+
+// StopVirtualMachine stops the virtual machine immediately.
+func (c *Client) StopVirtualMachine(ctx context.Context, node string, vmid int) error {
+	return errors.Trace(c.doTask(ctx, http.MethodPost, node, vmPath(node, vmid, "status", "stop"), nil))
+}
+
+// DeleteVirtualMachine deletes the stopped virtual machine, along with
+// its disks and any backup or replication jobs that refer to it.
+func (c *Client) DeleteVirtualMachine(ctx context.Context, node string, vmid int) error {
+	params := url.Values{"purge": {"1"}}
+	return errors.Trace(c.doTask(ctx, http.MethodDelete, node, vmPath(node, vmid), params))
+}
+
+// InterfaceAddresses returns the IP addresses of the virtual machine's
+// network interfaces, excluding loopback, as reported by the QEMU guest
+// agent.
+func (c *Client) InterfaceAddresses(ctx context.Context, node string, vmid int) ([]string, error) {
+	var result struct {
+		Result []struct {
+			Name        string `json:"name"`
+			IPAddresses []struct {
+				Address string `json:"ip-address"`
+			} `json:"ip-addresses"`
+		} `json:"result"`
+	}
+	path := vmPath(node, vmid, "agent", "network-get-interfaces")
+	if err := c.do(ctx, http.MethodGet, path, nil, &result); err != nil {
+		return nil, errors.Trace(err)
+	}
+	var addrs []string
+	for _, iface := range result.Result {
+		if iface.Name == "lo" {
+			continue
+		}
+		for _, addr := range iface.IPAddresses {
+			if !strings.HasPrefix(addr.Address, "fe80:") {
+				addrs = append(addrs, addr.Address)
+			}
+		}
+	}
+	return addrs, nil
+}
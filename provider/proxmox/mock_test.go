@@ -0,0 +1,198 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/juju/testing"
+
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+func newFakeDialFunc(dialStub *testing.Stub, client Client) DialFunc {
+	return func(ctx context.Context, config proxmoxclient.Config) (Client, error) {
+		dialStub.AddCall("Dial", config)
+		if err := dialStub.NextErr(); err != nil {
+			return nil, err
+		}
+		return client, nil
+	}
+}
+
+// fakeClient is an in-memory stand-in for a Proxmox VE cluster.
+type fakeClient struct {
+	// mu guards testing.Stub access, to ensure that the recorded
+	// method calls correspond to the errors returned.
+	mu sync.Mutex
+	testing.Stub
+
+	nodes   []proxmoxclient.Node
+	vms     []proxmoxclient.VirtualMachine
+	configs map[int]map[string]string
+	volumes map[string][]proxmoxclient.Volume
+	addrs   []string
+	nextID  int
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		nodes: []proxmoxclient.Node{
+			{Name: "pve1", Status: "online"},
+			{Name: "pve2", Status: "online"},
+			{Name: "pve3", Status: "offline"},
+		},
+		configs: make(map[int]map[string]string),
+		volumes: make(map[string][]proxmoxclient.Volume),
+		nextID:  100,
+	}
+}
+
+func (c *fakeClient) Version(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Version")
+	return "6.2-4", c.NextErr()
+}
+
+func (c *fakeClient) Nodes(ctx context.Context) ([]proxmoxclient.Node, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Nodes")
+	return c.nodes, c.NextErr()
+}
+
+func (c *fakeClient) VirtualMachines(ctx context.Context) ([]proxmoxclient.VirtualMachine, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "VirtualMachines")
+	return c.vms, c.NextErr()
+}
+
+func (c *fakeClient) VirtualMachineConfig(ctx context.Context, node string, vmid int) (map[string]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "VirtualMachineConfig", node, vmid)
+	config := make(map[string]string)
+	for k, v := range c.configs[vmid] {
+		config[k] = v
+	}
+	return config, c.NextErr()
+}
+
+func (c *fakeClient) UpdateVirtualMachineConfig(ctx context.Context, node string, vmid int, config map[string]string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "UpdateVirtualMachineConfig", node, vmid, config)
+	if err := c.NextErr(); err != nil {
+		return err
+	}
+	if c.configs[vmid] == nil {
+		c.configs[vmid] = make(map[string]string)
+	}
+	for k, v := range config {
+		if k == "delete" {
+			delete(c.configs[vmid], v)
+			continue
+		}
+		c.configs[vmid][k] = v
+	}
+	return nil
+}
+
+func (c *fakeClient) NextID(ctx context.Context) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "NextID")
+	return c.nextID, c.NextErr()
+}
+
+func (c *fakeClient) CloneVirtualMachine(ctx context.Context, args proxmoxclient.CloneVirtualMachineParams) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "CloneVirtualMachine", args)
+	return c.NextErr()
+}
+
+func (c *fakeClient) ResizeDisk(ctx context.Context, node string, vmid int, disk string, sizeMiB uint64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "ResizeDisk", node, vmid, disk, sizeMiB)
+	return c.NextErr()
+}
+
+func (c *fakeClient) StartVirtualMachine(ctx context.Context, node string, vmid int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "StartVirtualMachine", node, vmid)
+	return c.NextErr()
+}
+
+func (c *fakeClient) StopVirtualMachine(ctx context.Context, node string, vmid int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "StopVirtualMachine", node, vmid)
+	return c.NextErr()
+}
+
+func (c *fakeClient) DeleteVirtualMachine(ctx context.Context, node string, vmid int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DeleteVirtualMachine", node, vmid)
+	return c.NextErr()
+}
+
+func (c *fakeClient) InterfaceAddresses(ctx context.Context, node string, vmid int) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "InterfaceAddresses", node, vmid)
+	return c.addrs, c.NextErr()
+}
+
+func (c *fakeClient) Volumes(ctx context.Context, node, storage string) ([]proxmoxclient.Volume, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "Volumes", node, storage)
+	return c.volumes[node], c.NextErr()
+}
+
+func (c *fakeClient) CreateVolume(ctx context.Context, node, storage string, vmid int, name string, sizeMiB uint64) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "CreateVolume", node, storage, vmid, name, sizeMiB)
+	return fmt.Sprintf("%s:%s", storage, name), c.NextErr()
+}
+
+func (c *fakeClient) DeleteVolume(ctx context.Context, node, storage, volid string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MethodCall(c, "DeleteVolume", node, storage, volid)
+	return c.NextErr()
+}
+
+func (c *fakeClient) UploadISO(ctx context.Context, node, storage, filename string, content io.Reader) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return "", err
+	}
+	c.MethodCall(c, "UploadISO", node, storage, filename, string(data))
+	return fmt.Sprintf("%s:iso/%s", storage, filename), c.NextErr()
+}
+
+// fakeRun stands in for genisoimage, writing the output file.
+func fakeRun(dir, command string, args ...string) (string, error) {
+	for i, arg := range args {
+		if arg == "-output" {
+			return "", ioutil.WriteFile(args[i+1], []byte(strings.Join(args, " ")), 0644)
+		}
+	}
+	return "", nil
+}
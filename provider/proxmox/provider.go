@@ -0,0 +1,192 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package proxmox implements a Juju provider for Proxmox VE clusters,
+// using the Proxmox VE REST API. Instances are cloned from templates,
+// and each cluster node is an availability zone.
+package proxmox
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/jsonschema"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+)
+
+var logger = loggo.GetLogger("juju.provider.proxmox")
+
+const (
+	providerType = "proxmox"
+)
+
+var cloudSchema = &jsonschema.Schema{
+	Type:     []jsonschema.Type{jsonschema.ObjectType},
+	Required: []string{cloud.EndpointKey, cloud.AuthTypesKey},
+	Order:    []string{cloud.EndpointKey, cloud.AuthTypesKey, cloud.RegionsKey},
+	Properties: map[string]*jsonschema.Schema{
+		cloud.EndpointKey: {
+			Singular: "the API endpoint url of any cluster node (e.g. https://pve1:8006)",
+			Type:     []jsonschema.Type{jsonschema.StringType},
+			Format:   jsonschema.FormatURI,
+		},
+		cloud.AuthTypesKey: {
+			Singular:    "auth type",
+			Plural:      "auth types",
+			Type:        []jsonschema.Type{jsonschema.ArrayType},
+			UniqueItems: jsonschema.Bool(true),
+			Default:     string(cloud.AccessKeyAuthType),
+			Items: &jsonschema.ItemSpec{
+				Schemas: []*jsonschema.Schema{{
+					Type: []jsonschema.Type{jsonschema.StringType},
+					Enum: []interface{}{
+						string(cloud.AccessKeyAuthType),
+						string(cloud.UserPassAuthType),
+					},
+				}},
+			},
+		},
+		cloud.RegionsKey: {
+			Type:     []jsonschema.Type{jsonschema.ObjectType},
+			Singular: "region",
+			Plural:   "regions",
+			Default:  "default",
+			AdditionalProperties: &jsonschema.Schema{
+				Type:          []jsonschema.Type{jsonschema.ObjectType},
+				MaxProperties: jsonschema.Int(0),
+			},
+		},
+	},
+}
+
+type environProvider struct {
+	environProviderCredentials
+	dial DialFunc
+	run  runFunc
+}
+
+// EnvironProviderConfig contains configuration for the EnvironProvider.
+type EnvironProviderConfig struct {
+	// Dial is a function used for dialing Proxmox VE API clients.
+	Dial DialFunc
+}
+
+// NewEnvironProvider returns a new environs.EnvironProvider that will
+// dial Proxmox VE API clients with the given dial function.
+func NewEnvironProvider(config EnvironProviderConfig) environs.CloudEnvironProvider {
+	return &environProvider{
+		dial: config.Dial,
+		run:  run,
+	}
+}
+
+// Version implements environs.EnvironProvider.
+func (p *environProvider) Version() int {
+	return 0
+}
+
+// Open implements environs.EnvironProvider.
+func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
+	logger.Infof("opening model %q", args.Config.Name())
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	env, err := newEnviron(p, args.Cloud, args.Config)
+	return env, errors.Trace(err)
+}
+
+// CloudSchema returns the schema for adding new clouds of this type.
+func (p *environProvider) CloudSchema() *jsonschema.Schema {
+	return cloudSchema
+}
+
+// Ping tests the connection to the cloud, to verify the endpoint is valid.
+func (p *environProvider) Ping(callCtx callcontext.ProviderCallContext, endpoint string) error {
+	if err := validateEndpoint(endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	// Use a token that cannot be valid; the API responds with an
+	// authentication failure if it is there at all.
+	ctx := context.Background()
+	client, err := p.dial(ctx, proxmoxclient.Config{
+		Endpoint: endpoint,
+		TokenID:  "juju@pve!ping",
+		Logger:   logger,
+	})
+	if err == nil {
+		_, err = client.Version(ctx)
+	}
+	if err == nil || proxmoxclient.IsUnauthorized(err) {
+		return nil
+	}
+	logger.Errorf("unexpected error pinging Proxmox VE API: %v", err)
+	return errors.Errorf("no Proxmox VE API available at %s", endpoint)
+}
+
+// PrepareConfig implements environs.EnvironProvider.
+func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*config.Config, error) {
+	if err := validateCloudSpec(args.Cloud); err != nil {
+		return nil, errors.Annotate(err, "validating cloud spec")
+	}
+	return args.Config, nil
+}
+
+// Validate implements environs.EnvironProvider.
+func (*environProvider) Validate(cfg, old *config.Config) (*config.Config, error) {
+	newEcfg, err := validateConfig(cfg, nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid config")
+	}
+	if old != nil {
+		oldEcfg, err := validateConfig(old, nil)
+		if err != nil {
+			return nil, errors.Annotate(err, "invalid base config")
+		}
+		if newEcfg, err = validateConfig(cfg, oldEcfg); err != nil {
+			return nil, errors.Annotate(err, "invalid config change")
+		}
+	}
+	return newEcfg.Config, nil
+}
+
+func validateCloudSpec(spec environs.CloudSpec) error {
+	if err := spec.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateEndpoint(spec.Endpoint); err != nil {
+		return errors.Trace(err)
+	}
+	if spec.Credential == nil {
+		return errors.NotValidf("missing credential")
+	}
+	switch authType := spec.Credential.AuthType(); authType {
+	case cloud.AccessKeyAuthType, cloud.UserPassAuthType:
+	default:
+		return errors.NotSupportedf("%q auth-type", authType)
+	}
+	return nil
+}
+
+// validateEndpoint checks that endpoint is the URL of the API.
+func validateEndpoint(endpoint string) error {
+	if endpoint == "" {
+		return errors.NotValidf("missing endpoint")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return errors.NotValidf("endpoint %q (expected a URL like https://pve1:8006)", endpoint)
+	}
+	switch u.Scheme {
+	case "http", "https":
+	default:
+		return errors.NotValidf("endpoint %q (expected a URL like https://pve1:8006)", endpoint)
+	}
+	return nil
+}
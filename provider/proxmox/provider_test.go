@@ -0,0 +1,164 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	stdtesting "testing"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func TestProxmox(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+func newConfig(c *gc.C, attrs coretesting.Attrs) *config.Config {
+	attrs = coretesting.FakeConfig().Merge(coretesting.Attrs{
+		"type": "proxmox",
+	}).Merge(attrs)
+	cfg, err := config.New(config.NoDefaults, attrs)
+	c.Assert(err, jc.ErrorIsNil)
+	return cfg
+}
+
+func fakeCloudSpec() environs.CloudSpec {
+	cred := cloud.NewCredential(cloud.AccessKeyAuthType, map[string]string{
+		"token-id":     "juju@pve!automation",
+		"token-secret": "sekrit",
+	})
+	return environs.CloudSpec{
+		Type:       "proxmox",
+		Name:       "lab",
+		Region:     "default",
+		Endpoint:   "https://pve1:8006",
+		Credential: &cred,
+	}
+}
+
+type providerSuite struct {
+	testing.IsolationSuite
+
+	dialStub testing.Stub
+	client   *fakeClient
+	provider environs.CloudEnvironProvider
+	spec     environs.CloudSpec
+}
+
+var _ = gc.Suite(&providerSuite{})
+
+func (s *providerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dialStub.ResetCalls()
+	s.client = newFakeClient()
+	s.provider = NewEnvironProvider(EnvironProviderConfig{
+		Dial: newFakeDialFunc(&s.dialStub, s.client),
+	})
+	s.spec = fakeCloudSpec()
+}
+
+func (s *providerSuite) TestRegistered(c *gc.C) {
+	provider, err := environs.Provider("proxmox")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(provider, gc.NotNil)
+}
+
+func (s *providerSuite) TestOpen(c *gc.C) {
+	env, err := environs.Open(s.provider, environs.OpenParams{
+		Cloud:  s.spec,
+		Config: newConfig(c, nil),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(env, gc.NotNil)
+}
+
+func (s *providerSuite) TestOpenMissingCredential(c *gc.C) {
+	s.spec.Credential = nil
+	s.testOpenError(c, s.spec, `validating cloud spec: missing credential not valid`)
+}
+
+func (s *providerSuite) TestOpenUnsupportedCredential(c *gc.C) {
+	credential := cloud.NewCredential(cloud.OAuth1AuthType, map[string]string{})
+	s.spec.Credential = &credential
+	s.testOpenError(c, s.spec, `validating cloud spec: "oauth1" auth-type not supported`)
+}
+
+func (s *providerSuite) TestOpenInvalidEndpoint(c *gc.C) {
+	s.spec.Endpoint = "pve1:8006"
+	s.testOpenError(c, s.spec, `validating cloud spec: endpoint "pve1:8006" \(expected a URL like https://pve1:8006\) not valid`)
+}
+
+func (s *providerSuite) TestDialTokenCredential(c *gc.C) {
+	_, err := dialClient(context.Background(), s.spec, newFakeDialFunc(&s.dialStub, s.client))
+	c.Assert(err, jc.ErrorIsNil)
+	s.dialStub.CheckCallNames(c, "Dial")
+	config := s.dialStub.Calls()[0].Args[0].(proxmoxclient.Config)
+	c.Assert(config.Endpoint, gc.Equals, "https://pve1:8006")
+	c.Assert(config.TokenID, gc.Equals, "juju@pve!automation")
+	c.Assert(config.TokenSecret, gc.Equals, "sekrit")
+	c.Assert(config.HTTPClient, gc.IsNil)
+}
+
+func (s *providerSuite) TestDialUserPassCredential(c *gc.C) {
+	credential := cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "juju@pve",
+		"password": "password",
+	})
+	s.spec.Credential = &credential
+	s.spec.CACertificates = []string{coretesting.CACert}
+	_, err := dialClient(context.Background(), s.spec, newFakeDialFunc(&s.dialStub, s.client))
+	c.Assert(err, jc.ErrorIsNil)
+	config := s.dialStub.Calls()[0].Args[0].(proxmoxclient.Config)
+	c.Assert(config.Username, gc.Equals, "juju@pve")
+	c.Assert(config.Password, gc.Equals, "password")
+	c.Assert(config.HTTPClient, gc.NotNil)
+}
+
+func (s *providerSuite) TestPing(c *gc.C) {
+	s.client.SetErrors(&proxmoxclient.Error{StatusCode: 401, Message: "authentication failure"})
+	err := s.provider.Ping(callcontext.NewCloudCallContext(), "https://pve1:8006")
+	c.Assert(err, jc.ErrorIsNil)
+	s.client.CheckCallNames(c, "Version")
+}
+
+func (s *providerSuite) TestPingUnreachable(c *gc.C) {
+	s.client.SetErrors(errors.New("connection refused"))
+	err := s.provider.Ping(callcontext.NewCloudCallContext(), "https://pve1:8006")
+	c.Assert(err, gc.ErrorMatches, "no Proxmox VE API available at https://pve1:8006")
+}
+
+func (s *providerSuite) TestCloudSchema(c *gc.C) {
+	config := `
+auth-types: [access-key, userpass]
+endpoint: https://pve1:8006
+`[1:]
+	var v interface{}
+	err := yaml.Unmarshal([]byte(config), &v)
+	c.Assert(err, jc.ErrorIsNil)
+	v, err = utils.ConformYAML(v)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.provider.CloudSchema().Validate(v)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *providerSuite) testOpenError(c *gc.C, spec environs.CloudSpec, expect string) {
+	_, err := environs.Open(s.provider, environs.OpenParams{
+		Cloud:  spec,
+		Config: newConfig(c, nil),
+	})
+	c.Assert(err, gc.ErrorMatches, expect)
+}
@@ -0,0 +1,70 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/juju/errors"
+)
+
+const (
+	metadataFile = "meta-data"
+	userdataFile = "user-data"
+)
+
+// runFunc provides the signature for running an external
+// command and returning the combined output.
+// The first parameter, if non-empty will use the input
+// path as the working directory for the command.
+type runFunc func(string, string, ...string) (string, error)
+
+// run the command and return the combined output.
+func run(dir, command string, args ...string) (string, error) {
+	logger.Tracef("(%s) %s %v", dir, command, args)
+
+	cmd := exec.Command(command, args...)
+	if dir != "" {
+		cmd.Dir = dir
+	}
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// writeSeedImage writes a cloud-init NoCloud seed image containing the
+// user data to dir, returning the path of the image. The Proxmox VE API
+// cannot upload cloud-init snippets, so the seed is attached to the
+// instance as a CD-ROM. See
+// http://cloudinit.readthedocs.io/en/latest/topics/datasources/nocloud.html
+func writeSeedImage(run runFunc, dir, name string, userData []byte) (string, error) {
+	metadata := fmt.Sprintf("{\"instance-id\": %q, \"local-hostname\": %q}", name, name)
+	if err := ioutil.WriteFile(filepath.Join(dir, metadataFile), []byte(metadata), 0644); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, userdataFile), userData, 0600); err != nil {
+		return "", errors.Trace(err)
+	}
+	path := filepath.Join(dir, seedImageName(name))
+	out, err := run(dir,
+		"genisoimage",
+		"-output", path,
+		"-volid", "cidata",
+		"-joliet", "-rock",
+		userdataFile,
+		metadataFile,
+	)
+	if err != nil {
+		return "", errors.Annotatef(err, "creating seed image: %s", out)
+	}
+	return path, nil
+}
+
+// seedImageName returns the file name of the seed image for the named
+// instance.
+func seedImageName(name string) string {
+	return name + "-seed.iso"
+}
@@ -0,0 +1,407 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/instance"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	storageProviderType = storage.ProviderType("proxmox")
+
+	// maxSCSIDisks is the number of SCSI disks a virtual machine may
+	// have; scsi0 is the root disk.
+	maxSCSIDisks = 31
+)
+
+// StorageProviderTypes implements storage.ProviderRegistry.
+func (env *environ) StorageProviderTypes() ([]storage.ProviderType, error) {
+	return []storage.ProviderType{storageProviderType}, nil
+}
+
+// StorageProvider implements storage.ProviderRegistry.
+func (env *environ) StorageProvider(t storage.ProviderType) (storage.Provider, error) {
+	if t == storageProviderType {
+		return &storageProvider{env}, nil
+	}
+	return nil, errors.NotFoundf("storage provider %q", t)
+}
+
+// storageProvider creates volumes in the configured storage, on the
+// node of the instance they are attached to.
+type storageProvider struct {
+	env *environ
+}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// ValidateConfig is part of the storage.Provider interface.
+func (*storageProvider) ValidateConfig(cfg *storage.Config) error {
+	return nil
+}
+
+// Supports is part of the storage.Provider interface.
+func (*storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// Scope is part of the storage.Provider interface.
+func (*storageProvider) Scope() storage.Scope {
+	return storage.ScopeEnviron
+}
+
+// Dynamic is part of the storage.Provider interface.
+func (*storageProvider) Dynamic() bool {
+	return true
+}
+
+// Releasable is part of the storage.Provider interface.
+func (*storageProvider) Releasable() bool {
+	return false
+}
+
+// DefaultPools is part of the storage.Provider interface.
+func (*storageProvider) DefaultPools() []*storage.Config {
+	return nil
+}
+
+// FilesystemSource is part of the storage.Provider interface.
+func (*storageProvider) FilesystemSource(*storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// VolumeSource is part of the storage.Provider interface.
+func (p *storageProvider) VolumeSource(*storage.Config) (storage.VolumeSource, error) {
+	return &volumeSource{env: p.env}, nil
+}
+
+type volumeSource struct {
+	env *environ
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// volumeId returns the provider ID of the volume with the given
+// Proxmox VE volume ID on the node.
+func volumeId(node, volid string) string {
+	return node + "/" + volid
+}
+
+// parseVolumeId returns the node, storage and Proxmox VE volume ID of
+// the volume with the given provider ID.
+func parseVolumeId(volumeId string) (node, storageName, volid string, _ error) {
+	parts := strings.SplitN(volumeId, "/", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return "", "", "", errors.NotValidf("volume ID %q", volumeId)
+	}
+	pos := strings.Index(parts[1], ":")
+	if pos <= 0 {
+		return "", "", "", errors.NotValidf("volume ID %q", volumeId)
+	}
+	return parts[0], parts[1][:pos], parts[1], nil
+}
+
+// deviceLink returns the stable device path of the SCSI disk with the
+// given configuration key, e.g. "scsi1".
+func deviceLink(disk string) string {
+	return fmt.Sprintf("/dev/disk/by-id/scsi-0QEMU_QEMU_HARDDISK_drive-%s", disk)
+}
+
+// CreateVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(callCtx callcontext.ProviderCallContext, params []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
+	results := make([]storage.CreateVolumesResult, len(params))
+	for i, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Error = v.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+			var err error
+			results[i].Volume, results[i].VolumeAttachment, err = v.createVolume(ctx, client, p)
+			return errors.Trace(err)
+		})
+	}
+	return results, nil
+}
+
+func (v *volumeSource) createVolume(ctx context.Context, client Client, p storage.VolumeParams) (*storage.Volume, *storage.VolumeAttachment, error) {
+	// Volumes are owned by a virtual machine, and created on its node.
+	vm, err := v.findVirtualMachine(ctx, client, p.Attachment.InstanceId)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	storageName := v.env.config().storage()
+	name := fmt.Sprintf("vm-%d-%s", vm.VMID, v.env.namespace.Value(p.Tag.String()))
+	volid, err := client.CreateVolume(ctx, vm.Node, storageName, vm.VMID, name, p.Size)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	volume := &storage.Volume{
+		Tag: p.Tag,
+		VolumeInfo: storage.VolumeInfo{
+			VolumeId: volumeId(vm.Node, volid),
+			Size:     p.Size,
+			// Volumes are deleted with the virtual machine that
+			// owns them.
+			Persistent: false,
+		},
+	}
+	info, err := v.attachVolume(ctx, client, vm, volid)
+	if err != nil {
+		// The volume exists, so report it; the storage provisioner
+		// will retry the attachment.
+		logger.Warningf("attaching %q to %q: %v", volid, vm.Name, err)
+		return volume, nil, nil
+	}
+	attachment := &storage.VolumeAttachment{
+		Volume:               p.Tag,
+		Machine:              p.Attachment.Machine,
+		VolumeAttachmentInfo: *info,
+	}
+	return volume, attachment, nil
+}
+
+// ValidateVolumeParams is part of the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	if params.Attachment == nil || params.Attachment.InstanceId == "" {
+		// The node, and owner, is decided by the instance.
+		return errors.NotSupportedf("creating unattached Proxmox VE volumes")
+	}
+	return nil
+}
+
+// ListVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) ListVolumes(callCtx callcontext.ProviderCallContext) ([]string, error) {
+	prefix := v.env.namespace.Value("volume-")
+	storageName := v.env.config().storage()
+	var ids []string
+	err := v.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		nodes, err := client.Nodes(ctx)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		// Shared storage reports the same volumes on every node.
+		seen := make(map[string]bool)
+		for _, node := range nodes {
+			if !node.Online() {
+				continue
+			}
+			volumes, err := client.Volumes(ctx, node.Name, storageName)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			for _, vol := range volumes {
+				if seen[vol.VolID] || !strings.Contains(vol.VolID, prefix) {
+					continue
+				}
+				seen[vol.VolID] = true
+				ids = append(ids, volumeId(node.Name, vol.VolID))
+			}
+		}
+		return nil
+	})
+	return ids, errors.Trace(err)
+}
+
+// DescribeVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(callCtx callcontext.ProviderCallContext, volumeIds []string) ([]storage.DescribeVolumesResult, error) {
+	results := make([]storage.DescribeVolumesResult, len(volumeIds))
+	err := v.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		for i, id := range volumeIds {
+			results[i].VolumeInfo, results[i].Error = v.describeVolume(ctx, client, id)
+		}
+		return nil
+	})
+	return results, errors.Trace(err)
+}
+
+func (v *volumeSource) describeVolume(ctx context.Context, client Client, id string) (*storage.VolumeInfo, error) {
+	node, storageName, volid, err := parseVolumeId(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumes, err := client.Volumes(ctx, node, storageName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, vol := range volumes {
+		if vol.VolID == volid {
+			return &storage.VolumeInfo{
+				VolumeId:   id,
+				Size:       vol.Size >> 20,
+				Persistent: false,
+			}, nil
+		}
+	}
+	return nil, errors.NotFoundf("volume %q", id)
+}
+
+// DestroyVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(callCtx callcontext.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	err := v.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		for i, id := range volumeIds {
+			node, storageName, volid, err := parseVolumeId(id)
+			if err != nil {
+				results[i] = errors.Trace(err)
+				continue
+			}
+			err = client.DeleteVolume(ctx, node, storageName, volid)
+			if err != nil && !proxmoxclient.IsNotFound(err) {
+				results[i] = errors.Trace(err)
+			}
+		}
+		return nil
+	})
+	return results, errors.Trace(err)
+}
+
+// ReleaseVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) ReleaseVolumes(callCtx callcontext.ProviderCallContext, volumeIds []string) ([]error, error) {
+	results := make([]error, len(volumeIds))
+	for i := range volumeIds {
+		results[i] = errors.NotSupportedf("releasing Proxmox VE volumes")
+	}
+	return results, nil
+}
+
+// AttachVolumes is part of the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(callCtx callcontext.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error) {
+	results := make([]storage.AttachVolumesResult, len(params))
+	err := v.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		for i, p := range params {
+			info, err := v.attachVolumeId(ctx, client, p.VolumeId, p.InstanceId)
+			if err != nil {
+				results[i].Error = err
+				continue
+			}
+			results[i].VolumeAttachment = &storage.VolumeAttachment{
+				Volume:               p.Volume,
+				Machine:              p.Machine,
+				VolumeAttachmentInfo: *info,
+			}
+		}
+		return nil
+	})
+	return results, errors.Trace(err)
+}
+
+func (v *volumeSource) attachVolumeId(ctx context.Context, client Client, id string, instId instance.Id) (*storage.VolumeAttachmentInfo, error) {
+	node, _, volid, err := parseVolumeId(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	vm, err := v.findVirtualMachine(ctx, client, instId)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if vm.Node != node {
+		return nil, errors.Errorf("volume %q and instance %q must be on the same node", id, instId)
+	}
+	return v.attachVolume(ctx, client, vm, volid)
+}
+
+// attachVolume attaches the volume to the virtual machine as a SCSI
+// disk, unless it is already attached.
+func (v *volumeSource) attachVolume(ctx context.Context, client Client, vm proxmoxclient.VirtualMachine, volid string) (*storage.VolumeAttachmentInfo, error) {
+	config, err := client.VirtualMachineConfig(ctx, vm.Node, vm.VMID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if disk := attachedDisk(config, volid); disk != "" {
+		return &storage.VolumeAttachmentInfo{DeviceLink: deviceLink(disk)}, nil
+	}
+	var disk string
+	for i := 1; i < maxSCSIDisks; i++ {
+		if _, ok := config[fmt.Sprintf("scsi%d", i)]; !ok {
+			disk = fmt.Sprintf("scsi%d", i)
+			break
+		}
+	}
+	if disk == "" {
+		return nil, errors.Errorf("no free SCSI disks on %q", vm.Name)
+	}
+	if err := client.UpdateVirtualMachineConfig(ctx, vm.Node, vm.VMID, map[string]string{
+		disk: volid,
+	}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &storage.VolumeAttachmentInfo{DeviceLink: deviceLink(disk)}, nil
+}
+
+// attachedDisk returns the configuration key of the SCSI disk backed by
+// the volume, or an empty string if it is not attached.
+func attachedDisk(config map[string]string, volid string) string {
+	for key, value := range config {
+		if !strings.HasPrefix(key, "scsi") || key == "scsihw" {
+			continue
+		}
+		// The value is the volume ID followed by any disk options.
+		if value == volid || strings.HasPrefix(value, volid+",") {
+			return key
+		}
+	}
+	return ""
+}
+
+// DetachVolumes is part of the storage.VolumeSource interface. Detached
+// volumes remain as unused disks of the virtual machine.
+func (v *volumeSource) DetachVolumes(callCtx callcontext.ProviderCallContext, params []storage.VolumeAttachmentParams) ([]error, error) {
+	results := make([]error, len(params))
+	err := v.env.withClient(callCtx, func(ctx context.Context, client Client) error {
+		for i, p := range params {
+			results[i] = v.detachVolume(ctx, client, p.VolumeId, p.InstanceId)
+		}
+		return nil
+	})
+	return results, errors.Trace(err)
+}
+
+func (v *volumeSource) detachVolume(ctx context.Context, client Client, id string, instId instance.Id) error {
+	_, _, volid, err := parseVolumeId(id)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	vm, err := v.findVirtualMachine(ctx, client, instId)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	config, err := client.VirtualMachineConfig(ctx, vm.Node, vm.VMID)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	disk := attachedDisk(config, volid)
+	if disk == "" {
+		// Not attached; nothing to do.
+		return nil
+	}
+	return errors.Trace(client.UpdateVirtualMachineConfig(ctx, vm.Node, vm.VMID, map[string]string{
+		"delete": disk,
+	}))
+}
+
+// findVirtualMachine returns the virtual machine of the instance.
+func (v *volumeSource) findVirtualMachine(ctx context.Context, client Client, id instance.Id) (proxmoxclient.VirtualMachine, error) {
+	vms, err := v.env.prefixedVirtualMachines(ctx, client, string(id))
+	if err != nil {
+		return proxmoxclient.VirtualMachine{}, errors.Trace(err)
+	}
+	for _, vm := range vms {
+		if vm.Name == string(id) {
+			return vm, nil
+		}
+	}
+	return proxmoxclient.VirtualMachine{}, errors.NotFoundf("instance %q", id)
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package proxmox
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/core/instance"
+	callcontext "github.com/juju/juju/environs/context"
+	"github.com/juju/juju/provider/proxmox/internal/proxmoxclient"
+	"github.com/juju/juju/storage"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type storageSuite struct {
+	coretesting.BaseSuite
+
+	dialStub testing.Stub
+	client   *fakeClient
+	env      *environ
+	source   storage.VolumeSource
+	callCtx  callcontext.ProviderCallContext
+}
+
+var _ = gc.Suite(&storageSuite{})
+
+func (s *storageSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dialStub.ResetCalls()
+	s.client = newFakeClient()
+	provider := &environProvider{
+		dial: newFakeDialFunc(&s.dialStub, s.client),
+		run:  fakeRun,
+	}
+	env, err := newEnviron(provider, fakeCloudSpec(), newConfig(c, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	s.env = env
+	s.callCtx = callcontext.NewCloudCallContext()
+	s.client.vms = []proxmoxclient.VirtualMachine{
+		{VMID: 100, Name: env.namespace.Prefix() + "0", Node: "pve1", Type: "qemu", Status: "running"},
+	}
+	s.client.configs[100] = map[string]string{
+		"scsihw": "virtio-scsi-pci",
+		"scsi0":  "local-lvm:vm-100-disk-0,size=8G",
+	}
+
+	storageProvider, err := env.StorageProvider(storageProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = storageProvider.VolumeSource(nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *storageSuite) TestParseVolumeId(c *gc.C) {
+	node, storageName, volid, err := parseVolumeId("pve1/local-lvm:vm-100-juju-06f00d-volume-0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(node, gc.Equals, "pve1")
+	c.Check(storageName, gc.Equals, "local-lvm")
+	c.Check(volid, gc.Equals, "local-lvm:vm-100-juju-06f00d-volume-0")
+
+	for _, id := range []string{"", "pve1", "/local-lvm:vol", "pve1/vol", "pve1/:vol"} {
+		_, _, _, err := parseVolumeId(id)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *storageSuite) TestAttachedDisk(c *gc.C) {
+	config := map[string]string{
+		"scsihw": "local-lvm:vol",
+		"scsi0":  "local-lvm:root,size=8G",
+		"scsi1":  "local-lvm:vol,size=1G",
+		"ide2":   "local-lvm:vol2",
+	}
+	c.Check(attachedDisk(config, "local-lvm:vol"), gc.Equals, "scsi1")
+	c.Check(attachedDisk(config, "local-lvm:vol2"), gc.Equals, "")
+	c.Check(attachedDisk(config, "local-lvm:ro"), gc.Equals, "")
+}
+
+func (s *storageSuite) TestCreateVolumes(c *gc.C) {
+	instId := instance.Id(s.env.namespace.Prefix() + "0")
+	results, err := s.source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: instId,
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	volid := "local-lvm:vm-100-" + s.env.namespace.Value("volume-0")
+	c.Assert(results[0].Volume.VolumeId, gc.Equals, "pve1/"+volid)
+	c.Assert(results[0].Volume.Size, gc.Equals, uint64(1024))
+	c.Assert(results[0].VolumeAttachment.DeviceLink, gc.Equals, "/dev/disk/by-id/scsi-0QEMU_QEMU_HARDDISK_drive-scsi1")
+	c.Assert(s.client.configs[100]["scsi1"], gc.Equals, volid)
+
+	s.client.CheckCallNames(c, "VirtualMachines", "CreateVolume", "VirtualMachineConfig", "UpdateVirtualMachineConfig")
+	s.client.CheckCall(c, 1, "CreateVolume", "pve1", "local-lvm", 100, "vm-100-"+s.env.namespace.Value("volume-0"), uint64(1024))
+}
+
+func (s *storageSuite) TestCreateVolumesUnattached(c *gc.C) {
+	results, err := s.source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:  names.NewVolumeTag("0"),
+		Size: 1024,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.Satisfies, errors.IsNotSupported)
+	s.client.CheckNoCalls(c)
+}
+
+func (s *storageSuite) TestListVolumes(c *gc.C) {
+	prefix := s.env.namespace.Prefix()
+	shared := proxmoxclient.Volume{VolID: "local-lvm:vm-100-" + prefix + "volume-0", VMID: 100}
+	s.client.volumes["pve1"] = []proxmoxclient.Volume{
+		{VolID: "local-lvm:vm-100-disk-0", VMID: 100},
+		shared,
+	}
+	s.client.volumes["pve2"] = []proxmoxclient.Volume{
+		shared,
+		{VolID: "local-lvm:vm-101-" + prefix + "volume-1", VMID: 101},
+	}
+	s.client.volumes["pve3"] = []proxmoxclient.Volume{
+		{VolID: "local-lvm:vm-102-" + prefix + "volume-2", VMID: 102},
+	}
+	ids, err := s.source.ListVolumes(s.callCtx)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []string{
+		"pve1/local-lvm:vm-100-" + prefix + "volume-0",
+		"pve2/local-lvm:vm-101-" + prefix + "volume-1",
+	})
+}
+
+func (s *storageSuite) TestDescribeVolumes(c *gc.C) {
+	s.client.volumes["pve1"] = []proxmoxclient.Volume{
+		{VolID: "local-lvm:vol", Size: 2 << 30},
+	}
+	results, err := s.source.DescribeVolumes(s.callCtx, []string{"pve1/local-lvm:vol", "pve1/local-lvm:missing"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeInfo, jc.DeepEquals, &storage.VolumeInfo{
+		VolumeId: "pve1/local-lvm:vol",
+		Size:     2048,
+	})
+	c.Assert(results[1].Error, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *storageSuite) TestDestroyVolumes(c *gc.C) {
+	s.client.SetErrors(nil, &proxmoxclient.Error{StatusCode: 404, Message: "Not Found"})
+	results, err := s.source.DestroyVolumes(s.callCtx, []string{"pve1/local-lvm:vol", "pve2/local-lvm:gone", "bad"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0], jc.ErrorIsNil)
+	c.Assert(results[1], jc.ErrorIsNil)
+	c.Assert(results[2], jc.Satisfies, errors.IsNotValid)
+	s.client.CheckCalls(c, []testing.StubCall{
+		{"DeleteVolume", []interface{}{"pve1", "local-lvm", "local-lvm:vol"}},
+		{"DeleteVolume", []interface{}{"pve2", "local-lvm", "local-lvm:gone"}},
+	})
+}
+
+func (s *storageSuite) TestAttachVolumesAlreadyAttached(c *gc.C) {
+	s.client.configs[100]["scsi1"] = "local-lvm:vol,size=1G"
+	results, err := s.source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id(s.env.namespace.Prefix() + "0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "pve1/local-lvm:vol",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].VolumeAttachment.DeviceLink, gc.Equals, "/dev/disk/by-id/scsi-0QEMU_QEMU_HARDDISK_drive-scsi1")
+	s.client.CheckCallNames(c, "VirtualMachines", "VirtualMachineConfig")
+}
+
+func (s *storageSuite) TestAttachVolumesOtherNode(c *gc.C) {
+	results, err := s.source.AttachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id(s.env.namespace.Prefix() + "0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "pve2/local-lvm:vol",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, `volume "pve2/local-lvm:vol" and instance ".*" must be on the same node`)
+}
+
+func (s *storageSuite) TestDetachVolumes(c *gc.C) {
+	s.client.configs[100]["scsi2"] = "local-lvm:vol"
+	results, err := s.source.DetachVolumes(s.callCtx, []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id(s.env.namespace.Prefix() + "0"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "pve1/local-lvm:vol",
+	}, {
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("1"),
+			InstanceId: "gone",
+		},
+		Volume:   names.NewVolumeTag("1"),
+		VolumeId: "pve1/local-lvm:vol1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []error{nil, nil})
+	_, ok := s.client.configs[100]["scsi2"]
+	c.Assert(ok, jc.IsFalse)
+}
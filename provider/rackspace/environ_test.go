@@ -350,6 +350,16 @@ func (e *fakeConfigurator) FindIngressRules() ([]network.IngressRule, error) {
 	return nil, nil
 }
 
+func (e *fakeConfigurator) SetEgressCIDRs(cidrs []string) error {
+	e.Push("SetEgressCIDRs", cidrs)
+	return nil
+}
+
+func (e *fakeConfigurator) FindEgressCIDRs() ([]string, error) {
+	e.Push("FindEgressCIDRs")
+	return nil, nil
+}
+
 type fakeInstance struct {
 	methodCalls []methodCall
 }
@@ -99,6 +99,32 @@ func (c *rackspaceFirewaller) InstanceIngressRules(ctx context.ProviderCallConte
 	return rules, err
 }
 
+// SetInstanceEgressCIDRs implements Firewaller interface.
+func (c *rackspaceFirewaller) SetInstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string, cidrs []string) error {
+	_, configurator, err := c.getInstanceConfigurator(ctx, inst)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := configurator.SetEgressCIDRs(cidrs); err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// InstanceEgressCIDRs implements Firewaller interface.
+func (c *rackspaceFirewaller) InstanceEgressCIDRs(ctx context.ProviderCallContext, inst instances.Instance, machineId string) ([]string, error) {
+	_, configurator, err := c.getInstanceConfigurator(ctx, inst)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cidrs, err := configurator.FindEgressCIDRs()
+	if err != nil {
+		common.HandleCredentialError(IsAuthorisationFailure, err, ctx)
+	}
+	return cidrs, err
+}
+
 func (c *rackspaceFirewaller) changeIngressRules(ctx context.ProviderCallContext, inst instances.Instance, insert bool, rules []network.IngressRule) error {
 	addresses, sshClient, err := c.getInstanceConfigurator(ctx, inst)
 	if err != nil {
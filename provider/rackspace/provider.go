@@ -31,6 +31,13 @@ func (p environProvider) Ping(ctx context.ProviderCallContext, endpoint string)
 	return errors.NotImplementedf("Ping")
 }
 
+// SupportsInstanceEgress implements environs.EgressFirewallingProvider.
+// Rackspace instances restrict their outbound traffic with iptables,
+// as they do their inbound traffic.
+func (p *environProvider) SupportsInstanceEgress() bool {
+	return true
+}
+
 // PrepareConfig is part of the EnvironProvider interface.
 func (p *environProvider) PrepareConfig(args environs.PrepareConfigParams) (*config.Config, error) {
 	args.Cloud = transformCloudSpec(args.Cloud)
@@ -23,6 +23,7 @@ type environInstance struct {
 }
 
 var _ instances.Instance = (*environInstance)(nil)
+var _ instances.InstanceEgressFirewaller = (*environInstance)(nil)
 
 func newInstance(base *mo.VirtualMachine, env *environ) *environInstance {
 	return &environInstance{
@@ -87,6 +88,27 @@ func (inst *environInstance) IngressRules(ctx context.ProviderCallContext, machi
 	return client.FindIngressRules()
 }
 
+// SetEgressCIDRs restricts the outbound traffic of the instance, which
+// should have been started with the given machine id, to the given CIDRs.
+func (inst *environInstance) SetEgressCIDRs(ctx context.ProviderCallContext, machineID string, cidrs []string) error {
+	_, client, err := inst.getInstanceConfigurator(ctx)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(client.SetEgressCIDRs(cidrs))
+}
+
+// EgressCIDRs returns the CIDRs to which the outbound traffic of the
+// instance, which should have been started with the given machine id,
+// is restricted.
+func (inst *environInstance) EgressCIDRs(ctx context.ProviderCallContext, machineID string) ([]string, error) {
+	_, client, err := inst.getInstanceConfigurator(ctx)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return client.FindEgressCIDRs()
+}
+
 func (inst *environInstance) changeIngressRules(ctx context.ProviderCallContext, insert bool, rules []network.IngressRule) error {
 	if inst.env.ecfg.externalNetwork() == "" {
 		// Open/Close port without an externalNetwork defined is treated as a no-op.
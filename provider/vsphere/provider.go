@@ -50,6 +50,11 @@ func (p *environProvider) Version() int {
 	return currentProviderVersion
 }
 
+// SupportsInstanceEgress implements environs.EgressFirewallingProvider.
+func (p *environProvider) SupportsInstanceEgress() bool {
+	return true
+}
+
 // Open implements environs.EnvironProvider.
 func (p *environProvider) Open(args environs.OpenParams) (environs.Environ, error) {
 	if err := validateCloudSpec(args.Cloud); err != nil {
@@ -17,6 +17,12 @@ const ConfigChanged = "controller.config-changed"
 // with, at least, the origin of the message.
 type ConfigChangedMessage struct {
 	Config controller.Config
+
+	// TraceParent holds the trace context, in the W3C Trace Context
+	// "traceparent" format, of the request that changed the config,
+	// so that subscribers can record their work as part of its trace.
+	TraceParent string `yaml:"trace-parent,omitempty"`
+
 	// TODO(thumper): add a version int to allow out of order messages.
 	// Out of order could occur if two events happen simultaneously on two
 	// different machines, and the forwarding of those messages cross each other.
@@ -4,11 +4,14 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"reflect"
 	"strings"
 
 	"github.com/juju/errors"
+
+	"github.com/juju/juju/core/trace"
 )
 
 var ErrShutdown = errors.New("connection is shut down")
@@ -24,6 +27,10 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// TraceParent, if set, is sent with the request so that
+	// the server can record its handling in the caller's trace.
+	TraceParent string
 }
 
 // RequestError represents an error returned from an RPC request.
@@ -91,9 +98,10 @@ func (conn *Conn) send(call *Call) {
 
 	// Encode and send the request.
 	hdr := &Header{
-		RequestId: reqId,
-		Request:   call.Request,
-		Version:   1,
+		RequestId:   reqId,
+		Request:     call.Request,
+		Version:     1,
+		TraceParent: call.TraceParent,
 	}
 	params := call.Params
 	if params == nil {
@@ -177,3 +185,18 @@ func (conn *Conn) Call(req Request, params, response interface{}) error {
 	result := <-call.Done
 	return errors.Trace(result.Error)
 }
+
+// CallContext is like Call, but if the given context holds a trace
+// span, the request is made as part of that span's trace.
+func (conn *Conn) CallContext(ctx context.Context, req Request, params, response interface{}) error {
+	call := &Call{
+		Request:     req,
+		Params:      params,
+		Response:    response,
+		Done:        make(chan *Call, 1),
+		TraceParent: trace.TraceParentFromContext(ctx),
+	}
+	conn.send(call)
+	result := <-call.Done
+	return errors.Trace(result.Error)
+}
@@ -73,6 +73,8 @@ type inMsgV1 struct {
 	ErrorCode string                 `json:"error-code"`
 	ErrorInfo map[string]interface{} `json:"error-info"`
 	Response  json.RawMessage        `json:"response"`
+
+	TraceParent string `json:"trace-parent"`
 }
 
 // outMsg holds an outgoing message.
@@ -99,6 +101,8 @@ type outMsgV1 struct {
 	ErrorCode string                 `json:"error-code,omitempty"`
 	ErrorInfo map[string]interface{} `json:"error-info,omitempty"`
 	Response  interface{}            `json:"response,omitempty"`
+
+	TraceParent string `json:"trace-parent,omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -143,6 +147,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	hdr.ErrorCode = c.msg.ErrorCode
 	hdr.ErrorInfo = c.msg.ErrorInfo
 	hdr.Version = version
+	hdr.TraceParent = c.msg.TraceParent
 	return nil
 }
 
@@ -279,6 +284,7 @@ func newOutMsgV1(hdr *rpc.Header, body interface{}) outMsgV1 {
 	}
 	if hdr.IsRequest() {
 		result.Params = body
+		result.TraceParent = hdr.TraceParent
 	} else {
 		result.Response = body
 	}
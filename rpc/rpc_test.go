@@ -20,6 +20,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/testing"
@@ -1140,6 +1141,20 @@ func (*rpcSuite) TestChangeAPIWhileServingRequest(c *gc.C) {
 	}
 }
 
+func (*rpcSuite) TestCallContextPropagatesTrace(c *gc.C) {
+	root := &Root{}
+	root.contextInst = &ContextMethods{root: root}
+
+	client, _, srvDone, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ctx := trace.ContextWithTraceParent(context.Background(), traceParent)
+	err := client.CallContext(ctx, rpc.Request{"ContextMethods", 0, "", "Call0"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(trace.TraceParentFromContext(root.contextInst.callContext), gc.Equals, traceParent)
+}
+
 func (*rpcSuite) TestCodeNotImplementedMatchesAPIserverParams(c *gc.C) {
 	c.Assert(rpc.CodeNotImplemented, gc.Equals, params.CodeNotImplemented)
 }
@@ -14,6 +14,8 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/rpcreflect"
+
+	"github.com/juju/juju/core/trace"
 )
 
 const codeNotImplemented = "not implemented"
@@ -70,6 +72,11 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// TraceParent holds the context of the caller's trace span, if any,
+	// in the W3C Trace Context "traceparent" format. It is only sent in
+	// requests, and only in version 1 of the wire format.
+	TraceParent string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -567,6 +574,8 @@ func (conn *Conn) runRequest(
 	// TODO(axw) provide a means for clients to cancel a request.
 	ctx, cancel := context.WithCancel(conn.context)
 	defer cancel()
+	// Continue the caller's trace, if it sent one.
+	ctx = trace.ContextWithTraceParent(ctx, req.hdr.TraceParent)
 
 	rv, err := req.Call(ctx, req.hdr.Request.Id, arg)
 	if err != nil {
@@ -146,6 +146,15 @@ func allCollections() CollectionSchema {
 			global: true,
 		},
 
+		// This collection holds API tokens minted for local users, used
+		// to authenticate automation without passwords.
+		apiTokensC: {
+			global: true,
+			indexes: []mgo.Index{{
+				Key: []string{"owner"},
+			}},
+		},
+
 		// This collection holds users that are relative to controllers.
 		controllerUsersC: {
 			global: true,
@@ -371,6 +380,16 @@ func allCollections() CollectionSchema {
 				Key: []string{"model-uuid", "unitid"},
 			}},
 		},
+		storageSnapshotsC: {
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "storageid"},
+			}},
+		},
+		volumeResizesC:        {},
+		filesystemResizesC:    {},
+		storageMovesC:         {},
+		volumeCopiesC:         {},
+		volumeEncryptionKeysC: {},
 		volumesC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "storageid"},
@@ -557,6 +576,7 @@ const (
 	cloudsC                    = "clouds"
 	cloudContainersC           = "cloudcontainers"
 	cloudServicesC             = "cloudservices"
+	apiTokensC                 = "apiTokens"
 	cloudCredentialsC          = "cloudCredentials"
 	constraintsC               = "constraints"
 	containerRefsC             = "containerRefs"
@@ -612,6 +632,12 @@ const (
 	storageConstraintsC        = "storageconstraints"
 	deviceConstraintsC         = "deviceConstraints"
 	storageInstancesC          = "storageinstances"
+	storageSnapshotsC          = "storagesnapshots"
+	volumeResizesC             = "volumeresizes"
+	filesystemResizesC         = "filesystemresizes"
+	storageMovesC              = "storagemoves"
+	volumeCopiesC              = "volumecopies"
+	volumeEncryptionKeysC      = "volumeencryptionkeys"
 	subnetsC                   = "subnets"
 	linkLayerDevicesC          = "linklayerdevices"
 	linkLayerDevicesRefsC      = "linklayerdevicesrefs"
@@ -0,0 +1,325 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/permission"
+)
+
+// apiTokenSecretSeparator separates the token ID from its secret
+// in the string handed out to the token holder.
+const apiTokenSecretSeparator = ":"
+
+// APIToken represents a long-lived, scoped credential that a local user
+// (typically one dedicated to automation, acting as a service account)
+// may use to log in to the API without a password.
+type APIToken struct {
+	st  *State
+	doc apiTokenDoc
+}
+
+type apiTokenDoc struct {
+	DocID        string    `bson:"_id"`
+	Owner        string    `bson:"owner"`
+	Description  string    `bson:"description,omitempty"`
+	Models       []string  `bson:"models"`
+	Access       string    `bson:"access"`
+	PasswordHash string    `bson:"passwordhash"`
+	PasswordSalt string    `bson:"passwordsalt"`
+	CreatedBy    string    `bson:"createdby"`
+	DateCreated  time.Time `bson:"datecreated"`
+	Expires      time.Time `bson:"expires"`
+	Revoked      bool      `bson:"revoked,omitempty"`
+}
+
+// AddAPITokenArgs contains the parameters for minting an API token.
+type AddAPITokenArgs struct {
+	// Owner is the local user that the token authenticates as.
+	Owner names.UserTag
+
+	// Description is an optional free-form note identifying the
+	// purpose of the token, e.g. the CI job that uses it.
+	Description string
+
+	// Models holds the UUIDs of the models the token may be used to
+	// access. A token is never valid for controller-only logins.
+	Models []string
+
+	// Access is the maximum model access level granted by the token.
+	// The holder never gets more access than the owner has.
+	Access permission.Access
+
+	// Expires is the time after which the token may no longer be used.
+	Expires time.Time
+
+	// CreatedBy is the user minting the token.
+	CreatedBy names.UserTag
+}
+
+// Validate checks that the args are suitable for minting a token.
+func (args AddAPITokenArgs) Validate() error {
+	if !args.Owner.IsLocal() {
+		return errors.NotValidf("token owner %q (not a local user)", args.Owner.Id())
+	}
+	if len(args.Models) == 0 {
+		return errors.NotValidf("token without models")
+	}
+	for _, uuid := range args.Models {
+		if !names.IsValidModel(uuid) {
+			return errors.NotValidf("model UUID %q", uuid)
+		}
+	}
+	if err := permission.ValidateModelAccess(args.Access); err != nil {
+		return errors.Trace(err)
+	}
+	if args.Expires.IsZero() {
+		return errors.NotValidf("token without expiry")
+	}
+	return nil
+}
+
+// AddAPIToken mints a new API token with the given scope. It returns the
+// token along with the secret string that must be presented to log in;
+// the secret is not stored, and cannot be recovered later.
+func (st *State) AddAPIToken(args AddAPITokenArgs) (*APIToken, string, error) {
+	if err := args.Validate(); err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if !args.Expires.After(st.nowToTheSecond()) {
+		return nil, "", errors.NotValidf("expiry time %s in the past", args.Expires)
+	}
+	owner, err := st.User(args.Owner)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	if owner.IsDisabled() {
+		return nil, "", errors.Errorf("user %q is disabled", owner.Name())
+	}
+
+	id, err := newAPITokenID()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	secret, err := utils.RandomPassword()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	salt, err := utils.RandomSalt()
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	token := &APIToken{
+		st: st,
+		doc: apiTokenDoc{
+			DocID:        id,
+			Owner:        strings.ToLower(owner.Name()),
+			Description:  args.Description,
+			Models:       args.Models,
+			Access:       string(args.Access),
+			PasswordHash: utils.UserPasswordHash(secret, salt),
+			PasswordSalt: salt,
+			CreatedBy:    args.CreatedBy.Id(),
+			DateCreated:  st.nowToTheSecond(),
+			Expires:      args.Expires.UTC(),
+		},
+	}
+	ops := []txn.Op{{
+		C:      usersC,
+		Id:     token.doc.Owner,
+		Assert: bson.D{{"deleted", bson.D{{"$ne", true}}}},
+	}, {
+		C:      apiTokensC,
+		Id:     id,
+		Assert: txn.DocMissing,
+		Insert: &token.doc,
+	}}
+	if err := st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			err = errors.Errorf("user %q has been removed", owner.Name())
+		}
+		return nil, "", errors.Trace(err)
+	}
+	return token, id + apiTokenSecretSeparator + secret, nil
+}
+
+func newAPITokenID() (string, error) {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", errors.Trace(err)
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
+
+// SplitAPITokenSecret splits a token string, as returned by AddAPIToken,
+// into the token ID and the secret.
+func SplitAPITokenSecret(secret string) (string, string, error) {
+	parts := strings.SplitN(secret, apiTokenSecretSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("API token")
+	}
+	return parts[0], parts[1], nil
+}
+
+// APIToken returns the API token with the given ID.
+func (st *State) APIToken(id string) (*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var doc apiTokenDoc
+	err := tokens.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("API token %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API token %q", id)
+	}
+	return &APIToken{st: st, doc: doc}, nil
+}
+
+// APITokens returns the API tokens owned by the given user, including
+// those that have expired or been revoked, ordered by creation time.
+func (st *State) APITokens(owner names.UserTag) ([]*APIToken, error) {
+	tokens, closer := st.db().GetCollection(apiTokensC)
+	defer closer()
+
+	var docs []apiTokenDoc
+	err := tokens.Find(bson.D{{"owner", strings.ToLower(owner.Name())}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get API tokens for %q", owner.Id())
+	}
+	result := make([]*APIToken, len(docs))
+	for i, doc := range docs {
+		result[i] = &APIToken{st: st, doc: doc}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].doc.DateCreated.Before(result[j].doc.DateCreated)
+	})
+	return result, nil
+}
+
+// AuthenticateAPIToken checks the given token string, and returns the
+// matching token if it is valid for logging in to the specified model.
+// Unknown, revoked and expired tokens all result in an Unauthorized
+// error, so as not to reveal which tokens exist.
+func (st *State) AuthenticateAPIToken(secret, modelUUID string) (*APIToken, error) {
+	id, password, err := SplitAPITokenSecret(secret)
+	if err != nil {
+		return nil, errors.Unauthorizedf("invalid API token")
+	}
+	token, err := st.APIToken(id)
+	if errors.IsNotFound(err) {
+		return nil, errors.Unauthorizedf("invalid API token")
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if utils.UserPasswordHash(password, token.doc.PasswordSalt) != token.doc.PasswordHash {
+		return nil, errors.Unauthorizedf("invalid API token")
+	}
+	if token.IsRevoked() {
+		return nil, errors.Unauthorizedf("API token %q has been revoked", id)
+	}
+	if !st.nowToTheSecond().Before(token.Expires()) {
+		return nil, errors.Unauthorizedf("API token %q has expired", id)
+	}
+	if !token.ValidForModel(modelUUID) {
+		return nil, errors.Unauthorizedf("API token %q not valid for model %q", id, modelUUID)
+	}
+	return token, nil
+}
+
+// String returns a representation of the token suitable for logging
+// and auditing; it never includes the secret.
+func (t *APIToken) String() string {
+	return fmt.Sprintf("token %s (%s)", t.doc.DocID, t.doc.Owner)
+}
+
+// Id returns the unique identifier of the token.
+func (t *APIToken) Id() string {
+	return t.doc.DocID
+}
+
+// Owner returns the tag of the user the token authenticates as.
+func (t *APIToken) Owner() names.UserTag {
+	return names.NewLocalUserTag(t.doc.Owner)
+}
+
+// Description returns the description given when the token was minted.
+func (t *APIToken) Description() string {
+	return t.doc.Description
+}
+
+// Models returns the UUIDs of the models the token may access.
+func (t *APIToken) Models() []string {
+	return t.doc.Models
+}
+
+// ValidForModel reports whether the token may be used to access the
+// model with the given UUID.
+func (t *APIToken) ValidForModel(modelUUID string) bool {
+	for _, uuid := range t.doc.Models {
+		if uuid == modelUUID {
+			return true
+		}
+	}
+	return false
+}
+
+// Access returns the maximum model access level granted by the token.
+func (t *APIToken) Access() permission.Access {
+	return permission.Access(t.doc.Access)
+}
+
+// CreatedBy returns the name of the user that minted the token.
+func (t *APIToken) CreatedBy() string {
+	return t.doc.CreatedBy
+}
+
+// DateCreated returns when the token was minted, in UTC.
+func (t *APIToken) DateCreated() time.Time {
+	return t.doc.DateCreated.UTC()
+}
+
+// Expires returns the time after which the token is no longer valid, in UTC.
+func (t *APIToken) Expires() time.Time {
+	return t.doc.Expires.UTC()
+}
+
+// IsRevoked reports whether the token has been revoked.
+func (t *APIToken) IsRevoked() bool {
+	return t.doc.Revoked
+}
+
+// Revoke marks the token as revoked, so that it can no longer be used
+// to log in. Connections already authenticated with the token are not
+// affected. Revoking a revoked token is a no-op.
+func (t *APIToken) Revoke() error {
+	if t.doc.Revoked {
+		return nil
+	}
+	ops := []txn.Op{{
+		C:      apiTokensC,
+		Id:     t.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"revoked", true}}}},
+	}}
+	if err := t.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(err, "cannot revoke API token %q", t.doc.DocID)
+	}
+	t.doc.Revoked = true
+	return nil
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type APITokenSuite struct {
+	ConnSuite
+	owner names.UserTag
+}
+
+var _ = gc.Suite(&APITokenSuite{})
+
+func (s *APITokenSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "ci-bot", NoModelUser: true})
+	s.owner = user.UserTag()
+}
+
+func (s *APITokenSuite) addToken(c *gc.C) (*state.APIToken, string) {
+	token, secret, err := s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:       s.owner,
+		Description: "nightly deploy",
+		Models:      []string{s.Model.UUID()},
+		Access:      permission.WriteAccess,
+		Expires:     s.Clock.Now().Add(time.Hour),
+		CreatedBy:   s.Owner,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return token, secret
+}
+
+func (s *APITokenSuite) TestAddAPIToken(c *gc.C) {
+	token, secret := s.addToken(c)
+	c.Assert(token.Owner(), gc.Equals, s.owner)
+	c.Assert(token.Description(), gc.Equals, "nightly deploy")
+	c.Assert(token.Models(), jc.DeepEquals, []string{s.Model.UUID()})
+	c.Assert(token.Access(), gc.Equals, permission.WriteAccess)
+	c.Assert(token.CreatedBy(), gc.Equals, s.Owner.Id())
+	c.Assert(token.IsRevoked(), jc.IsFalse)
+
+	id, _, err := state.SplitAPITokenSecret(secret)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, token.Id())
+
+	fetched, err := s.State.APIToken(token.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fetched.Owner(), gc.Equals, s.owner)
+
+	tokens, err := s.State.APITokens(s.owner)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(tokens, gc.HasLen, 1)
+	c.Assert(tokens[0].Id(), gc.Equals, token.Id())
+}
+
+func (s *APITokenSuite) TestAddAPITokenInvalidArgs(c *gc.C) {
+	args := state.AddAPITokenArgs{
+		Owner:     s.owner,
+		Models:    []string{s.Model.UUID()},
+		Access:    permission.SuperuserAccess,
+		Expires:   s.Clock.Now().Add(time.Hour),
+		CreatedBy: s.Owner,
+	}
+	_, _, err := s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `"superuser" model access not valid`)
+
+	args.Access = permission.ReadAccess
+	args.Models = nil
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `token without models not valid`)
+
+	args.Models = []string{s.Model.UUID()}
+	args.Expires = s.Clock.Now().Add(-time.Hour)
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `expiry time .* in the past not valid`)
+
+	args.Expires = s.Clock.Now().Add(time.Hour)
+	args.Owner = names.NewUserTag("bob@external")
+	_, _, err = s.State.AddAPIToken(args)
+	c.Assert(err, gc.ErrorMatches, `token owner "bob@external" \(not a local user\) not valid`)
+}
+
+func (s *APITokenSuite) TestAuthenticateAPIToken(c *gc.C) {
+	token, secret := s.addToken(c)
+	authed, err := s.State.AuthenticateAPIToken(secret, s.Model.UUID())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(authed.Id(), gc.Equals, token.Id())
+}
+
+func (s *APITokenSuite) TestAuthenticateAPITokenBadSecret(c *gc.C) {
+	token, _ := s.addToken(c)
+	_, err := s.State.AuthenticateAPIToken(token.Id()+":wrong", s.Model.UUID())
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+	_, err = s.State.AuthenticateAPIToken("garbage", s.Model.UUID())
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *APITokenSuite) TestAuthenticateAPITokenWrongModel(c *gc.C) {
+	_, secret := s.addToken(c)
+	_, err := s.State.AuthenticateAPIToken(secret, "deadbeef-0bad-400d-8000-4b1d0d06f00d")
+	c.Assert(err, gc.ErrorMatches, `API token .* not valid for model .*`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *APITokenSuite) TestAuthenticateAPITokenExpired(c *gc.C) {
+	_, secret := s.addToken(c)
+	s.Clock.Advance(2 * time.Hour)
+	_, err := s.State.AuthenticateAPIToken(secret, s.Model.UUID())
+	c.Assert(err, gc.ErrorMatches, `API token .* has expired`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *APITokenSuite) TestRevoke(c *gc.C) {
+	token, secret := s.addToken(c)
+	err := token.Revoke()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(token.IsRevoked(), jc.IsTrue)
+
+	_, err = s.State.AuthenticateAPIToken(secret, s.Model.UUID())
+	c.Assert(err, gc.ErrorMatches, `API token .* has been revoked`)
+
+	// Revoking again is a no-op.
+	err = token.Revoke()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *APITokenSuite) TestAddAPITokenRemovedUser(c *gc.C) {
+	err := s.State.RemoveUser(s.owner)
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.State.AddAPIToken(state.AddAPITokenArgs{
+		Owner:     s.owner,
+		Models:    []string{s.Model.UUID()},
+		Access:    permission.ReadAccess,
+		Expires:   s.Clock.Now().Add(time.Hour),
+		CreatedBy: s.Owner,
+	})
+	c.Assert(err, jc.Satisfies, isDeletedUserError)
+}
@@ -64,6 +64,14 @@ type applicationDoc struct {
 	TxnRevno             int64        `bson:"txn-revno"`
 	MetricCredentials    []byte       `bson:"metric-credentials"`
 
+	// EgressPolicy restricts the outbound traffic of the
+	// application's units when set.
+	EgressPolicy *egressPolicyDoc `bson:"egress-policy,omitempty"`
+
+	// ExposedEndpoints holds the expose settings of the
+	// application's endpoints when it is exposed.
+	ExposedEndpoints map[string]exposedEndpointDoc `bson:"exposed-endpoints,omitempty"`
+
 	// CAAS related attributes.
 	DesiredScale int    `bson:"scale"`
 	PasswordHash string `bson:"passwordhash"`
@@ -589,29 +597,37 @@ func (a *Application) IsExposed() bool {
 	return a.doc.Exposed
 }
 
-// SetExposed marks the application as exposed.
+// SetExposed marks the application as exposed, removing any endpoint
+// expose settings so that all of its endpoints may be reached from any
+// network.
 // See ClearExposed and IsExposed.
 func (a *Application) SetExposed() error {
 	return a.setExposed(true)
 }
 
-// ClearExposed removes the exposed flag from the application.
+// ClearExposed removes the exposed flag, and any endpoint expose
+// settings, from the application.
 // See SetExposed and IsExposed.
 func (a *Application) ClearExposed() error {
 	return a.setExposed(false)
 }
 
 func (a *Application) setExposed(exposed bool) (err error) {
+	update := bson.D{
+		{"$set", bson.D{{"exposed", exposed}}},
+		{"$unset", bson.D{{"exposed-endpoints", nil}}},
+	}
 	ops := []txn.Op{{
 		C:      applicationsC,
 		Id:     a.doc.DocID,
 		Assert: isAliveDoc,
-		Update: bson.D{{"$set", bson.D{{"exposed", exposed}}}},
+		Update: update,
 	}}
 	if err := a.st.db().RunTransaction(ops); err != nil {
 		return errors.Errorf("cannot set exposed flag for application %q to %v: %v", a, exposed, onAbort(err, applicationNotAliveErr))
 	}
 	a.doc.Exposed = exposed
+	a.doc.ExposedEndpoints = nil
 	return nil
 }
 
@@ -0,0 +1,286 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/network"
+)
+
+// EgressPolicy describes the destinations to which the units of an
+// application may send outbound traffic. Traffic to any other
+// destination is dropped once a policy is set. Only IPv4 traffic
+// is restricted.
+type EgressPolicy struct {
+	// ToCIDRs holds the destination networks, in CIDR notation.
+	ToCIDRs []string
+
+	// ToSpaces holds the names of the spaces whose subnets may
+	// be reached.
+	ToSpaces []string
+
+	// ToApplications holds the names of the applications whose
+	// units' machines may be reached.
+	ToApplications []string
+}
+
+// IsEmpty returns true if the policy does not allow any destinations.
+func (p EgressPolicy) IsEmpty() bool {
+	return len(p.ToCIDRs) == 0 && len(p.ToSpaces) == 0 && len(p.ToApplications) == 0
+}
+
+// Validate returns an error if the policy is malformed.
+func (p EgressPolicy) Validate() error {
+	if p.IsEmpty() {
+		return errors.NotValidf("empty egress policy")
+	}
+	for _, cidr := range p.ToCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("egress CIDR %q", cidr)
+		}
+		if network.CIDRAddressType(cidr) != network.IPv4Address {
+			return errors.NotSupportedf("IPv6 egress CIDR %q", cidr)
+		}
+	}
+	for _, space := range p.ToSpaces {
+		if !names.IsValidSpace(space) {
+			return errors.NotValidf("space name %q", space)
+		}
+	}
+	for _, app := range p.ToApplications {
+		if !names.IsValidApplication(app) {
+			return errors.NotValidf("application name %q", app)
+		}
+	}
+	return nil
+}
+
+// egressPolicyDoc is the persistent form of an EgressPolicy,
+// embedded in the application document.
+type egressPolicyDoc struct {
+	ToCIDRs        []string `bson:"to-cidrs,omitempty"`
+	ToSpaces       []string `bson:"to-spaces,omitempty"`
+	ToApplications []string `bson:"to-applications,omitempty"`
+}
+
+// EgressPolicy returns the outbound traffic policy of the application,
+// and whether one has been set. Applications without a policy may send
+// traffic anywhere.
+func (a *Application) EgressPolicy() (EgressPolicy, bool) {
+	doc := a.doc.EgressPolicy
+	if doc == nil {
+		return EgressPolicy{}, false
+	}
+	return EgressPolicy{
+		ToCIDRs:        doc.ToCIDRs,
+		ToSpaces:       doc.ToSpaces,
+		ToApplications: doc.ToApplications,
+	}, true
+}
+
+// SetEgressPolicy restricts the outbound traffic of the application's
+// units to the destinations described by the policy. The referenced
+// spaces and applications must exist.
+func (a *Application) SetEgressPolicy(policy EgressPolicy) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set egress policy for application %q", a)
+
+	if err := policy.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	for _, name := range policy.ToSpaces {
+		if _, err := a.st.SpaceByName(name); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	var ops []txn.Op
+	for _, name := range policy.ToApplications {
+		if _, err := a.st.Application(name); err != nil {
+			return errors.Trace(err)
+		}
+		if name == a.doc.Name {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      applicationsC,
+			Id:     a.st.docID(name),
+			Assert: txn.DocExists,
+		})
+	}
+	doc := &egressPolicyDoc{
+		ToCIDRs:        policy.ToCIDRs,
+		ToSpaces:       policy.ToSpaces,
+		ToApplications: policy.ToApplications,
+	}
+	ops = append(ops, txn.Op{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$set", bson.D{{"egress-policy", doc}}}},
+	})
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return onAbort(err, applicationNotAliveErr)
+	}
+	a.doc.EgressPolicy = doc
+	return nil
+}
+
+// ClearEgressPolicy removes any outbound traffic restriction from the
+// application's units.
+func (a *Application) ClearEgressPolicy() error {
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: isAliveDoc,
+		Update: bson.D{{"$unset", bson.D{{"egress-policy", nil}}}},
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		return errors.Errorf("cannot clear egress policy for application %q: %v", a, onAbort(err, applicationNotAliveErr))
+	}
+	a.doc.EgressPolicy = nil
+	return nil
+}
+
+// EgressCIDRs resolves the application's egress policy into the set of
+// IPv4 destination CIDRs its units may reach. The addresses of the
+// controller are always included so that agents keep working. IPv6
+// subnets and addresses are left out, as IPv6 traffic isn't
+// restricted. The second result is false, and no CIDRs are returned,
+// if the application has no egress policy.
+func (a *Application) EgressCIDRs() ([]string, bool, error) {
+	policy, ok := a.EgressPolicy()
+	if !ok {
+		return nil, false, nil
+	}
+	cidrs := set.NewStrings(policy.ToCIDRs...)
+	for _, name := range policy.ToSpaces {
+		space, err := a.st.SpaceByName(name)
+		if errors.IsNotFound(err) {
+			// The space has since been removed, so there
+			// is nothing in it to reach.
+			continue
+		}
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		subnets, err := space.Subnets()
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		for _, subnet := range subnets {
+			if network.CIDRAddressType(subnet.CIDR()) != network.IPv4Address {
+				continue
+			}
+			cidrs.Add(subnet.CIDR())
+		}
+	}
+	for _, name := range policy.ToApplications {
+		addrs, err := a.st.applicationMachineAddresses(name)
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		for _, addr := range addrs {
+			cidrs.Add(addr + "/32")
+		}
+	}
+	hostPorts, err := a.st.APIHostPortsForAgents()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	for _, server := range hostPorts {
+		for _, hp := range server {
+			if hp.Type != network.IPv4Address {
+				continue
+			}
+			cidrs.Add(hp.Value + "/32")
+		}
+	}
+	return cidrs.SortedValues(), true, nil
+}
+
+// EgressCIDRs returns the IPv4 destination CIDRs to which the outbound
+// traffic of the machine is restricted. A machine is only restricted if
+// every application with a unit on it has an egress policy, in which
+// case it may reach the destinations of any of them. The second result
+// is false, and no CIDRs are returned, if the machine is unrestricted.
+func (m *Machine) EgressCIDRs() ([]string, bool, error) {
+	units, err := m.Units()
+	if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if len(units) == 0 {
+		return nil, false, nil
+	}
+	cidrs := set.NewStrings()
+	seen := set.NewStrings()
+	for _, unit := range units {
+		if seen.Contains(unit.ApplicationName()) {
+			continue
+		}
+		seen.Add(unit.ApplicationName())
+		app, err := unit.Application()
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		appCIDRs, restricted, err := app.EgressCIDRs()
+		if err != nil {
+			return nil, false, errors.Trace(err)
+		}
+		if !restricted {
+			return nil, false, nil
+		}
+		cidrs = cidrs.Union(set.NewStrings(appCIDRs...))
+	}
+	return cidrs.SortedValues(), true, nil
+}
+
+// applicationMachineAddresses returns the IPv4 addresses of the machines
+// hosting units of the named application. A missing application has
+// no addresses.
+func (st *State) applicationMachineAddresses(name string) ([]string, error) {
+	app, err := st.Application(name)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	units, err := app.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var addrs []string
+	for _, unit := range units {
+		machineId, err := unit.AssignedMachineId()
+		if errors.IsNotAssigned(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machine, err := st.Machine(machineId)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, addr := range machine.Addresses() {
+			switch {
+			case addr.Type != network.IPv4Address,
+				addr.Scope == network.ScopeMachineLocal,
+				addr.Scope == network.ScopeLinkLocal:
+				continue
+			}
+			addrs = append(addrs, addr.Value)
+		}
+	}
+	return addrs, nil
+}
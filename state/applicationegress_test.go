@@ -0,0 +1,165 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+)
+
+type ApplicationEgressSuite struct {
+	ConnSuite
+
+	mysql *state.Application
+}
+
+var _ = gc.Suite(&ApplicationEgressSuite{})
+
+func (s *ApplicationEgressSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	err := s.State.SetAPIHostPorts([]network.SpaceHostPorts{{{
+		SpaceAddress: network.NewScopedSpaceAddress("10.0.0.1", network.ScopeCloudLocal),
+		NetPort:      17070,
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ApplicationEgressSuite) TestNoEgressPolicy(c *gc.C) {
+	_, ok := s.mysql.EgressPolicy()
+	c.Assert(ok, jc.IsFalse)
+
+	cidrs, restricted, err := s.mysql.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsFalse)
+	c.Assert(cidrs, gc.HasLen, 0)
+}
+
+func (s *ApplicationEgressSuite) TestSetEgressPolicy(c *gc.C) {
+	policy := state.EgressPolicy{ToCIDRs: []string{"192.168.0.0/16"}}
+	err := s.mysql.SetEgressPolicy(policy)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	got, ok := s.mysql.EgressPolicy()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(got, jc.DeepEquals, policy)
+
+	err = s.mysql.ClearEgressPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok = s.mysql.EgressPolicy()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ApplicationEgressSuite) TestSetEgressPolicyInvalid(c *gc.C) {
+	err := s.mysql.SetEgressPolicy(state.EgressPolicy{})
+	c.Assert(err, gc.ErrorMatches, `cannot set egress policy for application "mysql": empty egress policy not valid`)
+
+	err = s.mysql.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"10.0.0.1"}})
+	c.Assert(err, gc.ErrorMatches, `.*egress CIDR "10.0.0.1" not valid`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotValid)
+
+	err = s.mysql.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"2001:db8::/32"}})
+	c.Assert(err, gc.ErrorMatches, `.*IPv6 egress CIDR "2001:db8::/32" not supported`)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *ApplicationEgressSuite) TestSetEgressPolicyUnknownReferences(c *gc.C) {
+	err := s.mysql.SetEgressPolicy(state.EgressPolicy{ToSpaces: []string{"missing"}})
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+
+	err = s.mysql.SetEgressPolicy(state.EgressPolicy{ToApplications: []string{"missing"}})
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+
+	_, ok := s.mysql.EgressPolicy()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *ApplicationEgressSuite) TestEgressCIDRs(c *gc.C) {
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "172.16.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	subnet6, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "2001:db8:1::/64"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", "", []string{subnet.ID(), subnet6.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineId, err := unit.AssignedMachineId()
+	c.Assert(err, jc.ErrorIsNil)
+	machine, err := s.State.Machine(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetProviderAddresses(
+		network.NewScopedSpaceAddress("127.0.0.1", network.ScopeMachineLocal),
+		network.NewScopedSpaceAddress("192.168.1.5", network.ScopeCloudLocal),
+		network.NewScopedSpaceAddress("2001:db8::5", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetEgressPolicy(state.EgressPolicy{
+		ToCIDRs:        []string{"8.8.8.8/32"},
+		ToSpaces:       []string{"internal"},
+		ToApplications: []string{"wordpress"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, restricted, err := s.mysql.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsTrue)
+	c.Assert(cidrs, jc.DeepEquals, []string{
+		"10.0.0.1/32",
+		"172.16.0.0/24",
+		"192.168.1.5/32",
+		"8.8.8.8/32",
+	})
+}
+
+func (s *ApplicationEgressSuite) TestMachineEgressCIDRs(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	_, restricted, err := machine.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsFalse)
+
+	unit, err := s.mysql.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"8.8.8.8/32"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cidrs, restricted, err := machine.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsTrue)
+	c.Assert(cidrs, jc.DeepEquals, []string{"10.0.0.1/32", "8.8.8.8/32"})
+
+	// A unit of an unrestricted application lifts the restriction.
+	wordpress := s.AddTestingApplication(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err = wordpress.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	_, restricted, err = machine.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsFalse)
+
+	// Otherwise the machine may reach the destinations of either.
+	err = wordpress.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"1.1.1.1/32"}})
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs, restricted, err = machine.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsTrue)
+	c.Assert(cidrs, jc.DeepEquals, []string{"1.1.1.1/32", "10.0.0.1/32", "8.8.8.8/32"})
+}
@@ -0,0 +1,189 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// WildcardEndpoint is the endpoint name used in expose settings to
+// refer to all of an application's endpoints.
+const WildcardEndpoint = ""
+
+// anyNetworkCIDR is the CIDR from which exposed ports may be reached
+// when no spaces or CIDRs have been given.
+const anyNetworkCIDR = "0.0.0.0/0"
+
+// ExposedEndpoint describes the networks from which the ports opened
+// for an exposed endpoint may be reached.
+type ExposedEndpoint struct {
+	// ExposeToSpaces holds the names of the spaces whose subnets
+	// may reach the endpoint's ports.
+	ExposeToSpaces []string
+
+	// ExposeToCIDRs holds the networks, in CIDR notation, that may
+	// reach the endpoint's ports.
+	ExposeToCIDRs []string
+}
+
+// AllowTrafficFromAnyNetwork returns true if the endpoint's ports may
+// be reached from any network.
+func (e ExposedEndpoint) AllowTrafficFromAnyNetwork() bool {
+	if len(e.ExposeToSpaces) == 0 && len(e.ExposeToCIDRs) == 0 {
+		return true
+	}
+	for _, cidr := range e.ExposeToCIDRs {
+		if cidr == anyNetworkCIDR {
+			return true
+		}
+	}
+	return false
+}
+
+// exposedEndpointDoc is the persistent form of an ExposedEndpoint,
+// embedded in the application document.
+type exposedEndpointDoc struct {
+	ExposeToSpaces []string `bson:"to-spaces,omitempty"`
+	ExposeToCIDRs  []string `bson:"to-cidrs,omitempty"`
+}
+
+// ExposedEndpoints returns the expose settings of the application's
+// endpoints, keyed by endpoint name. Settings under WildcardEndpoint
+// apply to all endpoints. An exposed application without settings has
+// all of its endpoints exposed to any network.
+func (a *Application) ExposedEndpoints() map[string]ExposedEndpoint {
+	if len(a.doc.ExposedEndpoints) == 0 {
+		return nil
+	}
+	result := make(map[string]ExposedEndpoint, len(a.doc.ExposedEndpoints))
+	for name, doc := range a.doc.ExposedEndpoints {
+		result[name] = ExposedEndpoint{
+			ExposeToSpaces: doc.ExposeToSpaces,
+			ExposeToCIDRs:  doc.ExposeToCIDRs,
+		}
+	}
+	return result
+}
+
+// MergeExposeSettings marks the application as exposed and merges the
+// given endpoint expose settings into the existing ones, replacing the
+// settings of any endpoint already present. The endpoints must be
+// defined by the application's charm, and the referenced spaces must
+// exist.
+func (a *Application) MergeExposeSettings(exposedEndpoints map[string]ExposedEndpoint) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set expose settings for application %q", a)
+
+	if err := a.validateExposeSettings(exposedEndpoints); err != nil {
+		return errors.Trace(err)
+	}
+	merged := make(map[string]exposedEndpointDoc, len(a.doc.ExposedEndpoints)+len(exposedEndpoints))
+	for name, doc := range a.doc.ExposedEndpoints {
+		merged[name] = doc
+	}
+	for name, exposed := range exposedEndpoints {
+		merged[name] = exposedEndpointDoc{
+			ExposeToSpaces: exposed.ExposeToSpaces,
+			ExposeToCIDRs:  exposed.ExposeToCIDRs,
+		}
+	}
+	ops := []txn.Op{{
+		C:      applicationsC,
+		Id:     a.doc.DocID,
+		Assert: bson.D{{"life", Alive}, {"charmurl", a.doc.CharmURL}},
+		Update: bson.D{{"$set", bson.D{
+			{"exposed", true},
+			{"exposed-endpoints", merged},
+		}}},
+	}}
+	if err := a.st.db().RunTransaction(ops); err != nil {
+		if err == txn.ErrAborted {
+			return errors.New("application is no longer alive or its charm has changed")
+		}
+		return errors.Trace(err)
+	}
+	a.doc.Exposed = true
+	a.doc.ExposedEndpoints = merged
+	return nil
+}
+
+func (a *Application) validateExposeSettings(exposedEndpoints map[string]ExposedEndpoint) error {
+	if len(exposedEndpoints) == 0 {
+		return errors.NotValidf("empty expose settings")
+	}
+	endpoints, err := a.Endpoints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	known := set.NewStrings(WildcardEndpoint)
+	for _, ep := range endpoints {
+		known.Add(ep.Name)
+	}
+	for name, exposed := range exposedEndpoints {
+		if !known.Contains(name) {
+			return errors.NotFoundf("endpoint %q", name)
+		}
+		for _, cidr := range exposed.ExposeToCIDRs {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return errors.NotValidf("expose CIDR %q", cidr)
+			}
+		}
+		for _, space := range exposed.ExposeToSpaces {
+			if !names.IsValidSpace(space) {
+				return errors.NotValidf("space name %q", space)
+			}
+			if _, err := a.st.SpaceByName(space); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	}
+	return nil
+}
+
+// ExposedCIDRs resolves the expose settings of the application into
+// the CIDRs from which each exposed endpoint's ports may be reached,
+// keyed by endpoint name as in ExposedEndpoints. Nil is returned if
+// the application is not exposed.
+func (a *Application) ExposedCIDRs() (map[string][]string, error) {
+	if !a.doc.Exposed {
+		return nil, nil
+	}
+	exposedEndpoints := a.ExposedEndpoints()
+	if len(exposedEndpoints) == 0 {
+		exposedEndpoints = map[string]ExposedEndpoint{WildcardEndpoint: {}}
+	}
+	result := make(map[string][]string, len(exposedEndpoints))
+	for name, exposed := range exposedEndpoints {
+		if exposed.AllowTrafficFromAnyNetwork() {
+			result[name] = []string{anyNetworkCIDR}
+			continue
+		}
+		cidrs := set.NewStrings(exposed.ExposeToCIDRs...)
+		for _, spaceName := range exposed.ExposeToSpaces {
+			space, err := a.st.SpaceByName(spaceName)
+			if errors.IsNotFound(err) {
+				// The space has since been removed, so there
+				// is nothing in it to allow.
+				continue
+			}
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			subnets, err := space.Subnets()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			for _, subnet := range subnets {
+				cidrs.Add(subnet.CIDR())
+			}
+		}
+		result[name] = cidrs.SortedValues()
+	}
+	return result, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/network"
+	"github.com/juju/juju/state"
+)
+
+type ApplicationExposeSuite struct {
+	ConnSuite
+
+	mysql *state.Application
+}
+
+var _ = gc.Suite(&ApplicationExposeSuite{})
+
+func (s *ApplicationExposeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.mysql = s.AddTestingApplication(c, "mysql", s.AddTestingCharm(c, "mysql"))
+
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{CIDR: "172.16.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", "", []string{subnet.ID()}, false)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ApplicationExposeSuite) TestNotExposed(c *gc.C) {
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+
+	cidrs, err := s.mysql.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, gc.IsNil)
+}
+
+func (s *ApplicationExposeSuite) TestSetExposedAllowsAnyNetwork(c *gc.C) {
+	err := s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+	cidrs, err := s.mysql.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, map[string][]string{
+		state.WildcardEndpoint: {"0.0.0.0/0"},
+	})
+}
+
+func (s *ApplicationExposeSuite) TestMergeExposeSettings(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToSpaces: []string{"internal"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedEndpoints(), jc.DeepEquals, map[string]state.ExposedEndpoint{
+		"server":       {ExposeToSpaces: []string{"internal"}},
+		"server-admin": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+
+	cidrs, err := s.mysql.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, map[string][]string{
+		"server":       {"172.16.0.0/24"},
+		"server-admin": {"10.0.0.0/8"},
+	})
+
+	// Unexposing removes the settings.
+	err = s.mysql.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+}
+
+func (s *ApplicationExposeSuite) TestSetExposedRemovesExposeSettings(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"server": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.mysql.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+
+	err = s.mysql.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.IsExposed(), jc.IsTrue)
+	c.Assert(s.mysql.ExposedEndpoints(), gc.HasLen, 0)
+	cidrs, err := s.mysql.ExposedCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cidrs, jc.DeepEquals, map[string][]string{
+		state.WildcardEndpoint: {"0.0.0.0/0"},
+	})
+}
+
+func (s *ApplicationExposeSuite) TestMergeExposeSettingsInvalid(c *gc.C) {
+	err := s.mysql.MergeExposeSettings(nil)
+	c.Assert(err, gc.ErrorMatches, `cannot set expose settings for application "mysql": empty expose settings not valid`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"bogus": {},
+	})
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"10.0.0.1"}},
+	})
+	c.Assert(err, gc.ErrorMatches, `.*expose CIDR "10.0.0.1" not valid`)
+
+	err = s.mysql.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		state.WildcardEndpoint: {ExposeToSpaces: []string{"missing"}},
+	})
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+
+	c.Assert(s.mysql.IsExposed(), jc.IsFalse)
+}
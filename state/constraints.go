@@ -30,6 +30,8 @@ type constraintsDoc struct {
 	Spaces         *[]string
 	VirtType       *string
 	Zones          *[]string
+	Spot           *bool
+	SpotMaxPrice   *string
 }
 
 func (doc constraintsDoc) value() constraints.Value {
@@ -46,6 +48,8 @@ func (doc constraintsDoc) value() constraints.Value {
 		Spaces:         doc.Spaces,
 		VirtType:       doc.VirtType,
 		Zones:          doc.Zones,
+		Spot:           doc.Spot,
+		SpotMaxPrice:   doc.SpotMaxPrice,
 	}
 	return result
 }
@@ -64,6 +68,8 @@ func newConstraintsDoc(cons constraints.Value) constraintsDoc {
 		Spaces:         cons.Spaces,
 		VirtType:       cons.VirtType,
 		Zones:          cons.Zones,
+		Spot:           cons.Spot,
+		SpotMaxPrice:   cons.SpotMaxPrice,
 	}
 	return result
 }
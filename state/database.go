@@ -4,6 +4,7 @@
 package state
 
 import (
+	"context"
 	"runtime/debug"
 	"strings"
 
@@ -259,6 +260,10 @@ type database struct {
 
 	// clock is used to time how long transactions take to run
 	clock clock.Clock
+
+	// traceCtx, if non-nil, holds the trace span that transactions
+	// run through this database are recorded under.
+	traceCtx context.Context
 }
 
 // RunTransactionObserverFunc is the type of a function to be called
@@ -275,6 +280,7 @@ func (db *database) copySession(modelUUID string) (*database, SessionCloser) {
 		ownSession:             true,
 		serverSideTransactions: db.serverSideTransactions,
 		clock:                  db.clock,
+		traceCtx:               db.traceCtx,
 	}, session.Close
 }
 
@@ -372,11 +378,20 @@ func (db *database) TransactionRunner() (runner jujutxn.Runner, closer SessionCl
 		}
 		runner = jujutxn.NewRunner(params)
 	}
-	return &multiModelRunner{
+	runner = &multiModelRunner{
 		rawRunner: runner,
 		modelUUID: db.modelUUID,
 		schema:    db.schema,
-	}, closer
+	}
+	if db.traceCtx != nil {
+		runner = &tracingRunner{
+			Runner:    runner,
+			ctx:       db.traceCtx,
+			dbName:    db.raw.Name,
+			modelUUID: db.modelUUID,
+		}
+	}
+	return runner, closer
 }
 
 // RunTransaction is part of the Database interface.
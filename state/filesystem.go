@@ -52,6 +52,12 @@ type Filesystem interface {
 	// managed by Juju.
 	Volume() (names.VolumeTag, error)
 
+	// Composition returns the way in which the volumes backing this
+	// filesystem are combined, and reports whether the filesystem is
+	// backed by a composition of multiple volumes. The first of the
+	// composed volumes is the one returned by Volume.
+	Composition() (storage.FilesystemComposition, bool)
+
 	// Info returns the filesystem's FilesystemInfo, or a NotProvisioned
 	// error if the filesystem has not yet been provisioned.
 	Info() (FilesystemInfo, error)
@@ -121,6 +127,13 @@ type filesystemDoc struct {
 	// the filesystem as being non-detachable, and to determine
 	// which filesystems must be removed along with said machine.
 	HostId string `bson:"hostid,omitempty"`
+
+	// Composition, if non-empty, is the way in which the volumes
+	// backing the filesystem are combined by the machine agent.
+	// MemberVolumeIds holds the IDs of the composed volumes other
+	// than the one identified by VolumeId.
+	Composition     string   `bson:"composition,omitempty"`
+	MemberVolumeIds []string `bson:"membervolumeids,omitempty"`
 }
 
 // filesystemAttachmentDoc records information about a filesystem attachment.
@@ -155,6 +168,11 @@ type FilesystemParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the storage snapshot
+	// from which the filesystem is to be created. If the filesystem
+	// is volume-backed, the snapshot is of the backing volume.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 // FilesystemInfo describes information about a filesystem.
@@ -231,6 +249,31 @@ func (f *filesystem) Volume() (names.VolumeTag, error) {
 	return names.NewVolumeTag(f.doc.VolumeId), nil
 }
 
+// Composition is required to implement Filesystem.
+func (f *filesystem) Composition() (storage.FilesystemComposition, bool) {
+	if f.doc.Composition == "" {
+		return storage.FilesystemComposition{}, false
+	}
+	return storage.FilesystemComposition{
+		Type:    storage.CompositionType(f.doc.Composition),
+		Volumes: f.volumeTags(),
+	}, true
+}
+
+// volumeTags returns the tags of all of the volumes backing the
+// filesystem, starting with the one identified by the VolumeId.
+func (f *filesystem) volumeTags() []names.VolumeTag {
+	if f.doc.VolumeId == "" {
+		return nil
+	}
+	tags := make([]names.VolumeTag, 0, 1+len(f.doc.MemberVolumeIds))
+	tags = append(tags, names.NewVolumeTag(f.doc.VolumeId))
+	for _, id := range f.doc.MemberVolumeIds {
+		tags = append(tags, names.NewVolumeTag(id))
+	}
+	return tags
+}
+
 // Info is required to implement Filesystem.
 func (f *filesystem) Info() (FilesystemInfo, error) {
 	if f.doc.Info == nil {
@@ -359,12 +402,16 @@ func (sb *storageBackend) StorageInstanceFilesystem(tag names.StorageTag) (Files
 }
 
 func (sb *storageBackend) volumeFilesystem(tag names.VolumeTag) (*filesystem, error) {
-	query := bson.D{{"volumeid", tag.Id()}}
+	query := bson.D{{"$or", []bson.D{
+		{{"volumeid", tag.Id()}},
+		{{"membervolumeids", tag.Id()}},
+	}}}
 	description := fmt.Sprintf("filesystem for volume %q", tag.Id())
 	return sb.filesystem(query, description)
 }
 
-// VolumeFilesystem returns the Filesystem backed by the specified volume.
+// VolumeFilesystem returns the Filesystem backed by the specified volume,
+// either alone or as one of a composition of volumes.
 func (sb *storageBackend) VolumeFilesystem(tag names.VolumeTag) (Filesystem, error) {
 	f, err := sb.volumeFilesystem(tag)
 	return f, err
@@ -618,18 +665,6 @@ func (sb *storageBackend) DetachFilesystem(host names.Tag, filesystem names.File
 	return sb.mb.db().Run(buildTxn)
 }
 
-func (sb *storageBackend) filesystemVolumeAttachment(host names.Tag, f names.FilesystemTag) (VolumeAttachment, error) {
-	filesystem, err := getFilesystemByTag(sb.mb, f)
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	v, err := filesystem.Volume()
-	if err != nil {
-		return nil, errors.Trace(err)
-	}
-	return sb.VolumeAttachment(host, v)
-}
-
 func detachFilesystemOps(host names.Tag, f names.FilesystemTag) []txn.Op {
 	return []txn.Op{{
 		C:      filesystemAttachmentsC,
@@ -666,18 +701,17 @@ func (sb *storageBackend) RemoveFilesystemAttachment(host names.Tag, filesystem
 		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		volumeAttachment, err := sb.filesystemVolumeAttachment(host, filesystem)
-		if err != nil {
-			if errors.Cause(err) != ErrNoBackingVolume && !errors.IsNotFound(err) {
+		// If the filesystem is backed by one or more volumes, then
+		// since the filesystem has been detached, we should now
+		// detach the volumes as well if they are detachable. If
+		// a volume is not detachable, we'll just destroy it along
+		// with the filesystem.
+		for _, volume := range f.volumeTags() {
+			if _, err := sb.VolumeAttachment(host, volume); errors.IsNotFound(err) {
+				continue
+			} else if err != nil {
 				return nil, errors.Trace(err)
 			}
-		} else {
-			// The filesystem is backed by a volume. Since the
-			// filesystem has been detached, we should now
-			// detach the volume as well if it is detachable.
-			// If the volume is not detachable, we'll just
-			// destroy it along with the filesystem.
-			volume := volumeAttachment.Volume()
 			detachableVolume, err := isDetachableVolumeTag(sb.mb.db(), volume)
 			if err != nil {
 				return nil, errors.Trace(err)
@@ -867,12 +901,22 @@ func removeFilesystemOps(sb *storageBackend, filesystem Filesystem, release bool
 		},
 		removeModelFilesystemRefOp(sb.mb, filesystem.Tag().Id()),
 		removeStatusOp(sb.mb, filesystem.globalKey()),
+		removeStorageResizeOp(filesystemResizesC, filesystem.Tag().Id()),
 	}
-	// If the filesystem is backed by a volume, the volume should
-	// be destroyed once the filesystem is removed. The volume must
+	// If the filesystem is backed by volumes, the volumes should
+	// be destroyed once the filesystem is removed. The volumes must
 	// not be destroyed before the filesystem is removed.
 	volumeTag, err := filesystem.Volume()
-	if err == nil {
+	if err == ErrNoBackingVolume {
+		return ops, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeTags := []names.VolumeTag{volumeTag}
+	if composition, ok := filesystem.Composition(); ok {
+		volumeTags = composition.Volumes
+	}
+	for _, volumeTag := range volumeTags {
 		volume, err := getVolumeByTag(sb.mb, volumeTag)
 		if err != nil {
 			return nil, errors.Trace(err)
@@ -882,8 +926,6 @@ func removeFilesystemOps(sb *storageBackend, filesystem Filesystem, release bool
 			return nil, errors.Trace(err)
 		}
 		ops = append(ops, volOps...)
-	} else if err != ErrNoBackingVolume {
-		return nil, errors.Trace(err)
 	}
 	return ops, nil
 }
@@ -907,7 +949,7 @@ func (sb *storageBackend) AddExistingFilesystem(
 		return names.StorageTag{}, errors.Trace(err)
 	}
 	storageTag := names.NewStorageTag(storageId)
-	fsOps, _, volumeTag, err := sb.addFilesystemOps(
+	fsOps, _, volumeTags, err := sb.addFilesystemOps(
 		FilesystemParams{
 			Pool:         info.Pool,
 			Size:         info.Size,
@@ -920,7 +962,7 @@ func (sb *storageBackend) AddExistingFilesystem(
 	if err != nil {
 		return names.StorageTag{}, errors.Trace(err)
 	}
-	if volumeTag != (names.VolumeTag{}) && backingVolume == nil {
+	if len(volumeTags) > 0 && backingVolume == nil {
 		return names.StorageTag{}, errors.Errorf("backing volume info missing")
 	}
 	ops := []txn.Op{{
@@ -1041,39 +1083,43 @@ func newFilesystemId(mb modelBackend, hostId string) (string, error) {
 // addFilesystemOps returns txn.Ops to create a new filesystem with the
 // specified parameters. If the storage source cannot create filesystems
 // directly, a volume will be created and Juju will manage a filesystem
-// on it.
-func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId string) ([]txn.Op, names.FilesystemTag, names.VolumeTag, error) {
+// on it; if the storage pool specifies a composition, then multiple
+// volumes will be created and composed to back the filesystem. The tags
+// of any volumes created are returned, starting with the backing volume.
+func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId string) ([]txn.Op, names.FilesystemTag, []names.VolumeTag, error) {
 	var err error
 	params, err = sb.filesystemParamsWithDefaults(params)
 	if err != nil {
-		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Trace(err)
+		return nil, names.FilesystemTag{}, nil, errors.Trace(err)
 	}
 	detachable, err := isDetachableFilesystemPool(sb, params.Pool)
 	if err != nil {
-		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Trace(err)
+		return nil, names.FilesystemTag{}, nil, errors.Trace(err)
 	}
 	origHostId := hostId
 	hostId, err = sb.validateFilesystemParams(params, hostId)
 	if err != nil {
-		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Annotate(err, "validating filesystem params")
+		return nil, names.FilesystemTag{}, nil, errors.Annotate(err, "validating filesystem params")
 	}
 
 	filesystemId, err := newFilesystemId(sb.mb, hostId)
 	if err != nil {
-		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Annotate(err, "cannot generate filesystem name")
+		return nil, names.FilesystemTag{}, nil, errors.Annotate(err, "cannot generate filesystem name")
 	}
 	filesystemTag := names.NewFilesystemTag(filesystemId)
 
 	// Check if the filesystem needs a volume.
-	var volumeId string
-	var volumeTag names.VolumeTag
+	var volumeTags []names.VolumeTag
 	var ops []txn.Op
-	_, provider, _, err := poolStorageProvider(sb, params.Pool)
+	_, provider, attrs, err := poolStorageProvider(sb, params.Pool)
 	if err != nil {
-		return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Trace(err)
+		return nil, names.FilesystemTag{}, nil, errors.Trace(err)
+	}
+	compositionType, volumeCount, err := storage.CompositionFromConfig(attrs)
+	if err != nil {
+		return nil, names.FilesystemTag{}, nil, errors.Annotatef(err, "invalid storage pool %q", params.Pool)
 	}
 	if !provider.Supports(storage.StorageKindFilesystem) {
-		var volumeOps []txn.Op
 		if params.volumeInfo != nil {
 			// The filesystem ID for volume-backed filesystems
 			// is the string representation of the filesystem tag.
@@ -1084,13 +1130,36 @@ func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId strin
 			params.volumeInfo,
 			params.Pool,
 			params.Size,
+			params.Snapshot,
+			false,
 		}
-		volumeOps, volumeTag, err = sb.addVolumeOps(volumeParams, hostId)
-		if err != nil {
-			return nil, names.FilesystemTag{}, names.VolumeTag{}, errors.Annotate(err, "creating backing volume")
+		if compositionType != "" {
+			if params.volumeInfo != nil {
+				return nil, names.FilesystemTag{}, nil, errors.NotSupportedf("importing composed filesystems")
+			}
+			if params.Snapshot != "" {
+				return nil, names.FilesystemTag{}, nil, errors.NotSupportedf("restoring composed filesystems from snapshots")
+			}
+			volumeParams.Size = compositionType.VolumeSize(params.Size, volumeCount)
+			volumeParams.composed = true
+		}
+		for i := 0; i < volumeCount; i++ {
+			if i > 0 {
+				// Only the first of the composed volumes is
+				// assigned to the storage instance.
+				volumeParams.storage = names.StorageTag{}
+			}
+			volumeOps, volumeTag, err := sb.addVolumeOps(volumeParams, hostId)
+			if err != nil {
+				return nil, names.FilesystemTag{}, nil, errors.Annotate(err, "creating backing volume")
+			}
+			volumeTags = append(volumeTags, volumeTag)
+			ops = append(ops, volumeOps...)
 		}
-		volumeId = volumeTag.Id()
-		ops = append(ops, volumeOps...)
+	} else if compositionType != "" {
+		return nil, names.FilesystemTag{}, nil, errors.NotSupportedf(
+			"composing volumes in pool %q, whose provider creates filesystems", params.Pool,
+		)
 	}
 
 	statusDoc := statusDoc{
@@ -1099,9 +1168,17 @@ func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId strin
 	}
 	doc := filesystemDoc{
 		FilesystemId: filesystemId,
-		VolumeId:     volumeId,
 		StorageId:    params.storage.Id(),
 	}
+	if len(volumeTags) > 0 {
+		doc.VolumeId = volumeTags[0].Id()
+	}
+	if len(volumeTags) > 1 {
+		doc.Composition = string(compositionType)
+		for _, volumeTag := range volumeTags[1:] {
+			doc.MemberVolumeIds = append(doc.MemberVolumeIds, volumeTag.Id())
+		}
+	}
 	if params.filesystemId != "" {
 		// We're importing an already provisioned filesystem into the
 		// model. Set provisioned info rather than params, and set the
@@ -1121,7 +1198,7 @@ func (sb *storageBackend) addFilesystemOps(params FilesystemParams, hostId strin
 		doc.HostId = origHostId
 	}
 	ops = append(ops, sb.newFilesystemOps(doc, statusDoc)...)
-	return ops, filesystemTag, volumeTag, nil
+	return ops, filesystemTag, volumeTags, nil
 }
 
 func (sb *storageBackend) newFilesystemOps(doc filesystemDoc, status statusDoc) []txn.Op {
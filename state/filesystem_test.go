@@ -13,6 +13,7 @@ import (
 	"github.com/juju/juju/core/status"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/testing"
+	"github.com/juju/juju/storage"
 )
 
 type FilesystemStateSuite struct {
@@ -404,6 +405,53 @@ func (s *FilesystemIAASModelSuite) TestVolumeBackedFilesystemScope(c *gc.C) {
 	c.Assert(volumeTag, gc.Equals, names.NewVolumeTag("0"))
 }
 
+func (s *FilesystemIAASModelSuite) TestComposedVolumeBackedFilesystem(c *gc.C) {
+	_, err := s.pm.Create("composed-block", "modelscoped-block", map[string]interface{}{
+		"composition":         "raid0",
+		"composition-volumes": 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, unit, storageTag := s.setupSingleStorage(c, "filesystem", "composed-block")
+	err = s.st.AssignUnit(unit, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := unitMachine(c, s.st, unit).MachineTag()
+
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+	composition, ok := filesystem.Composition()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(composition, jc.DeepEquals, storage.FilesystemComposition{
+		Type:    storage.CompositionRAID0,
+		Volumes: []names.VolumeTag{names.NewVolumeTag("0"), names.NewVolumeTag("1")},
+	})
+
+	for _, volumeTag := range composition.Volumes {
+		volume := s.volume(c, volumeTag)
+		params, ok := volume.Params()
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(params.Size, gc.Equals, uint64(512))
+		s.volumeAttachment(c, machineTag, volumeTag)
+		c.Assert(s.volumeFilesystem(c, volumeTag).Tag(), gc.Equals, filesystem.Tag())
+	}
+
+	// Only the first of the composed volumes is
+	// associated with the storage instance.
+	c.Assert(s.storageInstanceVolume(c, storageTag).Tag(), gc.Equals, names.NewVolumeTag("0"))
+}
+
+func (s *FilesystemIAASModelSuite) TestComposedPoolBlockStorage(c *gc.C) {
+	_, err := s.pm.Create("composed-block", "modelscoped-block", map[string]interface{}{
+		"composition":         "lvm",
+		"composition-volumes": 2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	ch := s.AddTestingCharm(c, "storage-block")
+	app := s.AddTestingApplicationWithStorage(c, "storage-block", ch, map[string]state.StorageConstraints{
+		"data": makeStorageCons("composed-block", 1024, 1),
+	})
+	_, err = app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, gc.ErrorMatches, `.*block storage in pool "composed-block", which composes volumes, not supported`)
+}
+
 func (s *FilesystemIAASModelSuite) TestWatchModelFilesystems(c *gc.C) {
 	app := s.setupMixedScopeStorageApplication(c, "filesystem")
 	addUnit := func() *state.Unit {
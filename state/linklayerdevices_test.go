@@ -804,6 +804,39 @@ func (s *linkLayerDevicesStateSuite) TestGetNetworkInfoForSpaces(c *gc.C) {
 	c.Assert(resDoesNotExists.NetworkInfos, gc.HasLen, 0)
 }
 
+func (s *linkLayerDevicesStateSuite) TestGetNetworkInfoForSpacesDualStack(c *gc.C) {
+	_, err := s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "10.20.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(corenetwork.SubnetInfo{CIDR: "2001:db8:20::/64"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.createNICWithIP(c, s.machine, "eth0", "10.20.0.20/24")
+	err = s.machine.SetDevicesAddresses(state.LinkLayerDeviceAddress{
+		DeviceName:   "eth0",
+		CIDRAddress:  "2001:db8:20::20/64",
+		ConfigMethod: state.StaticAddress,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.machine.SetMachineAddresses(
+		corenetwork.NewScopedSpaceAddress("10.20.0.20", corenetwork.ScopeCloudLocal),
+		corenetwork.NewScopedSpaceAddress("2001:db8:20::20", corenetwork.ScopePublic),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	res := s.machine.GetNetworkInfoForSpaces(set.NewStrings(corenetwork.AlphaSpaceId))
+	resDefault, ok := res[corenetwork.AlphaSpaceId]
+	c.Assert(ok, jc.IsTrue)
+	c.Check(resDefault.Error, jc.ErrorIsNil)
+	c.Assert(resDefault.NetworkInfos, gc.HasLen, 1)
+	c.Check(resDefault.NetworkInfos[0].InterfaceName, gc.Equals, "eth0")
+
+	var addrs []string
+	for _, addr := range resDefault.NetworkInfos[0].Addresses {
+		addrs = append(addrs, addr.Address)
+	}
+	c.Check(addrs, jc.SameContents, []string{"10.20.0.20", "2001:db8:20::20"})
+}
+
 func (s *linkLayerDevicesStateSuite) TestSetLinkLayerDevicesWithLightStateChurn(c *gc.C) {
 	childArgs, churnHook := s.prepareSetLinkLayerDevicesWithStateChurn(c)
 	defer state.SetTestHooks(c, s.State, churnHook).Check()
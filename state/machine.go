@@ -231,6 +231,11 @@ type instanceData struct {
 	// the cloud instance should be retained.
 	KeepInstance bool `bson:"keep-instance,omitempty"`
 
+	// KeepWorkloads is set to true if, on machine removal from Juju,
+	// the agent of a manually provisioned machine should leave the
+	// workloads of its units installed when it uninstalls itself.
+	KeepWorkloads bool `bson:"keep-workloads,omitempty"`
+
 	// CharmProfiles contains the names of LXD profiles used by this machine.
 	// Profiles would have been defined in the charm deployed to this machine.
 	CharmProfiles []string `bson:"charm-profiles,omitempty"`
@@ -325,6 +330,32 @@ func (m *Machine) KeepInstance() (bool, error) {
 	return instData.KeepInstance, nil
 }
 
+// SetKeepWorkloads sets whether the workloads of the machine's units
+// will be left installed when the machine agent uninstalls itself.
+// This is only relevant for manually provisioned machines.
+func (m *Machine) SetKeepWorkloads(keepWorkloads bool) error {
+	ops := []txn.Op{{
+		C:      instanceDataC,
+		Id:     m.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$set", bson.D{{"keep-workloads", keepWorkloads}}}},
+	}}
+	if err := m.st.db().RunTransaction(ops); err != nil {
+		return errors.Annotatef(onAbort(err, nil), "cannot set KeepWorkloads on machine %v", m)
+	}
+	return nil
+}
+
+// KeepWorkloads reports whether the workloads of the machine's units
+// will be left installed when the machine agent uninstalls itself.
+func (m *Machine) KeepWorkloads() (bool, error) {
+	instData, err := getInstanceData(m.st, m.Id())
+	if err != nil {
+		return false, err
+	}
+	return instData.KeepWorkloads, nil
+}
+
 // CharmProfiles returns the names of any LXD profiles used by the machine,
 // which were defined in the charm deployed to that machine.
 func (m *Machine) CharmProfiles() ([]string, error) {
@@ -1548,7 +1579,9 @@ func (m *Machine) setPreferredAddressOps(addr address, isPublic bool) []txn.Op {
 	return ops
 }
 
-func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddresses []address) ([]txn.Op, *address) {
+func (m *Machine) setPublicAddressOps(
+	providerAddresses []address, machineAddresses []address, preferIPv6 bool,
+) ([]txn.Op, *address) {
 	publicAddress := m.doc.PreferredPublicAddress
 	logger.Tracef(
 		"machine %v: current public address: %#v \nprovider addresses: %#v \nmachine addresses: %#v",
@@ -1556,11 +1589,16 @@ func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddres
 
 	// Always prefer an exact match if available.
 	checkScope := func(addr address) bool {
-		return corenetwork.ExactScopeMatch(addr.networkAddress(), corenetwork.ScopePublic)
+		return corenetwork.ExactScopeMatch(addr.networkAddress(), corenetwork.ScopePublic) &&
+			matchesPreferredType(addr, preferIPv6)
 	}
 	// Without an exact match, prefer a fallback match.
+	matchFunc := corenetwork.ScopeMatchPublic
+	if preferIPv6 {
+		matchFunc = corenetwork.PreferIPv6(matchFunc)
+	}
 	getAddr := func(addresses []address) corenetwork.SpaceAddress {
-		addr, _ := networkAddresses(addresses).OneMatchingScope(corenetwork.ScopeMatchPublic)
+		addr, _ := networkAddresses(addresses).OneMatchingScope(matchFunc)
 		return addr
 	}
 
@@ -1574,16 +1612,23 @@ func (m *Machine) setPublicAddressOps(providerAddresses []address, machineAddres
 	return ops, &newAddr
 }
 
-func (m *Machine) setPrivateAddressOps(providerAddresses []address, machineAddresses []address) ([]txn.Op, *address) {
+func (m *Machine) setPrivateAddressOps(
+	providerAddresses []address, machineAddresses []address, preferIPv6 bool,
+) ([]txn.Op, *address) {
 	privateAddress := m.doc.PreferredPrivateAddress
 	// Always prefer an exact match if available.
 	checkScope := func(addr address) bool {
 		return corenetwork.ExactScopeMatch(
-			addr.networkAddress(), corenetwork.ScopeMachineLocal, corenetwork.ScopeCloudLocal, corenetwork.ScopeFanLocal)
+			addr.networkAddress(), corenetwork.ScopeMachineLocal, corenetwork.ScopeCloudLocal, corenetwork.ScopeFanLocal) &&
+			matchesPreferredType(addr, preferIPv6)
 	}
 	// Without an exact match, prefer a fallback match.
+	matchFunc := corenetwork.ScopeMatchCloudLocal
+	if preferIPv6 {
+		matchFunc = corenetwork.PreferIPv6(matchFunc)
+	}
 	getAddr := func(addresses []address) corenetwork.SpaceAddress {
-		addr, _ := networkAddresses(addresses).OneMatchingScope(corenetwork.ScopeMatchCloudLocal)
+		addr, _ := networkAddresses(addresses).OneMatchingScope(matchFunc)
 		return addr
 	}
 
@@ -1596,6 +1641,13 @@ func (m *Machine) setPrivateAddressOps(providerAddresses []address, machineAddre
 	return ops, &newAddr
 }
 
+// matchesPreferredType returns true if the input address is of the type
+// preferred for the machine's preferred addresses. When IPv6 is not
+// preferred, any address type is acceptable.
+func matchesPreferredType(addr address, preferIPv6 bool) bool {
+	return !preferIPv6 || addr.networkAddress().Type == corenetwork.IPv6Address
+}
+
 // SetProviderAddresses records any addresses related to the machine, sourced
 // by asking the provider.
 func (m *Machine) SetProviderAddresses(addresses ...corenetwork.SpaceAddress) error {
@@ -1737,13 +1789,31 @@ func (m *Machine) setAddressesOps(
 		Update: bson.D{{"$set", set}},
 	}}
 
-	setPrivateAddressOps, newPrivate := m.setPrivateAddressOps(providerStateAddresses, machineStateAddresses)
-	setPublicAddressOps, newPublic := m.setPublicAddressOps(providerStateAddresses, machineStateAddresses)
+	preferIPv6, err := m.preferIPv6()
+	if err != nil {
+		return nil, nil, nil, nil, nil, errors.Trace(err)
+	}
+	setPrivateAddressOps, newPrivate := m.setPrivateAddressOps(providerStateAddresses, machineStateAddresses, preferIPv6)
+	setPublicAddressOps, newPublic := m.setPublicAddressOps(providerStateAddresses, machineStateAddresses, preferIPv6)
 	ops = append(ops, setPrivateAddressOps...)
 	ops = append(ops, setPublicAddressOps...)
 	return ops, machineStateAddresses, providerStateAddresses, newPrivate, newPublic, nil
 }
 
+// preferIPv6 reports whether the model is configured to prefer
+// IPv6 addresses when selecting the machine's preferred addresses.
+func (m *Machine) preferIPv6() (bool, error) {
+	model, err := m.st.Model()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	cfg, err := model.ModelConfig()
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return cfg.PreferIPv6(), nil
+}
+
 // CheckProvisioned returns true if the machine was provisioned with the given nonce.
 func (m *Machine) CheckProvisioned(nonce string) bool {
 	return nonce == m.doc.Nonce && nonce != ""
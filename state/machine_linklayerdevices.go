@@ -1051,6 +1051,17 @@ func addAddressToResult(networkInfos []network.NetworkInfo, address *Address) ([
 	return append(networkInfos, networkInfo), nil
 }
 
+// isDualStackAddress returns true if the input address is on the input
+// device, and is a routable address of a different type to the input
+// private address.
+func isDualStackAddress(addr *Address, privateAddress corenetwork.SpaceAddress, privateDevice string) bool {
+	if privateDevice == "" || addr.DeviceName() != privateDevice {
+		return false
+	}
+	other := corenetwork.NewMachineAddress(addr.Value())
+	return other.Type != privateAddress.Type && other.Scope != corenetwork.ScopeLinkLocal
+}
+
 // GetNetworkInfoForSpaces returns MachineNetworkInfoResult with a list of devices for each space in spaces
 // TODO(wpk): 2017-05-04 This does not work for L2-only devices as it iterates over addresses, needs to be fixed.
 // When changing the method we have to keep the ordering.
@@ -1084,6 +1095,15 @@ func (m *Machine) GetNetworkInfoForSpaces(spaces set.Strings) map[string]Machine
 		return results
 	}
 
+	// The device with the preferred private address may also have an
+	// address of the other family, which is included for the default space.
+	var privateDevice string
+	for _, addr := range addresses {
+		if privateAddress.Value != "" && addr.Value() == privateAddress.Value {
+			privateDevice = addr.DeviceName()
+		}
+	}
+
 	for _, addr := range addresses {
 		subnet, err := addr.Subnet()
 		switch {
@@ -1101,7 +1121,8 @@ func (m *Machine) GetNetworkInfoForSpaces(spaces set.Strings) map[string]Machine
 					results[subnet.spaceID] = r
 				}
 			}
-			if spaces.Contains(corenetwork.AlphaSpaceId) && privateAddress.Value == addr.Value() {
+			if spaces.Contains(corenetwork.AlphaSpaceId) &&
+				(privateAddress.Value == addr.Value() || isDualStackAddress(addr, privateAddress, privateDevice)) {
 				r := results[corenetwork.AlphaSpaceId]
 				r.NetworkInfos, err = addAddressToResult(r.NetworkInfos, addr)
 				if err != nil {
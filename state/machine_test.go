@@ -139,6 +139,19 @@ func (s *MachineSuite) TestSetKeepInstance(c *gc.C) {
 	c.Assert(keep, jc.IsTrue)
 }
 
+func (s *MachineSuite) TestSetKeepWorkloads(c *gc.C) {
+	err := s.machine.SetProvisioned("1234", "", "nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.machine.SetKeepWorkloads(true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	m, err := s.State.Machine(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	keep, err := m.KeepWorkloads()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keep, jc.IsTrue)
+}
+
 func (s *MachineSuite) TestAddMachineInsideMachineModelDying(c *gc.C) {
 	model, err := s.State.Model()
 	c.Assert(err, jc.ErrorIsNil)
@@ -2099,6 +2112,67 @@ func (s *MachineSuite) TestStablePublicAddress(c *gc.C) {
 	c.Assert(addr.Value, gc.Equals, "8.8.8.8")
 }
 
+func (s *MachineSuite) TestPublicAddressPreferringIPv6(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = machine.SetProviderAddresses(corenetwork.NewScopedSpaceAddress("8.8.8.8", corenetwork.ScopePublic))
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err := machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "8.8.8.8")
+
+	// Without the preference, the IPv4 address is retained.
+	addrs := []corenetwork.SpaceAddress{
+		corenetwork.NewScopedSpaceAddress("8.8.8.8", corenetwork.ScopePublic),
+		corenetwork.NewScopedSpaceAddress("2001:db8::1", corenetwork.ScopePublic),
+	}
+	err = machine.SetProviderAddresses(addrs...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err = machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "8.8.8.8")
+
+	err = s.Model.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// With the preference, the IPv6 address is selected.
+	err = machine.SetProviderAddresses(addrs...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err = machine.PublicAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "2001:db8::1")
+}
+
+func (s *MachineSuite) TestPrivateAddressPreferringIPv6(c *gc.C) {
+	err := s.Model.UpdateModelConfig(map[string]interface{}{"prefer-ipv6": true}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A cloud-local IPv4 address is used when there is no IPv6 alternative.
+	err = machine.SetMachineAddresses(corenetwork.NewScopedSpaceAddress("10.0.0.1", corenetwork.ScopeCloudLocal))
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err := machine.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "10.0.0.1")
+
+	err = machine.SetMachineAddresses(
+		corenetwork.NewScopedSpaceAddress("10.0.0.1", corenetwork.ScopeCloudLocal),
+		corenetwork.NewScopedSpaceAddress("fc00::1", corenetwork.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	addr, err = machine.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(addr.Value, gc.Equals, "fc00::1")
+}
+
 func (s *MachineSuite) TestAddressesRaceMachineFirst(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -2467,6 +2541,22 @@ func (s *MachineSuite) TestMachineValidActions(c *gc.C) {
 			givenPayload:    map[string]interface{}{"command": "allyourbasearebelongtous", "timeout": 5.0},
 			expectedPayload: map[string]interface{}{"command": "allyourbasearebelongtous", "timeout": 5.0},
 		},
+		{
+			actionName:   "juju-check-network",
+			givenPayload: map[string]interface{}{"timeout": 5.0},
+			errString:    `validation failed: (root) : "targets" property is missing and required, given {"timeout":5}`,
+		},
+		{
+			actionName: "juju-check-network",
+			givenPayload: map[string]interface{}{
+				"targets": []interface{}{map[string]interface{}{"to": "mysql/0", "address": "10.0.0.2"}},
+				"timeout": 5.0,
+			},
+			expectedPayload: map[string]interface{}{
+				"targets": []interface{}{map[string]interface{}{"to": "mysql/0", "address": "10.0.0.2"}},
+				"timeout": 5.0,
+			},
+		},
 		{
 			actionName: "baiku",
 			errString:  `cannot add action "baiku" to a machine; only predefined actions allowed`,
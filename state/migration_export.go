@@ -547,13 +547,24 @@ func (e *exporter) openedPortsArgsForMachine(machineId string, portsData []ports
 		// Don't bother including a subnet if there are no ports open on it.
 		if doc.MachineID == machineId && len(doc.Ports) > 0 {
 			args := description.OpenedPortsArgs{SubnetID: doc.SubnetID}
+			seen := make(map[description.PortRangeArgs]bool)
 			for _, p := range doc.Ports {
-				args.OpenedPorts = append(args.OpenedPorts, description.PortRangeArgs{
+				// The model description cannot carry the endpoints
+				// ports were opened for, so ranges are exported as
+				// opened for all endpoints. Only expose settings
+				// for specific endpoints tell them apart, and those
+				// are not exported either.
+				portRange := description.PortRangeArgs{
 					UnitName: p.UnitName,
 					FromPort: p.FromPort,
 					ToPort:   p.ToPort,
 					Protocol: p.Protocol,
-				})
+				}
+				if seen[portRange] {
+					continue
+				}
+				seen[portRange] = true
+				args.OpenedPorts = append(args.OpenedPorts, portRange)
 			}
 			result = append(result, args)
 		}
@@ -764,6 +775,17 @@ type addApplicationContext struct {
 func (e *exporter) addApplication(ctx addApplicationContext) error {
 	application := ctx.application
 	appName := application.Name()
+	// The model description cannot represent egress policies, and
+	// dropping one would silently lift the restriction on the
+	// target controller.
+	if application.doc.EgressPolicy != nil {
+		return errors.NotSupportedf("migrating egress policy of application %q", appName)
+	}
+	// Likewise for expose settings, where dropping them would expose
+	// the application to any network.
+	if len(application.doc.ExposedEndpoints) > 0 {
+		return errors.NotSupportedf("migrating expose settings of application %q", appName)
+	}
 	globalKey := application.globalKey()
 	charmConfigKey := application.charmConfigKey()
 	appConfigKey := application.applicationConfigKey()
@@ -1793,6 +1815,12 @@ func (e *exporter) constraintsArgs(globalKey string) (description.ConstraintsArg
 		e.logger.Tracef("no constraints found for key %q", globalKey)
 		return description.ConstraintsArgs{}, nil
 	}
+	// The model description cannot represent spot constraints, and
+	// dropping them would silently turn spot machines into on-demand
+	// ones on the target controller.
+	if spot, _ := doc["spot"].(bool); spot || doc["spotmaxprice"] != nil {
+		return description.ConstraintsArgs{}, errors.NotSupportedf("migrating spot constraints for %s", globalKey)
+	}
 	// We capture any type error using a closure to avoid having to return
 	// multiple values from the optional functions. This does mean that we will
 	// only report on the last one, but that is fine as there shouldn't be any.
@@ -1987,6 +2015,12 @@ func (s remoteApplicationShim) GlobalKey() string {
 }
 
 func (e *exporter) storage() error {
+	if err := e.storageSnapshots(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := e.volumeEncryptionKeys(); err != nil {
+		return errors.Trace(err)
+	}
 	if err := e.volumes(); err != nil {
 		return errors.Trace(err)
 	}
@@ -2002,6 +2036,42 @@ func (e *exporter) storage() error {
 	return nil
 }
 
+// storageSnapshots refuses to export models with storage snapshots,
+// which are not supported by the model description. Migrating the
+// model without them would leave the snapshots in the storage provider
+// with nothing to delete them.
+func (e *exporter) storageSnapshots() error {
+	coll, closer := e.st.db().GetCollection(storageSnapshotsC)
+	defer closer()
+
+	n, err := coll.Count()
+	if err != nil {
+		return errors.Annotate(err, "failed to read storage snapshots")
+	}
+	if n > 0 {
+		return errors.NotSupportedf("migrating storage snapshots")
+	}
+	return nil
+}
+
+// volumeEncryptionKeys refuses to export models with volumes encrypted
+// by the machine agent. Their keys are not supported by the model
+// description, and without them the volumes' data could not be read
+// after the migration.
+func (e *exporter) volumeEncryptionKeys() error {
+	coll, closer := e.st.db().GetCollection(volumeEncryptionKeysC)
+	defer closer()
+
+	n, err := coll.Count()
+	if err != nil {
+		return errors.Annotate(err, "failed to read volume encryption keys")
+	}
+	if n > 0 {
+		return errors.NotSupportedf("migrating volumes encrypted by the machine agent")
+	}
+	return nil
+}
+
 func (e *exporter) volumes() error {
 	coll, closer := e.st.db().GetCollection(volumesC)
 	defer closer()
@@ -2198,6 +2268,9 @@ func (e *exporter) filesystems() error {
 }
 
 func (e *exporter) addFilesystem(fs *filesystem, fsAttachments []filesystemAttachmentDoc) error {
+	if fs.doc.Composition != "" {
+		return errors.NotSupportedf("migrating filesystem %s backed by composed volumes", fs.doc.FilesystemId)
+	}
 	// Here we don't care about the cases where the filesystem is not assigned to storage instances
 	// nor no backing volues. In both those situations we have empty tags.
 	storage, _ := fs.Storage()
@@ -2331,6 +2404,9 @@ func (e *exporter) readStorageAttachments() (map[string][]names.UnitTag, error)
 	iter := coll.Find(nil).Iter()
 	defer func() { _ = iter.Close() }()
 	for iter.Next(&doc) {
+		if doc.MoveStatus != StorageMoveNone {
+			return nil, errors.NotSupportedf("migrating storage %q while it is being moved", doc.StorageInstance)
+		}
 		unit := names.NewUnitTag(doc.Unit)
 		result[doc.StorageInstance] = append(result[doc.StorageInstance], unit)
 		count++
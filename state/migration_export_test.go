@@ -370,6 +370,15 @@ func (s *MigrationExportSuite) assertMachinesMigrated(c *gc.C, cons constraints.
 	c.Assert(exAddr.SpaceID(), gc.Equals, "0")
 }
 
+func (s *MigrationExportSuite) TestSpotConstraintsNotSupported(c *gc.C) {
+	cons := constraints.MustParse("mem=8G spot=true spot-max-price=0.05")
+	c.Assert(s.State.SetModelConstraints(cons), jc.ErrorIsNil)
+
+	_, err := s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `migrating spot constraints for e not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *MigrationExportSuite) TestMachineDevices(c *gc.C) {
 	machine := s.Factory.MakeMachine(c, nil)
 	// Create two devices, first with all fields set, second just to show that
@@ -559,6 +568,28 @@ func (s *MigrationExportSuite) TestMultipleApplications(c *gc.C) {
 	c.Assert(applications, gc.HasLen, 3)
 }
 
+func (s *MigrationExportSuite) TestApplicationEgressPolicyNotSupported(c *gc.C) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := application.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"10.0.0.0/8"}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `migrating egress policy of application "wordpress" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MigrationExportSuite) TestApplicationExposeSettingsNotSupported(c *gc.C) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{Name: "wordpress"})
+	err := application.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		state.WildcardEndpoint: {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `migrating expose settings of application "wordpress" not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *MigrationExportSuite) TestApplicationExposingOffers(c *gc.C) {
 	_ = s.Factory.MakeUser(c, &factory.UserParams{Name: "admin"})
 	fooUser := s.Factory.MakeUser(c, &factory.UserParams{Name: "foo"})
@@ -1832,6 +1863,39 @@ func (s *MigrationExportSuite) TestStorage(c *gc.C) {
 	})
 }
 
+func (s *MigrationExportSuite) TestStorageSnapshotsNotSupported(c *gc.C) {
+	_, _, storageTag := s.makeUnitWithStorage(c)
+	sb, err := state.NewStorageBackend(s.State)
+	c.Assert(err, jc.ErrorIsNil)
+	volume, err := sb.StorageInstanceVolume(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = sb.SetVolumeInfo(volume.VolumeTag(), state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = sb.CreateStorageSnapshot(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `migrating storage snapshots not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *MigrationExportSuite) TestVolumeEncryptionKeysNotSupported(c *gc.C) {
+	pm := poolmanager.New(state.NewStateSettings(s.State), provider.CommonStorageProviders())
+	_, err := pm.Create("encrypted-loop", provider.LoopProviderType, map[string]interface{}{
+		"encrypted": true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Factory.MakeMachine(c, &factory.MachineParams{
+		Volumes: []state.HostVolumeParams{{
+			Volume: state.VolumeParams{Pool: "encrypted-loop", Size: 1024},
+		}},
+	})
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `migrating volumes encrypted by the machine agent not supported`)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
 func (s *MigrationExportSuite) TestStoragePools(c *gc.C) {
 	pm := poolmanager.New(state.NewStateSettings(s.State), provider.CommonStorageProviders())
 	_, err := pm.Create("test-pool", provider.LoopProviderType, map[string]interface{}{
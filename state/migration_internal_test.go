@@ -106,6 +106,8 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Users aren't migrated.
 		usersC,
 		userLastLoginC,
+		// API tokens belong to users, which aren't migrated.
+		apiTokensC,
 		// Controller users contain extra data about users therefore
 		// are not migrated either.
 		controllerUsersC,
@@ -213,6 +215,17 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		relationNetworksC,
 		firewallRulesC,
 		dockerResourcesC,
+		// Storage snapshots are not yet supported by the model
+		// description, so models with snapshots cannot be migrated.
+		storageSnapshotsC,
+		volumeResizesC,
+		filesystemResizesC,
+		storageMovesC,
+		volumeCopiesC,
+		// Volume encryption keys are not yet supported by the
+		// model description, so models with volumes encrypted
+		// by the machine agent cannot be migrated.
+		volumeEncryptionKeysC,
 		// TODO(raftlease)
 		// This collection shouldn't be migrated, but we need to make
 		// sure the leader units' leases are claimed in the target
@@ -348,6 +361,10 @@ func (s *MigrationSuite) TestInstanceDataFields(c *gc.C) {
 		// KeepInstance is only set when a machine is
 		// dying/dead (to be removed).
 		"KeepInstance",
+		// KeepWorkloads, like KeepInstance, is only set when a
+		// machine is being removed, and models with dying
+		// machines cannot be migrated.
+		"KeepWorkloads",
 	)
 	migrated := set.NewStrings(
 		// DocID is the model + machine id
@@ -388,6 +405,12 @@ func (s *MigrationSuite) TestApplicationDocFields(c *gc.C) {
 		// RelationCount is handled by the number of times the application name
 		// appears in relation endpoints.
 		"RelationCount",
+		// Egress policies are not supported by the model
+		// description; exporting them is refused.
+		"EgressPolicy",
+		// Expose settings are not supported by the model
+		// description; exporting them is refused.
+		"ExposedEndpoints",
 	)
 	migrated := set.NewStrings(
 		"Name",
@@ -572,6 +595,10 @@ func (s *MigrationSuite) TestConstraintsDocFields(c *gc.C) {
 		"Spaces",
 		"VirtType",
 		"Zones",
+		// Spot constraints are not supported by the model
+		// description; exporting them is refused.
+		"Spot",
+		"SpotMaxPrice",
 	)
 	s.AssertExportedFields(c, constraintsDoc{}, fields)
 }
@@ -748,8 +775,10 @@ func (s *MigrationSuite) TestVolumeDocFields(c *gc.C) {
 	// The info and params fields ar structs.
 	s.AssertExportedFields(c, VolumeInfo{}, set.NewStrings(
 		"HardwareId", "WWN", "Size", "Pool", "VolumeId", "Persistent"))
+	// Snapshot is not migrated, as storage snapshots are not
+	// yet supported by the model description.
 	s.AssertExportedFields(c, VolumeParams{}, set.NewStrings(
-		"Size", "Pool"))
+		"Size", "Pool", "Snapshot"))
 }
 
 func (s *MigrationSuite) TestVolumeAttachmentDocFields(c *gc.C) {
@@ -779,6 +808,10 @@ func (s *MigrationSuite) TestFilesystemDocFields(c *gc.C) {
 		"Life",
 		"HostId",    // recreated from pool properties
 		"Releasing", // only when dying; can't migrate dying storage
+		// Filesystems backed by composed volumes are
+		// not supported by the model description.
+		"Composition",
+		"MemberVolumeIds",
 	)
 	migrated := set.NewStrings(
 		"FilesystemId",
@@ -792,8 +825,10 @@ func (s *MigrationSuite) TestFilesystemDocFields(c *gc.C) {
 	// The info and params fields ar structs.
 	s.AssertExportedFields(c, FilesystemInfo{}, set.NewStrings(
 		"Size", "Pool", "FilesystemId"))
+	// Snapshot is not migrated, as storage snapshots are not
+	// yet supported by the model description.
 	s.AssertExportedFields(c, FilesystemParams{}, set.NewStrings(
-		"Size", "Pool"))
+		"Size", "Pool", "Snapshot"))
 }
 
 func (s *MigrationSuite) TestFilesystemAttachmentDocFields(c *gc.C) {
@@ -839,6 +874,9 @@ func (s *MigrationSuite) TestStorageAttachmentDocFields(c *gc.C) {
 		"ModelUUID",
 		"DocID",
 		"Life",
+		// Storage moves are not supported by the model
+		// description; exporting them is refused.
+		"MoveStatus",
 	)
 	migrated := set.NewStrings(
 		"Unit",
@@ -37,6 +37,10 @@ type PortRange struct {
 	FromPort int
 	ToPort   int
 	Protocol string
+
+	// Endpoint is the name of the endpoint the range was opened
+	// for. The empty name refers to all of the unit's endpoints.
+	Endpoint string `bson:"endpoint,omitempty"`
 }
 
 // NewPortRange create a new port range and validate it.
@@ -121,8 +125,10 @@ func (prA PortRange) CheckConflicts(prB PortRange) error {
 
 	// An exact port range match (including the associated unit name) is not
 	// considered a conflict due to the fact that many charms issue commands
-	// to open the same port multiple times.
-	if prA == prB {
+	// to open the same port multiple times. A unit may also open the same
+	// range for several endpoints.
+	if prA.UnitName == prB.UnitName && prA.FromPort == prB.FromPort &&
+		prA.ToPort == prB.ToPort && prA.Protocol == prB.Protocol {
 		return nil
 	}
 	if prA.Protocol != prB.Protocol {
@@ -134,13 +140,26 @@ func (prA PortRange) CheckConflicts(prB PortRange) error {
 	return nil
 }
 
+// closes reports whether closing the port range closes the other,
+// opened range.
+func (p PortRange) closes(opened PortRange) bool {
+	if p.Endpoint == "" {
+		opened.Endpoint = ""
+	}
+	return p == opened
+}
+
 // Strings returns the port range as a string.
 func (p PortRange) String() string {
 	proto := strings.ToLower(p.Protocol)
+	owner := fmt.Sprintf("%q", p.UnitName)
+	if p.Endpoint != "" {
+		owner = fmt.Sprintf("%q, endpoint %q", p.UnitName, p.Endpoint)
+	}
 	if proto == "icmp" {
-		return fmt.Sprintf("%s (%q)", proto, p.UnitName)
+		return fmt.Sprintf("%s (%s)", proto, owner)
 	}
-	return fmt.Sprintf("%d-%d/%s (%q)", p.FromPort, p.ToPort, proto, p.UnitName)
+	return fmt.Sprintf("%d-%d/%s (%s)", p.FromPort, p.ToPort, proto, owner)
 }
 
 // portsDoc represents the state of ports opened on machines for networks
@@ -269,7 +288,8 @@ func (p *Ports) verifySubnetAliveWhenSet() error {
 }
 
 // ClosePorts removes the specified port range from the list of ports
-// maintained by this document.
+// maintained by this document. A range without an endpoint is closed
+// for all the endpoints of the unit it was opened for.
 func (p *Ports) ClosePorts(portRange PortRange) (err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot close ports %s", portRange)
 
@@ -295,7 +315,7 @@ func (p *Ports) ClosePorts(portRange PortRange) (err error) {
 
 		found := false
 		for _, existingPortsDef := range ports.doc.Ports {
-			if existingPortsDef == portRange {
+			if portRange.closes(existingPortsDef) {
 				found = true
 				continue
 			}
@@ -349,6 +369,15 @@ func (p *Ports) Refresh() error {
 	return nil
 }
 
+// PortRanges returns the port ranges maintained by this document,
+// along with the units and endpoints they were opened for. A range
+// opened by a unit for several endpoints is returned once for each.
+func (p *Ports) PortRanges() []PortRange {
+	result := make([]PortRange, len(p.doc.Ports))
+	copy(result, p.doc.Ports)
+	return result
+}
+
 // AllPortRanges returns a map with network.PortRange as keys and unit
 // names as values.
 func (p *Ports) AllPortRanges() map[network.PortRange]string {
@@ -508,17 +537,10 @@ func removePortsForUnitOps(st *State, unit *Unit) ([]txn.Op, error) {
 	}
 	var ops []txn.Op
 	for _, ports := range allPorts {
-		allRanges := ports.AllPortRanges()
 		var keepPorts []PortRange
-		for portRange, unitName := range allRanges {
-			if unitName != unit.Name() {
-				unitRange := PortRange{
-					UnitName: unitName,
-					FromPort: portRange.FromPort,
-					ToPort:   portRange.ToPort,
-					Protocol: portRange.Protocol,
-				}
-				keepPorts = append(keepPorts, unitRange)
+		for _, portRange := range ports.PortRanges() {
+			if portRange.UnitName != unit.Name() {
+				keepPorts = append(keepPorts, portRange)
 			}
 		}
 		if len(keepPorts) > 0 {
@@ -4,12 +4,17 @@
 package stateenvirons
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/permission"
 	"github.com/juju/juju/state"
 )
 
@@ -25,6 +30,36 @@ type EnvironConfigGetter struct {
 	// NewContainerBroker is a func that returns a caas container broker
 	// for the relevant model.
 	NewContainerBroker caas.NewContainerBrokerFunc
+
+	// Region, if set, is the region to return the cloud spec for
+	// instead of the model's own region.
+	Region string
+
+	// CloudName, if set, is the cloud to return the cloud spec for
+	// instead of the model's own cloud.
+	CloudName string
+
+	// CredentialTag, if set, is the credential to return the cloud spec
+	// for instead of the model's own credential.
+	CredentialTag names.CloudCredentialTag
+}
+
+// ModelConfig implements environs.EnvironConfigGetter. If the config
+// getter is for a cloud of another type to the model's cloud, the
+// model's config is returned with the type of that cloud.
+func (g EnvironConfigGetter) ModelConfig() (*config.Config, error) {
+	cfg, err := g.Model.ModelConfig()
+	if err != nil || g.CloudName == "" {
+		return cfg, err
+	}
+	otherCloud, err := g.State.Cloud(g.CloudName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if otherCloud.Type == cfg.Type() {
+		return cfg, nil
+	}
+	return cfg.Apply(map[string]interface{}{config.TypeKey: otherCloud.Type})
 }
 
 // CloudAPIVersion returns the cloud API version for the cloud with the given spec.
@@ -61,6 +96,17 @@ func (g EnvironConfigGetter) CloudSpec() (environs.CloudSpec, error) {
 	cloudName := g.Model.Cloud()
 	regionName := g.Model.CloudRegion()
 	credentialTag, _ := g.Model.CloudCredential()
+	if g.CloudName != "" && g.CloudName != cloudName {
+		// The model's credential is not valid in another cloud.
+		cloudName = g.CloudName
+		credentialTag = names.CloudCredentialTag{}
+	}
+	if g.Region != "" {
+		regionName = g.Region
+	}
+	if g.CredentialTag != (names.CloudCredentialTag{}) {
+		credentialTag = g.CredentialTag
+	}
 	return CloudSpec(g.State, cloudName, regionName, credentialTag)
 }
 
@@ -110,6 +156,71 @@ func GetNewEnvironFunc(newEnviron environs.NewEnvironFunc) NewEnvironFunc {
 	}
 }
 
+// RegionConfigGetter returns an EnvironConfigGetter for the cloud,
+// region and credential named by a region placement directive. The
+// model's cloud and credential are used unless the placement names
+// others; a named credential must belong to the model's owner and be
+// valid. Placing a machine in another cloud requires the model's owner
+// to be allowed to add models to that cloud, and a named credential.
+func RegionConfigGetter(st *state.State, m *state.Model, placement instance.RegionPlacement) (EnvironConfigGetter, error) {
+	g := EnvironConfigGetter{State: st, Model: m, Region: placement.Region}
+	cloudName := m.Cloud()
+	if placement.Cloud != "" && placement.Cloud != cloudName {
+		cloudName = placement.Cloud
+		g.CloudName = cloudName
+		if err := checkCloudAccess(st, cloudName, m.Owner()); err != nil {
+			return EnvironConfigGetter{}, errors.Trace(err)
+		}
+		if placement.Credential == "" {
+			return EnvironConfigGetter{}, errors.Errorf("cannot place machine in cloud %q: a credential must be specified", cloudName)
+		}
+	}
+	if placement.Credential != "" {
+		id := fmt.Sprintf("%s/%s/%s", cloudName, m.Owner().Id(), placement.Credential)
+		if !names.IsValidCloudCredential(id) {
+			return EnvironConfigGetter{}, errors.NotValidf("credential %q", placement.Credential)
+		}
+		g.CredentialTag = names.NewCloudCredentialTag(id)
+		cred, err := st.CloudCredential(g.CredentialTag)
+		if err != nil {
+			return EnvironConfigGetter{}, errors.Annotatef(err, "cannot place machine in cloud %q", cloudName)
+		}
+		if !cred.IsValid() {
+			return EnvironConfigGetter{}, errors.NotValidf("credential %q for cloud %q", placement.Credential, cloudName)
+		}
+	}
+	return g, nil
+}
+
+// checkCloudAccess returns an error satisfying errors.IsUnauthorized
+// unless the user may add models to the named cloud.
+func checkCloudAccess(st *state.State, cloudName string, user names.UserTag) error {
+	access, err := st.GetCloudAccess(cloudName, user)
+	if errors.IsNotFound(err) {
+		access = permission.NoAccess
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot place machine in cloud %q", cloudName)
+	}
+	if !access.EqualOrGreaterCloudAccessThan(permission.AddModelAccess) {
+		return errors.Unauthorizedf("cannot place machine in cloud %q: %q does not have %s access", cloudName, user.Id(), permission.AddModelAccess)
+	}
+	return nil
+}
+
+// RegionEnviron returns an Environ for the cloud region named by a
+// region placement directive.
+func RegionEnviron(st *state.State, placement instance.RegionPlacement, newEnviron environs.NewEnvironFunc) (environs.Environ, error) {
+	m, err := st.Model()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	g, err := RegionConfigGetter(st, m, placement)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return environs.GetEnviron(g, newEnviron)
+}
+
 // NewCAASBrokerFunc defines the type of a function that, given a state.State,
 // returns a new CAAS broker.
 type NewCAASBrokerFunc func(*state.State) (caas.Broker, error)
@@ -4,12 +4,14 @@
 package stateenvirons_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/juju/caas"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
 
 	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/instance"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/state/stateenvirons"
 	statetesting "github.com/juju/juju/state/testing"
@@ -72,6 +74,145 @@ func (s *environSuite) TestCloudSpec(c *gc.C) {
 	})
 }
 
+func (s *environSuite) TestCloudSpecRegion(c *gc.C) {
+	cloudSpec, err := stateenvirons.EnvironConfigGetter{
+		State:  s.State,
+		Model:  s.Model,
+		Region: "nether-region",
+	}.CloudSpec()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cloudSpec.Region, gc.Equals, "nether-region")
+	c.Assert(cloudSpec.Endpoint, gc.Equals, "nether-endpoint")
+	c.Assert(cloudSpec.IdentityEndpoint, gc.Equals, "nether-identity-endpoint")
+	c.Assert(cloudSpec.StorageEndpoint, gc.Equals, "nether-storage-endpoint")
+}
+
+func (s *environSuite) TestRegionEnviron(c *gc.C) {
+	var callArgs environs.OpenParams
+	newEnviron := func(args environs.OpenParams) (environs.Environ, error) {
+		callArgs = args
+		return nil, nil
+	}
+	_, err := stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{Region: "nether-region"}, newEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callArgs.Cloud.Region, gc.Equals, "nether-region")
+
+	_, err = stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{Region: "no-such-region"}, newEnviron)
+	c.Assert(err, gc.ErrorMatches, `getting cloud region definition: region "no-such-region" not found.*`)
+}
+
+func (s *environSuite) TestRegionEnvironOtherCloud(c *gc.C) {
+	err := s.State.AddCloud(cloud.Cloud{
+		Name:      "other-cloud",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.UserPassAuthType},
+		Regions:   []cloud.Region{{Name: "other-region", Endpoint: "other-endpoint"}},
+	}, s.Owner.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	credTag := names.NewCloudCredentialTag("other-cloud/" + s.Owner.Id() + "/other")
+	err = s.State.UpdateCloudCredential(credTag, cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "other-user",
+		"password": "other-password",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+
+	var callArgs environs.OpenParams
+	newEnviron := func(args environs.OpenParams) (environs.Environ, error) {
+		callArgs = args
+		return nil, nil
+	}
+	_, err = stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{
+		Cloud:      "other-cloud",
+		Region:     "other-region",
+		Credential: "other",
+	}, newEnviron)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(callArgs.Cloud.Name, gc.Equals, "other-cloud")
+	c.Assert(callArgs.Cloud.Region, gc.Equals, "other-region")
+	c.Assert(callArgs.Cloud.Endpoint, gc.Equals, "other-endpoint")
+	c.Assert(callArgs.Cloud.Credential, gc.NotNil)
+	c.Assert(callArgs.Cloud.Credential.Attributes()["username"], gc.Equals, "other-user")
+}
+
+func (s *environSuite) TestRegionEnvironOtherCloudNoAccess(c *gc.C) {
+	otherUser := s.Factory.MakeUser(c, &factory.UserParams{Name: "other-owner"})
+	err := s.State.AddCloud(cloud.Cloud{
+		Name:      "other-cloud",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.UserPassAuthType},
+		Regions:   []cloud.Region{{Name: "other-region", Endpoint: "other-endpoint"}},
+	}, otherUser.Name())
+	c.Assert(err, jc.ErrorIsNil)
+
+	newEnviron := func(args environs.OpenParams) (environs.Environ, error) {
+		c.Fatalf("unexpected call to newEnviron")
+		return nil, nil
+	}
+	_, err = stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{
+		Cloud:      "other-cloud",
+		Region:     "other-region",
+		Credential: "other",
+	}, newEnviron)
+	c.Assert(err, gc.ErrorMatches, `cannot place machine in cloud "other-cloud": ".*" does not have add-model access`)
+	c.Assert(err, jc.Satisfies, errors.IsUnauthorized)
+}
+
+func (s *environSuite) TestRegionEnvironOtherCloudEmptyAuthNeedsCredential(c *gc.C) {
+	err := s.State.AddCloud(cloud.Cloud{
+		Name:      "other-cloud",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.EmptyAuthType},
+		Regions:   []cloud.Region{{Name: "other-region", Endpoint: "other-endpoint"}},
+	}, s.Owner.Name())
+	c.Assert(err, jc.ErrorIsNil)
+
+	newEnviron := func(args environs.OpenParams) (environs.Environ, error) {
+		c.Fatalf("unexpected call to newEnviron")
+		return nil, nil
+	}
+	_, err = stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{
+		Cloud:  "other-cloud",
+		Region: "other-region",
+	}, newEnviron)
+	c.Assert(err, gc.ErrorMatches, `cannot place machine in cloud "other-cloud": a credential must be specified`)
+}
+
+func (s *environSuite) TestRegionEnvironInvalidCredential(c *gc.C) {
+	err := s.State.AddCloud(cloud.Cloud{
+		Name:      "other-cloud",
+		Type:      "dummy",
+		AuthTypes: []cloud.AuthType{cloud.UserPassAuthType},
+		Regions:   []cloud.Region{{Name: "other-region", Endpoint: "other-endpoint"}},
+	}, s.Owner.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	credTag := names.NewCloudCredentialTag("other-cloud/" + s.Owner.Id() + "/other")
+	err = s.State.UpdateCloudCredential(credTag, cloud.NewCredential(cloud.UserPassAuthType, map[string]string{
+		"username": "other-user",
+		"password": "other-password",
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.InvalidateCloudCredential(credTag, "testing")
+	c.Assert(err, jc.ErrorIsNil)
+
+	newEnviron := func(args environs.OpenParams) (environs.Environ, error) {
+		c.Fatalf("unexpected call to newEnviron")
+		return nil, nil
+	}
+	_, err = stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{
+		Cloud:      "other-cloud",
+		Region:     "other-region",
+		Credential: "other",
+	}, newEnviron)
+	c.Assert(err, gc.ErrorMatches, `credential "other" for cloud "other-cloud" not valid`)
+
+	_, err = stateenvirons.RegionEnviron(s.State, instance.RegionPlacement{
+		Cloud:      "other-cloud",
+		Region:     "other-region",
+		Credential: "missing",
+	}, newEnviron)
+	c.Assert(err, gc.ErrorMatches, `cannot place machine in cloud "other-cloud": .* not found`)
+}
+
 func (s *environSuite) TestGetNewCAASBrokerFunc(c *gc.C) {
 	var calls int
 	var callArgs environs.OpenParams
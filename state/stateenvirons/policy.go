@@ -5,12 +5,14 @@ package stateenvirons
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/featureflag"
 
 	"github.com/juju/juju/caas"
 	"github.com/juju/juju/core/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/provider"
@@ -39,10 +41,22 @@ func (p environStatePolicy) Prechecker() (environs.InstancePrechecker, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	if model.Type() == state.ModelTypeIAAS {
-		return p.getEnviron(p.st)
+	if model.Type() != state.ModelTypeIAAS {
+		return p.getBroker(p.st)
+	}
+	env, err := p.getEnviron(p.st)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if featureflag.Enabled(feature.MultiCloud) {
+		return regionPrechecker{
+			InstancePrechecker: env,
+			st:                 p.st,
+			model:              model,
+			newEnviron:         environs.New,
+		}, nil
 	}
-	return p.getBroker(p.st)
+	return env, nil
 }
 
 // ConfigValidator implements state.Policy.
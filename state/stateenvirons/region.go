@@ -0,0 +1,60 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package stateenvirons
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cloud"
+	"github.com/juju/juju/core/instance"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/context"
+	"github.com/juju/juju/state"
+)
+
+// regionPrechecker is an environs.InstancePrechecker that accepts region
+// placement directives for machines in other regions of the model's
+// cloud, or in regions of other clouds. The rest of the placement is
+// checked by the environ for the named region.
+type regionPrechecker struct {
+	environs.InstancePrechecker
+
+	st    *state.State
+	model *state.Model
+
+	newEnviron environs.NewEnvironFunc
+}
+
+// PrecheckInstance implements environs.InstancePrechecker.
+func (p regionPrechecker) PrecheckInstance(ctx context.ProviderCallContext, args environs.PrecheckInstanceParams) error {
+	placement, ok := instance.ParseRegionPlacement(args.Placement)
+	if !ok {
+		return p.InstancePrechecker.PrecheckInstance(ctx, args)
+	}
+	args.Placement = placement.Directive
+	cloudName := placement.Cloud
+	if cloudName == "" {
+		cloudName = p.model.Cloud()
+	}
+	if cloudName == p.model.Cloud() && placement.Region == p.model.CloudRegion() && placement.Credential == "" {
+		return p.InstancePrechecker.PrecheckInstance(ctx, args)
+	}
+
+	regionCloud, err := p.st.Cloud(cloudName)
+	if err != nil {
+		return errors.Annotatef(err, "cannot place machine in cloud %q", cloudName)
+	}
+	if _, err := cloud.RegionByName(regionCloud.Regions, placement.Region); err != nil {
+		return errors.Annotatef(err, "cannot place machine in cloud %q", cloudName)
+	}
+	g, err := RegionConfigGetter(p.st, p.model, placement)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	env, err := environs.GetEnviron(g, p.newEnviron)
+	if err != nil {
+		return errors.Annotatef(err, "opening environ for region %q", placement.Region)
+	}
+	return env.PrecheckInstance(ctx, args)
+}
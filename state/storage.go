@@ -70,6 +70,10 @@ type StorageAttachment interface {
 
 	// Life reports whether the storage attachment is Alive, Dying or Dead.
 	Life() Life
+
+	// MoveStatus reports the progress of moving the attached storage
+	// to another storage pool, if it is being moved.
+	MoveStatus() StorageMoveStatus
 }
 
 // StorageKind defines the type of a store: whether it is a block device
@@ -220,6 +224,10 @@ type storageInstanceDoc struct {
 type storageInstanceConstraints struct {
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot is the ID of the storage snapshot from which the
+	// storage instance's volume or filesystem should be restored.
+	Snapshot string `bson:"snapshot,omitempty"`
 }
 
 type storageAttachment struct {
@@ -238,15 +246,21 @@ func (s *storageAttachment) Life() Life {
 	return s.doc.Life
 }
 
+// MoveStatus is required to implement StorageAttachment.
+func (s *storageAttachment) MoveStatus() StorageMoveStatus {
+	return s.doc.MoveStatus
+}
+
 // storageAttachmentDoc describes a unit's attachment to a charm storage
 // instance.
 type storageAttachmentDoc struct {
 	DocID     string `bson:"_id"`
 	ModelUUID string `bson:"model-uuid"`
 
-	Unit            string `bson:"unitid"`
-	StorageInstance string `bson:"storageid"`
-	Life            Life   `bson:"life"`
+	Unit            string            `bson:"unitid"`
+	StorageInstance string            `bson:"storageid"`
+	Life            Life              `bson:"life"`
+	MoveStatus      StorageMoveStatus `bson:"move-status,omitempty"`
 }
 
 // newStorageInstanceId returns a unique storage instance name. The name
@@ -521,7 +535,7 @@ func removeStorageInstanceOps(si *storageInstance, assert bson.D, force bool) ([
 		Id:     si.doc.Id,
 		Assert: append(assert, ownerAssert),
 		Remove: true,
-	}}
+	}, removeStorageMoveOp(si.doc.Id)}
 	if owner != nil {
 		// Ensure that removing the storage will not violate the
 		// owner's charm storage requirements.
@@ -832,8 +846,9 @@ func createStorageOps(
 				Owner:       owner,
 				StorageName: t.storageName,
 				Constraints: storageInstanceConstraints{
-					Pool: cons.Pool,
-					Size: cons.Size,
+					Pool:     cons.Pool,
+					Size:     cons.Size,
+					Snapshot: cons.snapshot,
 				},
 			}
 			var hostStorageOps []txn.Op
@@ -1369,6 +1384,16 @@ func (sb *storageBackend) RemoveStorageAttachment(storage names.StorageTag, unit
 		} else if err != nil {
 			return nil, errors.Trace(err)
 		}
+		if !force && s.doc.Life == Alive && s.doc.MoveStatus != StorageMoveNone {
+			// The storage is being moved to another pool, and
+			// the unit has stopped using it; keep the attachment
+			// while the storage's data is copied.
+			move, err := sb.storageMove(storage.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return quiesceStorageMoveOps(sb, move)
+		}
 		if s.doc.Life != Dying {
 			// TODO (anastasiamac 2019-04-05) We might want to ignore this when forcing...
 			return nil, errors.New("storage attachment is not dying")
@@ -1387,6 +1412,13 @@ func (sb *storageBackend) RemoveStorageAttachment(storage names.StorageTag, unit
 		}
 		return ops, nil
 	}
+	if s, err := sb.storageAttachment(storage, unit); err == nil && (force || s.doc.Life != Alive) {
+		// Abandon any pending move of the storage first, so that
+		// the storage's original volume is assigned to it again.
+		if err := sb.abandonStorageMove(storage); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return sb.mb.db().Run(buildTxn)
 }
 
@@ -1668,6 +1700,10 @@ type StorageConstraints struct {
 
 	// Count is the required number of storage instances.
 	Count uint64 `bson:"count"`
+
+	// snapshot, if non-empty, is the ID of the storage snapshot from
+	// which the storage instances are to be restored.
+	snapshot string
 }
 
 func createStorageConstraintsOp(key string, cons map[string]StorageConstraints) txn.Op {
@@ -2269,7 +2305,7 @@ func (sb *storageBackend) hostStorageOps(
 
 	// Create filesystems and filesystem attachments.
 	for _, f := range args.filesystems {
-		ops, filesystemTag, volumeTag, err := sb.addFilesystemOps(f.Filesystem, hostId)
+		ops, filesystemTag, volumeTags, err := sb.addFilesystemOps(f.Filesystem, hostId)
 		if err != nil {
 			return nil, nil, nil, errors.Trace(err)
 		}
@@ -2277,8 +2313,8 @@ func (sb *storageBackend) hostStorageOps(
 		fsAttachments = append(fsAttachments, filesystemAttachmentTemplate{
 			filesystemTag, f.Filesystem.storage, f.Attachment, createAndAttach,
 		})
-		if volumeTag != (names.VolumeTag{}) {
-			// The filesystem requires a volume, so create a volume attachment too.
+		for _, volumeTag := range volumeTags {
+			// The filesystem requires volumes, so create volume attachments too.
 			volumeAttachments = append(volumeAttachments, volumeAttachmentTemplate{
 				volumeTag, VolumeAttachmentParams{}, createAndAttach,
 			})
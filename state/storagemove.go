@@ -0,0 +1,565 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// storageMoveDoc records a request to move a storage instance to
+// another storage pool. The document ID is the ID of the storage
+// instance being moved.
+type storageMoveDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+
+	// Unit is the ID of the unit to which the storage is attached.
+	Unit string `bson:"unit"`
+
+	// Machine is the ID of the machine to which the source and
+	// target volumes are attached.
+	Machine string `bson:"machine"`
+
+	// Pool is the name of the storage pool to move the storage to.
+	Pool string `bson:"pool"`
+
+	// SourceVolume is the name of the volume currently assigned
+	// to the storage instance.
+	SourceVolume string `bson:"source-volume"`
+
+	// TargetVolume is the name of the volume created in the new
+	// pool, to which the storage's data is copied.
+	TargetVolume string `bson:"target-volume"`
+
+	// Filesystem is the ID of the filesystem assigned to the storage
+	// instance, if it is filesystem storage. The filesystem is backed
+	// by the source volume until the data is copied, and by the target
+	// volume afterwards.
+	Filesystem string `bson:"filesystem,omitempty"`
+}
+
+// volumeCopyDoc records a request to copy the contents of one volume
+// to another, both attached to the same machine. The document ID is
+// the ID of the target volume's attachment, so that copy requests are
+// scoped to the machine that performs the copy.
+type volumeCopyDoc struct {
+	DocID      string `bson:"_id"`
+	ModelUUID  string `bson:"model-uuid"`
+	StorageId  string `bson:"storageid"`
+	Source     string `bson:"source"`
+	Filesystem string `bson:"filesystem,omitempty"`
+}
+
+// VolumeCopy describes a pending copy of the contents of one volume to
+// another, both attached to the same machine.
+type VolumeCopy struct {
+	// Source is the tag of the volume to copy from.
+	Source names.VolumeTag
+
+	// Filesystem is the tag of the filesystem backed by the source
+	// volume, or the zero tag if block storage is being moved. The
+	// filesystem must not be mounted while its data is copied.
+	Filesystem names.FilesystemTag
+}
+
+// StorageMoveStatus describes the progress of moving a unit's attached
+// storage to another storage pool. The storage attachment remains Alive
+// throughout the move.
+type StorageMoveStatus string
+
+const (
+	// StorageMoveNone indicates that the storage is not being moved.
+	StorageMoveNone StorageMoveStatus = ""
+
+	// StorageMoveDetaching indicates that the unit should run its
+	// "storage-detaching" hook and stop using the storage.
+	StorageMoveDetaching StorageMoveStatus = "detaching"
+
+	// StorageMoveCopying indicates that the unit has stopped using
+	// the storage, and its data is being copied to the new pool.
+	StorageMoveCopying StorageMoveStatus = "copying"
+)
+
+// MoveStorageInstance requests that the storage instance with the
+// specified tag be moved to the named storage pool.
+//
+// A new volume is created in the target pool and attached to the same
+// machine as the storage's current volume. The storage attachment's
+// move status is then set to StorageMoveDetaching, so that the unit runs
+// its "storage-detaching" hook and stops using the storage. Once the
+// unit has done so, the machine's storage provisioner copies the data to
+// the new volume, which is then assigned to the storage instance in
+// place of the old one. Finally the move status is cleared, and the
+// unit runs its "storage-attached" hook against the new volume. The
+// storage attachment remains Alive throughout.
+//
+// Filesystem storage is moved by copying the volume backing the
+// filesystem, and is then backed by the new volume. Filesystems that
+// are not backed by a single volume cannot be moved, and neither can
+// storage encrypted by the machine agent, whose data would need to be
+// copied between the decrypted devices.
+func (sb *storageBackend) MoveStorageInstance(tag names.StorageTag, pool string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot move %s", names.ReadableString(tag))
+	if sb.modelType == ModelTypeCAAS {
+		return errors.NotSupportedf("moving storage in a Kubernetes model")
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		si, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+		if si.Kind() != StorageKindBlock && si.Kind() != StorageKindFilesystem {
+			return nil, errors.NotSupportedf("moving %s storage", si.Kind())
+		}
+		if si.Pool() == pool {
+			return nil, errors.Errorf("storage is already in pool %q", pool)
+		}
+		if _, err := sb.storageMove(tag.Id()); err == nil {
+			return nil, errors.New("a move is already pending")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+
+		attachments, err := sb.StorageAttachments(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(attachments) != 1 {
+			return nil, errors.New("storage must be attached to exactly one unit")
+		}
+		if attachments[0].Life() != Alive {
+			return nil, errors.New("storage attachment is not alive")
+		}
+		unitTag := attachments[0].Unit()
+		u, err := sb.unit(unitTag.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		machineId, err := u.AssignedMachineId()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		m, err := sb.machine(machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		var filesystemOps []txn.Op
+		var filesystemId string
+		if si.Kind() == StorageKindFilesystem {
+			f, err := sb.storageInstanceFilesystem(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, ok := f.Composition(); ok {
+				return nil, errors.NotSupportedf("moving storage backed by composed volumes")
+			}
+			if _, err := f.Volume(); err == ErrNoBackingVolume {
+				return nil, errors.NotSupportedf("moving filesystem storage not backed by a volume")
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			filesystemAttachment, err := sb.FilesystemAttachment(m.MachineTag(), f.FilesystemTag())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, err := filesystemAttachment.Info(); err != nil {
+				return nil, errors.Trace(err)
+			}
+			filesystemId = f.doc.FilesystemId
+			filesystemOps = []txn.Op{{
+				C:      filesystemsC,
+				Id:     filesystemId,
+				Assert: isAliveDoc,
+			}}
+		}
+
+		source, err := sb.storageInstanceVolume(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		info, err := source.Info()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		sourceAttachment, err := sb.VolumeAttachment(m.MachineTag(), source.VolumeTag())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := sourceAttachment.Info(); err != nil {
+			return nil, errors.Trace(err)
+		}
+		for _, poolName := range []string{info.Pool, pool} {
+			encrypted, err := sb.agentEncryptsPool(poolName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if encrypted {
+				return nil, errors.NotSupportedf("moving storage encrypted by the machine agent")
+			}
+		}
+
+		volumeOps, targetTag, err := sb.addVolumeOps(VolumeParams{
+			Pool: pool,
+			Size: info.Size,
+		}, machineId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		volumeAttachments := []volumeAttachmentTemplate{{
+			tag:    targetTag,
+			params: VolumeAttachmentParams{},
+		}}
+		machineOps, err := addMachineStorageAttachmentsOps(m, volumeAttachments, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		ops := []txn.Op{{
+			C:      storageInstancesC,
+			Id:     si.doc.Id,
+			Assert: append(isAliveDoc, bson.DocElem{"constraints.pool", si.Pool()}),
+		}, {
+			C:      volumesC,
+			Id:     source.doc.Name,
+			Assert: append(isAliveDoc, bson.DocElem{"storageid", tag.Id()}),
+		}, {
+			C:      storageMovesC,
+			Id:     tag.Id(),
+			Assert: txn.DocMissing,
+			Insert: &storageMoveDoc{
+				Unit:         unitTag.Id(),
+				Machine:      machineId,
+				Pool:         pool,
+				SourceVolume: source.doc.Name,
+				TargetVolume: targetTag.Id(),
+				Filesystem:   filesystemId,
+			},
+		}}
+		ops = append(ops, filesystemOps...)
+		ops = append(ops, volumeOps...)
+		ops = append(ops, createMachineVolumeAttachmentsOps(machineId, volumeAttachments)...)
+		ops = append(ops, machineOps...)
+		ops = append(ops, txn.Op{
+			C:      storageAttachmentsC,
+			Id:     storageAttachmentId(unitTag.Id(), tag.Id()),
+			Assert: append(isAliveDoc, bson.DocElem{"move-status", bson.D{{"$exists", false}}}),
+			Update: bson.D{{"$set", bson.D{{"move-status", StorageMoveDetaching}}}},
+		})
+		return ops, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+func (sb *storageBackend) storageMove(id string) (*storageMoveDoc, error) {
+	coll, closer := sb.mb.db().GetCollection(storageMovesC)
+	defer closer()
+
+	var doc storageMoveDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("pending move of storage %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get pending move of storage %q", id)
+	}
+	return &doc, nil
+}
+
+func (sb *storageBackend) volumeCopy(id string) (*volumeCopyDoc, error) {
+	coll, closer := sb.mb.db().GetCollection(volumeCopiesC)
+	defer closer()
+
+	var doc volumeCopyDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("pending copy to volume attachment %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get pending copy to volume attachment %q", id)
+	}
+	return &doc, nil
+}
+
+// quiesceStorageMoveOps returns txn.Ops to record that the unit has run
+// its "storage-detaching" hook for a storage instance being moved. The
+// storage's volume is unassigned from the storage instance, so the unit
+// will see the storage as unprovisioned, and the copy of the volume's
+// data is requested.
+func quiesceStorageMoveOps(sb *storageBackend, move *storageMoveDoc) ([]txn.Op, error) {
+	copyId := volumeAttachmentId(move.Machine, move.TargetVolume)
+	if _, err := sb.volumeCopy(copyId); err == nil {
+		// The copy has already been requested.
+		return nil, jujutxn.ErrNoOperations
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	storageId := sb.mb.localID(move.DocID)
+	return []txn.Op{{
+		C:      storageMovesC,
+		Id:     storageId,
+		Assert: txn.DocExists,
+	}, {
+		C:      storageAttachmentsC,
+		Id:     storageAttachmentId(move.Unit, storageId),
+		Assert: append(isAliveDoc, bson.DocElem{"move-status", StorageMoveDetaching}),
+		Update: bson.D{{"$set", bson.D{{"move-status", StorageMoveCopying}}}},
+	}, {
+		C:      volumesC,
+		Id:     move.SourceVolume,
+		Assert: bson.D{{"storageid", storageId}},
+		Update: bson.D{{"$unset", bson.D{{"storageid", nil}}}},
+	}, {
+		C:      volumeCopiesC,
+		Id:     copyId,
+		Assert: txn.DocMissing,
+		Insert: &volumeCopyDoc{
+			StorageId:  storageId,
+			Source:     move.SourceVolume,
+			Filesystem: move.Filesystem,
+		},
+	}}, nil
+}
+
+// abandonStorageMove abandons the pending move of the storage instance
+// with the specified tag, if any. The target volume is destroyed, the
+// source volume is reassigned to the storage instance, and the storage
+// attachment's move status is cleared.
+func (sb *storageBackend) abandonStorageMove(tag names.StorageTag) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		move, err := sb.storageMove(tag.Id())
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return abandonStorageMoveOps(sb, move)
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+func abandonStorageMoveOps(sb *storageBackend, move *storageMoveDoc) ([]txn.Op, error) {
+	storageId := sb.mb.localID(move.DocID)
+	ops := []txn.Op{{
+		C:      storageMovesC,
+		Id:     storageId,
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	_, err := sb.storageAttachment(names.NewStorageTag(storageId), names.NewUnitTag(move.Unit))
+	if err == nil {
+		ops = append(ops, resumeStorageAttachmentOp(move.Unit, storageId))
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	copyId := volumeAttachmentId(move.Machine, move.TargetVolume)
+	if _, err := sb.volumeCopy(copyId); err == nil {
+		ops = append(ops, txn.Op{
+			C:      volumeCopiesC,
+			Id:     copyId,
+			Assert: txn.DocExists,
+			Remove: true,
+		}, txn.Op{
+			C:      volumesC,
+			Id:     move.SourceVolume,
+			Assert: bson.D{{"storageid", bson.D{{"$exists", false}}}},
+			Update: bson.D{{"$set", bson.D{{"storageid", storageId}}}},
+		})
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	target, err := getVolumeByTag(sb.mb, names.NewVolumeTag(move.TargetVolume))
+	if errors.IsNotFound(err) {
+		return ops, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if target.Life() == Alive {
+		destroyOps, err := destroyVolumeOps(sb, target, false, nil)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, destroyOps...)
+	}
+	return ops, nil
+}
+
+// PendingVolumeCopy returns the pending copy of data to the target
+// volume attached to the specified machine. If there is no pending
+// copy, an error satisfying errors.IsNotFound is returned.
+func (sb *storageBackend) PendingVolumeCopy(host names.MachineTag, target names.VolumeTag) (VolumeCopy, error) {
+	doc, err := sb.volumeCopy(volumeAttachmentId(host.Id(), target.Id()))
+	if err != nil {
+		return VolumeCopy{}, errors.Trace(err)
+	}
+	result := VolumeCopy{Source: names.NewVolumeTag(doc.Source)}
+	if doc.Filesystem != "" {
+		result.Filesystem = names.NewFilesystemTag(doc.Filesystem)
+	}
+	return result, nil
+}
+
+// SetVolumeCopied records that the contents of the source volume have
+// been copied to the specified target volume, completing the move of
+// the storage instance to its new pool. The target volume is assigned
+// to the storage instance, and backs its filesystem if it has one. The
+// source volume is destroyed, and the storage attachment's move status
+// is cleared so that the unit runs its "storage-attached" hook.
+func (sb *storageBackend) SetVolumeCopied(host names.MachineTag, target names.VolumeTag) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set %s copied", names.ReadableString(target))
+	buildTxn := func(int) ([]txn.Op, error) {
+		copyId := volumeAttachmentId(host.Id(), target.Id())
+		copyDoc, err := sb.volumeCopy(copyId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		move, err := sb.storageMove(copyDoc.StorageId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		source, err := getVolumeByTag(sb.mb, names.NewVolumeTag(copyDoc.Source))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      volumeCopiesC,
+			Id:     copyId,
+			Assert: txn.DocExists,
+			Remove: true,
+		}, {
+			C:      storageMovesC,
+			Id:     copyDoc.StorageId,
+			Assert: txn.DocExists,
+			Remove: true,
+		}, {
+			C:      volumesC,
+			Id:     target.Id(),
+			Assert: append(isAliveDoc, bson.DocElem{"storageid", bson.D{{"$exists", false}}}),
+			Update: bson.D{{"$set", bson.D{{"storageid", copyDoc.StorageId}}}},
+		}, {
+			C:      storageInstancesC,
+			Id:     copyDoc.StorageId,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"constraints.pool", move.Pool}}}},
+		}, resumeStorageAttachmentOp(move.Unit, copyDoc.StorageId)}
+		if move.Filesystem != "" {
+			ops = append(ops, txn.Op{
+				C:      filesystemsC,
+				Id:     move.Filesystem,
+				Assert: bson.D{{"volumeid", move.SourceVolume}},
+				Update: bson.D{{"$set", bson.D{
+					{"volumeid", target.Id()},
+					{"info.pool", move.Pool},
+				}}},
+			})
+		}
+		if source.Life() == Alive {
+			hasNoStorageAssignment := bson.D{{"storageid", bson.D{{"$exists", false}}}}
+			destroyOps, err := destroyVolumeOps(sb, source, false, hasNoStorageAssignment)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, destroyOps...)
+		}
+		return ops, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// SetVolumeCopyFailed abandons the move of the storage instance whose
+// data was being copied to the specified target volume. The target
+// volume is destroyed, the source volume is reassigned to the storage
+// instance, and the reason for the failure is recorded in the source
+// volume's status message.
+func (sb *storageBackend) SetVolumeCopyFailed(host names.MachineTag, target names.VolumeTag, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set %s copy failed", names.ReadableString(target))
+	var move *storageMoveDoc
+	buildTxn := func(int) ([]txn.Op, error) {
+		copyDoc, err := sb.volumeCopy(volumeAttachmentId(host.Id(), target.Id()))
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		move, err = sb.storageMove(copyDoc.StorageId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		return abandonStorageMoveOps(sb, move)
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return errors.Trace(err)
+	}
+
+	globalKey := volumeGlobalKey(move.SourceVolume)
+	current, err := getStatus(sb.mb.db(), globalKey, "volume")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return setStatus(sb.mb.db(), setStatusParams{
+		badge:     "volume",
+		globalKey: globalKey,
+		status:    current.Status,
+		message:   fmt.Sprintf("moving to pool %q failed: %s", move.Pool, message),
+		rawData:   current.Data,
+		updated:   timeOrNow(nil, sb.mb.clock()),
+	})
+}
+
+// resumeStorageAttachmentOp returns a txn.Op to clear the move status of
+// the attachment of a storage instance that was being moved, so that the
+// unit runs its "storage-attached" hook if it had stopped using the
+// storage.
+func resumeStorageAttachmentOp(unitId, storageId string) txn.Op {
+	return txn.Op{
+		C:      storageAttachmentsC,
+		Id:     storageAttachmentId(unitId, storageId),
+		Assert: txn.DocExists,
+		Update: bson.D{{"$unset", bson.D{{"move-status", nil}}}},
+	}
+}
+
+// WatchMachineVolumeCopies returns a StringsWatcher that notifies of
+// requests to copy the contents of volumes attached to the specified
+// machine. The watcher reports the IDs of the target volumes.
+func (sb *storageBackend) WatchMachineVolumeCopies(m names.MachineTag) StringsWatcher {
+	mb := sb.mb
+	prefix := m.Id() + ":"
+	pattern := fmt.Sprintf("^%s", mb.docID(prefix))
+	members := bson.D{{"_id", bson.D{{"$regex", pattern}}}}
+	filter := func(id interface{}) bool {
+		k, err := mb.strictLocalID(id.(string))
+		if err != nil {
+			return false
+		}
+		return strings.HasPrefix(k, prefix)
+	}
+	transform := func(id string) string {
+		return strings.TrimPrefix(id, prefix)
+	}
+	return newLifecycleWatcher(mb, volumeCopiesC, members, filter, transform)
+}
+
+func removeStorageMoveOp(id string) txn.Op {
+	return txn.Op{
+		C:      storageMovesC,
+		Id:     id,
+		Remove: true,
+	}
+}
+
+func removeVolumeCopyOp(id string) txn.Op {
+	return txn.Op{
+		C:      volumeCopiesC,
+		Id:     id,
+		Remove: true,
+	}
+}
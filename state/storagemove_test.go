@@ -0,0 +1,289 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageMoveSuite struct {
+	StorageStateSuiteBase
+
+	machineTag names.MachineTag
+	unitTag    names.UnitTag
+	storageTag names.StorageTag
+	source     names.VolumeTag
+}
+
+var _ = gc.Suite(&StorageMoveSuite{})
+
+func (s *StorageMoveSuite) SetUpTest(c *gc.C) {
+	s.StorageStateSuiteBase.SetUpTest(c)
+
+	_, u, storageTag := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	s.machineTag = names.NewMachineTag("0")
+	s.unitTag = u.UnitTag()
+	s.storageTag = storageTag
+	s.source = s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(s.source, state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(
+		s.machineTag, s.source, state.VolumeAttachmentInfo{DeviceName: "sdc"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// moveAndQuiesce requests the move of the storage instance to the
+// "persistent-block" pool, and removes the storage attachment as the
+// unit does after running the "storage-detaching" hook. The tag of
+// the target volume is returned.
+func (s *StorageMoveSuite) moveAndQuiesce(c *gc.C) names.VolumeTag {
+	err := s.storageBackend.MoveStorageInstance(s.storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.RemoveStorageAttachment(s.storageTag, s.unitTag, false)
+	c.Assert(err, jc.ErrorIsNil)
+	return names.NewVolumeTag("1")
+}
+
+func (s *StorageMoveSuite) assertStorageAttachmentMoveStatus(c *gc.C, status state.StorageMoveStatus) {
+	att, err := s.storageBackend.StorageAttachment(s.storageTag, s.unitTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(att.Life(), gc.Equals, state.Alive)
+	c.Assert(att.MoveStatus(), gc.Equals, status)
+}
+
+func (s *StorageMoveSuite) TestMoveStorageInstance(c *gc.C) {
+	err := s.storageBackend.MoveStorageInstance(s.storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The unit is asked to stop using the storage, without
+	// the storage attachment leaving the Alive state.
+	s.assertStorageAttachmentMoveStatus(c, state.StorageMoveDetaching)
+
+	// A volume is created in the target pool, and attached
+	// to the unit's machine.
+	target := s.volume(c, names.NewVolumeTag("1"))
+	params, ok := target.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params.Pool, gc.Equals, "persistent-block")
+	c.Assert(params.Size, gc.Equals, uint64(1024))
+	_, err = target.StorageInstance()
+	c.Assert(err, jc.Satisfies, errors.IsNotAssigned)
+	_, err = s.storageBackend.VolumeAttachment(s.machineTag, target.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	// No copy is requested until the unit has stopped
+	// using the storage.
+	_, err = s.storageBackend.PendingVolumeCopy(s.machineTag, target.VolumeTag())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.storageBackend.MoveStorageInstance(s.storageTag, "persistent-block")
+	c.Assert(err, gc.ErrorMatches, "cannot move storage data/0: a move is already pending")
+}
+
+func (s *StorageMoveSuite) TestMoveStorageInstanceSamePool(c *gc.C) {
+	err := s.storageBackend.MoveStorageInstance(s.storageTag, "loop-pool")
+	c.Assert(err, gc.ErrorMatches, `cannot move storage data/0: storage is already in pool "loop-pool"`)
+}
+
+func (s *StorageMoveSuite) TestMoveStorageInstanceUnknownPool(c *gc.C) {
+	err := s.storageBackend.MoveStorageInstance(s.storageTag, "nope")
+	c.Assert(err, gc.ErrorMatches, `cannot move storage data/0: .*pool "nope" not found`)
+}
+
+func (s *StorageMoveSuite) TestMoveStorageInstanceFilesystemNoBackingVolume(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "tmpfs-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.MoveStorageInstance(storageTag, "loop-pool")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, `cannot move storage data/\d+: moving filesystem storage not backed by a volume not supported`)
+}
+
+func (s *StorageMoveSuite) TestMoveStorageInstanceAgentEncrypted(c *gc.C) {
+	_, err := s.pm.Create("encrypted-loop", "loop", map[string]interface{}{
+		"encrypted": "true",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.MoveStorageInstance(s.storageTag, "encrypted-loop")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "cannot move storage data/0: moving storage encrypted by the machine agent not supported")
+}
+
+func (s *StorageMoveSuite) TestMoveFilesystemStorage(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	machineTag := names.NewMachineTag("1")
+	f := s.storageInstanceFilesystem(c, storageTag)
+	source := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(source, state.VolumeInfo{VolumeId: "vol-456", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(
+		machineTag, source, state.VolumeAttachmentInfo{DeviceName: "sdd"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetFilesystemInfo(f.FilesystemTag(), state.FilesystemInfo{FilesystemId: "fs-456", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetFilesystemAttachmentInfo(
+		machineTag, f.FilesystemTag(), state.FilesystemAttachmentInfo{MountPoint: "/srv"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.MoveStorageInstance(storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.RemoveStorageAttachment(storageTag, u.UnitTag(), false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var target names.VolumeTag
+	volumes, err := s.storageBackend.AllVolumes()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, v := range volumes {
+		if params, ok := v.Params(); ok && params.Pool == "persistent-block" {
+			target = v.VolumeTag()
+		}
+	}
+	c.Assert(target, gc.Not(gc.Equals), names.VolumeTag{})
+
+	// The provisioner is told which filesystem to unmount
+	// while the data is copied.
+	pending, err := s.storageBackend.PendingVolumeCopy(machineTag, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, state.VolumeCopy{
+		Source:     source,
+		Filesystem: f.FilesystemTag(),
+	})
+
+	err = s.storageBackend.SetVolumeCopied(machineTag, target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The filesystem is now backed by the target volume.
+	f = s.filesystem(c, f.FilesystemTag())
+	volumeTag, err := f.Volume()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeTag, gc.Equals, target)
+	info, err := f.Info()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Pool, gc.Equals, "persistent-block")
+	c.Assert(s.volume(c, source).Life(), gc.Equals, state.Dying)
+}
+
+func (s *StorageMoveSuite) TestRemoveStorageAttachmentQuiescesMove(c *gc.C) {
+	target := s.moveAndQuiesce(c)
+
+	// The storage attachment is kept while the data is copied,
+	// and the source volume is unassigned from the storage.
+	s.assertStorageAttachmentMoveStatus(c, state.StorageMoveCopying)
+	_, err := s.storageBackend.StorageInstanceVolume(s.storageTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	pending, err := s.storageBackend.PendingVolumeCopy(s.machineTag, target)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, state.VolumeCopy{Source: s.source})
+
+	// Removing the storage attachment again is a no-op.
+	err = s.storageBackend.RemoveStorageAttachment(s.storageTag, s.unitTag, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStorageAttachmentMoveStatus(c, state.StorageMoveCopying)
+}
+
+func (s *StorageMoveSuite) TestRemoveDyingStorageAttachmentAbandonsMove(c *gc.C) {
+	target := s.moveAndQuiesce(c)
+
+	// The unit is destroyed while the data is being copied.
+	err := s.storageBackend.DestroyUnitStorageAttachments(s.unitTag)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.RemoveStorageAttachment(s.storageTag, s.unitTag, false)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storageBackend.StorageAttachment(s.storageTag, s.unitTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.storageBackend.PendingVolumeCopy(s.machineTag, target)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.volume(c, target).Life(), gc.Equals, state.Dying)
+	c.Assert(s.storageInstanceVolume(c, s.storageTag).VolumeTag(), gc.Equals, s.source)
+}
+
+func (s *StorageMoveSuite) TestRemoveStorageAttachmentForceAbandonsMove(c *gc.C) {
+	target := s.moveAndQuiesce(c)
+
+	err := s.storageBackend.RemoveStorageAttachment(s.storageTag, s.unitTag, true)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storageBackend.StorageAttachment(s.storageTag, s.unitTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.storageBackend.PendingVolumeCopy(s.machineTag, target)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(s.volume(c, target).Life(), gc.Equals, state.Dying)
+	c.Assert(s.storageInstanceVolume(c, s.storageTag).VolumeTag(), gc.Equals, s.source)
+}
+
+func (s *StorageMoveSuite) TestSetVolumeCopied(c *gc.C) {
+	target := s.moveAndQuiesce(c)
+
+	err := s.storageBackend.SetVolumeCopied(s.machineTag, target)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertStorageAttachmentMoveStatus(c, state.StorageMoveNone)
+	c.Assert(s.storageInstanceVolume(c, s.storageTag).VolumeTag(), gc.Equals, target)
+	si, err := s.storageBackend.StorageInstance(s.storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Pool(), gc.Equals, "persistent-block")
+	c.Assert(s.volume(c, s.source).Life(), gc.Equals, state.Dying)
+	_, err = s.storageBackend.PendingVolumeCopy(s.machineTag, target)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.storageBackend.SetVolumeCopied(s.machineTag, target)
+	c.Assert(err, gc.ErrorMatches,
+		`cannot set volume 1 copied: pending copy to volume attachment "0:1" not found`)
+}
+
+func (s *StorageMoveSuite) TestSetVolumeCopyFailed(c *gc.C) {
+	target := s.moveAndQuiesce(c)
+
+	err := s.storageBackend.SetVolumeCopyFailed(s.machineTag, target, "out of space")
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertStorageAttachmentMoveStatus(c, state.StorageMoveNone)
+	c.Assert(s.storageInstanceVolume(c, s.storageTag).VolumeTag(), gc.Equals, s.source)
+	si, err := s.storageBackend.StorageInstance(s.storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(si.Pool(), gc.Equals, "loop-pool")
+	c.Assert(s.volume(c, target).Life(), gc.Equals, state.Dying)
+	volumeStatus, err := s.volume(c, s.source).Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeStatus.Message, gc.Equals, `moving to pool "persistent-block" failed: out of space`)
+
+	// The storage may be moved again.
+	err = s.storageBackend.MoveStorageInstance(s.storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageMoveSuite) TestWatchMachineVolumeCopies(c *gc.C) {
+	w := s.storageBackend.WatchMachineVolumeCopies(s.machineTag)
+	defer testing.AssertStop(c, w)
+	wc := testing.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	err := s.storageBackend.MoveStorageInstance(s.storageTag, "persistent-block")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = s.storageBackend.RemoveStorageAttachment(s.storageTag, s.unitTag, false)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("1")
+	wc.AssertNoChange()
+
+	err = s.storageBackend.SetVolumeCopied(s.machineTag, names.NewVolumeTag("1"))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChangeInSingleEvent("1")
+	wc.AssertNoChange()
+}
@@ -0,0 +1,273 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// storageResizeDoc records a request to grow a volume or filesystem.
+// The document ID is the ID of the volume or filesystem, so that
+// resize requests are scoped to the same host as the storage.
+type storageResizeDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Size      uint64 `bson:"size"`
+}
+
+// ResizeStorageInstance requests that the volume or filesystem assigned
+// to the storage instance with the specified tag be grown to the given
+// size, in MiB. The resize is performed asynchronously by the storage
+// provisioner responsible for the volume or filesystem.
+//
+// Filesystems backed by volumes are resized by first growing the volume,
+// and then growing the filesystem once the volume has been resized.
+func (sb *storageBackend) ResizeStorageInstance(tag names.StorageTag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot resize %s", names.ReadableString(tag))
+	if sb.modelType == ModelTypeCAAS {
+		return errors.NotSupportedf("resizing storage in a Kubernetes model")
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		si, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+
+		var (
+			collection string
+			resizeC    string
+			id         string
+			current    uint64
+		)
+		if si.Kind() == StorageKindFilesystem {
+			f, err := sb.storageInstanceFilesystem(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, ok := f.Composition(); ok {
+				return nil, errors.NotSupportedf("resizing storage backed by composed volumes")
+			}
+			if _, err := f.Volume(); err == ErrNoBackingVolume {
+				info, err := f.Info()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				collection, resizeC = filesystemsC, filesystemResizesC
+				id, current = f.doc.FilesystemId, info.Size
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if id == "" {
+			v, err := sb.storageInstanceVolume(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			info, err := v.Info()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			collection, resizeC = volumesC, volumeResizesC
+			id, current = v.doc.Name, info.Size
+		}
+		if size <= current {
+			return nil, errors.Errorf(
+				"new size %dMiB must be larger than current size %dMiB", size, current,
+			)
+		}
+		if _, err := sb.storageResize(resizeC, id); err == nil {
+			return nil, errors.New("a resize is already pending")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+
+		return []txn.Op{{
+			C:      storageInstancesC,
+			Id:     si.doc.Id,
+			Assert: isAliveDoc,
+		}, {
+			C:      collection,
+			Id:     id,
+			Assert: append(isAliveDoc, bson.DocElem{"info.size", current}),
+		}, {
+			C:      resizeC,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: &storageResizeDoc{Size: size},
+		}}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// PendingStorageResize returns the size, in MiB, that the volume or
+// filesystem with the specified tag has been requested to grow to. If
+// there is no pending resize, an error satisfying errors.IsNotFound is
+// returned.
+func (sb *storageBackend) PendingStorageResize(tag names.Tag) (uint64, error) {
+	resizeC, _, err := storageResizeCollections(tag)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	doc, err := sb.storageResize(resizeC, tag.Id())
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	return doc.Size, nil
+}
+
+func (sb *storageBackend) storageResize(collection, id string) (*storageResizeDoc, error) {
+	coll, closer := sb.mb.db().GetCollection(collection)
+	defer closer()
+
+	var doc storageResizeDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("pending resize of %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get pending resize of %q", id)
+	}
+	return &doc, nil
+}
+
+// SetStorageResized records that the volume or filesystem with the
+// specified tag has been grown to the given size, in MiB, completing
+// the pending resize. If the volume backs a filesystem, a resize of
+// the filesystem is requested so that it may grow into the volume; a
+// resize of the filesystem that is already pending is updated to the
+// new size.
+func (sb *storageBackend) SetStorageResized(tag names.Tag, size uint64) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set %s resized", names.ReadableString(tag))
+	resizeC, collection, err := storageResizeCollections(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(int) ([]txn.Op, error) {
+		if _, err := sb.storageResize(resizeC, tag.Id()); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      resizeC,
+			Id:     tag.Id(),
+			Assert: txn.DocExists,
+			Remove: true,
+		}, {
+			C:      collection,
+			Id:     tag.Id(),
+			Assert: bson.D{{"info", bson.D{{"$exists", true}}}},
+			Update: bson.D{{"$set", bson.D{{"info.size", size}}}},
+		}}
+		if volumeTag, ok := tag.(names.VolumeTag); ok {
+			f, err := sb.volumeFilesystem(volumeTag)
+			if errors.IsNotFound(err) {
+				return ops, nil
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, err := f.Info(); err != nil {
+				// The filesystem has not been created yet, and
+				// will be created to fill the resized volume.
+				return ops, nil
+			}
+			pending, err := sb.storageResize(filesystemResizesC, f.doc.FilesystemId)
+			if errors.IsNotFound(err) {
+				ops = append(ops, txn.Op{
+					C:      filesystemResizesC,
+					Id:     f.doc.FilesystemId,
+					Assert: txn.DocMissing,
+					Insert: &storageResizeDoc{Size: size},
+				})
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			} else {
+				// The filesystem has yet to grow into the volume's
+				// previous size, so have it grow into the new size
+				// instead.
+				ops = append(ops, txn.Op{
+					C:      filesystemResizesC,
+					Id:     f.doc.FilesystemId,
+					Assert: bson.D{{"size", pending.Size}},
+					Update: bson.D{{"$set", bson.D{{"size", size}}}},
+				})
+			}
+		}
+		return ops, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// SetStorageResizeFailed abandons the pending resize of the volume or
+// filesystem with the specified tag, recording the reason for the
+// failure in the entity's status message.
+func (sb *storageBackend) SetStorageResizeFailed(tag names.Tag, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set %s resize failed", names.ReadableString(tag))
+	resizeC, _, err := storageResizeCollections(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	doc, err := sb.storageResize(resizeC, tag.Id())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ops := []txn.Op{{
+		C:      resizeC,
+		Id:     tag.Id(),
+		Assert: txn.DocExists,
+		Remove: true,
+	}}
+	if err := sb.mb.db().RunTransaction(ops); err == txn.ErrAborted {
+		return errors.NotFoundf("pending resize of %q", tag.Id())
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	var globalKey, badge string
+	switch tag := tag.(type) {
+	case names.VolumeTag:
+		globalKey, badge = volumeGlobalKey(tag.Id()), "volume"
+	case names.FilesystemTag:
+		globalKey, badge = filesystemGlobalKey(tag.Id()), "filesystem"
+	}
+	current, err := getStatus(sb.mb.db(), globalKey, badge)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return setStatus(sb.mb.db(), setStatusParams{
+		badge:     badge,
+		globalKey: globalKey,
+		status:    current.Status,
+		message:   fmt.Sprintf("resizing to %dMiB failed: %s", doc.Size, message),
+		rawData:   current.Data,
+		updated:   timeOrNow(nil, sb.mb.clock()),
+	})
+}
+
+// storageResizeCollections returns the names of the collections holding
+// resize requests and the resized entities for the specified volume or
+// filesystem tag.
+func storageResizeCollections(tag names.Tag) (resizeC, collection string, _ error) {
+	switch tag.(type) {
+	case names.VolumeTag:
+		return volumeResizesC, volumesC, nil
+	case names.FilesystemTag:
+		return filesystemResizesC, filesystemsC, nil
+	}
+	return "", "", errors.NotValidf("resize of %s", names.ReadableString(tag))
+}
+
+func removeStorageResizeOp(collection, id string) txn.Op {
+	return txn.Op{
+		C:      collection,
+		Id:     id,
+		Remove: true,
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageResizeSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageResizeSuite{})
+
+// setupProvisionedVolume adds a unit with a single block storage
+// instance, assigns the unit to a machine, and provisions and
+// attaches the storage instance's volume.
+func (s *StorageResizeSuite) setupProvisionedVolume(c *gc.C, kind string) names.VolumeTag {
+	_, u, storageTag := s.setupSingleStorage(c, kind, "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var volumeTag names.VolumeTag
+	if kind == "filesystem" {
+		volumeTag = s.filesystemVolume(c, s.storageInstanceFilesystem(c, storageTag).FilesystemTag()).VolumeTag()
+	} else {
+		volumeTag = s.storageInstanceVolume(c, storageTag).VolumeTag()
+	}
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetVolumeAttachmentInfo(
+		names.NewMachineTag("0"), volumeTag, state.VolumeAttachmentInfo{DeviceName: "sdc"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	return volumeTag
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceVolume(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "block")
+
+	err := s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	size, err := s.storageBackend.PendingStorageResize(volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	err = s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 4096)
+	c.Assert(err, gc.ErrorMatches, "cannot resize storage data/0: a resize is already pending")
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceShrink(c *gc.C) {
+	s.setupProvisionedVolume(c, "block")
+
+	err := s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 1024)
+	c.Assert(err, gc.ErrorMatches,
+		"cannot resize storage data/0: new size 1024MiB must be larger than current size 1024MiB")
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceNotProvisioned(c *gc.C) {
+	_, u, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot resize storage data/0: volume "0/0" not provisioned`)
+}
+
+func (s *StorageResizeSuite) TestResizeStorageInstanceFilesystem(c *gc.C) {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", "tmpfs-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	filesystemTag := s.storageInstanceFilesystem(c, storageTag).FilesystemTag()
+	err = s.storageBackend.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(storageTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	size, err := s.storageBackend.PendingStorageResize(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(2048))
+
+	err = s.storageBackend.SetStorageResized(filesystemTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertFilesystemInfo(c, filesystemTag, state.FilesystemInfo{
+		FilesystemId: "fs-123",
+		Pool:         "tmpfs-pool",
+		Size:         2048,
+	})
+	_, err = s.storageBackend.PendingStorageResize(filesystemTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageResizeSuite) TestSetStorageResizedVolume(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "block")
+	err := s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.SetStorageResized(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertVolumeInfo(c, volumeTag, state.VolumeInfo{
+		VolumeId: "vol-123",
+		Pool:     "loop-pool",
+		Size:     2048,
+	})
+	_, err = s.storageBackend.PendingStorageResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.storageBackend.SetStorageResized(volumeTag, 2048)
+	c.Assert(err, gc.ErrorMatches, `cannot set volume 0/0 resized: pending resize of "0/0" not found`)
+}
+
+func (s *StorageResizeSuite) TestSetStorageResizedVolumeBackedFilesystem(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "filesystem")
+	filesystemTag := s.volumeFilesystem(c, volumeTag).FilesystemTag()
+	err := s.storageBackend.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	// Volume-backed filesystems are resized by their volumes first.
+	_, err = s.storageBackend.PendingStorageResize(filesystemTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.storageBackend.SetStorageResized(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	size, err := s.storageBackend.PendingStorageResize(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(2048))
+}
+
+func (s *StorageResizeSuite) TestSetStorageResizedFilesystemResizePending(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "filesystem")
+	filesystemTag := s.volumeFilesystem(c, volumeTag).FilesystemTag()
+	err := s.storageBackend.SetFilesystemInfo(filesystemTag, state.FilesystemInfo{FilesystemId: "fs-123", Size: 1024})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageResized(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The volume is resized again before the filesystem
+	// has grown into it.
+	err = s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 4096)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageResized(volumeTag, 4096)
+	c.Assert(err, jc.ErrorIsNil)
+	size, err := s.storageBackend.PendingStorageResize(filesystemTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(size, gc.Equals, uint64(4096))
+}
+
+func (s *StorageResizeSuite) TestSetStorageResizeFailed(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "block")
+	err := s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.SetStorageResizeFailed(volumeTag, "resizing not supported")
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.PendingStorageResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	volumeStatus, err := s.volume(c, volumeTag).Status()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeStatus.Message, gc.Equals, "resizing to 2048MiB failed: resizing not supported")
+	s.assertVolumeInfo(c, volumeTag, state.VolumeInfo{
+		VolumeId: "vol-123",
+		Pool:     "loop-pool",
+		Size:     1024,
+	})
+}
+
+func (s *StorageResizeSuite) TestRemoveVolumeRemovesResize(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "block")
+	err := s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.obliterateVolume(c, volumeTag)
+	_, err = s.storageBackend.PendingStorageResize(volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageResizeSuite) TestWatchVolumeResizes(c *gc.C) {
+	volumeTag := s.setupProvisionedVolume(c, "block")
+
+	machineWatcher := s.storageBackend.WatchMachineVolumeResizes(names.NewMachineTag("0"))
+	defer testing.AssertStop(c, machineWatcher)
+	mwc := testing.NewStringsWatcherC(c, s.State, machineWatcher)
+	mwc.AssertChangeInSingleEvent() // initial
+	mwc.AssertNoChange()
+
+	modelWatcher := s.storageBackend.WatchModelVolumeResizes()
+	defer testing.AssertStop(c, modelWatcher)
+	wc := testing.NewStringsWatcherC(c, s.State, modelWatcher)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	err := s.storageBackend.ResizeStorageInstance(names.NewStorageTag("data/0"), 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	mwc.AssertChangeInSingleEvent(volumeTag.Id())
+	mwc.AssertNoChange()
+	// The volume is scoped to machine 0, so its resize
+	// is not reported by the model resize watcher.
+	wc.AssertNoChange()
+
+	err = s.storageBackend.SetStorageResized(volumeTag, 2048)
+	c.Assert(err, jc.ErrorIsNil)
+	mwc.AssertChangeInSingleEvent(volumeTag.Id())
+	mwc.AssertNoChange()
+}
@@ -0,0 +1,591 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v6"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// StorageSnapshotStatus describes the progress of a storage snapshot.
+type StorageSnapshotStatus string
+
+const (
+	// StorageSnapshotPending indicates that the snapshot has been
+	// requested, but not yet taken by the storage provisioner.
+	StorageSnapshotPending StorageSnapshotStatus = "pending"
+
+	// StorageSnapshotAvailable indicates that the snapshot has been
+	// taken, and may be used to restore storage.
+	StorageSnapshotAvailable StorageSnapshotStatus = "available"
+
+	// StorageSnapshotFailed indicates that the storage provisioner
+	// failed to take the snapshot.
+	StorageSnapshotFailed StorageSnapshotStatus = "failed"
+)
+
+// StorageSnapshot describes a point-in-time snapshot of the volume or
+// filesystem assigned to a storage instance.
+type StorageSnapshot interface {
+	// Id returns the unique ID of the snapshot. Snapshots of
+	// machine-scoped volumes and filesystems are themselves
+	// scoped to the machine, and have IDs of the form
+	// "<machine-id>/<number>".
+	Id() string
+
+	// StorageInstance returns the tag of the storage instance that
+	// was snapshotted.
+	StorageInstance() names.StorageTag
+
+	// StorageName returns the name of the charm storage that the
+	// snapshotted storage instance was created for.
+	StorageName() string
+
+	// Kind returns the kind of the snapshotted storage instance.
+	Kind() StorageKind
+
+	// Volume returns the tag of the snapshotted volume, or
+	// ErrNoBackingVolume if a filesystem was snapshotted.
+	Volume() (names.VolumeTag, error)
+
+	// Filesystem returns the tag of the snapshotted filesystem,
+	// or a NotFound error if a volume was snapshotted.
+	Filesystem() (names.FilesystemTag, error)
+
+	// Host returns the tag of the machine that the snapshot is
+	// scoped to, if any.
+	Host() (names.MachineTag, bool)
+
+	// Pool returns the name of the storage pool that the snapshotted
+	// storage was provisioned from.
+	Pool() string
+
+	// Size returns the size of the snapshotted storage, in MiB.
+	Size() uint64
+
+	// Status returns the status of the snapshot, and a message
+	// describing the reason for failure, if any.
+	Status() (StorageSnapshotStatus, string)
+
+	// SnapshotId returns the provider-supplied ID of the snapshot,
+	// or a NotProvisioned error if the snapshot is not available.
+	SnapshotId() (string, error)
+
+	// Created returns the time at which the snapshot was requested.
+	Created() time.Time
+
+	// Life returns the life of the snapshot. Dying snapshots are
+	// deleted from the storage provider by the storage provisioner,
+	// which then removes them.
+	Life() Life
+}
+
+type storageSnapshot struct {
+	doc storageSnapshotDoc
+}
+
+// storageSnapshotDoc records a snapshot of a volume or filesystem.
+type storageSnapshotDoc struct {
+	DocID        string                `bson:"_id"`
+	ModelUUID    string                `bson:"model-uuid"`
+	Id           string                `bson:"id"`
+	StorageId    string                `bson:"storageid"`
+	StorageName  string                `bson:"storagename"`
+	Kind         StorageKind           `bson:"storagekind"`
+	VolumeId     string                `bson:"volumeid,omitempty"`
+	FilesystemId string                `bson:"filesystemid,omitempty"`
+	Pool         string                `bson:"pool"`
+	Size         uint64                `bson:"size"`
+	Status       StorageSnapshotStatus `bson:"status"`
+	Message      string                `bson:"message,omitempty"`
+	SnapshotId   string                `bson:"snapshotid,omitempty"`
+	Created      int64                 `bson:"created"`
+	Life         Life                  `bson:"life"`
+	Restores     int                   `bson:"restores,omitempty"`
+	TxnRevno     int64                 `bson:"txn-revno"`
+}
+
+// Id is required to implement StorageSnapshot.
+func (s *storageSnapshot) Id() string {
+	return s.doc.Id
+}
+
+// StorageInstance is required to implement StorageSnapshot.
+func (s *storageSnapshot) StorageInstance() names.StorageTag {
+	return names.NewStorageTag(s.doc.StorageId)
+}
+
+// StorageName is required to implement StorageSnapshot.
+func (s *storageSnapshot) StorageName() string {
+	return s.doc.StorageName
+}
+
+// Kind is required to implement StorageSnapshot.
+func (s *storageSnapshot) Kind() StorageKind {
+	return s.doc.Kind
+}
+
+// Volume is required to implement StorageSnapshot.
+func (s *storageSnapshot) Volume() (names.VolumeTag, error) {
+	if s.doc.VolumeId == "" {
+		return names.VolumeTag{}, ErrNoBackingVolume
+	}
+	return names.NewVolumeTag(s.doc.VolumeId), nil
+}
+
+// Filesystem is required to implement StorageSnapshot.
+func (s *storageSnapshot) Filesystem() (names.FilesystemTag, error) {
+	if s.doc.FilesystemId == "" {
+		return names.FilesystemTag{}, errors.NotFoundf("filesystem for storage snapshot %q", s.doc.Id)
+	}
+	return names.NewFilesystemTag(s.doc.FilesystemId), nil
+}
+
+// Host is required to implement StorageSnapshot.
+func (s *storageSnapshot) Host() (names.MachineTag, bool) {
+	hostId := storageSnapshotHostId(s.doc.Id)
+	if hostId == "" {
+		return names.MachineTag{}, false
+	}
+	return names.NewMachineTag(hostId), true
+}
+
+// Pool is required to implement StorageSnapshot.
+func (s *storageSnapshot) Pool() string {
+	return s.doc.Pool
+}
+
+// Size is required to implement StorageSnapshot.
+func (s *storageSnapshot) Size() uint64 {
+	return s.doc.Size
+}
+
+// Status is required to implement StorageSnapshot.
+func (s *storageSnapshot) Status() (StorageSnapshotStatus, string) {
+	return s.doc.Status, s.doc.Message
+}
+
+// SnapshotId is required to implement StorageSnapshot.
+func (s *storageSnapshot) SnapshotId() (string, error) {
+	if s.doc.Status != StorageSnapshotAvailable {
+		return "", errors.NotProvisionedf("storage snapshot %q", s.doc.Id)
+	}
+	return s.doc.SnapshotId, nil
+}
+
+// Created is required to implement StorageSnapshot.
+func (s *storageSnapshot) Created() time.Time {
+	return time.Unix(0, s.doc.Created).UTC()
+}
+
+// Life is required to implement StorageSnapshot.
+func (s *storageSnapshot) Life() Life {
+	return s.doc.Life
+}
+
+// storageSnapshotHostId returns the ID of the machine that the
+// snapshot with the given ID is scoped to, or "" if the snapshot
+// is model-scoped.
+func storageSnapshotHostId(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		return id[:i]
+	}
+	return ""
+}
+
+// StorageSnapshot returns the StorageSnapshot with the specified ID.
+func (sb *storageBackend) StorageSnapshot(id string) (StorageSnapshot, error) {
+	s, err := sb.storageSnapshot(id)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return s, nil
+}
+
+func (sb *storageBackend) storageSnapshot(id string) (*storageSnapshot, error) {
+	coll, closer := sb.mb.db().GetCollection(storageSnapshotsC)
+	defer closer()
+
+	var doc storageSnapshotDoc
+	err := coll.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("storage snapshot %q", id)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get storage snapshot %q", id)
+	}
+	return &storageSnapshot{doc}, nil
+}
+
+// AllStorageSnapshots returns all storage snapshots in the model.
+func (sb *storageBackend) AllStorageSnapshots() ([]StorageSnapshot, error) {
+	return sb.storageSnapshots(nil)
+}
+
+// StorageInstanceSnapshots returns the snapshots taken of the storage
+// instance with the specified tag.
+func (sb *storageBackend) StorageInstanceSnapshots(tag names.StorageTag) ([]StorageSnapshot, error) {
+	return sb.storageSnapshots(bson.D{{"storageid", tag.Id()}})
+}
+
+func (sb *storageBackend) storageSnapshots(query bson.D) ([]StorageSnapshot, error) {
+	coll, closer := sb.mb.db().GetCollection(storageSnapshotsC)
+	defer closer()
+
+	var docs []storageSnapshotDoc
+	if err := coll.Find(query).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot get storage snapshots")
+	}
+	snapshots := make([]StorageSnapshot, len(docs))
+	for i, doc := range docs {
+		snapshots[i] = &storageSnapshot{doc}
+	}
+	return snapshots, nil
+}
+
+// CreateStorageSnapshot requests a snapshot of the volume or filesystem
+// assigned to the storage instance with the specified tag, returning
+// the ID of the new snapshot. The snapshot is taken asynchronously by
+// the storage provisioner responsible for the volume or filesystem.
+func (sb *storageBackend) CreateStorageSnapshot(tag names.StorageTag) (_ string, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot create snapshot of %s", names.ReadableString(tag))
+	if sb.modelType == ModelTypeCAAS {
+		return "", errors.NotSupportedf("storage snapshots in a Kubernetes model")
+	}
+
+	var id string
+	buildTxn := func(int) ([]txn.Op, error) {
+		si, err := sb.storageInstance(tag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if si.Life() != Alive {
+			return nil, errors.New("storage is not alive")
+		}
+
+		doc := storageSnapshotDoc{
+			StorageId:   si.doc.Id,
+			StorageName: si.doc.StorageName,
+			Kind:        si.doc.Kind,
+			Status:      StorageSnapshotPending,
+			Created:     sb.mb.clock().Now().UnixNano(),
+			Life:        Alive,
+		}
+		var hostId string
+		var assertOps []txn.Op
+		if si.Kind() == StorageKindFilesystem {
+			f, err := sb.storageInstanceFilesystem(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if _, ok := f.Composition(); ok {
+				return nil, errors.NotSupportedf("snapshotting storage backed by composed volumes")
+			}
+			if volumeTag, err := f.Volume(); err == nil {
+				// Volume-backed filesystems are
+				// snapshotted by their volumes.
+				doc.VolumeId = volumeTag.Id()
+			} else if err != ErrNoBackingVolume {
+				return nil, errors.Trace(err)
+			} else {
+				info, err := f.Info()
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				doc.FilesystemId = f.doc.FilesystemId
+				doc.Pool = info.Pool
+				doc.Size = info.Size
+				if machineTag, ok := names.FilesystemMachine(f.FilesystemTag()); ok {
+					hostId = machineTag.Id()
+				}
+				assertOps = append(assertOps, txn.Op{
+					C:      filesystemsC,
+					Id:     f.doc.FilesystemId,
+					Assert: isAliveDoc,
+				})
+			}
+		}
+		if doc.FilesystemId == "" {
+			v, err := sb.storageInstanceVolume(tag)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			info, err := v.Info()
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			doc.VolumeId = v.doc.Name
+			doc.Pool = info.Pool
+			doc.Size = info.Size
+			if machineTag, ok := names.VolumeMachine(v.VolumeTag()); ok {
+				hostId = machineTag.Id()
+			}
+			assertOps = append(assertOps, txn.Op{
+				C:      volumesC,
+				Id:     v.doc.Name,
+				Assert: isAliveDoc,
+			})
+		}
+
+		seq, err := sequence(sb.mb, "storagesnapshot")
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		id = fmt.Sprint(seq)
+		if hostId != "" {
+			id = hostId + "/" + id
+		}
+		doc.Id = id
+
+		ops := []txn.Op{{
+			C:      storageInstancesC,
+			Id:     si.doc.Id,
+			Assert: isAliveDoc,
+		}, {
+			C:      storageSnapshotsC,
+			Id:     id,
+			Assert: txn.DocMissing,
+			Insert: &doc,
+		}}
+		return append(ops, assertOps...), nil
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return "", errors.Trace(err)
+	}
+	return id, nil
+}
+
+// SetStorageSnapshotId records the provider-supplied ID of the
+// snapshot with the specified ID, marking the snapshot available.
+func (sb *storageBackend) SetStorageSnapshotId(id, snapshotId string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set provider ID of storage snapshot %q", id)
+	if snapshotId == "" {
+		return errors.New("snapshot ID not set")
+	}
+	return sb.setStorageSnapshotStatus(id, bson.D{
+		{"status", StorageSnapshotAvailable},
+		{"snapshotid", snapshotId},
+	})
+}
+
+// SetStorageSnapshotFailed marks the snapshot with the specified ID as
+// failed, recording the reason for the failure.
+func (sb *storageBackend) SetStorageSnapshotFailed(id, message string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set status of storage snapshot %q", id)
+	return sb.setStorageSnapshotStatus(id, bson.D{
+		{"status", StorageSnapshotFailed},
+		{"message", message},
+	})
+}
+
+func (sb *storageBackend) setStorageSnapshotStatus(id string, update bson.D) error {
+	buildTxn := func(int) ([]txn.Op, error) {
+		s, err := sb.storageSnapshot(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Status != StorageSnapshotPending {
+			return nil, errors.Errorf("snapshot is %s", s.doc.Status)
+		}
+		return []txn.Op{{
+			C:      storageSnapshotsC,
+			Id:     id,
+			Assert: bson.D{{"status", StorageSnapshotPending}},
+			Update: bson.D{{"$set", update}},
+		}}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// DestroyStorageSnapshot marks the snapshot with the specified ID as
+// dying, so that the storage provisioner deletes it from the storage
+// provider and then removes it. Failed snapshots, which do not exist
+// in the storage provider, are removed immediately. Pending snapshots,
+// and snapshots from which storage is still being restored, cannot be
+// destroyed.
+func (sb *storageBackend) DestroyStorageSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot destroy storage snapshot %q", id)
+	buildTxn := func(int) ([]txn.Op, error) {
+		s, err := sb.storageSnapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life != Alive {
+			return nil, jujutxn.ErrNoOperations
+		}
+		switch s.doc.Status {
+		case StorageSnapshotPending:
+			return nil, errors.New("snapshot is pending")
+		case StorageSnapshotFailed:
+			return []txn.Op{{
+				C:      storageSnapshotsC,
+				Id:     id,
+				Assert: bson.D{{"status", StorageSnapshotFailed}},
+				Remove: true,
+			}}, nil
+		}
+		restoring, err := sb.storageSnapshotRestoring(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if restoring {
+			return nil, errors.New("storage is being restored from the snapshot")
+		}
+		// RestoreStorage updates the snapshot, so asserting its
+		// txn-revno ensures that no restore started since the
+		// check above.
+		return []txn.Op{{
+			C:  storageSnapshotsC,
+			Id: id,
+			Assert: bson.D{
+				{"life", Alive},
+				{"status", StorageSnapshotAvailable},
+				{"txn-revno", s.doc.TxnRevno},
+			},
+			Update: bson.D{{"$set", bson.D{{"life", Dying}}}},
+		}}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// storageSnapshotRestoring reports whether any volume or filesystem is
+// yet to be created from the snapshot with the specified ID. The
+// creation parameters of volumes and filesystems are cleared once they
+// are provisioned.
+func (sb *storageBackend) storageSnapshotRestoring(id string) (bool, error) {
+	for _, collection := range []string{volumesC, filesystemsC} {
+		coll, closer := sb.mb.db().GetCollection(collection)
+		n, err := coll.Find(bson.D{{"params.snapshot", id}}).Count()
+		closer()
+		if err != nil {
+			return false, errors.Annotatef(err, "cannot count %s restored from snapshot", collection)
+		}
+		if n > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RemoveStorageSnapshot removes the dying snapshot with the specified
+// ID. It is called by the storage provisioner once the snapshot has
+// been deleted from the storage provider.
+func (sb *storageBackend) RemoveStorageSnapshot(id string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot remove storage snapshot %q", id)
+	buildTxn := func(int) ([]txn.Op, error) {
+		s, err := sb.storageSnapshot(id)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Life == Alive {
+			return nil, errors.New("snapshot is not dying")
+		}
+		return []txn.Op{{
+			C:      storageSnapshotsC,
+			Id:     id,
+			Assert: bson.D{{"life", bson.D{{"$ne", Alive}}}},
+			Remove: true,
+		}}, nil
+	}
+	return sb.mb.db().Run(buildTxn)
+}
+
+// RestoreStorage adds a storage instance to the unit with the specified
+// tag, whose volume or filesystem is created from the snapshot with the
+// specified ID. The restored storage is assigned to the charm storage
+// that was snapshotted, and provisioned from the same storage pool.
+func (sb *storageBackend) RestoreStorage(snapshotId string, tag names.UnitTag) (_ names.StorageTag, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot restore storage snapshot %q to %s", snapshotId, names.ReadableString(tag))
+	u, err := sb.unit(tag.Id())
+	if err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	var storageTag names.StorageTag
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := u.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		s, err := sb.storageSnapshot(snapshotId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if s.doc.Status != StorageSnapshotAvailable {
+			return nil, errors.Errorf("snapshot is %s", s.doc.Status)
+		}
+		if s.doc.Life != Alive {
+			return nil, errors.New("snapshot is being deleted")
+		}
+		if hostId := storageSnapshotHostId(s.doc.Id); hostId != "" {
+			// The snapshot is local to a machine,
+			// so the unit must be assigned to it.
+			machineId, err := u.AssignedMachineId()
+			if err != nil && !errors.IsNotAssigned(err) {
+				return nil, errors.Trace(err)
+			}
+			if machineId != hostId {
+				return nil, errors.Errorf(
+					"snapshot is scoped to machine %s, unit is not assigned to it", hostId,
+				)
+			}
+		}
+		ch, err := u.charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		charmStorage, ok := ch.Meta().Storage[s.doc.StorageName]
+		if !ok {
+			return nil, errors.NotFoundf("charm storage %q", s.doc.StorageName)
+		}
+		kind := StorageKindBlock
+		if charmStorage.Type == charm.StorageFilesystem {
+			kind = StorageKindFilesystem
+		}
+		if kind != s.doc.Kind {
+			return nil, errors.Errorf(
+				"charm storage %q is not of kind %s", s.doc.StorageName, s.doc.Kind,
+			)
+		}
+
+		cons := StorageConstraints{
+			Pool:     s.doc.Pool,
+			Size:     s.doc.Size,
+			Count:    1,
+			snapshot: s.doc.Id,
+		}
+		tags, ops, err := sb.addStorageForUnitOps(u, s.doc.StorageName, cons)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if len(tags) != 1 {
+			return nil, errors.Errorf("expected 1 storage instance, got %d", len(tags))
+		}
+		storageTag = tags[0]
+		// Counting the restores changes the snapshot's txn-revno,
+		// which DestroyStorageSnapshot asserts.
+		return append(ops, txn.Op{
+			C:      storageSnapshotsC,
+			Id:     s.doc.Id,
+			Assert: bson.D{{"life", Alive}, {"status", StorageSnapshotAvailable}},
+			Update: bson.D{{"$inc", bson.D{{"restores", 1}}}},
+		}), nil
+	}
+	if err := sb.mb.db().Run(buildTxn); err != nil {
+		return names.StorageTag{}, errors.Trace(err)
+	}
+	return storageTag, nil
+}
@@ -0,0 +1,350 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/testing"
+)
+
+type StorageSnapshotSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&StorageSnapshotSuite{})
+
+// setupProvisionedStorage adds a unit with a single storage instance of
+// the specified kind, assigns the unit to a machine, and provisions the
+// storage instance's volume.
+func (s *StorageSnapshotSuite) setupProvisionedStorage(c *gc.C, kind, pool string) (*state.Application, *state.Unit, names.VolumeTag) {
+	app, u, storageTag := s.setupSingleStorageDetachable(c, kind, pool)
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var volumeTag names.VolumeTag
+	if kind == "filesystem" {
+		volumeTag = s.filesystemVolume(c, s.storageInstanceFilesystem(c, storageTag).FilesystemTag()).VolumeTag()
+	} else {
+		volumeTag = s.storageInstanceVolume(c, storageTag).VolumeTag()
+	}
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-123", Size: 2048})
+	c.Assert(err, jc.ErrorIsNil)
+	return app, u, volumeTag
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshot(c *gc.C) {
+	_, _, volumeTag := s.setupProvisionedStorage(c, "block", "loop-pool")
+
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "0/0")
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Id(), gc.Equals, "0/0")
+	c.Assert(snapshot.StorageInstance(), gc.Equals, names.NewStorageTag("data/0"))
+	c.Assert(snapshot.StorageName(), gc.Equals, "data")
+	c.Assert(snapshot.Kind(), gc.Equals, state.StorageKindBlock)
+	c.Assert(snapshot.Pool(), gc.Equals, "loop-pool")
+	c.Assert(snapshot.Size(), gc.Equals, uint64(2048))
+	host, ok := snapshot.Host()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(host, gc.Equals, names.NewMachineTag("0"))
+	snapshotVolume, err := snapshot.Volume()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotVolume, gc.Equals, volumeTag)
+	status, message := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotPending)
+	c.Assert(message, gc.Equals, "")
+	_, err = snapshot.SnapshotId()
+	c.Assert(err, jc.Satisfies, errors.IsNotProvisioned)
+
+	all, err := s.storageBackend.AllStorageSnapshots()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+	c.Assert(all[0].Id(), gc.Equals, "0/0")
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotModelScoped(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "modelscoped")
+
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(id, gc.Equals, "0")
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, ok := snapshot.Host()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotVolumeBackedFilesystem(c *gc.C) {
+	_, _, volumeTag := s.setupProvisionedStorage(c, "filesystem", "loop-pool")
+
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Kind(), gc.Equals, state.StorageKindFilesystem)
+	snapshotVolume, err := snapshot.Volume()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotVolume, gc.Equals, volumeTag)
+	_, err = snapshot.Filesystem()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageSnapshotSuite) TestCreateStorageSnapshotNotProvisioned(c *gc.C) {
+	_, u, _ := s.setupSingleStorage(c, "block", "loop-pool")
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, gc.ErrorMatches, `cannot create snapshot of storage data/0: volume "0/0" not provisioned`)
+}
+
+func (s *StorageSnapshotSuite) TestSetStorageSnapshotId(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	status, _ := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotAvailable)
+	snapshotId, err := snapshot.SnapshotId()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshotId, gc.Equals, "snapshot-0-0")
+
+	err = s.storageBackend.SetStorageSnapshotFailed(id, "too late")
+	c.Assert(err, gc.ErrorMatches, `cannot set status of storage snapshot "0/0": snapshot is available`)
+}
+
+func (s *StorageSnapshotSuite) TestSetStorageSnapshotFailed(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.SetStorageSnapshotFailed(id, "snapshots not supported")
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	status, message := snapshot.Status()
+	c.Assert(status, gc.Equals, state.StorageSnapshotFailed)
+	c.Assert(message, gc.Equals, "snapshots not supported")
+
+	// Failed snapshots do not exist in the storage
+	// provider, so they are removed immediately.
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageSnapshotSuite) TestDestroyStorageSnapshot(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Life(), gc.Equals, state.Dying)
+
+	// Destroying a dying snapshot is a no-op.
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.RemoveStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StorageSnapshotSuite) TestDestroyStorageSnapshotPending(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, gc.ErrorMatches, `cannot destroy storage snapshot "0/0": snapshot is pending`)
+}
+
+func (s *StorageSnapshotSuite) TestDestroyStorageSnapshotRestoring(c *gc.C) {
+	_, u, _ := s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+	storageTag, err := s.storageBackend.RestoreStorage(id, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, gc.ErrorMatches, `cannot destroy storage snapshot "0/0": storage is being restored from the snapshot`)
+
+	// Once the restored volume is provisioned, the
+	// snapshot may be destroyed.
+	volumeTag := s.storageInstanceVolume(c, storageTag).VolumeTag()
+	err = s.storageBackend.SetVolumeInfo(volumeTag, state.VolumeInfo{VolumeId: "vol-456", Size: 2048})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageSnapshotSuite) TestDestroyStorageSnapshotRestoreStarted(c *gc.C) {
+	_, u, _ := s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	defer state.SetBeforeHooks(c, s.State, func() {
+		_, err := s.storageBackend.RestoreStorage(id, u.UnitTag())
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, gc.ErrorMatches, `cannot destroy storage snapshot "0/0": storage is being restored from the snapshot`)
+	snapshot, err := s.storageBackend.StorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(snapshot.Life(), gc.Equals, state.Alive)
+}
+
+func (s *StorageSnapshotSuite) TestRemoveStorageSnapshotNotDying(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.storageBackend.RemoveStorageSnapshot(id)
+	c.Assert(err, gc.ErrorMatches, `cannot remove storage snapshot "0/0": snapshot is not dying`)
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorage(c *gc.C) {
+	_, u, _ := s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	storageTag, err := s.storageBackend.RestoreStorage(id, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(storageTag, gc.Equals, names.NewStorageTag("data/1"))
+
+	volume := s.storageInstanceVolume(c, storageTag)
+	c.Assert(volume.VolumeTag(), gc.Equals, names.NewVolumeTag("0/1"))
+	params, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(params, jc.DeepEquals, state.VolumeParams{
+		Pool:     "loop-pool",
+		Size:     2048,
+		Snapshot: "0/0",
+	})
+	_, err = s.storageBackend.VolumeAttachment(names.NewMachineTag("0"), volume.VolumeTag())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorageVolumeBackedFilesystem(c *gc.C) {
+	_, u, _ := s.setupProvisionedStorage(c, "filesystem", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	storageTag, err := s.storageBackend.RestoreStorage(id, u.UnitTag())
+	c.Assert(err, jc.ErrorIsNil)
+
+	filesystem := s.storageInstanceFilesystem(c, storageTag)
+	filesystemParams, ok := filesystem.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(filesystemParams.Snapshot, gc.Equals, id)
+	volume := s.filesystemVolume(c, filesystem.FilesystemTag())
+	volumeParams, ok := volume.Params()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(volumeParams.Snapshot, gc.Equals, id)
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorageSnapshotPending(c *gc.C) {
+	_, u, _ := s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.RestoreStorage(id, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot restore storage snapshot "0/0" to unit storage-block/0: snapshot is pending`)
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorageSnapshotDying(c *gc.C) {
+	_, u, _ := s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.RestoreStorage(id, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches, `cannot restore storage snapshot "0/0" to unit storage-block/0: snapshot is being deleted`)
+}
+
+func (s *StorageSnapshotSuite) TestRestoreStorageOtherMachine(c *gc.C) {
+	app, _, _ := s.setupProvisionedStorage(c, "block", "loop-pool")
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	u, err := app.AddUnit(state.AddUnitParams{})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.storageBackend.RestoreStorage(id, u.UnitTag())
+	c.Assert(err, gc.ErrorMatches,
+		`cannot restore storage snapshot "0/0" to unit storage-block/1: `+
+			`snapshot is scoped to machine 0, unit is not assigned to it`)
+}
+
+func (s *StorageSnapshotSuite) TestWatchStorageSnapshots(c *gc.C) {
+	s.setupProvisionedStorage(c, "block", "loop-pool")
+
+	machineWatcher := s.storageBackend.WatchMachineStorageSnapshots(names.NewMachineTag("0"))
+	defer testing.AssertStop(c, machineWatcher)
+	mwc := testing.NewStringsWatcherC(c, s.State, machineWatcher)
+	mwc.AssertChangeInSingleEvent() // initial
+	mwc.AssertNoChange()
+
+	modelWatcher := s.storageBackend.WatchModelStorageSnapshots()
+	defer testing.AssertStop(c, modelWatcher)
+	wc := testing.NewStringsWatcherC(c, s.State, modelWatcher)
+	wc.AssertChangeInSingleEvent() // initial
+	wc.AssertNoChange()
+
+	id, err := s.storageBackend.CreateStorageSnapshot(names.NewStorageTag("data/0"))
+	c.Assert(err, jc.ErrorIsNil)
+	mwc.AssertChangeInSingleEvent(id)
+	mwc.AssertNoChange()
+	// The snapshot is scoped to machine 0, so it is
+	// not reported by the model snapshot watcher.
+	wc.AssertNoChange()
+
+	err = s.storageBackend.SetStorageSnapshotId(id, "snapshot-0-0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.storageBackend.DestroyStorageSnapshot(id)
+	c.Assert(err, jc.ErrorIsNil)
+	mwc.AssertChangeInSingleEvent(id)
+	mwc.AssertNoChange()
+}
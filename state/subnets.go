@@ -139,6 +139,34 @@ func (s *Subnet) CIDR() string {
 	return s.doc.CIDR
 }
 
+// AddressType returns the type of the addresses in the subnet,
+// derived from its CIDR.
+func (s *Subnet) AddressType() network.AddressType {
+	return network.CIDRAddressType(s.doc.CIDR)
+}
+
+// DualStackSubnets returns the alive subnets of the other address type
+// that share this subnet's provider network, which together with this
+// subnet make up a dual-stack network. An empty slice is returned if the
+// subnet is not part of a known provider network.
+func (s *Subnet) DualStackSubnets() ([]*Subnet, error) {
+	if s.doc.ProviderNetworkId == "" {
+		return nil, nil
+	}
+	docs, err := s.st.dualStackSubnetDocs(s.doc.ProviderNetworkId, s.AddressType())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnets := make([]*Subnet, len(docs))
+	for i, doc := range docs {
+		subnets[i] = &Subnet{st: s.st, doc: doc}
+		if err := subnets[i].setSpace(nil); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return subnets, nil
+}
+
 // VLANTag returns the subnet VLAN tag. It's a number between 1 and
 // 4094 for VLANs and 0 if the network is not a VLAN.
 func (s *Subnet) VLANTag() int {
@@ -365,6 +393,18 @@ func (st *State) addSubnetOps(id string, args network.SubnetInfo) (subnetDoc, []
 	if !unique {
 		return subnetDoc{}, nil, errors.AlreadyExistsf("subnet %q", args.CIDR)
 	}
+	if args.SpaceID == "" && args.ProviderNetworkId != "" {
+		// A subnet sharing a provider network with a subnet of the other
+		// address type is the second half of a dual-stack network, so it
+		// joins the space of its counterpart.
+		docs, err := st.dualStackSubnetDocs(string(args.ProviderNetworkId), network.CIDRAddressType(args.CIDR))
+		if err != nil {
+			return subnetDoc{}, nil, errors.Trace(err)
+		}
+		if len(docs) > 0 {
+			args.SpaceID = docs[0].SpaceID
+		}
+	}
 	if args.SpaceID == "" {
 		// Ensure the subnet is added to the default space
 		// if none is defined for the subnet.
@@ -425,6 +465,32 @@ func (st *State) uniqueSubnet(cidr, providerID string) (bool, error) {
 	return count == 0, nil
 }
 
+// dualStackSubnetDocs returns the docs for alive subnets in the input
+// provider network that do not have the input address type.
+func (st *State) dualStackSubnetDocs(providerNetworkId string, addrType network.AddressType) ([]subnetDoc, error) {
+	subnets, closer := st.db().GetCollection(subnetsC)
+	defer closer()
+
+	var docs []subnetDoc
+	err := subnets.Find(bson.D{
+		{"provider-network-id", providerNetworkId},
+		{"life", Alive},
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets in provider network %q", providerNetworkId)
+	}
+	var result []subnetDoc
+	for _, doc := range docs {
+		if doc.FanLocalUnderlay != "" {
+			continue
+		}
+		if docType := network.CIDRAddressType(doc.CIDR); docType != "" && docType != addrType {
+			result = append(result, doc)
+		}
+	}
+	return result, nil
+}
+
 // Subnet returns the subnet specified by the id.
 func (st *State) Subnet(id string) (*Subnet, error) {
 	return st.subnet(bson.M{"subnet-id": id}, id)
@@ -72,6 +72,93 @@ func (s *SubnetSuite) assertSubnetMatchesInfo(c *gc.C, subnet *state.Subnet, inf
 	c.Assert(subnet.IsPublic(), gc.Equals, info.IsPublic)
 }
 
+func (s *SubnetSuite) TestAddSubnetJoinsDualStackSpace(c *gc.C) {
+	space, err := s.State.AddSpace("dual", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "v4",
+		ProviderNetworkId: "net",
+		CIDR:              "10.0.0.0/24",
+		SpaceID:           space.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "v6",
+		ProviderNetworkId: "net",
+		CIDR:              "2001:db8::/64",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subnet.AddressType(), gc.Equals, network.IPv6Address)
+	c.Check(subnet.SpaceID(), gc.Equals, space.Id())
+}
+
+func (s *SubnetSuite) TestAddSubnetSameAddressTypeDoesNotJoinSpace(c *gc.C) {
+	space, err := s.State.AddSpace("v4-only", "", nil, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "a",
+		ProviderNetworkId: "net",
+		CIDR:              "10.0.0.0/24",
+		SpaceID:           space.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnet, err := s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "b",
+		ProviderNetworkId: "net",
+		CIDR:              "10.0.1.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subnet.SpaceID(), gc.Equals, network.AlphaSpaceId)
+}
+
+func (s *SubnetSuite) TestDualStackSubnets(c *gc.C) {
+	v4, err := s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "v4",
+		ProviderNetworkId: "net",
+		CIDR:              "10.0.0.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "v4-other",
+		ProviderNetworkId: "net",
+		CIDR:              "10.0.1.0/24",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	v6, err := s.State.AddSubnet(network.SubnetInfo{
+		ProviderId:        "v6",
+		ProviderNetworkId: "net",
+		CIDR:              "2001:db8::/64",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	lone, err := s.State.AddSubnet(network.SubnetInfo{
+		ProviderId: "lone",
+		CIDR:       "2001:db8:1::/64",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	peers, err := v4.DualStackSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(peers, gc.HasLen, 1)
+	c.Check(peers[0].CIDR(), gc.Equals, v6.CIDR())
+
+	peers, err = v6.DualStackSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	cidrs := make([]string, len(peers))
+	for i, peer := range peers {
+		c.Check(peer.AddressType(), gc.Equals, network.IPv4Address)
+		cidrs[i] = peer.CIDR()
+	}
+	c.Check(cidrs, jc.SameContents, []string{"10.0.0.0/24", "10.0.1.0/24"})
+
+	peers, err = lone.DualStackSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(peers, gc.HasLen, 0)
+}
+
 func (s *SubnetSuite) TestAddSubnetFailsWithEmptyCIDR(c *gc.C) {
 	subnetInfo := network.SubnetInfo{}
 	s.assertAddSubnetForInfoFailsWithSuffix(c, subnetInfo, "missing CIDR")
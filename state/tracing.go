@@ -0,0 +1,86 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"context"
+	"strconv"
+
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/core/trace"
+)
+
+// WithTraceContext returns a State whose transactions are recorded as
+// spans in the trace held by the given context, so that the database
+// work done for an API request can be attributed to it. If the context
+// holds no span, st is returned unchanged.
+//
+// The returned State shares the session and workers of st, and is only
+// valid while st is; it must not be closed.
+func (st *State) WithTraceContext(ctx context.Context) *State {
+	if _, ok := trace.SpanContextFromContext(ctx); !ok {
+		return st
+	}
+	db, ok := st.database.(*database)
+	if !ok {
+		return st
+	}
+	tracedDB := *db
+	tracedDB.traceCtx = ctx
+	tracedSt := *st
+	tracedSt.database = &tracedDB
+	return &tracedSt
+}
+
+// tracingRunner is a jujutxn.Runner that records each transaction
+// it runs as a span in the trace held by ctx.
+type tracingRunner struct {
+	jujutxn.Runner
+	ctx       context.Context
+	dbName    string
+	modelUUID string
+}
+
+// RunTransaction is part of the jujutxn.Runner interface.
+func (r *tracingRunner) RunTransaction(tx *jujutxn.Transaction) error {
+	span := r.startSpan()
+	defer span.End()
+	span.SetAttributes(trace.StringAttr("db.txn.ops", strconv.Itoa(len(tx.Ops))))
+	err := r.Runner.RunTransaction(tx)
+	span.RecordError(err)
+	return err
+}
+
+// Run is part of the jujutxn.Runner interface.
+func (r *tracingRunner) Run(transactions jujutxn.TransactionSource) error {
+	span := r.startSpan()
+	defer span.End()
+	var attempts, numOps int
+	err := r.Runner.Run(func(attempt int) ([]txn.Op, error) {
+		attempts = attempt + 1
+		ops, err := transactions(attempt)
+		numOps = len(ops)
+		return ops, err
+	})
+	span.SetAttributes(
+		trace.StringAttr("db.txn.ops", strconv.Itoa(numOps)),
+		trace.StringAttr("db.txn.attempts", strconv.Itoa(attempts)),
+	)
+	span.RecordError(err)
+	return err
+}
+
+func (r *tracingRunner) startSpan() trace.Span {
+	attrs := []trace.Attribute{
+		trace.StringAttr("db.system", "mongodb"),
+		trace.StringAttr("db.name", r.dbName),
+	}
+	if r.modelUUID != "" {
+		attrs = append(attrs, trace.StringAttr("juju.model-uuid", r.modelUUID))
+	}
+	_, span := trace.StartSpan(r.ctx, "mongo.txn", trace.SpanKindInternal, attrs...)
+	return span
+}
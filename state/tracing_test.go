@@ -0,0 +1,54 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/trace"
+)
+
+type TracingSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&TracingSuite{})
+
+func (s *TracingSuite) TestWithTraceContextRecordsTransactions(c *gc.C) {
+	exporter := &trace.MemoryExporter{}
+	tracer := trace.NewTracer(exporter, testclock.NewClock(time.Time{}))
+	ctx, parent := tracer.Start(context.Background(), "request", trace.SpanKindServer)
+
+	st := s.State.WithTraceContext(ctx)
+	err := st.UpdateControllerConfig(map[string]interface{}{
+		controller.AuditingEnabled: true,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	parent.End()
+
+	spans := exporter.Spans()
+	c.Assert(spans, gc.HasLen, 2)
+	txnSpan := spans[0]
+	c.Check(txnSpan.Name, gc.Equals, "mongo.txn")
+	c.Check(txnSpan.SpanContext.TraceID, gc.Equals, parent.SpanContext().TraceID)
+	c.Check(txnSpan.ParentSpanID, gc.Equals, parent.SpanContext().SpanID)
+	c.Check(txnSpan.Error, gc.Equals, "")
+
+	// The original State is not traced.
+	err = s.State.UpdateControllerConfig(map[string]interface{}{
+		controller.AuditingEnabled: false,
+	}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(exporter.Spans(), gc.HasLen, 2)
+}
+
+func (s *TracingSuite) TestWithTraceContextNoSpan(c *gc.C) {
+	c.Assert(s.State.WithTraceContext(context.Background()), gc.Equals, s.State)
+}
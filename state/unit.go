@@ -1412,10 +1412,23 @@ func (u *Unit) SetStatus(unitStatus status.StatusInfo) error {
 // opening the requested range conflicts with another already opened range on
 // the same subnet and and the unit's assigned machine.
 func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int) (err error) {
+	return u.openPorts(subnetID, "", protocol, fromPort, toPort)
+}
+
+// OpenPortsForEndpoint opens the given port range and protocol for the
+// named endpoint of the unit, which must be defined by the unit's charm.
+// The empty endpoint name refers to all of the unit's endpoints. The
+// same range may be opened for several endpoints.
+func (u *Unit) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.openPorts("", endpoint, protocol, fromPort, toPort)
+}
+
+func (u *Unit) openPorts(subnetID, endpoint, protocol string, fromPort, toPort int) (err error) {
 	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
 	}
+	ports.Endpoint = endpoint
 	defer errors.DeferredAnnotatef(&err, "cannot open ports %v for unit %q on subnet %q", ports, u, subnetID)
 
 	machineID, err := u.AssignedMachineId()
@@ -1426,6 +1439,9 @@ func (u *Unit) OpenPortsOnSubnet(subnetID, protocol string, fromPort, toPort int
 	if err := u.checkSubnetAliveWhenSet(subnetID); err != nil {
 		return errors.Trace(err)
 	}
+	if err := u.checkEndpointWhenSet(endpoint); err != nil {
+		return errors.Trace(err)
+	}
 
 	machinePorts, err := getOrCreatePorts(u.st, machineID, subnetID)
 	if err != nil {
@@ -1453,14 +1469,43 @@ func (u *Unit) checkSubnetAliveWhenSet(subnetID string) error {
 	return nil
 }
 
+// checkEndpointWhenSet returns an error satisfying errors.IsNotFound if
+// the endpoint is set but not defined by the unit's charm.
+func (u *Unit) checkEndpointWhenSet(endpoint string) error {
+	if endpoint == "" {
+		return nil
+	}
+	app, err := u.Application()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := app.Endpoint(endpoint); err != nil {
+		return errors.NotFoundf("endpoint %q", endpoint)
+	}
+	return nil
+}
+
 // ClosePortsOnSubnet closes the given port range and protocol for the unit on
 // the given subnet, which can be empty. When non-empty, subnetID must refer to
-// an existing, alive subnet, otherwise an error is returned.
+// an existing, alive subnet, otherwise an error is returned. The range is
+// closed for all the endpoints it was opened for.
 func (u *Unit) ClosePortsOnSubnet(subnetID, protocol string, fromPort, toPort int) (err error) {
+	return u.closePorts(subnetID, "", protocol, fromPort, toPort)
+}
+
+// ClosePortsForEndpoint closes the given port range and protocol for the
+// named endpoint of the unit. Closing a range for one endpoint leaves it
+// open for any other endpoint it was opened for.
+func (u *Unit) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return u.closePorts("", endpoint, protocol, fromPort, toPort)
+}
+
+func (u *Unit) closePorts(subnetID, endpoint, protocol string, fromPort, toPort int) (err error) {
 	ports, err := NewPortRange(u.Name(), fromPort, toPort, protocol)
 	if err != nil {
 		return errors.Annotatef(err, "invalid port range %v-%v/%v", fromPort, toPort, protocol)
 	}
+	ports.Endpoint = endpoint
 	defer errors.DeferredAnnotatef(&err, "cannot close ports %v for unit %q on subnet %q", ports, u, subnetID)
 
 	machineID, err := u.AssignedMachineId()
@@ -1551,12 +1596,19 @@ func (u *Unit) OpenedPortsOnSubnet(subnetID string) ([]corenetwork.PortRange, er
 		return nil, errors.Annotatef(err, "failed getting ports for unit %q, subnet %q", u, subnetID)
 	}
 	ports := machinePorts.PortsForUnit(u.Name())
+	seen := make(map[corenetwork.PortRange]bool)
 	for _, port := range ports {
-		result = append(result, corenetwork.PortRange{
+		portRange := corenetwork.PortRange{
 			Protocol: port.Protocol,
 			FromPort: port.FromPort,
 			ToPort:   port.ToPort,
-		})
+		}
+		// A range opened for several endpoints is only listed once.
+		if seen[portRange] {
+			continue
+		}
+		seen[portRange] = true
+		result = append(result, portRange)
 	}
 	corenetwork.SortPortRanges(result)
 	return result, nil
@@ -2491,9 +2543,10 @@ func storageParamsForStorageInstance(
 			}
 		} else if errors.IsNotFound(err) {
 			filesystemParams := FilesystemParams{
-				storage: storage.StorageTag(),
-				Pool:    storage.doc.Constraints.Pool,
-				Size:    storage.doc.Constraints.Size,
+				storage:  storage.StorageTag(),
+				Pool:     storage.doc.Constraints.Pool,
+				Size:     storage.doc.Constraints.Size,
+				Snapshot: storage.doc.Constraints.Snapshot,
 			}
 			filesystems = append(filesystems, HostFilesystemParams{
 				filesystemParams, filesystemAttachmentParams,
@@ -2536,9 +2589,10 @@ func storageParamsForStorageInstance(
 			volumeAttachments[volume.VolumeTag()] = volumeAttachmentParams
 		} else if errors.IsNotFound(err) {
 			volumeParams := VolumeParams{
-				storage: storage.StorageTag(),
-				Pool:    storage.doc.Constraints.Pool,
-				Size:    storage.doc.Constraints.Size,
+				storage:  storage.StorageTag(),
+				Pool:     storage.doc.Constraints.Pool,
+				Size:     storage.doc.Constraints.Size,
+				Snapshot: storage.doc.Constraints.Snapshot,
 			}
 			volumes = append(volumes, HostVolumeParams{
 				volumeParams, volumeAttachmentParams,
@@ -2906,6 +2960,9 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, e
 	if len(name) == 0 {
 		return nil, errors.New("no action name given")
 	}
+	if name == actions.JujuCheckNetworkActionName {
+		return nil, errors.Errorf("cannot add action %q to a unit; only machines can probe the network", name)
+	}
 
 	// If the action is predefined inside juju, get spec from map
 	spec, ok := actions.PredefinedActionsSpec[name]
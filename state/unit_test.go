@@ -1553,6 +1553,51 @@ func (s *UnitSuite) TestOpenClosePortWhenDying(c *gc.C) {
 	})
 }
 
+func (s *UnitSuite) TestOpenPortsForEndpoint(c *gc.C) {
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.unit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPortsForEndpoint("monitoring-port", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.OpenPortsForEndpoint("bogus", "tcp", 80, 80)
+	c.Assert(errors.Cause(err), jc.Satisfies, errors.IsNotFound)
+
+	ports, err := machine.AllPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports, gc.HasLen, 1)
+	c.Assert(ports[0].PortsForUnit(s.unit.Name()), jc.DeepEquals, []state.PortRange{
+		{UnitName: s.unit.Name(), FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "url"},
+		{UnitName: s.unit.Name(), FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "monitoring-port"},
+	})
+	// The range is only listed once.
+	opened, err := s.unit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(opened, jc.DeepEquals, []corenetwork.PortRange{
+		{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+	})
+
+	err = s.unit.ClosePortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	ports, err = machine.AllPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ports[0].PortsForUnit(s.unit.Name()), jc.DeepEquals, []state.PortRange{
+		{UnitName: s.unit.Name(), FromPort: 80, ToPort: 80, Protocol: "tcp", Endpoint: "monitoring-port"},
+	})
+
+	// Closing the range without an endpoint closes it for all of them.
+	err = s.unit.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.ClosePorts("tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	opened, err = s.unit.OpenedPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(opened, gc.HasLen, 0)
+}
+
 func (s *UnitSuite) TestRemoveLastUnitOnMachineRemovesAllPorts(c *gc.C) {
 	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
 	c.Assert(err, jc.ErrorIsNil)
@@ -1570,7 +1615,7 @@ func (s *UnitSuite) TestRemoveLastUnitOnMachineRemovesAllPorts(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ports, gc.HasLen, 1)
 	c.Assert(ports[0].PortsForUnit(s.unit.Name()), jc.DeepEquals, []state.PortRange{
-		{s.unit.Name(), 100, 200, "tcp"},
+		{UnitName: s.unit.Name(), FromPort: 100, ToPort: 200, Protocol: "tcp"},
 	})
 
 	// Now remove the unit and check again.
@@ -1614,10 +1659,10 @@ func (s *UnitSuite) TestRemoveUnitRemovesItsPortsOnly(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(ports, gc.HasLen, 1)
 	c.Assert(ports[0].PortsForUnit(s.unit.Name()), jc.DeepEquals, []state.PortRange{
-		{s.unit.Name(), 100, 200, "tcp"},
+		{UnitName: s.unit.Name(), FromPort: 100, ToPort: 200, Protocol: "tcp"},
 	})
 	c.Assert(ports[0].PortsForUnit(otherUnit.Name()), jc.DeepEquals, []state.PortRange{
-		{otherUnit.Name(), 300, 400, "udp"},
+		{UnitName: otherUnit.Name(), FromPort: 300, ToPort: 400, Protocol: "udp"},
 	})
 
 	// Now remove the first unit and check again.
@@ -1634,7 +1679,7 @@ func (s *UnitSuite) TestRemoveUnitRemovesItsPortsOnly(c *gc.C) {
 	c.Assert(ports, gc.HasLen, 1)
 	c.Assert(ports[0].PortsForUnit(s.unit.Name()), gc.HasLen, 0)
 	c.Assert(ports[0].PortsForUnit(otherUnit.Name()), jc.DeepEquals, []state.PortRange{
-		{otherUnit.Name(), 300, 400, "udp"},
+		{UnitName: otherUnit.Name(), FromPort: 300, ToPort: 400, Protocol: "udp"},
 	})
 }
 
@@ -2123,6 +2168,10 @@ snapshot:
 			givenPayload:    map[string]interface{}{"command": "allyourbasearebelongtous", "timeout": 5.0},
 			expectedPayload: map[string]interface{}{"command": "allyourbasearebelongtous", "timeout": 5.0},
 		},
+		{
+			actionName: "juju-check-network",
+			errString:  `cannot add action "juju-check-network" to a unit; only machines can probe the network`,
+		},
 		{
 			actionName: "baiku",
 			errString:  `action "baiku" not defined on unit "wordpress-actions/0"`,
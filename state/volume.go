@@ -176,6 +176,14 @@ type VolumeParams struct {
 
 	Pool string `bson:"pool"`
 	Size uint64 `bson:"size"`
+
+	// Snapshot, if non-empty, is the ID of the storage snapshot
+	// from which the volume is to be created.
+	Snapshot string `bson:"snapshot,omitempty"`
+
+	// composed, if true, indicates that the volume is to be one of
+	// the volumes composed to back a filesystem.
+	composed bool
 }
 
 // VolumeInfo describes information about a volume.
@@ -803,7 +811,7 @@ func removeVolumeAttachmentOps(host names.Tag, v *volume) []txn.Op {
 		Id:     volumeAttachmentId(host.Id(), v.doc.Name),
 		Assert: bson.D{{"life", Dying}},
 		Remove: true,
-	}, decrefVolumeOp}
+	}, decrefVolumeOp, removeVolumeCopyOp(volumeAttachmentId(host.Id(), v.doc.Name))}
 	if host.Kind() == names.MachineTagKind {
 		ops = append(ops, txn.Op{
 			C:      machinesC,
@@ -988,6 +996,8 @@ func (sb *storageBackend) removeVolumeOps(tag names.VolumeTag) []txn.Op {
 		},
 		removeModelVolumeRefOp(sb.mb, tag.Id()),
 		removeStatusOp(sb.mb, volumeGlobalKey(tag.Id())),
+		removeStorageResizeOp(volumeResizesC, tag.Id()),
+		removeVolumeEncryptionKeyOp(tag.Id()),
 	}
 }
 
@@ -1025,6 +1035,17 @@ func (sb *storageBackend) addVolumeOps(params VolumeParams, hostId string) ([]tx
 	if err != nil {
 		return nil, names.VolumeTag{}, errors.Annotate(err, "validating volume params")
 	}
+	if !params.composed {
+		_, _, attrs, err := poolStorageProvider(sb, params.Pool)
+		if err != nil {
+			return nil, names.VolumeTag{}, errors.Trace(err)
+		}
+		if compositionType, _, _ := storage.CompositionFromConfig(attrs); compositionType != "" {
+			return nil, names.VolumeTag{}, errors.NotSupportedf(
+				"block storage in pool %q, which composes volumes", params.Pool,
+			)
+		}
+	}
 	name, err := newVolumeName(sb.mb, hostId)
 	if err != nil {
 		return nil, names.VolumeTag{}, errors.Annotate(err, "cannot generate volume name")
@@ -1037,6 +1058,7 @@ func (sb *storageBackend) addVolumeOps(params VolumeParams, hostId string) ([]tx
 		Name:      name,
 		StorageId: params.storage.Id(),
 	}
+	var keyOps []txn.Op
 	if params.volumeInfo != nil {
 		// We're importing an already provisioned volume into the
 		// model. Set provisioned info rather than params, and set
@@ -1047,11 +1069,15 @@ func (sb *storageBackend) addVolumeOps(params VolumeParams, hostId string) ([]tx
 		// Every new volume is created with one attachment.
 		doc.Params = &params
 		doc.AttachmentCount = 1
+		keyOps, err = sb.addVolumeEncryptionKeyOps(params.Pool, name)
+		if err != nil {
+			return nil, names.VolumeTag{}, errors.Trace(err)
+		}
 	}
 	if !detachable {
 		doc.HostId = origHostId
 	}
-	return sb.newVolumeOps(doc, statusDoc), names.NewVolumeTag(name), nil
+	return append(sb.newVolumeOps(doc, statusDoc), keyOps...), names.NewVolumeTag(name), nil
 }
 
 func (sb *storageBackend) newVolumeOps(doc volumeDoc, status statusDoc) []txn.Op {
@@ -0,0 +1,107 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/storage"
+)
+
+// volumeEncryptionKeyLength is the length, in bytes, of the randomly
+// generated keys used to encrypt volumes.
+const volumeEncryptionKeyLength = 64
+
+// volumeEncryptionKeyDoc records the key used by the machine agent to
+// encrypt a volume. The document ID is the ID of the volume. Keys are
+// kept apart from the volume documents, so that they are not exposed
+// through the APIs that report volume details.
+type volumeEncryptionKeyDoc struct {
+	DocID     string `bson:"_id"`
+	ModelUUID string `bson:"model-uuid"`
+	Key       string `bson:"key"`
+}
+
+// addVolumeEncryptionKeyOps returns txn.Ops to record a new encryption
+// key for the volume with the specified ID, if volumes created in the
+// specified pool are to be encrypted by the machine agent.
+func (sb *storageBackend) addVolumeEncryptionKeyOps(poolName, volumeId string) ([]txn.Op, error) {
+	encrypted, err := sb.agentEncryptsPool(poolName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !encrypted {
+		return nil, nil
+	}
+	key := make([]byte, volumeEncryptionKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, errors.Annotate(err, "generating volume encryption key")
+	}
+	return []txn.Op{{
+		C:      volumeEncryptionKeysC,
+		Id:     volumeId,
+		Assert: txn.DocMissing,
+		Insert: &volumeEncryptionKeyDoc{
+			Key: base64.StdEncoding.EncodeToString(key),
+		},
+	}}, nil
+}
+
+// agentEncryptsPool reports whether volumes created in the specified
+// pool are encrypted by the machine agent, rather than by the storage
+// provider.
+func (sb *storageBackend) agentEncryptsPool(poolName string) (bool, error) {
+	_, provider, attrs, err := poolStorageProvider(sb, poolName)
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	encrypted, err := storage.IsEncrypted(attrs)
+	if err != nil {
+		return false, errors.Annotatef(err, "invalid storage pool %q", poolName)
+	}
+	if !encrypted {
+		return false, nil
+	}
+	native, ok := provider.(storage.NativeEncryptionProvider)
+	return !ok || !native.EncryptsVolumes(), nil
+}
+
+// VolumeEncryptionKey returns the key with which the machine agent is
+// to encrypt the specified volume, which must be attached to the
+// specified host. If the volume is not to be encrypted by the machine
+// agent, an error satisfying errors.IsNotFound is returned.
+func (sb *storageBackend) VolumeEncryptionKey(host names.Tag, volume names.VolumeTag) (string, error) {
+	att, err := sb.VolumeAttachment(host, volume)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if att.Life() == Dead {
+		return "", errors.NotFoundf("volume %q on %q", volume.Id(), names.ReadableString(host))
+	}
+
+	coll, closer := sb.mb.db().GetCollection(volumeEncryptionKeysC)
+	defer closer()
+	var doc volumeEncryptionKeyDoc
+	err = coll.FindId(volume.Id()).One(&doc)
+	if err == mgo.ErrNotFound {
+		return "", errors.NotFoundf("encryption key for volume %q", volume.Id())
+	} else if err != nil {
+		return "", errors.Annotatef(err, "getting encryption key for volume %q", volume.Id())
+	}
+	return doc.Key, nil
+}
+
+func removeVolumeEncryptionKeyOp(volumeId string) txn.Op {
+	return txn.Op{
+		C:      volumeEncryptionKeysC,
+		Id:     volumeId,
+		Remove: true,
+	}
+}
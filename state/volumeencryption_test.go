@@ -0,0 +1,63 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"encoding/base64"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/state"
+)
+
+type VolumeEncryptionSuite struct {
+	StorageStateSuiteBase
+}
+
+var _ = gc.Suite(&VolumeEncryptionSuite{})
+
+func (s *VolumeEncryptionSuite) SetUpTest(c *gc.C) {
+	s.StorageStateSuiteBase.SetUpTest(c)
+	_, err := s.pm.Create("encrypted-block", "modelscoped-block", map[string]interface{}{
+		"encrypted": "true",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// setupAssignedFilesystem adds a unit with filesystem storage in the
+// specified pool, assigns it to a new machine, and returns the tag of
+// the filesystem's backing volume.
+func (s *VolumeEncryptionSuite) setupAssignedFilesystem(c *gc.C, pool string) names.VolumeTag {
+	_, u, storageTag := s.setupSingleStorage(c, "filesystem", pool)
+	err := s.State.AssignUnit(u, state.AssignCleanEmpty)
+	c.Assert(err, jc.ErrorIsNil)
+	volumeTag, err := s.storageInstanceFilesystem(c, storageTag).Volume()
+	c.Assert(err, jc.ErrorIsNil)
+	return volumeTag
+}
+
+func (s *VolumeEncryptionSuite) TestVolumeEncryptionKey(c *gc.C) {
+	volumeTag := s.setupAssignedFilesystem(c, "encrypted-block")
+
+	key, err := s.storageBackend.VolumeEncryptionKey(names.NewMachineTag("0"), volumeTag)
+	c.Assert(err, jc.ErrorIsNil)
+	decoded, err := base64.StdEncoding.DecodeString(key)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decoded, gc.HasLen, 64)
+
+	// The key is only released for machines to which
+	// the volume is attached.
+	_, err = s.storageBackend.VolumeEncryptionKey(names.NewMachineTag("1"), volumeTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *VolumeEncryptionSuite) TestVolumeEncryptionKeyNotEncrypted(c *gc.C) {
+	volumeTag := s.setupAssignedFilesystem(c, "persistent-block")
+	_, err := s.storageBackend.VolumeEncryptionKey(names.NewMachineTag("0"), volumeTag)
+	c.Assert(err, gc.ErrorMatches, `encryption key for volume "0" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
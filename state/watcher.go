@@ -237,6 +237,24 @@ func (sb *storageBackend) WatchModelFilesystems() StringsWatcher {
 	return sb.watchModelHostStorage(filesystemsC)
 }
 
+// WatchModelStorageSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all model-scoped storage snapshots.
+func (sb *storageBackend) WatchModelStorageSnapshots() StringsWatcher {
+	return sb.watchModelHostStorage(storageSnapshotsC)
+}
+
+// WatchModelVolumeResizes returns a StringsWatcher that notifies of
+// requests to resize model-scoped volumes.
+func (sb *storageBackend) WatchModelVolumeResizes() StringsWatcher {
+	return sb.watchModelHostStorage(volumeResizesC)
+}
+
+// WatchModelFilesystemResizes returns a StringsWatcher that notifies of
+// requests to resize model-scoped filesystems.
+func (sb *storageBackend) WatchModelFilesystemResizes() StringsWatcher {
+	return sb.watchModelHostStorage(filesystemResizesC)
+}
+
 var machineOrUnitSnippet = "(" + names.NumberSnippet + "|" + names.UnitSnippet + ")"
 
 func (sb *storageBackend) watchModelHostStorage(collection string) StringsWatcher {
@@ -265,6 +283,25 @@ func (sb *storageBackend) WatchMachineFilesystems(m names.MachineTag) StringsWat
 	return sb.watchHostStorage(m, filesystemsC)
 }
 
+// WatchMachineStorageSnapshots returns a StringsWatcher that notifies of
+// changes to the lifecycles of all storage snapshots scoped to the specified
+// machine.
+func (sb *storageBackend) WatchMachineStorageSnapshots(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorage(m, storageSnapshotsC)
+}
+
+// WatchMachineVolumeResizes returns a StringsWatcher that notifies of
+// requests to resize volumes scoped to the specified machine.
+func (sb *storageBackend) WatchMachineVolumeResizes(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorage(m, volumeResizesC)
+}
+
+// WatchMachineFilesystemResizes returns a StringsWatcher that notifies of
+// requests to resize filesystems scoped to the specified machine.
+func (sb *storageBackend) WatchMachineFilesystemResizes(m names.MachineTag) StringsWatcher {
+	return sb.watchHostStorage(m, filesystemResizesC)
+}
+
 // WatchUnitFilesystems returns a StringsWatcher that notifies of changes
 // to the lifecycles of all filesystems scoped to units of the specified application.
 func (sb *storageBackend) WatchUnitFilesystems(app names.ApplicationTag) StringsWatcher {
@@ -1839,6 +1876,16 @@ func (sb *storageBackend) WatchFilesystemAttachment(host names.Tag, f names.File
 	return newEntityWatcher(sb.mb, filesystemAttachmentsC, sb.mb.docID(id))
 }
 
+// WatchVolume returns a watcher for observing changes to a volume.
+func (sb *storageBackend) WatchVolume(v names.VolumeTag) NotifyWatcher {
+	return newEntityWatcher(sb.mb, volumesC, sb.mb.docID(v.Id()))
+}
+
+// WatchFilesystem returns a watcher for observing changes to a filesystem.
+func (sb *storageBackend) WatchFilesystem(f names.FilesystemTag) NotifyWatcher {
+	return newEntityWatcher(sb.mb, filesystemsC, sb.mb.docID(f.Id()))
+}
+
 // WatchCharmConfig returns a watcher for observing changes to the
 // application's charm configuration settings. The returned watcher will be
 // valid only while the application's charm URL is not changed.
@@ -0,0 +1,91 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"gopkg.in/juju/names.v3"
+)
+
+// CompositionType identifies the way in which multiple volumes are
+// combined by the machine agent to present a single block device, on
+// which a managed filesystem is created.
+type CompositionType string
+
+const (
+	// CompositionRAID0 stripes data across the volumes, providing
+	// the combined capacity and throughput of all of them.
+	CompositionRAID0 CompositionType = "raid0"
+
+	// CompositionRAID1 mirrors data across the volumes, providing
+	// the capacity of one volume and tolerating the loss of all
+	// but one of them.
+	CompositionRAID1 CompositionType = "raid1"
+
+	// CompositionLVM combines the volumes into an LVM volume group,
+	// with a single logical volume spanning all of them.
+	CompositionLVM CompositionType = "lvm"
+)
+
+// FilesystemComposition describes how the volumes backing a filesystem
+// are combined to present a single block device.
+type FilesystemComposition struct {
+	// Type is the way in which the volumes are combined.
+	Type CompositionType
+
+	// Volumes holds the tags of the volumes that are combined. The
+	// first volume is the filesystem's backing volume.
+	Volumes []names.VolumeTag
+}
+
+// CompositionFromConfig returns the composition type and the number of
+// volumes to compose, as requested by the specified storage pool
+// attributes. If the attributes do not specify a composition, the
+// empty composition type and a volume count of 1 are returned.
+func CompositionFromConfig(attrs map[string]interface{}) (CompositionType, int, error) {
+	v, ok := attrs[ConfigComposition]
+	if !ok {
+		if _, ok := attrs[ConfigCompositionVolumes]; ok {
+			return "", 0, errors.Errorf(
+				"%q specified without %q", ConfigCompositionVolumes, ConfigComposition,
+			)
+		}
+		return "", 1, nil
+	}
+	compositionType := CompositionType(fmt.Sprint(v))
+	switch compositionType {
+	case CompositionRAID0, CompositionRAID1, CompositionLVM:
+	default:
+		return "", 0, errors.NotValidf("%s %q", ConfigComposition, compositionType)
+	}
+	v, ok = attrs[ConfigCompositionVolumes]
+	if !ok {
+		return "", 0, errors.Errorf(
+			"%q specified without %q", ConfigComposition, ConfigCompositionVolumes,
+		)
+	}
+	n, err := schema.ForceInt().Coerce(v, []string{ConfigCompositionVolumes})
+	if err != nil {
+		return "", 0, errors.Trace(err)
+	}
+	count := n.(int)
+	if count < 2 {
+		return "", 0, errors.Errorf("%s must be at least 2, got %d", ConfigCompositionVolumes, count)
+	}
+	return compositionType, count, nil
+}
+
+// VolumeSize returns the size, in MiB, of each of the specified number
+// of volumes that must be composed to provide a device of at least the
+// specified size.
+func (t CompositionType) VolumeSize(size uint64, count int) uint64 {
+	if t == CompositionRAID1 {
+		return size
+	}
+	n := uint64(count)
+	return (size + n - 1) / n
+}
@@ -0,0 +1,60 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storage_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/storage"
+)
+
+type CompositionSuite struct{}
+
+var _ = gc.Suite(&CompositionSuite{})
+
+func (s *CompositionSuite) TestCompositionFromConfigNone(c *gc.C) {
+	compositionType, count, err := storage.CompositionFromConfig(map[string]interface{}{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(compositionType, gc.Equals, storage.CompositionType(""))
+	c.Assert(count, gc.Equals, 1)
+}
+
+func (s *CompositionSuite) TestCompositionFromConfig(c *gc.C) {
+	compositionType, count, err := storage.CompositionFromConfig(map[string]interface{}{
+		"composition":         "raid0",
+		"composition-volumes": "4",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(compositionType, gc.Equals, storage.CompositionRAID0)
+	c.Assert(count, gc.Equals, 4)
+}
+
+func (s *CompositionSuite) TestCompositionFromConfigInvalid(c *gc.C) {
+	for _, test := range []struct {
+		attrs map[string]interface{}
+		err   string
+	}{{
+		attrs: map[string]interface{}{"composition": "raid5", "composition-volumes": 3},
+		err:   `composition "raid5" not valid`,
+	}, {
+		attrs: map[string]interface{}{"composition": "lvm"},
+		err:   `"composition" specified without "composition-volumes"`,
+	}, {
+		attrs: map[string]interface{}{"composition-volumes": 2},
+		err:   `"composition-volumes" specified without "composition"`,
+	}, {
+		attrs: map[string]interface{}{"composition": "raid1", "composition-volumes": 1},
+		err:   `composition-volumes must be at least 2, got 1`,
+	}} {
+		_, _, err := storage.CompositionFromConfig(test.attrs)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *CompositionSuite) TestVolumeSize(c *gc.C) {
+	c.Assert(storage.CompositionRAID0.VolumeSize(1000, 3), gc.Equals, uint64(334))
+	c.Assert(storage.CompositionLVM.VolumeSize(1000, 4), gc.Equals, uint64(250))
+	c.Assert(storage.CompositionRAID1.VolumeSize(1000, 2), gc.Equals, uint64(1000))
+}
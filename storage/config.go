@@ -19,6 +19,27 @@ const (
 	// should not be relied upon until a storage source is
 	// constructed.
 	ConfigStorageDir = "storage-dir"
+
+	// ConfigEncrypted is the name of the common pool attribute which,
+	// when true, causes filesystems created on volumes in the pool to
+	// be encrypted by the machine agent using LUKS (dm-crypt). The
+	// encryption keys are held by the controller, and released only
+	// to the agent of the machine to which the volume is attached.
+	//
+	// Storage providers that implement NativeEncryptionProvider
+	// encrypt volumes themselves, and no LUKS encryption is applied.
+	ConfigEncrypted = "encrypted"
+
+	// ConfigComposition is the name of the common pool attribute
+	// which, when set, causes filesystems in the pool to be created
+	// on a device composed of multiple volumes, assembled by the
+	// machine agent. See CompositionType for the accepted values.
+	ConfigComposition = "composition"
+
+	// ConfigCompositionVolumes is the name of the common pool
+	// attribute that specifies the number of volumes composed to
+	// back each filesystem in a pool with a composition.
+	ConfigCompositionVolumes = "composition-volumes"
 )
 
 // Config defines the configuration for a storage source.
@@ -28,11 +49,23 @@ type Config struct {
 	attrs    map[string]interface{}
 }
 
-var fields = schema.Fields{}
+var fields = schema.Fields{
+	ConfigEncrypted: schema.Bool(),
+	ConfigComposition: schema.OneOf(
+		schema.Const(string(CompositionRAID0)),
+		schema.Const(string(CompositionRAID1)),
+		schema.Const(string(CompositionLVM)),
+	),
+	ConfigCompositionVolumes: schema.ForceInt(),
+}
 
 var configChecker = schema.FieldMap(
 	fields,
-	schema.Defaults{},
+	schema.Defaults{
+		ConfigEncrypted:          schema.Omit,
+		ConfigComposition:        schema.Omit,
+		ConfigCompositionVolumes: schema.Omit,
+	},
 )
 
 // NewConfig creates a new Config for instantiating a storage source.
@@ -41,6 +74,9 @@ func NewConfig(name string, provider ProviderType, attrs map[string]interface{})
 	if err != nil {
 		return nil, errors.Annotate(err, "validating common storage config")
 	}
+	if _, _, err := CompositionFromConfig(attrs); err != nil {
+		return nil, errors.Annotate(err, "validating common storage config")
+	}
 	return &Config{
 		name:     name,
 		provider: provider,
@@ -76,3 +112,18 @@ func (c *Config) ValueString(name string) (string, bool) {
 	v, ok := c.attrs[name].(string)
 	return v, ok
 }
+
+// IsEncrypted reports whether or not the specified storage pool
+// attributes request that volumes in the pool be encrypted by
+// the machine agent.
+func IsEncrypted(attrs map[string]interface{}) (bool, error) {
+	v, ok := attrs[ConfigEncrypted]
+	if !ok {
+		return false, nil
+	}
+	encrypted, err := schema.Bool().Coerce(v, []string{ConfigEncrypted})
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return encrypted.(bool), nil
+}
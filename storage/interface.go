@@ -87,6 +87,15 @@ type Provider interface {
 	ValidateConfig(*Config) error
 }
 
+// NativeEncryptionProvider may be implemented by a Provider whose volumes
+// are encrypted by the cloud when the "encrypted" pool attribute is set.
+// Juju does not apply its own encryption to such volumes.
+type NativeEncryptionProvider interface {
+	// EncryptsVolumes reports whether or not the provider encrypts
+	// volumes itself when the "encrypted" pool attribute is set.
+	EncryptsVolumes() bool
+}
+
 // VolumeSource provides an interface for creating, destroying, describing,
 // attaching and detaching volumes in the environment. A VolumeSource is
 // configured in a particular way, and corresponds to a storage "pool".
@@ -216,6 +225,67 @@ type VolumeImporter interface {
 	) (VolumeInfo, error)
 }
 
+// VolumeSnapshotter provides an interface for taking point-in-time
+// snapshots of volumes. Volume sources that implement VolumeSnapshotter
+// must also support creating volumes from snapshots, by honouring
+// VolumeParams.SnapshotId in CreateVolumes.
+type VolumeSnapshotter interface {
+	// CreateVolumeSnapshots takes snapshots of the volumes with the
+	// specified provider volume IDs, returning a result for each.
+	CreateVolumeSnapshots(
+		ctx context.ProviderCallContext,
+		params []VolumeSnapshotParams,
+	) ([]CreateSnapshotsResult, error)
+
+	// DeleteVolumeSnapshots deletes the snapshots with the specified
+	// provider-supplied snapshot IDs. Snapshots that no longer exist
+	// are not reported as errors.
+	DeleteVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error)
+}
+
+// FilesystemSnapshotter provides an interface for taking point-in-time
+// snapshots of filesystems. Filesystem sources that implement
+// FilesystemSnapshotter must also support creating filesystems from
+// snapshots, by honouring FilesystemParams.SnapshotId in
+// CreateFilesystems.
+type FilesystemSnapshotter interface {
+	// CreateFilesystemSnapshots takes snapshots of the filesystems
+	// with the specified provider filesystem IDs, returning a result
+	// for each.
+	CreateFilesystemSnapshots(
+		ctx context.ProviderCallContext,
+		params []FilesystemSnapshotParams,
+	) ([]CreateSnapshotsResult, error)
+
+	// DeleteFilesystemSnapshots deletes the snapshots with the
+	// specified provider-supplied snapshot IDs. Snapshots that no
+	// longer exist are not reported as errors.
+	DeleteFilesystemSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error)
+}
+
+// VolumeResizer provides an interface for growing provisioned volumes.
+// Volumes are only ever grown; Juju does not support shrinking storage.
+type VolumeResizer interface {
+	// ResizeVolumes grows the volumes with the specified provider
+	// volume IDs, returning a result for each.
+	ResizeVolumes(
+		ctx context.ProviderCallContext,
+		params []VolumeResizeParams,
+	) ([]ResizeResult, error)
+}
+
+// FilesystemResizer provides an interface for growing provisioned
+// filesystems. For a filesystem backed by a volume, the volume will
+// already have been grown by the time ResizeFilesystems is called.
+type FilesystemResizer interface {
+	// ResizeFilesystems grows the filesystems with the specified
+	// provider filesystem IDs, returning a result for each.
+	ResizeFilesystems(
+		ctx context.ProviderCallContext,
+		params []FilesystemResizeParams,
+	) ([]ResizeResult, error)
+}
+
 // VolumeParams is a fully specified set of parameters for volume creation,
 // derived from one or more of user-specified storage constraints, a
 // storage pool definition, and charm storage metadata.
@@ -239,6 +309,11 @@ type VolumeParams struct {
 	// storage provider supports tags.
 	ResourceTags map[string]string
 
+	// SnapshotId is the provider-supplied ID of the snapshot that the
+	// volume should be created from, if any. SnapshotId will only be
+	// set for volume sources that implement VolumeSnapshotter.
+	SnapshotId string
+
 	// Attachment identifies the machine that the volume should be attached
 	// to initially, or nil if the volume should not be attached to any
 	// machine. Some providers, such as MAAS, do not support dynamic
@@ -315,6 +390,13 @@ type FilesystemParams struct {
 	// storage provider supports tags.
 	ResourceTags map[string]string
 
+	// SnapshotId is the provider-supplied ID of the snapshot that the
+	// filesystem should be created from, if any. SnapshotId will only
+	// be set for filesystem sources that implement FilesystemSnapshotter,
+	// or for volume-backed filesystems whose volume is being created from
+	// the same snapshot.
+	SnapshotId string
+
 	// Attachment identifies the machine that the filesystem should be attached
 	// to initially, or nil if the filesystem should not be attached to any
 	// machine.
@@ -339,6 +421,95 @@ type FilesystemAttachmentParams struct {
 	Path string
 }
 
+// VolumeSnapshotParams is a set of parameters for taking a snapshot
+// of a volume.
+type VolumeSnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	// Volume is the tag of the volume to take a snapshot of.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume.
+	VolumeId string
+
+	// ResourceTags is a set of tags to set on the created snapshot, if
+	// the storage provider supports tags.
+	ResourceTags map[string]string
+}
+
+// FilesystemSnapshotParams is a set of parameters for taking a snapshot
+// of a filesystem.
+type FilesystemSnapshotParams struct {
+	// Id is the unique ID assigned by Juju to the snapshot.
+	Id string
+
+	// Filesystem is the tag of the filesystem to take a snapshot of.
+	Filesystem names.FilesystemTag
+
+	// FilesystemId is the unique provider-supplied ID for the filesystem.
+	FilesystemId string
+
+	// ResourceTags is a set of tags to set on the created snapshot, if
+	// the storage provider supports tags.
+	ResourceTags map[string]string
+}
+
+// VolumeResizeParams is a set of parameters for growing a volume.
+type VolumeResizeParams struct {
+	// Volume is the tag of the volume to grow.
+	Volume names.VolumeTag
+
+	// VolumeId is the unique provider-supplied ID for the volume.
+	VolumeId string
+
+	// Size is the size that the volume should be grown to, in MiB.
+	Size uint64
+}
+
+// FilesystemResizeParams is a set of parameters for growing a
+// filesystem.
+type FilesystemResizeParams struct {
+	// Filesystem is the tag of the filesystem to grow.
+	Filesystem names.FilesystemTag
+
+	// Volume is the tag of the volume that backs the filesystem,
+	// if any.
+	Volume names.VolumeTag
+
+	// FilesystemId is the unique provider-supplied ID for the
+	// filesystem.
+	FilesystemId string
+
+	// Size is the size that the filesystem should be grown to, in MiB.
+	Size uint64
+
+	// Path is the path at which the filesystem is mounted on the
+	// machine, if it is attached to the machine running the resize.
+	Path string
+}
+
+// ResizeResult contains the result of a VolumeResizer.ResizeVolumes
+// or FilesystemResizer.ResizeFilesystems call for one volume or
+// filesystem. Size should only be used if Error is nil.
+type ResizeResult struct {
+	// Size is the size of the volume or filesystem after resizing,
+	// in MiB. This may be larger than the requested size, if the
+	// storage provider rounds up sizes.
+	Size  uint64
+	Error error
+}
+
+// CreateSnapshotsResult contains the result of a
+// VolumeSnapshotter.CreateVolumeSnapshots or
+// FilesystemSnapshotter.CreateFilesystemSnapshots call for one
+// snapshot. SnapshotId should only be used if Error is nil.
+type CreateSnapshotsResult struct {
+	// SnapshotId is the unique provider-supplied ID for the snapshot.
+	SnapshotId string
+	Error      error
+}
+
 // CreateVolumesResult contains the result of a VolumeSource.CreateVolumes call
 // for one volume. Volume and VolumeAttachment should only be used if Error is
 // nil.
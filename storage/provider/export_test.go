@@ -40,6 +40,8 @@ func NewMockManagedFilesystemSource(
 	run func(string, ...string) (string, error),
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice,
 	filesystems map[names.FilesystemTag]storage.Filesystem,
+	volumeEncryptionKeys map[names.VolumeTag]string,
+	filesystemCompositions map[names.FilesystemTag]storage.FilesystemComposition,
 ) (storage.FilesystemSource, *MockDirFuncs) {
 	dirFuncs := &MockDirFuncs{
 		osDirFuncs{run},
@@ -47,8 +49,10 @@ func NewMockManagedFilesystemSource(
 		set.NewStrings(),
 	}
 	return &managedFilesystemSource{
-		run, dirFuncs,
+		run, dirFuncs, etcDir,
 		volumeBlockDevices, filesystems,
+		volumeEncryptionKeys,
+		filesystemCompositions,
 	}, dirFuncs
 }
 
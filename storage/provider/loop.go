@@ -103,7 +103,14 @@ type loopVolumeSource struct {
 	storageDir string
 }
 
-var _ storage.VolumeSource = (*loopVolumeSource)(nil)
+var (
+	_ storage.VolumeSource      = (*loopVolumeSource)(nil)
+	_ storage.VolumeSnapshotter = (*loopVolumeSource)(nil)
+)
+
+// loopSnapshotDir is the name of the directory, relative to the
+// storage directory, in which loop volume snapshots are stored.
+const loopSnapshotDir = "snapshots"
 
 // CreateVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) CreateVolumes(ctx context.ProviderCallContext, args []storage.VolumeParams) ([]storage.CreateVolumesResult, error) {
@@ -124,6 +131,17 @@ func (lvs *loopVolumeSource) createVolume(params storage.VolumeParams) (storage.
 	if err := ensureDir(lvs.dirFuncs, filepath.Dir(loopFilePath)); err != nil {
 		return storage.Volume{}, errors.Trace(err)
 	}
+	if params.SnapshotId != "" {
+		snapshotFilePath, err := lvs.snapshotFilePath(params.SnapshotId)
+		if err != nil {
+			return storage.Volume{}, errors.Trace(err)
+		}
+		if err := copyBlockFile(lvs.run, snapshotFilePath, loopFilePath); err != nil {
+			return storage.Volume{}, errors.Annotate(err, "could not restore block file from snapshot")
+		}
+	}
+	// If the volume was restored from a snapshot, fallocate will
+	// extend the file to the requested size if necessary.
 	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
 		return storage.Volume{}, errors.Annotate(err, "could not create block file")
 	}
@@ -140,6 +158,111 @@ func (lvs *loopVolumeSource) volumeFilePath(tag names.VolumeTag) string {
 	return filepath.Join(lvs.storageDir, tag.String())
 }
 
+func (lvs *loopVolumeSource) snapshotFilePath(snapshotId string) (string, error) {
+	if !strings.HasPrefix(snapshotId, "snapshot-") || strings.ContainsAny(snapshotId, `/\`) {
+		return "", errors.NotValidf("loop snapshot ID %q", snapshotId)
+	}
+	return filepath.Join(lvs.storageDir, loopSnapshotDir, snapshotId), nil
+}
+
+// CreateVolumeSnapshots is defined on the VolumeSnapshotter interface.
+//
+// The loop backing file is copied while the volume may still be attached
+// and in use, so snapshots are only crash-consistent.
+func (lvs *loopVolumeSource) CreateVolumeSnapshots(
+	ctx context.ProviderCallContext, args []storage.VolumeSnapshotParams,
+) ([]storage.CreateSnapshotsResult, error) {
+	results := make([]storage.CreateSnapshotsResult, len(args))
+	for i, arg := range args {
+		snapshotId, err := lvs.createVolumeSnapshot(arg)
+		if err != nil {
+			results[i].Error = errors.Annotatef(err, "creating snapshot of %s", names.ReadableString(arg.Volume))
+			continue
+		}
+		results[i].SnapshotId = snapshotId
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) createVolumeSnapshot(params storage.VolumeSnapshotParams) (string, error) {
+	tag, err := names.ParseVolumeTag(params.VolumeId)
+	if err != nil {
+		return "", errors.Errorf("invalid loop volume ID %q", params.VolumeId)
+	}
+	snapshotId := "snapshot-" + strings.Replace(params.Id, "/", "-", -1)
+	snapshotFilePath, err := lvs.snapshotFilePath(snapshotId)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := ensureDir(lvs.dirFuncs, filepath.Dir(snapshotFilePath)); err != nil {
+		return "", errors.Trace(err)
+	}
+	if err := copyBlockFile(lvs.run, lvs.volumeFilePath(tag), snapshotFilePath); err != nil {
+		return "", errors.Trace(err)
+	}
+	return snapshotId, nil
+}
+
+// DeleteVolumeSnapshots is defined on the VolumeSnapshotter interface.
+func (lvs *loopVolumeSource) DeleteVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	results := make([]error, len(snapshotIds))
+	for i, snapshotId := range snapshotIds {
+		if err := lvs.deleteVolumeSnapshot(snapshotId); err != nil {
+			results[i] = errors.Annotatef(err, "deleting snapshot %q", snapshotId)
+		}
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) deleteVolumeSnapshot(snapshotId string) error {
+	snapshotFilePath, err := lvs.snapshotFilePath(snapshotId)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.Remove(snapshotFilePath); err != nil && !os.IsNotExist(err) {
+		return errors.Annotate(err, "removing loop snapshot file")
+	}
+	return nil
+}
+
+// ResizeVolumes is defined on the VolumeResizer interface.
+func (lvs *loopVolumeSource) ResizeVolumes(
+	ctx context.ProviderCallContext, args []storage.VolumeResizeParams,
+) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(args))
+	for i, arg := range args {
+		if err := lvs.resizeVolume(arg); err != nil {
+			results[i].Error = errors.Annotatef(err, "resizing %s", names.ReadableString(arg.Volume))
+			continue
+		}
+		results[i].Size = arg.Size
+	}
+	return results, nil
+}
+
+func (lvs *loopVolumeSource) resizeVolume(params storage.VolumeResizeParams) error {
+	tag, err := names.ParseVolumeTag(params.VolumeId)
+	if err != nil {
+		return errors.Errorf("invalid loop volume ID %q", params.VolumeId)
+	}
+	loopFilePath := lvs.volumeFilePath(tag)
+	if err := createBlockFile(lvs.run, loopFilePath, params.Size); err != nil {
+		return errors.Trace(err)
+	}
+	// Any attached loop device must be told to re-read the size
+	// of its backing file before the extra space can be used.
+	deviceNames, err := associatedLoopDevices(lvs.run, loopFilePath)
+	if err != nil {
+		return errors.Annotate(err, "locating loop device")
+	}
+	for _, deviceName := range deviceNames {
+		if err := refreshLoopDevice(lvs.run, deviceName); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // ListVolumes is defined on the VolumeSource interface.
 func (lvs *loopVolumeSource) ListVolumes(ctx context.ProviderCallContext) ([]string, error) {
 	// TODO(axw) implement this when we need it.
@@ -259,6 +382,16 @@ func createBlockFile(run runCommandFunc, filePath string, sizeInMiB uint64) erro
 	return nil
 }
 
+// copyBlockFile copies the file at the source path to the destination
+// path, preserving holes in sparse files.
+func copyBlockFile(run runCommandFunc, sourcePath, destPath string) error {
+	_, err := run("cp", "--sparse=always", sourcePath, destPath)
+	if err != nil {
+		return errors.Annotatef(err, "copying loop backing file %q", sourcePath)
+	}
+	return nil
+}
+
 // attachLoopDevice attaches a loop device to the file with the
 // specified path, and returns the loop device's name (e.g. "loop0").
 // losetup will create additional loop devices as necessary.
@@ -298,6 +431,16 @@ func detachLoopDevice(run runCommandFunc, deviceName string) error {
 	return err
 }
 
+// refreshLoopDevice updates the capacity of the loop device with the
+// specified name to match the size of its backing file.
+func refreshLoopDevice(run runCommandFunc, deviceName string) error {
+	_, err := run("losetup", "-c", path.Join("/dev", deviceName))
+	if err != nil {
+		return errors.Annotatef(err, "refreshing loop device %q", deviceName)
+	}
+	return nil
+}
+
 // associatedLoopDevices returns the device names of the loop devices
 // associated with the specified file path.
 func associatedLoopDevices(run runCommandFunc, filePath string) ([]string, error) {
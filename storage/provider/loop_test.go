@@ -130,6 +130,141 @@ func (s *loopSuite) TestCreateVolumesNoAttachment(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *loopSuite) TestCreateVolumesFromSnapshot(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	s.commands.expect("cp", "--sparse=always",
+		filepath.Join(s.storageDir, "snapshots", "snapshot-0-1"),
+		filepath.Join(s.storageDir, "volume-0-2"),
+	)
+	s.commands.expect("fallocate", "-l", "4MiB", filepath.Join(s.storageDir, "volume-0-2"))
+
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0/2"),
+		Size:       4,
+		SnapshotId: "snapshot-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Volume, jc.DeepEquals, &storage.Volume{
+		names.NewVolumeTag("0/2"),
+		storage.VolumeInfo{
+			VolumeId: "volume-0-2",
+			Size:     4,
+		},
+	})
+	s.commands.assertDrained()
+}
+
+func (s *loopSuite) TestCreateVolumesFromInvalidSnapshot(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	results, err := source.CreateVolumes(s.callCtx, []storage.VolumeParams{{
+		Tag:        names.NewVolumeTag("0/2"),
+		Size:       4,
+		SnapshotId: "../volume-0-1",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, gc.ErrorMatches, `creating volume: loop snapshot ID "../volume-0-1" not valid`)
+}
+
+func (s *loopSuite) TestCreateVolumeSnapshots(c *gc.C) {
+	source, dirFuncs := s.loopVolumeSource(c)
+	s.commands.expect("cp", "--sparse=always",
+		filepath.Join(s.storageDir, "volume-0-1"),
+		filepath.Join(s.storageDir, "snapshots", "snapshot-0-1"),
+	)
+	s.commands.expect("cp", "--sparse=always",
+		filepath.Join(s.storageDir, "volume-0-2"),
+		filepath.Join(s.storageDir, "snapshots", "snapshot-0-2"),
+	).respond("", errors.New("no space left on device"))
+
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsTrue)
+	results, err := snapshotter.CreateVolumeSnapshots(s.callCtx, []storage.VolumeSnapshotParams{{
+		Id:       "0/1",
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+	}, {
+		Id:       "0/2",
+		Volume:   names.NewVolumeTag("0/2"),
+		VolumeId: "volume-0-2",
+	}, {
+		Id:       "0/3",
+		Volume:   names.NewVolumeTag("0/3"),
+		VolumeId: "invalid",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.DeepEquals, storage.CreateSnapshotsResult{SnapshotId: "snapshot-0-1"})
+	c.Assert(results[1].Error, gc.ErrorMatches,
+		`creating snapshot of volume 0/2: copying loop backing file ".*volume-0-2": no space left on device`)
+	c.Assert(results[2].Error, gc.ErrorMatches,
+		`creating snapshot of volume 0/3: invalid loop volume ID "invalid"`)
+	c.Assert(dirFuncs.Dirs.Contains(filepath.Join(s.storageDir, "snapshots")), jc.IsTrue)
+	s.commands.assertDrained()
+}
+
+func (s *loopSuite) TestDeleteVolumeSnapshots(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	snapshotDir := filepath.Join(s.storageDir, "snapshots")
+	err := os.MkdirAll(snapshotDir, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	fileName := filepath.Join(snapshotDir, "snapshot-0-1")
+	err = ioutil.WriteFile(fileName, nil, 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	c.Assert(ok, jc.IsTrue)
+	errs, err := snapshotter.DeleteVolumeSnapshots(s.callCtx, []string{
+		"snapshot-0-1", "snapshot-0-2", "../volume-0-1",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(errs, gc.HasLen, 3)
+	c.Assert(errs[0], jc.ErrorIsNil)
+	// Snapshots that no longer exist are not errors.
+	c.Assert(errs[1], jc.ErrorIsNil)
+	c.Assert(errs[2], gc.ErrorMatches, `deleting snapshot "../volume-0-1": loop snapshot ID "../volume-0-1" not valid`)
+
+	_, err = os.Stat(fileName)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *loopSuite) TestResizeVolumes(c *gc.C) {
+	source, _ := s.loopVolumeSource(c)
+	fileName := filepath.Join(s.storageDir, "volume-0-1")
+	s.commands.expect("fallocate", "-l", "4096MiB", fileName)
+	cmd := s.commands.expect("losetup", "-j", fileName)
+	cmd.respond("/dev/loop0: foo\n", nil)
+	s.commands.expect("losetup", "-c", "/dev/loop0")
+	cmd = s.commands.expect("fallocate", "-l", "4096MiB", filepath.Join(s.storageDir, "volume-0-2"))
+	cmd.respond("", errors.New("no space left on device"))
+
+	resizer, ok := source.(storage.VolumeResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeVolumes(s.callCtx, []storage.VolumeResizeParams{{
+		Volume:   names.NewVolumeTag("0/1"),
+		VolumeId: "volume-0-1",
+		Size:     4096,
+	}, {
+		Volume:   names.NewVolumeTag("0/2"),
+		VolumeId: "volume-0-2",
+		Size:     4096,
+	}, {
+		Volume:   names.NewVolumeTag("0/3"),
+		VolumeId: "invalid",
+		Size:     4096,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeResult{Size: 4096})
+	c.Assert(results[1].Error, gc.ErrorMatches,
+		`resizing volume 0/2: allocating loop backing file ".*volume-0-2": no space left on device`)
+	c.Assert(results[2].Error, gc.ErrorMatches,
+		`resizing volume 0/3: invalid loop volume ID "invalid"`)
+	s.commands.assertDrained()
+}
+
 func (s *loopSuite) TestDestroyVolumes(c *gc.C) {
 	source, _ := s.loopVolumeSource(c)
 	fileName := filepath.Join(s.storageDir, "volume-0")
@@ -5,6 +5,7 @@ package provider
 
 import (
 	"bufio"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
@@ -23,6 +24,15 @@ const (
 	// defaultFilesystemType is the default filesystem type
 	// to create for volume-backed managed filesystems.
 	defaultFilesystemType = "ext4"
+
+	// encryptionKeyDir is the directory in which volume encryption
+	// keys are briefly written, so that they may be passed to
+	// cryptsetup. The directory is expected to be backed by memory.
+	encryptionKeyDir = "/run"
+
+	// composedLogicalVolumeName is the name of the logical volume
+	// created in the volume group of LVM-composed filesystems.
+	composedLogicalVolumeName = "data"
 )
 
 // managedFilesystemSource is an implementation of storage.FilesystemSource
@@ -30,26 +40,39 @@ const (
 //
 // managedFilesystemSource is expected to be called from a single goroutine.
 type managedFilesystemSource struct {
-	run                runCommandFunc
-	dirFuncs           dirFuncs
-	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice
-	filesystems        map[names.FilesystemTag]storage.Filesystem
+	run                  runCommandFunc
+	dirFuncs             dirFuncs
+	keyDir               string
+	volumeBlockDevices   map[names.VolumeTag]storage.BlockDevice
+	filesystems          map[names.FilesystemTag]storage.Filesystem
+	volumeEncryptionKeys map[names.VolumeTag]string
+
+	filesystemCompositions map[names.FilesystemTag]storage.FilesystemComposition
 }
 
 // NewManagedFilesystemSource returns a storage.FilesystemSource that manages
 // filesystems on block devices on the host machine.
 //
 // The parameters are maps that the caller will update with information about
-// block devices and filesystems created by the source. The caller must not
-// update the maps during calls to the source's methods.
+// block devices and filesystems created by the source, the keys with which
+// encrypted volumes are to be encrypted, and the compositions of filesystems
+// backed by multiple volumes. Filesystems on volumes with an encryption key
+// are created on a LUKS-encrypted device. Filesystems with a composition are
+// created on a RAID or LVM device combining all of the composed volumes. The
+// caller must not update the maps during calls to the source's methods.
 func NewManagedFilesystemSource(
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice,
 	filesystems map[names.FilesystemTag]storage.Filesystem,
+	volumeEncryptionKeys map[names.VolumeTag]string,
+	filesystemCompositions map[names.FilesystemTag]storage.FilesystemComposition,
 ) storage.FilesystemSource {
 	return &managedFilesystemSource{
 		logAndExec,
 		&osDirFuncs{logAndExec},
+		encryptionKeyDir,
 		volumeBlockDevices, filesystems,
+		volumeEncryptionKeys,
+		filesystemCompositions,
 	}
 }
 
@@ -87,19 +110,90 @@ func (s *managedFilesystemSource) CreateFilesystems(ctx context.ProviderCallCont
 }
 
 func (s *managedFilesystemSource) createFilesystem(arg storage.FilesystemParams) (*storage.Filesystem, error) {
+	if composition, ok := s.filesystemCompositions[arg.Tag]; ok {
+		return s.createComposedFilesystem(arg, composition)
+	}
 	blockDevice, err := s.backingVolumeBlockDevice(arg.Volume)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	devicePath := devicePath(blockDevice)
-	if isDiskDevice(devicePath) {
-		if err := destroyPartitions(s.run, devicePath); err != nil {
+	key, haveKey, err := s.encryptionKey(arg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if haveKey && arg.SnapshotId != "" {
+		// The snapshot's contents are encrypted with the key
+		// of the volume from which the snapshot was taken.
+		return nil, errors.NotSupportedf("restoring encrypted filesystems from snapshots")
+	}
+	// A backing volume created from a snapshot already
+	// contains the filesystem, so it must not be formatted.
+	if arg.SnapshotId == "" {
+		devicePath := devicePath(blockDevice)
+		if isDiskDevice(devicePath) {
+			if err := destroyPartitions(s.run, devicePath); err != nil {
+				return nil, errors.Trace(err)
+			}
+			if err := createPartition(s.run, devicePath); err != nil {
+				return nil, errors.Trace(err)
+			}
+			devicePath = partitionDevicePath(devicePath)
+		}
+		if haveKey {
+			devicePath, err = s.formatEncryptedDevice(arg.Volume, key, devicePath)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if err := createFilesystem(s.run, devicePath); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return &storage.Filesystem{
+		arg.Tag,
+		arg.Volume,
+		storage.FilesystemInfo{
+			arg.Tag.String(),
+			blockDevice.Size,
+		},
+	}, nil
+}
+
+// createComposedFilesystem creates a filesystem on a device combining
+// all of the volumes in the specified composition.
+func (s *managedFilesystemSource) createComposedFilesystem(
+	arg storage.FilesystemParams, composition storage.FilesystemComposition,
+) (*storage.Filesystem, error) {
+	if arg.SnapshotId != "" {
+		return nil, errors.NotSupportedf("restoring composed filesystems from snapshots")
+	}
+	devicePaths := make([]string, len(composition.Volumes))
+	var size uint64
+	for i, volumeTag := range composition.Volumes {
+		blockDevice, err := s.backingVolumeBlockDevice(volumeTag)
+		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		if err := createPartition(s.run, devicePath); err != nil {
+		devicePaths[i] = devicePath(blockDevice)
+		if composition.Type != storage.CompositionRAID1 {
+			size += blockDevice.Size
+		} else if i == 0 || blockDevice.Size < size {
+			size = blockDevice.Size
+		}
+	}
+	key, haveKey, err := s.encryptionKey(arg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	devicePath, err := s.createComposedDevice(arg.Tag, composition.Type, devicePaths)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if haveKey {
+		devicePath, err = s.formatEncryptedDevice(arg.Volume, key, devicePath)
+		if err != nil {
 			return nil, errors.Trace(err)
 		}
-		devicePath = partitionDevicePath(devicePath)
 	}
 	if err := createFilesystem(s.run, devicePath); err != nil {
 		return nil, errors.Trace(err)
@@ -109,11 +203,65 @@ func (s *managedFilesystemSource) createFilesystem(arg storage.FilesystemParams)
 		arg.Volume,
 		storage.FilesystemInfo{
 			arg.Tag.String(),
-			blockDevice.Size,
+			size,
 		},
 	}, nil
 }
 
+// encryptionKey returns the key with which the filesystem's backing
+// volume is encrypted, if any. An error is returned if the filesystem
+// is to be encrypted, but the key is not yet available.
+func (s *managedFilesystemSource) encryptionKey(arg storage.FilesystemParams) (string, bool, error) {
+	encrypted, err := storage.IsEncrypted(arg.Attributes)
+	if err != nil {
+		return "", false, errors.Trace(err)
+	}
+	key, haveKey := s.volumeEncryptionKeys[arg.Volume]
+	if encrypted && !haveKey {
+		return "", false, errors.Errorf(
+			"encryption key for backing-volume %s is not yet available", arg.Volume.Id(),
+		)
+	}
+	return key, haveKey, nil
+}
+
+// ResizeFilesystems is defined on storage.FilesystemResizer.
+func (s *managedFilesystemSource) ResizeFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemResizeParams) ([]storage.ResizeResult, error) {
+	results := make([]storage.ResizeResult, len(args))
+	for i, arg := range args {
+		if err := s.resizeFilesystem(arg); err != nil {
+			results[i].Error = err
+			continue
+		}
+		results[i].Size = arg.Size
+	}
+	return results, nil
+}
+
+func (s *managedFilesystemSource) resizeFilesystem(arg storage.FilesystemResizeParams) error {
+	if _, ok := s.filesystemCompositions[arg.Tag]; ok {
+		return errors.NotSupportedf("resizing composed filesystems")
+	}
+	blockDevice, err := s.backingVolumeBlockDevice(arg.Volume)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	devicePath := devicePath(blockDevice)
+	if isDiskDevice(devicePath) {
+		if err := growPartition(s.run, devicePath); err != nil {
+			return errors.Trace(err)
+		}
+		devicePath = partitionDevicePath(devicePath)
+	}
+	if key, ok := s.volumeEncryptionKeys[arg.Volume]; ok {
+		devicePath, err = s.growEncryptedDevice(arg.Volume, key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(growFilesystem(s.run, devicePath))
+}
+
 // DestroyFilesystems is defined on storage.FilesystemSource.
 func (s *managedFilesystemSource) DestroyFilesystems(ctx context.ProviderCallContext, filesystemIds []string) ([]error, error) {
 	// DestroyFilesystems is a no-op; there is nothing to destroy,
@@ -146,15 +294,25 @@ func (s *managedFilesystemSource) attachFilesystem(arg storage.FilesystemAttachm
 	if !ok {
 		return nil, errors.Errorf("filesystem %v is not yet provisioned", arg.Filesystem.Id())
 	}
-	blockDevice, err := s.backingVolumeBlockDevice(filesystem.Volume)
+	_, composed := s.filesystemCompositions[arg.Filesystem]
+	devicePath, err := s.filesystemDevicePath(filesystem)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	devicePath := devicePath(blockDevice)
-	if isDiskDevice(devicePath) {
-		devicePath = partitionDevicePath(devicePath)
+	// The keys for encrypted filesystems are not stored on the
+	// machine, so they cannot be mounted at boot; the storage
+	// provisioner mounts them again when it starts. The same
+	// goes for composed filesystems, whose devices are assembled
+	// by the storage provisioner.
+	key, encrypted := s.volumeEncryptionKeys[filesystem.Volume]
+	if encrypted {
+		devicePath, err = s.openEncryptedDevice(filesystem.Volume, key, devicePath)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
 	}
-	if err := mountFilesystem(s.run, s.dirFuncs, devicePath, arg.Path, arg.ReadOnly); err != nil {
+	persistent := !encrypted && !composed
+	if err := mountFilesystem(s.run, s.dirFuncs, devicePath, arg.Path, arg.ReadOnly, persistent); err != nil {
 		return nil, errors.Trace(err)
 	}
 	return &storage.FilesystemAttachment{
@@ -167,17 +325,256 @@ func (s *managedFilesystemSource) attachFilesystem(arg storage.FilesystemAttachm
 	}, nil
 }
 
+// filesystemDevicePath returns the path of the device containing the
+// specified filesystem, prior to any decryption. The combined device
+// of composed filesystems is assembled if necessary.
+func (s *managedFilesystemSource) filesystemDevicePath(filesystem storage.Filesystem) (string, error) {
+	if composition, ok := s.filesystemCompositions[filesystem.Tag]; ok {
+		return s.assembleComposedDevice(filesystem.Tag, composition)
+	}
+	blockDevice, err := s.backingVolumeBlockDevice(filesystem.Volume)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	devicePath := devicePath(blockDevice)
+	if isDiskDevice(devicePath) {
+		devicePath = partitionDevicePath(devicePath)
+	}
+	return devicePath, nil
+}
+
 // DetachFilesystems is defined on storage.FilesystemSource.
 func (s *managedFilesystemSource) DetachFilesystems(ctx context.ProviderCallContext, args []storage.FilesystemAttachmentParams) ([]error, error) {
 	results := make([]error, len(args))
 	for i, arg := range args {
 		if err := maybeUnmount(s.run, s.dirFuncs, arg.Path); err != nil {
 			results[i] = err
+			continue
+		}
+		filesystem, ok := s.filesystems[arg.Filesystem]
+		if !ok {
+			continue
+		}
+		if _, ok := s.volumeEncryptionKeys[filesystem.Volume]; ok {
+			if err := s.closeEncryptedDevice(filesystem.Volume); err != nil {
+				results[i] = err
+				continue
+			}
+		}
+		if composition, ok := s.filesystemCompositions[arg.Filesystem]; ok {
+			results[i] = s.deactivateComposedDevice(arg.Filesystem, composition.Type)
 		}
 	}
 	return results, nil
 }
 
+// createComposedDevice combines the devices with the specified paths
+// into a single device, as described by the composition type, and
+// returns the path of the combined device.
+func (s *managedFilesystemSource) createComposedDevice(
+	tag names.FilesystemTag, compositionType storage.CompositionType, devicePaths []string,
+) (string, error) {
+	logger.Debugf("combining %q into %s device", devicePaths, compositionType)
+	name := composedDeviceName(tag)
+	switch compositionType {
+	case storage.CompositionRAID0, storage.CompositionRAID1:
+		args := []string{
+			"--create", composedDevicePath(tag, compositionType),
+			"--run",
+			"--level=" + strings.TrimPrefix(string(compositionType), "raid"),
+			fmt.Sprintf("--raid-devices=%d", len(devicePaths)),
+		}
+		if _, err := s.run("mdadm", append(args, devicePaths...)...); err != nil {
+			return "", errors.Annotate(err, "mdadm create failed")
+		}
+	case storage.CompositionLVM:
+		if _, err := s.run("pvcreate", devicePaths...); err != nil {
+			return "", errors.Annotate(err, "pvcreate failed")
+		}
+		if _, err := s.run("vgcreate", append([]string{name}, devicePaths...)...); err != nil {
+			return "", errors.Annotate(err, "vgcreate failed")
+		}
+		if _, err := s.run(
+			"lvcreate", "--extents", "100%FREE",
+			"--name", composedLogicalVolumeName, name,
+		); err != nil {
+			return "", errors.Annotate(err, "lvcreate failed")
+		}
+	default:
+		return "", errors.NotSupportedf("composition %q", compositionType)
+	}
+	composedPath := composedDevicePath(tag, compositionType)
+	logger.Infof("created %s device %q", compositionType, composedPath)
+	return composedPath, nil
+}
+
+// assembleComposedDevice assembles the combined device for the filesystem
+// with the specified tag, if it is not already assembled, and returns the
+// path of the combined device.
+func (s *managedFilesystemSource) assembleComposedDevice(
+	tag names.FilesystemTag, composition storage.FilesystemComposition,
+) (string, error) {
+	composedPath := composedDevicePath(tag, composition.Type)
+	if _, err := s.dirFuncs.lstat(composedPath); err == nil {
+		return composedPath, nil
+	} else if !os.IsNotExist(err) {
+		return "", errors.Trace(err)
+	}
+	switch composition.Type {
+	case storage.CompositionRAID0, storage.CompositionRAID1:
+		args := []string{"--assemble", composedPath}
+		for _, volumeTag := range composition.Volumes {
+			blockDevice, err := s.backingVolumeBlockDevice(volumeTag)
+			if err != nil {
+				return "", errors.Trace(err)
+			}
+			args = append(args, devicePath(blockDevice))
+		}
+		if _, err := s.run("mdadm", args...); err != nil {
+			return "", errors.Annotate(err, "mdadm assemble failed")
+		}
+	case storage.CompositionLVM:
+		if _, err := s.run("vgchange", "--activate", "y", composedDeviceName(tag)); err != nil {
+			return "", errors.Annotate(err, "vgchange failed")
+		}
+	default:
+		return "", errors.NotSupportedf("composition %q", composition.Type)
+	}
+	logger.Infof("assembled %s device %q", composition.Type, composedPath)
+	return composedPath, nil
+}
+
+// deactivateComposedDevice stops the combined device for the filesystem
+// with the specified tag, if it is assembled, so that the composed
+// volumes may be detached.
+func (s *managedFilesystemSource) deactivateComposedDevice(
+	tag names.FilesystemTag, compositionType storage.CompositionType,
+) error {
+	composedPath := composedDevicePath(tag, compositionType)
+	if _, err := s.dirFuncs.lstat(composedPath); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	switch compositionType {
+	case storage.CompositionRAID0, storage.CompositionRAID1:
+		if _, err := s.run("mdadm", "--stop", composedPath); err != nil {
+			return errors.Annotate(err, "mdadm stop failed")
+		}
+	case storage.CompositionLVM:
+		if _, err := s.run("vgchange", "--activate", "n", composedDeviceName(tag)); err != nil {
+			return errors.Annotate(err, "vgchange failed")
+		}
+	}
+	return nil
+}
+
+// formatEncryptedDevice sets up LUKS encryption on the device with the
+// specified path using the specified key, and opens the encrypted device.
+// The path of the opened device, on which the filesystem is to be created,
+// is returned.
+func (s *managedFilesystemSource) formatEncryptedDevice(v names.VolumeTag, key, devicePath string) (string, error) {
+	logger.Debugf("encrypting %q", devicePath)
+	err := s.withKeyFile(v, key, func(keyFile string) error {
+		_, err := s.run("cryptsetup", "luksFormat", "--batch-mode", "--key-file", keyFile, devicePath)
+		return errors.Annotate(err, "cryptsetup luksFormat failed")
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return s.openEncryptedDevice(v, key, devicePath)
+}
+
+// openEncryptedDevice opens the LUKS-encrypted device with the specified
+// path using the specified key, if it is not already open, and returns
+// the path of the opened device.
+func (s *managedFilesystemSource) openEncryptedDevice(v names.VolumeTag, key, devicePath string) (string, error) {
+	name := encryptedDeviceName(v)
+	mappedPath := encryptedDevicePath(v)
+	if _, err := s.dirFuncs.lstat(mappedPath); err == nil {
+		logger.Debugf("encrypted device %q already open at %q", devicePath, mappedPath)
+		return mappedPath, nil
+	} else if !os.IsNotExist(err) {
+		return "", errors.Trace(err)
+	}
+	err := s.withKeyFile(v, key, func(keyFile string) error {
+		_, err := s.run("cryptsetup", "open", "--type", "luks", "--key-file", keyFile, devicePath, name)
+		return errors.Annotate(err, "cryptsetup open failed")
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	logger.Infof("opened encrypted device %q at %q", devicePath, mappedPath)
+	return mappedPath, nil
+}
+
+// growEncryptedDevice grows the open LUKS-encrypted device for the
+// specified volume to fill the underlying device, and returns the path
+// of the opened device.
+func (s *managedFilesystemSource) growEncryptedDevice(v names.VolumeTag, key string) (string, error) {
+	err := s.withKeyFile(v, key, func(keyFile string) error {
+		_, err := s.run("cryptsetup", "resize", "--key-file", keyFile, encryptedDeviceName(v))
+		return errors.Annotate(err, "cryptsetup resize failed")
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return encryptedDevicePath(v), nil
+}
+
+// closeEncryptedDevice closes the LUKS-encrypted device for the
+// specified volume, if it is open.
+func (s *managedFilesystemSource) closeEncryptedDevice(v names.VolumeTag) error {
+	if _, err := s.dirFuncs.lstat(encryptedDevicePath(v)); os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := s.run("cryptsetup", "close", encryptedDeviceName(v)); err != nil {
+		return errors.Annotate(err, "cryptsetup close failed")
+	}
+	return nil
+}
+
+// withKeyFile writes the specified key to a file readable only by the
+// current user, and calls f with the path of the file. The file is
+// removed once f returns.
+func (s *managedFilesystemSource) withKeyFile(v names.VolumeTag, key string, f func(keyFile string) error) error {
+	keyFile := filepath.Join(s.keyDir, encryptedDeviceName(v)+".key")
+	if err := ioutil.WriteFile(keyFile, []byte(key), 0600); err != nil {
+		return errors.Annotate(err, "writing encryption key")
+	}
+	defer os.Remove(keyFile)
+	return f(keyFile)
+}
+
+// encryptedDeviceName returns the name of the device-mapper device
+// through which the encrypted volume with the specified tag is opened.
+func encryptedDeviceName(v names.VolumeTag) string {
+	return "juju-" + v.String()
+}
+
+// encryptedDevicePath returns the path of the device-mapper device
+// through which the encrypted volume with the specified tag is opened.
+func encryptedDevicePath(v names.VolumeTag) string {
+	return path.Join("/dev/mapper", encryptedDeviceName(v))
+}
+
+// composedDeviceName returns the name of the RAID device or LVM volume
+// group combining the volumes of the filesystem with the specified tag.
+func composedDeviceName(tag names.FilesystemTag) string {
+	return "juju-" + tag.String()
+}
+
+// composedDevicePath returns the path of the device combining the
+// volumes of the filesystem with the specified tag.
+func composedDevicePath(tag names.FilesystemTag, compositionType storage.CompositionType) string {
+	if compositionType == storage.CompositionLVM {
+		return path.Join("/dev", composedDeviceName(tag), composedLogicalVolumeName)
+	}
+	return path.Join("/dev/md", composedDeviceName(tag))
+}
+
 func destroyPartitions(run runCommandFunc, devicePath string) error {
 	logger.Debugf("destroying partitions on %q", devicePath)
 	if _, err := run("sgdisk", "--zap-all", devicePath); err != nil {
@@ -207,7 +604,31 @@ func createFilesystem(run runCommandFunc, devicePath string) error {
 	return nil
 }
 
-func mountFilesystem(run runCommandFunc, dirFuncs dirFuncs, devicePath, mountPoint string, readOnly bool) error {
+// growPartition grows the single partition (1) on the disk with the
+// specified device path to fill the disk.
+func growPartition(run runCommandFunc, devicePath string) error {
+	logger.Debugf("growing partition on %q", devicePath)
+	if _, err := run("growpart", devicePath, "1"); err != nil {
+		return errors.Annotate(err, "growpart failed")
+	}
+	return nil
+}
+
+// growFilesystem grows the filesystem on the device with the specified
+// path to fill the device. The filesystem may be mounted.
+func growFilesystem(run runCommandFunc, devicePath string) error {
+	logger.Debugf("growing filesystem on %q", devicePath)
+	if _, err := run("resize2fs", devicePath); err != nil {
+		return errors.Annotate(err, "resize2fs failed")
+	}
+	logger.Infof("grew filesystem on %q", devicePath)
+	return nil
+}
+
+// mountFilesystem mounts the filesystem on the device with the specified
+// path at the specified mount point. If persistent is true, the mount is
+// recorded in /etc/fstab so that it is restored when the machine boots.
+func mountFilesystem(run runCommandFunc, dirFuncs dirFuncs, devicePath, mountPoint string, readOnly, persistent bool) error {
 	logger.Debugf("attempting to mount filesystem on %q at %q", devicePath, mountPoint)
 	if err := dirFuncs.mkDirAll(mountPoint, 0755); err != nil {
 		return errors.Annotate(err, "creating mount point")
@@ -229,6 +650,9 @@ func mountFilesystem(run runCommandFunc, dirFuncs dirFuncs, devicePath, mountPoi
 		return errors.Annotate(err, "mount failed")
 	}
 	logger.Infof("mounted filesystem on %q at %q", devicePath, mountPoint)
+	if !persistent {
+		return nil
+	}
 
 	// Look for the mtab entry resulting from the mount and copy it to fstab.
 	// This ensures the mount is available available after a reboot.
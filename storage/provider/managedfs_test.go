@@ -6,8 +6,10 @@ package provider_test
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/names.v3"
@@ -26,6 +28,8 @@ type managedfsSuite struct {
 	dirFuncs     *provider.MockDirFuncs
 	blockDevices map[names.VolumeTag]storage.BlockDevice
 	filesystems  map[names.FilesystemTag]storage.Filesystem
+	keys         map[names.VolumeTag]string
+	compositions map[names.FilesystemTag]storage.FilesystemComposition
 	fakeEtcDir   string
 
 	callCtx context.ProviderCallContext
@@ -35,6 +39,8 @@ func (s *managedfsSuite) SetUpTest(c *gc.C) {
 	s.BaseSuite.SetUpTest(c)
 	s.blockDevices = make(map[names.VolumeTag]storage.BlockDevice)
 	s.filesystems = make(map[names.FilesystemTag]storage.Filesystem)
+	s.keys = make(map[names.VolumeTag]string)
+	s.compositions = make(map[names.FilesystemTag]storage.FilesystemComposition)
 	s.callCtx = context.NewCloudCallContext()
 	s.fakeEtcDir = c.MkDir()
 }
@@ -53,6 +59,8 @@ func (s *managedfsSuite) initSource(c *gc.C) storage.FilesystemSource {
 		s.commands.run,
 		s.blockDevices,
 		s.filesystems,
+		s.keys,
+		s.compositions,
 	)
 	s.dirFuncs = mockDirFuncs
 	return source
@@ -110,6 +118,75 @@ func (s *managedfsSuite) TestCreateFilesystems(c *gc.C) {
 	}})
 }
 
+func (s *managedfsSuite) TestCreateFilesystemsFromSnapshot(c *gc.C) {
+	source := s.initSource(c)
+	// The volume was restored from a snapshot, so it must
+	// be neither partitioned nor formatted.
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		HardwareId: "capncrunch",
+		Size:       2,
+	}
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:        names.NewFilesystemTag("0/0"),
+		Volume:     names.NewVolumeTag("0"),
+		Size:       2,
+		SnapshotId: "snapshot-0-0",
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateFilesystemsResult{{
+		Filesystem: &storage.Filesystem{
+			names.NewFilesystemTag("0/0"),
+			names.NewVolumeTag("0"),
+			storage.FilesystemInfo{
+				FilesystemId: "filesystem-0-0",
+				Size:         2,
+			},
+		},
+	}})
+}
+
+func (s *managedfsSuite) TestResizeFilesystems(c *gc.C) {
+	source := s.initSource(c)
+	// The partition on sda is grown before the filesystem.
+	s.commands.expect("growpart", "/dev/sda", "1")
+	s.commands.expect("resize2fs", "/dev/sda1")
+	s.commands.expect("resize2fs", "/dev/xvdf1").respond("", errors.New("no"))
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       4,
+	}
+	s.blockDevices[names.NewVolumeTag("1")] = storage.BlockDevice{
+		DeviceName: "xvdf1",
+		Size:       6,
+	}
+	resizer, ok := source.(storage.FilesystemResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeFilesystems(s.callCtx, []storage.FilesystemResizeParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		Volume:       names.NewVolumeTag("0"),
+		FilesystemId: "filesystem-0-0",
+		Size:         4,
+	}, {
+		Filesystem:   names.NewFilesystemTag("0/1"),
+		Volume:       names.NewVolumeTag("1"),
+		FilesystemId: "filesystem-0-1",
+		Size:         6,
+	}, {
+		Filesystem:   names.NewFilesystemTag("0/2"),
+		Volume:       names.NewVolumeTag("2"),
+		FilesystemId: "filesystem-0-2",
+		Size:         6,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Assert(results[0], jc.DeepEquals, storage.ResizeResult{Size: 4})
+	c.Assert(results[1].Error, gc.ErrorMatches, "resize2fs failed: no")
+	c.Assert(results[2].Error, gc.ErrorMatches, "backing-volume 2 is not yet attached")
+	s.commands.assertDrained()
+}
+
 func (s *managedfsSuite) TestCreateFilesystemsNoBlockDevice(c *gc.C) {
 	source := s.initSource(c)
 	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
@@ -121,8 +198,267 @@ func (s *managedfsSuite) TestCreateFilesystemsNoBlockDevice(c *gc.C) {
 	c.Assert(results[0].Error, gc.ErrorMatches, "backing-volume 0 is not yet attached")
 }
 
+func (s *managedfsSuite) TestCreateFilesystemsEncrypted(c *gc.C) {
+	source := s.initSource(c)
+	keyFile := filepath.Join(s.fakeEtcDir, "juju-volume-0.key")
+	s.commands.expect("sgdisk", "--zap-all", "/dev/sda")
+	s.commands.expect("sgdisk", "-n", "1:0:-1", "/dev/sda")
+	s.commands.expect("cryptsetup", "luksFormat", "--batch-mode", "--key-file", keyFile, "/dev/sda1")
+	s.commands.expect("cryptsetup", "open", "--type", "luks", "--key-file", keyFile, "/dev/sda1", "juju-volume-0")
+	s.commands.expect("mkfs.ext4", "/dev/mapper/juju-volume-0")
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       2,
+	}
+	s.keys[names.NewVolumeTag("0")] = "sekrit"
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:        names.NewFilesystemTag("0/0"),
+		Volume:     names.NewVolumeTag("0"),
+		Size:       2,
+		Attributes: map[string]interface{}{"encrypted": true},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	// The key must not be left lying around.
+	_, err = os.Stat(keyFile)
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *managedfsSuite) TestCreateFilesystemsEncryptedNoKey(c *gc.C) {
+	source := s.initSource(c)
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       2,
+	}
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:        names.NewFilesystemTag("0/0"),
+		Volume:     names.NewVolumeTag("0"),
+		Size:       2,
+		Attributes: map[string]interface{}{"encrypted": true},
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "encryption key for backing-volume 0 is not yet available")
+}
+
+func (s *managedfsSuite) TestResizeFilesystemsEncrypted(c *gc.C) {
+	source := s.initSource(c)
+	keyFile := filepath.Join(s.fakeEtcDir, "juju-volume-0.key")
+	s.commands.expect("growpart", "/dev/sda", "1")
+	s.commands.expect("cryptsetup", "resize", "--key-file", keyFile, "juju-volume-0")
+	s.commands.expect("resize2fs", "/dev/mapper/juju-volume-0")
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       4,
+	}
+	s.keys[names.NewVolumeTag("0")] = "sekrit"
+	resizer, ok := source.(storage.FilesystemResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeFilesystems(s.callCtx, []storage.FilesystemResizeParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		Volume:       names.NewVolumeTag("0"),
+		FilesystemId: "filesystem-0-0",
+		Size:         4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.ResizeResult{{Size: 4}})
+}
+
+func (s *managedfsSuite) TestCreateFilesystemsRAID0(c *gc.C) {
+	source := s.initSource(c)
+	s.commands.expect(
+		"mdadm", "--create", "/dev/md/juju-filesystem-0-0", "--run",
+		"--level=0", "--raid-devices=2", "/dev/sda", "/dev/sdb",
+	)
+	s.commands.expect("mkfs.ext4", "/dev/md/juju-filesystem-0-0")
+
+	s.setUpComposition(storage.CompositionRAID0)
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+		Size:   4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, []storage.CreateFilesystemsResult{{
+		Filesystem: &storage.Filesystem{
+			names.NewFilesystemTag("0/0"),
+			names.NewVolumeTag("0"),
+			storage.FilesystemInfo{
+				FilesystemId: "filesystem-0-0",
+				Size:         4,
+			},
+		},
+	}})
+}
+
+func (s *managedfsSuite) TestCreateFilesystemsLVM(c *gc.C) {
+	source := s.initSource(c)
+	s.commands.expect("pvcreate", "/dev/sda", "/dev/sdb")
+	s.commands.expect("vgcreate", "juju-filesystem-0-0", "/dev/sda", "/dev/sdb")
+	s.commands.expect("lvcreate", "--extents", "100%FREE", "--name", "data", "juju-filesystem-0-0")
+	s.commands.expect("mkfs.ext4", "/dev/juju-filesystem-0-0/data")
+
+	s.setUpComposition(storage.CompositionLVM)
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+		Size:   4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Filesystem.Size, gc.Equals, uint64(4))
+}
+
+func (s *managedfsSuite) TestCreateFilesystemsRAID1(c *gc.C) {
+	source := s.initSource(c)
+	s.commands.expect(
+		"mdadm", "--create", "/dev/md/juju-filesystem-0-0", "--run",
+		"--level=1", "--raid-devices=2", "/dev/sda", "/dev/sdb",
+	)
+	s.commands.expect("mkfs.ext4", "/dev/md/juju-filesystem-0-0")
+
+	s.setUpComposition(storage.CompositionRAID1)
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+		Size:   2,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+	c.Assert(results[0].Filesystem.Size, gc.Equals, uint64(2))
+}
+
+func (s *managedfsSuite) TestCreateFilesystemsComposedNotAttached(c *gc.C) {
+	source := s.initSource(c)
+	s.setUpComposition(storage.CompositionRAID0)
+	delete(s.blockDevices, names.NewVolumeTag("1"))
+	results, err := source.CreateFilesystems(s.callCtx, []storage.FilesystemParams{{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+		Size:   4,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "backing-volume 1 is not yet attached")
+}
+
+func (s *managedfsSuite) TestResizeFilesystemsComposed(c *gc.C) {
+	source := s.initSource(c)
+	s.setUpComposition(storage.CompositionLVM)
+	resizer, ok := source.(storage.FilesystemResizer)
+	c.Assert(ok, jc.IsTrue)
+	results, err := resizer.ResizeFilesystems(s.callCtx, []storage.FilesystemResizeParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		Volume:       names.NewVolumeTag("0"),
+		FilesystemId: "filesystem-0-0",
+		Size:         8,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results[0].Error, gc.ErrorMatches, "resizing composed filesystems not supported")
+}
+
+func (s *managedfsSuite) setUpComposition(compositionType storage.CompositionType) {
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       2,
+	}
+	s.blockDevices[names.NewVolumeTag("1")] = storage.BlockDevice{
+		DeviceName: "sdb",
+		Size:       2,
+	}
+	s.compositions[names.NewFilesystemTag("0/0")] = storage.FilesystemComposition{
+		Type:    compositionType,
+		Volumes: []names.VolumeTag{names.NewVolumeTag("0"), names.NewVolumeTag("1")},
+	}
+}
+
 const testMountPoint = "/in/the/place"
 
+func (s *managedfsSuite) TestAttachFilesystemsComposed(c *gc.C) {
+	mtabEntry := fmt.Sprintf("/dev/md/juju-filesystem-0-0 %s other mtab stuff", testMountPoint)
+	err := ioutil.WriteFile(filepath.Join(s.fakeEtcDir, "mtab"), []byte(mtabEntry), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	source := s.initSource(c)
+	s.commands.expect("mdadm", "--assemble", "/dev/md/juju-filesystem-0-0", "/dev/sda", "/dev/sdb")
+	cmd := s.commands.expect("df", "--output=source", filepath.Dir(testMountPoint))
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	cmd = s.commands.expect("df", "--output=source", testMountPoint)
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	s.commands.expect("mount", "/dev/md/juju-filesystem-0-0", testMountPoint)
+
+	s.setUpComposition(storage.CompositionRAID1)
+	s.filesystems[names.NewFilesystemTag("0/0")] = storage.Filesystem{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+	}
+
+	results, err := source.AttachFilesystems(s.callCtx, []storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		FilesystemId: "filesystem-0-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-ance",
+		},
+		Path: testMountPoint,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	// The device is assembled by the storage provisioner,
+	// so the filesystem must not be mounted at boot.
+	_, err = os.Stat(filepath.Join(s.fakeEtcDir, "fstab"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
+func (s *managedfsSuite) TestAttachFilesystemsEncrypted(c *gc.C) {
+	mtabEntry := fmt.Sprintf("/dev/mapper/juju-volume-0 %s other mtab stuff", testMountPoint)
+	err := ioutil.WriteFile(filepath.Join(s.fakeEtcDir, "mtab"), []byte(mtabEntry), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	source := s.initSource(c)
+	keyFile := filepath.Join(s.fakeEtcDir, "juju-volume-0.key")
+	s.commands.expect("cryptsetup", "open", "--type", "luks", "--key-file", keyFile, "/dev/sda1", "juju-volume-0")
+	cmd := s.commands.expect("df", "--output=source", filepath.Dir(testMountPoint))
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	cmd = s.commands.expect("df", "--output=source", testMountPoint)
+	cmd.respond("headers\n/same/as/rootfs", nil)
+	s.commands.expect("mount", "/dev/mapper/juju-volume-0", testMountPoint)
+
+	s.blockDevices[names.NewVolumeTag("0")] = storage.BlockDevice{
+		DeviceName: "sda",
+		Size:       2,
+	}
+	s.filesystems[names.NewFilesystemTag("0/0")] = storage.Filesystem{
+		Tag:    names.NewFilesystemTag("0/0"),
+		Volume: names.NewVolumeTag("0"),
+	}
+	s.keys[names.NewVolumeTag("0")] = "sekrit"
+
+	results, err := source.AttachFilesystems(s.callCtx, []storage.FilesystemAttachmentParams{{
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		FilesystemId: "filesystem-0-0",
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "inst-ance",
+		},
+		Path: testMountPoint,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Error, jc.ErrorIsNil)
+
+	// The key is not stored on the machine, so the
+	// filesystem must not be mounted at boot.
+	_, err = os.Stat(filepath.Join(s.fakeEtcDir, "fstab"))
+	c.Assert(err, jc.Satisfies, os.IsNotExist)
+}
+
 func (s *managedfsSuite) TestAttachFilesystems(c *gc.C) {
 	nonRelatedFstabEntry := "/dev/foo /mount/point stuff"
 	err := ioutil.WriteFile(filepath.Join(s.fakeEtcDir, "fstab"), []byte(nonRelatedFstabEntry), 0644)
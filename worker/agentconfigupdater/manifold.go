@@ -12,6 +12,7 @@ import (
 	coreagent "github.com/juju/juju/agent"
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/mongo"
 	jworker "github.com/juju/juju/worker"
 )
@@ -31,6 +32,7 @@ type ManifoldConfig struct {
 	AgentName      string
 	APICallerName  string
 	CentralHubName string
+	Tracer         trace.Tracer
 	Logger         Logger
 }
 
@@ -140,6 +142,7 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				Agent:        agent,
 				Hub:          hub,
 				MongoProfile: configMongoMemoryProfile,
+				Tracer:       config.Tracer,
 				Logger:       config.Logger,
 			})
 		},
@@ -4,6 +4,7 @@
 package agentconfigupdater
 
 import (
+	"context"
 	"time"
 
 	"github.com/juju/errors"
@@ -12,6 +13,7 @@ import (
 	"gopkg.in/tomb.v2"
 
 	coreagent "github.com/juju/juju/agent"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/mongo"
 	controllermsg "github.com/juju/juju/pubsub/controller"
 	jworker "github.com/juju/juju/worker"
@@ -24,6 +26,10 @@ type WorkerConfig struct {
 	Hub          *pubsub.StructuredHub
 	MongoProfile mongo.MemoryProfile
 	Logger       Logger
+
+	// Tracer, if non-nil, is used to record the handling of config
+	// changes as part of the trace of the request that made them.
+	Tracer trace.Tracer
 }
 
 // Validate ensures that the required values are set in the structure.
@@ -53,6 +59,9 @@ func NewWorker(config WorkerConfig) (worker.Worker, error) {
 		return nil, errors.Trace(err)
 	}
 
+	if config.Tracer == nil {
+		config.Tracer = trace.NoopTracer{}
+	}
 	started := make(chan struct{})
 	w := &agentConfigUpdater{
 		config:       config,
@@ -89,6 +98,11 @@ func (w *agentConfigUpdater) onConfigChanged(topic string, data controllermsg.Co
 		w.config.Logger.Criticalf("programming error in %s message data: %v", topic, err)
 		return
 	}
+	ctx := trace.ContextWithTraceParent(context.Background(), data.TraceParent)
+	_, span := w.config.Tracer.Start(ctx, "agentconfigupdater.config-changed", trace.SpanKindConsumer,
+		trace.StringAttr("messaging.destination", topic),
+	)
+	defer span.End()
 
 	mongoProfile := mongo.MemoryProfile(data.Config.MongoMemoryProfile())
 	if mongoProfile == w.mongoProfile {
@@ -102,6 +116,7 @@ func (w *agentConfigUpdater) onConfigChanged(topic string, data controllermsg.Co
 		return nil
 	})
 	if err != nil {
+		span.RecordError(err)
 		w.tomb.Kill(errors.Annotate(err, "failed to update agent config"))
 		return
 	}
@@ -6,6 +6,7 @@ package agentconfigupdater_test
 import (
 	"time"
 
+	"github.com/juju/clock/testclock"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/pubsub"
@@ -15,6 +16,7 @@ import (
 	"gopkg.in/juju/worker.v1/workertest"
 
 	"github.com/juju/juju/controller"
+	"github.com/juju/juju/core/trace"
 	controllermsg "github.com/juju/juju/pubsub/controller"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/agentconfigupdater"
@@ -145,3 +147,32 @@ func (s *WorkerSuite) TestUpdateMongoProfile(c *gc.C) {
 
 	c.Assert(err, gc.Equals, jworker.ErrRestartAgent)
 }
+
+func (s *WorkerSuite) TestConfigChangedContinuesTrace(c *gc.C) {
+	exporter := &trace.MemoryExporter{}
+	config := s.config
+	config.Tracer = trace.NewTracer(exporter, testclock.NewClock(time.Time{}))
+	w, err := agentconfigupdater.NewWorker(config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	handled, err := s.hub.Publish(controllermsg.ConfigChanged, controllermsg.ConfigChangedMessage{
+		Config: controller.Config{
+			controller.MongoMemoryProfile: controller.DefaultMongoMemoryProfile,
+		},
+		TraceParent: traceParent,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case <-handled:
+	case <-time.After(testing.LongWait):
+		c.Fatalf("event not handled")
+	}
+
+	spans := exporter.Spans()
+	c.Assert(spans, gc.HasLen, 1)
+	c.Check(spans[0].Kind, gc.Equals, trace.SpanKindConsumer)
+	c.Check(spans[0].SpanContext.TraceID.String(), gc.Equals, "4bf92f3577b34da6a3ce929d0e0e4736")
+	c.Check(spans[0].ParentSpanID.String(), gc.Equals, "00f067aa0ba902b7")
+}
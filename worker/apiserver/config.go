@@ -96,6 +96,42 @@ func getRateLimitConfig(cfg agent.Config) (apiserver.RateLimitConfig, error) {
 		}
 		result.ConnUpperThreshold = val
 	}
+	if v := cfg.Value(agent.AgentUserRequestRateBurst); v != "" {
+		val, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentUserRequestRateBurst,
+			)
+		}
+		result.UserRequestRateBurst = val
+	}
+	if v := cfg.Value(agent.AgentUserRequestRateRefill); v != "" {
+		val, err := time.ParseDuration(v)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentUserRequestRateRefill,
+			)
+		}
+		result.UserRequestRateRefill = val
+	}
+	if v := cfg.Value(agent.AgentModelRequestRateBurst); v != "" {
+		val, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentModelRequestRateBurst,
+			)
+		}
+		result.ModelRequestRateBurst = val
+	}
+	if v := cfg.Value(agent.AgentModelRequestRateRefill); v != "" {
+		val, err := time.ParseDuration(v)
+		if err != nil {
+			return apiserver.RateLimitConfig{}, errors.Annotatef(
+				err, "parsing %s", agent.AgentModelRequestRateRefill,
+			)
+		}
+		result.ModelRequestRateRefill = val
+	}
 	return result, nil
 }
 
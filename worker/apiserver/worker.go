@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/core/cache"
 	"github.com/juju/juju/core/lease"
 	"github.com/juju/juju/core/presence"
+	"github.com/juju/juju/core/trace"
 	"github.com/juju/juju/state"
 )
 
@@ -42,6 +43,10 @@ type Config struct {
 	GetAuditConfig                    func() auditlog.Config
 	NewServer                         NewServerFunc
 	MetricsCollector                  *apiserver.Collector
+
+	// Tracer, if non-nil, is used to record a trace
+	// span for each API request.
+	Tracer trace.Tracer
 }
 
 // NewServerFunc is the type of function that will be used
@@ -149,6 +154,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		LogSinkConfig:                 &logSinkConfig,
 		GetAuditConfig:                config.GetAuditConfig,
 		LeaseManager:                  config.LeaseManager,
+		Tracer:                        config.Tracer,
 	}
 	return config.NewServer(serverConfig)
 }
@@ -0,0 +1,141 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1"
+	dt "gopkg.in/juju/worker.v1/dependency/testing"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker/egressfirewaller"
+)
+
+type ManifoldSuite struct {
+	testing.IsolationSuite
+	config egressfirewaller.ManifoldConfig
+}
+
+var _ = gc.Suite(&ManifoldSuite{})
+
+func (s *ManifoldSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.config = egressfirewaller.ManifoldConfig{
+		AgentName:     "agent",
+		APICallerName: "api-caller",
+		Clock:         clock.WallClock,
+		CheckInterval: time.Minute,
+		Logger:        loggo.GetLogger("test"),
+		NewFacade:     func(base.APICaller) egressfirewaller.Facade { return &fakeFacade{} },
+		NewWorker:     func(egressfirewaller.Config) (worker.Worker, error) { return nil, nil },
+	}
+}
+
+func (s *ManifoldSuite) TestValid(c *gc.C) {
+	c.Check(s.config.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ManifoldSuite) TestMissingAgentName(c *gc.C) {
+	s.config.AgentName = ""
+	s.checkNotValid(c, "empty AgentName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingAPICallerName(c *gc.C) {
+	s.config.APICallerName = ""
+	s.checkNotValid(c, "empty APICallerName not valid")
+}
+
+func (s *ManifoldSuite) TestMissingClock(c *gc.C) {
+	s.config.Clock = nil
+	s.checkNotValid(c, "nil Clock not valid")
+}
+
+func (s *ManifoldSuite) TestMissingLogger(c *gc.C) {
+	s.config.Logger = nil
+	s.checkNotValid(c, "nil Logger not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewFacade(c *gc.C) {
+	s.config.NewFacade = nil
+	s.checkNotValid(c, "nil NewFacade not valid")
+}
+
+func (s *ManifoldSuite) TestMissingNewWorker(c *gc.C) {
+	s.config.NewWorker = nil
+	s.checkNotValid(c, "nil NewWorker not valid")
+}
+
+func (s *ManifoldSuite) checkNotValid(c *gc.C, expect string) {
+	err := s.config.Validate()
+	c.Check(err, gc.ErrorMatches, expect)
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ManifoldSuite) TestInputs(c *gc.C) {
+	manifold := egressfirewaller.Manifold(s.config)
+	c.Check(manifold.Inputs, jc.DeepEquals, []string{"agent", "api-caller"})
+}
+
+func (s *ManifoldSuite) TestStart(c *gc.C) {
+	facade := &fakeFacade{}
+	s.config.NewFacade = func(base.APICaller) egressfirewaller.Facade {
+		return facade
+	}
+	var config egressfirewaller.Config
+	s.config.NewWorker = func(c egressfirewaller.Config) (worker.Worker, error) {
+		config = c
+		return &fakeWorker{}, nil
+	}
+	manifold := egressfirewaller.Manifold(s.config)
+	w, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"agent":      &fakeAgent{tag: names.NewMachineTag("42")},
+		"api-caller": struct{ base.APICaller }{},
+	}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(w, gc.FitsTypeOf, &fakeWorker{})
+	c.Check(config.Facade, gc.Equals, facade)
+	c.Check(config.Tag, gc.Equals, names.NewMachineTag("42"))
+	c.Check(config.CheckInterval, gc.Equals, time.Minute)
+	c.Check(config.RunCommand, gc.NotNil)
+}
+
+func (s *ManifoldSuite) TestStartUnitAgent(c *gc.C) {
+	manifold := egressfirewaller.Manifold(s.config)
+	_, err := manifold.Start(dt.StubContext(nil, map[string]interface{}{
+		"agent":      &fakeAgent{tag: names.NewUnitTag("mysql/0")},
+		"api-caller": struct{ base.APICaller }{},
+	}))
+	c.Assert(err, gc.ErrorMatches, "egressfirewaller may only be used with a machine agent")
+}
+
+type fakeWorker struct {
+	worker.Worker
+}
+
+type fakeAgent struct {
+	agent.Agent
+	tag names.Tag
+}
+
+func (a *fakeAgent) CurrentConfig() agent.Config {
+	return &fakeConfig{tag: a.tag}
+}
+
+type fakeConfig struct {
+	agent.Config
+	tag names.Tag
+}
+
+func (c *fakeConfig) Tag() names.Tag {
+	return c.tag
+}
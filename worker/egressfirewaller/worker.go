@@ -0,0 +1,204 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"github.com/juju/utils/exec"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/network/iptables"
+)
+
+// listRulesCommand lists the rules of the OUTPUT chain in the format
+// expected by iptables.ParseEgressRules. It never prompts for a
+// password, so that the worker is not blocked where it cannot use sudo.
+const listRulesCommand = "sudo -n iptables -S OUTPUT"
+
+// Logger defines the methods used by the egress firewaller for logging.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Infof(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+// Facade defines the API methods used by the egress firewaller.
+type Facade interface {
+	EgressCIDRs(names.MachineTag) ([]string, error)
+}
+
+// Config holds the configuration and dependencies for an egress
+// firewaller worker.
+type Config struct {
+	Facade        Facade
+	Tag           names.MachineTag
+	Clock         clock.Clock
+	Logger        Logger
+	CheckInterval time.Duration
+
+	// RunCommand runs the given bash script on the machine,
+	// returning its standard output.
+	RunCommand func(string) (string, error)
+}
+
+// Validate returns an error if the config cannot be used to start an
+// egress firewaller.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Tag.Id() == "" {
+		return errors.NotValidf("empty Tag")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.CheckInterval <= 0 {
+		return errors.NotValidf("non-positive CheckInterval")
+	}
+	if config.RunCommand == nil {
+		return errors.NotValidf("nil RunCommand")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that periodically gets the egress policy
+// the machine agent must enforce, and applies it to the machine with
+// iptables rules. It is used where the firewaller cannot restrict the
+// outbound traffic of the machine through the provider, such as on
+// containers and manual machines.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &egressWorker{
+		config: config,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type egressWorker struct {
+	catacomb catacomb.Catacomb
+	config   Config
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *egressWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *egressWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *egressWorker) loop() error {
+	// The first check happens immediately, so that a restarted
+	// machine is restricted again as soon as possible.
+	timer := w.config.Clock.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-timer.Chan():
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+			timer.Reset(w.config.CheckInterval)
+		}
+	}
+}
+
+func (w *egressWorker) check() error {
+	want, err := w.config.Facade.EgressCIDRs(w.config.Tag)
+	if err != nil {
+		return errors.Annotate(err, "getting egress CIDRs")
+	}
+	// ParseEgressRules returns sorted CIDRs.
+	sort.Strings(want)
+	out, err := w.config.RunCommand(listRulesCommand)
+	if err != nil {
+		// Machines without iptables or sudo cannot have been restricted,
+		// so this only matters if they need to be.
+		if want == nil {
+			w.config.Logger.Debugf("cannot list iptables rules: %v", err)
+		} else {
+			w.config.Logger.Errorf("cannot list iptables rules: %v", err)
+		}
+		return nil
+	}
+	have, err := iptables.ParseEgressRules(strings.NewReader(out))
+	if err != nil {
+		w.config.Logger.Errorf("cannot parse iptables rules: %v", err)
+		return nil
+	}
+	if sameCIDRs(have, want) {
+		return nil
+	}
+	commands := []string{iptables.FlushEgressCommand{}.Render()}
+	if want != nil {
+		for _, cidr := range want {
+			commands = append(commands, iptables.EgressRuleCommand{DestinationCIDR: cidr}.Render())
+		}
+		commands = append(commands, iptables.EgressDropCommand{}.Render())
+	}
+	if _, err := w.config.RunCommand(strings.Join(commands, "\n")); err != nil {
+		// The rules are applied again at the next check.
+		w.config.Logger.Errorf("cannot set egress rules: %v", err)
+		return nil
+	}
+	if want == nil {
+		w.config.Logger.Infof("removed egress restrictions")
+	} else {
+		w.config.Logger.Infof("restricted egress to %v", want)
+	}
+	return nil
+}
+
+// sameCIDRs reports whether both egress policies are the same, where
+// nil means unrestricted and is different from an empty slice.
+func sameCIDRs(have, want []string) bool {
+	if (have == nil) != (want == nil) || len(have) != len(want) {
+		return false
+	}
+	for i := range have {
+		if have[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RunCommand runs the given bash script, returning its standard output
+// or an error if it fails.
+func RunCommand(script string) (string, error) {
+	result, err := exec.RunCommands(exec.RunParams{
+		Commands: script,
+	})
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if result.Code != 0 {
+		return "", errors.Errorf("exit code %d: %s", result.Code, strings.TrimSpace(string(result.Stderr)))
+	}
+	return string(result.Stdout), nil
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package egressfirewaller_test
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	"github.com/juju/juju/network/iptables"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/egressfirewaller"
+)
+
+const (
+	listRules = "sudo -n iptables -S OUTPUT"
+
+	unrestrictedRules = `-P OUTPUT ACCEPT
+`
+	restrictedRules = `-P OUTPUT ACCEPT
+-A OUTPUT -d 10.0.0.0/8 -m comment --comment "juju egress" -j ACCEPT
+-A OUTPUT -o lo -m comment --comment "juju egress" -j ACCEPT
+-A OUTPUT -m state --state NEW -m comment --comment "juju egress" -j DROP
+`
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock  *testclock.Clock
+	facade *fakeFacade
+	runner *fakeRunner
+	config egressfirewaller.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Time{})
+	s.facade = &fakeFacade{}
+	s.runner = &fakeRunner{rules: unrestrictedRules}
+	s.config = egressfirewaller.Config{
+		Facade:        s.facade,
+		Tag:           names.NewMachineTag("0"),
+		Clock:         s.clock,
+		Logger:        loggo.GetLogger("test"),
+		CheckInterval: time.Minute,
+		RunCommand:    s.runner.RunCommand,
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	s.config.RunCommand = nil
+	_, err := egressfirewaller.NewWorker(s.config)
+	c.Assert(err, gc.ErrorMatches, "nil RunCommand not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *WorkerSuite) TestRestricts(c *gc.C) {
+	s.facade.cidrs = [][]string{{"192.168.0.0/16", "10.0.0.0/8"}}
+
+	s.runWorker(c)
+
+	s.facade.CheckCalls(c, []testing.StubCall{
+		{"EgressCIDRs", []interface{}{names.NewMachineTag("0")}},
+	})
+	s.runner.CheckCalls(c, []testing.StubCall{
+		{"RunCommand", []interface{}{listRules}},
+		{"RunCommand", []interface{}{strings.Join([]string{
+			iptables.FlushEgressCommand{}.Render(),
+			iptables.EgressRuleCommand{DestinationCIDR: "10.0.0.0/8"}.Render(),
+			iptables.EgressRuleCommand{DestinationCIDR: "192.168.0.0/16"}.Render(),
+			iptables.EgressDropCommand{}.Render(),
+		}, "\n")}},
+	})
+}
+
+func (s *WorkerSuite) TestRestrictsToNothing(c *gc.C) {
+	s.facade.cidrs = [][]string{{}}
+
+	s.runWorker(c)
+
+	s.runner.CheckCalls(c, []testing.StubCall{
+		{"RunCommand", []interface{}{listRules}},
+		{"RunCommand", []interface{}{strings.Join([]string{
+			iptables.FlushEgressCommand{}.Render(),
+			iptables.EgressDropCommand{}.Render(),
+		}, "\n")}},
+	})
+}
+
+func (s *WorkerSuite) TestAlreadyRestricted(c *gc.C) {
+	s.facade.cidrs = [][]string{{"10.0.0.0/8"}}
+	s.runner.rules = restrictedRules
+
+	s.runWorker(c)
+
+	s.runner.CheckCalls(c, []testing.StubCall{
+		{"RunCommand", []interface{}{listRules}},
+	})
+}
+
+func (s *WorkerSuite) TestRemovesRestriction(c *gc.C) {
+	s.runner.rules = restrictedRules
+
+	s.runWorker(c)
+
+	s.runner.CheckCalls(c, []testing.StubCall{
+		{"RunCommand", []interface{}{listRules}},
+		{"RunCommand", []interface{}{iptables.FlushEgressCommand{}.Render()}},
+	})
+}
+
+func (s *WorkerSuite) TestUnrestrictedWithoutIptables(c *gc.C) {
+	s.runner.SetErrors(errors.New("sudo: iptables: command not found"))
+
+	s.runWorker(c)
+
+	s.runner.CheckCallNames(c, "RunCommand")
+}
+
+func (s *WorkerSuite) TestChecksPeriodically(c *gc.C) {
+	s.facade.cidrs = [][]string{nil, {"10.0.0.0/8"}}
+
+	w, err := egressfirewaller.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 0)
+	s.advance(c, 0)
+	s.runner.CheckCallNames(c, "RunCommand")
+
+	s.advance(c, time.Minute)
+	s.advance(c, 0)
+	s.facade.CheckCallNames(c, "EgressCIDRs", "EgressCIDRs")
+	s.runner.CheckCallNames(c, "RunCommand", "RunCommand", "RunCommand")
+}
+
+func (s *WorkerSuite) TestSetRulesErrorRetries(c *gc.C) {
+	s.facade.cidrs = [][]string{{"10.0.0.0/8"}, {"10.0.0.0/8"}}
+	s.runner.SetErrors(nil, errors.New("boom"))
+
+	w, err := egressfirewaller.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 0)
+	s.advance(c, time.Minute)
+	s.advance(c, 0)
+
+	s.runner.CheckCallNames(c, "RunCommand", "RunCommand", "RunCommand", "RunCommand")
+}
+
+func (s *WorkerSuite) TestEgressCIDRsError(c *gc.C) {
+	s.facade.SetErrors(errors.New("boom"))
+
+	w, err := egressfirewaller.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting egress CIDRs: boom")
+}
+
+// runWorker starts the worker and waits for it to complete its
+// first check.
+func (s *WorkerSuite) runWorker(c *gc.C) {
+	w, err := egressfirewaller.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 0)
+	s.advance(c, 0)
+}
+
+// advance waits for the worker to be waiting for its next check,
+// and then advances the clock.
+func (s *WorkerSuite) advance(c *gc.C, d time.Duration) {
+	err := s.clock.WaitAdvance(d, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type fakeFacade struct {
+	testing.Stub
+
+	mu    sync.Mutex
+	cidrs [][]string
+}
+
+func (f *fakeFacade) EgressCIDRs(tag names.MachineTag) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.AddCall("EgressCIDRs", tag)
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	var cidrs []string
+	if len(f.cidrs) > 0 {
+		cidrs, f.cidrs = f.cidrs[0], f.cidrs[1:]
+	}
+	return cidrs, nil
+}
+
+type fakeRunner struct {
+	testing.Stub
+
+	mu    sync.Mutex
+	rules string
+}
+
+func (r *fakeRunner) RunCommand(script string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.AddCall("RunCommand", script)
+	if err := r.NextErr(); err != nil {
+		return "", err
+	}
+	if script == listRules {
+		return r.rules, nil
+	}
+	return "", nil
+}
@@ -5,6 +5,7 @@ package firewaller
 
 import (
 	"io"
+	"reflect"
 	"strings"
 	"time"
 
@@ -124,7 +125,14 @@ func (cfg Config) Validate() error {
 	return nil
 }
 
-type portRanges map[corenetwork.PortRange]bool
+// endpointPortRange is a port range opened for an endpoint of a unit.
+// The empty endpoint name refers to all of the unit's endpoints.
+type endpointPortRange struct {
+	endpoint  string
+	portRange corenetwork.PortRange
+}
+
+type portRanges map[endpointPortRange]bool
 
 // Firewaller watches the state for port ranges opened or closed on
 // machines and reflects those changes onto the backing environment.
@@ -143,6 +151,8 @@ type Firewaller struct {
 	unitds               map[names.UnitTag]*unitData
 	applicationids       map[names.ApplicationTag]*applicationData
 	exposedChange        chan *exposedChange
+	egressChange         chan *egressChange
+	egressRetry          <-chan time.Time
 	globalMode           bool
 	globalIngressRuleRef map[string]int // map of rule names to count of occurrences
 
@@ -180,6 +190,7 @@ func NewFirewaller(cfg Config) (worker.Worker, error) {
 		unitds:                     make(map[names.UnitTag]*unitData),
 		applicationids:             make(map[names.ApplicationTag]*applicationData),
 		exposedChange:              make(chan *exposedChange),
+		egressChange:               make(chan *egressChange),
 		relationIngress:            make(map[names.RelationTag]*remoteRelationData),
 		localRelationsChange:       make(chan *remoteRelationNetworkChange),
 		pollClock:                  clk,
@@ -312,7 +323,7 @@ func (fw *Firewaller) loop() error {
 				return errors.Trace(err)
 			}
 		case change := <-fw.exposedChange:
-			change.applicationd.exposed = change.exposed
+			change.applicationd.exposedCIDRs = change.exposedCIDRs
 			unitds := []*unitData{}
 			for _, unitd := range change.applicationd.unitds {
 				unitds = append(unitds, unitd)
@@ -320,6 +331,20 @@ func (fw *Firewaller) loop() error {
 			if err := fw.flushUnits(unitds); err != nil {
 				return errors.Annotate(err, "cannot change firewall ports")
 			}
+		case change := <-fw.egressChange:
+			change.applicationd.egressCIDRs = change.cidrs
+			unitds := []*unitData{}
+			for _, unitd := range change.applicationd.unitds {
+				unitds = append(unitds, unitd)
+			}
+			if err := fw.flushUnits(unitds); err != nil {
+				return errors.Annotate(err, "cannot change egress restriction")
+			}
+		case <-fw.egressRetry:
+			fw.egressRetry = nil
+			if err := fw.retryPendingEgress(); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
@@ -477,22 +502,27 @@ func (fw *Firewaller) startUnit(unit *firewaller.Unit, machineTag names.MachineT
 // startApplication creates a new data value for tracking details of the
 // application and starts watching the application for exposure changes.
 func (fw *Firewaller) startApplication(app *firewaller.Application) error {
-	exposed, err := app.IsExposed()
+	exposedCIDRs, err := app.ExposedCIDRs()
+	if err != nil {
+		return err
+	}
+	egressCIDRs, err := app.EgressCIDRs()
 	if err != nil {
 		return err
 	}
 	applicationd := &applicationData{
-		fw:          fw,
-		application: app,
-		exposed:     exposed,
-		unitds:      make(map[names.UnitTag]*unitData),
+		fw:           fw,
+		application:  app,
+		exposedCIDRs: exposedCIDRs,
+		egressCIDRs:  egressCIDRs,
+		unitds:       make(map[names.UnitTag]*unitData),
 	}
 	fw.applicationids[app.Tag()] = applicationd
 
 	err = catacomb.Invoke(catacomb.Plan{
 		Site: &applicationd.catacomb,
 		Work: func() error {
-			return applicationd.watchLoop(exposed)
+			return applicationd.watchLoop(exposedCIDRs, egressCIDRs)
 		},
 	})
 	if err != nil {
@@ -575,6 +605,13 @@ func (fw *Firewaller) reconcileInstances() error {
 			return nil
 		}
 
+		if egressInstance, ok := envInstances[0].(instances.InstanceEgressFirewaller); ok {
+			machined.egressCIDRs, err = egressInstance.EgressCIDRs(fw.cloudCallContext, machineId)
+			if err != nil {
+				return err
+			}
+		}
+
 		initialRules, err := fwInstance.IngressRules(fw.cloudCallContext, machineId)
 		if err != nil {
 			return err
@@ -598,6 +635,9 @@ func (fw *Firewaller) reconcileInstances() error {
 				return err
 			}
 		}
+		if err := fw.flushInstanceEgress(machined); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -667,13 +707,14 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 		return err
 	}
 
-	ports, err := m.OpenedPorts(subnetTag)
+	openedRanges, err := m.OpenedPortRanges(subnetTag)
 	if err != nil {
 		return err
 	}
 
 	newPortRanges := make(map[names.UnitTag]portRanges)
-	for portRange, unitTag := range ports {
+	for _, opened := range openedRanges {
+		unitTag := opened.UnitTag
 		unitd, ok := machined.unitds[unitTag]
 		if !ok {
 			// It is common to receive port change notification before
@@ -687,7 +728,10 @@ func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, subnetTag
 			ranges = make(portRanges)
 			newPortRanges[unitd.tag] = ranges
 		}
-		ranges[portRange] = true
+		ranges[endpointPortRange{
+			endpoint:  opened.Endpoint,
+			portRange: opened.PortRange,
+		}] = true
 	}
 
 	if !unitPortsEqual(machined.definedPorts, newPortRanges) {
@@ -752,9 +796,32 @@ func (fw *Firewaller) flushMachine(machined *machineData) error {
 	toOpen, toClose := diffRanges(machined.ingressRules, want)
 	machined.ingressRules = want
 	if fw.globalMode {
+		// The machine agent restricts egress in the global mode.
 		return fw.flushGlobalPorts(toOpen, toClose)
 	}
-	return fw.flushInstancePorts(machined, toOpen, toClose)
+	if err := fw.flushInstancePorts(machined, toOpen, toClose); err != nil {
+		return errors.Trace(err)
+	}
+	return fw.flushInstanceEgress(machined)
+}
+
+// gatherEgressCIDRs returns the CIDRs to which the outbound traffic of
+// the specified machine should be restricted. A machine is only
+// restricted if every application with a unit on it is, in which case
+// it may reach the destinations of any of them; nil is returned if the
+// machine should be unrestricted.
+func (fw *Firewaller) gatherEgressCIDRs(machined *machineData) []string {
+	if len(machined.unitds) == 0 {
+		return nil
+	}
+	cidrs := set.NewStrings()
+	for _, unitd := range machined.unitds {
+		if unitd.applicationd.egressCIDRs == nil {
+			return nil
+		}
+		cidrs = cidrs.Union(set.NewStrings(unitd.applicationd.egressCIDRs...))
+	}
+	return cidrs.SortedValues()
 }
 
 // gatherIngressRules returns the ingress rules to open and close
@@ -769,32 +836,63 @@ func (fw *Firewaller) gatherIngressRules(machines ...*machineData) ([]network.In
 				continue
 			}
 
-			cidrs := set.NewStrings()
-			// If the unit is exposed, allow access from everywhere.
-			if unitd.applicationd.exposed {
-				cidrs.Add("0.0.0.0/0")
+			rangeCIDRs := make(map[corenetwork.PortRange]set.Strings)
+			if exposedCIDRs := unitd.applicationd.exposedCIDRs; exposedCIDRs != nil {
+				// The unit is exposed, so allow access to each range
+				// from the networks its endpoint is exposed to.
+				for opened := range portRanges {
+					cidrs, ok := rangeCIDRs[opened.portRange]
+					if !ok {
+						cidrs = set.NewStrings()
+						rangeCIDRs[opened.portRange] = cidrs
+					}
+					for _, cidr := range endpointCIDRs(exposedCIDRs, opened.endpoint) {
+						cidrs.Add(cidr)
+					}
+				}
 			} else {
 				// Not exposed, so add any ingress rules required by remote relations.
+				cidrs := set.NewStrings()
 				if err := fw.updateForRemoteRelationIngress(unitd.applicationd.application.Tag(), cidrs); err != nil {
 					return nil, errors.Trace(err)
 				}
 				fw.logger.Debugf("CIDRS for %v: %v", unitTag, cidrs.Values())
+				for opened := range portRanges {
+					rangeCIDRs[opened.portRange] = cidrs
+				}
 			}
-			if cidrs.Size() > 0 {
-				for portRange := range portRanges {
-					sourceCidrs := cidrs.SortedValues()
-					rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, sourceCidrs...)
-					if err != nil {
-						return nil, errors.Trace(err)
-					}
-					want = append(want, rule)
+			for portRange, cidrs := range rangeCIDRs {
+				if cidrs.Size() == 0 {
+					continue
 				}
+				rule, err := network.NewIngressRule(portRange.Protocol, portRange.FromPort, portRange.ToPort, cidrs.SortedValues()...)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				want = append(want, rule)
 			}
 		}
 	}
 	return want, nil
 }
 
+// endpointCIDRs returns the CIDRs from which a port range opened for the
+// given endpoint may be reached, given the exposed CIDRs of each of the
+// application's endpoints. The expose settings of all endpoints apply
+// to the ranges opened for all endpoints, while the settings given for
+// all endpoints apply to every range.
+func endpointCIDRs(exposedCIDRs map[string][]string, endpoint string) []string {
+	if endpoint == "" {
+		var cidrs []string
+		for _, exposed := range exposedCIDRs {
+			cidrs = append(cidrs, exposed...)
+		}
+		return cidrs
+	}
+	cidrs := append([]string(nil), exposedCIDRs[""]...)
+	return append(cidrs, exposedCIDRs[endpoint]...)
+}
+
 // TODO(wallyworld) - consider making this configurable.
 const maxAllowedCIDRS = 20
 
@@ -952,6 +1050,111 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	return nil
 }
 
+// flushInstanceEgress restricts the outbound traffic of the machine's
+// instance to the CIDRs gathered for it, or lifts the restriction.
+// Machines which have not yet been provisioned are retried later.
+func (fw *Firewaller) flushInstanceEgress(machined *machineData) (err error) {
+	defer func() {
+		if params.IsCodeNotFound(err) {
+			err = nil
+		}
+	}()
+
+	want := fw.gatherEgressCIDRs(machined)
+	if !machined.egressPending && egressCIDRsEqual(machined.egressCIDRs, want) {
+		return nil
+	}
+	fw.logger.Debugf("flush instance egress: %v => %v", machined.egressCIDRs, want)
+	m, err := machined.machine()
+	if err != nil {
+		return err
+	}
+	instanceId, err := m.InstanceId()
+	if params.IsCodeNotProvisioned(err) {
+		// Not provisioned yet, so try again once it may have been.
+		machined.egressPending = true
+		fw.scheduleEgressRetry()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	envInstances, err := fw.environInstances.Instances(fw.cloudCallContext, []instance.Id{instanceId})
+	if err == environs.ErrNoInstances {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	machined.egressPending = false
+	fwInstance, ok := envInstances[0].(instances.InstanceEgressFirewaller)
+	if !ok {
+		// The machine agent restricts egress where the provider
+		// cannot.
+		machined.egressCIDRs = want
+		return nil
+	}
+	err = fwInstance.SetEgressCIDRs(fw.cloudCallContext, machined.tag.Id(), want)
+	if errors.IsNotSupported(err) {
+		// Some clouds of a provider cannot restrict egress, such as
+		// EC2-Classic regions or OpenStack clouds without Neutron.
+		// Retrying won't help, so don't fail the firewaller.
+		if want != nil {
+			fw.logger.Warningf("cannot restrict egress of %q: %v", machined.tag, err)
+		}
+		machined.egressCIDRs = want
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	machined.egressCIDRs = want
+	if want == nil {
+		fw.logger.Infof("removed egress restriction on %q", machined.tag)
+	} else {
+		fw.logger.Infof("restricted egress on %q to %v", machined.tag, want)
+	}
+	return nil
+}
+
+// egressRetryDelay is how long the firewaller waits before retrying
+// egress restrictions for machines that were not yet provisioned.
+const egressRetryDelay = 30 * time.Second
+
+// scheduleEgressRetry arranges for retryPendingEgress to be called,
+// unless a retry is already scheduled.
+func (fw *Firewaller) scheduleEgressRetry() {
+	if fw.egressRetry == nil {
+		fw.egressRetry = fw.pollClock.After(egressRetryDelay)
+	}
+}
+
+// retryPendingEgress flushes the egress restrictions of machines whose
+// previous flush could not be applied.
+func (fw *Firewaller) retryPendingEgress() error {
+	for _, machined := range fw.machineds {
+		if !machined.egressPending {
+			continue
+		}
+		if err := fw.flushInstanceEgress(machined); err != nil {
+			return errors.Annotatef(err, "cannot restrict egress of %q", machined.tag)
+		}
+	}
+	return nil
+}
+
+func egressCIDRsEqual(a, b []string) bool {
+	if (a == nil) != (b == nil) || len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -1048,6 +1251,12 @@ type machineData struct {
 	ingressRules []network.IngressRule
 	// ports defined by units on this machine
 	definedPorts map[names.UnitTag]portRanges
+	// egressCIDRs holds the CIDRs to which the machine's outbound
+	// traffic is restricted; nil if it is unrestricted.
+	egressCIDRs []string
+	// egressPending is true if the machine's egress restriction
+	// still needs to be applied to its instance.
+	egressPending bool
 }
 
 func (md *machineData) machine() (*firewaller.Machine, error) {
@@ -1095,10 +1304,16 @@ type unitData struct {
 	machined     *machineData
 }
 
-// exposedChange contains the changed exposed flag for one specific application.
+// exposedChange contains the changed expose settings for one specific application.
 type exposedChange struct {
 	applicationd *applicationData
-	exposed      bool
+	exposedCIDRs map[string][]string
+}
+
+// egressChange contains the changed egress CIDRs for one specific application.
+type egressChange struct {
+	applicationd *applicationData
+	cidrs        []string
 }
 
 // applicationData holds application details and watches exposure changes.
@@ -1106,12 +1321,16 @@ type applicationData struct {
 	catacomb    catacomb.Catacomb
 	fw          *Firewaller
 	application *firewaller.Application
-	exposed     bool
-	unitds      map[names.UnitTag]*unitData
+	// exposedCIDRs holds the networks from which each exposed
+	// endpoint may be reached; nil if the application is not exposed.
+	exposedCIDRs map[string][]string
+	egressCIDRs  []string
+	unitds       map[names.UnitTag]*unitData
 }
 
-// watchLoop watches the application's exposed flag for changes.
-func (ad *applicationData) watchLoop(exposed bool) error {
+// watchLoop watches the application's expose settings and egress
+// restriction for changes.
+func (ad *applicationData) watchLoop(exposedCIDRs map[string][]string, egressCIDRs []string) error {
 	appWatcher, err := ad.application.Watch()
 	if err != nil {
 		if params.IsCodeNotFound(err) {
@@ -1130,25 +1349,45 @@ func (ad *applicationData) watchLoop(exposed bool) error {
 			if !ok {
 				return errors.New("application watcher closed")
 			}
-			change, err := ad.application.IsExposed()
+			change, err := ad.application.ExposedCIDRs()
+			if err != nil {
+				if errors.IsNotFound(err) {
+					ad.fw.logger.Debugf("application(%q).ExposedCIDRs() returned NotFound: %v", ad.application.Name(), err)
+					return nil
+				}
+				return errors.Trace(err)
+			}
+			if reflect.DeepEqual(change, exposedCIDRs) {
+				ad.fw.logger.Tracef("application(%q).ExposedCIDRs() == %v (unchanged)", ad.application.Name(), exposedCIDRs)
+			} else {
+				ad.fw.logger.Tracef("application(%q).ExposedCIDRs() changed %v => %v", ad.application.Name(), exposedCIDRs, change)
+
+				exposedCIDRs = change
+				select {
+				case <-ad.catacomb.Dying():
+					return ad.catacomb.ErrDying()
+				case ad.fw.exposedChange <- &exposedChange{ad, change}:
+				}
+			}
+
+			cidrs, err := ad.application.EgressCIDRs()
 			if err != nil {
 				if errors.IsNotFound(err) {
-					ad.fw.logger.Debugf("application(%q).IsExposed() returned NotFound: %v", ad.application.Name(), err)
+					ad.fw.logger.Debugf("application(%q).EgressCIDRs() returned NotFound: %v", ad.application.Name(), err)
 					return nil
 				}
 				return errors.Trace(err)
 			}
-			if change == exposed {
-				ad.fw.logger.Tracef("application(%q).IsExposed() == %v (unchanged)", ad.application.Name(), exposed)
+			if egressCIDRsEqual(cidrs, egressCIDRs) {
 				continue
 			}
-			ad.fw.logger.Tracef("application(%q).IsExposed() changed %v => %v", ad.application.Name(), exposed, change)
+			ad.fw.logger.Tracef("application(%q).EgressCIDRs() changed %v => %v", ad.application.Name(), egressCIDRs, cidrs)
 
-			exposed = change
+			egressCIDRs = cidrs
 			select {
 			case <-ad.catacomb.Dying():
 				return ad.catacomb.ErrDying()
-			case ad.fw.exposedChange <- &exposedChange{ad, change}:
+			case ad.fw.egressChange <- &egressChange{ad, cidrs}:
 			}
 		}
 	}
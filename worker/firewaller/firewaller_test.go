@@ -11,6 +11,7 @@ import (
 
 	"github.com/juju/clock"
 	"github.com/juju/clock/testclock"
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	jc "github.com/juju/testing/checkers"
@@ -158,6 +159,32 @@ func (s *firewallerBaseSuite) assertEnvironPorts(c *gc.C, expected []network.Ing
 	}
 }
 
+// assertEgress retrieves the egress CIDRs of the instance and compares
+// them to the expected.
+func (s *firewallerBaseSuite) assertEgress(c *gc.C, inst instances.Instance, machineId string, expected []string) {
+	fwInst, ok := inst.(instances.InstanceEgressFirewaller)
+	c.Assert(ok, gc.Equals, true)
+
+	start := time.Now()
+	for {
+		s.BackingState.StartSync()
+		got, err := fwInst.EgressCIDRs(s.callCtx, machineId)
+		if err != nil {
+			c.Fatal(err)
+			return
+		}
+		if reflect.DeepEqual(got, expected) {
+			c.Succeed()
+			return
+		}
+		if time.Since(start) > coretesting.LongWait {
+			c.Fatalf("timed out: expected %q; got %q", expected, got)
+			return
+		}
+		time.Sleep(coretesting.ShortWait)
+	}
+}
+
 func (s *firewallerBaseSuite) addUnit(c *gc.C, app *state.Application) (*state.Unit, *state.Machine) {
 	u, err := app.AddUnit(state.AddUnitParams{})
 	c.Assert(err, jc.ErrorIsNil)
@@ -539,6 +566,139 @@ func (s *InstanceModeSuite) TestSetClearExposedApplication(c *gc.C) {
 	s.assertPorts(c, inst, m.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestExposeToCIDRs(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err := u.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+	})
+
+	// Changing the settings updates the rules.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"10.0.0.0/8", "192.168.0.0/16"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8", "192.168.0.0/16"),
+	})
+
+	err = app.ClearExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestExposeEndpointsToCIDRs(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+
+	u, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+	err := u.OpenPortsForEndpoint("url", "tcp", 80, 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPortsForEndpoint("monitoring-port", "tcp", 8080, 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u.OpenPort("tcp", 443)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Each range may only be reached from the networks its endpoint
+	// is exposed to, while ranges opened for all endpoints may be
+	// reached from the networks of any of them.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"url":             {ExposeToCIDRs: []string{"10.0.0.0/8"}},
+		"monitoring-port": {ExposeToCIDRs: []string{"192.168.0.0/16"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8"),
+		network.MustNewIngressRule("tcp", 443, 443, "10.0.0.0/8", "192.168.0.0/16"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "192.168.0.0/16"),
+	})
+
+	// Settings for all endpoints apply to every range.
+	err = app.MergeExposeSettings(map[string]state.ExposedEndpoint{
+		"": {ExposeToCIDRs: []string{"172.16.0.0/12"}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "10.0.0.0/8", "172.16.0.0/12"),
+		network.MustNewIngressRule("tcp", 443, 443, "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "172.16.0.0/12", "192.168.0.0/16"),
+	})
+
+	// A plain expose removes the settings, allowing any network.
+	err = app.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertPorts(c, inst, m.Id(), []network.IngressRule{
+		network.MustNewIngressRule("tcp", 80, 80, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 443, 443, "0.0.0.0/0"),
+		network.MustNewIngressRule("tcp", 8080, 8080, "0.0.0.0/0"),
+	})
+}
+
+func (s *InstanceModeSuite) TestSetClearEgressPolicy(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+
+	_, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	// No egress policy, so no restriction.
+	s.assertEgress(c, inst, m.Id(), nil)
+
+	err := app.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"10.0.0.0/8"}})
+	c.Assert(err, jc.ErrorIsNil)
+	expected, restricted, err := app.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(restricted, jc.IsTrue)
+	c.Assert(set.NewStrings(expected...).Contains("10.0.0.0/8"), jc.IsTrue)
+
+	s.assertEgress(c, inst, m.Id(), expected)
+
+	err = app.ClearEgressPolicy()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertEgress(c, inst, m.Id(), nil)
+}
+
+func (s *InstanceModeSuite) TestEgressPolicyBeforeProvisioning(c *gc.C) {
+	fw := s.newFirewaller(c)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	app := s.AddTestingApplication(c, "wordpress", s.charm)
+	err := app.SetEgressPolicy(state.EgressPolicy{ToCIDRs: []string{"10.0.0.0/8"}})
+	c.Assert(err, jc.ErrorIsNil)
+	expected, _, err := app.EgressCIDRs()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The restriction is applied once the machine is provisioned.
+	_, m := s.addUnit(c, app)
+	inst := s.startInstance(c, m)
+
+	s.assertEgress(c, inst, m.Id(), expected)
+}
+
 func (s *InstanceModeSuite) TestRemoveUnit(c *gc.C) {
 	fw := s.newFirewaller(c)
 	defer statetesting.AssertKillAndWait(c, fw)
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/juju/api/instancepoller"
 	"github.com/juju/juju/core/watcher"
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/worker/common"
 )
 
@@ -36,6 +37,9 @@ func (s facadeShim) Machine(tag names.MachineTag) (Machine, error) { return s.ap
 func (s facadeShim) WatchModelMachines() (watcher.StringsWatcher, error) {
 	return s.api.WatchModelMachines()
 }
+func (s facadeShim) RegionCloudSpec(tag names.MachineTag) (*environs.CloudSpec, error) {
+	return s.api.RegionCloudSpec(tag)
+}
 
 // ManifoldConfig describes the resources used by the instancepoller worker.
 type ManifoldConfig struct {
@@ -72,9 +76,10 @@ func (config ManifoldConfig) start(context dependency.Context) (worker.Worker, e
 		Facade: facadeShim{
 			api: instancepoller.NewAPI(apiCaller),
 		},
-		Environ:       environ,
-		Logger:        config.Logger,
-		CredentialAPI: credentialAPI,
+		Environ:          environ,
+		NewRegionEnviron: newRegionEnvironFunc(environ),
+		Logger:           config.Logger,
+		CredentialAPI:    credentialAPI,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -93,3 +98,22 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 		Start: config.start,
 	}
 }
+
+// newRegionEnvironFunc returns a function that opens environs for other
+// cloud regions, using the config of the model's environ.
+func newRegionEnvironFunc(environ environs.Environ) func(environs.CloudSpec) (Environ, error) {
+	return func(spec environs.CloudSpec) (Environ, error) {
+		cfg := environ.Config()
+		if spec.Type != cfg.Type() {
+			// The region is in a cloud of another type.
+			var err error
+			if cfg, err = cfg.Apply(map[string]interface{}{config.TypeKey: spec.Type}); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return environs.New(environs.OpenParams{
+			Cloud:  spec,
+			Config: cfg,
+		})
+	}
+}
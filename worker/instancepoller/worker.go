@@ -4,6 +4,8 @@
 package instancepoller
 
 import (
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/juju/clock"
@@ -71,6 +73,7 @@ type Machine interface {
 type FacadeAPI interface {
 	WatchModelMachines() (watcher.StringsWatcher, error)
 	Machine(tag names.MachineTag) (Machine, error)
+	RegionCloudSpec(tag names.MachineTag) (*environs.CloudSpec, error)
 }
 
 // Config encapsulates the configuration options for instantiating a new
@@ -81,6 +84,10 @@ type Config struct {
 	Environ Environ
 	Logger  Logger
 
+	// NewRegionEnviron returns an Environ for polling the instances of
+	// machines placed in a cloud region other than the model's own.
+	NewRegionEnviron func(environs.CloudSpec) (Environ, error)
+
 	CredentialAPI common.CredentialAPI
 }
 
@@ -98,6 +105,9 @@ func (config Config) Validate() error {
 	if config.Logger == nil {
 		return errors.NotValidf("nil Logger")
 	}
+	if config.NewRegionEnviron == nil {
+		return errors.NotValidf("nil NewRegionEnviron")
+	}
 	if config.CredentialAPI == nil {
 		return errors.NotValidf("nil CredentialAPI")
 	}
@@ -117,6 +127,10 @@ type pollGroupEntry struct {
 	tag        names.MachineTag
 	instanceID instance.Id
 
+	// regionSpec is the cloud spec of the region the machine was
+	// placed in, if that is not the model's own region.
+	regionSpec *environs.CloudSpec
+
 	shortPollInterval time.Duration
 	shortPollAt       time.Time
 }
@@ -142,6 +156,10 @@ type updaterWorker struct {
 	instanceIDToGroupEntry map[instance.Id]*pollGroupEntry
 	callContext            context.ProviderCallContext
 
+	// regionEnvirons holds the environs for the other cloud regions
+	// that machines were placed in, keyed by regionKey.
+	regionEnvirons map[string]Environ
+
 	// Hook function which tests can use to be notified when the worker
 	// has processed a full loop iteration.
 	loopCompletedHook func()
@@ -162,6 +180,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		},
 		instanceIDToGroupEntry: make(map[instance.Id]*pollGroupEntry),
 		callContext:            common.NewCloudCallContext(config.CredentialAPI, nil),
+		regionEnvirons:         make(map[string]Environ),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -284,16 +303,24 @@ func (u *updaterWorker) queueMachineForPolling(tag names.MachineTag) error {
 		return nil
 	}
 
+	// Machines placed in another cloud region are polled using the
+	// environ for that region.
+	regionSpec, err := u.config.Facade.RegionCloudSpec(tag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
 	// Add all new machines to the short poll group and arrange for them to
 	// be polled as soon as possible.
-	u.appendToShortPollGroup(tag, m)
+	u.appendToShortPollGroup(tag, m, regionSpec)
 	return nil
 }
 
-func (u *updaterWorker) appendToShortPollGroup(tag names.MachineTag, m Machine) {
+func (u *updaterWorker) appendToShortPollGroup(tag names.MachineTag, m Machine, regionSpec *environs.CloudSpec) {
 	entry := &pollGroupEntry{
-		tag: tag,
-		m:   m,
+		tag:        tag,
+		m:          m,
+		regionSpec: regionSpec,
 	}
 	entry.resetShortPollInterval(u.config.Clock)
 	u.pollGroup[shortPollGroup][tag] = entry
@@ -320,8 +347,10 @@ func (u *updaterWorker) lookupPolledMachine(tag names.MachineTag) (*pollGroupEnt
 }
 
 func (u *updaterWorker) pollGroupMembers(groupType pollGroupType) error {
-	// Build a list of instance IDs to pass as a query to the provider.
-	var instList []instance.Id
+	// Build the lists of instance IDs to pass as queries to the
+	// providers, one for each cloud region.
+	instLists := make(map[string][]instance.Id)
+	regionSpecs := make(map[string]*environs.CloudSpec)
 	now := u.config.Clock.Now()
 	for _, entry := range u.pollGroup[groupType] {
 		if groupType == shortPollGroup && now.Before(entry.shortPollAt) {
@@ -339,14 +368,43 @@ func (u *updaterWorker) pollGroupMembers(groupType pollGroupType) error {
 			return errors.Trace(err)
 		}
 
-		instList = append(instList, entry.instanceID)
+		key := regionKey(entry.regionSpec)
+		instLists[key] = append(instLists[key], entry.instanceID)
+		regionSpecs[key] = entry.regionSpec
 	}
 
-	if len(instList) == 0 {
-		return nil
+	for key, instList := range instLists {
+		env, err := u.regionEnviron(key, regionSpecs[key])
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := u.pollInstances(groupType, env, instList); err != nil {
+			return errors.Trace(err)
+		}
 	}
+	return nil
+}
 
-	infoList, err := u.config.Environ.Instances(u.callContext, instList)
+// regionEnviron returns the environ for polling the instances in the
+// cloud region with the given spec, opening it the first time the
+// region is polled. A nil spec is for the model's own region.
+func (u *updaterWorker) regionEnviron(key string, spec *environs.CloudSpec) (Environ, error) {
+	if spec == nil {
+		return u.config.Environ, nil
+	}
+	if env, ok := u.regionEnvirons[key]; ok {
+		return env, nil
+	}
+	env, err := u.config.NewRegionEnviron(*spec)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening environ for region %q", spec.Region)
+	}
+	u.regionEnvirons[key] = env
+	return env, nil
+}
+
+func (u *updaterWorker) pollInstances(groupType pollGroupType, env Environ, instList []instance.Id) error {
+	infoList, err := env.Instances(u.callContext, instList)
 	if err != nil && !(err == environs.ErrPartialInstances || err == environs.ErrNoInstances) {
 		return errors.Trace(err)
 	}
@@ -375,6 +433,25 @@ func (u *updaterWorker) pollGroupMembers(groupType pollGroupType) error {
 	return nil
 }
 
+// regionKey returns the key of the environ for the cloud region with the
+// given spec. Regions used with different credentials have different
+// environs. The model's own region has the empty key.
+func regionKey(spec *environs.CloudSpec) string {
+	if spec == nil {
+		return ""
+	}
+	key := spec.Name + "/" + spec.Region
+	if spec.Credential == nil {
+		return key
+	}
+	attrs := make([]string, 0, len(spec.Credential.Attributes()))
+	for k, v := range spec.Credential.Attributes() {
+		attrs = append(attrs, k+"="+v)
+	}
+	sort.Strings(attrs)
+	return key + "/" + string(spec.Credential.AuthType()) + ":" + strings.Join(attrs, ",")
+}
+
 func (u *updaterWorker) resolveInstanceID(entry *pollGroupEntry) error {
 	if entry.instanceID != "" {
 		return nil // already resolved
@@ -74,6 +74,9 @@ func (s *configSuite) TestConfigValidation(c *gc.C) {
 		Environ:       mocks.NewMockEnviron(ctrl),
 		Logger:        loggo.GetLogger("juju.worker.instancepoller"),
 		CredentialAPI: mocks.NewMockCredentialAPI(ctrl),
+		NewRegionEnviron: func(environs.CloudSpec) (Environ, error) {
+			return nil, errors.New("no regions")
+		},
 	}
 	c.Assert(origCfg.Validate(), jc.ErrorIsNil)
 
@@ -96,6 +99,10 @@ func (s *configSuite) TestConfigValidation(c *gc.C) {
 	testCfg = origCfg
 	testCfg.CredentialAPI = nil
 	c.Assert(testCfg.Validate(), gc.ErrorMatches, "nil CredentialAPI.*")
+
+	testCfg = origCfg
+	testCfg.NewRegionEnviron = nil
+	c.Assert(testCfg.Validate(), gc.ErrorMatches, "nil NewRegionEnviron.*")
 }
 
 type pollGroupEntrySuite struct{}
@@ -158,7 +165,7 @@ func (s *workerSuite) TestQueueingExistingMachineAlwaysMovesItToShortPollGroup(c
 	machine := mocks.NewMockMachine(ctrl)
 	machine.EXPECT().Refresh().Return(nil)
 	machine.EXPECT().Life().Return(life.Alive)
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 
 	// Manually move entry to long poll group.
 	entry, _ := updWorker.lookupPolledMachine(machineTag)
@@ -226,7 +233,7 @@ func (s *workerSuite) TestStartedMachineWithNetAddressesMovesToLongPollGroup(c *
 	// Start with machine "0" in the short poll group.
 	machineTag := names.NewMachineTag("0")
 	machine := mocks.NewMockMachine(ctrl)
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 	c.Assert(updWorker.pollGroup[shortPollGroup], gc.HasLen, 1)
 
 	// The machine is assigned a network address.
@@ -255,7 +262,7 @@ func (s *workerSuite) TestNonStartedMachinesGetBumpedPollInterval(c *gc.C) {
 	for specIndex, spec := range specs {
 		c.Logf("provider reports instance status as: %q", spec)
 		machineTag := names.NewMachineTag(fmt.Sprint(specIndex))
-		updWorker.appendToShortPollGroup(machineTag, machine)
+		updWorker.appendToShortPollGroup(machineTag, machine, nil)
 		entry, _ := updWorker.lookupPolledMachine(machineTag)
 
 		updWorker.maybeSwitchPollGroup(shortPollGroup, entry, spec, status.Pending)
@@ -277,7 +284,7 @@ func (s *workerSuite) TestMoveMachineWithUnknownStatusBackToShortPollGroup(c *gc
 	machine.EXPECT().ProviderAddresses().Return(testAddrs, nil).AnyTimes()
 
 	// Move the machine to the long poll group.
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 	entry, _ := updWorker.lookupPolledMachine(machineTag)
 	updWorker.maybeSwitchPollGroup(shortPollGroup, entry, status.Running, status.Started)
 	c.Assert(updWorker.pollGroup[shortPollGroup], gc.HasLen, 0)
@@ -303,7 +310,7 @@ func (s *workerSuite) TestSkipMachineIfShortPollTargetTimeNotElapsed(c *gc.C) {
 	machine := mocks.NewMockMachine(ctrl)
 
 	// Add machine to short poll group and bump its poll interval
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 	entry, _ := updWorker.lookupPolledMachine(machineTag)
 	entry.bumpShortPollInterval(mocked.clock)
 	pollAt := entry.shortPollAt
@@ -329,7 +336,7 @@ func (s *workerSuite) TestDeadMachineGetsRemoved(c *gc.C) {
 	machine := mocks.NewMockMachine(ctrl)
 
 	// Add machine to short poll group
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 	c.Assert(updWorker.pollGroup[shortPollGroup], gc.HasLen, 1)
 
 	// On next refresh, the machine reports as dead
@@ -392,7 +399,7 @@ func (s *workerSuite) TestBatchPollingOfGroupMembers(c *gc.C) {
 	machineTag0 := names.NewMachineTag("0")
 	machine0 := mocks.NewMockMachine(ctrl)
 	machine0.EXPECT().InstanceId().Return(instance.Id(""), common.ServerError(errors.NotProvisionedf("not there")))
-	updWorker.appendToShortPollGroup(machineTag0, machine0)
+	updWorker.appendToShortPollGroup(machineTag0, machine0, nil)
 
 	machineTag1 := names.NewMachineTag("1")
 	machine1 := mocks.NewMockMachine(ctrl)
@@ -401,7 +408,7 @@ func (s *workerSuite) TestBatchPollingOfGroupMembers(c *gc.C) {
 	machine1.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running)}, nil)
 	machine1.EXPECT().Status().Return(params.StatusResult{Status: string(status.Started)}, nil)
 	machine1.EXPECT().ProviderAddresses().Return(nil, nil).AnyTimes() // no addresses assigned yet
-	updWorker.appendToShortPollGroup(machineTag1, machine1)
+	updWorker.appendToShortPollGroup(machineTag1, machine1, nil)
 
 	machine1Info := mocks.NewMockInstance(ctrl)
 	machine1Info.EXPECT().Addresses(gomock.Any()).Return(nil, nil) // no addresses reported by provider
@@ -415,6 +422,77 @@ func (s *workerSuite) TestBatchPollingOfGroupMembers(c *gc.C) {
 	})
 }
 
+func (s *workerSuite) TestPollingOfMachinesInOtherRegions(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, mocked := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	// Machine 0 is in the model's region and machine 1 was placed in a
+	// region of another cloud.
+	machineTag0 := names.NewMachineTag("0")
+	machine0 := mocks.NewMockMachine(ctrl)
+	machine0.EXPECT().Life().Return(life.Alive)
+	machine0.EXPECT().InstanceId().Return(instance.Id("i-model"), nil)
+	machine0.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running)}, nil)
+	machine0.EXPECT().Status().Return(params.StatusResult{Status: string(status.Started)}, nil)
+	machine0.EXPECT().ProviderAddresses().Return(nil, nil).AnyTimes()
+	updWorker.appendToShortPollGroup(machineTag0, machine0, nil)
+
+	regionSpec := &environs.CloudSpec{Type: "dummy", Name: "other-cloud", Region: "other-region"}
+	machineTag1 := names.NewMachineTag("1")
+	machine1 := mocks.NewMockMachine(ctrl)
+	machine1.EXPECT().Life().Return(life.Alive)
+	machine1.EXPECT().InstanceId().Return(instance.Id("i-region"), nil)
+	machine1.EXPECT().InstanceStatus().Return(params.StatusResult{Status: string(status.Running)}, nil)
+	machine1.EXPECT().Status().Return(params.StatusResult{Status: string(status.Started)}, nil)
+	machine1.EXPECT().ProviderAddresses().Return(nil, nil)
+	machine1.EXPECT().SetProviderAddresses(testAddrs[0]).Return(nil)
+	machine1.EXPECT().ProviderAddresses().Return(testAddrs[:1], nil).AnyTimes()
+	updWorker.appendToShortPollGroup(machineTag1, machine1, regionSpec)
+
+	machine0Info := mocks.NewMockInstance(ctrl)
+	machine0Info.EXPECT().Addresses(gomock.Any()).Return(nil, nil)
+	machine0Info.EXPECT().Status(gomock.Any()).Return(instance.Status{Status: status.Running})
+	mocked.environ.EXPECT().Instances(gomock.Any(), []instance.Id{"i-model"}).Return([]instances.Instance{machine0Info}, nil)
+
+	machine1Info := mocks.NewMockInstance(ctrl)
+	machine1Info.EXPECT().Addresses(gomock.Any()).Return(testAddrs[:1], nil)
+	machine1Info.EXPECT().Status(gomock.Any()).Return(instance.Status{Status: status.Running})
+	mocked.regionEnviron.EXPECT().Instances(gomock.Any(), []instance.Id{"i-region"}).Return([]instances.Instance{machine1Info}, nil)
+
+	s.assertWorkerCompletesLoop(c, updWorker, func() {
+		mocked.clock.Advance(ShortPoll)
+	})
+	c.Assert(*mocked.regionSpecs, jc.DeepEquals, []environs.CloudSpec{*regionSpec})
+}
+
+func (s *workerSuite) TestQueueingMachineInOtherRegion(c *gc.C) {
+	ctrl := gomock.NewController(c)
+	defer ctrl.Finish()
+
+	w, mocked := s.startWorker(c, ctrl)
+	defer workertest.CleanKill(c, w)
+	updWorker := w.(*updaterWorker)
+
+	regionSpec := &environs.CloudSpec{Type: "dummy", Name: "other-cloud", Region: "other-region"}
+	machineTag := names.NewMachineTag("0")
+	machine := mocks.NewMockMachine(ctrl)
+	machine.EXPECT().IsManual().Return(false, nil)
+	mocked.facadeAPI.addMachine(machineTag, machine)
+	mocked.facadeAPI.setRegionCloudSpec(machineTag, regionSpec)
+
+	err := updWorker.queueMachineForPolling(machineTag)
+	c.Assert(err, jc.ErrorIsNil)
+
+	entry, groupType := updWorker.lookupPolledMachine(machineTag)
+	c.Assert(entry, gc.NotNil)
+	c.Assert(groupType, gc.Equals, shortPollGroup)
+	c.Assert(entry.regionSpec, jc.DeepEquals, regionSpec)
+}
+
 func (s *workerSuite) TestLongPollMachineNotKnownByProvider(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -427,7 +505,7 @@ func (s *workerSuite) TestLongPollMachineNotKnownByProvider(c *gc.C) {
 	machine := mocks.NewMockMachine(ctrl)
 
 	// Add machine to short poll group and manually move it to long poll group.
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 	entry, _ := updWorker.lookupPolledMachine(machineTag)
 	updWorker.pollGroup[longPollGroup][machineTag] = entry
 	delete(updWorker.pollGroup[shortPollGroup], machineTag)
@@ -459,7 +537,7 @@ func (s *workerSuite) TestLongPollNoMachineInGroupKnownByProvider(c *gc.C) {
 	machine := mocks.NewMockMachine(ctrl)
 
 	// Add machine to short poll group and manually move it to long poll group.
-	updWorker.appendToShortPollGroup(machineTag, machine)
+	updWorker.appendToShortPollGroup(machineTag, machine, nil)
 	entry, _ := updWorker.lookupPolledMachine(machineTag)
 	updWorker.pollGroup[longPollGroup][machineTag] = entry
 	delete(updWorker.pollGroup[shortPollGroup], machineTag)
@@ -501,17 +579,21 @@ func (s *workerSuite) assertWorkerCompletesLoops(c *gc.C, w *updaterWorker, numL
 }
 
 type workerMocks struct {
-	clock     *testclock.Clock
-	facadeAPI *mockFacadeAPI
-	environ   *mocks.MockEnviron
+	clock         *testclock.Clock
+	facadeAPI     *mockFacadeAPI
+	environ       *mocks.MockEnviron
+	regionEnviron *mocks.MockEnviron
+	regionSpecs   *[]environs.CloudSpec
 }
 
 func (s *workerSuite) startWorker(c *gc.C, ctrl *gomock.Controller) (worker.Worker, workerMocks) {
 	workerMainLoopEnteredCh := make(chan struct{}, 1)
 	mocked := workerMocks{
-		clock:     testclock.NewClock(time.Now()),
-		facadeAPI: newMockFacadeAPI(ctrl, workerMainLoopEnteredCh),
-		environ:   mocks.NewMockEnviron(ctrl),
+		clock:         testclock.NewClock(time.Now()),
+		facadeAPI:     newMockFacadeAPI(ctrl, workerMainLoopEnteredCh),
+		environ:       mocks.NewMockEnviron(ctrl),
+		regionEnviron: mocks.NewMockEnviron(ctrl),
+		regionSpecs:   new([]environs.CloudSpec),
 	}
 
 	w, err := NewWorker(Config{
@@ -520,6 +602,10 @@ func (s *workerSuite) startWorker(c *gc.C, ctrl *gomock.Controller) (worker.Work
 		Environ:       mocked.environ,
 		CredentialAPI: mocks.NewMockCredentialAPI(ctrl),
 		Logger:        loggo.GetLogger("juju.worker.instancepoller"),
+		NewRegionEnviron: func(spec environs.CloudSpec) (Environ, error) {
+			*mocked.regionSpecs = append(*mocked.regionSpecs, spec)
+			return mocked.regionEnviron, nil
+		},
 	})
 	c.Assert(err, jc.ErrorIsNil)
 
@@ -538,7 +624,8 @@ func (s *workerSuite) startWorker(c *gc.C, ctrl *gomock.Controller) (worker.Work
 // FacadeAPI interface. Because the Machine() method returns a Machine interface,
 // gomock will import instancepoller and cause an import cycle.
 type mockFacadeAPI struct {
-	machineMap map[names.MachineTag]Machine
+	machineMap     map[names.MachineTag]Machine
+	regionSpecsMap map[names.MachineTag]*environs.CloudSpec
 
 	sw              *mocks.MockStringsWatcher
 	watcherChangeCh chan []string
@@ -547,6 +634,7 @@ type mockFacadeAPI struct {
 func newMockFacadeAPI(ctrl *gomock.Controller, workerGotWatcherCh chan<- struct{}) *mockFacadeAPI {
 	api := &mockFacadeAPI{
 		machineMap:      make(map[names.MachineTag]Machine),
+		regionSpecsMap:  make(map[names.MachineTag]*environs.CloudSpec),
 		sw:              mocks.NewMockStringsWatcher(ctrl),
 		watcherChangeCh: make(chan []string),
 	}
@@ -571,6 +659,9 @@ func (api *mockFacadeAPI) assertEnqueueChange(c *gc.C, values []string) {
 	}
 }
 func (api *mockFacadeAPI) addMachine(tag names.MachineTag, m Machine) { api.machineMap[tag] = m }
+func (api *mockFacadeAPI) setRegionCloudSpec(tag names.MachineTag, spec *environs.CloudSpec) {
+	api.regionSpecsMap[tag] = spec
+}
 
 func (api *mockFacadeAPI) WatchModelMachines() (watcher.StringsWatcher, error) { return api.sw, nil }
 func (api *mockFacadeAPI) Machine(tag names.MachineTag) (Machine, error) {
@@ -579,3 +670,6 @@ func (api *mockFacadeAPI) Machine(tag names.MachineTag) (Machine, error) {
 	}
 	return nil, errors.NotFoundf(tag.String())
 }
+func (api *mockFacadeAPI) RegionCloudSpec(tag names.MachineTag) (*environs.CloudSpec, error) {
+	return api.regionSpecsMap[tag], nil
+}
@@ -25,14 +25,16 @@ func WriteProfileFunctions(profileDir string) error {
 		logger.Debugf("skipping profile funcs install")
 		return nil
 	}
-	filename := profileFilename(profileDir)
+	filename := ProfileFilename(profileDir)
 	if err := ioutil.WriteFile(filename, []byte(bashFuncs), 0644); err != nil {
 		return errors.Annotate(err, "writing introspection bash funcs")
 	}
 	return nil
 }
 
-func profileFilename(profileDir string) string {
+// ProfileFilename returns the path of the profile script written
+// to the given directory by WriteProfileFunctions.
+func ProfileFilename(profileDir string) string {
 	return path.Join(profileDir, bashFuncsFilename)
 }
 
@@ -19,7 +19,7 @@ type profileSuite struct {
 var _ = gc.Suite(&profileSuite{})
 
 func (*profileSuite) TestProfileFilename(c *gc.C) {
-	c.Assert(profileFilename(ProfileDir), gc.Equals, "/etc/profile.d/juju-introspection.sh")
+	c.Assert(ProfileFilename(ProfileDir), gc.Equals, "/etc/profile.d/juju-introspection.sh")
 }
 
 func (*profileSuite) TestNonLinux(c *gc.C) {
@@ -38,7 +38,7 @@ func (s *profileSuite) TestLinux(c *gc.C) {
 	err := WriteProfileFunctions(dir)
 	c.Assert(err, jc.ErrorIsNil)
 
-	content, err := ioutil.ReadFile(profileFilename(dir))
+	content, err := ioutil.ReadFile(ProfileFilename(dir))
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(string(content), gc.Equals, bashFuncs)
 }
@@ -0,0 +1,147 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machineactions
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+
+	corenetwork "github.com/juju/juju/core/network"
+)
+
+const (
+	// probeOK is the result recorded for a successful probe.
+	probeOK = "ok"
+
+	// defaultProbeMTU is the MTU assumed when none is given.
+	defaultProbeMTU = 1500
+
+	// defaultProbeTimeout is the probe timeout used when none is given.
+	defaultProbeTimeout = 5 * time.Second
+
+	// ICMP payload sizes are the MTU less the IP and ICMP headers.
+	ipv4ProbeOverhead = 28
+	ipv6ProbeOverhead = 48
+)
+
+// pingAddress sends a single ICMP echo request of the given MTU, which
+// must not be fragmented, to the address.
+var pingAddress = func(address string, mtu int, timeout time.Duration) error {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return errors.NotValidf("address %q", address)
+	}
+	command, size := "ping", mtu-ipv4ProbeOverhead
+	if ip.To4() == nil {
+		command, size = "ping6", mtu-ipv6ProbeOverhead
+	}
+	seconds := int(timeout.Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	res, err := runCommandWithTimeout(
+		fmt.Sprintf("%s -c 1 -W %d -M do -s %d %s", command, seconds, size, ip),
+		timeout+time.Second, clock.WallClock,
+	)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if res.Code != 0 {
+		output := strings.TrimSpace(string(res.Stderr))
+		if output == "" {
+			output = fmt.Sprintf("%s exited %d", command, res.Code)
+		}
+		return errors.New(output)
+	}
+	return nil
+}
+
+// dialAddress opens, and closes, a TCP connection to the address.
+var dialAddress = func(address string, port int, timeout time.Duration) error {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(address, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return conn.Close()
+}
+
+// handleCheckNetworkAction probes each of the targets given in the
+// action parameters: an ICMP echo request of the target's MTU is sent
+// to its address, and a TCP connection is made to the first port of
+// each TCP port range opened by the target. The results of the probes
+// are returned under "probes", in the order of the targets.
+func handleCheckNetworkAction(params map[string]interface{}) (results map[string]interface{}, err error) {
+	// The timeout is passed in in nanoseconds(which are represented in go as int64)
+	// But due to serialization it comes out as float64
+	timeout := defaultProbeTimeout
+	if t, _ := params["timeout"].(float64); t > 0 {
+		timeout = time.Duration(t)
+	}
+
+	targets, _ := params["targets"].([]interface{})
+	probes := make([]interface{}, 0, len(targets))
+	for _, target := range targets {
+		target, ok := target.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("invalid action parameters")
+		}
+		probes = append(probes, probeTarget(target, timeout))
+	}
+	return map[string]interface{}{"probes": probes}, nil
+}
+
+func probeTarget(target map[string]interface{}, timeout time.Duration) map[string]interface{} {
+	address, _ := target["address"].(string)
+	mtu := defaultProbeMTU
+	if m, _ := target["mtu"].(float64); m > 0 {
+		mtu = int(m)
+	}
+	logger.Tracef("probing %q with MTU %d", address, mtu)
+
+	result := map[string]interface{}{
+		"address": address,
+		"mtu":     mtu,
+	}
+	for _, key := range []string{"from", "to", "relation"} {
+		if value, ok := target[key].(string); ok {
+			result[key] = value
+		}
+	}
+	result["icmp"] = probeResult(pingAddress(address, mtu, timeout))
+
+	ports, _ := target["ports"].([]interface{})
+	if len(ports) == 0 {
+		return result
+	}
+	portResults := make(map[string]interface{}, len(ports))
+	for _, port := range ports {
+		port, _ := port.(string)
+		portRange, err := corenetwork.ParsePortRange(port)
+		switch {
+		case err != nil:
+			portResults[port] = probeResult(err)
+		case portRange.Protocol != "tcp":
+			// Only TCP ports can be probed without the
+			// cooperation of the workload.
+			continue
+		default:
+			portResults[portRange.String()] = probeResult(dialAddress(address, portRange.FromPort, timeout))
+		}
+	}
+	result["ports"] = portResults
+	return result
+}
+
+func probeResult(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return probeOK
+}
@@ -35,6 +35,8 @@ func HandleAction(name string, params map[string]interface{}) (results map[strin
 	switch name {
 	case actions.JujuRunActionName:
 		return handleJujuRunAction(params)
+	case actions.JujuCheckNetworkActionName:
+		return handleCheckNetworkAction(params)
 	default:
 		return nil, errors.Errorf("unexpected action %s", name)
 	}
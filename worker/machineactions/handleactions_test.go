@@ -6,6 +6,7 @@ package machineactions_test
 
 import (
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -78,3 +79,76 @@ func (s *HandleSuite) TestErrorRun(c *gc.C) {
 	c.Assert(results["Stdout"], gc.Equals, "")
 	c.Assert(results["Stderr"], gc.Equals, "")
 }
+
+func (s *HandleSuite) TestCheckNetworkInvalidParams(c *gc.C) {
+	results, err := machineactions.HandleAction(actions.JujuCheckNetworkActionName, map[string]interface{}{
+		"targets": []interface{}{map[string]interface{}{"to": "mysql/0"}},
+		"timeout": float64(0),
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid action parameters")
+	c.Assert(results, gc.IsNil)
+}
+
+func (s *HandleSuite) TestCheckNetwork(c *gc.C) {
+	var pinged []string
+	s.PatchValue(machineactions.PingAddress, func(address string, mtu int, timeout time.Duration) error {
+		c.Check(timeout, gc.Equals, time.Second)
+		pinged = append(pinged, address)
+		if mtu > 1500 {
+			return errors.New("message too long")
+		}
+		return nil
+	})
+	var dialled []int
+	s.PatchValue(machineactions.DialAddress, func(address string, port int, timeout time.Duration) error {
+		dialled = append(dialled, port)
+		if port == 443 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+
+	params := map[string]interface{}{
+		"targets": []interface{}{
+			map[string]interface{}{
+				"from":     "wordpress/0",
+				"to":       "mysql/0",
+				"relation": "wordpress:db mysql:server",
+				"address":  "10.0.0.2",
+				"ports":    []interface{}{"3306/tcp", "443/tcp", "53/udp"},
+			},
+			map[string]interface{}{
+				"to":      "mysql/1",
+				"address": "10.0.0.3",
+				"mtu":     float64(9000),
+			},
+		},
+		"timeout": float64(time.Second),
+	}
+	results, err := machineactions.HandleAction(actions.JujuCheckNetworkActionName, params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pinged, jc.DeepEquals, []string{"10.0.0.2", "10.0.0.3"})
+	c.Assert(dialled, jc.DeepEquals, []int{3306, 443})
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{
+		"probes": []interface{}{
+			map[string]interface{}{
+				"from":     "wordpress/0",
+				"to":       "mysql/0",
+				"relation": "wordpress:db mysql:server",
+				"address":  "10.0.0.2",
+				"mtu":      1500,
+				"icmp":     "ok",
+				"ports": map[string]interface{}{
+					"3306/tcp": "ok",
+					"443/tcp":  "connection refused",
+				},
+			},
+			map[string]interface{}{
+				"to":      "mysql/1",
+				"address": "10.0.0.3",
+				"mtu":     9000,
+				"icmp":    "message too long",
+			},
+		},
+	})
+}
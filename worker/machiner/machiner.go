@@ -34,6 +34,11 @@ type Config struct {
 	// ClearMachineAddressesOnStart indicates whether or not to clear
 	// the machine's machine addresses when the worker starts.
 	ClearMachineAddressesOnStart bool
+
+	// NotifyMachineDead, if non-nil, will be called after the machine
+	// is set to Dead, with whether the workloads of the machine's
+	// units should be kept when the agent uninstalls itself.
+	NotifyMachineDead func(keepWorkloads bool) error
 }
 
 // Validate reports whether or not the configuration is valid.
@@ -187,6 +192,16 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 		return errors.Annotatef(err, "%s failed to set status stopped", mr.config.Tag)
 	}
 
+	// Whether the workloads should be kept is read before the machine
+	// is Dead, while the machine's data is sure to still be there.
+	var keepWorkloads bool
+	if mr.config.NotifyMachineDead != nil {
+		var err error
+		if keepWorkloads, err = mr.machine.KeepWorkloads(); err != nil {
+			return errors.Annotatef(err, "%s failed to read whether to keep workloads", mr.config.Tag)
+		}
+	}
+
 	// Attempt to mark the machine Dead. If the machine still has units
 	// assigned, or storage attached, this will fail with
 	// CodeHasAssignedUnits or CodeMachineHasAttachedStorage respectively.
@@ -206,6 +221,11 @@ func (mr *Machiner) Handle(_ <-chan struct{}) error {
 		}
 		return errors.Trace(err)
 	}
+	if mr.config.NotifyMachineDead != nil {
+		if err := mr.config.NotifyMachineDead(keepWorkloads); err != nil {
+			return errors.Annotatef(err, "%s failed to notify machine dead", mr.config.Tag)
+		}
+	}
 	return jworker.ErrTerminateAgent
 }
 
@@ -83,7 +83,8 @@ func (s *MachinerSuite) TestMachinerSetUpMachineNotFound(c *gc.C) {
 		&params.Error{Code: params.CodeNotFound}, // Machine
 	)
 	w, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	err = stopWorker(w)
@@ -108,7 +109,8 @@ func (s *MachinerSuite) testMachinerMachineRefreshNotFoundOrUnauthorized(c *gc.C
 		&params.Error{Code: code}, // Refresh
 	)
 	w, err := machiner.NewMachiner(machiner.Config{
-		s.accessor, s.machineTag, false,
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
 	})
 	c.Assert(err, jc.ErrorIsNil)
 	s.accessor.machine.watcher.changes <- struct{}{}
@@ -308,6 +310,52 @@ func (s *MachinerSuite) TestSetDead(c *gc.C) {
 	c.Assert(err, gc.Equals, jworker.ErrTerminateAgent)
 }
 
+func (s *MachinerSuite) TestSetDeadNotifiesMachineDead(c *gc.C) {
+	s.accessor.machine.life = life.Dying
+	s.accessor.machine.keepWorkloads = true
+	var notified []bool
+	mr, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
+		NotifyMachineDead: func(keepWorkloads bool) error {
+			notified = append(notified, keepWorkloads)
+			return nil
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.watcher.changes <- struct{}{}
+
+	err = stopWorker(mr)
+	c.Assert(err, gc.Equals, jworker.ErrTerminateAgent)
+	c.Assert(notified, jc.DeepEquals, []bool{true})
+	s.accessor.machine.CheckCallNames(c,
+		"SetMachineAddresses",
+		"SetStatus",
+		"Watch",
+		"Refresh",
+		"Life",
+		"SetStatus",
+		"KeepWorkloads",
+		"EnsureDead",
+	)
+}
+
+func (s *MachinerSuite) TestSetDeadNotifyMachineDeadError(c *gc.C) {
+	s.accessor.machine.life = life.Dying
+	mr, err := machiner.NewMachiner(machiner.Config{
+		MachineAccessor: s.accessor,
+		Tag:             s.machineTag,
+		NotifyMachineDead: func(bool) error {
+			return errors.New("write failed")
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.accessor.machine.watcher.changes <- struct{}{}
+
+	err = stopWorker(mr)
+	c.Assert(err, gc.ErrorMatches, "machine-123 failed to notify machine dead: write failed")
+}
+
 func (s *MachinerSuite) TestSetMachineAddresses(c *gc.C) {
 	s.addresses = []net.Addr{
 		&net.IPAddr{IP: net.IPv4(10, 0, 0, 1)},
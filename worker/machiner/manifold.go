@@ -4,6 +4,8 @@
 package machiner
 
 import (
+	"strings"
+
 	"github.com/juju/errors"
 	"gopkg.in/juju/names.v3"
 	"gopkg.in/juju/worker.v1"
@@ -13,6 +15,7 @@ import (
 	apiagent "github.com/juju/juju/api/agent"
 	"github.com/juju/juju/api/base"
 	apimachiner "github.com/juju/juju/api/machiner"
+	"github.com/juju/juju/environs/manual"
 )
 
 // ManifoldConfig defines the names of the manifolds on which a
@@ -85,11 +88,20 @@ func newWorker(a agent.Agent, apiCaller base.APICaller) (worker.Worker, error) {
 	if ignoreMachineAddresses {
 		logger.Infof("machine addresses not used, only addresses from provider")
 	}
+	// Only manually provisioned machines uninstall their agent when
+	// they die; for all others the instance is stopped by the provider.
+	var notifyMachineDead func(bool) error
+	if strings.HasPrefix(currentConfig.Nonce(), manual.ManualInstancePrefix) {
+		notifyMachineDead = func(keepWorkloads bool) error {
+			return agent.SetCanUninstall(a, keepWorkloads)
+		}
+	}
 	accessor := APIMachineAccessor{apimachiner.NewState(apiCaller)}
 	w, err := NewMachiner(Config{
 		MachineAccessor:              accessor,
 		Tag:                          tag.(names.MachineTag),
 		ClearMachineAddressesOnStart: ignoreMachineAddresses,
+		NotifyMachineDead:            notifyMachineDead,
 	})
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot start machiner worker")
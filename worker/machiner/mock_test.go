@@ -32,8 +32,9 @@ func (w *mockWatcher) Wait() error {
 type mockMachine struct {
 	machiner.Machine
 	gitjujutesting.Stub
-	watcher mockWatcher
-	life    life.Value
+	watcher       mockWatcher
+	life          life.Value
+	keepWorkloads bool
 }
 
 func (m *mockMachine) Refresh() error {
@@ -66,6 +67,11 @@ func (m *mockMachine) SetStatus(status status.Status, info string, data map[stri
 	return m.NextErr()
 }
 
+func (m *mockMachine) KeepWorkloads() (bool, error) {
+	m.MethodCall(m, "KeepWorkloads")
+	return m.keepWorkloads, m.NextErr()
+}
+
 func (m *mockMachine) Watch() (watcher.NotifyWatcher, error) {
 	m.MethodCall(m, "Watch")
 	if err := m.NextErr(); err != nil {
@@ -0,0 +1,148 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer
+
+import (
+	"time"
+
+	"github.com/juju/clock"
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/api/machinereplacer"
+)
+
+// Logger defines the methods used by the machine replacer for logging.
+type Logger interface {
+	Debugf(string, ...interface{})
+	Infof(string, ...interface{})
+	Errorf(string, ...interface{})
+}
+
+// Facade defines the API methods used by the machine replacer.
+type Facade interface {
+	DownMachines() ([]machinereplacer.DownMachine, error)
+	ReplaceMachine(names.MachineTag) (names.MachineTag, error)
+}
+
+// Config holds the configuration and dependencies for a machine
+// replacer worker.
+type Config struct {
+	Facade        Facade
+	Clock         clock.Clock
+	Logger        Logger
+	CheckInterval time.Duration
+}
+
+// Validate returns an error if the config cannot be used to start a
+// machine replacer.
+func (config Config) Validate() error {
+	if config.Facade == nil {
+		return errors.NotValidf("nil Facade")
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if config.Logger == nil {
+		return errors.NotValidf("nil Logger")
+	}
+	if config.CheckInterval <= 0 {
+		return errors.NotValidf("non-positive CheckInterval")
+	}
+	return nil
+}
+
+// NewWorker returns a worker that periodically looks for machines
+// which have been down for longer than the replacement delay of the
+// applications they host, and replaces them.
+//
+// How long a machine has been down is measured from when the worker
+// first saw it down, so a restarted worker waits the full delay again
+// before replacing anything.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &replacerWorker{
+		config:    config,
+		downSince: make(map[names.MachineTag]time.Time),
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+type replacerWorker struct {
+	catacomb  catacomb.Catacomb
+	config    Config
+	downSince map[names.MachineTag]time.Time
+}
+
+// Kill is part of the worker.Worker interface.
+func (w *replacerWorker) Kill() {
+	w.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (w *replacerWorker) Wait() error {
+	return w.catacomb.Wait()
+}
+
+func (w *replacerWorker) loop() error {
+	timer := w.config.Clock.NewTimer(w.config.CheckInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-w.catacomb.Dying():
+			return w.catacomb.ErrDying()
+		case <-timer.Chan():
+			if err := w.check(); err != nil {
+				return errors.Trace(err)
+			}
+			timer.Reset(w.config.CheckInterval)
+		}
+	}
+}
+
+func (w *replacerWorker) check() error {
+	machines, err := w.config.Facade.DownMachines()
+	if err != nil {
+		return errors.Annotate(err, "getting down machines")
+	}
+	now := w.config.Clock.Now()
+	down := make(map[names.MachineTag]time.Time)
+	for _, m := range machines {
+		since, ok := w.downSince[m.Tag]
+		if !ok {
+			if m.ReplaceAfter > 0 {
+				w.config.Logger.Infof("%s is down; replacing it in %v unless it recovers", names.ReadableString(m.Tag), m.ReplaceAfter)
+			}
+			since = now
+		}
+		if now.Sub(since) < m.ReplaceAfter {
+			down[m.Tag] = since
+			continue
+		}
+		replacement, err := w.config.Facade.ReplaceMachine(m.Tag)
+		if err != nil {
+			// The machine may have recovered since it was listed;
+			// if not, it will be tried again at the next check.
+			w.config.Logger.Errorf("cannot replace %s: %v", names.ReadableString(m.Tag), err)
+			down[m.Tag] = since
+			continue
+		}
+		w.config.Logger.Infof("replaced %s with %s", names.ReadableString(m.Tag), names.ReadableString(replacement))
+	}
+	// Machines that recovered are forgotten, so that the full delay
+	// applies if they go down again.
+	w.downSince = down
+	return nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package machinereplacer_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/clock/testclock"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1/workertest"
+
+	apimachinereplacer "github.com/juju/juju/api/machinereplacer"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/machinereplacer"
+)
+
+type WorkerSuite struct {
+	testing.IsolationSuite
+
+	clock  *testclock.Clock
+	facade *fakeFacade
+	config machinereplacer.Config
+}
+
+var _ = gc.Suite(&WorkerSuite{})
+
+func (s *WorkerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testclock.NewClock(time.Time{})
+	s.facade = &fakeFacade{}
+	s.config = machinereplacer.Config{
+		Facade:        s.facade,
+		Clock:         s.clock,
+		Logger:        loggo.GetLogger("test"),
+		CheckInterval: time.Minute,
+	}
+}
+
+func (s *WorkerSuite) TestValidate(c *gc.C) {
+	s.config.CheckInterval = 0
+	_, err := machinereplacer.NewWorker(s.config)
+	c.Assert(err, gc.ErrorMatches, "non-positive CheckInterval not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *WorkerSuite) TestReplacesAfterDelay(c *gc.C) {
+	machine1 := names.NewMachineTag("1")
+	machine2 := names.NewMachineTag("2")
+	down := []apimachinereplacer.DownMachine{
+		{Tag: machine1, ReplaceAfter: 10 * time.Minute},
+		{Tag: machine2, ReplaceAfter: 5 * time.Minute},
+	}
+	s.facade.down = [][]apimachinereplacer.DownMachine{down, down}
+
+	w, err := machinereplacer.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// The first check notes that the machines are down.
+	s.advance(c, time.Minute)
+	// The second check is 5 minutes later.
+	s.advance(c, 5*time.Minute)
+	s.advance(c, 0)
+
+	s.facade.CheckCalls(c, []testing.StubCall{
+		{"DownMachines", nil},
+		{"DownMachines", nil},
+		{"ReplaceMachine", []interface{}{machine2}},
+	})
+}
+
+func (s *WorkerSuite) TestReplacesInterruptedImmediately(c *gc.C) {
+	machine1 := names.NewMachineTag("1")
+	s.facade.down = [][]apimachinereplacer.DownMachine{{
+		{Tag: machine1},
+	}}
+
+	w, err := machinereplacer.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, time.Minute)
+	s.advance(c, 0)
+
+	s.facade.CheckCalls(c, []testing.StubCall{
+		{"DownMachines", nil},
+		{"ReplaceMachine", []interface{}{machine1}},
+	})
+}
+
+func (s *WorkerSuite) TestRecoveredMachineWaitsAgain(c *gc.C) {
+	machine1 := names.NewMachineTag("1")
+	down := []apimachinereplacer.DownMachine{
+		{Tag: machine1, ReplaceAfter: 5 * time.Minute},
+	}
+	s.facade.down = [][]apimachinereplacer.DownMachine{down, nil, down, down}
+
+	w, err := machinereplacer.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, time.Minute)
+	s.advance(c, time.Minute)
+	// The machine went down again, so it must be down for
+	// another 5 minutes.
+	s.advance(c, 5*time.Minute)
+	s.advance(c, time.Minute)
+	s.advance(c, 0)
+
+	s.facade.CheckCallNames(c, "DownMachines", "DownMachines", "DownMachines", "DownMachines")
+}
+
+func (s *WorkerSuite) TestReplaceErrorRetries(c *gc.C) {
+	machine1 := names.NewMachineTag("1")
+	down := []apimachinereplacer.DownMachine{
+		{Tag: machine1, ReplaceAfter: time.Minute},
+	}
+	s.facade.down = [][]apimachinereplacer.DownMachine{down, down, down}
+	s.facade.SetErrors(nil, nil, errors.New("boom"))
+
+	w, err := machinereplacer.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, time.Minute)
+	s.advance(c, time.Minute)
+	s.advance(c, time.Minute)
+	s.advance(c, 0)
+
+	s.facade.CheckCalls(c, []testing.StubCall{
+		{"DownMachines", nil},
+		{"DownMachines", nil},
+		{"ReplaceMachine", []interface{}{machine1}},
+		{"DownMachines", nil},
+		{"ReplaceMachine", []interface{}{machine1}},
+	})
+}
+
+func (s *WorkerSuite) TestDownMachinesError(c *gc.C) {
+	s.facade.SetErrors(errors.New("boom"))
+
+	w, err := machinereplacer.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "getting down machines: boom")
+}
+
+// advance waits for the worker to be waiting for its next check,
+// and then advances the clock.
+func (s *WorkerSuite) advance(c *gc.C, d time.Duration) {
+	err := s.clock.WaitAdvance(d, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+type fakeFacade struct {
+	testing.Stub
+
+	mu   sync.Mutex
+	down [][]apimachinereplacer.DownMachine
+}
+
+func (f *fakeFacade) DownMachines() ([]apimachinereplacer.DownMachine, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.AddCall("DownMachines")
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	var down []apimachinereplacer.DownMachine
+	if len(f.down) > 0 {
+		down, f.down = f.down[0], f.down[1:]
+	}
+	return down, nil
+}
+
+func (f *fakeFacade) ReplaceMachine(tag names.MachineTag) (names.MachineTag, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.AddCall("ReplaceMachine", tag)
+	if err := f.NextErr(); err != nil {
+		return names.MachineTag{}, err
+	}
+	return names.NewMachineTag("42"), nil
+}
@@ -40,6 +40,8 @@ var (
 	GetToolsFinder          = &getToolsFinder
 	RetryStrategyDelay      = &retryStrategyDelay
 	RetryStrategyCount      = &retryStrategyCount
+	InterruptionCheckDelay  = &interruptionCheckDelay
+	NewRegionBroker         = &newRegionBroker
 )
 
 var ClassifyMachine = classifyMachine
@@ -62,7 +64,8 @@ func SetupToStartMachine(p ProvisionerTask, machine apiprovisioner.MachineProvis
 	environs.StartInstanceParams,
 	error,
 ) {
-	return p.(*provisionerTask).setupToStartMachine(machine, version)
+	startInstanceParams, _, err := p.(*provisionerTask).setupToStartMachine(machine, version)
+	return startInstanceParams, err
 }
 
 func (cs *ContainerSetup) SetGetNetConfig(getNetConf func(common.NetworkConfigSource) ([]params.NetworkConfig, error)) {
@@ -30,6 +30,10 @@ var _ Provisioner = (*containerProvisioner)(nil)
 var (
 	retryStrategyDelay = 10 * time.Second
 	retryStrategyCount = 10
+
+	// interruptionCheckDelay is how often the provisioner asks brokers
+	// that support interruptible instances which have been interrupted.
+	interruptionCheckDelay = time.Minute
 )
 
 // Provisioner represents a running provisioner worker.
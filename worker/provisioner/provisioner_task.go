@@ -114,6 +114,8 @@ func NewProvisionerTask(
 		imageStream:                imageStream,
 		retryStartInstanceStrategy: retryStartInstanceStrategy,
 		cloudCallCtx:               cloudCallContext,
+		regionBrokers:              make(map[string]environs.InstanceBroker),
+		spotMachines:               make(map[string]bool),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &task.catacomb,
@@ -155,6 +157,13 @@ type provisionerTask struct {
 	machinesMutex            sync.RWMutex
 	availabilityZoneMachines []*AvailabilityZoneMachine
 	cloudCallCtx             context.ProviderCallContext
+	// region name -> broker, for machines placed in other
+	// regions of the model's cloud
+	regionBrokers      map[string]environs.InstanceBroker
+	regionBrokersMutex sync.Mutex
+	// machine id -> whether the machine has a spot constraint; only
+	// accessed from the task's loop
+	spotMachines map[string]bool
 }
 
 // Kill implements worker.Worker.Kill.
@@ -175,6 +184,14 @@ func (task *provisionerTask) loop() error {
 	// as unknown.
 	var harvestModeChan chan config.HarvestMode
 
+	// Brokers that can start interruptible instances are polled for
+	// spot instances the cloud has reclaimed.
+	interruptionChecker, _ := task.broker.(environs.InstanceInterruptionChecker)
+	var interruptionCheck <-chan time.Time
+	if interruptionChecker != nil {
+		interruptionCheck = time.After(interruptionCheckDelay)
+	}
+
 	// When the watcher is started, it will have the initial changes be all
 	// the machines that are relevant. Also, since this is available straight
 	// away, we know there will be some changes right off the bat.
@@ -209,6 +226,88 @@ func (task *provisionerTask) loop() error {
 			if err := task.processMachinesWithTransientErrors(); err != nil {
 				return errors.Annotate(err, "failed to process machines with transient errors")
 			}
+		case <-interruptionCheck:
+			task.processInterruptedInstances(interruptionChecker)
+			interruptionCheck = time.After(interruptionCheckDelay)
+		}
+	}
+}
+
+// processInterruptedInstances marks the machines whose spot instances
+// have been interrupted by the cloud with a provisioning error, with
+// "interrupted" set in the status data. The machine replacer then
+// provisions a new machine and moves the units onto it. Errors are
+// logged rather than returned, as the check is repeated periodically.
+func (task *provisionerTask) processInterruptedInstances(checker environs.InstanceInterruptionChecker) {
+	task.machinesMutex.RLock()
+	candidates := make(map[instance.Id]apiprovisioner.MachineProvisioner)
+	for _, machine := range task.machines {
+		if machine.Life() == life.Dead {
+			continue
+		}
+		instId, err := machine.InstanceId()
+		if err != nil {
+			continue
+		}
+		candidates[instId] = machine
+	}
+	task.machinesMutex.RUnlock()
+
+	// Only machines with a spot constraint can have interruptible
+	// instances. Constraints cannot change once a machine is
+	// provisioned, so they are only looked up once per machine.
+	spotMachines := make(map[string]bool)
+	machines := make(map[instance.Id]apiprovisioner.MachineProvisioner)
+	for instId, machine := range candidates {
+		spot, ok := task.spotMachines[machine.Id()]
+		if !ok {
+			cons, err := machine.Constraints()
+			if err != nil {
+				task.logger.Errorf("cannot get constraints of machine %q: %v", machine.Id(), err)
+				continue
+			}
+			spot = cons.HasSpot()
+		}
+		spotMachines[machine.Id()] = spot
+		if spot {
+			machines[instId] = machine
+		}
+	}
+	task.spotMachines = spotMachines
+	if len(machines) == 0 {
+		return
+	}
+
+	ids := make([]instance.Id, 0, len(machines))
+	for id := range machines {
+		ids = append(ids, id)
+	}
+	interrupted, err := checker.InterruptedInstances(task.cloudCallCtx, ids...)
+	if err != nil {
+		task.logger.Errorf("cannot check for interrupted instances: %v", err)
+		return
+	}
+	for _, id := range interrupted {
+		machine, ok := machines[id]
+		if !ok {
+			continue
+		}
+		instStatus, _, err := machine.InstanceStatus()
+		if err != nil {
+			task.logger.Errorf("cannot get instance status of machine %q: %v", machine.Id(), err)
+			continue
+		}
+		if instStatus == status.ProvisioningError {
+			// Already marked.
+			continue
+		}
+		task.logger.Warningf("instance %q of machine %q was interrupted by the cloud", id, machine.Id())
+		if err := machine.SetInstanceStatus(
+			status.ProvisioningError,
+			fmt.Sprintf("instance %q interrupted by the cloud", id),
+			map[string]interface{}{"interrupted": true},
+		); err != nil {
+			task.logger.Errorf("cannot set instance status of machine %q: %v", machine.Id(), err)
 		}
 	}
 }
@@ -308,6 +407,11 @@ func (task *provisionerTask) processMachines(ids []string) error {
 	if err := task.stopInstances(append(stopping, unknown...)); err != nil {
 		return err
 	}
+	if !task.harvestMode.HarvestNone() && task.harvestMode.HarvestDestroyed() {
+		if err := task.stopRegionInstances(dead); err != nil {
+			return err
+		}
+	}
 
 	// Remove any dead machines from state.
 	for _, machine := range dead {
@@ -708,12 +812,19 @@ func (task *provisionerTask) constructStartInstanceParams(
 		}
 	}
 
+	// The cloud region of machines placed in another region is handled
+	// by the broker, not the provider.
+	placement := provisioningInfo.Placement
+	if p, ok := instance.ParseRegionPlacement(placement); ok {
+		placement = p.Directive
+	}
+
 	startInstanceParams := environs.StartInstanceParams{
 		ControllerUUID:    controllerUUID,
 		Constraints:       provisioningInfo.Constraints,
 		Tools:             possibleTools,
 		InstanceConfig:    instanceConfig,
-		Placement:         provisioningInfo.Placement,
+		Placement:         placement,
 		Volumes:           volumes,
 		VolumeAttachments: volumeAttachments,
 		SubnetsToZones:    subnetsToZones,
@@ -997,16 +1108,22 @@ func (task *provisionerTask) setErrorStatus(message string, machine apiprovision
 // and StartInstanceParams to be used by startMachine.
 func (task *provisionerTask) setupToStartMachine(machine apiprovisioner.MachineProvisioner, version *version.Number) (
 	environs.StartInstanceParams,
+	environs.InstanceBroker,
 	error,
 ) {
 	pInfo, err := machine.ProvisioningInfo()
 	if err != nil {
-		return environs.StartInstanceParams{}, errors.Annotatef(err, "fetching provisioning info for machine %q", machine)
+		return environs.StartInstanceParams{}, nil, errors.Annotatef(err, "fetching provisioning info for machine %q", machine)
+	}
+
+	broker, err := task.brokerForMachine(pInfo)
+	if err != nil {
+		return environs.StartInstanceParams{}, nil, errors.Annotatef(err, "getting broker for machine %q", machine)
 	}
 
 	instanceCfg, err := task.constructInstanceConfig(machine, task.auth, pInfo)
 	if err != nil {
-		return environs.StartInstanceParams{}, errors.Annotatef(err, "creating instance config for machine %q", machine)
+		return environs.StartInstanceParams{}, nil, errors.Annotatef(err, "creating instance config for machine %q", machine)
 	}
 
 	assocProvInfoAndMachCfg(pInfo, instanceCfg)
@@ -1022,7 +1139,7 @@ func (task *provisionerTask) setupToStartMachine(machine apiprovisioner.MachineP
 		arch,
 	)
 	if err != nil {
-		return environs.StartInstanceParams{}, errors.Annotatef(err, "cannot find agent binaries for machine %q", machine)
+		return environs.StartInstanceParams{}, nil, errors.Annotatef(err, "cannot find agent binaries for machine %q", machine)
 	}
 
 	startInstanceParams, err := task.constructStartInstanceParams(
@@ -1033,10 +1150,21 @@ func (task *provisionerTask) setupToStartMachine(machine apiprovisioner.MachineP
 		possibleTools,
 	)
 	if err != nil {
-		return environs.StartInstanceParams{}, errors.Annotatef(err, "cannot construct params for machine %q", machine)
+		return environs.StartInstanceParams{}, nil, errors.Annotatef(err, "cannot construct params for machine %q", machine)
 	}
 
-	return startInstanceParams, nil
+	return startInstanceParams, broker, nil
+}
+
+// checkInstanceQuotas checks that starting an instance with the given
+// parameters would not exceed the broker's cloud quotas, if the broker
+// can report them.
+func (task *provisionerTask) checkInstanceQuotas(broker environs.InstanceBroker, args environs.StartInstanceParams) error {
+	checker, ok := broker.(environs.QuotaChecker)
+	if !ok {
+		return nil
+	}
+	return checker.CheckInstanceQuotas(task.cloudCallCtx, args)
 }
 
 // populateExcludedMachines, translates the results of DeriveAvailabilityZones
@@ -1073,16 +1201,29 @@ func (task *provisionerTask) startMachine(
 	if err != nil {
 		return err
 	}
-	startInstanceParams, err := task.setupToStartMachine(machine, v)
+	startInstanceParams, broker, err := task.setupToStartMachine(machine, v)
 	if err != nil {
 		return task.setErrorStatus("%v", machine, err)
 	}
+	// Availability zones are only tracked for the model's region; the
+	// provider picks the zone for machines placed in other regions.
+	inModelRegion := broker == task.broker
 
 	// Figure out if the zones available to use for a new instance are
 	// restricted based on placement, and if so exclude those machines
 	// from being started in any other zone.
-	if err := task.populateExcludedMachines(machine.Id(), startInstanceParams); err != nil {
-		return err
+	if inModelRegion {
+		if err := task.populateExcludedMachines(machine.Id(), startInstanceParams); err != nil {
+			return err
+		}
+	}
+
+	// Check the cloud's quotas up front, so that a machine which cannot
+	// be started is given a clear status rather than a cloud API error.
+	if err := task.checkInstanceQuotas(broker, startInstanceParams); environs.IsQuotaExceeded(err) {
+		return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+	} else if err != nil {
+		task.logger.Warningf("cannot check quotas for machine %s: %v", machine, err)
 	}
 
 	// TODO (jam): 2017-01-19 Should we be setting this earlier in the cycle?
@@ -1101,23 +1242,26 @@ func (task *provisionerTask) startMachine(
 	// one of the StartInstance calls returns an error satisfying
 	// environs.IsAvailabilityZoneIndependent.
 	for attemptsLeft := task.retryStartInstanceStrategy.retryCount; attemptsLeft >= 0; {
-		if startInstanceParams.AvailabilityZone, err = task.machineAvailabilityZoneDistribution(
-			machine.Id(), distributionGroupMachineIds, startInstanceParams.Constraints,
-		); err != nil {
-			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+		if inModelRegion {
+			if startInstanceParams.AvailabilityZone, err = task.machineAvailabilityZoneDistribution(
+				machine.Id(), distributionGroupMachineIds, startInstanceParams.Constraints,
+			); err != nil {
+				return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
+			}
 		}
 		if startInstanceParams.AvailabilityZone != "" {
 			task.logger.Infof("trying machine %s StartInstance in availability zone %s",
 				machine, startInstanceParams.AvailabilityZone)
 		}
 
-		attemptResult, err := task.broker.StartInstance(task.cloudCallCtx, startInstanceParams)
+		attemptResult, err := broker.StartInstance(task.cloudCallCtx, startInstanceParams)
 		if err == nil {
 			result = attemptResult
 			break
-		} else if attemptsLeft <= 0 {
+		} else if attemptsLeft <= 0 || environs.IsQuotaExceeded(err) {
 			// Set the state to error, so the machine will be skipped
-			// next time until the error is resolved.
+			// next time until the error is resolved. There is no point
+			// retrying while a quota would still be exceeded.
 			task.removeMachineFromAZMap(machine)
 			return task.setErrorStatus("cannot start instance for machine %q: %v", machine, err)
 		}
@@ -1196,7 +1340,7 @@ func (task *provisionerTask) startMachine(
 		if err2 := task.setErrorStatus("cannot register instance for machine %v: %v", machine, err); err2 != nil {
 			task.logger.Errorf("%v", errors.Annotate(err2, "cannot set machine's status"))
 		}
-		if err2 := task.broker.StopInstances(task.cloudCallCtx, result.Instance.Id()); err2 != nil {
+		if err2 := broker.StopInstances(task.cloudCallCtx, result.Instance.Id()); err2 != nil {
 			task.logger.Errorf("%v", errors.Annotate(err2, "after failing to set instance info"))
 		}
 		return errors.Annotate(err, "cannot set instance info")
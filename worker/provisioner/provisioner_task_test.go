@@ -204,6 +204,176 @@ func (s *ProvisionerTaskSuite) TestProvisionerRetries(c *gc.C) {
 	s.instanceBroker.CheckCallNames(c, "StartInstance", "StartInstance")
 }
 
+func (s *ProvisionerTaskSuite) TestInterruptedInstances(c *gc.C) {
+	s.PatchValue(provisioner.InterruptionCheckDelay, 10*time.Millisecond)
+
+	i0 := &testInstance{id: "zero"}
+	i1 := &testInstance{id: "one"}
+	i2 := &testInstance{id: "two"}
+	s.instances = []instances.Instance{i0, i1, i2}
+	m0 := &testMachine{id: "0", life: life.Alive, instance: i0, constraints: "spot=true"}
+	m1 := &testMachine{id: "1", life: life.Alive, instance: i1, constraints: "spot=true"}
+	m2 := &testMachine{id: "2", life: life.Alive, instance: i2}
+	s.machinesResults = []apiprovisioner.MachineResult{
+		{Machine: m0},
+		{Machine: m1},
+		{Machine: m2},
+	}
+
+	broker := &testInterruptionBroker{
+		testInstanceBroker: s.instanceBroker,
+		interrupted:        []instance.Id{"one"},
+	}
+	task := s.newProvisionerTaskWithBroker(c, broker, nil)
+	defer workertest.CleanKill(c, task)
+
+	s.sendModelMachinesChange(c, "0", "1", "2")
+	s.waitForTask(c, []string{"AllRunningInstances", "InterruptedInstances"})
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		_, msg, _ := m1.InstanceStatus()
+		if msg != "" {
+			c.Check(msg, gc.Equals, `instance "one" interrupted by the cloud`)
+			break
+		}
+		if !a.HasNext() {
+			c.Fatalf("timed out waiting for machine to be marked")
+		}
+	}
+	_, msg, _ := m0.InstanceStatus()
+	c.Check(msg, gc.Equals, "")
+
+	// Only the spot instances are checked.
+	for _, call := range broker.Calls() {
+		if call.FuncName != "InterruptedInstances" {
+			continue
+		}
+		c.Check(call.Args[1], jc.SameContents, []instance.Id{"zero", "one"})
+	}
+}
+
+func (s *ProvisionerTaskSuite) TestStartInstanceInRegion(c *gc.C) {
+	regionBroker := &testInstanceBroker{
+		Stub:      &testing.Stub{},
+		callsChan: make(chan string, 2),
+	}
+	regionBroker.SetErrors(errors.New("no capacity"))
+	var openedSpec environs.CloudSpec
+	s.PatchValue(provisioner.NewRegionBroker, func(
+		controllerUUID string, spec environs.CloudSpec, cfg *config.Config,
+	) (environs.InstanceBroker, error) {
+		openedSpec = spec
+		return regionBroker, nil
+	})
+
+	broker := &testConfigBroker{
+		testInstanceBroker: s.instanceBroker,
+		config:             coretesting.ModelConfig(c),
+	}
+	task := s.newProvisionerTaskWithBroker(c, broker, nil)
+	defer workertest.CleanKill(c, task)
+
+	m0 := &testMachine{
+		id:        "0",
+		placement: "region=nether-region,zone=a",
+		cloudSpec: &params.CloudSpec{Type: "dummy", Name: "dummy", Region: "nether-region"},
+	}
+	s.machineStatusResults = []apiprovisioner.MachineStatusResult{
+		{Machine: m0, Status: params.StatusResult{}},
+	}
+	s.sendMachineErrorRetryChange(c)
+
+	select {
+	case call := <-regionBroker.callsChan:
+		c.Assert(call, gc.Equals, "StartInstance")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for StartInstance")
+	}
+	workertest.CleanKill(c, task)
+
+	c.Assert(openedSpec.Region, gc.Equals, "nether-region")
+	regionBroker.CheckCallNames(c, "StartInstance")
+	args := regionBroker.Calls()[0].Args[1].(environs.StartInstanceParams)
+	c.Assert(args.Placement, gc.Equals, "zone=a")
+	s.instanceBroker.CheckNoCalls(c)
+}
+
+func (s *ProvisionerTaskSuite) TestStartInstanceInOtherCloud(c *gc.C) {
+	regionBroker := &testInstanceBroker{
+		Stub:      &testing.Stub{},
+		callsChan: make(chan string, 2),
+	}
+	regionBroker.SetErrors(errors.New("no capacity"))
+	var openedCfg *config.Config
+	s.PatchValue(provisioner.NewRegionBroker, func(
+		controllerUUID string, spec environs.CloudSpec, cfg *config.Config,
+	) (environs.InstanceBroker, error) {
+		openedCfg = cfg
+		return regionBroker, nil
+	})
+
+	broker := &testConfigBroker{
+		testInstanceBroker: s.instanceBroker,
+		config:             coretesting.ModelConfig(c),
+	}
+	task := s.newProvisionerTaskWithBroker(c, broker, nil)
+	defer workertest.CleanKill(c, task)
+
+	m0 := &testMachine{
+		id:        "0",
+		placement: "region=other-maas/default,credential=other",
+		cloudSpec: &params.CloudSpec{Type: "maas", Name: "other-maas", Region: "default"},
+	}
+	s.machineStatusResults = []apiprovisioner.MachineStatusResult{
+		{Machine: m0, Status: params.StatusResult{}},
+	}
+	s.sendMachineErrorRetryChange(c)
+
+	select {
+	case call := <-regionBroker.callsChan:
+		c.Assert(call, gc.Equals, "StartInstance")
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for StartInstance")
+	}
+	workertest.CleanKill(c, task)
+
+	c.Assert(openedCfg.Type(), gc.Equals, "maas")
+	args := regionBroker.Calls()[0].Args[1].(environs.StartInstanceParams)
+	c.Assert(args.Placement, gc.Equals, "")
+	s.instanceBroker.CheckNoCalls(c)
+}
+
+func (s *ProvisionerTaskSuite) TestStartInstanceQuotaExceeded(c *gc.C) {
+	broker := &testQuotaBroker{
+		testInstanceBroker: s.instanceBroker,
+		err:                environs.NewQuotaExceededError("vCPUs"),
+	}
+	task := s.newProvisionerTaskWithBroker(c, broker, nil)
+	defer workertest.CleanKill(c, task)
+
+	m0 := &testMachine{id: "0"}
+	s.machineStatusResults = []apiprovisioner.MachineStatusResult{{Machine: m0, Status: params.StatusResult{}}}
+	s.sendMachineErrorRetryChange(c)
+
+	// Wait for instance status to be set.
+	timeout := time.After(coretesting.LongWait)
+	for msg := ""; msg == ""; {
+		select {
+		case <-time.After(coretesting.ShortWait):
+			_, msg, _ = m0.InstanceStatus()
+		case <-timeout:
+			c.Fatalf("machine InstanceStatus was not set")
+		}
+	}
+	workertest.CleanKill(c, task)
+
+	_, msg, err := m0.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(msg, gc.Equals, "quota exceeded: vCPUs")
+	c.Assert(broker.checked, gc.HasLen, 1)
+	s.instanceBroker.CheckNoCalls(c)
+}
+
 func (s *ProvisionerTaskSuite) TestZoneConstraintsNoZoneAvailable(c *gc.C) {
 	ctrl := gomock.NewController(c)
 	defer ctrl.Finish()
@@ -507,6 +677,52 @@ func (t *testInstanceBroker) MaintainInstance(ctx context.ProviderCallContext, a
 	return nil
 }
 
+type testInterruptionBroker struct {
+	*testInstanceBroker
+
+	interrupted []instance.Id
+}
+
+func (t *testInterruptionBroker) InterruptedInstances(ctx context.ProviderCallContext, ids ...instance.Id) ([]instance.Id, error) {
+	t.AddCall("InterruptedInstances", ctx, ids)
+	// The check is periodic, so don't block once the test
+	// has stopped listening.
+	select {
+	case t.callsChan <- "InterruptedInstances":
+	default:
+	}
+	return t.interrupted, t.NextErr()
+}
+
+type testConfigBroker struct {
+	*testInstanceBroker
+
+	config *config.Config
+}
+
+func (t *testConfigBroker) Config() *config.Config {
+	return t.config
+}
+
+type testQuotaBroker struct {
+	*testInstanceBroker
+
+	mu      sync.Mutex
+	checked []environs.StartInstanceParams
+	err     error
+}
+
+func (t *testQuotaBroker) Quotas(ctx context.ProviderCallContext) ([]environs.Quota, error) {
+	return nil, t.err
+}
+
+func (t *testQuotaBroker) CheckInstanceQuotas(ctx context.ProviderCallContext, args environs.StartInstanceParams) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.checked = append(t.checked, args)
+	return t.err
+}
+
 type testInstance struct {
 	instances.Instance
 	id string
@@ -528,6 +744,8 @@ type testMachine struct {
 
 	markForRemoval bool
 	constraints    string
+	placement      string
+	cloudSpec      *params.CloudSpec
 
 	instStatusMsg string
 	modStatusMsg  string
@@ -546,6 +764,9 @@ func (m *testMachine) Life() life.Value {
 }
 
 func (m *testMachine) InstanceId() (instance.Id, error) {
+	if m.instance == nil {
+		return "", &params.Error{Code: params.CodeNotProvisioned}
+	}
 	return m.instance.Id(), nil
 }
 
@@ -558,6 +779,10 @@ func (m *testMachine) KeepInstance() (bool, error) {
 	return m.keepInstance, nil
 }
 
+func (m *testMachine) Constraints() (constraints.Value, error) {
+	return constraints.MustParse(m.constraints), nil
+}
+
 func (m *testMachine) MarkForRemoval() error {
 	m.markForRemoval = true
 	return nil
@@ -622,6 +847,8 @@ func (m *testMachine) ProvisioningInfo() (*params.ProvisioningInfo, error) {
 		ControllerConfig: coretesting.FakeControllerConfig(),
 		Series:           series.DefaultSupportedLTS(),
 		Constraints:      constraints.MustParse(m.constraints),
+		Placement:        m.placement,
+		CloudSpec:        m.cloudSpec,
 	}, nil
 }
 
@@ -0,0 +1,125 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/featureflag"
+
+	"github.com/juju/juju/api/common/cloudspec"
+	apiprovisioner "github.com/juju/juju/api/provisioner"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/feature"
+)
+
+// newRegionBroker returns a broker for another cloud region. It is a variable so that it can be replaced in tests.
+var newRegionBroker = func(controllerUUID string, spec environs.CloudSpec, cfg *config.Config) (environs.InstanceBroker, error) {
+	return environs.New(environs.OpenParams{
+		ControllerUUID: controllerUUID,
+		Cloud:          spec,
+		Config:         cfg,
+	})
+}
+
+// brokerForMachine returns the broker to start the instance of a machine
+// with the given provisioning info. Machines placed in another cloud
+// region are started by a broker for that region.
+func (task *provisionerTask) brokerForMachine(pInfo *params.ProvisioningInfo) (environs.InstanceBroker, error) {
+	if pInfo.CloudSpec == nil {
+		return task.broker, nil
+	}
+	return task.regionBroker(pInfo.CloudSpec)
+}
+
+// regionBroker returns the broker for the cloud region with the given
+// spec, opening it the first time the region is used.
+func (task *provisionerTask) regionBroker(pSpec *params.CloudSpec) (environs.InstanceBroker, error) {
+	task.regionBrokersMutex.Lock()
+	defer task.regionBrokersMutex.Unlock()
+	key := regionBrokerKey(pSpec)
+	if broker, ok := task.regionBrokers[key]; ok {
+		return broker, nil
+	}
+	// Only the model's environ can open its other regions.
+	configGetter, ok := task.broker.(environs.ConfigGetter)
+	if !ok {
+		return nil, errors.NotSupportedf("starting instances in region %q", pSpec.Region)
+	}
+	var specAPI cloudspec.CloudSpecAPI
+	spec, err := specAPI.MakeCloudSpec(pSpec)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cfg := configGetter.Config()
+	if spec.Type != cfg.Type() {
+		// The region is in a cloud of another type.
+		if cfg, err = cfg.Apply(map[string]interface{}{config.TypeKey: spec.Type}); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	broker, err := newRegionBroker(task.controllerUUID, spec, cfg)
+	if err != nil {
+		return nil, errors.Annotatef(err, "opening broker for region %q", pSpec.Region)
+	}
+	task.regionBrokers[key] = broker
+	return broker, nil
+}
+
+// regionBrokerKey returns the key of the broker for the cloud region with
+// the given spec. Regions used with different credentials have different
+// brokers.
+func regionBrokerKey(pSpec *params.CloudSpec) string {
+	key := pSpec.Name + "/" + pSpec.Region
+	if pSpec.Credential == nil {
+		return key
+	}
+	attrs := make([]string, 0, len(pSpec.Credential.Attributes))
+	for k, v := range pSpec.Credential.Attributes {
+		attrs = append(attrs, k+"="+v)
+	}
+	sort.Strings(attrs)
+	return key + "/" + pSpec.Credential.AuthType + ":" + strings.Join(attrs, ",")
+}
+
+// stopRegionInstances stops the instances of the dead machines that were
+// placed in other cloud regions. The model's broker does
+// not report those instances, so they are not stopped with the rest.
+func (task *provisionerTask) stopRegionInstances(dead []apiprovisioner.MachineProvisioner) error {
+	if !featureflag.Enabled(feature.MultiCloud) {
+		return nil
+	}
+	for _, machine := range dead {
+		instId, err := machine.InstanceId()
+		if err != nil {
+			continue
+		}
+		if _, found := task.instances[instId]; found {
+			continue
+		}
+		if keep, _ := machine.KeepInstance(); keep {
+			continue
+		}
+		pInfo, err := machine.ProvisioningInfo()
+		if err != nil {
+			return errors.Annotatef(err, "fetching provisioning info for machine %q", machine)
+		}
+		if pInfo.CloudSpec == nil {
+			continue
+		}
+		broker, err := task.regionBroker(pInfo.CloudSpec)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		task.logger.Infof("stopping instance %q in region %q", instId, pInfo.CloudSpec.Region)
+		if err := broker.StopInstances(task.cloudCallCtx, instId); err != nil {
+			return errors.Annotatef(err, "broker failed to stop instance in region %q", pInfo.CloudSpec.Region)
+		}
+	}
+	return nil
+}
@@ -22,39 +22,21 @@ func machineBlockDevicesChanged(ctx *context) error {
 	// in different sessions, and there is no guarantee that
 	// the block device will remain attached to the machine
 	// in between.
+	//
+	// Only the backing-volumes whose block devices are not
+	// already attached are queried.
 	for _, params := range ctx.incompleteFilesystemParams {
-		if params.Volume == (names.VolumeTag{}) {
-			// Filesystem is not volume-backed.
-			continue
-		}
-		if _, ok := ctx.volumeBlockDevices[params.Volume]; ok {
-			// Backing-volume's block device is already attached.
-			continue
+		for _, tag := range missingVolumeBlockDevices(ctx, params.Tag, params.Volume) {
+			volumeTags = appendVolumeTag(volumeTags, tag)
 		}
-		volumeTags = append(volumeTags, params.Volume)
 	}
 	for _, params := range ctx.incompleteFilesystemAttachmentParams {
 		filesystem, ok := ctx.filesystems[params.Filesystem]
 		if !ok {
 			continue
 		}
-		if filesystem.Volume == (names.VolumeTag{}) {
-			// Filesystem is not volume-backed.
-			continue
-		}
-		if _, ok := ctx.volumeBlockDevices[filesystem.Volume]; ok {
-			// Backing-volume's block device is already attached.
-			continue
-		}
-		var found bool
-		for _, tag := range volumeTags {
-			if filesystem.Volume == tag {
-				found = true
-				break
-			}
-		}
-		if !found {
-			volumeTags = append(volumeTags, filesystem.Volume)
+		for _, tag := range missingVolumeBlockDevices(ctx, filesystem.Tag, filesystem.Volume) {
+			volumeTags = appendVolumeTag(volumeTags, tag)
 		}
 	}
 	if len(volumeTags) == 0 {
@@ -101,23 +83,11 @@ func refreshVolumeBlockDevices(ctx *context, volumeTags []names.VolumeTag) error
 	if err != nil {
 		return errors.Annotate(err, "refreshing volume block devices")
 	}
+	var attached []names.VolumeTag
 	for i, result := range results {
 		if result.Error == nil {
 			ctx.volumeBlockDevices[volumeTags[i]] = result.Result
-			for _, params := range ctx.incompleteFilesystemParams {
-				if params.Volume == volumeTags[i] {
-					updatePendingFilesystem(ctx, params)
-				}
-			}
-			for id, params := range ctx.incompleteFilesystemAttachmentParams {
-				filesystem, ok := ctx.filesystems[params.Filesystem]
-				if !ok {
-					continue
-				}
-				if filesystem.Volume == volumeTags[i] {
-					updatePendingFilesystemAttachment(ctx, id, params)
-				}
-			}
+			attached = append(attached, volumeTags[i])
 		} else if params.IsCodeNotProvisioned(result.Error) || params.IsCodeNotFound(result.Error) {
 			// Either the volume (attachment) isn't provisioned,
 			// or the corresponding block device is not yet known.
@@ -131,5 +101,91 @@ func refreshVolumeBlockDevices(ctx *context, volumeTags []names.VolumeTag) error
 			)
 		}
 	}
+	// Filesystems on encrypted volumes cannot be created or
+	// attached until we have the volumes' encryption keys.
+	if err := refreshVolumeEncryptionKeys(ctx, machineTag, attached); err != nil {
+		return errors.Trace(err)
+	}
+	for _, volumeTag := range attached {
+		for _, params := range ctx.incompleteFilesystemParams {
+			if containsVolumeTag(filesystemVolumes(ctx, params.Tag, params.Volume), volumeTag) {
+				updatePendingFilesystem(ctx, params)
+			}
+		}
+		for id, params := range ctx.incompleteFilesystemAttachmentParams {
+			filesystem, ok := ctx.filesystems[params.Filesystem]
+			if !ok {
+				continue
+			}
+			if containsVolumeTag(filesystemVolumes(ctx, filesystem.Tag, filesystem.Volume), volumeTag) {
+				updatePendingFilesystemAttachment(ctx, id, params)
+			}
+		}
+	}
+	return nil
+}
+
+// containsVolumeTag reports whether or not the given volume tag
+// is in the given slice.
+func containsVolumeTag(tags []names.VolumeTag, tag names.VolumeTag) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// appendVolumeTag appends the given volume tag to the given slice,
+// if it is not already there.
+func appendVolumeTag(tags []names.VolumeTag, tag names.VolumeTag) []names.VolumeTag {
+	if containsVolumeTag(tags, tag) {
+		return tags
+	}
+	return append(tags, tag)
+}
+
+// refreshVolumeEncryptionKeys obtains the encryption keys for the specified
+// volumes attached to the scope-machine, if they are encrypted and we do not
+// already have their keys.
+func refreshVolumeEncryptionKeys(ctx *context, machineTag names.MachineTag, volumeTags []names.VolumeTag) error {
+	if ctx.config.EncryptionKeys == nil {
+		return nil
+	}
+	var ids []params.MachineStorageId
+	var idVolumeTags []names.VolumeTag
+	for _, volumeTag := range volumeTags {
+		if _, ok := ctx.volumeEncryptionKeys[volumeTag]; ok {
+			continue
+		}
+		ids = append(ids, params.MachineStorageId{
+			MachineTag:    machineTag.String(),
+			AttachmentTag: volumeTag.String(),
+		})
+		idVolumeTags = append(idVolumeTags, volumeTag)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	results, err := ctx.config.EncryptionKeys.VolumeEncryptionKeys(ids)
+	if errors.IsNotImplemented(err) {
+		// The controller predates volume encryption,
+		// so none of the volumes are encrypted.
+		ctx.config.Logger.Debugf("volume encryption not supported by controller")
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "getting volume encryption keys")
+	}
+	for i, result := range results {
+		if result.Error == nil {
+			ctx.volumeEncryptionKeys[idVolumeTags[i]] = result.Result
+		} else if !params.IsCodeNotFound(result.Error) {
+			// NotFound means that the volume is not encrypted.
+			return errors.Annotatef(
+				result.Error, "getting encryption key for volume attachment %v",
+				ids[i],
+			)
+		}
+	}
 	return nil
 }
@@ -21,6 +21,12 @@ type Logger interface {
 }
 
 // Config holds configuration and dependencies for a storageprovisioner worker.
+//
+// Snapshots is optional; if it is nil, the worker will not take or
+// delete storage snapshots. Likewise, Resizes is optional; if it is nil, the worker will
+// not resize storage, and Copies is optional; if it is nil, the worker
+// will not copy data between volumes when storage is moved. EncryptionKeys
+// is also optional; if it is nil, the worker will not encrypt volumes.
 type Config struct {
 	Model            names.ModelTag
 	Scope            names.Tag
@@ -28,6 +34,10 @@ type Config struct {
 	Applications     ApplicationWatcher
 	Volumes          VolumeAccessor
 	Filesystems      FilesystemAccessor
+	Snapshots        SnapshotAccessor
+	Resizes          ResizeAccessor
+	Copies           VolumeCopyAccessor
+	EncryptionKeys   EncryptionKeyAccessor
 	Life             LifecycleManager
 	Registry         storage.ProviderRegistry
 	Machines         MachineAccessor
@@ -0,0 +1,417 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+	"gopkg.in/juju/worker.v1/catacomb"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/status"
+	"github.com/juju/juju/storage"
+)
+
+// copyBlockDevice copies the contents of the block device at sourcePath
+// to the block device at targetPath. The copy is stopped if the abort
+// channel is closed. It is a variable so that it can be replaced for
+// testing.
+var copyBlockDevice = func(sourcePath, targetPath string, abort <-chan struct{}) error {
+	cmd := exec.Command(
+		"dd", "if="+sourcePath, "of="+targetPath,
+		"bs=4M", "conv=fsync", "status=none",
+	)
+	var output strings.Builder
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return errors.Trace(err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			if output := strings.TrimSpace(output.String()); output != "" {
+				err = errors.Annotate(err, output)
+			}
+			return err
+		}
+		return nil
+	case <-abort:
+		cmd.Process.Kill()
+		<-done
+		return errors.New("copy aborted")
+	}
+}
+
+// volumeCopy holds the details of a copy of data between volumes
+// that is in progress.
+type volumeCopy struct {
+	params params.VolumeCopyParams
+
+	// targetBlockDevice is the block device of the target volume.
+	targetBlockDevice storage.BlockDevice
+
+	// filesystemAttachment holds the parameters for remounting the
+	// filesystem backed by the source volume once the copy is done,
+	// or nil if block storage is being copied.
+	filesystemAttachment *storage.FilesystemAttachmentParams
+}
+
+// volumeCopiesChanged is called when requests to copy data to the
+// volumes with the specified IDs, attached to the scope machine, have
+// been seen to have changed.
+func volumeCopiesChanged(ctx *context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	machineStorageIds := make([]params.MachineStorageId, len(ids))
+	for i, id := range ids {
+		machineStorageIds[i] = params.MachineStorageId{
+			MachineTag:    ctx.config.Scope.String(),
+			AttachmentTag: names.NewVolumeTag(id).String(),
+		}
+	}
+	paramsResults, err := ctx.config.Copies.VolumeCopyParams(machineStorageIds)
+	if err != nil {
+		return errors.Annotate(err, "getting volume copy params")
+	}
+	for i, result := range paramsResults {
+		tag := names.NewVolumeTag(ids[i])
+		if result.Error != nil {
+			// The copy has completed or been abandoned
+			// since the change was observed.
+			ctx.config.Logger.Debugf("ignoring copy to %s: %v", names.ReadableString(tag), result.Error)
+			delete(ctx.pendingVolumeCopies, tag)
+			continue
+		}
+		if _, ok := ctx.copyingVolumes[tag]; ok {
+			// The copy is already in progress.
+			continue
+		}
+		ctx.pendingVolumeCopies[tag] = *result.Result
+	}
+	return processPendingVolumeCopies(ctx)
+}
+
+// processPendingVolumeCopies starts copying data between the volumes of
+// each pending copy whose source and target block devices are both
+// known. Copies whose block devices are not yet visible on the machine,
+// or whose filesystem is not yet mounted, remain pending, and are
+// retried when the machine's block devices or attachments change.
+//
+// Each copy is run by a volumeCopier worker, which reports the outcome
+// on ctx.volumeCopyResults; see volumeCopied.
+func processPendingVolumeCopies(ctx *context) error {
+	if len(ctx.pendingVolumeCopies) == 0 {
+		return nil
+	}
+	var statuses []params.EntityStatusArgs
+	for tag, copyParams := range ctx.pendingVolumeCopies {
+		sourceTag, err := names.ParseVolumeTag(copyParams.SourceVolumeTag)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sourceDevice, targetDevice, err := volumeCopyBlockDevices(ctx, copyParams)
+		if errors.IsNotFound(err) {
+			ctx.config.Logger.Debugf("waiting to copy to %s: %v", names.ReadableString(tag), err)
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		sourcePath, err := storage.BlockDevicePath(sourceDevice)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		targetPath, err := storage.BlockDevicePath(targetDevice)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		filesystemAttachment, err := volumeCopyFilesystemAttachment(ctx, copyParams)
+		if errors.IsNotFound(err) {
+			ctx.config.Logger.Debugf("waiting to copy to %s: %v", names.ReadableString(tag), err)
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+		delete(ctx.pendingVolumeCopies, tag)
+
+		inProgress := volumeCopy{
+			params:               copyParams,
+			targetBlockDevice:    targetDevice,
+			filesystemAttachment: filesystemAttachment,
+		}
+		if filesystemAttachment != nil {
+			// The filesystem must not be modified while its
+			// data is copied, so it is unmounted until the
+			// copy is done.
+			if err := unmountFilesystem(ctx, *filesystemAttachment); err != nil {
+				ctx.config.Logger.Debugf("failed to copy to %s: %v", names.ReadableString(tag), err)
+				if err := publishVolumeCopyResults(ctx, []params.VolumeCopyResult{{
+					MachineTag: copyParams.MachineTag,
+					VolumeTag:  copyParams.TargetVolumeTag,
+					Message:    err.Error(),
+				}}); err != nil {
+					return errors.Trace(err)
+				}
+				continue
+			}
+		}
+
+		w, err := newVolumeCopier(copyParams, sourcePath, targetPath, ctx.volumeCopyResults, ctx.config.Logger)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := ctx.addWorker(w); err != nil {
+			return errors.Trace(err)
+		}
+		ctx.copyingVolumes[tag] = inProgress
+		statuses = append(statuses, params.EntityStatusArgs{
+			Tag:    tag.String(),
+			Status: status.Attached.String(),
+			Info:   fmt.Sprintf("copying data from %s", names.ReadableString(sourceTag)),
+		})
+	}
+	setStatus(ctx, statuses)
+	return nil
+}
+
+// volumeCopied is called when a volumeCopier has finished copying data
+// to a volume. Any filesystem backed by the source volume is mounted
+// again from the target volume if the copy succeeded, or from the
+// source volume if it failed, and the outcome is recorded in state.
+func volumeCopied(ctx *context, result params.VolumeCopyResult) error {
+	tag, err := names.ParseVolumeTag(result.VolumeTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	inProgress, ok := ctx.copyingVolumes[tag]
+	if !ok {
+		return errors.Errorf("copy to %s is not in progress", names.ReadableString(tag))
+	}
+	delete(ctx.copyingVolumes, tag)
+
+	if inProgress.filesystemAttachment != nil {
+		if err := remountCopiedFilesystem(ctx, inProgress, result.Message == ""); err != nil {
+			ctx.config.Logger.Debugf("failed to copy to %s: %v", names.ReadableString(tag), err)
+			result.Message = err.Error()
+		}
+	}
+	if result.Message == "" {
+		setStatus(ctx, []params.EntityStatusArgs{{
+			Tag:    tag.String(),
+			Status: status.Attached.String(),
+		}})
+	}
+	return publishVolumeCopyResults(ctx, []params.VolumeCopyResult{result})
+}
+
+// publishVolumeCopyResults records the outcomes of copies of data to
+// volumes in state.
+func publishVolumeCopyResults(ctx *context, results []params.VolumeCopyResult) error {
+	errorResults, err := ctx.config.Copies.SetVolumeCopyResults(results)
+	if err != nil {
+		return errors.Annotate(err, "publishing volume copies to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"publishing copy to %s to state: %v",
+				results[i].VolumeTag, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// volumeCopyBlockDevices returns the source and target block devices
+// for the specified volume copy. If either block device is not yet
+// known, an error satisfying errors.IsNotFound is returned.
+func volumeCopyBlockDevices(ctx *context, copyParams params.VolumeCopyParams) (storage.BlockDevice, storage.BlockDevice, error) {
+	ids := []params.MachineStorageId{{
+		MachineTag:    copyParams.MachineTag,
+		AttachmentTag: copyParams.SourceVolumeTag,
+	}, {
+		MachineTag:    copyParams.MachineTag,
+		AttachmentTag: copyParams.TargetVolumeTag,
+	}}
+	results, err := ctx.config.Volumes.VolumeBlockDevices(ids)
+	if err != nil {
+		return storage.BlockDevice{}, storage.BlockDevice{}, errors.Annotate(err, "getting volume block devices")
+	}
+	blockDevices := make([]storage.BlockDevice, len(results))
+	for i, result := range results {
+		if result.Error != nil {
+			if params.IsCodeNotProvisioned(result.Error) || params.IsCodeNotFound(result.Error) {
+				return storage.BlockDevice{}, storage.BlockDevice{}, errors.NotFoundf(
+					"block device for %s", ids[i].AttachmentTag,
+				)
+			}
+			return storage.BlockDevice{}, storage.BlockDevice{}, errors.Annotatef(
+				result.Error, "getting block device info for volume attachment %v", ids[i],
+			)
+		}
+		blockDevices[i] = result.Result
+	}
+	return blockDevices[0], blockDevices[1], nil
+}
+
+// volumeCopyFilesystemAttachment returns the parameters of the mounted
+// filesystem whose data is to be copied, or nil if block storage is
+// being copied. If the filesystem is not yet mounted by this
+// provisioner, an error satisfying errors.IsNotFound is returned.
+func volumeCopyFilesystemAttachment(ctx *context, copyParams params.VolumeCopyParams) (*storage.FilesystemAttachmentParams, error) {
+	if copyParams.FilesystemTag == "" {
+		return nil, nil
+	}
+	id := params.MachineStorageId{
+		MachineTag:    copyParams.MachineTag,
+		AttachmentTag: copyParams.FilesystemTag,
+	}
+	attachment, ok := ctx.filesystemAttachments[id]
+	if !ok {
+		return nil, errors.NotFoundf("attachment of %s", copyParams.FilesystemTag)
+	}
+	filesystem, ok := ctx.filesystems[attachment.Filesystem]
+	if !ok {
+		return nil, errors.NotFoundf("%s", copyParams.FilesystemTag)
+	}
+	attachmentParams, err := filesystemAttachmentParams(ctx, []params.MachineStorageId{id})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	result := attachmentParams[0]
+	result.FilesystemId = filesystem.FilesystemId
+	result.Path = attachment.Path
+	return &result, nil
+}
+
+// unmountFilesystem unmounts the filesystem with the specified
+// attachment parameters.
+func unmountFilesystem(ctx *context, attachment storage.FilesystemAttachmentParams) error {
+	errs, err := ctx.managedFilesystemSource.DetachFilesystems(
+		ctx.config.CloudCallContext, []storage.FilesystemAttachmentParams{attachment},
+	)
+	if err == nil {
+		err = errs[0]
+	}
+	return errors.Annotatef(err, "unmounting %s", names.ReadableString(attachment.Filesystem))
+}
+
+// remountCopiedFilesystem mounts the filesystem whose data was copied,
+// from the target volume if copied is true, and otherwise from the
+// source volume. If the filesystem cannot be mounted from the target
+// volume, it is mounted from the source volume again and an error is
+// returned, so that the move is recorded as failed.
+func remountCopiedFilesystem(ctx *context, inProgress volumeCopy, copied bool) error {
+	attachment := *inProgress.filesystemAttachment
+	filesystem, ok := ctx.filesystems[attachment.Filesystem]
+	if !ok {
+		return errors.NotFoundf("%s", names.ReadableString(attachment.Filesystem))
+	}
+	sourceVolume := filesystem.Volume
+	mount := func(volume names.VolumeTag) error {
+		filesystem.Volume = volume
+		ctx.filesystems[attachment.Filesystem] = filesystem
+		results, err := ctx.managedFilesystemSource.AttachFilesystems(
+			ctx.config.CloudCallContext, []storage.FilesystemAttachmentParams{attachment},
+		)
+		if err == nil {
+			err = results[0].Error
+		}
+		return errors.Annotatef(err, "mounting %s", names.ReadableString(attachment.Filesystem))
+	}
+	if !copied {
+		return mount(sourceVolume)
+	}
+	targetVolume, err := names.ParseVolumeTag(inProgress.params.TargetVolumeTag)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	ctx.volumeBlockDevices[targetVolume] = inProgress.targetBlockDevice
+	err = mount(targetVolume)
+	if err == nil {
+		return nil
+	}
+	if err := mount(sourceVolume); err != nil {
+		ctx.config.Logger.Errorf("remounting filesystem from source volume: %v", err)
+	}
+	return errors.Trace(err)
+}
+
+// volumeCopier is a worker that copies the contents of one volume to
+// another, and sends the outcome on a channel.
+type volumeCopier struct {
+	catacomb   catacomb.Catacomb
+	params     params.VolumeCopyParams
+	sourcePath string
+	targetPath string
+	out        chan<- params.VolumeCopyResult
+	logger     Logger
+}
+
+func newVolumeCopier(
+	copyParams params.VolumeCopyParams,
+	sourcePath, targetPath string,
+	out chan<- params.VolumeCopyResult,
+	logger Logger,
+) (*volumeCopier, error) {
+	w := &volumeCopier{
+		params:     copyParams,
+		sourcePath: sourcePath,
+		targetPath: targetPath,
+		out:        out,
+		logger:     logger,
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &w.catacomb,
+		Work: w.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return w, nil
+}
+
+func (vc *volumeCopier) loop() error {
+	result := params.VolumeCopyResult{
+		MachineTag: vc.params.MachineTag,
+		VolumeTag:  vc.params.TargetVolumeTag,
+	}
+	vc.logger.Debugf("copying %s to %s", vc.sourcePath, vc.targetPath)
+	if err := copyBlockDevice(vc.sourcePath, vc.targetPath, vc.catacomb.Dying()); err != nil {
+		select {
+		case <-vc.catacomb.Dying():
+			return vc.catacomb.ErrDying()
+		default:
+		}
+		vc.logger.Debugf("failed to copy %s to %s: %v", vc.sourcePath, vc.targetPath, err)
+		result.Message = err.Error()
+	} else {
+		vc.logger.Debugf("copied %s to %s", vc.sourcePath, vc.targetPath)
+	}
+	select {
+	case <-vc.catacomb.Dying():
+		return vc.catacomb.ErrDying()
+	case vc.out <- result:
+	}
+	return nil
+}
+
+// Kill is part of the worker.Worker interface.
+func (vc *volumeCopier) Kill() {
+	vc.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (vc *volumeCopier) Wait() error {
+	return vc.catacomb.Wait()
+}
@@ -125,24 +125,71 @@ func updateFilesystem(ctx *context, info storage.Filesystem) {
 }
 
 func updatePendingFilesystem(ctx *context, params storage.FilesystemParams) {
-	if params.Volume != (names.VolumeTag{}) {
-		// The filesystem is volume-backed: we must watch for
-		// the corresponding block device. This will trigger a
-		// one-time (for the volume) forced update of block
-		// devices. If the block device is not immediately
-		// available, then we rely on the watcher. The forced
-		// update is necessary in case the block device was
-		// added to state already, and we didn't observe it.
-		if _, ok := ctx.volumeBlockDevices[params.Volume]; !ok {
-			ctx.pendingVolumeBlockDevices.Add(params.Volume)
-			ctx.incompleteFilesystemParams[params.Tag] = params
-			return
+	// If the filesystem is volume-backed, we must watch for
+	// the corresponding block devices. This will trigger a
+	// one-time (for the volume) forced update of block
+	// devices. If the block devices are not immediately
+	// available, then we rely on the watcher. The forced
+	// update is necessary in case the block devices were
+	// added to state already, and we didn't observe them.
+	if missing := missingVolumeBlockDevices(ctx, params.Tag, params.Volume); len(missing) > 0 {
+		for _, volumeTag := range missing {
+			ctx.pendingVolumeBlockDevices.Add(volumeTag)
 		}
+		ctx.incompleteFilesystemParams[params.Tag] = params
+		return
 	}
 	delete(ctx.incompleteFilesystemParams, params.Tag)
 	scheduleOperations(ctx, &createFilesystemOp{args: params})
 }
 
+// updateFilesystemComposition records the composition of the volumes
+// backing the filesystem with the specified tag, if it is backed by
+// composed volumes.
+func updateFilesystemComposition(ctx *context, tag names.FilesystemTag, in *params.FilesystemComposition) error {
+	if in == nil {
+		return nil
+	}
+	volumeTags := make([]names.VolumeTag, len(in.VolumeTags))
+	for i, tagString := range in.VolumeTags {
+		volumeTag, err := names.ParseVolumeTag(tagString)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		volumeTags[i] = volumeTag
+	}
+	ctx.filesystemCompositions[tag] = storage.FilesystemComposition{
+		Type:    storage.CompositionType(in.Type),
+		Volumes: volumeTags,
+	}
+	return nil
+}
+
+// filesystemVolumes returns the tags of the volumes backing the filesystem
+// with the specified tag and backing volume, if any.
+func filesystemVolumes(ctx *context, tag names.FilesystemTag, volume names.VolumeTag) []names.VolumeTag {
+	if composition, ok := ctx.filesystemCompositions[tag]; ok {
+		return composition.Volumes
+	}
+	if volume == (names.VolumeTag{}) {
+		return nil
+	}
+	return []names.VolumeTag{volume}
+}
+
+// missingVolumeBlockDevices returns the tags of the volumes backing the
+// filesystem with the specified tag and backing volume whose block devices
+// have not yet been observed.
+func missingVolumeBlockDevices(ctx *context, tag names.FilesystemTag, volume names.VolumeTag) []names.VolumeTag {
+	var missing []names.VolumeTag
+	for _, volumeTag := range filesystemVolumes(ctx, tag, volume) {
+		if _, ok := ctx.volumeBlockDevices[volumeTag]; !ok {
+			missing = append(missing, volumeTag)
+		}
+	}
+	return missing
+}
+
 func removePendingFilesystem(ctx *context, tag names.FilesystemTag) {
 	delete(ctx.incompleteFilesystemParams, tag)
 	ctx.schedule.Remove(tag)
@@ -163,14 +210,12 @@ func updatePendingFilesystemAttachment(
 		incomplete = true
 	} else {
 		params.FilesystemId = filesystem.FilesystemId
-		if filesystem.Volume != (names.VolumeTag{}) {
-			// The filesystem is volume-backed: if the filesystem
-			// was created in another session, then the block device
-			// may not have been seen yet. We must wait for the block
-			// device watcher to trigger.
-			if _, ok := ctx.volumeBlockDevices[filesystem.Volume]; !ok {
-				incomplete = true
-			}
+		// If the filesystem is volume-backed, and the filesystem
+		// was created in another session, then the block devices
+		// may not have been seen yet. We must wait for the block
+		// device watcher to trigger.
+		if len(missingVolumeBlockDevices(ctx, filesystem.Tag, filesystem.Volume)) > 0 {
+			incomplete = true
 		}
 	}
 	if params.InstanceId == "" {
@@ -212,6 +257,9 @@ func processDeadFilesystems(ctx *context, tags []names.FilesystemTag, filesystem
 			if err != nil {
 				return errors.Annotate(err, "getting filesystem info")
 			}
+			if err := updateFilesystemComposition(ctx, tag, result.Result.Composition); err != nil {
+				return errors.Annotate(err, "getting filesystem info")
+			}
 			updateFilesystem(ctx, filesystem)
 			destroy = append(destroy, tag)
 			continue
@@ -291,13 +339,16 @@ func processAliveFilesystems(ctx *context, tags []names.FilesystemTag, filesyste
 			if err != nil {
 				return errors.Annotate(err, "getting filesystem info")
 			}
+			if err := updateFilesystemComposition(ctx, tag, result.Result.Composition); err != nil {
+				return errors.Annotate(err, "getting filesystem info")
+			}
 			updateFilesystem(ctx, filesystem)
 			if !ctx.isApplicationKind() {
-				if filesystem.Volume != (names.VolumeTag{}) {
-					// Ensure that volume-backed filesystems' block
-					// devices are present even after creating the
-					// filesystem, so that attachments can be made.
-					maybeAddPendingVolumeBlockDevice(ctx, filesystem.Volume)
+				// Ensure that volume-backed filesystems' block
+				// devices are present even after creating the
+				// filesystem, so that attachments can be made.
+				for _, volumeTag := range filesystemVolumes(ctx, tag, filesystem.Volume) {
+					maybeAddPendingVolumeBlockDevice(ctx, volumeTag)
 				}
 			}
 			continue
@@ -425,6 +476,9 @@ func filesystemParams(ctx *context, tags []names.FilesystemTag) ([]storage.Files
 		if err != nil {
 			return nil, errors.Annotate(err, "getting filesystem parameters")
 		}
+		if err := updateFilesystemComposition(ctx, params.Tag, result.Result.Composition); err != nil {
+			return nil, errors.Annotate(err, "getting filesystem parameters")
+		}
 		allParams[i] = params
 	}
 	return allParams, nil
@@ -488,6 +542,7 @@ func filesystemParamsFromParams(in params.FilesystemParams) (storage.FilesystemP
 		Provider:     providerType,
 		Attributes:   in.Attributes,
 		ResourceTags: in.Tags,
+		SnapshotId:   in.SnapshotId,
 	}, nil
 }
 
@@ -508,6 +508,7 @@ func filesystemsFromStorage(in []storage.Filesystem) []params.Filesystem {
 				"", // pool
 				f.Size,
 			},
+			nil, // composition is recorded by state
 		}
 		if f.Volume != (names.VolumeTag{}) {
 			paramsFilesystem.VolumeTag = f.Volume.String()
@@ -58,6 +58,10 @@ func (config MachineManifoldConfig) newWorker(a agent.Agent, apiCaller base.APIC
 		StorageDir:       storageDir,
 		Volumes:          api,
 		Filesystems:      api,
+		Snapshots:        api,
+		Resizes:          api,
+		Copies:           api,
+		EncryptionKeys:   api,
 		Life:             api,
 		Registry:         provider.CommonStorageProviders(),
 		Machines:         api,
@@ -60,6 +60,9 @@ func ModelManifold(config ModelManifoldConfig) dependency.Manifold {
 				Applications:     api,
 				Volumes:          api,
 				Filesystems:      api,
+				Snapshots:        api,
+				Resizes:          api,
+				Copies:           api,
 				Life:             api,
 				Registry:         registry,
 				Machines:         api,
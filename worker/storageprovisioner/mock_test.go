@@ -337,6 +337,7 @@ type mockFilesystemAccessor struct {
 	provisionedMachines    map[string]instance.Id
 	provisionedFilesystems map[string]params.Filesystem
 	provisionedAttachments map[params.MachineStorageId]params.FilesystemAttachment
+	compositions           map[string]*params.FilesystemComposition
 
 	setFilesystemInfo           func([]params.Filesystem) ([]params.ErrorResult, error)
 	setFilesystemAttachmentInfo func([]params.FilesystemAttachment) ([]params.ErrorResult, error)
@@ -406,6 +407,7 @@ func (v *mockFilesystemAccessor) FilesystemParams(filesystems []names.Filesystem
 			// volumes with the same ID as the filesystem.
 			filesystemParams.VolumeTag = names.NewVolumeTag(tag.Id()).String()
 		}
+		filesystemParams.Composition = v.compositions[tag.String()]
 		results[i] = params.FilesystemParamsResult{Result: filesystemParams}
 	}
 	return results, nil
@@ -469,9 +471,101 @@ func newMockFilesystemAccessor() *mockFilesystemAccessor {
 		provisionedMachines:    make(map[string]instance.Id),
 		provisionedFilesystems: make(map[string]params.Filesystem),
 		provisionedAttachments: make(map[params.MachineStorageId]params.FilesystemAttachment),
+		compositions:           make(map[string]*params.FilesystemComposition),
 	}
 }
 
+type mockSnapshotAccessor struct {
+	snapshotsWatcher          *mockStringsWatcher
+	storageSnapshotParams     func([]string) ([]params.StorageSnapshotParamsResult, error)
+	setStorageSnapshotResults func([]params.StorageSnapshotResult) ([]params.ErrorResult, error)
+	removeStorageSnapshots    func([]string) ([]params.ErrorResult, error)
+}
+
+func (m *mockSnapshotAccessor) WatchStorageSnapshots(names.Tag) (watcher.StringsWatcher, error) {
+	return m.snapshotsWatcher, nil
+}
+
+func (m *mockSnapshotAccessor) StorageSnapshotParams(ids []string) ([]params.StorageSnapshotParamsResult, error) {
+	return m.storageSnapshotParams(ids)
+}
+
+func (m *mockSnapshotAccessor) SetStorageSnapshotResults(results []params.StorageSnapshotResult) ([]params.ErrorResult, error) {
+	return m.setStorageSnapshotResults(results)
+}
+
+func (m *mockSnapshotAccessor) RemoveStorageSnapshots(ids []string) ([]params.ErrorResult, error) {
+	return m.removeStorageSnapshots(ids)
+}
+
+func newMockSnapshotAccessor() *mockSnapshotAccessor {
+	return &mockSnapshotAccessor{
+		snapshotsWatcher: newMockStringsWatcher(),
+	}
+}
+
+type mockResizeAccessor struct {
+	volumeResizesWatcher     *mockStringsWatcher
+	filesystemResizesWatcher *mockStringsWatcher
+	storageResizeParams      func([]names.Tag) ([]params.StorageResizeParamsResult, error)
+	setStorageResizeResults  func([]params.StorageResizeResult) ([]params.ErrorResult, error)
+}
+
+func (m *mockResizeAccessor) WatchVolumeResizes(names.Tag) (watcher.StringsWatcher, error) {
+	return m.volumeResizesWatcher, nil
+}
+
+func (m *mockResizeAccessor) WatchFilesystemResizes(names.Tag) (watcher.StringsWatcher, error) {
+	return m.filesystemResizesWatcher, nil
+}
+
+func (m *mockResizeAccessor) StorageResizeParams(tags []names.Tag) ([]params.StorageResizeParamsResult, error) {
+	return m.storageResizeParams(tags)
+}
+
+func (m *mockResizeAccessor) SetStorageResizeResults(results []params.StorageResizeResult) ([]params.ErrorResult, error) {
+	return m.setStorageResizeResults(results)
+}
+
+func newMockResizeAccessor() *mockResizeAccessor {
+	return &mockResizeAccessor{
+		volumeResizesWatcher:     newMockStringsWatcher(),
+		filesystemResizesWatcher: newMockStringsWatcher(),
+	}
+}
+
+type mockVolumeCopyAccessor struct {
+	volumeCopiesWatcher  *mockStringsWatcher
+	volumeCopyParams     func([]params.MachineStorageId) ([]params.VolumeCopyParamsResult, error)
+	setVolumeCopyResults func([]params.VolumeCopyResult) ([]params.ErrorResult, error)
+}
+
+func (m *mockVolumeCopyAccessor) WatchVolumeCopies(names.MachineTag) (watcher.StringsWatcher, error) {
+	return m.volumeCopiesWatcher, nil
+}
+
+func (m *mockVolumeCopyAccessor) VolumeCopyParams(ids []params.MachineStorageId) ([]params.VolumeCopyParamsResult, error) {
+	return m.volumeCopyParams(ids)
+}
+
+func (m *mockVolumeCopyAccessor) SetVolumeCopyResults(results []params.VolumeCopyResult) ([]params.ErrorResult, error) {
+	return m.setVolumeCopyResults(results)
+}
+
+func newMockVolumeCopyAccessor() *mockVolumeCopyAccessor {
+	return &mockVolumeCopyAccessor{
+		volumeCopiesWatcher: newMockStringsWatcher(),
+	}
+}
+
+type mockEncryptionKeyAccessor struct {
+	volumeEncryptionKeys func([]params.MachineStorageId) ([]params.StringResult, error)
+}
+
+func (m *mockEncryptionKeyAccessor) VolumeEncryptionKeys(ids []params.MachineStorageId) ([]params.StringResult, error) {
+	return m.volumeEncryptionKeys(ids)
+}
+
 type mockLifecycleManager struct {
 	err               *params.Error
 	life              func([]names.Tag) ([]params.LifeResult, error)
@@ -547,6 +641,9 @@ type dummyProvider struct {
 	filesystemSourceFunc         func(*storage.Config) (storage.FilesystemSource, error)
 	createVolumesFunc            func([]storage.VolumeParams) ([]storage.CreateVolumesResult, error)
 	createFilesystemsFunc        func([]storage.FilesystemParams) ([]storage.CreateFilesystemsResult, error)
+	createVolumeSnapshotsFunc    func([]storage.VolumeSnapshotParams) ([]storage.CreateSnapshotsResult, error)
+	deleteVolumeSnapshotsFunc    func([]string) ([]error, error)
+	resizeVolumesFunc            func([]storage.VolumeResizeParams) ([]storage.ResizeResult, error)
 	attachVolumesFunc            func([]storage.VolumeAttachmentParams) ([]storage.AttachVolumesResult, error)
 	attachFilesystemsFunc        func([]storage.FilesystemAttachmentParams) ([]storage.AttachFilesystemsResult, error)
 	detachVolumesFunc            func([]storage.VolumeAttachmentParams) ([]error, error)
@@ -622,6 +719,30 @@ func (s *dummyVolumeSource) CreateVolumes(ctx context.ProviderCallContext, param
 	return results, nil
 }
 
+// CreateVolumeSnapshots snapshots volumes, if the test provides createVolumeSnapshotsFunc.
+func (s *dummyVolumeSource) CreateVolumeSnapshots(ctx context.ProviderCallContext, params []storage.VolumeSnapshotParams) ([]storage.CreateSnapshotsResult, error) {
+	if s.provider != nil && s.provider.createVolumeSnapshotsFunc != nil {
+		return s.provider.createVolumeSnapshotsFunc(params)
+	}
+	return nil, errors.NotImplementedf("CreateVolumeSnapshots")
+}
+
+// DeleteVolumeSnapshots deletes volume snapshots, if the test provides deleteVolumeSnapshotsFunc.
+func (s *dummyVolumeSource) DeleteVolumeSnapshots(ctx context.ProviderCallContext, snapshotIds []string) ([]error, error) {
+	if s.provider != nil && s.provider.deleteVolumeSnapshotsFunc != nil {
+		return s.provider.deleteVolumeSnapshotsFunc(snapshotIds)
+	}
+	return nil, errors.NotImplementedf("DeleteVolumeSnapshots")
+}
+
+// ResizeVolumes grows volumes, if the test provides resizeVolumesFunc.
+func (s *dummyVolumeSource) ResizeVolumes(ctx context.ProviderCallContext, params []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+	if s.provider != nil && s.provider.resizeVolumesFunc != nil {
+		return s.provider.resizeVolumesFunc(params)
+	}
+	return nil, errors.NotImplementedf("ResizeVolumes")
+}
+
 // DestroyVolumes destroys volumes.
 func (s *dummyVolumeSource) DestroyVolumes(ctx context.ProviderCallContext, volumeIds []string) ([]error, error) {
 	if s.provider.destroyVolumesFunc != nil {
@@ -752,8 +873,11 @@ func (s *dummyFilesystemSource) DetachFilesystems(ctx context.ProviderCallContex
 }
 
 type mockManagedFilesystemSource struct {
-	blockDevices map[names.VolumeTag]storage.BlockDevice
-	filesystems  map[names.FilesystemTag]storage.Filesystem
+	blockDevices      map[names.VolumeTag]storage.BlockDevice
+	filesystems       map[names.FilesystemTag]storage.Filesystem
+	keys              map[names.VolumeTag]string
+	compositions      map[names.FilesystemTag]storage.FilesystemComposition
+	detachFilesystems func([]storage.FilesystemAttachmentParams) ([]error, error)
 }
 
 func (s *mockManagedFilesystemSource) ValidateFilesystemParams(params storage.FilesystemParams) error {
@@ -819,6 +943,9 @@ func (s *mockManagedFilesystemSource) AttachFilesystems(ctx context.ProviderCall
 }
 
 func (s *mockManagedFilesystemSource) DetachFilesystems(ctx context.ProviderCallContext, params []storage.FilesystemAttachmentParams) ([]error, error) {
+	if s.detachFilesystems != nil {
+		return s.detachFilesystems(params)
+	}
 	return nil, errors.NotImplementedf("DetachFilesystems")
 }
 
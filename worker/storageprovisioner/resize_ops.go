@@ -0,0 +1,243 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/storage"
+)
+
+// volumeResizesChanged is called when requests to resize the volumes
+// with the specified IDs have been seen to have changed.
+func volumeResizesChanged(ctx *context, ids []string) error {
+	tags := make([]names.Tag, len(ids))
+	for i, id := range ids {
+		tags[i] = names.NewVolumeTag(id)
+	}
+	return storageResizesChanged(ctx, tags)
+}
+
+// filesystemResizesChanged is called when requests to resize the
+// filesystems with the specified IDs have been seen to have changed.
+func filesystemResizesChanged(ctx *context, ids []string) error {
+	tags := make([]names.Tag, len(ids))
+	for i, id := range ids {
+		tags[i] = names.NewFilesystemTag(id)
+	}
+	return storageResizesChanged(ctx, tags)
+}
+
+// storageResizesChanged resizes the volumes and filesystems with the
+// specified tags, if they are still pending a resize, and records the
+// outcome in state.
+func storageResizesChanged(ctx *context, tags []names.Tag) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	paramsResults, err := ctx.config.Resizes.StorageResizeParams(tags)
+	if err != nil {
+		return errors.Annotate(err, "getting storage resize params")
+	}
+	volumeArgs := make(map[storage.ProviderType][]storage.VolumeResizeParams)
+	filesystemArgs := make(map[storage.ProviderType][]storage.FilesystemResizeParams)
+	var managedFilesystemArgs []storage.FilesystemResizeParams
+	for i, result := range paramsResults {
+		if result.Error != nil {
+			// The resize has completed, or the storage has
+			// been removed, since the change was observed.
+			ctx.config.Logger.Debugf("ignoring resize of %s: %v", names.ReadableString(tags[i]), result.Error)
+			continue
+		}
+		providerType := storage.ProviderType(result.Result.Provider)
+		switch tag := tags[i].(type) {
+		case names.VolumeTag:
+			volumeArgs[providerType] = append(volumeArgs[providerType], storage.VolumeResizeParams{
+				Volume:   tag,
+				VolumeId: result.Result.ProviderId,
+				Size:     result.Result.Size,
+			})
+		case names.FilesystemTag:
+			args, err := filesystemResizeParamsFromParams(ctx, tag, *result.Result)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if args.Volume != (names.VolumeTag{}) {
+				managedFilesystemArgs = append(managedFilesystemArgs, args)
+				continue
+			}
+			filesystemArgs[providerType] = append(filesystemArgs[providerType], args)
+		}
+	}
+
+	var results []params.StorageResizeResult
+	for providerType, args := range volumeArgs {
+		results = append(results, resizeVolumes(ctx, providerType, args)...)
+	}
+	for providerType, args := range filesystemArgs {
+		source, err := filesystemSource(ctx.config.StorageDir, string(providerType), providerType, ctx.config.Registry)
+		if err != nil {
+			err = errors.Annotate(err, "getting filesystem source")
+		}
+		results = append(results, resizeFilesystems(ctx, providerType, source, err, args)...)
+	}
+	if len(managedFilesystemArgs) > 0 {
+		results = append(results, resizeFilesystems(
+			ctx, "managed", ctx.managedFilesystemSource, nil, managedFilesystemArgs,
+		)...)
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Resizes.SetStorageResizeResults(results)
+	if err != nil {
+		return errors.Annotate(err, "publishing storage resizes to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"publishing resize of %s to state: %v",
+				results[i].Tag, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// resizeVolumes grows volumes from the specified storage provider,
+// returning the outcome of each.
+func resizeVolumes(
+	ctx *context,
+	providerType storage.ProviderType,
+	args []storage.VolumeResizeParams,
+) []params.StorageResizeResult {
+	results := make([]params.StorageResizeResult, len(args))
+	for i, arg := range args {
+		results[i].Tag = arg.Volume.String()
+	}
+	fail := func(err error) []params.StorageResizeResult {
+		ctx.config.Logger.Debugf("failed to resize volumes: %v", err)
+		for i := range results {
+			results[i].Message = err.Error()
+		}
+		return results
+	}
+
+	source, err := volumeSource(ctx.config.StorageDir, string(providerType), providerType, ctx.config.Registry)
+	if err != nil {
+		return fail(errors.Annotate(err, "getting volume source"))
+	}
+	resizer, ok := source.(storage.VolumeResizer)
+	if !ok {
+		return fail(errors.NotSupportedf("resizing %q volumes", providerType))
+	}
+	ctx.config.Logger.Debugf("resizing volumes: %v", args)
+	resizeResults, err := resizer.ResizeVolumes(ctx.config.CloudCallContext, args)
+	if err != nil {
+		return fail(errors.Annotatef(err, "resizing volumes from source %q", providerType))
+	}
+	for i, result := range resizeResults {
+		if result.Error != nil {
+			results[i].Message = result.Error.Error()
+			ctx.config.Logger.Debugf(
+				"failed to resize %s: %v",
+				names.ReadableString(args[i].Volume), result.Error,
+			)
+			continue
+		}
+		results[i].Size = resizedSize(result, args[i].Size)
+	}
+	return results
+}
+
+// resizeFilesystems grows filesystems from the specified filesystem
+// source, returning the outcome of each. If sourceErr is non-nil, the
+// source could not be obtained, and all resizes fail.
+func resizeFilesystems(
+	ctx *context,
+	sourceName storage.ProviderType,
+	source storage.FilesystemSource,
+	sourceErr error,
+	args []storage.FilesystemResizeParams,
+) []params.StorageResizeResult {
+	results := make([]params.StorageResizeResult, len(args))
+	for i, arg := range args {
+		results[i].Tag = arg.Filesystem.String()
+	}
+	fail := func(err error) []params.StorageResizeResult {
+		ctx.config.Logger.Debugf("failed to resize filesystems: %v", err)
+		for i := range results {
+			results[i].Message = err.Error()
+		}
+		return results
+	}
+
+	if sourceErr != nil {
+		return fail(sourceErr)
+	}
+	resizer, ok := source.(storage.FilesystemResizer)
+	if !ok {
+		return fail(errors.NotSupportedf("resizing %q filesystems", sourceName))
+	}
+	ctx.config.Logger.Debugf("resizing filesystems: %v", args)
+	resizeResults, err := resizer.ResizeFilesystems(ctx.config.CloudCallContext, args)
+	if err != nil {
+		return fail(errors.Annotatef(err, "resizing filesystems from source %q", sourceName))
+	}
+	for i, result := range resizeResults {
+		if result.Error != nil {
+			results[i].Message = result.Error.Error()
+			ctx.config.Logger.Debugf(
+				"failed to resize %s: %v",
+				names.ReadableString(args[i].Filesystem), result.Error,
+			)
+			continue
+		}
+		results[i].Size = resizedSize(result, args[i].Size)
+	}
+	return results
+}
+
+// resizedSize returns the size reported by the storage provider for a
+// successful resize, falling back to the requested size if the provider
+// did not report one.
+func resizedSize(result storage.ResizeResult, requested uint64) uint64 {
+	if result.Size == 0 {
+		return requested
+	}
+	return result.Size
+}
+
+func filesystemResizeParamsFromParams(
+	ctx *context,
+	tag names.FilesystemTag,
+	in params.StorageResizeParams,
+) (storage.FilesystemResizeParams, error) {
+	var volumeTag names.VolumeTag
+	if in.VolumeTag != "" {
+		var err error
+		volumeTag, err = names.ParseVolumeTag(in.VolumeTag)
+		if err != nil {
+			return storage.FilesystemResizeParams{}, errors.Trace(err)
+		}
+	}
+	// If the filesystem is attached to the machine this
+	// provisioner is running on, grow it where it is mounted.
+	var path string
+	if attachment, ok := ctx.filesystemAttachments[params.MachineStorageId{
+		MachineTag:    ctx.config.Scope.String(),
+		AttachmentTag: tag.String(),
+	}]; ok {
+		path = attachment.Path
+	}
+	return storage.FilesystemResizeParams{
+		Filesystem:   tag,
+		Volume:       volumeTag,
+		FilesystemId: in.ProviderId,
+		Size:         in.Size,
+		Path:         path,
+	}, nil
+}
@@ -0,0 +1,294 @@
+// Copyright 2020 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package storageprovisioner
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v3"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/core/life"
+	"github.com/juju/juju/storage"
+)
+
+// storageSnapshotsChanged is called when the storage snapshots with the
+// specified IDs have been seen to have changed. Snapshots that are still
+// pending are taken immediately, and the outcome recorded in state.
+// Dying snapshots are deleted from the storage provider, and then
+// removed from state.
+func storageSnapshotsChanged(ctx *context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	paramsResults, err := ctx.config.Snapshots.StorageSnapshotParams(ids)
+	if err != nil {
+		return errors.Annotate(err, "getting storage snapshot params")
+	}
+	volumeArgs := make(map[storage.ProviderType][]storage.VolumeSnapshotParams)
+	filesystemArgs := make(map[storage.ProviderType][]storage.FilesystemSnapshotParams)
+	var dying []params.StorageSnapshotParams
+	for i, result := range paramsResults {
+		if result.Error != nil {
+			// The snapshot has been taken or removed
+			// since the change was observed.
+			ctx.config.Logger.Debugf("ignoring storage snapshot %q: %v", ids[i], result.Error)
+			continue
+		}
+		if result.Result.Life == life.Dying {
+			dying = append(dying, *result.Result)
+			continue
+		}
+		providerType := storage.ProviderType(result.Result.Provider)
+		if result.Result.VolumeTag != "" {
+			args, err := volumeSnapshotParamsFromParams(*result.Result)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			volumeArgs[providerType] = append(volumeArgs[providerType], args)
+		} else {
+			args, err := filesystemSnapshotParamsFromParams(*result.Result)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			filesystemArgs[providerType] = append(filesystemArgs[providerType], args)
+		}
+	}
+
+	if err := removeStorageSnapshots(ctx, dying); err != nil {
+		return errors.Trace(err)
+	}
+
+	var results []params.StorageSnapshotResult
+	for providerType, args := range volumeArgs {
+		results = append(results, createVolumeSnapshots(ctx, providerType, args)...)
+	}
+	for providerType, args := range filesystemArgs {
+		results = append(results, createFilesystemSnapshots(ctx, providerType, args)...)
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Snapshots.SetStorageSnapshotResults(results)
+	if err != nil {
+		return errors.Annotate(err, "publishing storage snapshots to state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"publishing storage snapshot %q to state: %v",
+				results[i].Id, result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// createVolumeSnapshots takes snapshots of volumes from the specified
+// storage provider, returning the outcome of each.
+func createVolumeSnapshots(
+	ctx *context,
+	providerType storage.ProviderType,
+	args []storage.VolumeSnapshotParams,
+) []params.StorageSnapshotResult {
+	results := make([]params.StorageSnapshotResult, len(args))
+	for i, arg := range args {
+		results[i].Id = arg.Id
+	}
+	fail := func(err error) []params.StorageSnapshotResult {
+		ctx.config.Logger.Debugf("failed to create volume snapshots: %v", err)
+		for i := range results {
+			results[i].Message = err.Error()
+		}
+		return results
+	}
+
+	source, err := volumeSource(ctx.config.StorageDir, string(providerType), providerType, ctx.config.Registry)
+	if err != nil {
+		return fail(errors.Annotate(err, "getting volume source"))
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return fail(errors.NotSupportedf("snapshots of %q volumes", providerType))
+	}
+	ctx.config.Logger.Debugf("creating volume snapshots: %v", args)
+	snapshotResults, err := snapshotter.CreateVolumeSnapshots(ctx.config.CloudCallContext, args)
+	if err != nil {
+		return fail(errors.Annotatef(err, "creating volume snapshots from source %q", providerType))
+	}
+	for i, result := range snapshotResults {
+		if result.Error != nil {
+			results[i].Message = result.Error.Error()
+			ctx.config.Logger.Debugf(
+				"failed to snapshot %s: %v",
+				names.ReadableString(args[i].Volume), result.Error,
+			)
+			continue
+		}
+		results[i].SnapshotId = result.SnapshotId
+	}
+	return results
+}
+
+// createFilesystemSnapshots takes snapshots of filesystems from the
+// specified storage provider, returning the outcome of each.
+func createFilesystemSnapshots(
+	ctx *context,
+	providerType storage.ProviderType,
+	args []storage.FilesystemSnapshotParams,
+) []params.StorageSnapshotResult {
+	results := make([]params.StorageSnapshotResult, len(args))
+	for i, arg := range args {
+		results[i].Id = arg.Id
+	}
+	fail := func(err error) []params.StorageSnapshotResult {
+		ctx.config.Logger.Debugf("failed to create filesystem snapshots: %v", err)
+		for i := range results {
+			results[i].Message = err.Error()
+		}
+		return results
+	}
+
+	source, err := filesystemSource(ctx.config.StorageDir, string(providerType), providerType, ctx.config.Registry)
+	if err != nil {
+		return fail(errors.Annotate(err, "getting filesystem source"))
+	}
+	snapshotter, ok := source.(storage.FilesystemSnapshotter)
+	if !ok {
+		return fail(errors.NotSupportedf("snapshots of %q filesystems", providerType))
+	}
+	ctx.config.Logger.Debugf("creating filesystem snapshots: %v", args)
+	snapshotResults, err := snapshotter.CreateFilesystemSnapshots(ctx.config.CloudCallContext, args)
+	if err != nil {
+		return fail(errors.Annotatef(err, "creating filesystem snapshots from source %q", providerType))
+	}
+	for i, result := range snapshotResults {
+		if result.Error != nil {
+			results[i].Message = result.Error.Error()
+			ctx.config.Logger.Debugf(
+				"failed to snapshot %s: %v",
+				names.ReadableString(args[i].Filesystem), result.Error,
+			)
+			continue
+		}
+		results[i].SnapshotId = result.SnapshotId
+	}
+	return results
+}
+
+// removeStorageSnapshots deletes dying snapshots from the storage
+// provider, and removes from state those that were deleted. Snapshots
+// that could not be deleted remain dying, and are retried when the
+// worker is restarted.
+func removeStorageSnapshots(ctx *context, snapshots []params.StorageSnapshotParams) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	type sourceKey struct {
+		providerType storage.ProviderType
+		volume       bool
+	}
+	bySource := make(map[sourceKey][]params.StorageSnapshotParams)
+	for _, snapshot := range snapshots {
+		key := sourceKey{storage.ProviderType(snapshot.Provider), snapshot.VolumeTag != ""}
+		bySource[key] = append(bySource[key], snapshot)
+	}
+
+	var remove []string
+	for key, snapshots := range bySource {
+		snapshotIds := make([]string, len(snapshots))
+		for i, snapshot := range snapshots {
+			snapshotIds[i] = snapshot.SnapshotId
+		}
+		var errs []error
+		var err error
+		if key.volume {
+			errs, err = deleteVolumeSnapshots(ctx, key.providerType, snapshotIds)
+		} else {
+			errs, err = deleteFilesystemSnapshots(ctx, key.providerType, snapshotIds)
+		}
+		if err != nil {
+			ctx.config.Logger.Errorf("deleting storage snapshots: %v", err)
+			continue
+		}
+		for i, err := range errs {
+			if err != nil {
+				ctx.config.Logger.Errorf("deleting storage snapshot %q: %v", snapshots[i].Id, err)
+				continue
+			}
+			remove = append(remove, snapshots[i].Id)
+		}
+	}
+	if len(remove) == 0 {
+		return nil
+	}
+	errorResults, err := ctx.config.Snapshots.RemoveStorageSnapshots(remove)
+	if err != nil {
+		return errors.Annotate(err, "removing storage snapshots from state")
+	}
+	for i, result := range errorResults {
+		if result.Error != nil {
+			ctx.config.Logger.Errorf(
+				"removing storage snapshot %q from state: %v",
+				remove[i], result.Error,
+			)
+		}
+	}
+	return nil
+}
+
+// deleteVolumeSnapshots deletes volume snapshots from the specified
+// storage provider.
+func deleteVolumeSnapshots(ctx *context, providerType storage.ProviderType, snapshotIds []string) ([]error, error) {
+	source, err := volumeSource(ctx.config.StorageDir, string(providerType), providerType, ctx.config.Registry)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting volume source")
+	}
+	snapshotter, ok := source.(storage.VolumeSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshots of %q volumes", providerType)
+	}
+	ctx.config.Logger.Debugf("deleting volume snapshots: %v", snapshotIds)
+	return snapshotter.DeleteVolumeSnapshots(ctx.config.CloudCallContext, snapshotIds)
+}
+
+// deleteFilesystemSnapshots deletes filesystem snapshots from the
+// specified storage provider.
+func deleteFilesystemSnapshots(ctx *context, providerType storage.ProviderType, snapshotIds []string) ([]error, error) {
+	source, err := filesystemSource(ctx.config.StorageDir, string(providerType), providerType, ctx.config.Registry)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting filesystem source")
+	}
+	snapshotter, ok := source.(storage.FilesystemSnapshotter)
+	if !ok {
+		return nil, errors.NotSupportedf("snapshots of %q filesystems", providerType)
+	}
+	ctx.config.Logger.Debugf("deleting filesystem snapshots: %v", snapshotIds)
+	return snapshotter.DeleteFilesystemSnapshots(ctx.config.CloudCallContext, snapshotIds)
+}
+
+func volumeSnapshotParamsFromParams(in params.StorageSnapshotParams) (storage.VolumeSnapshotParams, error) {
+	volumeTag, err := names.ParseVolumeTag(in.VolumeTag)
+	if err != nil {
+		return storage.VolumeSnapshotParams{}, errors.Trace(err)
+	}
+	return storage.VolumeSnapshotParams{
+		Id:           in.Id,
+		Volume:       volumeTag,
+		VolumeId:     in.VolumeId,
+		ResourceTags: in.Tags,
+	}, nil
+}
+
+func filesystemSnapshotParamsFromParams(in params.StorageSnapshotParams) (storage.FilesystemSnapshotParams, error) {
+	filesystemTag, err := names.ParseFilesystemTag(in.FilesystemTag)
+	if err != nil {
+		return storage.FilesystemSnapshotParams{}, errors.Trace(err)
+	}
+	return storage.FilesystemSnapshotParams{
+		Id:           in.Id,
+		Filesystem:   filesystemTag,
+		FilesystemId: in.FilesystemId,
+		ResourceTags: in.Tags,
+	}, nil
+}
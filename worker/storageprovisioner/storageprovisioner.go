@@ -140,6 +140,74 @@ type FilesystemAccessor interface {
 	SetFilesystemAttachmentInfo([]params.FilesystemAttachment) ([]params.ErrorResult, error)
 }
 
+// SnapshotAccessor defines an interface used to allow a storage
+// provisioner worker to take and delete snapshots of volumes and
+// filesystems.
+type SnapshotAccessor interface {
+	// WatchStorageSnapshots watches for changes to storage snapshots
+	// that this storage provisioner is responsible for.
+	WatchStorageSnapshots(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// StorageSnapshotParams returns the parameters for creating or
+	// deleting the storage snapshots with the specified IDs.
+	StorageSnapshotParams([]string) ([]params.StorageSnapshotParamsResult, error)
+
+	// SetStorageSnapshotResults records the outcomes of creating
+	// storage snapshots.
+	SetStorageSnapshotResults([]params.StorageSnapshotResult) ([]params.ErrorResult, error)
+
+	// RemoveStorageSnapshots removes the dying storage snapshots
+	// with the specified IDs, once they have been deleted from the
+	// storage provider.
+	RemoveStorageSnapshots([]string) ([]params.ErrorResult, error)
+}
+
+// ResizeAccessor defines an interface used to allow a storage
+// provisioner worker to grow volumes and filesystems.
+type ResizeAccessor interface {
+	// WatchVolumeResizes watches for requests to resize volumes
+	// that this storage provisioner is responsible for.
+	WatchVolumeResizes(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// WatchFilesystemResizes watches for requests to resize
+	// filesystems that this storage provisioner is responsible for.
+	WatchFilesystemResizes(scope names.Tag) (watcher.StringsWatcher, error)
+
+	// StorageResizeParams returns the parameters for resizing the
+	// volumes and filesystems with the specified tags.
+	StorageResizeParams([]names.Tag) ([]params.StorageResizeParamsResult, error)
+
+	// SetStorageResizeResults records the outcomes of resizing
+	// volumes and filesystems.
+	SetStorageResizeResults([]params.StorageResizeResult) ([]params.ErrorResult, error)
+}
+
+// VolumeCopyAccessor defines an interface used to allow a machine-scoped
+// storage provisioner worker to copy data between volumes attached to the
+// machine, when storage is moved between pools.
+type VolumeCopyAccessor interface {
+	// WatchVolumeCopies watches for requests to copy data to volumes
+	// attached to the specified machine.
+	WatchVolumeCopies(names.MachineTag) (watcher.StringsWatcher, error)
+
+	// VolumeCopyParams returns the parameters for copying data to
+	// the volumes with the specified attachment IDs.
+	VolumeCopyParams([]params.MachineStorageId) ([]params.VolumeCopyParamsResult, error)
+
+	// SetVolumeCopyResults records the outcomes of copying data to
+	// volumes.
+	SetVolumeCopyResults([]params.VolumeCopyResult) ([]params.ErrorResult, error)
+}
+
+// EncryptionKeyAccessor defines an interface used to allow a
+// machine-scoped storage provisioner worker to obtain the keys with
+// which to encrypt volumes attached to the machine.
+type EncryptionKeyAccessor interface {
+	// VolumeEncryptionKeys returns the encryption keys for the volumes
+	// with the specified attachment IDs.
+	VolumeEncryptionKeys([]params.MachineStorageId) ([]params.StringResult, error)
+}
+
 // MachineAccessor defines an interface used to allow a storage provisioner
 // worker to perform machine related operations.
 type MachineAccessor interface {
@@ -222,9 +290,14 @@ func (w *storageProvisioner) loop() error {
 		volumeAttachmentsChanges     watcher.MachineStorageIdsChannel
 		volumeAttachmentPlansChanges watcher.MachineStorageIdsChannel
 		filesystemAttachmentsChanges watcher.MachineStorageIdsChannel
+		storageSnapshotsChanges      watcher.StringsChannel
+		volumeResizesChanges         watcher.StringsChannel
+		filesystemResizesChanges     watcher.StringsChannel
+		volumeCopiesChanges          watcher.StringsChannel
 		machineBlockDevicesChanges   <-chan struct{}
 	)
 	machineChanges := make(chan names.MachineTag)
+	volumeCopyResults := make(chan params.VolumeCopyResult)
 
 	// Machine-scoped provisioners need to watch block devices, to create
 	// volume-backed filesystems.
@@ -247,6 +320,21 @@ func (w *storageProvisioner) loop() error {
 		}
 
 		volumeAttachmentPlansChanges = volumeAttachmentPlansWatcher.Changes()
+
+		if w.config.Copies != nil {
+			volumeCopiesWatcher, err := w.config.Copies.WatchVolumeCopies(machineTag)
+			if errors.IsNotImplemented(err) {
+				// The controller does not support moving storage.
+				w.config.Logger.Debugf("not watching volume copies: %v", err)
+			} else if err != nil {
+				return errors.Annotate(err, "watching volume copies")
+			} else {
+				if err := w.catacomb.Add(volumeCopiesWatcher); err != nil {
+					return errors.Trace(err)
+				}
+				volumeCopiesChanges = volumeCopiesWatcher.Changes()
+			}
+		}
 	}
 
 	ctx := context{
@@ -256,7 +344,9 @@ func (w *storageProvisioner) loop() error {
 		volumes:                              make(map[names.VolumeTag]storage.Volume),
 		volumeAttachments:                    make(map[params.MachineStorageId]storage.VolumeAttachment),
 		volumeBlockDevices:                   make(map[names.VolumeTag]storage.BlockDevice),
+		volumeEncryptionKeys:                 make(map[names.VolumeTag]string),
 		filesystems:                          make(map[names.FilesystemTag]storage.Filesystem),
+		filesystemCompositions:               make(map[names.FilesystemTag]storage.FilesystemComposition),
 		filesystemAttachments:                make(map[params.MachineStorageId]storage.FilesystemAttachment),
 		machines:                             make(map[names.MachineTag]*machineWatcher),
 		machineChanges:                       machineChanges,
@@ -266,9 +356,13 @@ func (w *storageProvisioner) loop() error {
 		incompleteFilesystemParams:           make(map[names.FilesystemTag]storage.FilesystemParams),
 		incompleteFilesystemAttachmentParams: make(map[params.MachineStorageId]storage.FilesystemAttachmentParams),
 		pendingVolumeBlockDevices:            names.NewSet(),
+		pendingVolumeCopies:                  make(map[names.VolumeTag]params.VolumeCopyParams),
+		copyingVolumes:                       make(map[names.VolumeTag]volumeCopy),
+		volumeCopyResults:                    volumeCopyResults,
 	}
 	ctx.managedFilesystemSource = newManagedFilesystemSource(
 		ctx.volumeBlockDevices, ctx.filesystems,
+		ctx.volumeEncryptionKeys, ctx.filesystemCompositions,
 	)
 	// Units don't use managed volume backed filesystems.
 	if ctx.isApplicationKind() {
@@ -315,6 +409,47 @@ func (w *storageProvisioner) loop() error {
 	}
 	filesystemAttachmentsChanges = filesystemAttachmentsWatcher.Changes()
 
+	// Storage snapshots are not supported for CAAS models.
+	if w.config.Snapshots != nil && !ctx.isApplicationKind() {
+		storageSnapshotsWatcher, err := w.config.Snapshots.WatchStorageSnapshots(w.config.Scope)
+		if errors.IsNotImplemented(err) {
+			// The controller does not support storage snapshots.
+			w.config.Logger.Debugf("not watching storage snapshots: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching storage snapshots")
+		} else {
+			if err := w.catacomb.Add(storageSnapshotsWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			storageSnapshotsChanges = storageSnapshotsWatcher.Changes()
+		}
+	}
+
+	// Resizing storage is not supported for CAAS models.
+	if w.config.Resizes != nil && !ctx.isApplicationKind() {
+		volumeResizesWatcher, err := w.config.Resizes.WatchVolumeResizes(w.config.Scope)
+		if errors.IsNotImplemented(err) {
+			// The controller does not support resizing storage.
+			w.config.Logger.Debugf("not watching storage resizes: %v", err)
+		} else if err != nil {
+			return errors.Annotate(err, "watching volume resizes")
+		} else {
+			if err := w.catacomb.Add(volumeResizesWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			volumeResizesChanges = volumeResizesWatcher.Changes()
+
+			filesystemResizesWatcher, err := w.config.Resizes.WatchFilesystemResizes(w.config.Scope)
+			if err != nil {
+				return errors.Annotate(err, "watching filesystem resizes")
+			}
+			if err := w.catacomb.Add(filesystemResizesWatcher); err != nil {
+				return errors.Trace(err)
+			}
+			filesystemResizesChanges = filesystemResizesWatcher.Changes()
+		}
+	}
+
 	for {
 
 		// Check if block devices need to be refreshed.
@@ -360,6 +495,34 @@ func (w *storageProvisioner) loop() error {
 			if err := filesystemAttachmentsChanged(&ctx, changes); err != nil {
 				return errors.Trace(err)
 			}
+		case changes, ok := <-storageSnapshotsChanges:
+			if !ok {
+				return errors.New("storage snapshots watcher closed")
+			}
+			if err := storageSnapshotsChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-volumeResizesChanges:
+			if !ok {
+				return errors.New("volume resizes watcher closed")
+			}
+			if err := volumeResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-filesystemResizesChanges:
+			if !ok {
+				return errors.New("filesystem resizes watcher closed")
+			}
+			if err := filesystemResizesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
+		case changes, ok := <-volumeCopiesChanges:
+			if !ok {
+				return errors.New("volume copies watcher closed")
+			}
+			if err := volumeCopiesChanged(&ctx, changes); err != nil {
+				return errors.Trace(err)
+			}
 		case _, ok := <-machineBlockDevicesChanges:
 			if !ok {
 				return errors.New("machine block devices watcher closed")
@@ -367,6 +530,13 @@ func (w *storageProvisioner) loop() error {
 			if err := machineBlockDevicesChanged(&ctx); err != nil {
 				return errors.Trace(err)
 			}
+			if err := processPendingVolumeCopies(&ctx); err != nil {
+				return errors.Trace(err)
+			}
+		case result := <-volumeCopyResults:
+			if err := volumeCopied(&ctx, result); err != nil {
+				return errors.Trace(err)
+			}
 		case machineTag := <-machineChanges:
 			if err := refreshMachine(&ctx, machineTag); err != nil {
 				return errors.Trace(err)
@@ -376,6 +546,11 @@ func (w *storageProvisioner) loop() error {
 			if err := processSchedule(&ctx); err != nil {
 				return errors.Trace(err)
 			}
+			// Copies of filesystem data wait for the
+			// filesystem to be mounted.
+			if err := processPendingVolumeCopies(&ctx); err != nil {
+				return errors.Trace(err)
+			}
 		}
 	}
 }
@@ -472,9 +647,19 @@ type context struct {
 	// is only used by the machine-scoped storage provisioner.
 	volumeBlockDevices map[names.VolumeTag]storage.BlockDevice
 
+	// volumeEncryptionKeys contains the encryption keys for encrypted
+	// volumes attached to the scope-machine. This is only used by the
+	// machine-scoped storage provisioner.
+	volumeEncryptionKeys map[names.VolumeTag]string
+
 	// filesystems contains information about provisioned filesystems.
 	filesystems map[names.FilesystemTag]storage.Filesystem
 
+	// filesystemCompositions contains information about how the
+	// volumes backing filesystems are composed, for filesystems
+	// backed by more than one volume.
+	filesystemCompositions map[names.FilesystemTag]storage.FilesystemComposition
+
 	// filesystemAttachments contains information about attached filesystems.
 	filesystemAttachments map[params.MachineStorageId]storage.FilesystemAttachment
 
@@ -527,6 +712,21 @@ type context struct {
 	// block devices we wish to enquire.
 	pendingVolumeBlockDevices names.Set
 
+	// pendingVolumeCopies contains the parameters of requested copies
+	// of data to volumes attached to the scope-machine, keyed by the
+	// target volume, whose block devices are not yet known. This is
+	// only used by the machine-scoped storage provisioner.
+	pendingVolumeCopies map[names.VolumeTag]params.VolumeCopyParams
+
+	// copyingVolumes contains the details of copies of data to volumes
+	// attached to the scope-machine that are in progress, keyed by the
+	// target volume.
+	copyingVolumes map[names.VolumeTag]volumeCopy
+
+	// volumeCopyResults is a channel that volume copiers will send to
+	// once they have finished copying data.
+	volumeCopyResults chan<- params.VolumeCopyResult
+
 	// managedFilesystemSource is a storage.FilesystemSource that
 	// manages filesystems backed by volumes attached to the host
 	// machine.
@@ -49,10 +49,14 @@ func (s *storageProvisionerSuite) SetUpTest(c *gc.C) {
 		func(
 			blockDevices map[names.VolumeTag]storage.BlockDevice,
 			filesystems map[names.FilesystemTag]storage.Filesystem,
+			keys map[names.VolumeTag]string,
+			compositions map[names.FilesystemTag]storage.FilesystemComposition,
 		) storage.FilesystemSource {
 			s.managedFilesystemSource = &mockManagedFilesystemSource{
 				blockDevices: blockDevices,
 				filesystems:  filesystems,
+				keys:         keys,
+				compositions: compositions,
 			}
 			return s.managedFilesystemSource
 		},
@@ -1069,6 +1073,96 @@ func (s *storageProvisionerSuite) TestCreateVolumeBackedFilesystem(c *gc.C) {
 	}})
 }
 
+func (s *storageProvisionerSuite) TestCreateEncryptedVolumeBackedFilesystem(c *gc.C) {
+	filesystemInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemInfo = func(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+		filesystemInfoSet <- filesystems
+		return nil, nil
+	}
+	encryptionKeys := &mockEncryptionKeyAccessor{
+		volumeEncryptionKeys: func(ids []params.MachineStorageId) ([]params.StringResult, error) {
+			c.Assert(ids, jc.DeepEquals, []params.MachineStorageId{{
+				MachineTag: "machine-0", AttachmentTag: "volume-0-0",
+			}})
+			return []params.StringResult{{Result: "sekrit"}}, nil
+		},
+	}
+
+	args := &workerArgs{
+		scope:          names.NewMachineTag("0"),
+		filesystems:    filesystemAccessor,
+		encryptionKeys: encryptionKeys,
+		registry:       s.registry,
+	}
+	args.volumes = newMockVolumeAccessor()
+	args.volumes.blockDevices[params.MachineStorageId{
+		MachineTag:    "machine-0",
+		AttachmentTag: "volume-0-0",
+	}] = storage.BlockDevice{
+		DeviceName: "xvdf1",
+		Size:       123,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.filesystemsWatcher.changes <- []string{"0/0"}
+	waitChannel(c, filesystemInfoSet, "waiting for filesystem info to be set")
+
+	// The key for the encrypted volume must be made available to
+	// the managed filesystem source before the filesystem is created.
+	c.Assert(s.managedFilesystemSource.keys, jc.DeepEquals, map[names.VolumeTag]string{
+		names.NewVolumeTag("0/0"): "sekrit",
+	})
+}
+
+func (s *storageProvisionerSuite) TestCreateComposedVolumeBackedFilesystem(c *gc.C) {
+	filesystemInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemInfo = func(filesystems []params.Filesystem) ([]params.ErrorResult, error) {
+		filesystemInfoSet <- filesystems
+		return nil, nil
+	}
+	filesystemAccessor.compositions["filesystem-0-0"] = &params.FilesystemComposition{
+		Type:       "raid0",
+		VolumeTags: []string{"volume-0-0", "volume-0-1"},
+	}
+
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+		registry:    s.registry,
+	}
+	args.volumes = newMockVolumeAccessor()
+	for _, volumeTag := range []string{"volume-0-0", "volume-0-1"} {
+		args.volumes.blockDevices[params.MachineStorageId{
+			MachineTag:    "machine-0",
+			AttachmentTag: volumeTag,
+		}] = storage.BlockDevice{
+			DeviceName: "xvdf-" + volumeTag,
+			Size:       123,
+		}
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	filesystemAccessor.filesystemsWatcher.changes <- []string{"0/0"}
+	waitChannel(c, filesystemInfoSet, "waiting for filesystem info to be set")
+
+	// The composition must be made available to the managed
+	// filesystem source before the filesystem is created, along
+	// with the block devices of all of the composed volumes.
+	c.Assert(s.managedFilesystemSource.compositions, jc.DeepEquals, map[names.FilesystemTag]storage.FilesystemComposition{
+		names.NewFilesystemTag("0/0"): {
+			Type:    storage.CompositionRAID0,
+			Volumes: []names.VolumeTag{names.NewVolumeTag("0/0"), names.NewVolumeTag("0/1")},
+		},
+	})
+	c.Assert(s.managedFilesystemSource.blockDevices, gc.HasLen, 2)
+}
+
 func (s *storageProvisionerSuite) TestAttachVolumeBackedFilesystem(c *gc.C) {
 	infoSet := make(chan interface{})
 	filesystemAccessor := newMockFilesystemAccessor()
@@ -2032,6 +2126,410 @@ func (s *caasStorageProvisionerSuite) TestRemoveFilesystems(c *gc.C) {
 	waitChannel(c, removed, "waiting for filesystem to be removed")
 }
 
+func (s *storageProvisionerSuite) TestStorageSnapshotAdded(c *gc.C) {
+	snapshotAccessor := newMockSnapshotAccessor()
+	snapshotAccessor.storageSnapshotParams = func(ids []string) ([]params.StorageSnapshotParamsResult, error) {
+		c.Assert(ids, jc.DeepEquals, []string{"1", "2"})
+		return []params.StorageSnapshotParamsResult{{
+			Result: &params.StorageSnapshotParams{
+				Id:        "1",
+				VolumeTag: "volume-1",
+				VolumeId:  "id-1",
+				Provider:  "dummy",
+			},
+		}, {
+			// Snapshot 2 has already been taken.
+			Error: &params.Error{Message: `storage snapshot "2" is available`},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	snapshotAccessor.setStorageSnapshotResults = func(results []params.StorageSnapshotResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.StorageSnapshotResult{{
+			Id:         "1",
+			SnapshotId: "snap-1",
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+	s.provider.createVolumeSnapshotsFunc = func(args []storage.VolumeSnapshotParams) ([]storage.CreateSnapshotsResult, error) {
+		c.Assert(args, jc.DeepEquals, []storage.VolumeSnapshotParams{{
+			Id:       "1",
+			Volume:   names.NewVolumeTag("1"),
+			VolumeId: "id-1",
+		}})
+		return []storage.CreateSnapshotsResult{{SnapshotId: "snap-1"}}, nil
+	}
+
+	args := &workerArgs{snapshots: snapshotAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshotAccessor.snapshotsWatcher.changes <- []string{"1", "2"}
+	waitChannel(c, resultsSet, "waiting for storage snapshot results to be set")
+}
+
+func (s *storageProvisionerSuite) TestStorageSnapshotNotSupported(c *gc.C) {
+	snapshotAccessor := newMockSnapshotAccessor()
+	snapshotAccessor.storageSnapshotParams = func(ids []string) ([]params.StorageSnapshotParamsResult, error) {
+		return []params.StorageSnapshotParamsResult{{
+			Result: &params.StorageSnapshotParams{
+				Id:            "1",
+				FilesystemTag: "filesystem-1",
+				FilesystemId:  "id-1",
+				Provider:      "dummy",
+			},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	snapshotAccessor.setStorageSnapshotResults = func(results []params.StorageSnapshotResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.StorageSnapshotResult{{
+			Id:      "1",
+			Message: `snapshots of "dummy" filesystems not supported`,
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+
+	args := &workerArgs{snapshots: snapshotAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshotAccessor.snapshotsWatcher.changes <- []string{"1"}
+	waitChannel(c, resultsSet, "waiting for storage snapshot results to be set")
+}
+
+func (s *storageProvisionerSuite) TestStorageSnapshotDying(c *gc.C) {
+	snapshotAccessor := newMockSnapshotAccessor()
+	snapshotAccessor.storageSnapshotParams = func(ids []string) ([]params.StorageSnapshotParamsResult, error) {
+		c.Assert(ids, jc.DeepEquals, []string{"1", "2"})
+		return []params.StorageSnapshotParamsResult{{
+			Result: &params.StorageSnapshotParams{
+				Id:         "1",
+				Life:       life.Dying,
+				SnapshotId: "snap-1",
+				VolumeTag:  "volume-1",
+				Provider:   "dummy",
+			},
+		}, {
+			Result: &params.StorageSnapshotParams{
+				Id:         "2",
+				Life:       life.Dying,
+				SnapshotId: "snap-2",
+				VolumeTag:  "volume-2",
+				Provider:   "dummy",
+			},
+		}}, nil
+	}
+	removed := make(chan interface{})
+	snapshotAccessor.removeStorageSnapshots = func(ids []string) ([]params.ErrorResult, error) {
+		defer close(removed)
+		// Snapshot 2 could not be deleted, so it
+		// is not removed.
+		c.Assert(ids, jc.DeepEquals, []string{"1"})
+		return make([]params.ErrorResult, len(ids)), nil
+	}
+	s.provider.deleteVolumeSnapshotsFunc = func(snapshotIds []string) ([]error, error) {
+		c.Assert(snapshotIds, jc.DeepEquals, []string{"snap-1", "snap-2"})
+		return []error{nil, errors.New("snapshot is busy")}, nil
+	}
+
+	args := &workerArgs{snapshots: snapshotAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	snapshotAccessor.snapshotsWatcher.changes <- []string{"1", "2"}
+	waitChannel(c, removed, "waiting for storage snapshots to be removed")
+}
+
+func (s *storageProvisionerSuite) TestVolumeResizeAdded(c *gc.C) {
+	resizeAccessor := newMockResizeAccessor()
+	resizeAccessor.storageResizeParams = func(tags []names.Tag) ([]params.StorageResizeParamsResult, error) {
+		c.Assert(tags, jc.DeepEquals, []names.Tag{names.NewVolumeTag("1"), names.NewVolumeTag("2")})
+		return []params.StorageResizeParamsResult{{
+			Result: &params.StorageResizeParams{
+				Tag:        "volume-1",
+				ProviderId: "id-1",
+				Size:       2048,
+				Provider:   "dummy",
+			},
+		}, {
+			// Volume 2 has already been resized.
+			Error: &params.Error{Message: `pending resize of "2" not found`},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	resizeAccessor.setStorageResizeResults = func(results []params.StorageResizeResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.StorageResizeResult{{
+			Tag:  "volume-1",
+			Size: 2048,
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+	s.provider.resizeVolumesFunc = func(args []storage.VolumeResizeParams) ([]storage.ResizeResult, error) {
+		c.Assert(args, jc.DeepEquals, []storage.VolumeResizeParams{{
+			Volume:   names.NewVolumeTag("1"),
+			VolumeId: "id-1",
+			Size:     2048,
+		}})
+		// The provider does not report the new size,
+		// so the requested size is recorded.
+		return []storage.ResizeResult{{}}, nil
+	}
+
+	args := &workerArgs{resizes: resizeAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	resizeAccessor.volumeResizesWatcher.changes <- []string{"1", "2"}
+	waitChannel(c, resultsSet, "waiting for storage resize results to be set")
+}
+
+func (s *storageProvisionerSuite) TestFilesystemResizeNotSupported(c *gc.C) {
+	resizeAccessor := newMockResizeAccessor()
+	resizeAccessor.storageResizeParams = func(tags []names.Tag) ([]params.StorageResizeParamsResult, error) {
+		return []params.StorageResizeParamsResult{{
+			Result: &params.StorageResizeParams{
+				Tag:        "filesystem-1",
+				ProviderId: "id-1",
+				Size:       2048,
+				Provider:   "dummy",
+			},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	resizeAccessor.setStorageResizeResults = func(results []params.StorageResizeResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.StorageResizeResult{{
+			Tag:     "filesystem-1",
+			Message: `resizing "dummy" filesystems not supported`,
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+
+	args := &workerArgs{resizes: resizeAccessor, registry: s.registry}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	resizeAccessor.filesystemResizesWatcher.changes <- []string{"1"}
+	waitChannel(c, resultsSet, "waiting for storage resize results to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeCopyAdded(c *gc.C) {
+	copyAccessor := newMockVolumeCopyAccessor()
+	copyAccessor.volumeCopyParams = func(ids []params.MachineStorageId) ([]params.VolumeCopyParamsResult, error) {
+		c.Assert(ids, jc.DeepEquals, []params.MachineStorageId{
+			{MachineTag: "machine-1", AttachmentTag: "volume-2"},
+			{MachineTag: "machine-1", AttachmentTag: "volume-3"},
+		})
+		return []params.VolumeCopyParamsResult{{
+			Result: &params.VolumeCopyParams{
+				MachineTag:      "machine-1",
+				SourceVolumeTag: "volume-1",
+				TargetVolumeTag: "volume-2",
+			},
+		}, {
+			// The copy to volume 3 has already completed.
+			Error: &params.Error{Message: `pending copy to volume attachment "1:3" not found`},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	copyAccessor.setVolumeCopyResults = func(results []params.VolumeCopyResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.VolumeCopyResult{{
+			MachineTag: "machine-1",
+			VolumeTag:  "volume-2",
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+	var copied [][]string
+	s.PatchValue(storageprovisioner.CopyBlockDevice, func(sourcePath, targetPath string, abort <-chan struct{}) error {
+		copied = append(copied, []string{sourcePath, targetPath})
+		return nil
+	})
+
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.blockDevices[params.MachineStorageId{
+		MachineTag: "machine-1", AttachmentTag: "volume-1",
+	}] = storage.BlockDevice{DeviceName: "loop0"}
+	volumeAccessor.blockDevices[params.MachineStorageId{
+		MachineTag: "machine-1", AttachmentTag: "volume-2",
+	}] = storage.BlockDevice{DeviceName: "sdb"}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("1"),
+		volumes:  volumeAccessor,
+		copies:   copyAccessor,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	copyAccessor.volumeCopiesWatcher.changes <- []string{"2", "3"}
+	waitChannel(c, resultsSet, "waiting for volume copy results to be set")
+	c.Assert(copied, jc.DeepEquals, [][]string{{"/dev/loop0", "/dev/sdb"}})
+}
+
+func (s *storageProvisionerSuite) TestVolumeCopyWaitsForBlockDevice(c *gc.C) {
+	copyAccessor := newMockVolumeCopyAccessor()
+	copyAccessor.volumeCopyParams = func(ids []params.MachineStorageId) ([]params.VolumeCopyParamsResult, error) {
+		return []params.VolumeCopyParamsResult{{
+			Result: &params.VolumeCopyParams{
+				MachineTag:      "machine-1",
+				SourceVolumeTag: "volume-1",
+				TargetVolumeTag: "volume-2",
+			},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	copyAccessor.setVolumeCopyResults = func(results []params.VolumeCopyResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.VolumeCopyResult{{
+			MachineTag: "machine-1",
+			VolumeTag:  "volume-2",
+			Message:    "no space left on device",
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+	s.PatchValue(storageprovisioner.CopyBlockDevice, func(sourcePath, targetPath string, abort <-chan struct{}) error {
+		return errors.New("no space left on device")
+	})
+
+	volumeAccessor := newMockVolumeAccessor()
+	volumeAccessor.blockDevices[params.MachineStorageId{
+		MachineTag: "machine-1", AttachmentTag: "volume-1",
+	}] = storage.BlockDevice{DeviceName: "loop0"}
+
+	args := &workerArgs{
+		scope:    names.NewMachineTag("1"),
+		volumes:  volumeAccessor,
+		copies:   copyAccessor,
+		registry: s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// The target volume's block device is not yet known,
+	// so the copy is deferred.
+	copyAccessor.volumeCopiesWatcher.changes <- []string{"2"}
+	assertNoEvent(c, resultsSet, "volume copy results set")
+
+	volumeAccessor.blockDevices[params.MachineStorageId{
+		MachineTag: "machine-1", AttachmentTag: "volume-2",
+	}] = storage.BlockDevice{DeviceName: "sdb"}
+	volumeAccessor.blockDevicesWatcher.changes <- struct{}{}
+	waitChannel(c, resultsSet, "waiting for volume copy results to be set")
+}
+
+func (s *storageProvisionerSuite) TestVolumeCopyFilesystem(c *gc.C) {
+	copyAccessor := newMockVolumeCopyAccessor()
+	copyAccessor.volumeCopyParams = func(ids []params.MachineStorageId) ([]params.VolumeCopyParamsResult, error) {
+		return []params.VolumeCopyParamsResult{{
+			Result: &params.VolumeCopyParams{
+				MachineTag:      "machine-0",
+				SourceVolumeTag: "volume-0-0",
+				TargetVolumeTag: "volume-1",
+				FilesystemTag:   "filesystem-0-0",
+			},
+		}}, nil
+	}
+	resultsSet := make(chan interface{})
+	copyAccessor.setVolumeCopyResults = func(results []params.VolumeCopyResult) ([]params.ErrorResult, error) {
+		defer close(resultsSet)
+		c.Assert(results, jc.DeepEquals, []params.VolumeCopyResult{{
+			MachineTag: "machine-0",
+			VolumeTag:  "volume-1",
+		}})
+		return make([]params.ErrorResult, len(results)), nil
+	}
+	var copied [][]string
+	s.PatchValue(storageprovisioner.CopyBlockDevice, func(sourcePath, targetPath string, abort <-chan struct{}) error {
+		copied = append(copied, []string{sourcePath, targetPath})
+		return nil
+	})
+
+	attachmentInfoSet := make(chan interface{})
+	filesystemAccessor := newMockFilesystemAccessor()
+	filesystemAccessor.setFilesystemAttachmentInfo = func(attachments []params.FilesystemAttachment) ([]params.ErrorResult, error) {
+		attachmentInfoSet <- attachments
+		return make([]params.ErrorResult, len(attachments)), nil
+	}
+	args := &workerArgs{
+		scope:       names.NewMachineTag("0"),
+		filesystems: filesystemAccessor,
+		copies:      copyAccessor,
+		registry:    s.registry,
+	}
+	worker := newStorageProvisioner(c, args)
+	defer func() { c.Assert(worker.Wait(), gc.IsNil) }()
+	defer worker.Kill()
+
+	// Mount the filesystem backed by the source volume.
+	filesystemAccessor.provisionedFilesystems["filesystem-0-0"] = params.Filesystem{
+		FilesystemTag: "filesystem-0-0",
+		VolumeTag:     "volume-0-0",
+		Info: params.FilesystemInfo{
+			FilesystemId: "whatever",
+			Size:         123,
+		},
+	}
+	filesystemAccessor.provisionedMachines["machine-0"] = instance.Id("already-provisioned-0")
+	args.volumes.blockDevices[params.MachineStorageId{
+		MachineTag:    "machine-0",
+		AttachmentTag: "volume-0-0",
+	}] = storage.BlockDevice{DeviceName: "xvdf1", Size: 123}
+	args.volumes.blockDevices[params.MachineStorageId{
+		MachineTag:    "machine-0",
+		AttachmentTag: "volume-1",
+	}] = storage.BlockDevice{DeviceName: "xvdg1", Size: 123}
+	filesystemAccessor.attachmentsWatcher.changes <- []watcher.MachineStorageId{{
+		MachineTag:    "machine-0",
+		AttachmentTag: "filesystem-0-0",
+	}}
+	filesystemAccessor.filesystemsWatcher.changes <- []string{"0/0"}
+	waitChannel(c, attachmentInfoSet, "waiting for filesystem attachment info to be set")
+
+	var detached []storage.FilesystemAttachmentParams
+	s.managedFilesystemSource.detachFilesystems = func(args []storage.FilesystemAttachmentParams) ([]error, error) {
+		detached = append(detached, args...)
+		return make([]error, len(args)), nil
+	}
+	copyAccessor.volumeCopiesWatcher.changes <- []string{"1"}
+	waitChannel(c, resultsSet, "waiting for volume copy results to be set")
+
+	// The filesystem is unmounted while its data is copied,
+	// and mounted again from the target volume.
+	c.Assert(detached, jc.DeepEquals, []storage.FilesystemAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Provider:   "dummy",
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: "already-provisioned-0",
+			ReadOnly:   true,
+		},
+		Filesystem:   names.NewFilesystemTag("0/0"),
+		FilesystemId: "whatever",
+		Path:         "/mnt/xvdf1",
+	}})
+	c.Assert(copied, jc.DeepEquals, [][]string{{"/dev/xvdf1", "/dev/xvdg1"}})
+	c.Assert(
+		s.managedFilesystemSource.filesystems[names.NewFilesystemTag("0/0")].Volume,
+		gc.Equals, names.NewVolumeTag("1"),
+	)
+	statuses := args.statusSetter.args
+	c.Assert(len(statuses) >= 2, jc.IsTrue)
+	c.Assert(statuses[len(statuses)-2:], jc.DeepEquals, []params.EntityStatusArgs{
+		{Tag: "volume-1", Status: "attached", Info: "copying data from volume 0/0"},
+		{Tag: "volume-1", Status: "attached"},
+	})
+}
+
 func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 	if args == nil {
 		args = &workerArgs{}
@@ -2062,11 +2560,31 @@ func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 	if args.statusSetter == nil {
 		args.statusSetter = &mockStatusSetter{}
 	}
+	var snapshots storageprovisioner.SnapshotAccessor
+	if args.snapshots != nil {
+		snapshots = args.snapshots
+	}
+	var resizes storageprovisioner.ResizeAccessor
+	if args.resizes != nil {
+		resizes = args.resizes
+	}
+	var copies storageprovisioner.VolumeCopyAccessor
+	if args.copies != nil {
+		copies = args.copies
+	}
+	var encryptionKeys storageprovisioner.EncryptionKeyAccessor
+	if args.encryptionKeys != nil {
+		encryptionKeys = args.encryptionKeys
+	}
 	worker, err := storageprovisioner.NewStorageProvisioner(storageprovisioner.Config{
 		Scope:            args.scope,
 		StorageDir:       storageDir,
 		Volumes:          args.volumes,
 		Filesystems:      args.filesystems,
+		Snapshots:        snapshots,
+		Resizes:          resizes,
+		Copies:           copies,
+		EncryptionKeys:   encryptionKeys,
 		Life:             args.life,
 		Registry:         args.registry,
 		Machines:         args.machines,
@@ -2080,14 +2598,18 @@ func newStorageProvisioner(c *gc.C, args *workerArgs) worker.Worker {
 }
 
 type workerArgs struct {
-	scope        names.Tag
-	volumes      *mockVolumeAccessor
-	filesystems  *mockFilesystemAccessor
-	life         *mockLifecycleManager
-	registry     storage.ProviderRegistry
-	machines     *mockMachineAccessor
-	clock        clock.Clock
-	statusSetter *mockStatusSetter
+	scope          names.Tag
+	volumes        *mockVolumeAccessor
+	filesystems    *mockFilesystemAccessor
+	snapshots      *mockSnapshotAccessor
+	resizes        *mockResizeAccessor
+	copies         *mockVolumeCopyAccessor
+	encryptionKeys *mockEncryptionKeyAccessor
+	life           *mockLifecycleManager
+	registry       storage.ProviderRegistry
+	machines       *mockMachineAccessor
+	clock          clock.Clock
+	statusSetter   *mockStatusSetter
 }
 
 func waitChannel(c *gc.C, ch <-chan interface{}, activity string) interface{} {
@@ -114,4 +114,9 @@ type StorageSnapshot struct {
 	Life     life.Value
 	Attached bool
 	Location string
+	Size     uint64
+
+	// Moving is true if the storage is being moved to another
+	// storage pool, and the unit should stop using it.
+	Moving bool
 }
@@ -30,6 +30,7 @@ func newStorageAttachmentWatcher(
 	watcher watcher.NotifyWatcher,
 	unitTag names.UnitTag,
 	storageTag names.StorageTag,
+	attached bool,
 	out chan<- storageAttachmentChange,
 ) (*storageAttachmentWatcher, error) {
 	s := &storageAttachmentWatcher{
@@ -38,6 +39,7 @@ func newStorageAttachmentWatcher(
 		out:        out,
 		storageTag: storageTag,
 		unitTag:    unitTag,
+		attached:   attached,
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &s.catacomb,
@@ -61,6 +63,10 @@ type storageAttachmentWatcher struct {
 	storageTag names.StorageTag
 	unitTag    names.UnitTag
 	out        chan<- storageAttachmentChange
+
+	// attached records whether the storage was attached when
+	// last reported.
+	attached bool
 }
 
 type storageAttachmentChange struct {
@@ -82,6 +88,8 @@ func getStorageSnapshot(
 		Kind:     attachment.Kind,
 		Attached: true,
 		Location: attachment.Location,
+		Size:     attachment.Size,
+		Moving:   attachment.Moving,
 	}
 	return snapshot, nil
 }
@@ -103,12 +111,19 @@ func (s *storageAttachmentWatcher) loop() error {
 				// from state, so we can stop watching.
 				return nil
 			} else if params.IsCodeNotProvisioned(err) {
-				// We do not care about unattached
-				// storage here.
-				continue
+				if !s.attached {
+					// We do not care about unattached
+					// storage here.
+					continue
+				}
+				// The storage was attached, but is no longer
+				// provisioned; e.g. it is being moved to another
+				// storage pool. Report it as unattached.
+				snapshot = StorageSnapshot{}
 			} else if err != nil {
 				return err
 			}
+			s.attached = snapshot.Attached
 			change := storageAttachmentChange{
 				s.storageTag,
 				snapshot,
@@ -847,8 +847,18 @@ func (w *RemoteStateWatcher) relationUnitsChanged(change relationUnitsChange) er
 // storageAttachmentChanged responds to storage attachment changes.
 func (w *RemoteStateWatcher) storageAttachmentChanged(change storageAttachmentChange) {
 	w.mu.Lock()
-	w.current.Storage[change.Tag] = change.Snapshot
-	w.mu.Unlock()
+	defer w.mu.Unlock()
+	snapshot := change.Snapshot
+	if !snapshot.Attached {
+		// The storage is no longer attached, so its lifecycle
+		// state is only known from the unit's storage watcher.
+		current, ok := w.current.Storage[change.Tag]
+		if !ok {
+			return
+		}
+		snapshot.Life = current.Life
+	}
+	w.current.Storage[change.Tag] = snapshot
 }
 
 func (w *RemoteStateWatcher) actionsChanged(actions []string) {
@@ -954,7 +964,7 @@ func (w *RemoteStateWatcher) watchStorageAttachment(
 		}
 	}
 	innerSAW, err := newStorageAttachmentWatcher(
-		w.st, saw, w.unit.Tag(), tag, w.storageAttachmentChanges,
+		w.st, saw, w.unit.Tag(), tag, storageSnapshot.Attached, w.storageAttachmentChanges,
 	)
 	if err != nil {
 		return errors.Trace(err)
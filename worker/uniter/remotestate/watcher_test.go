@@ -414,6 +414,7 @@ func (s *WatcherSuite) TestStorageChanged(c *gc.C) {
 		Life:       life.Dying,
 		Kind:       params.StorageKindBlock,
 		Location:   "malta",
+		Size:       1024,
 	}
 
 	// We should not see any event until the storage attachment watchers
@@ -433,6 +434,7 @@ func (s *WatcherSuite) TestStorageChanged(c *gc.C) {
 			Kind:     params.StorageKindBlock,
 			Attached: true,
 			Location: "malta",
+			Size:     1024,
 		},
 	})
 
@@ -504,6 +506,51 @@ func (s *WatcherSuite) TestStorageUnattachedChanged(c *gc.C) {
 	})
 }
 
+func (s *WatcherSuite) TestStorageUnprovisionedChanged(c *gc.C) {
+	s.signalAll()
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	storageTag0 := names.NewStorageTag("blob/0")
+	storageAttachmentId0 := params.StorageAttachmentId{
+		UnitTag:    s.st.unit.tag.String(),
+		StorageTag: storageTag0.String(),
+	}
+	storageTag0Watcher := newMockNotifyWatcher()
+	s.st.storageAttachmentWatchers[storageTag0] = storageTag0Watcher
+	s.st.storageAttachment[storageAttachmentId0] = params.StorageAttachment{
+		UnitTag:    storageAttachmentId0.UnitTag,
+		StorageTag: storageAttachmentId0.StorageTag,
+		Life:       life.Dying,
+		Kind:       params.StorageKindBlock,
+		Location:   "malta",
+	}
+
+	s.st.unit.storageWatcher.changes <- []string{"blob/0"}
+	storageTag0Watcher.changes <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	// The storage becomes unprovisioned, e.g. while it is being
+	// moved to another pool; it should be reported as unattached,
+	// retaining its lifecycle state.
+	s.st.storageAttachment[storageAttachmentId0] = params.StorageAttachment{
+		UnitTag:    storageAttachmentId0.UnitTag,
+		StorageTag: storageAttachmentId0.StorageTag,
+		Life:       life.Dying,
+		Kind:       params.StorageKindUnknown, // unprovisioned
+	}
+	storageTag0Watcher.changes <- struct{}{}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	c.Assert(s.watcher.Snapshot().Storage, jc.DeepEquals, map[names.StorageTag]remotestate.StorageSnapshot{
+		storageTag0: {
+			Life: life.Dying,
+		},
+	})
+
+	// Further changes while unprovisioned are not reported.
+	storageTag0Watcher.changes <- struct{}{}
+	assertNoNotifyEvent(c, s.watcher.RemoteStateChanged(), "remote state change")
+}
+
 func (s *WatcherSuite) TestStorageAttachmentRemoved(c *gc.C) {
 	s.signalAll()
 	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
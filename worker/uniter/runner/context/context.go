@@ -490,16 +490,26 @@ func (ctx *HookContext) AddUnitStorage(cons map[string]params.StorageConstraints
 }
 
 func (ctx *HookContext) OpenPorts(protocol string, fromPort, toPort int) error {
+	return ctx.OpenPortsForEndpoint("", protocol, fromPort, toPort)
+}
+
+func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int) error {
+	return ctx.ClosePortsForEndpoint("", protocol, fromPort, toPort)
+}
+
+// OpenPortsForEndpoint implements jujuc.ContextNetworking.
+func (ctx *HookContext) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
 	return tryOpenPorts(
-		protocol, fromPort, toPort,
+		protocol, fromPort, toPort, endpoint,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
 }
 
-func (ctx *HookContext) ClosePorts(protocol string, fromPort, toPort int) error {
+// ClosePortsForEndpoint implements jujuc.ContextNetworking.
+func (ctx *HookContext) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
 	return tryClosePorts(
-		protocol, fromPort, toPort,
+		protocol, fromPort, toPort, endpoint,
 		ctx.unit.Tag(),
 		ctx.machinePorts, ctx.pendingPorts,
 	)
@@ -824,14 +834,31 @@ func (ctx *HookContext) Flush(process string, ctxErr error) (err error) {
 		if writeChanges {
 			var e error
 			var op string
-			if rangeInfo.ShouldOpen {
+			switch {
+			case rangeInfo.ShouldOpen && rangeKey.Endpoint != "":
+				e = ctx.unit.OpenPortsForEndpoint(
+					rangeKey.Endpoint,
+					rangeKey.Ports.Protocol,
+					rangeKey.Ports.FromPort,
+					rangeKey.Ports.ToPort,
+				)
+				op = "open"
+			case rangeInfo.ShouldOpen:
 				e = ctx.unit.OpenPorts(
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
 					rangeKey.Ports.ToPort,
 				)
 				op = "open"
-			} else {
+			case rangeKey.Endpoint != "":
+				e = ctx.unit.ClosePortsForEndpoint(
+					rangeKey.Endpoint,
+					rangeKey.Ports.Protocol,
+					rangeKey.Ports.FromPort,
+					rangeKey.Ports.ToPort,
+				)
+				op = "close"
+			default:
 				e = ctx.unit.ClosePorts(
 					rangeKey.Ports.Protocol,
 					rangeKey.Ports.FromPort,
@@ -123,7 +123,7 @@ func (s *FlushContextSuite) TestRunHookOpensAndClosesPendingPorts(c *gc.C) {
 
 	// Try opening some ports via the context.
 	err = ctx.OpenPorts("tcp", 100, 200)
-	c.Assert(err, jc.ErrorIsNil) // duplicates are ignored
+	c.Assert(err, jc.ErrorIsNil) // duplicates are not errors
 	err = ctx.OpenPorts("udp", 200, 300)
 	c.Assert(err, gc.ErrorMatches, `cannot open 200-300/udp \(unit "u/0"\): conflicts with existing 200-300/udp \(unit "u/1"\)`)
 	err = ctx.OpenPorts("udp", 100, 200)
@@ -21,11 +21,13 @@ type PortRangeInfo struct {
 	RelationTag names.RelationTag
 }
 
-// PortRange contains a port range and a relation id. Used as key to
-// pendingRelations and is only exported for testing.
+// PortRange contains a port range, a relation id and the endpoint the
+// range is opened or closed for, which is empty for all endpoints. Used
+// as key to pendingRelations and is only exported for testing.
 type PortRange struct {
 	Ports      network.PortRange
 	RelationId int
+	Endpoint   string
 }
 
 func validatePortRange(protocol string, fromPort, toPort int) (network.PortRange, error) {
@@ -44,6 +46,7 @@ func validatePortRange(protocol string, fromPort, toPort int) (network.PortRange
 func tryOpenPorts(
 	protocol string,
 	fromPort, toPort int,
+	endpoint string,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
 	pendingPorts map[PortRange]PortRangeInfo,
@@ -61,6 +64,7 @@ func tryOpenPorts(
 	rangeKey := PortRange{
 		Ports:      newRange,
 		RelationId: relationId,
+		Endpoint:   endpoint,
 	}
 
 	rangeInfo, isKnown := pendingPorts[rangeKey]
@@ -87,9 +91,11 @@ func tryOpenPorts(
 		}
 		if newRange.ConflictsWith(portRange) {
 			if portRange == newRange && relUnitTag == unitTag {
-				// The same unit trying to open the same range is just
-				// ignored.
-				return nil
+				// The same unit may open the same range again, as it
+				// is not known which endpoints the range is already
+				// opened for. Opening it twice for the same endpoints
+				// has no effect.
+				continue
 			}
 			return errors.Errorf(
 				"cannot open %v (unit %q): conflicts with existing %v (unit %q)",
@@ -99,6 +105,10 @@ func tryOpenPorts(
 	}
 	// Ensure other pending port ranges do not conflict with this one.
 	for rangeKey, rangeInfo := range pendingPorts {
+		if rangeKey.Ports == newRange {
+			// The same range requested earlier for other endpoints.
+			continue
+		}
 		if newRange.ConflictsWith(rangeKey.Ports) && rangeInfo.ShouldOpen {
 			return errors.Errorf(
 				"cannot open %v (unit %q): conflicts with %v requested earlier",
@@ -116,6 +126,7 @@ func tryOpenPorts(
 func tryClosePorts(
 	protocol string,
 	fromPort, toPort int,
+	endpoint string,
 	unitTag names.UnitTag,
 	machinePorts map[network.PortRange]params.RelationUnit,
 	pendingPorts map[PortRange]PortRangeInfo,
@@ -133,16 +144,27 @@ func tryClosePorts(
 	rangeKey := PortRange{
 		Ports:      newRange,
 		RelationId: relationId,
+		Endpoint:   endpoint,
 	}
 
+	if endpoint == "" {
+		// Closing a range for all endpoints cancels any pending
+		// request to open it for some of them.
+		for key, info := range pendingPorts {
+			if key.Ports == newRange && key.Endpoint != "" && info.ShouldOpen {
+				delete(pendingPorts, key)
+			}
+		}
+	}
 	rangeInfo, isKnown := pendingPorts[rangeKey]
 	if isKnown {
-		if rangeInfo.ShouldOpen {
-			// If the same range is already pending to be opened, just
-			// remove it from pending.
-			delete(pendingPorts, rangeKey)
+		if !rangeInfo.ShouldOpen {
+			// The same range is already pending to be closed.
+			return nil
 		}
-		return nil
+		// If the same range is already pending to be opened, remove
+		// it from pending, and close it if it was already opened.
+		delete(pendingPorts, rangeKey)
 	}
 
 	// Ensure the range we're trying to close is opened on the
@@ -116,6 +116,12 @@ func makeMachinePorts(
 
 func makePendingPorts(
 	proto string, fromPort, toPort int, shouldOpen bool,
+) map[context.PortRange]context.PortRangeInfo {
+	return makeEndpointPendingPorts("", proto, fromPort, toPort, shouldOpen)
+}
+
+func makeEndpointPendingPorts(
+	endpoint, proto string, fromPort, toPort int, shouldOpen bool,
 ) map[context.PortRange]context.PortRangeInfo {
 	result := make(map[context.PortRange]context.PortRangeInfo)
 	portRange := network.PortRange{
@@ -126,6 +132,7 @@ func makePendingPorts(
 	key := context.PortRange{
 		Ports:      portRange,
 		RelationId: -1,
+		Endpoint:   endpoint,
 	}
 	result[key] = context.PortRangeInfo{
 		ShouldOpen: shouldOpen,
@@ -137,6 +144,7 @@ type portsTest struct {
 	about         string
 	proto         string
 	ports         []int
+	endpoint      string
 	machinePorts  map[network.PortRange]params.RelationUnit
 	pendingPorts  map[context.PortRange]context.PortRangeInfo
 	expectErr     string
@@ -169,9 +177,22 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 		about:         "open a new range (no machine ports yet)",
 		expectPending: makePendingPorts("tcp", 10, 20, true),
 	}, {
-		about:         "open an existing range (ignored)",
+		about:         "open an existing range (opened again)",
 		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
-		expectPending: map[context.PortRange]context.PortRangeInfo{},
+		expectPending: makePendingPorts("tcp", 10, 20, true),
+	}, {
+		about:         "open an existing range for an endpoint",
+		endpoint:      "url",
+		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
+		expectPending: makeEndpointPendingPorts("url", "tcp", 10, 20, true),
+	}, {
+		about:        "open a range for an endpoint pending to be opened for another endpoint",
+		endpoint:     "url",
+		pendingPorts: makeEndpointPendingPorts("db", "tcp", 10, 20, true),
+		expectPending: map[context.PortRange]context.PortRangeInfo{
+			{Ports: network.PortRange{FromPort: 10, ToPort: 20, Protocol: "tcp"}, RelationId: -1, Endpoint: "db"}:  {ShouldOpen: true},
+			{Ports: network.PortRange{FromPort: 10, ToPort: 20, Protocol: "tcp"}, RelationId: -1, Endpoint: "url"}: {ShouldOpen: true},
+		},
 	}, {
 		about:         "open a range pending to be closed already",
 		pendingPorts:  makePendingPorts("tcp", 10, 20, false),
@@ -188,10 +209,6 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 		about:        "try opening a range conflicting with another unit",
 		machinePorts: makeMachinePorts("u/1", "tcp", 10, 20),
 		expectErr:    `cannot open 10-20/tcp \(unit "u/0"\): conflicts with existing 10-20/tcp \(unit "u/1"\)`,
-	}, {
-		about:         "open a range conflicting with the same unit (ignored)",
-		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
-		expectPending: map[context.PortRange]context.PortRangeInfo{},
 	}, {
 		about:        "try opening a range conflicting with another pending range",
 		pendingPorts: makePendingPorts("tcp", 5, 25, true),
@@ -205,6 +222,7 @@ func (s *PortsSuite) TestTryOpenPorts(c *gc.C) {
 			test.proto,
 			test.ports[0],
 			test.ports[1],
+			test.endpoint,
 			names.NewUnitTag("u/0"),
 			test.machinePorts,
 			test.pendingPorts,
@@ -238,6 +256,20 @@ func (s *PortsSuite) TestTryClosePorts(c *gc.C) {
 		about:         "close a range pending to be opened already (removed from pending)",
 		pendingPorts:  makePendingPorts("tcp", 10, 20, true),
 		expectPending: map[context.PortRange]context.PortRangeInfo{},
+	}, {
+		about:         "close an existing range pending to be opened again",
+		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
+		pendingPorts:  makePendingPorts("tcp", 10, 20, true),
+		expectPending: makePendingPorts("tcp", 10, 20, false),
+	}, {
+		about:         "close an existing range for an endpoint",
+		endpoint:      "url",
+		machinePorts:  makeMachinePorts("u/0", "tcp", 10, 20),
+		expectPending: makeEndpointPendingPorts("url", "tcp", 10, 20, false),
+	}, {
+		about:         "close a range pending to be opened for an endpoint (removed from pending)",
+		pendingPorts:  makeEndpointPendingPorts("url", "tcp", 10, 20, true),
+		expectPending: map[context.PortRange]context.PortRangeInfo{},
 	}, {
 		about:         "close a range pending to be closed already (ignored)",
 		pendingPorts:  makePendingPorts("tcp", 10, 20, false),
@@ -259,6 +291,7 @@ func (s *PortsSuite) TestTryClosePorts(c *gc.C) {
 			test.proto,
 			test.ports[0],
 			test.ports[1],
+			test.endpoint,
 			names.NewUnitTag("u/0"),
 			test.machinePorts,
 			test.pendingPorts,
@@ -162,6 +162,16 @@ type ContextNetworking interface {
 	// separately by a co- located unit).
 	ClosePorts(protocol string, fromPort, toPort int) error
 
+	// OpenPortsForEndpoint marks the supplied port range for opening
+	// when the given endpoint of the executing unit's application is
+	// exposed.
+	OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error
+
+	// ClosePortsForEndpoint ensures the supplied port range is closed
+	// for the given endpoint, leaving it open for any other endpoint
+	// it was opened for.
+	ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error
+
 	// OpenedPorts returns all port ranges currently opened by this
 	// unit on its assigned machine. The result is sorted first by
 	// protocol, then by number.
@@ -304,6 +314,10 @@ type ContextStorageAttachment interface {
 	// Location returns the location of the storage: the mount point for
 	// filesystem-kind stores, and the device path for block-kind stores.
 	Location() string
+
+	// Size returns the size of the storage in MiB, or 0 if the
+	// size is not known.
+	Size() uint64
 }
 
 // ContextVersion expresses the parts of a hook context related to
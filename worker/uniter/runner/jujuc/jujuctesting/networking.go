@@ -95,6 +95,28 @@ func (c *ContextNetworking) ClosePorts(protocol string, from, to int) error {
 	return nil
 }
 
+// OpenPortsForEndpoint implements jujuc.ContextNetworking.
+func (c *ContextNetworking) OpenPortsForEndpoint(endpoint, protocol string, from, to int) error {
+	c.stub.AddCall("OpenPortsForEndpoint", endpoint, protocol, from, to)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	c.info.AddPorts(protocol, from, to)
+	return nil
+}
+
+// ClosePortsForEndpoint implements jujuc.ContextNetworking.
+func (c *ContextNetworking) ClosePortsForEndpoint(endpoint, protocol string, from, to int) error {
+	c.stub.AddCall("ClosePortsForEndpoint", endpoint, protocol, from, to)
+	if err := c.stub.NextErr(); err != nil {
+		return errors.Trace(err)
+	}
+
+	c.info.RemovePorts(protocol, from, to)
+	return nil
+}
+
 // OpenedPorts implements jujuc.ContextNetworking.
 func (c *ContextNetworking) OpenedPorts() []network.PortRange {
 	c.stub.AddCall("OpenedPorts")
@@ -37,7 +37,7 @@ func (s *Storage) SetAttachment(attach jujuc.ContextStorageAttachment) {
 func (s *Storage) SetNewAttachment(name, location string, kind storage.StorageKind, stub *testing.Stub) {
 	tag := names.NewStorageTag(name)
 	attachment := &ContextStorageAttachment{
-		info: &StorageAttachment{tag, kind, location},
+		info: &StorageAttachment{Tag: tag, Kind: kind, Location: location},
 	}
 	attachment.stub = stub
 	s.SetAttachment(attachment)
@@ -126,12 +126,14 @@ func parseArguments(args []string) (portRange, error) {
 // portCommand implements the open-port and close-port commands.
 type portCommand struct {
 	cmd.CommandBase
-	info       *cmd.Info
-	action     func(*portCommand) error
-	Protocol   string
-	FromPort   int
-	ToPort     int
-	formatFlag string // deprecated
+	info          *cmd.Info
+	action        func(*portCommand) error
+	Protocol      string
+	FromPort      int
+	ToPort        int
+	Endpoints     []string
+	endpointsFlag string
+	formatFlag    string // deprecated
 }
 
 func (c *portCommand) Info() *cmd.Info {
@@ -140,6 +142,7 @@ func (c *portCommand) Info() *cmd.Info {
 
 func (c *portCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.formatFlag, "format", "", "deprecated format flag")
+	f.StringVar(&c.endpointsFlag, "endpoints", "", "a comma-delimited list of application endpoints to target with this operation")
 }
 
 func (c *portCommand) Init(args []string) error {
@@ -155,6 +158,17 @@ func (c *portCommand) Init(args []string) error {
 	c.FromPort = portRange.fromPort
 	c.ToPort = portRange.toPort
 	c.Protocol = portRange.protocol
+
+	c.Endpoints = nil
+	if c.endpointsFlag != "" {
+		for _, endpoint := range strings.Split(c.endpointsFlag, ",") {
+			endpoint = strings.TrimSpace(endpoint)
+			if endpoint == "" {
+				return errors.Errorf("invalid endpoints %q", c.endpointsFlag)
+			}
+			c.Endpoints = append(c.Endpoints, endpoint)
+		}
+	}
 	return cmd.CheckEmpty(args[1:])
 }
 
@@ -169,14 +183,27 @@ var openPortInfo = &cmd.Info{
 	Name:    "open-port",
 	Args:    portFormat,
 	Purpose: "register a port or range to open",
-	Doc:     "The port range will only be open while the application is exposed.",
+	Doc: `
+The port range will only be open while the application is exposed.
+
+By default, the port range is opened for all of the unit's endpoints.
+The --endpoints option opens it for the given endpoints only, so that it
+can only be reached from the networks those endpoints are exposed to.`[1:],
 }
 
 func NewOpenPortCommand(ctx Context) (cmd.Command, error) {
 	return &portCommand{
 		info: openPortInfo,
 		action: func(c *portCommand) error {
-			return ctx.OpenPorts(c.Protocol, c.FromPort, c.ToPort)
+			if len(c.Endpoints) == 0 {
+				return ctx.OpenPorts(c.Protocol, c.FromPort, c.ToPort)
+			}
+			for _, endpoint := range c.Endpoints {
+				if err := ctx.OpenPortsForEndpoint(endpoint, c.Protocol, c.FromPort, c.ToPort); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
 		},
 	}, nil
 }
@@ -185,13 +212,25 @@ var closePortInfo = &cmd.Info{
 	Name:    "close-port",
 	Args:    portFormat,
 	Purpose: "ensure a port or range is always closed",
+	Doc: `
+By default, the port range is closed for all of the unit's endpoints.
+The --endpoints option closes it for the given endpoints only, leaving
+it open for any other endpoint it was opened for.`[1:],
 }
 
 func NewClosePortCommand(ctx Context) (cmd.Command, error) {
 	return &portCommand{
 		info: closePortInfo,
 		action: func(c *portCommand) error {
-			return ctx.ClosePorts(c.Protocol, c.FromPort, c.ToPort)
+			if len(c.Endpoints) == 0 {
+				return ctx.ClosePorts(c.Protocol, c.FromPort, c.ToPort)
+			}
+			for _, endpoint := range c.Endpoints {
+				if err := ctx.ClosePortsForEndpoint(endpoint, c.Protocol, c.FromPort, c.ToPort); err != nil {
+					return errors.Trace(err)
+				}
+			}
+			return nil
 		},
 	}, nil
 }
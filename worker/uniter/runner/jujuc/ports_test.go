@@ -10,6 +10,7 @@ import (
 
 	"github.com/juju/cmd"
 	"github.com/juju/cmd/cmdtesting"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -91,6 +92,26 @@ func (s *PortsSuite) TestOpenClose(c *gc.C) {
 	}
 }
 
+func (s *PortsSuite) TestOpenCloseForEndpoints(c *gc.C) {
+	hctx := s.GetHookContext(c, -1, "")
+	for _, args := range [][]string{
+		{"open-port", "--endpoints", "url,monitoring-port", "80"},
+		{"close-port", "--endpoints", "url", "80/tcp"},
+	} {
+		com, err := jujuc.NewCommand(hctx, cmdString(args[0]))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := cmdtesting.Context(c)
+		code := cmd.Main(jujuc.NewJujucCommandWrappedForTest(com), ctx, args[1:])
+		c.Check(code, gc.Equals, 0)
+		c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
+	}
+	s.Stub.CheckCalls(c, []testing.StubCall{
+		{"OpenPortsForEndpoint", []interface{}{"url", "tcp", 80, 80}},
+		{"OpenPortsForEndpoint", []interface{}{"monitoring-port", "tcp", 80, 80}},
+		{"ClosePortsForEndpoint", []interface{}{"url", "tcp", 80, 80}},
+	})
+}
+
 var badPortsTests = []struct {
 	args []string
 	err  string
@@ -109,6 +130,7 @@ var badPortsTests = []struct {
 	{[]string{"80-90/http"}, `protocol must be "tcp", "udp", or "icmp"; got "http"`},
 	{[]string{"20-10/tcp"}, `invalid port range 20-10/tcp; expected fromPort <= toPort`},
 	{[]string{"80/icmp"}, `protocol "icmp" doesn't support any ports; got "80"`},
+	{[]string{"--endpoints", "url,", "80"}, `invalid endpoints "url,"`},
 }
 
 func (s *PortsSuite) TestBadArgs(c *gc.C) {
@@ -136,6 +158,10 @@ register a port or range to open
 
 Details:
 The port range will only be open while the application is exposed.
+
+By default, the port range is opened for all of the unit's endpoints.
+The --endpoints option opens it for the given endpoints only, so that it
+can only be reached from the networks those endpoints are exposed to.
 `[1:])
 
 	close, err := jujuc.NewCommand(hctx, cmdString("close-port"))
@@ -145,6 +171,11 @@ Usage: close-port <port>[/<protocol>] or <from>-<to>[/<protocol>] or icmp
 
 Summary:
 ensure a port or range is always closed
+
+Details:
+By default, the port range is closed for all of the unit's endpoints.
+The --endpoints option closes it for the given endpoints only, leaving
+it open for any other endpoint it was opened for.
 `[1:])
 }
 
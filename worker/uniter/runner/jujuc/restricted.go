@@ -82,6 +82,16 @@ func (*RestrictedContext) ClosePorts(protocol string, fromPort, toPort int) erro
 	return ErrRestrictedContext
 }
 
+// OpenPortsForEndpoint implements hooks.Context.
+func (*RestrictedContext) OpenPortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return ErrRestrictedContext
+}
+
+// ClosePortsForEndpoint implements hooks.Context.
+func (*RestrictedContext) ClosePortsForEndpoint(endpoint, protocol string, fromPort, toPort int) error {
+	return ErrRestrictedContext
+}
+
 // OpenedPorts implements hooks.Context.
 func (*RestrictedContext) OpenedPorts() []network.PortRange { return nil }
 
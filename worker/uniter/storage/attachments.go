@@ -136,6 +136,7 @@ func (a *Attachments) init() error {
 				tag:      storageTag,
 				kind:     storage.StorageKind(attachment.Kind),
 				location: attachment.Location,
+				size:     attachment.Size,
 			},
 		}
 	}
@@ -209,10 +210,11 @@ func (a *Attachments) CommitHook(hi hook.Info) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if err := storageState.CommitHook(hi); err != nil {
+	storageTag := names.NewStorageTag(hi.StorageId)
+	size := a.storageAttachments[storageTag].Size()
+	if err := storageState.commitHook(hi, size); err != nil {
 		return err
 	}
-	storageTag := names.NewStorageTag(hi.StorageId)
 	switch hi.Kind {
 	case hooks.StorageAttached:
 		a.pending.Remove(storageTag)
@@ -234,7 +236,7 @@ func (a *Attachments) removeStorageAttachment(tag names.StorageTag) error {
 }
 
 func (a *Attachments) storageStateForHook(hi hook.Info) (*stateFile, error) {
-	if !hi.Kind.IsStorage() {
+	if !hook.IsStorage(hi.Kind) {
 		return nil, errors.Errorf("not a storage hook: %#v", hi)
 	}
 	storageAttachment, ok := a.storageAttachments[names.NewStorageTag(hi.StorageId)]
@@ -335,6 +335,217 @@ func (s *attachmentsSuite) TestAttachmentsCommitHook(c *gc.C) {
 	c.Assert(removed, jc.IsTrue)
 }
 
+func (s *attachmentsSuite) TestAttachmentsStorageResized(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	storageTag := names.NewStorageTag("data/0")
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return nil, nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(att, s.modelType)
+
+	localState := resolver.LocalState{State: operation.State{
+		Kind:      operation.Continue,
+		Installed: true,
+	}}
+	nextOp := func(size uint64) (operation.Operation, error) {
+		return r.NextOp(localState, remotestate.Snapshot{
+			Life: life.Alive,
+			Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+				storageTag: {
+					Kind:     params.StorageKindBlock,
+					Life:     life.Alive,
+					Location: "/dev/sdb",
+					Attached: true,
+					Size:     size,
+				},
+			},
+		}, &mockOperations{})
+	}
+
+	op, err := nextOp(1024)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-attached")
+	err = att.CommitHook(hook.Info{
+		Kind:      hooks.StorageAttached,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	stateFile := filepath.Join(stateDir, "data-0")
+	data, err := ioutil.ReadFile(stateFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 1024\n")
+
+	_, err = nextOp(1024)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+	op, err = nextOp(2048)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-resized")
+	ctx, err := att.Storage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.Size(), gc.Equals, uint64(2048))
+	err = att.CommitHook(hook.Info{
+		Kind:      hook.StorageResized,
+		StorageId: storageTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	data, err = ioutil.ReadFile(stateFile)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 2048\n")
+
+	_, err = nextOp(2048)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *attachmentsSuite) TestAttachmentsStorageMoved(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	var removed bool
+	storageTag := names.NewStorageTag("data/0")
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return nil, nil
+		},
+		remove: func(s names.StorageTag, u names.UnitTag) error {
+			removed = true
+			c.Assert(s, gc.Equals, storageTag)
+			return nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(att, s.modelType)
+
+	localState := resolver.LocalState{State: operation.State{
+		Kind:      operation.Continue,
+		Installed: true,
+	}}
+	nextOp := func(snapshot remotestate.StorageSnapshot) (operation.Operation, error) {
+		return r.NextOp(localState, remotestate.Snapshot{
+			Life: life.Alive,
+			Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+				storageTag: snapshot,
+			},
+		}, &mockOperations{})
+	}
+	commitHook := func(kind hooks.Kind) {
+		err := att.CommitHook(hook.Info{
+			Kind:      kind,
+			StorageId: storageTag.Id(),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	attached := remotestate.StorageSnapshot{
+		Kind:     params.StorageKindBlock,
+		Life:     life.Alive,
+		Location: "/dev/sdb",
+		Attached: true,
+	}
+
+	op, err := nextOp(attached)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-attached")
+	commitHook(hooks.StorageAttached)
+
+	// The storage is being moved, so the charm is told to stop
+	// using it, although the attachment remains alive.
+	moving := attached
+	moving.Moving = true
+	op, err = nextOp(moving)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-detaching")
+	commitHook(hooks.StorageDetaching)
+	c.Assert(removed, jc.IsTrue)
+	c.Assert(filepath.Join(stateDir, "data-0"), jc.DoesNotExist)
+
+	// The storage-attached hook is not run again while the
+	// storage is being moved, nor while it is unprovisioned
+	// during the copy.
+	_, err = nextOp(moving)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+	_, err = nextOp(remotestate.StorageSnapshot{Life: life.Alive})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+	// Once the move completes, the storage is attached again
+	// at its new location.
+	moved := attached
+	moved.Location = "/dev/sdc"
+	op, err = nextOp(moved)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.String(), gc.Equals, "run hook storage-attached")
+	commitHook(hooks.StorageAttached)
+	ctx, err := att.Storage(storageTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.Location(), gc.Equals, "/dev/sdc")
+
+	_, err = nextOp(moved)
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+}
+
+func (s *attachmentsSuite) TestAttachmentsStorageSizeLearned(c *gc.C) {
+	stateDir := c.MkDir()
+	unitTag := names.NewUnitTag("mysql/0")
+	abort := make(chan struct{})
+
+	// The storage was attached by an agent that did
+	// not record the size of storage.
+	writeFile(c, filepath.Join(stateDir, "data-0"), "attached: true")
+	storageTag := names.NewStorageTag("data/0")
+	st := &mockStorageAccessor{
+		unitStorageAttachments: func(u names.UnitTag) ([]params.StorageAttachmentId, error) {
+			return []params.StorageAttachmentId{{
+				StorageTag: storageTag.String(),
+				UnitTag:    unitTag.String(),
+			}}, nil
+		},
+		storageAttachment: func(s names.StorageTag, u names.UnitTag) (params.StorageAttachment, error) {
+			return params.StorageAttachment{
+				Kind:     params.StorageKindBlock,
+				Location: "/dev/sdb",
+				Life:     life.Alive,
+				Size:     1024,
+			}, nil
+		},
+	}
+
+	att, err := storage.NewAttachments(st, unitTag, stateDir, abort)
+	c.Assert(err, jc.ErrorIsNil)
+	r := storage.NewResolver(att, s.modelType)
+
+	localState := resolver.LocalState{State: operation.State{
+		Kind:      operation.Continue,
+		Installed: true,
+	}}
+	_, err = r.NextOp(localState, remotestate.Snapshot{
+		Life: life.Alive,
+		Storage: map[names.StorageTag]remotestate.StorageSnapshot{
+			storageTag: {
+				Kind:     params.StorageKindBlock,
+				Life:     life.Alive,
+				Location: "/dev/sdb",
+				Attached: true,
+				Size:     1024,
+			},
+		},
+	}, &mockOperations{})
+	c.Assert(err, gc.Equals, resolver.ErrNoOperation)
+
+	data, err := ioutil.ReadFile(filepath.Join(stateDir, "data-0"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "attached: true\nsize: 1024\n")
+}
+
 func (s *attachmentsSuite) TestAttachmentsSetDying(c *gc.C) {
 	stateDir := c.MkDir()
 	unitTag := names.NewUnitTag("mysql/0")
@@ -143,8 +143,20 @@ func (s *storageResolver) nextHookOp(
 	case life.Alive:
 		storageAttachment, ok := s.storage.storageAttachments[tag]
 		if ok && storageAttachment.attached {
+			if snap.Moving {
+				// The storage is being moved to another pool,
+				// so the charm must stop using it until the
+				// move completes.
+				hookInfo.Kind = hooks.StorageDetaching
+				break
+			}
 			// Once the storage is attached, we only care about
-			// lifecycle state changes.
+			// lifecycle state changes, and the storage growing.
+			return s.maybeResizedHookOp(tag, storageAttachment, snap, opFactory)
+		}
+		if snap.Moving {
+			// The charm has stopped using the storage; wait for
+			// the move to complete before attaching it again.
 			return nil, resolver.ErrNoOperation
 		}
 		// The storage-attached hook has not been committed, so add the
@@ -172,6 +184,42 @@ func (s *storageResolver) nextHookOp(
 		hookInfo.Kind = hooks.StorageDetaching
 	}
 
+	return s.newRunHookOp(tag, hookInfo, snap, opFactory)
+}
+
+// maybeResizedHookOp returns an operation to run the "storage-resized"
+// hook if the attached storage has grown since the size was last
+// reported to the charm.
+func (s *storageResolver) maybeResizedHookOp(
+	tag names.StorageTag,
+	attachment storageAttachment,
+	snap remotestate.StorageSnapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
+	if snap.Size <= attachment.size {
+		return nil, resolver.ErrNoOperation
+	}
+	if attachment.size == 0 {
+		// The storage was attached before its size was
+		// recorded; learn the size without running a hook.
+		if err := attachment.RecordSize(snap.Size); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return nil, resolver.ErrNoOperation
+	}
+	hookInfo := hook.Info{
+		Kind:      hook.StorageResized,
+		StorageId: tag.Id(),
+	}
+	return s.newRunHookOp(tag, hookInfo, snap, opFactory)
+}
+
+func (s *storageResolver) newRunHookOp(
+	tag names.StorageTag,
+	hookInfo hook.Info,
+	snap remotestate.StorageSnapshot,
+	opFactory operation.Factory,
+) (operation.Operation, error) {
 	// Update the local state to reflect what we're about to report
 	// to a hook.
 	stateFile, err := readStateFile(s.storage.storageStateDir, tag)
@@ -183,6 +231,7 @@ func (s *storageResolver) nextHookOp(
 			tag:      tag,
 			kind:     storage.StorageKind(snap.Kind),
 			location: snap.Location,
+			size:     snap.Size,
 		},
 	}
 